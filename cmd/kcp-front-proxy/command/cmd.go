@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/component-base/version"
+
+	frontproxyoptions "github.com/kcp-dev/kcp/cmd/kcp-front-proxy/options"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/proxy"
+)
+
+// NewCommand returns the command running the front-proxy until the given context is done. It is
+// used by the kcp-front-proxy binary and the front-proxy subcommand of kcp.
+func NewCommand(ctx context.Context) *cobra.Command {
+	options := frontproxyoptions.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "kcp-front-proxy",
+		Short: "Terminate TLS and handles client cert auth for backend API servers",
+		Long: `kcp-front-proxy is a reverse proxy that accepts client certificates and
+forwards Common Name and Organizations to backend API servers in HTTP headers.
+The proxy terminates TLS and communicates with API servers via mTLS. Traffic is
+routed based on paths.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.Logs.ValidateAndApply(kcpfeatures.DefaultFeatureGate); err != nil {
+				return err
+			}
+			if err := options.Complete(); err != nil {
+				return err
+			}
+			if errs := options.Validate(); errs != nil {
+				return errors.NewAggregate(errs)
+			}
+
+			if options.Proxy.ProfilerAddress != "" {
+				//nolint:errcheck,gosec
+				go http.ListenAndServe(options.Proxy.ProfilerAddress, nil)
+			}
+
+			config, err := proxy.NewConfig(options.Proxy)
+			if err != nil {
+				return err
+			}
+			completedConfig, err := config.Complete()
+			if err != nil {
+				return err
+			}
+
+			server, err := proxy.NewServer(ctx, completedConfig)
+			if err != nil {
+				return err
+			}
+			prepared, err := server.PrepareRun(ctx)
+			if err != nil {
+				return err
+			}
+			return prepared.Run(ctx)
+		},
+	}
+
+	options.AddFlags(cmd.Flags())
+
+	if v := version.Get().String(); len(v) == 0 {
+		cmd.Version = "<unknown>"
+	} else {
+		cmd.Version = v
+	}
+
+	return cmd
+}
@@ -38,7 +38,11 @@ import (
 	"k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
+	cacheservercommand "github.com/kcp-dev/kcp/cmd/cache-server/command"
+	frontproxycommand "github.com/kcp-dev/kcp/cmd/kcp-front-proxy/command"
 	"github.com/kcp-dev/kcp/cmd/kcp/options"
+	virtualworkspacescommand "github.com/kcp-dev/kcp/cmd/virtual-workspaces/command"
+	"github.com/kcp-dev/kcp/pkg/cmd/flags"
 	"github.com/kcp-dev/kcp/pkg/cmd/help"
 	"github.com/kcp-dev/kcp/pkg/embeddedetcd"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
@@ -46,6 +50,8 @@ import (
 )
 
 func main() {
+	ctx := genericapiserver.SetupSignalContext()
+
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	cmd := &cobra.Command{
@@ -63,6 +69,9 @@ func main() {
 			To get started, launch a new cluster with 'kcp start', which will
 			initialize your personal control plane and write an admin kubeconfig file
 			to disk.
+
+			The other components of a sharded deployment are started with
+			'kcp front-proxy', 'kcp cache-server' and 'kcp virtual-workspaces'.
 		`),
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -71,16 +80,7 @@ func main() {
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
 
 	// manually extract root directory from flags first as it influence all other flags
-	rootDir := ".kcp"
-	for i, f := range os.Args {
-		if f == "--root-directory" {
-			if i < len(os.Args)-1 {
-				rootDir = os.Args[i+1]
-			} // else let normal flag processing fail
-		} else if strings.HasPrefix(f, "--root-directory=") {
-			rootDir = strings.TrimPrefix(f, "--root-directory=")
-		}
-	}
+	rootDir := flags.RootDirectory(os.Args[1:], ".kcp")
 
 	serverOptions := options.NewOptions(rootDir)
 	serverOptions.Server.Core.GenericControlPlane.Logs.Config.Verbosity = config.VerbosityLevel(2)
@@ -131,8 +131,6 @@ func main() {
 				return err
 			}
 
-			ctx := genericapiserver.SetupSignalContext()
-
 			// the etcd server must be up before NewServer because storage decorators access it right away
 			if completedConfig.Core.EmbeddedEtcd.Config != nil {
 				if err := embeddedetcd.NewServer(completedConfig.Core.EmbeddedEtcd).Run(ctx); err != nil {
@@ -186,6 +184,16 @@ func main() {
 		"run-virtual-workspaces",
 	})
 
+	// the other components of a kcp deployment run as subcommands too, such that a single binary
+	// and image can serve all of them. The standalone binaries remain as thin wrappers.
+	frontProxyCmd := frontproxycommand.NewCommand(ctx)
+	frontProxyCmd.Use = "front-proxy"
+	cmd.AddCommand(frontProxyCmd)
+	cmd.AddCommand(cacheservercommand.NewCommand(ctx, flags.RootDirectory(os.Args[1:], cacheservercommand.DefaultRootDirectory)))
+	virtualWorkspacesCmd := virtualworkspacescommand.NewCommand(ctx, os.Stderr)
+	virtualWorkspacesCmd.Use = "virtual-workspaces"
+	cmd.AddCommand(virtualWorkspacesCmd)
+
 	help.FitTerminal(cmd.OutOrStdout())
 
 	if v := version.Get().String(); len(v) == 0 {
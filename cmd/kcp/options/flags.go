@@ -35,6 +35,9 @@ var (
 		"KCP Controllers",
 		"KCP Home Workspaces",
 		"KCP Cache Server",
+		"KCP Usage Export",
+		"KCP Identity Escrow",
+		"KCP Informer Cache",
 		"KCP",
 	}
 )
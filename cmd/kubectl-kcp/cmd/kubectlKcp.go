@@ -30,6 +30,10 @@ import (
 	bindcmd "github.com/kcp-dev/kcp/pkg/cliplugins/bind/cmd"
 	claimscmd "github.com/kcp-dev/kcp/pkg/cliplugins/claims/cmd"
 	crdcmd "github.com/kcp-dev/kcp/pkg/cliplugins/crd/cmd"
+	debugcmd "github.com/kcp-dev/kcp/pkg/cliplugins/debug/cmd"
+	doctorcmd "github.com/kcp-dev/kcp/pkg/cliplugins/doctor/cmd"
+	generatecmd "github.com/kcp-dev/kcp/pkg/cliplugins/generate/cmd"
+	identitycmd "github.com/kcp-dev/kcp/pkg/cliplugins/identity/cmd"
 	workloadcmd "github.com/kcp-dev/kcp/pkg/cliplugins/workload/cmd"
 	workspacecmd "github.com/kcp-dev/kcp/pkg/cliplugins/workspace/cmd"
 	"github.com/kcp-dev/kcp/pkg/cmd/help"
@@ -88,5 +92,17 @@ func KubectlKcpCommand() *cobra.Command {
 	claimsCmd := claimscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 	root.AddCommand(claimsCmd)
 
+	doctorCmd := doctorcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root.AddCommand(doctorCmd)
+
+	identityCmd := identitycmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root.AddCommand(identityCmd)
+
+	generateCmd := generatecmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root.AddCommand(generateCmd)
+
+	debugCmd := debugcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root.AddCommand(debugCmd)
+
 	return root
 }
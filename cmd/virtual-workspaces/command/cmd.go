@@ -35,11 +35,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/pkg/version"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/component-base/config"
 	"k8s.io/klog/v2"
 
 	"github.com/kcp-dev/kcp/cmd/virtual-workspaces/options"
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/server/bootstrap"
 	virtualrootapiserver "github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
@@ -97,10 +100,6 @@ func Run(ctx context.Context, o *options.Options) error {
 	if err != nil {
 		return err
 	}
-	cacheKcpClusterClient, err := kcpclientset.NewForConfig(cacheConfig)
-	if err != nil {
-		return err
-	}
 
 	// Don't throttle
 	nonIdentityConfig.QPS = -1
@@ -142,7 +141,38 @@ func Run(ctx context.Context, o *options.Options) error {
 		return err
 	}
 	wildcardKcpInformers := kcpinformers.NewSharedInformerFactory(kcpClusterClient, 10*time.Minute)
+
+	// reads of the cache informers fall back to the shard while the cache server is unreachable
+	var cacheLocalFallback *cacheclient.LocalFallback
+	if o.Cache.LocalFallback {
+		probeClient, err := kcpclientset.NewForConfig(rest.CopyConfig(cacheConfig))
+		if err != nil {
+			return err
+		}
+		cacheLocalFallback, err = cacheclient.NewLocalFallback(identityConfig, func(ctx context.Context) error {
+			_, err := probeClient.ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{Limit: 1})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		cacheConfig = cacheclient.WithLocalFallbackRoundTripper(cacheConfig, cacheLocalFallback)
+	}
+	cacheKcpClusterClient, err := kcpclientset.NewForConfig(cacheConfig)
+	if err != nil {
+		return err
+	}
 	cacheKcpInformers := kcpinformers.NewSharedInformerFactory(cacheKcpClusterClient, 10*time.Minute)
+	// the payload of big objects replicated to the cache server is resolved like in the shards.
+	offloader, err := o.Cache.Offloader()
+	if err != nil {
+		return err
+	}
+	if offloader != nil {
+		if err := offloader.TransformInformers(offload.KcpInformers(cacheKcpInformers)...); err != nil {
+			return err
+		}
+	}
 
 	if o.ProfilerAddress != "" {
 		//nolint:errcheck,gosec
@@ -179,7 +209,7 @@ func Run(ctx context.Context, o *options.Options) error {
 	if err != nil {
 		return err
 	}
-	tmcVWs, err := o.TmcVirtualWorkspaces.NewVirtualWorkspaces(identityConfig, o.RootPathPrefix, cacheKcpInformers)
+	tmcVWs, err := o.TmcVirtualWorkspaces.NewVirtualWorkspaces(identityConfig, o.RootPathPrefix, wildcardKubeInformers, cacheKcpInformers)
 	if err != nil {
 		return err
 	}
@@ -201,6 +231,9 @@ func Run(ctx context.Context, o *options.Options) error {
 	}
 
 	logger.Info("Starting informers")
+	if cacheLocalFallback != nil {
+		go cacheLocalFallback.Start(ctx, o.Cache.LocalFallbackProbeInterval)
+	}
 	wildcardKubeInformers.Start(ctx.Done())
 	wildcardKcpInformers.Start(ctx.Done())
 	cacheKcpInformers.Start(ctx.Done())
@@ -20,6 +20,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -206,3 +207,20 @@ func Unmarshal(fileName string, crd *apiextensionsv1.CustomResourceDefinition) e
 
 	return yaml.Unmarshal(bs, crd)
 }
+
+// GroupResources returns the GroupResources of all embedded CRDs.
+func GroupResources() ([]metav1.GroupResource, error) {
+	entries, err := raw.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	grs := make([]metav1.GroupResource, 0, len(entries))
+	for _, e := range entries {
+		group, resource, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".yaml"), "_")
+		if !ok {
+			return nil, fmt.Errorf("unexpected embedded CRD file name %q, expected <group>_<resource>.yaml", e.Name())
+		}
+		grs = append(grs, metav1.GroupResource{Group: group, Resource: resource})
+	}
+	return grs, nil
+}
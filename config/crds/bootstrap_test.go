@@ -21,6 +21,9 @@ import (
 	"embed"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -57,3 +60,15 @@ func TestCreateFromFS(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupResources(t *testing.T) {
+	grs, err := GroupResources()
+	require.NoError(t, err)
+	require.Contains(t, grs, metav1.GroupResource{Group: tenancy.GroupName, Resource: "workspaces"})
+	require.Contains(t, grs, metav1.GroupResource{Group: "core.kcp.io", Resource: "replicationconfigs"})
+	for _, gr := range grs {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, Unmarshal(gr.Group+"_"+gr.Resource+".yaml", crd))
+		require.Equal(t, gr.Resource+"."+gr.Group, crd.Name)
+	}
+}
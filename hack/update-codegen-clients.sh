@@ -31,6 +31,27 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd "${SCRIPT_ROOT}"; go list -f '{{.Dir}}' -m k8s.i
 go install "${CODEGEN_PKG}"/cmd/applyconfiguration-gen
 go install "${CODEGEN_PKG}"/cmd/client-gen
 
+go install "${CODEGEN_PKG}"/cmd/openapi-gen
+
+"$GOPATH"/bin/openapi-gen  --input-dirs github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/apiresource/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/topology/v1alpha1 \
+--input-dirs github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1 \
+--input-dirs k8s.io/apimachinery/pkg/apis/meta/v1,k8s.io/apimachinery/pkg/runtime,k8s.io/apimachinery/pkg/version \
+--output-package github.com/kcp-dev/kcp/pkg/openapi -O zz_generated.openapi \
+--go-header-file ./hack/../hack/boilerplate/boilerplate.generatego.txt \
+--output-base "${SCRIPT_ROOT}" \
+--trim-path-prefix github.com/kcp-dev/kcp
+
+# the OpenAPI models are the input for the Extract<Kind> functions of the apply configurations.
+OPENAPI_MODELS=$(mktemp)
+trap 'rm -f "${OPENAPI_MODELS}"' EXIT
+go run ./pkg/openapi/cmd/models-schema > "${OPENAPI_MODELS}"
+
 "$GOPATH"/bin/applyconfiguration-gen \
   --input-dirs github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1 \
   --input-dirs github.com/kcp-dev/kcp/sdk/apis/apiresource/v1alpha1 \
@@ -41,6 +62,7 @@ go install "${CODEGEN_PKG}"/cmd/client-gen
   --input-dirs github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1 \
   --input-dirs github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1 \
   --input-dirs k8s.io/apimachinery/pkg/apis/meta/v1,k8s.io/apimachinery/pkg/runtime,k8s.io/apimachinery/pkg/version,k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1 \
+  --openapi-schema "${OPENAPI_MODELS}" \
   --output-package github.com/kcp-dev/kcp/sdk/client/applyconfiguration \
   --go-header-file ./hack/../hack/boilerplate/boilerplate.generatego.txt \
   --output-base "${SCRIPT_ROOT}" \
@@ -118,19 +140,3 @@ ${CODE_GENERATOR} \
   "paths=./..." \
   "output:dir=./../client"
 popd
-
-go install "${CODEGEN_PKG}"/cmd/openapi-gen
-
-"$GOPATH"/bin/openapi-gen  --input-dirs github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/apiresource/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/topology/v1alpha1 \
---input-dirs github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1 \
---input-dirs k8s.io/apimachinery/pkg/apis/meta/v1,k8s.io/apimachinery/pkg/runtime,k8s.io/apimachinery/pkg/version \
---output-package github.com/kcp-dev/kcp/pkg/openapi -O zz_generated.openapi \
---go-header-file ./hack/../hack/boilerplate/boilerplate.generatego.txt \
---output-base "${SCRIPT_ROOT}" \
---trim-path-prefix github.com/kcp-dev/kcp
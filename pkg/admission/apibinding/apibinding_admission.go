@@ -33,12 +33,14 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/admission/apiexport"
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
-	"github.com/kcp-dev/kcp/pkg/indexers"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
@@ -52,15 +54,11 @@ const (
 func Register(plugins *admission.Plugins) {
 	plugins.Register(PluginName,
 		func(_ io.Reader) (admission.Interface, error) {
-			p := &apiBindingAdmission{
-				Handler:          admission.NewHandler(admission.Create, admission.Update),
-				createAuthorizer: delegated.NewDelegatedAuthorizer,
-			}
-			p.getAPIExport = func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
-				return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), p.apiExportIndexer, p.cacheAPIExportIndexer, path, name)
-			}
-
-			return p, nil
+			return &apiBindingAdmission{
+				Handler:                admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer:       delegated.NewDelegatedAuthorizer,
+				strictPermissionClaims: kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.StrictPermissionClaimAcceptance),
+			}, nil
 		})
 }
 
@@ -69,11 +67,11 @@ type apiBindingAdmission struct {
 
 	getAPIExport func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
 
-	apiExportIndexer      cache.Indexer
-	cacheAPIExportIndexer cache.Indexer
-
 	deepSARClient    kcpkubernetesclientset.ClusterInterface
 	createAuthorizer delegated.DelegatedAuthorizerFactory
+
+	// strictPermissionClaims rejects accepted permission claims not matching the APIExport instead of warning.
+	strictPermissionClaims bool
 }
 
 // Ensure that the required admission interfaces are implemented.
@@ -214,6 +212,15 @@ func (o *apiBindingAdmission) Validate(ctx context.Context, a admission.Attribut
 	if len(errs) > 0 {
 		return admission.NewForbidden(a, fmt.Errorf("%v", errs))
 	}
+	if permissionClaimsChanged(oldAPIBinding, apiBinding) {
+		claims := make([]apisv1alpha1.PermissionClaim, 0, len(apiBinding.Spec.PermissionClaims))
+		for _, claim := range apiBinding.Spec.PermissionClaims {
+			claims = append(claims, claim.PermissionClaim)
+		}
+		if errs := apiexport.ValidatePermissionClaimsUnambiguous(claims, field.NewPath("spec", "permissionClaims")); len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+	}
 
 	switch {
 	case a.GetOperation() == admission.Create,
@@ -259,9 +266,50 @@ func (o *apiBindingAdmission) Validate(ctx context.Context, a admission.Attribut
 		}
 	}
 
+	return o.validatePermissionClaims(ctx, a, clusterName, oldAPIBinding, apiBinding)
+}
+
+// validatePermissionClaims checks changed permission claim acceptances against the claims requested by the
+// APIExport. Mismatches are rejected in strict mode, and returned as warnings otherwise.
+func (o *apiBindingAdmission) validatePermissionClaims(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name, oldAPIBinding, apiBinding *apisv1alpha1.APIBinding) error {
+	if !permissionClaimsChanged(oldAPIBinding, apiBinding) {
+		return nil
+	}
+
+	path := logicalcluster.NewPath(apiBinding.Spec.Reference.Export.Path)
+	if path.Empty() {
+		path = clusterName.Path()
+	}
+	export, err := o.getAPIExport(path, apiBinding.Spec.Reference.Export.Name)
+	if err != nil {
+		// the APIBinding controller reports missing exports.
+		return nil
+	}
+
+	errs := ValidateAcceptedPermissionClaims(apiBinding.Spec.PermissionClaims, export.Spec.PermissionClaims, field.NewPath("spec", "permissionClaims"))
+	if len(errs) == 0 {
+		return nil
+	}
+	if o.strictPermissionClaims {
+		return admission.NewForbidden(a, fmt.Errorf("%v", errs))
+	}
+	for _, err := range errs {
+		warning.AddWarning(ctx, "", err.Error())
+	}
 	return nil
 }
 
+// permissionClaimsChanged returns whether the APIBinding has permission claims that are new, or that
+// refer to another APIExport than before. Unchanged claims are not validated again.
+func permissionClaimsChanged(oldAPIBinding, apiBinding *apisv1alpha1.APIBinding) bool {
+	if len(apiBinding.Spec.PermissionClaims) == 0 {
+		return false
+	}
+	return oldAPIBinding == nil ||
+		!reflect.DeepEqual(oldAPIBinding.Spec.PermissionClaims, apiBinding.Spec.PermissionClaims) ||
+		!reflect.DeepEqual(oldAPIBinding.Spec.Reference, apiBinding.Spec.Reference)
+}
+
 func (o *apiBindingAdmission) checkAPIExportAccess(ctx context.Context, user user.Info, apiExportClusterName logicalcluster.Name, apiExportName string) error {
 	logger := klog.FromContext(ctx)
 	authz, err := o.createAuthorizer(apiExportClusterName, o.deepSARClient, delegated.Options{})
@@ -279,11 +327,8 @@ func (o *apiBindingAdmission) ValidateInitialization() error {
 	if o.deepSARClient == nil {
 		return fmt.Errorf(PluginName + " plugin needs a deepSARClient")
 	}
-	if o.apiExportIndexer == nil {
-		return fmt.Errorf(PluginName + " plugin needs an APIExport indexer")
-	}
-	if o.cacheAPIExportIndexer == nil {
-		return fmt.Errorf(PluginName + " plugin needs a cache APIExport indexer")
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIExport getter")
 	}
 	return nil
 }
@@ -295,18 +340,11 @@ func (o *apiBindingAdmission) SetDeepSARClient(client kcpkubernetesclientset.Clu
 }
 
 func (o *apiBindingAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
-	apiExportsReady := local.Apis().V1alpha1().APIExports().Informer().HasSynced
-	cacheAPIExportsReady := local.Apis().V1alpha1().APIExports().Informer().HasSynced
-	o.SetReadyFunc(func() bool {
-		return apiExportsReady() && cacheAPIExportsReady()
-	})
-	o.apiExportIndexer = local.Apis().V1alpha1().APIExports().Informer().GetIndexer()
-	o.cacheAPIExportIndexer = global.Apis().V1alpha1().APIExports().Informer().GetIndexer()
-
-	indexers.AddIfNotPresentOrDie(local.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-	})
-	indexers.AddIfNotPresentOrDie(global.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-	})
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	o.SetReadyFunc(apiExports.HasSynced)
+	o.getAPIExport = apiExports.Get
 }
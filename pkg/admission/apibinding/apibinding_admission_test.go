@@ -35,6 +35,7 @@ import (
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
 
 	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
@@ -284,6 +285,61 @@ func TestValidate(t *testing.T) {
 			authzError:     errors.New("some error here"),
 			expectedErrors: []string{"no permission to bind to export root:org:workspaceName:someExport"},
 		},
+		{
+			name: "Create: valid acceptance policy passes",
+			attr: createAttr(
+				newAPIBinding().withName("test").withReference(logicalcluster.NewPath("root:org:workspaceName"), "someExport").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root-org-workspaceName:someExport")).
+					withAcceptancePolicy(apisv1alpha1.PermissionClaimAcceptanceRule{Resource: "configmaps", Namespaces: []string{"team-a-*"}}).APIBinding,
+			),
+			authzDecision: authorizer.DecisionAllow,
+		},
+		{
+			name: "Create: invalid acceptance policy fails",
+			attr: createAttr(
+				newAPIBinding().withName("test").withReference(logicalcluster.NewPath("root:org:workspaceName"), "someExport").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root-org-workspaceName:someExport")).
+					withAcceptancePolicy(
+						apisv1alpha1.PermissionClaimAcceptanceRule{Namespaces: []string{"Team_A"}},
+						apisv1alpha1.PermissionClaimAcceptanceRule{Resource: "secrets", All: true, Namespaces: []string{"*"}},
+					).APIBinding,
+			),
+			authzDecision: authorizer.DecisionAllow,
+			expectedErrors: []string{
+				"spec.acceptancePolicy.rules[0].resource: Required value",
+				"spec.acceptancePolicy.rules[0].namespaces[0]: Invalid value: \"Team_A\"",
+				"spec.acceptancePolicy.rules[1].all: Invalid value: true",
+			},
+		},
+		{
+			name: "Create: resource overrides with distinct aliases pass",
+			attr: createAttr(
+				newAPIBinding().withName("test").withReference(logicalcluster.NewPath("root:org:workspaceName"), "someExport").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root-org-workspaceName:someExport")).
+					withResourceOverrides(
+						apisv1alpha1.ResourceOverride{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, Alias: "acme-widgets"},
+						apisv1alpha1.ResourceOverride{GroupResource: apisv1alpha1.GroupResource{Group: "other.io", Resource: "widgets"}, Alias: "acme-widgets"},
+					).APIBinding,
+			),
+			authzDecision: authorizer.DecisionAllow,
+		},
+		{
+			name: "Create: invalid resource overrides fail",
+			attr: createAttr(
+				newAPIBinding().withName("test").withReference(logicalcluster.NewPath("root:org:workspaceName"), "someExport").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root-org-workspaceName:someExport")).
+					withResourceOverrides(
+						apisv1alpha1.ResourceOverride{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, Alias: "widgets"},
+						apisv1alpha1.ResourceOverride{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "gadgets"}, Alias: "acme-things"},
+						apisv1alpha1.ResourceOverride{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "sprockets"}, Alias: "acme-things"},
+					).APIBinding,
+			),
+			authzDecision: authorizer.DecisionAllow,
+			expectedErrors: []string{
+				"spec.resourceOverrides[0].alias: Invalid value: \"widgets\": must differ from the resource",
+				"spec.resourceOverrides[2].alias: Duplicate value: \"acme-things, also used for gadgets\"",
+			},
+		},
 		{
 			name: "Update: missing workspace reference exportName fails",
 			attr: updateAttr(
@@ -476,6 +532,120 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+type warningRecorder []string
+
+func (r *warningRecorder) AddWarning(_, text string) {
+	*r = append(*r, text)
+}
+
+func TestValidatePermissionClaims(t *testing.T) {
+	configMaps := apisv1alpha1.PermissionClaim{
+		GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default", Name: "setup"}},
+	}
+	broader := apisv1alpha1.PermissionClaim{GroupResource: configMaps.GroupResource, All: true}
+	secrets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+
+	tests := map[string]struct {
+		claims       []apisv1alpha1.AcceptablePermissionClaim
+		strict       bool
+		wantErr      string
+		wantWarnings []string
+	}{
+		"matching claims": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted}},
+		},
+		"stale rejection": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: secrets, State: apisv1alpha1.ClaimRejected}},
+		},
+		"rejection with reason": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: configMaps, State: apisv1alpha1.ClaimRejected, Reason: "TooBroad"}},
+		},
+		"acceptance with reason": {
+			claims:  []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted, Reason: "TooBroad"}},
+			wantErr: "spec.permissionClaims[0].reason: Forbidden: can only be set for rejected claims",
+		},
+		"stale acceptance warns": {
+			claims:       []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted}},
+			wantWarnings: []string{`spec.permissionClaims[0]: Invalid value: "secrets": is not requested by the APIExport`},
+		},
+		"stale acceptance in strict mode": {
+			claims:  []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted}},
+			strict:  true,
+			wantErr: `spec.permissionClaims[0]: Invalid value: "secrets": is not requested by the APIExport`,
+		},
+		"broader acceptance in strict mode": {
+			claims:  []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: broader, State: apisv1alpha1.ClaimAccepted}},
+			strict:  true,
+			wantErr: "spec.permissionClaims[0].all: Invalid value: true: is broader than requested by the APIExport for configmaps",
+		},
+		"contradicting decisions": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimRejected},
+			},
+			wantErr: "spec.permissionClaims[1]: Invalid value: \"configmaps\": configmaps is already claimed in spec.permissionClaims[0]",
+		},
+		"other selector in strict mode": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: apisv1alpha1.PermissionClaim{
+				GroupResource:    configMaps.GroupResource,
+				ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default", Name: "setup"}, {Namespace: "kube-system"}},
+			}, State: apisv1alpha1.ClaimAccepted}},
+			strict:  true,
+			wantErr: "spec.permissionClaims[0].resourceSelector[1]",
+		},
+		"read-through acceptance of other claim in strict mode": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: apisv1alpha1.PermissionClaim{
+				GroupResource: configMaps.GroupResource,
+				ReferencedBy: &apisv1alpha1.PermissionClaimReference{
+					GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+					NameField:     "spec.configMapName",
+				},
+			}, State: apisv1alpha1.ClaimAccepted}},
+			strict:  true,
+			wantErr: "spec.permissionClaims[0].referencedBy",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			export := newExport(logicalcluster.NewPath("root:org:workspaceName"), "someExport").APIExport
+			export.Spec.PermissionClaims = []apisv1alpha1.PermissionClaim{configMaps}
+
+			o := &apiBindingAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface, opts delegated.Options) (authorizer.Authorizer, error) {
+					return &fakeAuthorizer{authorized: authorizer.DecisionAllow}, nil
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					return export, nil
+				},
+				strictPermissionClaims: tc.strict,
+			}
+
+			binding := newAPIBinding().withName("test").withReference(logicalcluster.NewPath("root:org:workspaceName"), "someExport").
+				withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root-org-workspaceName:someExport")).APIBinding
+			binding.Spec.PermissionClaims = tc.claims
+			attr := createAttr(binding)
+
+			var warnings warningRecorder
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.From(binding)})
+			ctx = warning.WithWarningRecorder(ctx, &warnings)
+
+			err := o.Validate(ctx, attr, nil)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, len(tc.wantWarnings), len(warnings), "warnings: %v", warnings)
+			for i, w := range tc.wantWarnings {
+				require.Contains(t, warnings[i], w)
+			}
+		})
+	}
+}
+
 type fakeAuthorizer struct {
 	authorized authorizer.Decision
 	err        error
@@ -522,6 +692,16 @@ func (b *bindingBuilder) withLabel(k, v string) *bindingBuilder {
 	return b
 }
 
+func (b *bindingBuilder) withAcceptancePolicy(rules ...apisv1alpha1.PermissionClaimAcceptanceRule) *bindingBuilder {
+	b.Spec.AcceptancePolicy = &apisv1alpha1.PermissionClaimAcceptancePolicy{Rules: rules}
+	return b
+}
+
+func (b *bindingBuilder) withResourceOverrides(overrides ...apisv1alpha1.ResourceOverride) *bindingBuilder {
+	b.Spec.ResourceOverrides = overrides
+	return b
+}
+
 func (b *bindingBuilder) withPhase(phase apisv1alpha1.APIBindingPhaseType) *bindingBuilder {
 	b.Status.Phase = phase
 	return b
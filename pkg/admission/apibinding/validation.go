@@ -18,7 +18,10 @@ package apibinding
 
 import (
 	"fmt"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
@@ -29,6 +32,15 @@ func ValidateAPIBinding(apiBinding *apisv1alpha1.APIBinding) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, ValidateAPIBindingReference(apiBinding.Spec.Reference, field.NewPath("spec", "reference"))...)
+	if apiBinding.Spec.AcceptancePolicy != nil {
+		allErrs = append(allErrs, validateAcceptancePolicy(apiBinding.Spec.AcceptancePolicy, field.NewPath("spec", "acceptancePolicy"))...)
+	}
+	allErrs = append(allErrs, validateResourceOverrides(apiBinding.Spec.ResourceOverrides, field.NewPath("spec", "resourceOverrides"))...)
+	for i, claim := range apiBinding.Spec.PermissionClaims {
+		if claim.Reason != "" && claim.State != apisv1alpha1.ClaimRejected {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "permissionClaims").Index(i).Child("reason"), "can only be set for rejected claims"))
+		}
+	}
 
 	return allErrs
 }
@@ -64,3 +76,101 @@ func ValidateAPIBindingReference(reference apisv1alpha1.BindingReference, path *
 
 	return allErrs
 }
+
+func validateAcceptancePolicy(policy *apisv1alpha1.PermissionClaimAcceptancePolicy, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(policy.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("rules"), ""))
+	}
+	for i, rule := range policy.Rules {
+		rulePath := path.Child("rules").Index(i)
+		if rule.Resource == "" {
+			allErrs = append(allErrs, field.Required(rulePath.Child("resource"), ""))
+		}
+		if rule.All && len(rule.Namespaces) > 0 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("all"), rule.All, "claims of all objects never match a rule with namespaces"))
+		}
+		for j, namespace := range rule.Namespaces {
+			if namespace == "*" {
+				continue
+			}
+			// wildcards can stand for a single character, so replace them by an allowed one to validate the rest.
+			for _, msg := range validation.IsDNS1123Label(strings.ReplaceAll(namespace, "*", "x")) {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("namespaces").Index(j), namespace, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateResourceOverrides(overrides []apisv1alpha1.ResourceOverride, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	aliases := map[string]string{}
+	for i, override := range overrides {
+		if override.Alias == override.Resource {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("alias"), override.Alias, "must differ from the resource"))
+			continue
+		}
+		key := override.Alias + "." + override.Group
+		if other, found := aliases[key]; found {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("alias"), override.Alias+", also used for "+other))
+			continue
+		}
+		aliases[key] = override.Resource
+	}
+
+	return allErrs
+}
+
+// ValidateAcceptedPermissionClaims validates that every accepted permission claim corresponds to a claim requested
+// by the APIExport, and is not broader than requested. Rejected claims are not validated.
+func ValidateAcceptedPermissionClaims(claims []apisv1alpha1.AcceptablePermissionClaim, exported []apisv1alpha1.PermissionClaim, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, claim := range claims {
+		if claim.State != apisv1alpha1.ClaimAccepted {
+			continue
+		}
+
+		var requested *apisv1alpha1.PermissionClaim
+		for j := range exported {
+			if exported[j].Equal(claim.PermissionClaim) {
+				requested = &exported[j]
+				break
+			}
+		}
+		if requested == nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), claim.String(), "is not requested by the APIExport"))
+			continue
+		}
+		if requested.All {
+			continue
+		}
+
+		if claim.ReferencedBy != nil && !equality.Semantic.DeepEqual(claim.ReferencedBy, requested.ReferencedBy) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("referencedBy"), claim.ReferencedBy, fmt.Sprintf("is not requested by the APIExport for %s", claim.String())))
+			continue
+		}
+		if claim.All {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("all"), claim.All, fmt.Sprintf("is broader than requested by the APIExport for %s", claim.String())))
+			continue
+		}
+		for j, selector := range claim.ResourceSelector {
+			found := false
+			for _, requestedSelector := range requested.ResourceSelector {
+				if equality.Semantic.DeepEqual(selector, requestedSelector) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("resourceSelector").Index(j), selector, fmt.Sprintf("is not requested by the APIExport for %s", claim.String())))
+			}
+		}
+	}
+
+	return allErrs
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	"github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/conversion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
@@ -134,11 +135,12 @@ func (o *apiConversionAdmission) ValidateInitialization() error {
 }
 
 func (o *apiConversionAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	schemas := helpers.NewCrossClusterGetter[*apisv1alpha1.APIResourceSchema](
+		apisv1alpha1.Resource("apiresourceschemas"),
+		local.Apis().V1alpha1().APIResourceSchemas().Informer(),
+		global.Apis().V1alpha1().APIResourceSchemas().Informer(),
+	)
 	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
-		apiResourceSchema, err := local.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
-		if err == nil {
-			return apiResourceSchema, nil
-		}
-		return global.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
+		return schemas.Get(clusterName.Path(), name)
 	}
 }
@@ -20,15 +20,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 
 	builtinapiexport "github.com/kcp-dev/kcp/pkg/virtual/apiexport/schemas/builtin"
 	"github.com/kcp-dev/kcp/sdk/apis/apis"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
 )
 
 // PluginName is the name used to identify this admission webhook.
@@ -79,6 +86,10 @@ func (e *APIExportAdmission) Validate(ctx context.Context, a admission.Attribute
 		return fmt.Errorf("failed to convert unstructured to APIExport: %w", err)
 	}
 
+	if errs := validateMaintenanceWindows(ae.Spec.MaintenanceWindows, field.NewPath("spec", "maintenanceWindows")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
 	for i, pc := range ae.Spec.PermissionClaims {
 		if pc.IdentityHash == "" && !e.isBuiltIn(pc.GroupResource) && pc.Group != apis.GroupName {
 			return admission.NewForbidden(a,
@@ -90,7 +101,135 @@ func (e *APIExportAdmission) Validate(ctx context.Context, a admission.Attribute
 					"",
 					"identityHash is required for API types that are not built-in"))
 		}
+
+		var errs field.ErrorList
+		for j, selector := range pc.ResourceSelector {
+			fldPath := field.NewPath("spec").
+				Child("permissionClaims").
+				Index(i).
+				Child("resourceSelector").
+				Index(j)
+			errs = append(errs, validateNamespacePattern(selector.Namespace, fldPath.Child("namespace"))...)
+			errs = append(errs, metav1validation.ValidateLabelSelector(&pc.ResourceSelector[j].LabelSelector, fldPath)...)
+		}
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+	}
+
+	// only check changed claims for ambiguities, in order to not block updates of existing APIExports.
+	if a.GetOperation() == admission.Update {
+		old, ok := a.GetOldObject().(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+		oldExport := &apisv1alpha1.APIExport{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(old.Object, oldExport); err != nil {
+			return fmt.Errorf("failed to convert unstructured to APIExport: %w", err)
+		}
+		if reflect.DeepEqual(oldExport.Spec.PermissionClaims, ae.Spec.PermissionClaims) {
+			return nil
+		}
+	}
+	if errs := ValidatePermissionClaimsUnambiguous(ae.Spec.PermissionClaims, field.NewPath("spec", "permissionClaims")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
 	}
 
 	return nil
 }
+
+// ValidatePermissionClaimsUnambiguous checks that every resource is claimed at most once, and that the resource
+// selectors of a claim neither repeat nor cover each other.
+func ValidatePermissionClaimsUnambiguous(claims []apisv1alpha1.PermissionClaim, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, claim := range claims {
+		for k := 0; k < i; k++ {
+			if !claims[k].Equal(claim) {
+				continue
+			}
+			msg := fmt.Sprintf("%s is already claimed in %s, list all resource selectors in a single claim", claim.String(), fldPath.Index(k))
+			if claim.All != claims[k].All {
+				msg = fmt.Sprintf("%s is claimed both for all objects and by resource selectors in %s, remove the claim by resource selectors or the one with \"all\"", claim.String(), fldPath.Index(k))
+			}
+			errs = append(errs, field.Invalid(fldPath.Index(i), claim.String(), msg))
+			break
+		}
+
+		for j, selector := range claim.ResourceSelector {
+			for k, other := range claim.ResourceSelector {
+				if k == j || !selectorCovers(other, selector) {
+					continue
+				}
+				selectorPath := fldPath.Index(i).Child("resourceSelector")
+				if equality.Semantic.DeepEqual(other, selector) {
+					if k < j {
+						errs = append(errs, field.Duplicate(selectorPath.Index(j), selector))
+						break
+					}
+					continue
+				}
+				if selectorCovers(selector, other) && k > j {
+					// both cover each other, report only the later one.
+					continue
+				}
+				errs = append(errs, field.Invalid(selectorPath.Index(j), selector, fmt.Sprintf("is redundant because %s selects all objects it selects, remove it or narrow down %s", selectorPath.Index(k), selectorPath.Index(k))))
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// selectorCovers returns whether every object matched by selector b is also matched by selector a.
+// It is conservative, i.e. it can return false for selectors that actually cover each other.
+func selectorCovers(a, b apisv1alpha1.ResourceSelector) bool {
+	if a.Namespace != "" && (b.Namespace == "" || !permissionclaims.NamespaceMatches(a.Namespace, b.Namespace)) {
+		return false
+	}
+	if a.Name != "" && a.Name != b.Name {
+		return false
+	}
+	if equality.Semantic.DeepEqual(a.LabelSelector, b.LabelSelector) {
+		return true
+	}
+	if len(a.MatchExpressions) > 0 || len(b.MatchExpressions) > 0 {
+		return false
+	}
+	for k, v := range a.MatchLabels {
+		if bv, ok := b.MatchLabels[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// validateNamespacePattern checks that the namespace of a resource selector is a namespace name,
+// possibly with "*" wildcards standing for any sequence of characters.
+func validateNamespacePattern(pattern string, fldPath *field.Path) field.ErrorList {
+	if pattern == "" {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if pattern != "*" {
+		// wildcards can stand for a single character, so replace them by an allowed one to validate the rest.
+		for _, msg := range validation.IsDNS1123Label(strings.ReplaceAll(pattern, "*", "x")) {
+			errs = append(errs, field.Invalid(fldPath, pattern, msg))
+		}
+	}
+	return errs
+}
+
+// validateMaintenanceWindows checks that the duration of every maintenance window is positive and
+// at most a week, such that the next window is always known.
+func validateMaintenanceWindows(windows []apisv1alpha1.MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, w := range windows {
+		if d := w.Duration.Duration; d <= 0 || d > 7*24*time.Hour {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("duration"), w.Duration.Duration.String(), "must be positive and at most 168h"))
+		}
+	}
+	return errs
+}
@@ -19,6 +19,7 @@ package apiexport
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -73,6 +74,7 @@ func TestAdmission(t *testing.T) {
 		hasIdentity bool
 		isBuiltIn   bool
 		modifyPCs   func([]apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim
+		windows     []apisv1alpha1.MaintenanceWindow
 		want        error
 	}{
 		"NotAPIExportKind": {
@@ -179,6 +181,149 @@ func TestAdmission(t *testing.T) {
 			hasIdentity: true,
 			isBuiltIn:   false,
 		},
+		"ValidCreateLabelSelector": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "my-operator"}},
+				}}
+				return pcs
+			},
+		},
+		"ForbiddenCreateInvalidLabelSelector": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{Name: "foo"}, {
+					LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app.kubernetes.io/managed-by", Operator: metav1.LabelSelectorOpIn},
+					}},
+				}}
+				return pcs
+			},
+			want: field.Required(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(0).
+					Child("resourceSelector").
+					Index(1).
+					Child("matchExpressions").
+					Index(0).
+					Child("values"),
+				"must be specified when `operator` is 'In' or 'NotIn'"),
+		},
+		"ValidCreateNamespacePattern": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{Namespace: "team-a-*"}, {Namespace: "*-staging"}}
+				return pcs
+			},
+		},
+		"ForbiddenCreateDuplicateClaim": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{Name: "foo"}}
+				pcs = append(pcs, *pcs[0].DeepCopy())
+				pcs[1].ResourceSelector = []apisv1alpha1.ResourceSelector{{Name: "bar"}}
+				return pcs
+			},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(1),
+				"somethings.some:coolidentityhash",
+				"somethings.some:coolidentityhash is already claimed in spec.permissionClaims[0], list all resource selectors in a single claim"),
+		},
+		"ForbiddenCreateAllAndSelectorClaims": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].All = true
+				pcs = append(pcs, *pcs[0].DeepCopy())
+				pcs[1].All = false
+				pcs[1].ResourceSelector = []apisv1alpha1.ResourceSelector{{Name: "foo"}}
+				return pcs
+			},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(1),
+				"somethings.some:coolidentityhash",
+				`somethings.some:coolidentityhash is claimed both for all objects and by resource selectors in spec.permissionClaims[0], remove the claim by resource selectors or the one with "all"`),
+		},
+		"ForbiddenCreateDuplicateSelector": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{Namespace: "default", Name: "foo"}, {Namespace: "default", Name: "foo"}}
+				return pcs
+			},
+			want: field.Duplicate(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(0).
+					Child("resourceSelector").
+					Index(1),
+				apisv1alpha1.ResourceSelector{Namespace: "default", Name: "foo"}),
+		},
+		"ForbiddenCreateRedundantSelector": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{
+					{Namespace: "team-a-dev", Name: "foo"},
+					{Namespace: "team-a-*", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}},
+					{Namespace: "team-a-*"},
+				}
+				return pcs
+			},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(0).
+					Child("resourceSelector").
+					Index(0),
+				apisv1alpha1.ResourceSelector{Namespace: "team-a-dev", Name: "foo"},
+				"is redundant because spec.permissionClaims[0].resourceSelector[2] selects all objects it selects, remove it or narrow down spec.permissionClaims[0].resourceSelector[2]"),
+		},
+		"ValidUpdateUnchangedDuplicateClaim": {
+			update:      true,
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].All = true
+				return append(pcs, *pcs[0].DeepCopy())
+			},
+		},
+		"ForbiddenCreateInvalidNamespacePattern": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			modifyPCs: func(pcs []apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim {
+				pcs[0].ResourceSelector = []apisv1alpha1.ResourceSelector{{Namespace: "Team-A-*"}}
+				return pcs
+			},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("permissionClaims").
+					Index(0).
+					Child("resourceSelector").
+					Index(0).
+					Child("namespace"),
+				"Team-A-*",
+				"a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"),
+		},
 		"ValidNoPermissionClaims": {
 			kind:     "APIExport",
 			resource: "apiexports",
@@ -186,6 +331,32 @@ func TestAdmission(t *testing.T) {
 				return []apisv1alpha1.PermissionClaim{}
 			},
 		},
+		"ValidCreateMaintenanceWindow": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			windows: []apisv1alpha1.MaintenanceWindow{{
+				Days:     []apisv1alpha1.MaintenanceWindowDay{"Saturday"},
+				Start:    "22:00",
+				Duration: metav1.Duration{Duration: 6 * time.Hour},
+			}},
+		},
+		"ForbiddenCreateMaintenanceWindowTooLong": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			windows: []apisv1alpha1.MaintenanceWindow{{
+				Start:    "22:00",
+				Duration: metav1.Duration{Duration: 8 * 24 * time.Hour},
+			}},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("maintenanceWindows").
+					Index(0).
+					Child("duration"),
+				"192h0m0s",
+				"must be positive and at most 168h"),
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -210,6 +381,7 @@ func TestAdmission(t *testing.T) {
 			if tc.modifyPCs != nil {
 				ae.Spec.PermissionClaims = tc.modifyPCs(ae.Spec.PermissionClaims)
 			}
+			ae.Spec.MaintenanceWindows = tc.windows
 			var attr admission.Attributes
 			if tc.update {
 				attr = updateAttr("cool-something", ae, tc.kind, tc.resource)
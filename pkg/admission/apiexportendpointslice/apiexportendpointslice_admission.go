@@ -33,13 +33,12 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
-	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	apibindingadmission "github.com/kcp-dev/kcp/pkg/admission/apibinding"
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
-	"github.com/kcp-dev/kcp/pkg/indexers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
 )
@@ -51,15 +50,10 @@ const (
 func Register(plugins *admission.Plugins) {
 	plugins.Register(PluginName,
 		func(_ io.Reader) (admission.Interface, error) {
-			p := &apiExportEndpointSliceAdmission{
+			return &apiExportEndpointSliceAdmission{
 				Handler:          admission.NewHandler(admission.Create, admission.Update),
 				createAuthorizer: delegated.NewDelegatedAuthorizer,
-			}
-			p.getAPIExport = func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
-				return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), p.localApiExportIndexer, p.globalApiExportIndexer, path, name)
-			}
-
-			return p, nil
+			}, nil
 		})
 }
 
@@ -68,9 +62,6 @@ type apiExportEndpointSliceAdmission struct {
 
 	getAPIExport func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
 
-	localApiExportIndexer  cache.Indexer
-	globalApiExportIndexer cache.Indexer
-
 	deepSARClient    kcpkubernetesclientset.ClusterInterface
 	createAuthorizer delegated.DelegatedAuthorizerFactory
 }
@@ -174,6 +165,9 @@ func (o *apiExportEndpointSliceAdmission) ValidateInitialization() error {
 	if o.deepSARClient == nil {
 		return fmt.Errorf(PluginName + " plugin needs a deepSARClient")
 	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIExport getter")
+	}
 	return nil
 }
 
@@ -184,11 +178,11 @@ func (o *apiExportEndpointSliceAdmission) SetDeepSARClient(client kcpkubernetesc
 }
 
 func (o *apiExportEndpointSliceAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
-	localApiExportsReady := local.Apis().V1alpha1().APIExports().Informer().HasSynced
-	globalApiExportsReady := global.Apis().V1alpha1().APIExports().Informer().HasSynced
-	o.SetReadyFunc(func() bool {
-		return localApiExportsReady() && globalApiExportsReady()
-	})
-	o.localApiExportIndexer = local.Apis().V1alpha1().APIExports().Informer().GetIndexer()
-	o.globalApiExportIndexer = global.Apis().V1alpha1().APIExports().Informer().GetIndexer()
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	o.SetReadyFunc(apiExports.HasSynced)
+	o.getAPIExport = apiExports.Get
 }
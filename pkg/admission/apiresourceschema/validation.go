@@ -32,7 +32,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	"k8s.io/apiserver/pkg/util/webhook"
 
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 )
 
@@ -157,11 +160,57 @@ func ValidateAPIResourceSchemaSpec(ctx context.Context, spec *apisv1alpha1.APIRe
 		allErrs = append(allErrs, crdvalidation.ValidateCustomResourceDefinitionNames(&crdNames, fldPath.Child("names"))...)
 	}
 
+	allErrs = append(allErrs, ValidateAPIResourceSchemaConversion(spec.Conversion, fldPath.Child("conversion"))...)
+
 	// TODO(sttts): validate predecessors
 
 	return allErrs
 }
 
+// acceptedConversionReviewVersions are the ConversionReview versions the apiextensions-apiserver can send.
+var acceptedConversionReviewVersions = sets.NewString(apiextensionsv1.SchemeGroupVersion.Version, apiextensionsv1beta1.SchemeGroupVersion.Version)
+
+// ValidateAPIResourceSchemaConversion validates the conversion strategy of an APIResourceSchema.
+func ValidateAPIResourceSchemaConversion(conversion *apisv1alpha1.CustomResourceConversion, fldPath *field.Path) field.ErrorList {
+	if conversion == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	switch conversion.Strategy {
+	case apisv1alpha1.NoneConverter:
+		if conversion.Webhook != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("webhook"), "should not be set when strategy is not set to Webhook"))
+		}
+	case apisv1alpha1.WebhookConverter:
+		if conversion.Webhook == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("webhook"), "required when strategy is set to Webhook"))
+			break
+		}
+		allErrs = append(allErrs, webhook.ValidateWebhookURL(fldPath.Child("webhook", "clientConfig", "url"), conversion.Webhook.ClientConfig.URL, true)...)
+
+		versionsPath := fldPath.Child("webhook", "conversionReviewVersions")
+		if len(conversion.Webhook.ConversionReviewVersions) == 0 {
+			allErrs = append(allErrs, field.Required(versionsPath, ""))
+			break
+		}
+		seen := sets.NewString()
+		for i, v := range conversion.Webhook.ConversionReviewVersions {
+			if seen.Has(v) {
+				allErrs = append(allErrs, field.Invalid(versionsPath.Index(i), v, "duplicate version"))
+			}
+			seen.Insert(v)
+		}
+		if !seen.HasAny(acceptedConversionReviewVersions.List()...) {
+			allErrs = append(allErrs, field.Invalid(versionsPath, conversion.Webhook.ConversionReviewVersions, fmt.Sprintf("must include at least one of %s", strings.Join(acceptedConversionReviewVersions.List(), ", "))))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("strategy"), conversion.Strategy, []string{string(apisv1alpha1.NoneConverter), string(apisv1alpha1.WebhookConverter)}))
+	}
+
+	return allErrs
+}
+
 var defaultValidationOpts = crdvalidation.ValidationOptions{
 	AllowDefaults:                            true,
 	RequireRecognizedConversionReviewVersion: true,
@@ -197,6 +246,11 @@ func ValidateAPIResourceVersion(ctx context.Context, version *apisv1alpha1.APIRe
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("schema"), string(version.Schema.Raw), fmt.Sprintf("invalid schema: %v", err)))
 		} else {
 			allErrs = append(allErrs, crdvalidation.ValidateCustomResourceDefinitionValidation(ctx, &crdSchemaInternal, statusEnabled, defaultValidationOpts, fldPath.Child("schema"))...)
+
+			// Without the feature gate, the rules would silently be dropped from the bound CRDs.
+			if !kcpfeatures.DefaultFeatureGate.Enabled(genericfeatures.CustomResourceValidationExpressions) && crdvalidation.SchemaHas(crdSchemaInternal.OpenAPIV3Schema, hasValidationRules) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("schema"), fmt.Sprintf("x-kubernetes-validations rules require the %s feature gate", genericfeatures.CustomResourceValidationExpressions)))
+			}
 		}
 	}
 
@@ -219,6 +273,10 @@ func ValidateAPIResourceVersion(ctx context.Context, version *apisv1alpha1.APIRe
 	return allErrs
 }
 
+func hasValidationRules(s *apiextensionsinternal.JSONSchemaProps) bool {
+	return len(s.XValidations) > 0
+}
+
 // ValidateAPIResourceSchemaUpdate validates an APIResourceSchema on update.
 func ValidateAPIResourceSchemaUpdate(ctx context.Context, s, old *apisv1alpha1.APIResourceSchema) field.ErrorList {
 	allErrs := ValidateAPIResourceSchema(ctx, s)
@@ -17,8 +17,19 @@ limitations under the License.
 package apiresourceschema
 
 import (
+	"context"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 )
 
 func TestValidationOptionDrift(t *testing.T) {
@@ -45,3 +56,116 @@ func TestValidationOptionDrift(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateAPIResourceSchemaConversion(t *testing.T) {
+	webhookConversion := func(url string, versions ...string) *apisv1alpha1.CustomResourceConversion {
+		return &apisv1alpha1.CustomResourceConversion{
+			Strategy: apisv1alpha1.WebhookConverter,
+			Webhook: &apisv1alpha1.WebhookConversion{
+				ClientConfig:             apisv1alpha1.WebhookClientConfig{URL: url},
+				ConversionReviewVersions: versions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conversion *apisv1alpha1.CustomResourceConversion
+		wantErrs   []string
+	}{
+		{name: "no conversion"},
+		{name: "none", conversion: &apisv1alpha1.CustomResourceConversion{Strategy: apisv1alpha1.NoneConverter}},
+		{
+			name:       "none with webhook",
+			conversion: &apisv1alpha1.CustomResourceConversion{Strategy: apisv1alpha1.NoneConverter, Webhook: &apisv1alpha1.WebhookConversion{}},
+			wantErrs:   []string{"spec.conversion.webhook: Forbidden"},
+		},
+		{name: "webhook", conversion: webhookConversion("https://example.com/convert", "v1", "v1beta1")},
+		{
+			name:       "webhook without webhook",
+			conversion: &apisv1alpha1.CustomResourceConversion{Strategy: apisv1alpha1.WebhookConverter},
+			wantErrs:   []string{"spec.conversion.webhook: Required value"},
+		},
+		{
+			name:       "webhook with http url",
+			conversion: webhookConversion("http://example.com/convert", "v1"),
+			wantErrs:   []string{"spec.conversion.webhook.clientConfig.url: Invalid value"},
+		},
+		{
+			name:       "webhook without review versions",
+			conversion: webhookConversion("https://example.com/convert"),
+			wantErrs:   []string{"spec.conversion.webhook.conversionReviewVersions: Required value"},
+		},
+		{
+			name:       "webhook with unknown review versions",
+			conversion: webhookConversion("https://example.com/convert", "v2", "v2"),
+			wantErrs: []string{
+				"spec.conversion.webhook.conversionReviewVersions[1]: Invalid value",
+				"spec.conversion.webhook.conversionReviewVersions: Invalid value",
+			},
+		},
+		{
+			name:       "unknown strategy",
+			conversion: &apisv1alpha1.CustomResourceConversion{Strategy: "Magic"},
+			wantErrs:   []string{"spec.conversion.strategy: Unsupported value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateAPIResourceSchemaConversion(tt.conversion, field.NewPath("spec", "conversion"))
+			require.Len(t, errs, len(tt.wantErrs), "unexpected errors: %v", errs)
+			for i, want := range tt.wantErrs {
+				require.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidateAPIResourceVersionValidationRules(t *testing.T) {
+	newVersion := func(rule string) *apisv1alpha1.APIResourceVersion {
+		return &apisv1alpha1.APIResourceVersion{
+			Name:    "v1",
+			Served:  true,
+			Storage: true,
+			Schema: runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object",` +
+				`"properties":{"replicas":{"type":"integer"}},"x-kubernetes-validations":[{"rule":"` + rule + `"}]}}}`)},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		rule        string
+		gateEnabled bool
+		wantErrs    []string
+	}{
+		{name: "valid rule", rule: "self.replicas <= 10", gateEnabled: true},
+		{
+			name:        "rule with syntax error",
+			rule:        "self.replicas <=",
+			gateEnabled: true,
+			wantErrs:    []string{"x-kubernetes-validations[0].rule: Invalid value"},
+		},
+		{
+			name:        "rule with unknown field",
+			rule:        "self.unknown <= 10",
+			gateEnabled: true,
+			wantErrs:    []string{"undefined field 'unknown'"},
+		},
+		{
+			name:     "rule without feature gate",
+			rule:     "self.replicas <= 10",
+			wantErrs: []string{"spec.versions[0].schema: Forbidden: x-kubernetes-validations rules require the CustomResourceValidationExpressions feature gate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, tt.gateEnabled)()
+
+			errs := ValidateAPIResourceVersion(context.Background(), newVersion(tt.rule), field.NewPath("spec", "versions").Index(0))
+			require.Len(t, errs, len(tt.wantErrs), "unexpected errors: %v", errs)
+			for i, want := range tt.wantErrs {
+				require.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
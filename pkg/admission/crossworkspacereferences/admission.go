@@ -0,0 +1,338 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crossworkspacereferences contains an admission plugin validating references to objects
+// in other workspaces, for resources whose APIResourceSchema opts in via the
+// apis.kcp.io/cross-workspace-references annotation.
+package crossworkspacereferences
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+)
+
+const (
+	PluginName = "apis.kcp.io/CrossWorkspaceReferences"
+)
+
+// Reference describes a field pair of an object referencing an object in another workspace.
+type Reference struct {
+	// Group is the API group of the referenced objects.
+	Group string `json:"group"`
+	// Resource is the resource of the referenced objects.
+	Resource string `json:"resource"`
+	// PathField is the dot separated path of the field holding the logical cluster path of the
+	// referenced object. An empty field value means the workspace of the referencing object.
+	PathField string `json:"pathField"`
+	// NameField is the dot separated path of the field holding the name of the referenced object.
+	// An empty field value means there is no reference.
+	NameField string `json:"nameField"`
+}
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &crossWorkspaceReferences{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+type crossWorkspaceReferences struct {
+	*admission.Handler
+
+	getAPIBindings       func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	getters              map[schema.GroupResource]func(path logicalcluster.Path, name string) (metav1.Object, error)
+
+	deepSARClient    kcpkubernetesclientset.ClusterInterface
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var (
+	_ = admission.ValidationInterface(&crossWorkspaceReferences{})
+	_ = admission.InitializationValidator(&crossWorkspaceReferences{})
+	_ = kcpinitializers.WantsDeepSARClient(&crossWorkspaceReferences{})
+	_ = kcpinitializers.WantsKcpInformers(&crossWorkspaceReferences{})
+)
+
+// Validate checks that the cross-workspace references of objects of bound resources, whose APIResourceSchema opts in,
+// point to existing objects the requesting user has access to. References are only checked on creation and when
+// they change.
+func (o *crossWorkspaceReferences) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	refs, err := o.referencesFor(clusterName, a.GetResource().GroupResource())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	var old *unstructured.Unstructured
+	if a.GetOperation() == admission.Update {
+		if old, ok = a.GetOldObject().(*unstructured.Unstructured); !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+	}
+
+	var errs field.ErrorList
+	for _, ref := range refs {
+		path, name, err := referenceFrom(u, ref)
+		if err != nil {
+			errs = append(errs, field.Invalid(fieldPath(ref.NameField), name, err.Error()))
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		if old != nil {
+			if oldPath, oldName, err := referenceFrom(old, ref); err == nil && oldPath == path && oldName == name {
+				continue
+			}
+		}
+		if path.Empty() {
+			path = clusterName.Path()
+		}
+
+		// unified error that does not leak workspace existence
+		invalid := field.Invalid(fieldPath(ref.NameField), path.Join(name).String(),
+			fmt.Sprintf("%s %q does not exist in workspace %q or is not accessible", schema.GroupResource{Group: ref.Group, Resource: ref.Resource}, name, path))
+
+		getter, found := o.getters[schema.GroupResource{Group: ref.Group, Resource: ref.Resource}]
+		if !found {
+			errs = append(errs, field.NotSupported(fieldPath(ref.NameField), ref.Resource+"."+ref.Group, supportedResources(o.getters)))
+			continue
+		}
+		obj, err := getter(path, name)
+		if err != nil {
+			errs = append(errs, invalid)
+			continue
+		}
+		if err := o.checkAccess(ctx, a.GetUserInfo(), logicalcluster.From(obj), ref, name); err != nil {
+			errs = append(errs, invalid)
+			continue
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	return nil
+}
+
+// referencesFor returns the references declared by the APIResourceSchema of the given resource if it is bound
+// in the given logical cluster.
+func (o *crossWorkspaceReferences) referencesFor(clusterName logicalcluster.Name, gr schema.GroupResource) ([]Reference, error) {
+	bindings, err := o.getAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		for _, br := range binding.Status.BoundResources {
+			if br.Group != gr.Group || br.ServedResource() != gr.Resource {
+				continue
+			}
+			sch, err := o.getAPIResourceSchema(logicalcluster.Name(binding.Status.APIExportClusterName), br.Schema.Name)
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			} else if err != nil {
+				return nil, err
+			}
+			value, found := sch.Annotations[apisv1alpha1.CrossWorkspaceReferencesAnnotationKey]
+			if !found {
+				return nil, nil
+			}
+			var refs []Reference
+			if err := json.Unmarshal([]byte(value), &refs); err != nil {
+				return nil, fmt.Errorf("invalid %s annotation on APIResourceSchema %s|%s: %w", apisv1alpha1.CrossWorkspaceReferencesAnnotationKey, logicalcluster.From(sch), sch.Name, err)
+			}
+			return refs, nil
+		}
+	}
+	return nil, nil
+}
+
+func (o *crossWorkspaceReferences) checkAccess(ctx context.Context, user user.Info, clusterName logicalcluster.Name, ref Reference, name string) error {
+	logger := klog.FromContext(ctx)
+	authz, err := o.createAuthorizer(clusterName, o.deepSARClient, delegated.Options{})
+	if err != nil {
+		// Logging a more specific error for the operator
+		logger.Error(err, "error creating authorizer from delegating authorizer config")
+		// Returning a less specific error to the end user
+		return fmt.Errorf("unable to authorize request")
+	}
+
+	getAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "get",
+		APIGroup:        ref.Group,
+		Resource:        ref.Resource,
+		Name:            name,
+		ResourceRequest: true,
+	}
+	if decision, _, err := authz.Authorize(ctx, getAttr); err != nil {
+		return fmt.Errorf("unable to determine access to %s: %w", ref.Resource, err)
+	} else if decision != authorizer.DecisionAllow {
+		return fmt.Errorf("no permission to get %s %q", ref.Resource, name)
+	}
+	return nil
+}
+
+// referenceFrom returns the path and name of the given reference in u.
+func referenceFrom(u *unstructured.Unstructured, ref Reference) (logicalcluster.Path, string, error) {
+	name, _, err := unstructured.NestedString(u.Object, strings.Split(ref.NameField, ".")...)
+	if err != nil {
+		return logicalcluster.Path{}, "", err
+	}
+	path, _, err := unstructured.NestedString(u.Object, strings.Split(ref.PathField, ".")...)
+	if err != nil {
+		return logicalcluster.Path{}, "", err
+	}
+	return logicalcluster.NewPath(path), name, nil
+}
+
+func fieldPath(dotted string) *field.Path {
+	parts := strings.Split(dotted, ".")
+	return field.NewPath(parts[0], parts[1:]...)
+}
+
+func supportedResources(getters map[schema.GroupResource]func(path logicalcluster.Path, name string) (metav1.Object, error)) []string {
+	ret := make([]string, 0, len(getters))
+	for gr := range getters {
+		ret = append(ret, gr.String())
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *crossWorkspaceReferences) ValidateInitialization() error {
+	if o.deepSARClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a deepSARClient")
+	}
+	if o.getAPIBindings == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIBinding lister")
+	}
+	return nil
+}
+
+// SetDeepSARClient is an admission plugin initializer function that injects a client capable of deep SAR requests into
+// this admission plugin.
+func (o *crossWorkspaceReferences) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
+	o.deepSARClient = client
+}
+
+func (o *crossWorkspaceReferences) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	apiBindingsInformer := local.Apis().V1alpha1().APIBindings()
+	o.getAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		return apiBindingsInformer.Lister().Cluster(clusterName).List(labels.Everything())
+	}
+
+	apiResourceSchemas := helpers.NewCrossClusterGetter[*apisv1alpha1.APIResourceSchema](
+		apisv1alpha1.Resource("apiresourceschemas"),
+		local.Apis().V1alpha1().APIResourceSchemas().Informer(),
+		global.Apis().V1alpha1().APIResourceSchemas().Informer(),
+	)
+	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemas.Get(clusterName.Path(), name)
+	}
+
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	workspaceTypes := helpers.NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](
+		tenancyv1alpha1.Resource("workspacetypes"),
+		local.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+		global.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+	)
+	syncTargets := helpers.NewCrossClusterGetter[*workloadv1alpha1.SyncTarget](
+		workloadv1alpha1.Resource("synctargets"),
+		local.Workload().V1alpha1().SyncTargets().Informer(),
+		global.Workload().V1alpha1().SyncTargets().Informer(),
+	)
+	locations := helpers.NewCrossClusterGetter[*schedulingv1alpha1.Location](
+		schedulingv1alpha1.Resource("locations"),
+		local.Scheduling().V1alpha1().Locations().Informer(),
+		global.Scheduling().V1alpha1().Locations().Informer(),
+	)
+	o.getters = map[schema.GroupResource]func(path logicalcluster.Path, name string) (metav1.Object, error){
+		apisv1alpha1.Resource("apiexports"): func(path logicalcluster.Path, name string) (metav1.Object, error) {
+			return apiExports.Get(path, name)
+		},
+		tenancyv1alpha1.Resource("workspacetypes"): func(path logicalcluster.Path, name string) (metav1.Object, error) {
+			return workspaceTypes.Get(path, name)
+		},
+		workloadv1alpha1.Resource("synctargets"): func(path logicalcluster.Path, name string) (metav1.Object, error) {
+			return syncTargets.Get(path, name)
+		},
+		schedulingv1alpha1.Resource("locations"): func(path logicalcluster.Path, name string) (metav1.Object, error) {
+			return locations.Get(path, name)
+		},
+	}
+
+	o.SetReadyFunc(func() bool {
+		return apiBindingsInformer.Informer().HasSynced() &&
+			apiResourceSchemas.HasSynced() &&
+			apiExports.HasSynced() &&
+			workspaceTypes.HasSynced() &&
+			syncTargets.HasSynced() &&
+			locations.HasSynced()
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspacereferences
+
+import (
+	"context"
+	"testing"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	marker := `[{"group":"apis.kcp.io","resource":"apiexports","pathField":"spec.export.path","nameField":"spec.export.name"}]`
+
+	newWidget := func(path, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "w"},
+			"spec": map[string]interface{}{
+				"export": map[string]interface{}{"path": path, "name": name},
+			},
+		}}
+	}
+	attr := func(obj, old *unstructured.Unstructured) admission.Attributes {
+		op := admission.Create
+		if old != nil {
+			op = admission.Update
+		}
+		return admission.NewAttributesRecord(obj, old, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, "", "w", widgets, "", op, nil, false, &user.DefaultInfo{Name: "alice"})
+	}
+
+	tests := map[string]struct {
+		marker     string
+		attr       admission.Attributes
+		authorized authorizer.Decision
+
+		wantErr string
+	}{
+		"no marker": {
+			attr: attr(newWidget("root:missing", "foo"), nil),
+		},
+		"existing and accessible": {
+			marker:     marker,
+			attr:       attr(newWidget("root:org", "foo"), nil),
+			authorized: authorizer.DecisionAllow,
+		},
+		"empty name is no reference": {
+			marker: marker,
+			attr:   attr(newWidget("root:org", ""), nil),
+		},
+		"not existing": {
+			marker:     marker,
+			attr:       attr(newWidget("root:org", "bar"), nil),
+			authorized: authorizer.DecisionAllow,
+			wantErr:    `spec.export.name: Invalid value: "root:org:bar": apiexports.apis.kcp.io "bar" does not exist in workspace "root:org" or is not accessible`,
+		},
+		"not accessible": {
+			marker:     marker,
+			attr:       attr(newWidget("root:org", "foo"), nil),
+			authorized: authorizer.DecisionDeny,
+			wantErr:    `apiexports.apis.kcp.io "foo" does not exist in workspace "root:org" or is not accessible`,
+		},
+		"unchanged reference on update": {
+			marker:     marker,
+			attr:       attr(newWidget("root:org", "bar"), newWidget("root:org", "bar")),
+			authorized: authorizer.DecisionDeny,
+		},
+		"changed reference on update": {
+			marker:     marker,
+			attr:       attr(newWidget("root:org", "bar"), newWidget("root:org", "foo")),
+			authorized: authorizer.DecisionAllow,
+			wantErr:    `apiexports.apis.kcp.io "bar" does not exist`,
+		},
+		"unsupported resource": {
+			marker:  `[{"group":"","resource":"secrets","pathField":"spec.export.path","nameField":"spec.export.name"}]`,
+			attr:    attr(newWidget("root:org", "foo"), nil),
+			wantErr: `spec.export.name: Unsupported value: "secrets."`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &crossWorkspaceReferences{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return []*apisv1alpha1.APIBinding{{
+						Status: apisv1alpha1.APIBindingStatus{
+							APIExportClusterName: "provider",
+							BoundResources: []apisv1alpha1.BoundAPIResource{{
+								Group:    "example.com",
+								Resource: "widgets",
+								Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.example.com"},
+							}},
+						},
+					}}, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					sch := &apisv1alpha1.APIResourceSchema{ObjectMeta: metav1.ObjectMeta{Name: name}}
+					if tc.marker != "" {
+						sch.Annotations = map[string]string{apisv1alpha1.CrossWorkspaceReferencesAnnotationKey: tc.marker}
+					}
+					return sch, nil
+				},
+				getters: map[schema.GroupResource]func(path logicalcluster.Path, name string) (metav1.Object, error){
+					apisv1alpha1.Resource("apiexports"): func(path logicalcluster.Path, name string) (metav1.Object, error) {
+						if path.String() == "root:org" && name == "foo" {
+							return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "org"}}}, nil
+						}
+						return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+					},
+				},
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface, opts delegated.Options) (authorizer.Authorizer, error) {
+					require.Equal(t, logicalcluster.Name("org"), clusterName)
+					return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+						return tc.authorized, "", nil
+					}), nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: "consumer"})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
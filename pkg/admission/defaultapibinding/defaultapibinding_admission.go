@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultapibinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	apibindingadmission "github.com/kcp-dev/kcp/pkg/admission/apibinding"
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+)
+
+const (
+	PluginName = "apis.kcp.io/DefaultAPIBinding"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &defaultAPIBindingAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+type defaultAPIBindingAdmission struct {
+	*admission.Handler
+
+	getAPIExport func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+
+	deepSARClient    kcpkubernetesclientset.ClusterInterface
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var (
+	_ = admission.ValidationInterface(&defaultAPIBindingAdmission{})
+	_ = admission.InitializationValidator(&defaultAPIBindingAdmission{})
+	_ = kcpinitializers.WantsDeepSARClient(&defaultAPIBindingAdmission{})
+	_ = kcpinitializers.WantsKcpInformers(&defaultAPIBindingAdmission{})
+)
+
+// Validate performs a SubjectAccessReview on the creation of DefaultAPIBindings and on changes of
+// their export, making sure the user is allowed to use the 'bind' verb with the referenced
+// APIExport. The APIBindings in child workspaces are created by a privileged controller.
+func (o *defaultAPIBindingAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("defaultapibindings") {
+		return nil
+	}
+
+	d, err := toDefaultAPIBinding(a.GetObject())
+	if err != nil {
+		return err
+	}
+	if a.GetOperation() == admission.Update {
+		old, err := toDefaultAPIBinding(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		if old.Spec.Export == d.Spec.Export {
+			return nil
+		}
+	}
+
+	// unified forbidden error that does not leak workspace existence
+	action := "create"
+	if a.GetOperation() == admission.Update {
+		action = "update"
+	}
+	forbidden := admission.NewForbidden(a, fmt.Errorf("unable to %s DefaultAPIBinding: no permission to bind to export %s",
+		action, logicalcluster.NewPath(d.Spec.Export.Path).Join(d.Spec.Export.Name).String()))
+
+	// get cluster name of export
+	var exportClusterName logicalcluster.Name
+	if d.Spec.Export.Path == "" {
+		exportClusterName = clusterName
+	} else {
+		export, err := o.getAPIExport(logicalcluster.NewPath(d.Spec.Export.Path), d.Spec.Export.Name)
+		if err != nil {
+			return forbidden
+		}
+		exportClusterName = logicalcluster.From(export)
+	}
+
+	// Access check
+	if err := o.checkAPIExportAccess(ctx, a.GetUserInfo(), exportClusterName, d.Spec.Export.Name); err != nil {
+		return forbidden
+	}
+
+	return nil
+}
+
+func toDefaultAPIBinding(obj runtime.Object) (*apisv1alpha1.DefaultAPIBinding, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	d := &apisv1alpha1.DefaultAPIBinding{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to DefaultAPIBinding: %w", err)
+	}
+	return d, nil
+}
+
+func (o *defaultAPIBindingAdmission) checkAPIExportAccess(ctx context.Context, user user.Info, apiExportClusterName logicalcluster.Name, apiExportName string) error {
+	logger := klog.FromContext(ctx)
+	authz, err := o.createAuthorizer(apiExportClusterName, o.deepSARClient, delegated.Options{})
+	if err != nil {
+		// Logging a more specific error for the operator
+		logger.Error(err, "error creating authorizer from delegating authorizer config")
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	return apibindingadmission.CheckAPIExportAccess(ctx, user, apiExportName, authz)
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *defaultAPIBindingAdmission) ValidateInitialization() error {
+	if o.deepSARClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a deepSARClient")
+	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIExport getter")
+	}
+	return nil
+}
+
+// SetDeepSARClient is an admission plugin initializer function that injects a client capable of deep SAR requests into
+// this admission plugin.
+func (o *defaultAPIBindingAdmission) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
+	o.deepSARClient = client
+}
+
+func (o *defaultAPIBindingAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	o.SetReadyFunc(apiExports.HasSynced)
+	o.getAPIExport = apiExports.Get
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultapibinding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func newDefaultAPIBinding(path, export string, claims ...apisv1alpha1.PermissionClaim) *apisv1alpha1.DefaultAPIBinding {
+	return &apisv1alpha1.DefaultAPIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root-org-ws"},
+		},
+		Spec: apisv1alpha1.DefaultAPIBindingSpec{
+			Export:                   apisv1alpha1.ExportBindingReference{Path: path, Name: export},
+			AcceptedPermissionClaims: claims,
+		},
+	}
+}
+
+func createAttr(d *apisv1alpha1.DefaultAPIBinding) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(d),
+		nil,
+		apisv1alpha1.Kind("DefaultAPIBinding").WithVersion("v1alpha1"),
+		"",
+		d.Name,
+		apisv1alpha1.Resource("defaultapibindings").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func updateAttr(d, old *apisv1alpha1.DefaultAPIBinding) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(d),
+		helpers.ToUnstructuredOrDie(old),
+		apisv1alpha1.Kind("DefaultAPIBinding").WithVersion("v1alpha1"),
+		"",
+		d.Name,
+		apisv1alpha1.Resource("defaultapibindings").WithVersion("v1alpha1"),
+		"",
+		admission.Update,
+		&metav1.UpdateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	configmaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+
+	tests := []struct {
+		name             string
+		attr             admission.Attributes
+		authzDecision    authorizer.Decision
+		authzError       error
+		wantCheckCluster logicalcluster.Name
+		wantErr          string
+	}{
+		{
+			name:             "Create: local export passes when authorized",
+			attr:             createAttr(newDefaultAPIBinding("", "someExport")),
+			authzDecision:    authorizer.DecisionAllow,
+			wantCheckCluster: "root-org-ws",
+		},
+		{
+			name:             "Create: remote export passes when authorized",
+			attr:             createAttr(newDefaultAPIBinding("root:org", "someExport")),
+			authzDecision:    authorizer.DecisionAllow,
+			wantCheckCluster: "root-org",
+		},
+		{
+			name:          "Create: fails when denied",
+			attr:          createAttr(newDefaultAPIBinding("root:org", "someExport")),
+			authzDecision: authorizer.DecisionDeny,
+			wantErr:       "unable to create DefaultAPIBinding: no permission to bind to export root:org:someExport",
+		},
+		{
+			name:          "Create: fails with no authorization decision",
+			attr:          createAttr(newDefaultAPIBinding("root:org", "someExport")),
+			authzDecision: authorizer.DecisionNoOpinion,
+			wantErr:       "no permission to bind to export root:org:someExport",
+		},
+		{
+			name:          "Create: fails when there's an error checking authorization",
+			attr:          createAttr(newDefaultAPIBinding("root:org", "someExport")),
+			authzDecision: authorizer.DecisionAllow,
+			authzError:    errors.New("some error here"),
+			wantErr:       "no permission to bind to export root:org:someExport",
+		},
+		{
+			name:    "Create: fails without leaking a missing export",
+			attr:    createAttr(newDefaultAPIBinding("root:missing", "someExport")),
+			wantErr: "no permission to bind to export root:missing:someExport",
+		},
+		{
+			name:          "Update: changed export fails when denied",
+			attr:          updateAttr(newDefaultAPIBinding("root:org", "someExport"), newDefaultAPIBinding("", "someExport")),
+			authzDecision: authorizer.DecisionDeny,
+			wantErr:       "unable to update DefaultAPIBinding: no permission to bind to export root:org:someExport",
+		},
+		{
+			name:          "Update: changed claims pass without access check",
+			attr:          updateAttr(newDefaultAPIBinding("root:org", "someExport", configmaps), newDefaultAPIBinding("root:org", "someExport")),
+			authzDecision: authorizer.DecisionDeny,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var checked logicalcluster.Name
+			o := &defaultAPIBindingAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface, opts delegated.Options) (authorizer.Authorizer, error) {
+					checked = clusterName
+					return &fakeAuthorizer{tc.authzDecision, tc.authzError}, nil
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					if path.String() == "root:org" {
+						return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root-org"}}}, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), path.Join(name).String())
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: "root-org-ws"})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCheckCluster, checked)
+		})
+	}
+}
+
+type fakeAuthorizer struct {
+	authorized authorizer.Decision
+	err        error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	return a.authorized, "reason", a.err
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+)
+
+// CrossClusterGetter gets objects of type T by logical cluster path and name, for admission
+// plugins that resolve references to objects in other workspaces, e.g. APIExports or
+// WorkspaceTypes. Objects are looked up in the informer of the local shard first, and in
+// the informer of the cache server, which holds the objects replicated from all shards, if
+// they are not found locally.
+type CrossClusterGetter[T runtime.Object] struct {
+	groupResource schema.GroupResource
+	local, global cache.SharedIndexInformer
+}
+
+// NewCrossClusterGetter returns a CrossClusterGetter for the given local and cache server
+// informers of groupResource. It adds the indexes it depends on to both informers, and hence
+// must be called before they are started.
+func NewCrossClusterGetter[T runtime.Object](groupResource schema.GroupResource, local, global cache.SharedIndexInformer) *CrossClusterGetter[T] {
+	for _, informer := range []cache.SharedIndexInformer{local, global} {
+		indexers.AddIfNotPresentOrDie(informer.GetIndexer(), cache.Indexers{
+			indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+		})
+	}
+
+	return &CrossClusterGetter[T]{
+		groupResource: groupResource,
+		local:         local,
+		global:        global,
+	}
+}
+
+// Get returns the object with the given name in the logical cluster with the given path.
+// The path can be a canonical path or a logical cluster name. A NotFound error is
+// returned if neither informer knows the object.
+func (g *CrossClusterGetter[T]) Get(path logicalcluster.Path, name string) (T, error) {
+	return indexers.ByPathAndNameWithFallback[T](g.groupResource, g.local.GetIndexer(), g.global.GetIndexer(), path, name)
+}
+
+// HasSynced returns true if both informers have synced.
+func (g *CrossClusterGetter[T]) HasSynced() bool {
+	return g.local.HasSynced() && g.global.HasSynced()
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func newWorkspaceType(clusterName, path, name string) *tenancyv1alpha1.WorkspaceType {
+	return &tenancyv1alpha1.WorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:         clusterName,
+				core.LogicalClusterPathAnnotationKey: path,
+			},
+		},
+	}
+}
+
+func TestCrossClusterGetter(t *testing.T) {
+	local := cache.NewSharedIndexInformer(&cache.ListWatch{}, &tenancyv1alpha1.WorkspaceType{}, 0, cache.Indexers{})
+	global := cache.NewSharedIndexInformer(&cache.ListWatch{}, &tenancyv1alpha1.WorkspaceType{}, 0, cache.Indexers{})
+
+	getter := NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](tenancyv1alpha1.Resource("workspacetypes"), local, global)
+	// plugins sharing the informers register the same index again
+	NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](tenancyv1alpha1.Resource("workspacetypes"), local, global)
+
+	require.NoError(t, local.GetIndexer().Add(newWorkspaceType("abc", "root:org", "universal")))
+	require.NoError(t, global.GetIndexer().Add(newWorkspaceType("abc", "root:org", "universal")))
+	require.NoError(t, global.GetIndexer().Add(newWorkspaceType("def", "root:other", "team")))
+
+	tests := map[string]struct {
+		path         logicalcluster.Path
+		name         string
+		wantCluster  string
+		wantNotFound bool
+	}{
+		"local by path":         {path: logicalcluster.NewPath("root:org"), name: "universal", wantCluster: "abc"},
+		"local by cluster name": {path: logicalcluster.NewPath("abc"), name: "universal", wantCluster: "abc"},
+		"cache server fallback": {path: logicalcluster.NewPath("root:other"), name: "team", wantCluster: "def"},
+		"not found":             {path: logicalcluster.NewPath("root:org"), name: "team", wantNotFound: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			wt, err := getter.Get(tt.path, tt.name)
+			if tt.wantNotFound {
+				require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCluster, logicalcluster.From(wt).String())
+			require.Equal(t, tt.name, wt.Name)
+		})
+	}
+}
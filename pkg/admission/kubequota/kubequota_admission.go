@@ -27,6 +27,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/admission/initializer"
@@ -40,6 +41,8 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	"github.com/kcp-dev/kcp/pkg/reconciler/kubequota"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
 	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
@@ -76,7 +79,7 @@ func NewKubeResourceQuota(config *resourcequotaapi.Configuration) *KubeResourceQ
 
 		userSuppliedConfiguration: config,
 
-		delegates: map[logicalcluster.Name]*stoppableQuotaAdmission{},
+		delegates: map[logicalcluster.Name]map[string]*stoppableQuotaAdmission{},
 	}
 }
 
@@ -96,7 +99,7 @@ type KubeResourceQuota struct {
 	userSuppliedConfiguration *resourcequotaapi.Configuration
 
 	lock      sync.RWMutex
-	delegates map[logicalcluster.Name]*stoppableQuotaAdmission
+	delegates map[logicalcluster.Name]map[string]*stoppableQuotaAdmission
 
 	workspaceDeletionMonitorStarter sync.Once
 }
@@ -153,7 +156,12 @@ func (k *KubeResourceQuota) Validate(ctx context.Context, a admission.Attributes
 		return err
 	}
 
-	delegate, err := k.getOrCreateDelegate(cluster.Name)
+	bucket, err := quotaBucket(a)
+	if err != nil {
+		return err
+	}
+
+	delegate, err := k.getOrCreateDelegate(cluster.Name, bucket)
 	if err != nil {
 		return err
 	}
@@ -161,10 +169,53 @@ func (k *KubeResourceQuota) Validate(ctx context.Context, a admission.Attributes
 	return delegate.Validate(ctx, a, o)
 }
 
-// getOrCreateDelegate creates a resourcequota.QuotaAdmission plugin for clusterName.
-func (k *KubeResourceQuota) getOrCreateDelegate(clusterName logicalcluster.Name) (*stoppableQuotaAdmission, error) {
+// quotaBucket returns the quota bucket the object of the request is charged against. Only the
+// APIExport virtual workspace may create objects in a provider bucket or move objects between buckets.
+func quotaBucket(a admission.Attributes) (string, error) {
+	if a.GetObject() == nil {
+		return "", nil
+	}
+	obj, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		// not an object with metadata, nothing to charge against a provider.
+		return "", nil //nolint:nilerr
+	}
+	bucket := kubequota.BucketOf(obj)
+
+	var provider string
+	if a.GetUserInfo() != nil {
+		if values := a.GetUserInfo().GetExtra()[apisv1alpha1.ProviderQuotaUserExtraKey]; len(values) > 0 {
+			provider = values[0]
+		}
+	}
+
+	switch a.GetOperation() {
+	case admission.Create:
+		if bucket != "" && bucket != provider {
+			return "", admission.NewForbidden(a, fmt.Errorf("label %s can only be set through the APIExport virtual workspace", apisv1alpha1.ProviderQuotaLabelKey))
+		}
+	case admission.Update:
+		if a.GetOldObject() == nil {
+			return bucket, nil
+		}
+		old, err := meta.Accessor(a.GetOldObject())
+		if err != nil {
+			return bucket, nil //nolint:nilerr
+		}
+		if oldBucket := kubequota.BucketOf(old); oldBucket != bucket {
+			if provider == "" || (oldBucket != "" && oldBucket != provider) || (bucket != "" && bucket != provider) {
+				return "", admission.NewForbidden(a, fmt.Errorf("label %s can only be changed through the APIExport virtual workspace", apisv1alpha1.ProviderQuotaLabelKey))
+			}
+		}
+	}
+
+	return bucket, nil
+}
+
+// getOrCreateDelegate creates a resourcequota.QuotaAdmission plugin for the given quota bucket of clusterName.
+func (k *KubeResourceQuota) getOrCreateDelegate(clusterName logicalcluster.Name, bucket string) (*stoppableQuotaAdmission, error) {
 	k.lock.RLock()
-	delegate := k.delegates[clusterName]
+	delegate := k.delegates[clusterName][bucket]
 	k.lock.RUnlock()
 
 	if delegate != nil {
@@ -174,7 +225,7 @@ func (k *KubeResourceQuota) getOrCreateDelegate(clusterName logicalcluster.Name)
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
-	delegate = k.delegates[clusterName]
+	delegate = k.delegates[clusterName][bucket]
 	if delegate != nil {
 		return delegate, nil
 	}
@@ -202,8 +253,8 @@ func (k *KubeResourceQuota) getOrCreateDelegate(clusterName logicalcluster.Name)
 		stop:           cancel,
 	}
 
-	delegate.SetResourceQuotaLister(k.scopingResourceQuotaInformer.Cluster(clusterName).Lister())
-	delegate.SetExternalKubeClientSet(k.kubeClusterClient.Cluster(clusterName.Path()))
+	delegate.SetResourceQuotaLister(kubequota.NewBucketResourceQuotaLister(k.scopingResourceQuotaInformer.Cluster(clusterName).Lister(), bucket))
+	delegate.SetExternalKubeClientSet(kubequota.NewBucketKubeClient(k.kubeClusterClient.Cluster(clusterName.Path()), bucket))
 	delegate.SetQuotaConfiguration(k.quotaConfiguration)
 
 	if err := delegate.ValidateInitialization(); err != nil {
@@ -211,7 +262,10 @@ func (k *KubeResourceQuota) getOrCreateDelegate(clusterName logicalcluster.Name)
 		return nil, err
 	}
 
-	k.delegates[clusterName] = delegate
+	if k.delegates[clusterName] == nil {
+		k.delegates[clusterName] = map[string]*stoppableQuotaAdmission{}
+	}
+	k.delegates[clusterName][bucket] = delegate
 
 	return delegate, nil
 }
@@ -225,11 +279,11 @@ func (k *KubeResourceQuota) stopQuotaAdmissionForCluster(clusterName logicalclus
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
-	delegate := k.delegates[clusterName]
+	delegates := k.delegates[clusterName]
 
 	logger := klog.Background().WithValues("clusterName", clusterName)
 
-	if delegate == nil {
+	if delegates == nil {
 		logger.V(3).Info("received event to stop quota admission for logical cluster, but it wasn't in the map")
 		return
 	}
@@ -237,7 +291,9 @@ func (k *KubeResourceQuota) stopQuotaAdmissionForCluster(clusterName logicalclus
 	logger.V(2).Info("stopping quota admission for logical cluster")
 
 	delete(k.delegates, clusterName)
-	delegate.stop()
+	for _, delegate := range delegates {
+		delegate.stop()
+	}
 }
 
 func (k *KubeResourceQuota) SetKubeClusterClient(kubeClusterClient kcpkubernetesclientset.ClusterInterface) {
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubequota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestQuotaBucket(t *testing.T) {
+	configMap := func(bucket string) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+		if bucket != "" {
+			cm.Labels = map[string]string{apisv1alpha1.ProviderQuotaLabelKey: bucket}
+		}
+		return cm
+	}
+	consumer := &user.DefaultInfo{Name: "consumer"}
+	provider := func(bucket string) user.Info {
+		return &user.DefaultInfo{Name: "system:serviceaccount:default:rest", Extra: map[string][]string{apisv1alpha1.ProviderQuotaUserExtraKey: {bucket}}}
+	}
+
+	tests := map[string]struct {
+		op         admission.Operation
+		obj, old   runtime.Object
+		user       user.Info
+		wantBucket string
+		wantErr    bool
+	}{
+		"consumer creates unlabeled object":             {op: admission.Create, obj: configMap(""), user: consumer},
+		"consumer cannot create labeled object":         {op: admission.Create, obj: configMap("abc"), user: consumer, wantErr: true},
+		"provider creates labeled object":               {op: admission.Create, obj: configMap("abc"), user: provider("abc"), wantBucket: "abc"},
+		"provider cannot create object in other bucket": {op: admission.Create, obj: configMap("def"), user: provider("abc"), wantErr: true},
+		"provider creates unlabeled object":             {op: admission.Create, obj: configMap(""), user: provider("abc")},
+		"consumer updates provider object":              {op: admission.Update, obj: configMap("abc"), old: configMap("abc"), user: consumer, wantBucket: "abc"},
+		"consumer cannot remove label":                  {op: admission.Update, obj: configMap(""), old: configMap("abc"), user: consumer, wantErr: true},
+		"consumer cannot add label":                     {op: admission.Update, obj: configMap("abc"), old: configMap(""), user: consumer, wantErr: true},
+		"provider adopts consumer object":               {op: admission.Update, obj: configMap("abc"), old: configMap(""), user: provider("abc"), wantBucket: "abc"},
+		"provider cannot steal from other provider":     {op: admission.Update, obj: configMap("abc"), old: configMap("def"), user: provider("abc"), wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := admission.NewAttributesRecord(tt.obj, tt.old, corev1.SchemeGroupVersion.WithKind("ConfigMap"), "default", "cm", corev1.SchemeGroupVersion.WithResource("configmaps"), "", tt.op, nil, false, tt.user)
+			bucket, err := quotaBucket(a)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBucket, bucket)
+		})
+	}
+}
@@ -46,6 +46,8 @@ import (
 	"github.com/kcp-dev/kcp/pkg/admission/apiexportendpointslice"
 	"github.com/kcp-dev/kcp/pkg/admission/apiresourceschema"
 	"github.com/kcp-dev/kcp/pkg/admission/crdnooverlappinggvr"
+	"github.com/kcp-dev/kcp/pkg/admission/crossworkspacereferences"
+	"github.com/kcp-dev/kcp/pkg/admission/defaultapibinding"
 	"github.com/kcp-dev/kcp/pkg/admission/kubequota"
 	kcplimitranger "github.com/kcp-dev/kcp/pkg/admission/limitranger"
 	"github.com/kcp-dev/kcp/pkg/admission/logicalcluster"
@@ -61,6 +63,10 @@ import (
 	"github.com/kcp-dev/kcp/pkg/admission/shard"
 	kcpvalidatingwebhook "github.com/kcp-dev/kcp/pkg/admission/validatingwebhook"
 	"github.com/kcp-dev/kcp/pkg/admission/workspace"
+	"github.com/kcp-dev/kcp/pkg/admission/workspaceapirestrictions"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacepodsecurity"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacerequest"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacetemplate"
 	"github.com/kcp-dev/kcp/pkg/admission/workspacetype"
 	"github.com/kcp-dev/kcp/pkg/admission/workspacetypeexists"
 )
@@ -71,15 +77,21 @@ var AllOrderedPlugins = beforeWebhooks(kubeapiserveroptions.AllOrderedPlugins,
 	apiresourceschema.PluginName,
 	apiconversion.PluginName,
 	workspace.PluginName,
+	workspacerequest.PluginName,
 	logicalclusterfinalizer.PluginName,
 	shard.PluginName,
 	workspacetype.PluginName,
 	workspacetypeexists.PluginName,
+	workspacetemplate.PluginName,
 	logicalcluster.PluginName,
+	workspaceapirestrictions.PluginName,
+	workspacepodsecurity.PluginName,
 	apiexport.PluginName,
 	apibinding.PluginName,
 	apibindingfinalizer.PluginName,
 	apiexportendpointslice.PluginName,
+	defaultapibinding.PluginName,
+	crossworkspacereferences.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	kcplimitranger.PluginName,
@@ -109,10 +121,14 @@ func beforeWebhooks(recommended []string, plugins ...string) []string {
 func RegisterAllKcpAdmissionPlugins(plugins *admission.Plugins) {
 	kubeapiserveroptions.RegisterAllAdmissionPlugins(plugins)
 	workspace.Register(plugins)
+	workspacerequest.Register(plugins)
 	logicalclusterfinalizer.Register(plugins)
 	shard.Register(plugins)
 	workspacetype.Register(plugins)
 	workspacetypeexists.Register(plugins)
+	workspacetemplate.Register(plugins)
+	workspaceapirestrictions.Register(plugins)
+	workspacepodsecurity.Register(plugins)
 	logicalcluster.Register(plugins)
 	apiresourceschema.Register(plugins)
 	apiexport.Register(plugins)
@@ -120,6 +136,8 @@ func RegisterAllKcpAdmissionPlugins(plugins *admission.Plugins) {
 	apibinding.Register(plugins)
 	apibindingfinalizer.Register(plugins)
 	apiexportendpointslice.Register(plugins)
+	defaultapibinding.Register(plugins)
+	crossworkspacereferences.Register(plugins)
 	workspacenamespacelifecycle.Register(plugins)
 	kcpvalidatingwebhook.Register(plugins)
 	kcpmutatingwebhook.Register(plugins)
@@ -143,10 +161,14 @@ var defaultOnPluginsInKcp = sets.NewString(
 
 	// KCP
 	workspace.PluginName,
+	workspacerequest.PluginName,
 	logicalclusterfinalizer.PluginName,
 	shard.PluginName,
 	workspacetype.PluginName,
 	workspacetypeexists.PluginName,
+	workspacetemplate.PluginName,
+	workspaceapirestrictions.PluginName,
+	workspacepodsecurity.PluginName,
 	logicalcluster.PluginName,
 	apiresourceschema.PluginName,
 	apiexport.PluginName,
@@ -154,6 +176,8 @@ var defaultOnPluginsInKcp = sets.NewString(
 	apibinding.PluginName,
 	apibindingfinalizer.PluginName,
 	apiexportendpointslice.PluginName,
+	defaultapibinding.PluginName,
+	crossworkspacereferences.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	reservedcrdannotations.PluginName,
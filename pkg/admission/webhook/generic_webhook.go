@@ -145,7 +145,7 @@ func (p *WebhookDispatcher) getAPIExportCluster(attr admission.Attributes, clust
 	}
 	for _, apiBinding := range objs {
 		for _, br := range apiBinding.Status.BoundResources {
-			if br.Group == attr.GetResource().Group && br.Resource == attr.GetResource().Resource {
+			if br.Group == attr.GetResource().Group && br.ServedResource() == attr.GetResource().Resource {
 				return logicalcluster.Name(apiBinding.Status.APIExportClusterName), nil
 			}
 		}
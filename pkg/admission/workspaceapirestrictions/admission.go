@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workspaceapirestrictions enforces the API restrictions of WorkspaceTypes in the
+// workspaces of that type.
+package workspaceapirestrictions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	kuser "k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+const (
+	PluginName = "tenancy.kcp.io/WorkspaceAPIRestrictions"
+)
+
+// unrestrictedGroups are the API groups a workspace cannot function without.
+var unrestrictedGroups = sets.NewString(
+	corev1alpha1.SchemeGroupVersion.Group,
+	tenancyv1alpha1.SchemeGroupVersion.Group,
+	apisv1alpha1.SchemeGroupVersion.Group,
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceAPIRestrictions{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// workspaceAPIRestrictions rejects the creation and update of objects of resources that are
+// restricted by the WorkspaceType of the workspace, and APIBindings to APIExports exporting them.
+// Requests of privileged system users are not restricted, so that kcp controllers keep working.
+type workspaceAPIRestrictions struct {
+	*admission.Handler
+
+	getType              func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error)
+	getAPIExport         func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+	logicalClusterLister corev1alpha1listers.LogicalClusterClusterLister
+}
+
+// Ensure that the required admission interfaces are implemented.
+var (
+	_ = admission.ValidationInterface(&workspaceAPIRestrictions{})
+	_ = admission.InitializationValidator(&workspaceAPIRestrictions{})
+	_ = kcpinitializers.WantsKcpInformers(&workspaceAPIRestrictions{})
+)
+
+func (o *workspaceAPIRestrictions) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if sets.NewString(a.GetUserInfo().GetGroups()...).Has(kuser.SystemPrivilegedGroup) {
+		return nil
+	}
+
+	gr := a.GetResource().GroupResource()
+	if unrestrictedGroups.Has(gr.Group) && gr != apisv1alpha1.Resource("apibindings") {
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	restrictions, wt, err := o.restrictionsFor(ctx, clusterName)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if restrictions == nil {
+		return nil
+	}
+	typeName := canonicalPathFrom(wt).Join(wt.Name).String()
+
+	if gr != apisv1alpha1.Resource("apibindings") {
+		if !Allowed(restrictions, gr) {
+			return admission.NewForbidden(a, fmt.Errorf("%s are not allowed in workspaces of type %s", gr, typeName))
+		}
+		return nil
+	}
+
+	// only check on create and reference changes, in order to not block updates of existing APIBindings.
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	binding := &apisv1alpha1.APIBinding{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, binding); err != nil {
+		return fmt.Errorf("failed to convert unstructured to APIBinding: %w", err)
+	}
+	if binding.Spec.Reference.Export == nil {
+		return nil
+	}
+	if a.GetOperation() == admission.Update {
+		old, ok := a.GetOldObject().(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+		oldBinding := &apisv1alpha1.APIBinding{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(old.Object, oldBinding); err != nil {
+			return fmt.Errorf("failed to convert unstructured to APIBinding: %w", err)
+		}
+		if oldBinding.Spec.Reference.Export != nil && *oldBinding.Spec.Reference.Export == *binding.Spec.Reference.Export {
+			return nil
+		}
+	}
+
+	path := logicalcluster.NewPath(binding.Spec.Reference.Export.Path)
+	if path.Empty() {
+		path = clusterName.Path()
+	}
+	export, err := o.getAPIExport(path, binding.Spec.Reference.Export.Name)
+	if err != nil {
+		// the export might show up later, exporting restricted resources.
+		return admission.NewForbidden(a, fmt.Errorf("cannot verify the resources of APIExport %s against the API restrictions of workspace type %s: %w", path.Join(binding.Spec.Reference.Export.Name), typeName, err))
+	}
+	for _, schemaName := range export.Spec.LatestResourceSchemas {
+		exported, ok := parseAPIResourceSchemaName(schemaName)
+		if !ok {
+			return admission.NewForbidden(a, fmt.Errorf("cannot determine the resource of APIResourceSchema %q of APIExport %s", schemaName, path.Join(export.Name)))
+		}
+		if !Allowed(restrictions, exported) {
+			return admission.NewForbidden(a, fmt.Errorf("APIExport %s exports %s, which are not allowed in workspaces of type %s", path.Join(export.Name), exported, typeName))
+		}
+	}
+
+	return nil
+}
+
+// restrictionsFor returns the API restrictions of the type of the given workspace, and the type.
+// Logical clusters without LogicalCluster object or without type are not restricted. A type that
+// cannot be resolved is an error, as its restrictions are unknown.
+func (o *workspaceAPIRestrictions) restrictionsFor(ctx context.Context, clusterName logicalcluster.Name) (*tenancyv1alpha1.APIRestrictions, *tenancyv1alpha1.WorkspaceType, error) {
+	logger := klog.FromContext(ctx)
+
+	logicalCluster, err := o.logicalClusterLister.Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	typeAnnotation, found := logicalCluster.Annotations[tenancyv1alpha1.LogicalClusterTypeAnnotationKey]
+	if !found {
+		return nil, nil, nil
+	}
+	wtPath, wtName := logicalcluster.NewPath(typeAnnotation).Split()
+	if wtPath.Empty() {
+		return nil, nil, fmt.Errorf("invalid workspace type %q", typeAnnotation)
+	}
+	wt, err := o.getType(wtPath, wtName)
+	if err != nil {
+		logger.V(2).Info("failed to resolve workspace type, rejecting request", "type", typeAnnotation, "err", err)
+		return nil, nil, fmt.Errorf("cannot resolve workspace type %s to apply its API restrictions: %w", typeAnnotation, err)
+	}
+	return wt.Spec.APIRestrictions, wt, nil
+}
+
+// Allowed returns whether the group resource can be used under the given restrictions.
+func Allowed(restrictions *tenancyv1alpha1.APIRestrictions, gr schema.GroupResource) bool {
+	if restrictions == nil || unrestrictedGroups.Has(gr.Group) {
+		return true
+	}
+	for _, pattern := range restrictions.Denied {
+		if patternMatches(pattern, gr) {
+			return false
+		}
+	}
+	if len(restrictions.Allowed) == 0 {
+		return true
+	}
+	for _, pattern := range restrictions.Allowed {
+		if patternMatches(pattern, gr) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternMatches(pattern tenancyv1alpha1.GroupResourcePattern, gr schema.GroupResource) bool {
+	return (pattern.Group == "*" || pattern.Group == gr.Group) &&
+		(pattern.Resource == "*" || pattern.Resource == gr.Resource)
+}
+
+// parseAPIResourceSchemaName returns the group resource of an APIResourceSchema name of the
+// form <prefix>.<resource>.<group>, where the core group is named "core".
+func parseAPIResourceSchemaName(name string) (schema.GroupResource, bool) {
+	comps := strings.SplitN(name, ".", 3)
+	if len(comps) < 3 {
+		return schema.GroupResource{}, false
+	}
+	if comps[2] == "core" {
+		comps[2] = ""
+	}
+	return schema.GroupResource{Resource: comps[1], Group: comps[2]}, true
+}
+
+func canonicalPathFrom(wt *tenancyv1alpha1.WorkspaceType) logicalcluster.Path {
+	return logicalcluster.NewPath(wt.Annotations[core.LogicalClusterPathAnnotationKey])
+}
+
+func (o *workspaceAPIRestrictions) ValidateInitialization() error {
+	if o.getType == nil {
+		return fmt.Errorf(PluginName + " plugin needs a WorkspaceType getter")
+	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIExport getter")
+	}
+	if o.logicalClusterLister == nil {
+		return fmt.Errorf(PluginName + " plugin needs a LogicalCluster lister")
+	}
+	return nil
+}
+
+func (o *workspaceAPIRestrictions) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	types := helpers.NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](
+		tenancyv1alpha1.Resource("workspacetypes"),
+		local.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+		global.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+	)
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	logicalClusterReady := local.Core().V1alpha1().LogicalClusters().Informer().HasSynced
+
+	o.SetReadyFunc(func() bool {
+		return types.HasSynced() && apiExports.HasSynced() && logicalClusterReady()
+	})
+
+	o.getType = types.Get
+	o.getAPIExport = apiExports.Get
+	o.logicalClusterLister = local.Core().V1alpha1().LogicalClusters().Lister()
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspaceapirestrictions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		name         string
+		restrictions *tenancyv1alpha1.APIRestrictions
+		gr           schema.GroupResource
+		want         bool
+	}{
+		{name: "no restrictions", gr: schema.GroupResource{Resource: "configmaps"}, want: true},
+		{
+			name:         "denied core resource",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Denied: []tenancyv1alpha1.GroupResourcePattern{{Resource: "secrets"}}},
+			gr:           schema.GroupResource{Resource: "secrets"},
+		},
+		{
+			name:         "denied resource of other group",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Denied: []tenancyv1alpha1.GroupResourcePattern{{Resource: "secrets"}}},
+			gr:           schema.GroupResource{Group: "example.com", Resource: "secrets"},
+			want:         true,
+		},
+		{
+			name:         "denied group wildcard",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Denied: []tenancyv1alpha1.GroupResourcePattern{{Group: "*", Resource: "secrets"}}},
+			gr:           schema.GroupResource{Group: "example.com", Resource: "secrets"},
+		},
+		{
+			name:         "denied resource wildcard",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Denied: []tenancyv1alpha1.GroupResourcePattern{{Group: "apps", Resource: "*"}}},
+			gr:           schema.GroupResource{Group: "apps", Resource: "deployments"},
+		},
+		{
+			name:         "allowed list",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Allowed: []tenancyv1alpha1.GroupResourcePattern{{Resource: "configmaps"}}},
+			gr:           schema.GroupResource{Resource: "configmaps"},
+			want:         true,
+		},
+		{
+			name:         "not in allowed list",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Allowed: []tenancyv1alpha1.GroupResourcePattern{{Resource: "configmaps"}}},
+			gr:           schema.GroupResource{Resource: "secrets"},
+		},
+		{
+			name: "deny wins over allow",
+			restrictions: &tenancyv1alpha1.APIRestrictions{
+				Allowed: []tenancyv1alpha1.GroupResourcePattern{{Group: "*", Resource: "*"}},
+				Denied:  []tenancyv1alpha1.GroupResourcePattern{{Resource: "secrets"}},
+			},
+			gr: schema.GroupResource{Resource: "secrets"},
+		},
+		{
+			name:         "kcp groups are never restricted",
+			restrictions: &tenancyv1alpha1.APIRestrictions{Denied: []tenancyv1alpha1.GroupResourcePattern{{Group: "*", Resource: "*"}}},
+			gr:           tenancyv1alpha1.Resource("workspaces"),
+			want:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Allowed(tt.restrictions, tt.gr))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	restricted := &tenancyv1alpha1.WorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "restricted",
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:         "root-cluster",
+				core.LogicalClusterPathAnnotationKey: "root",
+			},
+		},
+		Spec: tenancyv1alpha1.WorkspaceTypeSpec{
+			APIRestrictions: &tenancyv1alpha1.APIRestrictions{
+				Denied: []tenancyv1alpha1.GroupResourcePattern{{Resource: "secrets"}, {Group: "widgets.example.com", Resource: "*"}},
+			},
+		},
+	}
+	universal := &tenancyv1alpha1.WorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "universal",
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:         "root-cluster",
+				core.LogicalClusterPathAnnotationKey: "root",
+			},
+		},
+	}
+	logicalClusters := []*corev1alpha1.LogicalCluster{
+		newLogicalCluster("root:restricted-ws", "root:restricted"),
+		newLogicalCluster("root:other-ws", "root:universal"),
+		newLogicalCluster("root:deleted-type-ws", "root:deleted"),
+	}
+	exports := []*apisv1alpha1.APIExport{
+		newAPIExport("root:restricted-ws", "widgets", "v1.widgets.widgets.example.com"),
+		newAPIExport("root:restricted-ws", "gadgets", "v1.gadgets.gadgets.example.com"),
+		newAPIExport("root:restricted-ws", "core-secrets", "v1.secrets.core"),
+		newAPIExport("root:restricted-ws", "invalid", "invalid"),
+	}
+
+	tests := []struct {
+		name        string
+		clusterName logicalcluster.Name
+		attr        admission.Attributes
+		wantErr     string
+	}{
+		{
+			name:        "allows unrestricted resource",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}, nil),
+		},
+		{
+			name:        "rejects denied resource",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s"}}, nil),
+			wantErr:     "secrets are not allowed in workspaces of type root:restricted",
+		},
+		{
+			name:        "allows denied resource for privileged users",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s"}}, []string{user.SystemPrivilegedGroup}),
+		},
+		{
+			name:        "allows denied resource in workspaces of other types",
+			clusterName: "root:other-ws",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s"}}, nil),
+		},
+		{
+			name:        "rejects binding to an export of a denied group",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"), helpers.ToUnstructuredOrDie(newAPIBinding("widgets")), nil),
+			wantErr:     "APIExport root:restricted-ws:widgets exports widgets.widgets.example.com",
+		},
+		{
+			name:        "rejects binding to an export of a denied core resource",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"), helpers.ToUnstructuredOrDie(newAPIBinding("core-secrets")), nil),
+			wantErr:     "exports secrets",
+		},
+		{
+			name:        "allows binding to an export of allowed resources",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"), helpers.ToUnstructuredOrDie(newAPIBinding("gadgets")), nil),
+		},
+		{
+			name:        "rejects binding to an unknown export",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"), helpers.ToUnstructuredOrDie(newAPIBinding("unknown")), nil),
+			wantErr:     "cannot verify the resources of APIExport root:restricted-ws:unknown",
+		},
+		{
+			name:        "rejects binding to an export with unparsable schema name",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"), helpers.ToUnstructuredOrDie(newAPIBinding("invalid")), nil),
+			wantErr:     `cannot determine the resource of APIResourceSchema "invalid"`,
+		},
+		{
+			name:        "rejects requests in workspaces of unresolvable type",
+			clusterName: "root:deleted-type-ws",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}, nil),
+			wantErr:     "cannot resolve workspace type root:deleted",
+		},
+		{
+			name:        "allows requests in logical clusters without LogicalCluster",
+			clusterName: "system:admin",
+			attr:        createAttr(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s"}}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &workspaceAPIRestrictions{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getType: func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+					for _, wt := range []*tenancyv1alpha1.WorkspaceType{restricted, universal} {
+						if path == logicalcluster.NewPath("root") && name == wt.Name {
+							return wt, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspacetypes"), name)
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					for _, export := range exports {
+						if logicalcluster.From(export).Path() == path && export.Name == name {
+							return export, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				logicalClusterLister: fakeLogicalClusterClusterLister(logicalClusters),
+			}
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: tt.clusterName})
+			err := o.Validate(ctx, tt.attr, nil)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func createAttr(gvr schema.GroupVersionResource, obj runtime.Object, groups []string) admission.Attributes {
+	return admission.NewAttributesRecord(
+		obj,
+		nil,
+		gvr.GroupVersion().WithKind("Object"),
+		"",
+		"name",
+		gvr,
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Groups: groups},
+	)
+}
+
+func newLogicalCluster(clusterName, typ string) *corev1alpha1.LogicalCluster {
+	return &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:                    clusterName,
+				tenancyv1alpha1.LogicalClusterTypeAnnotationKey: typ,
+			},
+		},
+	}
+}
+
+func newAPIExport(clusterName, name string, schemas ...string) *apisv1alpha1.APIExport {
+	return &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: clusterName},
+		},
+		Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: schemas},
+	}
+}
+
+func newAPIBinding(export string) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding"},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.BindingReference{
+				Export: &apisv1alpha1.ExportBindingReference{Name: export},
+			},
+		},
+	}
+}
+
+type fakeLogicalClusterClusterLister []*corev1alpha1.LogicalCluster
+
+func (l fakeLogicalClusterClusterLister) List(selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l, nil
+}
+
+func (l fakeLogicalClusterClusterLister) Cluster(cluster logicalcluster.Name) corev1alpha1listers.LogicalClusterLister {
+	var perCluster []*corev1alpha1.LogicalCluster
+	for _, logicalCluster := range l {
+		if logicalcluster.From(logicalCluster) == cluster {
+			perCluster = append(perCluster, logicalCluster)
+		}
+	}
+	return fakeLogicalClusterLister(perCluster)
+}
+
+type fakeLogicalClusterLister []*corev1alpha1.LogicalCluster
+
+func (l fakeLogicalClusterLister) List(selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l.ListWithContext(context.Background(), selector)
+}
+
+func (l fakeLogicalClusterLister) ListWithContext(ctx context.Context, selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l, nil
+}
+
+func (l fakeLogicalClusterLister) Get(name string) (*corev1alpha1.LogicalCluster, error) {
+	return l.GetWithContext(context.Background(), name)
+}
+
+func (l fakeLogicalClusterLister) GetWithContext(ctx context.Context, name string) (*corev1alpha1.LogicalCluster, error) {
+	for _, t := range l {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), name)
+}
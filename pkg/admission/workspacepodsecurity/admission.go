@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workspacepodsecurity enforces the pod security level of WorkspaceTypes on pods and pod
+// templates in the workspaces of that type.
+package workspacepodsecurity
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	kuser "k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	podsecurityadmission "k8s.io/pod-security-admission/admission"
+	podsecurityapi "k8s.io/pod-security-admission/api"
+	podsecuritypolicy "k8s.io/pod-security-admission/policy"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+const (
+	PluginName = "tenancy.kcp.io/WorkspacePodSecurity"
+)
+
+// podSpecResources are the resources with pod specs, with the type they are decoded into. In kcp,
+// they are usually bound through APIBindings and arrive as unstructured objects.
+var podSpecResources = map[schema.GroupResource]func() runtime.Object{
+	corev1.Resource("pods"):                   func() runtime.Object { return &corev1.Pod{} },
+	corev1.Resource("replicationcontrollers"): func() runtime.Object { return &corev1.ReplicationController{} },
+	corev1.Resource("podtemplates"):           func() runtime.Object { return &corev1.PodTemplate{} },
+	appsv1.Resource("replicasets"):            func() runtime.Object { return &appsv1.ReplicaSet{} },
+	appsv1.Resource("deployments"):            func() runtime.Object { return &appsv1.Deployment{} },
+	appsv1.Resource("statefulsets"):           func() runtime.Object { return &appsv1.StatefulSet{} },
+	appsv1.Resource("daemonsets"):             func() runtime.Object { return &appsv1.DaemonSet{} },
+	batchv1.Resource("jobs"):                  func() runtime.Object { return &batchv1.Job{} },
+	batchv1.Resource("cronjobs"):              func() runtime.Object { return &batchv1.CronJob{} },
+}
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			evaluator, err := podsecuritypolicy.NewEvaluator(podsecuritypolicy.DefaultChecks())
+			if err != nil {
+				return nil, err
+			}
+			return &workspacePodSecurity{
+				Handler:   admission.NewHandler(admission.Create, admission.Update),
+				evaluator: evaluator,
+			}, nil
+		})
+}
+
+// workspacePodSecurity rejects pods and objects with pod templates that violate the pod security
+// level of the WorkspaceType of the workspace, before they are synced to physical clusters.
+// Requests of privileged system users are not restricted, so that kcp controllers keep working.
+type workspacePodSecurity struct {
+	*admission.Handler
+
+	evaluator podsecuritypolicy.Evaluator
+
+	getType              func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error)
+	logicalClusterLister corev1alpha1listers.LogicalClusterClusterLister
+}
+
+// Ensure that the required admission interfaces are implemented.
+var (
+	_ = admission.ValidationInterface(&workspacePodSecurity{})
+	_ = admission.InitializationValidator(&workspacePodSecurity{})
+	_ = kcpinitializers.WantsKcpInformers(&workspacePodSecurity{})
+)
+
+func (o *workspacePodSecurity) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	newObj, ok := podSpecResources[a.GetResource().GroupResource()]
+	if !ok || a.GetSubresource() != "" {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if sets.NewString(a.GetUserInfo().GetGroups()...).Has(kuser.SystemPrivilegedGroup) {
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	podSecurity, err := o.podSecurityFor(ctx, clusterName)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if podSecurity == nil {
+		return nil
+	}
+	lv, err := levelVersionOf(podSecurity)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if lv.Level == podsecurityapi.LevelPrivileged {
+		return nil
+	}
+
+	podMetadata, podSpec, err := extractPodSpec(a.GetObject(), newObj)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if podSpec == nil {
+		return nil
+	}
+
+	// don't block updates of existing objects that do not touch the pod spec, e.g. of replicas.
+	if a.GetOperation() == admission.Update && a.GetOldObject() != nil {
+		oldPodMetadata, oldPodSpec, err := extractPodSpec(a.GetOldObject(), newObj)
+		if err == nil && oldPodSpec != nil && equality.Semantic.DeepEqual(podSpec, oldPodSpec) &&
+			equality.Semantic.DeepEqual(podMetadata.Labels, oldPodMetadata.Labels) &&
+			equality.Semantic.DeepEqual(podMetadata.Annotations, oldPodMetadata.Annotations) {
+			return nil
+		}
+	}
+
+	result := podsecuritypolicy.AggregateCheckResults(o.evaluator.EvaluatePod(lv, podMetadata, podSpec))
+	if !result.Allowed {
+		return admission.NewForbidden(a, fmt.Errorf("violates PodSecurity %q of the workspace: %s", lv.String(), result.ForbiddenDetail()))
+	}
+
+	return nil
+}
+
+// podSecurityFor returns the pod security of the type of the given workspace. Logical clusters
+// without LogicalCluster object or without type are not restricted. A type that cannot be
+// resolved is an error, as its pod security is unknown.
+func (o *workspacePodSecurity) podSecurityFor(ctx context.Context, clusterName logicalcluster.Name) (*tenancyv1alpha1.WorkspacePodSecurity, error) {
+	logger := klog.FromContext(ctx)
+
+	logicalCluster, err := o.logicalClusterLister.Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	typeAnnotation, found := logicalCluster.Annotations[tenancyv1alpha1.LogicalClusterTypeAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	wtPath, wtName := logicalcluster.NewPath(typeAnnotation).Split()
+	if wtPath.Empty() {
+		return nil, fmt.Errorf("invalid workspace type %q", typeAnnotation)
+	}
+	wt, err := o.getType(wtPath, wtName)
+	if err != nil {
+		logger.V(2).Info("failed to resolve workspace type, rejecting request", "type", typeAnnotation, "err", err)
+		return nil, fmt.Errorf("cannot resolve workspace type %s to apply its pod security: %w", typeAnnotation, err)
+	}
+	return wt.Spec.PodSecurity, nil
+}
+
+func levelVersionOf(podSecurity *tenancyv1alpha1.WorkspacePodSecurity) (podsecurityapi.LevelVersion, error) {
+	level, err := podsecurityapi.ParseLevel(string(podSecurity.Enforce))
+	if err != nil {
+		return podsecurityapi.LevelVersion{}, err
+	}
+	version := podsecurityapi.LatestVersion()
+	if podSecurity.EnforceVersion != "" {
+		if version, err = podsecurityapi.ParseVersion(podSecurity.EnforceVersion); err != nil {
+			return podsecurityapi.LevelVersion{}, err
+		}
+	}
+	return podsecurityapi.LevelVersion{Level: level, Version: version}, nil
+}
+
+// extractPodSpec returns the pod metadata and spec of a typed or unstructured object, decoding the latter
+// into the object returned by newObj.
+func extractPodSpec(obj runtime.Object, newObj func() runtime.Object) (*metav1.ObjectMeta, *corev1.PodSpec, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		typed := newObj()
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", u.GetKind(), err)
+		}
+		obj = typed
+	}
+	return podsecurityadmission.DefaultPodSpecExtractor{}.ExtractPodSpec(obj)
+}
+
+func (o *workspacePodSecurity) ValidateInitialization() error {
+	if o.evaluator == nil {
+		return fmt.Errorf(PluginName + " plugin needs a pod security evaluator")
+	}
+	if o.getType == nil {
+		return fmt.Errorf(PluginName + " plugin needs a WorkspaceType getter")
+	}
+	if o.logicalClusterLister == nil {
+		return fmt.Errorf(PluginName + " plugin needs a LogicalCluster lister")
+	}
+	return nil
+}
+
+func (o *workspacePodSecurity) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	types := helpers.NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](
+		tenancyv1alpha1.Resource("workspacetypes"),
+		local.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+		global.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+	)
+	logicalClusterReady := local.Core().V1alpha1().LogicalClusters().Informer().HasSynced
+
+	o.SetReadyFunc(func() bool {
+		return types.HasSynced() && logicalClusterReady()
+	})
+
+	o.getType = types.Get
+	o.logicalClusterLister = local.Core().V1alpha1().LogicalClusters().Lister()
+}
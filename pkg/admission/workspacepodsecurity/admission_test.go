@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacepodsecurity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	podsecuritypolicy "k8s.io/pod-security-admission/policy"
+
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	newType := func(name string, level tenancyv1alpha1.PodSecurityLevel) *tenancyv1alpha1.WorkspaceType {
+		return &tenancyv1alpha1.WorkspaceType{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					logicalcluster.AnnotationKey:         "root-cluster",
+					core.LogicalClusterPathAnnotationKey: "root",
+				},
+			},
+			Spec: tenancyv1alpha1.WorkspaceTypeSpec{
+				PodSecurity: &tenancyv1alpha1.WorkspacePodSecurity{Enforce: level},
+			},
+		}
+	}
+	types := []*tenancyv1alpha1.WorkspaceType{
+		newType("baseline", tenancyv1alpha1.PodSecurityLevelBaseline),
+		newType("restricted", tenancyv1alpha1.PodSecurityLevelRestricted),
+		newType("universal", ""),
+	}
+	types[2].Spec.PodSecurity = nil
+	logicalClusters := []*corev1alpha1.LogicalCluster{
+		newLogicalCluster("root:baseline-ws", "root:baseline"),
+		newLogicalCluster("root:restricted-ws", "root:restricted"),
+		newLogicalCluster("root:other-ws", "root:universal"),
+		newLogicalCluster("root:deleted-type-ws", "root:deleted"),
+	}
+
+	privileged := true
+	privilegedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:            "c",
+			SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+		}}},
+	}
+	plainPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "c"}}},
+	}
+	deployment := func(pod *corev1.Pod, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "deployment"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec},
+			},
+		}
+	}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+
+	tests := []struct {
+		name        string
+		clusterName logicalcluster.Name
+		attr        admission.Attributes
+		wantErr     string
+	}{
+		{
+			name:        "allows baseline pod in baseline workspace",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(pods, plainPod, nil, nil),
+		},
+		{
+			name:        "rejects privileged pod in baseline workspace",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(pods, privilegedPod, nil, nil),
+			wantErr:     `violates PodSecurity "baseline:latest" of the workspace: privileged`,
+		},
+		{
+			name:        "rejects baseline pod in restricted workspace",
+			clusterName: "root:restricted-ws",
+			attr:        createAttr(pods, plainPod, nil, nil),
+			wantErr:     `violates PodSecurity "restricted:latest"`,
+		},
+		{
+			name:        "rejects unstructured deployment with privileged template",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(deployments, toUnstructured(t, deployment(privilegedPod, 1)), nil, nil),
+			wantErr:     "privileged",
+		},
+		{
+			name:        "allows update of a deployment not touching the template",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(deployments, toUnstructured(t, deployment(privilegedPod, 2)), toUnstructured(t, deployment(privilegedPod, 1)), nil),
+		},
+		{
+			name:        "rejects update of a deployment to a privileged template",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(deployments, toUnstructured(t, deployment(privilegedPod, 1)), toUnstructured(t, deployment(plainPod, 1)), nil),
+			wantErr:     "privileged",
+		},
+		{
+			name:        "allows privileged pod for privileged users",
+			clusterName: "root:baseline-ws",
+			attr:        createAttr(pods, privilegedPod, nil, []string{user.SystemPrivilegedGroup}),
+		},
+		{
+			name:        "allows privileged pod in workspaces of other types",
+			clusterName: "root:other-ws",
+			attr:        createAttr(pods, privilegedPod, nil, nil),
+		},
+		{
+			name:        "rejects pod in workspaces of unresolvable type",
+			clusterName: "root:deleted-type-ws",
+			attr:        createAttr(pods, plainPod, nil, nil),
+			wantErr:     "cannot resolve workspace type root:deleted",
+		},
+		{
+			name:        "allows privileged pod in logical clusters without LogicalCluster",
+			clusterName: "system:admin",
+			attr:        createAttr(pods, privilegedPod, nil, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator, err := podsecuritypolicy.NewEvaluator(podsecuritypolicy.DefaultChecks())
+			require.NoError(t, err)
+			o := &workspacePodSecurity{
+				Handler:   admission.NewHandler(admission.Create, admission.Update),
+				evaluator: evaluator,
+				getType: func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+					for _, wt := range types {
+						if path == logicalcluster.NewPath("root") && name == wt.Name {
+							return wt, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspacetypes"), name)
+				},
+				logicalClusterLister: fakeLogicalClusterClusterLister(logicalClusters),
+			}
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: tt.clusterName})
+			err = o.Validate(ctx, tt.attr, nil)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func createAttr(gvr schema.GroupVersionResource, obj, old runtime.Object, groups []string) admission.Attributes {
+	operation := admission.Create
+	var options runtime.Object = &metav1.CreateOptions{}
+	if old != nil {
+		operation = admission.Update
+		options = &metav1.UpdateOptions{}
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		old,
+		gvr.GroupVersion().WithKind("Object"),
+		"default",
+		"name",
+		gvr,
+		"",
+		operation,
+		options,
+		false,
+		&user.DefaultInfo{Groups: groups},
+	)
+}
+
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: raw}
+}
+
+func newLogicalCluster(clusterName, typ string) *corev1alpha1.LogicalCluster {
+	return &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:                    clusterName,
+				tenancyv1alpha1.LogicalClusterTypeAnnotationKey: typ,
+			},
+		},
+	}
+}
+
+type fakeLogicalClusterClusterLister []*corev1alpha1.LogicalCluster
+
+func (l fakeLogicalClusterClusterLister) List(selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l, nil
+}
+
+func (l fakeLogicalClusterClusterLister) Cluster(cluster logicalcluster.Name) corev1alpha1listers.LogicalClusterLister {
+	var perCluster []*corev1alpha1.LogicalCluster
+	for _, logicalCluster := range l {
+		if logicalcluster.From(logicalCluster) == cluster {
+			perCluster = append(perCluster, logicalCluster)
+		}
+	}
+	return fakeLogicalClusterLister(perCluster)
+}
+
+type fakeLogicalClusterLister []*corev1alpha1.LogicalCluster
+
+func (l fakeLogicalClusterLister) List(selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l.ListWithContext(context.Background(), selector)
+}
+
+func (l fakeLogicalClusterLister) ListWithContext(ctx context.Context, selector labels.Selector) (ret []*corev1alpha1.LogicalCluster, err error) {
+	return l, nil
+}
+
+func (l fakeLogicalClusterLister) Get(name string) (*corev1alpha1.LogicalCluster, error) {
+	return l.GetWithContext(context.Background(), name)
+}
+
+func (l fakeLogicalClusterLister) GetWithContext(ctx context.Context, name string) (*corev1alpha1.LogicalCluster, error) {
+	for _, t := range l {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), name)
+}
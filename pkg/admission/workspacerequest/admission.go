@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacerequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	kuser "k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kcp-dev/kcp/pkg/admission/workspace"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// Validate and admit WorkspaceRequest creation and decisions.
+
+const (
+	PluginName = "tenancy.kcp.io/WorkspaceRequest"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceRequest{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				now:     time.Now,
+			}, nil
+		})
+}
+
+type workspaceRequest struct {
+	*admission.Handler
+
+	now func() time.Time
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ admission.MutationInterface = &workspaceRequest{}
+var _ admission.ValidationInterface = &workspaceRequest{}
+
+// Admit ensures that
+// - the requester is recorded in annotations on create
+// - the status is empty on create
+// - the deciding user and time are recorded when a decision is made.
+func (o *workspaceRequest) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("workspacerequests") {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	req := &tenancyv1alpha1.WorkspaceRequest{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, req); err != nil {
+		return fmt.Errorf("failed to convert unstructured to WorkspaceRequest: %w", err)
+	}
+
+	switch a.GetOperation() {
+	case admission.Create:
+		userInfo, err := workspace.WorkspaceOwnerAnnotationValue(a.GetUserInfo())
+		if err != nil {
+			return admission.NewForbidden(a, err)
+		}
+		if req.Annotations == nil {
+			req.Annotations = map[string]string{}
+		}
+		req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey] = userInfo
+		req.Status = tenancyv1alpha1.WorkspaceRequestStatus{}
+	case admission.Update:
+		if a.GetSubresource() != "status" {
+			return nil
+		}
+		old, err := oldWorkspaceRequest(a)
+		if err != nil {
+			return err
+		}
+		if old.Status.Decision == "" && req.Status.Decision != "" {
+			now := metav1.NewTime(o.now())
+			req.Status.DecidedBy = a.GetUserInfo().GetName()
+			req.Status.DecisionTime = &now
+		}
+	}
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(req)
+	if err != nil {
+		return err
+	}
+	u.Object = raw
+	return nil
+}
+
+// Validate ensures that
+// - the requester is recorded in annotations on create, and it is not changed afterwards
+// - a decision is made only once, and not by the requester
+// - the deciding user and time are not changed.
+func (o *workspaceRequest) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("workspacerequests") {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	req := &tenancyv1alpha1.WorkspaceRequest{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, req); err != nil {
+		return fmt.Errorf("failed to convert unstructured to WorkspaceRequest: %w", err)
+	}
+
+	switch a.GetOperation() {
+	case admission.Create:
+		userInfo, err := workspace.WorkspaceOwnerAnnotationValue(a.GetUserInfo())
+		if err != nil {
+			return admission.NewForbidden(a, err)
+		}
+		if got := req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey]; got != userInfo {
+			return admission.NewForbidden(a, fmt.Errorf("expected user annotation %s=%s", tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey, userInfo))
+		}
+		if req.Status.Decision != "" {
+			return admission.NewForbidden(a, errors.New("status.decision cannot be set on create"))
+		}
+	case admission.Update:
+		old, err := oldWorkspaceRequest(a)
+		if err != nil {
+			return err
+		}
+
+		if req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey] != old.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey] {
+			return admission.NewForbidden(a, fmt.Errorf("annotation %s is immutable", tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey))
+		}
+
+		switch {
+		case old.Status.Decision != "":
+			if req.Status.Decision != old.Status.Decision {
+				return admission.NewForbidden(a, fmt.Errorf("status.decision is immutable once set to %s", old.Status.Decision))
+			}
+			if req.Status.DecidedBy != old.Status.DecidedBy || !req.Status.DecisionTime.Equal(old.Status.DecisionTime) {
+				return admission.NewForbidden(a, errors.New("status.decidedBy and status.decisionTime are immutable"))
+			}
+		case req.Status.Decision != "":
+			if sets.NewString(a.GetUserInfo().GetGroups()...).Has(kuser.SystemPrivilegedGroup) {
+				break
+			}
+			requester, err := requester(old)
+			if err != nil {
+				return admission.NewForbidden(a, err)
+			}
+			if requester.Username == a.GetUserInfo().GetName() {
+				return admission.NewForbidden(a, errors.New("requesters cannot decide on their own WorkspaceRequest"))
+			}
+		default:
+			if req.Status.DecidedBy != "" || req.Status.DecisionTime != nil {
+				return admission.NewForbidden(a, errors.New("status.decidedBy and status.decisionTime cannot be set without status.decision"))
+			}
+		}
+	}
+
+	return nil
+}
+
+func oldWorkspaceRequest(a admission.Attributes) (*tenancyv1alpha1.WorkspaceRequest, error) {
+	u, ok := a.GetOldObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", a.GetOldObject())
+	}
+	old := &tenancyv1alpha1.WorkspaceRequest{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, old); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to WorkspaceRequest: %w", err)
+	}
+	return old, nil
+}
+
+// requester returns the user that created the given WorkspaceRequest.
+func requester(req *tenancyv1alpha1.WorkspaceRequest) (*authenticationv1.UserInfo, error) {
+	raw, found := req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey]
+	if !found {
+		return nil, fmt.Errorf("missing annotation %s", tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey)
+	}
+	var info authenticationv1.UserInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotation %s: %w", tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey, err)
+	}
+	return &info, nil
+}
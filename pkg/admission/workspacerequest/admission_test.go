@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacerequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	kuser "k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+const requesterAnnotation = `{"username":"requester","groups":["team"]}`
+
+var (
+	requesterUser = &kuser.DefaultInfo{Name: "requester", Groups: []string{"team"}}
+	approverUser  = &kuser.DefaultInfo{Name: "approver"}
+	decisionTime  = metav1.NewTime(time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC))
+)
+
+func createAttr(req *tenancyv1alpha1.WorkspaceRequest, info kuser.Info) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(req),
+		nil,
+		tenancyv1alpha1.Kind("WorkspaceRequest").WithVersion("v1alpha1"),
+		"",
+		req.Name,
+		tenancyv1alpha1.Resource("workspacerequests").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		info,
+	)
+}
+
+func updateStatusAttr(req, old *tenancyv1alpha1.WorkspaceRequest, info kuser.Info) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(req),
+		helpers.ToUnstructuredOrDie(old),
+		tenancyv1alpha1.Kind("WorkspaceRequest").WithVersion("v1alpha1"),
+		"",
+		req.Name,
+		tenancyv1alpha1.Resource("workspacerequests").WithVersion("v1alpha1"),
+		"status",
+		admission.Update,
+		&metav1.UpdateOptions{},
+		false,
+		info,
+	)
+}
+
+type builder struct {
+	*tenancyv1alpha1.WorkspaceRequest
+}
+
+func newRequest() builder {
+	return builder{WorkspaceRequest: &tenancyv1alpha1.WorkspaceRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-ws",
+			Annotations: map[string]string{tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey: requesterAnnotation},
+		},
+		Spec: tenancyv1alpha1.WorkspaceRequestSpec{
+			WorkspaceName: "team-ws",
+			Justification: "we need a place for our APIs",
+		},
+	}}
+}
+
+func (b builder) decided(decision tenancyv1alpha1.WorkspaceRequestDecision, by string) builder {
+	b.Status.Decision = decision
+	b.Status.DecidedBy = by
+	if by != "" {
+		b.Status.DecisionTime = decisionTime.DeepCopy()
+	}
+	return b
+}
+
+func TestAdmit(t *testing.T) {
+	tests := map[string]struct {
+		a           admission.Attributes
+		expectedObj *tenancyv1alpha1.WorkspaceRequest
+	}{
+		"records the requester and clears the status on create": {
+			a: createAttr(func() *tenancyv1alpha1.WorkspaceRequest {
+				req := newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "").WorkspaceRequest
+				req.Annotations = nil
+				return req
+			}(), requesterUser),
+			expectedObj: newRequest().WorkspaceRequest,
+		},
+		"records the approver on decision": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "").WorkspaceRequest,
+				newRequest().WorkspaceRequest,
+				approverUser,
+			),
+			expectedObj: newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest,
+		},
+		"keeps an existing decision": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestRejected, "approver").WorkspaceRequest,
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestRejected, "approver").WorkspaceRequest,
+				&kuser.DefaultInfo{Name: "someone-else"},
+			),
+			expectedObj: newRequest().decided(tenancyv1alpha1.WorkspaceRequestRejected, "approver").WorkspaceRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &workspaceRequest{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				now:     func() time.Time { return decisionTime.Time },
+			}
+			err := o.Admit(context.Background(), tt.a, nil)
+			require.NoError(t, err)
+
+			got := &tenancyv1alpha1.WorkspaceRequest{}
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(tt.a.GetObject().(*unstructured.Unstructured).Object, got))
+			expected := &tenancyv1alpha1.WorkspaceRequest{}
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(helpers.ToUnstructuredOrDie(tt.expectedObj).Object, expected))
+			require.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		a       admission.Attributes
+		wantErr bool
+	}{
+		"create with requester annotation": {
+			a: createAttr(newRequest().WorkspaceRequest, requesterUser),
+		},
+		"create with foreign requester annotation": {
+			a:       createAttr(newRequest().WorkspaceRequest, approverUser),
+			wantErr: true,
+		},
+		"create with decision": {
+			a:       createAttr(newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "").WorkspaceRequest, requesterUser),
+			wantErr: true,
+		},
+		"approve": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest,
+				newRequest().WorkspaceRequest,
+				approverUser,
+			),
+		},
+		"self-approve": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "requester").WorkspaceRequest,
+				newRequest().WorkspaceRequest,
+				requesterUser,
+			),
+			wantErr: true,
+		},
+		"self-approve as privileged system user": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "requester").WorkspaceRequest,
+				newRequest().WorkspaceRequest,
+				&kuser.DefaultInfo{Name: "requester", Groups: []string{kuser.SystemPrivilegedGroup}},
+			),
+		},
+		"change decision": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest,
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestRejected, "approver").WorkspaceRequest,
+				approverUser,
+			),
+			wantErr: true,
+		},
+		"change approver": {
+			a: updateStatusAttr(
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "someone-else").WorkspaceRequest,
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest,
+				approverUser,
+			),
+			wantErr: true,
+		},
+		"update phase after decision": {
+			a: updateStatusAttr(
+				func() *tenancyv1alpha1.WorkspaceRequest {
+					req := newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest
+					req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhaseCompleted
+					return req
+				}(),
+				newRequest().decided(tenancyv1alpha1.WorkspaceRequestApproved, "approver").WorkspaceRequest,
+				&kuser.DefaultInfo{Name: "system:kcp", Groups: []string{kuser.SystemPrivilegedGroup}},
+			),
+		},
+		"set approver without decision": {
+			a: updateStatusAttr(
+				newRequest().decided("", "approver").WorkspaceRequest,
+				newRequest().WorkspaceRequest,
+				approverUser,
+			),
+			wantErr: true,
+		},
+		"change requester annotation": {
+			a: updateStatusAttr(
+				func() *tenancyv1alpha1.WorkspaceRequest {
+					req := newRequest().WorkspaceRequest
+					req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey] = `{"username":"approver"}`
+					return req
+				}(),
+				newRequest().WorkspaceRequest,
+				approverUser,
+			),
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &workspaceRequest{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				now:     func() time.Time { return decisionTime.Time },
+			}
+			err := o.Validate(context.Background(), tt.a, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	kuser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apibindingadmission "github.com/kcp-dev/kcp/pkg/admission/apibinding"
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.io/WorkspaceTemplate"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceTemplate{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// workspaceTemplate authorizes the creation of Workspaces from a WorkspaceTemplate. The objects of
+// the template are created by a privileged controller, hence the user creating the Workspace must
+// be allowed to create them.
+type workspaceTemplate struct {
+	*admission.Handler
+
+	getWorkspaceTemplate func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error)
+	getAPIExport         func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+
+	deepSARClient    kcpkubernetesclientset.ClusterInterface
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var (
+	_ = admission.MutationInterface(&workspaceTemplate{})
+	_ = admission.ValidationInterface(&workspaceTemplate{})
+	_ = admission.InitializationValidator(&workspaceTemplate{})
+	_ = kcpinitializers.WantsDeepSARClient(&workspaceTemplate{})
+	_ = kcpinitializers.WantsKcpInformers(&workspaceTemplate{})
+)
+
+// Admit records the generation of the WorkspaceTemplate on Workspaces created from it.
+func (o *workspaceTemplate) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("workspaces") || a.GetOperation() != admission.Create {
+		return nil
+	}
+
+	u, ws, err := toWorkspace(a.GetObject())
+	if err != nil {
+		return err
+	}
+	name, found := ws.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]
+	if !found {
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	template, err := o.getWorkspaceTemplate(clusterName, name)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to use workspace template %q: %w", name, err))
+	}
+	ws.Annotations[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey] = strconv.FormatInt(template.Generation, 10)
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ws)
+	if err != nil {
+		return err
+	}
+	u.Object = raw
+	return nil
+}
+
+// Validate ensures that
+//   - the user creating a Workspace from a WorkspaceTemplate may use the template, bind its
+//     APIExports, and create and bind its ClusterRoles, the latter checked in the workspace of
+//     the template as the new workspace does not exist yet,
+//   - the template annotations of a Workspace are immutable.
+func (o *workspaceTemplate) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("workspaces") {
+		return nil
+	}
+
+	_, ws, err := toWorkspace(a.GetObject())
+	if err != nil {
+		return err
+	}
+
+	switch a.GetOperation() {
+	case admission.Update:
+		_, old, err := toWorkspace(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		if sets.NewString(a.GetUserInfo().GetGroups()...).Has(kuser.SystemPrivilegedGroup) {
+			return nil
+		}
+		for _, key := range []string{tenancyv1alpha1.WorkspaceTemplateAnnotationKey, tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey} {
+			if old.Annotations[key] != ws.Annotations[key] {
+				return admission.NewForbidden(a, fmt.Errorf("annotation %s is immutable", key))
+			}
+		}
+	case admission.Create:
+		name, found := ws.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]
+		if !found {
+			return nil
+		}
+
+		if !o.WaitForReady() {
+			return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+		}
+
+		template, err := o.getWorkspaceTemplate(clusterName, name)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("unable to use workspace template %q: %w", name, err))
+		}
+		if got := ws.Annotations[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey]; got != strconv.FormatInt(template.Generation, 10) {
+			return admission.NewForbidden(a, fmt.Errorf("workspace template %q has changed, please retry", name))
+		}
+		if err := o.authorizeTemplate(ctx, a.GetUserInfo(), clusterName, template); err != nil {
+			return admission.NewForbidden(a, err)
+		}
+	}
+
+	return nil
+}
+
+// authorizeTemplate checks that the user may use the template and create its objects.
+func (o *workspaceTemplate) authorizeTemplate(ctx context.Context, user kuser.Info, clusterName logicalcluster.Name, template *tenancyv1alpha1.WorkspaceTemplate) error {
+	authz, err := o.createAuthorizer(clusterName, o.deepSARClient, delegated.Options{})
+	if err != nil {
+		return fmt.Errorf("unable to determine access to workspace template %q", template.Name)
+	}
+
+	checks := []authorizer.AttributesRecord{{
+		User:            user,
+		Verb:            "use",
+		APIGroup:        tenancyv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      tenancyv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "workspacetemplates",
+		Name:            template.Name,
+		ResourceRequest: true,
+	}}
+	if len(template.Spec.ClusterRoles) > 0 {
+		checks = append(checks, authorizer.AttributesRecord{
+			User:            user,
+			Verb:            "escalate",
+			APIGroup:        rbacv1.GroupName,
+			APIVersion:      rbacv1.SchemeGroupVersion.Version,
+			Resource:        "clusterroles",
+			ResourceRequest: true,
+		})
+	}
+	for _, b := range template.Spec.ClusterRoleBindings {
+		checks = append(checks, authorizer.AttributesRecord{
+			User:            user,
+			Verb:            "bind",
+			APIGroup:        rbacv1.GroupName,
+			APIVersion:      rbacv1.SchemeGroupVersion.Version,
+			Resource:        "clusterroles",
+			Name:            b.ClusterRole,
+			ResourceRequest: true,
+		})
+	}
+	for _, attr := range checks {
+		attr := attr
+		if decision, _, err := authz.Authorize(ctx, &attr); err != nil {
+			return fmt.Errorf("unable to determine access to workspace template %q: %w", template.Name, err)
+		} else if decision != authorizer.DecisionAllow {
+			resource := attr.Resource
+			if attr.Name != "" {
+				resource = fmt.Sprintf("%s %q", attr.Resource, attr.Name)
+			}
+			return fmt.Errorf("unable to use workspace template %q: missing verb=%q permission on %s", template.Name, attr.Verb, resource)
+		}
+	}
+
+	for _, bundle := range template.Spec.APIBindings {
+		// unified forbidden error that does not leak workspace existence
+		forbidden := fmt.Errorf("unable to use workspace template %q: no permission to bind to export %s",
+			template.Name, logicalcluster.NewPath(bundle.Path).Join(bundle.Export).String())
+
+		exportClusterName := clusterName
+		if bundle.Path != "" {
+			export, err := o.getAPIExport(logicalcluster.NewPath(bundle.Path), bundle.Export)
+			if err != nil {
+				return forbidden
+			}
+			exportClusterName = logicalcluster.From(export)
+		}
+
+		exportAuthz, err := o.createAuthorizer(exportClusterName, o.deepSARClient, delegated.Options{})
+		if err != nil {
+			return errors.New("unable to authorize request")
+		}
+		if err := apibindingadmission.CheckAPIExportAccess(ctx, user, bundle.Export, exportAuthz); err != nil {
+			return forbidden
+		}
+	}
+
+	return nil
+}
+
+func toWorkspace(obj runtime.Object) (*unstructured.Unstructured, *tenancyv1alpha1.Workspace, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	ws := &tenancyv1alpha1.Workspace{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, ws); err != nil {
+		return nil, nil, fmt.Errorf("failed to convert unstructured to Workspace: %w", err)
+	}
+	return u, ws, nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *workspaceTemplate) ValidateInitialization() error {
+	if o.deepSARClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a deepSARClient")
+	}
+	if o.getWorkspaceTemplate == nil {
+		return fmt.Errorf(PluginName + " plugin needs a WorkspaceTemplate getter")
+	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIExport getter")
+	}
+	return nil
+}
+
+// SetDeepSARClient is an admission plugin initializer function that injects a client capable of deep SAR requests into
+// this admission plugin.
+func (o *workspaceTemplate) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
+	o.deepSARClient = client
+}
+
+func (o *workspaceTemplate) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	templatesReady := local.Tenancy().V1alpha1().WorkspaceTemplates().Informer().HasSynced
+	templateLister := local.Tenancy().V1alpha1().WorkspaceTemplates().Lister()
+	apiExports := helpers.NewCrossClusterGetter[*apisv1alpha1.APIExport](
+		apisv1alpha1.Resource("apiexports"),
+		local.Apis().V1alpha1().APIExports().Informer(),
+		global.Apis().V1alpha1().APIExports().Informer(),
+	)
+	o.SetReadyFunc(func() bool {
+		return templatesReady() && apiExports.HasSynced()
+	})
+	o.getWorkspaceTemplate = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error) {
+		return templateLister.Cluster(clusterName).Get(name)
+	}
+	o.getAPIExport = apiExports.Get
+}
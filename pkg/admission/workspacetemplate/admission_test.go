@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"testing"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func workspace(annotations ...string) *tenancyv1alpha1.Workspace {
+	ws := &tenancyv1alpha1.Workspace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: tenancyv1alpha1.SchemeGroupVersion.String(), Kind: "Workspace"},
+		ObjectMeta: metav1.ObjectMeta{Name: "child", Annotations: map[string]string{}},
+	}
+	for i := 0; i+1 < len(annotations); i += 2 {
+		ws.Annotations[annotations[i]] = annotations[i+1]
+	}
+	return ws
+}
+
+func attr(op admission.Operation, ws, old *tenancyv1alpha1.Workspace) admission.Attributes {
+	var oldObj *unstructured.Unstructured
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(ws),
+		oldObj,
+		tenancyv1alpha1.Kind("Workspace").WithVersion("v1alpha1"),
+		"",
+		ws.Name,
+		tenancyv1alpha1.Resource("workspaces").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestAdmit(t *testing.T) {
+	o := &workspaceTemplate{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+		getWorkspaceTemplate: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error) {
+			return &tenancyv1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 3}}, nil
+		},
+	}
+	o.SetReadyFunc(func() bool { return true })
+
+	ctx := request.WithCluster(context.Background(), request.Cluster{Name: "parent"})
+	a := attr(admission.Create, workspace(tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "1"), nil)
+	require.NoError(t, o.Admit(ctx, a, nil))
+	got := a.GetObject().(*unstructured.Unstructured).GetAnnotations()
+	require.Equal(t, "3", got[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey])
+}
+
+func TestValidate(t *testing.T) {
+	templates := map[string]*tenancyv1alpha1.WorkspaceTemplate{
+		"team": {
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Generation: 2},
+			Spec: tenancyv1alpha1.WorkspaceTemplateSpec{
+				APIBindings: []tenancyv1alpha1.APIBindingClaimBundle{
+					{APIExportReference: tenancyv1alpha1.APIExportReference{Export: "widgets"}},
+					{APIExportReference: tenancyv1alpha1.APIExportReference{Path: "root:org", Export: "gadgets"}},
+				},
+				ClusterRoles: []tenancyv1alpha1.WorkspaceTemplateClusterRole{{Name: "widget-editor"}},
+				ClusterRoleBindings: []tenancyv1alpha1.WorkspaceTemplateClusterRoleBinding{
+					{Name: "team-a", ClusterRole: "widget-editor", Subjects: []rbacv1.Subject{{Kind: "Group", Name: "team-a"}}},
+				},
+			},
+		},
+	}
+	templated := workspace(tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "2")
+	all := []string{"parent/use/workspacetemplates/team", "parent/escalate/clusterroles/", "parent/bind/clusterroles/widget-editor", "parent/bind/apiexports/widgets", "root-org/bind/apiexports/gadgets"}
+
+	tests := map[string]struct {
+		attr    admission.Attributes
+		allowed []string
+		wantErr string
+	}{
+		"workspace without template": {
+			attr: attr(admission.Create, workspace(), nil),
+		},
+		"all allowed": {
+			attr:    attr(admission.Create, templated, nil),
+			allowed: all,
+		},
+		"template not allowed to use": {
+			attr:    attr(admission.Create, templated, nil),
+			allowed: all[1:],
+			wantErr: `missing verb="use" permission on workspacetemplates "team"`,
+		},
+		"cluster roles not allowed to escalate": {
+			attr:    attr(admission.Create, templated, nil),
+			allowed: []string{all[0], all[2], all[3], all[4]},
+			wantErr: `missing verb="escalate" permission on clusterroles`,
+		},
+		"cluster role not allowed to bind": {
+			attr:    attr(admission.Create, templated, nil),
+			allowed: []string{all[0], all[1], all[3], all[4]},
+			wantErr: `missing verb="bind" permission on clusterroles "widget-editor"`,
+		},
+		"remote export not allowed to bind": {
+			attr:    attr(admission.Create, templated, nil),
+			allowed: all[:4],
+			wantErr: "no permission to bind to export root:org:gadgets",
+		},
+		"missing template": {
+			attr:    attr(admission.Create, workspace(tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "missing"), nil),
+			allowed: all,
+			wantErr: `unable to use workspace template "missing"`,
+		},
+		"changed template": {
+			attr:    attr(admission.Create, workspace(tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "1"), nil),
+			allowed: all,
+			wantErr: `workspace template "team" has changed`,
+		},
+		"template annotation changed on update": {
+			attr:    attr(admission.Update, workspace(tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "other"), workspace()),
+			wantErr: "annotation tenancy.kcp.io/template is immutable",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			allowed := sets.NewString(tt.allowed...)
+			o := &workspaceTemplate{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface, opts delegated.Options) (authorizer.Authorizer, error) {
+					return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+						if allowed.Has(clusterName.String() + "/" + a.GetVerb() + "/" + a.GetResource() + "/" + a.GetName()) {
+							return authorizer.DecisionAllow, "", nil
+						}
+						return authorizer.DecisionNoOpinion, "", nil
+					}), nil
+				},
+				getWorkspaceTemplate: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error) {
+					if template, found := templates[name]; found && clusterName == "parent" {
+						return template, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspacetemplates"), name)
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					if path.String() == "root:org" {
+						return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root-org"}}}, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+			}
+			o.SetReadyFunc(func() bool { return true })
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: "parent"})
+			err := o.Validate(ctx, tt.attr, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -34,11 +34,10 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
-	"k8s.io/client-go/tools/cache"
 
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
-	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
@@ -57,10 +56,9 @@ func Register(plugins *admission.Plugins) {
 				Handler:          admission.NewHandler(admission.Create, admission.Update),
 				createAuthorizer: delegated.NewDelegatedAuthorizer,
 			}
-			plugin.getType = func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
-				return indexers.ByPathAndNameWithFallback[*tenancyv1alpha1.WorkspaceType](tenancyv1alpha1.Resource("workspacetypes"), plugin.typeIndexer, plugin.globalTypeIndexer, path, name)
-			}
-			plugin.transitiveTypeResolver = NewTransitiveTypeResolver(plugin.getType)
+			plugin.transitiveTypeResolver = NewTransitiveTypeResolver(func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+				return plugin.getType(path, name)
+			})
 
 			return plugin, nil
 		})
@@ -75,9 +73,6 @@ type workspacetypeExists struct {
 
 	getType func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error)
 
-	typeIndexer       cache.Indexer
-	globalTypeIndexer cache.Indexer
-
 	logicalClusterLister corev1alpha1listers.LogicalClusterClusterLister
 
 	transitiveTypeResolver TransitiveTypeResolver
@@ -173,6 +168,16 @@ func (o *workspacetypeExists) Admit(ctx context.Context, a admission.Attributes,
 
 	addAdditionalWorkspaceLabels(wt, ws)
 
+	// inherit the metadata the parent workspace propagates to its children
+	delete(ws.Annotations, tenancyv1alpha1.InheritedMetadataAnnotationKey)
+	inherited, err := PropagatedMetadataFrom(logicalCluster.Annotations, tenancyv1alpha1.PropagatedMetadataAnnotationKey)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if _, err := ApplyInheritedMetadata(ws, inherited); err != nil {
+		return admission.NewForbidden(a, err)
+	}
+
 	return updateUnstructured(u, ws)
 }
 
@@ -327,11 +332,8 @@ func (o *workspacetypeExists) Validate(ctx context.Context, a admission.Attribut
 }
 
 func (o *workspacetypeExists) ValidateInitialization() error {
-	if o.typeIndexer == nil {
-		return fmt.Errorf(PluginName + " plugin needs a WorkspaceType indexer")
-	}
-	if o.globalTypeIndexer == nil {
-		return fmt.Errorf(PluginName + " plugin needs a global WorkspaceType indexer")
+	if o.getType == nil {
+		return fmt.Errorf(PluginName + " plugin needs a WorkspaceType getter")
 	}
 	if o.logicalClusterLister == nil {
 		return fmt.Errorf(PluginName + " plugin needs a LogicalCluster lister")
@@ -340,27 +342,19 @@ func (o *workspacetypeExists) ValidateInitialization() error {
 }
 
 func (o *workspacetypeExists) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
-	localTypesReady := local.Tenancy().V1alpha1().WorkspaceTypes().Informer().HasSynced
-	globalTypesReady := global.Tenancy().V1alpha1().WorkspaceTypes().Informer().HasSynced
-
+	types := helpers.NewCrossClusterGetter[*tenancyv1alpha1.WorkspaceType](
+		tenancyv1alpha1.Resource("workspacetypes"),
+		local.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+		global.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+	)
 	logicalClusterReady := local.Core().V1alpha1().LogicalClusters().Informer().HasSynced
 
 	o.SetReadyFunc(func() bool {
-		return localTypesReady() && globalTypesReady() && logicalClusterReady()
+		return types.HasSynced() && logicalClusterReady()
 	})
 
-	o.typeIndexer = local.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer()
-	o.globalTypeIndexer = global.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer()
-
+	o.getType = types.Get
 	o.logicalClusterLister = local.Core().V1alpha1().LogicalClusters().Lister()
-
-	indexers.AddIfNotPresentOrDie(local.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-	})
-
-	indexers.AddIfNotPresentOrDie(global.Tenancy().V1alpha1().WorkspaceTypes().Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-	})
 }
 
 func (o *workspacetypeExists) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
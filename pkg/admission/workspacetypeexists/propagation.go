@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetypeexists
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// PropagatedMetadata is the value of the tenancyv1alpha1.PropagatedMetadataAnnotationKey
+// and tenancyv1alpha1.InheritedMetadataAnnotationKey annotations.
+type PropagatedMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PropagatedMetadataFrom decodes the metadata stored in the annotation with the given key.
+// A missing annotation decodes as empty metadata. Keys with a reserved prefix are dropped, such
+// that they are neither set nor removed by propagation.
+func PropagatedMetadataFrom(annotations map[string]string, key string) (*PropagatedMetadata, error) {
+	m := &PropagatedMetadata{}
+	value, found := annotations[key]
+	if !found || value == "" {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(value), m); err != nil {
+		return nil, fmt.Errorf("failed to decode %s annotation: %w", key, err)
+	}
+	for k := range m.Labels {
+		if isReservedKey(k) {
+			delete(m.Labels, k)
+		}
+	}
+	for k := range m.Annotations {
+		if isReservedKey(k) {
+			delete(m.Annotations, k)
+		}
+	}
+	return m, nil
+}
+
+// Encode returns the annotation value of the metadata, or an empty string if there is none.
+func (m *PropagatedMetadata) Encode() (string, error) {
+	if len(m.Labels) == 0 && len(m.Annotations) == 0 {
+		return "", nil
+	}
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// PropagatedMetadataFor returns the metadata a workspace propagates to its children, i.e. the
+// metadata it inherited itself and the labels and annotations selected by its type. Keys with
+// a reserved prefix, e.g. kcp.io/cluster or the phase and owner metadata of tenancy.kcp.io,
+// are never propagated.
+func PropagatedMetadataFor(ws *tenancyv1alpha1.Workspace, wt *tenancyv1alpha1.WorkspaceType, inherited *PropagatedMetadata) *PropagatedMetadata {
+	m := &PropagatedMetadata{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+	}
+	if wt != nil && wt.Spec.PropagatedMetadata != nil {
+		for _, key := range wt.Spec.PropagatedMetadata.Labels {
+			if isReservedKey(key) {
+				continue
+			}
+			if value, found := ws.Labels[key]; found {
+				m.Labels[key] = value
+			}
+		}
+		for _, key := range wt.Spec.PropagatedMetadata.Annotations {
+			if isReservedKey(key) {
+				continue
+			}
+			if value, found := ws.Annotations[key]; found {
+				m.Annotations[key] = value
+			}
+		}
+	}
+
+	// inherited metadata wins, descendants cannot override it.
+	for key, value := range inherited.Labels {
+		m.Labels[key] = value
+	}
+	for key, value := range inherited.Annotations {
+		m.Annotations[key] = value
+	}
+
+	return m
+}
+
+// ApplyInheritedMetadata sets the inherited labels and annotations on the workspace, and removes
+// those that were inherited before, but are not anymore. It returns whether the workspace changed.
+func ApplyInheritedMetadata(ws *tenancyv1alpha1.Workspace, inherited *PropagatedMetadata) (bool, error) {
+	previous, err := PropagatedMetadataFrom(ws.Annotations, tenancyv1alpha1.InheritedMetadataAnnotationKey)
+	if err != nil {
+		// overwritten below
+		previous = &PropagatedMetadata{}
+	}
+	value, err := inherited.Encode()
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for key, old := range previous.Labels {
+		if _, found := inherited.Labels[key]; !found && ws.Labels[key] == old {
+			delete(ws.Labels, key)
+			changed = true
+		}
+	}
+	for key, old := range previous.Annotations {
+		if _, found := inherited.Annotations[key]; !found && ws.Annotations[key] == old {
+			delete(ws.Annotations, key)
+			changed = true
+		}
+	}
+
+	for key, value := range inherited.Labels {
+		if current, found := ws.Labels[key]; found && current == value {
+			continue
+		}
+		if ws.Labels == nil {
+			ws.Labels = map[string]string{}
+		}
+		ws.Labels[key] = value
+		changed = true
+	}
+	for key, value := range inherited.Annotations {
+		if current, found := ws.Annotations[key]; found && current == value {
+			continue
+		}
+		if ws.Annotations == nil {
+			ws.Annotations = map[string]string{}
+		}
+		ws.Annotations[key] = value
+		changed = true
+	}
+
+	if ws.Annotations[tenancyv1alpha1.InheritedMetadataAnnotationKey] != value {
+		if value == "" {
+			delete(ws.Annotations, tenancyv1alpha1.InheritedMetadataAnnotationKey)
+		} else {
+			if ws.Annotations == nil {
+				ws.Annotations = map[string]string{}
+			}
+			ws.Annotations[tenancyv1alpha1.InheritedMetadataAnnotationKey] = value
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// isReservedKey returns whether the label or annotation key has the prefix of kcp.io, or of one
+// of its subdomains. Those keys are owned by kcp, e.g. kcp.io/cluster, the owner and phase metadata
+// of tenancy.kcp.io, or authorization.kcp.io/required-groups, and must not be set by propagation.
+func isReservedKey(key string) bool {
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return false
+	}
+	prefix := key[:i]
+	return prefix == "kcp.io" || strings.HasSuffix(prefix, ".kcp.io")
+}
@@ -19,10 +19,12 @@ package bootstrap
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
 	rbacv1helpers "k8s.io/kubernetes/pkg/apis/rbac/v1"
 	rbacrest "k8s.io/kubernetes/pkg/registry/rbac/rest"
 	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac/bootstrappolicy"
 
+	"github.com/kcp-dev/kcp/sdk/apis/apis"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	"github.com/kcp-dev/kcp/sdk/apis/tenancy"
 )
@@ -41,9 +43,16 @@ const (
 	SystemLogicalClusterAdmin = "system:kcp:logical-cluster-admin"
 	// SystemExternalLogicalClusterAdmin is a group used by the workspace controllers to manage LogicalCluster
 	// resources after creation, using a subset of permissions allowed for the internal logical-cluster-admin.
+	// It is also used to publish the APIBindings of a shard in the status of APIExports on other shards.
 	SystemExternalLogicalClusterAdmin = "system:kcp:external-logical-cluster-admin"
 	// SystemKcpWorkspaceAccessGroup is a group that gives a user system:authenticated access to a workspace.
 	SystemKcpWorkspaceAccessGroup = "system:kcp:workspace:access"
+	// SystemKcpDiscoveryChanges is the cluster role allowing authenticated users with access to a workspace
+	// to watch the discovery changes of the workspace.
+	SystemKcpDiscoveryChanges = "system:kcp:discovery-changes"
+	// SystemKcpFeatureInfo is the cluster role allowing authenticated users with access to a workspace
+	// to read the features of the serving shard.
+	SystemKcpFeatureInfo = "system:kcp:feature-info"
 )
 
 // ClusterRoleBindings return default rolebindings to the default roles.
@@ -53,6 +62,8 @@ func clusterRoleBindings() []rbacv1.ClusterRoleBinding {
 		clusterRoleBindingCustomName(rbacv1helpers.NewClusterBinding(SystemKcpWorkspaceBootstrapper).Groups(SystemKcpWorkspaceBootstrapper, "apis.kcp.io:binding:"+SystemKcpWorkspaceBootstrapper).BindingOrDie(), SystemKcpWorkspaceBootstrapper),
 		clusterRoleBindingCustomName(rbacv1helpers.NewClusterBinding(SystemLogicalClusterAdmin).Groups(SystemLogicalClusterAdmin).BindingOrDie(), SystemLogicalClusterAdmin),
 		clusterRoleBindingCustomName(rbacv1helpers.NewClusterBinding(SystemExternalLogicalClusterAdmin).Groups(SystemExternalLogicalClusterAdmin).BindingOrDie(), SystemExternalLogicalClusterAdmin),
+		clusterRoleBindingCustomName(rbacv1helpers.NewClusterBinding(SystemKcpDiscoveryChanges).Groups(user.AllAuthenticated).BindingOrDie(), SystemKcpDiscoveryChanges),
+		clusterRoleBindingCustomName(rbacv1helpers.NewClusterBinding(SystemKcpFeatureInfo).Groups(user.AllAuthenticated).BindingOrDie(), SystemKcpFeatureInfo),
 	}
 }
 
@@ -85,9 +96,22 @@ func clusterRoles() []rbacv1.ClusterRole {
 			Rules: []rbacv1.PolicyRule{
 				rbacv1helpers.NewRule("delete", "update", "get").Groups(core.GroupName).Resources("logicalclusters", "logicalclusters/status").RuleOrDie(),
 				rbacv1helpers.NewRule("delete", "update", "get").Groups(tenancy.GroupName).Resources("workspaces").RuleOrDie(),
+				rbacv1helpers.NewRule("get", "patch").Groups(apis.GroupName).Resources("apiexports/status").RuleOrDie(),
 				rbacv1helpers.NewRule("access").URLs("/").RuleOrDie(),
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: SystemKcpDiscoveryChanges},
+			Rules: []rbacv1.PolicyRule{
+				rbacv1helpers.NewRule("get").URLs("/discoverychanges").RuleOrDie(),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: SystemKcpFeatureInfo},
+			Rules: []rbacv1.PolicyRule{
+				rbacv1helpers.NewRule("get").URLs("/featureinfo").RuleOrDie(),
+			},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: SystemKcpWorkspaceAccessGroup},
 			Rules: []rbacv1.PolicyRule{
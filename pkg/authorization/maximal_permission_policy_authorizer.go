@@ -125,10 +125,12 @@ func (a *MaximalPermissionPolicyAuthorizer) Authorize(ctx context.Context, attr
 		return authorizer.DecisionNoOpinion, MaximalPermissionPolicyAccessNotPermittedReason, fmt.Errorf("error getting APIBindings: %w", err)
 	}
 	var relevantBinding *apisv1alpha1.APIBinding
+	var relevantResource string
 	for _, binding := range bindings {
 		for _, br := range binding.Status.BoundResources {
-			if br.Group == attr.GetAPIGroup() && br.Resource == attr.GetResource() {
+			if br.Group == attr.GetAPIGroup() && br.ServedResource() == attr.GetResource() {
 				relevantBinding = binding
+				relevantResource = br.Resource
 				break
 			}
 		}
@@ -161,6 +163,8 @@ func (a *MaximalPermissionPolicyAuthorizer) Authorize(ctx context.Context, attr
 	// If bound, create a rbac authorizer filtered to the cluster.
 	clusterAuthorizer := a.newAuthorizer(logicalcluster.From(apiExport))
 	prefixedAttr := deepCopyAttributes(attr)
+	// the policy is written for the resource of the APIExport, not for an alias it is served as.
+	prefixedAttr.Resource = relevantResource
 	userInfo := prefixedAttr.User.(*user.DefaultInfo)
 	userInfo.Name = apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + userInfo.Name
 	userInfo.Groups = make([]string, 0, len(attr.GetUser().GetGroups()))
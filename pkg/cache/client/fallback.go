@@ -0,0 +1,306 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	fallbackActive = compbasemetrics.NewGauge(
+		&compbasemetrics.GaugeOpts{
+			Name:           "cache_client_local_fallback_active",
+			Help:           "Whether reads of cache clients are served by the local shard because the cache server is unreachable (1) or not (0).",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+	)
+	fallbackTransitions = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "cache_client_local_fallback_transitions_total",
+			Help:           "Number of transitions between reading from the cache server and falling back to the local shard.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"mode"},
+	)
+	registerFallbackMetrics sync.Once
+)
+
+// LocalFallback serves the reads of cache clients from the local shard while the cache
+// server is unreachable. Writes always go to the cache server.
+//
+// The objects of the local shard are a subset of those in the cache server, with resource
+// versions unrelated to those of the cache server. Hence, when switching between both, watches
+// whose last list was served by the other side are answered with 410 Gone, forcing informers
+// to relist, and resource versions of lists are dropped.
+type LocalFallback struct {
+	local    http.RoundTripper
+	localURL *url.URL
+	probe    func(ctx context.Context) error
+
+	lock     sync.Mutex
+	degraded bool
+	// lists records per path whether the last list was served by the local shard.
+	lists map[string]bool
+	// watches are the bodies of watches served by the local shard, closed on recovery.
+	watches map[*fallbackBody]struct{}
+}
+
+// NewLocalFallback returns a fallback to the shard of the given config. The probe is called
+// while the cache server is unreachable, bypassing the fallback, and is to succeed once it is
+// reachable again.
+func NewLocalFallback(localConfig *rest.Config, probe func(ctx context.Context) error) (*LocalFallback, error) {
+	registerFallbackMetrics.Do(func() {
+		legacyregistry.MustRegister(fallbackActive)
+		legacyregistry.MustRegister(fallbackTransitions)
+	})
+
+	local, err := rest.TransportFor(localConfig)
+	if err != nil {
+		return nil, err
+	}
+	localURL, _, err := rest.DefaultServerURL(localConfig.Host, "", schema.GroupVersion{}, rest.IsConfigTransportTLS(*localConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalFallback{
+		local:    local,
+		localURL: localURL,
+		probe:    probe,
+		lists:    map[string]bool{},
+		watches:  map[*fallbackBody]struct{}{},
+	}, nil
+}
+
+// WithLocalFallbackRoundTripper wraps an existing config's with a round tripper falling back to
+// the local shard. It must be the outermost wrapper of the cache client config.
+//
+// Note: it is the caller responsibility to make a copy of the rest config.
+func WithLocalFallbackRoundTripper(cfg *rest.Config, fallback *LocalFallback) *rest.Config {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &localFallbackRoundTripper{delegate: rt, fallback: fallback}
+	})
+
+	return cfg
+}
+
+// Degraded returns whether reads are currently served by the local shard.
+func (f *LocalFallback) Degraded() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.degraded
+}
+
+// Start probes the cache server in the given interval while degraded, switching back to it
+// once the probe succeeds, until the context is done.
+func (f *LocalFallback) Start(ctx context.Context, interval time.Duration) {
+	logger := klog.FromContext(ctx).WithValues("component", "cache-local-fallback")
+	ctx = klog.NewContext(ctx, logger)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if !f.Degraded() {
+			return
+		}
+		if err := f.probe(ctx); err != nil {
+			logger.V(4).Info("cache server still unreachable", "err", err)
+			return
+		}
+		f.recover(ctx)
+	}, interval)
+}
+
+func (f *LocalFallback) degrade(ctx context.Context, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.degraded {
+		return
+	}
+	f.degraded = true
+	fallbackActive.Set(1)
+	fallbackTransitions.WithLabelValues("local").Inc()
+	klog.FromContext(ctx).Error(err, "cache server unreachable, serving reads from the local shard")
+}
+
+func (f *LocalFallback) recover(ctx context.Context) {
+	f.lock.Lock()
+	watches := f.watches
+	f.watches = map[*fallbackBody]struct{}{}
+	f.degraded = false
+	f.lock.Unlock()
+
+	fallbackActive.Set(0)
+	fallbackTransitions.WithLabelValues("cache").Inc()
+	klog.FromContext(ctx).Info("cache server reachable again, serving reads from the cache server", "watches", len(watches))
+
+	// closing the watches makes informers re-establish them against the cache server,
+	// where they are answered with 410 Gone and relist.
+	for body := range watches {
+		body.ReadCloser.Close()
+	}
+}
+
+// prepare records that a request is served by the local shard or the cache server. It
+// returns a 410 Gone response for watches whose last list was served by the other side.
+func (f *LocalFallback) prepare(req *http.Request, local bool) (*http.Request, *http.Response) {
+	key := req.URL.Path
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	lastLocal, listed := f.lists[key]
+	if isWatch(req) {
+		if listed && lastLocal != local {
+			return nil, goneResponse(req)
+		}
+		return req, nil
+	}
+
+	f.lists[key] = local
+	if !listed || lastLocal == local {
+		return req, nil
+	}
+	q := req.URL.Query()
+	if _, found := q["resourceVersion"]; !found {
+		return req, nil
+	}
+	q.Del("resourceVersion")
+	q.Del("resourceVersionMatch")
+	req = req.Clone(req.Context())
+	req.URL.RawQuery = q.Encode()
+	return req, nil
+}
+
+func (f *LocalFallback) serveLocally(req *http.Request) (*http.Response, error) {
+	req, resp := f.prepare(req, true)
+	if resp != nil {
+		return resp, nil
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = f.localURL.Scheme
+	req.URL.Host = f.localURL.Host
+	req.URL.Path = strings.TrimSuffix(f.localURL.Path, "/") + req.URL.Path
+	req.Host = ""
+
+	resp, err := f.local.RoundTrip(req)
+	if err != nil || !isWatch(req) {
+		return resp, err
+	}
+
+	body := &fallbackBody{ReadCloser: resp.Body, fallback: f}
+	f.lock.Lock()
+	if !f.degraded {
+		// recovered in the meantime.
+		f.lock.Unlock()
+		resp.Body.Close()
+		return goneResponse(req), nil
+	}
+	f.watches[body] = struct{}{}
+	f.lock.Unlock()
+	resp.Body = body
+	return resp, nil
+}
+
+// localFallbackRoundTripper sends reads to the local shard while the cache server is unreachable.
+type localFallbackRoundTripper struct {
+	delegate http.RoundTripper
+	fallback *LocalFallback
+}
+
+func (rt *localFallbackRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	f := rt.fallback
+	if !f.Degraded() {
+		cacheReq, resp := f.prepare(req, false)
+		if resp != nil {
+			return resp, nil
+		}
+		resp, err := rt.delegate.RoundTrip(cacheReq)
+		if err == nil || req.Context().Err() != nil {
+			// responses, including errors, mean that the cache server is reachable.
+			return resp, err
+		}
+		f.degrade(req.Context(), err)
+	}
+
+	return f.serveLocally(req)
+}
+
+// fallbackBody is the body of a watch served by the local shard.
+type fallbackBody struct {
+	io.ReadCloser
+	fallback *LocalFallback
+}
+
+func (b *fallbackBody) Close() error {
+	b.fallback.lock.Lock()
+	delete(b.fallback.watches, b)
+	b.fallback.lock.Unlock()
+	return b.ReadCloser.Close()
+}
+
+func isWatch(req *http.Request) bool {
+	switch req.URL.Query().Get("watch") {
+	case "true", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// goneResponse returns a 410 Gone response, making reflectors relist.
+func goneResponse(req *http.Request) *http.Response {
+	status := apierrors.NewResourceExpired("the cache client switched between the cache server and the local shard").ErrStatus
+	status.APIVersion, status.Kind = "v1", "Status"
+	body, err := json.Marshal(status)
+	if err != nil {
+		body = []byte(fmt.Sprintf(`{"kind":"Status","apiVersion":"v1","status":"Failure","code":410,"reason":"%s"}`, metav1.StatusReasonExpired))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusGone, http.StatusText(http.StatusGone)),
+		StatusCode:    http.StatusGone,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/rest"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLocalFallback(t *testing.T) {
+	var lastQuery atomic.Value
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			lastQuery.Store(name + "?" + req.URL.RawQuery)
+			w.WriteHeader(http.StatusOK)
+			if req.URL.Query().Get("watch") != "true" {
+				return
+			}
+			w.(http.Flusher).Flush()
+			<-req.Context().Done()
+		}
+	}
+	cache := httptest.NewServer(handler("cache"))
+	defer cache.Close()
+	local := httptest.NewServer(handler("local"))
+	defer local.Close()
+
+	fallback, err := NewLocalFallback(&rest.Config{Host: local.URL}, func(ctx context.Context) error { return nil })
+	require.NoError(t, err)
+
+	var down atomic.Bool
+	cfg := &rest.Config{Host: cache.URL}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if down.Load() {
+				return nil, errors.New("connection refused")
+			}
+			return rt.RoundTrip(req)
+		})
+	})
+	cfg = WithLocalFallbackRoundTripper(cfg, fallback)
+	client, err := rest.HTTPClientFor(cfg)
+	require.NoError(t, err)
+
+	get := func(query string) *http.Response {
+		t.Helper()
+		resp, err := client.Get(cache.URL + "/clusters/*/apis/apis.kcp.io/v1alpha1/apiexports?" + query)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Log("Reads are served by the cache server while it is reachable")
+	resp := get("resourceVersion=5")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "cache?resourceVersion=5", lastQuery.Load())
+	require.False(t, fallback.Degraded())
+
+	t.Log("Writes are not served by the local shard")
+	down.Store(true)
+	_, err = client.Post(cache.URL+"/clusters/root/apis/apis.kcp.io/v1alpha1/apiexports", "application/json", nil) //nolint:bodyclose
+	require.Error(t, err)
+	require.False(t, fallback.Degraded())
+
+	t.Log("Reads fall back to the local shard, dropping the resource version of the cache server")
+	resp = get("resourceVersion=5")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "local?", lastQuery.Load())
+	require.True(t, fallback.Degraded())
+
+	t.Log("Watches are served by the local shard")
+	watch := get("watch=true&resourceVersion=7")
+	defer watch.Body.Close()
+	require.Equal(t, http.StatusOK, watch.StatusCode)
+	require.Equal(t, "local?watch=true&resourceVersion=7", lastQuery.Load())
+
+	t.Log("Watches of the local shard are closed on recovery")
+	down.Store(false)
+	fallback.recover(context.Background())
+	require.False(t, fallback.Degraded())
+	_, err = io.ReadAll(watch.Body)
+	require.Error(t, err)
+
+	t.Log("Watches of the cache server are expired until relisting")
+	resp = get("watch=true&resourceVersion=7")
+	resp.Body.Close()
+	require.Equal(t, http.StatusGone, resp.StatusCode)
+
+	resp = get("resourceVersion=7")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "cache?", lastQuery.Load())
+
+	resp = get("watch=true&resourceVersion=9")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "cache?watch=true&resourceVersion=9", lastQuery.Load())
+}
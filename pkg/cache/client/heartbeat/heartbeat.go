@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package heartbeat implements the client side of the shard heartbeats of the cache server.
+// A cache server with a shard TTL purges the objects of shards that have not sent a heartbeat,
+// nor written any object, within the TTL. Heartbeats also carry the replication state of the
+// shard, which the cache server exposes as replication lag metrics.
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+// Path is the path of the heartbeat endpoint of the cache server, below the shard prefix.
+const Path = "/heartbeat"
+
+// Heartbeat is the optional body of a heartbeat request.
+type Heartbeat struct {
+	// Resources is the replication state of the replicated resources of the shard.
+	Resources []ResourceState `json:"resources,omitempty"`
+}
+
+// ResourceState is the replication state of one resource of a shard.
+type ResourceState struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+
+	// ObservedResourceVersion is the highest resource version of the resource the shard has observed.
+	ObservedResourceVersion int64 `json:"observedResourceVersion"`
+	// Pending is the number of observed objects not replicated to the cache server yet.
+	Pending int `json:"pending"`
+	// OldestPendingResourceVersion is the resource version of the oldest pending change.
+	// Zero if nothing is pending.
+	OldestPendingResourceVersion int64 `json:"oldestPendingResourceVersion,omitempty"`
+	// OldestPendingAgeSeconds is the time since the oldest pending change has been observed.
+	// Zero if nothing is pending.
+	OldestPendingAgeSeconds float64 `json:"oldestPendingAgeSeconds,omitempty"`
+}
+
+// Start sends a heartbeat for the given shard to the cache server every interval until the
+// context is done. The config must be a cache server client config as returned by the cache
+// client options, i.e. adding the /services/cache prefix and the shard from the context to
+// request paths. If state is not nil, the returned replication state is sent with each heartbeat.
+func Start(ctx context.Context, config *rest.Config, shardName string, interval time.Duration, state func() []ResourceState) error {
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return err
+	}
+	u, _, err := rest.DefaultServerURL(config.Host, "", schema.GroupVersion{}, true)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, Path)
+	url := u.String()
+
+	logger := klog.FromContext(ctx).WithValues("shard", shardName)
+	ctx = cacheclient.WithShardInContext(ctx, shard.New(shardName))
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		var hb Heartbeat
+		if state != nil {
+			hb.Resources = state()
+		}
+		if err := send(ctx, client, url, &hb); err != nil {
+			logger.Error(err, "failed to send heartbeat to the cache server")
+		}
+	}, interval)
+
+	return nil
+}
+
+func send(ctx context.Context, client *http.Client, url string, hb *Heartbeat) error {
+	body, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("heartbeat failed with status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package options
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -25,15 +26,50 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/replication"
 	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
 )
 
 type Cache struct {
 	KubeconfigFile string
+
+	// OffloadThreshold is the size in bytes above which the payload of replicated objects
+	// is stored in the blob store instead of the cache server. Zero disables offloading.
+	OffloadThreshold int
+	// OffloadBlobStoreDir is the directory of the file blob store. It must be shared by
+	// all shards using the same cache server.
+	OffloadBlobStoreDir string
+
+	// IncrementalReplication enables streaming deltas of replicated objects to the cache
+	// server over a single long-running request instead of one request per change.
+	IncrementalReplication bool
+	// ReplicationEncoding forces the encoding of the replication stream. If empty, protobuf is
+	// negotiated, falling back to JSON for cache servers not supporting it.
+	ReplicationEncoding string
+	// ReplicationCompression enables the gzip compression of the replication stream.
+	ReplicationCompression bool
+
+	// LocalFallback enables serving reads of cache clients from the local shard while the
+	// cache server is unreachable.
+	LocalFallback bool
+	// LocalFallbackProbeInterval is the interval in which the cache server is probed while
+	// reads are served from the local shard.
+	LocalFallbackProbeInterval time.Duration
+
+	// HeartbeatInterval is the interval in which the shard sends heartbeats to the cache
+	// server, keeping its objects from being purged by a cache server with a shard TTL.
+	// Zero disables heartbeats.
+	HeartbeatInterval time.Duration
 }
 
 func NewCache() *Cache {
-	return &Cache{}
+	return &Cache{
+		HeartbeatInterval:          30 * time.Second,
+		ReplicationCompression:     true,
+		LocalFallback:              true,
+		LocalFallbackProbeInterval: 10 * time.Second,
+	}
 }
 
 func (o *Cache) AddFlags(flags *pflag.FlagSet) {
@@ -42,10 +78,79 @@ func (o *Cache) AddFlags(flags *pflag.FlagSet) {
 
 	flags.StringVar(&o.KubeconfigFile, "cache-kubeconfig", o.KubeconfigFile,
 		"The kubeconfig file of the cache server instance that hosts workspaces.")
+
+	flags.IntVar(&o.OffloadThreshold, "cache-offload-threshold", o.OffloadThreshold,
+		"The size in bytes above which the payload of objects replicated to the cache server is stored in the blob store, "+
+			"with the cache server only holding a reference and a checksum. Zero disables offloading.")
+	flags.StringVar(&o.OffloadBlobStoreDir, "cache-offload-blob-store-dir", o.OffloadBlobStoreDir,
+		"The directory of the blob store for offloaded payloads. It must be shared by all shards using the same cache server.")
+
+	flags.BoolVar(&o.IncrementalReplication, "cache-incremental-replication", o.IncrementalReplication,
+		"Replicate objects to the cache server by streaming deltas over a single long-running HTTP/2 request, "+
+			"which is re-established automatically after disconnects.")
+	flags.StringVar(&o.ReplicationEncoding, "cache-replication-encoding", o.ReplicationEncoding,
+		"Force the encoding of the incremental replication stream, one of json or protobuf, e.g. for benchmarking. "+
+			"By default protobuf is used, falling back to json if the cache server does not support it.")
+	flags.BoolVar(&o.ReplicationCompression, "cache-replication-compression", o.ReplicationCompression,
+		"Compress the incremental replication stream with gzip.")
+
+	flags.BoolVar(&o.LocalFallback, "cache-local-fallback", o.LocalFallback,
+		"Serve reads of informers and clients of the cache server from the local shard while the cache server is unreachable, "+
+			"switching back once it is reachable again. Objects of other shards are not visible in the meantime.")
+	flags.DurationVar(&o.LocalFallbackProbeInterval, "cache-local-fallback-probe-interval", o.LocalFallbackProbeInterval,
+		"The interval in which the cache server is probed while reads are served from the local shard.")
+
+	flags.DurationVar(&o.HeartbeatInterval, "cache-heartbeat-interval", o.HeartbeatInterval,
+		"The interval in which the shard sends heartbeats to the cache server. It must be well below the --shard-ttl "+
+			"of the cache server, otherwise the objects of the shard are purged. Zero disables heartbeats.")
 }
 
 func (o *Cache) Validate() []error {
-	return nil
+	var errs []error
+
+	if o.OffloadThreshold < 0 {
+		errs = append(errs, fmt.Errorf("--cache-offload-threshold must not be negative"))
+	}
+	if o.OffloadThreshold > 0 && len(o.OffloadBlobStoreDir) == 0 {
+		errs = append(errs, fmt.Errorf("--cache-offload-blob-store-dir is required if --cache-offload-threshold is set"))
+	}
+	switch replication.Encoding(o.ReplicationEncoding) {
+	case "", replication.EncodingJSON, replication.EncodingProtobuf:
+	default:
+		errs = append(errs, fmt.Errorf("--cache-replication-encoding must be one of %s or %s", replication.EncodingJSON, replication.EncodingProtobuf))
+	}
+	if o.LocalFallback && o.LocalFallbackProbeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--cache-local-fallback-probe-interval must be positive"))
+	}
+	if o.HeartbeatInterval < 0 {
+		errs = append(errs, fmt.Errorf("--cache-heartbeat-interval must not be negative"))
+	}
+
+	return errs
+}
+
+// Offloader returns the offloader for big replicated objects, or nil if offloading is disabled.
+func (o *Cache) Offloader() (*offload.Offloader, error) {
+	if o.OffloadThreshold <= 0 {
+		return nil, nil
+	}
+	store, err := offload.NewFileBlobStore(o.OffloadBlobStoreDir)
+	if err != nil {
+		return nil, err
+	}
+	return offload.NewOffloader(store, o.OffloadThreshold), nil
+}
+
+// ReplicationStream returns the stream to replicate objects of the given shard, or nil if
+// incremental replication is disabled. The config must be one returned by RestConfig.
+func (o *Cache) ReplicationStream(config *rest.Config, shardName string) (*replication.Stream, error) {
+	if !o.IncrementalReplication {
+		return nil, nil
+	}
+	return replication.NewStream(config, shardName, replication.StreamOptions{
+		Encoding:           replication.Encoding(o.ReplicationEncoding),
+		DisableCompression: !o.ReplicationCompression,
+	})
 }
 
 func (o *Cache) RestConfig(fallback *rest.Config) (*rest.Config, error) {
@@ -0,0 +1,395 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Encoding is the encoding of the deltas and acknowledgements of a replication stream.
+type Encoding string
+
+const (
+	// EncodingJSON encodes the messages as a stream of JSON documents.
+	EncodingJSON Encoding = "json"
+	// EncodingProtobuf encodes the messages as length-prefixed protobuf messages.
+	EncodingProtobuf Encoding = "protobuf"
+)
+
+const (
+	// ContentTypeJSON is the content type of JSON encoded streams.
+	ContentTypeJSON = "application/json"
+	// ContentTypeProtobuf is the content type of protobuf encoded streams.
+	ContentTypeProtobuf = "application/vnd.kcp.replication+protobuf"
+
+	// ContentEncodingGzip is the content encoding of gzip compressed streams.
+	ContentEncodingGzip = "gzip"
+)
+
+// ContentType returns the content type of the encoding, or an empty string if the encoding is unknown.
+func (e Encoding) ContentType() string {
+	switch e {
+	case EncodingJSON:
+		return ContentTypeJSON
+	case EncodingProtobuf:
+		return ContentTypeProtobuf
+	default:
+		return ""
+	}
+}
+
+// EncodingForContentType returns the encoding of the given content type. An empty content type is JSON.
+func EncodingForContentType(contentType string) (Encoding, bool) {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return EncodingJSON, true
+	case ContentTypeProtobuf:
+		return EncodingProtobuf, true
+	default:
+		return "", false
+	}
+}
+
+// Encoder writes deltas or acknowledgements to a stream.
+type Encoder interface {
+	Encode(msg interface{}) error
+}
+
+// Decoder reads deltas or acknowledgements from a stream.
+type Decoder interface {
+	Decode(msg interface{}) error
+}
+
+// NewEncoder returns an encoder of messages in the given encoding. If compress is true, the
+// stream is gzip compressed, and flushed after each message such that the peer can decode it
+// right away.
+func NewEncoder(w io.Writer, encoding Encoding, compress bool) (Encoder, error) {
+	if encoding.ContentType() == "" {
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+	return &encoder{w: newMessageWriter(w, compress), encoding: encoding}, nil
+}
+
+// Marshal returns msg encoded as one message of a stream in the given encoding, such that it
+// can be encoded without holding the lock of the stream.
+func Marshal(encoding Encoding, msg interface{}) ([]byte, error) {
+	switch encoding {
+	case EncodingJSON:
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		// like json.Encoder, terminate every document by a newline.
+		return append(b, '\n'), nil
+	case EncodingProtobuf:
+		return marshalProtobuf(msg)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+type encoder struct {
+	w        *messageWriter
+	encoding Encoding
+}
+
+func (e *encoder) Encode(msg interface{}) error {
+	b, err := Marshal(e.encoding, msg)
+	if err != nil {
+		return err
+	}
+	return e.w.WriteMessage(b)
+}
+
+// messageWriter writes marshalled messages to a stream, optionally gzip compressed and
+// flushed after each message.
+type messageWriter struct {
+	w     io.Writer
+	flush func() error
+}
+
+func newMessageWriter(w io.Writer, compress bool) *messageWriter {
+	if !compress {
+		return &messageWriter{w: w}
+	}
+	gz := gzip.NewWriter(w)
+	return &messageWriter{w: gz, flush: gz.Flush}
+}
+
+// WriteMessage writes one message as returned by Marshal.
+func (w *messageWriter) WriteMessage(b []byte) error {
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+	if w.flush == nil {
+		return nil
+	}
+	return w.flush()
+}
+
+// NewDecoder returns a decoder of messages in the given encoding, gzip decompressing the stream
+// if compressed is true.
+func NewDecoder(r io.Reader, encoding Encoding, compressed bool) (Decoder, error) {
+	if compressed {
+		r = &gzipReader{r: r}
+	}
+	switch encoding {
+	case EncodingJSON:
+		return json.NewDecoder(r), nil
+	case EncodingProtobuf:
+		return &protobufDecoder{r: bufio.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// gzipReader creates the gzip reader on the first read, as reading the gzip header blocks
+// until the peer sent its first message.
+type gzipReader struct {
+	r  io.Reader
+	gz *gzip.Reader
+}
+
+func (r *gzipReader) Read(p []byte) (int, error) {
+	if r.gz == nil {
+		gz, err := gzip.NewReader(r.r)
+		if err != nil {
+			return 0, err
+		}
+		r.gz = gz
+	}
+	return r.gz.Read(p)
+}
+
+// protobuf field numbers of Delta.
+const (
+	deltaID protowire.Number = iota + 1
+	deltaType
+	deltaGroup
+	deltaVersion
+	deltaResource
+	deltaCluster
+	deltaNamespace
+	deltaName
+	deltaResourceVersion
+	_ // formerly the JSON encoded object
+	deltaPatch
+	deltaObject
+)
+
+// protobuf field numbers of Ack. The status is a protobuf encoded metav1.Status.
+const (
+	ackID protowire.Number = iota + 1
+	ackResourceVersion
+	ackStatus
+)
+
+// maxProtobufMessageSize limits the size of decoded messages.
+const maxProtobufMessageSize = 64 * 1024 * 1024
+
+// marshalProtobuf returns msg as length-prefixed protobuf message.
+func marshalProtobuf(msg interface{}) ([]byte, error) {
+	var b []byte
+	switch msg := msg.(type) {
+	case *Delta:
+		b = appendUint(b, deltaID, msg.ID)
+		b = appendString(b, deltaType, string(msg.Type))
+		b = appendString(b, deltaGroup, msg.Group)
+		b = appendString(b, deltaVersion, msg.Version)
+		b = appendString(b, deltaResource, msg.Resource)
+		b = appendString(b, deltaCluster, msg.Cluster)
+		b = appendString(b, deltaNamespace, msg.Namespace)
+		b = appendString(b, deltaName, msg.Name)
+		b = appendString(b, deltaResourceVersion, msg.ResourceVersion)
+		b = appendBytes(b, deltaPatch, msg.Patch)
+		if msg.Object != nil {
+			object, err := appendValue(nil, msg.Object.Object)
+			if err != nil {
+				return nil, err
+			}
+			b = protowire.AppendTag(b, deltaObject, protowire.BytesType)
+			b = protowire.AppendBytes(b, object)
+		}
+	case *Ack:
+		b = appendUint(b, ackID, msg.ID)
+		b = appendString(b, ackResourceVersion, msg.ResourceVersion)
+		if msg.Status != nil {
+			status, err := msg.Status.Marshal()
+			if err != nil {
+				return nil, err
+			}
+			b = protowire.AppendTag(b, ackStatus, protowire.BytesType)
+			b = protowire.AppendBytes(b, status)
+		}
+	default:
+		return nil, fmt.Errorf("cannot encode %T as protobuf", msg)
+	}
+
+	return append(protowire.AppendVarint(make([]byte, 0, len(b)+binary.MaxVarintLen64), uint64(len(b))), b...), nil
+}
+
+func appendUint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+type protobufDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *protobufDecoder) Decode(msg interface{}) error {
+	// binary.ReadUvarint returns io.EOF only if the stream ends before the message.
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+	if size > maxProtobufMessageSize {
+		return fmt.Errorf("protobuf message of %d bytes exceeds the limit of %d bytes", size, maxProtobufMessageSize)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	switch msg := msg.(type) {
+	case *Delta:
+		*msg = Delta{}
+		return consumeFields(b, func(num protowire.Number, v uint64, bs []byte) error {
+			switch num {
+			case deltaID:
+				msg.ID = v
+			case deltaType:
+				msg.Type = DeltaType(bs)
+			case deltaGroup:
+				msg.Group = string(bs)
+			case deltaVersion:
+				msg.Version = string(bs)
+			case deltaResource:
+				msg.Resource = string(bs)
+			case deltaCluster:
+				msg.Cluster = string(bs)
+			case deltaNamespace:
+				msg.Namespace = string(bs)
+			case deltaName:
+				msg.Name = string(bs)
+			case deltaResourceVersion:
+				msg.ResourceVersion = string(bs)
+			case deltaObject:
+				object, err := consumeValue(bs, 0)
+				if err != nil {
+					return err
+				}
+				content, ok := object.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("protobuf delta object is a %T, not an object", object)
+				}
+				msg.Object = &unstructured.Unstructured{Object: content}
+			case deltaPatch:
+				msg.Patch = append([]byte(nil), bs...)
+			}
+			return nil
+		})
+	case *Ack:
+		*msg = Ack{}
+		return consumeFields(b, func(num protowire.Number, v uint64, bs []byte) error {
+			switch num {
+			case ackID:
+				msg.ID = v
+			case ackResourceVersion:
+				msg.ResourceVersion = string(bs)
+			case ackStatus:
+				msg.Status = &metav1.Status{}
+				return msg.Status.Unmarshal(bs)
+			}
+			return nil
+		})
+	default:
+		return fmt.Errorf("cannot decode %T from protobuf", msg)
+	}
+}
+
+// consumeFields calls fn for each varint and length-delimited field of the message. Fields
+// of other wire types are skipped.
+func consumeFields(b []byte, fn func(num protowire.Number, v uint64, bs []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var v uint64
+		var bs []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			bs, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.VarintType && typ != protowire.BytesType {
+			continue
+		}
+		if err := fn(num, v, bs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
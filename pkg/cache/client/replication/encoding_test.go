@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestEncoding(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "foo").Status()
+	deltas := []Delta{
+		{ID: 1, Type: DeltaAdded, Version: "v1", Resource: "configmaps", Cluster: "root", Namespace: "default", Name: "foo", Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "foo", "namespace": "default", "generation": int64(-3), "labels": map[string]interface{}{}},
+			"data":       map[string]interface{}{"a": "b", "empty": ""},
+			"spec": map[string]interface{}{
+				"float":  1.5,
+				"int":    int64(math.MaxInt64),
+				"bool":   false,
+				"null":   nil,
+				"list":   []interface{}{int64(1), "two", []interface{}{}, map[string]interface{}{"three": true}},
+				"nested": map[string]interface{}{"deeper": map[string]interface{}{"deepest": int64(0)}},
+			},
+		}}},
+		{ID: 2, Type: DeltaModified, Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apiexports", Cluster: "root", Name: "bar", ResourceVersion: "7", Patch: json.RawMessage(`{"spec":null}`)},
+		{ID: 3, Type: DeltaDeleted, Version: "v1", Resource: "configmaps", Cluster: "root", Namespace: "default", Name: "foo"},
+	}
+	acks := []Ack{
+		{ID: 1, ResourceVersion: "8"},
+		{ID: 2, Status: &notFound},
+		{ID: 3},
+	}
+
+	for _, encoding := range []Encoding{EncodingJSON, EncodingProtobuf} {
+		for _, compress := range []bool{false, true} {
+			var buf bytes.Buffer
+			enc, err := NewEncoder(&buf, encoding, compress)
+			require.NoError(t, err)
+			for i := range deltas {
+				require.NoError(t, enc.Encode(&deltas[i]))
+			}
+			dec, err := NewDecoder(&buf, encoding, compress)
+			require.NoError(t, err)
+			for i := range deltas {
+				var delta Delta
+				require.NoError(t, dec.Decode(&delta), "%s, compress=%v", encoding, compress)
+				require.Equal(t, deltas[i], delta, "%s, compress=%v", encoding, compress)
+			}
+			var delta Delta
+			if compress {
+				// the gzip stream is flushed, but never closed.
+				require.ErrorIs(t, dec.Decode(&delta), io.ErrUnexpectedEOF, "%s, compress=%v", encoding, compress)
+			} else {
+				require.ErrorIs(t, dec.Decode(&delta), io.EOF, "%s, compress=%v", encoding, compress)
+			}
+
+			buf.Reset()
+			enc, err = NewEncoder(&buf, encoding, compress)
+			require.NoError(t, err)
+			for i := range acks {
+				require.NoError(t, enc.Encode(&acks[i]))
+			}
+			dec, err = NewDecoder(&buf, encoding, compress)
+			require.NoError(t, err)
+			for i := range acks {
+				var ack Ack
+				require.NoError(t, dec.Decode(&ack), "%s, compress=%v", encoding, compress)
+				require.Equal(t, acks[i], ack, "%s, compress=%v", encoding, compress)
+			}
+		}
+	}
+}
+
+func TestProtobufDecoderTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, EncodingProtobuf, false)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(&Delta{ID: 1, Name: "foo"}))
+
+	dec, err := NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), EncodingProtobuf, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, dec.Decode(&Delta{}), io.ErrUnexpectedEOF)
+}
+
+func TestProtobufValueErrors(t *testing.T) {
+	_, err := Marshal(EncodingProtobuf, &Delta{Object: &unstructured.Unstructured{Object: map[string]interface{}{"spec": struct{}{}}}})
+	require.ErrorContains(t, err, "spec: cannot encode struct {} as protobuf value")
+
+	var b []byte
+	for i := 0; i <= maxValueDepth; i++ {
+		b = protowire.AppendBytes(protowire.AppendTag(nil, valueList, protowire.BytesType), protowire.AppendBytes(protowire.AppendTag(nil, listValues, protowire.BytesType), b))
+	}
+	_, err = consumeValue(b, 0)
+	require.ErrorContains(t, err, "nested deeper than")
+}
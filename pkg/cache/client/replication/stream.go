@@ -0,0 +1,328 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+// errStreamClosed is returned by Send after Close has been called.
+var errStreamClosed = errors.New("replication stream closed")
+
+// Stream sends deltas of replicated objects of one shard to the cache server over a single
+// long-running request. The request is established lazily, and re-established by the next
+// Send after a disconnect. Deltas in flight during a disconnect fail, and are expected to be
+// retried by the caller, e.g. by requeuing the object in a workqueue.
+type Stream struct {
+	client    *http.Client
+	url       string
+	shardName string
+	opts      StreamOptions
+
+	lock   sync.Mutex
+	conn   *connection
+	nextID uint64
+	closed bool
+
+	// jsonFallback is set if the cache server rejected the protobuf encoding. It is set by
+	// the go routine of a connection, which does not take lock.
+	jsonFallback atomic.Bool
+}
+
+// StreamOptions configure the encoding of a Stream.
+type StreamOptions struct {
+	// Encoding forces the encoding of the stream. If empty, protobuf is used, falling back to
+	// JSON for cache servers not supporting it.
+	Encoding Encoding
+	// DisableCompression disables the gzip compression of the stream.
+	DisableCompression bool
+}
+
+// NewStream returns a Stream to the cache server for the given shard. The config must be a cache
+// server client config as returned by the cache client options, i.e. adding the /services/cache
+// prefix and the shard from the context to request paths.
+func NewStream(config *rest.Config, shardName string, opts StreamOptions) (*Stream, error) {
+	if opts.Encoding != "" && opts.Encoding.ContentType() == "" {
+		return nil, fmt.Errorf("unknown replication stream encoding %q", opts.Encoding)
+	}
+
+	config = rest.CopyConfig(config)
+	// the stream is long-running
+	config.Timeout = 0
+
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	u, _, err := rest.DefaultServerURL(config.Host, "", schema.GroupVersion{}, true)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, Path)
+
+	return &Stream{
+		client:    client,
+		url:       u.String(),
+		shardName: shardName,
+		opts:      opts,
+	}, nil
+}
+
+// Send sends the delta and waits until the cache server acknowledges it. Errors of the cache
+// server applying the delta are returned as API errors, such that apierrors.IsNotFound and
+// friends work as for direct requests.
+func (s *Stream) Send(ctx context.Context, delta Delta) (*Ack, error) {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil, errStreamClosed
+	}
+	if s.conn == nil || s.conn.failed() {
+		conn, err := s.connect(s.encoding())
+		if err != nil {
+			s.lock.Unlock()
+			return nil, err
+		}
+		s.conn = conn
+	}
+	conn := s.conn
+	s.nextID++
+	delta.ID = s.nextID
+	ch := conn.register(delta.ID)
+	s.lock.Unlock()
+
+	// encode outside of the lock, and hand the message to the writer of the connection,
+	// such that neither a large object nor a blocked request body stalls other Send calls.
+	msg, err := Marshal(conn.encoding, &delta)
+	if err != nil {
+		conn.unregister(delta.ID)
+		return nil, err
+	}
+	select {
+	case conn.writes <- msg:
+	case <-conn.done:
+		return nil, conn.err
+	case <-ctx.Done():
+		conn.unregister(delta.ID)
+		return nil, ctx.Err()
+	}
+
+	select {
+	case ack := <-ch:
+		if ack.Status != nil && ack.Status.Status != metav1.StatusSuccess {
+			return nil, &apierrors.StatusError{ErrStatus: *ack.Status}
+		}
+		return &ack, nil
+	case <-conn.done:
+		return nil, conn.err
+	case <-ctx.Done():
+		conn.unregister(delta.ID)
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the stream. Pending and future Send calls fail.
+func (s *Stream) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	if s.conn != nil {
+		s.conn.fail(errStreamClosed)
+	}
+}
+
+// encoding returns the encoding of the next connection.
+func (s *Stream) encoding() Encoding {
+	switch {
+	case s.opts.Encoding != "":
+		return s.opts.Encoding
+	case s.jsonFallback.Load():
+		return EncodingJSON
+	default:
+		return EncodingProtobuf
+	}
+}
+
+// connect starts a new request to the cache server. The request body is written by a go routine
+// writing the messages handed over by Send, the response body is read by a go routine delivering
+// the acknowledgements.
+func (s *Stream) connect(encoding Encoding) (*connection, error) {
+	if encoding.ContentType() == "" {
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+	compress := !s.opts.DisableCompression
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(cacheclient.WithShardInContext(context.Background(), shard.New(s.shardName)))
+	conn := &connection{
+		body:     pw,
+		encoding: encoding,
+		writes:   make(chan []byte),
+		cancel:   cancel,
+		pending:  map[uint64]chan Ack{},
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		w := newMessageWriter(pw, compress)
+		for {
+			select {
+			case msg := <-conn.writes:
+				if err := w.WriteMessage(msg); err != nil {
+					conn.fail(err)
+					return
+				}
+			case <-conn.done:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, pr)
+		if err != nil {
+			conn.fail(err)
+			return
+		}
+		req.Header.Set("Content-Type", encoding.ContentType())
+		req.Header.Set("Accept", encoding.ContentType())
+		if compress {
+			req.Header.Set("Content-Encoding", ContentEncodingGzip)
+			req.Header.Set("Accept-Encoding", ContentEncodingGzip)
+		} else {
+			// keep the transport from negotiating compression on its own.
+			req.Header.Set("Accept-Encoding", "identity")
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			conn.fail(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			if resp.StatusCode == http.StatusUnsupportedMediaType && s.opts.Encoding == "" && encoding == EncodingProtobuf {
+				// the next connection falls back to JSON.
+				s.jsonFallback.Store(true)
+			}
+			conn.fail(fmt.Errorf("replication stream failed with status %d: %s", resp.StatusCode, body))
+			return
+		}
+
+		respEncoding, ok := EncodingForContentType(resp.Header.Get("Content-Type"))
+		if !ok {
+			conn.fail(fmt.Errorf("unsupported content type %q of the replication stream", resp.Header.Get("Content-Type")))
+			return
+		}
+		dec, err := NewDecoder(resp.Body, respEncoding, resp.Header.Get("Content-Encoding") == ContentEncodingGzip)
+		if err != nil {
+			conn.fail(err)
+			return
+		}
+		for {
+			var ack Ack
+			if err := dec.Decode(&ack); err != nil {
+				if errors.Is(err, io.EOF) {
+					err = errors.New("replication stream closed by the cache server")
+				}
+				conn.fail(err)
+				return
+			}
+			conn.deliver(ack)
+		}
+	}()
+
+	return conn, nil
+}
+
+// connection is one request of a Stream.
+type connection struct {
+	body     *io.PipeWriter
+	encoding Encoding
+	writes   chan []byte
+	cancel   context.CancelFunc
+
+	lock    sync.Mutex
+	pending map[uint64]chan Ack
+	err     error
+	done    chan struct{}
+}
+
+func (c *connection) register(id uint64) <-chan Ack {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ch := make(chan Ack, 1)
+	c.pending[id] = ch
+	return ch
+}
+
+func (c *connection) unregister(id uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.pending, id)
+}
+
+func (c *connection) deliver(ack Ack) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if ch, found := c.pending[ack.ID]; found {
+		ch <- ack
+		delete(c.pending, ack.ID)
+	}
+}
+
+func (c *connection) failed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// fail tears the connection down. Waiting Send calls return err.
+func (c *connection) fail(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+	c.body.CloseWithError(err)
+	c.cancel()
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestStream(t *testing.T) {
+	tests := map[string]struct {
+		opts        StreamOptions
+		contentType string
+		gzip        bool
+	}{
+		"default":            {contentType: ContentTypeProtobuf, gzip: true},
+		"json":               {opts: StreamOptions{Encoding: EncodingJSON}, contentType: ContentTypeJSON, gzip: true},
+		"protobuf":           {opts: StreamOptions{Encoding: EncodingProtobuf, DisableCompression: true}, contentType: ContentTypeProtobuf},
+		"json, uncompressed": {opts: StreamOptions{Encoding: EncodingJSON, DisableCompression: true}, contentType: ContentTypeJSON},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testStream(t, tt.opts, tt.contentType, tt.gzip)
+		})
+	}
+}
+
+func testStream(t *testing.T, opts StreamOptions, contentType string, gzip bool) {
+	var connects int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, Path, req.URL.Path)
+		require.Equal(t, 2, req.ProtoMajor)
+		require.Equal(t, contentType, req.Header.Get("Content-Type"))
+		require.Equal(t, gzip, req.Header.Get("Content-Encoding") == ContentEncodingGzip)
+		atomic.AddInt32(&connects, 1)
+
+		encoding, ok := EncodingForContentType(contentType)
+		require.True(t, ok)
+		dec, err := NewDecoder(req.Body, encoding, gzip)
+		require.NoError(t, err)
+		enc, err := NewEncoder(w, encoding, gzip)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", contentType)
+		if gzip {
+			w.Header().Set("Content-Encoding", ContentEncodingGzip)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for {
+			var delta Delta
+			if err := dec.Decode(&delta); err != nil {
+				return
+			}
+			switch delta.Name {
+			case "disconnect":
+				return
+			case "missing":
+				s := apierrors.NewNotFound(schema.GroupResource{Group: delta.Group, Resource: delta.Resource}, delta.Name).Status()
+				require.NoError(t, enc.Encode(&Ack{ID: delta.ID, Status: &s}))
+			default:
+				require.Equal(t, "APIExport", delta.Object.GetKind())
+				require.NoError(t, enc.Encode(&Ack{ID: delta.ID, ResourceVersion: "42"}))
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	s, err := NewStream(&rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, "amber", opts)
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx := context.Background()
+	delta := Delta{Type: DeltaAdded, Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apiexports", Cluster: "root", Name: "foo", Object: newObject("apis.kcp.io/v1alpha1", "APIExport", "foo")}
+
+	ack, err := s.Send(ctx, delta)
+	require.NoError(t, err)
+	require.Equal(t, "42", ack.ResourceVersion)
+
+	delta.Name = "missing"
+	_, err = s.Send(ctx, delta)
+	require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+
+	delta.Name = "disconnect"
+	_, err = s.Send(ctx, delta)
+	require.Error(t, err)
+
+	delta.Name = "foo"
+	ack, err = s.Send(ctx, delta)
+	require.NoError(t, err, "the stream should resume after a disconnect")
+	require.Equal(t, "42", ack.ResourceVersion)
+	require.Equal(t, int32(2), atomic.LoadInt32(&connects))
+
+	s.Close()
+	_, err = s.Send(ctx, delta)
+	require.ErrorIs(t, err, errStreamClosed)
+}
+
+func TestStreamFallsBackToJSON(t *testing.T) {
+	var contentTypes []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		contentTypes = append(contentTypes, req.Header.Get("Content-Type"))
+		if req.Header.Get("Content-Type") != ContentTypeJSON {
+			http.Error(w, "unsupported", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		dec, err := NewDecoder(req.Body, EncodingJSON, true)
+		require.NoError(t, err)
+		enc, err := NewEncoder(w, EncodingJSON, true)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Header().Set("Content-Encoding", ContentEncodingGzip)
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		var delta Delta
+		if err := dec.Decode(&delta); err != nil {
+			return
+		}
+		require.NoError(t, enc.Encode(&Ack{ID: delta.ID, ResourceVersion: "42"}))
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	s, err := NewStream(&rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, "amber", StreamOptions{})
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx := context.Background()
+	delta := Delta{Type: DeltaAdded, Version: "v1", Resource: "configmaps", Cluster: "root", Name: "foo", Object: newObject("v1", "ConfigMap", "foo")}
+	_, err = s.Send(ctx, delta)
+	require.Error(t, err, "the cache server rejects protobuf")
+
+	ack, err := s.Send(ctx, delta)
+	require.NoError(t, err)
+	require.Equal(t, "42", ack.ResourceVersion)
+	require.Equal(t, []string{ContentTypeProtobuf, ContentTypeJSON}, contentTypes)
+}
+
+func TestStreamSendHonoursContext(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// never read the request body, such that writing it blocks on flow control.
+		<-req.Context().Done()
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	s, err := NewStream(&rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, "amber", StreamOptions{Encoding: EncodingProtobuf, DisableCompression: true})
+	require.NoError(t, err)
+	defer s.Close()
+
+	object := newObject("v1", "ConfigMap", "foo")
+	object.Object["data"] = map[string]interface{}{"a": strings.Repeat("a", 1<<20)}
+	delta := Delta{Type: DeltaAdded, Version: "v1", Resource: "configmaps", Cluster: "root", Name: "foo", Object: object}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err = s.Send(ctx, delta)
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded, "Send %d should give up when the context is done", i)
+	}
+}
+
+func newObject(apiVersion, kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replication implements the client side of the incremental replication protocol of the
+// cache server. Instead of one request per replicated object, a shard keeps one long-running
+// request to /services/cache/shards/{shard}/replication open, streams deltas of the replicated
+// objects in the request body, and receives an acknowledgement per delta in the response body.
+// Both bodies are streams of JSON documents or of length-prefixed protobuf messages, optionally
+// gzip compressed, as negotiated by the Content-Type, Accept, Content-Encoding and Accept-Encoding
+// headers of the request. The protocol needs HTTP/2, as HTTP/1.1 servers cannot read the request
+// body after the response has been started.
+package replication
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Path is the path of the replication endpoint of the cache server, below the shard prefix.
+const Path = "/replication"
+
+// DeltaType is the type of change a Delta transmits.
+type DeltaType string
+
+const (
+	// DeltaAdded creates the object given in Delta.Object.
+	DeltaAdded DeltaType = "ADDED"
+	// DeltaModified updates the object, either by replacing it with Delta.Object, or
+	// by applying the JSON merge patch in Delta.Patch.
+	DeltaModified DeltaType = "MODIFIED"
+	// DeltaDeleted deletes the object.
+	DeltaDeleted DeltaType = "DELETED"
+)
+
+// Delta is a change of a replicated object, sent by a shard to the cache server.
+type Delta struct {
+	// ID identifies the delta within a stream. The acknowledgement carries the same ID.
+	ID uint64 `json:"id"`
+	// Type is the type of the change.
+	Type DeltaType `json:"type"`
+
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// ResourceVersion is the resource version of the cached object the delta is based on.
+	// It is a precondition for modifications and deletions if set.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// Object is the full object for additions and full replacements. In protobuf encoded
+	// streams it is encoded as protobuf too, see value.go.
+	Object *unstructured.Unstructured `json:"object,omitempty"`
+	// Patch is a JSON merge patch for modifications.
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// Ack is the acknowledgement of a Delta, sent by the cache server after applying it.
+type Ack struct {
+	// ID is the ID of the acknowledged delta.
+	ID uint64 `json:"id"`
+	// ResourceVersion is the resource version of the cached object after applying the delta.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Status is set if the delta could not be applied.
+	Status *metav1.Status `json:"status,omitempty"`
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Replicated objects are unstructured, i.e. most of them are custom resources without a protobuf
+// schema of their own. In protobuf encoded streams they are encoded as a tree of Value messages,
+// like google.protobuf.Value, but keeping integers apart from floating point numbers as
+// unstructured objects do:
+//
+//	message Value {
+//	  oneof kind {
+//	    bool   null   = 1;
+//	    string string = 2;
+//	    sint64 int    = 3;
+//	    double float  = 4;
+//	    bool   bool   = 5;
+//	    Object object = 6;
+//	    List   list   = 7;
+//	  }
+//	}
+//	message Object { repeated Field fields = 1; }
+//	message Field { string key = 1; Value value = 2; }
+//	message List { repeated Value values = 1; }
+
+// protobuf field numbers of Value.
+const (
+	valueNull protowire.Number = iota + 1
+	valueString
+	valueInt
+	valueFloat
+	valueBool
+	valueObject
+	valueList
+)
+
+// protobuf field numbers of Object, Field and List.
+const (
+	objectFields protowire.Number = 1
+	fieldKey     protowire.Number = 1
+	fieldValue   protowire.Number = 2
+	listValues   protowire.Number = 1
+)
+
+// maxValueDepth limits the nesting of decoded values.
+const maxValueDepth = 1000
+
+// appendValue appends v as Value message, without tag and length prefix.
+func appendValue(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		b = protowire.AppendTag(b, valueNull, protowire.VarintType)
+		return protowire.AppendVarint(b, 1), nil
+	case string:
+		b = protowire.AppendTag(b, valueString, protowire.BytesType)
+		return protowire.AppendString(b, v), nil
+	case int64:
+		b = protowire.AppendTag(b, valueInt, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeZigZag(v)), nil
+	case int:
+		return appendValue(b, int64(v))
+	case int32:
+		return appendValue(b, int64(v))
+	case float64:
+		b = protowire.AppendTag(b, valueFloat, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(v)), nil
+	case float32:
+		return appendValue(b, float64(v))
+	case bool:
+		b = protowire.AppendTag(b, valueBool, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(v)), nil
+	case map[string]interface{}:
+		var object []byte
+		for key, value := range v {
+			field := protowire.AppendTag(nil, fieldKey, protowire.BytesType)
+			field = protowire.AppendString(field, key)
+			nested, err := appendValue(nil, value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			field = protowire.AppendTag(field, fieldValue, protowire.BytesType)
+			field = protowire.AppendBytes(field, nested)
+			object = protowire.AppendTag(object, objectFields, protowire.BytesType)
+			object = protowire.AppendBytes(object, field)
+		}
+		b = protowire.AppendTag(b, valueObject, protowire.BytesType)
+		return protowire.AppendBytes(b, object), nil
+	case []interface{}:
+		var list []byte
+		for i, value := range v {
+			nested, err := appendValue(nil, value)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			list = protowire.AppendTag(list, listValues, protowire.BytesType)
+			list = protowire.AppendBytes(list, nested)
+		}
+		b = protowire.AppendTag(b, valueList, protowire.BytesType)
+		return protowire.AppendBytes(b, list), nil
+	default:
+		return nil, fmt.Errorf("cannot encode %T as protobuf value", v)
+	}
+}
+
+// consumeValue decodes a Value message as the Go types of unstructured objects.
+func consumeValue(b []byte, depth int) (interface{}, error) {
+	if depth > maxValueDepth {
+		return nil, fmt.Errorf("protobuf value nested deeper than %d levels", maxValueDepth)
+	}
+
+	var ret interface{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case num == valueNull && typ == protowire.VarintType:
+			_, n = protowire.ConsumeVarint(b)
+			ret = nil
+		case num == valueString && typ == protowire.BytesType:
+			var s []byte
+			s, n = protowire.ConsumeBytes(b)
+			ret = string(s)
+		case num == valueInt && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			ret = protowire.DecodeZigZag(v)
+		case num == valueFloat && typ == protowire.Fixed64Type:
+			var v uint64
+			v, n = protowire.ConsumeFixed64(b)
+			ret = math.Float64frombits(v)
+		case num == valueBool && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			ret = protowire.DecodeBool(v)
+		case num == valueObject && typ == protowire.BytesType:
+			var object []byte
+			if object, n = protowire.ConsumeBytes(b); n >= 0 {
+				var err error
+				if ret, err = consumeObject(object, depth); err != nil {
+					return nil, err
+				}
+			}
+		case num == valueList && typ == protowire.BytesType:
+			var list []byte
+			if list, n = protowire.ConsumeBytes(b); n >= 0 {
+				var err error
+				if ret, err = consumeList(list, depth); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return ret, nil
+}
+
+func consumeObject(b []byte, depth int) (map[string]interface{}, error) {
+	object := map[string]interface{}{}
+	err := consumeFields(b, func(num protowire.Number, _ uint64, field []byte) error {
+		if num != objectFields {
+			return nil
+		}
+		var key string
+		var value interface{}
+		var hasKey bool
+		if err := consumeFields(field, func(num protowire.Number, _ uint64, bs []byte) error {
+			var err error
+			switch num {
+			case fieldKey:
+				key, hasKey = string(bs), true
+			case fieldValue:
+				value, err = consumeValue(bs, depth+1)
+			}
+			return err
+		}); err != nil {
+			return err
+		}
+		if !hasKey {
+			return errors.New("protobuf object field without key")
+		}
+		object[key] = value
+		return nil
+	})
+	return object, err
+}
+
+func consumeList(b []byte, depth int) ([]interface{}, error) {
+	list := []interface{}{}
+	err := consumeFields(b, func(num protowire.Number, _ uint64, bs []byte) error {
+		if num != listValues {
+			return nil
+		}
+		value, err := consumeValue(bs, depth+1)
+		if err != nil {
+			return err
+		}
+		list = append(list, value)
+		return nil
+	})
+	return list, err
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replicator is a client library to participate in the replication of objects through
+// the cache server. A shard pushes objects into its own part of the cache server, and reads and
+// watches the objects replicated by all shards.
+//
+// The rest.Config passed to NewForConfig is expected to target the cache server, e.g. as returned
+// by the RestConfig method of the cache client options.
+package replicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/replication"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	kcpscheme "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/scheme"
+)
+
+// DefaultBackoff is the backoff used to retry transient failures of the cache server.
+var DefaultBackoff = wait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      5 * time.Second,
+}
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kcpscheme.AddToScheme(scheme))
+}
+
+// Client pushes the objects of a shard into the cache server, and reads and watches the
+// replicated objects.
+type Client struct {
+	shardName          string
+	dynamicCacheClient kcpdynamic.ClusterInterface
+	stream             *replication.Stream
+
+	// Backoff is the backoff to retry transient failures and conflicts with.
+	Backoff wait.Backoff
+	// ResolveConflict is called when pushing an object conflicts with a concurrent write.
+	ResolveConflict ConflictHandler
+}
+
+// NewClient returns a client pushing objects of the given shard. If stream is non-nil, writes
+// are sent over the replication stream instead of individual requests.
+func NewClient(shardName string, dynamicCacheClient kcpdynamic.ClusterInterface, stream *replication.Stream) *Client {
+	return &Client{
+		shardName:          shardName,
+		dynamicCacheClient: dynamicCacheClient,
+		stream:             stream,
+		Backoff:            DefaultBackoff,
+		ResolveConflict:    OverwriteCached,
+	}
+}
+
+// NewForConfig returns a client pushing objects of the given shard to the cache server the config
+// points to. If incremental is true, writes are sent over a replication stream, which must be
+// closed with Close.
+func NewForConfig(config *rest.Config, shardName string, incremental bool) (*Client, error) {
+	dynamicCacheClient, err := kcpdynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	var stream *replication.Stream
+	if incremental {
+		if stream, err = replication.NewStream(config, shardName, replication.StreamOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return NewClient(shardName, dynamicCacheClient, stream), nil
+}
+
+// Close closes the replication stream, if any.
+func (c *Client) Close() {
+	if c.stream != nil {
+		c.stream.Close()
+	}
+}
+
+// Push creates or updates the given object in the cache server. The object must carry the
+// logical cluster annotation. Typed objects must be registered in the kube or kcp scheme,
+// unstructured objects must have their kind set.
+//
+// Transient failures and conflicts are retried with the backoff of the client.
+func (c *Client) Push(ctx context.Context, gvr schema.GroupVersionResource, obj runtime.Object) (*unstructured.Unstructured, error) {
+	desired, err := toUnstructured(gvr, obj)
+	if err != nil {
+		return nil, err
+	}
+	clusterName := logicalcluster.From(desired)
+	if clusterName.Empty() {
+		return nil, fmt.Errorf("%s %s has no logical cluster", gvr.Resource, desired.GetName())
+	}
+	desired.SetResourceVersion("")
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[genericrequest.AnnotationKey] = c.shardName
+	desired.SetAnnotations(annotations)
+
+	ctx = cacheclient.WithShardInContext(ctx, shard.New(c.shardName))
+	client := c.dynamicCacheClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(desired.GetNamespace())
+
+	var result *unstructured.Unstructured
+	conflicted := false
+	err = retry.OnError(c.Backoff, isRetriable, func() error {
+		cached, err := client.Get(ctx, desired.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			result, err = c.create(ctx, gvr, clusterName, desired)
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		toWrite := desired
+		if conflicted {
+			if toWrite, err = c.ResolveConflict(ctx, cached, desired); err != nil {
+				return err
+			} else if toWrite == nil {
+				result = cached
+				return nil
+			}
+		}
+		toWrite = toWrite.DeepCopy()
+		toWrite.SetResourceVersion(cached.GetResourceVersion())
+
+		result, err = c.update(ctx, gvr, clusterName, toWrite)
+		if apierrors.IsConflict(err) {
+			conflicted = true
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete deletes the object of the shard from the cache server. It is not an error if the
+// object does not exist.
+func (c *Client) Delete(ctx context.Context, gvr schema.GroupVersionResource, clusterName logicalcluster.Name, namespace, name string) error {
+	ctx = cacheclient.WithShardInContext(ctx, shard.New(c.shardName))
+	err := retry.OnError(c.Backoff, isRetriable, func() error {
+		if c.stream != nil {
+			_, err := c.stream.Send(ctx, replication.Delta{
+				Type:      replication.DeltaDeleted,
+				Group:     gvr.Group,
+				Version:   gvr.Version,
+				Resource:  gvr.Resource,
+				Cluster:   clusterName.String(),
+				Namespace: namespace,
+				Name:      name,
+			})
+			return err
+		}
+		return c.dynamicCacheClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Get reads the object pushed by the shard of the client from the cache server into the given
+// object, which can be typed or unstructured.
+func (c *Client) Get(ctx context.Context, gvr schema.GroupVersionResource, clusterName logicalcluster.Name, namespace, name string, into runtime.Object) error {
+	ctx = cacheclient.WithShardInContext(ctx, shard.New(c.shardName))
+
+	var obj *unstructured.Unstructured
+	if err := retry.OnError(c.Backoff, isRetriable, func() error {
+		var err error
+		obj, err = c.dynamicCacheClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}); err != nil {
+		return err
+	}
+	return fromUnstructured(obj, into)
+}
+
+func (c *Client) create(ctx context.Context, gvr schema.GroupVersionResource, clusterName logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.stream == nil {
+		return c.dynamicCacheClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+	}
+	return c.send(ctx, gvr, clusterName, replication.DeltaAdded, obj)
+}
+
+func (c *Client) update(ctx context.Context, gvr schema.GroupVersionResource, clusterName logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.stream == nil {
+		return c.dynamicCacheClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+	}
+	return c.send(ctx, gvr, clusterName, replication.DeltaModified, obj)
+}
+
+// send sends the whole object over the replication stream.
+func (c *Client) send(ctx context.Context, gvr schema.GroupVersionResource, clusterName logicalcluster.Name, typ replication.DeltaType, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	ack, err := c.stream.Send(ctx, replication.Delta{
+		Type:            typ,
+		Group:           gvr.Group,
+		Version:         gvr.Version,
+		Resource:        gvr.Resource,
+		Cluster:         clusterName.String(),
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Object:          obj,
+	})
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion(ack.ResourceVersion)
+	return obj, nil
+}
+
+// isRetriable returns true for conflicts, races between creations, and errors of an overloaded
+// or temporarily unreachable cache server.
+func isRetriable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return apierrors.IsConflict(err) ||
+		apierrors.IsAlreadyExists(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsProbableEOF(err)
+}
+
+func toUnstructured(gvr schema.GroupVersionResource, obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if u.GetKind() == "" {
+			return nil, fmt.Errorf("%s %s has no kind", gvr.Resource, u.GetName())
+		}
+		u = u.DeepCopy()
+		u.SetAPIVersion(gvr.GroupVersion().String())
+		return u, nil
+	}
+
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: raw}
+	u.SetAPIVersion(gvr.GroupVersion().String())
+	u.SetKind(gvks[0].Kind)
+	return u, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured, into runtime.Object) error {
+	if u, ok := into.(*unstructured.Unstructured); ok {
+		obj.DeepCopyInto(u)
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, into)
+}
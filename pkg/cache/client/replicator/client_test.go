@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpfakedynamic "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/dynamic/fake"
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+var exportsGVR = apisv1alpha1.SchemeGroupVersion.WithResource("apiexports")
+
+func newTestClient() (*Client, *kcpfakedynamic.FakeDynamicClusterClientset) {
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{exportsGVR: "APIExportList"})
+	c := NewClient("amber", fakeClient, nil)
+	c.Backoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+	return c, fakeClient
+}
+
+func newExport(name, identity string) *apisv1alpha1.APIExport {
+	return &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root"},
+		},
+		Spec: apisv1alpha1.APIExportSpec{Identity: &apisv1alpha1.Identity{SecretRef: &corev1.SecretReference{Name: identity}}},
+	}
+}
+
+func TestPushAndGet(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestClient()
+
+	pushed, err := c.Push(ctx, exportsGVR, newExport("foo", "one"))
+	require.NoError(t, err)
+	require.Equal(t, "APIExport", pushed.GetKind())
+	require.Equal(t, "amber", pushed.GetAnnotations()[genericrequest.AnnotationKey])
+
+	_, err = c.Push(ctx, exportsGVR, newExport("foo", "two"))
+	require.NoError(t, err)
+
+	var export apisv1alpha1.APIExport
+	require.NoError(t, c.Get(ctx, exportsGVR, "root", "", "foo", &export))
+	require.Equal(t, "two", export.Spec.Identity.SecretRef.Name)
+
+	require.NoError(t, c.Delete(ctx, exportsGVR, "root", "", "foo"))
+	require.NoError(t, c.Delete(ctx, exportsGVR, "root", "", "foo"), "deleting a missing object must succeed")
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, exportsGVR, "root", "", "foo", &export)))
+}
+
+func TestPushConflict(t *testing.T) {
+	for name, tt := range map[string]struct {
+		resolve      ConflictHandler
+		wantIdentity string
+	}{
+		"overwrite cached": {resolve: OverwriteCached, wantIdentity: "mine"},
+		"keep cached":      {resolve: KeepCached, wantIdentity: "theirs"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c, fakeClient := newTestClient()
+			c.ResolveConflict = tt.resolve
+
+			_, err := c.Push(ctx, exportsGVR, newExport("foo", "original"))
+			require.NoError(t, err)
+
+			// a concurrent writer updates the object right before our update.
+			conflicted := false
+			fakeClient.PrependReactor("update", "apiexports", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				if conflicted {
+					return false, nil, nil
+				}
+				conflicted = true
+				theirs, err := toUnstructured(exportsGVR, newExport("foo", "theirs"))
+				require.NoError(t, err)
+				require.NoError(t, fakeClient.Tracker().Cluster(logicalcluster.NewPath("root")).Update(exportsGVR, theirs, ""))
+				return true, nil, apierrors.NewConflict(exportsGVR.GroupResource(), "foo", nil)
+			})
+
+			_, err = c.Push(ctx, exportsGVR, newExport("foo", "mine"))
+			require.NoError(t, err)
+			require.True(t, conflicted)
+
+			var export apisv1alpha1.APIExport
+			require.NoError(t, c.Get(ctx, exportsGVR, "root", "", "foo", &export))
+			require.Equal(t, tt.wantIdentity, export.Spec.Identity.SecretRef.Name)
+		})
+	}
+}
+
+func TestPushRetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	c, fakeClient := newTestClient()
+
+	failures := 2
+	fakeClient.PrependReactor("create", "apiexports", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if failures == 0 {
+			return false, nil, nil
+		}
+		failures--
+		return true, nil, apierrors.NewServiceUnavailable("overloaded")
+	})
+	_, err := c.Push(ctx, exportsGVR, newExport("foo", "one"))
+	require.NoError(t, err)
+	require.Zero(t, failures)
+
+	fakeClient.PrependReactor("update", "apiexports", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(exportsGVR.GroupResource(), "foo", nil)
+	})
+	_, err = c.Push(ctx, exportsGVR, newExport("foo", "two"))
+	require.True(t, apierrors.IsForbidden(err), "expected permanent errors not to be retried")
+}
+
+func TestPushRequiresKind(t *testing.T) {
+	c, _ := newTestClient()
+	u := &unstructured.Unstructured{}
+	u.SetName("foo")
+	u.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: "root"})
+	_, err := c.Push(context.Background(), exportsGVR, u)
+	require.Error(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	c, fakeClient := newTestClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	export := func(name, rv string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apisv1alpha1.SchemeGroupVersion.String())
+		u.SetKind("APIExport")
+		u.SetName(name)
+		u.SetResourceVersion(rv)
+		return u
+	}
+
+	fakeClient.PrependReactor("list", "apiexports", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*export("foo", "1")}}
+		list.SetResourceVersion("2")
+		return true, list, nil
+	})
+	var watchedFrom []string
+	watchers := make(chan *watch.FakeWatcher, 2)
+	fakeClient.PrependWatchReactor("apiexports", func(action kcptesting.Action) (bool, watch.Interface, error) {
+		watchedFrom = append(watchedFrom, action.(kcptesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion)
+		if len(watchedFrom) > 2 {
+			return true, nil, apierrors.NewResourceExpired("too old")
+		}
+		w := watch.NewFake()
+		watchers <- w
+		return true, w, nil
+	})
+	go func() {
+		w := <-watchers
+		w.Add(export("bar", "3"))
+		w.Action(watch.Bookmark, export("", "4"))
+		w.Stop()
+		w = <-watchers
+		w.Delete(export("foo", "5"))
+		w.Stop()
+	}()
+
+	type event struct {
+		Type  watch.EventType
+		Name  string
+		Token SyncToken
+	}
+	var got []event
+	token, err := c.Watch(ctx, exportsGVR, "", func(ctx context.Context, eventType watch.EventType, obj *unstructured.Unstructured, token SyncToken) error {
+		got = append(got, event{eventType, obj.GetName(), token})
+		return nil
+	})
+	require.ErrorIs(t, err, ErrSyncTokenExpired)
+	require.Equal(t, SyncToken("5"), token)
+	require.Equal(t, []string{"2", "4", "5"}, watchedFrom, "expected the watch to be resumed from the last token")
+	require.Equal(t, []event{
+		{watch.Added, "foo", ""},
+		{watch.Added, "bar", "3"},
+		{watch.Deleted, "foo", "5"},
+	}, got)
+}
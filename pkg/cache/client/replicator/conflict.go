@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ConflictHandler resolves a conflict between the object in the cache server, written
+// concurrently by another writer of the same shard, and the object being pushed. It returns the
+// object to write, or nil to keep the cached object. The resource version of the returned
+// object is set by the caller.
+type ConflictHandler func(ctx context.Context, cached, desired *unstructured.Unstructured) (*unstructured.Unstructured, error)
+
+// OverwriteCached resolves conflicts by writing the pushed object. It is the default.
+func OverwriteCached(ctx context.Context, cached, desired *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return desired, nil
+}
+
+// KeepCached resolves conflicts by keeping the concurrently written object.
+func KeepCached(ctx context.Context, cached, desired *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return nil, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+// SyncToken is an opaque position in the changes of a resource in the cache server. It can be
+// persisted to resume watching, e.g. after a restart.
+type SyncToken string
+
+// ErrSyncTokenExpired is returned by Watch if the cache server does not retain the changes since
+// the given token anymore. The caller has to start over with an empty token.
+var ErrSyncTokenExpired = errors.New("sync token expired")
+
+// EventHandler is called by Watch for every change of a replicated object. token is the position
+// after the change, and empty while the objects of the initial list are delivered. Watch stops
+// with the error returned by the handler.
+type EventHandler func(ctx context.Context, eventType watch.EventType, obj *unstructured.Unstructured, token SyncToken) error
+
+// Watch delivers the changes of the given resource replicated by all shards to handler. With an
+// empty token, all objects are delivered as watch.Added first. Interrupted watches are resumed
+// with the backoff of the client.
+//
+// Watch returns the last token when the context is done, or with ErrSyncTokenExpired if the
+// token is too old.
+func (c *Client) Watch(ctx context.Context, gvr schema.GroupVersionResource, token SyncToken, handler EventHandler) (SyncToken, error) {
+	logger := klog.FromContext(ctx).WithValues("resource", gvr.String())
+	ctx = cacheclient.WithShardInContext(ctx, shard.Wildcard)
+	client := c.dynamicCacheClient.Resource(gvr)
+
+	if token == "" {
+		var list *unstructured.UnstructuredList
+		if err := retry.OnError(c.Backoff, isRetriable, func() error {
+			var err error
+			list, err = client.List(ctx, metav1.ListOptions{})
+			return err
+		}); err != nil {
+			return token, err
+		}
+		for i := range list.Items {
+			if err := handler(ctx, watch.Added, &list.Items[i], ""); err != nil {
+				return token, err
+			}
+		}
+		token = SyncToken(list.GetResourceVersion())
+	}
+
+	backoff := c.Backoff
+	for {
+		w, err := client.Watch(ctx, metav1.ListOptions{ResourceVersion: string(token), AllowWatchBookmarks: true})
+		if isExpired(err) {
+			return token, ErrSyncTokenExpired
+		} else if err != nil && !isRetriable(err) {
+			if ctx.Err() != nil {
+				return token, nil
+			}
+			return token, err
+		}
+
+		if err == nil {
+			var progressed bool
+			token, progressed, err = c.consume(ctx, w, token, handler)
+			w.Stop()
+			if err != nil {
+				return token, err
+			}
+			if progressed {
+				backoff = c.Backoff
+			}
+		}
+
+		delay := backoff.Step()
+		logger.V(4).Info("resuming watch", "token", token, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return token, nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// consume delivers the events of the watch until it ends. It returns the last token, and
+// whether any event has been received.
+func (c *Client) consume(ctx context.Context, w watch.Interface, token SyncToken, handler EventHandler) (SyncToken, bool, error) {
+	progressed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return token, progressed, nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return token, progressed, nil
+			}
+			switch ev.Type {
+			case watch.Error:
+				err := apierrors.FromObject(ev.Object)
+				if isExpired(err) {
+					return token, progressed, ErrSyncTokenExpired
+				}
+				klog.FromContext(ctx).V(4).Info("watch failed", "err", err)
+				return token, progressed, nil
+			case watch.Added, watch.Modified, watch.Deleted, watch.Bookmark:
+				obj, ok := ev.Object.(*unstructured.Unstructured)
+				if !ok {
+					return token, progressed, fmt.Errorf("expected an Unstructured, got %T", ev.Object)
+				}
+				progressed = true
+				if rv := obj.GetResourceVersion(); rv != "" {
+					token = SyncToken(rv)
+				}
+				if ev.Type == watch.Bookmark {
+					continue
+				}
+				if err := handler(ctx, ev.Type, obj, token); err != nil {
+					return token, progressed, err
+				}
+			}
+		}
+	}
+}
+
+func isExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
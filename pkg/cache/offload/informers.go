@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offload
+
+import (
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+
+	"k8s.io/client-go/tools/cache"
+
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+)
+
+// KcpInformers returns the informers of the kcp resources replicated to the cache server by the
+// replication controller, whose objects can be offloaded.
+func KcpInformers(f kcpinformers.SharedInformerFactory) []cache.SharedIndexInformer {
+	return []cache.SharedIndexInformer{
+		f.Apis().V1alpha1().APIExports().Informer(),
+		f.Apis().V1alpha1().APIResourceSchemas().Informer(),
+		f.Apis().V1alpha1().APIConversions().Informer(),
+		f.Apis().V1alpha1().DefaultAPIBindings().Informer(),
+		f.Core().V1alpha1().Shards().Informer(),
+		f.Core().V1alpha1().ReplicationConfigs().Informer(),
+		f.Core().V1alpha1().LogicalClusters().Informer(),
+		f.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
+		f.Tenancy().V1alpha1().WorkspaceTemplates().Informer(),
+		f.Workload().V1alpha1().SyncTargets().Informer(),
+		f.Workload().V1alpha1().SyncTargetPools().Informer(),
+		f.Scheduling().V1alpha1().Locations().Informer(),
+	}
+}
+
+// KubeInformers returns the informers of the kube resources replicated to the cache server by the
+// replication controller, whose objects can be offloaded.
+func KubeInformers(f kcpkubernetesinformers.SharedInformerFactory) []cache.SharedIndexInformer {
+	return []cache.SharedIndexInformer{
+		f.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
+		f.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer(),
+		f.Rbac().V1().ClusterRoles().Informer(),
+		f.Rbac().V1().ClusterRoleBindings().Informer(),
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// PayloadAnnotationKey is set on objects in the cache server whose payload, i.e. all
+	// fields except apiVersion, kind and metadata, is stored in a blob store. The value is
+	// the key of the blob.
+	PayloadAnnotationKey = "internal.cache.kcp.io/offloaded-payload"
+
+	// PayloadChecksumAnnotationKey holds the checksum of the offloaded payload in the
+	// form "sha256:<hex>". Readers verify it before restoring the payload.
+	PayloadChecksumAnnotationKey = "internal.cache.kcp.io/offloaded-payload-checksum"
+
+	// initialResolveBackoff and maxResolveBackoff bound the back-off between attempts to resolve
+	// the payload of an object. The informer does not process other objects meanwhile, hence
+	// the maximum is kept short.
+	initialResolveBackoff = 100 * time.Millisecond
+	maxResolveBackoff     = 5 * time.Second
+)
+
+// errInvalidPayload is returned by Resolve for payloads that do not match the object.
+var errInvalidPayload = errors.New("invalid offloaded payload")
+
+// Offloader moves the payload of objects exceeding a size threshold to a blob store, and
+// restores it on read. This keeps the memory usage of the cache server and its etcd
+// bounded in the presence of big objects, e.g. APIResourceSchemas with large schemas.
+type Offloader struct {
+	store     BlobStore
+	threshold int
+
+	// backoff delays the retries of objects whose payload cannot be read.
+	backoff *flowcontrol.Backoff
+	sleep   func(time.Duration)
+}
+
+// NewOffloader returns an Offloader storing the payload of objects whose JSON
+// serialization is bigger than threshold bytes in the given blob store.
+func NewOffloader(store BlobStore, threshold int) *Offloader {
+	return &Offloader{
+		store:     store,
+		threshold: threshold,
+		backoff:   flowcontrol.NewBackOff(initialResolveBackoff, maxResolveBackoff),
+		sleep:     time.Sleep,
+	}
+}
+
+// Offload moves the payload of obj to the blob store if obj exceeds the size threshold,
+// and replaces it by a reference and a checksum in the annotations of obj. Objects below
+// the threshold are left untouched apart from removing stale offloading annotations.
+// The blob key is scoped by shard, logical cluster, resource, namespace and name, and
+// contains the checksum, such that readers of the old object version keep finding the
+// old payload until it is explicitly deleted.
+func (o *Offloader) Offload(ctx context.Context, shard string, gr schema.GroupResource, obj *unstructured.Unstructured) error {
+	removeAnnotations(obj)
+
+	raw, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	if len(raw) <= o.threshold {
+		return nil
+	}
+
+	payload := map[string]interface{}{}
+	for k, v := range obj.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+		default:
+			payload[k] = v
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	group := gr.Group
+	if group == "" {
+		group = "core"
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "_"
+	}
+	key := path.Join(shard, logicalcluster.From(obj).String(), group, gr.Resource, namespace, obj.GetName(), checksum)
+	if err := o.store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to store payload of %s %s|%s/%s: %w", gr, logicalcluster.From(obj), obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	for k := range payload {
+		delete(obj.Object, k)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PayloadAnnotationKey] = key
+	annotations[PayloadChecksumAnnotationKey] = "sha256:" + checksum
+	obj.SetAnnotations(annotations)
+
+	return nil
+}
+
+// Resolve restores the offloaded payload of obj from the blob store after verifying its
+// checksum. The offloading annotations are kept, such that writers know which blob the
+// object refers to. Objects that are not offloaded are left untouched.
+func (o *Offloader) Resolve(ctx context.Context, obj *unstructured.Unstructured) error {
+	key := PayloadRefFrom(obj)
+	if key == "" {
+		return nil
+	}
+
+	data, err := o.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if expected, actual := obj.GetAnnotations()[PayloadChecksumAnnotationKey], "sha256:"+hex.EncodeToString(sum[:]); expected != actual {
+		return fmt.Errorf("%w: checksum mismatch of offloaded payload %q: expected %q, got %q", errInvalidPayload, key, expected, actual)
+	}
+
+	// decode the same way as the unstructured decoder does, i.e. with int64 numbers
+	payload := map[string]interface{}{}
+	if err := utiljson.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode offloaded payload %q: %v", errInvalidPayload, key, err)
+	}
+	for k, v := range payload {
+		obj.Object[k] = v
+	}
+
+	return nil
+}
+
+// Delete removes the blob the given reference points to.
+func (o *Offloader) Delete(ctx context.Context, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	return o.store.Delete(ctx, ref)
+}
+
+// Transform is a cache.TransformFunc resolving offloaded objects before they are stored in
+// an informer. It supports typed and unstructured objects. Objects are never delivered without
+// their payload:
+//
+//   - if the payload cannot be read, e.g. because the blob store is unavailable, Transform
+//     waits with exponential back-off per object and returns a cache.ErrRequeue, such that
+//     the informer keeps the version it has, if any, and retries the object.
+//   - if the payload is gone or corrupt for good, e.g. because a newer version replaced the
+//     blob already, the object is dropped with an error. A newer version will follow.
+//
+// Use TransformInformers to keep the last good version of objects on deletion too.
+func (o *Offloader) Transform(obj interface{}) (interface{}, error) {
+	return o.transform(nil, obj)
+}
+
+// transform implements Transform. With a store, objects whose payload is gone or corrupt for
+// good are replaced by the version in the store, i.e. the last good one. This lets deletions
+// pass, as the writer deletes the blob together with the object.
+func (o *Offloader) transform(store cache.Store, obj interface{}) (interface{}, error) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return obj, nil // e.g. tombstones
+	}
+	if _, found := metaObj.GetAnnotations()[PayloadAnnotationKey]; !found {
+		return obj, nil
+	}
+	key := fmt.Sprintf("%s|%s/%s", logicalcluster.From(metaObj), metaObj.GetNamespace(), metaObj.GetName())
+
+	resolved, err := o.resolveObject(obj)
+	if err == nil {
+		o.backoff.Reset(key)
+		return resolved, nil
+	}
+	err = fmt.Errorf("failed to resolve the offloaded payload of %T %s: %w", obj, key, err)
+
+	if !errors.Is(err, ErrNotFound) && !errors.Is(err, errInvalidPayload) {
+		o.backoff.GC()
+		o.backoff.Next(key, o.backoff.Clock.Now())
+		delay := o.backoff.Get(key)
+		utilruntime.HandleError(fmt.Errorf("%w, retrying in %s", err, delay))
+		o.sleep(delay)
+		return nil, cache.ErrRequeue{Err: err}
+	}
+
+	o.backoff.Reset(key)
+	if store != nil {
+		if stored, exists, getErr := store.Get(obj); getErr == nil && exists {
+			utilruntime.HandleError(fmt.Errorf("%w, keeping the last resolved version", err))
+			return stored, nil
+		}
+	}
+	return nil, err
+}
+
+func (o *Offloader) resolveObject(obj interface{}) (interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		u = u.DeepCopy()
+		if err := o.Resolve(context.Background(), u); err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: raw}
+	if err := o.Resolve(context.Background(), u); err != nil {
+		return nil, err
+	}
+	resolved := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// TransformInformers resolves offloaded objects in the given informers of the cache server like
+// Transform, keeping the last resolved version of objects whose payload is gone. A nil Offloader leaves the informers alone. It must be called before the informers
+// are started.
+func (o *Offloader) TransformInformers(informers ...cache.SharedIndexInformer) error {
+	if o == nil {
+		return nil
+	}
+	for _, informer := range informers {
+		store := informer.GetStore()
+		if err := informer.SetTransform(func(obj interface{}) (interface{}, error) { return o.transform(store, obj) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PayloadRefFrom returns the blob key of the offloaded payload of obj, or an empty string
+// if the payload of obj is not offloaded.
+func PayloadRefFrom(obj metav1.Object) string {
+	return obj.GetAnnotations()[PayloadAnnotationKey]
+}
+
+func removeAnnotations(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[PayloadAnnotationKey]; !found {
+		if _, found := annotations[PayloadChecksumAnnotationKey]; !found {
+			return
+		}
+	}
+	delete(annotations, PayloadAnnotationKey)
+	delete(annotations, PayloadChecksumAnnotationKey)
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offload
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// flakyBlobStore fails reads with err while failing is set.
+type flakyBlobStore struct {
+	BlobStore
+	err     error
+	failing bool
+}
+
+func (s *flakyBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if s.failing {
+		return nil, s.err
+	}
+	return s.BlobStore.Get(ctx, key)
+}
+
+func TestOffloadAndResolve(t *testing.T) {
+	ctx := context.Background()
+	gr := schema.GroupResource{Group: "apis.kcp.io", Resource: "apiresourceschemas"}
+
+	newObject := func(description string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apis.kcp.io/v1alpha1",
+			"kind":       "APIResourceSchema",
+			"metadata": map[string]interface{}{
+				"name": "v1.widgets.example.com",
+				"annotations": map[string]interface{}{
+					logicalcluster.AnnotationKey: "root",
+				},
+			},
+			"spec": map[string]interface{}{
+				"group":       "example.com",
+				"description": description,
+				"replicas":    int64(3),
+			},
+		}}
+	}
+
+	store, err := NewFileBlobStore(t.TempDir())
+	require.NoError(t, err)
+	o := NewOffloader(store, 1024)
+
+	t.Run("small objects are not offloaded", func(t *testing.T) {
+		obj := newObject("small")
+		require.NoError(t, o.Offload(ctx, "amber", gr, obj))
+		require.Equal(t, newObject("small"), obj)
+	})
+
+	t.Run("big objects are offloaded and resolved", func(t *testing.T) {
+		original := newObject(strings.Repeat("x", 2048))
+		obj := original.DeepCopy()
+		require.NoError(t, o.Offload(ctx, "amber", gr, obj))
+
+		ref := PayloadRefFrom(obj)
+		require.True(t, strings.HasPrefix(ref, "amber/root/apis.kcp.io/apiresourceschemas/_/v1.widgets.example.com/"), ref)
+		require.True(t, strings.HasPrefix(obj.GetAnnotations()[PayloadChecksumAnnotationKey], "sha256:"))
+		require.NotContains(t, obj.Object, "spec")
+
+		require.NoError(t, o.Resolve(ctx, obj))
+		require.Equal(t, original.Object["spec"], obj.Object["spec"])
+
+		// offloading again removes stale annotations if the object is small enough now
+		obj.Object["spec"] = newObject("small").Object["spec"]
+		require.NoError(t, o.Offload(ctx, "amber", gr, obj))
+		require.Empty(t, PayloadRefFrom(obj))
+		require.NotContains(t, obj.GetAnnotations(), PayloadChecksumAnnotationKey)
+
+		require.NoError(t, o.Delete(ctx, ref))
+		_, err := store.Get(ctx, ref)
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		obj := newObject(strings.Repeat("x", 2048))
+		require.NoError(t, o.Offload(ctx, "amber", gr, obj))
+		require.NoError(t, store.Put(ctx, PayloadRefFrom(obj), []byte(`{"spec":{}}`)))
+
+		err := o.Resolve(ctx, obj)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch")
+
+		_, err = o.Transform(obj)
+		require.Error(t, err, "objects must not be delivered without their payload")
+		var requeue cache.ErrRequeue
+		require.False(t, errors.As(err, &requeue), "corrupt payloads must not be retried")
+
+		// with an informer store, the last resolved version is kept, e.g. to let deletions pass.
+		lastGood := newObject("last good")
+		store := cache.NewStore(kcpcache.MetaClusterNamespaceKeyFunc)
+		require.NoError(t, store.Add(lastGood))
+		transformed, err := o.transform(store, obj)
+		require.NoError(t, err)
+		require.Same(t, lastGood, transformed)
+	})
+
+	t.Run("unavailable store", func(t *testing.T) {
+		unavailable := &flakyBlobStore{BlobStore: store, err: errors.New("connection refused")}
+		o := NewOffloader(unavailable, 1024)
+		var slept []time.Duration
+		o.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+		original := newObject(strings.Repeat("x", 2048))
+		obj := original.DeepCopy()
+		require.NoError(t, o.Offload(ctx, "amber", gr, obj))
+		unavailable.failing = true
+
+		// the last good version is not used either, the object is retried until the store is back.
+		store := cache.NewStore(kcpcache.MetaClusterNamespaceKeyFunc)
+		require.NoError(t, store.Add(newObject("last good")))
+		for i := 0; i < 3; i++ {
+			transformed, err := o.transform(store, obj)
+			require.Nil(t, transformed, "objects must not be delivered without their payload")
+			var requeue cache.ErrRequeue
+			require.True(t, errors.As(err, &requeue), "expected the object to be requeued, got %v", err)
+		}
+		require.Equal(t, []time.Duration{initialResolveBackoff, 2 * initialResolveBackoff, 4 * initialResolveBackoff}, slept)
+
+		unavailable.failing = false
+		transformed, err := o.transform(store, obj)
+		require.NoError(t, err)
+		require.Equal(t, original.Object["spec"], transformed.(*unstructured.Unstructured).Object["spec"])
+	})
+
+	t.Run("typed objects are resolved by the informer transform", func(t *testing.T) {
+		apiResourceSchema := &apisv1alpha1.APIResourceSchema{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apis.kcp.io/v1alpha1", Kind: "APIResourceSchema"},
+			ObjectMeta: metav1.ObjectMeta{Name: "v1.widgets.example.com"},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: strings.Repeat("x", 2048)},
+			},
+		}
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(apiResourceSchema)
+		require.NoError(t, err)
+		u := &unstructured.Unstructured{Object: raw}
+		require.NoError(t, o.Offload(ctx, "amber", gr, u))
+		offloaded := &apisv1alpha1.APIResourceSchema{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, offloaded))
+		require.Empty(t, offloaded.Spec.Group)
+
+		transformed, err := o.Transform(offloaded)
+		require.NoError(t, err)
+		resolved, ok := transformed.(*apisv1alpha1.APIResourceSchema)
+		require.True(t, ok)
+		require.Equal(t, apiResourceSchema.Spec, resolved.Spec)
+		require.NotEmpty(t, PayloadRefFrom(resolved))
+	})
+}
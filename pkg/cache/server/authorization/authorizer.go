@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+var (
+	readVerbs   = sets.NewString("get", "list", "watch")
+	healthPaths = []string{"/livez", "/readyz", "/healthz"}
+)
+
+// NewShardAuthorizer returns an authorizer allowing the shard identities to read the objects
+// of all shards, and to write the objects of their own shard only. Health checks are allowed
+// for everybody. Rejected writes are logged.
+func NewShardAuthorizer(identities *ShardIdentities) authorizer.Authorizer {
+	return &shardAuthorizer{identities: identities}
+}
+
+type shardAuthorizer struct {
+	identities *ShardIdentities
+}
+
+func (a *shardAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	if !attr.IsResourceRequest() && attr.GetVerb() == "get" {
+		for _, p := range healthPaths {
+			if attr.GetPath() == p || strings.HasPrefix(attr.GetPath(), p+"/") {
+				return authorizer.DecisionAllow, "", nil
+			}
+		}
+	}
+
+	u := attr.GetUser()
+	if u == nil {
+		return authorizer.DecisionNoOpinion, "no user", nil
+	}
+	shards := a.identities.shardsOf(u)
+	if readVerbs.Has(attr.GetVerb()) {
+		if shards.Len() == 0 {
+			return authorizer.DecisionNoOpinion, fmt.Sprintf("user %q is not a shard identity", u.GetName()), nil
+		}
+		return authorizer.DecisionAllow, "", nil
+	}
+
+	shard := request.ShardFrom(ctx)
+	if !shard.Empty() && !shard.Wildcard() && shards.Has(string(shard)) {
+		return authorizer.DecisionAllow, "", nil
+	}
+
+	reason := fmt.Sprintf("user %q is not allowed to write to shard %q", u.GetName(), shard)
+	klog.FromContext(ctx).WithName("shard-authorization").Info("rejected push",
+		"user", u.GetName(),
+		"groups", u.GetGroups(),
+		"shard", shard,
+		"userShards", shards.List(),
+		"verb", attr.GetVerb(),
+		"resource", attr.GetResource(),
+		"name", attr.GetName(),
+		"path", attr.GetPath(),
+	)
+	return authorizer.DecisionNoOpinion, reason, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestLoadShardIdentities(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		wantErr bool
+	}{
+		"valid": {
+			content: "shards:\n- name: amber\n  users: [shard-amber]\n- name: sapphire\n  groups: [shards:sapphire]\n",
+		},
+		"unknown field": {
+			content: "shards:\n- name: amber\n  user: [shard-amber]\n",
+			wantErr: true,
+		},
+		"duplicate shard": {
+			content: "shards:\n- name: amber\n  users: [a]\n- name: amber\n  users: [b]\n",
+			wantErr: true,
+		},
+		"wildcard shard": {
+			content: "shards:\n- name: '*'\n  users: [a]\n",
+			wantErr: true,
+		},
+		"no identity": {
+			content: "shards:\n- name: amber\n",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "identities.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+			_, err := LoadShardIdentities(path)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShardAuthorizer(t *testing.T) {
+	a := NewShardAuthorizer(&ShardIdentities{Shards: []ShardIdentity{
+		{Name: "amber", Users: []string{"shard-amber"}},
+		{Name: "sapphire", Groups: []string{"shards:sapphire"}},
+	}})
+	amber := &user.DefaultInfo{Name: "shard-amber"}
+	sapphire := &user.DefaultInfo{Name: "shard-sapphire-1", Groups: []string{"shards:sapphire"}}
+	stranger := &user.DefaultInfo{Name: "stranger"}
+
+	tests := map[string]struct {
+		user      user.Info
+		shard     request.Shard
+		verb      string
+		path      string
+		wantAllow bool
+	}{
+		"own shard write":         {user: amber, shard: "amber", verb: "create", wantAllow: true},
+		"own shard via group":     {user: sapphire, shard: "sapphire", verb: "update", wantAllow: true},
+		"other shard write":       {user: amber, shard: "sapphire", verb: "delete"},
+		"wildcard write":          {user: amber, shard: "*", verb: "deletecollection"},
+		"replication stream":      {user: amber, shard: "amber", verb: "post", path: "/replication", wantAllow: true},
+		"other replication":       {user: sapphire, shard: "amber", verb: "post", path: "/replication"},
+		"read across shards":      {user: sapphire, shard: "*", verb: "list", wantAllow: true},
+		"stranger read":           {user: stranger, shard: "*", verb: "list"},
+		"stranger write":          {user: stranger, shard: "amber", verb: "create"},
+		"health check":            {user: &user.DefaultInfo{Name: user.Anonymous}, verb: "get", path: "/readyz", wantAllow: true},
+		"health check subpath":    {user: &user.DefaultInfo{Name: user.Anonymous}, verb: "get", path: "/livez/ping", wantAllow: true},
+		"anonymous metrics":       {user: &user.DefaultInfo{Name: user.Anonymous}, verb: "get", path: "/metrics"},
+		"shard identity metrics":  {user: amber, verb: "get", path: "/metrics", wantAllow: true},
+		"health check write":      {user: &user.DefaultInfo{Name: user.Anonymous}, verb: "post", path: "/readyz"},
+		"no shard in the context": {user: amber, verb: "create"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := request.WithShard(context.Background(), tt.shard)
+			attr := authorizer.AttributesRecord{
+				User:            tt.user,
+				Verb:            tt.verb,
+				Path:            tt.path,
+				ResourceRequest: tt.path == "",
+				APIGroup:        "apis.kcp.io",
+				Resource:        "apiexports",
+				Name:            "foo",
+			}
+			decision, _, err := a.Authorize(ctx, attr)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAllow, decision == authorizer.DecisionAllow)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authorization restricts the writes to the cache server to the shard identity of the
+// client. The shard identities are configured in an allow-list mapping users and groups, e.g.
+// the common name of a client certificate, to shard names.
+package authorization
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"sigs.k8s.io/yaml"
+)
+
+// ShardIdentities is the allow-list of the identities of the shards.
+type ShardIdentities struct {
+	// Shards are the identities allowed to write the objects of a shard. All of them may read the
+	// objects of all shards.
+	Shards []ShardIdentity `json:"shards"`
+}
+
+// ShardIdentity maps users and groups to a shard.
+type ShardIdentity struct {
+	// Name is the name of the shard.
+	Name string `json:"name"`
+	// Users are the names of the users acting as the shard.
+	Users []string `json:"users,omitempty"`
+	// Groups are the groups whose members act as the shard.
+	Groups []string `json:"groups,omitempty"`
+}
+
+// LoadShardIdentities reads and validates the shard identities from the given YAML or JSON file.
+func LoadShardIdentities(path string) (*ShardIdentities, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var identities ShardIdentities
+	if err := yaml.UnmarshalStrict(bs, &identities); err != nil {
+		return nil, fmt.Errorf("failed to parse shard identities %q: %w", path, err)
+	}
+	if err := identities.validate(); err != nil {
+		return nil, fmt.Errorf("invalid shard identities %q: %w", path, err)
+	}
+	return &identities, nil
+}
+
+func (s *ShardIdentities) validate() error {
+	seen := sets.NewString()
+	for i, shard := range s.Shards {
+		switch {
+		case shard.Name == "":
+			return fmt.Errorf("shards[%d]: name must not be empty", i)
+		case shard.Name == "*":
+			return fmt.Errorf("shards[%d]: name must not be the wildcard", i)
+		case seen.Has(shard.Name):
+			return fmt.Errorf("shards[%d]: duplicate shard %q", i, shard.Name)
+		case len(shard.Users) == 0 && len(shard.Groups) == 0:
+			return fmt.Errorf("shards[%d]: at least one user or group is required", i)
+		}
+		seen.Insert(shard.Name)
+	}
+	return nil
+}
+
+// shardsOf returns the names of the shards the user acts as.
+func (s *ShardIdentities) shardsOf(u user.Info) sets.String {
+	shards := sets.NewString()
+	groups := sets.NewString(u.GetGroups()...)
+	for _, shard := range s.Shards {
+		if sets.NewString(shard.Users...).Has(u.GetName()) || groups.HasAny(shard.Groups...) {
+			shards.Insert(shard.Name)
+		}
+	}
+	return shards
+}
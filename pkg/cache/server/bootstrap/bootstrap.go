@@ -42,25 +42,15 @@ var SystemCRDLogicalCluster = logicalcluster.Name("system:system-crds")
 const SystemCacheServerShard = "system:cache:server"
 
 func Bootstrap(ctx context.Context, apiExtensionsClusterClient kcpapiextensionsclientset.ClusterInterface) error {
+	// all kcp APIs are served, such that ReplicationConfigs can opt any of them into the replication.
+	grs, err := configcrds.GroupResources()
+	if err != nil {
+		return err
+	}
 	crds := []*apiextensionsv1.CustomResourceDefinition{}
-	for _, gr := range []struct{ group, resource string }{
-		{"apis.kcp.io", "apiresourceschemas"},
-		{"apis.kcp.io", "apiconversions"},
-		{"apis.kcp.io", "apiexports"},
-		{"core.kcp.io", "logicalclusters"},
-		{"core.kcp.io", "shards"},
-		{"tenancy.kcp.io", "workspacetypes"},
-		{"workload.kcp.io", "synctargets"},
-		{"scheduling.kcp.io", "locations"},
-		{"rbac.authorization.k8s.io", "roles"},
-		{"rbac.authorization.k8s.io", "clusterroles"},
-		{"rbac.authorization.k8s.io", "rolebindings"},
-		{"rbac.authorization.k8s.io", "clusterrolebindings"},
-		{"admissionregistration.k8s.io", "mutatingwebhookconfigurations"},
-		{"admissionregistration.k8s.io", "validatingwebhookconfigurations"},
-	} {
+	for _, gr := range grs {
 		crd := &apiextensionsv1.CustomResourceDefinition{}
-		if err := configcrds.Unmarshal(fmt.Sprintf("%s_%s.yaml", gr.group, gr.resource), crd); err != nil {
+		if err := configcrds.Unmarshal(fmt.Sprintf("%s_%s.yaml", gr.Group, gr.Resource), crd); err != nil {
 			panic(fmt.Errorf("failed to unmarshal %v resource: %w", gr, err))
 		}
 		for i := range crd.Spec.Versions {
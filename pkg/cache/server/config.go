@@ -22,6 +22,8 @@ import (
 	"strings"
 	"time"
 
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
@@ -31,6 +33,7 @@ import (
 	apiextensionsoptions "k8s.io/apiextensions-apiserver/pkg/cmd/server/options"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/features"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
@@ -38,7 +41,11 @@ import (
 	"k8s.io/client-go/rest"
 
 	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/replication"
 	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	cacheauthorization "github.com/kcp-dev/kcp/pkg/cache/server/authorization"
+	"github.com/kcp-dev/kcp/pkg/cache/server/gc"
+	"github.com/kcp-dev/kcp/pkg/cache/server/metrics"
 	cacheserveroptions "github.com/kcp-dev/kcp/pkg/cache/server/options"
 	"github.com/kcp-dev/kcp/pkg/embeddedetcd"
 	"github.com/kcp-dev/kcp/pkg/server/filters"
@@ -65,6 +72,9 @@ type completedConfig struct {
 type ExtraConfig struct {
 	ApiExtensionsClusterClient         kcpapiextensionsclientset.ClusterInterface
 	ApiExtensionsSharedInformerFactory kcpapiextensionsinformers.SharedInformerFactory
+	DynamicClusterClient               kcpdynamic.ClusterInterface
+	// Heartbeats records when shards have been seen last, for the garbage collection of objects of expired shards.
+	Heartbeats *gc.Heartbeats
 }
 
 type CompletedConfig struct {
@@ -88,6 +98,7 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 	c := &Config{
 		Options: opts,
 	}
+	c.Heartbeats = gc.NewHeartbeats()
 	if opts.EmbeddedEtcd.Enabled {
 		var err error
 		c.EmbeddedEtcd, err = embeddedetcd.NewConfig(opts.EmbeddedEtcd, opts.Etcd.EnableWatchCache)
@@ -110,6 +121,7 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 	if err := opts.ServerRunOptions.ApplyTo(&serverConfig.Config); err != nil {
 		return nil, err
 	}
+	serverConfig.EnableMetrics = opts.EnableMetrics
 	if err := opts.Etcd.ApplyTo(&serverConfig.Config); err != nil {
 		return nil, err
 	}
@@ -129,12 +141,20 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 	if err := opts.Authorization.ApplyTo(&serverConfig.Config.Authorization); err != nil {
 		return nil, err
 	}
+	if opts.ShardIdentitiesFile != "" {
+		if err := applyShardAuthorization(opts, &serverConfig.Config); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := opts.APIEnablement.ApplyTo(&serverConfig.Config, apiextensionsapiserver.DefaultAPIResourceConfigSource(), apiextensionsapiserver.Scheme); err != nil {
 		return nil, err
 	}
 
 	serverConfig.Config.BuildHandlerChainFunc = func(apiHandler http.Handler, genericConfig *genericapiserver.Config) (secure http.Handler) {
+		apiHandler = metrics.WithPushErrors(apiHandler)
+		// only authorized writes keep a shard alive.
+		apiHandler = gc.WithHeartbeats(apiHandler, c.Heartbeats)
 		apiHandler = genericapiserver.DefaultBuildHandlerChainFromAuthz(apiHandler, genericConfig)
 		apiHandler = genericapiserver.DefaultBuildHandlerChainBeforeAuthz(apiHandler, genericConfig)
 		apiHandler = filters.WithAuditEventClusterAnnotation(apiHandler)
@@ -145,6 +165,15 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 		return apiHandler
 	}
 
+	// the replication stream of a shard is open as long as the shard is running.
+	basicLongRunningRequestCheck := serverConfig.LongRunningFunc
+	serverConfig.LongRunningFunc = func(r *http.Request, requestInfo *request.RequestInfo) bool {
+		if requestInfo != nil && !requestInfo.IsResourceRequest && requestInfo.Path == replication.Path {
+			return true
+		}
+		return basicLongRunningRequestCheck(r, requestInfo)
+	}
+
 	opts.Etcd.StorageConfig.Paging = utilfeature.DefaultFeatureGate.Enabled(features.APIListChunking)
 	// this is where the true decodable levels come from.
 	opts.Etcd.StorageConfig.Codec = apiextensionsapiserver.Codecs.LegacyCodec(apiextensionsv1beta1.SchemeGroupVersion, apiextensionsv1.SchemeGroupVersion)
@@ -193,6 +222,11 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 		return nil, err
 	}
 
+	c.DynamicClusterClient, err = kcpdynamic.NewForConfig(rt)
+	if err != nil {
+		return nil, err
+	}
+
 	c.ApiExtensionsSharedInformerFactory = kcpapiextensionsinformers.NewSharedInformerFactoryWithOptions(
 		c.ApiExtensionsClusterClient,
 		resyncPeriod,
@@ -201,7 +235,7 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 	c.ApiExtensions = &apiextensionsapiserver.Config{
 		GenericConfig: serverConfig,
 		ExtraConfig: apiextensionsapiserver.ExtraConfig{
-			CRDRESTOptionsGetter:   apiextensionsoptions.NewCRDRESTOptionsGetter(*opts.Etcd),
+			CRDRESTOptionsGetter:   crdRESTOptionsGetter{delegate: apiextensionsoptions.NewCRDRESTOptionsGetter(*opts.Etcd)},
 			MasterCount:            1,
 			Client:                 c.ApiExtensionsClusterClient,
 			Informers:              c.ApiExtensionsSharedInformerFactory,
@@ -214,6 +248,34 @@ func NewConfig(opts *cacheserveroptions.CompletedOptions, optionalLocalShardRest
 	return c, nil
 }
 
+// applyShardAuthorization authenticates the clients of the cache server and restricts their
+// writes to the shard they are allowed to act as. The storage sets the shard annotation of
+// written objects to the shard of the request, i.e. stored objects are always annotated for
+// the authorized shard. The loopback client is always allowed.
+func applyShardAuthorization(opts *cacheserveroptions.CompletedOptions, config *genericapiserver.Config) error {
+	identities, err := cacheauthorization.LoadShardIdentities(opts.ShardIdentitiesFile)
+	if err != nil {
+		return err
+	}
+
+	authenticatorConfig, err := opts.ClientAuthentication.ToAuthenticationConfig()
+	if err != nil {
+		return err
+	}
+	if authenticatorConfig.ClientCAContentProvider != nil {
+		if err := config.Authentication.ApplyClientCert(authenticatorConfig.ClientCAContentProvider, config.SecureServing); err != nil {
+			return fmt.Errorf("unable to load client CA file: %w", err)
+		}
+	}
+	if config.Authentication.Authenticator, _, err = authenticatorConfig.New(); err != nil {
+		return err
+	}
+	config.Authorization.Authorizer = cacheauthorization.NewShardAuthorizer(identities)
+
+	genericapiserver.AuthorizeClientBearerToken(config.LoopbackClientConfig, &config.Authentication, &config.Authorization)
+	return nil
+}
+
 // nopCRConversionFactory implements conversion.Factory and always returns a no-op converter because we currently have
 // no need to perform CR conversions in the cache server.
 type nopCRConversionFactory struct{}
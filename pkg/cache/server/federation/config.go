@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// Config configures the peers of a cache server and the resources replicated from them.
+type Config struct {
+	// Name is the peer name of this cache server on the other cache servers. If set, the
+	// resources of ReplicationConfigs listing it as endpoint are replicated from the peers too.
+	Name string `json:"name,omitempty"`
+	// Peers are the cache servers of the other regions.
+	Peers []Peer `json:"peers"`
+	// Resources are the resources replicated from the peers.
+	Resources []Resource `json:"resources"`
+}
+
+// Peer is a cache server of another region.
+type Peer struct {
+	// Name identifies the peer. It is recorded as the origin of the objects replicated from it,
+	// and must be the same on all cache servers the peer is configured on.
+	Name string `json:"name"`
+	// Kubeconfig is the path of the kubeconfig to read from the peer.
+	Kubeconfig string `json:"kubeconfig"`
+}
+
+// Resource is a resource replicated from the peers.
+type Resource struct {
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+}
+
+func (r Resource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+// LoadConfig reads and validates the federation config from the given YAML or JSON file.
+func LoadConfig(path string) (*Config, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(bs, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse federation config %q: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid federation config %q: %w", path, err)
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	if len(c.Peers) == 0 {
+		return fmt.Errorf("at least one peer is required")
+	}
+	seen := sets.NewString()
+	for i, peer := range c.Peers {
+		switch {
+		case peer.Name == "":
+			return fmt.Errorf("peers[%d]: name must not be empty", i)
+		case peer.Name == c.Name:
+			return fmt.Errorf("peers[%d]: peer %q must not have the name of this cache server", i, peer.Name)
+		case seen.Has(peer.Name):
+			return fmt.Errorf("peers[%d]: duplicate peer %q", i, peer.Name)
+		case peer.Kubeconfig == "":
+			return fmt.Errorf("peers[%d]: kubeconfig must not be empty", i)
+		}
+		seen.Insert(peer.Name)
+	}
+
+	if len(c.Resources) == 0 && c.Name == "" {
+		return fmt.Errorf("at least one resource or the name of this cache server is required")
+	}
+	seenResources := map[schema.GroupVersionResource]bool{}
+	for i, r := range c.Resources {
+		switch {
+		case r.Version == "" || r.Resource == "":
+			return fmt.Errorf("resources[%d]: version and resource must not be empty", i)
+		case seenResources[r.GroupVersionResource()]:
+			return fmt.Errorf("resources[%d]: duplicate resource %q", i, r.GroupVersionResource().String())
+		}
+		seenResources[r.GroupVersionResource()] = true
+	}
+	return nil
+}
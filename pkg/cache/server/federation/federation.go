@@ -0,0 +1,327 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package federation implements the federation of cache servers of different regions. A federated
+// cache server periodically reads a configured set of resources, and the resources of the
+// ReplicationConfigs listing it as endpoint, from its peers and writes them locally, into the
+// shards they belong to, such that shards only ever talk to the cache server of their region.
+// Replicated objects are annotated with the peer they originate from, and objects replicated from
+// a third cache server are never replicated again, which prevents replication loops.
+package federation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	"github.com/kcp-dev/kcp/pkg/cache/server/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/cache/server/gc"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+const (
+	ControllerName = "cache-server-federation"
+
+	// OriginAnnotationKey is set on objects replicated from a peer to the name of the peer.
+	OriginAnnotationKey = "internal.cache.kcp.io/origin"
+)
+
+type listFunc func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error)
+
+type peer struct {
+	name string
+	list listFunc
+}
+
+var replicationConfigs = corev1alpha1.SchemeGroupVersion.WithResource("replicationconfigs")
+
+// Federator replicates the objects of the shards of the peers to the local cache server.
+type Federator struct {
+	// name is the peer name of the local cache server. Empty if it is not an endpoint of ReplicationConfigs.
+	name      string
+	peers     []peer
+	resources []schema.GroupVersionResource
+
+	// observe keeps the shards of the peers alive for the garbage collection.
+	observe   func(shardName string)
+	listLocal listFunc
+	create    func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error
+	update    func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error
+	delete    func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error
+}
+
+// NewFederator returns a federator replicating from the peers of the config to the cache server
+// of the given loopback client.
+func NewFederator(config *Config, heartbeats *gc.Heartbeats, client kcpdynamic.ClusterInterface) (*Federator, error) {
+	f := &Federator{
+		name:      config.Name,
+		observe:   heartbeats.Observe,
+		listLocal: listFromClient(client),
+		create: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			_, err := resourceClient(client, gvr, obj).Create(cacheclient.WithShardInContext(ctx, shard.New(shardName)), obj, metav1.CreateOptions{})
+			return err
+		},
+		update: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			_, err := resourceClient(client, gvr, obj).Update(cacheclient.WithShardInContext(ctx, shard.New(shardName)), obj, metav1.UpdateOptions{})
+			return err
+		},
+		delete: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			uid := obj.GetUID()
+			return resourceClient(client, gvr, obj).Delete(cacheclient.WithShardInContext(ctx, shard.New(shardName)), obj.GetName(), metav1.DeleteOptions{
+				// don't delete an object that has been replaced in the meantime
+				Preconditions: &metav1.Preconditions{UID: &uid},
+			})
+		},
+	}
+	for _, r := range config.Resources {
+		f.resources = append(f.resources, r.GroupVersionResource())
+	}
+	for _, p := range config.Peers {
+		peerClient, err := newPeerClient(p)
+		if err != nil {
+			return nil, err
+		}
+		f.peers = append(f.peers, peer{name: p.Name, list: listFromClient(peerClient)})
+	}
+	return f, nil
+}
+
+func newPeerClient(p Peer) (kcpdynamic.ClusterInterface, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: p.Kubeconfig}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig of peer %q from %q: %w", p.Name, p.Kubeconfig, err)
+	}
+	rt := cacheclient.WithCacheServiceRoundTripper(config)
+	rt = cacheclient.WithShardNameFromContextRoundTripper(rt)
+	rt = cacheclient.WithDefaultShardRoundTripper(rt, shard.Wildcard)
+	rt = rest.AddUserAgent(rt, "kcp-cache-server-federation")
+	return kcpdynamic.NewForConfig(rt)
+}
+
+// listFromClient lists the objects of all shards and clusters.
+func listFromClient(client kcpdynamic.ClusterInterface) listFunc {
+	return func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+		list, err := client.Resource(gvr).List(cacheclient.WithShardInContext(ctx, shard.Wildcard), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+}
+
+func resourceClient(client kcpdynamic.ClusterInterface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) dynamic.ResourceInterface {
+	clusterClient := client.Resource(gvr).Cluster(logicalcluster.From(obj).Path())
+	if ns := obj.GetNamespace(); ns != "" {
+		return clusterClient.Namespace(ns)
+	}
+	return clusterClient
+}
+
+// Start replicates from the peers every interval until the context is done.
+func (f *Federator) Start(ctx context.Context, interval time.Duration) {
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "name", f.name, "peers", len(f.peers), "resources", len(f.resources), "interval", interval)
+	defer logger.Info("Shutting down controller")
+
+	wait.UntilWithContext(ctx, f.sync, interval)
+}
+
+// sync replicates all resources from all peers.
+func (f *Federator) sync(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	resources := f.endpointResources(ctx)
+	for _, p := range f.peers {
+		for _, gvr := range resources {
+			if err := f.syncResource(ctx, p, gvr); err != nil {
+				logger.Error(err, "failed to replicate from peer", "peer", p.name, "resource", gvr.GroupResource().String())
+			}
+		}
+	}
+}
+
+// endpointResources returns the configured resources, and the resources of the ReplicationConfigs
+// of the root workspace listing the local cache server as endpoint. The ReplicationConfigs are read
+// locally and from all peers, as only the cache server of the region of the root shard holds them
+// unless they are federated.
+func (f *Federator) endpointResources(ctx context.Context) []schema.GroupVersionResource {
+	resources := append([]schema.GroupVersionResource(nil), f.resources...)
+	if f.name == "" {
+		return resources
+	}
+	logger := klog.FromContext(ctx)
+
+	seen := make(map[schema.GroupVersionResource]bool, len(resources))
+	for _, gvr := range resources {
+		seen[gvr] = true
+	}
+	sources := append([]peer{{name: "", list: f.listLocal}}, f.peers...)
+	for _, p := range sources {
+		objs, err := p.list(ctx, replicationConfigs)
+		if err != nil {
+			logger.Error(err, "failed to list ReplicationConfigs", "peer", p.name)
+			continue
+		}
+		for i := range objs {
+			if logicalcluster.From(&objs[i]) != core.RootCluster {
+				continue
+			}
+			var config corev1alpha1.ReplicationConfig
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objs[i].Object, &config); err != nil {
+				logging.WithObject(logger, &objs[i]).Error(err, "failed to decode ReplicationConfig", "peer", p.name)
+				continue
+			}
+			for _, r := range config.Spec.Resources {
+				gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+				if seen[gvr] || !sets.NewString(r.Endpoints...).Has(f.name) {
+					continue
+				}
+				seen[gvr] = true
+				resources = append(resources, gvr)
+			}
+		}
+	}
+	return resources
+}
+
+type objectKey struct {
+	shard, cluster, namespace, name string
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	return objectKey{
+		shard:     obj.GetAnnotations()[shard.AnnotationKey],
+		cluster:   logicalcluster.From(obj).String(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+// syncResource makes the local replicas of one resource of a peer match the objects of the peer.
+// Nothing is deleted if the peer cannot be read.
+func (f *Federator) syncResource(ctx context.Context, p peer, gvr schema.GroupVersionResource) error {
+	logger := klog.FromContext(ctx).WithValues("peer", p.name, "resource", gvr.GroupResource().String())
+
+	remote, err := p.list(ctx, gvr)
+	if err != nil {
+		return fmt.Errorf("failed to list the objects of the peer: %w", err)
+	}
+	local, err := f.listLocal(ctx, gvr)
+	if err != nil {
+		return fmt.Errorf("failed to list the local objects: %w", err)
+	}
+	existing := make(map[objectKey]*unstructured.Unstructured, len(local))
+	for i := range local {
+		existing[keyOf(&local[i])] = &local[i]
+	}
+
+	observed := sets.NewString()
+	wanted := map[objectKey]bool{}
+	for i := range remote {
+		obj := &remote[i]
+		if _, replicated := obj.GetAnnotations()[OriginAnnotationKey]; replicated {
+			// replicated to the peer from a third cache server, which we replicate from directly.
+			continue
+		}
+		key := keyOf(obj)
+		if key.shard == "" || key.shard == bootstrap.SystemCacheServerShard {
+			continue
+		}
+		if !observed.Has(key.shard) {
+			observed.Insert(key.shard)
+			f.observe(key.shard)
+		}
+		wanted[key] = true
+
+		replica := newReplica(obj, p.name)
+		current, found := existing[key]
+		switch {
+		case !found:
+			err = f.create(ctx, gvr, key.shard, replica)
+		case current.GetAnnotations()[OriginAnnotationKey] != p.name:
+			logging.WithObject(logger, obj).Info("not replicating object, a different object of the same shard exists locally", "shard", key.shard, "origin", current.GetAnnotations()[OriginAnnotationKey])
+			continue
+		case equality.Semantic.DeepEqual(newReplica(current, p.name).Object, replica.Object):
+			continue
+		default:
+			replica.SetResourceVersion(current.GetResourceVersion())
+			err = f.update(ctx, gvr, key.shard, replica)
+		}
+		if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+			// changed in the meantime, retried with the next sync.
+			continue
+		} else if err != nil {
+			logging.WithObject(logger, obj).Error(err, "failed to replicate object", "shard", key.shard)
+			continue
+		}
+		logging.WithObject(logger, obj).V(4).Info("replicated object", "shard", key.shard)
+	}
+
+	for key, obj := range existing {
+		if obj.GetAnnotations()[OriginAnnotationKey] != p.name || wanted[key] {
+			continue
+		}
+		if err := f.delete(ctx, gvr, key.shard, obj); apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			continue
+		} else if err != nil {
+			logging.WithObject(logger, obj).Error(err, "failed to delete replica", "shard", key.shard)
+			continue
+		}
+		logging.WithObject(logger, obj).V(4).Info("deleted replica", "shard", key.shard)
+	}
+	return nil
+}
+
+// newReplica returns a copy of the object without the fields owned by the storage, annotated
+// with the origin.
+func newReplica(obj *unstructured.Unstructured, origin string) *unstructured.Unstructured {
+	replica := obj.DeepCopy()
+	replica.SetResourceVersion("")
+	replica.SetUID("")
+	replica.SetGeneration(0)
+	replica.SetCreationTimestamp(metav1.Time{})
+	replica.SetManagedFields(nil)
+	replica.SetSelfLink("")
+
+	annotations := replica.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[OriginAnnotationKey] = origin
+	replica.SetAnnotations(annotations)
+	return replica
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+var apiExports = schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apiexports"}
+
+func newObject(shardName, cluster, name, origin string, spec map[string]interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apis.kcp.io/v1alpha1",
+		"kind":       "APIExport",
+		"spec":       spec,
+	}}
+	obj.SetName(name)
+	obj.SetResourceVersion("42")
+	obj.SetUID(types.UID(shardName + "-" + name))
+	annotations := map[string]string{
+		shard.AnnotationKey:          shardName,
+		logicalcluster.AnnotationKey: cluster,
+	}
+	if origin != "" {
+		annotations[OriginAnnotationKey] = origin
+	}
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+// fakeStore is the local cache server.
+type fakeStore struct {
+	objects map[objectKey]unstructured.Unstructured
+	writes  []string
+}
+
+func (s *fakeStore) federator(peers ...peer) (*Federator, *[]string) {
+	var observed []string
+	return &Federator{
+		peers:     peers,
+		resources: []schema.GroupVersionResource{apiExports},
+		observe:   func(shardName string) { observed = append(observed, shardName) },
+		listLocal: func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			var objs []unstructured.Unstructured
+			for _, obj := range s.objects {
+				objs = append(objs, obj)
+			}
+			return objs, nil
+		},
+		create: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			s.writes = append(s.writes, "create "+shardName+"/"+obj.GetName())
+			s.objects[keyOf(obj)] = *obj
+			return nil
+		},
+		update: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			s.writes = append(s.writes, "update "+shardName+"/"+obj.GetName())
+			s.objects[keyOf(obj)] = *obj
+			return nil
+		},
+		delete: func(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+			s.writes = append(s.writes, "delete "+shardName+"/"+obj.GetName())
+			delete(s.objects, keyOf(obj))
+			return nil
+		},
+	}, &observed
+}
+
+func staticPeer(name string, objs *[]unstructured.Unstructured, err *error) peer {
+	return peer{name: name, list: func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+		if *err != nil {
+			return nil, *err
+		}
+		return *objs, nil
+	}}
+}
+
+func TestSyncResource(t *testing.T) {
+	local := newObject("amber", "root", "local", "", nil)
+	store := &fakeStore{objects: map[objectKey]unstructured.Unstructured{
+		keyOf(&local): local,
+	}}
+
+	var peerErr error
+	remote := []unstructured.Unstructured{
+		newObject("sapphire", "root", "widgets", "", map[string]interface{}{"a": "b"}),
+		// replicated to the peer from ourselves or a third cache server, must not loop.
+		newObject("amber", "root", "local", "eu", nil),
+		newObject("ruby", "root", "gadgets", "us", nil),
+		// clashes with an object of a local shard.
+		newObject("amber", "root", "local", "", map[string]interface{}{"clash": "true"}),
+	}
+	f, observed := store.federator(staticPeer("eu", &remote, &peerErr))
+	ctx := context.Background()
+
+	require.NoError(t, f.syncResource(ctx, f.peers[0], apiExports))
+	require.Equal(t, []string{"create sapphire/widgets"}, store.writes)
+	require.Equal(t, []string{"sapphire", "amber"}, *observed)
+	replica := store.objects[keyOf(&remote[0])]
+	require.Equal(t, "eu", replica.GetAnnotations()[OriginAnnotationKey])
+	require.Empty(t, replica.GetResourceVersion())
+	require.Empty(t, replica.GetUID())
+	require.Equal(t, local, store.objects[keyOf(&local)], "local objects must not be overwritten")
+
+	t.Log("Nothing is written if nothing changed")
+	store.writes = nil
+	replica.SetResourceVersion("1")
+	store.objects[keyOf(&replica)] = replica
+	require.NoError(t, f.syncResource(ctx, f.peers[0], apiExports))
+	require.Empty(t, store.writes)
+
+	t.Log("Changes are replicated")
+	remote[0] = newObject("sapphire", "root", "widgets", "", map[string]interface{}{"a": "c"})
+	require.NoError(t, f.syncResource(ctx, f.peers[0], apiExports))
+	require.Equal(t, []string{"update sapphire/widgets"}, store.writes)
+	replica = store.objects[keyOf(&remote[0])]
+	require.Equal(t, "1", replica.GetResourceVersion())
+	require.Equal(t, map[string]interface{}{"a": "c"}, replica.Object["spec"])
+
+	t.Log("Nothing is deleted if the peer is unavailable")
+	store.writes = nil
+	peerErr = errors.New("unavailable")
+	require.Error(t, f.syncResource(ctx, f.peers[0], apiExports))
+	require.Empty(t, store.writes)
+
+	t.Log("Replicas of other peers are kept when the object is deleted on the peer")
+	other := newObject("emerald", "root", "widgets", "us", nil)
+	store.objects[keyOf(&other)] = other
+	peerErr = nil
+	remote = remote[1:]
+	require.NoError(t, f.syncResource(ctx, f.peers[0], apiExports))
+	require.Equal(t, []string{"delete sapphire/widgets"}, store.writes)
+	require.Len(t, store.objects, 2)
+	require.Contains(t, store.objects, keyOf(&local))
+	require.Contains(t, store.objects, keyOf(&other))
+}
+
+func newReplicationConfig(cluster, name string, resources ...interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.kcp.io/v1alpha1",
+		"kind":       "ReplicationConfig",
+		"spec":       map[string]interface{}{"resources": resources},
+	}}
+	obj.SetName(name)
+	obj.SetAnnotations(map[string]string{
+		shard.AnnotationKey:          "root",
+		logicalcluster.AnnotationKey: cluster,
+	})
+	return obj
+}
+
+func TestEndpointResources(t *testing.T) {
+	workspaces := map[string]interface{}{"group": "tenancy.kcp.io", "version": "v1alpha1", "resource": "workspaces", "endpoints": []interface{}{"us", "eu"}}
+	secrets := map[string]interface{}{"version": "v1", "resource": "secrets", "endpoints": []interface{}{"eu"}}
+	configMaps := map[string]interface{}{"version": "v1", "resource": "configmaps"}
+	apiExportsOfUS := map[string]interface{}{"group": "apis.kcp.io", "version": "v1alpha1", "resource": "apiexports", "endpoints": []interface{}{"us"}}
+
+	listConfigs := func(objs ...unstructured.Unstructured) listFunc {
+		return func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			require.Equal(t, replicationConfigs, gvr)
+			return objs, nil
+		}
+	}
+	var peerErr error
+	f := &Federator{
+		name:      "us",
+		resources: []schema.GroupVersionResource{apiExports},
+		listLocal: listConfigs(newReplicationConfig("root", "a", workspaces, secrets, configMaps)),
+		peers: []peer{
+			{name: "eu", list: listConfigs(
+				newReplicationConfig("root", "b", apiExportsOfUS),
+				newReplicationConfig("other", "c", map[string]interface{}{"version": "v1", "resource": "pods", "endpoints": []interface{}{"us"}}),
+			)},
+			{name: "ap", list: func(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+				return nil, peerErr
+			}},
+		},
+	}
+	peerErr = errors.New("unavailable")
+
+	require.Equal(t, []schema.GroupVersionResource{
+		apiExports,
+		{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces"},
+	}, f.endpointResources(context.Background()), "only resources listing the cache server as endpoint in the root workspace are replicated, once")
+
+	f.name = ""
+	require.Equal(t, []schema.GroupVersionResource{apiExports}, f.endpointResources(context.Background()), "unnamed cache servers are no endpoints")
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"valid": {
+			config: `
+peers:
+- name: eu
+  kubeconfig: /etc/kcp/eu.kubeconfig
+resources:
+- group: apis.kcp.io
+  version: v1alpha1
+  resource: apiexports
+`,
+		},
+		"no peers": {
+			config:  "resources: [{version: v1, resource: things}]",
+			wantErr: "at least one peer is required",
+		},
+		"duplicate peer": {
+			config:  "peers: [{name: eu, kubeconfig: a}, {name: eu, kubeconfig: b}]\nresources: [{version: v1, resource: things}]",
+			wantErr: `duplicate peer "eu"`,
+		},
+		"no resources": {
+			config:  "peers: [{name: eu, kubeconfig: a}]",
+			wantErr: "at least one resource or the name of this cache server is required",
+		},
+		"endpoint of ReplicationConfigs only": {
+			config: "name: us\npeers: [{name: eu, kubeconfig: a}]",
+		},
+		"peer named like this cache server": {
+			config:  "name: eu\npeers: [{name: eu, kubeconfig: a}]",
+			wantErr: `peer "eu" must not have the name of this cache server`,
+		},
+		"unknown field": {
+			config:  "peers: [{name: eu, kubeconfig: a, url: b}]\nresources: [{version: v1, resource: things}]",
+			wantErr: "failed to parse",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "federation.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600))
+
+			config, err := LoadConfig(path)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, r := range config.Resources {
+				require.Equal(t, apiExports, r.GroupVersionResource())
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc implements the garbage collection of objects of shards that disappeared, e.g.
+// because they died or have been decommissioned. Shards prove to be alive by sending heartbeats
+// or by writing objects. Objects of shards not seen within the TTL are purged.
+package gc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	"github.com/kcp-dev/kcp/pkg/cache/server/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const ControllerName = "cache-server-gc"
+
+var (
+	purgedObjects = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "cache_server_gc_purged_objects_total",
+			Help:           "Number of objects purged from the cache server because their shard has not been seen within the TTL.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"shard", "resource"},
+	)
+	purgeErrors = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "cache_server_gc_errors_total",
+			Help:           "Number of errors listing or purging objects of expired shards.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	registerMetrics sync.Once
+)
+
+// Collector periodically purges objects of shards whose heartbeat expired.
+type Collector struct {
+	heartbeats *Heartbeats
+	store      Store
+	crdLister  kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister
+	client     kcpdynamic.ClusterInterface
+	ttl        time.Duration
+}
+
+// NewCollector returns a Collector purging the objects of shards not seen within the ttl, by any of
+// the replicas of the cache server sharing the store.
+func NewCollector(heartbeats *Heartbeats, store Store, crdLister kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister, client kcpdynamic.ClusterInterface, ttl time.Duration) *Collector {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(purgedObjects)
+		legacyregistry.MustRegister(purgeErrors)
+	})
+
+	return &Collector{
+		heartbeats: heartbeats,
+		store:      store,
+		crdLister:  crdLister,
+		client:     client,
+		ttl:        ttl,
+	}
+}
+
+// Start runs the collector every interval until the context is done.
+func (c *Collector) Start(ctx context.Context, interval time.Duration) {
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "ttl", c.ttl, "interval", interval)
+	defer logger.Info("Shutting down controller")
+
+	wait.UntilWithContext(ctx, c.collect, interval)
+}
+
+// collect purges the objects of expired shards of all resources served by the cache server.
+func (c *Collector) collect(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	// without the heartbeats received by the other replicas, live shards might look expired.
+	if err := c.heartbeats.Sync(ctx, c.store); err != nil {
+		logger.Error(err, "failed to sync heartbeats, skipping garbage collection")
+		return
+	}
+
+	crds, err := c.crdLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list CustomResourceDefinitions")
+		return
+	}
+	for _, crd := range crds {
+		gvr, ok := storageGVR(crd)
+		if !ok {
+			continue
+		}
+		if err := c.collectResource(ctx, gvr); err != nil {
+			purgeErrors.WithLabelValues(gvr.GroupResource().String()).Inc()
+			logger.Error(err, "failed to collect objects of expired shards", "resource", gvr.GroupResource().String())
+		}
+	}
+}
+
+func (c *Collector) collectResource(ctx context.Context, gvr schema.GroupVersionResource) error {
+	logger := klog.FromContext(ctx).WithValues("resource", gvr.GroupResource().String())
+
+	expired := map[string]bool{}
+	options := metav1.ListOptions{Limit: listPageSize}
+	for {
+		list, err := c.client.Resource(gvr).List(cacheclient.WithShardInContext(ctx, shard.Wildcard), options)
+		if err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			shardName, found := obj.GetAnnotations()[shard.AnnotationKey]
+			if !found || shardName == bootstrap.SystemCacheServerShard {
+				continue
+			}
+			if _, checked := expired[shardName]; !checked {
+				expired[shardName] = c.heartbeats.Expired(shardName, c.ttl)
+				if expired[shardName] {
+					logger.V(2).Info("shard has not been seen within the TTL, purging its objects", "shard", shardName)
+				}
+			}
+			if !expired[shardName] {
+				continue
+			}
+
+			if err := c.purge(ctx, gvr, shardName, obj); apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				// gone or replaced in the meantime
+				continue
+			} else if err != nil {
+				purgeErrors.WithLabelValues(gvr.GroupResource().String()).Inc()
+				logging.WithObject(logger, obj).Error(err, "failed to purge object", "shard", shardName)
+				continue
+			}
+			purgedObjects.WithLabelValues(shardName, gvr.GroupResource().String()).Inc()
+			logging.WithObject(logger, obj).V(4).Info("purged object", "shard", shardName)
+		}
+
+		if list.GetContinue() == "" {
+			return nil
+		}
+		options.Continue = list.GetContinue()
+	}
+}
+
+func (c *Collector) purge(ctx context.Context, gvr schema.GroupVersionResource, shardName string, obj *unstructured.Unstructured) error {
+	clusterClient := c.client.Resource(gvr).Cluster(logicalcluster.From(obj).Path())
+	var client dynamic.ResourceInterface = clusterClient
+	if ns := obj.GetNamespace(); ns != "" {
+		client = clusterClient.Namespace(ns)
+	}
+	uid := obj.GetUID()
+	return client.Delete(cacheclient.WithShardInContext(ctx, shard.New(shardName)), obj.GetName(), metav1.DeleteOptions{
+		// don't delete an object that has been replaced in the meantime
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+}
+
+func storageGVR(crd *apiextensionsv1.CustomResourceDefinition) (schema.GroupVersionResource, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return schema.GroupVersionResource{Group: crd.Spec.Group, Version: v.Name, Resource: crd.Spec.Names.Plural}, true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+)
+
+// Heartbeats keeps track of the last time each shard has been seen, i.e. has sent a heartbeat
+// or written an object. Heartbeats are recorded in memory, and shared with the other replicas of
+// the cache server through a Store by Sync. Every shard is considered seen at startup, such that no
+// shard expires before it had the chance to send a heartbeat to a restarted replica.
+type Heartbeats struct {
+	now   func() time.Time
+	start time.Time
+
+	lock     sync.RWMutex
+	lastSeen map[string]time.Time
+	// synced is when each shard has been seen last at the previous Sync.
+	synced map[string]time.Time
+}
+
+func NewHeartbeats() *Heartbeats {
+	return &Heartbeats{
+		now:      time.Now,
+		start:    time.Now(),
+		lastSeen: map[string]time.Time{},
+		synced:   map[string]time.Time{},
+	}
+}
+
+// Observe records that the shard is alive.
+func (h *Heartbeats) Observe(shard string) {
+	now := h.now()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.lastSeen[shard] = now
+}
+
+// Expired returns true if the shard has not been seen within the ttl.
+func (h *Heartbeats) Expired(shard string, ttl time.Duration) bool {
+	h.lock.RLock()
+	lastSeen, found := h.lastSeen[shard]
+	h.lock.RUnlock()
+
+	if !found || lastSeen.Before(h.start) {
+		lastSeen = h.start
+	}
+	return h.now().Sub(lastSeen) > ttl
+}
+
+// Sync writes the shards seen since the previous Sync to the store, and records the shards
+// seen by other replicas.
+func (h *Heartbeats) Sync(ctx context.Context, store Store) error {
+	h.lock.RLock()
+	var changed map[string]time.Time
+	for shard, lastSeen := range h.lastSeen {
+		if synced, found := h.synced[shard]; !found || lastSeen.After(synced) {
+			if changed == nil {
+				changed = map[string]time.Time{}
+			}
+			changed[shard] = lastSeen
+		}
+	}
+	h.lock.RUnlock()
+
+	for shard, lastSeen := range changed {
+		if err := store.Put(ctx, shard, lastSeen); err != nil {
+			return err
+		}
+	}
+	stored, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for shard, lastSeen := range stored {
+		if lastSeen.After(h.lastSeen[shard]) {
+			h.lastSeen[shard] = lastSeen
+		}
+		h.synced[shard] = lastSeen
+	}
+	for shard, lastSeen := range changed {
+		if lastSeen.After(h.synced[shard]) {
+			h.synced[shard] = lastSeen
+		}
+	}
+	return nil
+}
+
+// Handler returns the handler of the heartbeat endpoint. If onHeartbeat is not nil, it is called
+// with the body of each heartbeat.
+func (h *Heartbeats) Handler(onHeartbeat func(shardName string, hb *heartbeat.Heartbeat)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		shardName := request.ShardFrom(req.Context())
+		if shardName.Empty() || shardName.Wildcard() {
+			http.Error(w, "heartbeat requires a single shard", http.StatusBadRequest)
+			return
+		}
+		var hb heartbeat.Heartbeat
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(&hb); err != nil && !errors.Is(err, io.EOF) {
+				http.Error(w, fmt.Sprintf("invalid heartbeat: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		h.Observe(string(shardName))
+		if onHeartbeat != nil {
+			onHeartbeat(string(shardName), &hb)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// WithHeartbeats records every create, update and patch request of a single shard as a heartbeat
+// of that shard. Deletions are not recorded, as the garbage collector deletes on behalf of
+// expired shards.
+func WithHeartbeats(handler http.Handler, heartbeats *Heartbeats) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			if shardName := request.ShardFrom(req.Context()); !shardName.Empty() && !shardName.Wildcard() {
+				heartbeats.Observe(string(shardName))
+			}
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestHeartbeats(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := &Heartbeats{
+		now:      func() time.Time { return now },
+		start:    now,
+		lastSeen: map[string]time.Time{},
+		synced:   map[string]time.Time{},
+	}
+	const ttl = time.Minute
+
+	require.False(t, h.Expired("amber", ttl), "unknown shards are considered seen at startup")
+
+	now = now.Add(2 * ttl)
+	require.True(t, h.Expired("amber", ttl))
+
+	h.Observe("amber")
+	require.False(t, h.Expired("amber", ttl))
+	require.True(t, h.Expired("sapphire", ttl))
+
+	serve := func(handler http.Handler, method, shard string) int {
+		req := httptest.NewRequest(method, "/", nil)
+		req = req.WithContext(request.WithShard(req.Context(), request.Shard(shard)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	now = now.Add(2 * ttl)
+	require.Equal(t, http.StatusOK, serve(h.Handler(nil), http.MethodPost, "amber"))
+	require.False(t, h.Expired("amber", ttl), "a heartbeat refreshes the shard")
+	require.Equal(t, http.StatusMethodNotAllowed, serve(h.Handler(nil), http.MethodGet, "amber"))
+	require.Equal(t, http.StatusBadRequest, serve(h.Handler(nil), http.MethodPost, "*"))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	require.Equal(t, http.StatusOK, serve(WithHeartbeats(ok, h), http.MethodDelete, "sapphire"))
+	require.True(t, h.Expired("sapphire", ttl), "deletions don't refresh the shard")
+	require.Equal(t, http.StatusOK, serve(WithHeartbeats(ok, h), http.MethodPut, "sapphire"))
+	require.False(t, h.Expired("sapphire", ttl), "writes refresh the shard")
+}
+
+// memoryStore is a Store in memory, counting the writes.
+type memoryStore struct {
+	lastSeen map[string]time.Time
+	puts     int
+}
+
+func (s *memoryStore) Put(ctx context.Context, shard string, lastSeen time.Time) error {
+	s.puts++
+	if lastSeen.After(s.lastSeen[shard]) {
+		s.lastSeen[shard] = lastSeen
+	}
+	return nil
+}
+
+func (s *memoryStore) List(ctx context.Context) (map[string]time.Time, error) {
+	ret := make(map[string]time.Time, len(s.lastSeen))
+	for shard, lastSeen := range s.lastSeen {
+		ret[shard] = lastSeen
+	}
+	return ret, nil
+}
+
+func TestHeartbeatsSync(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	replica := func() *Heartbeats {
+		return &Heartbeats{
+			now:      func() time.Time { return now },
+			start:    now,
+			lastSeen: map[string]time.Time{},
+			synced:   map[string]time.Time{},
+		}
+	}
+	a, b := replica(), replica()
+	store := &memoryStore{lastSeen: map[string]time.Time{}}
+	const ttl = time.Minute
+	ctx := context.Background()
+
+	now = now.Add(ttl / 2)
+	a.Observe("amber")
+	require.NoError(t, a.Sync(ctx, store))
+	require.Equal(t, 1, store.puts)
+
+	now = now.Add(ttl)
+	require.NoError(t, b.Sync(ctx, store))
+	require.False(t, b.Expired("amber", ttl), "heartbeats received by other replicas are shared")
+	require.True(t, b.Expired("sapphire", ttl))
+
+	require.NoError(t, a.Sync(ctx, store))
+	require.NoError(t, b.Sync(ctx, store))
+	require.Equal(t, 1, store.puts, "shards not seen since the previous sync are not written again")
+
+	now = now.Add(ttl)
+	require.NoError(t, b.Sync(ctx, store))
+	require.True(t, b.Expired("amber", ttl))
+}
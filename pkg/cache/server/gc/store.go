@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Store persists when shards have been seen last, such that the heartbeats received by any replica
+// of the cache server are known to all of them, and survive restarts.
+type Store interface {
+	// Put records that the shard has been seen at the given time, unless it has been seen later.
+	Put(ctx context.Context, shard string, lastSeen time.Time) error
+	// List returns when each shard has been seen last.
+	List(ctx context.Context) (map[string]time.Time, error)
+}
+
+// listPageSize is the number of keys read from etcd at once.
+const listPageSize = 500
+
+// NewEtcdStore returns a Store keeping the heartbeats under the given prefix of etcd, next to the
+// objects of the cache server, in a group no resource is served of.
+func NewEtcdStore(kv clientv3.KV, prefix string) Store {
+	return &etcdStore{kv: kv, prefix: strings.TrimSuffix(prefix, "/") + "/gc.cache.kcp.io/heartbeats/"}
+}
+
+type etcdStore struct {
+	kv     clientv3.KV
+	prefix string
+}
+
+// encodeLastSeen encodes the time with a fixed width, such that the encoded values compare like the times.
+func encodeLastSeen(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
+
+func (s *etcdStore) Put(ctx context.Context, shard string, lastSeen time.Time) error {
+	key, value := s.prefix+shard, encodeLastSeen(lastSeen)
+	_, err := s.kv.Txn(ctx).If(
+		clientv3.Compare(clientv3.CreateRevision(key), "=", 0),
+	).Then(
+		clientv3.OpPut(key, value),
+	).Else(
+		clientv3.OpTxn(
+			[]clientv3.Cmp{clientv3.Compare(clientv3.Value(key), "<", value)},
+			[]clientv3.Op{clientv3.OpPut(key, value)},
+			nil,
+		),
+	).Commit()
+	return err
+}
+
+func (s *etcdStore) List(ctx context.Context) (map[string]time.Time, error) {
+	ret := map[string]time.Time{}
+	var rev int64
+	key, end := s.prefix, clientv3.GetPrefixRangeEnd(s.prefix)
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(listPageSize)}
+		if rev != 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := s.kv.Get(ctx, key, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		for _, kv := range resp.Kvs {
+			nanos, err := strconv.ParseInt(string(kv.Value), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid heartbeat of key %q: %w", string(kv.Key), err)
+			}
+			ret[strings.TrimPrefix(string(kv.Key), s.prefix)] = time.Unix(0, nanos)
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			return ret, nil
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
@@ -62,10 +62,10 @@ func init() {
 //
 // Note:
 // not all paths require to have a valid shard name,
-// as of today the following paths pass through: "/livez", "/readyz", "/healthz".
+// as of today the following paths pass through: "/livez", "/readyz", "/healthz", "/metrics".
 func WithShardScope(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		if path := req.URL.Path; path == "/livez" || path == "/readyz" || path == "/healthz" {
+		if path := req.URL.Path; path == "/livez" || path == "/readyz" || path == "/healthz" || path == "/metrics" {
 			handler.ServeHTTP(w, req)
 			return
 		}
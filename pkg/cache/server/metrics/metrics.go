@@ -0,0 +1,258 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements the replication metrics of the cache server, per shard and resource:
+// the replication lag as reported by the shards with their heartbeats, the errors of writes pushed
+// by the shards, and the number of cached objects.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+var (
+	labelNames = []string{"shard", "group", "version", "resource"}
+
+	lagResourceVersionsDesc = compbasemetrics.NewDesc(
+		"cache_server_replication_lag_resource_versions",
+		"Resource version delta between the latest change observed by the shard and its oldest change not replicated to the cache server yet. Zero if the cache server is up to date.",
+		labelNames, nil, compbasemetrics.ALPHA, "",
+	)
+	lagSecondsDesc = compbasemetrics.NewDesc(
+		"cache_server_replication_lag_seconds",
+		"Age of the oldest change of the shard not replicated to the cache server yet. Zero if the cache server is up to date.",
+		labelNames, nil, compbasemetrics.ALPHA, "",
+	)
+	pendingDesc = compbasemetrics.NewDesc(
+		"cache_server_replication_pending_objects",
+		"Number of objects of the shard with changes not replicated to the cache server yet.",
+		labelNames, nil, compbasemetrics.ALPHA, "",
+	)
+	objectsDesc = compbasemetrics.NewDesc(
+		"cache_server_objects",
+		"Number of objects in the cache server.",
+		labelNames, nil, compbasemetrics.ALPHA, "",
+	)
+
+	pushErrors = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "cache_server_replication_push_errors_total",
+			Help:           "Number of failed writes pushed by shards to the cache server, by HTTP status code.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		append(append([]string{}, labelNames...), "code"),
+	)
+)
+
+// collector collects the replication metrics of the cache server.
+type collector struct {
+	compbasemetrics.BaseStableCollector
+
+	now func() time.Time
+
+	lock sync.RWMutex
+	// states holds the latest reported replication state by shard and resource.
+	states map[string]map[schema.GroupVersionResource]reportedState
+	// objects holds the number of objects by shard and resource.
+	objects map[string]map[schema.GroupVersionResource]int
+}
+
+type reportedState struct {
+	heartbeat.ResourceState
+	received time.Time
+}
+
+var _ compbasemetrics.StableCollector = &collector{}
+
+var (
+	replication = &collector{
+		now:     time.Now,
+		states:  map[string]map[schema.GroupVersionResource]reportedState{},
+		objects: map[string]map[schema.GroupVersionResource]int{},
+	}
+
+	registerMetrics sync.Once
+)
+
+// Register metrics.
+func Register() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(pushErrors)
+		legacyregistry.CustomMustRegister(replication)
+	})
+}
+
+func init() {
+	Register()
+}
+
+// ObserveHeartbeat records the replication state reported by a shard with its heartbeat.
+func ObserveHeartbeat(shardName string, hb *heartbeat.Heartbeat) {
+	replication.observeHeartbeat(shardName, hb)
+}
+
+func (r *collector) observeHeartbeat(shardName string, hb *heartbeat.Heartbeat) {
+	if len(hb.Resources) == 0 {
+		return
+	}
+	now := r.now()
+	states := make(map[schema.GroupVersionResource]reportedState, len(hb.Resources))
+	for _, s := range hb.Resources {
+		states[schema.GroupVersionResource{Group: s.Group, Version: s.Version, Resource: s.Resource}] = reportedState{ResourceState: s, received: now}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.states[shardName] = states
+}
+
+// DescribeWithStability implements compbasemetrics.StableCollector.
+func (r *collector) DescribeWithStability(ch chan<- *compbasemetrics.Desc) {
+	ch <- lagResourceVersionsDesc
+	ch <- lagSecondsDesc
+	ch <- pendingDesc
+	ch <- objectsDesc
+}
+
+// CollectWithStability implements compbasemetrics.StableCollector.
+func (r *collector) CollectWithStability(ch chan<- compbasemetrics.Metric) {
+	now := r.now()
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for shardName, states := range r.states {
+		for gvr, s := range states {
+			var lagRVs, lagSeconds float64
+			if s.Pending > 0 {
+				if s.ObservedResourceVersion > s.OldestPendingResourceVersion {
+					lagRVs = float64(s.ObservedResourceVersion - s.OldestPendingResourceVersion)
+				}
+				// the oldest change keeps aging since the heartbeat was received.
+				lagSeconds = s.OldestPendingAgeSeconds + now.Sub(s.received).Seconds()
+			}
+			labelValues := []string{shardName, gvr.Group, gvr.Version, gvr.Resource}
+			ch <- compbasemetrics.NewLazyConstMetric(lagResourceVersionsDesc, compbasemetrics.GaugeValue, lagRVs, labelValues...)
+			ch <- compbasemetrics.NewLazyConstMetric(lagSecondsDesc, compbasemetrics.GaugeValue, lagSeconds, labelValues...)
+			ch <- compbasemetrics.NewLazyConstMetric(pendingDesc, compbasemetrics.GaugeValue, float64(s.Pending), labelValues...)
+		}
+	}
+	for shardName, counts := range r.objects {
+		for gvr, n := range counts {
+			ch <- compbasemetrics.NewLazyConstMetric(objectsDesc, compbasemetrics.GaugeValue, float64(n), shardName, gvr.Group, gvr.Version, gvr.Resource)
+		}
+	}
+}
+
+// StartCounting counts the objects in the cache server by shard and resource every interval
+// until the context is done.
+func StartCounting(ctx context.Context, crdLister kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister, client kcpdynamic.ClusterInterface, interval time.Duration) {
+	logger := klog.FromContext(ctx)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		crds, err := crdLister.List(labels.Everything())
+		if err != nil {
+			logger.Error(err, "failed to list CustomResourceDefinitions")
+			return
+		}
+
+		objects := map[string]map[schema.GroupVersionResource]int{}
+		for _, crd := range crds {
+			var gvr schema.GroupVersionResource
+			for _, v := range crd.Spec.Versions {
+				if v.Storage {
+					gvr = schema.GroupVersionResource{Group: crd.Spec.Group, Version: v.Name, Resource: crd.Spec.Names.Plural}
+				}
+			}
+			if gvr.Empty() {
+				continue
+			}
+
+			list, err := client.Resource(gvr).List(cacheclient.WithShardInContext(ctx, shard.Wildcard), metav1.ListOptions{})
+			if err != nil {
+				logger.Error(err, "failed to count objects", "resource", gvr.GroupResource().String())
+				return
+			}
+			for i := range list.Items {
+				shardName := list.Items[i].GetAnnotations()[shard.AnnotationKey]
+				if objects[shardName] == nil {
+					objects[shardName] = map[schema.GroupVersionResource]int{}
+				}
+				objects[shardName][gvr]++
+			}
+		}
+
+		replication.lock.Lock()
+		defer replication.lock.Unlock()
+		replication.objects = objects
+	}, interval)
+}
+
+// WithPushErrors counts failed writes of single shards. It must be installed after the
+// request info and the shard have been added to the request context.
+func WithPushErrors(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			handler.ServeHTTP(w, req)
+			return
+		}
+		shardName := request.ShardFrom(req.Context())
+		info, ok := request.RequestInfoFrom(req.Context())
+		if shardName.Empty() || shardName.Wildcard() || !ok || !info.IsResourceRequest {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(rw, req)
+		if rw.status >= http.StatusBadRequest {
+			pushErrors.WithLabelValues(string(shardName), info.APIGroup, info.APIVersion, info.Resource, strconv.Itoa(rw.status)).Inc()
+		}
+	})
+}
+
+// statusRecorder records the status code of a non-streaming response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
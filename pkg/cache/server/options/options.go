@@ -17,6 +17,7 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -37,6 +38,18 @@ type Options struct {
 	APIEnablement    *genericoptions.APIEnablementOptions
 	EmbeddedEtcd     etcdoptions.Options
 	SyntheticDelay   time.Duration
+	ShardTTL         time.Duration
+	GCInterval       time.Duration
+	EnableMetrics    bool
+
+	// ClientAuthentication authenticates the shards if ShardIdentitiesFile is set.
+	ClientAuthentication *kubeoptions.BuiltInAuthenticationOptions
+	// ShardIdentitiesFile is the allow-list of shard identities. If set, shards may only write their own objects.
+	ShardIdentitiesFile string
+
+	// FederationConfigFile configures the peer cache servers to replicate from. If empty, federation is disabled.
+	FederationConfigFile string
+	FederationInterval   time.Duration
 }
 
 type completedOptions struct {
@@ -48,6 +61,18 @@ type completedOptions struct {
 	APIEnablement    *genericoptions.APIEnablementOptions
 	EmbeddedEtcd     etcdoptions.CompletedOptions
 	SyntheticDelay   time.Duration
+	ShardTTL         time.Duration
+	GCInterval       time.Duration
+	EnableMetrics    bool
+
+	// ClientAuthentication authenticates the shards if ShardIdentitiesFile is set.
+	ClientAuthentication *kubeoptions.BuiltInAuthenticationOptions
+	// ShardIdentitiesFile is the allow-list of shard identities. If set, shards may only write their own objects.
+	ShardIdentitiesFile string
+
+	// FederationConfigFile configures the peer cache servers to replicate from. If empty, federation is disabled.
+	FederationConfigFile string
+	FederationInterval   time.Duration
 }
 
 type CompletedOptions struct {
@@ -63,6 +88,21 @@ func (o *CompletedOptions) Validate() []error {
 	errors = append(errors, o.Authorization.Validate()...)
 	errors = append(errors, o.APIEnablement.Validate()...)
 	errors = append(errors, o.EmbeddedEtcd.Validate()...)
+	if o.ShardTTL < 0 {
+		errors = append(errors, fmt.Errorf("--shard-ttl must not be negative"))
+	}
+	if o.ShardTTL > 0 && o.GCInterval <= 0 {
+		errors = append(errors, fmt.Errorf("--gc-interval must be positive"))
+	}
+	if o.FederationConfigFile != "" && o.FederationInterval <= 0 {
+		errors = append(errors, fmt.Errorf("--federation-interval must be positive"))
+	}
+	if o.ShardIdentitiesFile != "" {
+		errors = append(errors, o.ClientAuthentication.Validate()...)
+		if o.ClientAuthentication.ClientCert.ClientCA == "" && o.ClientAuthentication.TokenFile.TokenFile == "" {
+			errors = append(errors, fmt.Errorf("--client-ca-file or --token-auth-file is required with --shard-identities-file"))
+		}
+	}
 	return errors
 }
 
@@ -76,12 +116,22 @@ func NewOptions(rootDir string) *Options {
 		Authorization:    genericoptions.NewDelegatingAuthorizationOptions(),
 		APIEnablement:    genericoptions.NewAPIEnablementOptions(),
 		EmbeddedEtcd:     *etcdoptions.NewOptions(rootDir),
+		GCInterval:       time.Minute,
+		EnableMetrics:    true,
+
+		FederationInterval: 30 * time.Second,
+
+		ClientAuthentication: kubeoptions.NewBuiltInAuthenticationOptions().
+			WithAnonymous().
+			WithClientCert().
+			WithTokenFile(),
 	}
 
 	o.ServerRunOptions.EnablePriorityAndFairness = false
 	o.SecureServing.ServerCert.CertDirectory = rootDir
 	o.SecureServing.BindPort = 6443
 	o.Etcd.StorageConfig.Transport.ServerList = []string{"embedded"}
+	o.EmbeddedEtcd.Enabled = true
 	// TODO: enable the watch cache, it was disabled because
 	//  - we need to pass a shard name so that the watch cache can calculate the key
 	//    we already do that for cluster names (stored in the obj)
@@ -92,7 +142,12 @@ func NewOptions(rootDir string) *Options {
 
 func (o *Options) Complete() (*CompletedOptions, error) {
 	if servers := o.Etcd.StorageConfig.Transport.ServerList; len(servers) == 1 && servers[0] == "embedded" {
-		o.EmbeddedEtcd.Enabled = true
+		if !o.EmbeddedEtcd.Enabled {
+			return nil, fmt.Errorf("--etcd-servers must be specified if --embedded-etcd is false")
+		}
+	} else {
+		// an external etcd takes precedence, e.g. when the cache server runs within a kcp server.
+		o.EmbeddedEtcd.Enabled = false
 	}
 
 	// TODO: enable authN/Z stack
@@ -111,11 +166,30 @@ func (o *Options) Complete() (*CompletedOptions, error) {
 		Authorization:    o.Authorization,
 		APIEnablement:    o.APIEnablement,
 		EmbeddedEtcd:     o.EmbeddedEtcd.Complete(o.Etcd),
+		SyntheticDelay:   o.SyntheticDelay,
+		ShardTTL:         o.ShardTTL,
+		GCInterval:       o.GCInterval,
+		EnableMetrics:    o.EnableMetrics,
+
+		ClientAuthentication: o.ClientAuthentication,
+		ShardIdentitiesFile:  o.ShardIdentitiesFile,
+
+		FederationConfigFile: o.FederationConfigFile,
+		FederationInterval:   o.FederationInterval,
 	}}, nil
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.EmbeddedEtcd.Enabled, "embedded-etcd", o.EmbeddedEtcd.Enabled, "Run an embedded etcd server storing the data in --embedded-etcd-directory. Ignored if --etcd-servers is specified.")
 	o.EmbeddedEtcd.AddFlags(fs)
+	o.Etcd.AddFlags(fs)
 	o.SecureServing.AddFlags(fs)
 	fs.DurationVar(&o.SyntheticDelay, "synthetic-delay", 0, "The duration of time the cache server will inject a delay for to all inbound requests. Useful for testing.")
+	fs.DurationVar(&o.ShardTTL, "shard-ttl", o.ShardTTL, "The duration after which objects of a shard are purged if the shard has neither sent a heartbeat nor written an object. Zero disables the garbage collection.")
+	fs.BoolVar(&o.EnableMetrics, "enable-metrics", o.EnableMetrics, "Serve the Prometheus metrics, including the replication lag of the shards, at /metrics.")
+	fs.DurationVar(&o.GCInterval, "gc-interval", o.GCInterval, "The interval of the garbage collection of objects of shards whose TTL expired.")
+	o.ClientAuthentication.AddFlags(fs)
+	fs.StringVar(&o.ShardIdentitiesFile, "shard-identities-file", o.ShardIdentitiesFile, "A YAML file mapping users and groups, authenticated by --client-ca-file or --token-auth-file, to shard names. If set, clients may only write the objects of their own shard.")
+	fs.StringVar(&o.FederationConfigFile, "federation-config-file", o.FederationConfigFile, "A YAML file configuring peer cache servers, e.g. of other regions, and the resources to replicate from them. If set, the objects of the shards of the peers are replicated to this cache server.")
+	fs.DurationVar(&o.FederationInterval, "federation-interval", o.FederationInterval, "The interval in which the resources of --federation-config-file are replicated from the peers.")
 }
@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replication implements the server side of the incremental replication protocol,
+// see github.com/kcp-dev/kcp/pkg/cache/client/replication.
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/replication"
+	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+)
+
+// NewHandler returns the handler of the replication endpoint. It applies the deltas streamed
+// by a shard through the given client, and acknowledges each of them in the response.
+//
+// The client must target the cache server itself, i.e. add the /services/cache prefix and
+// the shard from the context to request paths.
+func NewHandler(client kcpdynamic.ClusterInterface) http.Handler {
+	return &handler{client: client}
+}
+
+type handler struct {
+	client kcpdynamic.ClusterInterface
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	shardName := request.ShardFrom(req.Context())
+	if shardName.Empty() || shardName.Wildcard() {
+		http.Error(w, "replication requires a single shard", http.StatusBadRequest)
+		return
+	}
+	if req.ProtoMajor < 2 {
+		http.Error(w, "replication requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	reqEncoding, ok := replication.EncodingForContentType(req.Header.Get("Content-Type"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", req.Header.Get("Content-Type")), http.StatusUnsupportedMediaType)
+		return
+	}
+	reqCompressed, ok := parseContentEncoding(req.Header.Get("Content-Encoding"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported content encoding %q", req.Header.Get("Content-Encoding")), http.StatusUnsupportedMediaType)
+		return
+	}
+	respEncoding := negotiateEncoding(req.Header.Get("Accept"), reqEncoding)
+	respCompressed := acceptsGzip(req.Header.Get("Accept-Encoding"))
+
+	logger := klog.FromContext(req.Context()).WithValues("shard", shardName)
+	ctx := cacheclient.WithShardInContext(req.Context(), shard.New(string(shardName)))
+
+	dec, err := replication.NewDecoder(req.Body, reqEncoding, reqCompressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", respEncoding.ContentType())
+	if respCompressed {
+		w.Header().Set("Content-Encoding", replication.ContentEncodingGzip)
+	}
+	enc, err := replication.NewEncoder(w, respEncoding, respCompressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		var delta replication.Delta
+		if err := dec.Decode(&delta); err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				logger.V(2).Info("replication stream failed", "err", err)
+			}
+			return
+		}
+
+		ack := h.apply(ctx, &delta)
+		if err := enc.Encode(ack); err != nil {
+			logger.V(2).Info("replication stream failed", "err", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// parseContentEncoding returns whether the request body is gzip compressed, and false as second
+// value if the content encoding is not supported.
+func parseContentEncoding(contentEncoding string) (gzip bool, ok bool) {
+	switch strings.TrimSpace(contentEncoding) {
+	case "", "identity":
+		return false, true
+	case replication.ContentEncodingGzip:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// negotiateEncoding returns the first supported encoding of the Accept header, or the encoding of
+// the request if there is none.
+func negotiateEncoding(accept string, fallback replication.Encoding) replication.Encoding {
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+		if mediaType == "" {
+			continue
+		}
+		if encoding, ok := replication.EncodingForContentType(mediaType); ok {
+			return encoding
+		}
+	}
+	return fallback
+}
+
+// acceptsGzip returns whether the Accept-Encoding header allows gzip compressed responses.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		parts := strings.SplitN(coding, ";", 2)
+		if strings.TrimSpace(parts[0]) != replication.ContentEncodingGzip {
+			continue
+		}
+		return len(parts) == 1 || strings.ReplaceAll(parts[1], " ", "") != "q=0"
+	}
+	return false
+}
+
+// apply applies the delta and returns its acknowledgement.
+func (h *handler) apply(ctx context.Context, delta *replication.Delta) *replication.Ack {
+	ack := &replication.Ack{ID: delta.ID}
+
+	obj, err := h.applyDelta(ctx, delta)
+	if err != nil {
+		var status apierrors.APIStatus
+		if !errors.As(err, &status) {
+			status = apierrors.NewInternalError(err)
+		}
+		s := status.Status()
+		ack.Status = &s
+		return ack
+	}
+	if obj != nil {
+		ack.ResourceVersion = obj.GetResourceVersion()
+	}
+	return ack
+}
+
+func (h *handler) applyDelta(ctx context.Context, delta *replication.Delta) (*unstructured.Unstructured, error) {
+	if delta.Cluster == "" || delta.Name == "" || delta.Resource == "" || delta.Version == "" {
+		return nil, apierrors.NewBadRequest("cluster, name, version and resource are required")
+	}
+	gvr := schema.GroupVersionResource{Group: delta.Group, Version: delta.Version, Resource: delta.Resource}
+	clusterClient := h.client.Resource(gvr).Cluster(logicalcluster.NewPath(delta.Cluster))
+	var client dynamic.ResourceInterface = clusterClient
+	if delta.Namespace != "" {
+		client = clusterClient.Namespace(delta.Namespace)
+	}
+
+	switch delta.Type {
+	case replication.DeltaAdded:
+		obj, err := decodeObject(delta)
+		if err != nil {
+			return nil, err
+		}
+		return client.Create(ctx, obj, metav1.CreateOptions{})
+	case replication.DeltaModified:
+		if delta.Object != nil {
+			obj, err := decodeObject(delta)
+			if err != nil {
+				return nil, err
+			}
+			if delta.ResourceVersion != "" {
+				obj.SetResourceVersion(delta.ResourceVersion)
+			}
+			return client.Update(ctx, obj, metav1.UpdateOptions{})
+		}
+		if len(delta.Patch) == 0 {
+			return nil, apierrors.NewBadRequest("modification requires an object or a patch")
+		}
+		patch, err := withResourceVersion(delta.Patch, delta.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		return client.Patch(ctx, delta.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case replication.DeltaDeleted:
+		var opts metav1.DeleteOptions
+		if delta.ResourceVersion != "" {
+			opts.Preconditions = &metav1.Preconditions{ResourceVersion: &delta.ResourceVersion}
+		}
+		return nil, client.Delete(ctx, delta.Name, opts)
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unknown delta type %q", delta.Type))
+	}
+}
+
+func decodeObject(delta *replication.Delta) (*unstructured.Unstructured, error) {
+	obj := delta.Object
+	if obj == nil {
+		return nil, apierrors.NewBadRequest("object is required")
+	}
+	if obj.GetName() != delta.Name || obj.GetNamespace() != delta.Namespace {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("object %s/%s does not match the delta %s/%s", obj.GetNamespace(), obj.GetName(), delta.Namespace, delta.Name))
+	}
+	return obj, nil
+}
+
+// withResourceVersion adds the resource version to the merge patch, turning it into a precondition.
+func withResourceVersion(patch []byte, rv string) ([]byte, error) {
+	if rv == "" {
+		return patch, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(patch, &m); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid patch: %v", err))
+	}
+	if err := unstructured.SetNestedField(m, rv, "metadata", "resourceVersion"); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid patch: %v", err))
+	}
+	return json.Marshal(m)
+}
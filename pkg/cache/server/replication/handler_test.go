@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/cache/client/replication"
+)
+
+func TestNegotiation(t *testing.T) {
+	require.Equal(t, replication.EncodingJSON, negotiateEncoding("", replication.EncodingJSON))
+	require.Equal(t, replication.EncodingProtobuf, negotiateEncoding("", replication.EncodingProtobuf))
+	require.Equal(t, replication.EncodingProtobuf, negotiateEncoding("text/plain, application/vnd.kcp.replication+protobuf;q=0.9, application/json", replication.EncodingJSON))
+	require.Equal(t, replication.EncodingJSON, negotiateEncoding("*/*", replication.EncodingJSON))
+
+	require.True(t, acceptsGzip("gzip"))
+	require.True(t, acceptsGzip("deflate, gzip;q=0.5"))
+	require.False(t, acceptsGzip("gzip; q=0"))
+	require.False(t, acceptsGzip("identity"))
+	require.False(t, acceptsGzip(""))
+
+	for contentEncoding, expected := range map[string]bool{"": false, "identity": false, "gzip": true} {
+		gzip, ok := parseContentEncoding(contentEncoding)
+		require.True(t, ok)
+		require.Equal(t, expected, gzip)
+	}
+	_, ok := parseContentEncoding("br")
+	require.False(t, ok)
+}
+
+func TestUnsupportedMediaType(t *testing.T) {
+	for name, headers := range map[string]map[string]string{
+		"content type":     {"Content-Type": "application/vnd.kubernetes.protobuf"},
+		"content encoding": {"Content-Type": replication.ContentTypeProtobuf, "Content-Encoding": "br"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/replication", strings.NewReader(""))
+			req.ProtoMajor = 2
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			req = req.WithContext(request.WithShard(req.Context(), "amber"))
+
+			w := httptest.NewRecorder()
+			NewHandler(nil).ServeHTTP(w, req)
+			require.Equal(t, http.StatusUnsupportedMediaType, w.Code, "shards fall back to JSON on 415")
+		})
+	}
+}
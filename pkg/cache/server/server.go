@@ -18,14 +18,30 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
 
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+	cacheclientreplication "github.com/kcp-dev/kcp/pkg/cache/client/replication"
 	"github.com/kcp-dev/kcp/pkg/cache/server/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/cache/server/federation"
+	"github.com/kcp-dev/kcp/pkg/cache/server/gc"
+	"github.com/kcp-dev/kcp/pkg/cache/server/metrics"
+	"github.com/kcp-dev/kcp/pkg/cache/server/replication"
 )
 
+// objectCountInterval is the interval in which the objects are counted for the metrics.
+const objectCountInterval = time.Minute
+
 type Server struct {
 	CompletedConfig
 
@@ -42,6 +58,8 @@ func NewServer(c CompletedConfig) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.apiextensions.GenericAPIServer.Handler.NonGoRestfulMux.Handle(cacheclientreplication.Path, replication.NewHandler(s.DynamicClusterClient))
+	s.apiextensions.GenericAPIServer.Handler.NonGoRestfulMux.Handle(heartbeat.Path, s.Heartbeats.Handler(metrics.ObserveHeartbeat))
 	return s, nil
 }
 
@@ -79,6 +97,72 @@ func (s *Server) PrepareRun(ctx context.Context) (preparedServer, error) {
 	}); err != nil {
 		return preparedServer{}, err
 	}
+
+	if s.Options.EnableMetrics {
+		if err := s.apiextensions.GenericAPIServer.AddPostStartHook("cache-server-object-metrics", func(hookContext genericapiserver.PostStartHookContext) error {
+			logger := logger.WithValues("postStartHook", "cache-server-object-metrics")
+			ctx := klog.NewContext(goContext(hookContext), logger)
+			crdInformer := s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions()
+			go func() {
+				if !cache.WaitForCacheSync(hookContext.StopCh, crdInformer.Informer().HasSynced) {
+					return
+				}
+				metrics.StartCounting(ctx, crdInformer.Lister(), s.DynamicClusterClient, objectCountInterval)
+			}()
+			return nil
+		}); err != nil {
+			return preparedServer{}, err
+		}
+	}
+
+	if s.Options.ShardTTL > 0 {
+		if err := s.apiextensions.GenericAPIServer.AddPostStartHook("cache-server-gc", func(hookContext genericapiserver.PostStartHookContext) error {
+			logger := logger.WithValues("postStartHook", "cache-server-gc")
+			ctx := klog.NewContext(goContext(hookContext), logger)
+			crdInformer := s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions()
+			etcdClient, err := newEtcdClient(ctx, s.Options.Etcd.StorageConfig.Transport)
+			if err != nil {
+				return err
+			}
+			store := gc.NewEtcdStore(etcdClient, s.Options.Etcd.StorageConfig.Prefix)
+			collector := gc.NewCollector(s.Heartbeats, store, crdInformer.Lister(), s.DynamicClusterClient, s.Options.ShardTTL)
+			go func() {
+				defer etcdClient.Close()
+				if !cache.WaitForCacheSync(hookContext.StopCh, crdInformer.Informer().HasSynced) {
+					return
+				}
+				collector.Start(ctx, s.Options.GCInterval)
+			}()
+			return nil
+		}); err != nil {
+			return preparedServer{}, err
+		}
+	}
+
+	if s.Options.FederationConfigFile != "" {
+		federationConfig, err := federation.LoadConfig(s.Options.FederationConfigFile)
+		if err != nil {
+			return preparedServer{}, err
+		}
+		federator, err := federation.NewFederator(federationConfig, s.Heartbeats, s.DynamicClusterClient)
+		if err != nil {
+			return preparedServer{}, err
+		}
+		if err := s.apiextensions.GenericAPIServer.AddPostStartHook("cache-server-federation", func(hookContext genericapiserver.PostStartHookContext) error {
+			logger := logger.WithValues("postStartHook", "cache-server-federation")
+			ctx := klog.NewContext(goContext(hookContext), logger)
+			crdInformer := s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions()
+			go func() {
+				if !cache.WaitForCacheSync(hookContext.StopCh, crdInformer.Informer().HasSynced) {
+					return
+				}
+				federator.Start(ctx, s.Options.FederationInterval)
+			}()
+			return nil
+		}); err != nil {
+			return preparedServer{}, err
+		}
+	}
 	return preparedServer{s, s.apiextensions.GenericAPIServer.Handler}, nil
 }
 
@@ -90,6 +174,28 @@ func (s preparedServer) RunPostStartHooks(stopCh <-chan struct{}) {
 	s.apiextensions.GenericAPIServer.RunPostStartHooks(stopCh)
 }
 
+// newEtcdClient returns a client of the etcd storing the objects of the cache server.
+func newEtcdClient(ctx context.Context, config storagebackend.TransportConfig) (*clientv3.Client, error) {
+	var tlsConfig *tls.Config
+	if config.CertFile != "" || config.KeyFile != "" || config.TrustedCAFile != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      config.CertFile,
+			KeyFile:       config.KeyFile,
+			TrustedCAFile: config.TrustedCAFile,
+		}
+		var err error
+		if tlsConfig, err = tlsInfo.ClientConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return clientv3.New(clientv3.Config{
+		Endpoints:   config.ServerList,
+		TLS:         tlsConfig,
+		DialTimeout: 20 * time.Second,
+		Context:     ctx,
+	})
+}
+
 // goContext turns the PostStartHookContext into a context.Context for use in routines that may or may not
 // run inside of a post-start-hook. The k8s APIServer wrote the post-start-hook context code before contexts
 // were part of the Go stdlib.
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+)
+
+// crdRESTOptionsGetter decorates the storage of the cached resources with
+// acrossScopesStorage.
+type crdRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+}
+
+func (g crdRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	opts, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return opts, err
+	}
+	decorator := opts.Decorator
+	opts.Decorator = func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(ctx context.Context, obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc storage.AttrFunc,
+		trigger storage.IndexerFuncs,
+		indexers *cache.Indexers,
+	) (storage.Interface, factory.DestroyFunc, error) {
+		s, destroy, err := decorator(config, resourcePrefix, keyFunc, newFunc, newListFunc, getAttrsFunc, trigger, indexers)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &acrossScopesStorage{Interface: s, resourcePrefix: resourcePrefix}, destroy, nil
+	}
+	return opts, nil
+}
+
+// acrossScopesStorage serves lists and watches across shards or clusters that select a
+// single name, e.g. with the metadata.name field selector, or a namespace. The generic
+// registry reads the key of the single object or namespace for these, but the key doesn't
+// include the wildcard shard or cluster, i.e. nothing would be found. Instead, the whole
+// resource is read and the name and namespace are applied as field selectors to every
+// object. Limit and continue are passed through to the recursive list.
+type acrossScopesStorage struct {
+	storage.Interface
+	resourcePrefix string
+}
+
+func (s *acrossScopesStorage) GetList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	key, opts = s.acrossScopes(ctx, key, opts)
+	return s.Interface.GetList(ctx, key, opts, listObj)
+}
+
+func (s *acrossScopesStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	key, opts = s.acrossScopes(ctx, key, opts)
+	return s.Interface.Watch(ctx, key, opts)
+}
+
+func (s *acrossScopesStorage) acrossScopes(ctx context.Context, key string, opts storage.ListOptions) (string, storage.ListOptions) {
+	if cluster := request.ClusterFrom(ctx); !request.ShardFrom(ctx).Wildcard() && (cluster == nil || !cluster.Wildcard) {
+		return key, opts
+	}
+	// the namespace is below the shard and cluster in the key, i.e. the key of a namespace
+	// across shards or clusters doesn't exist either.
+	ns, _ := request.NamespaceFrom(ctx)
+	if opts.Recursive && ns == "" {
+		return key, opts
+	}
+
+	opts.Recursive = true
+	if opts.Predicate.Field == nil {
+		opts.Predicate.Field = fields.Everything()
+	}
+	if ns != "" {
+		inNamespace := fields.OneTermEqualSelector("metadata.namespace", ns)
+		if opts.Predicate.Field.Empty() {
+			opts.Predicate.Field = inNamespace
+		} else {
+			opts.Predicate.Field = fields.AndSelectors(opts.Predicate.Field, inNamespace)
+		}
+	}
+	return genericregistry.NoNamespaceKeyRootFunc(ctx, s.resourcePrefix), opts
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+func TestAcrossScopesStorage(t *testing.T) {
+	s := &acrossScopesStorage{resourcePrefix: "/apis.kcp.io/apiexports"}
+	byName := storage.ListOptions{Predicate: storage.SelectionPredicate{
+		Label: labels.Everything(),
+		Field: fields.OneTermEqualSelector("metadata.name", "foo"),
+	}}
+
+	tests := map[string]struct {
+		shard         request.Shard
+		cluster       request.Cluster
+		namespace     string
+		key           string
+		opts          storage.ListOptions
+		wantKey       string
+		wantRecursive bool
+		wantField     string
+	}{
+		"single object": {
+			shard:     "amber",
+			cluster:   request.Cluster{Name: logicalcluster.Name("root")},
+			key:       "/apis.kcp.io/apiexports/amber/root/foo",
+			opts:      byName,
+			wantKey:   "/apis.kcp.io/apiexports/amber/root/foo",
+			wantField: "metadata.name=foo",
+		},
+		"across shards": {
+			shard:         "*",
+			cluster:       request.Cluster{Wildcard: true},
+			key:           "/apis.kcp.io/apiexports/foo",
+			opts:          byName,
+			wantKey:       "/apis.kcp.io/apiexports",
+			wantRecursive: true,
+			wantField:     "metadata.name=foo",
+		},
+		"across clusters": {
+			shard:         "amber",
+			cluster:       request.Cluster{Wildcard: true},
+			key:           "/apis.kcp.io/apiexports/amber/foo",
+			opts:          byName,
+			wantKey:       "/apis.kcp.io/apiexports/amber",
+			wantRecursive: true,
+			wantField:     "metadata.name=foo",
+		},
+		"across clusters in a namespace": {
+			shard:         "amber",
+			cluster:       request.Cluster{Wildcard: true},
+			namespace:     "default",
+			key:           "/apis.kcp.io/apiexports/amber/default/foo",
+			opts:          byName,
+			wantKey:       "/apis.kcp.io/apiexports/amber",
+			wantRecursive: true,
+			wantField:     "metadata.name=foo,metadata.namespace=default",
+		},
+		"recursive list in a namespace across shards": {
+			shard:         "*",
+			cluster:       request.Cluster{Wildcard: true},
+			namespace:     "default",
+			key:           "/apis.kcp.io/apiexports/default",
+			opts:          storage.ListOptions{Recursive: true, Predicate: storage.SelectionPredicate{Label: labels.Everything(), Limit: 100, Continue: "abc"}},
+			wantKey:       "/apis.kcp.io/apiexports",
+			wantRecursive: true,
+			wantField:     "metadata.namespace=default",
+		},
+		"recursive list in a namespace of a cluster": {
+			shard:         "amber",
+			cluster:       request.Cluster{Name: logicalcluster.Name("root")},
+			namespace:     "default",
+			key:           "/apis.kcp.io/apiexports/amber/root/default",
+			opts:          storage.ListOptions{Recursive: true, Predicate: storage.Everything},
+			wantKey:       "/apis.kcp.io/apiexports/amber/root/default",
+			wantRecursive: true,
+			wantField:     "",
+		},
+		"recursive list": {
+			shard:         "*",
+			cluster:       request.Cluster{Wildcard: true},
+			key:           "/apis.kcp.io/apiexports",
+			opts:          storage.ListOptions{Recursive: true, Predicate: storage.Everything},
+			wantKey:       "/apis.kcp.io/apiexports",
+			wantRecursive: true,
+			wantField:     "",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := request.WithShard(context.Background(), tt.shard)
+			ctx = request.WithCluster(ctx, tt.cluster)
+			if tt.namespace != "" {
+				ctx = request.WithNamespace(ctx, tt.namespace)
+			}
+			key, opts := s.acrossScopes(ctx, tt.key, tt.opts)
+			require.Equal(t, tt.wantKey, key)
+			require.Equal(t, tt.wantRecursive, opts.Recursive)
+			require.Equal(t, tt.wantField, opts.Predicate.Field.String())
+			require.Equal(t, tt.opts.Predicate.Limit, opts.Predicate.Limit, "limit is passed through")
+			require.Equal(t, tt.opts.Predicate.Continue, opts.Predicate.Continue, "continue is passed through")
+		})
+	}
+}
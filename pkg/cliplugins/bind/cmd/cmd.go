@@ -30,6 +30,9 @@ var (
 	bindExampleUses = `
 	# Create an APIBinding named "my-binding" that binds to the APIExport "my-export" in the "root:my-service" workspace.
 	%[1]s bind apiexport root:my-service:my-export --name my-binding
+
+	# Bind to the APIExport, accepting its permission claims for configmaps and widgets.example.io without prompting.
+	%[1]s bind apiexport root:my-service:my-export --accept-claim configmaps --accept-claim widgets.example.io
 	`
 
 	bindComputeExampleUses = `
@@ -41,6 +44,9 @@ var (
 
     # Create a placement to deploy standard kubernetes workloads to synctargets in the "root:mylocations" location workspace, and select only locations in the us-east region.
     %[1]s bind compute root:mylocations --location-selectors=region=us-east1
+
+    # Print which locations and synctargets in the "root:mylocations" location workspace would be selected and why, without creating anything.
+    %[1]s bind compute root:mylocations --location-selectors=region=us-east1 --dry-run --explain
 	`
 )
 
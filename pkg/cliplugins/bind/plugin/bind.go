@@ -17,16 +17,24 @@ limitations under the License.
 package plugin
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
@@ -34,6 +42,8 @@ import (
 	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
 	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 )
 
@@ -47,6 +57,10 @@ type BindOptions struct {
 	APIBindingName string
 	// BindWaitTimeout is how long to wait for the APIBinding to be created and successful.
 	BindWaitTimeout time.Duration
+	// AcceptedClaims are the permission claims of the APIExport to accept, in <resource>.<group>
+	// notation, or <resource> for the core group. Claims that are neither accepted here nor
+	// interactively are rejected.
+	AcceptedClaims []string
 }
 
 // NewBindOptions returns new BindOptions.
@@ -62,6 +76,7 @@ func (b *BindOptions) BindFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&b.APIBindingName, "name", b.APIBindingName, "Name of the APIBinding to create.")
 	cmd.Flags().DurationVar(&b.BindWaitTimeout, "timeout", time.Second*30, "Duration to wait for APIBinding to be created successfully.")
+	cmd.Flags().StringSliceVar(&b.AcceptedClaims, "accept-claim", b.AcceptedClaims, "Permission claim of the APIExport to accept, as <resource>.<group>, or <resource> for the core group. Can be repeated. Other claims are prompted for on a terminal, and rejected otherwise.")
 }
 
 // Complete ensures all fields are initialized.
@@ -89,6 +104,12 @@ func (b *BindOptions) Validate() error {
 		return fmt.Errorf("fully qualified reference to workspace where APIExport exists is required. The format is `<logical-cluster-name>:<apiexport>` or `<full>:<path>:<to>:<apiexport>`")
 	}
 
+	for _, claim := range b.AcceptedClaims {
+		if gr := schema.ParseGroupResource(claim); gr.Resource == "" || strings.Contains(claim, ":") {
+			return fmt.Errorf("invalid --accept-claim %q, the format is <resource>.<group>, or <resource> for the core group", claim)
+		}
+	}
+
 	return b.Options.Validate()
 }
 
@@ -112,6 +133,39 @@ func (b *BindOptions) Run(ctx context.Context) error {
 		return fmt.Errorf("current URL %q does not point to workspace", config.Host)
 	}
 
+	kcpclient, err := newKCPClusterClient(config)
+	if err != nil {
+		return err
+	}
+
+	var claims []apisv1alpha1.AcceptablePermissionClaim
+	export, err := kcpclient.Cluster(path).ApisV1alpha1().APIExports().Get(ctx, apiExportName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsForbidden(err) && len(b.AcceptedClaims) == 0:
+		// binding only requires the bind verb, hence the claims might not be discoverable.
+		if _, err := fmt.Fprintf(b.ErrOut, "Cannot discover the permission claims of APIExport %s, binding without accepting any: %v\n", b.APIExportRef, err); err != nil {
+			return err
+		}
+	case err != nil:
+		return fmt.Errorf("cannot discover the permission claims of APIExport %s: %w", b.APIExportRef, err)
+	default:
+		var prompt func(claim apisv1alpha1.PermissionClaim) (bool, error)
+		if isTerminal(b.In) {
+			reader := bufio.NewReader(b.In)
+			prompt = func(claim apisv1alpha1.PermissionClaim) (bool, error) {
+				return promptClaim(reader, b.Out, b.APIExportRef, claim)
+			}
+		}
+		if claims, err = acceptablePermissionClaims(export.Spec.PermissionClaims, b.AcceptedClaims, prompt); err != nil {
+			return err
+		}
+		for _, claim := range claims {
+			if _, err := fmt.Fprintf(b.Out, "%s permission claim for %s.\n", claim.State, describeClaim(claim.PermissionClaim)); err != nil {
+				return err
+			}
+		}
+	}
+
 	binding := &apisv1alpha1.APIBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: apiBindingName,
@@ -123,14 +177,10 @@ func (b *BindOptions) Run(ctx context.Context) error {
 					Name: apiExportName,
 				},
 			},
+			PermissionClaims: claims,
 		},
 	}
 
-	kcpclient, err := newKCPClusterClient(config)
-	if err != nil {
-		return err
-	}
-
 	createdBinding, err := kcpclient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
 	if err != nil {
 		return err
@@ -140,18 +190,18 @@ func (b *BindOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	// wait for phase to be bound
-	if createdBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBound {
+	// wait for the binding to be ready, i.e. bound with all accepted claims applied
+	if !isReady(createdBinding) {
 		if err := wait.PollImmediate(time.Millisecond*500, b.BindWaitTimeout, func() (done bool, err error) {
-			createdBinding, err := kcpclient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+			createdBinding, err = kcpclient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
 			if err != nil {
 				return false, err
 			}
-			if createdBinding.Status.Phase == apisv1alpha1.APIBindingPhaseBound {
-				return true, nil
-			}
-			return false, nil
+			return isReady(createdBinding), nil
 		}); err != nil {
+			if ready := conditions.Get(createdBinding, conditionsv1alpha1.ReadyCondition); ready != nil && ready.Message != "" {
+				return fmt.Errorf("could not bind %s: %w: %s", binding.Name, err, ready.Message)
+			}
 			return fmt.Errorf("could not bind %s: %w", binding.Name, err)
 		}
 	}
@@ -163,6 +213,106 @@ func (b *BindOptions) Run(ctx context.Context) error {
 	return nil
 }
 
+func isReady(binding *apisv1alpha1.APIBinding) bool {
+	return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound && conditions.IsTrue(binding, conditionsv1alpha1.ReadyCondition)
+}
+
+// acceptablePermissionClaims decides about every claim of an APIExport. Claims whose group
+// resource is in accepted are accepted. The others are passed to prompt, or rejected if
+// prompt is nil. It fails if an accepted group resource is not claimed at all.
+func acceptablePermissionClaims(claims []apisv1alpha1.PermissionClaim, accepted []string, prompt func(claim apisv1alpha1.PermissionClaim) (bool, error)) ([]apisv1alpha1.AcceptablePermissionClaim, error) {
+	acceptedSet := sets.NewString()
+	for _, claim := range accepted {
+		acceptedSet.Insert(schema.ParseGroupResource(claim).String())
+	}
+
+	claimed := sets.NewString()
+	ret := make([]apisv1alpha1.AcceptablePermissionClaim, 0, len(claims))
+	for _, claim := range claims {
+		gr := schema.GroupResource{Group: claim.Group, Resource: claim.Resource}.String()
+		claimed.Insert(gr)
+
+		state := apisv1alpha1.ClaimRejected
+		switch {
+		case acceptedSet.Has(gr):
+			state = apisv1alpha1.ClaimAccepted
+		case prompt != nil:
+			ok, err := prompt(claim)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				state = apisv1alpha1.ClaimAccepted
+			}
+		}
+		ret = append(ret, apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: state})
+	}
+
+	if unknown := acceptedSet.Difference(claimed); unknown.Len() > 0 {
+		return nil, fmt.Errorf("the APIExport does not claim %s", strings.Join(unknown.List(), ", "))
+	}
+
+	return ret, nil
+}
+
+// promptClaim asks on out whether to accept the claim, and reads the answer from in.
+func promptClaim(in *bufio.Reader, out io.Writer, apiExportRef string, claim apisv1alpha1.PermissionClaim) (bool, error) {
+	if _, err := fmt.Fprintf(out, "APIExport %s claims access to %s. Accept? [y/N]: ", apiExportRef, describeClaim(claim)); err != nil {
+		return false, err
+	}
+	answer, err := in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// describeClaim returns a human readable description of the objects a claim grants access to.
+func describeClaim(claim apisv1alpha1.PermissionClaim) string {
+	gr := schema.GroupResource{Group: claim.Group, Resource: claim.Resource}.String()
+
+	var what string
+	switch {
+	case claim.ReferencedBy != nil:
+		what = fmt.Sprintf("%s referenced by %s in field %s", gr, schema.GroupResource{Group: claim.ReferencedBy.Group, Resource: claim.ReferencedBy.Resource}, claim.ReferencedBy.NameField)
+	case claim.All || len(claim.ResourceSelector) == 0:
+		what = "all " + gr
+	default:
+		var selectors []string
+		for _, s := range claim.ResourceSelector {
+			var parts []string
+			if s.Namespace != "" {
+				parts = append(parts, "namespace "+s.Namespace)
+			}
+			if s.Name != "" {
+				parts = append(parts, "name "+s.Name)
+			}
+			if selector, err := metav1.LabelSelectorAsSelector(&s.LabelSelector); err == nil && !selector.Empty() {
+				parts = append(parts, "labels "+selector.String())
+			}
+			selectors = append(selectors, strings.Join(parts, " and "))
+		}
+		what = fmt.Sprintf("%s with %s", gr, strings.Join(selectors, ", or "))
+	}
+	if claim.StatusOnly {
+		what += ", changing their status only"
+	}
+	if claim.IdentityHash != "" {
+		what += fmt.Sprintf(" (identity %s)", claim.IdentityHash)
+	}
+	return what
+}
+
+func isTerminal(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
 func newKCPClusterClient(config *rest.Config) (kcpclientset.ClusterInterface, error) {
 	clusterConfig := rest.CopyConfig(config)
 	u, err := url.Parse(config.Host)
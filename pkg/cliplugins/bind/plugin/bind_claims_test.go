@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestAcceptablePermissionClaims(t *testing.T) {
+	configmaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	widgets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, All: true, IdentityHash: "hash"}
+
+	tests := map[string]struct {
+		accepted []string
+		answers  map[string]bool
+		want     []apisv1alpha1.AcceptablePermissionClaimState
+		wantErr  string
+	}{
+		"nothing accepted, no prompt": {
+			want: []apisv1alpha1.AcceptablePermissionClaimState{apisv1alpha1.ClaimRejected, apisv1alpha1.ClaimRejected},
+		},
+		"accepted by flag": {
+			accepted: []string{"configmaps", "widgets.example.io"},
+			want:     []apisv1alpha1.AcceptablePermissionClaimState{apisv1alpha1.ClaimAccepted, apisv1alpha1.ClaimAccepted},
+		},
+		"prompted for claims not accepted by flag": {
+			accepted: []string{"widgets.example.io"},
+			answers:  map[string]bool{"configmaps": true},
+			want:     []apisv1alpha1.AcceptablePermissionClaimState{apisv1alpha1.ClaimAccepted, apisv1alpha1.ClaimAccepted},
+		},
+		"declined at the prompt": {
+			answers: map[string]bool{"configmaps": false, "widgets": true},
+			want:    []apisv1alpha1.AcceptablePermissionClaimState{apisv1alpha1.ClaimRejected, apisv1alpha1.ClaimAccepted},
+		},
+		"accepted claim is not claimed": {
+			accepted: []string{"secrets"},
+			wantErr:  "the APIExport does not claim secrets",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var prompt func(claim apisv1alpha1.PermissionClaim) (bool, error)
+			if tt.answers != nil {
+				prompt = func(claim apisv1alpha1.PermissionClaim) (bool, error) {
+					answer, found := tt.answers[claim.Resource]
+					require.True(t, found, "unexpected prompt for %s", claim.Resource)
+					return answer, nil
+				}
+			}
+
+			got, err := acceptablePermissionClaims([]apisv1alpha1.PermissionClaim{configmaps, widgets}, tt.accepted, prompt)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var states []apisv1alpha1.AcceptablePermissionClaimState
+			for _, claim := range got {
+				states = append(states, claim.State)
+			}
+			require.Equal(t, tt.want, states)
+			require.Equal(t, widgets, got[1].PermissionClaim)
+		})
+	}
+}
+
+func TestPromptClaim(t *testing.T) {
+	claim := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+	for answer, want := range map[string]bool{"y\n": true, "Yes\n": true, "n\n": false, "\n": false, "": false} {
+		var out bytes.Buffer
+		got, err := promptClaim(bufio.NewReader(strings.NewReader(answer)), &out, "root:provider:export", claim)
+		require.NoError(t, err)
+		require.Equal(t, want, got, "answer %q", answer)
+		require.Equal(t, "APIExport root:provider:export claims access to all secrets. Accept? [y/N]: ", out.String())
+	}
+}
+
+func TestDescribeClaim(t *testing.T) {
+	tests := map[string]struct {
+		claim apisv1alpha1.PermissionClaim
+		want  string
+	}{
+		"all": {
+			claim: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, All: true, IdentityHash: "hash"},
+			want:  "all widgets.example.io (identity hash)",
+		},
+		"selectors": {
+			claim: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, ResourceSelector: []apisv1alpha1.ResourceSelector{
+				{Namespace: "team-a-*", Name: "config"},
+				{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "widgets"}}},
+			}},
+			want: "configmaps with namespace team-a-* and name config, or labels app=widgets",
+		},
+		"referenced": {
+			claim: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, ReferencedBy: &apisv1alpha1.PermissionClaimReference{
+				GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+				NameField:     "spec.secretRef.name",
+			}},
+			want: "secrets referenced by widgets.example.io in field spec.secretRef.name",
+		},
+		"status only": {
+			claim: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "gadgets"}, All: true, StatusOnly: true},
+			want:  "all gadgets.example.io, changing their status only",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, describeClaim(tt.claim))
+		})
+	}
+}
@@ -35,6 +35,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
@@ -42,6 +44,7 @@ import (
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 	kcpclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned"
 )
 
@@ -67,6 +70,12 @@ type BindComputeOptions struct {
 
 	// BindWaitTimeout is how long to wait for the placement to be created and successful.
 	BindWaitTimeout time.Duration
+
+	// DryRun only prints where the placement would schedule workloads, without creating anything.
+	DryRun bool
+
+	// Explain adds the reasons to the output of DryRun, including the Locations and SyncTargets not selected.
+	Explain bool
 }
 
 func NewBindComputeOptions(streams genericclioptions.IOStreams) *BindComputeOptions {
@@ -93,6 +102,8 @@ func (o *BindComputeOptions) BindFlags(cmd *cobra.Command) {
 		"A list of label selectors to select locations in the location workspace to sync workload.")
 	cmd.Flags().StringVar(&o.PlacementName, "name", o.PlacementName, "Name of the placement to be created.")
 	cmd.Flags().DurationVar(&o.BindWaitTimeout, "timeout", time.Second*30, "Duration to wait for Placement to be created and bound successfully.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Only print the Locations and SyncTargets the placement would schedule workloads to, without creating anything.")
+	cmd.Flags().BoolVar(&o.Explain, "explain", o.Explain, "With --dry-run, also print why Locations and SyncTargets are selected or not.")
 }
 
 // Complete ensures all dynamically populated fields are initialized.
@@ -135,10 +146,14 @@ func (o *BindComputeOptions) Complete(args []string) error {
 
 // Validate validates the BindOptions are complete and usable.
 func (o *BindComputeOptions) Validate() error {
+	if o.Explain && !o.DryRun {
+		return fmt.Errorf("--explain requires --dry-run")
+	}
 	return nil
 }
 
-// Run creates a placement in the workspace, linking to the location workspace.
+// Run creates a placement in the workspace, linking to the location workspace. With DryRun, it
+// only prints where the placement would schedule workloads to.
 func (o *BindComputeOptions) Run(ctx context.Context) error {
 	config, err := o.ClientConfig.ClientConfig()
 	if err != nil {
@@ -149,6 +164,10 @@ func (o *BindComputeOptions) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to create kcp client: %w", err)
 	}
 
+	if o.DryRun {
+		return o.explain(ctx, config, userWorkspaceKcpClient)
+	}
+
 	// apply APIBindings
 	bindings, err := o.applyAPIBinding(ctx, userWorkspaceKcpClient, sets.NewString(o.APIExports...))
 	if err != nil {
@@ -351,8 +370,51 @@ func (o *BindComputeOptions) applyAPIBinding(ctx context.Context, client kcpclie
 	return bindings, utilerrors.NewAggregate(errs)
 }
 
-func (o *BindComputeOptions) applyPlacement(ctx context.Context, client kcpclient.Interface) error {
-	placement := &schedulingv1alpha1.Placement{
+// explain prints where the placement would schedule workloads to.
+func (o *BindComputeOptions) explain(ctx context.Context, config *rest.Config, userWorkspaceKcpClient kcpclient.Interface) error {
+	kcpClusterClient, err := newKCPClusterClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kcp client: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kube client: %w", err)
+	}
+
+	locations, err := kcpClusterClient.Cluster(o.LocationWorkspace).SchedulingV1alpha1().Locations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list Locations in %s: %w", o.LocationWorkspace, err)
+	}
+	syncTargets, err := kcpClusterClient.Cluster(o.LocationWorkspace).WorkloadV1alpha1().SyncTargets().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list SyncTargets in %s: %w", o.LocationWorkspace, err)
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	// like the scheduler, only consider the APIs of compute APIBindings. Those not created yet are not considered.
+	apiBindings, err := userWorkspaceKcpClient.ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list APIBindings: %w", err)
+	}
+	var computeBindings []apisv1alpha1.APIBinding
+	for _, binding := range apiBindings.Items {
+		if _, ok := binding.Annotations[workloadv1alpha1.ComputeAPIExportAnnotationKey]; ok {
+			computeBindings = append(computeBindings, binding)
+		}
+	}
+
+	explanation, err := explainPlacement(o.placement(), namespaces.Items, locations.Items, syncTargets.Items, computeBindings, time.Now())
+	if err != nil {
+		return err
+	}
+	return explanation.print(o.Out, o.PlacementName, o.LocationWorkspace.String(), o.Explain)
+}
+
+func (o *BindComputeOptions) placement() *schedulingv1alpha1.Placement {
+	return &schedulingv1alpha1.Placement{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: o.PlacementName,
 		},
@@ -367,8 +429,10 @@ func (o *BindComputeOptions) applyPlacement(ctx context.Context, client kcpclien
 			},
 		},
 	}
+}
 
-	_, err := client.SchedulingV1alpha1().Placements().Create(ctx, placement, metav1.CreateOptions{})
+func (o *BindComputeOptions) applyPlacement(ctx context.Context, client kcpclient.Interface) error {
+	_, err := client.SchedulingV1alpha1().Placements().Create(ctx, o.placement(), metav1.CreateOptions{})
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
 			_, err = fmt.Fprintf(o.Out, "placement %s already exists.\n", o.PlacementName)
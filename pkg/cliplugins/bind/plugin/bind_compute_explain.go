@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+)
+
+// placementExplanation describes where the scheduler would place the workloads of a Placement, and why.
+type placementExplanation struct {
+	// Namespaces are the namespaces selected by the namespace selector.
+	Namespaces []string
+	// Locations are all Locations in the location workspace.
+	Locations []locationExplanation
+}
+
+type locationExplanation struct {
+	Name string
+	// MatchedSelectors are the location selectors of the placement matching the Location.
+	MatchedSelectors []string
+	// Reason is why the Location is not a candidate. Empty for candidates.
+	Reason      string
+	SyncTargets []syncTargetExplanation
+}
+
+type syncTargetExplanation struct {
+	Name string
+	// Reason is why the SyncTarget cannot be scheduled to. Empty for valid SyncTargets.
+	Reason string
+}
+
+// candidates returns the candidate locations, i.e. those matching the placement with at least one valid SyncTarget.
+func (e *placementExplanation) candidates() []locationExplanation {
+	var ret []locationExplanation
+	for _, loc := range e.Locations {
+		if loc.Reason == "" {
+			ret = append(ret, loc)
+		}
+	}
+	return ret
+}
+
+// explainPlacement mirrors the decisions of the placement and workload schedulers for the given placement:
+// a Location is a candidate if its resource is the placement's location resource, one of the location selectors
+// matches and at least one of its SyncTargets is valid. A SyncTarget is valid if it matches the instance selector
+// of the Location, supports all APIs of the given compute APIBindings, is ready, schedulable and not evicting.
+func explainPlacement(placement *schedulingv1alpha1.Placement, namespaces []corev1.Namespace, locations []schedulingv1alpha1.Location, syncTargets []workloadv1alpha1.SyncTarget, apiBindings []apisv1alpha1.APIBinding, now time.Time) (*placementExplanation, error) {
+	ret := &placementExplanation{}
+
+	nsSelector, err := metav1.LabelSelectorAsSelector(placement.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("namespace selector format not correct: %w", err)
+	}
+	for _, ns := range namespaces {
+		if nsSelector.Matches(labels.Set(ns.Labels)) {
+			ret.Namespaces = append(ret.Namespaces, ns.Name)
+		}
+	}
+
+	for i := range locations {
+		loc := &locations[i]
+		explanation := locationExplanation{Name: loc.Name}
+		ret.Locations = append(ret.Locations, explanation)
+		current := &ret.Locations[len(ret.Locations)-1]
+
+		if loc.Spec.Resource != placement.Spec.LocationResource {
+			current.Reason = fmt.Sprintf("location resource %s.%s.%s does not match", loc.Spec.Resource.Resource, loc.Spec.Resource.Version, loc.Spec.Resource.Group)
+			continue
+		}
+
+		for j := range placement.Spec.LocationSelectors {
+			selector, err := metav1.LabelSelectorAsSelector(&placement.Spec.LocationSelectors[j])
+			if err != nil {
+				// the scheduler skips invalid selectors too
+				continue
+			}
+			if selector.Matches(labels.Set(loc.Labels)) {
+				current.MatchedSelectors = append(current.MatchedSelectors, selectorString(selector))
+			}
+		}
+		if len(current.MatchedSelectors) == 0 {
+			current.Reason = "no location selector matches"
+			continue
+		}
+
+		instanceSelector, err := metav1.LabelSelectorAsSelector(loc.Spec.InstanceSelector)
+		if err != nil {
+			current.Reason = fmt.Sprintf("invalid instance selector: %v", err)
+			continue
+		}
+		valid := 0
+		for j := range syncTargets {
+			syncTarget := &syncTargets[j]
+			if !instanceSelector.Matches(labels.Set(syncTarget.Labels)) {
+				continue
+			}
+			reason := syncTargetNotValidReason(syncTarget, apiBindings, now)
+			if reason == "" {
+				valid++
+			}
+			current.SyncTargets = append(current.SyncTargets, syncTargetExplanation{Name: syncTarget.Name, Reason: reason})
+		}
+		switch {
+		case len(current.SyncTargets) == 0:
+			current.Reason = fmt.Sprintf("no SyncTarget matches the instance selector %q", selectorString(instanceSelector))
+		case valid == 0:
+			current.Reason = "no SyncTarget is valid"
+		}
+	}
+
+	return ret, nil
+}
+
+func syncTargetNotValidReason(syncTarget *workloadv1alpha1.SyncTarget, apiBindings []apisv1alpha1.APIBinding, now time.Time) string {
+	supported := map[apisv1alpha1.GroupResource]workloadv1alpha1.ResourceToSync{}
+	for _, resource := range syncTarget.Status.SyncedResources {
+		if resource.State == workloadv1alpha1.ResourceSchemaAcceptedState {
+			supported[resource.GroupResource] = resource
+		}
+	}
+	for _, binding := range apiBindings {
+		for _, bound := range binding.Status.BoundResources {
+			resource, ok := supported[apisv1alpha1.GroupResource{Group: bound.Group, Resource: bound.Resource}]
+			if !ok || resource.IdentityHash != bound.Schema.IdentityHash {
+				return fmt.Sprintf("does not support %s of APIBinding %s", schema.GroupResource{Group: bound.Group, Resource: bound.Resource}, binding.Name)
+			}
+		}
+	}
+
+	switch {
+	case !conditions.IsTrue(syncTarget, conditionsv1alpha1.ReadyCondition):
+		return "not ready"
+	case syncTarget.Spec.Unschedulable:
+		return "unschedulable"
+	case syncTarget.Spec.EvictAfter != nil && !now.Before(syncTarget.Spec.EvictAfter.Time):
+		return "evicting"
+	}
+
+	return ""
+}
+
+func selectorString(selector labels.Selector) string {
+	if selector.Empty() {
+		return "<everything>"
+	}
+	return selector.String()
+}
+
+// print writes the explanation. Without explain, only the candidates are listed.
+func (e *placementExplanation) print(w io.Writer, placementName string, locationWorkspace string, explain bool) error {
+	var b strings.Builder
+
+	candidates := e.candidates()
+	if len(candidates) == 0 {
+		fmt.Fprintf(&b, "Placement %q would not be scheduled: no Location in workspace %s is a candidate.\n", placementName, locationWorkspace)
+	} else {
+		fmt.Fprintf(&b, "Placement %q would be scheduled to one of the following Locations in workspace %s, each with probability 1/%d:\n", placementName, locationWorkspace, len(candidates))
+	}
+
+	locations := candidates
+	if explain {
+		locations = e.Locations
+	}
+	for _, loc := range locations {
+		switch {
+		case loc.Reason != "":
+			fmt.Fprintf(&b, "  Location %q: not a candidate, %s\n", loc.Name, loc.Reason)
+		case explain:
+			fmt.Fprintf(&b, "  Location %q: candidate, matched by location selectors %s\n", loc.Name, strings.Join(quoted(loc.MatchedSelectors), ", "))
+		default:
+			fmt.Fprintf(&b, "  Location %q\n", loc.Name)
+		}
+
+		valid := 0
+		for _, syncTarget := range loc.SyncTargets {
+			if syncTarget.Reason == "" {
+				valid++
+			}
+		}
+		for _, syncTarget := range loc.SyncTargets {
+			switch {
+			case syncTarget.Reason == "":
+				fmt.Fprintf(&b, "    SyncTarget %q: valid, chosen with probability 1/%d\n", syncTarget.Name, valid)
+			case explain:
+				fmt.Fprintf(&b, "    SyncTarget %q: not valid, %s\n", syncTarget.Name, syncTarget.Reason)
+			}
+		}
+	}
+
+	if len(e.Namespaces) == 0 {
+		fmt.Fprintf(&b, "No namespace is selected by the namespace selector.\n")
+	} else {
+		fmt.Fprintf(&b, "Namespaces selected by the namespace selector: %s\n", strings.Join(e.Namespaces, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func quoted(ss []string) []string {
+	ret := make([]string, 0, len(ss))
+	for _, s := range ss {
+		ret = append(ret, fmt.Sprintf("%q", s))
+	}
+	return ret
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+)
+
+func TestExplainPlacement(t *testing.T) {
+	now := time.Now()
+	syncTargetsGVR := schedulingv1alpha1.GroupVersionResource{Group: "workload.kcp.io", Version: "v1alpha1", Resource: "synctargets"}
+
+	placement := &schedulingv1alpha1.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: "placement"},
+		Spec: schedulingv1alpha1.PlacementSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"workload": "true"}},
+			LocationSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"region": "us-east1"}},
+				{MatchLabels: map[string]string{"tier": "gold"}},
+			},
+			LocationResource: syncTargetsGVR,
+		},
+	}
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"workload": "true"}}},
+	}
+	location := func(name string, labels map[string]string, instanceLabels map[string]string) schedulingv1alpha1.Location {
+		return schedulingv1alpha1.Location{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec: schedulingv1alpha1.LocationSpec{
+				Resource:         syncTargetsGVR,
+				InstanceSelector: &metav1.LabelSelector{MatchLabels: instanceLabels},
+			},
+		}
+	}
+	otherResource := location("other", map[string]string{"region": "us-east1"}, nil)
+	otherResource.Spec.Resource.Resource = "others"
+	locations := []schedulingv1alpha1.Location{
+		location("east", map[string]string{"region": "us-east1", "tier": "gold"}, map[string]string{"region": "us-east1"}),
+		location("west", map[string]string{"region": "us-west1"}, map[string]string{"region": "us-west1"}),
+		location("empty", map[string]string{"tier": "gold"}, map[string]string{"region": "eu"}),
+		otherResource,
+	}
+	syncTarget := func(name, region string, ready bool) workloadv1alpha1.SyncTarget {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return workloadv1alpha1.SyncTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"region": region}},
+			Status: workloadv1alpha1.SyncTargetStatus{
+				Conditions: conditionsv1alpha1.Conditions{{Type: conditionsv1alpha1.ReadyCondition, Status: status}},
+				SyncedResources: []workloadv1alpha1.ResourceToSync{
+					{GroupResource: apisv1alpha1.GroupResource{Group: "apps", Resource: "deployments"}, IdentityHash: "hash", State: workloadv1alpha1.ResourceSchemaAcceptedState},
+				},
+			},
+		}
+	}
+	evicting := syncTarget("east-evicting", "us-east1", true)
+	evicting.Spec.EvictAfter = &metav1.Time{Time: now.Add(-time.Minute)}
+	unsupported := syncTarget("east-unsupported", "us-east1", true)
+	unsupported.Status.SyncedResources = nil
+	syncTargets := []workloadv1alpha1.SyncTarget{
+		syncTarget("east-1", "us-east1", true),
+		syncTarget("east-2", "us-east1", false),
+		evicting,
+		unsupported,
+		syncTarget("west-1", "us-west1", true),
+	}
+	apiBindings := []apisv1alpha1.APIBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "apps", Resource: "deployments", Schema: apisv1alpha1.BoundAPIResourceSchema{IdentityHash: "hash"}},
+				},
+			},
+		},
+	}
+
+	explanation, err := explainPlacement(placement, namespaces, locations, syncTargets, apiBindings, now)
+	require.NoError(t, err)
+	require.Equal(t, &placementExplanation{
+		Namespaces: []string{"apps"},
+		Locations: []locationExplanation{
+			{
+				Name:             "east",
+				MatchedSelectors: []string{"region=us-east1", "tier=gold"},
+				SyncTargets: []syncTargetExplanation{
+					{Name: "east-1"},
+					{Name: "east-2", Reason: "not ready"},
+					{Name: "east-evicting", Reason: "evicting"},
+					{Name: "east-unsupported", Reason: "does not support deployments.apps of APIBinding kubernetes"},
+				},
+			},
+			{Name: "west", Reason: "no location selector matches"},
+			{Name: "empty", MatchedSelectors: []string{"tier=gold"}, Reason: `no SyncTarget matches the instance selector "region=eu"`},
+			{Name: "other", Reason: "location resource others.v1alpha1.workload.kcp.io does not match"},
+		},
+	}, explanation)
+
+	var out bytes.Buffer
+	require.NoError(t, explanation.print(&out, "placement", "root:locations", false))
+	require.Equal(t, `Placement "placement" would be scheduled to one of the following Locations in workspace root:locations, each with probability 1/1:
+  Location "east"
+    SyncTarget "east-1": valid, chosen with probability 1/1
+Namespaces selected by the namespace selector: apps
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, explanation.print(&out, "placement", "root:locations", true))
+	require.Contains(t, out.String(), `  Location "east": candidate, matched by location selectors "region=us-east1", "tier=gold"`)
+	require.Contains(t, out.String(), `    SyncTarget "east-2": not valid, not ready`)
+	require.Contains(t, out.String(), `  Location "west": not a candidate, no location selector matches`)
+}
@@ -58,6 +58,21 @@ func TestBindOptionsValidate(t *testing.T) {
 			bindOptions: plugin.BindOptions{APIExportRef: "test-root:TestWorkspace:test-apiexport"},
 			wantValid:   false,
 		},
+		{
+			description: "Accepted claims of core and other groups",
+			bindOptions: plugin.BindOptions{APIExportRef: "root:test-workspace:test-apiexport", AcceptedClaims: []string{"configmaps", "widgets.example.io"}},
+			wantValid:   true,
+		},
+		{
+			description: "Accepted claim with a path",
+			bindOptions: plugin.BindOptions{APIExportRef: "root:test-workspace:test-apiexport", AcceptedClaims: []string{"root:configmaps"}},
+			wantValid:   false,
+		},
+		{
+			description: "Empty accepted claim",
+			bindOptions: plugin.BindOptions{APIExportRef: "root:test-workspace:test-apiexport", AcceptedClaims: []string{""}},
+			wantValid:   false,
+		},
 	}
 
 	for _, c := range testCases {
@@ -25,9 +25,9 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/kcp-dev/kcp/pkg/cliplugins/claims/plugin"
+	apiv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 )
 
-// TODO: Add examples for edit and update claims.
 var (
 	claimsExample = `
 	# Lists the permission claims and their respective status related to a specific APIBinding.
@@ -35,6 +35,15 @@ var (
 
 	# List permission claims and their respective status for all APIBindings in current workspace.
 	%[1]s claims get apibinding
+
+	# List the claims of all APIBindings in the current workspace that are neither accepted nor rejected.
+	%[1]s claims list --pending
+
+	# Accept the claims of the cert-manager APIBinding for secrets and configmaps.
+	%[1]s claims accept cert-manager secrets configmaps
+
+	# Reject the claim for secrets of all APIBindings in the current workspace, giving a reason to the providers.
+	%[1]s claims reject --all-bindings secrets --reason TooBroad
 	`
 )
 
@@ -84,5 +93,51 @@ func New(streams genericclioptions.IOStreams) *cobra.Command {
 	apibindingGetOpts.BindFlags(apibindingGetCmd)
 	getcmd.AddCommand(apibindingGetCmd)
 	claimsCmd.AddCommand(getcmd)
+
+	listOpts := plugin.NewListClaimsOptions(streams)
+	listCmd := &cobra.Command{
+		Use:          "list [<apibinding_name>]",
+		Short:        "List the claims requested by the APIExports of APIBindings, and whether they are accepted",
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listOpts.Complete(args); err != nil {
+				return err
+			}
+			if err := listOpts.Validate(); err != nil {
+				return err
+			}
+			return listOpts.Run(cmd.Context())
+		},
+	}
+	listOpts.BindFlags(listCmd)
+	claimsCmd.AddCommand(listCmd)
+
+	for _, decision := range []struct {
+		verb, short string
+		state       apiv1alpha1.AcceptablePermissionClaimState
+	}{
+		{"accept", "Accept claims requested by the APIExports of APIBindings", apiv1alpha1.ClaimAccepted},
+		{"reject", "Reject claims requested by the APIExports of APIBindings", apiv1alpha1.ClaimRejected},
+	} {
+		decideOpts := plugin.NewDecideClaimsOptions(streams, decision.state)
+		decideCmd := &cobra.Command{
+			Use:          decision.verb + " (<apibinding_name> | --all-bindings) <resource>.<group>...",
+			Short:        decision.short,
+			SilenceUsage: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := decideOpts.Complete(args); err != nil {
+					return err
+				}
+				if err := decideOpts.Validate(); err != nil {
+					return err
+				}
+				return decideOpts.Run(cmd.Context())
+			},
+		}
+		decideOpts.BindFlags(decideCmd)
+		claimsCmd.AddCommand(decideCmd)
+	}
+
 	return claimsCmd
 }
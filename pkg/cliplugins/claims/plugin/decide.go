@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	apiv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// reasonPattern is the format of rejection reasons, as validated by the APIBinding API.
+var reasonPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// DecideClaimsOptions contains the options for accepting or rejecting permission claims of
+// APIBindings.
+type DecideClaimsOptions struct {
+	*base.Options
+
+	// State is the decision to record, i.e. whether the claims are accepted or rejected.
+	State apiv1alpha1.AcceptablePermissionClaimState
+	// APIBindingName is the APIBinding to update, unless AllBindings is set.
+	APIBindingName string
+	// Claims are the claims to decide about, in <resource>.<group> notation, or <resource> for the
+	// core group.
+	Claims []string
+	// AllBindings updates every APIBinding of the current workspace whose APIExport requests
+	// the claims.
+	AllBindings bool
+	// Reason is the CamelCase reason for rejecting the claims.
+	Reason string
+
+	workspace        logicalcluster.Path
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewDecideClaimsOptions returns new DecideClaimsOptions recording the given decision.
+func NewDecideClaimsOptions(streams genericclioptions.IOStreams, state apiv1alpha1.AcceptablePermissionClaimState) *DecideClaimsOptions {
+	return &DecideClaimsOptions{
+		Options: base.NewOptions(streams),
+		State:   state,
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *DecideClaimsOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().BoolVar(&o.AllBindings, "all-bindings", o.AllBindings, "Update all APIBindings of the current workspace requesting the claims, instead of a single APIBinding")
+	if o.State == apiv1alpha1.ClaimRejected {
+		cmd.Flags().StringVar(&o.Reason, "reason", o.Reason, "CamelCase reason for rejecting the claims, e.g. TooBroad, published to the provider of the APIExport")
+	}
+}
+
+// Complete ensures all fields are initialized. The arguments are the APIBinding name, unless
+// --all-bindings is set, followed by the claims.
+func (o *DecideClaimsOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+	if !o.AllBindings && len(args) > 0 {
+		o.APIBindingName, args = args[0], args[1:]
+	}
+	o.Claims = args
+
+	var err error
+	o.workspace, o.kcpClusterClient, err = workspaceClient(o.Options)
+	return err
+}
+
+// Validate validates the DecideClaimsOptions are complete and usable.
+func (o *DecideClaimsOptions) Validate() error {
+	if !o.AllBindings && o.APIBindingName == "" {
+		return errors.New("an APIBinding name or --all-bindings is required")
+	}
+	if len(o.Claims) == 0 {
+		return errors.New("at least one claim in <resource>.<group> notation is required")
+	}
+	for _, claim := range o.Claims {
+		if gr := schema.ParseGroupResource(claim); gr.Resource == "" {
+			return fmt.Errorf("invalid claim %q, the format is <resource>.<group>, or <resource> for the core group", claim)
+		}
+	}
+	if o.Reason != "" && !reasonPattern.MatchString(o.Reason) {
+		return fmt.Errorf("invalid --reason %q, must be CamelCase", o.Reason)
+	}
+	return o.Options.Validate()
+}
+
+// Run records the decision about the claims in the APIBindings.
+func (o *DecideClaimsOptions) Run(ctx context.Context) error {
+	bindings, err := getAPIBindings(ctx, o.kcpClusterClient, o.workspace, o.APIBindingName)
+	if err != nil {
+		return err
+	}
+
+	claims := sets.NewString()
+	for _, claim := range o.Claims {
+		claims.Insert(schema.ParseGroupResource(claim).String())
+	}
+
+	client := o.kcpClusterClient.Cluster(o.workspace).ApisV1alpha1().APIBindings()
+	found := sets.NewString()
+	for _, binding := range bindings {
+		if o.AllBindings && len(decideClaims(binding.DeepCopy(), claims, o.State, o.Reason)) == 0 {
+			continue // the APIExport does not request any of the claims
+		}
+
+		var decided []string
+		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current, err := client.Get(ctx, binding.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			decided = decideClaims(current, claims, o.State, o.Reason)
+			if len(decided) == 0 {
+				return nil
+			}
+			_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+			return err
+		}); err != nil {
+			return fmt.Errorf("error updating apibinding %s: %w", binding.Name, err)
+		}
+
+		found.Insert(decided...)
+		for _, claim := range decided {
+			if _, err := fmt.Fprintf(o.Out, "apibinding %s: %s claim %s\n", binding.Name, strings.ToLower(string(o.State)), claim); err != nil {
+				return err
+			}
+		}
+	}
+
+	if missing := claims.Difference(found); missing.Len() > 0 {
+		if o.AllBindings {
+			return fmt.Errorf("no APIBinding in workspace %s requests %s", o.workspace, strings.Join(missing.List(), ", "))
+		}
+		return fmt.Errorf("apibinding %s does not request %s", o.APIBindingName, strings.Join(missing.List(), ", "))
+	}
+	return nil
+}
+
+// decideClaims records the decision for the claims of the binding's APIExport with the given
+// group resources in the binding's spec, replacing earlier decisions. It returns the group
+// resources of the decided claims.
+func decideClaims(binding *apiv1alpha1.APIBinding, claims sets.String, state apiv1alpha1.AcceptablePermissionClaimState, reason string) []string {
+	if state != apiv1alpha1.ClaimRejected {
+		reason = ""
+	}
+
+	decided := sets.NewString()
+	for _, requested := range binding.Status.ExportPermissionClaims {
+		gr := schema.GroupResource{Group: requested.Group, Resource: requested.Resource}.String()
+		if !claims.Has(gr) {
+			continue
+		}
+		decided.Insert(gr)
+
+		decision := apiv1alpha1.AcceptablePermissionClaim{PermissionClaim: requested, State: state, Reason: reason}
+		replaced := false
+		for i := range binding.Spec.PermissionClaims {
+			if binding.Spec.PermissionClaims[i].Equal(requested) {
+				binding.Spec.PermissionClaims[i] = decision
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			binding.Spec.PermissionClaims = append(binding.Spec.PermissionClaims, decision)
+		}
+	}
+	return decided.List()
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apiv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+var (
+	configmaps = apiv1alpha1.PermissionClaim{
+		GroupResource:    apiv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apiv1alpha1.ResourceSelector{{Namespace: "default", Name: "setup"}},
+	}
+	widgets = apiv1alpha1.PermissionClaim{GroupResource: apiv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, All: true, IdentityHash: "hash"}
+	secrets = apiv1alpha1.PermissionClaim{GroupResource: apiv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+)
+
+func newBinding(name string, requested []apiv1alpha1.PermissionClaim, decisions ...apiv1alpha1.AcceptablePermissionClaim) *apiv1alpha1.APIBinding {
+	return &apiv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root:consumer"}},
+		Spec:       apiv1alpha1.APIBindingSpec{PermissionClaims: decisions},
+		Status:     apiv1alpha1.APIBindingStatus{ExportPermissionClaims: requested},
+	}
+}
+
+func TestClaimRows(t *testing.T) {
+	binding := newBinding("b", []apiv1alpha1.PermissionClaim{configmaps, widgets},
+		apiv1alpha1.AcceptablePermissionClaim{PermissionClaim: widgets, State: apiv1alpha1.ClaimRejected, Reason: "TooBroad"},
+		apiv1alpha1.AcceptablePermissionClaim{PermissionClaim: secrets, State: apiv1alpha1.ClaimAccepted},
+	)
+	require.Equal(t, []claimRow{
+		{resource: "configmaps", identity: "<none>", selector: "namespace=default,name=setup", state: claimPending, reason: "<none>"},
+		{resource: "widgets.example.io", identity: "hash", selector: "all", state: "Rejected", reason: "TooBroad"},
+		{resource: "secrets", identity: "<none>", selector: "all", state: "Accepted (stale)", reason: "<none>"},
+	}, claimRows(binding))
+}
+
+func TestDecideClaims(t *testing.T) {
+	tests := map[string]struct {
+		opts    DecideClaimsOptions
+		wantErr string
+		want    map[string][]apiv1alpha1.AcceptablePermissionClaim
+	}{
+		"accept claims of one binding": {
+			opts: DecideClaimsOptions{State: apiv1alpha1.ClaimAccepted, APIBindingName: "a", Claims: []string{"configmaps", "widgets.example.io"}},
+			want: map[string][]apiv1alpha1.AcceptablePermissionClaim{
+				"a": {
+					{PermissionClaim: widgets, State: apiv1alpha1.ClaimAccepted},
+					{PermissionClaim: configmaps, State: apiv1alpha1.ClaimAccepted},
+				},
+				"b": nil,
+			},
+		},
+		"reject with reason in all bindings": {
+			opts: DecideClaimsOptions{State: apiv1alpha1.ClaimRejected, AllBindings: true, Claims: []string{"configmaps"}, Reason: "NotNeeded"},
+			want: map[string][]apiv1alpha1.AcceptablePermissionClaim{
+				"a": {
+					{PermissionClaim: widgets, State: apiv1alpha1.ClaimRejected, Reason: "TooBroad"},
+					{PermissionClaim: configmaps, State: apiv1alpha1.ClaimRejected, Reason: "NotNeeded"},
+				},
+				"b": {{PermissionClaim: configmaps, State: apiv1alpha1.ClaimRejected, Reason: "NotNeeded"}},
+			},
+		},
+		"claim not requested by the binding": {
+			opts:    DecideClaimsOptions{State: apiv1alpha1.ClaimAccepted, APIBindingName: "b", Claims: []string{"widgets.example.io"}},
+			wantErr: "apibinding b does not request widgets.example.io",
+		},
+		"claim not requested by any binding": {
+			opts:    DecideClaimsOptions{State: apiv1alpha1.ClaimAccepted, AllBindings: true, Claims: []string{"secrets"}},
+			wantErr: "no APIBinding in workspace root:consumer requests secrets",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := kcpfakeclient.NewSimpleClientset(
+				newBinding("a", []apiv1alpha1.PermissionClaim{configmaps, widgets},
+					apiv1alpha1.AcceptablePermissionClaim{PermissionClaim: widgets, State: apiv1alpha1.ClaimRejected, Reason: "TooBroad"},
+				),
+				newBinding("b", []apiv1alpha1.PermissionClaim{configmaps}),
+			)
+
+			streams, _, _, _ := genericclioptions.NewTestIOStreams()
+			opts := tt.opts
+			opts.Options = NewDecideClaimsOptions(streams, opts.State).Options
+			opts.workspace = logicalcluster.NewPath("root:consumer")
+			opts.kcpClusterClient = client
+
+			err := opts.Run(context.Background())
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for name, want := range tt.want {
+				binding, err := client.Cluster(opts.workspace).ApisV1alpha1().APIBindings().Get(context.Background(), name, metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, want, binding.Spec.PermissionClaims, "apibinding %s", name)
+			}
+		})
+	}
+}
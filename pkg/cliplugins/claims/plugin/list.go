@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	apiv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// claimPending is the state of a claim requested by the APIExport that the APIBinding neither
+// accepts nor rejects.
+const claimPending = "Pending"
+
+// ListClaimsOptions contains the options for listing the permission claims of APIBindings.
+type ListClaimsOptions struct {
+	*base.Options
+
+	// APIBindingName is the APIBinding whose claims are listed. All APIBindings of the current
+	// workspace are listed if empty.
+	APIBindingName string
+	// Pending restricts the list to the claims the APIBindings have not decided about.
+	Pending bool
+
+	workspace        logicalcluster.Path
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewListClaimsOptions returns new ListClaimsOptions.
+func NewListClaimsOptions(streams genericclioptions.IOStreams) *ListClaimsOptions {
+	return &ListClaimsOptions{
+		Options: base.NewOptions(streams),
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *ListClaimsOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().BoolVar(&o.Pending, "pending", o.Pending, "Only list the claims that are neither accepted nor rejected")
+}
+
+// Complete ensures all fields are initialized.
+func (o *ListClaimsOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		o.APIBindingName = args[0]
+	}
+
+	var err error
+	o.workspace, o.kcpClusterClient, err = workspaceClient(o.Options)
+	return err
+}
+
+// Validate validates the ListClaimsOptions are complete and usable.
+func (o *ListClaimsOptions) Validate() error {
+	return o.Options.Validate()
+}
+
+// Run prints the claims requested by the APIExports of the APIBindings, and the decisions about
+// them. Decisions about claims no longer requested are marked as stale.
+func (o *ListClaimsOptions) Run(ctx context.Context) error {
+	bindings, err := getAPIBindings(ctx, o.kcpClusterClient, o.workspace, o.APIBindingName)
+	if err != nil {
+		return err
+	}
+
+	out := printers.GetNewTabWriter(o.Out)
+	defer out.Flush()
+
+	if _, err := fmt.Fprintln(out, "APIBINDING\tRESOURCE\tIDENTITY\tSELECTOR\tSTATE\tREASON"); err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		for _, row := range claimRows(&binding) {
+			if o.Pending && row.state != claimPending {
+				continue
+			}
+			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\t%s\n", binding.Name, row.resource, row.identity, row.selector, row.state, row.reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type claimRow struct {
+	resource, identity, selector, state, reason string
+}
+
+// claimRows returns a row per claim requested by the APIExport of the binding, followed by the
+// decisions about claims the APIExport does not request anymore.
+func claimRows(binding *apiv1alpha1.APIBinding) []claimRow {
+	var rows []claimRow
+	decided := make([]bool, len(binding.Spec.PermissionClaims))
+	for _, requested := range binding.Status.ExportPermissionClaims {
+		row := newClaimRow(requested, claimPending, "")
+		for i, decision := range binding.Spec.PermissionClaims {
+			if requested.Equal(decision.PermissionClaim) {
+				row.state, row.reason = string(decision.State), decision.Reason
+				decided[i] = true
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	for i, decision := range binding.Spec.PermissionClaims {
+		if !decided[i] {
+			rows = append(rows, newClaimRow(decision.PermissionClaim, string(decision.State)+" (stale)", decision.Reason))
+		}
+	}
+	return rows
+}
+
+func newClaimRow(claim apiv1alpha1.PermissionClaim, state, reason string) claimRow {
+	row := claimRow{
+		resource: schema.GroupResource{Group: claim.Group, Resource: claim.Resource}.String(),
+		identity: "<none>",
+		selector: claimSelector(claim),
+		state:    state,
+		reason:   reason,
+	}
+	if claim.IdentityHash != "" {
+		row.identity = claim.IdentityHash
+	}
+	if row.reason == "" {
+		row.reason = "<none>"
+	}
+	return row
+}
+
+// claimSelector returns the objects selected by a claim in a compact form.
+func claimSelector(claim apiv1alpha1.PermissionClaim) string {
+	switch {
+	case claim.ReferencedBy != nil:
+		return fmt.Sprintf("referencedBy=%s:%s", schema.GroupResource{Group: claim.ReferencedBy.Group, Resource: claim.ReferencedBy.Resource}, claim.ReferencedBy.NameField)
+	case claim.All || len(claim.ResourceSelector) == 0:
+		return "all"
+	}
+
+	selectors := make([]string, 0, len(claim.ResourceSelector))
+	for _, s := range claim.ResourceSelector {
+		var parts []string
+		if s.Namespace != "" {
+			parts = append(parts, "namespace="+s.Namespace)
+		}
+		if s.Name != "" {
+			parts = append(parts, "name="+s.Name)
+		}
+		if selector, err := metav1.LabelSelectorAsSelector(&s.LabelSelector); err == nil && !selector.Empty() {
+			parts = append(parts, "labels="+selector.String())
+		}
+		selectors = append(selectors, strings.Join(parts, ","))
+	}
+	return strings.Join(selectors, ";")
+}
+
+// workspaceClient returns the current workspace and a cluster aware client.
+func workspaceClient(o *base.Options) (logicalcluster.Path, kcpclientset.ClusterInterface, error) {
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return logicalcluster.Path{}, nil, err
+	}
+	_, workspace, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return logicalcluster.Path{}, nil, fmt.Errorf("current URL %q does not point to workspace", config.Host)
+	}
+	client, err := newKCPClusterClient(o.ClientConfig)
+	if err != nil {
+		return logicalcluster.Path{}, nil, fmt.Errorf("error while creating kcp client %w", err)
+	}
+	return workspace, client, nil
+}
+
+// getAPIBindings returns the named APIBinding of the workspace, or all if name is empty.
+func getAPIBindings(ctx context.Context, client kcpclientset.ClusterInterface, workspace logicalcluster.Path, name string) ([]apiv1alpha1.APIBinding, error) {
+	if name != "" {
+		binding, err := client.Cluster(workspace).ApisV1alpha1().APIBindings().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error finding apibinding: %w", err)
+		}
+		return []apiv1alpha1.APIBinding{*binding}, nil
+	}
+
+	bindings, err := client.Cluster(workspace).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing apibindings in %q workspace: %w", workspace, err)
+	}
+	return bindings.Items, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/debug/plugin"
+)
+
+var (
+	debugWorkspaceExample = `
+	# Print a report on the current workspace.
+	%[1]s debug workspace
+
+	# Collect the report and the objects of the workspace root:org:team into a tarball, with the events of the last 24 hours.
+	%[1]s debug workspace root:org:team --events-since 24h -f team-debug.tar.gz
+	`
+)
+
+// New returns a cobra.Command for collecting diagnostics.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cliName := "kubectl"
+	if pflag.CommandLine.Name() == "kubectl-kcp" {
+		cliName = "kubectl kcp"
+	}
+
+	debugCmd := &cobra.Command{
+		Use:              "debug",
+		Short:            "Collect diagnostics of kcp objects",
+		SilenceUsage:     true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	workspaceOpts := plugin.NewWorkspaceOptions(streams)
+	workspaceCmd := &cobra.Command{
+		Use:   "workspace [<workspace>]",
+		Short: "Collect diagnostics of a workspace",
+		Long: `Collect diagnostics of a workspace: its LogicalCluster, the APIBindings with their conditions and the state of
+the bound CRDs, recent events and resource quotas. Without --output-file, a readable report is printed. With it,
+the report and the collected objects are written to a gzipped tarball, e.g. to attach to a bug report.
+Parts that cannot be collected, e.g. for lack of permissions, are listed in the report.`,
+		Example:      fmt.Sprintf(debugWorkspaceExample, cliName),
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := workspaceOpts.Complete(args); err != nil {
+				return err
+			}
+			if err := workspaceOpts.Validate(); err != nil {
+				return err
+			}
+			return workspaceOpts.Run(cmd.Context())
+		},
+	}
+	workspaceOpts.BindFlags(workspaceCmd)
+	debugCmd.AddCommand(workspaceCmd)
+
+	return debugCmd
+}
@@ -0,0 +1,493 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// WorkspaceOptions contains the options for collecting diagnostics of a workspace.
+type WorkspaceOptions struct {
+	*base.Options
+
+	// Path is the workspace to diagnose, either absolute or relative to the current workspace.
+	// Defaults to the current workspace.
+	Path string
+	// OutputFile is the path of a gzipped tarball to write the report and the collected objects to.
+	// If empty, the report is printed.
+	OutputFile string
+	// EventsSince restricts the events in the report to those that occurred within this duration.
+	EventsSince time.Duration
+
+	workspace         logicalcluster.Path
+	kcpClusterClient  kcpclientset.ClusterInterface
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+	discoveryClient   discovery.DiscoveryInterface
+	now               func() time.Time
+}
+
+// NewWorkspaceOptions returns a new WorkspaceOptions.
+func NewWorkspaceOptions(streams genericclioptions.IOStreams) *WorkspaceOptions {
+	return &WorkspaceOptions{
+		Options:     base.NewOptions(streams),
+		EventsSince: time.Hour,
+		now:         time.Now,
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *WorkspaceOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "f", o.OutputFile, "Path of a gzipped tarball to write the report and the collected objects to, instead of printing the report")
+	cmd.Flags().DurationVar(&o.EventsSince, "events-since", o.EventsSince, "Only include events that occurred within this duration")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *WorkspaceOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		o.Path = args[0]
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	baseURL, current, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
+	}
+	switch {
+	case o.Path == "":
+		o.workspace = current
+	case strings.Contains(o.Path, ":") || o.Path == core.RootCluster.String():
+		o.workspace = logicalcluster.NewPath(o.Path)
+	default:
+		o.workspace = current.Join(o.Path)
+	}
+
+	clusterConfig := rest.CopyConfig(config)
+	u, err := url.Parse(config.Host)
+	if err != nil {
+		return err
+	}
+	u.Path = ""
+	clusterConfig.Host = u.String()
+	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
+	if o.kcpClusterClient, err = kcpclientset.NewForConfig(clusterConfig); err != nil {
+		return err
+	}
+	if o.kubeClusterClient, err = kcpkubernetesclientset.NewForConfig(clusterConfig); err != nil {
+		return err
+	}
+
+	workspaceConfig := rest.CopyConfig(config)
+	workspaceConfig.Host = baseURL.String() + o.workspace.RequestPath()
+	o.discoveryClient, err = discovery.NewDiscoveryClientForConfig(workspaceConfig)
+	return err
+}
+
+// Validate validates the WorkspaceOptions are complete and usable.
+func (o *WorkspaceOptions) Validate() error {
+	if o.Path != "" && !logicalcluster.NewPath(o.Path).IsValid() {
+		return fmt.Errorf("invalid workspace path %q", o.Path)
+	}
+	if o.EventsSince < 0 {
+		return fmt.Errorf("--events-since must not be negative")
+	}
+	return o.Options.Validate()
+}
+
+// workspaceReport holds the diagnostics of a workspace.
+type workspaceReport struct {
+	Workspace      string               `json:"workspace"`
+	CollectedAt    metav1.Time          `json:"collectedAt"`
+	LogicalCluster *debugLogicalCluster `json:"logicalCluster,omitempty"`
+	APIBindings    []debugAPIBinding    `json:"apiBindings,omitempty"`
+	Events         []debugEvent         `json:"events,omitempty"`
+	Quotas         []debugQuota         `json:"quotas,omitempty"`
+	// Errors are the parts that could not be collected, e.g. for lack of permissions.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type debugLogicalCluster struct {
+	Phase        string           `json:"phase,omitempty"`
+	Owner        string           `json:"owner,omitempty"`
+	Initializers []string         `json:"initializers,omitempty"`
+	Conditions   []debugCondition `json:"conditions,omitempty"`
+}
+
+type debugCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type debugAPIBinding struct {
+	Name string `json:"name"`
+	// APIExport is the bound APIExport in <path>:<name> notation.
+	APIExport string `json:"apiExport,omitempty"`
+	// APIExportError is set if the APIExport cannot be retrieved.
+	APIExportError string             `json:"apiExportError,omitempty"`
+	Phase          string             `json:"phase,omitempty"`
+	Conditions     []debugCondition   `json:"conditions,omitempty"`
+	Resources      []debugBoundSchema `json:"resources,omitempty"`
+	// PendingClaims is the number of claims of the APIExport neither accepted nor rejected.
+	PendingClaims int `json:"pendingClaims,omitempty"`
+}
+
+// debugBoundSchema is the state of the CRD bound for a resource of an APIBinding.
+type debugBoundSchema struct {
+	Resource        string   `json:"resource"`
+	Schema          string   `json:"schema"`
+	StorageVersions []string `json:"storageVersions,omitempty"`
+	// Served is whether the resource is served in the workspace according to discovery.
+	Served bool `json:"served"`
+}
+
+type debugEvent struct {
+	Time    metav1.Time `json:"time"`
+	Type    string      `json:"type"`
+	Object  string      `json:"object"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+}
+
+type debugQuota struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Run collects the diagnostics and prints them, or writes them with the collected objects to the
+// output file. Parts that cannot be collected are listed in the report instead of failing.
+func (o *WorkspaceOptions) Run(ctx context.Context) error {
+	b := pluginhelpers.NewBundle(strings.ReplaceAll(o.workspace.String(), ":", "_"))
+	report := &workspaceReport{
+		Workspace:   o.workspace.String(),
+		CollectedAt: metav1.NewTime(o.now()),
+	}
+
+	o.collectLogicalCluster(ctx, report, b)
+	o.collectAPIBindings(ctx, report, b)
+	o.collectEvents(ctx, report, b)
+	o.collectQuotas(ctx, report, b)
+	report.Errors = b.Errors()
+
+	if o.OutputFile == "" {
+		return printReport(o.Out, report)
+	}
+
+	var buf bytes.Buffer
+	if err := printReport(&buf, report); err != nil {
+		return err
+	}
+	b.Add("report.txt", buf.Bytes())
+	b.AddYAML("report.yaml", report)
+	if err := b.Write(o.OutputFile, o.now()); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(o.Out, "Wrote diagnostics of workspace %q to %s.\n", o.workspace, o.OutputFile)
+	return err
+}
+
+func (o *WorkspaceOptions) collectLogicalCluster(ctx context.Context, report *workspaceReport, b *pluginhelpers.Bundle) {
+	lc, err := o.kcpClusterClient.Cluster(o.workspace).CoreV1alpha1().LogicalClusters().Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+	if err != nil {
+		b.Errorf("getting LogicalCluster: %v", err)
+		return
+	}
+	lc.APIVersion, lc.Kind = corev1alpha1.SchemeGroupVersion.String(), "LogicalCluster"
+	lc.ManagedFields = nil
+	b.AddYAML("logicalcluster.yaml", lc)
+
+	report.LogicalCluster = &debugLogicalCluster{
+		Phase:      string(lc.Status.Phase),
+		Conditions: debugConditions(lc.Status.Conditions),
+	}
+	if owner := lc.Spec.Owner; owner != nil {
+		report.LogicalCluster.Owner = fmt.Sprintf("%s %s|%s", owner.Resource, owner.Cluster, owner.Name)
+	}
+	for _, initializer := range lc.Status.Initializers {
+		report.LogicalCluster.Initializers = append(report.LogicalCluster.Initializers, string(initializer))
+	}
+}
+
+func (o *WorkspaceOptions) collectAPIBindings(ctx context.Context, report *workspaceReport, b *pluginhelpers.Bundle) {
+	bindings, err := o.kcpClusterClient.Cluster(o.workspace).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing APIBindings: %v", err)
+		return
+	}
+
+	served := sets.NewString()
+	_, resourceLists, err := o.discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		// partial results are returned for the groups that could be discovered
+		b.Errorf("discovering resources: %v", err)
+	}
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			served.Insert(schema.GroupResource{Group: gv.Group, Resource: r.Name}.String())
+		}
+	}
+
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		binding.APIVersion, binding.Kind = apisv1alpha1.SchemeGroupVersion.String(), "APIBinding"
+		binding.ManagedFields = nil
+
+		debug := debugAPIBinding{
+			Name:       binding.Name,
+			Phase:      string(binding.Status.Phase),
+			Conditions: debugConditions(binding.Status.Conditions),
+		}
+		for _, r := range binding.Status.BoundResources {
+			gr := schema.GroupResource{Group: r.Group, Resource: r.ServedResource()}
+			debug.Resources = append(debug.Resources, debugBoundSchema{
+				Resource:        gr.String(),
+				Schema:          r.Schema.Name,
+				StorageVersions: r.StorageVersions,
+				Served:          served.Has(gr.String()),
+			})
+		}
+		for _, claim := range binding.Status.ExportPermissionClaims {
+			decided := false
+			for _, c := range binding.Spec.PermissionClaims {
+				if c.PermissionClaim.Equal(claim) {
+					decided = true
+					break
+				}
+			}
+			if !decided {
+				debug.PendingClaims++
+			}
+		}
+
+		if ref := binding.Spec.Reference.Export; ref != nil {
+			exportPath := logicalcluster.NewPath(ref.Path)
+			if exportPath.Empty() {
+				exportPath = o.workspace
+			}
+			debug.APIExport = exportPath.Join(ref.Name).String()
+			if _, err := o.kcpClusterClient.Cluster(exportPath).ApisV1alpha1().APIExports().Get(ctx, ref.Name, metav1.GetOptions{}); err != nil {
+				debug.APIExportError = err.Error()
+			}
+		}
+
+		report.APIBindings = append(report.APIBindings, debug)
+	}
+	b.AddYAML("apibindings.yaml", bindings)
+}
+
+func (o *WorkspaceOptions) collectEvents(ctx context.Context, report *workspaceReport, b *pluginhelpers.Bundle) {
+	events, err := o.kubeClusterClient.Cluster(o.workspace).CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing events: %v", err)
+		return
+	}
+
+	since := o.now().Add(-o.EventsSince)
+	var recent []corev1.Event
+	for _, event := range events.Items {
+		if !eventTime(&event).Before(since) {
+			recent = append(recent, event)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return eventTime(&recent[i]).Before(eventTime(&recent[j]))
+	})
+	for i := range recent {
+		event := &recent[i]
+		object := strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name
+		if event.InvolvedObject.Namespace != "" {
+			object = event.InvolvedObject.Namespace + "/" + object
+		}
+		report.Events = append(report.Events, debugEvent{
+			Time:    metav1.NewTime(eventTime(event)),
+			Type:    event.Type,
+			Object:  object,
+			Reason:  event.Reason,
+			Message: strings.ReplaceAll(event.Message, "\n", " "),
+		})
+		event.ManagedFields = nil
+	}
+	if len(recent) > 0 {
+		b.AddYAML("events.yaml", recent)
+	}
+}
+
+func (o *WorkspaceOptions) collectQuotas(ctx context.Context, report *workspaceReport, b *pluginhelpers.Bundle) {
+	quotas, err := o.kubeClusterClient.Cluster(o.workspace).CoreV1().ResourceQuotas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing resource quotas: %v", err)
+		return
+	}
+
+	for _, quota := range quotas.Items {
+		debug := debugQuota{Name: quota.Name, Namespace: quota.Namespace}
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			used := quota.Status.Used[corev1.ResourceName(name)]
+			hard := quota.Status.Hard[corev1.ResourceName(name)]
+			debug.Resources = append(debug.Resources, fmt.Sprintf("%s %s/%s", name, used.String(), hard.String()))
+		}
+		report.Quotas = append(report.Quotas, debug)
+	}
+	if len(quotas.Items) > 0 {
+		b.AddYAML("resourcequotas.yaml", quotas)
+	}
+}
+
+// printReport prints the report in a readable form.
+func printReport(out io.Writer, report *workspaceReport) error {
+	w := printers.GetNewTabWriter(out)
+
+	fmt.Fprintf(w, "Workspace %s, collected at %s\n", report.Workspace, report.CollectedAt.UTC().Format(time.RFC3339))
+	if lc := report.LogicalCluster; lc != nil {
+		fmt.Fprintf(w, "\nLogicalCluster:\n  Phase:\t%s\n", lc.Phase)
+		if lc.Owner != "" {
+			fmt.Fprintf(w, "  Owner:\t%s\n", lc.Owner)
+		}
+		if len(lc.Initializers) > 0 {
+			fmt.Fprintf(w, "  Initializers:\t%s\n", strings.Join(lc.Initializers, ", "))
+		}
+		printConditions(w, "  ", lc.Conditions)
+	}
+
+	if len(report.APIBindings) > 0 {
+		fmt.Fprintf(w, "\nAPIBindings:\n")
+	}
+	for _, binding := range report.APIBindings {
+		fmt.Fprintf(w, "  %s:\n    APIExport:\t%s\n    Phase:\t%s\n", binding.Name, binding.APIExport, binding.Phase)
+		if binding.APIExportError != "" {
+			fmt.Fprintf(w, "    APIExport error:\t%s\n", binding.APIExportError)
+		}
+		if binding.PendingClaims > 0 {
+			fmt.Fprintf(w, "    Pending claims:\t%d\n", binding.PendingClaims)
+		}
+		printConditions(w, "    ", binding.Conditions)
+		if len(binding.Resources) > 0 {
+			fmt.Fprintf(w, "    RESOURCE\tSCHEMA\tSTORAGE VERSIONS\tSERVED\n")
+		}
+		for _, r := range binding.Resources {
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%t\n", r.Resource, r.Schema, strings.Join(r.StorageVersions, ","), r.Served)
+		}
+	}
+
+	if len(report.Events) > 0 {
+		fmt.Fprintf(w, "\nEvents:\n  TIME\tTYPE\tOBJECT\tREASON\tMESSAGE\n")
+	}
+	for _, e := range report.Events {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", e.Time.UTC().Format(time.RFC3339), e.Type, e.Object, e.Reason, e.Message)
+	}
+
+	if len(report.Quotas) > 0 {
+		fmt.Fprintf(w, "\nResourceQuotas:\n  QUOTA\tUSED/HARD\n")
+	}
+	for _, q := range report.Quotas {
+		name := q.Name
+		if q.Namespace != "" {
+			name = q.Namespace + "/" + q.Name
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", name, strings.Join(q.Resources, ", "))
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(w, "\nNot collected:\n")
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintf(w, "  %s\n", e)
+	}
+
+	return w.Flush()
+}
+
+func printConditions(w io.Writer, indent string, conditions []debugCondition) {
+	if len(conditions) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%sTYPE\tSTATUS\tREASON\tMESSAGE\n", indent)
+	for _, c := range conditions {
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\n", indent, c.Type, c.Status, c.Reason, c.Message)
+	}
+}
+
+func debugConditions(conditions conditionsv1alpha1.Conditions) []debugCondition {
+	ret := make([]debugCondition, 0, len(conditions))
+	for _, c := range conditions {
+		ret = append(ret, debugCondition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: strings.ReplaceAll(c.Message, "\n", " "),
+		})
+	}
+	return ret
+}
+
+// eventTime returns the time an event last occurred.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
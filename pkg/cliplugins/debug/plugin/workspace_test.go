@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestDebugWorkspace(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	annotations := map[string]string{logicalcluster.AnnotationKey: "root:org:team"}
+
+	kcpClient := kcpfakeclient.NewSimpleClientset(
+		&corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: annotations},
+			Spec: corev1alpha1.LogicalClusterSpec{
+				Owner: &corev1alpha1.LogicalClusterOwner{Resource: "workspaces", Cluster: "org", Name: "team"},
+			},
+			Status: corev1alpha1.LogicalClusterStatus{
+				Phase:        corev1alpha1.LogicalClusterPhaseInitializing,
+				Initializers: []corev1alpha1.LogicalClusterInitializer{"root:universal"},
+			},
+		},
+		&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: annotations},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "root:providers", Name: "widgets"}},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				Phase: apisv1alpha1.APIBindingPhaseBound,
+				Conditions: conditionsv1alpha1.Conditions{
+					{Type: apisv1alpha1.InitialBindingCompleted, Status: corev1.ConditionFalse, Reason: "NamingConflicts", Message: "conflict\nwith gadgets"},
+				},
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.example.io"}, StorageVersions: []string{"v1"}},
+					{Group: "example.io", Resource: "sprockets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "v1.sprockets.example.io"}, StorageVersions: []string{"v1"}},
+				},
+				ExportPermissionClaims: []apisv1alpha1.PermissionClaim{{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}},
+			},
+		},
+	)
+	kubeClient := kcpfakekubeclient.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "recent", Namespace: "default", Annotations: annotations},
+			InvolvedObject: corev1.ObjectReference{Kind: "ConfigMap", Namespace: "default", Name: "cm"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Failed",
+			Message:        "something failed",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "old", Namespace: "default", Annotations: annotations},
+			InvolvedObject: corev1.ObjectReference{Kind: "ConfigMap", Namespace: "default", Name: "cm"},
+			Reason:         "Ancient",
+			LastTimestamp:  metav1.NewTime(now.Add(-2 * time.Hour)),
+		},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default", Annotations: annotations},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceConfigMaps: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourceConfigMaps: resource.MustParse("3")},
+			},
+		},
+	)
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "example.io/v1", APIResources: []metav1.APIResource{{Name: "widgets"}}},
+	}}}
+
+	newOptions := func() (*WorkspaceOptions, *bytes.Buffer) {
+		streams, _, out, _ := genericclioptions.NewTestIOStreams()
+		opts := NewWorkspaceOptions(streams)
+		opts.workspace = logicalcluster.NewPath("root:org:team")
+		opts.kcpClusterClient = kcpClient
+		opts.kubeClusterClient = kubeClient
+		opts.discoveryClient = discoveryClient
+		opts.now = func() time.Time { return now }
+		return opts, out
+	}
+
+	t.Run("report", func(t *testing.T) {
+		opts, buf := newOptions()
+		require.NoError(t, opts.Run(context.Background()))
+
+		out := buf.String()
+		require.Contains(t, out, "Workspace root:org:team, collected at 2023-05-01T12:00:00Z")
+		require.Regexp(t, `Phase:\s+Initializing`, out)
+		require.Regexp(t, `Owner:\s+workspaces org\|team`, out)
+		require.Regexp(t, `Initializers:\s+root:universal`, out)
+		require.Regexp(t, `APIExport:\s+root:providers:widgets`, out)
+		require.Regexp(t, `APIExport error:\s+.*not found`, out)
+		require.Regexp(t, `Pending claims:\s+1`, out)
+		require.Regexp(t, `InitialBindingCompleted\s+False\s+NamingConflicts\s+conflict with gadgets`, out)
+		require.Regexp(t, `widgets.example.io\s+v1.widgets.example.io\s+v1\s+true`, out)
+		require.Regexp(t, `sprockets.example.io\s+v1.sprockets.example.io\s+v1\s+false`, out)
+		require.Regexp(t, `Warning\s+default/configmap/cm\s+Failed\s+something failed`, out)
+		require.NotContains(t, out, "Ancient")
+		require.Regexp(t, `default/quota\s+configmaps 3/10`, out)
+		require.NotContains(t, out, "Not collected")
+	})
+
+	t.Run("tarball", func(t *testing.T) {
+		opts, _ := newOptions()
+		opts.OutputFile = filepath.Join(t.TempDir(), "debug.tar.gz")
+		require.NoError(t, opts.Run(context.Background()))
+
+		f, err := os.Open(opts.OutputFile)
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		tr := tar.NewReader(gz)
+		var names []string
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, filepath.Base(hdr.Name))
+		}
+		require.ElementsMatch(t, []string{"logicalcluster.yaml", "apibindings.yaml", "events.yaml", "resourcequotas.yaml", "report.txt", "report.yaml"}, names)
+	})
+}
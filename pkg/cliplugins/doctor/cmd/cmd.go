@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/doctor/plugin"
+)
+
+var (
+	doctorExample = `
+	# Check the health of the kcp deployment the current workspace is served by.
+	%[1]s doctor
+
+	# Also measure the scheduling latency by creating, and deleting again, a workspace in the current workspace.
+	%[1]s doctor --probe-scheduling
+
+	# Print the report as JSON.
+	%[1]s doctor -o json
+	`
+)
+
+// New returns a cobra.Command checking the health of a kcp deployment.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cliName := "kubectl"
+	if pflag.CommandLine.Name() == "kubectl-kcp" {
+		cliName = "kubectl kcp"
+	}
+
+	opts := plugin.NewDoctorOptions(streams)
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the health of a kcp deployment",
+		Long: `Check the health of a kcp deployment from the client's perspective: the reachability of the front-proxy
+and the virtual workspace endpoints of all shards, the shard configuration, the freshness of the cache
+server as seen by the APIBindings of the current workspace, the workspace scheduling latency, and common
+misconfigurations of the kubeconfig. Fails if any check fails.`,
+		Example:      fmt.Sprintf(doctorExample, cliName),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.BindFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,398 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// doctor runs the checks against the deployment the config points to.
+type doctor struct {
+	config           *rest.Config
+	kcpClusterClient kcpclientset.ClusterInterface
+	timeout          time.Duration
+	probeScheduling  bool
+}
+
+func (d *doctor) run(ctx context.Context) *Report {
+	report := &Report{}
+	add := func(checks ...Check) {
+		report.Checks = append(report.Checks, checks...)
+	}
+
+	kubeconfig, workspace := checkKubeconfig(d.config)
+	add(kubeconfig...)
+	if workspace.Empty() {
+		return report
+	}
+
+	frontProxy := d.checkFrontProxy(ctx)
+	add(frontProxy)
+	if frontProxy.Status == StatusError {
+		// everything else talks to the front-proxy too.
+		return report
+	}
+
+	add(d.checkWorkspace(ctx, workspace))
+	shards, shardChecks := d.checkShards(ctx)
+	add(shardChecks...)
+	add(d.checkVirtualWorkspaces(ctx, shards)...)
+	add(d.checkCacheFreshness(ctx, workspace))
+	add(d.checkScheduling(ctx, workspace))
+
+	return report
+}
+
+// checkKubeconfig checks the kubeconfig for common misconfigurations, returning the current workspace.
+func checkKubeconfig(config *rest.Config) ([]Check, logicalcluster.Path) {
+	_, workspace, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return []Check{{
+			Name:    "kubeconfig",
+			Status:  StatusError,
+			Message: fmt.Sprintf("server URL %q does not point to a workspace, e.g. https://<front-proxy>/clusters/root", config.Host),
+		}}, logicalcluster.Path{}
+	}
+
+	checks := []Check{{
+		Name:    "kubeconfig",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("current workspace is %s", workspace),
+	}}
+	if config.Insecure {
+		checks = append(checks, Check{
+			Name:    "kubeconfig/tls",
+			Status:  StatusWarning,
+			Message: "TLS certificate verification is disabled",
+		})
+	}
+	if u, err := url.Parse(config.Host); err == nil && u.Scheme == "http" {
+		checks = append(checks, Check{
+			Name:    "kubeconfig/tls",
+			Status:  StatusWarning,
+			Message: "the server URL does not use TLS",
+		})
+	}
+	return checks, workspace
+}
+
+// get sends a GET request to the given URL with the credentials of the kubeconfig, returning
+// the status code and the duration until the response.
+func (d *doctor) get(ctx context.Context, u string) (int, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	client, err := rest.HTTPClientFor(d.config)
+	if err != nil {
+		return 0, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, duration, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, duration, nil
+}
+
+func (d *doctor) checkFrontProxy(ctx context.Context) Check {
+	check := Check{Name: "front-proxy"}
+
+	code, duration, err := d.get(ctx, strings.TrimSuffix(d.config.Host, "/")+"/version")
+	check.Duration = formatDuration(duration)
+	switch {
+	case err != nil:
+		check.Status, check.Message = StatusError, fmt.Sprintf("unreachable: %v", err)
+	case code == http.StatusUnauthorized:
+		check.Status, check.Message = StatusError, "reachable, but the credentials are not accepted"
+	case code >= 500:
+		check.Status, check.Message = StatusError, fmt.Sprintf("reachable, but responded with %d", code)
+	default:
+		check.Status, check.Message = StatusOK, fmt.Sprintf("reachable, responded with %d", code)
+	}
+	return check
+}
+
+func (d *doctor) checkWorkspace(ctx context.Context, workspace logicalcluster.Path) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	check := Check{Name: "workspace"}
+	cluster, err := d.kcpClusterClient.Cluster(workspace).CoreV1alpha1().LogicalClusters().Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsForbidden(err):
+		check.Status, check.Message = StatusSkipped, fmt.Sprintf("not permitted to get the logical cluster of %s", workspace)
+	case err != nil:
+		check.Status, check.Message = StatusError, fmt.Sprintf("failed to get the logical cluster of %s: %v", workspace, err)
+	case cluster.Status.Phase != corev1alpha1.LogicalClusterPhaseReady:
+		check.Status, check.Message = StatusWarning, fmt.Sprintf("%s is in phase %s", workspace, cluster.Status.Phase)
+	default:
+		check.Status, check.Message = StatusOK, fmt.Sprintf("%s is ready", workspace)
+	}
+	return check
+}
+
+func (d *doctor) checkShards(ctx context.Context) ([]corev1alpha1.Shard, []Check) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	shards, err := d.kcpClusterClient.Cluster(core.RootCluster.Path()).CoreV1alpha1().Shards().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsForbidden(err):
+		return nil, []Check{{Name: "shards", Status: StatusSkipped, Message: "not permitted to list shards in root"}}
+	case err != nil:
+		return nil, []Check{{Name: "shards", Status: StatusError, Message: fmt.Sprintf("failed to list shards: %v", err)}}
+	case len(shards.Items) == 0:
+		return nil, []Check{{Name: "shards", Status: StatusError, Message: "no shards registered in root"}}
+	}
+
+	return shards.Items, validateShards(shards.Items)
+}
+
+// validateShards checks the shards for common misconfigurations.
+func validateShards(shards []corev1alpha1.Shard) []Check {
+	checks := []Check{{
+		Name:    "shards",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d shard(s) registered", len(shards)),
+	}}
+
+	baseURLs := map[string][]string{}
+	for i := range shards {
+		shard := &shards[i]
+		check := Check{Name: "shard/" + shard.Name, Status: StatusOK}
+		var problems []string
+		for _, u := range []struct{ field, value string }{
+			{"baseURL", shard.Spec.BaseURL},
+			{"externalURL", shard.Spec.ExternalURL},
+			{"virtualWorkspaceURL", shard.Spec.VirtualWorkspaceURL},
+		} {
+			if u.value == "" {
+				problems = append(problems, fmt.Sprintf("%s is not set", u.field))
+				continue
+			}
+			if parsed, err := url.Parse(u.value); err != nil || parsed.Host == "" {
+				problems = append(problems, fmt.Sprintf("%s %q is not a valid URL", u.field, u.value))
+			}
+		}
+		if len(problems) > 0 {
+			check.Status = StatusError
+		}
+		if conditions.IsFalse(shard, conditionsv1alpha1.ReadyCondition) {
+			check.Status = StatusError
+			problems = append(problems, fmt.Sprintf("not ready: %s", conditions.GetMessage(shard, conditionsv1alpha1.ReadyCondition)))
+		}
+		if len(problems) == 0 {
+			check.Message = fmt.Sprintf("base URL %s", shard.Spec.BaseURL)
+		} else {
+			check.Message = strings.Join(problems, "; ")
+		}
+		checks = append(checks, check)
+
+		if shard.Spec.BaseURL != "" {
+			baseURLs[shard.Spec.BaseURL] = append(baseURLs[shard.Spec.BaseURL], shard.Name)
+		}
+	}
+
+	duplicates := make([]string, 0, len(baseURLs))
+	for u, names := range baseURLs {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates = append(duplicates, fmt.Sprintf("%s share the base URL %s", strings.Join(names, ", "), u))
+		}
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		checks[0].Status = StatusError
+		checks[0].Message = strings.Join(duplicates, "; ")
+	}
+
+	return checks
+}
+
+func (d *doctor) checkVirtualWorkspaces(ctx context.Context, shards []corev1alpha1.Shard) []Check {
+	urls := sets.NewString()
+	for _, shard := range shards {
+		if u, err := url.Parse(shard.Spec.VirtualWorkspaceURL); err == nil && u.Host != "" {
+			urls.Insert(strings.TrimSuffix(shard.Spec.VirtualWorkspaceURL, "/"))
+		}
+	}
+
+	checks := make([]Check, 0, urls.Len())
+	for _, u := range urls.List() {
+		check := Check{Name: "virtual-workspaces/" + u}
+		code, duration, err := d.get(ctx, u+"/readyz")
+		check.Duration = formatDuration(duration)
+		switch {
+		case err != nil:
+			check.Status, check.Message = StatusError, fmt.Sprintf("unreachable: %v", err)
+		case code >= 500:
+			check.Status, check.Message = StatusError, fmt.Sprintf("reachable, but responded with %d", code)
+		default:
+			check.Status, check.Message = StatusOK, fmt.Sprintf("reachable, responded with %d", code)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkCacheFreshness compares the APIBindings of the workspace with their APIExports. The
+// APIBinding controller reads APIExports through the cache server, hence a binding which
+// does not see an existing export, or does not catch up with its schemas, hints at a stale
+// cache server.
+func (d *doctor) checkCacheFreshness(ctx context.Context, workspace logicalcluster.Path) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	check := Check{Name: "cache-server"}
+	bindings, err := d.kcpClusterClient.Cluster(workspace).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsForbidden(err):
+		check.Status, check.Message = StatusSkipped, fmt.Sprintf("not permitted to list APIBindings in %s", workspace)
+		return check
+	case err != nil:
+		check.Status, check.Message = StatusError, fmt.Sprintf("failed to list APIBindings: %v", err)
+		return check
+	}
+
+	var compared int
+	var stale, lagging []string
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if binding.Spec.Reference.Export == nil {
+			continue
+		}
+		exportPath := logicalcluster.NewPath(binding.Spec.Reference.Export.Path)
+		if exportPath.Empty() {
+			exportPath = workspace
+		}
+		export, err := d.kcpClusterClient.Cluster(exportPath).ApisV1alpha1().APIExports().Get(ctx, binding.Spec.Reference.Export.Name, metav1.GetOptions{})
+		if err != nil {
+			// not visible to the user, nothing to compare.
+			continue
+		}
+		compared++
+
+		if conditions.IsFalse(binding, apisv1alpha1.APIExportValid) && conditions.GetReason(binding, apisv1alpha1.APIExportValid) == apisv1alpha1.APIExportNotFoundReason {
+			stale = append(stale, fmt.Sprintf("APIBinding %s does not find the existing APIExport %s|%s", binding.Name, exportPath, export.Name))
+			continue
+		}
+		if !conditions.IsTrue(binding, apisv1alpha1.InitialBindingCompleted) {
+			continue
+		}
+		bound := sets.NewString()
+		for _, r := range binding.Status.BoundResources {
+			bound.Insert(r.Schema.Name)
+		}
+		if latest := sets.NewString(export.Spec.LatestResourceSchemas...); !latest.Equal(bound) {
+			lagging = append(lagging, fmt.Sprintf("APIBinding %s has not caught up with the schemas of APIExport %s|%s", binding.Name, exportPath, export.Name))
+		}
+	}
+
+	switch {
+	case len(stale) > 0:
+		check.Status, check.Message = StatusError, strings.Join(append(stale, lagging...), "; ")
+	case len(lagging) > 0:
+		check.Status, check.Message = StatusWarning, strings.Join(lagging, "; ")
+	case compared == 0:
+		check.Status, check.Message = StatusSkipped, fmt.Sprintf("no APIBindings in %s to compare with their APIExports", workspace)
+	default:
+		check.Status, check.Message = StatusOK, fmt.Sprintf("%d APIBinding(s) consistent with their APIExports", compared)
+	}
+	return check
+}
+
+func (d *doctor) checkScheduling(ctx context.Context, workspace logicalcluster.Path) Check {
+	check := Check{Name: "workspace-scheduling"}
+	if !d.probeScheduling {
+		check.Status, check.Message = StatusSkipped, "enable with --probe-scheduling"
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	workspaces := d.kcpClusterClient.Cluster(workspace).TenancyV1alpha1().Workspaces()
+	start := time.Now()
+	ws, err := workspaces.Create(ctx, &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "kcp-doctor-"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		check.Status, check.Message = StatusError, fmt.Sprintf("failed to create a workspace in %s: %v", workspace, err)
+		return check
+	}
+	defer func() {
+		// use a fresh context, the probe might have timed out.
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		defer cancel()
+		_ = workspaces.Delete(ctx, ws.Name, metav1.DeleteOptions{})
+	}()
+
+	err = wait.PollImmediateUntilWithContext(ctx, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+		ws, err = workspaces.Get(ctx, ws.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil //nolint:nilerr // retry until the timeout
+		}
+		return ws.Status.Phase == corev1alpha1.LogicalClusterPhaseReady, nil
+	})
+	check.Duration = formatDuration(time.Since(start))
+	if err != nil {
+		check.Status, check.Message = StatusError, fmt.Sprintf("workspace %s not ready after %s, phase %q", ws.Name, d.timeout, ws.Status.Phase)
+		return check
+	}
+	check.Status, check.Message = StatusOK, fmt.Sprintf("workspace %s scheduled and ready", ws.Name)
+	return check
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK      Status = "OK"
+	StatusWarning Status = "Warning"
+	StatusError   Status = "Error"
+	StatusSkipped Status = "Skipped"
+)
+
+// Check is the result of a single diagnostic check.
+type Check struct {
+	// Name identifies the check, e.g. "front-proxy" or "shard/root".
+	Name string `json:"name"`
+	// Status is the outcome of the check.
+	Status Status `json:"status"`
+	// Message explains the outcome.
+	Message string `json:"message"`
+	// Duration is the time the probed endpoint took to respond, if applicable.
+	Duration string `json:"duration,omitempty"`
+}
+
+// Report is the result of all checks.
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// DoctorOptions contains the options for checking the health of a kcp deployment.
+type DoctorOptions struct {
+	*base.Options
+
+	// Output is the output format of the report, one of table, json or yaml.
+	Output string
+	// ProbeScheduling enables creating, and deleting again, a workspace in the current
+	// workspace to measure the scheduling latency.
+	ProbeScheduling bool
+	// Timeout is the time after which a single check is considered failed.
+	Timeout time.Duration
+
+	config           *rest.Config
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewDoctorOptions returns a new DoctorOptions.
+func NewDoctorOptions(streams genericclioptions.IOStreams) *DoctorOptions {
+	return &DoctorOptions{
+		Options: base.NewOptions(streams),
+		Output:  "table",
+		Timeout: 30 * time.Second,
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *DoctorOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format of the report. Valid values are 'table', 'json' and 'yaml'")
+	cmd.Flags().BoolVar(&o.ProbeScheduling, "probe-scheduling", o.ProbeScheduling, "Create a workspace in the current workspace to measure the scheduling latency, and delete it again")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "Time after which a single check is considered failed")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *DoctorOptions) Complete() error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	var err error
+	o.config, err = o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig := rest.CopyConfig(o.config)
+	u, err := url.Parse(o.config.Host)
+	if err != nil {
+		return err
+	}
+	u.Path = ""
+	clusterConfig.Host = u.String()
+	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
+	o.kcpClusterClient, err = kcpclientset.NewForConfig(clusterConfig)
+	return err
+}
+
+// Validate validates the DoctorOptions are complete and usable.
+func (o *DoctorOptions) Validate() error {
+	var errs []error
+
+	if err := o.Options.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if o.Output != "table" && o.Output != "json" && o.Output != "yaml" {
+		errs = append(errs, fmt.Errorf("invalid value %q for --output; valid values are table, json, yaml", o.Output))
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--timeout must be positive"))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// Run runs all checks and prints the report. It fails if any check failed.
+func (o *DoctorOptions) Run(ctx context.Context) error {
+	d := &doctor{
+		config:           o.config,
+		kcpClusterClient: o.kcpClusterClient,
+		timeout:          o.Timeout,
+		probeScheduling:  o.ProbeScheduling,
+	}
+	report := d.run(ctx)
+
+	if err := o.print(report); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range report.Checks {
+		if c.Status == StatusError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(report.Checks))
+	}
+	return nil
+}
+
+func (o *DoctorOptions) print(report *Report) error {
+	switch o.Output {
+	case "json":
+		bs, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Out, string(bs))
+		return err
+	case "yaml":
+		bs, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(bs)
+		return err
+	}
+
+	out := printers.GetNewTabWriter(o.Out)
+	defer out.Flush()
+
+	if _, err := fmt.Fprintf(out, "CHECK\tSTATUS\tDURATION\tMESSAGE\n"); err != nil {
+		return err
+	}
+	for _, c := range report.Checks {
+		if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", c.Name, c.Status, c.Duration, c.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestCheckKubeconfig(t *testing.T) {
+	tests := map[string]struct {
+		config    *rest.Config
+		want      []Status
+		workspace logicalcluster.Path
+	}{
+		"workspace URL": {
+			config:    &rest.Config{Host: "https://kcp/clusters/root:foo"},
+			want:      []Status{StatusOK},
+			workspace: logicalcluster.NewPath("root:foo"),
+		},
+		"no workspace URL": {
+			config: &rest.Config{Host: "https://kcp"},
+			want:   []Status{StatusError},
+		},
+		"insecure": {
+			config:    &rest.Config{Host: "http://kcp/clusters/root", TLSClientConfig: rest.TLSClientConfig{Insecure: true}},
+			want:      []Status{StatusOK, StatusWarning, StatusWarning},
+			workspace: logicalcluster.NewPath("root"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			checks, workspace := checkKubeconfig(tt.config)
+			require.Equal(t, tt.want, statuses(checks))
+			require.Equal(t, tt.workspace, workspace)
+		})
+	}
+}
+
+func TestValidateShards(t *testing.T) {
+	shard := func(name, baseURL string, conds ...conditionsv1alpha1.Condition) corev1alpha1.Shard {
+		return corev1alpha1.Shard{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1alpha1.ShardSpec{
+				BaseURL:             baseURL,
+				ExternalURL:         "https://front-proxy",
+				VirtualWorkspaceURL: baseURL,
+			},
+			Status: corev1alpha1.ShardStatus{Conditions: conds},
+		}
+	}
+
+	tests := map[string]struct {
+		shards []corev1alpha1.Shard
+		want   []Status
+	}{
+		"healthy": {
+			shards: []corev1alpha1.Shard{shard("root", "https://root"), shard("beta", "https://beta")},
+			want:   []Status{StatusOK, StatusOK, StatusOK},
+		},
+		"missing URL": {
+			shards: []corev1alpha1.Shard{shard("root", "")},
+			want:   []Status{StatusOK, StatusError},
+		},
+		"invalid URL": {
+			shards: []corev1alpha1.Shard{shard("root", "root:6443")},
+			want:   []Status{StatusOK, StatusError},
+		},
+		"duplicate base URL": {
+			shards: []corev1alpha1.Shard{shard("root", "https://root"), shard("beta", "https://root")},
+			want:   []Status{StatusError, StatusOK, StatusOK},
+		},
+		"not ready": {
+			shards: []corev1alpha1.Shard{shard("root", "https://root", conditionsv1alpha1.Condition{Type: conditionsv1alpha1.ReadyCondition, Status: "False"})},
+			want:   []Status{StatusOK, StatusError},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, statuses(validateShards(tt.shards)))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/clusters/root/version", "/services/readyz":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	root := map[string]string{logicalcluster.AnnotationKey: "root"}
+	objects := []runtime.Object{
+		&corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: root},
+			Status:     corev1alpha1.LogicalClusterStatus{Phase: corev1alpha1.LogicalClusterPhaseReady},
+		},
+		&corev1alpha1.Shard{
+			ObjectMeta: metav1.ObjectMeta{Name: "root", Annotations: root},
+			Spec: corev1alpha1.ShardSpec{
+				BaseURL:             server.URL,
+				ExternalURL:         server.URL,
+				VirtualWorkspaceURL: server.URL + "/services",
+			},
+		},
+		&apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: root},
+			Spec:       apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"v2.widgets.example.io"}},
+		},
+		&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: root},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Name: "widgets"}},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{{Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.example.io"}}},
+				Conditions: conditionsv1alpha1.Conditions{
+					{Type: apisv1alpha1.InitialBindingCompleted, Status: "True"},
+				},
+			},
+		},
+	}
+
+	streams, _, stdout, _ := genericclioptions.NewTestIOStreams()
+	opts := NewDoctorOptions(streams)
+	opts.Output = "json"
+	opts.Timeout = 10 * time.Second
+	opts.config = &rest.Config{Host: server.URL + "/clusters/root"}
+	opts.kcpClusterClient = kcpfakeclient.NewSimpleClientset(objects...)
+	require.NoError(t, opts.Validate())
+	require.NoError(t, opts.Run(context.Background()))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	got := map[string]Status{}
+	for _, c := range report.Checks {
+		got[c.Name] = c.Status
+	}
+	require.Equal(t, map[string]Status{
+		"kubeconfig":     StatusOK,
+		"kubeconfig/tls": StatusWarning,
+		"front-proxy":    StatusOK,
+		"workspace":      StatusOK,
+		"shards":         StatusOK,
+		"shard/root":     StatusOK,
+		"virtual-workspaces/" + server.URL + "/services": StatusOK,
+		"cache-server":         StatusWarning,
+		"workspace-scheduling": StatusSkipped,
+	}, got)
+}
+
+func statuses(checks []Check) []Status {
+	ret := make([]Status, 0, len(checks))
+	for _, c := range checks {
+		ret = append(ret, c.Status)
+	}
+	return ret
+}
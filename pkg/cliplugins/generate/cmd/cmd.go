@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/generate/plugin"
+)
+
+var (
+	vwKubeconfigExample = `
+	# Write a kubeconfig for the virtual workspace of the APIExport "widgets" in root:providers to widgets.kubeconfig.
+	%[1]s generate vw-kubeconfig --apiexport root:providers:widgets -o widgets.kubeconfig
+
+	# Print a kubeconfig with one context per partitioned APIExportEndpointSlice of the APIExport "widgets" in the current workspace.
+	%[1]s generate vw-kubeconfig --apiexport widgets --per-partition
+	`
+)
+
+// New returns a cobra.Command for generating artifacts.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cliName := "kubectl"
+	if pflag.CommandLine.Name() == "kubectl-kcp" {
+		cliName = "kubectl kcp"
+	}
+
+	generateCmd := &cobra.Command{
+		Use:              "generate",
+		Short:            "Generate configuration for working with kcp",
+		SilenceUsage:     true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	vwKubeconfigOpts := plugin.NewVWKubeconfigOptions(streams)
+	vwKubeconfigCmd := &cobra.Command{
+		Use:   "vw-kubeconfig --apiexport <path>:<name>",
+		Short: "Generate a kubeconfig for the virtual workspace of an APIExport",
+		Long: `Generate a kubeconfig for the virtual workspace of an APIExport, e.g. for the controllers of an API provider.
+The virtual workspace URLs are taken from the APIExportEndpointSlices of the APIExport in its workspace, and from
+the status of the APIExport if there are none. There is one context per URL, i.e. per shard. The CA and the
+credentials of the current context are embedded.`,
+		Example:      fmt.Sprintf(vwKubeconfigExample, cliName),
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := vwKubeconfigOpts.Complete(); err != nil {
+				return err
+			}
+			if err := vwKubeconfigOpts.Validate(); err != nil {
+				return err
+			}
+			return vwKubeconfigOpts.Run(cmd.Context())
+		},
+	}
+	vwKubeconfigOpts.BindFlags(vwKubeconfigCmd)
+	generateCmd.AddCommand(vwKubeconfigCmd)
+
+	return generateCmd
+}
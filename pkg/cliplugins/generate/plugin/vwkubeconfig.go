@@ -0,0 +1,293 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// VWKubeconfigOptions contains the options for generating a kubeconfig for the virtual workspace
+// of an APIExport.
+type VWKubeconfigOptions struct {
+	*base.Options
+
+	// APIExport is the APIExport as <path>:<name>, or the name of an APIExport in the current workspace.
+	APIExport string
+	// OutputFile is the file the kubeconfig is written to. Empty means stdout.
+	OutputFile string
+	// PerPartition creates one context per partitioned APIExportEndpointSlice of the APIExport,
+	// instead of one for all endpoints of the APIExport.
+	PerPartition bool
+
+	exportPath       logicalcluster.Path
+	exportName       string
+	config           *rest.Config
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewVWKubeconfigOptions returns a new VWKubeconfigOptions.
+func NewVWKubeconfigOptions(streams genericclioptions.IOStreams) *VWKubeconfigOptions {
+	return &VWKubeconfigOptions{
+		Options: base.NewOptions(streams),
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *VWKubeconfigOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().StringVar(&o.APIExport, "apiexport", o.APIExport, "APIExport as <path>:<name>, or the name of an APIExport in the current workspace")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "o", o.OutputFile, "File to write the kubeconfig to, stdout if empty")
+	cmd.Flags().BoolVar(&o.PerPartition, "per-partition", o.PerPartition, "Create one context per partitioned APIExportEndpointSlice of the APIExport")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *VWKubeconfigOptions) Complete() error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	o.config = config
+
+	_, current, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current URL %q does not point to workspace", config.Host)
+	}
+	o.exportPath, o.exportName = logicalcluster.NewPath(o.APIExport).Split()
+	if o.exportPath.Empty() {
+		o.exportPath = current
+	}
+
+	clusterConfig := rest.CopyConfig(config)
+	u, err := url.Parse(config.Host)
+	if err != nil {
+		return err
+	}
+	u.Path = ""
+	clusterConfig.Host = u.String()
+	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
+	o.kcpClusterClient, err = kcpclientset.NewForConfig(clusterConfig)
+	return err
+}
+
+// Validate validates the VWKubeconfigOptions are complete and usable.
+func (o *VWKubeconfigOptions) Validate() error {
+	if o.APIExport == "" {
+		return errors.New("--apiexport is required")
+	}
+	if !logicalcluster.NewPath(o.APIExport).IsValid() {
+		return fmt.Errorf("invalid APIExport %q, expected <path>:<name>", o.APIExport)
+	}
+	return o.Options.Validate()
+}
+
+// vwEndpoints are the virtual workspace URLs served under one context name.
+type vwEndpoints struct {
+	name string
+	urls []string
+}
+
+// Run writes the kubeconfig.
+func (o *VWKubeconfigOptions) Run(ctx context.Context) error {
+	endpoints, err := o.resolveEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	kubeconfig, err := o.kubeconfigFor(endpoints)
+	if err != nil {
+		return err
+	}
+	bs, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFile == "" {
+		_, err = o.Out.Write(bs)
+		return err
+	}
+	if err := os.WriteFile(o.OutputFile, bs, 0600); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(o.ErrOut, "Wrote kubeconfig for APIExport %s|%s to %s\n", o.exportPath, o.exportName, o.OutputFile)
+	return err
+}
+
+// resolveEndpoints returns the virtual workspace URLs of the APIExport, from its APIExportEndpointSlices
+// if there are any in the workspace of the APIExport, and from its status otherwise.
+func (o *VWKubeconfigOptions) resolveEndpoints(ctx context.Context) ([]vwEndpoints, error) {
+	export, err := o.kcpClusterClient.Cluster(o.exportPath).ApisV1alpha1().APIExports().Get(ctx, o.exportName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	slices, err := o.kcpClusterClient.Cluster(o.exportPath).ApisV1alpha1().APIExportEndpointSlices().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var unpartitioned []string
+	var partitioned []vwEndpoints
+	for _, slice := range slices.Items {
+		ref := slice.Spec.APIExport
+		if ref.Name != export.Name || (ref.Path != "" && ref.Path != o.exportPath.String()) {
+			continue
+		}
+		urls := endpointURLs(slice.Status.APIExportEndpoints)
+		if slice.Spec.Partition == "" {
+			unpartitioned = append(unpartitioned, urls...)
+			continue
+		}
+		partitioned = append(partitioned, vwEndpoints{name: export.Name + "-" + slice.Spec.Partition, urls: urls})
+	}
+
+	if o.PerPartition {
+		if len(partitioned) == 0 {
+			return nil, fmt.Errorf("no partitioned APIExportEndpointSlices found for APIExport %s|%s", o.exportPath, export.Name)
+		}
+		sort.Slice(partitioned, func(i, j int) bool { return partitioned[i].name < partitioned[j].name })
+		for _, p := range partitioned {
+			if len(p.urls) == 0 {
+				return nil, fmt.Errorf("APIExportEndpointSlice for %s has no endpoints yet", p.name)
+			}
+		}
+		return partitioned, nil
+	}
+
+	urls := unpartitioned
+	if len(urls) == 0 {
+		for _, vw := range export.Status.VirtualWorkspaces {
+			urls = append(urls, vw.URL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("APIExport %s|%s has no virtual workspace URLs yet", o.exportPath, export.Name)
+	}
+	return []vwEndpoints{{name: export.Name, urls: dedup(urls)}}, nil
+}
+
+// kubeconfigFor returns a kubeconfig with a cluster and context per URL, using the CA and the
+// credentials of the current context, embedded into the kubeconfig.
+func (o *VWKubeconfigOptions) kubeconfigFor(endpoints []vwEndpoints) (*clientcmdapi.Config, error) {
+	authInfo, err := embeddedAuthInfo(o.config)
+	if err != nil {
+		return nil, err
+	}
+	caData := o.config.CAData
+	if len(caData) == 0 && o.config.CAFile != "" {
+		if caData, err = os.ReadFile(o.config.CAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.AuthInfos[o.exportName] = authInfo
+	for _, e := range endpoints {
+		for i, u := range e.urls {
+			name := e.name
+			if len(e.urls) > 1 {
+				// one per shard
+				name = fmt.Sprintf("%s-%d", e.name, i)
+			}
+			kubeconfig.Clusters[name] = &clientcmdapi.Cluster{
+				Server:                   u,
+				CertificateAuthorityData: caData,
+				InsecureSkipTLSVerify:    o.config.Insecure,
+				TLSServerName:            o.config.ServerName,
+			}
+			kubeconfig.Contexts[name] = &clientcmdapi.Context{
+				Cluster:  name,
+				AuthInfo: o.exportName,
+			}
+			if kubeconfig.CurrentContext == "" {
+				kubeconfig.CurrentContext = name
+			}
+		}
+	}
+	return kubeconfig, nil
+}
+
+// embeddedAuthInfo returns the credentials of the given config with the files read into the kubeconfig.
+func embeddedAuthInfo(config *rest.Config) (*clientcmdapi.AuthInfo, error) {
+	authInfo := &clientcmdapi.AuthInfo{
+		ClientCertificateData: config.CertData,
+		ClientKeyData:         config.KeyData,
+		Token:                 config.BearerToken,
+		Username:              config.Username,
+		Password:              config.Password,
+		Exec:                  config.ExecProvider,
+		AuthProvider:          config.AuthProvider,
+	}
+	var err error
+	if len(authInfo.ClientCertificateData) == 0 && config.CertFile != "" {
+		if authInfo.ClientCertificateData, err = os.ReadFile(config.CertFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(authInfo.ClientKeyData) == 0 && config.KeyFile != "" {
+		if authInfo.ClientKeyData, err = os.ReadFile(config.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if authInfo.Token == "" && config.BearerTokenFile != "" {
+		// keep the file, the token might be rotated.
+		authInfo.TokenFile = config.BearerTokenFile
+	}
+	return authInfo, nil
+}
+
+func endpointURLs(endpoints []apisv1alpha1.APIExportEndpoint) []string {
+	urls := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		urls = append(urls, e.URL)
+	}
+	return urls
+}
+
+func dedup(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	ret := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if !seen[u] {
+			seen[u] = true
+			ret = append(ret, u)
+		}
+	}
+	return ret
+}
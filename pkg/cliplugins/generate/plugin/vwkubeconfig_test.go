@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestVWKubeconfig(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:providers"}},
+		Status: apisv1alpha1.APIExportStatus{
+			VirtualWorkspaces: []apisv1alpha1.VirtualWorkspace{{URL: "https://shard-1/services/apiexport/c-providers/widgets"}},
+		},
+	}
+	slice := func(name, export, partition string, urls ...string) *apisv1alpha1.APIExportEndpointSlice {
+		s := &apisv1alpha1.APIExportEndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root:providers"}},
+			Spec: apisv1alpha1.APIExportEndpointSliceSpec{
+				APIExport: apisv1alpha1.ExportBindingReference{Name: export},
+				Partition: partition,
+			},
+		}
+		for _, u := range urls {
+			s.Status.APIExportEndpoints = append(s.Status.APIExportEndpoints, apisv1alpha1.APIExportEndpoint{URL: u})
+		}
+		return s
+	}
+
+	tests := map[string]struct {
+		objects      []runtime.Object
+		perPartition bool
+
+		wantServers map[string]string
+		wantCurrent string
+		wantErr     string
+	}{
+		"from APIExport status": {
+			objects:     []runtime.Object{export, slice("other", "gadgets", "", "https://shard-1/services/apiexport/c-providers/gadgets")},
+			wantServers: map[string]string{"widgets": "https://shard-1/services/apiexport/c-providers/widgets"},
+			wantCurrent: "widgets",
+		},
+		"from endpoint slice, one context per shard": {
+			objects: []runtime.Object{export,
+				slice("widgets", "widgets", "", "https://shard-1/services/apiexport/c-providers/widgets", "https://shard-2/services/apiexport/c-providers/widgets"),
+				slice("widgets-eu", "widgets", "eu", "https://shard-2/services/apiexport/c-providers/widgets"),
+			},
+			wantServers: map[string]string{
+				"widgets-0": "https://shard-1/services/apiexport/c-providers/widgets",
+				"widgets-1": "https://shard-2/services/apiexport/c-providers/widgets",
+			},
+			wantCurrent: "widgets-0",
+		},
+		"per partition": {
+			objects: []runtime.Object{export,
+				slice("widgets", "widgets", "", "https://shard-1/services/apiexport/c-providers/widgets"),
+				slice("widgets-us", "widgets", "us", "https://shard-1/services/apiexport/c-providers/widgets"),
+				slice("widgets-eu", "widgets", "eu", "https://shard-2/services/apiexport/c-providers/widgets"),
+			},
+			perPartition: true,
+			wantServers: map[string]string{
+				"widgets-eu": "https://shard-2/services/apiexport/c-providers/widgets",
+				"widgets-us": "https://shard-1/services/apiexport/c-providers/widgets",
+			},
+			wantCurrent: "widgets-eu",
+		},
+		"per partition without partitions": {
+			objects:      []runtime.Object{export},
+			perPartition: true,
+			wantErr:      "no partitioned APIExportEndpointSlices found",
+		},
+		"no URLs yet": {
+			objects: []runtime.Object{&apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:providers"}},
+			}},
+			wantErr: "has no virtual workspace URLs yet",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			caFile := filepath.Join(t.TempDir(), "ca.crt")
+			require.NoError(t, os.WriteFile(caFile, []byte("ca-data"), 0600))
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			opts := NewVWKubeconfigOptions(streams)
+			opts.PerPartition = tt.perPartition
+			opts.exportPath = logicalcluster.NewPath("root:providers")
+			opts.exportName = "widgets"
+			opts.config = &rest.Config{
+				Host:            "https://front-proxy/clusters/root:providers",
+				TLSClientConfig: rest.TLSClientConfig{CAFile: caFile},
+				BearerToken:     "token",
+			}
+			opts.kcpClusterClient = kcpfakeclient.NewSimpleClientset(tt.objects...)
+
+			err := opts.Run(context.Background())
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			kubeconfig, err := clientcmd.Load(out.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCurrent, kubeconfig.CurrentContext)
+			servers := map[string]string{}
+			for name, context := range kubeconfig.Contexts {
+				cluster := kubeconfig.Clusters[context.Cluster]
+				servers[name] = cluster.Server
+				require.Equal(t, "ca-data", string(cluster.CertificateAuthorityData))
+				require.Equal(t, "token", kubeconfig.AuthInfos[context.AuthInfo].Token)
+			}
+			require.Equal(t, tt.wantServers, servers)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Bundle collects the files of a gzipped tarball, e.g. a support bundle, in memory.
+type Bundle struct {
+	dir   string
+	files map[string][]byte
+	errs  []string
+}
+
+// NewBundle returns a bundle with its files in the given directory.
+func NewBundle(dir string) *Bundle {
+	return &Bundle{
+		dir:   dir,
+		files: map[string][]byte{},
+	}
+}
+
+// Add adds a file.
+func (b *Bundle) Add(name string, data []byte) {
+	b.files[path.Join(b.dir, name)] = data
+}
+
+// AddYAML adds a file with the given object serialized as YAML.
+func (b *Bundle) AddYAML(name string, obj interface{}) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.Errorf("serializing %s: %v", name, err)
+		return
+	}
+	b.Add(name, data)
+}
+
+// Errorf records a part that could not be collected.
+func (b *Bundle) Errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Sprintf(format, args...))
+}
+
+// Errors returns the parts that could not be collected.
+func (b *Bundle) Errors() []string {
+	return b.errs
+}
+
+// Write writes the files as gzipped tarball.
+func (b *Bundle) Write(fileName string, modTime time.Time) error {
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(b.files[name])),
+			ModTime: modTime,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(b.files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
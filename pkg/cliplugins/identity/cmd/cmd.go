@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/identity/plugin"
+)
+
+var (
+	restoreExample = `
+	# Restore the deleted identity secret of the APIExport "widgets" in the current workspace.
+	%[1]s identity restore widgets --store-url file:///var/lib/kcp/identities --encryption-key-file escrow.key
+	`
+)
+
+// New returns a cobra.Command for APIExport identity related actions.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cliName := "kubectl"
+	if pflag.CommandLine.Name() == "kubectl-kcp" {
+		cliName = "kubectl kcp"
+	}
+
+	identityCmd := &cobra.Command{
+		Use:              "identity",
+		Short:            "Operations related to APIExport identities",
+		SilenceUsage:     true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	restoreOpts := plugin.NewRestoreOptions(streams)
+	restoreCmd := &cobra.Command{
+		Use:   "restore <apiexport_name>",
+		Short: "Restore the identity secret of an APIExport from the identity escrow",
+		Long: `Restore the identity secret of an APIExport in the current workspace from the identity escrow
+the shards keep encrypted copies of the identities in. Without its identity secret, an APIExport cannot
+serve the objects of its APIBindings anymore. The escrowed identity must match the identity hash in the
+status of the APIExport.`,
+		Example:      fmt.Sprintf(restoreExample, cliName),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := restoreOpts.Complete(args); err != nil {
+				return err
+			}
+			if err := restoreOpts.Validate(); err != nil {
+				return err
+			}
+			return restoreOpts.Run(cmd.Context())
+		},
+	}
+	restoreOpts.BindFlags(restoreCmd)
+	identityCmd.AddCommand(restoreCmd)
+
+	return identityCmd
+}
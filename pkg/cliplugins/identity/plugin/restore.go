@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// RestoreOptions contains the options for restoring the identity secret of an APIExport from the identity escrow.
+type RestoreOptions struct {
+	*base.Options
+
+	// APIExportName is the name of the APIExport in the current workspace whose identity is restored.
+	APIExportName string
+	// StoreURL is the URL of the identity escrow store, as configured on the shard.
+	StoreURL string
+	// EncryptionKeyFile is the file with the key the identities are encrypted with, as configured on the shard.
+	EncryptionKeyFile string
+
+	workspace         logicalcluster.Path
+	kcpClusterClient  kcpclientset.ClusterInterface
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+	escrow            *identityescrow.Escrow
+}
+
+// NewRestoreOptions returns a new RestoreOptions.
+func NewRestoreOptions(streams genericclioptions.IOStreams) *RestoreOptions {
+	return &RestoreOptions{
+		Options: base.NewOptions(streams),
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *RestoreOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().StringVar(&o.StoreURL, "store-url", o.StoreURL, "URL of the identity escrow store, i.e. the --identity-escrow-store-url of the shard")
+	cmd.Flags().StringVar(&o.EncryptionKeyFile, "encryption-key-file", o.EncryptionKeyFile, "File with the encryption key of the identity escrow, i.e. the --identity-escrow-encryption-key-file of the shard")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *RestoreOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.APIExportName = args[0]
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, o.workspace, err = pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current URL %q does not point to workspace", config.Host)
+	}
+
+	clusterConfig := rest.CopyConfig(config)
+	u, err := url.Parse(config.Host)
+	if err != nil {
+		return err
+	}
+	u.Path = ""
+	clusterConfig.Host = u.String()
+	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
+	if o.kcpClusterClient, err = kcpclientset.NewForConfig(clusterConfig); err != nil {
+		return err
+	}
+	if o.kubeClusterClient, err = kcpkubernetesclientset.NewForConfig(clusterConfig); err != nil {
+		return err
+	}
+
+	if o.StoreURL == "" || o.EncryptionKeyFile == "" {
+		// reported by Validate
+		return nil
+	}
+	store, err := identityescrow.NewStoreForURL(o.StoreURL)
+	if err != nil {
+		return err
+	}
+	encryptionKey, err := identityescrow.LoadEncryptionKey(o.EncryptionKeyFile)
+	if err != nil {
+		return err
+	}
+	o.escrow, err = identityescrow.New(store, encryptionKey)
+	return err
+}
+
+// Validate validates the RestoreOptions are complete and usable.
+func (o *RestoreOptions) Validate() error {
+	var errs []error
+
+	if err := o.Options.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if o.APIExportName == "" {
+		errs = append(errs, errors.New("APIExport name is required"))
+	}
+	if o.StoreURL == "" {
+		errs = append(errs, errors.New("--store-url is required"))
+	}
+	if o.EncryptionKeyFile == "" {
+		errs = append(errs, errors.New("--encryption-key-file is required"))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// Run restores the identity secret of the APIExport from the escrow.
+func (o *RestoreOptions) Run(ctx context.Context) error {
+	apiExport, err := o.kcpClusterClient.Cluster(o.workspace).ApisV1alpha1().APIExports().Get(ctx, o.APIExportName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting APIExport %s|%s: %w", o.workspace, o.APIExportName, err)
+	}
+
+	clusterName := logicalcluster.From(apiExport)
+	record, err := o.escrow.Get(ctx, clusterName, apiExport.Name)
+	if errors.Is(err, identityescrow.ErrNotFound) {
+		return fmt.Errorf("no identity of APIExport %s|%s in the escrow", o.workspace, apiExport.Name)
+	} else if err != nil {
+		return err
+	}
+	if apiExport.Status.IdentityHash != "" && apiExport.Status.IdentityHash != record.IdentityHash {
+		return fmt.Errorf("escrowed identity hash %q of APIExport %s|%s does not match status.identityHash %q", record.IdentityHash, o.workspace, apiExport.Name, apiExport.Status.IdentityHash)
+	}
+
+	// The APIExport is authoritative for where the secret is expected.
+	namespace, name := record.SecretNamespace, record.SecretName
+	if apiExport.Spec.Identity != nil && apiExport.Spec.Identity.SecretRef != nil {
+		namespace, name = apiExport.Spec.Identity.SecretRef.Namespace, apiExport.Spec.Identity.SecretRef.Name
+	}
+
+	kubeClient := o.kubeClusterClient.Cluster(o.workspace)
+	existing, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		if fmt.Sprintf("%x", sha256.Sum256(existing.Data[apisv1alpha1.SecretKeyAPIExportIdentity])) == record.IdentityHash {
+			_, err := fmt.Fprintf(o.Out, "Identity secret %s/%s of APIExport %s|%s is intact, nothing to restore.\n", namespace, name, o.workspace, apiExport.Name)
+			return err
+		}
+		return fmt.Errorf("identity secret %s/%s of APIExport %s|%s exists with a different identity, delete it first to restore the escrowed identity", namespace, name, o.workspace, apiExport.Name)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating namespace %s: %w", namespace, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Data: map[string][]byte{
+			apisv1alpha1.SecretKeyAPIExportIdentity: record.Key,
+		},
+	}
+	if _, err := kubeClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating identity secret %s/%s: %w", namespace, name, err)
+	}
+
+	_, err = fmt.Fprintf(o.Out, "Restored identity secret %s/%s of APIExport %s|%s with identity hash %s.\n", namespace, name, o.workspace, apiExport.Name, record.IdentityHash)
+	return err
+}
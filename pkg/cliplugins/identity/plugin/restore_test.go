@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestRestore(t *testing.T) {
+	hash := func(key string) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(key))) }
+	newAPIExport := func(identityHash string) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			Spec: apisv1alpha1.APIExportSpec{
+				Identity: &apisv1alpha1.Identity{
+					SecretRef: &corev1.SecretReference{Namespace: "kcp-system", Name: "widgets"},
+				},
+			},
+			Status: apisv1alpha1.APIExportStatus{IdentityHash: identityHash},
+		}
+	}
+	newSecret := func(key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "kcp-system",
+				Name:        "widgets",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			Data: map[string][]byte{apisv1alpha1.SecretKeyAPIExportIdentity: []byte(key)},
+		}
+	}
+
+	tests := map[string]struct {
+		apiExport   *apisv1alpha1.APIExport
+		secret      *corev1.Secret
+		escrowedKey string
+		wantErr     string
+		wantOut     string
+		wantKey     string
+	}{
+		"restores deleted secret": {
+			apiExport:   newAPIExport(hash("abc")),
+			escrowedKey: "abc",
+			wantOut:     "Restored identity secret kcp-system/widgets",
+			wantKey:     "abc",
+		},
+		"restores secret of APIExport without hash": {
+			apiExport:   newAPIExport(""),
+			escrowedKey: "abc",
+			wantOut:     "Restored identity secret kcp-system/widgets",
+			wantKey:     "abc",
+		},
+		"intact secret": {
+			apiExport:   newAPIExport(hash("abc")),
+			secret:      newSecret("abc"),
+			escrowedKey: "abc",
+			wantOut:     "is intact",
+			wantKey:     "abc",
+		},
+		"secret with other identity": {
+			apiExport:   newAPIExport(hash("abc")),
+			secret:      newSecret("def"),
+			escrowedKey: "abc",
+			wantErr:     "exists with a different identity",
+			wantKey:     "def",
+		},
+		"escrowed identity does not match": {
+			apiExport:   newAPIExport(hash("def")),
+			escrowedKey: "abc",
+			wantErr:     "does not match status.identityHash",
+		},
+		"not escrowed": {
+			apiExport: newAPIExport(hash("abc")),
+			wantErr:   "no identity of APIExport root:org|widgets in the escrow",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			escrow, err := identityescrow.New(identityescrow.NewFileStore(t.TempDir()), bytes.Repeat([]byte{1}, identityescrow.EncryptionKeySize))
+			require.NoError(t, err)
+			if tt.escrowedKey != "" {
+				require.NoError(t, escrow.Put(ctx, &identityescrow.Record{
+					Cluster:         "root:org",
+					APIExport:       "widgets",
+					SecretNamespace: "kcp-system",
+					SecretName:      "widgets",
+					IdentityHash:    hash(tt.escrowedKey),
+					Key:             []byte(tt.escrowedKey),
+				}))
+			}
+
+			var kubeObjects []runtime.Object
+			if tt.secret != nil {
+				kubeObjects = append(kubeObjects, tt.secret)
+			}
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			opts := NewRestoreOptions(streams)
+			opts.APIExportName = "widgets"
+			opts.workspace = logicalcluster.NewPath("root:org")
+			opts.kcpClusterClient = kcpfakeclient.NewSimpleClientset(tt.apiExport)
+			opts.kubeClusterClient = kcpfakekubeclient.NewSimpleClientset(kubeObjects...)
+			opts.escrow = escrow
+
+			err = opts.Run(ctx)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out.String(), tt.wantOut)
+			}
+
+			secret, err := opts.kubeClusterClient.Cluster(opts.workspace).CoreV1().Secrets("kcp-system").Get(ctx, "widgets", metav1.GetOptions{})
+			if tt.wantKey == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantKey, string(secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity]))
+		})
+	}
+}
@@ -58,6 +58,13 @@ var (
 
 	# create a context with the current workspace, named context-name
 	%[1]s workspace create-context context-name
+
+	# collect the objects, events, bindings and routing information of the current workspace into a
+	# tarball for bug reports, with secret data redacted
+	%[1]s workspace dump -f bundle.tar.gz
+
+	# restore a deleted workspace of the current workspace while it is in the Trashed phase
+	%[1]s workspace restore my-workspace
 `
 )
 
@@ -72,7 +79,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 
 	cmd := &cobra.Command{
 		Aliases:          []string{"ws", "workspaces"},
-		Use:              "workspace [create|create-context|use|current|<workspace>|..|.|-|~|<root:absolute:workspace>]",
+		Use:              "workspace [create|create-context|use|current|restore|<workspace>|..|.|-|~|<root:absolute:workspace>]",
 		Short:            "Manages KCP workspaces",
 		Example:          fmt.Sprintf(workspaceExample, cliName),
 		SilenceUsage:     true,
@@ -137,7 +144,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 	createCmd := &cobra.Command{
 		Use:          "create",
 		Short:        "Creates a new workspace",
-		Example:      "kcp workspace create <workspace name> [--type=<type>] [--enter [--ignore-not-ready]] --ignore-existing",
+		Example:      "kcp workspace create <workspace name> [--type=<type>] [--template=<template>] [--enter [--ignore-not-ready]] --ignore-existing",
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -178,18 +185,20 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 
 	treeCmdOpts := plugin.NewTreeOptions(streams)
 	treeCmd := &cobra.Command{
-		Use:          "tree",
-		Short:        "Print the current workspace tree.",
-		Example:      "kcp workspace tree",
+		Use:   "tree [<workspace>]",
+		Short: "Print the workspace tree under the current or the given workspace, with type, phase and shard of the workspaces.",
+		Example: `kcp workspace tree
+kcp workspace tree root:org --depth 2
+kcp workspace tree -o json`,
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
-			if len(args) != 0 {
+			if len(args) > 1 {
 				return cmd.Help()
 			}
-			if err := treeCmdOpts.Validate(); err != nil {
+			if err := treeCmdOpts.Complete(args); err != nil {
 				return err
 			}
-			if err := treeCmdOpts.Complete(); err != nil {
+			if err := treeCmdOpts.Validate(); err != nil {
 				return err
 			}
 			return treeCmdOpts.Run(c.Context())
@@ -197,10 +206,54 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 	}
 	treeCmdOpts.BindFlags(treeCmd)
 
+	dumpOpts := plugin.NewDumpOptions(streams)
+	dumpCmd := &cobra.Command{
+		Use:          "dump",
+		Short:        "Collects a support bundle of the current workspace, with secret data redacted.",
+		Example:      "kcp workspace dump --events-since=30m -f bundle.tar.gz",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return c.Help()
+			}
+			if err := dumpOpts.Complete(); err != nil {
+				return err
+			}
+			if err := dumpOpts.Validate(); err != nil {
+				return err
+			}
+			return dumpOpts.Run(c.Context())
+		},
+	}
+	dumpOpts.BindFlags(dumpCmd)
+
+	restoreOpts := plugin.NewRestoreWorkspaceOptions(streams)
+	restoreCmd := &cobra.Command{
+		Use:          "restore <workspace>",
+		Short:        "Restores a deleted workspace of the current workspace that is still in the Trashed phase.",
+		Example:      "kcp workspace restore my-workspace",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return c.Help()
+			}
+			if err := restoreOpts.Complete(args); err != nil {
+				return err
+			}
+			if err := restoreOpts.Validate(); err != nil {
+				return err
+			}
+			return restoreOpts.Run(c.Context())
+		},
+	}
+	restoreOpts.BindFlags(restoreCmd)
+
 	cmd.AddCommand(useCmd)
 	cmd.AddCommand(treeCmd)
 	cmd.AddCommand(currentCmd)
 	cmd.AddCommand(createCmd)
 	cmd.AddCommand(createContextCmd)
+	cmd.AddCommand(dumpCmd)
+	cmd.AddCommand(restoreCmd)
 	return cmd, nil
 }
@@ -0,0 +1,434 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// redactedValue replaces confidential values in a support bundle.
+const redactedValue = "REDACTED"
+
+// DumpOptions contains options for collecting a support bundle of the current workspace.
+type DumpOptions struct {
+	*base.Options
+
+	// OutputFile is the path of the gzipped tarball to write. It defaults to a file named after the
+	// workspace and the time of the dump in the working directory.
+	OutputFile string
+	// EventsSince restricts the events in the bundle to those that occurred within this duration.
+	EventsSince time.Duration
+
+	workspace        logicalcluster.Path
+	kcpClusterClient kcpclientset.ClusterInterface
+	dynamicClient    dynamic.Interface
+	discoveryClient  discovery.DiscoveryInterface
+	now              func() time.Time
+}
+
+// NewDumpOptions returns a new DumpOptions.
+func NewDumpOptions(streams genericclioptions.IOStreams) *DumpOptions {
+	return &DumpOptions{
+		Options:     base.NewOptions(streams),
+		EventsSince: time.Hour,
+		now:         time.Now,
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *DumpOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "f", o.OutputFile, "Path of the gzipped tarball to write. Defaults to <workspace>-dump-<time>.tar.gz in the working directory")
+	cmd.Flags().DurationVar(&o.EventsSince, "events-since", o.EventsSince, "Only include events that occurred within this duration")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *DumpOptions) Complete() error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, o.workspace, err = pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
+	}
+
+	if o.dynamicClient, err = dynamic.NewForConfig(config); err != nil {
+		return err
+	}
+	if o.discoveryClient, err = discovery.NewDiscoveryClientForConfig(config); err != nil {
+		return err
+	}
+	if o.kcpClusterClient, err = newKCPClusterClient(o.ClientConfig); err != nil {
+		return err
+	}
+
+	if o.OutputFile == "" {
+		o.OutputFile = fmt.Sprintf("%s-dump-%s.tar.gz", strings.ReplaceAll(o.workspace.String(), ":", "_"), o.now().UTC().Format("20060102-150405"))
+	}
+
+	return nil
+}
+
+// Validate validates the DumpOptions are complete and usable.
+func (o *DumpOptions) Validate() error {
+	if err := o.Options.Validate(); err != nil {
+		return err
+	}
+	if o.EventsSince < 0 {
+		return fmt.Errorf("--events-since must not be negative")
+	}
+	return nil
+}
+
+// Run collects the support bundle and writes it to the output file. Parts that cannot be collected,
+// e.g. for lack of permissions, are listed in errors.txt of the bundle instead of failing the dump.
+func (o *DumpOptions) Run(ctx context.Context) error {
+	b := pluginhelpers.NewBundle(strings.ReplaceAll(o.workspace.String(), ":", "_"))
+
+	routing := o.dumpWorkspace(ctx, b)
+	resources := o.dumpResources(ctx, b)
+	o.dumpAPIExports(ctx, b, routing)
+	o.dumpShards(ctx, b, routing)
+	b.AddYAML("routing.yaml", routing)
+	b.AddYAML("summary.yaml", &dumpSummary{
+		Workspace:   o.workspace.String(),
+		CollectedAt: metav1.NewTime(o.now()),
+		EventsSince: metav1.Duration{Duration: o.EventsSince},
+		Resources:   resources,
+		Errors:      len(b.Errors()),
+	})
+	if len(b.Errors()) > 0 {
+		b.Add("errors.txt", []byte(strings.Join(b.Errors(), "\n")+"\n"))
+	}
+
+	if err := b.Write(o.OutputFile, o.now()); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(o.Out, "Wrote support bundle of workspace %q to %s.\n", o.workspace, o.OutputFile); err != nil {
+		return err
+	}
+	if len(b.Errors()) > 0 {
+		if _, err := fmt.Fprintf(o.ErrOut, "%d parts could not be collected, see errors.txt in the bundle.\n", len(b.Errors())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpSummary describes a support bundle.
+type dumpSummary struct {
+	Workspace   string          `json:"workspace"`
+	CollectedAt metav1.Time     `json:"collectedAt"`
+	EventsSince metav1.Duration `json:"eventsSince"`
+	// Resources is the number of objects per resource, in <resource>.<group> notation.
+	Resources map[string]int `json:"resources"`
+	// Errors is the number of parts that could not be collected.
+	Errors int `json:"errors"`
+}
+
+// dumpRouting describes where the workspace and the virtual workspaces of its APIs are served.
+type dumpRouting struct {
+	Workspace  string       `json:"workspace"`
+	Type       string       `json:"type,omitempty"`
+	Cluster    string       `json:"cluster,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	Shards     []dumpShard  `json:"shards,omitempty"`
+	APIExports []dumpExport `json:"apiExports,omitempty"`
+}
+
+type dumpShard struct {
+	Name                string `json:"name"`
+	BaseURL             string `json:"baseURL"`
+	ExternalURL         string `json:"externalURL,omitempty"`
+	VirtualWorkspaceURL string `json:"virtualWorkspaceURL,omitempty"`
+}
+
+type dumpExport struct {
+	// APIExport is the APIExport in <path>:<name> notation.
+	APIExport         string   `json:"apiExport"`
+	IdentityHash      string   `json:"identityHash,omitempty"`
+	VirtualWorkspaces []string `json:"virtualWorkspaces,omitempty"`
+}
+
+// dumpWorkspace adds the Workspace object of the current workspace from its parent.
+func (o *DumpOptions) dumpWorkspace(ctx context.Context, b *pluginhelpers.Bundle) *dumpRouting {
+	routing := &dumpRouting{Workspace: o.workspace.String()}
+
+	parent, hasParent := o.workspace.Parent()
+	if !hasParent {
+		return routing
+	}
+	ws, err := o.kcpClusterClient.Cluster(parent).TenancyV1alpha1().Workspaces().Get(ctx, o.workspace.Base(), metav1.GetOptions{})
+	if err != nil {
+		b.Errorf("getting workspace %s in %s: %v", o.workspace.Base(), parent, err)
+		return routing
+	}
+	ws.APIVersion, ws.Kind = "tenancy.kcp.io/v1alpha1", "Workspace"
+	ws.ManagedFields = nil
+	b.AddYAML("workspace.yaml", ws)
+
+	routing.Type = logicalcluster.NewPath(ws.Spec.Type.Path).Join(string(ws.Spec.Type.Name)).String()
+	routing.Cluster = ws.Spec.Cluster
+	routing.URL = ws.Spec.URL
+	return routing
+}
+
+// dumpResources adds all objects of the workspace, one file per resource, and the recent events.
+// It returns the number of objects per resource.
+func (o *DumpOptions) dumpResources(ctx context.Context, b *pluginhelpers.Bundle) map[string]int {
+	counts := map[string]int{}
+
+	lists, err := discovery.ServerPreferredResources(o.discoveryClient)
+	if err != nil {
+		// partial results are returned for the groups that could be discovered
+		b.Errorf("discovering resources: %v", err)
+	}
+
+	var conditions []string
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			b.Errorf("parsing group version %q: %v", list.GroupVersion, err)
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !sets.NewString(r.Verbs...).Has("list") {
+				continue
+			}
+			gvr := gv.WithResource(r.Name)
+			if gvr.GroupResource() == (schema.GroupResource{Group: "events.k8s.io", Resource: "events"}) {
+				continue // the same events as in the core group
+			}
+
+			objs, err := o.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				b.Errorf("listing %s: %v", gvr.GroupResource(), err)
+				continue
+			}
+
+			fileName := path.Join("resources", gvr.GroupResource().String()+".yaml")
+			if gvr.GroupResource() == (schema.GroupResource{Resource: "events"}) {
+				objs.Items = recentEvents(objs.Items, o.now().Add(-o.EventsSince))
+				fileName = "events.yaml"
+			}
+			if len(objs.Items) == 0 {
+				continue
+			}
+
+			for i := range objs.Items {
+				redact(&objs.Items[i])
+				conditions = append(conditions, objectConditions(gvr.GroupResource(), &objs.Items[i])...)
+			}
+			counts[gvr.GroupResource().String()] = len(objs.Items)
+			b.AddYAML(fileName, objs.UnstructuredContent())
+		}
+	}
+
+	if len(conditions) > 0 {
+		var buf bytes.Buffer
+		w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "OBJECT\tTYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, c := range conditions {
+			fmt.Fprintln(w, c)
+		}
+		w.Flush()
+		b.Add("conditions.txt", buf.Bytes())
+	}
+
+	return counts
+}
+
+// dumpAPIExports adds the APIExports bound in the workspace, and records the virtual workspaces of
+// these and of the APIExports of the workspace.
+func (o *DumpOptions) dumpAPIExports(ctx context.Context, b *pluginhelpers.Bundle, routing *dumpRouting) {
+	exports, err := o.kcpClusterClient.Cluster(o.workspace).ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing APIExports: %v", err)
+	} else {
+		for i := range exports.Items {
+			routing.APIExports = append(routing.APIExports, newDumpExport(o.workspace, &exports.Items[i]))
+		}
+	}
+
+	bindings, err := o.kcpClusterClient.Cluster(o.workspace).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing APIBindings: %v", err)
+		return
+	}
+	for _, binding := range bindings.Items {
+		ref := binding.Spec.Reference.Export
+		if ref == nil {
+			continue
+		}
+		exportPath := logicalcluster.NewPath(ref.Path)
+		if exportPath.Empty() {
+			exportPath = o.workspace
+		}
+		if exportPath == o.workspace {
+			continue // already in the resources
+		}
+
+		export, err := o.kcpClusterClient.Cluster(exportPath).ApisV1alpha1().APIExports().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			b.Errorf("getting APIExport %s:%s of APIBinding %s: %v", exportPath, ref.Name, binding.Name, err)
+			continue
+		}
+		export.APIVersion, export.Kind = apisv1alpha1.SchemeGroupVersion.String(), "APIExport"
+		export.ManagedFields = nil
+		b.AddYAML(path.Join("apiexports", strings.ReplaceAll(exportPath.String(), ":", "_"), ref.Name+".yaml"), export)
+		routing.APIExports = append(routing.APIExports, newDumpExport(exportPath, export))
+	}
+}
+
+func newDumpExport(exportPath logicalcluster.Path, export *apisv1alpha1.APIExport) dumpExport {
+	ret := dumpExport{
+		APIExport:    exportPath.Join(export.Name).String(),
+		IdentityHash: export.Status.IdentityHash,
+	}
+	for _, vw := range export.Status.VirtualWorkspaces { //nolint:staticcheck // still the only place without APIExportEndpointSlices
+		ret.VirtualWorkspaces = append(ret.VirtualWorkspaces, vw.URL)
+	}
+	return ret
+}
+
+// dumpShards records the shards of the deployment. Usually, only administrators can list them.
+func (o *DumpOptions) dumpShards(ctx context.Context, b *pluginhelpers.Bundle, routing *dumpRouting) {
+	shards, err := o.kcpClusterClient.Cluster(core.RootCluster.Path()).CoreV1alpha1().Shards().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.Errorf("listing shards: %v", err)
+		return
+	}
+	for _, shard := range shards.Items {
+		routing.Shards = append(routing.Shards, dumpShard{
+			Name:                shard.Name,
+			BaseURL:             shard.Spec.BaseURL,
+			ExternalURL:         shard.Spec.ExternalURL,
+			VirtualWorkspaceURL: shard.Spec.VirtualWorkspaceURL,
+		})
+	}
+}
+
+// redact removes confidential data from an object before it is added to a support bundle: the
+// values of secrets, and their last applied configuration, which contains the values too. Managed
+// fields are removed as noise.
+func redact(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+
+	if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Kind: "Secret"}) {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, found, err := unstructured.NestedMap(obj.Object, field)
+		if err != nil || !found {
+			continue
+		}
+		for key := range values {
+			values[key] = redactedValue
+		}
+		unstructured.SetNestedMap(obj.Object, values, field) //nolint:errcheck
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, found := annotations["kubectl.kubernetes.io/last-applied-configuration"]; found {
+			annotations["kubectl.kubernetes.io/last-applied-configuration"] = redactedValue
+			obj.SetAnnotations(annotations)
+		}
+	}
+}
+
+// recentEvents returns the events that last occurred at or after since, oldest first.
+func recentEvents(events []unstructured.Unstructured, since time.Time) []unstructured.Unstructured {
+	var ret []unstructured.Unstructured
+	for _, event := range events {
+		if !eventTime(&event).Before(since) {
+			ret = append(ret, event)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return eventTime(&ret[i]).Before(eventTime(&ret[j]))
+	})
+	return ret
+}
+
+// eventTime returns the time a core event last occurred.
+func eventTime(event *unstructured.Unstructured) time.Time {
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		value, found, err := unstructured.NestedString(event.Object, field)
+		if err != nil || !found || value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return event.GetCreationTimestamp().Time
+}
+
+// objectConditions returns the conditions of an object as tab separated lines.
+func objectConditions(gr schema.GroupResource, obj *unstructured.Unstructured) []string {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil
+	}
+
+	name := gr.String() + "/" + obj.GetName()
+	if ns := obj.GetNamespace(); ns != "" {
+		name = gr.String() + "/" + ns + "/" + obj.GetName()
+	}
+	var ret []string
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := func(name string) string {
+			value, _ := condition[name].(string)
+			return value
+		}
+		ret = append(ret, strings.Join([]string{name, field("type"), field("status"), field("reason"), strings.ReplaceAll(field("message"), "\n", " ")}, "\t"))
+	}
+	return ret
+}
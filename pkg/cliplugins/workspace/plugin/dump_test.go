@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestRedact(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":          "creds",
+			"annotations":   map[string]interface{}{"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"c2VjcmV0"}}`, "other": "kept"},
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+		"stringData": map[string]interface{}{"token": "secret"},
+	}}
+	redact(secret)
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":        "creds",
+			"annotations": map[string]interface{}{"kubectl.kubernetes.io/last-applied-configuration": redactedValue, "other": "kept"},
+		},
+		"data":       map[string]interface{}{"password": redactedValue},
+		"stringData": map[string]interface{}{"token": redactedValue},
+	}, secret.Object)
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+	redact(configMap)
+	require.Equal(t, map[string]interface{}{"key": "value"}, configMap.Object["data"], "only secrets are redacted")
+}
+
+func TestRecentEvents(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(name string, fields map[string]interface{}) unstructured.Unstructured {
+		obj := map[string]interface{}{"metadata": map[string]interface{}{"name": name, "creationTimestamp": now.Add(-10 * time.Hour).Format(time.RFC3339)}}
+		for k, v := range fields {
+			obj[k] = v
+		}
+		return unstructured.Unstructured{Object: obj}
+	}
+
+	events := []unstructured.Unstructured{
+		event("old", map[string]interface{}{"lastTimestamp": now.Add(-2 * time.Hour).Format(time.RFC3339)}),
+		event("recent", map[string]interface{}{"lastTimestamp": now.Add(-10 * time.Minute).Format(time.RFC3339)}),
+		event("micro", map[string]interface{}{"eventTime": now.Add(-20 * time.Minute).Format(metav1.RFC3339Micro)}),
+		event("creation", nil),
+	}
+
+	var names []string
+	for _, e := range recentEvents(events, now.Add(-time.Hour)) {
+		names = append(names, e.GetName())
+	}
+	require.Equal(t, []string{"micro", "recent"}, names)
+}
+
+func TestDump(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds", "namespace": "default"},
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+	}}
+	event := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion":    "v1",
+		"kind":          "Event",
+		"metadata":      map[string]interface{}{"name": "creds.1", "namespace": "default"},
+		"lastTimestamp": now.Add(-time.Minute).Format(time.RFC3339),
+		"reason":        "Synced",
+	}}
+	binding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apis.kcp.io/v1alpha1",
+		"kind":       "APIBinding",
+		"metadata":   map[string]interface{}{"name": "widgets"},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "False", "reason": "APIExportNotFound", "message": "not found"},
+		}},
+	}}
+
+	scheme := runtime.NewScheme()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "secrets"}:                                 "SecretList",
+		{Version: "v1", Resource: "events"}:                                  "EventList",
+		{Version: "v1", Resource: "configmaps"}:                              "ConfigMapList",
+		{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apibindings"}: "APIBindingList",
+	}, secret, event, binding)
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "secrets", Namespaced: true, Kind: "Secret", Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "events", Namespaced: true, Kind: "Event", Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "serviceaccounts/token", Namespaced: true, Kind: "TokenRequest", Verbs: metav1.Verbs{"create"}},
+		}},
+		{GroupVersion: "apis.kcp.io/v1alpha1", APIResources: []metav1.APIResource{
+			{Name: "apibindings", Kind: "APIBinding", Verbs: metav1.Verbs{"get", "list"}},
+		}},
+	}}}
+
+	kcpClient := kcpfakeclient.NewSimpleClientset(
+		&tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"}},
+			Spec:       tenancyv1alpha1.WorkspaceSpec{Cluster: "abc", URL: "https://shard-1/clusters/abc"},
+		},
+		&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org:ws"}},
+			Spec: apisv1alpha1.APIBindingSpec{Reference: apisv1alpha1.BindingReference{
+				Export: &apisv1alpha1.ExportBindingReference{Path: "root:provider", Name: "widgets"},
+			}},
+		},
+		&apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"}},
+			Status: apisv1alpha1.APIExportStatus{
+				IdentityHash:      "hash",
+				VirtualWorkspaces: []apisv1alpha1.VirtualWorkspace{{URL: "https://shard-1/services/apiexport/root:provider/widgets"}},
+			},
+		},
+	)
+
+	outputFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+	opts := NewDumpOptions(streams)
+	opts.OutputFile = outputFile
+	opts.workspace = logicalcluster.NewPath("root:org:ws")
+	opts.kcpClusterClient = kcpClient
+	opts.dynamicClient = dynamicClient
+	opts.discoveryClient = discoveryClient
+	opts.now = func() time.Time { return now }
+
+	require.NoError(t, opts.Run(context.Background()))
+
+	files := readTarball(t, outputFile)
+	require.ElementsMatch(t, []string{
+		"root_org_ws/summary.yaml",
+		"root_org_ws/routing.yaml",
+		"root_org_ws/workspace.yaml",
+		"root_org_ws/events.yaml",
+		"root_org_ws/conditions.txt",
+		"root_org_ws/resources/secrets.yaml",
+		"root_org_ws/resources/apibindings.apis.kcp.io.yaml",
+		"root_org_ws/apiexports/root_provider/widgets.yaml",
+	}, keys(files))
+
+	require.Contains(t, files["root_org_ws/resources/secrets.yaml"], "password: "+redactedValue)
+	require.NotContains(t, files["root_org_ws/resources/secrets.yaml"], "c2VjcmV0")
+	require.Contains(t, files["root_org_ws/events.yaml"], "reason: Synced")
+	require.Contains(t, files["root_org_ws/conditions.txt"], "apibindings.apis.kcp.io/widgets")
+	require.Contains(t, files["root_org_ws/routing.yaml"], "url: https://shard-1/clusters/abc")
+	require.Contains(t, files["root_org_ws/routing.yaml"], "- https://shard-1/services/apiexport/root:provider/widgets")
+	require.Contains(t, files["root_org_ws/workspace.yaml"], "kind: Workspace")
+	require.Contains(t, files["root_org_ws/apiexports/root_provider/widgets.yaml"], "identityHash: hash")
+	require.Empty(t, errOut.String())
+}
+
+func readTarball(t *testing.T, fileName string) map[string]string {
+	t.Helper()
+
+	data, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
+
+func keys(m map[string]string) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	return ret
+}
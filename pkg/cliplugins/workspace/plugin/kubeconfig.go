@@ -29,7 +29,6 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/spf13/cobra"
-	"github.com/xlab/treeprint"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -452,6 +451,8 @@ type CreateWorkspaceOptions struct {
 	ReadyWaitTimeout time.Duration
 	// LocationSelector is the location selector to use when creating the workspace to select a matching shard.
 	LocationSelector string
+	// Template is the name of a WorkspaceTemplate in the current workspace to create the workspace from.
+	Template string
 
 	kcpClusterClient kcpclientset.ClusterInterface
 
@@ -503,6 +504,7 @@ func (o *CreateWorkspaceOptions) BindFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.EnterAfterCreate, "enter", o.EnterAfterCreate, "Immediately enter the created workspace")
 	cmd.Flags().BoolVar(&o.IgnoreExisting, "ignore-existing", o.IgnoreExisting, "Ignore if the workspace already exists. Requires none or absolute type path.")
 	cmd.Flags().StringVar(&o.LocationSelector, "location-selector", o.LocationSelector, "A label selector to select the scheduling location of the created workspace.")
+	cmd.Flags().StringVar(&o.Template, "template", o.Template, "A WorkspaceTemplate in the current workspace to create the workspace from. --type takes precedence over the type of the template.")
 }
 
 // Run creates a workspace.
@@ -520,8 +522,18 @@ func (o *CreateWorkspaceOptions) Run(ctx context.Context) error {
 		return fmt.Errorf("--ignore-existing must not be used with non-absolute type path")
 	}
 
+	var template *tenancyv1alpha1.WorkspaceTemplate
+	if o.Template != "" {
+		template, err = o.kcpClusterClient.Cluster(currentClusterName).TenancyV1alpha1().WorkspaceTemplates().Get(ctx, o.Template, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get WorkspaceTemplate %q: %w", o.Template, err)
+		}
+	}
+
 	var structuredWorkspaceType tenancyv1alpha1.WorkspaceTypeReference
-	if o.Type != "" {
+	if o.Type == "" && template != nil && template.Spec.Type != nil {
+		structuredWorkspaceType = *template.Spec.Type
+	} else if o.Type != "" {
 		separatorIndex := strings.LastIndex(o.Type, ":")
 		switch separatorIndex {
 		case -1:
@@ -546,6 +558,16 @@ func (o *CreateWorkspaceOptions) Run(ctx context.Context) error {
 		},
 	}
 
+	if template != nil {
+		// the WorkspaceTemplate controller creates the APIBindings and RBAC of the template.
+		ws.Labels = template.Spec.Labels
+		ws.Annotations = map[string]string{}
+		for k, v := range template.Spec.Annotations {
+			ws.Annotations[k] = v
+		}
+		ws.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey] = template.Name
+	}
+
 	if o.LocationSelector != "" {
 		selector, err := metav1.ParseToLabelSelector(o.LocationSelector)
 		if err != nil {
@@ -766,95 +788,3 @@ func newKCPClusterClient(clientConfig clientcmd.ClientConfig) (kcpclientset.Clus
 	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
 	return kcpclientset.NewForConfig(clusterConfig)
 }
-
-// TreeOptions contains options for displaying the workspace tree.
-type TreeOptions struct {
-	*base.Options
-
-	Full bool
-
-	kcpClusterClient kcpclientset.ClusterInterface
-}
-
-// NewShowWorkspaceTreeOptions returns a new ShowWorkspaceTreeOptions.
-func NewTreeOptions(streams genericclioptions.IOStreams) *TreeOptions {
-	return &TreeOptions{
-		Options: base.NewOptions(streams),
-	}
-}
-
-// BindFlags binds fields to cmd's flagset.
-func (o *TreeOptions) BindFlags(cmd *cobra.Command) {
-	o.Options.BindFlags(cmd)
-	cmd.Flags().BoolVarP(&o.Full, "full", "f", o.Full, "Show full workspace names")
-}
-
-// Complete ensures all dynamically populated fields are initialized.
-func (o *TreeOptions) Complete() error {
-	if err := o.Options.Complete(); err != nil {
-		return err
-	}
-
-	kcpClusterClient, err := newKCPClusterClient(o.ClientConfig)
-	if err != nil {
-		return err
-	}
-	o.kcpClusterClient = kcpClusterClient
-
-	return nil
-}
-
-// Run outputs the current workspace.
-func (o *TreeOptions) Run(ctx context.Context) error {
-	config, err := o.ClientConfig.ClientConfig()
-	if err != nil {
-		return err
-	}
-	_, current, err := pluginhelpers.ParseClusterURL(config.Host)
-	if err != nil {
-		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
-	}
-
-	tree := treeprint.New()
-	// NOTE(hasheddan): the cluster URL can be used for only the tree root as
-	// the friendly name is used in kubeconfig.
-	name := current.String()
-	if !o.Full {
-		name = name[strings.LastIndex(name, ":")+1:]
-	}
-	branch := tree.AddBranch(name)
-	if err := o.populateBranch(ctx, branch, current, name); err != nil {
-		return err
-	}
-
-	fmt.Println(tree.String())
-	return nil
-}
-
-func (o *TreeOptions) populateBranch(ctx context.Context, tree treeprint.Tree, parent logicalcluster.Path, parentName string) error {
-	results, err := o.kcpClusterClient.Cluster(parent).TenancyV1alpha1().Workspaces().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil
-		}
-		return err
-	}
-
-	for _, workspace := range results.Items {
-		_, current, err := pluginhelpers.ParseClusterURL(workspace.Spec.URL)
-		if err != nil {
-			return fmt.Errorf("current config context URL %q does not point to workspace", workspace.Spec.URL)
-		}
-		// NOTE(hasheddan): the cluster URL from the Workspace does not use the
-		// friendly name, so we use the Workspace name instead.
-		name := workspace.Name
-		if o.Full {
-			name = parentName + ":" + name
-		}
-		branch := tree.AddBranch(name)
-		if err := o.populateBranch(ctx, branch, current, name); err != nil {
-			return err
-		}
-	}
-	return nil
-}
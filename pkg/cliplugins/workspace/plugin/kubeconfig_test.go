@@ -267,6 +267,81 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateWithTemplate(t *testing.T) {
+	template := &tenancyv1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:foo"}},
+		Spec: tenancyv1alpha1.WorkspaceTemplateSpec{
+			Type:        &tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+			Labels:      map[string]string{"team": "a"},
+			Annotations: map[string]string{"owner": "team-a"},
+		},
+	}
+
+	tests := map[string]struct {
+		template string
+		typ      string
+
+		wantType        tenancyv1alpha1.WorkspaceTypeReference
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantErr         string
+	}{
+		"type, labels and annotations from template": {
+			template:        "team",
+			wantType:        tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+			wantLabels:      map[string]string{"team": "a"},
+			wantAnnotations: map[string]string{"owner": "team-a", tenancyv1alpha1.WorkspaceTemplateAnnotationKey: "team"},
+		},
+		"type flag takes precedence": {
+			template:        "team",
+			typ:             "root:universal",
+			wantType:        tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "universal"},
+			wantLabels:      map[string]string{"team": "a"},
+			wantAnnotations: map[string]string{"owner": "team-a", tenancyv1alpha1.WorkspaceTemplateAnnotationKey: "team"},
+		},
+		"missing template": {
+			template: "missing",
+			wantErr:  `failed to get WorkspaceTemplate "missing"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created *tenancyv1alpha1.Workspace
+			client := kcpfakeclient.NewSimpleClientset(template)
+			client.PrependReactor("create", "workspaces", func(action kcptesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := action.(kcptesting.CreateAction).GetObject().(*tenancyv1alpha1.Workspace)
+				created = obj.DeepCopy()
+				obj.Status.Phase = corev1alpha1.LogicalClusterPhaseReady
+				if err := client.Tracker().Cluster(logicalcluster.NewPath("root:foo")).Create(tenancyv1alpha1.SchemeGroupVersion.WithResource("workspaces"), obj, ""); err != nil {
+					return false, nil, err
+				}
+				return true, obj, nil
+			})
+
+			opts := NewCreateWorkspaceOptions(genericclioptions.NewTestIOStreamsDiscard())
+			opts.Name = "bar"
+			opts.Type = tt.typ
+			opts.Template = tt.template
+			opts.kcpClusterClient = client
+			opts.ClientConfig = clientcmd.NewDefaultClientConfig(clientcmdapi.Config{CurrentContext: "test",
+				Contexts:  map[string]*clientcmdapi.Context{"test": {Cluster: "test", AuthInfo: "test"}},
+				Clusters:  map[string]*clientcmdapi.Cluster{"test": {Server: "https://test/clusters/root:foo"}},
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{"test": {Token: "test"}},
+			}, nil)
+			err := opts.Run(context.Background())
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				require.Nil(t, created)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, created.Spec.Type)
+			require.Equal(t, tt.wantLabels, created.Labels)
+			require.Equal(t, tt.wantAnnotations, created.Annotations)
+		})
+	}
+}
+
 func TestUse(t *testing.T) {
 	homeWorkspaceLogicalCluster := logicalcluster.NewPath("root:users:ab:cd:user-name")
 	tests := []struct {
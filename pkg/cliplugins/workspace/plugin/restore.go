@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// RestoreWorkspaceOptions contains options for restoring a Trashed workspace.
+type RestoreWorkspaceOptions struct {
+	*base.Options
+
+	// Name is the name of the workspace to restore.
+	Name string
+	// ReadyWaitTimeout is how long to wait for the restored workspace to be ready before returning control to the user.
+	ReadyWaitTimeout time.Duration
+
+	workspace        logicalcluster.Path
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewRestoreWorkspaceOptions returns a new RestoreWorkspaceOptions.
+func NewRestoreWorkspaceOptions(streams genericclioptions.IOStreams) *RestoreWorkspaceOptions {
+	return &RestoreWorkspaceOptions{
+		Options: base.NewOptions(streams),
+
+		ReadyWaitTimeout: time.Minute,
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *RestoreWorkspaceOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().DurationVar(&o.ReadyWaitTimeout, "ready-wait-timeout", o.ReadyWaitTimeout, "How long to wait for the restored workspace to be ready. Zero does not wait")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *RestoreWorkspaceOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.Name = args[0]
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, o.workspace, err = pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
+	}
+
+	if o.kcpClusterClient, err = newKCPClusterClient(o.ClientConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the RestoreWorkspaceOptions are complete and usable.
+func (o *RestoreWorkspaceOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("workspace name is required")
+	}
+	if o.ReadyWaitTimeout < 0 {
+		return errors.New("--ready-wait-timeout must not be negative")
+	}
+	return o.Options.Validate()
+}
+
+// Run requests to restore a Trashed workspace, and waits for the restored workspace to be ready.
+func (o *RestoreWorkspaceOptions) Run(ctx context.Context) error {
+	workspaces := o.kcpClusterClient.Cluster(o.workspace).TenancyV1alpha1().Workspaces()
+
+	ws, err := workspaces.Get(ctx, o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ws.Status.Phase != corev1alpha1.LogicalClusterPhaseTrashed {
+		return fmt.Errorf("workspace %q is not trashed, but in phase %s", o.Name, ws.Status.Phase)
+	}
+	trashedUID := ws.UID
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid": trashedUID,
+			"annotations": map[string]interface{}{
+				tenancyv1alpha1.WorkspaceRestoreAnnotationKey: "true",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := workspaces.Patch(ctx, o.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	if o.ReadyWaitTimeout == 0 {
+		_, err := fmt.Fprintf(o.Out, "Workspace %q is being restored.\n", o.Name)
+		return err
+	}
+	if _, err := fmt.Fprintf(o.Out, "Workspace %q is being restored. Waiting for it to be ready...\n", o.Name); err != nil {
+		return err
+	}
+
+	// the restored workspace is a new object of the same name.
+	if err := wait.PollImmediate(time.Millisecond*500, o.ReadyWaitTimeout, func() (bool, error) {
+		ws, err = workspaces.Get(ctx, o.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return ws.UID != trashedUID && ws.Status.Phase == corev1alpha1.LogicalClusterPhaseReady, nil
+	}); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(o.Out, "Workspace %q is restored and ready to use.\n", o.Name)
+	return err
+}
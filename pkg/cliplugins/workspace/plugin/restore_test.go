@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestRestore(t *testing.T) {
+	tests := map[string]struct {
+		phase corev1alpha1.LogicalClusterPhaseType
+
+		wantErr        string
+		wantAnnotation bool
+	}{
+		"trashed workspace": {
+			phase:          corev1alpha1.LogicalClusterPhaseTrashed,
+			wantAnnotation: true,
+		},
+		"ready workspace": {
+			phase:   corev1alpha1.LogicalClusterPhaseReady,
+			wantErr: `workspace "ws" is not trashed, but in phase Ready`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := kcpfakeclient.NewSimpleClientset(&tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", UID: "old", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"}},
+				Status:     tenancyv1alpha1.WorkspaceStatus{Phase: tt.phase},
+			})
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			opts := NewRestoreWorkspaceOptions(streams)
+			opts.Name = "ws"
+			opts.ReadyWaitTimeout = 0
+			opts.workspace = logicalcluster.NewPath("root:org")
+			opts.kcpClusterClient = client
+
+			err := opts.Run(context.Background())
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "Workspace \"ws\" is being restored.\n", out.String())
+			}
+
+			ws, err := client.Cluster(logicalcluster.NewPath("root:org")).TenancyV1alpha1().Workspaces().Get(context.Background(), "ws", metav1.GetOptions{})
+			require.NoError(t, err)
+			_, found := ws.Annotations[tenancyv1alpha1.WorkspaceRestoreAnnotationKey]
+			require.Equal(t, tt.wantAnnotation, found)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/martinlindhe/base36"
+	"github.com/spf13/cobra"
+	"github.com/xlab/treeprint"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// shardHashAnnotationKey is set by the workspace scheduler to the hash of the name of the shard
+// a workspace is scheduled to.
+const shardHashAnnotationKey = "internal.tenancy.kcp.io/shard"
+
+// TreeOptions contains options for displaying the workspace tree.
+type TreeOptions struct {
+	*base.Options
+
+	// Full shows full workspace paths instead of names.
+	Full bool
+	// Path is the workspace whose tree is shown, either absolute or relative to the current
+	// workspace. Defaults to the current workspace.
+	Path string
+	// Depth limits the levels of workspaces shown below the root of the tree. 0 means unlimited.
+	Depth int
+	// Output is the output format, empty for a tree, or json.
+	Output string
+
+	root             logicalcluster.Path
+	kcpClusterClient kcpclientset.ClusterInterface
+}
+
+// NewTreeOptions returns a new TreeOptions.
+func NewTreeOptions(streams genericclioptions.IOStreams) *TreeOptions {
+	return &TreeOptions{
+		Options: base.NewOptions(streams),
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *TreeOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().BoolVarP(&o.Full, "full", "f", o.Full, "Show full workspace names")
+	cmd.Flags().IntVar(&o.Depth, "depth", o.Depth, "Number of levels of workspaces to show below the root of the tree, 0 for unlimited")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format, empty for a tree or json")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *TreeOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		o.Path = args[0]
+	}
+
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, current, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
+	}
+	switch {
+	case o.Path == "":
+		o.root = current
+	case strings.Contains(o.Path, ":") || o.Path == core.RootCluster.String():
+		o.root = logicalcluster.NewPath(o.Path)
+	default:
+		o.root = current.Join(o.Path)
+	}
+
+	kcpClusterClient, err := newKCPClusterClient(o.ClientConfig)
+	if err != nil {
+		return err
+	}
+	o.kcpClusterClient = kcpClusterClient
+
+	return nil
+}
+
+// Validate validates the TreeOptions are complete and usable.
+func (o *TreeOptions) Validate() error {
+	if o.Depth < 0 {
+		return errors.New("--depth must not be negative")
+	}
+	if o.Output != "" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, only json is supported", o.Output)
+	}
+	if o.Path != "" && !logicalcluster.NewPath(o.Path).IsValid() {
+		return fmt.Errorf("invalid workspace path %q", o.Path)
+	}
+	return o.Options.Validate()
+}
+
+// workspaceTreeNode is a workspace in the tree, as printed with --output=json.
+type workspaceTreeNode struct {
+	Name     string               `json:"name"`
+	Path     string               `json:"path"`
+	Type     string               `json:"type,omitempty"`
+	Phase    string               `json:"phase,omitempty"`
+	Shard    string               `json:"shard,omitempty"`
+	Children []*workspaceTreeNode `json:"children,omitempty"`
+}
+
+// Run outputs the workspace tree.
+func (o *TreeOptions) Run(ctx context.Context) error {
+	shards := o.shardNames(ctx)
+
+	root := &workspaceTreeNode{Name: o.root.Base(), Path: o.root.String()}
+	if parent, name := o.root.Split(); !parent.Empty() {
+		// the root workspace is described by its parent, which might not be accessible.
+		if ws, err := o.kcpClusterClient.Cluster(parent).TenancyV1alpha1().Workspaces().Get(ctx, name, metav1.GetOptions{}); err == nil {
+			describeWorkspace(root, ws, shards)
+		}
+	}
+	if err := o.populate(ctx, root, o.root, 1, shards); err != nil {
+		return err
+	}
+
+	if o.Output == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root)
+	}
+
+	tree := treeprint.New()
+	o.addBranch(tree, root)
+	_, err := fmt.Fprintln(o.Out, tree.String())
+	return err
+}
+
+// populate adds the child workspaces of the node, listed in the given logical cluster, down to
+// the maximum depth.
+func (o *TreeOptions) populate(ctx context.Context, node *workspaceTreeNode, cluster logicalcluster.Path, level int, shards map[string]string) error {
+	if o.Depth > 0 && level > o.Depth {
+		return nil
+	}
+
+	results, err := o.kcpClusterClient.Cluster(cluster).TenancyV1alpha1().Workspaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for i := range results.Items {
+		workspace := &results.Items[i]
+		child := &workspaceTreeNode{Name: workspace.Name, Path: logicalcluster.NewPath(node.Path).Join(workspace.Name).String()}
+		describeWorkspace(child, workspace, shards)
+		node.Children = append(node.Children, child)
+
+		if workspace.Spec.URL == "" {
+			continue // not scheduled yet, hence without children
+		}
+		// NOTE(hasheddan): the cluster URL from the Workspace does not use the
+		// friendly name, so we use the Workspace name instead.
+		_, current, err := pluginhelpers.ParseClusterURL(workspace.Spec.URL)
+		if err != nil {
+			return fmt.Errorf("current config context URL %q does not point to workspace", workspace.Spec.URL)
+		}
+		if err := o.populate(ctx, child, current, level+1, shards); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *TreeOptions) addBranch(tree treeprint.Tree, node *workspaceTreeNode) {
+	name := node.Name
+	if o.Full {
+		name = node.Path
+	}
+	var details []string
+	if node.Type != "" {
+		details = append(details, "type "+node.Type)
+	}
+	if node.Phase != "" {
+		details = append(details, "phase "+node.Phase)
+	}
+	if node.Shard != "" {
+		details = append(details, "shard "+node.Shard)
+	}
+	if len(details) > 0 {
+		name += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	branch := tree.AddBranch(name)
+	for _, child := range node.Children {
+		o.addBranch(branch, child)
+	}
+}
+
+// shardNames returns the names of the shards by the hash of their name. Usually, only
+// administrators can list shards, hence shards are not shown otherwise.
+func (o *TreeOptions) shardNames(ctx context.Context) map[string]string {
+	shards, err := o.kcpClusterClient.Cluster(core.RootCluster.Path()).CoreV1alpha1().Shards().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]string, len(shards.Items))
+	for _, shard := range shards.Items {
+		names[shardNameHash(shard.Name)] = shard.Name
+	}
+	return names
+}
+
+// shardNameHash returns the hash of the shard name the workspace scheduler sets in the
+// shardHashAnnotationKey annotation.
+func shardNameHash(name string) string {
+	hash := sha256.Sum224([]byte(name))
+	return strings.ToLower(base36.EncodeBytes(hash[:]))[:8]
+}
+
+func describeWorkspace(node *workspaceTreeNode, ws *tenancyv1alpha1.Workspace, shards map[string]string) {
+	if ws.Spec.Type.Name != "" {
+		node.Type = logicalcluster.NewPath(ws.Spec.Type.Path).Join(string(ws.Spec.Type.Name)).String()
+	}
+	node.Phase = string(ws.Status.Phase)
+	node.Shard = shards[ws.Annotations[shardHashAnnotationKey]]
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+)
+
+func TestTree(t *testing.T) {
+	workspace := func(parent, name, cluster, typ string, phase corev1alpha1.LogicalClusterPhaseType, shardHash string) *tenancyv1alpha1.Workspace {
+		ws := &tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: parent}},
+			Spec:       tenancyv1alpha1.WorkspaceSpec{Type: tenancyv1alpha1.WorkspaceTypeReference{Name: tenancyv1alpha1.WorkspaceTypeName(typ), Path: "root"}},
+			Status:     tenancyv1alpha1.WorkspaceStatus{Phase: phase},
+		}
+		if cluster != "" {
+			ws.Spec.Cluster = cluster
+			ws.Spec.URL = "https://front-proxy/clusters/" + cluster
+		}
+		if shardHash != "" {
+			ws.Annotations[shardHashAnnotationKey] = shardHash
+		}
+		return ws
+	}
+	alpha := shardNameHash("alpha")
+	client := kcpfakeclient.NewSimpleClientset(
+		&corev1alpha1.Shard{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Annotations: map[string]string{logicalcluster.AnnotationKey: "root"}}},
+		workspace("root", "org", "c-org", "organization", corev1alpha1.LogicalClusterPhaseReady, alpha),
+		workspace("root:org", "team", "c-team", "team", corev1alpha1.LogicalClusterPhaseReady, alpha),
+		workspace("root:org", "new", "", "universal", corev1alpha1.LogicalClusterPhaseScheduling, ""),
+		workspace("c-team", "app", "c-app", "universal", corev1alpha1.LogicalClusterPhaseInitializing, "unknown"),
+	)
+
+	tests := map[string]struct {
+		opts TreeOptions
+		want string
+	}{
+		"tree": {
+			want: `.
+└── org (type root:organization, phase Ready, shard alpha)
+    ├── new (type root:universal, phase Scheduling)
+    └── team (type root:team, phase Ready, shard alpha)
+        └── app (type root:universal, phase Initializing)
+
+`,
+		},
+		"full names with depth": {
+			opts: TreeOptions{Full: true, Depth: 1},
+			want: `.
+└── root:org (type root:organization, phase Ready, shard alpha)
+    ├── root:org:new (type root:universal, phase Scheduling)
+    └── root:org:team (type root:team, phase Ready, shard alpha)
+
+`,
+		},
+		"json": {
+			opts: TreeOptions{Output: "json", Depth: 1},
+			want: `{
+  "name": "org",
+  "path": "root:org",
+  "type": "root:organization",
+  "phase": "Ready",
+  "shard": "alpha",
+  "children": [
+    {
+      "name": "new",
+      "path": "root:org:new",
+      "type": "root:universal",
+      "phase": "Scheduling"
+    },
+    {
+      "name": "team",
+      "path": "root:org:team",
+      "type": "root:team",
+      "phase": "Ready",
+      "shard": "alpha"
+    }
+  ]
+}
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			opts := tt.opts
+			opts.Options = NewTreeOptions(streams).Options
+			opts.root = logicalcluster.NewPath("root:org")
+			opts.kcpClusterClient = client
+
+			require.NoError(t, opts.Run(context.Background()))
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
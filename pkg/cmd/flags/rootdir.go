@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"strings"
+)
+
+// RootDirectory manually extracts the value of --root-directory from the given command line
+// arguments, or returns defaultDir if it is not set. The root directory influences the defaults of
+// other flags, hence it has to be known before the flags are added and parsed. Normal flag
+// processing fails if the value is missing.
+func RootDirectory(args []string, defaultDir string) string {
+	rootDir := defaultDir
+	for i, f := range args {
+		if f == "--root-directory" {
+			if i < len(args)-1 {
+				rootDir = args[i+1]
+			}
+		} else if strings.HasPrefix(f, "--root-directory=") {
+			rootDir = strings.TrimPrefix(f, "--root-directory=")
+		}
+	}
+	return rootDir
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootDirectory(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"unset":           {args: []string{"start", "--v=2"}, want: ".kcp"},
+		"equals sign":     {args: []string{"start", "--root-directory=/data"}, want: "/data"},
+		"separate value":  {args: []string{"start", "--root-directory", "/data", "--v=2"}, want: "/data"},
+		"last one wins":   {args: []string{"--root-directory=/a", "--root-directory", "/b"}, want: "/b"},
+		"missing value":   {args: []string{"start", "--root-directory"}, want: ".kcp"},
+		"other flag name": {args: []string{"--root-directory-other=/data"}, want: ".kcp"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, RootDirectory(tt.args, ".kcp"))
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package conversion
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -30,31 +31,36 @@ import (
 )
 
 // CRConverterFactory instantiates converters that are capable of converting custom resources between different API
-// versions. It supports CEL-based conversion rules from APIConversion resources and the "none" conversion strategy.
+// versions. It supports CEL-based conversion rules from APIConversion resources, and the "none" and "webhook"
+// conversion strategies.
 type CRConverterFactory struct {
 	getAPIConversion                func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error)
+	webhookConverterFactory         conversion.Factory
 	objectCELTransformationsTimeout time.Duration
 }
 
 var _ conversion.Factory = &CRConverterFactory{}
 
-// NewCRConverterFactory returns a CRConverterFactory that supports APIConversion-based conversions and the "none"
-// conversion strategy.
+// NewCRConverterFactory returns a CRConverterFactory that supports APIConversion-based conversions, and the "none"
+// and "webhook" conversion strategies. Webhook converters are created by webhookConverterFactory, for bound CRDs only.
 func NewCRConverterFactory(
 	apiConversionInformer apisinformers.APIConversionClusterInformer,
+	webhookConverterFactory conversion.Factory,
 	objectCELTransformationsTimeout time.Duration,
 ) *CRConverterFactory {
 	return &CRConverterFactory{
 		getAPIConversion: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error) {
 			return apiConversionInformer.Lister().Cluster(clusterName).Get(name)
 		},
+		webhookConverterFactory:         webhookConverterFactory,
 		objectCELTransformationsTimeout: objectCELTransformationsTimeout,
 	}
 }
 
 // NewConverter returns the appropriate conversion.Converter based on the CRD. If the CRD identifies as for a "wildcard
-// partial metadata request", the nop converter is used. Otherwise, it returns a CEL-based converter if there is an
-// associated APIConversion, a nop converter if the strategy is "none", or an error otherwise.
+// partial metadata request", the nop converter is used. Otherwise, it returns a webhook converter if the CRD is bound
+// from an APIResourceSchema with the "webhook" strategy, a CEL-based converter if there is an associated APIConversion, a nop converter if the strategy is "none",
+// or an error otherwise.
 func (f *CRConverterFactory) NewConverter(crd *apiextensionsv1.CustomResourceDefinition) (conversion.CRConverter, error) {
 	// Wildcard, partial metadata requests never need conversion
 	if strings.HasSuffix(string(crd.UID), ".wildcard.partial-metadata") {
@@ -72,5 +78,13 @@ func (f *CRConverterFactory) NewConverter(crd *apiextensionsv1.CustomResourceDef
 		newConverter: func(crd *apiextensionsv1.CustomResourceDefinition, apiConversion *apisv1alpha1.APIConversion) (conversion.CRConverter, error) {
 			return NewConverter(crd, apiConversion, f.objectCELTransformationsTimeout)
 		},
+		newWebhookConverter: f.newWebhookConverter,
 	}, nil
 }
+
+func (f *CRConverterFactory) newWebhookConverter(crd *apiextensionsv1.CustomResourceDefinition) (conversion.CRConverter, error) {
+	if f.webhookConverterFactory == nil {
+		return nil, fmt.Errorf("conversion strategy %q is not supported for CRD %s", crd.Spec.Conversion.Strategy, crd.Name)
+	}
+	return f.webhookConverterFactory.NewConverter(crd)
+}
@@ -29,7 +29,7 @@ import (
 )
 
 func TestNewCRConverterFactory(t *testing.T) {
-	f := NewCRConverterFactory(nil, wait.ForeverTestTimeout)
+	f := NewCRConverterFactory(nil, nil, wait.ForeverTestTimeout)
 
 	crd := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -42,12 +42,14 @@ type deferredConverter struct {
 	// delegate is the actual converter
 	delegate conversion.CRConverter
 
-	getAPIConversion func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error)
-	newConverter     func(crd *apiextensionsv1.CustomResourceDefinition, apiConversion *apisv1alpha1.APIConversion) (conversion.CRConverter, error)
+	getAPIConversion    func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error)
+	newConverter        func(crd *apiextensionsv1.CustomResourceDefinition, apiConversion *apisv1alpha1.APIConversion) (conversion.CRConverter, error)
+	newWebhookConverter func(crd *apiextensionsv1.CustomResourceDefinition) (conversion.CRConverter, error)
 }
 
-// Convert converts in to targetGV. If there is an APIConversion for this CRD, a CEL-based converter is used. Otherwise,
-// a nop converter is used.
+// Convert converts in to targetGV. If the CRD is bound from an APIResourceSchema with the webhook strategy, a webhook
+// converter is used. Otherwise, if there is an APIConversion for this CRD, a CEL-based converter is used, or else a nop
+// converter.
 func (f *deferredConverter) Convert(in *unstructured.UnstructuredList, targetGV schema.GroupVersion) (*unstructured.UnstructuredList, error) {
 	converter, err := f.getConverter()
 	if err != nil {
@@ -68,6 +70,18 @@ func (f *deferredConverter) getConverter() (conversion.CRConverter, error) {
 	var clusterName logicalcluster.Name
 	var conversionName string
 	_, boundCRD := f.crd.Annotations[apisv1alpha1.AnnotationBoundCRDKey]
+
+	// The webhook strategy of bound CRDs comes from the conversion of an APIResourceSchema and takes
+	// precedence over an APIConversion. CRDs of tenants cannot call webhooks.
+	if boundCRD && f.crd.Spec.Conversion != nil && f.crd.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter {
+		converter, err := f.newWebhookConverter(f.crd)
+		if err != nil {
+			return nil, fmt.Errorf("error creating webhook converter for CRD %s: %w", f.crd.Name, err)
+		}
+		f.delegate = converter
+		return converter, nil
+	}
+
 	clusterNameAnnotation := f.crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey]
 	schemaNameAnnotation := f.crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey]
 
@@ -71,11 +71,13 @@ spec:
 	require.NoError(t, err, "error unmarshalling APIConversion")
 
 	tests := map[string]struct {
-		apiConversionNotFound bool
-		strategy              apiextensionsv1.ConversionStrategyType
-		expectedType          interface{}
-		wantErrorMatching     string
-		wantNewConverterCalls int
+		apiConversionNotFound        bool
+		tenant                       bool
+		strategy                     apiextensionsv1.ConversionStrategyType
+		expectedType                 interface{}
+		wantErrorMatching            string
+		wantNewConverterCalls        int
+		wantNewWebhookConverterCalls int
 	}{
 		"APIConversion not found, strategy=none": {
 			apiConversionNotFound: true,
@@ -84,11 +86,23 @@ spec:
 			wantNewConverterCalls: 0,
 		},
 		"APIConversion not found, strategy=webhook": {
+			apiConversionNotFound:        true,
+			strategy:                     apiextensionsv1.WebhookConverter,
+			expectedType:                 &fakeWebhookConverter{},
+			wantNewWebhookConverterCalls: 1,
+		},
+		"tenant CRD, APIConversion not found, strategy=webhook": {
 			apiConversionNotFound: true,
+			tenant:                true,
 			strategy:              apiextensionsv1.WebhookConverter,
 			wantErrorMatching:     "is not supported for CRD",
 			wantNewConverterCalls: 0,
 		},
+		"APIConversion exists, strategy=webhook": {
+			strategy:                     apiextensionsv1.WebhookConverter,
+			expectedType:                 &fakeWebhookConverter{},
+			wantNewWebhookConverterCalls: 1,
+		},
 		"APIConversion not found, strategy=unknown": {
 			apiConversionNotFound: true,
 			strategy:              "something",
@@ -96,6 +110,7 @@ spec:
 			wantNewConverterCalls: 0,
 		},
 		"APIConversion exists": {
+			strategy:              apiextensionsv1.NoneConverter,
 			expectedType:          &fakeConverter{},
 			wantNewConverterCalls: 1,
 		},
@@ -109,8 +124,14 @@ spec:
 
 			crd := crd.DeepCopy()
 			crd.Spec.Conversion.Strategy = tc.strategy
+			if tc.tenant {
+				// APIConversions of tenant CRDs live next to the CRD, by the same name.
+				crd.Name = "v1.widgets.kcp.io"
+				crd.Annotations = map[string]string{logicalcluster.AnnotationKey: "root"}
+			}
 
 			newConverterCalls := 0
+			newWebhookConverterCalls := 0
 
 			c := &deferredConverter{
 				crd: crd,
@@ -128,6 +149,10 @@ spec:
 					newConverterCalls++
 					return &fakeConverter{}, nil
 				},
+				newWebhookConverter: func(crd *apiextensionsv1.CustomResourceDefinition) (conversion.CRConverter, error) {
+					newWebhookConverterCalls++
+					return &fakeWebhookConverter{}, nil
+				},
 			}
 
 			converter, err := c.getConverter()
@@ -140,12 +165,14 @@ spec:
 
 			require.IsType(t, tc.expectedType, converter)
 			require.Equal(t, tc.wantNewConverterCalls, newConverterCalls)
+			require.Equal(t, tc.wantNewWebhookConverterCalls, newWebhookConverterCalls)
 
 			// Make sure we return the cached converter if we try to get it again
 			converter, err = c.getConverter()
 			require.NoError(t, err)
 			require.IsType(t, tc.expectedType, converter)
 			require.Equal(t, tc.wantNewConverterCalls, newConverterCalls)
+			require.Equal(t, tc.wantNewWebhookConverterCalls, newWebhookConverterCalls)
 		})
 	}
 }
@@ -155,3 +182,9 @@ type fakeConverter struct{}
 func (f *fakeConverter) Convert(in *unstructured.UnstructuredList, _ schema.GroupVersion) (*unstructured.UnstructuredList, error) {
 	return in, nil
 }
+
+type fakeWebhookConverter struct{}
+
+func (f *fakeWebhookConverter) Convert(in *unstructured.UnstructuredList, _ schema.GroupVersion) (*unstructured.UnstructuredList, error) {
+	return in, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/network"
+)
+
+// webhookDialTimeout is the timeout of establishing connections to conversion webhooks.
+const webhookDialTimeout = 30 * time.Second
+
+// NewWebhookAuthResolverWrapper returns the authentication info resolver wrapper for the conversion
+// webhooks of APIResourceSchemas. These webhooks are configured by API providers, hence connections
+// are only established to public addresses, or to the given networks allowed by the administrator,
+// and never through an HTTP proxy. Addresses are checked after name resolution, for every
+// connection, including those of redirects.
+func NewWebhookAuthResolverWrapper(allowedNetworks []*net.IPNet) webhook.AuthenticationInfoResolverWrapper {
+	return func(delegate webhook.AuthenticationInfoResolver) webhook.AuthenticationInfoResolver {
+		return &publicWebhookResolver{
+			delegate: delegate,
+			dialer: &net.Dialer{
+				Timeout: webhookDialTimeout,
+				Control: network.PublicAddressDialControl(allowedNetworks),
+			},
+		}
+	}
+}
+
+// publicWebhookResolver restricts the connections of the client configs of its delegate.
+type publicWebhookResolver struct {
+	delegate webhook.AuthenticationInfoResolver
+	dialer   *net.Dialer
+}
+
+func (r *publicWebhookResolver) ClientConfigFor(hostPort string) (*rest.Config, error) {
+	config, err := r.delegate.ClientConfigFor(hostPort)
+	if err != nil {
+		return nil, err
+	}
+	config = rest.CopyConfig(config)
+	config.Dial = r.dialer.DialContext
+	config.Proxy = func(*http.Request) (*url.URL, error) {
+		// no proxy: it would connect to the webhook on our behalf, unchecked.
+		return nil, nil
+	}
+	return config, nil
+}
+
+func (r *publicWebhookResolver) ClientConfigForService(serviceName, serviceNamespace string, servicePort int) (*rest.Config, error) {
+	return nil, fmt.Errorf("conversion webhooks are called by url, service %s/%s is not supported", serviceNamespace, serviceName)
+}
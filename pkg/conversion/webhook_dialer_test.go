@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/network"
+)
+
+type fakeAuthResolver struct{}
+
+func (fakeAuthResolver) ClientConfigFor(hostPort string) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+func (fakeAuthResolver) ClientConfigForService(serviceName, serviceNamespace string, servicePort int) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+func TestWebhookAuthResolverWrapper(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	get := func(resolver webhook.AuthenticationInfoResolver) error {
+		config, err := resolver.ClientConfigFor(server.Listener.Addr().String())
+		require.NoError(t, err)
+		client, err := rest.HTTPClientFor(config)
+		require.NoError(t, err)
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	t.Log("Loopback addresses are refused")
+	err := get(NewWebhookAuthResolverWrapper(nil)(fakeAuthResolver{}))
+	require.ErrorIs(t, err, network.ErrNonPublicAddress)
+	require.False(t, called)
+
+	t.Log("Allowed networks are connected to")
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+	require.NoError(t, get(NewWebhookAuthResolverWrapper([]*net.IPNet{loopback})(fakeAuthResolver{})))
+	require.True(t, called)
+
+	t.Log("Services are not supported")
+	_, err = NewWebhookAuthResolverWrapper(nil)(fakeAuthResolver{}).ClientConfigForService("webhook", "default", 443)
+	require.Error(t, err)
+}
@@ -79,8 +79,23 @@ func NewConfig(o options.CompletedOptions, enableWatchCache bool) (*Config, erro
 	cfg.ClientTLSInfo.KeyFile = filepath.Join(cfg.Dir, "secrets", "peer", "key.pem")
 	cfg.ClientTLSInfo.TrustedCAFile = filepath.Join(cfg.Dir, "secrets", "ca", "cert.pem")
 	cfg.ClientTLSInfo.ClientCertAuth = true
+	if o.ClientCertFile != "" {
+		cfg.ClientTLSInfo.CertFile = o.ClientCertFile
+		cfg.ClientTLSInfo.KeyFile = o.ClientKeyFile
+		cfg.ClientTLSInfo.TrustedCAFile = o.ClientTrustedCAFile
+	}
+	if o.PeerCertFile != "" {
+		cfg.PeerTLSInfo.CertFile = o.PeerCertFile
+		cfg.PeerTLSInfo.KeyFile = o.PeerKeyFile
+		cfg.PeerTLSInfo.TrustedCAFile = o.PeerTrustedCAFile
+	}
 	cfg.ForceNewCluster = o.ForceNewCluster
 
+	if o.AutoCompactionMode != "" {
+		cfg.AutoCompactionMode = o.AutoCompactionMode
+		cfg.AutoCompactionRetention = o.AutoCompactionRetention
+	}
+
 	if enableWatchCache {
 		// defines the interval for etcd watch progress notify events.
 		//
@@ -22,6 +22,7 @@ import (
 
 	"github.com/spf13/pflag"
 	etcdtypes "go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 
 	genericoptions "k8s.io/apiserver/pkg/server/options"
 )
@@ -36,6 +37,20 @@ type Options struct {
 	WalSizeBytes      int64
 	QuotaBackendBytes int64
 	ForceNewCluster   bool
+
+	// PeerCertFile, PeerKeyFile and PeerTrustedCAFile replace the self-signed peer certificates.
+	PeerCertFile      string
+	PeerKeyFile       string
+	PeerTrustedCAFile string
+	// ClientCertFile, ClientKeyFile and ClientTrustedCAFile replace the self-signed serving certificates
+	// of the client port. The client certificate of the server must then be passed with --etcd-certfile
+	// and --etcd-keyfile.
+	ClientCertFile      string
+	ClientKeyFile       string
+	ClientTrustedCAFile string
+
+	AutoCompactionMode      string
+	AutoCompactionRetention string
 }
 
 func NewOptions(rootDir string) *Options {
@@ -54,6 +69,14 @@ func (e *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.Int64Var(&e.WalSizeBytes, "embedded-etcd-wal-size-bytes", e.WalSizeBytes, "Size of embedded etcd WAL")
 	fs.Int64Var(&e.QuotaBackendBytes, "embedded-etcd-quota-backend-bytes", e.WalSizeBytes, "Alarm threshold for embedded etcd backend bytes")
 	fs.BoolVar(&e.ForceNewCluster, "embedded-etcd-force-new-cluster", e.ForceNewCluster, "Starts a new cluster from existing data restored from a different system")
+	fs.StringVar(&e.PeerCertFile, "embedded-etcd-peer-cert-file", e.PeerCertFile, "Certificate for the peer port of embedded etcd. A self-signed certificate is generated if empty")
+	fs.StringVar(&e.PeerKeyFile, "embedded-etcd-peer-key-file", e.PeerKeyFile, "Key of the certificate for the peer port of embedded etcd")
+	fs.StringVar(&e.PeerTrustedCAFile, "embedded-etcd-peer-trusted-ca-file", e.PeerTrustedCAFile, "CA to verify the peer certificates of embedded etcd")
+	fs.StringVar(&e.ClientCertFile, "embedded-etcd-client-cert-file", e.ClientCertFile, "Certificate for the client port of embedded etcd. A self-signed certificate is generated if empty. If set, the client certificate must be passed with --etcd-certfile and --etcd-keyfile")
+	fs.StringVar(&e.ClientKeyFile, "embedded-etcd-client-key-file", e.ClientKeyFile, "Key of the certificate for the client port of embedded etcd")
+	fs.StringVar(&e.ClientTrustedCAFile, "embedded-etcd-client-trusted-ca-file", e.ClientTrustedCAFile, "CA to verify the client certificates of embedded etcd")
+	fs.StringVar(&e.AutoCompactionMode, "embedded-etcd-auto-compaction-mode", e.AutoCompactionMode, "Auto compaction mode of embedded etcd, either 'periodic' or 'revision'. Empty disables auto compaction")
+	fs.StringVar(&e.AutoCompactionRetention, "embedded-etcd-auto-compaction-retention", e.AutoCompactionRetention, "Auto compaction retention of embedded etcd: a duration like '1h' for the periodic mode, a number of revisions for the revision mode")
 }
 
 type completedOptions struct {
@@ -68,9 +91,11 @@ type CompletedOptions struct {
 func (e *Options) Complete(etcdOptions *genericoptions.EtcdOptions) CompletedOptions {
 	if e.Enabled {
 		etcdOptions.StorageConfig.Transport.ServerList = []string{fmt.Sprintf("https://localhost:%s", e.ClientPort)}
-		etcdOptions.StorageConfig.Transport.KeyFile = filepath.Join(e.Directory, "secrets", "client", "key.pem")
-		etcdOptions.StorageConfig.Transport.CertFile = filepath.Join(e.Directory, "secrets", "client", "cert.pem")
-		etcdOptions.StorageConfig.Transport.TrustedCAFile = filepath.Join(e.Directory, "secrets", "ca", "cert.pem")
+		if e.ClientCertFile == "" {
+			etcdOptions.StorageConfig.Transport.KeyFile = filepath.Join(e.Directory, "secrets", "client", "key.pem")
+			etcdOptions.StorageConfig.Transport.CertFile = filepath.Join(e.Directory, "secrets", "client", "cert.pem")
+			etcdOptions.StorageConfig.Transport.TrustedCAFile = filepath.Join(e.Directory, "secrets", "ca", "cert.pem")
+		}
 	}
 
 	return CompletedOptions{&completedOptions{
@@ -94,6 +119,24 @@ func (e *Options) Validate() []error {
 				errs = append(errs, fmt.Errorf("--embedded-etcd-listen-metrics-urls parse failure: %w", err))
 			}
 		}
+		if (e.PeerCertFile == "") != (e.PeerKeyFile == "") || (e.PeerCertFile == "") != (e.PeerTrustedCAFile == "") {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-peer-cert-file, --embedded-etcd-peer-key-file and --embedded-etcd-peer-trusted-ca-file must be specified together"))
+		}
+		if (e.ClientCertFile == "") != (e.ClientKeyFile == "") || (e.ClientCertFile == "") != (e.ClientTrustedCAFile == "") {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-client-cert-file, --embedded-etcd-client-key-file and --embedded-etcd-client-trusted-ca-file must be specified together"))
+		}
+		switch e.AutoCompactionMode {
+		case "":
+			if e.AutoCompactionRetention != "" {
+				errs = append(errs, fmt.Errorf("--embedded-etcd-auto-compaction-retention requires --embedded-etcd-auto-compaction-mode"))
+			}
+		case v3compactor.ModePeriodic, v3compactor.ModeRevision:
+			if e.AutoCompactionRetention == "" {
+				errs = append(errs, fmt.Errorf("--embedded-etcd-auto-compaction-retention must be specified"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("--embedded-etcd-auto-compaction-mode must be either %q or %q", v3compactor.ModePeriodic, v3compactor.ModeRevision))
+		}
 	}
 
 	return errs
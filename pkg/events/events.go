@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events records events of system controllers into the logical cluster of the
+// involved object, such that users see them with `kubectl get events` in their workspace.
+package events
+
+import (
+	"context"
+	"fmt"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	conditionsapi "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	kcpscheme "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/scheme"
+)
+
+// maxEventMessageLength is the maximum length of an event message, longer messages are truncated.
+const maxEventMessageLength = 1024
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kcpscheme.AddToScheme(scheme))
+}
+
+// NewRecorder returns an event recorder for the given component. The events are created in the
+// logical cluster of the involved object, in the namespace of the object or in the default
+// namespace for cluster-scoped objects. The recorder stops when the context is done.
+func NewRecorder(ctx context.Context, kubeClusterClient kcpkubernetesclientset.ClusterInterface, component string) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&clusterSink{ctx: ctx, kubeClusterClient: kubeClusterClient})
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+
+	return &clusterRecorder{
+		EventRecorder: broadcaster.NewRecorder(scheme, corev1.EventSource{Component: component}),
+	}
+}
+
+// clusterRecorder annotates every event with the logical cluster of the involved object for
+// the clusterSink to route it.
+type clusterRecorder struct {
+	record.EventRecorder
+}
+
+func (r *clusterRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *clusterRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *clusterRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	m, err := meta.Accessor(object)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to record event for %T: %w", object, err))
+		return
+	}
+	clusterName := logicalcluster.From(m)
+	if clusterName.Empty() {
+		utilruntime.HandleError(fmt.Errorf("failed to record event for %T %s: no logical cluster", object, m.GetName()))
+		return
+	}
+
+	merged := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	merged[logicalcluster.AnnotationKey] = clusterName.String()
+
+	message := fmt.Sprintf(messageFmt, args...)
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-3] + "..."
+	}
+	r.EventRecorder.AnnotatedEventf(object, merged, eventtype, reason, "%s", message)
+}
+
+// clusterSink writes events into the logical cluster given by their logicalcluster.AnnotationKey
+// annotation.
+type clusterSink struct {
+	ctx               context.Context
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+}
+
+var _ record.EventSink = &clusterSink{}
+
+func (s *clusterSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	clusterName, event, err := fromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.kubeClusterClient.Cluster(clusterName.Path()).CoreV1().Events(event.Namespace).Create(s.ctx, event, metav1.CreateOptions{})
+}
+
+func (s *clusterSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	clusterName, event, err := fromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.kubeClusterClient.Cluster(clusterName.Path()).CoreV1().Events(event.Namespace).Update(s.ctx, event, metav1.UpdateOptions{})
+}
+
+func (s *clusterSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	clusterName, event, err := fromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.kubeClusterClient.Cluster(clusterName.Path()).CoreV1().Events(event.Namespace).Patch(s.ctx, event.Name, types.StrategicMergePatchType, data, metav1.PatchOptions{})
+}
+
+// fromEvent returns the logical cluster of the event and a copy of the event without the
+// logical cluster annotation.
+func fromEvent(event *corev1.Event) (logicalcluster.Name, *corev1.Event, error) {
+	clusterName := logicalcluster.From(event)
+	if clusterName.Empty() {
+		return "", nil, fmt.Errorf("event %s/%s has no logical cluster", event.Namespace, event.Name)
+	}
+	event = event.DeepCopy()
+	delete(event.Annotations, logicalcluster.AnnotationKey)
+	if len(event.Annotations) == 0 {
+		event.Annotations = nil
+	}
+	return clusterName, event, nil
+}
+
+// RecordConditionChanges records an event for every condition of obj that changed compared to
+// old: a Warning event when a condition becomes false with severity Error or Warning, or when
+// the reason or message of such a failure changes, and a Normal event when a condition recovers
+// from such a failure. Conditions that are false with severity Info, i.e. that are waiting for
+// something to happen, are not actionable and hence not recorded.
+func RecordConditionChanges(ctx context.Context, recorder record.EventRecorder, old, obj conditions.Getter) {
+	logger := klog.FromContext(ctx)
+
+	for _, c := range obj.GetConditions() {
+		prev := conditions.Get(old, c.Type)
+
+		switch {
+		case c.Status == corev1.ConditionFalse && isFailure(&c):
+			if prev != nil && prev.Status == c.Status && prev.Reason == c.Reason && prev.Message == c.Message {
+				continue
+			}
+			logger.V(4).Info("recording condition failure", "condition", c.Type, "reason", c.Reason)
+			recorder.Eventf(obj, corev1.EventTypeWarning, reasonOf(&c), "%s: %s", c.Type, c.Message)
+		case c.Status == corev1.ConditionTrue && prev != nil && prev.Status == corev1.ConditionFalse && isFailure(prev):
+			logger.V(4).Info("recording condition recovery", "condition", c.Type)
+			recorder.Eventf(obj, corev1.EventTypeNormal, string(c.Type), "%s is true again", c.Type)
+		}
+	}
+}
+
+func isFailure(c *conditionsapi.Condition) bool {
+	return c.Severity == conditionsapi.ConditionSeverityError || c.Severity == conditionsapi.ConditionSeverityWarning
+}
+
+func reasonOf(c *conditionsapi.Condition) string {
+	if c.Reason != "" {
+		return c.Reason
+	}
+	return string(c.Type)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+func TestRecordConditionChanges(t *testing.T) {
+	binding := func(conditions ...conditionsv1alpha1.Condition) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "org"},
+			},
+			Status: apisv1alpha1.APIBindingStatus{Conditions: conditions},
+		}
+	}
+	failed := conditionsv1alpha1.Condition{
+		Type:     apisv1alpha1.BindingUpToDate,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsv1alpha1.ConditionSeverityError,
+		Reason:   apisv1alpha1.NamingConflictsReason,
+		Message:  "conflict",
+	}
+	waiting := conditionsv1alpha1.Condition{
+		Type:     apisv1alpha1.InitialBindingCompleted,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsv1alpha1.ConditionSeverityInfo,
+		Reason:   apisv1alpha1.WaitingForEstablishedReason,
+	}
+	ready := conditionsv1alpha1.Condition{Type: apisv1alpha1.BindingUpToDate, Status: corev1.ConditionTrue}
+
+	tests := map[string]struct {
+		old, obj *apisv1alpha1.APIBinding
+		want     []string
+	}{
+		"new failure": {
+			old:  binding(),
+			obj:  binding(failed, waiting),
+			want: []string{"Warning NamingConflicts BindingUpToDate: conflict"},
+		},
+		"unchanged failure": {
+			old: binding(failed),
+			obj: binding(failed),
+		},
+		"changed failure message": {
+			old: binding(failed),
+			obj: binding(func() conditionsv1alpha1.Condition {
+				c := failed
+				c.Message = "another conflict"
+				return c
+			}()),
+			want: []string{"Warning NamingConflicts BindingUpToDate: another conflict"},
+		},
+		"recovery": {
+			old:  binding(failed),
+			obj:  binding(ready),
+			want: []string{"Normal BindingUpToDate BindingUpToDate is true again"},
+		},
+		"waiting to ready": {
+			old: binding(waiting),
+			obj: binding(conditionsv1alpha1.Condition{Type: apisv1alpha1.InitialBindingCompleted, Status: corev1.ConditionTrue}),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			RecordConditionChanges(context.Background(), recorder, tt.old, tt.obj)
+			close(recorder.Events)
+
+			var got []string
+			for e := range recorder.Events {
+				got = append(got, e)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClusterSink(t *testing.T) {
+	client := kcpfakekubeclient.NewSimpleClientset()
+	sink := &clusterSink{ctx: context.Background(), kubeClusterClient: client}
+
+	_, err := sink.Create(&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "foo.123", Namespace: metav1.NamespaceDefault}})
+	require.Error(t, err, "events without logical cluster must be rejected")
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo.123",
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "org"},
+		},
+		Reason: "Test",
+	}
+	_, err = sink.Create(event)
+	require.NoError(t, err)
+	require.Equal(t, "org", event.Annotations[logicalcluster.AnnotationKey], "the recorded event must not be mutated")
+
+	created, err := client.Cluster(logicalcluster.NewPath("org")).CoreV1().Events(metav1.NamespaceDefault).Get(context.Background(), "foo.123", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "Test", created.Reason)
+	require.Empty(t, created.Annotations)
+}
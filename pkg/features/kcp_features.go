@@ -48,6 +48,12 @@ const (
 	//
 	// Enable reverse tunnels to the downstream clusters through the syncers.
 	SyncerTunnel featuregate.Feature = "KCPSyncerTunnel"
+
+	// alpha: v0.11
+	//
+	// Reject APIBindings accepting permission claims that are not requested by the APIExport, or that are broader
+	// than requested. When disabled, these acceptances are only warned about.
+	StrictPermissionClaimAcceptance featuregate.Feature = "KCPStrictPermissionClaimAcceptance"
 )
 
 // DefaultFeatureGate exposes the upstream feature gate, but with our gate setting applied.
@@ -99,6 +105,8 @@ var defaultGenericControlPlaneFeatureGates = map[featuregate.Feature]featuregate
 	LocationAPI:  {Default: true, PreRelease: featuregate.Alpha},
 	SyncerTunnel: {Default: true, PreRelease: featuregate.Alpha},
 
+	StrictPermissionClaimAcceptance: {Default: false, PreRelease: featuregate.Alpha},
+
 	// inherited features from generic apiserver, relisted here to get a conflict if it is changed
 	// unintentionally on either side:
 	genericfeatures.AdvancedAuditing:                    {Default: true, PreRelease: featuregate.GA},
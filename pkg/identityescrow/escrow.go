@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package identityescrow keeps encrypted copies of APIExport identities outside of kcp, such that
+// an identity secret that got deleted by accident can be restored. Without its identity, the
+// objects of all APIBindings to an APIExport are orphaned, because the identity hash is part of
+// their storage path.
+package identityescrow
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// EncryptionKeySize is the size of the AES-256 key the identities are encrypted with.
+const EncryptionKeySize = 32
+
+// Record is an escrowed identity of an APIExport.
+type Record struct {
+	// Cluster is the logical cluster of the APIExport.
+	Cluster logicalcluster.Name `json:"cluster"`
+	// APIExport is the name of the APIExport.
+	APIExport string `json:"apiExport"`
+	// SecretNamespace is the namespace of the identity secret.
+	SecretNamespace string `json:"secretNamespace"`
+	// SecretName is the name of the identity secret.
+	SecretName string `json:"secretName"`
+	// IdentityHash is the identity hash of the APIExport, i.e. the sha256 of Key.
+	IdentityHash string `json:"identityHash"`
+	// Key is the identity key as stored in the identity secret.
+	Key []byte `json:"key"`
+}
+
+// Key returns the key the identity of the given APIExport is stored under.
+func Key(cluster logicalcluster.Name, apiExportName string) string {
+	return cluster.String() + "/" + apiExportName
+}
+
+// LoadEncryptionKey reads a base64 encoded AES-256 key from the given file.
+func LoadEncryptionKey(path string) ([]byte, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(bs)))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key in %s is not base64 encoded: %w", path, err)
+	}
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("encryption key in %s must be %d bytes, got %d", path, EncryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// Escrow encrypts identities with AES-GCM and keeps them in a Store.
+type Escrow struct {
+	store Store
+	aead  cipher.AEAD
+
+	lock sync.Mutex
+	// escrowed maps the keys of the identities written by this process to their hash, such that
+	// unchanged identities are not written again on every reconciliation.
+	escrowed map[string]string
+}
+
+// New returns an Escrow keeping the identities in store, encrypted with encryptionKey.
+func New(store Store, encryptionKey []byte) (*Escrow, error) {
+	if len(encryptionKey) != EncryptionKeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", EncryptionKeySize, len(encryptionKey))
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Escrow{
+		store:    store,
+		aead:     aead,
+		escrowed: map[string]string{},
+	}, nil
+}
+
+// Put stores the identity of record, unless it has been stored with the same hash before.
+func (e *Escrow) Put(ctx context.Context, record *Record) error {
+	if got := fmt.Sprintf("%x", sha256.Sum256(record.Key)); got != record.IdentityHash {
+		return fmt.Errorf("identity hash %q does not match the key of APIExport %s|%s", record.IdentityHash, record.Cluster, record.APIExport)
+	}
+
+	key := Key(record.Cluster, record.APIExport)
+	e.lock.Lock()
+	hash, found := e.escrowed[key]
+	e.lock.Unlock()
+	if found && hash == record.IdentityHash {
+		return nil
+	}
+
+	// The identity might have been escrowed before this process started.
+	if existing, err := e.Get(ctx, record.Cluster, record.APIExport); err == nil && existing.IdentityHash == record.IdentityHash {
+		e.remember(key, record.IdentityHash)
+		return nil
+	}
+
+	plaintext, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	// The key is authenticated too, such that an identity cannot be restored for another APIExport.
+	ciphertext := e.aead.Seal(nonce, nonce, plaintext, []byte(key))
+	if err := e.store.Put(ctx, key, ciphertext); err != nil {
+		return fmt.Errorf("failed to store identity of APIExport %s|%s: %w", record.Cluster, record.APIExport, err)
+	}
+
+	e.remember(key, record.IdentityHash)
+	return nil
+}
+
+// Get returns the escrowed identity of the given APIExport. It returns an error wrapping
+// ErrNotFound if there is none.
+func (e *Escrow) Get(ctx context.Context, cluster logicalcluster.Name, apiExportName string) (*Record, error) {
+	key := Key(cluster, apiExportName)
+	data, err := e.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < e.aead.NonceSize() {
+		return nil, fmt.Errorf("escrowed identity of APIExport %s|%s is corrupt", cluster, apiExportName)
+	}
+	nonce, ciphertext := data[:e.aead.NonceSize()], data[e.aead.NonceSize():]
+	plaintext, err := e.aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt escrowed identity of APIExport %s|%s: %w", cluster, apiExportName, err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(plaintext, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode escrowed identity of APIExport %s|%s: %w", cluster, apiExportName, err)
+	}
+	return &record, nil
+}
+
+func (e *Escrow) remember(key, hash string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.escrowed[key] = hash
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityescrow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingStore struct {
+	Store
+	puts int
+}
+
+func (s *countingStore) Put(ctx context.Context, key string, data []byte) error {
+	s.puts++
+	return s.Store.Put(ctx, key, data)
+}
+
+func newRecord(key string) *Record {
+	return &Record{
+		Cluster:         "root:org",
+		APIExport:       "widgets",
+		SecretNamespace: "kcp-system",
+		SecretName:      "widgets",
+		IdentityHash:    fmt.Sprintf("%x", sha256.Sum256([]byte(key))),
+		Key:             []byte(key),
+	}
+}
+
+func TestEscrow(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := &countingStore{Store: NewFileStore(dir)}
+	encryptionKey := bytes.Repeat([]byte{1}, EncryptionKeySize)
+
+	e, err := New(store, encryptionKey)
+	require.NoError(t, err)
+
+	_, err = e.Get(ctx, "root:org", "widgets")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	record := newRecord("abc")
+	require.NoError(t, e.Put(ctx, record))
+	require.Equal(t, 1, store.puts)
+
+	got, err := e.Get(ctx, "root:org", "widgets")
+	require.NoError(t, err)
+	require.Equal(t, record, got)
+
+	t.Log("The identity is encrypted")
+	data, err := os.ReadFile(filepath.Join(dir, "root:org", "widgets"))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "abc")
+	require.NotContains(t, string(data), record.IdentityHash)
+
+	t.Log("Unchanged identities are not written again")
+	require.NoError(t, e.Put(ctx, record))
+	require.Equal(t, 1, store.puts)
+
+	t.Log("Identities escrowed before a restart are not written again")
+	e, err = New(store, encryptionKey)
+	require.NoError(t, err)
+	require.NoError(t, e.Put(ctx, record))
+	require.Equal(t, 1, store.puts)
+
+	t.Log("Changed identities are written")
+	record = newRecord("def")
+	require.NoError(t, e.Put(ctx, record))
+	require.Equal(t, 2, store.puts)
+	got, err = e.Get(ctx, "root:org", "widgets")
+	require.NoError(t, err)
+	require.Equal(t, record, got)
+
+	t.Log("Identities with a wrong hash are rejected")
+	record.IdentityHash = "wrong"
+	require.ErrorContains(t, e.Put(ctx, record), "does not match")
+
+	t.Log("Identities cannot be decrypted with another key")
+	other, err := New(store, bytes.Repeat([]byte{2}, EncryptionKeySize))
+	require.NoError(t, err)
+	_, err = other.Get(ctx, "root:org", "widgets")
+	require.ErrorContains(t, err, "failed to decrypt")
+
+	t.Log("Identities cannot be restored for another APIExport")
+	require.NoError(t, os.Rename(filepath.Join(dir, "root:org", "widgets"), filepath.Join(dir, "root:org", "gadgets")))
+	_, err = e.Get(ctx, "root:org", "gadgets")
+	require.ErrorContains(t, err, "failed to decrypt")
+}
+
+func TestHTTPStore(t *testing.T) {
+	var lock sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, found := objects[r.URL.Path]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store, err := NewStoreForURL(server.URL + "/escrow/")
+	require.NoError(t, err)
+	e, err := New(store, bytes.Repeat([]byte{1}, EncryptionKeySize))
+	require.NoError(t, err)
+
+	_, err = e.Get(ctx, "root:org", "widgets")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	record := newRecord("abc")
+	require.NoError(t, e.Put(ctx, record))
+	require.Contains(t, objects, "/escrow/root:org/widgets")
+
+	got, err := e.Get(ctx, "root:org", "widgets")
+	require.NoError(t, err)
+	require.Equal(t, record, got)
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	key, err := LoadEncryptionKey(write("valid", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, EncryptionKeySize))+"\n"))
+	require.NoError(t, err)
+	require.Len(t, key, EncryptionKeySize)
+
+	_, err = LoadEncryptionKey(write("short", base64.StdEncoding.EncodeToString([]byte("short"))))
+	require.ErrorContains(t, err, "must be 32 bytes")
+
+	_, err = LoadEncryptionKey(write("invalid", strings.Repeat("!", 44)))
+	require.ErrorContains(t, err, "not base64 encoded")
+}
@@ -37,8 +37,11 @@ type Index interface {
 type PathRewriter func(segments []string) []string
 
 func New(rewriters []PathRewriter) *State {
+	indexers := newRegisteredIndexers()
 	return &State{
-		rewriters: rewriters,
+		rewriters:       rewriters,
+		indexers:        indexers,
+		orderedIndexers: sortedIndexers(indexers),
 
 		clusterShards:             map[logicalcluster.Name]string{},
 		shardWorkspaceNameCluster: map[string]map[logicalcluster.Name]map[string]logicalcluster.Name{},
@@ -52,7 +55,9 @@ func New(rewriters []PathRewriter) *State {
 // for every Shard, watching the Workspaces on them. It then
 // updates the workspace index, which maps logical clusters to shard URLs.
 type State struct {
-	rewriters []PathRewriter
+	rewriters       []PathRewriter
+	indexers        map[string]Indexer // registered indexer name -> indexer
+	orderedIndexers []Indexer
 
 	lock                      sync.RWMutex
 	clusterShards             map[logicalcluster.Name]string                                    // logical cluster -> shard name
@@ -63,6 +68,10 @@ type State struct {
 }
 
 func (c *State) UpsertWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
+	for _, indexer := range c.orderedIndexers {
+		indexer.UpsertWorkspace(shard, ws)
+	}
+
 	if ws.Status.Phase == corev1alpha1.LogicalClusterPhaseScheduling {
 		return
 	}
@@ -95,6 +104,10 @@ func (c *State) UpsertWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
 }
 
 func (c *State) DeleteWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
+	for _, indexer := range c.orderedIndexers {
+		indexer.DeleteWorkspace(shard, ws)
+	}
+
 	clusterName := logicalcluster.From(ws)
 
 	c.lock.RLock()
@@ -132,6 +145,10 @@ func (c *State) DeleteWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
 }
 
 func (c *State) UpsertLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster) {
+	for _, indexer := range c.orderedIndexers {
+		indexer.UpsertLogicalCluster(shard, logicalCluster)
+	}
+
 	clusterName := logicalcluster.From(logicalCluster)
 
 	c.lock.RLock()
@@ -146,6 +163,10 @@ func (c *State) UpsertLogicalCluster(shard string, logicalCluster *corev1alpha1.
 }
 
 func (c *State) DeleteLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster) {
+	for _, indexer := range c.orderedIndexers {
+		indexer.DeleteLogicalCluster(shard, logicalCluster)
+	}
+
 	clusterName := logicalcluster.From(logicalCluster)
 
 	c.lock.RLock()
@@ -174,6 +195,10 @@ func (c *State) UpsertShard(shardName, baseURL string) {
 }
 
 func (c *State) DeleteShard(shardName string) {
+	for _, indexer := range c.orderedIndexers {
+		indexer.DeleteShard(shardName)
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -188,6 +213,12 @@ func (c *State) DeleteShard(shardName string) {
 	delete(c.shardClusterParentCluster, shardName)
 }
 
+// Indexer returns the registered indexer of the given name.
+func (c *State) Indexer(name string) (Indexer, bool) {
+	indexer, found := c.indexers[name]
+	return indexer, found
+}
+
 func (c *State) Lookup(path logicalcluster.Path) (shard string, cluster logicalcluster.Name, found bool) {
 	segments := strings.Split(path.String(), ":")
 
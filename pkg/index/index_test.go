@@ -17,9 +17,11 @@ limitations under the License.
 package index
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -425,6 +427,73 @@ func TestUpsertWorkspace(t *testing.T) {
 	validateLookupOutput(t, logicalcluster.NewPath("root:org"), shard, cluster, found, "root", "44", true)
 }
 
+type labelIndexer struct {
+	lock       sync.Mutex
+	workspaces map[string]string // workspace cluster -> team label
+	shards     []string
+}
+
+func (i *labelIndexer) UpsertWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.workspaces[ws.Spec.Cluster] = ws.Labels["team"]
+}
+
+func (i *labelIndexer) DeleteWorkspace(shard string, ws *tenancyv1alpha1.Workspace) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	delete(i.workspaces, ws.Spec.Cluster)
+}
+
+func (i *labelIndexer) UpsertLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster) {
+}
+
+func (i *labelIndexer) DeleteLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster) {
+}
+
+func (i *labelIndexer) DeleteShard(shardName string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.shards = append(i.shards, shardName)
+}
+
+func TestIndexer(t *testing.T) {
+	RegisterIndexer("test-team", func() Indexer { return nil })
+	RegisterIndexer("test-team", func() Indexer {
+		return &labelIndexer{workspaces: map[string]string{}}
+	})
+	t.Cleanup(func() { UnregisterIndexer("test-team") })
+
+	target := New(nil)
+	indexer, found := target.Indexer("test-team")
+	require.True(t, found)
+	teams := indexer.(*labelIndexer)
+
+	other, _ := New(nil).Indexer("test-team")
+	require.NotSame(t, teams, other, "every index gets its own indexer instance")
+
+	_, found = target.Indexer("unknown")
+	require.False(t, found)
+
+	ws := newWorkspace("org", "root", "34")
+	ws.Labels = map[string]string{"team": "a"}
+	target.UpsertShard("root", "https://root.io")
+	target.UpsertWorkspace("root", ws)
+	require.Equal(t, map[string]string{"34": "a"}, teams.workspaces)
+
+	// label changes do not change the routing data, but reach the indexer
+	ws = ws.DeepCopy()
+	ws.Labels["team"] = "b"
+	target.UpsertWorkspace("root", ws)
+	require.Equal(t, map[string]string{"34": "b"}, teams.workspaces)
+
+	target.DeleteWorkspace("root", ws)
+	require.Empty(t, teams.workspaces)
+
+	target.DeleteShard("root")
+	require.Equal(t, []string{"root"}, teams.shards)
+}
+
 func validateLookupOutput(t *testing.T, path logicalcluster.Path, shard string, cluster logicalcluster.Name, found bool, expectedShard string, expectedCluster logicalcluster.Name, expectToFind bool) {
 	t.Helper()
 
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"github.com/kcp-dev/kcp/pkg/registry"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// Indexer maintains custom metadata about workspaces and logical clusters, next to the
+// routing data of the index, e.g. to route or annotate requests by custom labels.
+//
+// An Indexer receives every event the index receives, including those that do not change
+// the routing data. It is called without any lock of the index held, and must be safe for
+// concurrent use.
+type Indexer interface {
+	UpsertWorkspace(shard string, ws *tenancyv1alpha1.Workspace)
+	DeleteWorkspace(shard string, ws *tenancyv1alpha1.Workspace)
+	UpsertLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster)
+	DeleteLogicalCluster(shard string, logicalCluster *corev1alpha1.LogicalCluster)
+	DeleteShard(shardName string)
+}
+
+// IndexerFactory creates an Indexer for an index.
+type IndexerFactory func() Indexer
+
+var indexerFactories registry.Registry[Indexer]
+
+// RegisterIndexer registers an additional Indexer under the given name. Every index created
+// afterwards, in the front-proxy and in the local proxy of a shard, gets its own instance.
+// Registering the same name again replaces the previous factory.
+func RegisterIndexer(name string, factory IndexerFactory) {
+	indexerFactories.Register(name, factory)
+}
+
+// UnregisterIndexer removes the Indexer of the given name. Existing indexes are not changed.
+func UnregisterIndexer(name string) {
+	indexerFactories.Unregister(name)
+}
+
+// newRegisteredIndexers returns new instances of all registered indexers.
+func newRegisteredIndexers() map[string]Indexer {
+	return indexerFactories.New()
+}
+
+// sortedIndexers returns the indexers ordered by name, such that they are called in a stable order.
+func sortedIndexers(indexers map[string]Indexer) []Indexer {
+	names := registry.SortedNames(indexers)
+
+	sorted := make([]Indexer, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, indexers[name])
+	}
+	return sorted
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package indexers
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/kcp-dev/logicalcluster/v3"
@@ -61,9 +62,26 @@ func IndexAPIBindingByBoundResourceUID(obj interface{}) ([]string, error) {
 		return []string{}, fmt.Errorf("obj %T is not an APIBinding", obj)
 	}
 
-	ret := make([]string, 0, len(apiBinding.Status.BoundResources))
+	// bound CRDs serving another identity than the one named by the schema UID are named <uid>.<identity>,
+	// and the one of an identity rotation in progress exists before the bound resources are switched to it.
+	var rotation struct {
+		To string `json:"to"`
+	}
+	if value, found := apiBinding.Annotations[apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey]; found {
+		if err := json.Unmarshal([]byte(value), &rotation); err != nil {
+			return []string{}, fmt.Errorf("failed to unmarshal %s annotation: %w", apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey, err)
+		}
+	}
+
+	ret := make([]string, 0, 3*len(apiBinding.Status.BoundResources))
 	for _, r := range apiBinding.Status.BoundResources {
 		ret = append(ret, r.Schema.UID)
+		if r.Schema.IdentityHash != "" {
+			ret = append(ret, r.Schema.UID+"."+r.Schema.IdentityHash)
+		}
+		if rotation.To != "" && rotation.To != r.Schema.IdentityHash {
+			ret = append(ret, r.Schema.UID+"."+rotation.To)
+		}
 	}
 
 	return ret, nil
@@ -91,6 +109,28 @@ func APIBindingBoundResourceValue(clusterName logicalcluster.Name, group, resour
 	return fmt.Sprintf("%s|%s.%s", clusterName, resource, group)
 }
 
+// APIBindingByServedResources is the name of the index of APIBindings by the group resources they serve,
+// i.e. by the alias of a bound resource if any.
+const APIBindingByServedResources = "byServedResources"
+
+// IndexAPIBindingByServedResources is an index function that indexes an APIBinding by the group resources
+// it serves in its logical cluster. Use APIBindingBoundResourceValue for the index values.
+func IndexAPIBindingByServedResources(obj interface{}) ([]string, error) {
+	apiBinding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not an APIBinding", obj)
+	}
+
+	clusterName := logicalcluster.From(apiBinding)
+
+	ret := make([]string, 0, len(apiBinding.Status.BoundResources))
+	for _, r := range apiBinding.Status.BoundResources {
+		ret = append(ret, APIBindingBoundResourceValue(clusterName, r.Group, r.ServedResource()))
+	}
+
+	return ret, nil
+}
+
 const APIBindingsByAPIExport = "APIBindingByAPIExport"
 
 // IndexAPIBindingByAPIExport indexes the APIBindings by their APIExport's Reference Path and Name.
@@ -20,6 +20,7 @@ import (
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
@@ -35,9 +36,13 @@ const (
 	APIExportByClaimedIdentities = "APIExportByClaimedIdentities"
 )
 
-// IndexAPIExportByIdentity is an index function that indexes an APIExport by its identity hash.
+// IndexAPIExportByIdentity is an index function that indexes an APIExport by its identity hash, and by the
+// next identity hash while the identity is rotated.
 func IndexAPIExportByIdentity(obj interface{}) ([]string, error) {
 	apiExport := obj.(*apisv1alpha1.APIExport)
+	if apiExport.Status.NextIdentityHash != "" {
+		return []string{apiExport.Status.IdentityHash, apiExport.Status.NextIdentityHash}, nil
+	}
 	return []string{apiExport.Status.IdentityHash}, nil
 }
 
@@ -50,16 +55,15 @@ func IndexAPIExportBySecret(obj interface{}) ([]string, error) {
 		return []string{}, nil
 	}
 
-	ref := apiExport.Spec.Identity.SecretRef
-	if ref == nil {
-		return []string{}, nil
-	}
-
-	if ref.Namespace == "" || ref.Name == "" {
-		return []string{}, nil
+	var ret []string
+	for _, ref := range []*corev1.SecretReference{apiExport.Spec.Identity.SecretRef, apiExport.Spec.Identity.NextSecretRef} {
+		if ref == nil || ref.Namespace == "" || ref.Name == "" {
+			continue
+		}
+		ret = append(ret, kcpcache.ToClusterAwareKey(logicalcluster.From(apiExport).String(), ref.Namespace, ref.Name))
 	}
 
-	return []string{kcpcache.ToClusterAwareKey(logicalcluster.From(apiExport).String(), ref.Namespace, ref.Name)}, nil
+	return ret, nil
 }
 
 // IndexAPIExportByClaimedIdentities is an index function that indexes an APIExport by its permission claims' identity
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diskcache provides informers keeping the full objects in an on-disk store, and only
+// their keys and index values in memory. They trade latency of every read for a much lower memory
+// footprint of informers on very many objects, e.g. wildcard informers on replicated objects.
+package diskcache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"go.etcd.io/bbolt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/third_party/informers"
+)
+
+// Cache is the on-disk store of the objects of informers. Its content is rebuilt by the informers
+// on every start, i.e. nothing written is kept across restarts.
+type Cache struct {
+	db *bbolt.DB
+}
+
+// Open creates the store in the given directory, removing the content of a previous run.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "informers.db")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{
+		Timeout: time.Second,
+		// The content is thrown away on restart, hence there is no point in syncing it.
+		NoSync:         true,
+		NoFreelistSync: true,
+		FreelistType:   bbolt.FreelistMapType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open informer cache %s: %w", path, err)
+	}
+	return &Cache{db: db}, nil
+}
+
+// Close closes the store.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// NewIndexer returns a cache.Indexer keeping the objects of the type of exampleObject in the store,
+// keyed by kcpcache.MetaClusterNamespaceKeyFunc. Each indexer needs a unique name.
+func (c *Cache) NewIndexer(name string, exampleObject runtime.Object, indexers cache.Indexers) cache.Indexer {
+	return newIndexer(c.db, name, exampleObject, indexers)
+}
+
+// NewInformer returns a wildcard informer keeping its objects in the store. Each informer needs a
+// unique name. The cluster index is always added.
+func (c *Cache) NewInformer(name string, lw cache.ListerWatcher, exampleObject runtime.Object, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+	return informers.NewSharedIndexInformerWithIndexer(lw, exampleObject, resyncPeriod, c.NewIndexer(name, exampleObject, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+	}))
+}
+
+// ListWatch returns a cache.ListerWatcher from the List and Watch methods of a typed client.
+func ListWatch[L runtime.Object](listFunc func(context.Context, metav1.ListOptions) (L, error), watchFunc func(context.Context, metav1.ListOptions) (watch.Interface, error)) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return listFunc(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return watchFunc(context.TODO(), options)
+		},
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"go.etcd.io/bbolt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// indexer is a cache.Indexer keeping the objects in a bbolt bucket, and only their keys and
+// index values in memory. Every read decodes the objects from disk, i.e. callers get their
+// own copies.
+type indexer struct {
+	db        *bbolt.DB
+	bucket    []byte
+	newObject func() runtime.Object
+
+	lock sync.RWMutex
+	// values holds the index values of every stored object by key.
+	values   map[string]map[string][]string
+	indexers cache.Indexers
+	indices  cache.Indices
+}
+
+var _ cache.Indexer = &indexer{}
+
+func newIndexer(db *bbolt.DB, bucket string, exampleObject runtime.Object, indexers cache.Indexers) *indexer {
+	t := reflect.TypeOf(exampleObject).Elem()
+	return &indexer{
+		db:     db,
+		bucket: []byte(bucket),
+		newObject: func() runtime.Object {
+			return reflect.New(t).Interface().(runtime.Object)
+		},
+		values:   map[string]map[string][]string{},
+		indexers: indexers,
+		indices:  cache.Indices{},
+	}
+}
+
+func (i *indexer) Add(obj interface{}) error {
+	return i.Update(obj)
+}
+
+func (i *indexer) Update(obj interface{}) error {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		return cache.KeyError{Obj: obj, Err: err}
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if err := i.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(i.bucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	}); err != nil {
+		return err
+	}
+	i.updateIndices(key, obj)
+	return nil
+}
+
+func (i *indexer) Delete(obj interface{}) error {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		return cache.KeyError{Obj: obj, Err: err}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if _, found := i.values[key]; !found {
+		return nil
+	}
+	if err := i.db.Update(func(tx *bbolt.Tx) error {
+		if b := tx.Bucket(i.bucket); b != nil {
+			return b.Delete([]byte(key))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	i.updateIndices(key, nil)
+	return nil
+}
+
+func (i *indexer) List() []interface{} {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	objs := make([]interface{}, 0, len(i.values))
+	if err := i.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(i.bucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			obj, err := i.decode(v)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+			return nil
+		})
+	}); err != nil {
+		utilruntime.HandleError(err)
+	}
+	return objs
+}
+
+func (i *indexer) ListKeys() []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	keys := make([]string, 0, len(i.values))
+	for key := range i.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (i *indexer) Get(obj interface{}) (item interface{}, exists bool, err error) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, cache.KeyError{Obj: obj, Err: err}
+	}
+	return i.GetByKey(key)
+}
+
+func (i *indexer) GetByKey(key string) (item interface{}, exists bool, err error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	objs, err := i.getLockHeld(key)
+	if err != nil || len(objs) == 0 {
+		return nil, false, err
+	}
+	return objs[0], true, nil
+}
+
+func (i *indexer) Replace(list []interface{}, resourceVersion string) error {
+	type item struct {
+		key  string
+		obj  interface{}
+		data []byte
+	}
+	items := make([]item, 0, len(list))
+	for _, obj := range list {
+		key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+		if err != nil {
+			return cache.KeyError{Obj: obj, Err: err}
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		items = append(items, item{key: key, obj: obj, data: data})
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if err := i.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket(i.bucket) != nil {
+			if err := tx.DeleteBucket(i.bucket); err != nil {
+				return err
+			}
+		}
+		b, err := tx.CreateBucket(i.bucket)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := b.Put([]byte(item.key), item.data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	i.values = make(map[string]map[string][]string, len(items))
+	i.indices = cache.Indices{}
+	for _, item := range items {
+		i.updateIndices(item.key, item.obj)
+	}
+	return nil
+}
+
+func (i *indexer) Resync() error {
+	return nil
+}
+
+func (i *indexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	indexFunc := i.indexers[indexName]
+	if indexFunc == nil {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+	indexedValues, err := indexFunc(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := sets.NewString()
+	for _, value := range indexedValues {
+		keys.Insert(i.indices[indexName][value].UnsortedList()...)
+	}
+	return i.getLockHeld(keys.UnsortedList()...)
+}
+
+func (i *indexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.indexers[indexName] == nil {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+	return i.indices[indexName][indexedValue].List(), nil
+}
+
+func (i *indexer) ListIndexFuncValues(indexName string) []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	values := make([]string, 0, len(i.indices[indexName]))
+	for value := range i.indices[indexName] {
+		values = append(values, value)
+	}
+	return values
+}
+
+func (i *indexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.indexers[indexName] == nil {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+	return i.getLockHeld(i.indices[indexName][indexedValue].UnsortedList()...)
+}
+
+func (i *indexer) GetIndexers() cache.Indexers {
+	return i.indexers
+}
+
+func (i *indexer) AddIndexers(newIndexers cache.Indexers) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if len(i.values) > 0 {
+		return fmt.Errorf("cannot add indexers to running index")
+	}
+
+	oldKeys := sets.StringKeySet(i.indexers)
+	newKeys := sets.StringKeySet(newIndexers)
+	if oldKeys.HasAny(newKeys.List()...) {
+		return fmt.Errorf("indexer conflict: %v", oldKeys.Intersection(newKeys))
+	}
+
+	for k, v := range newIndexers {
+		i.indexers[k] = v
+	}
+	return nil
+}
+
+// updateIndices replaces the index values of the object with the given key by those of obj,
+// or removes them if obj is nil. The caller must hold the write lock.
+func (i *indexer) updateIndices(key string, obj interface{}) {
+	oldValues := i.values[key]
+	if obj == nil {
+		delete(i.values, key)
+	}
+
+	newValues := make(map[string][]string, len(i.indexers))
+	for name, indexFunc := range i.indexers {
+		if obj != nil {
+			values, err := indexFunc(obj)
+			if err != nil {
+				panic(fmt.Errorf("unable to calculate an index entry for key %q on index %q: %v", key, name, err))
+			}
+			newValues[name] = values
+		}
+
+		index := i.indices[name]
+		if index == nil {
+			index = cache.Index{}
+			i.indices[name] = index
+		}
+		for _, value := range oldValues[name] {
+			if set := index[value]; set != nil {
+				set.Delete(key)
+				if set.Len() == 0 {
+					delete(index, value)
+				}
+			}
+		}
+		for _, value := range newValues[name] {
+			if index[value] == nil {
+				index[value] = sets.NewString()
+			}
+			index[value].Insert(key)
+		}
+	}
+
+	if obj != nil {
+		i.values[key] = newValues
+	}
+}
+
+// getLockHeld decodes the objects with the given keys. Keys that are not stored are skipped.
+// The caller must hold the read lock.
+func (i *indexer) getLockHeld(keys ...string) ([]interface{}, error) {
+	objs := make([]interface{}, 0, len(keys))
+	err := i.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(i.bucket)
+		if b == nil {
+			return nil
+		}
+		for _, key := range keys {
+			data := b.Get([]byte(key))
+			if data == nil {
+				continue
+			}
+			obj, err := i.decode(data)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+		}
+		return nil
+	})
+	return objs, err
+}
+
+func (i *indexer) decode(data []byte) (runtime.Object, error) {
+	obj := i.newObject()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskcache
+
+import (
+	"sort"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newConfigMap(cluster, namespace, name, value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		},
+		Data: map[string]string{"value": value},
+	}
+}
+
+func names(objs []interface{}) []string {
+	ret := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		cm := obj.(*corev1.ConfigMap)
+		ret = append(ret, logicalcluster.From(cm).String()+"/"+cm.Name+"="+cm.Data["value"])
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestIndexer(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() }) //nolint:errcheck
+
+	indexer := store.NewIndexer("configmaps", &corev1.ConfigMap{}, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+	})
+	require.NoError(t, indexer.AddIndexers(cache.Indexers{
+		"byValue": func(obj interface{}) ([]string, error) {
+			return []string{obj.(*corev1.ConfigMap).Data["value"]}, nil
+		},
+	}))
+
+	t.Log("Objects are read back from disk")
+	require.NoError(t, indexer.Add(newConfigMap("root", "default", "a", "1")))
+	require.NoError(t, indexer.Add(newConfigMap("root", "default", "b", "2")))
+	require.NoError(t, indexer.Add(newConfigMap("root:org", "default", "a", "1")))
+	require.Equal(t, []string{"root/a=1", "root/b=2", "root:org/a=1"}, names(indexer.List()))
+	require.ElementsMatch(t, []string{"root|default/a", "root|default/b", "root:org|default/a"}, indexer.ListKeys())
+
+	obj, found, err := indexer.GetByKey("root|default/b")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "2", obj.(*corev1.ConfigMap).Data["value"])
+
+	t.Log("Returned objects are copies")
+	obj.(*corev1.ConfigMap).Data["value"] = "mutated"
+	obj, _, err = indexer.Get(newConfigMap("root", "default", "b", ""))
+	require.NoError(t, err)
+	require.Equal(t, "2", obj.(*corev1.ConfigMap).Data["value"])
+
+	t.Log("Objects are found by index")
+	objs, err := indexer.ByIndex(kcpcache.ClusterIndexName, "root")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root/a=1", "root/b=2"}, names(objs))
+	objs, err = indexer.ByIndex("byValue", "1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root/a=1", "root:org/a=1"}, names(objs))
+	objs, err = indexer.Index("byValue", newConfigMap("root", "default", "c", "2"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"root/b=2"}, names(objs))
+
+	t.Log("Updates move objects between index values")
+	require.NoError(t, indexer.Update(newConfigMap("root", "default", "a", "2")))
+	keys, err := indexer.IndexKeys("byValue", "2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root|default/a", "root|default/b"}, keys)
+	require.ElementsMatch(t, []string{"1", "2"}, indexer.ListIndexFuncValues("byValue"))
+
+	t.Log("Deleted objects are gone from disk and the indices")
+	require.NoError(t, indexer.Delete(cache.DeletedFinalStateUnknown{Key: "root:org|default/a"}))
+	_, found, err = indexer.GetByKey("root:org|default/a")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Equal(t, []string{"2"}, indexer.ListIndexFuncValues("byValue"))
+
+	t.Log("Replace drops everything else")
+	require.NoError(t, indexer.Replace([]interface{}{newConfigMap("root:org", "default", "c", "3")}, "42"))
+	require.Equal(t, []string{"root:org/c=3"}, names(indexer.List()))
+	require.Equal(t, []string{"3"}, indexer.ListIndexFuncValues("byValue"))
+	objs, err = indexer.ByIndex(kcpcache.ClusterIndexName, "root")
+	require.NoError(t, err)
+	require.Empty(t, objs)
+
+	t.Log("Indexers cannot be added to a filled indexer")
+	require.Error(t, indexer.AddIndexers(cache.Indexers{"other": kcpcache.ClusterIndexFunc}))
+
+	t.Log("Indexers of the same store are independent")
+	other := store.NewIndexer("other", &corev1.ConfigMap{}, cache.Indexers{})
+	require.Empty(t, other.List())
+}
@@ -49,7 +49,7 @@ const (
 
 // WithReconciler adds the reconciler name to the logger.
 func WithReconciler(logger logr.Logger, reconciler string) logr.Logger {
-	return logger.WithValues(ReconcilerKey, reconciler)
+	return withDebugOverrides(logger).WithValues(ReconcilerKey, reconciler)
 }
 
 // WithQueueKey adds the queue key to the logger.
@@ -64,7 +64,7 @@ type Object interface {
 
 // WithObject adds object identifiers to the logger.
 func WithObject(logger logr.Logger, obj Object) logr.Logger {
-	return logger.WithValues(From(obj)...)
+	return withDebugOverrides(logger).WithValues(From(obj)...)
 }
 
 // From provides the structured logging fields that identify an object, prefixing with the resource name.
@@ -116,7 +116,7 @@ func FromUserPrefix(u user.Info, prefix string) []interface{} {
 
 // WithCluster adds requested cluster identifiers to the logger.
 func WithCluster(logger logr.Logger, cluster *request.Cluster) logr.Logger {
-	return logger.WithValues(
+	return withDebugOverrides(logger).WithValues(
 		"clusterName", cluster.Name.String(),
 		"partialMetadata", cluster.PartialMetadataRequest,
 		"wildcard", cluster.Wildcard,
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// MaxDebugOverrideDuration is the maximal duration of a DebugOverride.
+	MaxDebugOverrideDuration = 24 * time.Hour
+
+	// maxCapturedLines is the number of log lines kept per capturing DebugOverride. Older lines are dropped.
+	maxCapturedLines = 10000
+
+	// captureRetention is how long captured log lines can be retrieved after the DebugOverride expired.
+	captureRetention = time.Hour
+
+	// maxVerbosity bounds the verbosity probing of klog.
+	maxVerbosity = 10
+)
+
+// DebugOverride raises the verbosity of the logs of a component, of a logical cluster or of both
+// for a bounded duration, without changing the global verbosity.
+type DebugOverride struct {
+	// ID identifies the override. It is assigned when the override is added.
+	ID string `json:"id,omitempty"`
+	// Component is the reconciler whose logs are raised, e.g. "kcp-workspace". Empty matches all components.
+	Component string `json:"component,omitempty"`
+	// Cluster is the logical cluster name whose logs are raised. Empty matches all logical clusters.
+	Cluster string `json:"cluster,omitempty"`
+	// Verbosity is the verbosity matching logs are written with.
+	Verbosity int `json:"verbosity"`
+	// Capture keeps the matching log lines in memory, to be retrieved with CapturedLogs.
+	Capture bool `json:"capture,omitempty"`
+	// Expires is the time the override ends.
+	Expires time.Time `json:"expires"`
+}
+
+type debugOverride struct {
+	DebugOverride
+
+	lock     sync.Mutex
+	captured []string
+	dropped  int
+}
+
+func (o *debugOverride) matches(component, cluster string, level int, now time.Time) bool {
+	if level > o.Verbosity || now.After(o.Expires) {
+		return false
+	}
+	if o.Component != "" && o.Component != component {
+		return false
+	}
+	if o.Cluster != "" && o.Cluster != cluster {
+		return false
+	}
+	return true
+}
+
+func (o *debugOverride) capture(line string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if len(o.captured) >= maxCapturedLines {
+		o.captured = o.captured[1:]
+		o.dropped++
+	}
+	o.captured = append(o.captured, line)
+}
+
+// debugOverrides holds the active []*debugOverride. It is read on every log call and hence
+// copied on write.
+var (
+	debugOverrides     atomic.Value
+	debugOverridesLock sync.Mutex
+)
+
+func init() {
+	debugOverrides.Store([]*debugOverride{})
+}
+
+func activeDebugOverrides() []*debugOverride {
+	return debugOverrides.Load().([]*debugOverride)
+}
+
+// AddDebugOverride activates the given override for the given duration, and returns it with ID and
+// expiry time set.
+func AddDebugOverride(o DebugOverride, duration time.Duration) (DebugOverride, error) {
+	if o.Verbosity < 0 {
+		return DebugOverride{}, fmt.Errorf("verbosity must not be negative")
+	}
+	if duration <= 0 || duration > MaxDebugOverrideDuration {
+		return DebugOverride{}, fmt.Errorf("duration must be positive and at most %s", MaxDebugOverrideDuration)
+	}
+	o.ID = rand.String(8)
+	o.Expires = time.Now().Add(duration)
+
+	debugOverridesLock.Lock()
+	defer debugOverridesLock.Unlock()
+
+	now := time.Now()
+	overrides := []*debugOverride{{DebugOverride: o}}
+	for _, existing := range activeDebugOverrides() {
+		if now.Before(existing.Expires.Add(captureRetention)) {
+			overrides = append(overrides, existing)
+		}
+	}
+	debugOverrides.Store(overrides)
+
+	return o, nil
+}
+
+// RemoveDebugOverride removes the override with the given ID, including its captured log lines.
+// It returns false if there is no such override.
+func RemoveDebugOverride(id string) bool {
+	debugOverridesLock.Lock()
+	defer debugOverridesLock.Unlock()
+
+	found := false
+	overrides := []*debugOverride{}
+	for _, existing := range activeDebugOverrides() {
+		if existing.ID == id {
+			found = true
+			continue
+		}
+		overrides = append(overrides, existing)
+	}
+	debugOverrides.Store(overrides)
+
+	return found
+}
+
+// DebugOverrides returns the overrides, including the expired ones whose captured log lines
+// can still be retrieved.
+func DebugOverrides() []DebugOverride {
+	var ret []DebugOverride
+	for _, o := range activeDebugOverrides() {
+		ret = append(ret, o.DebugOverride)
+	}
+	return ret
+}
+
+// CapturedLogs returns the log lines captured by the override with the given ID, and the number
+// of lines dropped because the capture buffer was full. It returns false if there is no such override.
+func CapturedLogs(id string) ([]string, int, bool) {
+	for _, o := range activeDebugOverrides() {
+		if o.ID != id {
+			continue
+		}
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		return append([]string(nil), o.captured...), o.dropped, true
+	}
+	return nil, 0, false
+}
+
+// Verbosity returns the global klog verbosity.
+func Verbosity() int {
+	v := 0
+	for v < maxVerbosity && klog.V(klog.Level(v+1)).Enabled() {
+		v++
+	}
+	return v
+}
+
+// SetVerbosity changes the global klog verbosity.
+func SetVerbosity(v int) error {
+	if v < 0 || v > maxVerbosity {
+		return fmt.Errorf("verbosity must be between 0 and %d", maxVerbosity)
+	}
+	var level klog.Level
+	return level.Set(strconv.Itoa(v))
+}
+
+// withDebugOverrides makes the logger subject to the active DebugOverrides.
+func withDebugOverrides(logger logr.Logger) logr.Logger {
+	sink := logger.GetSink()
+	if _, ok := sink.(*debugSink); ok || sink == nil {
+		return logger
+	}
+	if withCallDepth, ok := sink.(logr.CallDepthLogSink); ok {
+		// skip the frame of the debugSink
+		sink = withCallDepth.WithCallDepth(1)
+	}
+	return logger.WithSink(&debugSink{delegate: sink})
+}
+
+// debugSink writes the log lines matching a DebugOverride although the delegate is not enabled
+// for their level, and captures them if requested. The component and the logical cluster are
+// taken from the values added to the logger.
+type debugSink struct {
+	delegate  logr.LogSink
+	component string
+	cluster   string
+	// values are the values added to the logger, for captured log lines.
+	values []interface{}
+}
+
+var _ logr.LogSink = &debugSink{}
+var _ logr.CallDepthLogSink = &debugSink{}
+
+func (s *debugSink) Init(info logr.RuntimeInfo) {
+	s.delegate.Init(info)
+}
+
+func (s *debugSink) Enabled(level int) bool {
+	if s.delegate.Enabled(level) {
+		return true
+	}
+	overrides := activeDebugOverrides()
+	if len(overrides) == 0 {
+		return false
+	}
+	now := time.Now()
+	for _, o := range overrides {
+		if o.matches(s.component, s.cluster, level, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *debugSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	forced := false
+	if overrides := activeDebugOverrides(); len(overrides) > 0 {
+		now := time.Now()
+		for _, o := range overrides {
+			if !o.matches(s.component, s.cluster, level, now) {
+				continue
+			}
+			forced = true
+			if o.Capture {
+				o.capture(s.format(now, fmt.Sprintf("v=%d", level), msg, keysAndValues))
+			}
+		}
+	}
+
+	if s.delegate.Enabled(level) {
+		s.delegate.Info(level, msg, keysAndValues...)
+	} else if forced {
+		// the delegate checks the level again, so write with level 0 and record the original one
+		s.delegate.Info(0, msg, append([]interface{}{"v", level}, keysAndValues...)...)
+	}
+}
+
+func (s *debugSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if overrides := activeDebugOverrides(); len(overrides) > 0 {
+		now := time.Now()
+		for _, o := range overrides {
+			if o.Capture && o.matches(s.component, s.cluster, 0, now) {
+				o.capture(s.format(now, "error", msg, append([]interface{}{"err", err}, keysAndValues...)))
+			}
+		}
+	}
+	s.delegate.Error(err, msg, keysAndValues...)
+}
+
+func (s *debugSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	ret := &debugSink{
+		delegate:  s.delegate.WithValues(keysAndValues...),
+		component: s.component,
+		cluster:   s.cluster,
+		values:    append(append([]interface{}(nil), s.values...), keysAndValues...),
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			continue
+		}
+		value := fmt.Sprint(keysAndValues[i+1])
+		switch {
+		case key == ReconcilerKey:
+			ret.component = value
+		case key == WorkspaceKey || key == "clusterName" || strings.HasSuffix(key, "."+WorkspaceKey):
+			ret.cluster = value
+		}
+	}
+	return ret
+}
+
+func (s *debugSink) WithName(name string) logr.LogSink {
+	ret := *s
+	ret.delegate = s.delegate.WithName(name)
+	return &ret
+}
+
+func (s *debugSink) WithCallDepth(depth int) logr.LogSink {
+	delegate, ok := s.delegate.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	ret := *s
+	ret.delegate = delegate.WithCallDepth(depth)
+	return &ret
+}
+
+func (s *debugSink) format(now time.Time, level, msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %q", now.UTC().Format(time.RFC3339Nano), level, msg)
+	kvs := append(append([]interface{}(nil), s.values...), keysAndValues...)
+	for i := 0; i < len(kvs); i += 2 {
+		if i+1 < len(kvs) {
+			fmt.Fprintf(&b, " %v=%q", kvs[i], fmt.Sprint(kvs[i+1]))
+		} else {
+			fmt.Fprintf(&b, " %v=<missing>", kvs[i])
+		}
+	}
+	return b.String()
+}
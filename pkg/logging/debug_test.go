@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDebugOverrides(t *testing.T) {
+	var written []string
+	logger := funcr.New(func(prefix, args string) {
+		written = append(written, args)
+	}, funcr.Options{Verbosity: 2})
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cm",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "abc"},
+		},
+	}
+	workspaceLogger := WithReconciler(logger, "kcp-workspace")
+	objectLogger := WithObject(WithReconciler(logger, "kcp-apibinding"), configMap)
+
+	t.Log("Without overrides, only the global verbosity applies")
+	workspaceLogger.V(2).Info("a")
+	workspaceLogger.V(4).Info("b")
+	require.Len(t, written, 1)
+
+	t.Log("A component override raises the verbosity of that component only")
+	override, err := AddDebugOverride(DebugOverride{Component: "kcp-workspace", Verbosity: 4, Capture: true}, time.Minute)
+	require.NoError(t, err)
+	defer RemoveDebugOverride(override.ID)
+	workspaceLogger.V(4).Info("c")
+	workspaceLogger.V(5).Info("d")
+	objectLogger.V(4).Info("e")
+	require.Len(t, written, 2)
+	require.Contains(t, written[1], `"msg"="c"`)
+
+	lines, dropped, found := CapturedLogs(override.ID)
+	require.True(t, found)
+	require.Zero(t, dropped)
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], `v=4 "c" reconciler="kcp-workspace"`)
+
+	t.Log("A cluster override raises the verbosity of the logs about objects in that logical cluster")
+	clusterOverride, err := AddDebugOverride(DebugOverride{Cluster: "abc", Verbosity: 6}, time.Minute)
+	require.NoError(t, err)
+	defer RemoveDebugOverride(clusterOverride.ID)
+	objectLogger.V(6).Info("f")
+	workspaceLogger.V(6).Info("g")
+	require.Len(t, written, 3)
+	require.Contains(t, written[2], `"msg"="f"`)
+
+	t.Log("Removed overrides do not apply anymore")
+	require.True(t, RemoveDebugOverride(clusterOverride.ID))
+	objectLogger.V(6).Info("h")
+	require.Len(t, written, 3)
+	_, _, found = CapturedLogs(clusterOverride.ID)
+	require.False(t, found)
+
+	t.Log("Overrides are bounded")
+	_, err = AddDebugOverride(DebugOverride{Verbosity: 4}, 2*MaxDebugOverrideDuration)
+	require.Error(t, err)
+}
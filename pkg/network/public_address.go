@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package network restricts the connections kcp establishes on behalf of tenants and API
+// providers, e.g. to webhooks, to public addresses.
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// ErrNonPublicAddress is returned for addresses that are not public.
+var ErrNonPublicAddress = errors.New("address is not public")
+
+// nonPublicNetworks are special-purpose networks not covered by the classification methods of net.IP.
+var nonPublicNetworks = mustParseCIDRs(
+	// "this network", RFC 791
+	"0.0.0.0/8",
+	// shared address space of carrier-grade NAT, RFC 6598. Used by several CNIs, and by cloud
+	// metadata endpoints like 100.100.100.200.
+	"100.64.0.0/10",
+	// IETF protocol assignments, RFC 6890
+	"192.0.0.0/24",
+	// benchmarking, RFC 2544
+	"198.18.0.0/15",
+	// reserved, RFC 1112, including the limited broadcast address
+	"240.0.0.0/4",
+	// NAT64 well-known prefix, RFC 6052, embedding arbitrary IPv4 addresses
+	"64:ff9b::/96",
+	// local-use NAT64 prefix, RFC 8215
+	"64:ff9b:1::/48",
+)
+
+// CheckPublicAddress returns ErrNonPublicAddress if the IP of the given host:port address is a
+// loopback, private, link-local, multicast or other non-public address, unless it is in one of
+// the allowed networks.
+func CheckPublicAddress(address string, allowedNetworks []*net.IPNet) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %q", host)
+	}
+	for _, allowed := range allowedNetworks {
+		if allowed.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrNonPublicAddress
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return ErrNonPublicAddress
+		}
+	}
+	return nil
+}
+
+// PublicAddressDialControl returns a net.Dialer Control function refusing connections to
+// addresses that are not public, unless they are in one of the allowed networks. It runs after
+// name resolution, i.e. for the address actually dialed, and for every connection.
+func PublicAddressDialControl(allowedNetworks []*net.IPNet) func(network, address string, c syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		return CheckPublicAddress(address, allowedNetworks)
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	ret := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPublicAddress(t *testing.T) {
+	_, allowed, err := net.ParseCIDR("10.96.0.0/12")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "93.184.216.34:443"},
+		{address: "8.8.8.8:443"},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443"},
+		{address: "10.96.0.10:443"},
+		{address: "127.0.0.1:443", wantErr: true},
+		{address: "[::1]:443", wantErr: true},
+		{address: "10.0.0.1:443", wantErr: true},
+		{address: "192.168.1.1:443", wantErr: true},
+		{address: "169.254.169.254:80", wantErr: true},
+		{address: "[fe80::1]:443", wantErr: true},
+		{address: "0.0.0.0:443", wantErr: true},
+		{address: "0.1.2.3:443", wantErr: true},
+		{address: "100.64.0.1:443", wantErr: true},
+		{address: "100.100.100.200:80", wantErr: true},
+		{address: "192.0.0.8:443", wantErr: true},
+		{address: "198.18.0.1:443", wantErr: true},
+		{address: "198.19.255.254:443", wantErr: true},
+		{address: "255.255.255.255:443", wantErr: true},
+		{address: "[fd00::1]:443", wantErr: true},
+		{address: "[::ffff:10.0.0.1]:443", wantErr: true},
+		{address: "[64:ff9b::7f00:1]:443", wantErr: true},
+		{address: "[64:ff9b:1::a00:1]:443", wantErr: true},
+	} {
+		t.Run(tt.address, func(t *testing.T) {
+			err := CheckPublicAddress(tt.address, []*net.IPNet{allowed})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrNonPublicAddress)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
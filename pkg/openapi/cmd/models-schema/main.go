@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// models-schema prints the OpenAPI v2 models of the kcp APIs, including the
+// x-kubernetes-group-version-kind extensions. It is the input of
+// applyconfiguration-gen to generate the Extract<Kind> functions.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	endpointsopenapi "k8s.io/apiserver/pkg/endpoints/openapi"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/kube-openapi/pkg/common"
+	k8sopenapi "k8s.io/kubernetes/pkg/generated/openapi"
+
+	generatedopenapi "github.com/kcp-dev/kcp/pkg/openapi"
+	kcpscheme "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/scheme"
+)
+
+func main() {
+	if err := output(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func output() error {
+	// the kcp APIs reference some Kubernetes types, e.g. SecretReference.
+	getDefinitions := func(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+		defs := k8sopenapi.GetOpenAPIDefinitions(ref)
+		for k, v := range generatedopenapi.GetOpenAPIDefinitions(ref) {
+			defs[k] = v
+		}
+		return defs
+	}
+	config := genericapiserver.DefaultOpenAPIConfig(getDefinitions, endpointsopenapi.NewDefinitionNamer(kcpscheme.Scheme))
+
+	var names []string
+	for _, t := range kcpscheme.Scheme.AllKnownTypes() {
+		if !strings.HasPrefix(t.PkgPath(), "github.com/kcp-dev/kcp/sdk/apis/") {
+			continue
+		}
+		names = append(names, t.PkgPath()+"."+t.Name())
+	}
+	sort.Strings(names)
+
+	swagger, err := builder.BuildOpenAPIDefinitionsForResources(config, names...)
+	if err != nil {
+		return err
+	}
+	swagger.Info.Title = "kcp"
+	swagger.Info.Version = "unversioned"
+
+	data, err := json.MarshalIndent(swagger, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaim
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// Narrow returns the claim to accept for a claim requested by an APIExport, given a claim that
+// is accepted up front, e.g. by a WorkspaceType. The accepted claim must either equal the requested
+// claim, or narrow it down to a subset of its resource selectors. It returns false otherwise, e.g.
+// if the requested claim has been widened beyond what was accepted.
+func Narrow(accepted, requested apisv1alpha1.PermissionClaim) (apisv1alpha1.PermissionClaim, bool) {
+	if equality.Semantic.DeepEqual(accepted, requested) {
+		return requested, true
+	}
+	if !accepted.Equal(requested) || accepted.All || len(accepted.ResourceSelector) == 0 {
+		return apisv1alpha1.PermissionClaim{}, false
+	}
+
+	// everything but the scope must match
+	acceptedRest, requestedRest := accepted, requested
+	acceptedRest.All, acceptedRest.ResourceSelector = false, nil
+	requestedRest.All, requestedRest.ResourceSelector = false, nil
+	if !equality.Semantic.DeepEqual(acceptedRest, requestedRest) {
+		return apisv1alpha1.PermissionClaim{}, false
+	}
+
+	if !requested.All {
+		for _, selector := range accepted.ResourceSelector {
+			found := false
+			for _, requestedSelector := range requested.ResourceSelector {
+				if equality.Semantic.DeepEqual(selector, requestedSelector) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return apisv1alpha1.PermissionClaim{}, false
+			}
+		}
+	}
+
+	narrowed := *requested.DeepCopy()
+	narrowed.All = false
+	narrowed.ResourceSelector = accepted.DeepCopy().ResourceSelector
+	return narrowed, true
+}
+
+// Covers returns whether a claim accepted by an APIBinding is the claim requested by the APIExport,
+// or a narrowed down version of it. Other than with Narrow, the accepted claim may also be
+// status-only while the requested claim is not.
+func Covers(accepted, requested apisv1alpha1.PermissionClaim) bool {
+	if accepted.StatusOnly && !requested.StatusOnly {
+		accepted.StatusOnly = false
+	}
+	_, ok := Narrow(accepted, requested)
+	return ok
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestCovers(t *testing.T) {
+	secrets := apisv1alpha1.GroupResource{Resource: "secrets"}
+	all := apisv1alpha1.PermissionClaim{GroupResource: secrets, All: true}
+	selected := apisv1alpha1.PermissionClaim{GroupResource: secrets, ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default"}, {Namespace: "other"}}}
+	narrowed := apisv1alpha1.PermissionClaim{GroupResource: secrets, ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default"}}}
+
+	tests := map[string]struct {
+		accepted, requested apisv1alpha1.PermissionClaim
+		want                bool
+	}{
+		"equal":                             {accepted: all, requested: all, want: true},
+		"narrowed from all":                 {accepted: narrowed, requested: all, want: true},
+		"narrowed selectors":                {accepted: narrowed, requested: selected, want: true},
+		"wider than requested":              {accepted: selected, requested: narrowed},
+		"all accepted, selectors requested": {accepted: all, requested: selected},
+		"other resource":                    {accepted: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}, requested: all},
+		"other identity":                    {accepted: apisv1alpha1.PermissionClaim{GroupResource: secrets, All: true, IdentityHash: "abc"}, requested: all},
+		"accepted status-only":              {accepted: apisv1alpha1.PermissionClaim{GroupResource: secrets, All: true, StatusOnly: true}, requested: all, want: true},
+		"requested status-only":             {accepted: all, requested: apisv1alpha1.PermissionClaim{GroupResource: secrets, All: true, StatusOnly: true}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, Covers(tt.accepted, tt.requested))
+		})
+	}
+}
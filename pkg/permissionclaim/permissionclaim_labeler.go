@@ -95,7 +95,20 @@ func (l *Labeler) LabelsFor(ctx context.Context, cluster logicalcluster.Name, gr
 				continue
 			}
 
-			k, v, err := permissionclaims.ToLabelKeyAndValue(logicalcluster.From(export), export.Name, claim.PermissionClaim)
+			// the virtual workspace selects objects by the claim of the APIExport, and filters those
+			// outside of the narrowed down scope of the accepted claim itself.
+			var exportClaim *apisv1alpha1.PermissionClaim
+			for i := range export.Spec.PermissionClaims {
+				if Covers(claim.PermissionClaim, export.Spec.PermissionClaims[i]) {
+					exportClaim = &export.Spec.PermissionClaims[i]
+					break
+				}
+			}
+			if exportClaim == nil {
+				continue
+			}
+
+			k, v, err := permissionclaims.ToLabelKeyAndValue(logicalcluster.From(export), export.Name, *exportClaim)
 			if err != nil {
 				// extremely unlikely to get an error here - it means the json marshaling failed
 				logger.Error(err, "error calculating permission claim label key and value",
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/proxy/metrics"
+)
+
+// TokenCache caches the results of bearer token authentication for a short time, such that
+// repeated requests with the same token, e.g. of controllers, are not validated against the
+// token authenticators, like OIDC, every time.
+//
+// Cached results can be revoked by token or by user, e.g. when a service account is deleted.
+// Requests with a client certificate are not cached, as the certificate takes precedence
+// over the token.
+type TokenCache struct {
+	Authenticator authenticator.Request
+
+	successTTL time.Duration
+	failureTTL time.Duration
+	maxSize    int
+	now        func() time.Time
+
+	// key is the HMAC key to derive cache keys from tokens, such that tokens are not kept in memory.
+	key []byte
+
+	lock    sync.Mutex
+	entries map[string]*tokenCacheEntry
+	byUser  map[string]sets.String
+}
+
+type tokenCacheEntry struct {
+	resp    *authenticator.Response
+	ok      bool
+	err     error
+	expires time.Time
+}
+
+var _ authenticator.Request = &TokenCache{}
+
+// NewTokenCache returns a TokenCache caching successful authentications for successTTL and failed
+// ones for failureTTL, holding at most maxSize results.
+func NewTokenCache(delegate authenticator.Request, successTTL, failureTTL time.Duration, maxSize int) *TokenCache {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err) // rand should never fail
+	}
+	return &TokenCache{
+		Authenticator: delegate,
+		successTTL:    successTTL,
+		failureTTL:    failureTTL,
+		maxSize:       maxSize,
+		now:           time.Now,
+		key:           key,
+		entries:       map[string]*tokenCacheEntry{},
+		byUser:        map[string]sets.String{},
+	}
+}
+
+func (c *TokenCache) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	token := bearerToken(req)
+	if token == "" || (req.TLS != nil && len(req.TLS.PeerCertificates) > 0) {
+		return c.Authenticator.AuthenticateRequest(req)
+	}
+	audiences, _ := authenticator.AudiencesFrom(req.Context())
+	key := c.keyFor(token, audiences)
+
+	if entry, found := c.get(key); found {
+		metrics.RecordTokenCacheRequest("hit")
+		if !entry.ok {
+			return nil, false, entry.err
+		}
+		// like the bearer token authenticator, don't pass on the token after successful authentication.
+		req.Header.Del("Authorization")
+		// callers like the GroupFilter replace the user of the response, hence return a copy.
+		resp := *entry.resp
+		return &resp, true, nil
+	}
+	metrics.RecordTokenCacheRequest("miss")
+
+	resp, ok, err := c.Authenticator.AuthenticateRequest(req)
+	switch {
+	case ok && resp != nil && resp.User != nil:
+		copied := *resp
+		c.set(key, &tokenCacheEntry{resp: &copied, ok: true}, c.successTTL)
+	case !ok:
+		c.set(key, &tokenCacheEntry{err: err}, c.failureTTL)
+	}
+	return resp, ok, err
+}
+
+// Revoke removes the cached result of the given token.
+func (c *TokenCache) Revoke(token string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// all audiences share the prefix of the token.
+	prefix := c.keyFor(token, nil)
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.delete(key)
+		}
+	}
+}
+
+// RevokeUser removes all cached results of the given user.
+func (c *TokenCache) RevokeUser(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key := range c.byUser[name] {
+		c.delete(key)
+	}
+}
+
+// Flush removes all cached results.
+func (c *TokenCache) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = map[string]*tokenCacheEntry{}
+	c.byUser = map[string]sets.String{}
+}
+
+// ServiceAccountEventHandler returns an event handler revoking the cached results of deleted
+// service accounts.
+func (c *TokenCache) ServiceAccountEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			sa, ok := obj.(*corev1.ServiceAccount)
+			if !ok {
+				return
+			}
+			c.RevokeUser(serviceaccount.MakeUsername(sa.Namespace, sa.Name))
+		},
+	}
+}
+
+func (c *TokenCache) get(key string) (*tokenCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		c.delete(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *TokenCache) set(key string, entry *tokenCacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	now := c.now()
+	entry.expires = now.Add(ttl)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, found := c.entries[key]; !found && len(c.entries) >= c.maxSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				c.delete(k)
+			}
+		}
+		// still full: make room by evicting an arbitrary entry.
+		for k := range c.entries {
+			if len(c.entries) < c.maxSize {
+				break
+			}
+			c.delete(k)
+		}
+	}
+
+	c.delete(key)
+	c.entries[key] = entry
+	if entry.ok {
+		name := entry.resp.User.GetName()
+		if c.byUser[name] == nil {
+			c.byUser[name] = sets.NewString()
+		}
+		c.byUser[name].Insert(key)
+	}
+}
+
+// delete removes the entry. The lock must be held.
+func (c *TokenCache) delete(key string) {
+	entry, found := c.entries[key]
+	if !found {
+		return
+	}
+	delete(c.entries, key)
+	if entry.ok {
+		name := entry.resp.User.GetName()
+		c.byUser[name].Delete(key)
+		if c.byUser[name].Len() == 0 {
+			delete(c.byUser, name)
+		}
+	}
+}
+
+// keyFor returns the cache key of the token and audiences. The key of the token without
+// audiences is a prefix of the keys with audiences.
+func (c *TokenCache) keyFor(token string, audiences authenticator.Audiences) string {
+	h := hmac.New(sha256.New, c.key)
+	h.Write([]byte(token)) //nolint:errcheck
+	key := hex.EncodeToString(h.Sum(nil))
+	if len(audiences) > 0 {
+		key += "/" + strings.Join(audiences, ",")
+	}
+	return key
+}
+
+func bearerToken(req *http.Request) string {
+	auth := strings.TrimSpace(req.Header.Get("Authorization"))
+	parts := strings.SplitN(auth, " ", 2)
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/tools/cache"
+)
+
+// tokenAuthenticator authenticates the tokens in users and counts the calls.
+type tokenAuthenticator struct {
+	users map[string]string
+	calls int
+}
+
+func (a *tokenAuthenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	a.calls++
+	name, found := a.users[bearerToken(req)]
+	if !found {
+		return nil, false, errors.New("invalid bearer token")
+	}
+	return &authenticator.Response{User: &user.DefaultInfo{Name: name}}, true, nil
+}
+
+func TestTokenCache(t *testing.T) {
+	saName := serviceaccount.MakeUsername("default", "robot")
+	delegate := &tokenAuthenticator{users: map[string]string{"alice-token": "alice", "robot-token": saName}}
+	c := NewTokenCache(delegate, time.Minute, time.Second, 10)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	authenticate := func(token string) (*authenticator.Response, bool) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "https://localhost/api", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, ok, _ := c.AuthenticateRequest(req)
+		return resp, ok
+	}
+
+	resp, ok := authenticate("alice-token")
+	require.True(t, ok)
+	require.Equal(t, "alice", resp.User.GetName())
+	resp.User = &user.DefaultInfo{Name: "mutated"}
+	resp, ok = authenticate("alice-token")
+	require.True(t, ok)
+	require.Equal(t, "alice", resp.User.GetName(), "cached response must not be mutated by callers")
+	require.Equal(t, 1, delegate.calls, "expected a cache hit")
+
+	_, ok = authenticate("invalid-token")
+	require.False(t, ok)
+	_, ok = authenticate("invalid-token")
+	require.False(t, ok)
+	require.Equal(t, 2, delegate.calls, "expected failures to be cached")
+
+	now = now.Add(2 * time.Second)
+	authenticate("invalid-token")
+	authenticate("alice-token")
+	require.Equal(t, 3, delegate.calls, "expected the failure to expire, but not the success")
+
+	now = now.Add(time.Minute)
+	authenticate("alice-token")
+	require.Equal(t, 4, delegate.calls, "expected the success to expire")
+
+	c.Revoke("alice-token")
+	authenticate("alice-token")
+	require.Equal(t, 5, delegate.calls, "expected the token to be revoked")
+
+	authenticate("robot-token")
+	c.ServiceAccountEventHandler().OnDelete(cache.DeletedFinalStateUnknown{
+		Key: "default/robot",
+		Obj: &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "robot"}},
+	})
+	authenticate("robot-token")
+	require.Equal(t, 7, delegate.calls, "expected the deleted service account to be revoked")
+
+	c.Flush()
+	authenticate("robot-token")
+	require.Equal(t, 8, delegate.calls, "expected the cache to be flushed")
+}
+
+func TestTokenCacheSkipsClientCertificates(t *testing.T) {
+	delegate := &tokenAuthenticator{users: map[string]string{"alice-token": "alice"}}
+	c := NewTokenCache(delegate, time.Minute, time.Minute, 10)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, "https://localhost/api", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer alice-token")
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+		_, ok, err := c.AuthenticateRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, 2, delegate.calls, "expected requests with client certificates not to be cached")
+}
+
+func TestTokenCacheMaxSize(t *testing.T) {
+	delegate := &tokenAuthenticator{users: map[string]string{"a": "a", "b": "b", "c": "c"}}
+	c := NewTokenCache(delegate, time.Minute, 0, 2)
+
+	for _, token := range []string{"a", "b", "c"} {
+		req, err := http.NewRequest(http.MethodGet, "https://localhost/api", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		_, ok, err := c.AuthenticateRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Len(t, c.entries, 2)
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
+	kcpauthentication "github.com/kcp-dev/kcp/pkg/proxy/authentication"
 	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
 	bootstrap "github.com/kcp-dev/kcp/pkg/server/bootstrap"
 )
@@ -53,6 +54,8 @@ type ExtraConfig struct {
 	AuthenticationInfo    genericapiserver.AuthenticationInfo
 	ServingInfo           *genericapiserver.SecureServingInfo
 	AdditionalAuthEnabled bool
+	// TokenCache caches bearer token authentications. It is nil if caching is disabled.
+	TokenCache *kcpauthentication.TokenCache
 }
 
 type CompletedConfig struct {
@@ -89,7 +92,7 @@ func NewConfig(opts *proxyoptions.Options) (*Config, error) {
 	if err := c.Options.SecureServing.ApplyTo(&c.ServingInfo, &loopbackClientConfig); err != nil {
 		return nil, err
 	}
-	if err := c.Options.Authentication.ApplyTo(&c.AuthenticationInfo, c.ServingInfo, c.RootShardConfig); err != nil {
+	if c.TokenCache, err = c.Options.Authentication.ApplyTo(&c.AuthenticationInfo, c.ServingInfo, c.RootShardConfig); err != nil {
 		return nil, err
 	}
 
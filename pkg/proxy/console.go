@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+
+	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
+)
+
+const (
+	consoleUserHeader        = "X-Remote-User"
+	consoleGroupHeader       = "X-Remote-Group"
+	consoleExtraHeaderPrefix = "X-Remote-Extra-"
+
+	impersonateHeaderPrefix = "Impersonate-"
+)
+
+// NewConsoleHandler returns a handler that proxies requests to the web console backend
+// configured in o. The request path is passed on unchanged, i.e. the console has to be
+// served under the same path prefix by the backend.
+//
+// The user authenticated by the front-proxy is passed in the X-Remote-User, X-Remote-Group
+// and X-Remote-Extra- headers. Unauthenticated requests, e.g. for static assets or a login
+// page, are passed on without these headers. Values of these headers sent by the client are
+// always dropped, and so are the credentials of the client, i.e. the Authorization and Cookie
+// headers, and the Impersonate- headers: the backend must rely on the asserted user only.
+func NewConsoleHandler(o proxyoptions.Console) (http.Handler, error) {
+	u, err := url.Parse(o.Backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse console backend URL %q: %w", o.Backend, err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{}
+	if o.BackendServerCA != "" {
+		caCert, err := os.ReadFile(o.BackendServerCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read console backend CA file %q: %w", o.BackendServerCA, err)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		transport.TLSClientConfig.RootCAs = caCertPool
+	}
+	if o.ProxyClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(o.ProxyClientCert, o.ProxyClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load console client certificate %q or key %q: %w", o.ProxyClientCert, o.ProxyClientKey, err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	proxy.Transport = transport
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Header.Del("Authorization")
+		req.Header.Del("Proxy-Authorization")
+		req.Header.Del("Cookie")
+		for k := range req.Header {
+			if strings.HasPrefix(k, impersonateHeaderPrefix) {
+				req.Header.Del(k)
+			}
+		}
+	}
+
+	handler := WithProxyAuthHeaders(proxy, consoleUserHeader, consoleGroupHeader, consoleExtraHeaderPrefix)
+	return withoutProxyAuthHeaders(handler, consoleUserHeader, consoleGroupHeader, consoleExtraHeaderPrefix), nil
+}
+
+// WithConsole serves requests below consolePath with console, and all other requests with handler.
+func WithConsole(handler, console http.Handler, consolePath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, consolePath) || r.URL.Path == strings.TrimSuffix(consolePath, "/") {
+			console.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// withoutProxyAuthHeaders removes the headers the user is passed in from the client request,
+// such that clients cannot impersonate other users towards the backend.
+func withoutProxyAuthHeaders(delegate http.Handler, userHeader, groupHeader, extraHeaderPrefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(userHeader)
+		r.Header.Del(groupHeader)
+		for k := range r.Header {
+			if strings.HasPrefix(strings.ToLower(k), strings.ToLower(extraHeaderPrefix)) {
+				r.Header.Del(k)
+			}
+		}
+
+		delegate.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	var received *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.Write([]byte("console")) //nolint:errcheck
+	}))
+	t.Cleanup(backend.Close)
+
+	console, err := NewConsoleHandler(proxyoptions.Console{Path: "/console/", Backend: backend.URL})
+	require.NoError(t, err)
+	handler := WithConsole(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("mapping")) //nolint:errcheck
+	}), console, "/console/")
+
+	tests := map[string]struct {
+		path         string
+		user         user.Info
+		wantBody     string
+		wantUser     string
+		wantGroups   []string
+		wantExtraKey string
+	}{
+		"other paths are not proxied to the console": {
+			path:     "/clusters/root/api",
+			wantBody: "mapping",
+		},
+		"unauthenticated request is passed on without user": {
+			path:     "/console/index.html",
+			wantBody: "console",
+		},
+		"console root without trailing slash": {
+			path:     "/console",
+			wantBody: "console",
+		},
+		"authenticated request is passed on with user": {
+			path:       "/console/api/workspaces",
+			user:       &user.DefaultInfo{Name: "alice", Groups: []string{"team-a"}, Extra: map[string][]string{"scope": {"admin"}}},
+			wantBody:   "console",
+			wantUser:   "alice",
+			wantGroups: []string{"team-a"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			received = nil
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("X-Remote-User", "mallory")
+			req.Header.Add("X-Remote-Group", "system:masters")
+			req.Header.Set("X-Remote-Extra-Scope", "cluster-admin")
+			req.Header.Set("Authorization", "Bearer secret-token")
+			req.Header.Set("Cookie", "session=secret-session")
+			req.Header.Set("Impersonate-User", "admin")
+			req.Header.Add("Impersonate-Group", "system:masters")
+			req.Header.Set("Impersonate-Extra-Scope", "cluster-admin")
+			if tt.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), tt.user))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			body, err := io.ReadAll(rec.Result().Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBody, string(body))
+			if tt.wantBody != "console" {
+				require.Nil(t, received)
+				return
+			}
+
+			require.NotNil(t, received)
+			require.Equal(t, tt.path, received.URL.Path)
+			for k := range received.Header {
+				require.NotContains(t, k, "Impersonate-", "impersonation must not be passed to the console backend")
+			}
+			require.Empty(t, received.Header.Get("Authorization"), "the token must not be passed to the console backend")
+			require.Empty(t, received.Header.Get("Cookie"), "cookies must not be passed to the console backend")
+			require.Equal(t, tt.wantUser, received.Header.Get("X-Remote-User"))
+			require.Equal(t, tt.wantGroups, received.Header.Values("X-Remote-Group"))
+			if tt.user == nil {
+				require.Empty(t, received.Header.Values("X-Remote-Extra-Scope"))
+			} else {
+				require.Equal(t, []string{"admin"}, received.Header.Values("X-Remote-Extra-Scope"))
+			}
+		})
+	}
+}
@@ -31,4 +31,18 @@ limitations under the License.
 //     backend_server_ca: certs/kcp-ca-cert.pem
 //     proxy_client_cert: certs/proxy-client-cert.pem
 //     proxy_client_key: certs/proxy-client-key.pem
+//
+// The /services/ path can additionally be mapped once per shard, with the shard
+// name in a shard field. Virtual workspace requests are then routed to the shard
+// hosting the logical cluster they target, and to the mapping without shard
+// otherwise.
+//
+// Alternatively, with --generate-mapping the mapping is generated from the Shard
+// objects in the root shard and updated as shards are added or removed, see
+// package mapping.
+//
+// With --console-path and --console-backend, requests below the given path are
+// proxied to a web console instead. The console shares the authentication of the
+// front-proxy and receives the authenticated user in X-Remote-User, X-Remote-Group
+// and X-Remote-Extra- headers, but not the credentials of the client.
 package proxy
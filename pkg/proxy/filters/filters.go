@@ -18,6 +18,8 @@ package filters
 
 import (
 	"net/http"
+	"path"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -57,6 +59,63 @@ func WithOptionalAuthentication(handler, failed http.Handler, auth authenticator
 	})
 }
 
+// WithRequiredAuthentication creates a handler that rejects requests without an authenticated
+// user with the failed handler, unless their path matches one of unauthenticatedPaths. A "*"
+// path segment matches any single segment.
+// When passThroughTokens is true, i.e. the front-proxy does not authenticate bearer tokens itself,
+// requests with a well-formed bearer token are passed through for the shards to authenticate. Other
+// Authorization schemes are rejected, as the shards might treat them as anonymous.
+func WithRequiredAuthentication(handler, failed http.Handler, unauthenticatedPaths []string, passThroughTokens bool) http.Handler {
+	patterns := make([][]string, 0, len(unauthenticatedPaths))
+	for _, p := range unauthenticatedPaths {
+		patterns = append(patterns, strings.Split(p, "/"))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := request.UserFrom(req.Context()); ok || matchesAnyPath(patterns, req.URL.Path) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if passThroughTokens && hasBearerToken(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		failed.ServeHTTP(w, req)
+	})
+}
+
+// hasBearerToken returns whether the request carries an Authorization header of the form
+// "Bearer <token>", parsed like the bearer token authenticator of the shards does.
+func hasBearerToken(req *http.Request) bool {
+	parts := strings.Split(strings.TrimSpace(req.Header.Get("Authorization")), " ")
+	return len(parts) == 2 && strings.EqualFold(parts[0], "bearer") && parts[1] != ""
+}
+
+func matchesAnyPath(patterns [][]string, p string) bool {
+	// never match paths like /healthz/../clusters/root, which could be resolved differently further down.
+	if p != path.Clean(p) {
+		return false
+	}
+	segments := strings.Split(p, "/")
+	for _, pattern := range patterns {
+		if matchesPath(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != segments[i] && (pattern[i] != "*" || segments[i] == "") {
+			return false
+		}
+	}
+	return true
+}
+
 func NewUnauthorizedHandler() http.Handler {
 	scheme := runtime.NewScheme()
 	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Group: "", Version: "v1"})
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithRequiredAuthentication(t *testing.T) {
+	unauthenticatedPaths := []string{"/healthz", "/version", "/clusters/*/version"}
+
+	tests := map[string]struct {
+		path          string
+		authenticated bool
+		wantStatus    int
+	}{
+		"unauthenticated allowed path":                {path: "/healthz", wantStatus: http.StatusOK},
+		"unauthenticated allowed path with wildcard":  {path: "/clusters/root:org/version", wantStatus: http.StatusOK},
+		"unauthenticated other path":                  {path: "/clusters/root/api/v1/namespaces", wantStatus: http.StatusUnauthorized},
+		"unauthenticated root":                        {path: "/", wantStatus: http.StatusUnauthorized},
+		"unauthenticated sub-path of allowed path":    {path: "/healthz/etcd", wantStatus: http.StatusUnauthorized},
+		"unauthenticated empty wildcard segment":      {path: "/clusters//version", wantStatus: http.StatusUnauthorized},
+		"unauthenticated wildcard too many segments":  {path: "/clusters/root/org/version", wantStatus: http.StatusUnauthorized},
+		"unauthenticated unclean path":                {path: "/healthz/../clusters/root/api", wantStatus: http.StatusUnauthorized},
+		"unauthenticated allowed path trailing slash": {path: "/version/", wantStatus: http.StatusUnauthorized},
+		"authenticated other path":                    {path: "/clusters/root/api/v1/namespaces", authenticated: true, wantStatus: http.StatusOK},
+		"authenticated allowed path":                  {path: "/healthz", authenticated: true, wantStatus: http.StatusOK},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := WithRequiredAuthentication(ok, NewUnauthorizedHandler(), unauthenticatedPaths, false)
+
+			req := httptest.NewRequest(http.MethodGet, "https://kcp.example.io", nil)
+			req.URL.Path = tt.path
+			req = req.WithContext(request.WithRequestInfo(req.Context(), &request.RequestInfo{}))
+			if tt.authenticated {
+				req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "user-1"}))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthenticationChain(t *testing.T) {
+	// authenticates client certificates only, like the default front-proxy authenticator.
+	certAuth := authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "cert-user"}}, true, nil
+	})
+
+	tests := map[string]struct {
+		auth                  authenticator.Request
+		additionalAuthMethods bool
+		authorization         string
+		wantStatus            int
+	}{
+		"bearer token passed through to the shards": {
+			auth:          certAuth,
+			authorization: "Bearer token",
+			wantStatus:    http.StatusOK,
+		},
+		"bearer token passed through without authenticator": {
+			authorization: "Bearer token",
+			wantStatus:    http.StatusOK,
+		},
+		"bearer token rejected when authenticated by the front-proxy": {
+			auth:                  certAuth,
+			additionalAuthMethods: true,
+			authorization:         "Bearer token",
+			wantStatus:            http.StatusUnauthorized,
+		},
+		"basic auth not passed through": {
+			auth:          certAuth,
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		"unknown scheme not passed through": {
+			auth:          certAuth,
+			authorization: "foo",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		"empty bearer token not passed through": {
+			auth:          certAuth,
+			authorization: "Bearer ",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		"no credentials": {
+			auth:       certAuth,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			failed := NewUnauthorizedHandler()
+			handler := WithRequiredAuthentication(ok, failed, []string{"/healthz"}, !tt.additionalAuthMethods || tt.auth == nil)
+			handler = WithOptionalAuthentication(handler, failed, tt.auth, tt.additionalAuthMethods)
+
+			req := httptest.NewRequest(http.MethodGet, "https://kcp.example.io/clusters/root/api/v1/namespaces", nil)
+			req.TLS = nil
+			req = req.WithContext(request.WithRequestInfo(req.Context(), &request.RequestInfo{}))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
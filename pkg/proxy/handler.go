@@ -80,3 +80,43 @@ func shardHandler(index index.Index, proxy http.Handler) http.HandlerFunc {
 		proxy.ServeHTTP(w, req)
 	}
 }
+
+// servicesHandler routes virtual workspace requests to the handler of the shard hosting the
+// logical cluster they target, and to fallback if that is unknown.
+func servicesHandler(index index.Index, shardHandlers map[string]http.Handler, fallback http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if clusterPath, ok := virtualWorkspaceCluster(req.URL.Path); ok {
+			if shard, _, found := index.Lookup(clusterPath); found {
+				if handler, found := shardHandlers[shard]; found {
+					klog.FromContext(req.Context()).WithValues("clusterPath", clusterPath, "shard", shard).V(4).Info("Routing virtual workspace request")
+					handler.ServeHTTP(w, req)
+					return
+				}
+			}
+		}
+		fallback.ServeHTTP(w, req)
+	}
+}
+
+// virtualWorkspaceCluster returns the logical cluster a virtual workspace request targets. This
+// is the cluster following the first clusters segment after the virtual workspace name, e.g. the
+// consumer in /services/apiexport/root:org/my-export/clusters/<consumer>/api. For wildcard requests,
+// and virtual workspaces without clusters segment, it is the cluster following the virtual
+// workspace name, e.g. root:org.
+func virtualWorkspaceCluster(p string) (logicalcluster.Path, bool) {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if len(segments) < 3 || segments[0] != "services" {
+		return logicalcluster.Path{}, false
+	}
+	for i := 3; i+1 < len(segments); i++ {
+		if segments[i] != "clusters" {
+			continue
+		}
+		if clusterPath := logicalcluster.NewPath(segments[i+1]); clusterPath.IsValid() && clusterPath != logicalcluster.Wildcard {
+			return clusterPath, true
+		}
+		break
+	}
+	clusterPath := logicalcluster.NewPath(segments[2])
+	return clusterPath, clusterPath.IsValid()
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIndex maps logical cluster paths to shards.
+type fakeIndex map[string]string
+
+func (i fakeIndex) Lookup(path logicalcluster.Path) (string, logicalcluster.Name, bool) {
+	shard, found := i[path.String()]
+	return shard, logicalcluster.Name(path.String()), found
+}
+
+func (i fakeIndex) LookupURL(path logicalcluster.Path) (string, bool) {
+	shard, found := i[path.String()]
+	return "https://" + shard, found
+}
+
+func TestServicesHandler(t *testing.T) {
+	index := fakeIndex{
+		"root:org":      "root",
+		"root:org:team": "beta",
+		"2k7nsx6bq9ez3": "beta",
+	}
+	backend := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name)) //nolint:errcheck
+		})
+	}
+	handler := servicesHandler(index, map[string]http.Handler{
+		"root": backend("root"),
+		"beta": backend("beta"),
+	}, backend("fallback"))
+
+	tests := map[string]struct {
+		path string
+		want string
+	}{
+		"consumer on the other shard": {
+			path: "/services/apiexport/root:org/my-export/clusters/root:org:team/api/v1/configmaps",
+			want: "beta",
+		},
+		"consumer by logical cluster name": {
+			path: "/services/apiexport/root:org/my-export/clusters/2k7nsx6bq9ez3/api/v1/configmaps",
+			want: "beta",
+		},
+		"consumer on the same shard": {
+			path: "/services/apiexport/root:org:team/my-export/clusters/root:org/api/v1/configmaps",
+			want: "root",
+		},
+		"wildcard request goes to the shard of the virtual workspace cluster": {
+			path: "/services/apiexport/root:org:team/my-export/clusters/*/api/v1/configmaps",
+			want: "beta",
+		},
+		"virtual workspace without clusters segment": {
+			path: "/services/syncer/root:org:team/my-target/uid",
+			want: "beta",
+		},
+		"unknown cluster": {
+			path: "/services/apiexport/root:other/my-export/clusters/root:other/api",
+			want: "fallback",
+		},
+		"invalid cluster": {
+			path: "/services/workspaces/~",
+			want: "fallback",
+		},
+		"short path": {
+			path: "/services/apiexport",
+			want: "fallback",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+}
@@ -49,6 +49,7 @@ const (
 )
 
 type Index interface {
+	Lookup(path logicalcluster.Path) (shard string, cluster logicalcluster.Name, found bool)
 	LookupURL(path logicalcluster.Path) (url string, found bool)
 }
 
@@ -286,6 +287,15 @@ func (c *Controller) stopShard(shard *corev1alpha1.Shard) {
 	delete(c.shardLogicalClusterInformers, shard.Name)
 }
 
+// Indexer returns the registered indexer of the given name.
+func (c *Controller) Indexer(name string) (index.Indexer, bool) {
+	return c.state.Indexer(name)
+}
+
+func (c *Controller) Lookup(path logicalcluster.Path) (shard string, cluster logicalcluster.Name, found bool) {
+	return c.state.Lookup(path)
+}
+
 func (c *Controller) LookupURL(path logicalcluster.Path) (url string, found bool) {
 	return c.state.LookupURL(path)
 }
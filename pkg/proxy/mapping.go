@@ -23,29 +23,21 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"sync/atomic"
 
 	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kcp-dev/kcp/pkg/proxy/index"
+	"github.com/kcp-dev/kcp/pkg/proxy/mapping"
 	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
 )
 
 // PathMapping describes how to route traffic from a path to a backend server.
-// Each Path is registered with the DefaultServeMux with a handler that
-// delegates to the specified backend.
-type PathMapping struct {
-	Path              string `json:"path"`
-	Backend           string `json:"backend"`
-	BackendServerCA   string `json:"backend_server_ca"`
-	ProxyClientCert   string `json:"proxy_client_cert"`
-	ProxyClientKey    string `json:"proxy_client_key"`
-	UserHeader        string `json:"user_header,omitempty"`
-	GroupHeader       string `json:"group_header,omitempty"`
-	ExtraHeaderPrefix string `json:"extra_header_prefix"`
-}
+type PathMapping = mapping.PathMapping
 
+// NewHandler returns a handler routing requests according to the mapping file.
 func NewHandler(ctx context.Context, o *proxyoptions.Options, index index.Index) (http.Handler, error) {
 	mappingData, err := os.ReadFile(o.MappingFile)
 	if err != nil {
@@ -57,11 +49,49 @@ func NewHandler(ctx context.Context, o *proxyoptions.Options, index index.Index)
 		return nil, fmt.Errorf("failed to unmarshal mapping file %q: %w", o.MappingFile, err)
 	}
 
+	return newMappingHandler(ctx, mapping, index)
+}
+
+// DynamicHandler routes requests according to a path mapping that can be replaced at runtime.
+// Until the first mapping is set, it responds with 503 Service Unavailable.
+type DynamicHandler struct {
+	ctx      context.Context
+	index    index.Index
+	delegate atomic.Value
+}
+
+// NewDynamicHandler returns a DynamicHandler without mapping.
+func NewDynamicHandler(ctx context.Context, index index.Index) *DynamicHandler {
+	return &DynamicHandler{ctx: ctx, index: index}
+}
+
+// SetMapping replaces the mapping of the handler. In-flight requests are served by the old mapping.
+func (h *DynamicHandler) SetMapping(mapping []PathMapping) error {
+	handler, err := newMappingHandler(h.ctx, mapping, h.index)
+	if err != nil {
+		return err
+	}
+	h.delegate.Store(handler)
+	return nil
+}
+
+func (h *DynamicHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := h.delegate.Load().(http.Handler)
+	if !ok {
+		http.Error(w, "front-proxy mapping is not ready", http.StatusServiceUnavailable)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func newMappingHandler(ctx context.Context, mapping []PathMapping, index index.Index) (http.Handler, error) {
 	mux := http.NewServeMux()
 
 	mux.Handle("/metrics", legacyregistry.Handler())
 
 	logger := klog.FromContext(ctx)
+	var servicesFallback http.Handler
+	shardServices := map[string]http.Handler{}
 	for _, m := range mapping {
 		logger.WithValues("mapping", m).V(2).Info("adding mapping")
 
@@ -81,7 +111,6 @@ func NewHandler(ctx context.Context, o *proxyoptions.Options, index index.Index)
 			clusterProxy.Transport = transport
 			handler = shardHandler(index, clusterProxy)
 		} else {
-			// TODO: handle virtual workspace apiservers per shard
 			proxy := httputil.NewSingleHostReverseProxy(u)
 			proxy.Transport = transport
 			handler = proxy
@@ -102,7 +131,25 @@ func NewHandler(ctx context.Context, o *proxyoptions.Options, index index.Index)
 
 		handler = WithProxyAuthHeaders(handler, userHeader, groupHeader, extraHeaderPrefix)
 
-		mux.Handle(m.Path, handler)
+		switch {
+		case m.Path == "/services/" && m.Shard != "":
+			shardServices[m.Shard] = handler
+		case m.Path == "/services/":
+			servicesFallback = handler
+		case m.Shard != "":
+			return nil, fmt.Errorf("failed to create path mapping for path %q: shard is only supported for /services/", m.Path)
+		default:
+			mux.Handle(m.Path, handler)
+		}
+	}
+
+	if len(shardServices) > 0 {
+		if servicesFallback == nil {
+			servicesFallback = http.NotFoundHandler()
+		}
+		mux.Handle("/services/", servicesHandler(index, shardServices, servicesFallback))
+	} else if servicesFallback != nil {
+		mux.Handle("/services/", servicesFallback)
 	}
 
 	return mux, nil
@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapping
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-front-proxy-mapping"
+
+	// queueKey is the only key of the queue. Every Shard change leads to a
+	// regeneration of the whole mapping.
+	queueKey = "mapping"
+)
+
+// PathMapping describes how to route traffic from a path to a backend server.
+// Each Path is registered with the DefaultServeMux with a handler that
+// delegates to the specified backend.
+//
+// The /services/ path can be mapped several times, once per Shard. Virtual
+// workspace requests are then routed to the mapping of the shard hosting the
+// logical cluster they target, and to the mapping without Shard otherwise.
+type PathMapping struct {
+	Path              string `json:"path"`
+	Shard             string `json:"shard,omitempty"`
+	Backend           string `json:"backend"`
+	BackendServerCA   string `json:"backend_server_ca"`
+	ProxyClientCert   string `json:"proxy_client_cert"`
+	ProxyClientKey    string `json:"proxy_client_key"`
+	UserHeader        string `json:"user_header,omitempty"`
+	GroupHeader       string `json:"group_header,omitempty"`
+	ExtraHeaderPrefix string `json:"extra_header_prefix"`
+}
+
+// Template holds the parts of the generated PathMappings that cannot be derived from Shards.
+type Template struct {
+	BackendServerCA string
+	ProxyClientCert string
+	ProxyClientKey  string
+}
+
+// NewController returns a controller that maintains the front-proxy path mapping
+// from the Shards on the root shard. On every change, the mapping is written to
+// mappingFile, if not empty, and passed to onChange.
+func NewController(
+	shardInformer corev1alpha1informers.ShardInformer,
+	template Template,
+	mappingFile string,
+	onChange func([]PathMapping) error,
+) *Controller {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue:       queue,
+		shardLister: shardInformer.Lister(),
+		template:    template,
+		mappingFile: mappingFile,
+		onChange:    onChange,
+	}
+
+	shardInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue() },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue() },
+		DeleteFunc: func(obj interface{}) { c.enqueue() },
+	})
+
+	return c
+}
+
+// Controller watches Shards on the root shard and generates the front-proxy path
+// mapping from them, replacing a manually maintained mapping file.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	shardLister corev1alpha1listers.ShardLister
+
+	template    Template
+	mappingFile string
+	onChange    func([]PathMapping) error
+
+	// last is the last successfully applied mapping. It is only accessed by the single worker.
+	last []byte
+}
+
+func (c *Controller) enqueue() {
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), queueKey)
+	logger.V(4).Info("queueing mapping regeneration")
+	c.queue.Add(queueKey)
+}
+
+// Start starts the controller. The mapping is a single global state, hence there is only one worker.
+func (c *Controller) Start(ctx context.Context) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	shards, err := c.shardLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	mapping := Generate(shards, c.template)
+	bs, err := yaml.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mapping: %w", err)
+	}
+	if c.last != nil && bytes.Equal(bs, c.last) {
+		return nil
+	}
+
+	if c.mappingFile != "" {
+		logger.V(2).Info("writing mapping file", "file", c.mappingFile)
+		if err := writeFileAtomically(c.mappingFile, bs); err != nil {
+			return fmt.Errorf("failed to write mapping file %q: %w", c.mappingFile, err)
+		}
+	}
+	if c.onChange != nil {
+		if err := c.onChange(mapping); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("updated mapping", "shards", len(shards), "mappings", len(mapping))
+	c.last = bs
+	return nil
+}
+
+// Generate returns the path mapping for the given Shards. Workspace traffic under
+// /clusters/ is routed per shard through the workspace index, so the backend of that
+// mapping is only a fallback. Virtual workspace traffic under /services/ is mapped per
+// shard to its virtual workspace server, and resolved per logical cluster through the
+// workspace index too. Requests for no known logical cluster go to the virtual workspace
+// server of the root shard, or of the first shard by name if there is no root shard.
+func Generate(shards []*corev1alpha1.Shard, template Template) []PathMapping {
+	if len(shards) == 0 {
+		return []PathMapping{}
+	}
+
+	shards = append([]*corev1alpha1.Shard(nil), shards...)
+	sort.Slice(shards, func(i, j int) bool {
+		if (shards[i].Name == corev1alpha1.RootShard) != (shards[j].Name == corev1alpha1.RootShard) {
+			return shards[i].Name == corev1alpha1.RootShard
+		}
+		return shards[i].Name < shards[j].Name
+	})
+	primary := shards[0]
+
+	mapping := []PathMapping{
+		{
+			Path:            "/services/",
+			Backend:         virtualWorkspaceURL(primary),
+			BackendServerCA: template.BackendServerCA,
+			ProxyClientCert: template.ProxyClientCert,
+			ProxyClientKey:  template.ProxyClientKey,
+		},
+	}
+	for _, shard := range shards {
+		mapping = append(mapping, PathMapping{
+			Path:            "/services/",
+			Shard:           shard.Name,
+			Backend:         virtualWorkspaceURL(shard),
+			BackendServerCA: template.BackendServerCA,
+			ProxyClientCert: template.ProxyClientCert,
+			ProxyClientKey:  template.ProxyClientKey,
+		})
+	}
+	return append(mapping, PathMapping{
+		Path:            "/clusters/",
+		Backend:         primary.Spec.BaseURL,
+		BackendServerCA: template.BackendServerCA,
+		ProxyClientCert: template.ProxyClientCert,
+		ProxyClientKey:  template.ProxyClientKey,
+	})
+}
+
+func virtualWorkspaceURL(shard *corev1alpha1.Shard) string {
+	if shard.Spec.VirtualWorkspaceURL == "" {
+		return shard.Spec.BaseURL
+	}
+	return shard.Spec.VirtualWorkspaceURL
+}
+
+// writeFileAtomically replaces the file such that readers never see partial content.
+func writeFileAtomically(filename string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+func TestGenerate(t *testing.T) {
+	template := Template{
+		BackendServerCA: "ca.crt",
+		ProxyClientCert: "client.crt",
+		ProxyClientKey:  "client.key",
+	}
+	shard := func(name, baseURL, vwURL string) *corev1alpha1.Shard {
+		return &corev1alpha1.Shard{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1alpha1.ShardSpec{
+				BaseURL:             baseURL,
+				VirtualWorkspaceURL: vwURL,
+			},
+		}
+	}
+	services := func(shard, vwURL string) PathMapping {
+		return PathMapping{Path: "/services/", Shard: shard, Backend: vwURL, BackendServerCA: "ca.crt", ProxyClientCert: "client.crt", ProxyClientKey: "client.key"}
+	}
+	clusters := func(baseURL string) PathMapping {
+		return PathMapping{Path: "/clusters/", Backend: baseURL, BackendServerCA: "ca.crt", ProxyClientCert: "client.crt", ProxyClientKey: "client.key"}
+	}
+
+	tests := map[string]struct {
+		shards []*corev1alpha1.Shard
+		want   []PathMapping
+	}{
+		"no shards": {
+			want: []PathMapping{},
+		},
+		"root shard is preferred": {
+			shards: []*corev1alpha1.Shard{
+				shard("alpha", "https://alpha:6443", "https://alpha:7443"),
+				shard("root", "https://root:6443", "https://root:7443"),
+			},
+			want: []PathMapping{
+				services("", "https://root:7443"),
+				services("root", "https://root:7443"),
+				services("alpha", "https://alpha:7443"),
+				clusters("https://root:6443"),
+			},
+		},
+		"first shard by name without root shard": {
+			shards: []*corev1alpha1.Shard{
+				shard("beta", "https://beta:6443", "https://beta:7443"),
+				shard("alpha", "https://alpha:6443", "https://alpha:7443"),
+			},
+			want: []PathMapping{
+				services("", "https://alpha:7443"),
+				services("alpha", "https://alpha:7443"),
+				services("beta", "https://beta:7443"),
+				clusters("https://alpha:6443"),
+			},
+		},
+		"virtual workspaces default to the base URL": {
+			shards: []*corev1alpha1.Shard{
+				shard("root", "https://root:6443", ""),
+			},
+			want: []PathMapping{
+				services("", "https://root:6443"),
+				services("root", "https://root:6443"),
+				clusters("https://root:6443"),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, Generate(tc.shards, template))
+		})
+	}
+}
@@ -44,14 +44,29 @@ var (
 		},
 		[]string{"method", "code"},
 	)
+
+	tokenCacheRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "proxy_authentication_token_cache_requests_total",
+			Help:           "Number of bearer token authentications served from the token cache (hit) or by the authenticators (miss).",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"result"},
+	)
 )
 
+// RecordTokenCacheRequest records a token cache hit or miss.
+func RecordTokenCacheRequest(result string) {
+	tokenCacheRequests.WithLabelValues(result).Inc()
+}
+
 var registerMetrics sync.Once
 
 // Register metrics.
 func Register() {
 	registerMetrics.Do(func() {
 		legacyregistry.MustRegister(requestLatencies)
+		legacyregistry.MustRegister(tokenCacheRequests)
 	})
 }
 
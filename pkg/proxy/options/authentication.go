@@ -45,6 +45,12 @@ type Authentication struct {
 	BuiltInOptions *kubeoptions.BuiltInAuthenticationOptions
 	PassOnGroups   []string
 	DropGroups     []string
+
+	// TokenCacheTTL and TokenCacheFailureTTL are the durations bearer token authentication
+	// results are cached for. TokenCacheSize is the maximum number of cached results.
+	TokenCacheTTL        time.Duration
+	TokenCacheFailureTTL time.Duration
+	TokenCacheSize       int
 }
 
 // NewAuthentication creates a default Authentication.
@@ -60,6 +66,9 @@ func NewAuthentication() *Authentication {
 		// SystemExternalLogicalClusterAdmin must be used for all logical-cluster-admin
 		// requests via the proxy, so we drop SystemLogicalClusterAdmin here
 		DropGroups: []string{user.SystemPrivilegedGroup, bootstrap.SystemLogicalClusterAdmin},
+
+		TokenCacheTTL:  10 * time.Second,
+		TokenCacheSize: 10000,
 	}
 	auth.BuiltInOptions.ServiceAccounts.Issuers = []string{"https://kcp.default.svc"}
 	return auth
@@ -67,7 +76,7 @@ func NewAuthentication() *Authentication {
 
 // When configured to enable auth other than ClientCert, this returns true.
 func (c *Authentication) AdditionalAuthEnabled() bool {
-	return c.tokenAuthEnabled() || c.serviceAccountAuthEnabled() || c.oidcAuthEnabled()
+	return c.tokenAuthEnabled() || c.ServiceAccountAuthEnabled() || c.oidcAuthEnabled()
 }
 
 func (c *Authentication) oidcAuthEnabled() bool {
@@ -78,27 +87,36 @@ func (c *Authentication) tokenAuthEnabled() bool {
 	return c.BuiltInOptions.TokenFile != nil && c.BuiltInOptions.TokenFile.TokenFile != ""
 }
 
-func (c *Authentication) serviceAccountAuthEnabled() bool {
+func (c *Authentication) ServiceAccountAuthEnabled() bool {
 	return c.BuiltInOptions.ServiceAccounts != nil && len(c.BuiltInOptions.ServiceAccounts.KeyFiles) != 0
 }
 
-func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.AuthenticationInfo, servingInfo *genericapiserver.SecureServingInfo, rootShardConfig *rest.Config) error {
+func (c *Authentication) tokenCacheEnabled() bool {
+	return c.TokenCacheTTL > 0 || c.TokenCacheFailureTTL > 0
+}
+
+// ApplyTo sets up the authenticator. If the token cache is enabled, it is returned to hook up
+// revocations.
+func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.AuthenticationInfo, servingInfo *genericapiserver.SecureServingInfo, rootShardConfig *rest.Config) (*kcpauthentication.TokenCache, error) {
 	// Note BuiltInAuthenticationOptions.ApplyTo is not called, so we
 	// can reduce the dependencies pulled in from auth methods which aren't enabled
 	authenticatorConfig, err := c.BuiltInOptions.ToAuthenticationConfig()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	// the token cache below replaces the built-in one, which cannot be revoked.
+	authenticatorConfig.TokenSuccessCacheTTL = 0
+	authenticatorConfig.TokenFailureCacheTTL = 0
 
 	// Set up the ClientCert if the client-ca-file option was passed
 	if authenticatorConfig.ClientCAContentProvider != nil {
 		if err = authenticationInfo.ApplyClientCert(authenticatorConfig.ClientCAContentProvider, servingInfo); err != nil {
-			return fmt.Errorf("unable to load client CA file: %w", err)
+			return nil, fmt.Errorf("unable to load client CA file: %w", err)
 		}
 	}
 
 	// Set for service account auth, if enabled
-	if c.serviceAccountAuthEnabled() {
+	if c.ServiceAccountAuthEnabled() {
 		authenticationInfo.APIAudiences = c.BuiltInOptions.APIAudiences
 		if len(c.BuiltInOptions.ServiceAccounts.Issuers) != 0 && len(c.BuiltInOptions.APIAudiences) == 0 {
 			authenticationInfo.APIAudiences = authenticator.Audiences(c.BuiltInOptions.ServiceAccounts.Issuers)
@@ -107,7 +125,7 @@ func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.Authentica
 		config := rest.CopyConfig(rootShardConfig)
 		tokenGetterClient, err := kcpkubernetesclientset.NewForConfig(config)
 		if err != nil {
-			return fmt.Errorf("failed to create client for ServiceAccountTokenGetter: %w", err)
+			return nil, fmt.Errorf("failed to create client for ServiceAccountTokenGetter: %w", err)
 		}
 
 		versionedInformers := kcpkubernetesinformers.NewSharedInformerFactory(tokenGetterClient, 10*time.Minute)
@@ -122,7 +140,13 @@ func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.Authentica
 	// Sets up a union Authenticator for all enabled auth methods
 	authenticationInfo.Authenticator, _, err = authenticatorConfig.New()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var tokenCache *kcpauthentication.TokenCache
+	if c.tokenCacheEnabled() {
+		tokenCache = kcpauthentication.NewTokenCache(authenticationInfo.Authenticator, c.TokenCacheTTL, c.TokenCacheFailureTTL, c.TokenCacheSize)
+		authenticationInfo.Authenticator = tokenCache
 	}
 
 	// only pass on those groups to the shards we want
@@ -150,7 +174,7 @@ func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.Authentica
 		}
 	}
 
-	return nil
+	return tokenCache, nil
 }
 
 // AddFlags delegates to ClientCertAuthenticationOptions.
@@ -163,8 +187,25 @@ func (c *Authentication) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&c.DropGroups, "authentication-drop-groups", c.DropGroups,
 		"Groups that are not passed on to the shard. Empty matches none. \"prefix*\" matches "+
 			"all beginning with the given prefix. Dropping trumps over passing on.")
+	fs.DurationVar(&c.TokenCacheTTL, "authentication-token-cache-ttl", c.TokenCacheTTL,
+		"The duration to cache successful bearer token authentications for. Cached results of deleted "+
+			"service accounts are revoked. Zero disables caching of successful authentications.")
+	fs.DurationVar(&c.TokenCacheFailureTTL, "authentication-token-cache-failure-ttl", c.TokenCacheFailureTTL,
+		"The duration to cache failed bearer token authentications for. Zero disables caching of failed authentications.")
+	fs.IntVar(&c.TokenCacheSize, "authentication-token-cache-size", c.TokenCacheSize,
+		"The maximum number of cached bearer token authentications.")
 }
 
 func (c *Authentication) Validate() []error {
-	return nil
+	var errs []error
+	if c.TokenCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("--authentication-token-cache-ttl must not be negative"))
+	}
+	if c.TokenCacheFailureTTL < 0 {
+		errs = append(errs, fmt.Errorf("--authentication-token-cache-failure-ttl must not be negative"))
+	}
+	if c.tokenCacheEnabled() && c.TokenCacheSize <= 0 {
+		errs = append(errs, fmt.Errorf("--authentication-token-cache-size must be positive"))
+	}
+	return errs
 }
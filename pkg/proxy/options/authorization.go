@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Authorization configures which routes of the front-proxy can be used without authentication.
+type Authorization struct {
+	// UnauthenticatedPaths are the paths requests without credentials are accepted for. A "*" path
+	// segment matches any single segment. All other requests require authentication. Requests with
+	// bearer tokens are passed through to the shards if the front-proxy does not authenticate them.
+	UnauthenticatedPaths []string
+}
+
+// NewAuthorization creates a default Authorization, which only allows health, version and
+// service account issuer discovery requests without authentication.
+func NewAuthorization() *Authorization {
+	return &Authorization{
+		UnauthenticatedPaths: []string{
+			"/healthz",
+			"/livez",
+			"/readyz",
+			"/version",
+			"/.well-known/openid-configuration",
+			"/openid/v1/jwks",
+			"/clusters/*/version",
+			"/clusters/*/.well-known/openid-configuration",
+			"/clusters/*/openid/v1/jwks",
+		},
+	}
+}
+
+func (a *Authorization) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&a.UnauthenticatedPaths, "unauthenticated-paths", a.UnauthenticatedPaths,
+		"Paths that can be requested without authentication. A \"*\" path segment matches any single "+
+			"segment, e.g. /clusters/*/version. Requests without credentials to all other paths are rejected. "+
+			"Bearer tokens are passed through for the shards to authenticate, unless additional authentication methods are enabled.")
+}
+
+func (a *Authorization) Validate() []error {
+	var errs []error
+	for _, p := range a.UnauthenticatedPaths {
+		if !strings.HasPrefix(p, "/") {
+			errs = append(errs, fmt.Errorf("--unauthenticated-paths entry %q must start with a slash", p))
+			continue
+		}
+		for _, segment := range strings.Split(p[1:], "/") {
+			if strings.Contains(segment, "*") && segment != "*" {
+				errs = append(errs, fmt.Errorf("--unauthenticated-paths entry %q must only use \"*\" as a whole path segment", p))
+				break
+			}
+		}
+	}
+	return errs
+}
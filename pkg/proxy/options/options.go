@@ -18,18 +18,43 @@ package options
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/pflag"
 
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
 )
 
+// MappingTemplate holds the certificate paths of generated path mappings.
+type MappingTemplate struct {
+	BackendServerCA string
+	ProxyClientCert string
+	ProxyClientKey  string
+}
+
+// Console configures a web console served by the front-proxy under Path.
+type Console struct {
+	Path            string
+	Backend         string
+	BackendServerCA string
+	ProxyClientCert string
+	ProxyClientKey  string
+}
+
+// reservedPaths are served by the front-proxy itself or routed to shards, and cannot host a console.
+var reservedPaths = []string{"/clusters/", "/services/", "/metrics", "/readyz", "/livez"}
+
 type Options struct {
 	SecureServing    apiserveroptions.SecureServingOptionsWithLoopback
 	Authentication   Authentication
+	Authorization    Authorization
 	MappingFile      string
+	GenerateMapping  bool
+	MappingTemplate  MappingTemplate
+	Console          Console
 	RootDirectory    string
 	RootKubeconfig   string
 	ShardsKubeconfig string
@@ -40,6 +65,7 @@ func NewOptions() *Options {
 	o := &Options{
 		SecureServing:  *apiserveroptions.NewSecureServingOptions().WithLoopback(),
 		Authentication: *NewAuthentication(),
+		Authorization:  *NewAuthorization(),
 		RootKubeconfig: "",
 		RootDirectory:  ".kcp",
 	}
@@ -54,7 +80,17 @@ func NewOptions() *Options {
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	o.SecureServing.AddFlags(fs)
 	o.Authentication.AddFlags(fs)
-	fs.StringVar(&o.MappingFile, "mapping-file", o.MappingFile, "Config file mapping paths to backends")
+	o.Authorization.AddFlags(fs)
+	fs.StringVar(&o.MappingFile, "mapping-file", o.MappingFile, "Config file mapping paths to backends. With --generate-mapping, the generated mapping is written to this file if set.")
+	fs.BoolVar(&o.GenerateMapping, "generate-mapping", o.GenerateMapping, "Generate the path mapping from the Shards in the root shard, and update it as shards are added or removed.")
+	fs.StringVar(&o.MappingTemplate.BackendServerCA, "mapping-backend-server-ca", o.MappingTemplate.BackendServerCA, "The CA file to verify shard serving certificates with in a generated mapping.")
+	fs.StringVar(&o.MappingTemplate.ProxyClientCert, "mapping-proxy-client-cert", o.MappingTemplate.ProxyClientCert, "The client certificate file to authenticate against shards with in a generated mapping.")
+	fs.StringVar(&o.MappingTemplate.ProxyClientKey, "mapping-proxy-client-key", o.MappingTemplate.ProxyClientKey, "The client key file to authenticate against shards with in a generated mapping.")
+	fs.StringVar(&o.Console.Path, "console-path", o.Console.Path, "The path prefix, e.g. /console/, under which requests are proxied to --console-backend. Requests are authenticated like all other requests, and the user is passed to the console in the X-Remote-User, X-Remote-Group and X-Remote-Extra- headers. The Authorization, Cookie and Impersonate- headers of the client are not passed to the console.")
+	fs.StringVar(&o.Console.Backend, "console-backend", o.Console.Backend, "The URL of the web console to proxy requests under --console-path to.")
+	fs.StringVar(&o.Console.BackendServerCA, "console-backend-server-ca", o.Console.BackendServerCA, "The CA file to verify the serving certificate of the console backend with.")
+	fs.StringVar(&o.Console.ProxyClientCert, "console-proxy-client-cert", o.Console.ProxyClientCert, "The client certificate file to authenticate against the console backend with.")
+	fs.StringVar(&o.Console.ProxyClientKey, "console-proxy-client-key", o.Console.ProxyClientKey, "The client key file to authenticate against the console backend with.")
 	fs.StringVar(&o.RootDirectory, "root-directory", o.RootDirectory, "Root directory.")
 	fs.StringVar(&o.RootKubeconfig, "root-kubeconfig", o.RootKubeconfig, "The path to the kubeconfig of the root shard.")
 	fs.StringVar(&o.ShardsKubeconfig, "shards-kubeconfig", o.ShardsKubeconfig, "The path to the kubeconfig used for communication with all shards. The server name if provided is replaced with a shard's hostname.")
@@ -83,15 +119,56 @@ func (o *Options) Complete() error {
 func (o *Options) Validate() []error {
 	var errs []error
 
-	if o.MappingFile == "" {
+	if o.GenerateMapping {
+		if o.MappingTemplate.BackendServerCA == "" {
+			errs = append(errs, fmt.Errorf("--mapping-backend-server-ca is required with --generate-mapping"))
+		}
+		if o.MappingTemplate.ProxyClientCert == "" {
+			errs = append(errs, fmt.Errorf("--mapping-proxy-client-cert is required with --generate-mapping"))
+		}
+		if o.MappingTemplate.ProxyClientKey == "" {
+			errs = append(errs, fmt.Errorf("--mapping-proxy-client-key is required with --generate-mapping"))
+		}
+	} else if o.MappingFile == "" {
 		errs = append(errs, fmt.Errorf("--mapping-file is required"))
 	}
 	if len(o.ShardsKubeconfig) == 0 {
 		errs = append(errs, fmt.Errorf("--shards-kubeconfig is required"))
 	}
 
+	errs = append(errs, o.Console.Validate()...)
 	errs = append(errs, o.SecureServing.Validate()...)
 	errs = append(errs, o.Authentication.Validate()...)
+	errs = append(errs, o.Authorization.Validate()...)
+
+	return errs
+}
+
+func (c *Console) Validate() []error {
+	if c.Path == "" && c.Backend == "" {
+		return nil
+	}
+
+	var errs []error
+	if c.Path == "" {
+		errs = append(errs, fmt.Errorf("--console-path is required with --console-backend"))
+	} else if !strings.HasPrefix(c.Path, "/") || !strings.HasSuffix(c.Path, "/") || c.Path == "/" {
+		errs = append(errs, fmt.Errorf("--console-path must start and end with a slash, and must not be /"))
+	} else {
+		for _, reserved := range reservedPaths {
+			if strings.HasPrefix(c.Path, reserved) || strings.HasPrefix(reserved, c.Path) {
+				errs = append(errs, fmt.Errorf("--console-path %q overlaps with %q", c.Path, reserved))
+			}
+		}
+	}
+	if c.Backend == "" {
+		errs = append(errs, fmt.Errorf("--console-backend is required with --console-path"))
+	} else if u, err := url.Parse(c.Backend); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("--console-backend must be an http or https URL"))
+	}
+	if (c.ProxyClientCert == "") != (c.ProxyClientKey == "") {
+		errs = append(errs, fmt.Errorf("--console-proxy-client-cert and --console-proxy-client-key must be set together"))
+	}
 
 	return errs
 }
@@ -22,6 +22,9 @@ import (
 	"net/http"
 	"time"
 
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	genericfilters "k8s.io/apiserver/pkg/server/filters"
@@ -31,6 +34,7 @@ import (
 
 	frontproxyfilters "github.com/kcp-dev/kcp/pkg/proxy/filters"
 	"github.com/kcp-dev/kcp/pkg/proxy/index"
+	"github.com/kcp-dev/kcp/pkg/proxy/mapping"
 	"github.com/kcp-dev/kcp/pkg/proxy/metrics"
 	"github.com/kcp-dev/kcp/pkg/server"
 	"github.com/kcp-dev/kcp/pkg/server/requestinfo"
@@ -44,7 +48,10 @@ type Server struct {
 	CompletedConfig
 	Handler                  http.Handler
 	IndexController          *index.Controller
+	MappingController        *mapping.Controller
 	KcpSharedInformerFactory kcpinformers.SharedScopedInformerFactory
+	// KubeSharedInformerFactory is only set if service accounts are watched to revoke cached tokens.
+	KubeSharedInformerFactory kcpkubernetesinformers.SharedInformerFactory
 }
 
 func NewServer(ctx context.Context, c CompletedConfig) (*Server, error) {
@@ -70,12 +77,45 @@ func NewServer(ctx context.Context, c CompletedConfig) (*Server, error) {
 		},
 	)
 
-	handler, err := NewHandler(ctx, s.CompletedConfig.Options, s.IndexController)
-	if err != nil {
-		return s, err
+	// revoke cached tokens of deleted service accounts
+	if s.CompletedConfig.TokenCache != nil && s.CompletedConfig.Options.Authentication.ServiceAccountAuthEnabled() {
+		kubeClient, err := kcpkubernetesclientset.NewForConfig(s.CompletedConfig.RootShardConfig)
+		if err != nil {
+			return s, fmt.Errorf("failed to create kube client for informers: %w", err)
+		}
+		s.KubeSharedInformerFactory = kcpkubernetesinformers.NewSharedInformerFactory(kubeClient, 30*time.Minute)
+		s.KubeSharedInformerFactory.Core().V1().ServiceAccounts().Informer().AddEventHandler(s.CompletedConfig.TokenCache.ServiceAccountEventHandler())
+	}
+
+	var handler http.Handler
+	if s.CompletedConfig.Options.GenerateMapping {
+		dynamicHandler := NewDynamicHandler(ctx, s.IndexController)
+		s.MappingController = mapping.NewController(
+			s.KcpSharedInformerFactory.Core().V1alpha1().Shards(),
+			mapping.Template(s.CompletedConfig.Options.MappingTemplate),
+			s.CompletedConfig.Options.MappingFile,
+			dynamicHandler.SetMapping,
+		)
+		handler = dynamicHandler
+	} else {
+		handler, err = NewHandler(ctx, s.CompletedConfig.Options, s.IndexController)
+		if err != nil {
+			return s, err
+		}
+	}
+
+	if s.CompletedConfig.Options.Console.Path != "" {
+		consoleHandler, err := NewConsoleHandler(s.CompletedConfig.Options.Console)
+		if err != nil {
+			return s, err
+		}
+		handler = WithConsole(handler, consoleHandler, s.CompletedConfig.Options.Console.Path)
 	}
 
 	failedHandler := frontproxyfilters.NewUnauthorizedHandler()
+	// without additional authentication methods, bearer tokens are authenticated by the shards.
+	passThroughTokens := !s.CompletedConfig.AdditionalAuthEnabled || s.CompletedConfig.AuthenticationInfo.Authenticator == nil
+	handler = frontproxyfilters.WithRequiredAuthentication(handler, failedHandler, s.CompletedConfig.Options.Authorization.UnauthenticatedPaths, passThroughTokens)
 	handler = frontproxyfilters.WithOptionalAuthentication(
 		handler,
 		failedHandler,
@@ -132,9 +172,16 @@ func (s preparedServer) Run(ctx context.Context) error {
 
 	// start index
 	go s.IndexController.Start(ctx, 2)
+	if s.MappingController != nil {
+		go s.MappingController.Start(ctx)
+	}
 
 	s.KcpSharedInformerFactory.Start(ctx.Done())
 	s.KcpSharedInformerFactory.WaitForCacheSync(ctx.Done())
+	if s.KubeSharedInformerFactory != nil {
+		s.KubeSharedInformerFactory.Start(ctx.Done())
+		s.KubeSharedInformerFactory.WaitForCacheSync(ctx.Done())
+	}
 
 	doneCh, _, err := s.CompletedConfig.ServingInfo.Serve(s.Handler, time.Second*60, ctx.Done())
 	if err != nil {
@@ -38,12 +38,15 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/events"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
@@ -59,6 +62,18 @@ var (
 	SystemBoundCRDsClusterName = logicalcluster.Name("system:bound-crds")
 )
 
+// SchemaRollout decides how newer resource schemas of APIExports are rolled out to bound resources of APIBindings.
+type SchemaRollout string
+
+const (
+	// SchemaRolloutAutomatic serves bound resources with the latest resource schemas of their APIExports, unless
+	// pinned.
+	SchemaRolloutAutomatic SchemaRollout = "Automatic"
+	// SchemaRolloutManual keeps serving bound resources with the resource schemas they are bound with. Newer schemas
+	// are rolled out by pinning them in the APIBinding.
+	SchemaRolloutManual SchemaRollout = "Manual"
+)
+
 // NewController returns a new controller for APIBindings.
 func NewController(
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
@@ -71,13 +86,17 @@ func NewController(
 	globalAPIResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
 	globalAPIConversionInformer apisv1alpha1informers.APIConversionClusterInformer,
 	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+	eventRecorder record.EventRecorder,
+	schemaRollout SchemaRollout,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &controller{
 		queue:            queue,
 		crdClusterClient: crdClusterClient,
 		kcpClusterClient: kcpClusterClient,
+		eventRecorder:    eventRecorder,
+		schemaRollout:    schemaRollout,
 
 		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
 			list, err := apiBindingInformer.Lister().List(labels.Everything())
@@ -168,10 +187,14 @@ func NewController(
 		listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdInformer.Lister().Cluster(clusterName).List(labels.Everything())
 		},
+
 		deletedCRDTracker: newLockedStringSet(),
+		now:               time.Now,
 		commit:            committer.NewCommitter[*APIBinding, Patcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings()),
 	}
 
+	c.enqueueAfter = c.enqueueAPIBindingAfter
+
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
 
 	// APIBinding indexers
@@ -306,6 +329,8 @@ type controller struct {
 
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface
 	kcpClusterClient kcpclientset.ClusterInterface
+	eventRecorder    record.EventRecorder
+	schemaRollout    SchemaRollout
 
 	listAPIBindings            func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
 	listAPIBindingsByAPIExport func(apiExport *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error)
@@ -323,6 +348,8 @@ type controller struct {
 	listCRDs  func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
 
 	deletedCRDTracker *lockedStringSet
+	now               func() time.Time
+	enqueueAfter      func(*apisv1alpha1.APIBinding, time.Duration)
 	commit            CommitFunc
 }
 
@@ -338,6 +365,16 @@ func (c *controller) enqueueAPIBinding(apiBinding *apisv1alpha1.APIBinding, logg
 	c.queue.Add(key)
 }
 
+// enqueueAPIBindingAfter enqueues an APIBinding after the given duration.
+func (c *controller) enqueueAPIBindingAfter(apiBinding *apisv1alpha1.APIBinding, duration time.Duration) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(apiBinding)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.AddAfter(key, duration)
+}
+
 // enqueueAPIExport enqueues maps an APIExport to APIBindings for enqueuing.
 func (c *controller) enqueueAPIExport(export *apisv1alpha1.APIExport, logger logr.Logger, logSuffix string) {
 	bindings, err := c.listAPIBindingsByAPIExport(export)
@@ -490,6 +527,9 @@ func (c *controller) process(ctx context.Context, key string) (bool, error) {
 	newResource := &Resource{ObjectMeta: binding.ObjectMeta, Spec: &binding.Spec, Status: &binding.Status}
 	if err := c.commit(ctx, oldResource, newResource); err != nil {
 		errs = append(errs, err)
+	} else {
+		// Surface failures in the workspace of the binding, where users can see them.
+		events.RecordConditionChanges(ctx, c.eventRecorder, old, binding)
 	}
 
 	return requeue, utilerrors.NewAggregate(errs)
@@ -23,19 +23,27 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	crdvalidation "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilserrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	genericfeatures "k8s.io/apiserver/pkg/features"
 	"k8s.io/klog/v2"
 
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
 )
@@ -53,6 +61,8 @@ type reconciler interface {
 
 func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.APIBinding) (bool, error) {
 	reconcilers := []reconciler{
+		// accepting claims changes the spec. It comes first to stop before any status change.
+		&acceptanceReconciler{},
 		&phaseReconciler{
 			newReconciler:     &newReconciler{controller: c},
 			bindingReconciler: &bindingReconciler{controller: c},
@@ -79,6 +89,84 @@ func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.API
 	return requeue, utilserrors.NewAggregate(errs)
 }
 
+// acceptanceReconciler accepts the permission claims of the APIExport that match the acceptance
+// policy of the APIBinding and that are neither accepted nor rejected yet. Accepting changes the
+// spec, hence the PermissionClaimsAutoAccepted condition is only updated in the next pass.
+type acceptanceReconciler struct{}
+
+func (r *acceptanceReconciler) reconcile(ctx context.Context, apiBinding *apisv1alpha1.APIBinding) (reconcileStatus, error) {
+	policy := apiBinding.Spec.AcceptancePolicy
+	if policy == nil {
+		conditions.Delete(apiBinding, apisv1alpha1.PermissionClaimsAutoAccepted)
+		return reconcileStatusContinue, nil
+	}
+
+	logger := klog.FromContext(ctx)
+
+	var matched []string
+	specChanged := false
+	for _, claim := range apiBinding.Status.ExportPermissionClaims {
+		if !permissionclaims.PolicyAccepts(policy, claim) {
+			continue
+		}
+
+		decided := -1
+		for i, acceptable := range apiBinding.Spec.PermissionClaims {
+			if acceptable.PermissionClaim.Equal(claim) {
+				decided = i
+				break
+			}
+		}
+		if decided >= 0 {
+			acceptable := &apiBinding.Spec.PermissionClaims[decided]
+			if acceptable.State == apisv1alpha1.ClaimRejected {
+				// a rejection stands, also for a changed claim.
+				continue
+			}
+			if !equality.Semantic.DeepEqual(acceptable.PermissionClaim, claim) {
+				// the claim has changed, e.g. its selectors. Accept the changed claim.
+				logger.V(2).Info("accepting changed permission claim by acceptance policy", "claim", claim.String())
+				acceptable.PermissionClaim = *claim.DeepCopy()
+				specChanged = true
+			}
+			if acceptable.State == apisv1alpha1.ClaimAccepted {
+				matched = append(matched, claim.String())
+			}
+			continue
+		}
+
+		logger.V(2).Info("accepting permission claim by acceptance policy", "claim", claim.String())
+		apiBinding.Spec.PermissionClaims = append(apiBinding.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+			PermissionClaim: *claim.DeepCopy(),
+			State:           apisv1alpha1.ClaimAccepted,
+		})
+		matched = append(matched, claim.String())
+		specChanged = true
+	}
+
+	if specChanged {
+		// spec and status must not be committed together. Commit the accepted claims first.
+		return reconcileStatusStopAndRequeue, nil
+	}
+
+	if len(matched) == 0 {
+		conditions.Set(apiBinding, &conditionsv1alpha1.Condition{
+			Type:    apisv1alpha1.PermissionClaimsAutoAccepted,
+			Status:  corev1.ConditionTrue,
+			Reason:  apisv1alpha1.NoPermissionClaimsMatchedReason,
+			Message: "No permission claims of the APIExport match the acceptance policy",
+		})
+		return reconcileStatusContinue, nil
+	}
+	conditions.Set(apiBinding, &conditionsv1alpha1.Condition{
+		Type:    apisv1alpha1.PermissionClaimsAutoAccepted,
+		Status:  corev1.ConditionTrue,
+		Message: fmt.Sprintf("Accepted by the acceptance policy: %s", strings.Join(matched, ", ")),
+	})
+
+	return reconcileStatusContinue, nil
+}
+
 type summaryReconciler struct {
 	*controller
 }
@@ -184,11 +272,14 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 		)
 		return reconcileStatusContinue, err
 	}
+	getSchema := func(name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return r.getAPIResourceSchema(logicalcluster.From(apiExport), name)
+	}
 
 	logger = logging.WithObject(logger, apiExport)
 
-	// Record the export's permission claims
-	apiBinding.Status.ExportPermissionClaims = apiExport.Spec.PermissionClaims
+	// Record the export's permission claims and decide which schemas to bind
+	schemaNames := r.rolloutExportChanges(apiBinding, apiExport)
 
 	// Make sure the APIExport has an identity
 	if apiExport.Status.IdentityHash == "" {
@@ -209,14 +300,32 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 	clusterName := logicalcluster.From(apiExport)
 	apiBinding.Status.APIExportClusterName = clusterName.String()
 
+	r.updateProviderHealth(apiBinding, apiExport)
+
+	schemaNames, err = r.pinResourceSchemas(apiBinding, apiExport, schemaNames, getSchema)
+	if apierrors.IsNotFound(err) {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			apisv1alpha1.PinnedResourceSchemaNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Unable to bind APIs: %v",
+			err,
+		)
+		return reconcileStatusContinue, nil
+	}
+	if err != nil {
+		return reconcileStatusContinue, err
+	}
+
 	var needToWaitForRequeueWhenEstablished []string
 
 	// Process all APIResourceSchemas
-	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+	for _, schemaName := range schemaNames {
 		bindingClusterName := logicalcluster.From(apiBinding)
 
 		// Get the schema
-		schema, err := r.getAPIResourceSchema(logicalcluster.From(apiExport), schemaName)
+		schema, err := getSchema(schemaName)
 		if err != nil {
 			logger.Error(err, "error binding")
 
@@ -270,8 +379,8 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			return reconcileStatusStopAndRequeue, err
 		}
 
-		// If there are multiple versions, there must be an APIConversion
-		if len(schema.Spec.Versions) > 1 {
+		// If there are multiple versions without conversion strategy, there must be an APIConversion
+		if len(schema.Spec.Versions) > 1 && schema.Spec.Conversion == nil {
 			if _, err := r.getAPIConversion(logicalcluster.From(schema), schema.Name); err != nil {
 				// Need to wait until the APIConversion is present before we can proceed to create the bound CRD
 				conditions.MarkFalse(
@@ -293,8 +402,35 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			}
 		}
 
+		// Without the feature gate, the validation rules would silently be dropped from the bound CRD.
+		if !kcpfeatures.DefaultFeatureGate.Enabled(genericfeatures.CustomResourceValidationExpressions) && schemaHasValidationRules(schema) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.APIExportValid,
+				apisv1alpha1.APIResourceSchemaInvalidReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"APIResourceSchema %s has x-kubernetes-validations rules, which require the %s feature gate",
+				schemaName,
+				genericfeatures.CustomResourceValidationExpressions,
+			)
+			return reconcileStatusContinue, nil
+		}
+
+		// Keep the identity the objects were migrated to while the identity of the APIExport is rotated
+		identityHash := apiExport.Status.IdentityHash
+		for _, b := range apiBinding.Status.BoundResources {
+			if b.Group == schema.Spec.Group && b.Resource == schema.Spec.Names.Plural {
+				if b.Schema.IdentityHash != "" && b.Schema.IdentityHash == apiExport.Status.NextIdentityHash {
+					identityHash = b.Schema.IdentityHash
+				}
+				break
+			}
+		}
+
 		// Try to get the bound CRD
-		existingCRD, err := r.getCRD(SystemBoundCRDsClusterName, boundCRDName(schema))
+		crdName, existingCRD, err := GetBoundCRD(func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return r.getCRD(SystemBoundCRDsClusterName, name)
+		}, boundCRDName(schema), identityHash)
 		if err != nil && !apierrors.IsNotFound(err) {
 			conditions.MarkFalse(
 				apiBinding,
@@ -306,7 +442,7 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 
 			return reconcileStatusContinue, fmt.Errorf(
 				"error getting CRD %s|%s for APIBinding %s|%s, APIExport %s|%s, APIResourceSchema %s|%s: %w",
-				SystemBoundCRDsClusterName, boundCRDName(schema),
+				SystemBoundCRDsClusterName, crdName,
 				bindingClusterName, apiBinding.Name,
 				apiExportPath, apiExport.Name,
 				apiExportPath, schemaName,
@@ -341,6 +477,8 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 
 				return reconcileStatusContinue, nil
 			}
+			crd.Name = crdName
+			crd.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey] = identityHash
 			logger = logging.WithObject(logger, crd).WithValues(
 				"groupResource", fmt.Sprintf("%s.%s", crd.Spec.Names.Plural, crd.Spec.Group),
 			)
@@ -430,10 +568,11 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 		newBoundResource := apisv1alpha1.BoundAPIResource{
 			Group:    schema.Spec.Group,
 			Resource: schema.Spec.Names.Plural,
+			Alias:    resourceAlias(apiBinding, schema.Spec.Group, schema.Spec.Names.Plural),
 			Schema: apisv1alpha1.BoundAPIResourceSchema{
 				Name:         schema.Name,
 				UID:          string(schema.UID),
-				IdentityHash: apiExport.Status.IdentityHash,
+				IdentityHash: identityHash,
 			},
 			StorageVersions: sortedStorageVersions,
 		}
@@ -483,10 +622,220 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 	return reconcileStatusContinue, nil
 }
 
+// updateProviderHealth propagates the health reported by the provider of the APIExport to the ProviderHealthy
+// condition, and requeues the APIBinding for when the last heartbeat expires.
+func (r *bindingReconciler) updateProviderHealth(apiBinding *apisv1alpha1.APIBinding, apiExport *apisv1alpha1.APIExport) {
+	health := apiExport.Status.ProviderHealth
+	if health == nil {
+		conditions.Delete(apiBinding, apisv1alpha1.ProviderHealthy)
+		return
+	}
+
+	now := r.now()
+	expiry := health.LastHeartbeatTime.Add(time.Duration(health.LeaseDurationSeconds) * time.Second)
+	if !now.Before(expiry) {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.ProviderHealthy,
+			apisv1alpha1.ProviderHeartbeatExpiredReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"The provider of APIExport %s has not reported its health since %s",
+			apiExport.Name,
+			health.LastHeartbeatTime.UTC().Format(time.RFC3339),
+		)
+		return
+	}
+	r.enqueueAfter(apiBinding, expiry.Sub(now))
+
+	if !health.Healthy {
+		message := health.Message
+		if message == "" {
+			message = fmt.Sprintf("The provider of APIExport %s reported to be unhealthy", apiExport.Name)
+		}
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.ProviderHealthy,
+			apisv1alpha1.ProviderUnhealthyReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"%s",
+			message,
+		)
+		return
+	}
+	conditions.MarkTrue(apiBinding, apisv1alpha1.ProviderHealthy)
+}
+
+// rolloutExportChanges records the permission claims of the APIExport in the APIBinding and returns the
+// resource schemas to bind. If the APIExport has maintenance windows, changes of the schemas and claims
+// are rolled out to bound APIBindings only while a window is open. Outside of the windows, the changes
+// are pending and the APIBinding is requeued for when the next window opens.
+func (r *bindingReconciler) rolloutExportChanges(apiBinding *apisv1alpha1.APIBinding, apiExport *apisv1alpha1.APIExport) []string {
+	if len(apiExport.Spec.MaintenanceWindows) == 0 {
+		conditions.Delete(apiBinding, apisv1alpha1.ExportChangesApplied)
+		apiBinding.Status.ExportPermissionClaims = apiExport.Spec.PermissionClaims
+		return apiExport.Spec.LatestResourceSchemas
+	}
+
+	boundSchemas := sets.NewString()
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		boundSchemas.Insert(boundResource.Schema.Name)
+	}
+	var unchangedSchemas []string
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		if boundSchemas.Has(schemaName) {
+			unchangedSchemas = append(unchangedSchemas, schemaName)
+		}
+	}
+
+	var pending []string
+	if len(unchangedSchemas) < len(apiExport.Spec.LatestResourceSchemas) {
+		pending = append(pending, "resource schemas")
+	}
+	if !equality.Semantic.DeepEqual(apiBinding.Status.ExportPermissionClaims, apiExport.Spec.PermissionClaims) {
+		pending = append(pending, "permission claims")
+	}
+
+	now := r.now()
+	open, next := maintenanceWindowAt(apiExport.Spec.MaintenanceWindows, now)
+	if len(pending) == 0 || open || next.IsZero() || apiBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBound {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.ExportChangesApplied)
+		apiBinding.Status.ExportPermissionClaims = apiExport.Spec.PermissionClaims
+		return apiExport.Spec.LatestResourceSchemas
+	}
+
+	conditions.MarkFalse(
+		apiBinding,
+		apisv1alpha1.ExportChangesApplied,
+		apisv1alpha1.OutsideMaintenanceWindowReason,
+		conditionsv1alpha1.ConditionSeverityInfo,
+		"Changes of the %s of APIExport %s are pending until the next maintenance window at %s",
+		strings.Join(pending, " and "),
+		apiExport.Name,
+		next.Format(time.RFC3339),
+	)
+	r.enqueueAfter(apiBinding, next.Sub(now))
+	return unchangedSchemas
+}
+
+// pinResourceSchemas returns the resource schemas to bind, with the latest schemas of the APIExport replaced by the
+// schemas the resources are pinned to, explicitly in the APIBinding, or implicitly by their bound schemas with manual
+// schema rollout. It reports the resources not served with their latest schemas in the SchemaDrift condition, which
+// is only set if the APIBinding has pins or the schema rollout is manual.
+//
+// Only schemas the APIExport exports, i.e. the latest ones and those the resources are bound with, can be pinned.
+// Other pins are ignored, such that consumers cannot bind schemas of the provider workspace that were never exported.
+func (r *bindingReconciler) pinResourceSchemas(apiBinding *apisv1alpha1.APIBinding, apiExport *apisv1alpha1.APIExport, schemaNames []string, getSchema func(name string) (*apisv1alpha1.APIResourceSchema, error)) ([]string, error) {
+	if len(apiBinding.Spec.PinnedResourceSchemas) == 0 && r.schemaRollout != SchemaRolloutManual {
+		conditions.Delete(apiBinding, apisv1alpha1.SchemaDrift)
+		return schemaNames, nil
+	}
+
+	pinnable := sets.NewString(apiExport.Spec.LatestResourceSchemas...)
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		pinnable.Insert(boundResource.Schema.Name)
+	}
+
+	// resources are keyed by <resource>.<group>
+	pinned := map[string]string{}
+	for _, name := range apiBinding.Spec.PinnedResourceSchemas {
+		if !pinnable.Has(name) {
+			continue
+		}
+		schema, err := getSchema(name)
+		if err != nil {
+			return nil, err
+		}
+		pinned[schema.Spec.Names.Plural+"."+schema.Spec.Group] = name
+	}
+	bound := map[string]string{}
+	if r.schemaRollout == SchemaRolloutManual {
+		for _, boundResource := range apiBinding.Status.BoundResources {
+			bound[boundResource.Resource+"."+boundResource.Group] = boundResource.Schema.Name
+		}
+	}
+
+	ret := make([]string, 0, len(schemaNames))
+	var drifted []string
+	reason := apisv1alpha1.ManualSchemaRolloutReason
+	for _, name := range schemaNames {
+		latest, err := getSchema(name)
+		if err != nil {
+			// reported when binding the schema
+			ret = append(ret, name)
+			continue
+		}
+		resource := latest.Spec.Names.Plural + "." + latest.Spec.Group
+
+		bindName := name
+		if pinnedName, found := pinned[resource]; found {
+			bindName = pinnedName
+			if bindName != name {
+				reason = apisv1alpha1.PinnedResourceSchemasReason
+			}
+		} else if boundName, found := bound[resource]; found {
+			// keep the bound schema, unless it has been deleted.
+			if _, err := getSchema(boundName); err == nil {
+				bindName = boundName
+			}
+		}
+		if bindName != name {
+			drifted = append(drifted, fmt.Sprintf("%s (%s instead of %s)", resource, bindName, name))
+		}
+		ret = append(ret, bindName)
+	}
+
+	if len(drifted) == 0 {
+		conditions.Set(apiBinding, &conditionsv1alpha1.Condition{
+			Type:    apisv1alpha1.SchemaDrift,
+			Status:  corev1.ConditionFalse,
+			Reason:  apisv1alpha1.NoSchemaDriftReason,
+			Message: fmt.Sprintf("All resources are served with the latest resource schemas of APIExport %s", apiExport.Name),
+		})
+		return ret, nil
+	}
+	sort.Strings(drifted)
+	conditions.Set(apiBinding, &conditionsv1alpha1.Condition{
+		Type:    apisv1alpha1.SchemaDrift,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: fmt.Sprintf("Resources are not served with the latest resource schemas of APIExport %s: %s", apiExport.Name, strings.Join(drifted, ", ")),
+	})
+	return ret, nil
+}
+
 func boundCRDName(schema *apisv1alpha1.APIResourceSchema) string {
 	return string(schema.UID)
 }
 
+// resourceAlias returns the resource name the APIBinding serves the given resource of its APIExport as,
+// or the empty string if the resource is not overridden.
+func resourceAlias(apiBinding *apisv1alpha1.APIBinding, group, resource string) string {
+	for _, override := range apiBinding.Spec.ResourceOverrides {
+		if override.Group == group && override.Resource == resource {
+			return override.Alias
+		}
+	}
+	return ""
+}
+
+// schemaHasValidationRules returns true if any version of the schema has CEL validation rules.
+func schemaHasValidationRules(schema *apisv1alpha1.APIResourceSchema) bool {
+	for i := range schema.Spec.Versions {
+		var v1Schema apiextensionsv1.JSONSchemaProps
+		var internalSchema apiextensionsinternal.JSONSchemaProps
+		if err := json.Unmarshal(schema.Spec.Versions[i].Schema.Raw, &v1Schema); err != nil {
+			continue // invalid schemas are rejected by generateCRD
+		}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&v1Schema, &internalSchema, nil); err != nil {
+			continue
+		}
+		if crdvalidation.SchemaHas(&internalSchema, func(s *apiextensionsinternal.JSONSchemaProps) bool { return len(s.XValidations) > 0 }) {
+			return true
+		}
+	}
+	return false
+}
+
 func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.CustomResourceDefinition, error) {
 	crd := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -534,5 +883,21 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 		crd.Spec.Versions = append(crd.Spec.Versions, crdVersion)
 	}
 
+	if conversion := schema.Spec.Conversion; conversion != nil {
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.ConversionStrategyType(conversion.Strategy),
+		}
+		if conversion.Webhook != nil {
+			url := conversion.Webhook.ClientConfig.URL
+			crd.Spec.Conversion.Webhook = &apiextensionsv1.WebhookConversion{
+				ClientConfig: &apiextensionsv1.WebhookClientConfig{
+					URL:      &url,
+					CABundle: conversion.Webhook.ClientConfig.CABundle,
+				},
+				ConversionReviewVersions: conversion.Webhook.ConversionReviewVersions,
+			}
+		}
+	}
+
 	return crd, nil
 }
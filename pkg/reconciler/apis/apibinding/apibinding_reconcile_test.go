@@ -19,13 +19,16 @@ package apibinding
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -306,6 +309,33 @@ func TestReconcileBinding(t *testing.T) {
 			wantRequeue:        true,
 			wantNoReady:        true,
 		},
+		"bind existing CRD with resource override - other bindings - no conflicts": {
+			apiBinding: binding.DeepCopy().WithResourceOverride("kcp.io", "widgets", "today-widgets").Build(),
+			existingAPIBindings: []*apisv1alpha1.APIBinding{
+				conflicting.Build(),
+			},
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v0", "v1"},
+			wantAPIExportValid: true,
+			wantReady:          true,
+			wantBoundAPIExport: true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.io",
+					Resource: "widgets",
+					Alias:    "today-widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "today.widgets.kcp.io",
+						UID:          "todaywidgetsuid",
+						IdentityHash: "hash1",
+					},
+					StorageVersions: []string{"v0", "v1"},
+				},
+			},
+			wantPhaseBound:             true,
+			wantInitialBindingComplete: true,
+		},
 		"CRD already exists but isn't established yet": {
 			apiBinding:                binding.Build(),
 			getCRDError:               nil,
@@ -631,6 +661,438 @@ func TestReconcileBinding(t *testing.T) {
 	}
 }
 
+func TestUpdateProviderHealth(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat := func(healthy bool, message string, age time.Duration) *apisv1alpha1.ProviderHealth {
+		return &apisv1alpha1.ProviderHealth{
+			Healthy:              healthy,
+			Message:              message,
+			LastHeartbeatTime:    metav1.NewTime(now.Add(-age)),
+			LeaseDurationSeconds: 60,
+		}
+	}
+
+	tests := map[string]struct {
+		health        *apisv1alpha1.ProviderHealth
+		wantCondition *conditionsv1alpha1.Condition
+		wantRequeue   time.Duration
+	}{
+		"never reported": {},
+		"healthy": {
+			health:        heartbeat(true, "", 20*time.Second),
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue},
+			wantRequeue:   40 * time.Second,
+		},
+		"unhealthy": {
+			health: heartbeat(false, "database down", 20*time.Second),
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.ProviderUnhealthyReason,
+				Message:  "database down",
+			},
+			wantRequeue: 40 * time.Second,
+		},
+		"heartbeat expired": {
+			health: heartbeat(true, "", 60*time.Second),
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.ProviderHeartbeatExpiredReason,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := newBindingBuilder().WithName("binding").Build()
+			conditions.MarkTrue(apiBinding, apisv1alpha1.ProviderHealthy)
+			apiExport := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{Name: "export"},
+				Status:     apisv1alpha1.APIExportStatus{ProviderHealth: tc.health},
+			}
+
+			var requeue time.Duration
+			r := &bindingReconciler{controller: &controller{
+				now:          func() time.Time { return now },
+				enqueueAfter: func(_ *apisv1alpha1.APIBinding, d time.Duration) { requeue = d },
+			}}
+			r.updateProviderHealth(apiBinding, apiExport)
+
+			require.Equal(t, tc.wantRequeue, requeue)
+			if tc.wantCondition == nil {
+				require.Nil(t, conditions.Get(apiBinding, apisv1alpha1.ProviderHealthy))
+				return
+			}
+			tc.wantCondition.Type = apisv1alpha1.ProviderHealthy
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
+func TestRolloutExportChanges(t *testing.T) {
+	// a Monday
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	sunday := []apisv1alpha1.MaintenanceWindow{{
+		Days:     []apisv1alpha1.MaintenanceWindowDay{"Sunday"},
+		Start:    "02:00",
+		Duration: metav1.Duration{Duration: 4 * time.Hour},
+	}}
+	monday := []apisv1alpha1.MaintenanceWindow{{
+		Days:     []apisv1alpha1.MaintenanceWindowDay{"Monday"},
+		Start:    "11:00",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}}
+	configmaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	bound := apisv1alpha1.BoundAPIResource{
+		Group:    "kcp.io",
+		Resource: "widgets",
+		Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.kcp.io"},
+	}
+
+	tests := map[string]struct {
+		phase         apisv1alpha1.APIBindingPhaseType
+		windows       []apisv1alpha1.MaintenanceWindow
+		schemas       []string
+		claims        []apisv1alpha1.PermissionClaim
+		wantSchemas   []string
+		wantClaims    []apisv1alpha1.PermissionClaim
+		wantCondition *conditionsv1alpha1.Condition
+		wantRequeue   time.Duration
+	}{
+		"no maintenance windows": {
+			phase:       apisv1alpha1.APIBindingPhaseBound,
+			schemas:     []string{"v2.widgets.kcp.io"},
+			claims:      []apisv1alpha1.PermissionClaim{configmaps},
+			wantSchemas: []string{"v2.widgets.kcp.io"},
+			wantClaims:  []apisv1alpha1.PermissionClaim{configmaps},
+		},
+		"no changes outside of window": {
+			phase:         apisv1alpha1.APIBindingPhaseBound,
+			windows:       sunday,
+			schemas:       []string{"v1.widgets.kcp.io"},
+			wantSchemas:   []string{"v1.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue},
+		},
+		"changes outside of window": {
+			phase:       apisv1alpha1.APIBindingPhaseBound,
+			windows:     sunday,
+			schemas:     []string{"v2.widgets.kcp.io", "v1.widgets.kcp.io"},
+			claims:      []apisv1alpha1.PermissionClaim{configmaps},
+			wantSchemas: []string{"v1.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityInfo,
+				Reason:   apisv1alpha1.OutsideMaintenanceWindowReason,
+				Message:  "Changes of the resource schemas and permission claims of APIExport export are pending until the next maintenance window at 2023-05-07T02:00:00Z",
+			},
+			wantRequeue: 5*24*time.Hour + 14*time.Hour,
+		},
+		"changes during window": {
+			phase:         apisv1alpha1.APIBindingPhaseBound,
+			windows:       monday,
+			schemas:       []string{"v2.widgets.kcp.io"},
+			claims:        []apisv1alpha1.PermissionClaim{configmaps},
+			wantSchemas:   []string{"v2.widgets.kcp.io"},
+			wantClaims:    []apisv1alpha1.PermissionClaim{configmaps},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue},
+		},
+		"initial binding outside of window": {
+			phase:         apisv1alpha1.APIBindingPhaseBinding,
+			windows:       sunday,
+			schemas:       []string{"v2.widgets.kcp.io"},
+			claims:        []apisv1alpha1.PermissionClaim{configmaps},
+			wantSchemas:   []string{"v2.widgets.kcp.io"},
+			wantClaims:    []apisv1alpha1.PermissionClaim{configmaps},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := newBindingBuilder().WithName("binding").WithPhase(tc.phase).WithBoundResources(bound).Build()
+			apiExport := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{Name: "export"},
+				Spec: apisv1alpha1.APIExportSpec{
+					LatestResourceSchemas: tc.schemas,
+					PermissionClaims:      tc.claims,
+					MaintenanceWindows:    tc.windows,
+				},
+			}
+
+			var requeue time.Duration
+			r := &bindingReconciler{controller: &controller{
+				now:          func() time.Time { return now },
+				enqueueAfter: func(_ *apisv1alpha1.APIBinding, d time.Duration) { requeue = d },
+			}}
+			schemas := r.rolloutExportChanges(apiBinding, apiExport)
+
+			require.Equal(t, tc.wantSchemas, schemas)
+			require.Equal(t, tc.wantClaims, apiBinding.Status.ExportPermissionClaims)
+			require.Equal(t, tc.wantRequeue, requeue)
+			if tc.wantCondition == nil {
+				require.Nil(t, conditions.Get(apiBinding, apisv1alpha1.ExportChangesApplied))
+				return
+			}
+			tc.wantCondition.Type = apisv1alpha1.ExportChangesApplied
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
+func TestPinResourceSchemas(t *testing.T) {
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{}
+	for _, name := range []string{"v1.widgets.kcp.io", "v2.widgets.kcp.io", "v3.widgets.kcp.io", "v1.gadgets.kcp.io", "v2.gadgets.kcp.io"} {
+		schemas[name] = &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "kcp.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: strings.Split(name, ".")[1]},
+			},
+		}
+	}
+	getSchema := func(name string) (*apisv1alpha1.APIResourceSchema, error) {
+		if schema, found := schemas[name]; found {
+			return schema, nil
+		}
+		return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+	}
+	boundWidgets := apisv1alpha1.BoundAPIResource{
+		Group:    "kcp.io",
+		Resource: "widgets",
+		Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.kcp.io"},
+	}
+
+	tests := map[string]struct {
+		rollout       SchemaRollout
+		pins          []string
+		schemas       []string
+		wantSchemas   []string
+		wantNotFound  bool
+		wantCondition *conditionsv1alpha1.Condition
+	}{
+		"automatic rollout without pins": {
+			rollout:     SchemaRolloutAutomatic,
+			schemas:     []string{"v2.widgets.kcp.io"},
+			wantSchemas: []string{"v2.widgets.kcp.io"},
+		},
+		"pinned to an older schema": {
+			rollout:     SchemaRolloutAutomatic,
+			pins:        []string{"v1.widgets.kcp.io"},
+			schemas:     []string{"v2.widgets.kcp.io", "v2.gadgets.kcp.io"},
+			wantSchemas: []string{"v1.widgets.kcp.io", "v2.gadgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:  corev1.ConditionTrue,
+				Reason:  apisv1alpha1.PinnedResourceSchemasReason,
+				Message: "Resources are not served with the latest resource schemas of APIExport export: widgets.kcp.io (v1.widgets.kcp.io instead of v2.widgets.kcp.io)",
+			},
+		},
+		"pinned to the latest schema": {
+			rollout:     SchemaRolloutAutomatic,
+			pins:        []string{"v2.widgets.kcp.io"},
+			schemas:     []string{"v2.widgets.kcp.io"},
+			wantSchemas: []string{"v2.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:  corev1.ConditionFalse,
+				Reason:  apisv1alpha1.NoSchemaDriftReason,
+				Message: "All resources are served with the latest resource schemas of APIExport export",
+			},
+		},
+		"pins of resources not exported are ignored": {
+			rollout:     SchemaRolloutAutomatic,
+			pins:        []string{"v1.gadgets.kcp.io"},
+			schemas:     []string{"v2.widgets.kcp.io"},
+			wantSchemas: []string{"v2.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status: corev1.ConditionFalse,
+				Reason: apisv1alpha1.NoSchemaDriftReason,
+			},
+		},
+		"pins of schemas not exported are ignored": {
+			rollout:     SchemaRolloutAutomatic,
+			pins:        []string{"v2.widgets.kcp.io"},
+			schemas:     []string{"v3.widgets.kcp.io"},
+			wantSchemas: []string{"v3.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status: corev1.ConditionFalse,
+				Reason: apisv1alpha1.NoSchemaDriftReason,
+			},
+		},
+		"pinned schema not found": {
+			rollout:      SchemaRolloutAutomatic,
+			pins:         []string{"v4.widgets.kcp.io"},
+			schemas:      []string{"v4.widgets.kcp.io"},
+			wantNotFound: true,
+		},
+		"manual rollout keeps bound schemas": {
+			rollout:     SchemaRolloutManual,
+			schemas:     []string{"v2.widgets.kcp.io", "v2.gadgets.kcp.io"},
+			wantSchemas: []string{"v1.widgets.kcp.io", "v2.gadgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:  corev1.ConditionTrue,
+				Reason:  apisv1alpha1.ManualSchemaRolloutReason,
+				Message: "Resources are not served with the latest resource schemas of APIExport export: widgets.kcp.io (v1.widgets.kcp.io instead of v2.widgets.kcp.io)",
+			},
+		},
+		"manual rollout of a pinned schema": {
+			rollout:     SchemaRolloutManual,
+			pins:        []string{"v3.widgets.kcp.io"},
+			schemas:     []string{"v3.widgets.kcp.io"},
+			wantSchemas: []string{"v3.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:  corev1.ConditionFalse,
+				Reason:  apisv1alpha1.NoSchemaDriftReason,
+				Message: "All resources are served with the latest resource schemas of APIExport export",
+			},
+		},
+		"manual rollout with pins of schemas not exported": {
+			rollout:     SchemaRolloutManual,
+			pins:        []string{"v2.widgets.kcp.io"},
+			schemas:     []string{"v3.widgets.kcp.io"},
+			wantSchemas: []string{"v1.widgets.kcp.io"},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Status:  corev1.ConditionTrue,
+				Reason:  apisv1alpha1.ManualSchemaRolloutReason,
+				Message: "Resources are not served with the latest resource schemas of APIExport export: widgets.kcp.io (v1.widgets.kcp.io instead of v3.widgets.kcp.io)",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := newBindingBuilder().WithName("binding").WithPhase(apisv1alpha1.APIBindingPhaseBound).WithBoundResources(boundWidgets).Build()
+			apiBinding.Spec.PinnedResourceSchemas = tc.pins
+			apiExport := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{Name: "export"},
+				Spec:       apisv1alpha1.APIExportSpec{LatestResourceSchemas: tc.schemas},
+			}
+
+			r := &bindingReconciler{controller: &controller{schemaRollout: tc.rollout}}
+			schemaNames, err := r.pinResourceSchemas(apiBinding, apiExport, tc.schemas, getSchema)
+			if tc.wantNotFound {
+				require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSchemas, schemaNames)
+			if tc.wantCondition == nil {
+				require.Nil(t, conditions.Get(apiBinding, apisv1alpha1.SchemaDrift))
+				return
+			}
+			tc.wantCondition.Type = apisv1alpha1.SchemaDrift
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
+func TestReconcileAcceptancePolicy(t *testing.T) {
+	configMaps := apisv1alpha1.PermissionClaim{
+		GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "team-a"}},
+	}
+	secrets := apisv1alpha1.PermissionClaim{
+		GroupResource:    apisv1alpha1.GroupResource{Resource: "secrets"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "team-a"}},
+	}
+	widgets := apisv1alpha1.PermissionClaim{
+		GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+		All:           true,
+		IdentityHash:  "abc",
+	}
+	teamA := &apisv1alpha1.PermissionClaimAcceptancePolicy{Rules: []apisv1alpha1.PermissionClaimAcceptanceRule{
+		{Resource: "*", Namespaces: []string{"team-a"}},
+	}}
+
+	tests := map[string]struct {
+		policy        *apisv1alpha1.PermissionClaimAcceptancePolicy
+		decided       []apisv1alpha1.AcceptablePermissionClaim
+		wantClaims    []apisv1alpha1.AcceptablePermissionClaim
+		wantCondition *conditionsv1alpha1.Condition
+	}{
+		"no policy": {},
+		"accepts matching claims": {
+			policy: teamA,
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue, Message: "configmaps, secrets"},
+		},
+		"keeps decisions": {
+			policy: teamA,
+			decided: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimRejected},
+				{PermissionClaim: widgets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimRejected},
+				{PermissionClaim: widgets, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue, Message: "configmaps"},
+		},
+		"accepts changed claims": {
+			policy: teamA,
+			decided: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: apisv1alpha1.PermissionClaim{
+					GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+					ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "team-a", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"a": "b"}}}},
+				}, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue, Message: "configmaps, secrets"},
+		},
+		"keeps accepted claims": {
+			policy: teamA,
+			decided: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted},
+			},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue, Message: "configmaps, secrets"},
+		},
+		"nothing matches": {
+			policy: &apisv1alpha1.PermissionClaimAcceptancePolicy{Rules: []apisv1alpha1.PermissionClaimAcceptanceRule{
+				{Resource: "*", Namespaces: []string{"team-b"}},
+			}},
+			wantCondition: &conditionsv1alpha1.Condition{Status: corev1.ConditionTrue, Reason: apisv1alpha1.NoPermissionClaimsMatchedReason},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := newBindingBuilder().WithName("binding").Build()
+			apiBinding.Spec.AcceptancePolicy = tc.policy
+			apiBinding.Spec.PermissionClaims = append([]apisv1alpha1.AcceptablePermissionClaim(nil), tc.decided...)
+			apiBinding.Status.ExportPermissionClaims = []apisv1alpha1.PermissionClaim{configMaps, secrets, widgets}
+			conditions.MarkTrue(apiBinding, apisv1alpha1.PermissionClaimsAutoAccepted)
+
+			// accepting claims changes the spec, and must not change the status in the same pass.
+			oldStatus := apiBinding.Status.DeepCopy()
+			status, err := (&acceptanceReconciler{}).reconcile(context.Background(), apiBinding)
+			require.NoError(t, err)
+			if !equality.Semantic.DeepEqual(tc.decided, apiBinding.Spec.PermissionClaims) {
+				require.Equal(t, reconcileStatusStopAndRequeue, status)
+				require.Equal(t, *oldStatus, apiBinding.Status)
+
+				status, err = (&acceptanceReconciler{}).reconcile(context.Background(), apiBinding)
+				require.NoError(t, err)
+			}
+			require.Equal(t, reconcileStatusContinue, status)
+			require.Equal(t, tc.wantClaims, apiBinding.Spec.PermissionClaims)
+			if tc.wantCondition == nil {
+				require.Nil(t, conditions.Get(apiBinding, apisv1alpha1.PermissionClaimsAutoAccepted))
+				return
+			}
+			tc.wantCondition.Type = apisv1alpha1.PermissionClaimsAutoAccepted
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
 func TestCRDFromAPIResourceSchema(t *testing.T) {
 	tests := map[string]struct {
 		schema  *apisv1alpha1.APIResourceSchema
@@ -816,6 +1278,182 @@ func TestCRDFromAPIResourceSchema(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		"validation rules": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-name",
+					UID:         types.UID("my-uuid"),
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "my-cluster"},
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`{"type":"object","x-kubernetes-validations":[{"rule":"self.metadata.name.startsWith('w')","message":"must start with w"}]}`),
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								XValidations: apiextensionsv1.ValidationRules{
+									{Rule: "self.metadata.name.startsWith('w')", Message: "must start with w"},
+								},
+							}},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+				},
+			},
+		},
+		"defaults": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-name",
+					UID:         types.UID("my-uuid"),
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "my-cluster"},
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object","default":{},"properties":{"replicas":{"type":"integer","default":1}}}}}`),
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type:    "object",
+										Default: &apiextensionsv1.JSON{Raw: []byte(`{}`)},
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"replicas": {Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`1`)}},
+										},
+									},
+								},
+							}},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+				},
+			},
+		},
+		"webhook conversion": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-name",
+					UID:         types.UID("my-uuid"),
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "my-cluster"},
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{Name: "v1", Served: true, Storage: true, Schema: runtime.RawExtension{Raw: []byte(`{"type":"object"}`)}},
+					},
+					Conversion: &apisv1alpha1.CustomResourceConversion{
+						Strategy: apisv1alpha1.WebhookConverter,
+						Webhook: &apisv1alpha1.WebhookConversion{
+							ClientConfig: apisv1alpha1.WebhookClientConfig{
+								URL:      "https://example.com/convert",
+								CABundle: []byte("ca"),
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:         "v1",
+							Served:       true,
+							Storage:      true,
+							Schema:       &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"}},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook: &apiextensionsv1.WebhookConversion{
+							ClientConfig: &apiextensionsv1.WebhookClientConfig{
+								URL:      pointer.StringPtr("https://example.com/convert"),
+								CABundle: []byte("ca"),
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
+				},
+			},
+		},
 		"error when schema is invalid": {
 			schema: &apisv1alpha1.APIResourceSchema{
 				Spec: apisv1alpha1.APIResourceSchemaSpec{
@@ -904,6 +1542,14 @@ func (b *bindingBuilder) WithBoundResources(boundResources ...apisv1alpha1.Bound
 	return b
 }
 
+func (b *bindingBuilder) WithResourceOverride(group, resource, alias string) *bindingBuilder {
+	b.Spec.ResourceOverrides = append(b.Spec.ResourceOverrides, apisv1alpha1.ResourceOverride{
+		GroupResource: apisv1alpha1.GroupResource{Group: group, Resource: resource},
+		Alias:         alias,
+	})
+	return b
+}
+
 type boundAPIResourceBuilder struct {
 	apisv1alpha1.BoundAPIResource
 }
@@ -926,3 +1572,21 @@ func (b *boundAPIResourceBuilder) WithStorageVersions(v ...string) *boundAPIReso
 	b.StorageVersions = v
 	return b
 }
+
+func TestSchemaHasValidationRules(t *testing.T) {
+	newSchema := func(raws ...string) *apisv1alpha1.APIResourceSchema {
+		schema := &apisv1alpha1.APIResourceSchema{}
+		for _, raw := range raws {
+			schema.Spec.Versions = append(schema.Spec.Versions, apisv1alpha1.APIResourceVersion{Schema: runtime.RawExtension{Raw: []byte(raw)}})
+		}
+		return schema
+	}
+
+	require.False(t, schemaHasValidationRules(newSchema(`{"type":"object"}`)))
+	require.True(t, schemaHasValidationRules(newSchema(`{"type":"object","x-kubernetes-validations":[{"rule":"true"}]}`)))
+	require.True(t, schemaHasValidationRules(newSchema(
+		`{"type":"object"}`,
+		`{"type":"object","properties":{"spec":{"type":"array","items":{"type":"string","x-kubernetes-validations":[{"rule":"self != ''"}]}}}}`,
+	)))
+	require.False(t, schemaHasValidationRules(newSchema(`invalid json`)))
+}
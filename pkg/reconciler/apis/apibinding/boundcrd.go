@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// RotatedBoundCRDName returns the name of the bound CRD of the APIResourceSchema with the given UID
+// serving the objects stored under the given identity, when the bound CRD named by the schema UID
+// serves another identity. This is the case while and after the identity of an APIExport is rotated.
+func RotatedBoundCRDName(schemaUID, identityHash string) string {
+	return schemaUID + "." + identityHash
+}
+
+// GetBoundCRD returns the name of the bound CRD of the APIResourceSchema with the given UID serving
+// the objects stored under the given identity, and the CRD if it exists. getCRD gets a CRD of the
+// system:bound-crds logical cluster by name. The name is returned along with NotFound errors, i.e.
+// it is the name the bound CRD is to be created with.
+func GetBoundCRD(getCRD func(name string) (*apiextensionsv1.CustomResourceDefinition, error), schemaUID, identityHash string) (string, *apiextensionsv1.CustomResourceDefinition, error) {
+	crd, err := getCRD(schemaUID)
+	if err != nil {
+		return schemaUID, nil, err
+	}
+
+	// bound CRDs without the annotation were created before any rotation, and serve the identity of all their APIBindings.
+	if identity, found := crd.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey]; !found || identityHash == "" || identity == identityHash {
+		return schemaUID, crd, nil
+	}
+
+	name := RotatedBoundCRDName(schemaUID, identityHash)
+	crd, err = getCRD(name)
+	if err != nil {
+		return name, nil, err
+	}
+	return name, crd, nil
+}
@@ -44,6 +44,7 @@ type conflictChecker struct {
 
 	boundCRDs    []*apiextensionsv1.CustomResourceDefinition
 	crdToBinding map[string]*apisv1alpha1.APIBinding
+	crdToAlias   map[string]string
 }
 
 func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBinding) error {
@@ -55,6 +56,7 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 	}
 
 	ncc.crdToBinding = make(map[string]*apisv1alpha1.APIBinding)
+	ncc.crdToAlias = make(map[string]string)
 
 	for _, apiBinding := range apiBindings {
 		if apiBinding.Name == apiBindingToExclude.Name {
@@ -75,9 +77,9 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 			return err
 		}
 
-		boundSchemaUIDs := sets.NewString()
+		boundSchemaUIDs := map[string]string{}
 		for _, boundResource := range apiBinding.Status.BoundResources {
-			boundSchemaUIDs.Insert(boundResource.Schema.UID)
+			boundSchemaUIDs[boundResource.Schema.UID] = boundResource.Alias
 		}
 
 		for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
@@ -86,7 +88,8 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 				return err
 			}
 
-			if !boundSchemaUIDs.Has(string(schema.UID)) {
+			alias, bound := boundSchemaUIDs[string(schema.UID)]
+			if !bound {
 				continue
 			}
 
@@ -97,6 +100,7 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 
 			ncc.boundCRDs = append(ncc.boundCRDs, crd)
 			ncc.crdToBinding[crd.Name] = apiBinding
+			ncc.crdToAlias[crd.Name] = alias
 		}
 	}
 
@@ -109,8 +113,9 @@ func (ncc *conflictChecker) checkForConflicts(schema *apisv1alpha1.APIResourceSc
 		return fmt.Errorf("error checking for naming conflicts for APIBinding %s|%s: error getting CRDs: %w", logicalcluster.From(apiBinding), apiBinding.Name, err)
 	}
 
+	alias := resourceAlias(apiBinding, schema.Spec.Group, schema.Spec.Names.Plural)
 	for _, boundCRD := range ncc.boundCRDs {
-		if foundConflict, details := namesConflict(boundCRD, schema); foundConflict {
+		if foundConflict, details := namesConflict(boundCRD, ncc.crdToAlias[boundCRD.Name], schema, alias); foundConflict {
 			conflict := ncc.crdToBinding[boundCRD.Name]
 			path := logicalcluster.NewPath(conflict.Spec.Reference.Export.Path)
 			var boundTo string
@@ -132,35 +137,53 @@ func (ncc *conflictChecker) gvrConflict(schema *apisv1alpha1.APIResourceSchema,
 	if err != nil {
 		return err
 	}
+	resource := schema.Spec.Names.Plural
+	if alias := resourceAlias(apiBinding, schema.Spec.Group, resource); alias != "" {
+		resource = alias
+	}
 	for _, bindingClusterCRD := range bindingClusterCRDs {
-		if bindingClusterCRD.Spec.Group == schema.Spec.Group && bindingClusterCRD.Spec.Names.Plural == schema.Spec.Names.Plural {
+		if bindingClusterCRD.Spec.Group == schema.Spec.Group && bindingClusterCRD.Spec.Names.Plural == resource {
 			return fmt.Errorf("cannot create CustomResourceDefinition with %q group and %q resource because it overlaps with %q CustomResourceDefinition in %q logical cluster",
-				schema.Spec.Group, schema.Spec.Names.Plural, bindingClusterCRD.Name, bindingClusterName)
+				schema.Spec.Group, resource, bindingClusterCRD.Name, bindingClusterName)
 		}
 	}
 	return nil
 }
 
-func namesConflict(existing *apiextensionsv1.CustomResourceDefinition, incoming *apisv1alpha1.APIResourceSchema) (bool, string) {
+// namesConflict checks the names the existing bound CRD and the incoming schema are served with for
+// conflicts. Resources served under an alias are served without singular and short names. The objects
+// of aliased resources keep their kind though, hence kinds conflict with and without aliases, as
+// clients resolving resources by kind could not tell both resources apart.
+func namesConflict(existing *apiextensionsv1.CustomResourceDefinition, existingAlias string, incoming *apisv1alpha1.APIResourceSchema, incomingAlias string) (bool, string) {
 	if existing.Spec.Group != incoming.Spec.Group {
 		return false, ""
 	}
 	existingNames := sets.NewString()
-	existingNames.Insert(existing.Status.AcceptedNames.Plural)
-	existingNames.Insert(existing.Status.AcceptedNames.Singular)
-	existingNames.Insert(existing.Status.AcceptedNames.ShortNames...)
-
-	if existingNames.Has(incoming.Spec.Names.Plural) {
-		return true, fmt.Sprintf("spec.names.plural=%v is forbidden", incoming.Spec.Names.Plural)
+	if existingAlias != "" {
+		existingNames.Insert(existingAlias)
+	} else {
+		existingNames.Insert(existing.Status.AcceptedNames.Plural)
+		existingNames.Insert(existing.Status.AcceptedNames.Singular)
+		existingNames.Insert(existing.Status.AcceptedNames.ShortNames...)
 	}
 
-	if existingNames.Has(incoming.Spec.Names.Singular) {
-		return true, fmt.Sprintf("spec.names.singular=%v is forbidden", incoming.Spec.Names.Singular)
-	}
+	if incomingAlias != "" {
+		if existingNames.Has(incomingAlias) {
+			return true, fmt.Sprintf("spec.resourceOverrides alias=%v is forbidden", incomingAlias)
+		}
+	} else {
+		if existingNames.Has(incoming.Spec.Names.Plural) {
+			return true, fmt.Sprintf("spec.names.plural=%v is forbidden", incoming.Spec.Names.Plural)
+		}
+
+		if existingNames.Has(incoming.Spec.Names.Singular) {
+			return true, fmt.Sprintf("spec.names.singular=%v is forbidden", incoming.Spec.Names.Singular)
+		}
 
-	for _, shortName := range incoming.Spec.Names.ShortNames {
-		if existingNames.Has(shortName) {
-			return true, fmt.Sprintf("spec.names.shortNames=%v is forbidden", incoming.Spec.Names.ShortNames)
+		for _, shortName := range incoming.Spec.Names.ShortNames {
+			if existingNames.Has(shortName) {
+				return true, fmt.Sprintf("spec.names.shortNames=%v is forbidden", incoming.Spec.Names.ShortNames)
+			}
 		}
 	}
 
@@ -156,10 +156,12 @@ func TestNamesConflict(t *testing.T) {
 	}
 
 	scenarios := []struct {
-		name         string
-		existingCrd  *apiextensionsv1.CustomResourceDefinition
-		schema       *apisv1alpha1.APIResourceSchema
-		wantConflict bool
+		name          string
+		existingCrd   *apiextensionsv1.CustomResourceDefinition
+		existingAlias string
+		schema        *apisv1alpha1.APIResourceSchema
+		incomingAlias string
+		wantConflict  bool
 	}{
 		{
 			name:         "same group, plural conflict",
@@ -225,11 +227,66 @@ func TestNamesConflict(t *testing.T) {
 			existingCrd: existingCRDFn("acme.dev", "", "", "", "", "e"),
 			schema:      newSchemaFn("new.acme.dev", "", "", "", "", "e"),
 		},
+
+		{
+			name:          "same group, incoming alias, no conflict",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			schema:        newSchemaFn("acme.dev", "widgets", "widget", "w", "AcmeWidget", "AcmeWidgetList"),
+			incomingAlias: "other-widgets",
+		},
+
+		{
+			name:          "same group, existing alias, no conflict",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "AcmeWidget", "AcmeWidgetList"),
+			existingAlias: "other-widgets",
+			schema:        newSchemaFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+		},
+
+		{
+			name:          "same group, incoming alias, kind conflict",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			schema:        newSchemaFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			incomingAlias: "other-widgets",
+			wantConflict:  true,
+		},
+
+		{
+			name:          "same group, existing alias, listKind conflict",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "AcmeWidget", "WidgetList"),
+			existingAlias: "other-widgets",
+			schema:        newSchemaFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			wantConflict:  true,
+		},
+
+		{
+			name:          "same group, incoming alias conflicts with existing name",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			schema:        newSchemaFn("acme.dev", "gadgets", "gadget", "g", "Gadget", "GadgetList"),
+			incomingAlias: "w",
+			wantConflict:  true,
+		},
+
+		{
+			name:          "same group, incoming alias conflicts with existing alias",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			existingAlias: "other-widgets",
+			schema:        newSchemaFn("acme.dev", "widgets", "widget", "w", "AcmeWidget", "AcmeWidgetList"),
+			incomingAlias: "other-widgets",
+			wantConflict:  true,
+		},
+
+		{
+			name:          "same group, existing alias conflicts with incoming name",
+			existingCrd:   existingCRDFn("acme.dev", "widgets", "widget", "w", "Widget", "WidgetList"),
+			existingAlias: "gadgets",
+			schema:        newSchemaFn("acme.dev", "gadgets", "gadget", "g", "Gadget", "GadgetList"),
+			wantConflict:  true,
+		},
 	}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
-			actualConflict, _ := namesConflict(scenario.existingCrd, scenario.schema)
+			actualConflict, _ := namesConflict(scenario.existingCrd, scenario.existingAlias, scenario.schema, scenario.incomingAlias)
 			if actualConflict != scenario.wantConflict {
 				t.Fatal("didn't expect to hit name conflict")
 			}
@@ -267,6 +324,23 @@ func TestGVRConflict(t *testing.T) {
 			schema:  schemaFor(t, createCRD("", "crd1", "acmeGR", "acmeRS")),
 			wantErr: true,
 		},
+		{
+			name: "no conflict when the resource is served under an alias",
+			initialCRDs: []*apiextensionsv1.CustomResourceDefinition{
+				createCRD("root:acme", "crd1", "acmeGR", "acmeRS"),
+			},
+			binding: new(bindingBuilder).WithClusterName("root:acme").WithName("newBinding").WithResourceOverride("acmeGR", "acmeRS", "other-acmers").Build(),
+			schema:  schemaFor(t, createCRD("", "crd1", "acmeGR", "acmeRS")),
+		},
+		{
+			name: "alias conflicting with CRD fails",
+			initialCRDs: []*apiextensionsv1.CustomResourceDefinition{
+				createCRD("root:acme", "crd1", "acmeGR", "other-acmers"),
+			},
+			binding: new(bindingBuilder).WithClusterName("root:acme").WithName("newBinding").WithResourceOverride("acmeGR", "acmeRS", "other-acmers").Build(),
+			schema:  schemaFor(t, createCRD("", "crd1", "acmeGR", "acmeRS")),
+			wantErr: true,
+		},
 	}
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"time"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// maintenanceWindowAt returns whether one of the maintenance windows is open at the given time,
+// and if not, when the next one opens. next is zero if no window ever opens.
+func maintenanceWindowAt(windows []apisv1alpha1.MaintenanceWindow, now time.Time) (open bool, next time.Time) {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, window := range windows {
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			// validated by the schema
+			continue
+		}
+		offset := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+
+		// windows are at most a week long, i.e. a window open now started within the last week.
+		for day := -7; day <= 7; day++ {
+			windowStart := midnight.AddDate(0, 0, day).Add(offset)
+			if !startsOn(window, windowStart.Weekday()) {
+				continue
+			}
+			if !windowStart.After(now) && now.Before(windowStart.Add(window.Duration.Duration)) {
+				return true, time.Time{}
+			}
+			if windowStart.After(now) && (next.IsZero() || windowStart.Before(next)) {
+				next = windowStart
+			}
+		}
+	}
+	return false, next
+}
+
+func startsOn(window apisv1alpha1.MaintenanceWindow, weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestMaintenanceWindowAt(t *testing.T) {
+	window := func(start string, duration time.Duration, days ...apisv1alpha1.MaintenanceWindowDay) apisv1alpha1.MaintenanceWindow {
+		return apisv1alpha1.MaintenanceWindow{Days: days, Start: start, Duration: metav1.Duration{Duration: duration}}
+	}
+	// a Monday
+	monday := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		windows  []apisv1alpha1.MaintenanceWindow
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		"no windows": {
+			now: monday,
+		},
+		"daily, open": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("11:30", time.Hour)},
+			now:      monday,
+			wantOpen: true,
+		},
+		"daily, closed": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("10:00", time.Hour)},
+			now:      monday,
+			wantNext: time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC),
+		},
+		"daily, later today": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("22:00", time.Hour)},
+			now:      monday,
+			wantNext: time.Date(2023, 5, 1, 22, 0, 0, 0, time.UTC),
+		},
+		"end is exclusive": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("11:00", time.Hour)},
+			now:      monday,
+			wantNext: time.Date(2023, 5, 2, 11, 0, 0, 0, time.UTC),
+		},
+		"weekly, closed": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("02:00", 4*time.Hour, "Saturday", "Sunday")},
+			now:      monday,
+			wantNext: time.Date(2023, 5, 6, 2, 0, 0, 0, time.UTC),
+		},
+		"window started on previous day": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("22:00", 16*time.Hour, "Sunday")},
+			now:      monday,
+			wantOpen: true,
+		},
+		"week long window": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("13:00", 7*24*time.Hour, "Monday")},
+			now:      monday,
+			wantOpen: true,
+		},
+		"earliest of multiple windows": {
+			windows: []apisv1alpha1.MaintenanceWindow{
+				window("02:00", time.Hour, "Friday"),
+				window("03:00", time.Hour, "Wednesday"),
+			},
+			now:      monday,
+			wantNext: time.Date(2023, 5, 3, 3, 0, 0, 0, time.UTC),
+		},
+		"other time zone": {
+			windows:  []apisv1alpha1.MaintenanceWindow{window("11:30", time.Hour)},
+			now:      monday.In(time.FixedZone("UTC+2", 2*60*60)),
+			wantOpen: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			open, next := maintenanceWindowAt(tc.windows, tc.now)
+			require.Equal(t, tc.wantOpen, open)
+			require.Equal(t, tc.wantNext, next)
+		})
+	}
+}
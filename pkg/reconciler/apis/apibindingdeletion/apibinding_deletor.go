@@ -55,7 +55,7 @@ func (c *Controller) deleteAllCRs(ctx context.Context, apibinding *apisv1alpha1.
 		for _, version := range resource.StorageVersions {
 			gvr := schema.GroupVersionResource{
 				Group:    resource.Group,
-				Resource: resource.Resource,
+				Resource: resource.ServedResource(),
 				Version:  version,
 			}
 
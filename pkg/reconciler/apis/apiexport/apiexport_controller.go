@@ -32,15 +32,19 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 	apisv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/apis/v1alpha1"
@@ -62,8 +66,10 @@ func NewController(
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
 	secretInformer kcpcorev1informers.SecretClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	identityEscrow *identityescrow.Escrow,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &controller{
 		queue: queue,
@@ -118,9 +124,46 @@ func NewController(
 			return globalShardInformer.Lister().List(labels.Everything())
 		},
 
+		listAPIBindingsForAPIExport: func(apiExport *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+			// binding keys by full path
+			keys := sets.NewString()
+			if path := logicalcluster.NewPath(apiExport.Annotations[core.LogicalClusterPathAnnotationKey]); !path.Empty() {
+				pathKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, path.Join(apiExport.Name).String())
+				if err != nil {
+					return nil, err
+				}
+				keys.Insert(pathKeys...)
+			}
+
+			clusterKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, logicalcluster.From(apiExport).Path().Join(apiExport.Name).String())
+			if err != nil {
+				return nil, err
+			}
+			keys.Insert(clusterKeys...)
+
+			bindings := make([]*apisv1alpha1.APIBinding, 0, keys.Len())
+			for _, key := range keys.List() {
+				binding, exists, err := apiBindingInformer.Informer().GetIndexer().GetByKey(key)
+				if err != nil {
+					return nil, err
+				} else if !exists {
+					continue
+				}
+				bindings = append(bindings, binding.(*apisv1alpha1.APIBinding))
+			}
+			return bindings, nil
+		},
+		listAPIExportsForIdentity: func(identityHash string) ([]*apisv1alpha1.APIExport, error) {
+			return indexers.ByIndex[*apisv1alpha1.APIExport](apiExportInformer.Informer().GetIndexer(), indexers.APIExportByIdentity, identityHash)
+		},
+
 		commit: committer.NewCommitter[*APIExport, Patcher, *APIExportSpec, *APIExportStatus](kcpClusterClient.ApisV1alpha1().APIExports()),
 	}
 
+	if identityEscrow != nil {
+		c.escrowIdentity = identityEscrow.Put
+	}
+
 	indexers.AddIfNotPresentOrDie(
 		apiExportInformer.Informer().GetIndexer(),
 		cache.Indexers{
@@ -153,6 +196,27 @@ func NewController(
 		},
 	})
 
+	indexers.AddIfNotPresentOrDie(
+		apiBindingInformer.Informer().GetIndexer(),
+		cache.Indexers{
+			indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
+		},
+	)
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueAPIBinding(newObj.(*apisv1alpha1.APIBinding))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if binding, ok := obj.(*apisv1alpha1.APIBinding); ok {
+				c.enqueueAPIBinding(binding)
+			}
+		},
+	})
+
 	globalShardInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
@@ -198,6 +262,13 @@ type controller struct {
 
 	listShards func() ([]*corev1alpha1.Shard, error)
 
+	listAPIBindingsForAPIExport func(apiExport *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error)
+	listAPIExportsForIdentity   func(identityHash string) ([]*apisv1alpha1.APIExport, error)
+
+	// escrowIdentity stores an encrypted copy of an identity outside of kcp. It is nil if the
+	// identity escrow is disabled.
+	escrowIdentity func(ctx context.Context, record *identityescrow.Record) error
+
 	commit CommitFunc
 }
 
@@ -253,6 +324,36 @@ func (c *controller) enqueueSecret(secret *corev1.Secret) {
 	}
 }
 
+// enqueueAPIBinding enqueues the APIExports the APIBinding is bound to while their identity is
+// rotated, to update the progress of the rotation.
+func (c *controller) enqueueAPIBinding(binding *apisv1alpha1.APIBinding) {
+	identities := sets.NewString()
+	for _, r := range binding.Status.BoundResources {
+		identities.Insert(r.Schema.IdentityHash)
+	}
+
+	logger := logging.WithObject(logging.WithReconciler(klog.Background(), ControllerName), binding)
+	for _, identity := range identities.List() {
+		apiExports, err := c.listAPIExportsForIdentity(identity)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		for _, apiExport := range apiExports {
+			if apiExport.Status.NextIdentityHash == "" {
+				continue
+			}
+			key, err := kcpcache.MetaClusterNamespaceKeyFunc(apiExport)
+			if err != nil {
+				runtime.HandleError(err)
+				continue
+			}
+			logging.WithQueueKey(logger, key).V(4).Info("queueing APIExport via APIBinding")
+			c.queue.Add(key)
+		}
+	}
+}
+
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
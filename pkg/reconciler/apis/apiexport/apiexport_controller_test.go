@@ -30,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
@@ -47,6 +48,8 @@ func TestReconcile(t *testing.T) {
 		apiExportHasSomeOtherHash            bool
 		hasPreexistingVerifyFailure          bool
 		listShardsError                      error
+		escrowEnabled                        bool
+		escrowError                          error
 
 		apiBindings []interface{}
 
@@ -60,6 +63,8 @@ func TestReconcile(t *testing.T) {
 		wantIdentityValid             bool
 		wantVirtualWorkspaceURLsError bool
 		wantVirtualWorkspaceURLsReady bool
+		wantEscrowed                  *identityescrow.Record
+		wantEscrowFailure             bool
 	}{
 		"create secret when ref is nil and secret doesn't exist": {
 			secretExists: false,
@@ -135,6 +140,44 @@ func TestReconcile(t *testing.T) {
 			},
 			wantVirtualWorkspaceURLsReady: true,
 		},
+		"identity escrowed when valid": {
+			secretRefSet:  true,
+			secretExists:  true,
+			escrowEnabled: true,
+
+			wantStatusHashSet: true,
+			wantIdentityValid: true,
+			wantEscrowed: &identityescrow.Record{
+				Cluster:         "root:org:ws",
+				APIExport:       "my-export",
+				SecretNamespace: "somens",
+				SecretName:      "somename",
+				IdentityHash:    fmt.Sprintf("%x", sha256.Sum256([]byte("abc"))),
+				Key:             []byte("abc"),
+			},
+			wantVirtualWorkspaceURLsReady: true,
+		},
+		"identity not escrowed when invalid": {
+			secretRefSet:                         true,
+			secretExists:                         true,
+			apiExportHasExpectedHash:             true,
+			secretHashDoesntMatchAPIExportStatus: true,
+			escrowEnabled:                        true,
+
+			wantVerifyFailure: true,
+		},
+		"error escrowing identity": {
+			secretRefSet:  true,
+			secretExists:  true,
+			escrowEnabled: true,
+			escrowError:   errors.New("foo"),
+
+			wantStatusHashSet:             true,
+			wantIdentityValid:             true,
+			wantEscrowFailure:             true,
+			wantError:                     true,
+			wantVirtualWorkspaceURLsReady: true,
+		},
 	}
 
 	for name, tc := range tests {
@@ -208,6 +251,14 @@ func TestReconcile(t *testing.T) {
 				},
 			}
 
+			var escrowed *identityescrow.Record
+			if tc.escrowEnabled {
+				c.escrowIdentity = func(ctx context.Context, record *identityescrow.Record) error {
+					escrowed = record
+					return tc.escrowError
+				}
+			}
+
 			apiExport := &apisv1alpha1.APIExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -302,6 +353,172 @@ func TestReconcile(t *testing.T) {
 			if tc.wantVirtualWorkspaceURLsReady {
 				requireConditionMatches(t, apiExport, conditions.TrueCondition(apisv1alpha1.APIExportVirtualWorkspaceURLsReady))
 			}
+
+			if tc.wantEscrowed != nil {
+				require.Equal(t, tc.wantEscrowed, escrowed)
+				requireConditionMatches(t, apiExport, conditions.TrueCondition(apisv1alpha1.APIExportIdentityEscrowed))
+			} else if !tc.wantEscrowFailure {
+				require.Nil(t, escrowed)
+			}
+
+			if tc.wantEscrowFailure {
+				requireConditionMatches(t, apiExport,
+					conditions.FalseCondition(
+						apisv1alpha1.APIExportIdentityEscrowed,
+						apisv1alpha1.IdentityEscrowFailedReason,
+						conditionsv1alpha1.ConditionSeverityWarning,
+						"",
+					),
+				)
+			}
+		})
+	}
+}
+
+func TestReconcileIdentityRotation(t *testing.T) {
+	hash := func(key string) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(key))) }
+	currentHash, nextHash := hash("current"), hash("next")
+	boundWith := func(identities ...string) *apisv1alpha1.APIBinding {
+		binding := &apisv1alpha1.APIBinding{}
+		for _, identity := range identities {
+			binding.Status.BoundResources = append(binding.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+				Schema: apisv1alpha1.BoundAPIResourceSchema{IdentityHash: identity},
+			})
+		}
+		return binding
+	}
+
+	tests := map[string]struct {
+		nextKey          string
+		nextSecretRef    bool
+		nextIdentityHash string
+		apiBindings      []*apisv1alpha1.APIBinding
+
+		wantReplaced         bool
+		wantIdentityHash     string
+		wantNextIdentityHash string
+		wantCondition        *conditionsv1alpha1.Condition
+	}{
+		"next identity recorded": {
+			nextKey:       "next",
+			nextSecretRef: true,
+			apiBindings:   []*apisv1alpha1.APIBinding{boundWith(currentHash)},
+
+			wantIdentityHash:     currentHash,
+			wantNextIdentityHash: nextHash,
+			wantCondition:        conditions.FalseCondition(apisv1alpha1.APIExportIdentityRotated, apisv1alpha1.IdentityRotationInProgressReason, conditionsv1alpha1.ConditionSeverityInfo, "Migrated 0 of 1 APIBindings"),
+		},
+		"next identity recorded without APIBindings": {
+			nextKey:       "next",
+			nextSecretRef: true,
+
+			wantIdentityHash:     currentHash,
+			wantNextIdentityHash: nextHash,
+			wantCondition:        conditions.FalseCondition(apisv1alpha1.APIExportIdentityRotated, apisv1alpha1.IdentityRotationInProgressReason, conditionsv1alpha1.ConditionSeverityInfo, "Migrated 0 of 0 APIBindings"),
+		},
+		"migration in progress": {
+			nextKey:          "next",
+			nextSecretRef:    true,
+			nextIdentityHash: nextHash,
+			apiBindings:      []*apisv1alpha1.APIBinding{boundWith(nextHash, nextHash), boundWith(currentHash, nextHash), boundWith()},
+
+			wantIdentityHash:     currentHash,
+			wantNextIdentityHash: nextHash,
+			wantCondition:        conditions.FalseCondition(apisv1alpha1.APIExportIdentityRotated, apisv1alpha1.IdentityRotationInProgressReason, conditionsv1alpha1.ConditionSeverityInfo, "Migrated 2 of 3 APIBindings"),
+		},
+		"all migrated, secret replaced": {
+			nextKey:          "next",
+			nextSecretRef:    true,
+			nextIdentityHash: nextHash,
+			apiBindings:      []*apisv1alpha1.APIBinding{boundWith(nextHash)},
+
+			wantReplaced:         true,
+			wantIdentityHash:     currentHash,
+			wantNextIdentityHash: nextHash,
+		},
+		"identity hash replaced": {
+			nextKey:          "next",
+			nextIdentityHash: nextHash,
+
+			wantIdentityHash: nextHash,
+			wantCondition:    conditions.TrueCondition(apisv1alpha1.APIExportIdentityRotated),
+		},
+		"next identity equal to the current one": {
+			nextKey:       "current",
+			nextSecretRef: true,
+
+			wantIdentityHash: currentHash,
+			wantCondition:    conditions.FalseCondition(apisv1alpha1.APIExportIdentityRotated, apisv1alpha1.IdentityRotationFailedReason, conditionsv1alpha1.ConditionSeverityError, "must differ"),
+		},
+		"next identity changed during the rotation": {
+			nextKey:          "other",
+			nextSecretRef:    true,
+			nextIdentityHash: nextHash,
+
+			wantIdentityHash:     currentHash,
+			wantNextIdentityHash: nextHash,
+			wantCondition:        conditions.FalseCondition(apisv1alpha1.APIExportIdentityRotated, apisv1alpha1.IdentityRotationFailedReason, conditionsv1alpha1.ConditionSeverityError, "hash mismatch"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				getSecret: func(ctx context.Context, clusterName logicalcluster.Name, ns, name string) (*corev1.Secret, error) {
+					key := "current"
+					if name == "next" {
+						key = tc.nextKey
+					}
+					return &corev1.Secret{Data: map[string][]byte{apisv1alpha1.SecretKeyAPIExportIdentity: []byte(key)}}, nil
+				},
+				listShards: func() ([]*corev1alpha1.Shard, error) {
+					return nil, nil
+				},
+				listAPIBindingsForAPIExport: func(apiExport *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+					return tc.apiBindings, nil
+				},
+			}
+
+			apiExport := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:org:ws",
+					},
+					Name: "my-export",
+				},
+				Spec: apisv1alpha1.APIExportSpec{
+					Identity: &apisv1alpha1.Identity{
+						SecretRef: &corev1.SecretReference{Namespace: "somens", Name: "current"},
+					},
+				},
+				Status: apisv1alpha1.APIExportStatus{
+					IdentityHash:     currentHash,
+					NextIdentityHash: tc.nextIdentityHash,
+				},
+			}
+			if tc.nextSecretRef {
+				apiExport.Spec.Identity.NextSecretRef = &corev1.SecretReference{Namespace: "somens", Name: "next"}
+			} else {
+				// the rotation was completed in spec
+				apiExport.Spec.Identity.SecretRef.Name = "next"
+			}
+
+			require.NoError(t, c.reconcile(context.Background(), apiExport))
+
+			if tc.wantReplaced {
+				require.Equal(t, "next", apiExport.Spec.Identity.SecretRef.Name)
+				require.Nil(t, apiExport.Spec.Identity.NextSecretRef)
+			} else if tc.nextSecretRef {
+				require.Equal(t, "current", apiExport.Spec.Identity.SecretRef.Name)
+				require.NotNil(t, apiExport.Spec.Identity.NextSecretRef)
+			}
+			require.Equal(t, tc.wantIdentityHash, apiExport.Status.IdentityHash)
+			require.Equal(t, tc.wantNextIdentityHash, apiExport.Status.NextIdentityHash)
+			if tc.wantCondition != nil {
+				requireConditionMatches(t, apiExport, tc.wantCondition)
+			} else {
+				require.Nil(t, conditions.Get(apiExport, apisv1alpha1.APIExportIdentityRotated))
+			}
 		})
 	}
 }
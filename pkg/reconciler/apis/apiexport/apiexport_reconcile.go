@@ -31,6 +31,7 @@ import (
 	"k8s.io/klog/v2"
 
 	virtualworkspacesoptions "github.com/kcp-dev/kcp/cmd/virtual-workspaces/options"
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	apiexportbuilder "github.com/kcp-dev/kcp/pkg/virtual/apiexport/builder"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
@@ -95,6 +96,28 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 		)
 	}
 
+	// Rotate the identity to nextSecretRef, if requested
+	if identity.NextSecretRef != nil && conditions.IsTrue(apiExport, apisv1alpha1.APIExportIdentityValid) {
+		replaced, err := c.rotateIdentity(ctx, clusterName, apiExport)
+		if err != nil {
+			conditions.MarkFalse(
+				apiExport,
+				apisv1alpha1.APIExportIdentityRotated,
+				apisv1alpha1.IdentityRotationFailedReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				err.Error(),
+			)
+		} else if replaced {
+			// Record the spec change. The next iteration replaces the identity hash in status.
+			return nil
+		}
+	}
+
+	var escrowErr error
+	if c.escrowIdentity != nil && conditions.IsTrue(apiExport, apisv1alpha1.APIExportIdentityValid) {
+		escrowErr = c.escrowIdentitySecret(ctx, clusterName, apiExport)
+	}
+
 	// TODO(sttts): reactivate this with multi-shard support eventually
 	/*
 		// check if any APIBindings are bound to this APIExport. If so, add a virtualworkspaceURL
@@ -119,7 +142,7 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 		)
 	}
 
-	return nil
+	return escrowErr
 }
 
 func (c *controller) ensureSecretNamespaceExists(ctx context.Context, clusterName logicalcluster.Name) {
@@ -168,6 +191,13 @@ func (c *controller) updateOrVerifyIdentitySecretHash(ctx context.Context, clust
 		apiExport.Status.IdentityHash = hash
 	}
 
+	if apiExport.Status.IdentityHash != hash && hash == apiExport.Status.NextIdentityHash && apiExport.Spec.Identity.NextSecretRef == nil {
+		// The rotation was completed by replacing the secretRef with the nextSecretRef.
+		apiExport.Status.IdentityHash = hash
+		apiExport.Status.NextIdentityHash = ""
+		conditions.MarkTrue(apiExport, apisv1alpha1.APIExportIdentityRotated)
+	}
+
 	if apiExport.Status.IdentityHash != hash {
 		return fmt.Errorf("hash mismatch: identity secret hash %q must match status.identityHash %q", hash, apiExport.Status.IdentityHash)
 	}
@@ -177,6 +207,110 @@ func (c *controller) updateOrVerifyIdentitySecretHash(ctx context.Context, clust
 	return nil
 }
 
+// rotateIdentity verifies the identity referenced by nextSecretRef, and records its hash in status. The
+// objects of the APIBindings are moved to the next identity by the identity rotation controller. When
+// all APIBindings are bound with the next identity, it replaces secretRef with nextSecretRef, and
+// returns true. The identity hash in status is replaced in the next iteration, when the spec change
+// is recorded.
+func (c *controller) rotateIdentity(ctx context.Context, clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (bool, error) {
+	nextSecretRef := apiExport.Spec.Identity.NextSecretRef
+	secret, err := c.getSecret(ctx, clusterName, nextSecretRef.Namespace, nextSecretRef.Name)
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := IdentityHash(secret)
+	if err != nil {
+		return false, err
+	}
+
+	if hash == apiExport.Status.IdentityHash {
+		return false, fmt.Errorf("next identity secret hash %q must differ from status.identityHash", hash)
+	}
+
+	recorded := apiExport.Status.NextIdentityHash != ""
+	if !recorded {
+		apiExport.Status.NextIdentityHash = hash
+	}
+
+	if apiExport.Status.NextIdentityHash != hash {
+		return false, fmt.Errorf("hash mismatch: next identity secret hash %q must match status.nextIdentityHash %q", hash, apiExport.Status.NextIdentityHash)
+	}
+
+	bindings, err := c.listAPIBindingsForAPIExport(apiExport)
+	if err != nil {
+		return false, err
+	}
+
+	migrated := 0
+	for _, binding := range bindings {
+		if boundWithIdentity(binding, hash) {
+			migrated++
+		}
+	}
+
+	if !recorded || migrated < len(bindings) {
+		conditions.MarkFalse(
+			apiExport,
+			apisv1alpha1.APIExportIdentityRotated,
+			apisv1alpha1.IdentityRotationInProgressReason,
+			conditionsv1alpha1.ConditionSeverityInfo,
+			"Migrated %d of %d APIBindings",
+			migrated,
+			len(bindings),
+		)
+		return false, nil
+	}
+
+	klog.FromContext(ctx).V(2).Info("replacing identity", "identityHash", hash)
+	apiExport.Spec.Identity.SecretRef = nextSecretRef
+	apiExport.Spec.Identity.NextSecretRef = nil
+
+	return true, nil
+}
+
+// boundWithIdentity returns true if all bound resources of the APIBinding are bound with the given identity.
+func boundWithIdentity(binding *apisv1alpha1.APIBinding, identityHash string) bool {
+	for _, r := range binding.Status.BoundResources {
+		if r.Schema.IdentityHash != identityHash {
+			return false
+		}
+	}
+	return true
+}
+
+// escrowIdentitySecret stores an encrypted copy of the verified identity of the APIExport in the identity
+// escrow, from where it can be restored if the identity secret is deleted.
+func (c *controller) escrowIdentitySecret(ctx context.Context, clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) error {
+	secretRef := apiExport.Spec.Identity.SecretRef
+	secret, err := c.getSecret(ctx, clusterName, secretRef.Namespace, secretRef.Name)
+	if err == nil {
+		err = c.escrowIdentity(ctx, &identityescrow.Record{
+			Cluster:         clusterName,
+			APIExport:       apiExport.Name,
+			SecretNamespace: secretRef.Namespace,
+			SecretName:      secretRef.Name,
+			IdentityHash:    apiExport.Status.IdentityHash,
+			Key:             secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity],
+		})
+	}
+	if err != nil {
+		conditions.MarkFalse(
+			apiExport,
+			apisv1alpha1.APIExportIdentityEscrowed,
+			apisv1alpha1.IdentityEscrowFailedReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"Error escrowing identity: %v",
+			err,
+		)
+		return err
+	}
+
+	conditions.MarkTrue(apiExport, apisv1alpha1.APIExportIdentityEscrowed)
+
+	return nil
+}
+
 func (c *controller) updateVirtualWorkspaceURLs(ctx context.Context, apiExport *apisv1alpha1.APIExport) error {
 	logger := klog.FromContext(ctx)
 	shards, err := c.listShards()
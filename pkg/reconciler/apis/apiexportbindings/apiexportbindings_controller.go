@@ -0,0 +1,277 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportbindings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	apisv1alpha1apply "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-apiexport-bindings"
+)
+
+// NewController returns a new controller publishing the APIBindings of this shard in status.bindings
+// of their APIExports, as configured by spec.bindingVisibility. The APIExports might live on other
+// shards, hence the client must reach all shards, e.g. through the front-proxy.
+func NewController(
+	shardName string,
+	kcpClusterClient kcpclientset.ClusterInterface,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	apiExportInformer, globalAPIExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
+
+	c := &controller{
+		queue:     queue,
+		shardName: shardName,
+
+		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), apiExportInformer.Informer().GetIndexer(), globalAPIExportInformer.Informer().GetIndexer(), path, name)
+		},
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		applyBindings: func(ctx context.Context, clusterName logicalcluster.Name, name string, bindings *apisv1alpha1.APIExportBindings) error {
+			status := apisv1alpha1apply.APIExportStatus()
+			if bindings != nil {
+				claims := make([]*apisv1alpha1apply.PermissionClaimSummaryApplyConfiguration, 0, len(bindings.PermissionClaims))
+				for _, claim := range bindings.PermissionClaims {
+					summary := apisv1alpha1apply.PermissionClaimSummary().
+						WithGroup(claim.Group).
+						WithResource(claim.Resource).
+						WithAccepted(claim.Accepted).
+						WithRejected(claim.Rejected)
+					if claim.IdentityHash != "" {
+						summary = summary.WithIdentityHash(claim.IdentityHash)
+					}
+					for _, reason := range claim.RejectionReasons {
+						summary = summary.WithRejectionReasons(apisv1alpha1apply.PermissionClaimRejectionReason().WithReason(reason.Reason).WithCount(reason.Count))
+					}
+					claims = append(claims, summary)
+				}
+				status = status.WithBindings(apisv1alpha1apply.APIExportBindings().
+					WithShard(bindings.Shard).
+					WithCount(bindings.Count).
+					WithWorkspaces(bindings.Workspaces...).
+					WithPermissionClaims(claims...))
+			}
+			// Every shard applies its own entry with its own field manager. Applying no entry
+			// removes the one previously applied by this shard.
+			_, err := kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIExports().ApplyStatus(ctx,
+				apisv1alpha1apply.APIExport(name).WithStatus(status),
+				metav1.ApplyOptions{FieldManager: ControllerName + ":" + shardName, Force: true},
+			)
+			return err
+		},
+	}
+
+	indexers.AddIfNotPresentOrDie(apiBindingInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
+	})
+	for _, informer := range []apisv1alpha1informers.APIExportClusterInformer{apiExportInformer, globalAPIExportInformer} {
+		indexers.AddIfNotPresentOrDie(informer.Informer().GetIndexer(), cache.Indexers{
+			indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+		})
+	}
+
+	c.listAPIBindings = func(export *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+		keys := sets.NewString()
+		if path := logicalcluster.NewPath(export.Annotations[core.LogicalClusterPathAnnotationKey]); !path.Empty() {
+			pathKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, path.Join(export.Name).String())
+			if err != nil {
+				return nil, err
+			}
+			keys.Insert(pathKeys...)
+		}
+
+		clusterKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, logicalcluster.From(export).Path().Join(export.Name).String())
+		if err != nil {
+			return nil, err
+		}
+		keys.Insert(clusterKeys...)
+
+		bindings := make([]*apisv1alpha1.APIBinding, 0, keys.Len())
+		for _, key := range keys.List() {
+			binding, exists, err := apiBindingInformer.Informer().GetIndexer().GetByKey(key)
+			if err != nil {
+				return nil, err
+			} else if !exists {
+				continue
+			}
+			bindings = append(bindings, binding.(*apisv1alpha1.APIBinding))
+		}
+		return bindings, nil
+	}
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueueAPIBinding(oldObj)
+			c.enqueueAPIBinding(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj)
+		},
+	})
+
+	for _, informer := range []apisv1alpha1informers.APIExportClusterInformer{apiExportInformer, globalAPIExportInformer} {
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueAPIExport(obj.(*apisv1alpha1.APIExport))
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldExport, newExport := oldObj.(*apisv1alpha1.APIExport), newObj.(*apisv1alpha1.APIExport)
+				if oldExport.Spec.BindingVisibility != newExport.Spec.BindingVisibility ||
+					!equality.Semantic.DeepEqual(oldExport.Spec.PermissionClaims, newExport.Spec.PermissionClaims) ||
+					!equality.Semantic.DeepEqual(oldExport.Status.Bindings, newExport.Status.Bindings) {
+					c.enqueueAPIExport(newExport)
+				}
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller publishes the APIBindings of this shard in status.bindings of their APIExports. Each
+// shard owns the entry with its name.
+type controller struct {
+	queue     workqueue.RateLimitingInterface
+	shardName string
+
+	getAPIExport      func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+	listAPIBindings   func(export *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error)
+	getLogicalCluster func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	applyBindings     func(ctx context.Context, clusterName logicalcluster.Name, name string, bindings *apisv1alpha1.APIExportBindings) error
+}
+
+// enqueueAPIExport enqueues an APIExport.
+func (c *controller) enqueueAPIExport(export *apisv1alpha1.APIExport) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(export)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIExport")
+	c.queue.Add(key)
+}
+
+// enqueueAPIBinding enqueues the APIExport an APIBinding references.
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj))
+		return
+	}
+	if binding.Spec.Reference.Export == nil {
+		return
+	}
+
+	path := logicalcluster.NewPath(binding.Spec.Reference.Export.Path)
+	if path.Empty() {
+		path = logicalcluster.From(binding).Path()
+	}
+	export, err := c.getAPIExport(path, binding.Spec.Reference.Export.Name)
+	if err != nil {
+		return // nothing to publish for unknown APIExports
+	}
+
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(export).String(), "", export.Name)
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIExport because of APIBinding", "apibinding", binding.Name)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportbindings
+
+import (
+	"context"
+	"sort"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+)
+
+// maxWorkspaces is the maximal number of workspaces listed per shard.
+const maxWorkspaces = 100
+
+// unspecifiedRejectionReason counts the rejections of permission claims without a reason.
+const unspecifiedRejectionReason = "Unspecified"
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+
+	export, err := c.getAPIExport(clusterName.Path(), name)
+	if apierrors.IsNotFound(err) {
+		return nil // nothing to publish
+	}
+	if err != nil {
+		return err
+	}
+
+	desired, err := c.desiredBindings(export)
+	if err != nil {
+		return err
+	}
+
+	var current *apisv1alpha1.APIExportBindings
+	for i := range export.Status.Bindings {
+		if export.Status.Bindings[i].Shard == c.shardName {
+			current = &export.Status.Bindings[i]
+			break
+		}
+	}
+	if equality.Semantic.DeepEqual(current, desired) {
+		return nil
+	}
+
+	logger.V(2).Info("publishing APIBindings of shard", "shard", c.shardName, "bindings", desired)
+	return c.applyBindings(ctx, logicalcluster.From(export), export.Name, desired)
+}
+
+// desiredBindings returns the entry of this shard in status.bindings of the APIExport, or nil if
+// nothing is to be published about the APIBindings of this shard.
+func (c *controller) desiredBindings(export *apisv1alpha1.APIExport) (*apisv1alpha1.APIExportBindings, error) {
+	visibility := export.Spec.BindingVisibility
+	if visibility != apisv1alpha1.APIExportBindingVisibilityCount && visibility != apisv1alpha1.APIExportBindingVisibilityWorkspaces {
+		return nil, nil
+	}
+
+	bindings, err := c.listAPIBindings(export)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := sets.NewString()
+	live := make([]*apisv1alpha1.APIBinding, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.DeletionTimestamp != nil {
+			continue
+		}
+		live = append(live, binding)
+		clusters.Insert(logicalcluster.From(binding).String())
+	}
+	if len(live) == 0 {
+		return nil, nil
+	}
+
+	desired := &apisv1alpha1.APIExportBindings{
+		Shard:            c.shardName,
+		Count:            int32(len(live)),
+		PermissionClaims: permissionClaimSummaries(export.Spec.PermissionClaims, live),
+	}
+	if visibility != apisv1alpha1.APIExportBindingVisibilityWorkspaces {
+		return desired, nil
+	}
+
+	workspaces := make([]string, 0, clusters.Len())
+	for _, cluster := range clusters.UnsortedList() {
+		path := logicalcluster.Name(cluster).Path()
+		if lc, err := c.getLogicalCluster(logicalcluster.Name(cluster)); err == nil {
+			if p, found := lc.Annotations[core.LogicalClusterPathAnnotationKey]; found {
+				path = logicalcluster.NewPath(p)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		workspaces = append(workspaces, path.String())
+	}
+	sort.Strings(workspaces)
+	if len(workspaces) > maxWorkspaces {
+		workspaces = workspaces[:maxWorkspaces]
+	}
+	desired.Workspaces = workspaces
+
+	return desired, nil
+}
+
+// permissionClaimSummaries counts how the APIBindings decided about each permission claim of the
+// APIExport.
+func permissionClaimSummaries(claims []apisv1alpha1.PermissionClaim, bindings []*apisv1alpha1.APIBinding) []apisv1alpha1.PermissionClaimSummary {
+	if len(claims) == 0 {
+		return nil
+	}
+
+	summaries := make([]apisv1alpha1.PermissionClaimSummary, 0, len(claims))
+	for _, claim := range claims {
+		summary := apisv1alpha1.PermissionClaimSummary{
+			GroupResource: claim.GroupResource,
+			IdentityHash:  claim.IdentityHash,
+		}
+		reasons := map[string]int32{}
+		for _, binding := range bindings {
+			for _, decision := range binding.Spec.PermissionClaims {
+				if !claim.Equal(decision.PermissionClaim) {
+					continue
+				}
+				switch decision.State {
+				case apisv1alpha1.ClaimAccepted:
+					summary.Accepted++
+				case apisv1alpha1.ClaimRejected:
+					summary.Rejected++
+					reason := decision.Reason
+					if reason == "" {
+						reason = unspecifiedRejectionReason
+					}
+					reasons[reason]++
+				}
+				break
+			}
+		}
+		for _, reason := range sets.StringKeySet(reasons).List() {
+			summary.RejectionReasons = append(summary.RejectionReasons, apisv1alpha1.PermissionClaimRejectionReason{Reason: reason, Count: reasons[reason]})
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Group != summaries[j].Group {
+			return summaries[i].Group < summaries[j].Group
+		}
+		if summaries[i].Resource != summaries[j].Resource {
+			return summaries[i].Resource < summaries[j].Resource
+		}
+		return summaries[i].IdentityHash < summaries[j].IdentityHash
+	})
+
+	return summaries
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportbindings
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+func newBinding(cluster string) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		},
+	}
+}
+
+func TestProcess(t *testing.T) {
+	deleting := newBinding("c3")
+	deleting.DeletionTimestamp = &metav1.Time{}
+
+	tests := map[string]struct {
+		visibility apisv1alpha1.APIExportBindingVisibility
+		bindings   []*apisv1alpha1.APIBinding
+		existing   []apisv1alpha1.APIExportBindings
+
+		wantApplied bool
+		want        *apisv1alpha1.APIExportBindings
+	}{
+		"unset visibility publishes nothing": {
+			bindings: []*apisv1alpha1.APIBinding{newBinding("c1")},
+		},
+		"none removes the entry of the shard": {
+			visibility:  apisv1alpha1.APIExportBindingVisibilityNone,
+			bindings:    []*apisv1alpha1.APIBinding{newBinding("c1")},
+			existing:    []apisv1alpha1.APIExportBindings{{Shard: "other", Count: 3}, {Shard: "alpha", Count: 1}},
+			wantApplied: true,
+		},
+		"count": {
+			visibility:  apisv1alpha1.APIExportBindingVisibilityCount,
+			bindings:    []*apisv1alpha1.APIBinding{newBinding("c1"), newBinding("c2"), deleting},
+			existing:    []apisv1alpha1.APIExportBindings{{Shard: "other", Count: 3}},
+			wantApplied: true,
+			want:        &apisv1alpha1.APIExportBindings{Shard: "alpha", Count: 2},
+		},
+		"unchanged count": {
+			visibility: apisv1alpha1.APIExportBindingVisibilityCount,
+			bindings:   []*apisv1alpha1.APIBinding{newBinding("c1"), newBinding("c2")},
+			existing:   []apisv1alpha1.APIExportBindings{{Shard: "alpha", Count: 2}},
+		},
+		"no bindings left": {
+			visibility:  apisv1alpha1.APIExportBindingVisibilityCount,
+			existing:    []apisv1alpha1.APIExportBindings{{Shard: "alpha", Count: 2}},
+			wantApplied: true,
+		},
+		"workspaces": {
+			visibility:  apisv1alpha1.APIExportBindingVisibilityWorkspaces,
+			bindings:    []*apisv1alpha1.APIBinding{newBinding("c2"), newBinding("c1"), newBinding("unknown")},
+			wantApplied: true,
+			want:        &apisv1alpha1.APIExportBindings{Shard: "alpha", Count: 3, Workspaces: []string{"root:org:a", "root:org:b", "unknown"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			export := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "widgets",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "provider"},
+				},
+				Spec:   apisv1alpha1.APIExportSpec{BindingVisibility: tt.visibility},
+				Status: apisv1alpha1.APIExportStatus{Bindings: tt.existing},
+			}
+			paths := map[logicalcluster.Name]string{"c1": "root:org:b", "c2": "root:org:a"}
+
+			var applied bool
+			var got *apisv1alpha1.APIExportBindings
+			c := &controller{
+				shardName: "alpha",
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					require.Equal(t, "provider", path.String())
+					return export, nil
+				},
+				listAPIBindings: func(*apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+					return tt.bindings, nil
+				},
+				getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+					path, found := paths[clusterName]
+					if !found {
+						return nil, apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), corev1alpha1.LogicalClusterName)
+					}
+					return &corev1alpha1.LogicalCluster{ObjectMeta: metav1.ObjectMeta{
+						Name:        corev1alpha1.LogicalClusterName,
+						Annotations: map[string]string{core.LogicalClusterPathAnnotationKey: path},
+					}}, nil
+				},
+				applyBindings: func(_ context.Context, clusterName logicalcluster.Name, name string, bindings *apisv1alpha1.APIExportBindings) error {
+					require.Equal(t, logicalcluster.Name("provider"), clusterName)
+					require.Equal(t, "widgets", name)
+					applied, got = true, bindings
+					return nil
+				},
+			}
+
+			require.NoError(t, c.process(context.Background(), "provider|widgets"))
+			require.Equal(t, tt.wantApplied, applied)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkspacesAreCapped(t *testing.T) {
+	bindings := make([]*apisv1alpha1.APIBinding, 0, maxWorkspaces+10)
+	for i := 0; i < maxWorkspaces+10; i++ {
+		bindings = append(bindings, newBinding(fmt.Sprintf("c%03d", i)))
+	}
+	c := &controller{
+		shardName: "alpha",
+		listAPIBindings: func(*apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+			return bindings, nil
+		},
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return nil, apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), corev1alpha1.LogicalClusterName)
+		},
+	}
+
+	got, err := c.desiredBindings(&apisv1alpha1.APIExport{
+		Spec: apisv1alpha1.APIExportSpec{BindingVisibility: apisv1alpha1.APIExportBindingVisibilityWorkspaces},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(maxWorkspaces+10), got.Count)
+	require.Len(t, got.Workspaces, maxWorkspaces)
+	require.Equal(t, "c000", got.Workspaces[0])
+	require.Equal(t, "c099", got.Workspaces[maxWorkspaces-1])
+}
+
+func TestPermissionClaimSummaries(t *testing.T) {
+	configmaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	widgets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, IdentityHash: "hash", All: true}
+	otherWidgets := apisv1alpha1.PermissionClaim{GroupResource: widgets.GroupResource, IdentityHash: "other", All: true}
+
+	binding := func(cluster string, decisions ...apisv1alpha1.AcceptablePermissionClaim) *apisv1alpha1.APIBinding {
+		b := newBinding(cluster)
+		b.Spec.PermissionClaims = decisions
+		return b
+	}
+	accepted := func(claim apisv1alpha1.PermissionClaim) apisv1alpha1.AcceptablePermissionClaim {
+		return apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: apisv1alpha1.ClaimAccepted}
+	}
+	rejected := func(claim apisv1alpha1.PermissionClaim, reason string) apisv1alpha1.AcceptablePermissionClaim {
+		return apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: apisv1alpha1.ClaimRejected, Reason: reason}
+	}
+
+	got := permissionClaimSummaries([]apisv1alpha1.PermissionClaim{widgets, configmaps}, []*apisv1alpha1.APIBinding{
+		binding("c1", accepted(configmaps), rejected(widgets, "TooBroad")),
+		binding("c2", rejected(configmaps, ""), rejected(widgets, "TooBroad")),
+		binding("c3", rejected(configmaps, "NotNeeded"), accepted(otherWidgets)),
+		binding("c4"),
+	})
+	require.Equal(t, []apisv1alpha1.PermissionClaimSummary{
+		{
+			GroupResource: configmaps.GroupResource,
+			Accepted:      1,
+			Rejected:      2,
+			RejectionReasons: []apisv1alpha1.PermissionClaimRejectionReason{
+				{Reason: "NotNeeded", Count: 1},
+				{Reason: unspecifiedRejectionReason, Count: 1},
+			},
+		},
+		{
+			GroupResource:    widgets.GroupResource,
+			IdentityHash:     "hash",
+			Rejected:         2,
+			RejectionReasons: []apisv1alpha1.PermissionClaimRejectionReason{{Reason: "TooBroad", Count: 2}},
+		},
+	}, got)
+
+	require.Nil(t, permissionClaimSummaries(nil, []*apisv1alpha1.APIBinding{binding("c1", accepted(configmaps))}))
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
 )
@@ -56,7 +57,7 @@ func NewController(
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
 	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &controller{
 		queue: queue,
@@ -69,6 +70,7 @@ func NewController(
 		deleteCRD: func(ctx context.Context, name string) error {
 			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(apibinding.SystemBoundCRDsClusterName.Path()).Delete(ctx, name, metav1.DeleteOptions{})
 		},
+		now: time.Now,
 	}
 
 	indexers.AddIfNotPresentOrDie(
@@ -117,6 +119,7 @@ type controller struct {
 	getCRD                           func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getAPIBindingsByBoundResourceUID func(name string) ([]*apisv1alpha1.APIBinding, error)
 	deleteCRD                        func(ctx context.Context, name string) error
+	now                              func() time.Time
 }
 
 // enqueueCRD enqueues a CRD.
@@ -141,14 +144,17 @@ func (c *controller) enqueueFromAPIBinding(oldBinding, newBinding *apisv1alpha1.
 
 	uidSet := sets.String{}
 
-	if oldBinding != nil {
-		for _, boundResource := range oldBinding.Status.BoundResources {
-			uidSet.Insert(boundResource.Schema.UID)
+	// the index values are the names of the bound CRDs in use, including those of rotated identities.
+	for _, binding := range []*apisv1alpha1.APIBinding{oldBinding, newBinding} {
+		if binding == nil {
+			continue
 		}
-	}
-
-	for _, boundResource := range newBinding.Status.BoundResources {
-		uidSet.Insert(boundResource.Schema.UID)
+		names, err := indexers.IndexAPIBindingByBoundResourceUID(binding)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		uidSet.Insert(names...)
 	}
 
 	for uid := range uidSet {
@@ -233,7 +239,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
-	age := time.Since(obj.CreationTimestamp.Time)
+	age := c.now().Sub(obj.CreationTimestamp.Time)
 
 	if age < AgeThreshold {
 		duration := AgeThreshold - age
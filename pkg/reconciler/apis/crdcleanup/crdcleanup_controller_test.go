@@ -26,8 +26,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
 
+	reconcilertesting "github.com/kcp-dev/kcp/pkg/reconciler/testing"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
 )
@@ -53,7 +54,8 @@ func TestBoundCRDDeletion(t *testing.T) {
 		},
 	}
 
-	oldEnoughToDelete := time.Now().Add((AgeThreshold * -1) - time.Second)
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	oldEnoughToDelete := now.Add((AgeThreshold * -1) - time.Second)
 
 	tests := []struct {
 		name                       string
@@ -80,7 +82,7 @@ func TestBoundCRDDeletion(t *testing.T) {
 		},
 		{
 			name:                       "CRD won't have bindings after requeue",
-			creationTimestamp:          time.Now(),
+			creationTimestamp:          now,
 			hasBindings:                false,
 			expectDeletion:             false,
 			expectRequeue:              true,
@@ -89,7 +91,7 @@ func TestBoundCRDDeletion(t *testing.T) {
 		},
 		{
 			name:                       "CRD will have bindings after requeue",
-			creationTimestamp:          time.Now(),
+			creationTimestamp:          now,
 			hasBindings:                false,
 			expectDeletion:             false,
 			expectRequeue:              true,
@@ -104,21 +106,21 @@ func TestBoundCRDDeletion(t *testing.T) {
 
 			crd := &apiextensionsv1.CustomResourceDefinition{}
 			crd.SetName(schemaUID)
+			crd.CreationTimestamp = metav1.NewTime(tt.creationTimestamp)
 
-			q := testRateLimitingQueue{
-				workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
-				false,
-			}
+			clock := clocktesting.NewFakeClock(now)
+			q := reconcilertesting.NewQueue(clock)
+			requeued := false
 
 			controller := &controller{
-				queue: &q,
+				queue: q,
 				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 					return crd, nil
 				},
 				getAPIBindingsByBoundResourceUID: func(name string) ([]*apisv1alpha1.APIBinding, error) {
-					if !q.requeueHappened && tt.hasBindings {
+					if !requeued && tt.hasBindings {
 						return []*apisv1alpha1.APIBinding{apiBinding}, nil
-					} else if q.requeueHappened && tt.hasBindingsAfterRequeue {
+					} else if requeued && tt.hasBindingsAfterRequeue {
 						return []*apisv1alpha1.APIBinding{apiBinding}, nil
 					}
 					return []*apisv1alpha1.APIBinding{}, nil
@@ -127,10 +129,10 @@ func TestBoundCRDDeletion(t *testing.T) {
 					deleteHappened = true
 					return nil
 				},
+				now: clock.Now,
 			}
 
-			testController := func(creationTimestamp time.Time, expectDeletion bool) {
-				crd.ObjectMeta.CreationTimestamp = metav1.NewTime(creationTimestamp)
+			testController := func(expectDeletion bool) {
 				err := controller.process(context.Background(), schemaUID)
 				if err != nil {
 					t.Errorf("Unexpected error: %q", err)
@@ -141,29 +143,29 @@ func TestBoundCRDDeletion(t *testing.T) {
 				}
 			}
 
-			testController(tt.creationTimestamp, tt.expectDeletion)
+			testController(tt.expectDeletion)
 
-			if tt.expectRequeue != q.requeueHappened {
-				t.Errorf("Expected requeue: %t, but instead acutal requeue: %t", tt.expectRequeue, q.requeueHappened)
+			_, requeued = q.Waiting(schemaUID)
+			if tt.expectRequeue != requeued {
+				t.Errorf("Expected requeue: %t, but instead acutal requeue: %t", tt.expectRequeue, requeued)
 			}
 
 			if tt.expectRequeue {
 				// Test time passing but not long enough to trigger a delete
 				// This is to ensure CRDs do not get deleted before the configured threshold
-				testController(tt.creationTimestamp.Add(((AgeThreshold/2)+time.Second)*-1), false)
+				clock.Step(AgeThreshold/2 + time.Second)
+				if q.Len() != 0 {
+					t.Errorf("Expected the CRD to be requeued after %s only", AgeThreshold)
+				}
+				testController(false)
 
 				// This should be enough time passing to trigger a delete (if expected)
-				testController(tt.creationTimestamp.Add((AgeThreshold+time.Second)*-1), tt.expectDeletionAfterRequeue)
+				clock.Step(AgeThreshold / 2)
+				if q.Len() != 1 {
+					t.Errorf("Expected the CRD to be requeued after %s", AgeThreshold)
+				}
+				testController(tt.expectDeletionAfterRequeue)
 			}
 		})
 	}
 }
-
-type testRateLimitingQueue struct {
-	workqueue.RateLimitingInterface
-	requeueHappened bool
-}
-
-func (q *testRateLimitingQueue) AddAfter(item interface{}, duration time.Duration) {
-	q.requeueHappened = true
-}
@@ -0,0 +1,294 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityrotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
+	kcpapiextensionsv1informers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-apibinding-identityrotation"
+)
+
+// NewController returns a new controller moving the objects of APIBindings to the storage of the next
+// identity of their APIExport while its identity is rotated. The objects are copied in etcd, below the
+// given prefix.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	apiExportInformer, globalAPIExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+	store Store,
+	etcdPrefix string,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
+
+	c := &controller{
+		queue: queue,
+
+		getAPIBinding: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error) {
+			return apiBindingInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		updateAPIBinding: func(ctx context.Context, binding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(logicalcluster.From(binding).Path()).ApisV1alpha1().APIBindings().Update(ctx, binding, metav1.UpdateOptions{})
+			return err
+		},
+		updateAPIBindingStatus: func(ctx context.Context, binding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(logicalcluster.From(binding).Path()).ApisV1alpha1().APIBindings().UpdateStatus(ctx, binding, metav1.UpdateOptions{})
+			return err
+		},
+		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), apiExportInformer.Informer().GetIndexer(), globalAPIExportInformer.Informer().GetIndexer(), path, name)
+		},
+
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		createCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error {
+			_, err := crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
+			return err
+		},
+		updateCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error {
+			_, err := crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Update(ctx, crd, metav1.UpdateOptions{})
+			return err
+		},
+
+		store:      store,
+		etcdPrefix: etcdPrefix,
+		now:        time.Now,
+	}
+
+	indexers.AddIfNotPresentOrDie(apiBindingInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
+	})
+	for _, informer := range []apisv1alpha1informers.APIExportClusterInformer{apiExportInformer, globalAPIExportInformer} {
+		indexers.AddIfNotPresentOrDie(informer.Informer().GetIndexer(), cache.Indexers{
+			indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+		})
+	}
+
+	c.listAPIBindingKeysByAPIExport = func(export *apisv1alpha1.APIExport) ([]string, error) {
+		keys := sets.NewString()
+		if path := logicalcluster.NewPath(export.Annotations[core.LogicalClusterPathAnnotationKey]); !path.Empty() {
+			pathKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, path.Join(export.Name).String())
+			if err != nil {
+				return nil, err
+			}
+			keys.Insert(pathKeys...)
+		}
+
+		clusterKeys, err := apiBindingInformer.Informer().GetIndexer().IndexKeys(indexers.APIBindingsByAPIExport, logicalcluster.From(export).Path().Join(export.Name).String())
+		if err != nil {
+			return nil, err
+		}
+		keys.Insert(clusterKeys...)
+
+		return keys.List(), nil
+	}
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj.(*apisv1alpha1.APIBinding))
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueAPIBinding(newObj.(*apisv1alpha1.APIBinding))
+		},
+	})
+
+	for _, informer := range []apisv1alpha1informers.APIExportClusterInformer{apiExportInformer, globalAPIExportInformer} {
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldExport, newExport := oldObj.(*apisv1alpha1.APIExport), newObj.(*apisv1alpha1.APIExport)
+				if newExport.Status.NextIdentityHash != "" || oldExport.Status.IdentityHash != newExport.Status.IdentityHash {
+					c.enqueueAPIBindingsForAPIExport(newExport)
+				}
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller moves the objects of APIBindings to the storage of the next identity of their APIExport.
+// The objects of an APIBinding are
+//
+//  1. copied to the storage of the next identity, which is served by a second bound CRD,
+//  2. switched to the next identity in the bound resources of the APIBinding,
+//  3. copied again if they were modified through the old identity while the switch was observed,
+//  4. deleted from the storage of the old identity when the identity of the APIExport is replaced.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getAPIBinding                 func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error)
+	listAPIBindingKeysByAPIExport func(export *apisv1alpha1.APIExport) ([]string, error)
+	updateAPIBinding              func(ctx context.Context, binding *apisv1alpha1.APIBinding) error
+	updateAPIBindingStatus        func(ctx context.Context, binding *apisv1alpha1.APIBinding) error
+	getAPIExport                  func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+
+	getCRD    func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	createCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error
+	updateCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error
+
+	store      Store
+	etcdPrefix string
+	now        func() time.Time
+}
+
+// enqueueAPIBinding enqueues an APIBinding.
+func (c *controller) enqueueAPIBinding(binding *apisv1alpha1.APIBinding) {
+	if binding.Spec.Reference.Export == nil {
+		return
+	}
+
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(binding)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIBinding")
+	c.queue.Add(key)
+}
+
+// enqueueAPIBindingsForAPIExport enqueues the APIBindings of an APIExport.
+func (c *controller) enqueueAPIBindingsForAPIExport(export *apisv1alpha1.APIExport) {
+	keys, err := c.listAPIBindingKeysByAPIExport(export)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithObject(logging.WithReconciler(klog.Background(), ControllerName), export)
+	for _, key := range keys {
+		logging.WithQueueKey(logger, key).V(4).Info("queueing APIBinding because of APIExport")
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.process(ctx, key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) (time.Duration, error) {
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return 0, nil
+	}
+
+	binding, err := c.getAPIBinding(clusterName, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil // object deleted before we handled it
+		}
+		return 0, err
+	}
+	if binding.Spec.Reference.Export == nil {
+		return 0, nil
+	}
+
+	path := logicalcluster.NewPath(binding.Spec.Reference.Export.Path)
+	if path.Empty() {
+		path = clusterName.Path()
+	}
+	export, err := c.getAPIExport(path, binding.Spec.Reference.Export.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil // nothing to rotate without the APIExport
+		}
+		return 0, err
+	}
+
+	logger := logging.WithObject(klog.FromContext(ctx), binding)
+	ctx = klog.NewContext(ctx, logger)
+
+	return c.reconcile(ctx, binding.DeepCopy(), export)
+}
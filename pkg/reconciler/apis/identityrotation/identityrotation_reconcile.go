@@ -0,0 +1,311 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityrotation
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+const (
+	// switchGracePeriod is how long objects may still be written through the old identity after the
+	// bound resources were switched, by API servers that have not observed the switch yet.
+	switchGracePeriod = 30 * time.Second
+
+	// crdPollInterval is how often the bound CRD of the next identity is checked to be established.
+	crdPollInterval = 2 * time.Second
+)
+
+// reconcile moves the objects of the APIBinding one step further to the next identity of the APIExport.
+// It returns the duration after which the APIBinding has to be reconciled again, if any.
+func (c *controller) reconcile(ctx context.Context, binding *apisv1alpha1.APIBinding, export *apisv1alpha1.APIExport) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	rotation, err := GetRotation(binding)
+	if err != nil {
+		return 0, err
+	}
+
+	if rotation == nil {
+		target := export.Status.NextIdentityHash
+		if target == "" {
+			target = export.Status.IdentityHash
+		}
+		if target == "" {
+			return 0, nil
+		}
+
+		for _, r := range binding.Status.BoundResources {
+			if r.Schema.IdentityHash != "" && r.Schema.IdentityHash != target {
+				logger.V(2).Info("starting identity rotation", "from", r.Schema.IdentityHash, "to", target)
+				return 0, c.setRotation(ctx, binding, &Rotation{From: r.Schema.IdentityHash, To: target})
+			}
+		}
+		return 0, nil
+	}
+
+	var pending []apisv1alpha1.BoundAPIResource
+	for _, r := range binding.Status.BoundResources {
+		if r.Schema.IdentityHash == rotation.From {
+			pending = append(pending, r)
+		}
+	}
+
+	switch {
+	case len(pending) > 0:
+		return c.migrate(ctx, binding, rotation, pending)
+	case rotation.Revision != 0:
+		return c.catchUp(ctx, binding, rotation)
+	case export.Status.IdentityHash == rotation.To:
+		return 0, c.cleanUp(ctx, binding, rotation)
+	}
+
+	return 0, nil
+}
+
+// migrate copies the objects of the given bound resources to the next identity, and then switches the
+// bound resources to it.
+func (c *controller) migrate(ctx context.Context, binding *apisv1alpha1.APIBinding, rotation *Rotation, resources []apisv1alpha1.BoundAPIResource) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(binding)
+
+	established := true
+	for _, r := range resources {
+		ok, err := c.ensureBoundCRD(ctx, r.Schema.UID, rotation.From, rotation.To)
+		if err != nil {
+			return 0, err
+		}
+		established = established && ok
+	}
+	if !established {
+		logger.V(4).Info("waiting for the bound CRDs of the next identity to be established")
+		return crdPollInterval, nil
+	}
+
+	if rotation.Revision == 0 {
+		var revision int64
+		copied := 0
+		for _, r := range resources {
+			oldPrefix, newPrefix := c.prefix(r, rotation.From, clusterName), c.prefix(r, rotation.To, clusterName)
+			kvs, rev, err := c.store.List(ctx, oldPrefix)
+			if err != nil {
+				return 0, err
+			}
+			if revision == 0 || rev < revision {
+				revision = rev
+			}
+
+			// nothing is written through the next identity before the switch, i.e. existing keys are left
+			// over from an interrupted copy.
+			existing, _, err := c.store.List(ctx, newPrefix)
+			if err != nil {
+				return 0, err
+			}
+			keys := make(map[string]bool, len(kvs))
+			for _, kv := range kvs {
+				keys[strings.TrimPrefix(kv.Key, oldPrefix)] = true
+			}
+			for _, kv := range existing {
+				if !keys[strings.TrimPrefix(kv.Key, newPrefix)] {
+					if err := c.store.Delete(ctx, kv.Key, 0); err != nil {
+						return 0, err
+					}
+				}
+			}
+
+			for _, kv := range kvs {
+				if err := c.store.Put(ctx, newPrefix+strings.TrimPrefix(kv.Key, oldPrefix), kv.Value, 0); err != nil {
+					return 0, err
+				}
+			}
+			copied += len(kvs)
+		}
+
+		copiedRevision, err := c.store.Revision(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		logger.V(2).Info("copied objects to the next identity", "count", copied, "revision", revision, "copiedRevision", copiedRevision)
+		rotation.Revision, rotation.CopiedRevision = revision, copiedRevision
+		return 0, c.setRotation(ctx, binding, rotation)
+	}
+
+	for i := range binding.Status.BoundResources {
+		if binding.Status.BoundResources[i].Schema.IdentityHash == rotation.From {
+			binding.Status.BoundResources[i].Schema.IdentityHash = rotation.To
+		}
+	}
+	logger.V(2).Info("switching bound resources to the next identity", "to", rotation.To)
+	return 0, c.updateAPIBindingStatus(ctx, binding)
+}
+
+// catchUp copies the objects written through the old identity while the switch of the bound resources
+// was not observed everywhere yet, when the grace period after the switch is over.
+func (c *controller) catchUp(ctx context.Context, binding *apisv1alpha1.APIBinding, rotation *Rotation) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(binding)
+
+	if rotation.SwitchedAt == nil {
+		rotation.SwitchedAt = &metav1.Time{Time: c.now()}
+		return switchGracePeriod, c.setRotation(ctx, binding, rotation)
+	}
+	if wait := rotation.SwitchedAt.Add(switchGracePeriod).Sub(c.now()); wait > 0 {
+		return wait, nil
+	}
+
+	for _, r := range binding.Status.BoundResources {
+		if r.Schema.IdentityHash != rotation.To {
+			continue
+		}
+
+		oldPrefix, newPrefix := c.prefix(r, rotation.From, clusterName), c.prefix(r, rotation.To, clusterName)
+		oldKVs, _, err := c.store.List(ctx, oldPrefix)
+		if err != nil {
+			return 0, err
+		}
+		newKVs, _, err := c.store.List(ctx, newPrefix)
+		if err != nil {
+			return 0, err
+		}
+
+		// Objects written through the next identity after the copy win over the old identity.
+		oldKeys := make(map[string]bool, len(oldKVs))
+		for _, kv := range oldKVs {
+			name := strings.TrimPrefix(kv.Key, oldPrefix)
+			oldKeys[name] = true
+			if kv.ModRevision > rotation.Revision {
+				logger.V(4).Info("copying object modified after the copy", "key", kv.Key)
+				if err := c.store.Put(ctx, newPrefix+name, kv.Value, rotation.CopiedRevision); err != nil {
+					return 0, err
+				}
+			}
+		}
+		for _, kv := range newKVs {
+			if !oldKeys[strings.TrimPrefix(kv.Key, newPrefix)] && kv.ModRevision <= rotation.CopiedRevision {
+				logger.V(4).Info("deleting object deleted after the copy", "key", kv.Key)
+				if err := c.store.Delete(ctx, kv.Key, rotation.CopiedRevision); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	rotation.Revision, rotation.CopiedRevision, rotation.SwitchedAt = 0, 0, nil
+	return 0, c.setRotation(ctx, binding, rotation)
+}
+
+// cleanUp deletes the objects of the old identity when the APIExport has replaced it, and finishes the rotation.
+func (c *controller) cleanUp(ctx context.Context, binding *apisv1alpha1.APIBinding, rotation *Rotation) error {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(binding)
+
+	for _, r := range binding.Status.BoundResources {
+		kvs, _, err := c.store.List(ctx, c.prefix(r, rotation.From, clusterName))
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			if err := c.store.Delete(ctx, kv.Key, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.V(2).Info("finished identity rotation", "from", rotation.From, "to", rotation.To)
+	delete(binding.Annotations, apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey)
+	return c.updateAPIBinding(ctx, binding)
+}
+
+// ensureBoundCRD makes sure the bound CRD of the APIResourceSchema with the given UID serving the next
+// identity exists, and returns whether it is established.
+func (c *controller) ensureBoundCRD(ctx context.Context, schemaUID, from, to string) (bool, error) {
+	logger := klog.FromContext(ctx)
+	getCRD := func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		return c.getCRD(apibinding.SystemBoundCRDsClusterName, name)
+	}
+
+	_, fromCRD, err := apibinding.GetBoundCRD(getCRD, schemaUID, from)
+	if err != nil {
+		return false, err
+	}
+	if _, found := fromCRD.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey]; !found {
+		// bound CRDs created before rotations were supported serve any identity. Pin it to the old one,
+		// such that the next identity gets a bound CRD of its own.
+		crd := fromCRD.DeepCopy()
+		crd.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey] = from
+		logger.V(2).Info("pinning bound CRD to its identity", "crd", crd.Name, "identity", from)
+		return false, c.updateCRD(ctx, apibinding.SystemBoundCRDsClusterName.Path(), crd)
+	}
+
+	name, toCRD, err := apibinding.GetBoundCRD(getCRD, schemaUID, to)
+	if apierrors.IsNotFound(err) {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: make(map[string]string, len(fromCRD.Annotations)),
+			},
+			Spec: *fromCRD.Spec.DeepCopy(),
+		}
+		for k, v := range fromCRD.Annotations {
+			crd.Annotations[k] = v
+		}
+		crd.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey] = to
+		logger.V(2).Info("creating bound CRD for the next identity", "crd", name, "identity", to)
+		if err := c.createCRD(ctx, apibinding.SystemBoundCRDsClusterName.Path(), crd); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return apihelpers.IsCRDConditionTrue(toCRD, apiextensionsv1.Established), nil
+}
+
+// prefix returns the etcd prefix of the objects of the bound resource with the given identity in the
+// logical cluster.
+func (c *controller) prefix(r apisv1alpha1.BoundAPIResource, identity string, clusterName logicalcluster.Name) string {
+	return path.Join(c.etcdPrefix, r.Group, r.Resource, identity, clusterName.String()) + "/"
+}
+
+// setRotation records the progress of the rotation on the APIBinding.
+func (c *controller) setRotation(ctx context.Context, binding *apisv1alpha1.APIBinding, rotation *Rotation) error {
+	bs, err := json.Marshal(rotation)
+	if err != nil {
+		return err
+	}
+	if binding.Annotations == nil {
+		binding.Annotations = map[string]string{}
+	}
+	binding.Annotations[apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey] = string(bs)
+	return c.updateAPIBinding(ctx, binding)
+}
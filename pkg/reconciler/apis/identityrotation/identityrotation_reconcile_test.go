@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityrotation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// fakeStore is an in-memory Store. Every write increments the revision.
+type fakeStore struct {
+	revision int64
+	kvs      map[string]KeyValue
+}
+
+func newFakeStore(kvs map[string]string) *fakeStore {
+	s := &fakeStore{kvs: map[string]KeyValue{}}
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s.write(k, kvs[k])
+	}
+	return s
+}
+
+func (s *fakeStore) write(key, value string) {
+	s.revision++
+	s.kvs[key] = KeyValue{Key: key, Value: []byte(value), ModRevision: s.revision}
+}
+
+func (s *fakeStore) remove(key string) {
+	s.revision++
+	delete(s.kvs, key)
+}
+
+func (s *fakeStore) List(ctx context.Context, prefix string) ([]KeyValue, int64, error) {
+	var ret []KeyValue
+	for k, kv := range s.kvs {
+		if strings.HasPrefix(k, prefix) {
+			ret = append(ret, kv)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Key < ret[j].Key })
+	return ret, s.revision, nil
+}
+
+func (s *fakeStore) Put(ctx context.Context, key string, value []byte, notModifiedAfter int64) error {
+	if notModifiedAfter == 0 || s.kvs[key].ModRevision <= notModifiedAfter {
+		s.write(key, string(value))
+	}
+	return nil
+}
+
+func (s *fakeStore) Delete(ctx context.Context, key string, notModifiedAfter int64) error {
+	if kv, found := s.kvs[key]; found && (notModifiedAfter == 0 || kv.ModRevision <= notModifiedAfter) {
+		s.remove(key)
+	}
+	return nil
+}
+
+func (s *fakeStore) Revision(ctx context.Context) (int64, error) {
+	return s.revision, nil
+}
+
+func (s *fakeStore) values() map[string]string {
+	ret := make(map[string]string, len(s.kvs))
+	for k, kv := range s.kvs {
+		ret[k] = string(kv.Value)
+	}
+	return ret
+}
+
+func TestRotation(t *testing.T) {
+	const (
+		from = "old"
+		to   = "new"
+		uid  = "6a5b1c"
+	)
+
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:consumer"},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{{
+				Group:    "example.io",
+				Resource: "widgets",
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: uid, IdentityHash: from},
+			}},
+		},
+	}
+	export := &apisv1alpha1.APIExport{
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: from, NextIdentityHash: to},
+	}
+
+	store := newFakeStore(map[string]string{
+		"/registry/example.io/widgets/old/root:consumer/default/a":   "a",
+		"/registry/example.io/widgets/old/root:consumer/default/b":   "b",
+		"/registry/example.io/widgets/old/root:consumer/default/c":   "c",
+		"/registry/example.io/widgets/old/root:other/default/a":      "other",
+		"/registry/example.io/widgets/new/root:consumer/default/old": "left over",
+	})
+
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{
+		uid: {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: uid,
+				Annotations: map[string]string{
+					logicalcluster.AnnotationKey:       apibinding.SystemBoundCRDsClusterName.String(),
+					apisv1alpha1.AnnotationBoundCRDKey: "",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "example.io"},
+		},
+	}
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := &controller{
+		updateAPIBinding: func(ctx context.Context, b *apisv1alpha1.APIBinding) error {
+			binding = b.DeepCopy()
+			return nil
+		},
+		updateAPIBindingStatus: func(ctx context.Context, b *apisv1alpha1.APIBinding) error {
+			binding = b.DeepCopy()
+			return nil
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			require.Equal(t, apibinding.SystemBoundCRDsClusterName, clusterName)
+			crd, found := crds[name]
+			if !found {
+				return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+			}
+			return crd, nil
+		},
+		createCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error {
+			crds[crd.Name] = crd
+			return nil
+		},
+		updateCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) error {
+			crds[crd.Name] = crd
+			return nil
+		},
+		store:      store,
+		etcdPrefix: "/registry",
+		now:        func() time.Time { return now },
+	}
+
+	reconcile := func() time.Duration {
+		t.Helper()
+		requeue, err := c.reconcile(context.Background(), binding.DeepCopy(), export)
+		require.NoError(t, err)
+		return requeue
+	}
+	rotation := func() *Rotation {
+		t.Helper()
+		r, err := GetRotation(binding)
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Log("The rotation is started")
+	reconcile()
+	require.Equal(t, &Rotation{From: from, To: to}, rotation())
+
+	t.Log("The existing bound CRD is pinned to the old identity")
+	reconcile()
+	require.Equal(t, from, crds[uid].Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey])
+
+	t.Log("The bound CRD of the new identity is created")
+	require.Equal(t, crdPollInterval, reconcile())
+	rotated := crds[apibinding.RotatedBoundCRDName(uid, to)]
+	require.NotNil(t, rotated)
+	require.Equal(t, to, rotated.Annotations[apisv1alpha1.AnnotationBoundCRDIdentityKey])
+	require.Equal(t, "example.io", rotated.Spec.Group)
+
+	t.Log("Nothing is copied before the bound CRD is established")
+	require.Equal(t, crdPollInterval, reconcile())
+	require.Zero(t, rotation().Revision)
+	rotated.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+
+	t.Log("The objects are copied")
+	reconcile()
+	require.Equal(t, map[string]string{
+		"/registry/example.io/widgets/old/root:consumer/default/a": "a",
+		"/registry/example.io/widgets/old/root:consumer/default/b": "b",
+		"/registry/example.io/widgets/old/root:consumer/default/c": "c",
+		"/registry/example.io/widgets/old/root:other/default/a":    "other",
+		"/registry/example.io/widgets/new/root:consumer/default/a": "a",
+		"/registry/example.io/widgets/new/root:consumer/default/b": "b",
+		"/registry/example.io/widgets/new/root:consumer/default/c": "c",
+	}, store.values())
+	require.NotZero(t, rotation().Revision)
+	require.NotZero(t, rotation().CopiedRevision)
+
+	t.Log("Objects are modified through the old identity before the switch is observed")
+	store.write("/registry/example.io/widgets/old/root:consumer/default/a", "a2")
+	store.remove("/registry/example.io/widgets/old/root:consumer/default/b")
+	store.write("/registry/example.io/widgets/old/root:consumer/default/d", "d")
+
+	t.Log("The bound resources are switched to the new identity")
+	reconcile()
+	require.Equal(t, to, binding.Status.BoundResources[0].Schema.IdentityHash)
+
+	t.Log("Objects are modified through both identities")
+	store.write("/registry/example.io/widgets/old/root:consumer/default/c", "c2")
+	store.write("/registry/example.io/widgets/new/root:consumer/default/c", "c3")
+
+	t.Log("The switch is recorded, and the grace period is awaited")
+	require.Equal(t, switchGracePeriod, reconcile())
+	require.NotNil(t, rotation().SwitchedAt)
+	now = now.Add(switchGracePeriod / 2)
+	require.Equal(t, switchGracePeriod/2, reconcile())
+
+	t.Log("The objects modified through the old identity are copied again")
+	now = now.Add(switchGracePeriod)
+	reconcile()
+	require.Equal(t, map[string]string{
+		"/registry/example.io/widgets/old/root:consumer/default/a": "a2",
+		"/registry/example.io/widgets/old/root:consumer/default/c": "c2",
+		"/registry/example.io/widgets/old/root:consumer/default/d": "d",
+		"/registry/example.io/widgets/old/root:other/default/a":    "other",
+		"/registry/example.io/widgets/new/root:consumer/default/a": "a2",
+		"/registry/example.io/widgets/new/root:consumer/default/c": "c3",
+		"/registry/example.io/widgets/new/root:consumer/default/d": "d",
+	}, store.values())
+	require.Equal(t, &Rotation{From: from, To: to}, rotation())
+
+	t.Log("The old objects are kept until the APIExport replaced the identity")
+	reconcile()
+	require.NotNil(t, rotation())
+	export.Status.IdentityHash, export.Status.NextIdentityHash = to, ""
+	reconcile()
+	require.Nil(t, rotation())
+	require.Equal(t, map[string]string{
+		"/registry/example.io/widgets/old/root:other/default/a":    "other",
+		"/registry/example.io/widgets/new/root:consumer/default/a": "a2",
+		"/registry/example.io/widgets/new/root:consumer/default/c": "c3",
+		"/registry/example.io/widgets/new/root:consumer/default/d": "d",
+	}, store.values())
+
+	t.Log("Nothing is left to do")
+	reconcile()
+	require.Nil(t, rotation())
+}
+
+func TestRetiredIdentity(t *testing.T) {
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey: `{"from":"old","to":"new"}`,
+			},
+		},
+	}
+	require.Equal(t, "old", RetiredIdentity(binding, "new"))
+	require.Equal(t, "", RetiredIdentity(binding, "old"))
+	require.Equal(t, "", RetiredIdentity(&apisv1alpha1.APIBinding{}, "new"))
+}
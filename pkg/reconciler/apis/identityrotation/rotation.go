@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityrotation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// Rotation is the progress of moving the objects of an APIBinding from the storage of one identity
+// to the storage of the next one, stored in the internal.apis.kcp.io/identity-rotation annotation.
+type Rotation struct {
+	// From is the identity hash the objects are moved away from.
+	From string `json:"from"`
+	// To is the identity hash the objects are moved to.
+	To string `json:"to"`
+
+	// Revision is the etcd revision the objects were copied at. Objects of the old identity modified
+	// after it are copied again after the bound resources are switched to the new identity.
+	Revision int64 `json:"revision,omitempty"`
+	// CopiedRevision is the etcd revision after the objects were copied. Objects of the new identity
+	// modified after it were written through the new identity, and are not overwritten.
+	CopiedRevision int64 `json:"copiedRevision,omitempty"`
+	// SwitchedAt is the time the bound resources were observed switched to the new identity.
+	SwitchedAt *metav1.Time `json:"switchedAt,omitempty"`
+}
+
+// GetRotation returns the identity rotation in progress for the APIBinding, or nil.
+func GetRotation(binding *apisv1alpha1.APIBinding) (*Rotation, error) {
+	value, found := binding.Annotations[apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+
+	var rotation Rotation
+	if err := json.Unmarshal([]byte(value), &rotation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s annotation: %w", apisv1alpha1.InternalAPIBindingIdentityRotationAnnotationKey, err)
+	}
+	return &rotation, nil
+}
+
+// RetiredIdentity returns the identity hash the objects of the APIBinding bound with the given
+// identity hash were moved away from, if the identity rotation is not finished yet. Requests of the
+// APIExport owner still using the retired identity are served from the new identity.
+func RetiredIdentity(binding *apisv1alpha1.APIBinding, identityHash string) string {
+	rotation, err := GetRotation(binding)
+	if err != nil || rotation == nil || rotation.To != identityHash {
+		return ""
+	}
+	return rotation.From
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityrotation
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// KeyValue is a key of the storage with its value.
+type KeyValue struct {
+	Key         string
+	Value       []byte
+	ModRevision int64
+}
+
+// Store is the raw key-value storage of the objects, i.e. etcd. Objects are moved between identities
+// without decoding them, keeping their values as they are.
+type Store interface {
+	// List returns the keys with the given prefix, consistently at one revision, and that revision.
+	List(ctx context.Context, prefix string) ([]KeyValue, int64, error)
+	// Put writes the key unless it was modified after the given revision. Absent keys were never
+	// modified. A zero revision writes unconditionally.
+	Put(ctx context.Context, key string, value []byte, notModifiedAfter int64) error
+	// Delete deletes the key unless it was modified after the given revision. A zero revision
+	// deletes unconditionally.
+	Delete(ctx context.Context, key string, notModifiedAfter int64) error
+	// Revision returns the current revision of the storage.
+	Revision(ctx context.Context) (int64, error)
+}
+
+// listPageSize is the number of keys read from etcd at once.
+const listPageSize = 500
+
+// NewEtcdStore returns a Store reading and writing etcd.
+func NewEtcdStore(kv clientv3.KV) Store {
+	return &etcdStore{kv: kv}
+}
+
+type etcdStore struct {
+	kv clientv3.KV
+}
+
+func (s *etcdStore) List(ctx context.Context, prefix string) ([]KeyValue, int64, error) {
+	var ret []KeyValue
+	var rev int64
+	key, end := prefix, clientv3.GetPrefixRangeEnd(prefix)
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(listPageSize)}
+		if rev != 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := s.kv.Get(ctx, key, opts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		for _, kv := range resp.Kvs {
+			ret = append(ret, KeyValue{Key: string(kv.Key), Value: kv.Value, ModRevision: kv.ModRevision})
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			return ret, rev, nil
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+func (s *etcdStore) Put(ctx context.Context, key string, value []byte, notModifiedAfter int64) error {
+	if notModifiedAfter == 0 {
+		_, err := s.kv.Put(ctx, key, string(value))
+		return err
+	}
+	_, err := s.kv.Txn(ctx).If(
+		clientv3.Compare(clientv3.ModRevision(key), "<", notModifiedAfter+1),
+	).Then(
+		clientv3.OpPut(key, string(value)),
+	).Commit()
+	return err
+}
+
+func (s *etcdStore) Delete(ctx context.Context, key string, notModifiedAfter int64) error {
+	if notModifiedAfter == 0 {
+		_, err := s.kv.Delete(ctx, key)
+		return err
+	}
+	_, err := s.kv.Txn(ctx).If(
+		clientv3.Compare(clientv3.ModRevision(key), "<", notModifiedAfter+1),
+	).Then(
+		clientv3.OpDelete(key),
+	).Commit()
+	return err
+}
+
+func (s *etcdStore) Revision(ctx context.Context) (int64, error) {
+	resp, err := s.kv.Get(ctx, "/", clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
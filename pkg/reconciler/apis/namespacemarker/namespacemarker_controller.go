@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacemarker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-namespace-marker"
+
+	// MarkerAnnotation marks ConfigMaps created by this controller. Only those are deleted when no
+	// permission claim selects them anymore.
+	MarkerAnnotation = "apis.kcp.io/namespace-marker"
+
+	DescriptionAnnotation = "kubernetes.io/description"
+	Description           = "Marks the namespace for service providers that claimed this ConfigMap, " +
+		"to let them provision objects in new namespaces. It is maintained by kcp."
+)
+
+// NewController returns a new controller that creates the namespace marker ConfigMap in every
+// namespace selected by an accepted permission claim of an APIBinding in the same workspace, and
+// deletes it when no claim selects it anymore.
+func NewController(
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
+	configMapInformer kcpcorev1informers.ConfigMapClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
+
+	c := &controller{
+		queue: queue,
+
+		getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+			return namespaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		listNamespaces: func(clusterName logicalcluster.Name) ([]*corev1.Namespace, error) {
+			return namespaceInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return apiBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+		getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+			return configMapInformer.Lister().Cluster(clusterName).ConfigMaps(namespace).Get(name)
+		},
+		createConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+			_, err := kubeClusterClient.Cluster(clusterName.Path()).CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+			return err
+		},
+		deleteConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+			return kubeClusterClient.Cluster(clusterName.Path()).CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+	}
+
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNamespace,
+		UpdateFunc: func(_, obj interface{}) { c.enqueueNamespace(obj) },
+	})
+
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			cm, ok := obj.(*corev1.ConfigMap)
+			return ok && cm.Name == apisv1alpha1.NamespaceMarkerConfigMapName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			DeleteFunc: c.enqueueConfigMap,
+		},
+	})
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueAPIBinding,
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj) },
+		DeleteFunc: c.enqueueAPIBinding,
+	})
+
+	return c, nil
+}
+
+// controller maintains the namespace marker ConfigMaps service providers opt into through
+// permission claims, see apisv1alpha1.NamespaceMarkerConfigMapName.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getNamespace    func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error)
+	listNamespaces  func(clusterName logicalcluster.Name) ([]*corev1.Namespace, error)
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getConfigMap    func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+	createConfigMap func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error
+	deleteConfigMap func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error
+}
+
+func (c *controller) enqueueNamespace(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Namespace")
+	c.queue.Add(key)
+}
+
+func (c *controller) enqueueConfigMap(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a ConfigMap, but is %T", obj))
+		return
+	}
+
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(cm).String(), "", cm.Namespace)
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Namespace because of ConfigMap")
+	c.queue.Add(key)
+}
+
+// enqueueAPIBinding enqueues all namespaces of the workspace of the APIBinding, as its claims
+// might select other namespaces now.
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj))
+		return
+	}
+
+	clusterName := logicalcluster.From(binding)
+	namespaces, err := c.listNamespaces(clusterName)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, ns := range namespaces {
+		key := kcpcache.ToClusterAwareKey(clusterName.String(), "", ns.Name)
+		logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+		logger.V(4).Info("queueing Namespace because of APIBinding", "apibinding", binding.Name)
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+
+	ns, err := c.getNamespace(clusterName, name)
+	if apierrors.IsNotFound(err) {
+		return nil // namespace deleted before we handled it, the marker goes with it
+	}
+	if err != nil {
+		return err
+	}
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		return nil
+	}
+
+	bindings, err := c.listAPIBindings(clusterName)
+	if err != nil {
+		return err
+	}
+	wanted := false
+	for _, binding := range bindings {
+		if binding.DeletionTimestamp == nil && ClaimsMarker(binding, name) {
+			wanted = true
+			break
+		}
+	}
+
+	cm, err := c.getConfigMap(clusterName, name, apisv1alpha1.NamespaceMarkerConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case wanted && !exists:
+		logger.V(2).Info("creating namespace marker ConfigMap")
+		err := c.createConfigMap(ctx, clusterName, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: name,
+				Name:      apisv1alpha1.NamespaceMarkerConfigMapName,
+				Annotations: map[string]string{
+					MarkerAnnotation:      "true",
+					DescriptionAnnotation: Description,
+				},
+			},
+		})
+		// don't retry a create if the namespace doesn't exist or is terminating
+		if apierrors.IsNotFound(err) || apierrors.IsAlreadyExists(err) || apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+			return nil
+		}
+		return err
+	case !wanted && exists && cm.Annotations[MarkerAnnotation] == "true":
+		logger.V(2).Info("deleting namespace marker ConfigMap")
+		if err := c.deleteConfigMap(ctx, clusterName, name, apisv1alpha1.NamespaceMarkerConfigMapName); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimsMarker returns whether the APIBinding accepted a permission claim on configmaps with a
+// resource selector naming the namespace marker ConfigMap in the given namespace. Claims of all
+// configmaps or of other names do not opt into the marker.
+func ClaimsMarker(binding *apisv1alpha1.APIBinding, namespace string) bool {
+	for _, claim := range binding.Spec.PermissionClaims {
+		if claim.State != apisv1alpha1.ClaimAccepted || claim.Group != "" || claim.Resource != "configmaps" {
+			continue
+		}
+		marker := &metav1.ObjectMeta{Namespace: namespace, Name: apisv1alpha1.NamespaceMarkerConfigMapName}
+		for _, selector := range claim.ResourceSelector {
+			if selector.Name != marker.Name {
+				continue
+			}
+			if ok, err := permissionclaims.SelectorMatches(selector, marker); err == nil && ok {
+				return true
+			}
+		}
+	}
+	return false
+}
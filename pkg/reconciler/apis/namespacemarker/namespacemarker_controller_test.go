@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacemarker
+
+import (
+	"context"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func binding(state apisv1alpha1.AcceptablePermissionClaimState, resource string, selectors ...apisv1alpha1.ResourceSelector) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+		Spec: apisv1alpha1.APIBindingSpec{
+			PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{{
+				PermissionClaim: apisv1alpha1.PermissionClaim{
+					GroupResource:    apisv1alpha1.GroupResource{Resource: resource},
+					ResourceSelector: selectors,
+				},
+				State: state,
+			}},
+		},
+	}
+}
+
+func TestClaimsMarker(t *testing.T) {
+	marker := apisv1alpha1.ResourceSelector{Name: apisv1alpha1.NamespaceMarkerConfigMapName}
+
+	tests := map[string]struct {
+		binding *apisv1alpha1.APIBinding
+		want    bool
+	}{
+		"accepted marker claim": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "configmaps", marker),
+			want:    true,
+		},
+		"rejected marker claim": {
+			binding: binding(apisv1alpha1.ClaimRejected, "configmaps", marker),
+		},
+		"other name": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "configmaps", apisv1alpha1.ResourceSelector{Name: "settings"}),
+		},
+		"other resource": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "secrets", apisv1alpha1.ResourceSelector{Name: apisv1alpha1.NamespaceMarkerConfigMapName}),
+		},
+		"all configmaps": {
+			binding: func() *apisv1alpha1.APIBinding {
+				b := binding(apisv1alpha1.ClaimAccepted, "configmaps")
+				b.Spec.PermissionClaims[0].All = true
+				return b
+			}(),
+		},
+		"matching namespace wildcard": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "configmaps", apisv1alpha1.ResourceSelector{Name: apisv1alpha1.NamespaceMarkerConfigMapName, Namespace: "team-*"}),
+			want:    true,
+		},
+		"other namespace": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "configmaps", apisv1alpha1.ResourceSelector{Name: apisv1alpha1.NamespaceMarkerConfigMapName, Namespace: "default"}),
+		},
+		"label selector the marker does not match": {
+			binding: binding(apisv1alpha1.ClaimAccepted, "configmaps", apisv1alpha1.ResourceSelector{
+				Name:          apisv1alpha1.NamespaceMarkerConfigMapName,
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, ClaimsMarker(tt.binding, "team-a"))
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	marker := apisv1alpha1.ResourceSelector{Name: apisv1alpha1.NamespaceMarkerConfigMapName}
+	active := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	managed := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        apisv1alpha1.NamespaceMarkerConfigMapName,
+		Annotations: map[string]string{MarkerAnnotation: "true"},
+	}}
+
+	tests := map[string]struct {
+		namespace *corev1.Namespace
+		bindings  []*apisv1alpha1.APIBinding
+		configMap *corev1.ConfigMap
+
+		wantCreated *corev1.ConfigMap
+		wantDeleted bool
+	}{
+		"namespace not found": {
+			bindings: []*apisv1alpha1.APIBinding{binding(apisv1alpha1.ClaimAccepted, "configmaps", marker)},
+		},
+		"namespace terminating": {
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+			},
+			bindings: []*apisv1alpha1.APIBinding{binding(apisv1alpha1.ClaimAccepted, "configmaps", marker)},
+		},
+		"not claimed": {
+			namespace: active,
+			bindings:  []*apisv1alpha1.APIBinding{binding(apisv1alpha1.ClaimRejected, "configmaps", marker)},
+		},
+		"claimed, missing marker": {
+			namespace: active,
+			bindings:  []*apisv1alpha1.APIBinding{binding(apisv1alpha1.ClaimAccepted, "configmaps", marker)},
+			wantCreated: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      apisv1alpha1.NamespaceMarkerConfigMapName,
+					Annotations: map[string]string{
+						MarkerAnnotation:      "true",
+						DescriptionAnnotation: Description,
+					},
+				},
+			},
+		},
+		"claimed by a deleted binding": {
+			namespace: active,
+			bindings: []*apisv1alpha1.APIBinding{func() *apisv1alpha1.APIBinding {
+				b := binding(apisv1alpha1.ClaimAccepted, "configmaps", marker)
+				b.DeletionTimestamp = &metav1.Time{}
+				return b
+			}()},
+		},
+		"claimed, existing marker": {
+			namespace: active,
+			bindings:  []*apisv1alpha1.APIBinding{binding(apisv1alpha1.ClaimAccepted, "configmaps", marker)},
+			configMap: managed,
+		},
+		"not claimed anymore": {
+			namespace:   active,
+			configMap:   managed,
+			wantDeleted: true,
+		},
+		"not claimed, ConfigMap not created by kcp": {
+			namespace: active,
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: apisv1alpha1.NamespaceMarkerConfigMapName}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created *corev1.ConfigMap
+			var deleted bool
+			c := &controller{
+				getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+					require.Equal(t, logicalcluster.Name("root"), clusterName)
+					if tt.namespace == nil {
+						return nil, apierrors.NewNotFound(corev1.Resource("namespaces"), name)
+					}
+					return tt.namespace, nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return tt.bindings, nil
+				},
+				getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+					if tt.configMap == nil {
+						return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+					}
+					return tt.configMap, nil
+				},
+				createConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+					created = cm
+					return nil
+				},
+				deleteConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+					require.Equal(t, "default", namespace)
+					require.Equal(t, apisv1alpha1.NamespaceMarkerConfigMapName, name)
+					deleted = true
+					return nil
+				},
+			}
+
+			key := kcpcache.ToClusterAwareKey("root", "", "default")
+			require.NoError(t, c.process(context.Background(), key))
+			require.Equal(t, tt.wantCreated, created)
+			require.Equal(t, tt.wantDeleted, deleted)
+		})
+	}
+}
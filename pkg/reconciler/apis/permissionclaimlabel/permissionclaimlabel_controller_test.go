@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaimlabel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	reconcilertesting "github.com/kcp-dev/kcp/pkg/reconciler/testing"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/apis/v1alpha1"
+)
+
+func TestRequeueBackoff(t *testing.T) {
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	require.NoError(t, indexer.Add(&apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer"},
+		},
+	}))
+	key := "consumer|widgets"
+
+	clock := clocktesting.NewFakeClock(time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC))
+	queue := reconcilertesting.NewQueue(clock)
+	failures := 3
+	c := &controller{
+		queue:             queue,
+		apiBindingsLister: apisv1alpha1listers.NewAPIBindingClusterLister(indexer),
+		commit: func(ctx context.Context, old, new *Resource) error {
+			if failures > 0 {
+				failures--
+				return errors.New("conflict")
+			}
+			return nil
+		},
+	}
+
+	queue.Add(key)
+	for i, wantDelay := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond} {
+		require.Equal(t, 1, queue.Len(), "attempt %d", i)
+		require.True(t, c.processNextWorkItem(context.Background()))
+		require.Equal(t, i+1, queue.NumRequeues(key))
+
+		delay, ok := queue.Waiting(key)
+		require.True(t, ok)
+		require.Equal(t, wantDelay, delay)
+
+		clock.Step(delay - time.Millisecond)
+		require.Equal(t, 0, queue.Len(), "attempt %d should back off", i)
+		clock.Step(time.Millisecond)
+	}
+
+	require.Equal(t, 1, queue.Len())
+	require.True(t, c.processNextWorkItem(context.Background()))
+	require.Equal(t, 0, queue.NumRequeues(key), "backoff should be reset after success")
+	require.Equal(t, []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}, queue.Requeues(key))
+	_, ok := queue.Waiting(key)
+	require.False(t, ok)
+}
@@ -214,6 +214,18 @@ func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.API
 		apiBinding.Status.AppliedPermissionClaims = append(apiBinding.Status.AppliedPermissionClaims, acceptedClaimsMap[s])
 	}
 
+	// forget the usage of claims that are not applied anymore
+	var claimsUsage []apisv1alpha1.PermissionClaimUsage
+	for _, usage := range apiBinding.Status.PermissionClaimsUsage {
+		for _, claim := range apiBinding.Status.AppliedPermissionClaims {
+			if claim.GroupResource == usage.GroupResource && claim.IdentityHash == usage.IdentityHash {
+				claimsUsage = append(claimsUsage, usage)
+				break
+			}
+		}
+	}
+	apiBinding.Status.PermissionClaimsUsage = claimsUsage
+
 	if len(allErrs) > 0 {
 		i := len(allErrs)
 		if i > 10 {
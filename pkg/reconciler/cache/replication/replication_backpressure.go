@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	compbasemetrics "k8s.io/component-base/metrics"
+)
+
+const (
+	// minBatchSize and maxBatchSize bound the number of keys a worker replicates concurrently.
+	minBatchSize = 1
+	maxBatchSize = 32
+
+	// minPushBackoff and maxPushBackoff bound the pause of the replication after the cache server
+	// throttled a push, unless the cache server asks for a longer delay.
+	minPushBackoff = 100 * time.Millisecond
+	maxPushBackoff = 30 * time.Second
+)
+
+var (
+	throttledPushesTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "replication_throttled_pushes_total",
+			Help:           "Number of pushes of replicated objects the cache server rejected with 429 Too Many Requests.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"priority"},
+	)
+	replicationBatchSize = compbasemetrics.NewGauge(
+		&compbasemetrics.GaugeOpts{
+			Name:           "replication_batch_size",
+			Help:           "Number of objects a replication worker currently pushes to the cache server concurrently.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+	)
+	replicationQueueDepth = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Name:           "replication_queue_depth",
+			Help:           "Number of objects waiting to be replicated to the cache server, by priority.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"priority"},
+	)
+)
+
+// pushBackPressure adapts the replication to the load of the cache server. The number of keys
+// a worker replicates concurrently grows by one with every fully used batch pushed without
+// throttling, and is halved when the cache server responds with 429 Too Many Requests. A
+// throttled push also pauses all workers with exponential backoff, or for the delay the cache
+// server asks for if longer, instead of retrying right away.
+type pushBackPressure struct {
+	now    func() time.Time
+	jitter float64
+
+	lock        sync.Mutex
+	batch       int
+	throttles   int
+	pausedUntil time.Time
+}
+
+func newPushBackPressure() *pushBackPressure {
+	replicationBatchSize.Set(minBatchSize)
+	return &pushBackPressure{
+		now:    time.Now,
+		jitter: 0.2,
+		batch:  minBatchSize,
+	}
+}
+
+// batchSize returns the number of keys a worker should replicate concurrently.
+func (b *pushBackPressure) batchSize() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.batch
+}
+
+// pause returns how long pushes are paused because of throttling.
+func (b *pushBackPressure) pause() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if d := b.pausedUntil.Sub(b.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// succeeded records a batch of n keys replicated without throttling.
+func (b *pushBackPressure) succeeded(n int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.throttles = 0
+	if n >= b.batch && b.batch < maxBatchSize {
+		b.batch++
+		replicationBatchSize.Set(float64(b.batch))
+	}
+}
+
+// throttled records a push the cache server throttled with the given error, and returns the
+// delay after which the throttled key should be retried.
+func (b *pushBackPressure) throttled(err error, priority replicationPriority) time.Duration {
+	throttledPushesTotal.WithLabelValues(priority.String()).Inc()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.batch = b.batch / 2; b.batch < minBatchSize {
+		b.batch = minBatchSize
+	}
+	replicationBatchSize.Set(float64(b.batch))
+
+	delay := maxPushBackoff
+	if b.throttles < 16 {
+		if d := minPushBackoff << b.throttles; d < maxPushBackoff {
+			delay = d
+		}
+	}
+	b.throttles++
+	if b.jitter > 0 {
+		delay = wait.Jitter(delay, b.jitter)
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && time.Duration(seconds)*time.Second > delay {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	if until := b.now().Add(delay); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	return delay
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestPushBackPressure(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newPushBackPressure()
+	b.now = func() time.Time { return now }
+	b.jitter = 0
+
+	require.Equal(t, minBatchSize, b.batchSize())
+	require.Zero(t, b.pause())
+
+	t.Log("Fully used batches grow the batch size by one")
+	for i := 0; i < 7; i++ {
+		b.succeeded(b.batchSize())
+	}
+	require.Equal(t, 8, b.batchSize())
+	b.succeeded(3)
+	require.Equal(t, 8, b.batchSize(), "a partially used batch does not grow the batch size")
+
+	t.Log("Throttling halves the batch size and backs off exponentially")
+	throttled := apierrors.NewTooManyRequests("slow down", 0)
+	require.Equal(t, 100*time.Millisecond, b.throttled(throttled, priorityHigh))
+	require.Equal(t, 4, b.batchSize())
+	require.Equal(t, 200*time.Millisecond, b.throttled(throttled, priorityHigh))
+	require.Equal(t, 2, b.batchSize())
+	require.Equal(t, 200*time.Millisecond, b.pause())
+	now = now.Add(50 * time.Millisecond)
+	require.Equal(t, 150*time.Millisecond, b.pause())
+
+	t.Log("The delay asked for by the cache server is honored")
+	require.Equal(t, 5*time.Second, b.throttled(apierrors.NewTooManyRequests("slow down", 5), priorityLow))
+	require.Equal(t, minBatchSize, b.batchSize())
+	require.Equal(t, 5*time.Second, b.pause())
+
+	t.Log("The back-off is capped")
+	for i := 0; i < 20; i++ {
+		b.throttled(errors.New("throttled"), priorityLow)
+	}
+	require.Equal(t, maxPushBackoff, b.throttled(throttled, priorityLow))
+	require.Equal(t, minBatchSize, b.batchSize())
+
+	t.Log("Success resets the back-off")
+	now = now.Add(time.Minute)
+	require.Zero(t, b.pause())
+	b.succeeded(1)
+	require.Equal(t, 2, b.batchSize())
+	require.Equal(t, 100*time.Millisecond, b.throttled(throttled, priorityHigh))
+}
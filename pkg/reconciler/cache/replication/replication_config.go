@@ -0,0 +1,299 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	kcpdynamicinformer "github.com/kcp-dev/client-go/dynamic/dynamicinformer"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+// replicationConfigsKey is the only key of the config queue. All ReplicationConfigs are reconciled at once.
+const replicationConfigsKey = "replicationconfigs"
+
+func (c *controller) startConfigWorker(ctx context.Context) {
+	for c.processNextConfigWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextConfigWorkItem(ctx context.Context) bool {
+	key, quit := c.configQueue.Get()
+	if quit {
+		return false
+	}
+	defer c.configQueue.Done(key)
+
+	if err := c.reconcileReplicationConfigs(ctx); err != nil {
+		runtime.HandleError(fmt.Errorf("%v failed with: %w", key, err))
+		c.configQueue.AddRateLimited(key)
+		return true
+	}
+	c.configQueue.Forget(key)
+	return true
+}
+
+// reconcileReplicationConfigs starts replicating the resources listed by the ReplicationConfigs, and
+// updates the label selectors and conflict policies of the resources already replicated.
+//
+// Resources not served by the shard or by the cache server are skipped until the ReplicationConfigs
+// change. Objects of resources not listed anymore are no longer replicated, which deletes their cached
+// copies, and the informers of the resources are stopped once all cached copies are deleted.
+func (c *controller) reconcileReplicationConfigs(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	configs, err := c.listReplicationConfigs()
+	if err != nil {
+		return err
+	}
+	wanted, errs := configuredResources(configs)
+	for _, err := range errs {
+		logger.Error(err, "ignoring invalid resource of ReplicationConfig")
+	}
+
+	c.lock.RLock()
+	current := make(map[schema.GroupVersionResource]replicatedGVR, len(c.gvrs))
+	for gvr, info := range c.gvrs {
+		current[gvr] = info
+	}
+	c.lock.RUnlock()
+
+	for gvr, config := range wanted {
+		info, found := current[gvr]
+		if found && !info.configured {
+			if info.conflictPolicy == config.conflictPolicy {
+				continue
+			}
+			logger.Info("changing conflict policy of resource always replicated", "resource", gvr.String(), "policy", config.conflictPolicy)
+			info.conflictPolicy = config.conflictPolicy
+			c.setGVR(gvr, info)
+			c.enqueueAll(gvr, info)
+			continue
+		}
+		if found && info.selectors == selectorsString(config.selectors) && info.conflictPolicy == config.conflictPolicy {
+			continue
+		}
+		if !found {
+			if err := c.checkServed(ctx, gvr); apierrors.IsNotFound(err) {
+				logger.Error(err, "ignoring resource of ReplicationConfig that is not served by the shard or by the cache server", "resource", gvr.String())
+				continue
+			} else if err != nil {
+				return fmt.Errorf("failed to check whether %s is served: %w", gvr, err)
+			}
+			if info, err = c.newConfiguredGVR(ctx, gvr); err != nil {
+				return err
+			}
+			logger.Info("starting to replicate resource", "resource", gvr.String())
+		}
+		info.filter = selectorsFilter(config.selectors)
+		info.selectors = selectorsString(config.selectors)
+		info.conflictPolicy = config.conflictPolicy
+		c.setGVR(gvr, info)
+		c.enqueueAll(gvr, info)
+	}
+
+	for gvr, info := range current {
+		if _, found := wanted[gvr]; found {
+			continue
+		}
+		if !info.configured {
+			if info.conflictPolicy != "" {
+				logger.Info("resetting conflict policy of resource", "resource", gvr.String())
+				info.conflictPolicy = ""
+				c.setGVR(gvr, info)
+				c.enqueueAll(gvr, info)
+			}
+			continue
+		}
+		if info.selectors == "" {
+			if c.hasCachedCopies(info) {
+				continue
+			}
+			logger.Info("stopping the informers of resource", "resource", gvr.String())
+			if info.stop != nil {
+				info.stop()
+			}
+			c.lock.Lock()
+			delete(c.gvrs, gvr)
+			c.lock.Unlock()
+			continue
+		}
+		logger.Info("stopping to replicate resource", "resource", gvr.String())
+		info.filter = func(*unstructured.Unstructured) bool { return false }
+		info.selectors = ""
+		info.conflictPolicy = ""
+		c.setGVR(gvr, info)
+		c.enqueueAll(gvr, info)
+	}
+	return nil
+}
+
+// newConfiguredGVR creates and starts the local and global informers of a resource listed by ReplicationConfigs.
+func (c *controller) newConfiguredGVR(ctx context.Context, gvr schema.GroupVersionResource) (replicatedGVR, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	info := replicatedGVR{
+		configured: true,
+		local:      kcpdynamicinformer.NewFilteredDynamicInformer(c.dynamicLocalClient, gvr, 0, cache.Indexers{}, nil).Informer(),
+		global:     kcpdynamicinformer.NewFilteredDynamicInformer(c.dynamicCacheClient, gvr, 0, cache.Indexers{}, nil).Informer(),
+		stop:       cancel,
+	}
+	if err := c.setUpInformers(gvr, info); err != nil {
+		cancel()
+		return replicatedGVR{}, err
+	}
+	go info.local.Run(ctx.Done())
+	go info.global.Run(ctx.Done())
+	return info, nil
+}
+
+// hasCachedCopies returns true if the cache server still holds objects of this shard of a resource.
+func (c *controller) hasCachedCopies(info replicatedGVR) bool {
+	for _, obj := range info.global.GetStore().List() {
+		if u, ok := obj.(*unstructured.Unstructured); ok && u.GetAnnotations()[genericrequest.AnnotationKey] == c.shardName {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *controller) setGVR(gvr schema.GroupVersionResource, info replicatedGVR) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gvrs[gvr] = info
+}
+
+// enqueueAll enqueues the local objects and the cached objects of this shard of a resource.
+func (c *controller) enqueueAll(gvr schema.GroupVersionResource, info replicatedGVR) {
+	for _, obj := range info.local.GetStore().List() {
+		if IsNoSystemClusterName(obj) {
+			c.enqueueCacheObject(obj, gvr)
+		}
+	}
+	for _, obj := range info.global.GetStore().List() {
+		if u, ok := obj.(*unstructured.Unstructured); ok && u.GetAnnotations()[genericrequest.AnnotationKey] == c.shardName {
+			c.enqueueCacheObject(obj, gvr)
+		}
+	}
+}
+
+// resourceConfig is the configuration of a resource merged from all ReplicationConfigs listing it.
+type resourceConfig struct {
+	// selectors are the label selectors of the replicated objects. Empty means that all objects are replicated.
+	selectors []labels.Selector
+	// conflictPolicy is the strictest conflict policy of the ReplicationConfigs. Empty means LocalWins.
+	conflictPolicy corev1alpha1.ConflictPolicy
+}
+
+// conflictPolicyStrictness orders the conflict policies. The strictest one wins if ReplicationConfigs disagree.
+// LocalWins is represented by the empty policy, the default.
+var conflictPolicyStrictness = map[corev1alpha1.ConflictPolicy]int{
+	"":                                   0,
+	corev1alpha1.ConflictPolicyCacheWins: 1,
+	corev1alpha1.ConflictPolicyFail:      2,
+}
+
+// configuredResources returns the configuration of the resources listed by the given ReplicationConfigs.
+// Resources with invalid selectors or conflict policies are skipped.
+func configuredResources(configs []*corev1alpha1.ReplicationConfig) (map[schema.GroupVersionResource]*resourceConfig, []error) {
+	var errs []error
+	resources := map[schema.GroupVersionResource]*resourceConfig{}
+	everything := map[schema.GroupVersionResource]bool{}
+	for _, config := range configs {
+		for i, r := range config.Spec.Resources {
+			gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+			if gvr.Version == "" || gvr.Resource == "" {
+				errs = append(errs, fmt.Errorf("ReplicationConfig %s: spec.resources[%d]: version and resource must not be empty", config.Name, i))
+				continue
+			}
+			policy := r.ConflictPolicy
+			if policy == corev1alpha1.ConflictPolicyLocalWins {
+				policy = ""
+			}
+			if _, valid := conflictPolicyStrictness[policy]; !valid {
+				errs = append(errs, fmt.Errorf("ReplicationConfig %s: spec.resources[%d]: unknown conflict policy %q", config.Name, i, r.ConflictPolicy))
+				continue
+			}
+			var selector labels.Selector
+			if r.LabelSelector != nil {
+				var err error
+				if selector, err = metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
+					errs = append(errs, fmt.Errorf("ReplicationConfig %s: spec.resources[%d]: %w", config.Name, i, err))
+					continue
+				}
+			}
+
+			resource, found := resources[gvr]
+			if !found {
+				resource = &resourceConfig{conflictPolicy: policy}
+				resources[gvr] = resource
+			}
+			if conflictPolicyStrictness[policy] > conflictPolicyStrictness[resource.conflictPolicy] {
+				resource.conflictPolicy = policy
+			}
+			if selector == nil {
+				everything[gvr] = true
+				resource.selectors = nil
+			} else if !everything[gvr] {
+				resource.selectors = append(resource.selectors, selector)
+			}
+		}
+	}
+	return resources, errs
+}
+
+// selectorsFilter returns a filter accepting the objects matching any of the selectors, or all objects
+// if there are none.
+func selectorsFilter(selectors []labels.Selector) func(u *unstructured.Unstructured) bool {
+	if len(selectors) == 0 {
+		return nil
+	}
+	return func(u *unstructured.Unstructured) bool {
+		for _, s := range selectors {
+			if s.Matches(labels.Set(u.GetLabels())) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// selectorsString returns a canonical representation of the selectors. It is never empty.
+func selectorsString(selectors []labels.Selector) string {
+	if len(selectors) == 0 {
+		return "*"
+	}
+	strs := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		strs = append(strs, "{"+s.String()+"}")
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+var workspaces = schema.GroupVersionResource{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces"}
+
+func newReplicationConfig(name string, resources ...corev1alpha1.ReplicatedResource) *corev1alpha1.ReplicationConfig {
+	return &corev1alpha1.ReplicationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1alpha1.ReplicationConfigSpec{Resources: resources},
+	}
+}
+
+func newWorkspace(cluster, name, shard string, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("tenancy.kcp.io/v1alpha1")
+	u.SetKind("Workspace")
+	u.SetName(name)
+	u.SetLabels(labels)
+	annotations := map[string]string{logicalcluster.AnnotationKey: cluster}
+	if shard != "" {
+		annotations[genericrequest.AnnotationKey] = shard
+	}
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func TestConfiguredResources(t *testing.T) {
+	prod := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	shared := &metav1.LabelSelector{MatchLabels: map[string]string{"shared": "true"}}
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}}}
+
+	resources, errs := configuredResources([]*corev1alpha1.ReplicationConfig{
+		newReplicationConfig("a",
+			corev1alpha1.ReplicatedResource{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces", LabelSelector: prod, ConflictPolicy: corev1alpha1.ConflictPolicyFail},
+			corev1alpha1.ReplicatedResource{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apibindings", ConflictPolicy: corev1alpha1.ConflictPolicyCacheWins},
+			corev1alpha1.ReplicatedResource{Version: "v1", Resource: "configmaps", LabelSelector: invalid},
+		),
+		newReplicationConfig("b",
+			corev1alpha1.ReplicatedResource{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces", LabelSelector: shared, ConflictPolicy: corev1alpha1.ConflictPolicyCacheWins},
+			corev1alpha1.ReplicatedResource{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apibindings", LabelSelector: shared, ConflictPolicy: corev1alpha1.ConflictPolicyLocalWins},
+			corev1alpha1.ReplicatedResource{Version: "v1"},
+			corev1alpha1.ReplicatedResource{Version: "v1", Resource: "secrets", ConflictPolicy: "Unknown"},
+		),
+	})
+	apibindings := schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apibindings"}
+	require.Len(t, errs, 3)
+	require.Len(t, resources, 2)
+	require.Equal(t, "{env=prod},{shared=true}", selectorsString(resources[workspaces].selectors))
+	require.Equal(t, "*", selectorsString(resources[apibindings].selectors), "an unrestricted resource must not be restricted by other configs")
+	require.Equal(t, corev1alpha1.ConflictPolicyFail, resources[workspaces].conflictPolicy, "the strictest conflict policy must win")
+	require.Equal(t, corev1alpha1.ConflictPolicyCacheWins, resources[apibindings].conflictPolicy, "the strictest conflict policy must win")
+
+	filter := selectorsFilter(resources[workspaces].selectors)
+	require.True(t, filter(newWorkspace("root", "a", "", map[string]string{"env": "prod"})))
+	require.True(t, filter(newWorkspace("root", "b", "", map[string]string{"shared": "true"})))
+	require.False(t, filter(newWorkspace("root", "c", "", map[string]string{"env": "dev"})))
+	require.Nil(t, selectorsFilter(nil), "all objects are replicated without selectors")
+}
+
+func TestReconcileReplicationConfigs(t *testing.T) {
+	local := cache.NewSharedIndexInformer(&cache.ListWatch{}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+	global := cache.NewSharedIndexInformer(&cache.ListWatch{}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+	require.NoError(t, local.GetStore().Add(newWorkspace("root", "prod", "", map[string]string{"env": "prod"})))
+	require.NoError(t, local.GetStore().Add(newWorkspace("system:admin", "internal", "", nil)))
+	require.NoError(t, global.GetStore().Add(newWorkspace("root", "prod", "amber", map[string]string{"env": "prod"})))
+	require.NoError(t, global.GetStore().Add(newWorkspace("root", "other", "sapphire", nil)))
+
+	var configs []*corev1alpha1.ReplicationConfig
+	c := &controller{
+		shardName:   "amber",
+		queue:       newPriorityQueue(workqueue.DefaultControllerRateLimiter()),
+		configQueue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		gvrs: map[schema.GroupVersionResource]replicatedGVR{
+			corev1alpha1.SchemeGroupVersion.WithResource("shards"): {kind: "Shard", local: local, global: global},
+			workspaces: {configured: true, local: local, global: global, selectors: "*"},
+		},
+		listReplicationConfigs: func() ([]*corev1alpha1.ReplicationConfig, error) {
+			return configs, nil
+		},
+		checkServed: func(ctx context.Context, gvr schema.GroupVersionResource) error {
+			return apierrors.NewNotFound(gvr.GroupResource(), "")
+		},
+	}
+	stopped := false
+	info := c.gvrs[workspaces]
+	info.stop = func() { stopped = true }
+	c.gvrs[workspaces] = info
+	drain := func() []string {
+		var keys []string
+		for c.queue.Len() > 0 {
+			batch, _, _ := c.queue.Get(1)
+			c.queue.Done(batch[0])
+			keys = append(keys, batch...)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	prodKey, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(newWorkspace("root", "prod", "", nil))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Log("Restricting a configured resource requeues the objects of this shard")
+	configs = []*corev1alpha1.ReplicationConfig{newReplicationConfig("a",
+		corev1alpha1.ReplicatedResource{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}},
+		corev1alpha1.ReplicatedResource{Group: "core.kcp.io", Version: "v1alpha1", Resource: "shards", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}},
+	)}
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Equal(t, []string{"v1alpha1.workspaces.tenancy.kcp.io::" + prodKey}, drain())
+	require.False(t, c.gvrs[workspaces].filter(newWorkspace("root", "prod", "", map[string]string{"env": "prod"})))
+	require.Nil(t, c.gvrs[corev1alpha1.SchemeGroupVersion.WithResource("shards")].filter, "resources replicated always must not be restricted")
+
+	t.Log("Nothing is requeued if nothing changed")
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Empty(t, drain())
+
+	t.Log("The conflict policy of a resource replicated always is honored")
+	configs[0].Spec.Resources[1].ConflictPolicy = corev1alpha1.ConflictPolicyFail
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Equal(t, []string{"v1alpha1.shards.core.kcp.io::" + prodKey}, drain())
+	require.Equal(t, corev1alpha1.ConflictPolicyFail, c.gvrs[corev1alpha1.SchemeGroupVersion.WithResource("shards")].conflictPolicy)
+	require.Nil(t, c.gvrs[corev1alpha1.SchemeGroupVersion.WithResource("shards")].filter, "resources replicated always must not be restricted")
+
+	t.Log("Resources not served are skipped without an error")
+	configs[0].Spec.Resources = append(configs[0].Spec.Resources, corev1alpha1.ReplicatedResource{Version: "v1", Resource: "things"})
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Empty(t, drain())
+	require.NotContains(t, c.gvrs, schema.GroupVersionResource{Version: "v1", Resource: "things"})
+
+	t.Log("Removing a configured resource stops replicating its objects, and resets the conflict policies")
+	configs = nil
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Equal(t, []string{"v1alpha1.shards.core.kcp.io::" + prodKey, "v1alpha1.workspaces.tenancy.kcp.io::" + prodKey}, drain())
+	require.False(t, c.gvrs[workspaces].filter(newWorkspace("root", "prod", "", map[string]string{"env": "dev"})))
+	require.Empty(t, c.gvrs[corev1alpha1.SchemeGroupVersion.WithResource("shards")].conflictPolicy)
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.Empty(t, drain())
+	require.False(t, stopped, "the informers must keep running while cached copies exist")
+
+	t.Log("The informers of a removed resource are stopped once its cached copies are deleted")
+	require.NoError(t, global.GetStore().Delete(newWorkspace("root", "prod", "amber", nil)))
+	require.NoError(t, c.reconcileReplicationConfigs(ctx))
+	require.True(t, stopped)
+	require.NotContains(t, c.gvrs, workspaces)
+}
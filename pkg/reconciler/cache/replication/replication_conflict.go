@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+	compbasemetrics "k8s.io/component-base/metrics"
+
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+const (
+	// SourceResourceVersionAnnotationKey is set on cached copies to the resource version of the
+	// local object they have been replicated from.
+	SourceResourceVersionAnnotationKey = "internal.cache.kcp.io/source-resource-version"
+	// ContentHashAnnotationKey is set on cached copies to the hash of the content written by the
+	// replication. A cached copy whose content does not match the hash has been modified by
+	// someone else.
+	ContentHashAnnotationKey = "internal.cache.kcp.io/content-hash"
+	// ConflictAnnotationKey is set on cached copies to the JSON encoded Conflict last detected
+	// for them.
+	ConflictAnnotationKey = "internal.cache.kcp.io/replication-conflict"
+)
+
+// cacheOnlyAnnotations are only set on cached copies, never on local objects.
+var cacheOnlyAnnotations = sets.NewString(
+	genericrequest.AnnotationKey,
+	offload.PayloadAnnotationKey,
+	offload.PayloadChecksumAnnotationKey,
+	SourceResourceVersionAnnotationKey,
+	ContentHashAnnotationKey,
+	ConflictAnnotationKey,
+)
+
+var (
+	conflictsTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "replication_conflicts_total",
+			Help:           "Number of replicated objects whose copy in the cache server has been modified other than by the replication.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"resource", "policy"},
+	)
+	registerMetrics sync.Once
+)
+
+// Conflict is a modification of a cached copy other than by the replication, recorded in the
+// ConflictAnnotationKey annotation of the cached copy.
+type Conflict struct {
+	// Policy is the conflict policy applied.
+	Policy corev1alpha1.ConflictPolicy `json:"policy"`
+	// LocalResourceVersion is the resource version of the local object when the conflict was detected.
+	LocalResourceVersion string `json:"localResourceVersion"`
+	// SourceResourceVersion is the resource version of the local object the cached copy was last
+	// replicated from.
+	SourceResourceVersion string `json:"sourceResourceVersion"`
+	// LocalModified is true if the local object has been modified as well since it was last replicated.
+	LocalModified bool `json:"localModified"`
+	// DetectedAt is the time the conflict was detected.
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
+
+// sameAs returns whether both conflicts are about the same versions of the objects.
+func (c *Conflict) sameAs(other *Conflict) bool {
+	return other != nil &&
+		c.Policy == other.Policy &&
+		c.LocalResourceVersion == other.LocalResourceVersion &&
+		c.SourceResourceVersion == other.SourceResourceVersion
+}
+
+// detectConflict returns a conflict if cacheObject has been modified other than by the replication.
+// Cached copies without a content hash, i.e. replicated before conflicts were detected, never conflict.
+func detectConflict(cacheObject, localObject *unstructured.Unstructured) (*Conflict, error) {
+	annotations := cacheObject.GetAnnotations()
+	hash, found := annotations[ContentHashAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	current, err := contentHash(cacheObject)
+	if err != nil {
+		return nil, err
+	}
+	if current == hash {
+		return nil, nil
+	}
+
+	sourceRV := annotations[SourceResourceVersionAnnotationKey]
+	return &Conflict{
+		LocalResourceVersion:  localObject.GetResourceVersion(),
+		SourceResourceVersion: sourceRV,
+		LocalModified:         sourceRV != localObject.GetResourceVersion(),
+	}, nil
+}
+
+// recordedConflict returns the conflict recorded on cacheObject, or nil if there is none or it cannot be decoded.
+func recordedConflict(cacheObject *unstructured.Unstructured) *Conflict {
+	raw, found := cacheObject.GetAnnotations()[ConflictAnnotationKey]
+	if !found {
+		return nil
+	}
+	conflict := &Conflict{}
+	if err := json.Unmarshal([]byte(raw), conflict); err != nil {
+		return nil
+	}
+	return conflict
+}
+
+// setConflict records the conflict on cacheObject.
+func setConflict(cacheObject *unstructured.Unstructured, conflict *Conflict) error {
+	raw, err := json.Marshal(conflict)
+	if err != nil {
+		return err
+	}
+	setAnnotation(cacheObject, ConflictAnnotationKey, string(raw))
+	return nil
+}
+
+// stampReplicated records on cacheObject that it has been replicated from the local object with
+// the given resource version, with its current content. It must be called after all other changes.
+func stampReplicated(cacheObject *unstructured.Unstructured, sourceRV string) error {
+	hash, err := contentHash(cacheObject)
+	if err != nil {
+		return err
+	}
+	setAnnotation(cacheObject, SourceResourceVersionAnnotationKey, sourceRV)
+	setAnnotation(cacheObject, ContentHashAnnotationKey, hash)
+	return nil
+}
+
+// contentHash returns the hash of the replicated content of obj, i.e. its labels, its annotations
+// except the cache only ones, and all fields outside of the metadata. Other metadata fields are
+// maintained by the servers, and are not considered. Empty fields are not considered either, as
+// the cache server might return an empty status for objects written without.
+func contentHash(obj *unstructured.Unstructured) (string, error) {
+	content := make(map[string]interface{}, len(obj.Object)+1)
+	for k, v := range obj.Object {
+		if m, ok := v.(map[string]interface{}); k == "metadata" || v == nil || ok && len(m) == 0 {
+			continue
+		}
+		content[k] = v
+	}
+	if labels := obj.GetLabels(); len(labels) > 0 {
+		content["metadata.labels"] = labels
+	}
+	if annotations := withoutCacheOnlyAnnotations(obj.GetAnnotations()); len(annotations) > 0 {
+		content["metadata.annotations"] = annotations
+	}
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// adoptCachedCopy returns a copy of localObject with the labels, annotations and remaining fields
+// of cacheObject. The annotations only set on cached copies are not adopted.
+func adoptCachedCopy(localObject, cacheObject *unstructured.Unstructured) *unstructured.Unstructured {
+	adopted := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range cacheObject.Object {
+		if k != "metadata" {
+			adopted.Object[k] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+	if meta, found := localObject.Object["metadata"]; found {
+		adopted.Object["metadata"] = runtime.DeepCopyJSONValue(meta)
+	}
+	adopted.SetLabels(cacheObject.GetLabels())
+	adopted.SetAnnotations(withoutCacheOnlyAnnotations(cacheObject.GetAnnotations()))
+	return adopted
+}
+
+// withoutCacheOnlyAnnotations returns the annotations except the cache only ones, or nil if there are none.
+func withoutCacheOnlyAnnotations(annotations map[string]string) map[string]string {
+	var ret map[string]string
+	for k, v := range annotations {
+		if cacheOnlyAnnotations.Has(k) {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]string, len(annotations))
+		}
+		ret[k] = v
+	}
+	return ret
+}
+
+func setAnnotation(obj *unstructured.Unstructured, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+func removeAnnotation(obj *unstructured.Unstructured, key string) {
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[key]; !found {
+		return
+	}
+	delete(annotations, key)
+	obj.SetAnnotations(annotations)
+}
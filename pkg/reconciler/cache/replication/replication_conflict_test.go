@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
+)
+
+func TestContentHash(t *testing.T) {
+	newObject := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Elephant",
+			"metadata": map[string]interface{}{
+				"name":            "dumbo",
+				"resourceVersion": "42",
+				"labels":          map[string]interface{}{"a": "b"},
+				"annotations":     map[string]interface{}{"c": "d"},
+			},
+			"spec": map[string]interface{}{"color": "pink"},
+		}}
+	}
+	hash := func(u *unstructured.Unstructured) string {
+		t.Helper()
+		h, err := contentHash(u)
+		require.NoError(t, err)
+		return h
+	}
+	original := hash(newObject())
+
+	tests := map[string]struct {
+		modify func(u *unstructured.Unstructured)
+		equal  bool
+	}{
+		"server maintained metadata": {
+			modify: func(u *unstructured.Unstructured) {
+				u.SetResourceVersion("43")
+				u.SetGeneration(2)
+			},
+			equal: true,
+		},
+		"cache only annotations": {
+			modify: func(u *unstructured.Unstructured) {
+				WithShardName(WithReplicatedFrom(u, "42"), "amber")
+				WithAnnotation(u, offload.PayloadAnnotationKey, "ref")
+			},
+			equal: true,
+		},
+		"empty status": {
+			modify: func(u *unstructured.Unstructured) { u.Object["status"] = map[string]interface{}{} },
+			equal:  true,
+		},
+		"spec": {
+			modify: func(u *unstructured.Unstructured) { WithChange(u, []string{"spec", "color"}, "grey") },
+		},
+		"labels": {
+			modify: func(u *unstructured.Unstructured) { WithLabel(u, "a", "c") },
+		},
+		"annotations": {
+			modify: func(u *unstructured.Unstructured) { WithAnnotation(u, "e", "f") },
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := newObject()
+			tt.modify(u)
+			require.Equal(t, tt.equal, hash(u) == original)
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
@@ -28,18 +30,26 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 
 	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+	cacheclientreplication "github.com/kcp-dev/kcp/pkg/cache/client/replication"
 	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
@@ -59,19 +69,42 @@ const (
 // The replicated object will be placed under the same cluster as the original object.
 // In addition to that, all replicated objects will be placed under the shard taken from the shardName argument.
 // For example: shards/{shardName}/clusters/{clusterName}/apis/apis.kcp.io/v1alpha1/apiexports.
+//
+// If offloader is not nil, the payload of big objects is stored in its blob store, and the global informers
+// are set up to resolve offloaded objects. This has to happen before the global informers are started.
+//
+// On top of the resources replicated always, the resources listed by the ReplicationConfigs of the root
+// workspace are replicated, watched through dynamic informers of the given local and cache clients.
 func NewController(
 	shardName string,
+	dynamicLocalClient kcpdynamic.ClusterInterface,
 	dynamicCacheClient kcpdynamic.ClusterInterface,
 	localKcpInformers kcpinformers.SharedInformerFactory,
 	globalKcpInformers kcpinformers.SharedInformerFactory,
 	localKubeInformers kcpkubernetesinformers.SharedInformerFactory,
 	globalKubeInformers kcpkubernetesinformers.SharedInformerFactory,
+	offloader *offload.Offloader,
+	stream *cacheclientreplication.Stream,
 ) (*controller, error) {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(conflictsTotal)
+		legacyregistry.MustRegister(throttledPushesTotal)
+		legacyregistry.MustRegister(replicationBatchSize)
+		legacyregistry.MustRegister(replicationQueueDepth)
+	})
+
 	c := &controller{
 		shardName:          shardName,
-		queue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		queue:              newPriorityQueue(ratelimiter.For(ControllerName)),
+		backPressure:       newPushBackPressure(),
+		configQueue:        workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName+"-config"),
+		dynamicLocalClient: dynamicLocalClient,
 		dynamicCacheClient: dynamicCacheClient,
+		offloader:          offloader,
+		stream:             stream,
+		lag:                newLagTracker(),
 
+		// keep in sync with the informers of offload.KcpInformers and offload.KubeInformers.
 		gvrs: map[schema.GroupVersionResource]replicatedGVR{
 			apisv1alpha1.SchemeGroupVersion.WithResource("apiexports"): {
 				kind:   "APIExport",
@@ -88,6 +121,11 @@ func NewController(
 				local:  localKcpInformers.Apis().V1alpha1().APIConversions().Informer(),
 				global: globalKcpInformers.Apis().V1alpha1().APIConversions().Informer(),
 			},
+			apisv1alpha1.SchemeGroupVersion.WithResource("defaultapibindings"): {
+				kind:   "DefaultAPIBinding",
+				local:  localKcpInformers.Apis().V1alpha1().DefaultAPIBindings().Informer(),
+				global: globalKcpInformers.Apis().V1alpha1().DefaultAPIBindings().Informer(),
+			},
 			admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"): {
 				kind:   "MutatingWebhookConfiguration",
 				local:  localKubeInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
@@ -103,6 +141,11 @@ func NewController(
 				local:  localKcpInformers.Core().V1alpha1().Shards().Informer(),
 				global: globalKcpInformers.Core().V1alpha1().Shards().Informer(),
 			},
+			corev1alpha1.SchemeGroupVersion.WithResource("replicationconfigs"): {
+				kind:   "ReplicationConfig",
+				local:  localKcpInformers.Core().V1alpha1().ReplicationConfigs().Informer(),
+				global: globalKcpInformers.Core().V1alpha1().ReplicationConfigs().Informer(),
+			},
 			corev1alpha1.SchemeGroupVersion.WithResource("logicalclusters"): {
 				kind: "LogicalCluster",
 				filter: func(u *unstructured.Unstructured) bool {
@@ -116,11 +159,21 @@ func NewController(
 				local:  localKcpInformers.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
 				global: globalKcpInformers.Tenancy().V1alpha1().WorkspaceTypes().Informer(),
 			},
+			tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetemplates"): {
+				kind:   "WorkspaceTemplate",
+				local:  localKcpInformers.Tenancy().V1alpha1().WorkspaceTemplates().Informer(),
+				global: globalKcpInformers.Tenancy().V1alpha1().WorkspaceTemplates().Informer(),
+			},
 			workloadv1alpha1.SchemeGroupVersion.WithResource("synctargets"): {
 				kind:   "SyncTarget",
 				local:  localKcpInformers.Workload().V1alpha1().SyncTargets().Informer(),
 				global: globalKcpInformers.Workload().V1alpha1().SyncTargets().Informer(),
 			},
+			workloadv1alpha1.SchemeGroupVersion.WithResource("synctargetpools"): {
+				kind:   "SyncTargetPool",
+				local:  localKcpInformers.Workload().V1alpha1().SyncTargetPools().Informer(),
+				global: globalKcpInformers.Workload().V1alpha1().SyncTargetPools().Informer(),
+			},
 			schedulingv1alpha1.SchemeGroupVersion.WithResource("locations"): {
 				kind:   "Location",
 				local:  localKcpInformers.Scheduling().V1alpha1().Locations().Informer(),
@@ -146,38 +199,79 @@ func NewController(
 	}
 
 	for gvr, info := range c.gvrs {
-		indexers.AddIfNotPresentOrDie(
-			info.global.GetIndexer(),
-			cache.Indexers{
-				ByShardAndLogicalClusterAndNamespaceAndName: IndexByShardAndLogicalClusterAndNamespace,
-			},
-		)
-
-		// shadow gvr to get the right value in the closure
-		gvr := gvr
+		if err := c.setUpInformers(gvr, info); err != nil {
+			return nil, err
+		}
+	}
 
-		info.local.AddEventHandler(cache.FilteringResourceEventHandler{
-			FilterFunc: IsNoSystemClusterName,
-			Handler: cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { c.enqueueObject(obj, gvr) },
-				UpdateFunc: func(_, obj interface{}) { c.enqueueObject(obj, gvr) },
-				DeleteFunc: func(obj interface{}) { c.enqueueObject(obj, gvr) },
-			},
-		})
-
-		info.global.AddEventHandler(cache.FilteringResourceEventHandler{
-			FilterFunc: IsNoSystemClusterName, // not really needed, but cannot harm
-			Handler: cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { c.enqueueCacheObject(obj, gvr) },
-				UpdateFunc: func(_, obj interface{}) { c.enqueueCacheObject(obj, gvr) },
-				DeleteFunc: func(obj interface{}) { c.enqueueCacheObject(obj, gvr) },
-			},
-		})
+	c.checkServed = func(ctx context.Context, gvr schema.GroupVersionResource) error {
+		if _, err := dynamicLocalClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			return err
+		}
+		_, err := dynamicCacheClient.Resource(gvr).List(cacheclient.WithShardInContext(ctx, shard.Wildcard), metav1.ListOptions{Limit: 1})
+		return err
+	}
+	c.listReplicationConfigs = func() ([]*corev1alpha1.ReplicationConfig, error) {
+		return globalKcpInformers.Core().V1alpha1().ReplicationConfigs().Lister().Cluster(core.RootCluster).List(labels.Everything())
 	}
+	globalKcpInformers.Core().V1alpha1().ReplicationConfigs().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+			if err != nil {
+				runtime.HandleError(err)
+				return false
+			}
+			clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+			if err != nil {
+				runtime.HandleError(err)
+				return false
+			}
+			return clusterName == core.RootCluster
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.configQueue.Add(replicationConfigsKey) },
+			UpdateFunc: func(_, obj interface{}) { c.configQueue.Add(replicationConfigsKey) },
+			DeleteFunc: func(obj interface{}) { c.configQueue.Add(replicationConfigsKey) },
+		},
+	})
 
 	return c, nil
 }
 
+// setUpInformers sets up the indexes and event handlers of the informers of a replicated resource. This has
+// to happen before the informers are started.
+func (c *controller) setUpInformers(gvr schema.GroupVersionResource, info replicatedGVR) error {
+	if err := c.offloader.TransformInformers(info.global); err != nil {
+		return fmt.Errorf("failed to set up resolving of offloaded %s: %w", gvr, err)
+	}
+
+	indexers.AddIfNotPresentOrDie(
+		info.global.GetIndexer(),
+		cache.Indexers{
+			ByShardAndLogicalClusterAndNamespaceAndName: IndexByShardAndLogicalClusterAndNamespace,
+		},
+	)
+
+	info.local.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: IsNoSystemClusterName,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueObject(obj, gvr) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueObject(obj, gvr) },
+			DeleteFunc: func(obj interface{}) { c.enqueueObject(obj, gvr) },
+		},
+	})
+
+	info.global.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: IsNoSystemClusterName, // not really needed, but cannot harm
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueCacheObject(obj, gvr) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueCacheObject(obj, gvr) },
+			DeleteFunc: func(obj interface{}) { c.enqueueCacheObject(obj, gvr) },
+		},
+	})
+	return nil
+}
+
 func (c *controller) enqueueObject(obj interface{}, gvr schema.GroupVersionResource) {
 	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
 	if err != nil {
@@ -185,7 +279,8 @@ func (c *controller) enqueueObject(obj interface{}, gvr schema.GroupVersionResou
 		return
 	}
 	gvrKey := fmt.Sprintf("%s.%s.%s::%s", gvr.Version, gvr.Resource, gvr.Group, key)
-	c.queue.Add(gvrKey)
+	c.lag.observe(gvr, gvrKey, obj)
+	c.queue.Add(gvrKey, priorityOf(gvr))
 }
 
 func (c *controller) enqueueCacheObject(obj interface{}, gvr schema.GroupVersionResource) {
@@ -195,13 +290,17 @@ func (c *controller) enqueueCacheObject(obj interface{}, gvr schema.GroupVersion
 		return
 	}
 	gvrKey := fmt.Sprintf("%s.%s.%s::%s", gvr.Version, gvr.Resource, gvr.Group, key)
-	c.queue.Add(gvrKey)
+	c.queue.Add(gvrKey, priorityOf(gvr))
 }
 
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, workers int) {
 	defer runtime.HandleCrash()
 	defer c.queue.ShutDown()
+	defer c.configQueue.ShutDown()
+	if c.stream != nil {
+		defer c.stream.Close()
+	}
 
 	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
 	ctx = klog.NewContext(cacheclient.WithShardInContext(ctx, shard.New(c.shardName)), logger)
@@ -211,33 +310,92 @@ func (c *controller) Start(ctx context.Context, workers int) {
 	for i := 0; i < workers; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
+	go wait.UntilWithContext(ctx, c.startConfigWorker, time.Second)
+	c.configQueue.Add(replicationConfigsKey)
 	<-ctx.Done()
 }
 
 func (c *controller) startWorker(ctx context.Context) {
-	for c.processNextWorkItem(ctx) {
+	for c.processNextBatch(ctx) {
 	}
 }
 
-func (c *controller) processNextWorkItem(ctx context.Context) bool {
-	grKey, quit := c.queue.Get()
+// processNextBatch replicates the next batch of keys concurrently, after waiting for a pause
+// because of throttling by the cache server.
+func (c *controller) processNextBatch(ctx context.Context) bool {
+	if d := c.backPressure.pause(); d > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(d):
+		}
+	}
+
+	keys, priority, quit := c.queue.Get(c.backPressure.batchSize())
 	if quit {
 		return false
 	}
-	defer c.queue.Done(grKey)
+	for p := replicationPriority(0); int(p) < numPriorities; p++ {
+		replicationQueueDepth.WithLabelValues(p.String()).Set(float64(c.queue.Depth(p)))
+	}
+
+	var wg sync.WaitGroup
+	var throttled atomic.Bool
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer c.queue.Done(key)
+			if c.processKey(ctx, key, priority) {
+				throttled.Store(true)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	if !throttled.Load() {
+		c.backPressure.succeeded(len(keys))
+	}
+	return true
+}
 
-	logger := logging.WithQueueKey(klog.FromContext(ctx), grKey.(string))
+// processKey replicates the key, and returns true if the cache server throttled it. Throttled
+// keys are retried after the back-off of the replication, not rate limited as failures.
+func (c *controller) processKey(ctx context.Context, key string, priority replicationPriority) bool {
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
 	ctx = klog.NewContext(ctx, logger)
-	err := c.reconcile(ctx, grKey.(string))
+	pending, isPending := c.lag.snapshot(key)
+	err := c.reconcile(ctx, key)
 	if err == nil {
-		c.queue.Forget(grKey)
+		if isPending {
+			c.lag.replicated(key, pending)
+		}
+		c.queue.Forget(key)
+		return false
+	}
+
+	if apierrors.IsTooManyRequests(err) {
+		delay := c.backPressure.throttled(err, priority)
+		logger.V(2).Info("cache server throttled replication, backing off", "delay", delay.String())
+		c.queue.AddAfter(key, priority, delay)
 		return true
 	}
 
-	runtime.HandleError(fmt.Errorf("%v failed with: %w", grKey, err))
-	c.queue.AddRateLimited(grKey)
+	runtime.HandleError(fmt.Errorf("%v failed with: %w", key, err))
+	c.queue.AddRateLimited(key, priority)
+	return false
+}
 
-	return true
+// ReplicationState returns the replication state of the replicated resources, i.e. the local
+// changes that have not been replicated to the cache server yet.
+func (c *controller) ReplicationState() []heartbeat.ResourceState {
+	c.lock.RLock()
+	gvrs := make([]schema.GroupVersionResource, 0, len(c.gvrs))
+	for gvr := range c.gvrs {
+		gvrs = append(gvrs, gvr)
+	}
+	c.lock.RUnlock()
+	return c.lag.state(gvrs)
 }
 
 func IsNoSystemClusterName(obj interface{}) bool {
@@ -260,10 +418,27 @@ func IsNoSystemClusterName(obj interface{}) bool {
 
 type controller struct {
 	shardName string
-	queue     workqueue.RateLimitingInterface
-
+	queue     *priorityQueue
+	// backPressure adapts the batch size of the workers and pauses them when the cache server
+	// throttles the replication.
+	backPressure *pushBackPressure
+	// configQueue holds the single replicationConfigsKey, queued when a ReplicationConfig changes.
+	configQueue workqueue.RateLimitingInterface
+
+	dynamicLocalClient kcpdynamic.ClusterInterface
 	dynamicCacheClient kcpdynamic.ClusterInterface
-
+	offloader          *offload.Offloader
+	// stream is nil if incremental replication is disabled, and objects are written directly.
+	stream *cacheclientreplication.Stream
+	// lag tracks the local changes not replicated yet, reported to the cache server with heartbeats.
+	lag *lagTracker
+
+	listReplicationConfigs func() ([]*corev1alpha1.ReplicationConfig, error)
+	// checkServed returns a NotFound error if a resource listed by ReplicationConfigs is not
+	// served by the shard or by the cache server.
+	checkServed func(ctx context.Context, gvr schema.GroupVersionResource) error
+
+	lock sync.RWMutex
 	gvrs map[schema.GroupVersionResource]replicatedGVR
 }
 
@@ -271,4 +446,16 @@ type replicatedGVR struct {
 	kind          string
 	filter        func(u *unstructured.Unstructured) bool
 	global, local cache.SharedIndexInformer
+
+	// configured is true for resources replicated because of ReplicationConfigs. Their kind is taken
+	// from the objects.
+	configured bool
+	// selectors is the string representation of the label selectors of a configured resource,
+	// used to detect changes.
+	selectors string
+	// conflictPolicy is the conflict policy set by ReplicationConfigs, for configured resources and
+	// for resources always replicated alike. Empty means LocalWins.
+	conflictPolicy corev1alpha1.ConflictPolicy
+	// stop stops the informers of a configured resource.
+	stop context.CancelFunc
 }
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+)
+
+// lagTracker keeps track of the local changes that have not been replicated to the cache server yet.
+type lagTracker struct {
+	now func() time.Time
+
+	lock     sync.Mutex
+	observed map[schema.GroupVersionResource]int64
+	pending  map[string]*pendingChange
+}
+
+// pendingChange holds the oldest and the latest unreplicated change of one object.
+type pendingChange struct {
+	gvr schema.GroupVersionResource
+
+	oldestResourceVersion int64
+	oldestSince           time.Time
+
+	latestResourceVersion int64
+	latestSince           time.Time
+}
+
+func newLagTracker() *lagTracker {
+	return &lagTracker{
+		now:      time.Now,
+		observed: map[schema.GroupVersionResource]int64{},
+		pending:  map[string]*pendingChange{},
+	}
+}
+
+// observe records a change of a local object under the given queue key.
+func (t *lagTracker) observe(gvr schema.GroupVersionResource, gvrKey string, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	var rv int64
+	if m, err := meta.Accessor(obj); err == nil {
+		rv, _ = strconv.ParseInt(m.GetResourceVersion(), 10, 64)
+	}
+	now := t.now()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if rv > t.observed[gvr] {
+		t.observed[gvr] = rv
+	}
+	if p, found := t.pending[gvrKey]; found {
+		p.latestResourceVersion, p.latestSince = rv, now
+		return
+	}
+	t.pending[gvrKey] = &pendingChange{
+		gvr:                   gvr,
+		oldestResourceVersion: rv,
+		oldestSince:           now,
+		latestResourceVersion: rv,
+		latestSince:           now,
+	}
+}
+
+// snapshot returns the pending change under the given queue key before it is reconciled.
+func (t *lagTracker) snapshot(gvrKey string) (pendingChange, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, found := t.pending[gvrKey]
+	if !found {
+		return pendingChange{}, false
+	}
+	return *p, true
+}
+
+// replicated records that the changes in the snapshot have been replicated. Changes observed
+// in the meantime stay pending.
+func (t *lagTracker) replicated(gvrKey string, snapshot pendingChange) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, found := t.pending[gvrKey]
+	if !found {
+		return
+	}
+	if p.latestSince.Equal(snapshot.latestSince) {
+		delete(t.pending, gvrKey)
+		return
+	}
+	p.oldestResourceVersion, p.oldestSince = p.latestResourceVersion, p.latestSince
+}
+
+// state returns the replication state of the given resources.
+func (t *lagTracker) state(gvrs []schema.GroupVersionResource) []heartbeat.ResourceState {
+	now := t.now()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	states := make(map[schema.GroupVersionResource]*heartbeat.ResourceState, len(gvrs))
+	for _, gvr := range gvrs {
+		states[gvr] = &heartbeat.ResourceState{
+			Group:                   gvr.Group,
+			Version:                 gvr.Version,
+			Resource:                gvr.Resource,
+			ObservedResourceVersion: t.observed[gvr],
+		}
+	}
+	for _, p := range t.pending {
+		s, found := states[p.gvr]
+		if !found {
+			continue
+		}
+		s.Pending++
+		if s.OldestPendingResourceVersion == 0 || p.oldestResourceVersion < s.OldestPendingResourceVersion {
+			s.OldestPendingResourceVersion = p.oldestResourceVersion
+		}
+		if age := now.Sub(p.oldestSince).Seconds(); age > s.OldestPendingAgeSeconds {
+			s.OldestPendingAgeSeconds = age
+		}
+	}
+
+	ret := make([]heartbeat.ResourceState, 0, len(states))
+	for _, s := range states {
+		ret = append(ret, *s)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return schema.GroupVersionResource{Group: ret[i].Group, Version: ret[i].Version, Resource: ret[i].Resource}.String() <
+			schema.GroupVersionResource{Group: ret[j].Group, Version: ret[j].Version, Resource: ret[j].Resource}.String()
+	})
+	return ret
+}
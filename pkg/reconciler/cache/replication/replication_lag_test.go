@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestLagTracker(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newLagTracker()
+	tracker.now = func() time.Time { return now }
+
+	gvr := apisv1alpha1.SchemeGroupVersion.WithResource("apiexports")
+	gvrs := []schema.GroupVersionResource{gvr}
+	export := func(rv string) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: rv}}
+	}
+	state := func() heartbeat.ResourceState {
+		states := tracker.state(gvrs)
+		require.Len(t, states, 1)
+		return states[0]
+	}
+
+	require.Equal(t, heartbeat.ResourceState{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource}, state(), "expected nothing to be pending")
+
+	tracker.observe(gvr, "foo", export("10"))
+	now = now.Add(time.Second)
+	tracker.observe(gvr, "foo", export("12"))
+	tracker.observe(gvr, "bar", cache.DeletedFinalStateUnknown{Key: "bar", Obj: export("15")})
+	now = now.Add(time.Second)
+	require.Equal(t, heartbeat.ResourceState{
+		Group:                        gvr.Group,
+		Version:                      gvr.Version,
+		Resource:                     gvr.Resource,
+		ObservedResourceVersion:      15,
+		Pending:                      2,
+		OldestPendingResourceVersion: 10,
+		OldestPendingAgeSeconds:      2,
+	}, state())
+
+	// a change observed while reconciling stays pending.
+	snapshot, found := tracker.snapshot("foo")
+	require.True(t, found)
+	tracker.observe(gvr, "foo", export("16"))
+	tracker.replicated("foo", snapshot)
+	require.Equal(t, 2, state().Pending)
+	require.Equal(t, int64(15), state().OldestPendingResourceVersion)
+
+	for _, key := range []string{"foo", "bar"} {
+		snapshot, found := tracker.snapshot(key)
+		require.True(t, found)
+		tracker.replicated(key, snapshot)
+	}
+	require.Equal(t, heartbeat.ResourceState{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, ObservedResourceVersion: 16}, state(), "expected nothing to be pending")
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// replicationPriority is the priority with which changes of a resource are replicated.
+type replicationPriority int
+
+const (
+	// priorityLow is the priority of resources not listed in gvrPriorities, including those
+	// replicated because of ReplicationConfigs.
+	priorityLow replicationPriority = iota
+	// priorityNormal is the priority of authorization and admission resources.
+	priorityNormal
+	// priorityHigh is the priority of resources other shards need to serve APIs and workspaces.
+	priorityHigh
+
+	numPriorities = int(priorityHigh) + 1
+)
+
+func (p replicationPriority) String() string {
+	switch p {
+	case priorityHigh:
+		return "high"
+	case priorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+// gvrPriorities are the priorities of the resources replicated always, other than low.
+var gvrPriorities = map[schema.GroupVersionResource]replicationPriority{
+	apisv1alpha1.SchemeGroupVersion.WithResource("apiexports"):                                 priorityHigh,
+	apisv1alpha1.SchemeGroupVersion.WithResource("apiresourceschemas"):                         priorityHigh,
+	apisv1alpha1.SchemeGroupVersion.WithResource("apiconversions"):                             priorityHigh,
+	corev1alpha1.SchemeGroupVersion.WithResource("shards"):                                     priorityHigh,
+	corev1alpha1.SchemeGroupVersion.WithResource("logicalclusters"):                            priorityHigh,
+	tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetypes"):                          priorityHigh,
+	rbacv1.SchemeGroupVersion.WithResource("clusterroles"):                                     priorityNormal,
+	rbacv1.SchemeGroupVersion.WithResource("clusterrolebindings"):                              priorityNormal,
+	admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"):   priorityNormal,
+	admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"): priorityNormal,
+	corev1alpha1.SchemeGroupVersion.WithResource("replicationconfigs"):                         priorityNormal,
+	apisv1alpha1.SchemeGroupVersion.WithResource("defaultapibindings"):                         priorityNormal,
+	tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetemplates"):                      priorityNormal,
+}
+
+// prioritySchedule is the order in which consecutive batches are taken from the priorities.
+// Priorities without queued keys are skipped, such that lower priorities get a share of the
+// workers under load, but are never starved.
+var prioritySchedule = []replicationPriority{
+	priorityHigh, priorityHigh, priorityHigh, priorityHigh,
+	priorityNormal, priorityNormal,
+	priorityLow,
+}
+
+// priorityQueue is a work queue of "version.resource.group::key" keys with a queue per resource.
+// Batches of keys of one resource are handed out by priority of the resource, and round robin
+// between the resources of the same priority, such that a burst of changes of one resource does
+// not hold back the others.
+//
+// Like a workqueue, a key is never processed concurrently, and a key added while processed is
+// queued again when done.
+type priorityQueue struct {
+	rateLimiter workqueue.RateLimiter
+	afterFunc   func(d time.Duration, f func())
+
+	lock         sync.Mutex
+	cond         *sync.Cond
+	priorities   [numPriorities]*priorityKeys
+	dirty        map[string]replicationPriority
+	processing   sets.String
+	next         int
+	shuttingDown bool
+}
+
+// priorityKeys holds the queued keys of the resources of one priority.
+type priorityKeys struct {
+	// gvrs are the resources with queued keys, in round robin order.
+	gvrs []string
+	keys map[string][]string
+}
+
+func newPriorityQueue(rateLimiter workqueue.RateLimiter) *priorityQueue {
+	q := &priorityQueue{
+		rateLimiter: rateLimiter,
+		afterFunc:   func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+		dirty:       map[string]replicationPriority{},
+		processing:  sets.NewString(),
+	}
+	q.cond = sync.NewCond(&q.lock)
+	for i := range q.priorities {
+		q.priorities[i] = &priorityKeys{keys: map[string][]string{}}
+	}
+	return q
+}
+
+// priorityOf returns the priority of the resource.
+func priorityOf(gvr schema.GroupVersionResource) replicationPriority {
+	return gvrPriorities[gvr]
+}
+
+// Add queues the key with the given priority, unless it is queued already.
+func (q *priorityQueue) Add(key string, priority replicationPriority) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+	if _, found := q.dirty[key]; found {
+		return
+	}
+	q.dirty[key] = priority
+	if q.processing.Has(key) {
+		return
+	}
+	q.push(key, priority)
+	q.cond.Signal()
+}
+
+// AddAfter queues the key after the given duration.
+func (q *priorityQueue) AddAfter(key string, priority replicationPriority, d time.Duration) {
+	if d <= 0 {
+		q.Add(key, priority)
+		return
+	}
+	q.afterFunc(d, func() { q.Add(key, priority) })
+}
+
+// AddRateLimited queues the key after the rate limiter says it is ok.
+func (q *priorityQueue) AddRateLimited(key string, priority replicationPriority) {
+	q.AddAfter(key, priority, q.rateLimiter.When(key))
+}
+
+// Forget resets the rate limiting of the key.
+func (q *priorityQueue) Forget(key string) {
+	q.rateLimiter.Forget(key)
+}
+
+// Get blocks until keys are queued, and returns up to size keys of one resource and their
+// priority, taken from the priorities in the order of prioritySchedule. The keys must be passed
+// to Done when processed. It returns true if the queue is shutting down.
+func (q *priorityQueue) Get(size int) ([]string, replicationPriority, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.len() == 0 {
+		return nil, priorityLow, true
+	}
+
+	var priority replicationPriority
+	for i := range prioritySchedule {
+		priority = prioritySchedule[(q.next+i)%len(prioritySchedule)]
+		if len(q.priorities[priority].gvrs) > 0 {
+			q.next = (q.next + i + 1) % len(prioritySchedule)
+			break
+		}
+	}
+	pk := q.priorities[priority]
+
+	gvr := pk.gvrs[0]
+	queued := pk.keys[gvr]
+	if size < 1 {
+		size = 1
+	}
+	if size > len(queued) {
+		size = len(queued)
+	}
+	keys := append([]string(nil), queued[:size]...)
+	if rest := queued[size:]; len(rest) > 0 {
+		pk.keys[gvr] = rest
+		pk.gvrs = append(pk.gvrs[1:], gvr)
+	} else {
+		delete(pk.keys, gvr)
+		pk.gvrs = pk.gvrs[1:]
+	}
+
+	for _, key := range keys {
+		q.processing.Insert(key)
+		delete(q.dirty, key)
+	}
+	return keys, priority, false
+}
+
+// Done marks the key as processed, and queues it again if it was added while processed.
+func (q *priorityQueue) Done(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.processing.Delete(key)
+	if priority, found := q.dirty[key]; found {
+		q.push(key, priority)
+		q.cond.Signal()
+	}
+}
+
+// ShutDown makes Get return true once the queue is drained, and ignores new keys.
+func (q *priorityQueue) ShutDown() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// Len returns the number of queued keys.
+func (q *priorityQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.len()
+}
+
+// Depth returns the number of queued keys of the given priority.
+func (q *priorityQueue) Depth(priority replicationPriority) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	n := 0
+	for _, keys := range q.priorities[priority].keys {
+		n += len(keys)
+	}
+	return n
+}
+
+func (q *priorityQueue) len() int {
+	n := 0
+	for _, pk := range q.priorities {
+		for _, keys := range pk.keys {
+			n += len(keys)
+		}
+	}
+	return n
+}
+
+func (q *priorityQueue) push(key string, priority replicationPriority) {
+	gvr := key
+	if i := strings.Index(key, "::"); i >= 0 {
+		gvr = key[:i]
+	}
+	pk := q.priorities[priority]
+	if _, found := pk.keys[gvr]; !found {
+		pk.gvrs = append(pk.gvrs, gvr)
+	}
+	pk.keys[gvr] = append(pk.keys[gvr], key)
+}
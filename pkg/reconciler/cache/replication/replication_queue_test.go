@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	type batch struct {
+		keys     []string
+		priority replicationPriority
+	}
+	newQueue := func() *priorityQueue {
+		q := newPriorityQueue(workqueue.DefaultControllerRateLimiter())
+		q.afterFunc = func(d time.Duration, f func()) { f() }
+		return q
+	}
+	get := func(q *priorityQueue, size int) batch {
+		keys, priority, quit := q.Get(size)
+		require.False(t, quit)
+		for _, key := range keys {
+			q.Done(key)
+		}
+		return batch{keys, priority}
+	}
+
+	t.Run("batches hold keys of one resource, round robin between resources", func(t *testing.T) {
+		q := newQueue()
+		for _, key := range []string{"v1.a.g::1", "v1.a.g::2", "v1.a.g::3", "v1.b.g::1", "v1.a.g::1"} {
+			q.Add(key, priorityLow)
+		}
+		require.Equal(t, 4, q.Len())
+		require.Equal(t, batch{[]string{"v1.a.g::1", "v1.a.g::2"}, priorityLow}, get(q, 2))
+		require.Equal(t, batch{[]string{"v1.b.g::1"}, priorityLow}, get(q, 2))
+		require.Equal(t, batch{[]string{"v1.a.g::3"}, priorityLow}, get(q, 2))
+		require.Zero(t, q.Len())
+	})
+
+	t.Run("priorities are served by schedule", func(t *testing.T) {
+		q := newQueue()
+		for i := 0; i < 10; i++ {
+			q.Add("v1.high.g::"+string(rune('a'+i)), priorityHigh)
+		}
+		q.Add("v1.normal.g::a", priorityNormal)
+		q.Add("v1.low.g::a", priorityLow)
+		require.Equal(t, 10, q.Depth(priorityHigh))
+
+		var priorities []replicationPriority
+		for q.Len() > 0 {
+			priorities = append(priorities, get(q, 1).priority)
+		}
+		require.Equal(t, []replicationPriority{
+			priorityHigh, priorityHigh, priorityHigh, priorityHigh, priorityNormal, priorityLow,
+			priorityHigh, priorityHigh, priorityHigh, priorityHigh, priorityHigh, priorityHigh,
+		}, priorities)
+	})
+
+	t.Run("keys added while processed are queued again when done", func(t *testing.T) {
+		q := newQueue()
+		q.Add("v1.a.g::1", priorityHigh)
+		keys, _, _ := q.Get(10)
+		require.Equal(t, []string{"v1.a.g::1"}, keys)
+
+		q.Add("v1.a.g::1", priorityHigh)
+		require.Zero(t, q.Len(), "a key is not handed out while processed")
+		q.Done("v1.a.g::1")
+		require.Equal(t, 1, q.Len())
+
+		q.AddRateLimited("v1.a.g::2", priorityHigh)
+		require.Equal(t, 2, q.Len())
+	})
+
+	t.Run("shutting down drains the queue", func(t *testing.T) {
+		q := newQueue()
+		q.Add("v1.a.g::1", priorityHigh)
+		q.ShutDown()
+		q.Add("v1.a.g::2", priorityHigh)
+		require.Equal(t, batch{[]string{"v1.a.g::1"}, priorityHigh}, get(q, 10))
+		_, _, quit := q.Get(10)
+		require.True(t, quit)
+	})
+}
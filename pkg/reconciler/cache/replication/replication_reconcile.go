@@ -21,9 +21,11 @@ import (
 	"fmt"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -31,6 +33,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/klog/v2"
+
+	cacheclientreplication "github.com/kcp-dev/kcp/pkg/cache/client/replication"
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 )
 
 func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
@@ -43,10 +49,29 @@ func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
 	gvr := schema.GroupVersionResource{Version: gvrParts[0], Resource: gvrParts[1], Group: gvrParts[2]}
 	key := keyParts[1]
 
-	info := c.gvrs[gvr]
+	c.lock.RLock()
+	info, found := c.gvrs[gvr]
+	c.lock.RUnlock()
+	if !found {
+		return nil // not replicated anymore
+	}
+	if info.configured && info.selectors == "" {
+		// not listed anymore, the informers are stopped once all cached copies are deleted.
+		defer c.configQueue.Add(replicationConfigsKey)
+	}
+	if info.configured && (!info.local.HasSynced() || !info.global.HasSynced()) {
+		// a missing local object would delete the cached copy.
+		return fmt.Errorf("informers for %s have not synced yet", gvr)
+	}
+
+	// originalGlobalCopy is the cached object before the reconciler modifies it, used to compute
+	// the delta sent over the replication stream.
+	var originalGlobalCopy *unstructured.Unstructured
 
 	r := &reconciler{
-		shardName: c.shardName,
+		shardName:      c.shardName,
+		resource:       gvr.GroupResource().String(),
+		conflictPolicy: info.conflictPolicy,
 		getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
 			key := kcpcache.ToClusterAwareKey(cluster.String(), namespace, name)
 			obj, exists, err := info.local.GetIndexer().GetByKey(key)
@@ -68,8 +93,10 @@ func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
 			if _, ok := obj.(*unstructured.Unstructured); ok {
 				u = u.DeepCopy()
 			}
-			u.SetKind(info.kind)
-			u.SetAPIVersion(gvr.GroupVersion().String())
+			if !info.configured {
+				u.SetKind(info.kind)
+				u.SetAPIVersion(gvr.GroupVersion().String())
+			}
 			return u, nil
 		},
 		getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
@@ -93,8 +120,11 @@ func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
 				u = u.DeepCopy()
 			}
 
-			u.SetKind(info.kind)
-			u.SetAPIVersion(gvr.GroupVersion().String())
+			if !info.configured {
+				u.SetKind(info.kind)
+				u.SetAPIVersion(gvr.GroupVersion().String())
+			}
+			originalGlobalCopy = u.DeepCopy()
 			return u, nil
 		},
 		createObject: func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -106,12 +136,97 @@ func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
 		deleteObject: func(ctx context.Context, cluster logicalcluster.Name, ns, name string) error {
 			return c.dynamicCacheClient.Cluster(cluster.Path()).Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
 		},
+		updateLocalObject: func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			client := c.dynamicLocalClient.Cluster(cluster.Path()).Resource(gvr).Namespace(obj.GetNamespace())
+			updated, err := client.Update(ctx, obj, metav1.UpdateOptions{})
+			if err != nil {
+				return nil, err
+			}
+			status, found := obj.Object["status"]
+			if !found || equality.Semantic.DeepEqual(status, updated.Object["status"]) {
+				return updated, nil
+			}
+			updated = updated.DeepCopy()
+			updated.Object["status"] = status
+			return client.UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+		},
+	}
+	if c.stream != nil {
+		r.createObject = func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			return c.sendDelta(ctx, gvr, cluster, cacheclientreplication.DeltaAdded, obj, nil)
+		}
+		r.updateObject = func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			return c.sendDelta(ctx, gvr, cluster, cacheclientreplication.DeltaModified, obj, originalGlobalCopy)
+		}
+		r.deleteObject = func(ctx context.Context, cluster logicalcluster.Name, ns, name string) error {
+			obj := &unstructured.Unstructured{}
+			obj.SetNamespace(ns)
+			obj.SetName(name)
+			_, err := c.sendDelta(ctx, gvr, cluster, cacheclientreplication.DeltaDeleted, obj, nil)
+			return err
+		}
+	}
+	if c.offloader != nil {
+		r.offloadPayload = func(ctx context.Context, obj *unstructured.Unstructured) error {
+			return c.offloader.Offload(ctx, c.shardName, gvr.GroupResource(), obj)
+		}
+		r.deletePayload = c.offloader.Delete
 	}
 	return r.reconcile(ctx, key)
 }
 
+// sendDelta sends the change of obj over the replication stream. Modifications are sent as a
+// JSON merge patch against original, unless either side carries an offloaded payload, in which
+// case the whole object is sent.
+func (c *controller) sendDelta(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, typ cacheclientreplication.DeltaType, obj, original *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	delta := cacheclientreplication.Delta{
+		Type:      typ,
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Cluster:   cluster.String(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+
+	switch typ {
+	case cacheclientreplication.DeltaAdded:
+		delta.Object = obj
+	case cacheclientreplication.DeltaModified:
+		delta.ResourceVersion = obj.GetResourceVersion()
+		if original == nil || original.GetAnnotations()[offload.PayloadAnnotationKey] != "" || obj.GetAnnotations()[offload.PayloadAnnotationKey] != "" {
+			delta.Object = obj
+			break
+		}
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		originalRaw, err := original.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if delta.Patch, err = jsonpatch.CreateMergePatch(originalRaw, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	ack, err := c.stream.Send(ctx, delta)
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion(ack.ResourceVersion)
+	return obj, nil
+}
+
 type reconciler struct {
 	shardName string
+	// resource is the group resource of the replicated objects, used for metrics.
+	resource string
+	// conflictPolicy decides how objects are reconciled whose cached copy has been modified by
+	// someone else. Defaults to LocalWins.
+	conflictPolicy corev1alpha1.ConflictPolicy
 
 	getLocalCopy  func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
 	getGlobalCopy func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
@@ -119,6 +234,13 @@ type reconciler struct {
 	createObject func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	updateObject func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	deleteObject func(ctx context.Context, cluster logicalcluster.Name, ns, name string) error
+
+	// updateLocalObject updates the local object, including its status. It is used by the CacheWins conflict policy.
+	updateLocalObject func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+
+	// offloadPayload and deletePayload are nil if offloading of big objects is disabled.
+	offloadPayload func(ctx context.Context, obj *unstructured.Unstructured) error
+	deletePayload  func(ctx context.Context, ref string) error
 }
 
 // reconcile makes sure that the object under the given key from the local shard is replicated to the cache server.
@@ -126,6 +248,13 @@ type reconciler struct {
 //  1. creation of the object in the cache server when the cached object is not found by getGlobalCopy
 //  2. deletion of the object from the cache server when the original/local object was removed OR was not found by getLocalCopy
 //  3. modification of the cached object to match the original one when meta.annotations, meta.labels, spec or status are different
+//
+// Cached objects record the resource version of the local object and the hash of the content they have been replicated
+// with. If the cached object has been modified by someone else in the meantime, the conflict is recorded on it and
+// resolved according to the conflict policy.
+//
+// If offloading is enabled, the payload of objects exceeding the size threshold is stored in a blob store,
+// and the blob of the previous version is deleted after the cached object has been updated or deleted.
 func (r *reconciler) reconcile(ctx context.Context, key string) error {
 	logger := klog.FromContext(ctx).WithValues("reconcilerKey", key)
 
@@ -160,13 +289,13 @@ func (r *reconciler) reconcile(ctx context.Context, key string) error {
 		if err := r.deleteObject(ctx, clusterName, ns, name); err != nil && !apierrors.IsNotFound(err) {
 			return err
 		}
+		r.deleteStalePayload(ctx, offload.PayloadRefFrom(globalCopy), "")
 		return nil
 	}
 
 	// local exists, global doesn't. Create in cache.
 	if !globalExists {
-		// TODO: in the future the original RV will have to be stored in an annotation (?)
-		// so that the clients that need to modify the original/local object can do it
+		sourceRV := localCopy.GetResourceVersion()
 		localCopy.SetResourceVersion("")
 		annotations := localCopy.GetAnnotations()
 		if annotations == nil {
@@ -174,6 +303,15 @@ func (r *reconciler) reconcile(ctx context.Context, key string) error {
 		}
 		annotations[genericrequest.AnnotationKey] = r.shardName
 		localCopy.SetAnnotations(annotations)
+		if err := stampReplicated(localCopy, sourceRV); err != nil {
+			return err
+		}
+
+		if r.offloadPayload != nil {
+			if err := r.offloadPayload(ctx, localCopy); err != nil {
+				return err
+			}
+		}
 
 		logger.V(2).Info("Creating object in global cache")
 		_, err := r.createObject(ctx, clusterName, localCopy)
@@ -183,6 +321,44 @@ func (r *reconciler) reconcile(ctx context.Context, key string) error {
 		return nil
 	}
 
+	oldPayloadRef := offload.PayloadRefFrom(globalCopy)
+
+	// global has been modified by someone else. Resolve according to the conflict policy.
+	conflict, err := detectConflict(globalCopy, localCopy)
+	if err != nil {
+		return err
+	}
+	conflictResolved := false
+	if conflict != nil {
+		conflict.Policy = r.conflictPolicy
+		if conflict.Policy == "" {
+			conflict.Policy = corev1alpha1.ConflictPolicyLocalWins
+		}
+		logger = logger.WithValues("policy", conflict.Policy, "localModified", conflict.LocalModified)
+
+		switch conflict.Policy {
+		case corev1alpha1.ConflictPolicyFail:
+			return r.recordConflict(ctx, logger, clusterName, globalCopy, conflict, oldPayloadRef)
+		case corev1alpha1.ConflictPolicyCacheWins:
+			logger.Info("Cached object has been modified, updating local object to match it")
+			if localCopy, err = r.updateLocalObject(ctx, clusterName, adoptCachedCopy(localCopy, globalCopy)); err != nil {
+				return err
+			}
+		default:
+			logger.Info("Cached object has been modified, overwriting it")
+		}
+
+		conflict.DetectedAt = metav1.Now()
+		if err := setConflict(globalCopy, conflict); err != nil {
+			return err
+		}
+		conflictsTotal.WithLabelValues(r.resource, string(conflict.Policy)).Inc()
+	} else if recorded := recordedConflict(globalCopy); recorded != nil && recorded.Policy == corev1alpha1.ConflictPolicyFail {
+		// the conflict has been resolved by reverting the cached object.
+		removeAnnotation(globalCopy, ConflictAnnotationKey)
+		conflictResolved = true
+	}
+
 	// update global copy and compare
 	metaChanged, err := ensureMeta(globalCopy, localCopy)
 	if err != nil {
@@ -192,12 +368,64 @@ func (r *reconciler) reconcile(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
-	if !metaChanged && !remainingChanged {
+	_, stamped := globalCopy.GetAnnotations()[ContentHashAnnotationKey]
+	if !metaChanged && !remainingChanged && conflict == nil && !conflictResolved && stamped {
 		logger.V(4).Info("Object is up to date")
 		return nil
 	}
+	if err := stampReplicated(globalCopy, localCopy.GetResourceVersion()); err != nil {
+		return err
+	}
+
+	if r.offloadPayload != nil {
+		if err := r.offloadPayload(ctx, globalCopy); err != nil {
+			return err
+		}
+	}
 
 	logger.V(2).Info("Updating object in global cache")
-	_, err = r.updateObject(ctx, clusterName, globalCopy) // no need for patch because there is only this actor
-	return err
+	if _, err := r.updateObject(ctx, clusterName, globalCopy); err != nil { // no need for patch because there is only this actor
+		return err
+	}
+	r.deleteStalePayload(ctx, oldPayloadRef, offload.PayloadRefFrom(globalCopy))
+	return nil
+}
+
+// recordConflict records the conflict on the cached object without replicating the local object,
+// unless the same conflict has been recorded already.
+func (r *reconciler) recordConflict(ctx context.Context, logger klog.Logger, clusterName logicalcluster.Name, globalCopy *unstructured.Unstructured, conflict *Conflict, oldPayloadRef string) error {
+	if conflict.sameAs(recordedConflict(globalCopy)) {
+		logger.V(4).Info("Conflict has been recorded already")
+		return nil
+	}
+
+	conflict.DetectedAt = metav1.Now()
+	if err := setConflict(globalCopy, conflict); err != nil {
+		return err
+	}
+	conflictsTotal.WithLabelValues(r.resource, string(conflict.Policy)).Inc()
+
+	if r.offloadPayload != nil {
+		if err := r.offloadPayload(ctx, globalCopy); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Cached object has been modified, not replicating the local object until the conflict is resolved")
+	if _, err := r.updateObject(ctx, clusterName, globalCopy); err != nil {
+		return err
+	}
+	r.deleteStalePayload(ctx, oldPayloadRef, offload.PayloadRefFrom(globalCopy))
+	return nil
+}
+
+// deleteStalePayload deletes the blob of an offloaded payload that is not referenced anymore.
+// Failures are only logged as the cached object is already up to date.
+func (r *reconciler) deleteStalePayload(ctx context.Context, oldRef, newRef string) {
+	if r.deletePayload == nil || oldRef == "" || oldRef == newRef {
+		return
+	}
+	if err := r.deletePayload(ctx, oldRef); err != nil {
+		runtime.HandleError(fmt.Errorf("failed to delete offloaded payload %q: %w", oldRef, err))
+	}
 }
@@ -31,6 +31,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/endpoints/request"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 )
 
 func TestReconcile(t *testing.T) {
@@ -56,9 +58,10 @@ func TestReconcile(t *testing.T) {
 	}
 
 	scenarios := []struct {
-		name          string
-		getLocalCopy  func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
-		getGlobalCopy func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
+		name           string
+		conflictPolicy corev1alpha1.ConflictPolicy
+		getLocalCopy   func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
+		getGlobalCopy  func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error)
 
 		key string
 
@@ -66,6 +69,8 @@ func TestReconcile(t *testing.T) {
 		expectedDeleteName      string
 		expectedDeleteNamespace string
 		expectedUpdate          *unstructured.Unstructured
+		expectedLocalUpdate     *unstructured.Unstructured
+		expectedConflict        *Conflict
 
 		expectedError string
 	}{
@@ -79,7 +84,7 @@ func TestReconcile(t *testing.T) {
 			},
 			getGlobalCopy:  getCopyNotFoundFunc,
 			key:            "root|zoo/dumbo",
-			expectedCreate: WithShardName(WithoutResourceVersion(elephant.DeepCopy()), "root"),
+			expectedCreate: WithReplicatedFrom(WithShardName(WithoutResourceVersion(elephant.DeepCopy()), "root"), "42"),
 		},
 		{
 			name: "case 2: cached object is removed when local object was removed",
@@ -112,7 +117,7 @@ func TestReconcile(t *testing.T) {
 				return WithResourceVersion(elephant.DeepCopy(), "7"), nil
 			},
 			key:            "root|zoo/dumbo",
-			expectedUpdate: WithLabel(WithResourceVersion(elephant.DeepCopy(), "7"), "a", "b"),
+			expectedUpdate: WithReplicatedFrom(WithLabel(WithResourceVersion(elephant.DeepCopy(), "7"), "a", "b"), "42"),
 		},
 		{
 			name: "case 3: update, spec changed",
@@ -123,7 +128,7 @@ func TestReconcile(t *testing.T) {
 				return WithResourceVersion(elephant.DeepCopy(), "7"), nil
 			},
 			key:            "root|zoo/dumbo",
-			expectedUpdate: WithChange(WithResourceVersion(elephant.DeepCopy(), "7"), []string{"spec", "color"}, "blue"),
+			expectedUpdate: WithReplicatedFrom(WithChange(WithResourceVersion(elephant.DeepCopy(), "7"), []string{"spec", "color"}, "blue"), "42"),
 		},
 		{
 			name: "case 3: update, status changed",
@@ -134,19 +139,95 @@ func TestReconcile(t *testing.T) {
 				return WithResourceVersion(elephant.DeepCopy(), "7"), nil
 			},
 			key:            "root|zoo/dumbo",
-			expectedUpdate: WithChange(WithResourceVersion(elephant.DeepCopy(), "7"), []string{"status", "weight"}, "42.5"),
+			expectedUpdate: WithReplicatedFrom(WithChange(WithResourceVersion(elephant.DeepCopy(), "7"), []string{"status", "weight"}, "42.5"), "42"),
+		},
+		{
+			name: "case 3: up to date",
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithAnnotation(elephant.DeepCopy(), logicalcluster.AnnotationKey, "root"), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithReplicatedFrom(WithAnnotation(WithResourceVersion(elephant.DeepCopy(), "7"), logicalcluster.AnnotationKey, "root"), "42"), nil
+			},
+			key: "root|zoo/dumbo",
+		},
+		{
+			name: "case 4: cached object modified, local wins",
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return elephant.DeepCopy(), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithChange(WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "7"), "42"), []string{"spec", "color"}, "grey"), nil
+			},
+			key:              "root|zoo/dumbo",
+			expectedUpdate:   WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "7"), "42"),
+			expectedConflict: &Conflict{Policy: corev1alpha1.ConflictPolicyLocalWins, LocalResourceVersion: "42", SourceResourceVersion: "42"},
+		},
+		{
+			name:           "case 4: cached object modified, cache wins",
+			conflictPolicy: corev1alpha1.ConflictPolicyCacheWins,
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return elephant.DeepCopy(), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithChange(WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "7"), "42"), []string{"spec", "color"}, "grey"), nil
+			},
+			key:                 "root|zoo/dumbo",
+			expectedLocalUpdate: WithChange(elephant.DeepCopy(), []string{"spec", "color"}, "grey"),
+			expectedUpdate:      WithReplicatedFrom(WithChange(WithResourceVersion(elephant.DeepCopy(), "7"), []string{"spec", "color"}, "grey"), "43"),
+			expectedConflict:    &Conflict{Policy: corev1alpha1.ConflictPolicyCacheWins, LocalResourceVersion: "42", SourceResourceVersion: "42"},
+		},
+		{
+			name:           "case 4: cached and local object modified, fail",
+			conflictPolicy: corev1alpha1.ConflictPolicyFail,
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithChange(WithResourceVersion(elephant.DeepCopy(), "43"), []string{"spec", "color"}, "blue"), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithChange(WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "7"), "42"), []string{"spec", "color"}, "grey"), nil
+			},
+			key:              "root|zoo/dumbo",
+			expectedUpdate:   WithChange(WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "7"), "42"), []string{"spec", "color"}, "grey"),
+			expectedConflict: &Conflict{Policy: corev1alpha1.ConflictPolicyFail, LocalResourceVersion: "43", SourceResourceVersion: "42", LocalModified: true},
+		},
+		{
+			name:           "case 4: conflict recorded already, fail",
+			conflictPolicy: corev1alpha1.ConflictPolicyFail,
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return WithChange(WithResourceVersion(elephant.DeepCopy(), "43"), []string{"spec", "color"}, "blue"), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				global := WithChange(WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "8"), "42"), []string{"spec", "color"}, "grey")
+				return WithConflict(global, &Conflict{Policy: corev1alpha1.ConflictPolicyFail, LocalResourceVersion: "43", SourceResourceVersion: "42", LocalModified: true}), nil
+			},
+			key: "root|zoo/dumbo",
+		},
+		{
+			name:           "case 4: conflict resolved, fail",
+			conflictPolicy: corev1alpha1.ConflictPolicyFail,
+			getLocalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				return elephant.DeepCopy(), nil
+			},
+			getGlobalCopy: func(cluster logicalcluster.Name, namespace, name string) (*unstructured.Unstructured, error) {
+				global := WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "9"), "42")
+				return WithConflict(global, &Conflict{Policy: corev1alpha1.ConflictPolicyFail, LocalResourceVersion: "42", SourceResourceVersion: "42"}), nil
+			},
+			key:            "root|zoo/dumbo",
+			expectedUpdate: WithReplicatedFrom(WithResourceVersion(elephant.DeepCopy(), "9"), "42"),
 		},
 	}
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(tt *testing.T) {
 			var created *unstructured.Unstructured
-			var updated *unstructured.Unstructured
+			var updated, updatedLocal *unstructured.Unstructured
 			var deletedNamespace, deletedName string
 
 			r := &reconciler{
-				shardName:     "root",
-				getLocalCopy:  scenario.getLocalCopy,
-				getGlobalCopy: scenario.getGlobalCopy,
+				shardName:      "root",
+				resource:       gr.String(),
+				conflictPolicy: scenario.conflictPolicy,
+				getLocalCopy:   scenario.getLocalCopy,
+				getGlobalCopy:  scenario.getGlobalCopy,
 				createObject: func(ctx context.Context, clusterName logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 					created = obj.DeepCopy()
 					return created, nil
@@ -160,6 +241,10 @@ func TestReconcile(t *testing.T) {
 					deletedName = name
 					return nil
 				},
+				updateLocalObject: func(ctx context.Context, cluster logicalcluster.Name, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					updatedLocal = obj.DeepCopy()
+					return WithResourceVersion(obj.DeepCopy(), "43"), nil
+				},
 			}
 
 			err := r.reconcile(context.Background(), scenario.key)
@@ -179,6 +264,21 @@ func TestReconcile(t *testing.T) {
 				tt.Fatalf("expected created object to be %v, got %v, diff:\n%s", scenario.expectedCreate, created, cmp.Diff(scenario.expectedCreate, created))
 			}
 
+			var conflict *Conflict
+			if updated != nil {
+				if conflict = recordedConflict(updated); conflict != nil {
+					conflict.DetectedAt = metav1.Time{}
+					removeAnnotation(updated, ConflictAnnotationKey)
+				}
+			}
+			if !reflect.DeepEqual(scenario.expectedConflict, conflict) {
+				tt.Fatalf("expected recorded conflict to be %v, got %v", scenario.expectedConflict, conflict)
+			}
+
+			if !reflect.DeepEqual(scenario.expectedLocalUpdate, updatedLocal) {
+				tt.Fatalf("expected updated local object to be %v, got %v, diff:\n%s", scenario.expectedLocalUpdate, updatedLocal, cmp.Diff(scenario.expectedLocalUpdate, updatedLocal))
+			}
+
 			if scenario.expectedUpdate != nil && updated == nil {
 				tt.Fatalf("expected object to be updated, but it was not")
 			} else if scenario.expectedUpdate == nil && updated != nil {
@@ -224,15 +324,29 @@ func WithLabel(u *unstructured.Unstructured, key, value string) *unstructured.Un
 }
 
 func WithShardName(u *unstructured.Unstructured, name string) *unstructured.Unstructured {
+	return WithAnnotation(u, request.AnnotationKey, name)
+}
+
+func WithAnnotation(u *unstructured.Unstructured, key, value string) *unstructured.Unstructured {
 	ann := u.GetAnnotations()
 	if ann == nil {
 		ann = map[string]string{}
 	}
-	ann[request.AnnotationKey] = name
+	ann[key] = value
 	u.SetAnnotations(ann)
 	return u
 }
 
+func WithReplicatedFrom(u *unstructured.Unstructured, rv string) *unstructured.Unstructured {
+	stampReplicated(u, rv) //nolint:errcheck
+	return u
+}
+
+func WithConflict(u *unstructured.Unstructured, conflict *Conflict) *unstructured.Unstructured {
+	setConflict(u, conflict) //nolint:errcheck
+	return u
+}
+
 func WithChange(u *unstructured.Unstructured, path []string, value interface{}) *unstructured.Unstructured {
 	unstructured.SetNestedField(u.Object, value, path...) //nolint:errcheck
 	return u
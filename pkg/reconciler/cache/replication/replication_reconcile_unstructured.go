@@ -23,9 +23,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	genericrequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
 )
 
-// ensureMeta changes unstructuredCacheObject's metadata to match unstructuredLocalObject's metadata except the ResourceVersion and the shard annotation fields.
+// ensureMeta changes unstructuredCacheObject's metadata to match unstructuredLocalObject's metadata except the ResourceVersion, the shard annotation
+// and the annotations recording the replication and its conflicts.
+// The offloading annotations are removed from unstructuredCacheObject.
 func ensureMeta(cacheObject *unstructured.Unstructured, localObject *unstructured.Unstructured) (changed bool, err error) {
 	cacheObjMetaRaw, hasCacheObjMetaRaw, err := unstructured.NestedFieldNoCopy(cacheObject.Object, "metadata")
 	if err != nil {
@@ -66,15 +70,21 @@ func ensureMeta(cacheObject *unstructured.Unstructured, localObject *unstructure
 		if !ok {
 			return false, fmt.Errorf("metadata.annotations field of unstructuredCacheObject is of the type %T, expected map[string]interface{}", cacheObjAnnotationsRaw)
 		}
-		if shard, hasShard := cacheObjAnnotations[genericrequest.AnnotationKey]; hasShard {
-			unstructured.RemoveNestedField(cacheObjAnnotations, genericrequest.AnnotationKey)
-			defer func() {
+		for _, key := range []string{genericrequest.AnnotationKey, SourceResourceVersionAnnotationKey, ContentHashAnnotationKey, ConflictAnnotationKey} {
+			value, found := cacheObjAnnotations[key]
+			if !found {
+				continue
+			}
+			unstructured.RemoveNestedField(cacheObjAnnotations, key)
+			defer func(key string, value interface{}) {
 				if err == nil {
-					err = unstructured.SetNestedField(cacheObject.Object, shard, "metadata", "annotations", genericrequest.AnnotationKey)
+					err = unstructured.SetNestedField(cacheObject.Object, value, "metadata", "annotations", key)
 				}
-			}()
+			}(key, value)
 		}
-		// TODO: in the future the original RV will be stored in an annotation
+		// the offloading annotations are set by the offloader right before writing, drop them here.
+		unstructured.RemoveNestedField(cacheObjAnnotations, offload.PayloadAnnotationKey)
+		unstructured.RemoveNestedField(cacheObjAnnotations, offload.PayloadChecksumAnnotationKey)
 	}
 
 	// before we can compare with the local object we need to
@@ -43,6 +43,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	"github.com/kcp-dev/kcp/sdk/apis/workload/helpers"
 	"github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 	"github.com/kcp-dev/kcp/tmc/pkg/coordination"
@@ -69,8 +70,8 @@ func NewController(
 	informer := deploymentClusterInformer.Informer()
 
 	c := &controller{
-		upstreamViewQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"upstream-view"),
-		syncerViewQueue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"syncer-view"),
+		upstreamViewQueue:   workqueue.NewNamedRateLimitingQueue(ratelimiter.For(controllerName), controllerName+"upstream-view"),
+		syncerViewQueue:     workqueue.NewNamedRateLimitingQueue(ratelimiter.For(controllerName), controllerName+"syncer-view"),
 		syncerViewRetriever: coordination.NewDefaultSyncerViewManager[*appsv1.Deployment](),
 		gvr:                 appsv1.SchemeGroupVersion.WithResource("deployments"),
 
@@ -0,0 +1,273 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cabundle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+)
+
+const (
+	ControllerName = "kcp-ca-bundle-configmap"
+
+	// ConfigMapName is the name of the ConfigMap published into every namespace of every workspace.
+	ConfigMapName = "kcp-ca-bundle.crt"
+	// ConfigMapKey is the key of the PEM encoded CA bundle in the ConfigMap.
+	ConfigMapKey = "ca.crt"
+
+	DescriptionAnnotation = "kubernetes.io/description"
+	Description           = "Contains a CA bundle that can be used to verify kcp, i.e. the shards and the front-proxy, " +
+		"and other certificates trusted by the operator of this kcp deployment."
+)
+
+// NewController returns a new controller that publishes the given CA bundle as ConfigMap into every
+// namespace of every workspace, and updates the ConfigMaps when the bundle changes.
+func NewController(
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
+	configMapInformer kcpcorev1informers.ConfigMapClusterInformer,
+	caBundle dynamiccertificates.CAContentProvider,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
+
+	c := &controller{
+		queue: queue,
+
+		caBundle: caBundle,
+
+		getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+			return namespaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		listNamespaces: func() ([]*corev1.Namespace, error) {
+			return namespaceInformer.Lister().List(labels.Everything())
+		},
+		getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+			return configMapInformer.Lister().Cluster(clusterName).ConfigMaps(namespace).Get(name)
+		},
+		createConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+			_, err := kubeClusterClient.Cluster(clusterName.Path()).CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+			return err
+		},
+		updateConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+			_, err := kubeClusterClient.Cluster(clusterName.Path()).CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+			return err
+		},
+	}
+
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNamespace,
+		UpdateFunc: func(_, obj interface{}) { c.enqueueNamespace(obj) },
+	})
+
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			cm, ok := obj.(*corev1.ConfigMap)
+			return ok && cm.Name == ConfigMapName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(_, obj interface{}) { c.enqueueConfigMap(obj) },
+			DeleteFunc: c.enqueueConfigMap,
+		},
+	})
+
+	caBundle.AddListener(c)
+
+	return c, nil
+}
+
+// controller publishes a CA bundle ConfigMap into every namespace of every workspace, similar to
+// the kube-root-ca.crt ConfigMap, for workloads and webhooks to verify kcp-issued certificates.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	caBundle dynamiccertificates.CAContentProvider
+
+	getNamespace    func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error)
+	listNamespaces  func() ([]*corev1.Namespace, error)
+	getConfigMap    func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+	createConfigMap func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error
+	updateConfigMap func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error
+}
+
+// Enqueue enqueues all namespaces. It is called when the CA bundle changes.
+func (c *controller) Enqueue() {
+	namespaces, err := c.listNamespaces()
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, ns := range namespaces {
+		c.enqueueNamespace(ns)
+	}
+}
+
+func (c *controller) enqueueNamespace(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Namespace")
+	c.queue.Add(key)
+}
+
+func (c *controller) enqueueConfigMap(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a ConfigMap, but is %T", obj))
+		return
+	}
+
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(cm).String(), "", cm.Namespace)
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Namespace because of ConfigMap")
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+
+	ns, err := c.getNamespace(clusterName, name)
+	if apierrors.IsNotFound(err) {
+		return nil // namespace deleted before we handled it
+	}
+	if err != nil {
+		return err
+	}
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		return nil
+	}
+
+	data := map[string]string{ConfigMapKey: string(c.caBundle.CurrentCABundleContent())}
+
+	cm, err := c.getConfigMap(clusterName, name, ConfigMapName)
+	if apierrors.IsNotFound(err) {
+		logger.V(2).Info("creating ConfigMap", "name", ConfigMapName)
+		err := c.createConfigMap(ctx, clusterName, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   name,
+				Name:        ConfigMapName,
+				Annotations: map[string]string{DescriptionAnnotation: Description},
+			},
+			Data: data,
+		})
+		// don't retry a create if the namespace doesn't exist or is terminating
+		if apierrors.IsNotFound(err) || apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(cm.Data, data) && cm.Annotations[DescriptionAnnotation] == Description {
+		return nil
+	}
+
+	// copy so we don't modify the cache's instance of the configmap
+	cm = cm.DeepCopy()
+	cm.Data = data
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[DescriptionAnnotation] = Description
+
+	logger.V(2).Info("updating ConfigMap", "name", ConfigMapName)
+	return c.updateConfigMap(ctx, clusterName, cm)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cabundle
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+)
+
+type fakeCABundle []byte
+
+func (b fakeCABundle) Name() string                                      { return "fake" }
+func (b fakeCABundle) CurrentCABundleContent() []byte                    { return b }
+func (b fakeCABundle) VerifyOptions() (x509.VerifyOptions, bool)         { return x509.VerifyOptions{}, false }
+func (b fakeCABundle) AddListener(listener dynamiccertificates.Listener) {}
+
+func TestProcess(t *testing.T) {
+	const bundle = "-----BEGIN CERTIFICATE-----\nkcp\n-----END CERTIFICATE-----\n"
+
+	tests := map[string]struct {
+		namespace *corev1.Namespace
+		configMap *corev1.ConfigMap
+
+		wantCreated *corev1.ConfigMap
+		wantUpdated *corev1.ConfigMap
+	}{
+		"namespace not found": {},
+		"namespace terminating": {
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+			},
+		},
+		"missing ConfigMap": {
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			wantCreated: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        ConfigMapName,
+					Annotations: map[string]string{DescriptionAnnotation: Description},
+				},
+				Data: map[string]string{ConfigMapKey: bundle},
+			},
+		},
+		"up-to-date ConfigMap": {
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        ConfigMapName,
+					Annotations: map[string]string{DescriptionAnnotation: Description},
+				},
+				Data: map[string]string{ConfigMapKey: bundle},
+			},
+		},
+		"outdated ConfigMap": {
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      ConfigMapName,
+					Labels:    map[string]string{"team": "a"},
+				},
+				Data: map[string]string{ConfigMapKey: "old", "extra": "data"},
+			},
+			wantUpdated: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        ConfigMapName,
+					Labels:      map[string]string{"team": "a"},
+					Annotations: map[string]string{DescriptionAnnotation: Description},
+				},
+				Data: map[string]string{ConfigMapKey: bundle},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created, updated *corev1.ConfigMap
+			c := &controller{
+				caBundle: fakeCABundle(bundle),
+				getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+					require.Equal(t, logicalcluster.Name("root"), clusterName)
+					if tt.namespace == nil {
+						return nil, apierrors.NewNotFound(corev1.Resource("namespaces"), name)
+					}
+					return tt.namespace, nil
+				},
+				getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+					if tt.configMap == nil {
+						return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+					}
+					return tt.configMap, nil
+				},
+				createConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+					created = cm
+					return nil
+				},
+				updateConfigMap: func(ctx context.Context, clusterName logicalcluster.Name, cm *corev1.ConfigMap) error {
+					updated = cm
+					return nil
+				},
+			}
+
+			key := kcpcache.ToClusterAwareKey("root", "", "default")
+			require.NoError(t, c.process(context.Background(), key))
+			require.Equal(t, tt.wantCreated, created)
+			require.Equal(t, tt.wantUpdated, updated)
+		})
+	}
+}
@@ -232,6 +232,20 @@ func (d *logicalClusterResourcesDeleter) deleteEachItem(ctx context.Context, clu
 	return nil
 }
 
+// countItems returns the number of instances of gvr in the logical cluster, for the deletion report.
+// Errors are not fatal, but only lead to a lower count.
+func (d *logicalClusterResourcesDeleter) countItems(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) int {
+	list, _, err := d.listCollection(ctx, clusterName, gvr, verbs)
+	if err != nil {
+		klog.FromContext(ctx).V(5).Error(err, "unable to count items for the deletion report", "gvr", gvr)
+		return 0
+	}
+	if list == nil {
+		return 0
+	}
+	return len(list.Items)
+}
+
 type gvrDeletionMetadata struct {
 	// finalizerEstimateSeconds is an estimate of how much longer to wait.  zero means that no estimate has made and does not
 	// mean that all content has been removed.
@@ -354,6 +368,16 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 		deletionContentSuccessReason = "DiscoveryFailed"
 	}
 
+	countableResources := discovery.FilteredBy(and{
+		discovery.SupportsAllVerbs{Verbs: []string{"list"}},
+		isNotVirtualResource{},
+		isNamespaceScoped{},
+	}, resources)
+	namespacedResources, err := groupVersionResources(countableResources)
+	if err != nil {
+		logger.V(5).Error(err, "unable to parse namespaced resources for the deletion report")
+	}
+
 	deletableResources := discovery.FilteredBy(and{
 		discovery.SupportsAllVerbs{Verbs: []string{"delete"}},
 
@@ -378,6 +402,21 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 		deletionContentSuccessReason = "GroupVersionParsingFailed"
 	}
 
+	report, reportFound, err := ReportFrom(ws)
+	if err != nil {
+		logger.Error(err, "resetting deletion report")
+	}
+	if !reportFound {
+		// Count the content once up-front for the deletion report. Namespaced content is
+		// removed by namespace deletion, hence it has to be counted before the namespaces go away.
+		for gvr, verbs := range namespacedResources {
+			report.found(gvr, d.countItems(ctx, logicalcluster.From(ws), gvr, verbs))
+		}
+		for gvr, verbs := range groupVersionResources {
+			report.found(gvr, d.countItems(ctx, logicalcluster.From(ws), gvr, verbs))
+		}
+	}
+
 	numRemainingTotals := allGVRDeletionMetadata{
 		gvrToNumRemaining:        map[schema.GroupVersionResource]int{},
 		finalizersToNumRemaining: map[string]int{},
@@ -389,6 +428,7 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 			// If there is an error, hold on to it but proceed with all the remaining
 			// groupVersionResources.
 			deleteContentErrs = append(deleteContentErrs, err)
+			report.failed(gvr, err)
 		}
 		if gvrDeletionMetadata.finalizerEstimateSeconds > estimate {
 			estimate = gvrDeletionMetadata.finalizerEstimateSeconds
@@ -404,6 +444,10 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 		}
 	}
 
+	if err := report.recordOn(ws); err != nil {
+		logger.Error(err, "failed to record deletion report")
+	}
+
 	if len(deleteContentErrs) > 0 {
 		errs = append(errs, deleteContentErrs...)
 		deletionContentSuccessReason = "ContentDeletionFailed"
@@ -520,6 +564,13 @@ func (n isNotNamespaceScoped) Match(groupVersion string, r *metav1.APIResource)
 	return !r.Namespaced
 }
 
+type isNamespaceScoped struct{}
+
+// Match checks if the resource is a namespace scoped resource.
+func (n isNamespaceScoped) Match(groupVersion string, r *metav1.APIResource) bool {
+	return r.Namespaced
+}
+
 type and []discovery.ResourcePredicate
 
 func (a and) Match(groupVersion string, r *metav1.APIResource) bool {
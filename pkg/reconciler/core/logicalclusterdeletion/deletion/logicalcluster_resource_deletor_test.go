@@ -63,11 +63,15 @@ func TestWorkspaceTerminating(t *testing.T) {
 		gvrError                error
 		expectErrorOnDelete     error
 		expectConditions        conditionsv1alpha1.Conditions
+		expectReport            string
 	}{
 		{
 			name:           "discovery client error",
 			existingObject: []runtime.Object{},
 			metadataClientActionSet: []metaAction{
+				{"secrets", "list"},
+				{"nodelete", "list"},
+				{"customresourcedefinitions", "list"},
 				{"customresourcedefinitions", "delete-collection"},
 				{"customresourcedefinitions", "list"},
 			},
@@ -79,6 +83,7 @@ func TestWorkspaceTerminating(t *testing.T) {
 					Status: v1.ConditionFalse,
 				},
 			},
+			expectReport: "Deleted no content",
 		},
 		{
 			name: "do not delete ns scoped resource",
@@ -87,6 +92,9 @@ func TestWorkspaceTerminating(t *testing.T) {
 				newPartialObject("v1", "Secret", "s2", "ns2"),
 			},
 			metadataClientActionSet: []metaAction{
+				{"secrets", "list"},
+				{"nodelete", "list"},
+				{"customresourcedefinitions", "list"},
 				{"customresourcedefinitions", "delete-collection"},
 				{"customresourcedefinitions", "list"},
 			},
@@ -96,6 +104,7 @@ func TestWorkspaceTerminating(t *testing.T) {
 					Status: v1.ConditionTrue,
 				},
 			},
+			expectReport: "Deleted 2 secrets.v1",
 		},
 		{
 			name: "delete cluster scoped resource",
@@ -104,6 +113,9 @@ func TestWorkspaceTerminating(t *testing.T) {
 				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
 			},
 			metadataClientActionSet: []metaAction{
+				{"secrets", "list"},
+				{"nodelete", "list"},
+				{"customresourcedefinitions", "list"},
 				{"customresourcedefinitions", "delete-collection"},
 				{"customresourcedefinitions", "list"},
 			},
@@ -114,6 +126,7 @@ func TestWorkspaceTerminating(t *testing.T) {
 					Status: v1.ConditionFalse,
 				},
 			},
+			expectReport: "Deleted 2 customresourcedefinitions.v1.apiextensions.k8s.io",
 		},
 	}
 
@@ -125,6 +138,7 @@ func TestWorkspaceTerminating(t *testing.T) {
 			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, tt.existingObject...)
 			d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn)
 
+			ws := ws.DeepCopy()
 			err := d.Delete(context.TODO(), ws)
 			if !matchErrors(err, tt.expectErrorOnDelete) {
 				t.Errorf("expected error %q when syncing namespace, got %q", tt.expectErrorOnDelete, err)
@@ -140,6 +154,14 @@ func TestWorkspaceTerminating(t *testing.T) {
 				}
 			}
 
+			report, found, err := ReportFrom(ws)
+			if err != nil || !found {
+				t.Fatalf("expected deletion report, found=%v, err=%v", found, err)
+			}
+			if report.String() != tt.expectReport {
+				t.Errorf("expected deletion report %q, got %q", tt.expectReport, report.String())
+			}
+
 			if len(mockMetadataClient.Actions()) != len(tt.metadataClientActionSet) {
 				t.Fatalf("mismatched actions, expect %d actions, got %d actions", len(tt.metadataClientActionSet), len(mockMetadataClient.Actions()))
 			}
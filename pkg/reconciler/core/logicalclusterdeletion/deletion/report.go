@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+const (
+	// DeletionReportAnnotationKey is the annotation on a deleting LogicalCluster that accumulates
+	// the DeletionReport across deletion passes.
+	DeletionReportAnnotationKey = "internal.core.kcp.io/deletion-report"
+
+	// maxFailureMessageLength caps the error message kept per resource in the report.
+	maxFailureMessageLength = 256
+)
+
+// DeletionReport summarizes the content removed from a logical cluster during its deletion.
+type DeletionReport struct {
+	// Deleted maps a resource (in resource.version.group notation) to the number of
+	// instances found for deletion.
+	Deleted map[string]int `json:"deleted,omitempty"`
+	// Failed maps a resource (in resource.version.group notation) to the first error
+	// seen while deleting it. A resource can show up here even if a later pass deleted
+	// it successfully.
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// ReportFrom returns the DeletionReport recorded on the given LogicalCluster, and
+// whether one was found.
+func ReportFrom(logicalCluster *corev1alpha1.LogicalCluster) (*DeletionReport, bool, error) {
+	value, found := logicalCluster.Annotations[DeletionReportAnnotationKey]
+	if !found {
+		return &DeletionReport{}, false, nil
+	}
+	report := &DeletionReport{}
+	if err := json.Unmarshal([]byte(value), report); err != nil {
+		return &DeletionReport{}, true, fmt.Errorf("failed to decode %s annotation: %w", DeletionReportAnnotationKey, err)
+	}
+	return report, true, nil
+}
+
+// recordOn stores the report on the given LogicalCluster.
+func (r *DeletionReport) recordOn(logicalCluster *corev1alpha1.LogicalCluster) error {
+	bs, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionReportAnnotationKey] = string(bs)
+	return nil
+}
+
+// found records that count instances of gvr have been found before the deletion started.
+func (r *DeletionReport) found(gvr schema.GroupVersionResource, count int) {
+	if count == 0 {
+		return
+	}
+	if r.Deleted == nil {
+		r.Deleted = map[string]int{}
+	}
+	r.Deleted[reportKey(gvr)] = count
+}
+
+// failed records the first error seen while deleting instances of gvr. Later errors
+// are not recorded to not update the report on every deletion pass.
+func (r *DeletionReport) failed(gvr schema.GroupVersionResource, err error) {
+	if r.Failed == nil {
+		r.Failed = map[string]string{}
+	}
+	key := reportKey(gvr)
+	if _, found := r.Failed[key]; found {
+		return
+	}
+	r.Failed[key] = Truncate(err.Error(), maxFailureMessageLength)
+}
+
+// String returns a human readable summary of the report, sorted by resource.
+func (r *DeletionReport) String() string {
+	deleted := make([]string, 0, len(r.Deleted))
+	for resource, count := range r.Deleted {
+		deleted = append(deleted, fmt.Sprintf("%d %s", count, resource))
+	}
+	sort.Strings(deleted)
+
+	failed := make([]string, 0, len(r.Failed))
+	for resource, msg := range r.Failed {
+		failed = append(failed, fmt.Sprintf("%s: %s", resource, msg))
+	}
+	sort.Strings(failed)
+
+	var parts []string
+	if len(deleted) == 0 {
+		parts = append(parts, "Deleted no content")
+	} else {
+		parts = append(parts, fmt.Sprintf("Deleted %s", strings.Join(deleted, ", ")))
+	}
+	if len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf("failures: %s", strings.Join(failed, "; ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Truncate shortens s to at most max bytes, marking the cut with "...". It never splits a
+// multi-byte rune.
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+func reportKey(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Version)
+	}
+	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, gvr.Group)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		max  int
+		want string
+	}{
+		"short":              {s: "abc", max: 10, want: "abc"},
+		"exact":              {s: "abcdefghij", max: 10, want: "abcdefghij"},
+		"ascii":              {s: "abcdefghijk", max: 10, want: "abcdefg..."},
+		"cut inside a rune":  {s: "abcdef€xyz", max: 10, want: "abcdef..."},
+		"cut before a rune":  {s: "abcdefg€xyz", max: 10, want: "abcdefg..."},
+		"only runes":         {s: "€€€€€", max: 10, want: "€€..."},
+		"max below ellipsis": {s: "€€€€€", max: 3, want: "..."},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.max)
+			require.Equal(t, tt.want, got)
+			require.True(t, utf8.ValidString(got))
+			if tt.max >= len("...") {
+				require.LessOrEqual(t, len(got), tt.max)
+			}
+		})
+	}
+}
+
+func TestReportFailedKeepsValidUTF8(t *testing.T) {
+	r := &DeletionReport{}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	r.failed(gvr, errors.New(strings.Repeat("ä", maxFailureMessageLength)))
+	require.True(t, utf8.ValidString(r.Failed["configmaps.v1"]))
+	require.LessOrEqual(t, len(r.Failed["configmaps.v1"]), maxFailureMessageLength)
+}
@@ -18,6 +18,7 @@ package logicalclusterdeletion
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -29,6 +30,8 @@ import (
 	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -44,6 +47,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 	corev1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/core/v1alpha1"
@@ -53,6 +57,24 @@ import (
 
 const (
 	ControllerName = "kcp-logicalcluster-deletion"
+
+	// LogicalClusterDeletedReason is the reason of the event emitted on the owner of a
+	// logical cluster, e.g. a Workspace, carrying the deletion report.
+	LogicalClusterDeletedReason = "LogicalClusterDeleted"
+
+	// DeletionReportLabelKey is the label of the ConfigMaps in the parent cluster retaining
+	// deletion reports. Its value is the name of the deleted logical cluster.
+	DeletionReportLabelKey = "core.kcp.io/deletion-report"
+
+	// DeletionReportExpiresAtAnnotationKey is the annotation of the ConfigMaps retaining deletion
+	// reports, holding the RFC3339 time after which they are garbage collected.
+	DeletionReportExpiresAtAnnotationKey = "core.kcp.io/deletion-report-expires-at"
+
+	// maxEventMessageLength is the maximum length of an event message accepted by the API server.
+	maxEventMessageLength = 1024
+
+	// reportCollectionPeriod is how often expired deletion report ConfigMaps are garbage collected.
+	reportCollectionPeriod = 10 * time.Minute
 )
 
 var (
@@ -68,8 +90,9 @@ func NewController(
 	metadataClusterClient kcpmetadata.ClusterInterface,
 	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
 	discoverResourcesFn func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error),
+	reportRetention time.Duration,
 ) *Controller {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &Controller{
 		queue:                             queue,
@@ -81,6 +104,8 @@ func NewController(
 		logicalClusterLister:              logicalClusterInformer.Lister(),
 		deleter:                           deletion.NewWorkspacedResourcesDeleter(metadataClusterClient, discoverResourcesFn),
 		commit:                            committer.NewCommitter[*LogicalCluster, Patcher, *LogicalClusterSpec, *LogicalClusterStatus](kcpClusterClient.CoreV1alpha1().LogicalClusters()),
+		reportRetention:                   reportRetention,
+		now:                               time.Now,
 	}
 
 	logicalClusterInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
@@ -117,6 +142,7 @@ type Controller struct {
 	logicalClusterAdminConfig         *rest.Config
 	externalLogicalClusterAdminConfig *rest.Config
 	dynamicFrontProxyClient           kcpdynamic.ClusterInterface
+	kubeFrontProxyClient              kcpkubernetesclientset.ClusterInterface
 
 	metadataClusterClient kcpmetadata.ClusterInterface
 
@@ -125,6 +151,11 @@ type Controller struct {
 	deleter deletion.WorkspaceResourcesDeleterInterface
 
 	commit CommitFunc
+
+	// reportRetention is how long deletion reports are retained as ConfigMaps in the parent
+	// cluster. Zero only emits the report as event.
+	reportRetention time.Duration
+	now             func() time.Time
 }
 
 func (c *Controller) enqueue(obj interface{}) {
@@ -156,11 +187,20 @@ func (c *Controller) Start(ctx context.Context, numThreads int) {
 		return
 	}
 	c.dynamicFrontProxyClient = dynamicFrontProxyClient
+	kubeFrontProxyClient, err := kcpkubernetesclientset.NewForConfig(frontProxyConfig)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	c.kubeFrontProxyClient = kubeFrontProxyClient
 
 	for i := 0; i < numThreads; i++ {
 		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
 	}
 
+	// every shard collects the expired reports of the logical clusters it hosts.
+	go wait.UntilWithContext(ctx, c.collectExpiredReports, reportCollectionPeriod)
+
 	<-ctx.Done()
 }
 
@@ -246,8 +286,14 @@ func (c *Controller) process(ctx context.Context, key string) error {
 
 	errs := []error{deleteErr}
 
+	// The deletion report is recorded in the metadata, the progress in the status. Both
+	// cannot be committed at once. Commit the metadata first. The status follows in a later
+	// pass, which is queued anyway because the content is not gone yet.
 	oldResource := &Resource{ObjectMeta: logicalCluster.ObjectMeta, Spec: &logicalCluster.Spec, Status: &logicalCluster.Status}
 	newResource := &Resource{ObjectMeta: logicalClusterCopy.ObjectMeta, Spec: &logicalClusterCopy.Spec, Status: &logicalClusterCopy.Status}
+	if !equality.Semantic.DeepEqual(logicalCluster.ObjectMeta, logicalClusterCopy.ObjectMeta) {
+		newResource.Status = &logicalCluster.Status
+	}
 	if err := c.commit(ctx, oldResource, newResource); err != nil {
 		errs = append(errs, err)
 	}
@@ -299,7 +345,14 @@ func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.Log
 					logger.Info("owner has changed, skipping finalizer removal")
 					return fmt.Errorf("could not get owner %s %s/%s in cluster %s is of wrong UID: %w", gvr, ws.Spec.Owner.Namespace, ws.Spec.Owner.Name, ws.Spec.Owner.Cluster, err)
 				} else if err == nil {
+					// report while the owner still exists. Once its finalizer is removed, it
+					// can go away any moment.
 					finalizers := sets.NewString(obj.GetFinalizers()...)
+					deleteOwner := obj.GetDeletionTimestamp().IsZero() && ws.Spec.DirectlyDeletable
+					if finalizers.Has(corev1alpha1.LogicalClusterFinalizer) || deleteOwner {
+						c.reportDeletion(ctx, ws, obj)
+					}
+
 					if finalizers.Has(corev1alpha1.LogicalClusterFinalizer) {
 						logger.Info("removing finalizer from owner")
 						finalizers.Delete(corev1alpha1.LogicalClusterFinalizer)
@@ -310,12 +363,13 @@ func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.Log
 					}
 
 					// delete owner
-					if obj.GetDeletionTimestamp().IsZero() && ws.Spec.DirectlyDeletable {
+					if deleteOwner {
 						logger.Info("deleting owner")
 						if err := c.dynamicFrontProxyClient.Cluster(clusterPath).Resource(gvr).Namespace(ws.Spec.Owner.Namespace).Delete(ctx, ws.Spec.Owner.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); err != nil && !apierrors.IsNotFound(err) {
 							return fmt.Errorf("could not delete owner %s %s/%s in cluster %s: %w", gvr, ws.Spec.Owner.Namespace, ws.Spec.Owner.Name, ws.Spec.Owner.Cluster, err)
 						}
 					}
+
 				}
 			}
 
@@ -327,3 +381,124 @@ func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.Log
 
 	return nil
 }
+
+// reportDeletion emits the deletion report of the logical cluster as an event on the owner
+// in the parent cluster. The event is subject to the --event-ttl of the server hosting the owner.
+// With a report retention, the report is also stored as ConfigMap next to the owner.
+// Failures are logged, but do not block the deletion.
+func (c *Controller) reportDeletion(ctx context.Context, ws *corev1alpha1.LogicalCluster, owner metav1.Object) {
+	logger := klog.FromContext(ctx)
+
+	report, _, err := deletion.ReportFrom(ws)
+	if err != nil {
+		logger.Error(err, "failed to read deletion report")
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if len(report.Failed) > 0 {
+		eventType = corev1.EventTypeWarning
+	}
+	message := deletion.Truncate(fmt.Sprintf("Logical cluster %s deleted. %s", logicalcluster.From(ws), report), maxEventMessageLength)
+	namespace := ws.Spec.Owner.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ws.Spec.Owner.Name + ".",
+			Namespace:    namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: ws.Spec.Owner.APIVersion,
+			Kind:       ownerKind(owner),
+			Name:       ws.Spec.Owner.Name,
+			Namespace:  ws.Spec.Owner.Namespace,
+			UID:        ws.Spec.Owner.UID,
+		},
+		Reason:              LogicalClusterDeletedReason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: ControllerName},
+		ReportingController: ControllerName,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	if _, err := c.kubeFrontProxyClient.Cluster(logicalcluster.NewPath(ws.Spec.Owner.Cluster)).CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		logger.Error(err, "failed to create deletion report event on owner")
+	}
+
+	if c.reportRetention > 0 {
+		if err := c.retainReport(ctx, ws, namespace, report); err != nil {
+			logger.Error(err, "failed to retain deletion report")
+		}
+	}
+}
+
+// retainReport stores the report as ConfigMap in the given namespace of the owner's cluster,
+// expiring after the report retention. A report retained by an earlier pass is kept.
+func (c *Controller) retainReport(ctx context.Context, ws *corev1alpha1.LogicalCluster, namespace string, report *deletion.DeletionReport) error {
+	bs, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	clusterName := logicalcluster.From(ws)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deletion-report-" + clusterName.String(),
+			Namespace: namespace,
+			Labels: map[string]string{
+				DeletionReportLabelKey: clusterName.String(),
+			},
+			Annotations: map[string]string{
+				DeletionReportExpiresAtAnnotationKey: c.now().Add(c.reportRetention).UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{
+			"owner":       ws.Spec.Owner.Name,
+			"report.json": string(bs),
+			"summary":     report.String(),
+		},
+	}
+	_, err = c.kubeFrontProxyClient.Cluster(logicalcluster.NewPath(ws.Spec.Owner.Cluster)).CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// collectExpiredReports deletes the deletion report ConfigMaps of this shard whose retention
+// has passed.
+func (c *Controller) collectExpiredReports(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	cms, err := c.kubeClusterClient.CoreV1().ConfigMaps().List(ctx, metav1.ListOptions{LabelSelector: DeletionReportLabelKey})
+	if err != nil {
+		logger.Error(err, "failed to list deletion reports")
+		return
+	}
+	now := c.now()
+	for i := range cms.Items {
+		cm := &cms.Items[i]
+		expiresAt, err := time.Parse(time.RFC3339, cm.Annotations[DeletionReportExpiresAtAnnotationKey])
+		if err != nil || now.Before(expiresAt) {
+			continue
+		}
+		clusterName := logicalcluster.From(cm)
+		logger.V(2).Info("deleting expired deletion report", "cluster", clusterName, "namespace", cm.Namespace, "name", cm.Name)
+		err = c.kubeClusterClient.Cluster(clusterName.Path()).CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &cm.UID}})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			logger.Error(err, "failed to delete expired deletion report", "cluster", clusterName, "namespace", cm.Namespace, "name", cm.Name)
+		}
+	}
+}
+
+func ownerKind(owner metav1.Object) string {
+	if typed, ok := owner.(interface{ GetKind() string }); ok {
+		return typed.GetKind()
+	}
+	return ""
+}
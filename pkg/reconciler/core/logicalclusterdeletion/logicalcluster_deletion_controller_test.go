@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+func TestRetainedReportExpires(t *testing.T) {
+	ctx := context.Background()
+	client := kcpfakekubeclient.NewSimpleClientset()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &Controller{
+		kubeClusterClient:    client,
+		kubeFrontProxyClient: client,
+		reportRetention:      time.Hour,
+		now:                  func() time.Time { return now },
+	}
+
+	ws := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "child"},
+		},
+		Spec: corev1alpha1.LogicalClusterSpec{
+			Owner: &corev1alpha1.LogicalClusterOwner{Cluster: "parent", Name: "my-app"},
+		},
+	}
+	report := &deletion.DeletionReport{Deleted: map[string]int{"configmaps.v1": 3}}
+
+	require.NoError(t, c.retainReport(ctx, ws, metav1.NamespaceDefault, report))
+	// a later pass keeps the retained report.
+	now = now.Add(time.Minute)
+	require.NoError(t, c.retainReport(ctx, ws, metav1.NamespaceDefault, report))
+
+	cm, err := client.Cluster(logicalcluster.NewPath("parent")).CoreV1().ConfigMaps(metav1.NamespaceDefault).Get(ctx, "deletion-report-child", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "child", cm.Labels[DeletionReportLabelKey])
+	require.Equal(t, "2023-01-01T01:00:00Z", cm.Annotations[DeletionReportExpiresAtAnnotationKey])
+	require.Equal(t, "Deleted 3 configmaps.v1", cm.Data["summary"])
+
+	// the fake client does not set the cluster annotation like the server does.
+	cm.Annotations[logicalcluster.AnnotationKey] = "parent"
+	_, err = client.Cluster(logicalcluster.NewPath("parent")).CoreV1().ConfigMaps(metav1.NamespaceDefault).Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	c.collectExpiredReports(ctx)
+	_, err = client.Cluster(logicalcluster.NewPath("parent")).CoreV1().ConfigMaps(metav1.NamespaceDefault).Get(ctx, "deletion-report-child", metav1.GetOptions{})
+	require.NoError(t, err, "report must be retained before it expires")
+
+	now = now.Add(time.Hour)
+	c.collectExpiredReports(ctx)
+	_, err = client.Cluster(logicalcluster.NewPath("parent")).CoreV1().ConfigMaps(metav1.NamespaceDefault).Get(ctx, "deletion-report-child", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "expected expired report to be deleted, got %v", err)
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/informer"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/projection"
+	kcpratelimiter "github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
 	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
 )
@@ -77,7 +78,7 @@ func NewController(
 	informersStarted <-chan struct{},
 ) (*Controller, error) {
 	c := &Controller{
-		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		queue: workqueue.NewNamedRateLimitingQueue(kcpratelimiter.For(ControllerName), ControllerName),
 
 		dynamicDiscoverySharedInformerFactory: dynamicDiscoverySharedInformerFactory,
 		kubeClusterClient:                     kubeClusterClient,
@@ -269,7 +270,7 @@ func (c *Controller) startGarbageCollectorForLogicalCluster(ctx context.Context,
 
 	garbageCollectorController := garbageCollectorController{
 		clusterName: clusterName,
-		queue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "quota-"+clusterName.String()),
+		queue:       workqueue.NewNamedRateLimitingQueue(kcpratelimiter.For(ControllerName), "quota-"+clusterName.String()),
 		work: func(ctx context.Context) {
 			garbageCollector.ResyncMonitors(ctx, c.dynamicDiscoverySharedInformerFactory)
 		},
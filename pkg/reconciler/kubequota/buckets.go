@@ -0,0 +1,269 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubequota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/controller-manager/pkg/informerfactory"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// A quota bucket is a set of ResourceQuotas in a logical cluster together with the objects charged
+// against them. The consumer bucket (named "") holds the quotas and objects without the
+// apisv1alpha1.ProviderQuotaLabelKey label. Every value of that label forms a provider bucket, i.e.
+// objects created by a service provider through the APIExport virtual workspace are only charged
+// against the ResourceQuotas of the provider, not against the ones of the consumer.
+
+// BucketSelector returns the label selector matching the quotas and objects of the given bucket.
+func BucketSelector(bucket string) labels.Selector {
+	var req *labels.Requirement
+	var err error
+	if bucket == "" {
+		req, err = labels.NewRequirement(apisv1alpha1.ProviderQuotaLabelKey, selection.DoesNotExist, nil)
+	} else {
+		req, err = labels.NewRequirement(apisv1alpha1.ProviderQuotaLabelKey, selection.Equals, []string{bucket})
+	}
+	if err != nil {
+		// the bucket is a label value computed by us, hence this is a programming error
+		panic(fmt.Sprintf("invalid quota bucket %q: %v", bucket, err))
+	}
+	return labels.NewSelector().Add(*req)
+}
+
+// BucketOf returns the quota bucket of the given object.
+func BucketOf(obj metav1.Object) string {
+	return obj.GetLabels()[apisv1alpha1.ProviderQuotaLabelKey]
+}
+
+func inBucket(obj runtime.Object, bucket string) bool {
+	metaObj, err := meta.Accessor(obj)
+	return err == nil && BucketOf(metaObj) == bucket
+}
+
+func andSelector(selector, bucketSelector labels.Selector) labels.Selector {
+	if selector == nil {
+		return bucketSelector
+	}
+	reqs, _ := bucketSelector.Requirements()
+	return selector.Add(reqs...)
+}
+
+// NewBucketResourceQuotaLister returns a ResourceQuota lister only returning the quotas of the given bucket.
+func NewBucketResourceQuotaLister(delegate corelisters.ResourceQuotaLister, bucket string) corelisters.ResourceQuotaLister {
+	return &bucketResourceQuotaLister{delegate: delegate, bucket: bucket}
+}
+
+type bucketResourceQuotaLister struct {
+	delegate corelisters.ResourceQuotaLister
+	bucket   string
+}
+
+func (l *bucketResourceQuotaLister) List(selector labels.Selector) ([]*corev1.ResourceQuota, error) {
+	return l.delegate.List(andSelector(selector, BucketSelector(l.bucket)))
+}
+
+func (l *bucketResourceQuotaLister) ResourceQuotas(namespace string) corelisters.ResourceQuotaNamespaceLister {
+	return &bucketResourceQuotaNamespaceLister{delegate: l.delegate.ResourceQuotas(namespace), bucket: l.bucket}
+}
+
+type bucketResourceQuotaNamespaceLister struct {
+	delegate corelisters.ResourceQuotaNamespaceLister
+	bucket   string
+}
+
+func (l *bucketResourceQuotaNamespaceLister) List(selector labels.Selector) ([]*corev1.ResourceQuota, error) {
+	return l.delegate.List(andSelector(selector, BucketSelector(l.bucket)))
+}
+
+func (l *bucketResourceQuotaNamespaceLister) Get(name string) (*corev1.ResourceQuota, error) {
+	quota, err := l.delegate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if BucketOf(quota) != l.bucket {
+		return nil, errors.NewNotFound(corev1.Resource("resourcequotas"), name)
+	}
+	return quota, nil
+}
+
+// NewBucketKubeClient returns a kube client whose ResourceQuota list requests only return the quotas
+// of the given bucket. Everything else is passed through.
+func NewBucketKubeClient(delegate kubernetes.Interface, bucket string) kubernetes.Interface {
+	return &bucketKubeClient{Interface: delegate, bucket: bucket}
+}
+
+type bucketKubeClient struct {
+	kubernetes.Interface
+	bucket string
+}
+
+func (c *bucketKubeClient) CoreV1() typedcorev1.CoreV1Interface {
+	return &bucketCoreV1Client{CoreV1Interface: c.Interface.CoreV1(), bucket: c.bucket}
+}
+
+type bucketCoreV1Client struct {
+	typedcorev1.CoreV1Interface
+	bucket string
+}
+
+func (c *bucketCoreV1Client) ResourceQuotas(namespace string) typedcorev1.ResourceQuotaInterface {
+	return &bucketResourceQuotaClient{ResourceQuotaInterface: c.CoreV1Interface.ResourceQuotas(namespace), bucket: c.bucket}
+}
+
+type bucketResourceQuotaClient struct {
+	typedcorev1.ResourceQuotaInterface
+	bucket string
+}
+
+func (c *bucketResourceQuotaClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ResourceQuotaList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	opts.LabelSelector = andSelector(selector, BucketSelector(c.bucket)).String()
+	return c.ResourceQuotaInterface.List(ctx, opts)
+}
+
+// newBucketResourceQuotaInformer returns a ResourceQuota informer that only notifies about and lists
+// the quotas of the given bucket.
+func newBucketResourceQuotaInformer(delegate coreinformers.ResourceQuotaInformer, bucket string) coreinformers.ResourceQuotaInformer {
+	return &bucketResourceQuotaInformer{delegate: delegate, bucket: bucket}
+}
+
+type bucketResourceQuotaInformer struct {
+	delegate coreinformers.ResourceQuotaInformer
+	bucket   string
+}
+
+func (i *bucketResourceQuotaInformer) Informer() cache.SharedIndexInformer {
+	return &bucketSharedIndexInformer{SharedIndexInformer: i.delegate.Informer(), bucket: i.bucket}
+}
+
+func (i *bucketResourceQuotaInformer) Lister() corelisters.ResourceQuotaLister {
+	return NewBucketResourceQuotaLister(i.delegate.Lister(), i.bucket)
+}
+
+type bucketSharedIndexInformer struct {
+	cache.SharedIndexInformer
+	bucket string
+}
+
+func (i *bucketSharedIndexInformer) filter(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, ok := obj.(metav1.Object)
+	return ok && BucketOf(metaObj) == i.bucket
+}
+
+func (i *bucketSharedIndexInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	i.SharedIndexInformer.AddEventHandler(cache.FilteringResourceEventHandler{FilterFunc: i.filter, Handler: handler})
+}
+
+func (i *bucketSharedIndexInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{FilterFunc: i.filter, Handler: handler}, resyncPeriod)
+}
+
+// newBucketInformerFactory returns an informer factory whose listers only return the objects of the
+// given bucket. Quota usage computed from these listers is the usage of the bucket.
+func newBucketInformerFactory(delegate informerfactory.InformerFactory, bucket string) informerfactory.InformerFactory {
+	return &bucketInformerFactory{InformerFactory: delegate, bucket: bucket}
+}
+
+type bucketInformerFactory struct {
+	informerfactory.InformerFactory
+	bucket string
+}
+
+func (f *bucketInformerFactory) ForResource(resource schema.GroupVersionResource) (informers.GenericInformer, error) {
+	inf, err := f.InformerFactory.ForResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	return &bucketGenericInformer{GenericInformer: inf, bucket: f.bucket}, nil
+}
+
+type bucketGenericInformer struct {
+	informers.GenericInformer
+	bucket string
+}
+
+func (i *bucketGenericInformer) Lister() cache.GenericLister {
+	return &bucketGenericLister{delegate: i.GenericInformer.Lister(), bucket: i.bucket}
+}
+
+type bucketGenericLister struct {
+	delegate cache.GenericLister
+	bucket   string
+}
+
+func (l *bucketGenericLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	return l.delegate.List(andSelector(selector, BucketSelector(l.bucket)))
+}
+
+func (l *bucketGenericLister) Get(name string) (runtime.Object, error) {
+	obj, err := l.delegate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !inBucket(obj, l.bucket) {
+		return nil, errors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return obj, nil
+}
+
+func (l *bucketGenericLister) ByNamespace(namespace string) cache.GenericNamespaceLister {
+	return &bucketGenericNamespaceLister{delegate: l.delegate.ByNamespace(namespace), bucket: l.bucket}
+}
+
+type bucketGenericNamespaceLister struct {
+	delegate cache.GenericNamespaceLister
+	bucket   string
+}
+
+func (l *bucketGenericNamespaceLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	return l.delegate.List(andSelector(selector, BucketSelector(l.bucket)))
+}
+
+func (l *bucketGenericNamespaceLister) Get(name string) (runtime.Object, error) {
+	obj, err := l.delegate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !inBucket(obj, l.bucket) {
+		return nil, errors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return obj, nil
+}
@@ -28,8 +28,11 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/quota/v1/generic"
 	"k8s.io/client-go/tools/cache"
@@ -42,6 +45,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/informer"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	kcpratelimiter "github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
 )
@@ -71,13 +75,14 @@ type Controller struct {
 
 	// lock guards the fields in this group
 	lock        sync.RWMutex
-	cancelFuncs map[logicalcluster.Name]func()
+	cancelFuncs map[logicalcluster.Name]map[string]func()
 
 	resourceQuotaClusterInformer        kcpcorev1informers.ResourceQuotaClusterInformer
 	scopingGenericSharedInformerFactory scopeableInformerFactory
 
 	// For better testability
-	getLogicalCluster func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	getLogicalCluster  func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	listResourceQuotas func(clusterName logicalcluster.Name) ([]*corev1.ResourceQuota, error)
 }
 
 // NewController creates a new Controller.
@@ -92,7 +97,7 @@ func NewController(
 	informersStarted <-chan struct{},
 ) (*Controller, error) {
 	c := &Controller{
-		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		queue: workqueue.NewNamedRateLimitingQueue(kcpratelimiter.For(ControllerName), ControllerName),
 
 		dynamicDiscoverySharedInformerFactory: dynamicDiscoverySharedInformerFactory,
 		kubeClusterClient:                     kubeClusterClient,
@@ -103,7 +108,7 @@ func NewController(
 
 		workersPerLogicalCluster: workersPerLogicalCluster,
 
-		cancelFuncs: map[logicalcluster.Name]func(){},
+		cancelFuncs: map[logicalcluster.Name]map[string]func(){},
 
 		scopingGenericSharedInformerFactory: dynamicDiscoverySharedInformerFactory,
 		resourceQuotaClusterInformer:        kubeInformerFactory.Core().V1().ResourceQuotas(),
@@ -111,8 +116,28 @@ func NewController(
 		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
 			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
 		},
+		listResourceQuotas: func(clusterName logicalcluster.Name) ([]*corev1.ResourceQuota, error) {
+			return kubeInformerFactory.Core().V1().ResourceQuotas().Lister().Cluster(clusterName).List(labels.Everything())
+		},
 	}
 
+	// ResourceQuotas of providers come and go. Every provider has its own quota controller.
+	kubeInformerFactory.Core().V1().ResourceQuotas().Informer().AddEventHandler(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				quota, ok := obj.(*corev1.ResourceQuota)
+				return ok && BucketOf(quota) != ""
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.enqueueResourceQuota,
+				DeleteFunc: c.enqueueResourceQuota,
+			},
+		},
+	)
+
 	logicalClusterInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: c.enqueue,
@@ -139,6 +164,22 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.Add(key)
 }
 
+// enqueueResourceQuota adds the key of the logical cluster of a ResourceQuota to the queue.
+func (c *Controller) enqueueResourceQuota(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	quota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return
+	}
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(quota).String(), "", corev1alpha1.LogicalClusterName)
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(2).Info("queueing Workspace because of provider ResourceQuota", "resourceQuota", quota.Namespace+"/"+quota.Name)
+	c.queue.Add(key)
+}
+
 // Start starts the controller.
 func (c *Controller) Start(ctx context.Context, numThreads int) {
 	defer utilruntime.HandleCrash()
@@ -205,18 +246,16 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	ws, err := c.getLogicalCluster(clusterName)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			logger.V(2).Info("Workspace not found - stopping quota controller for it (if needed)")
+			logger.V(2).Info("Workspace not found - stopping quota controllers for it (if needed)")
 
 			c.lock.Lock()
-			cancel, ok := c.cancelFuncs[clusterName]
-			if ok {
+			for bucket, cancel := range c.cancelFuncs[clusterName] {
 				cancel()
-				delete(c.cancelFuncs, clusterName)
+				c.dynamicDiscoverySharedInformerFactory.Unsubscribe(subscriberName(clusterName, bucket))
 			}
+			delete(c.cancelFuncs, clusterName)
 			c.lock.Unlock()
 
-			c.dynamicDiscoverySharedInformerFactory.Unsubscribe("quota-" + clusterName.String())
-
 			return nil
 		}
 
@@ -224,30 +263,68 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	}
 	logger = logging.WithObject(logger, ws)
 
+	// The consumer bucket always exists, provider buckets as long as there are ResourceQuotas for them.
+	buckets := sets.NewString("")
+	quotas, err := c.listResourceQuotas(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		buckets.Insert(BucketOf(quota))
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	_, found := c.cancelFuncs[clusterName]
-	if found {
-		logger.V(4).Info("quota controller already exists")
-		return nil
+	cancelFuncs, found := c.cancelFuncs[clusterName]
+	if !found {
+		cancelFuncs = map[string]func(){}
+		c.cancelFuncs[clusterName] = cancelFuncs
 	}
 
-	logger.V(2).Info("starting quota controller")
+	for bucket, cancel := range cancelFuncs {
+		if buckets.Has(bucket) {
+			continue
+		}
+		logger.V(2).Info("stopping quota controller for provider", "bucket", bucket)
+		cancel()
+		delete(cancelFuncs, bucket)
+		c.dynamicDiscoverySharedInformerFactory.Unsubscribe(subscriberName(clusterName, bucket))
+	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	ctx = klog.NewContext(ctx, logger)
-	c.cancelFuncs[clusterName] = cancel
+	for _, bucket := range buckets.List() {
+		if _, found := cancelFuncs[bucket]; found {
+			logger.V(4).Info("quota controller already exists", "bucket", bucket)
+			continue
+		}
 
-	if err := c.startQuotaForLogicalCluster(ctx, clusterName); err != nil {
-		cancel()
-		return fmt.Errorf("error starting quota controller for cluster %q: %w", clusterName, err)
+		logger.V(2).Info("starting quota controller", "bucket", bucket)
+
+		ctx, cancel := context.WithCancel(ctx)
+		ctx = klog.NewContext(ctx, logger.WithValues("bucket", bucket))
+		cancelFuncs[bucket] = cancel
+
+		if err := c.startQuotaForLogicalCluster(ctx, clusterName, bucket); err != nil {
+			cancel()
+			delete(cancelFuncs, bucket)
+			return fmt.Errorf("error starting quota controller for cluster %q and bucket %q: %w", clusterName, bucket, err)
+		}
 	}
 
 	return nil
 }
 
-func (c *Controller) startQuotaForLogicalCluster(ctx context.Context, clusterName logicalcluster.Name) error {
+// subscriberName returns the name of the API change subscription of the quota controller of a bucket.
+func subscriberName(clusterName logicalcluster.Name, bucket string) string {
+	if bucket == "" {
+		return "quota-" + clusterName.String()
+	}
+	return "quota-" + clusterName.String() + "-" + bucket
+}
+
+// startQuotaForLogicalCluster starts a quota controller for the given bucket of the logical cluster. The controller
+// only maintains the ResourceQuotas of the bucket and computes their usage from the objects of the bucket.
+func (c *Controller) startQuotaForLogicalCluster(ctx context.Context, clusterName logicalcluster.Name, bucket string) error {
 	logger := klog.FromContext(ctx)
 	resourceQuotaControllerClient := c.kubeClusterClient.Cluster(clusterName.Path())
 
@@ -259,9 +336,9 @@ func (c *Controller) startQuotaForLogicalCluster(ctx context.Context, clusterNam
 
 	resourceQuotaControllerOptions := &resourcequota.ControllerOptions{
 		QuotaClient:           resourceQuotaControllerClient.CoreV1(),
-		ResourceQuotaInformer: c.resourceQuotaClusterInformer.Cluster(clusterName),
+		ResourceQuotaInformer: newBucketResourceQuotaInformer(c.resourceQuotaClusterInformer.Cluster(clusterName), bucket),
 		ResyncPeriod:          controller.StaticResyncPeriodFunc(c.quotaRecalculationPeriod),
-		InformerFactory:       c.scopingGenericSharedInformerFactory.Cluster(clusterName),
+		InformerFactory:       newBucketInformerFactory(c.scopingGenericSharedInformerFactory.Cluster(clusterName), bucket),
 		ReplenishmentResyncPeriod: func() time.Duration {
 			return c.fullResyncPeriod
 		},
@@ -272,7 +349,7 @@ func (c *Controller) startQuotaForLogicalCluster(ctx context.Context, clusterNam
 		ClusterName:          clusterName,
 	}
 	if resourceQuotaControllerClient.CoreV1().RESTClient().GetRateLimiter() != nil {
-		if err := ratelimiter.RegisterMetricAndTrackRateLimiterUsage(clusterName.String()+"-resource_quota_controller", resourceQuotaControllerClient.CoreV1().RESTClient().GetRateLimiter()); err != nil {
+		if err := ratelimiter.RegisterMetricAndTrackRateLimiterUsage(subscriberName(clusterName, bucket)+"-resource_quota_controller", resourceQuotaControllerClient.CoreV1().RESTClient().GetRateLimiter()); err != nil {
 			return err
 		}
 	}
@@ -288,14 +365,14 @@ func (c *Controller) startQuotaForLogicalCluster(ctx context.Context, clusterNam
 
 	quotaController := quotaController{
 		clusterName: clusterName,
-		queue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "quota-"+clusterName.String()),
+		queue:       workqueue.NewNamedRateLimitingQueue(kcpratelimiter.For(ControllerName), subscriberName(clusterName, bucket)),
 		work: func(ctx context.Context) {
 			resourceQuotaController.UpdateMonitors(ctx, c.dynamicDiscoverySharedInformerFactory.ServerPreferredResources)
 		},
 	}
 	go quotaController.Start(ctx)
 
-	apisChanged := c.dynamicDiscoverySharedInformerFactory.Subscribe("quota-" + clusterName.String())
+	apisChanged := c.dynamicDiscoverySharedInformerFactory.Subscribe(subscriberName(clusterName, bucket))
 
 	go func() {
 		for {
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimiter provides the workqueue rate limiters of the kcp controllers, configurable
+// per controller to tune reconciliation for large shards.
+package ratelimiter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// AllControllers is the controller name of a Config applying to all controllers without their own.
+const AllControllers = "*"
+
+// Config is the rate limiting of a controller workqueue. Failed keys are retried with exponential
+// back-off from BaseDelay to MaxDelay, and all keys are limited by an overall token bucket.
+type Config struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	QPS       float64
+	Burst     int
+}
+
+// DefaultConfig is the rate limiting of workqueue.DefaultControllerRateLimiter.
+var DefaultConfig = Config{
+	BaseDelay: 5 * time.Millisecond,
+	MaxDelay:  1000 * time.Second,
+	QPS:       10,
+	Burst:     100,
+}
+
+var (
+	lock    sync.RWMutex
+	configs = map[string]Config{}
+)
+
+// SetConfigs sets the rate limiting per controller name, with AllControllers applying to controllers
+// not listed. It must be called before the controllers are created.
+func SetConfigs(c map[string]Config) {
+	lock.Lock()
+	defer lock.Unlock()
+	configs = c
+}
+
+// ConfigFor returns the rate limiting of the named controller.
+func ConfigFor(name string) Config {
+	lock.RLock()
+	defer lock.RUnlock()
+	if c, found := configs[name]; found {
+		return c
+	}
+	if c, found := configs[AllControllers]; found {
+		return c
+	}
+	return DefaultConfig
+}
+
+// For returns a new rate limiter for the workqueue of the named controller.
+func For(name string) workqueue.RateLimiter {
+	c := ConfigFor(name)
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(c.BaseDelay, c.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(c.QPS), c.Burst)},
+	)
+}
+
+// Parse parses a Config from <base-delay>:<max-delay>:<qps>[:<burst>], e.g. "10ms:5m:50:500". The
+// burst defaults to ten times the QPS.
+func Parse(s string) (Config, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Config{}, fmt.Errorf("invalid rate limit %q, expected <base-delay>:<max-delay>:<qps>[:<burst>]", s)
+	}
+
+	var c Config
+	var err error
+	if c.BaseDelay, err = time.ParseDuration(parts[0]); err != nil {
+		return Config{}, fmt.Errorf("invalid base delay in rate limit %q: %w", s, err)
+	}
+	if c.MaxDelay, err = time.ParseDuration(parts[1]); err != nil {
+		return Config{}, fmt.Errorf("invalid max delay in rate limit %q: %w", s, err)
+	}
+	if c.QPS, err = strconv.ParseFloat(parts[2], 64); err != nil {
+		return Config{}, fmt.Errorf("invalid QPS in rate limit %q: %w", s, err)
+	}
+	c.Burst = int(c.QPS * 10)
+	if len(parts) == 4 {
+		if c.Burst, err = strconv.Atoi(parts[3]); err != nil {
+			return Config{}, fmt.Errorf("invalid burst in rate limit %q: %w", s, err)
+		}
+	}
+
+	switch {
+	case c.BaseDelay <= 0 || c.MaxDelay <= 0:
+		return Config{}, fmt.Errorf("invalid rate limit %q, delays must be positive", s)
+	case c.BaseDelay > c.MaxDelay:
+		return Config{}, fmt.Errorf("invalid rate limit %q, base delay must not exceed max delay", s)
+	case c.QPS <= 0 || c.Burst < 1:
+		return Config{}, fmt.Errorf("invalid rate limit %q, QPS and burst must be positive", s)
+	}
+	return c, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    Config
+		wantErr string
+	}{
+		"with burst":            {in: "10ms:5m:50:500", want: Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 5 * time.Minute, QPS: 50, Burst: 500}},
+		"burst defaulted":       {in: "1s:1m:2.5", want: Config{BaseDelay: time.Second, MaxDelay: time.Minute, QPS: 2.5, Burst: 25}},
+		"too few fields":        {in: "10ms:5m", wantErr: "expected <base-delay>:<max-delay>:<qps>[:<burst>]"},
+		"invalid duration":      {in: "10:5m:50", wantErr: "invalid base delay"},
+		"base exceeds max":      {in: "10m:5m:50", wantErr: "base delay must not exceed max delay"},
+		"non-positive qps":      {in: "10ms:5m:0", wantErr: "QPS and burst must be positive"},
+		"burst below one":       {in: "10ms:5m:0.05", wantErr: "QPS and burst must be positive"},
+		"invalid burst":         {in: "10ms:5m:50:many", wantErr: "invalid burst"},
+		"non-positive duration": {in: "0s:5m:50", wantErr: "delays must be positive"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfigFor(t *testing.T) {
+	defer SetConfigs(map[string]Config{})
+
+	require.Equal(t, DefaultConfig, ConfigFor("kcp-apibinding"))
+
+	specific := Config{BaseDelay: time.Second, MaxDelay: time.Minute, QPS: 1, Burst: 10}
+	SetConfigs(map[string]Config{"kcp-apibinding": specific})
+	require.Equal(t, specific, ConfigFor("kcp-apibinding"))
+	require.Equal(t, DefaultConfig, ConfigFor("kcp-apiexport"))
+
+	all := Config{BaseDelay: time.Millisecond, MaxDelay: time.Second, QPS: 100, Burst: 1000}
+	SetConfigs(map[string]Config{"kcp-apibinding": specific, AllControllers: all})
+	require.Equal(t, specific, ConfigFor("kcp-apibinding"))
+	require.Equal(t, all, ConfigFor("kcp-apiexport"))
+
+	limiter := For("kcp-apibinding")
+	require.Equal(t, time.Second, limiter.When("key"))
+	require.Equal(t, 2*time.Second, limiter.When("key"))
+	limiter.Forget("key")
+	require.Equal(t, time.Second, limiter.When("key"))
+}
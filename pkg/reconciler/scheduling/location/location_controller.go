@@ -35,6 +35,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
@@ -54,8 +55,9 @@ func NewController(
 	kcpClusterClient kcpclientset.ClusterInterface,
 	locationInformer schedulingv1alpha1informers.LocationClusterInformer,
 	syncTargetInformer workloadv1alpha1informers.SyncTargetClusterInformer,
+	syncTargetPoolInformer workloadv1alpha1informers.SyncTargetPoolClusterInformer,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(controllerName), controllerName)
 
 	c := &controller{
 		queue: queue,
@@ -70,6 +72,7 @@ func NewController(
 		kcpClusterClient: kcpClusterClient,
 		locationLister:   locationInformer.Lister(),
 		syncTargetLister: syncTargetInformer.Lister(),
+		poolLister:       syncTargetPoolInformer.Lister(),
 		commit:           committer.NewCommitter[*Location, Patcher, *LocationSpec, *LocationStatus](kcpClusterClient.SchedulingV1alpha1().Locations()),
 	}
 
@@ -80,7 +83,7 @@ func NewController(
 	})
 
 	syncTargetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) { c.enqueueSyncTarget(obj) },
+		AddFunc: func(obj interface{}) { c.enqueueLocationsInCluster(obj, "SyncTarget") },
 		UpdateFunc: func(old, obj interface{}) {
 			oldCluster, ok := old.(*workloadv1alpha1.SyncTarget)
 			if !ok {
@@ -98,10 +101,28 @@ func NewController(
 			oldCluster.Status.LastSyncerHeartbeatTime = objCluster.Status.LastSyncerHeartbeatTime
 
 			if !equality.Semantic.DeepEqual(oldCluster, objCluster) {
-				c.enqueueSyncTarget(obj)
+				c.enqueueLocationsInCluster(obj, "SyncTarget")
 			}
 		},
-		DeleteFunc: func(obj interface{}) { c.enqueueSyncTarget(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueLocationsInCluster(obj, "SyncTarget") },
+	})
+
+	syncTargetPoolInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { c.enqueueLocationsInCluster(obj, "SyncTargetPool") },
+		UpdateFunc: func(old, obj interface{}) {
+			oldPool, ok := old.(*workloadv1alpha1.SyncTargetPool)
+			if !ok {
+				return
+			}
+			objPool, ok := obj.(*workloadv1alpha1.SyncTargetPool)
+			if !ok {
+				return
+			}
+			if !equality.Semantic.DeepEqual(oldPool.Spec, objPool.Spec) || !equality.Semantic.DeepEqual(oldPool.Labels, objPool.Labels) {
+				c.enqueueLocationsInCluster(obj, "SyncTargetPool")
+			}
+		},
+		DeleteFunc: func(obj interface{}) { c.enqueueLocationsInCluster(obj, "SyncTargetPool") },
 	})
 
 	return c, nil
@@ -123,6 +144,7 @@ type controller struct {
 
 	locationLister   schedulingv1alpha1listers.LocationClusterLister
 	syncTargetLister workloadv1alpha1listers.SyncTargetClusterLister
+	poolLister       workloadv1alpha1listers.SyncTargetPoolClusterLister
 
 	commit CommitFunc
 }
@@ -139,8 +161,8 @@ func (c *controller) enqueueLocation(obj interface{}) {
 	c.queue.Add(key)
 }
 
-// enqueueSyncTarget maps a SyncTarget to LocationDomain for enqueuing.
-func (c *controller) enqueueSyncTarget(obj interface{}) {
+// enqueueLocationsInCluster maps a SyncTarget or SyncTargetPool to the Locations of its cluster for enqueuing.
+func (c *controller) enqueueLocationsInCluster(obj interface{}, kind string) {
 	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -158,14 +180,14 @@ func (c *controller) enqueueSyncTarget(obj interface{}) {
 	}
 
 	for _, domain := range domains {
-		syncTargetKey := key
+		objKey := key
 		key, err := kcpcache.MetaClusterNamespaceKeyFunc(domain)
 		if err != nil {
 			runtime.HandleError(err)
 			return
 		}
 		logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), controllerName), key)
-		logger.V(2).Info("queueing Location because SyncTarget changed", "SyncTarget", syncTargetKey)
+		logger.V(2).Info("queueing Location because "+kind+" changed", kind, objKey)
 		c.queue.Add(key)
 	}
 }
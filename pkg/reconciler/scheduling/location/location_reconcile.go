@@ -45,9 +45,10 @@ type reconciler interface {
 
 // statusReconciler reconciles Location objects' status.
 type statusReconciler struct {
-	listSyncTargets func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error)
-	updateLocation  func(ctx context.Context, clusterName logicalcluster.Path, location *schedulingv1alpha1.Location) (*schedulingv1alpha1.Location, error)
-	enqueueAfter    func(*schedulingv1alpha1.Location, time.Duration)
+	listSyncTargets     func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error)
+	listSyncTargetPools func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error)
+	updateLocation      func(ctx context.Context, clusterName logicalcluster.Path, location *schedulingv1alpha1.Location) (*schedulingv1alpha1.Location, error)
+	enqueueAfter        func(*schedulingv1alpha1.Location, time.Duration)
 }
 
 func (r *statusReconciler) reconcile(ctx context.Context, location *schedulingv1alpha1.Location) (reconcileStatus, error) {
@@ -83,8 +84,20 @@ func (r *statusReconciler) reconcile(ctx context.Context, location *schedulingv1
 	}
 
 	// update status
-	locationClusters, err := LocationSyncTargets(syncTargets, location)
-	if err != nil {
+	var locationClusters []*workloadv1alpha1.SyncTarget
+	if IsSyncTargetPoolLocation(location) {
+		pools, err := r.listSyncTargetPools(clusterName)
+		if err != nil {
+			return reconcileStatusStop, err
+		}
+		locationPools, err := LocationSyncTargetPools(pools, location)
+		if err != nil {
+			return reconcileStatusStop, err
+		}
+		if locationClusters, err = PoolSyncTargets(syncTargets, locationPools...); err != nil {
+			return reconcileStatusStop, err
+		}
+	} else if locationClusters, err = LocationSyncTargets(syncTargets, location); err != nil {
 		return reconcileStatusStop, err
 	}
 	available := len(FilterReady(locationClusters))
@@ -101,9 +114,10 @@ func uint32Ptr(i uint32) *uint32 {
 func (c *controller) reconcile(ctx context.Context, location *schedulingv1alpha1.Location) error {
 	reconcilers := []reconciler{
 		&statusReconciler{
-			listSyncTargets: c.listSyncTarget,
-			updateLocation:  c.updateLocation,
-			enqueueAfter:    c.enqueueAfter,
+			listSyncTargets:     c.listSyncTarget,
+			listSyncTargetPools: c.listSyncTargetPools,
+			updateLocation:      c.updateLocation,
+			enqueueAfter:        c.enqueueAfter,
 		},
 	}
 
@@ -126,6 +140,10 @@ func (c *controller) listSyncTarget(clusterName logicalcluster.Name) ([]*workloa
 	return c.syncTargetLister.Cluster(clusterName).List(labels.Everything())
 }
 
+func (c *controller) listSyncTargetPools(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error) {
+	return c.poolLister.Cluster(clusterName).List(labels.Everything())
+}
+
 func (c *controller) updateLocation(ctx context.Context, clusterName logicalcluster.Path, location *schedulingv1alpha1.Location) (*schedulingv1alpha1.Location, error) {
 	return c.kcpClusterClient.Cluster(clusterName).SchedulingV1alpha1().Locations().Update(ctx, location, metav1.UpdateOptions{})
 }
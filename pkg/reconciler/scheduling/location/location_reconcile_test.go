@@ -95,10 +95,13 @@ func TestLocationStatusReconciler(t *testing.T) {
 	}
 	usEast1WithoutLabelString := usEast1.DeepCopy()
 	usEast1WithoutLabelString.Annotations = nil
+	usEast1Pools := usEast1.DeepCopy()
+	usEast1Pools.Spec.Resource.Resource = "synctargetpools"
 
 	tests := map[string]struct {
 		location    *schedulingv1alpha1.Location
 		syncTargets map[logicalcluster.Path][]*workloadv1alpha1.SyncTarget
+		pools       map[logicalcluster.Path][]*workloadv1alpha1.SyncTargetPool
 
 		listSyncTargetError error
 		updateLocationError error
@@ -142,6 +145,24 @@ func TestLocationStatusReconciler(t *testing.T) {
 			wantLocation:        and(availableInstances(1), instances(4)),
 			wantReconcileStatus: reconcileStatusContinue,
 		},
+		"with sync target pools": {
+			location: usEast1Pools,
+			syncTargets: map[logicalcluster.Path][]*workloadv1alpha1.SyncTarget{
+				logicalcluster.NewPath("root:org:negotiation-workspace"): {
+					withLabels(withConditions(cluster("a-1"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"pool": "a"}),
+					withLabels(withConditions(cluster("a-2"), conditionsv1alpha1.Condition{Type: "Ready", Status: "False"}), map[string]string{"pool": "a"}),
+					withLabels(withConditions(cluster("b-1"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"pool": "b"}),
+				},
+			},
+			pools: map[logicalcluster.Path][]*workloadv1alpha1.SyncTargetPool{
+				logicalcluster.NewPath("root:org:negotiation-workspace"): {
+					pool("a", map[string]string{"region": "us-east1"}, map[string]string{"pool": "a"}),
+					pool("b", map[string]string{"region": "us-west1"}, map[string]string{"pool": "b"}),
+				},
+			},
+			wantLocation:        and(availableInstances(1), instances(2)),
+			wantReconcileStatus: reconcileStatusContinue,
+		},
 	}
 
 	for name, tc := range tests {
@@ -155,6 +176,9 @@ func TestLocationStatusReconciler(t *testing.T) {
 					}
 					return tc.syncTargets[clusterName.Path()], nil
 				},
+				listSyncTargetPools: func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error) {
+					return tc.pools[clusterName.Path()], nil
+				},
 				updateLocation: func(ctx context.Context, clusterName logicalcluster.Path, location *schedulingv1alpha1.Location) (*schedulingv1alpha1.Location, error) {
 					if tc.updateLocationError != nil {
 						return nil, tc.updateLocationError
@@ -203,6 +227,18 @@ func cluster(name string) *workloadv1alpha1.SyncTarget {
 	return ret
 }
 
+func pool(name string, labels, selector map[string]string) *workloadv1alpha1.SyncTargetPool {
+	return &workloadv1alpha1.SyncTargetPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: workloadv1alpha1.SyncTargetPoolSpec{
+			Selector: metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
 func withLabels(cluster *workloadv1alpha1.SyncTarget, labels map[string]string) *workloadv1alpha1.SyncTarget {
 	cluster.Labels = labels
 	return cluster
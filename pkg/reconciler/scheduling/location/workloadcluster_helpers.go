@@ -46,6 +46,47 @@ func LocationSyncTargets(syncTargets []*workloadv1alpha1.SyncTarget, location *s
 	return ret, nil
 }
 
+// IsSyncTargetPoolLocation returns true if the instances of the location are SyncTargetPools
+// instead of SyncTargets.
+func IsSyncTargetPoolLocation(location *schedulingv1alpha1.Location) bool {
+	return location.Spec.Resource.Resource == "synctargetpools"
+}
+
+// LocationSyncTargetPools returns a list of sync target pools that match the given location definition.
+func LocationSyncTargetPools(pools []*workloadv1alpha1.SyncTargetPool, location *schedulingv1alpha1.Location) (ret []*workloadv1alpha1.SyncTargetPool, err error) {
+	sel, err := metav1.LabelSelectorAsSelector(location.Spec.InstanceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector %v in location %s: %w", location.Spec.InstanceSelector, location.Name, err)
+	}
+
+	for _, pool := range pools {
+		if sel.Matches(labels.Set(pool.Labels)) {
+			ret = append(ret, pool)
+		}
+	}
+
+	return ret, nil
+}
+
+// PoolSyncTargets returns a list of sync targets that are members of the given pools, without duplicates.
+func PoolSyncTargets(syncTargets []*workloadv1alpha1.SyncTarget, pools ...*workloadv1alpha1.SyncTargetPool) (ret []*workloadv1alpha1.SyncTarget, err error) {
+	seen := map[string]bool{}
+	for _, pool := range pools {
+		sel, err := metav1.LabelSelectorAsSelector(&pool.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse label selector %v in pool %s: %w", pool.Spec.Selector, pool.Name, err)
+		}
+		for _, wc := range syncTargets {
+			if !seen[wc.Name] && sel.Matches(labels.Set(wc.Labels)) {
+				seen[wc.Name] = true
+				ret = append(ret, wc)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
 // FilterReady returns the ready sync targets.
 func FilterReady(syncTargets []*workloadv1alpha1.SyncTarget) []*workloadv1alpha1.SyncTarget {
 	ready := make([]*workloadv1alpha1.SyncTarget, 0, len(syncTargets))
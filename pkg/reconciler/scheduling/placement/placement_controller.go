@@ -35,12 +35,15 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/events"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
@@ -61,8 +64,9 @@ func NewController(
 	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
 	locationInformer schedulingv1alpha1informers.LocationClusterInformer,
 	placementInformer schedulingv1alpha1informers.PlacementClusterInformer,
+	eventRecorder record.EventRecorder,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &controller{
 		queue: queue,
@@ -75,6 +79,7 @@ func NewController(
 			queue.AddAfter(key, duration)
 		},
 		kcpClusterClient: kcpClusterClient,
+		eventRecorder:    eventRecorder,
 
 		namespaceLister: namespaceInformer.Lister(),
 
@@ -160,6 +165,7 @@ type controller struct {
 	enqueueAfter func(*corev1.Namespace, time.Duration)
 
 	kcpClusterClient kcpclientset.ClusterInterface
+	eventRecorder    record.EventRecorder
 
 	namespaceLister corev1listers.NamespaceClusterLister
 
@@ -337,6 +343,9 @@ func (c *controller) process(ctx context.Context, key string) error {
 	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
 	if err := c.commit(ctx, oldResource, newResource); err != nil {
 		errs = append(errs, err)
+	} else {
+		// Surface failures in the workspace of the placement, where users can see them.
+		events.RecordConditionChanges(ctx, c.eventRecorder, old, obj)
 	}
 
 	return utilerrors.NewAggregate(errs)
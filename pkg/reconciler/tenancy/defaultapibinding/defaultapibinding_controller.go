@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultapibinding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-default-apibinding"
+
+	// byOwnerCluster indexes LogicalClusters by the logical cluster of the workspace owning them.
+	byOwnerCluster = "defaultapibinding-byOwnerCluster"
+)
+
+// NewController returns a new controller which creates the APIBindings declared by the
+// DefaultAPIBindings of a workspace in all of its child workspaces, keeps their accepted claims in
+// sync, and deletes them again when the DefaultAPIBinding goes away.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+	defaultAPIBindingInformer, globalDefaultAPIBindingInformer apisv1alpha1informers.DefaultAPIBindingClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	apiExportInformer, globalAPIExportInformer apisv1alpha1informers.APIExportClusterInformer,
+) (*controller, error) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName),
+
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		listChildLogicalClusters: func(clusterName logicalcluster.Name) ([]*corev1alpha1.LogicalCluster, error) {
+			return indexers.ByIndex[*corev1alpha1.LogicalCluster](logicalClusterInformer.Informer().GetIndexer(), byOwnerCluster, clusterName.String())
+		},
+		listDefaultAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.DefaultAPIBinding, error) {
+			defaults, err := defaultAPIBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
+			if err != nil || len(defaults) > 0 {
+				return defaults, err
+			}
+			// the parent might live on another shard.
+			return globalDefaultAPIBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return apiBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+		createAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
+			return err
+		},
+		updateAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Update(ctx, binding, metav1.UpdateOptions{})
+			return err
+		},
+		deleteAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, name string) error {
+			return kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Delete(ctx, name, metav1.DeleteOptions{})
+		},
+
+		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), apiExportInformer.Informer().GetIndexer(), globalAPIExportInformer.Informer().GetIndexer(), path, name)
+		},
+	}
+
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+
+	indexers.AddIfNotPresentOrDie(logicalClusterInformer.Informer().GetIndexer(), cache.Indexers{
+		byOwnerCluster: indexByOwnerCluster,
+	})
+	indexers.AddIfNotPresentOrDie(apiExportInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+	indexers.AddIfNotPresentOrDie(globalAPIExportInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+
+	logicalClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueLogicalCluster(obj, logger)
+		},
+	})
+
+	for _, informer := range []apisv1alpha1informers.DefaultAPIBindingClusterInformer{defaultAPIBindingInformer, globalDefaultAPIBindingInformer} {
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueDefaultAPIBinding(obj, logger)
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				c.enqueueDefaultAPIBinding(obj, logger)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueDefaultAPIBinding(obj, logger)
+			},
+		})
+	}
+
+	apiBindingInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			binding, ok := obj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return false
+			}
+			_, found := binding.Labels[apisv1alpha1.DefaultAPIBindingLabelKey]
+			return found
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueAPIBinding(obj, logger)
+			},
+		},
+	})
+
+	return c, nil
+}
+
+// controller creates the APIBindings of the DefaultAPIBindings of the parent workspace in a
+// workspace. It is keyed by LogicalCluster.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getLogicalCluster        func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	listChildLogicalClusters func(clusterName logicalcluster.Name) ([]*corev1alpha1.LogicalCluster, error)
+	listDefaultAPIBindings   func(clusterName logicalcluster.Name) ([]*apisv1alpha1.DefaultAPIBinding, error)
+
+	listAPIBindings  func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	createAPIBinding func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error
+	updateAPIBinding func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error
+	deleteAPIBinding func(ctx context.Context, clusterName logicalcluster.Path, name string) error
+
+	getAPIExport func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+}
+
+func indexByOwnerCluster(obj interface{}) ([]string, error) {
+	logicalCluster, ok := obj.(*corev1alpha1.LogicalCluster)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a LogicalCluster, but is %T", obj)
+	}
+	if logicalCluster.Spec.Owner == nil || logicalCluster.Spec.Owner.Cluster == "" {
+		return []string{}, nil
+	}
+	return []string{logicalCluster.Spec.Owner.Cluster}, nil
+}
+
+func (c *controller) enqueueLogicalCluster(obj interface{}, logger logr.Logger) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(2).Info("queueing LogicalCluster")
+	c.queue.Add(key)
+}
+
+// enqueueDefaultAPIBinding enqueues the child workspaces of the workspace of the DefaultAPIBinding
+// living on this shard.
+func (c *controller) enqueueDefaultAPIBinding(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	defaultAPIBinding, ok := obj.(*apisv1alpha1.DefaultAPIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a DefaultAPIBinding, but is %T", obj))
+		return
+	}
+
+	children, err := c.listChildLogicalClusters(logicalcluster.From(defaultAPIBinding))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger = logging.WithObject(logger, defaultAPIBinding)
+	for _, child := range children {
+		c.enqueueLogicalCluster(child, logger.WithValues("reason", "DefaultAPIBinding changed"))
+	}
+}
+
+// enqueueAPIBinding enqueues the workspace of a deleted APIBinding created for a DefaultAPIBinding,
+// to recreate it.
+func (c *controller) enqueueAPIBinding(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	apiBinding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj))
+		return
+	}
+
+	logicalCluster, err := c.getLogicalCluster(logicalcluster.From(apiBinding))
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			runtime.HandleError(err)
+		}
+		return
+	}
+	c.enqueueLogicalCluster(logicalCluster, logging.WithObject(logger, apiBinding))
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%s: failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "unable to decode key")
+		return nil
+	}
+
+	logicalCluster, err := c.getLogicalCluster(clusterName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil // deleted in the meantime
+	}
+
+	logger = logging.WithObject(logger, logicalCluster)
+	ctx = klog.NewContext(ctx, logger)
+
+	return c.reconcile(ctx, logicalCluster)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultapibinding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+func (c *controller) reconcile(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	logger := klog.FromContext(ctx)
+
+	if !logicalCluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	owner := logicalCluster.Spec.Owner
+	if owner == nil || owner.Cluster == "" || owner.Resource != "workspaces" {
+		return nil // not a child workspace
+	}
+	clusterName := logicalcluster.From(logicalCluster)
+	parent := logicalcluster.Name(owner.Cluster)
+
+	defaults, err := c.listDefaultAPIBindings(parent)
+	if err != nil {
+		return err
+	}
+	bindings, err := c.listAPIBindings(clusterName)
+	if err != nil {
+		return err
+	}
+
+	// the export each DefaultAPIBinding binds, with the path defaulted to the parent.
+	exportRefs := make(map[string]apisv1alpha1.ExportBindingReference, len(defaults))
+	defaultsByName := make(map[string]*apisv1alpha1.DefaultAPIBinding, len(defaults))
+	for _, d := range defaults {
+		defaultsByName[d.Name] = d
+		exportRef := d.Spec.Export
+		if exportRef.Path == "" {
+			exportRef.Path = parent.String()
+		}
+		exportRefs[d.Name] = exportRef
+	}
+
+	var errs []error
+	existing := make(map[string]bool, len(bindings))
+	for _, binding := range bindings {
+		existing[binding.Name] = true
+
+		name, found := binding.Labels[apisv1alpha1.DefaultAPIBindingLabelKey]
+		if !found {
+			continue // not ours, or taken over by the workspace owner
+		}
+		exportRef, stillDefault := exportRefs[name]
+		if stillDefault && name == binding.Name && binding.Spec.Reference.Export != nil && *binding.Spec.Reference.Export == exportRef {
+			if err := c.updateAcceptedClaims(ctx, clusterName, defaultsByName[name], binding); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		logger.V(2).Info("deleting APIBinding of removed or changed DefaultAPIBinding", "apiBinding", binding.Name)
+		if err := c.deleteAPIBinding(ctx, clusterName.Path(), binding.Name); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, d := range defaults {
+		if _, found := existing[d.Name]; found {
+			// wait for a deleted binding to be gone, and leave unlabeled ones alone.
+			continue
+		}
+		exportRef := exportRefs[d.Name]
+
+		apiExport, err := c.getAPIExport(logicalcluster.NewPath(exportRef.Path), exportRef.Name)
+		if err != nil {
+			// retry, the APIExport might not be replicated yet.
+			errs = append(errs, fmt.Errorf("failed to get APIExport %s|%s of DefaultAPIBinding %s: %w", exportRef.Path, exportRef.Name, d.Name, err))
+			continue
+		}
+
+		apiBinding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: d.Name,
+				Labels: map[string]string{
+					apisv1alpha1.DefaultAPIBindingLabelKey: d.Name,
+				},
+			},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{
+					Export: &exportRef,
+				},
+			},
+		}
+		claims := acceptedClaims(d, apiExport)
+		for _, claim := range claims {
+			apiBinding.Spec.PermissionClaims = append(apiBinding.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+				PermissionClaim: claim,
+				State:           apisv1alpha1.ClaimAccepted,
+			})
+		}
+		if len(claims) > 0 {
+			bs, err := json.Marshal(claims)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			apiBinding.Annotations = map[string]string{apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey: string(bs)}
+		}
+
+		logger.V(2).Info("creating APIBinding for DefaultAPIBinding", "apiBinding", apiBinding.Name)
+		if err := c.createAPIBinding(ctx, clusterName.Path(), apiBinding); err != nil && !apierrors.IsAlreadyExists(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// updateAcceptedClaims applies changes of the accepted claims of the DefaultAPIBinding to the
+// APIBinding created for it. Claims accepted before for the DefaultAPIBinding, but not anymore, are
+// removed, leaving those accepted by the workspace owner alone.
+func (c *controller) updateAcceptedClaims(ctx context.Context, clusterName logicalcluster.Name, d *apisv1alpha1.DefaultAPIBinding, binding *apisv1alpha1.APIBinding) error {
+	logger := klog.FromContext(ctx)
+
+	exportRef := binding.Spec.Reference.Export
+	apiExport, err := c.getAPIExport(logicalcluster.NewPath(exportRef.Path), exportRef.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get APIExport %s|%s of DefaultAPIBinding %s: %w", exportRef.Path, exportRef.Name, d.Name, err)
+	}
+
+	var previous []apisv1alpha1.PermissionClaim
+	if value, found := binding.Annotations[apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey]; found {
+		if err := json.Unmarshal([]byte(value), &previous); err != nil {
+			// treat as nothing accepted before, the annotation is rewritten below.
+			logger.Error(err, "failed to decode accepted claims of APIBinding", "apiBinding", binding.Name)
+		}
+	}
+	claims := acceptedClaims(d, apiExport)
+
+	updated := binding.DeepCopy()
+	updated.Spec.PermissionClaims = nil
+	for _, acceptable := range binding.Spec.PermissionClaims {
+		if containsClaim(previous, acceptable.PermissionClaim) && !containsClaim(claims, acceptable.PermissionClaim) {
+			continue // no longer accepted for the DefaultAPIBinding
+		}
+		if containsClaim(claims, acceptable.PermissionClaim) {
+			continue // re-added as accepted below
+		}
+		updated.Spec.PermissionClaims = append(updated.Spec.PermissionClaims, acceptable)
+	}
+	for _, claim := range claims {
+		updated.Spec.PermissionClaims = append(updated.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+			PermissionClaim: claim,
+			State:           apisv1alpha1.ClaimAccepted,
+		})
+	}
+	if len(claims) > 0 {
+		bs, err := json.Marshal(claims)
+		if err != nil {
+			return err
+		}
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey] = string(bs)
+	} else {
+		delete(updated.Annotations, apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey)
+	}
+
+	if equality.Semantic.DeepEqual(binding.Spec.PermissionClaims, updated.Spec.PermissionClaims) &&
+		binding.Annotations[apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey] == updated.Annotations[apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey] {
+		return nil
+	}
+
+	logger.V(2).Info("updating accepted claims of APIBinding for DefaultAPIBinding", "apiBinding", binding.Name)
+	return c.updateAPIBinding(ctx, clusterName.Path(), updated)
+}
+
+// acceptedClaims returns the claims of the APIExport the DefaultAPIBinding accepts. Other claims
+// are left to the workspace owner.
+func acceptedClaims(d *apisv1alpha1.DefaultAPIBinding, apiExport *apisv1alpha1.APIExport) []apisv1alpha1.PermissionClaim {
+	var claims []apisv1alpha1.PermissionClaim
+	for _, claim := range apiExport.Spec.PermissionClaims {
+		if accepts(d, claim) {
+			claims = append(claims, claim)
+		}
+	}
+	return claims
+}
+
+func containsClaim(claims []apisv1alpha1.PermissionClaim, claim apisv1alpha1.PermissionClaim) bool {
+	for _, c := range claims {
+		if equality.Semantic.DeepEqual(c, claim) {
+			return true
+		}
+	}
+	return false
+}
+
+// accepts returns whether the DefaultAPIBinding accepts the given claim of the APIExport.
+func accepts(d *apisv1alpha1.DefaultAPIBinding, claim apisv1alpha1.PermissionClaim) bool {
+	for _, accepted := range d.Spec.AcceptedPermissionClaims {
+		if equality.Semantic.DeepEqual(accepted, claim) {
+			return true
+		}
+	}
+	return false
+}
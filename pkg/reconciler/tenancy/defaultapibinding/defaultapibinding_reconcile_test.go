@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultapibinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	claim := func(resource string) apisv1alpha1.PermissionClaim {
+		return apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: resource}, All: true}
+	}
+	defaultAPIBinding := func(name, path, export string, claims ...apisv1alpha1.PermissionClaim) *apisv1alpha1.DefaultAPIBinding {
+		return &apisv1alpha1.DefaultAPIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "parent"}},
+			Spec: apisv1alpha1.DefaultAPIBindingSpec{
+				Export:                   apisv1alpha1.ExportBindingReference{Path: path, Name: export},
+				AcceptedPermissionClaims: claims,
+			},
+		}
+	}
+	apiBinding := func(name, label, path, export string) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "child"}},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: path, Name: export}},
+			},
+		}
+		if label != "" {
+			b.Labels = map[string]string{apisv1alpha1.DefaultAPIBindingLabelKey: label}
+		}
+		return b
+	}
+	withClaims := func(b *apisv1alpha1.APIBinding, annotation string, claims ...apisv1alpha1.AcceptablePermissionClaim) *apisv1alpha1.APIBinding {
+		if annotation != "" {
+			b.Annotations[apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey] = annotation
+		}
+		b.Spec.PermissionClaims = claims
+		return b
+	}
+	child := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: map[string]string{logicalcluster.AnnotationKey: "child"}},
+		Spec: corev1alpha1.LogicalClusterSpec{
+			Owner: &corev1alpha1.LogicalClusterOwner{APIVersion: "tenancy.kcp.io/v1alpha1", Resource: "workspaces", Name: "child", Cluster: "parent"},
+		},
+	}
+	exports := map[string]*apisv1alpha1.APIExport{
+		"parent|widgets": {
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+			Spec:       apisv1alpha1.APIExportSpec{PermissionClaims: []apisv1alpha1.PermissionClaim{claim("secrets"), claim("configmaps")}},
+		},
+		"root:org|gadgets": {ObjectMeta: metav1.ObjectMeta{Name: "gadgets"}},
+	}
+
+	tests := map[string]struct {
+		logicalCluster *corev1alpha1.LogicalCluster
+		defaults       []*apisv1alpha1.DefaultAPIBinding
+		bindings       []*apisv1alpha1.APIBinding
+
+		wantCreated []*apisv1alpha1.APIBinding
+		wantUpdated []*apisv1alpha1.APIBinding
+		wantDeleted []string
+		wantError   bool
+	}{
+		"creates bindings with path defaulted to the parent, accepting listed claims": {
+			logicalCluster: child,
+			defaults: []*apisv1alpha1.DefaultAPIBinding{
+				defaultAPIBinding("widgets", "", "widgets", claim("configmaps")),
+				defaultAPIBinding("gadgets", "root:org", "gadgets"),
+			},
+			wantCreated: []*apisv1alpha1.APIBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "widgets",
+						Labels:      map[string]string{apisv1alpha1.DefaultAPIBindingLabelKey: "widgets"},
+						Annotations: map[string]string{apisv1alpha1.DefaultAPIBindingAcceptedClaimsAnnotationKey: `[{"resource":"configmaps","all":true}]`},
+					},
+					Spec: apisv1alpha1.APIBindingSpec{
+						Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "parent", Name: "widgets"}},
+						PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+							{PermissionClaim: claim("configmaps"), State: apisv1alpha1.ClaimAccepted},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "gadgets", Labels: map[string]string{apisv1alpha1.DefaultAPIBindingLabelKey: "gadgets"}},
+					Spec: apisv1alpha1.APIBindingSpec{
+						Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "root:org", Name: "gadgets"}},
+					},
+				},
+			},
+		},
+		"leaves existing bindings alone": {
+			logicalCluster: child,
+			defaults:       []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("widgets", "", "widgets"), defaultAPIBinding("gadgets", "root:org", "gadgets")},
+			bindings: []*apisv1alpha1.APIBinding{
+				apiBinding("widgets", "widgets", "parent", "widgets"),
+				apiBinding("gadgets", "", "root:org", "other"),
+			},
+		},
+		"applies changed accepted claims, leaving those of the workspace owner alone": {
+			logicalCluster: child,
+			defaults:       []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("widgets", "", "widgets", claim("secrets"))},
+			bindings: []*apisv1alpha1.APIBinding{
+				withClaims(apiBinding("widgets", "widgets", "parent", "widgets"), `[{"resource":"configmaps","all":true}]`,
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim("configmaps"), State: apisv1alpha1.ClaimAccepted},
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim("services"), State: apisv1alpha1.ClaimAccepted},
+				),
+			},
+			wantUpdated: []*apisv1alpha1.APIBinding{
+				withClaims(apiBinding("widgets", "widgets", "parent", "widgets"), `[{"resource":"secrets","all":true}]`,
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim("services"), State: apisv1alpha1.ClaimAccepted},
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim("secrets"), State: apisv1alpha1.ClaimAccepted},
+				),
+			},
+		},
+		"removes the last accepted claim": {
+			logicalCluster: child,
+			defaults:       []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("widgets", "", "widgets")},
+			bindings: []*apisv1alpha1.APIBinding{
+				withClaims(apiBinding("widgets", "widgets", "parent", "widgets"), `[{"resource":"configmaps","all":true}]`,
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim("configmaps"), State: apisv1alpha1.ClaimAccepted},
+				),
+			},
+			wantUpdated: []*apisv1alpha1.APIBinding{
+				withClaims(apiBinding("widgets", "widgets", "parent", "widgets"), ""),
+			},
+		},
+		"deletes bindings of removed or changed defaults, but not unlabeled ones": {
+			logicalCluster: child,
+			defaults:       []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("widgets", "root:org", "widgets")},
+			bindings: []*apisv1alpha1.APIBinding{
+				apiBinding("widgets", "widgets", "parent", "widgets"),
+				apiBinding("gadgets", "gadgets", "root:org", "gadgets"),
+				apiBinding("mine", "", "root:org", "gadgets"),
+			},
+			wantDeleted: []string{"widgets", "gadgets"},
+		},
+		"retries on missing APIExport": {
+			logicalCluster: child,
+			defaults:       []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("missing", "", "missing")},
+			wantError:      true,
+		},
+		"ignores workspaces without parent": {
+			logicalCluster: &corev1alpha1.LogicalCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: map[string]string{logicalcluster.AnnotationKey: "child"}},
+			},
+			defaults: []*apisv1alpha1.DefaultAPIBinding{defaultAPIBinding("widgets", "", "widgets")},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created, updated []*apisv1alpha1.APIBinding
+			var deleted []string
+			c := &controller{
+				listDefaultAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.DefaultAPIBinding, error) {
+					require.Equal(t, logicalcluster.Name("parent"), clusterName)
+					return tt.defaults, nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return tt.bindings, nil
+				},
+				createAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+					require.Equal(t, "child", clusterName.String())
+					created = append(created, binding)
+					return nil
+				},
+				updateAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+					require.Equal(t, "child", clusterName.String())
+					updated = append(updated, binding)
+					return nil
+				},
+				deleteAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, name string) error {
+					require.Equal(t, "child", clusterName.String())
+					deleted = append(deleted, name)
+					return nil
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					if export, found := exports[path.String()+"|"+name]; found {
+						return export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+			}
+
+			err := c.reconcile(context.Background(), tt.logicalCluster)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantCreated, created)
+			require.Equal(t, tt.wantUpdated, updated)
+			require.Equal(t, tt.wantDeleted, deleted)
+		})
+	}
+}
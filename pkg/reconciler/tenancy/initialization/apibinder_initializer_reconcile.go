@@ -26,6 +26,7 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/permissionclaim"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/tenancy/initialization"
@@ -117,78 +119,78 @@ func (b *APIBinder) reconcile(ctx context.Context, logicalCluster *corev1alpha1.
 	requiredExportRefs := map[tenancyv1alpha1.APIExportReference]struct{}{}
 	someExportsMissing := false
 
-	for _, wt := range wts {
-		logger := logging.WithObject(logger, wt)
-		logger.V(2).Info("attempting to initialize APIBindings")
-
-		for i := range wt.Spec.DefaultAPIBindings {
-			exportRef := wt.Spec.DefaultAPIBindings[i]
-			if exportRef.Path == "" {
-				exportRef.Path = logicalcluster.From(wt).String()
-			}
-			apiExport, err := b.getAPIExport(logicalcluster.NewPath(exportRef.Path), exportRef.Export)
-			if err != nil {
-				if !someExportsMissing {
-					errors = append(errors, fmt.Errorf("unable to complete initialization: unable to find at least 1 APIExport"))
-				}
-				someExportsMissing = true
-				continue
+	for _, defaultBinding := range defaultAPIBindingsFor(wts) {
+		exportRef := defaultBinding.exportRef
+		apiExport, err := b.getAPIExport(logicalcluster.NewPath(exportRef.Path), exportRef.Export)
+		if err != nil {
+			if !someExportsMissing {
+				errors = append(errors, fmt.Errorf("unable to complete initialization: unable to find at least 1 APIExport"))
 			}
+			someExportsMissing = true
+			continue
+		}
 
-			// Keep track of unique set of expected exports across all WTs
-			requiredExportRefs[exportRef] = struct{}{}
+		// Keep track of unique set of expected exports across all WTs
+		requiredExportRefs[exportRef] = struct{}{}
 
-			logger := logger.WithValues("apiExport.path", exportRef.Path, "apiExport.name", exportRef.Export)
-			ctx := klog.NewContext(ctx, logger)
+		logger := logger.WithValues("apiExport.path", exportRef.Path, "apiExport.name", exportRef.Export)
+		ctx := klog.NewContext(ctx, logger)
 
-			apiBindingName := generateAPIBindingName(clusterName, exportRef.Path, exportRef.Export)
-			logger = logger.WithValues("apiBindingName", apiBindingName)
+		apiBindingName := defaultBinding.apiBindingName(clusterName, workspaceNameOf(logicalCluster))
+		if msgs := validation.IsDNS1123Subdomain(apiBindingName); len(msgs) > 0 {
+			errors = append(errors, fmt.Errorf("invalid APIBinding name %q for APIExport %s|%s: %s", apiBindingName, exportRef.Path, exportRef.Export, strings.Join(msgs, ", ")))
+			continue
+		}
+		logger = logger.WithValues("apiBindingName", apiBindingName)
 
-			if _, err = b.getAPIBinding(clusterName, apiBindingName); err == nil {
-				logger.V(4).Info("APIBinding already exists - skipping creation")
-				continue
-			}
+		if _, err = b.getAPIBinding(clusterName, apiBindingName); err == nil {
+			logger.V(4).Info("APIBinding already exists - skipping creation")
+			continue
+		}
 
-			apiBinding := &apisv1alpha1.APIBinding{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: apiBindingName,
-				},
-				Spec: apisv1alpha1.APIBindingSpec{
-					Reference: apisv1alpha1.BindingReference{
-						Export: &apisv1alpha1.ExportBindingReference{
-							Path: exportRef.Path,
-							Name: apiExport.Name,
-						},
+		apiBinding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: apiBindingName,
+			},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{
+					Export: &apisv1alpha1.ExportBindingReference{
+						Path: exportRef.Path,
+						Name: apiExport.Name,
 					},
 				},
-			}
-
-			for i := range apiExport.Spec.PermissionClaims {
-				exportClaim := apiExport.Spec.PermissionClaims[i]
+			},
+		}
 
-				acceptedClaim := apisv1alpha1.AcceptablePermissionClaim{
-					PermissionClaim: exportClaim,
-					State:           apisv1alpha1.ClaimAccepted,
-				}
+		for i := range apiExport.Spec.PermissionClaims {
+			claim, accepted := defaultBinding.accept(apiExport.Spec.PermissionClaims[i])
+			if !accepted {
+				// left to the workspace owner
+				continue
+			}
 
-				apiBinding.Spec.PermissionClaims = append(apiBinding.Spec.PermissionClaims, acceptedClaim)
+			acceptedClaim := apisv1alpha1.AcceptablePermissionClaim{
+				PermissionClaim: claim,
+				State:           apisv1alpha1.ClaimAccepted,
 			}
 
-			logger = logging.WithObject(logger, apiBinding)
+			apiBinding.Spec.PermissionClaims = append(apiBinding.Spec.PermissionClaims, acceptedClaim)
+		}
 
-			logger.V(2).Info("trying to create APIBinding")
-			if _, err := b.createAPIBinding(ctx, clusterName.Path(), apiBinding); err != nil {
-				if apierrors.IsAlreadyExists(err) {
-					logger.V(2).Info("APIBinding already exists")
-					continue
-				}
+		logger = logging.WithObject(logger, apiBinding)
 
-				errors = append(errors, err)
+		logger.V(2).Info("trying to create APIBinding")
+		if _, err := b.createAPIBinding(ctx, clusterName.Path(), apiBinding); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				logger.V(2).Info("APIBinding already exists")
 				continue
 			}
 
-			logger.V(2).Info("created APIBinding")
+			errors = append(errors, err)
+			continue
 		}
+
+		logger.V(2).Info("created APIBinding")
 	}
 
 	if len(errors) > 0 {
@@ -249,6 +251,140 @@ func (b *APIBinder) reconcile(ctx context.Context, logicalCluster *corev1alpha1.
 	return nil
 }
 
+// defaultAPIBinding is an APIExport to bind during initialization, and the permission claims to accept.
+type defaultAPIBinding struct {
+	exportRef tenancyv1alpha1.APIExportReference
+
+	// name is the template of the APIBinding name from defaultAPIBindings. If empty, the name is generated.
+	name string
+	// overridden is set once the APIExport is found in defaultAPIBindings of a type. Entries of
+	// the types it extends are ignored.
+	overridden bool
+	// acceptAllClaims is set if the APIExport is referenced in defaultAPIBindings without accepted claims.
+	acceptAllClaims bool
+	// acceptedClaims are the claims accepted by defaultAPIBindings and claim bundles.
+	acceptedClaims []apisv1alpha1.PermissionClaim
+}
+
+// accept returns the claim to accept for the given claim of the APIExport, and whether it is accepted.
+// Claims narrowed down to different resource selectors by multiple types are accepted with the union
+// of the selectors.
+func (b *defaultAPIBinding) accept(claim apisv1alpha1.PermissionClaim) (apisv1alpha1.PermissionClaim, bool) {
+	if b.acceptAllClaims {
+		return claim, true
+	}
+
+	var ret *apisv1alpha1.PermissionClaim
+	for i := range b.acceptedClaims {
+		narrowed, ok := permissionclaim.Narrow(b.acceptedClaims[i], claim)
+		if !ok {
+			continue
+		}
+		if equality.Semantic.DeepEqual(narrowed, claim) {
+			return claim, true
+		}
+		if ret == nil {
+			ret = &narrowed
+			continue
+		}
+		for _, selector := range narrowed.ResourceSelector {
+			if !containsSelector(ret.ResourceSelector, selector) {
+				ret.ResourceSelector = append(ret.ResourceSelector, selector)
+			}
+		}
+	}
+	if ret == nil {
+		return apisv1alpha1.PermissionClaim{}, false
+	}
+	return *ret, true
+}
+
+func containsSelector(selectors []apisv1alpha1.ResourceSelector, selector apisv1alpha1.ResourceSelector) bool {
+	for i := range selectors {
+		if equality.Semantic.DeepEqual(selectors[i], selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiBindingName returns the name of the APIBinding in the given workspace, either from the
+// name template with the "{export}" and "{workspace}" placeholders, or generated.
+func (b *defaultAPIBinding) apiBindingName(clusterName logicalcluster.Name, workspaceName string) string {
+	if b.name == "" {
+		return generateAPIBindingName(clusterName, b.exportRef.Path, b.exportRef.Export)
+	}
+	return strings.NewReplacer("{export}", b.exportRef.Export, "{workspace}", workspaceName).Replace(b.name)
+}
+
+// workspaceNameOf returns the name of the Workspace owning the logical cluster, or the logical
+// cluster name if there is none, e.g. for the root workspace.
+func workspaceNameOf(logicalCluster *corev1alpha1.LogicalCluster) string {
+	if owner := logicalCluster.Spec.Owner; owner != nil && owner.Name != "" {
+		return owner.Name
+	}
+	return logicalcluster.From(logicalCluster).String()
+}
+
+// defaultAPIBindingsFor returns the APIExports to bind for the given WorkspaceTypes, from their
+// apiBindings, defaultAPIBindings and claimBundles. APIExports referenced multiple times are bound
+// once. An entry in apiBindings or defaultAPIBindings of a type overrides the entries for the same
+// APIExport in the types it extends, while claims accepted by claim bundles are added up across
+// all types.
+//
+// The types are expected in the order returned by the transitive type resolver, i.e. ending with
+// the leaf type, and listing every other type before the types it extends.
+func defaultAPIBindingsFor(wts []*tenancyv1alpha1.WorkspaceType) []*defaultAPIBinding {
+	var bindings []*defaultAPIBinding
+	byRef := map[tenancyv1alpha1.APIExportReference]*defaultAPIBinding{}
+	get := func(wt *tenancyv1alpha1.WorkspaceType, exportRef tenancyv1alpha1.APIExportReference) *defaultAPIBinding {
+		if exportRef.Path == "" {
+			exportRef.Path = logicalcluster.From(wt).String()
+		}
+		binding, found := byRef[exportRef]
+		if !found {
+			binding = &defaultAPIBinding{exportRef: exportRef}
+			byRef[exportRef] = binding
+			bindings = append(bindings, binding)
+		}
+		return binding
+	}
+
+	if len(wts) == 0 {
+		return nil
+	}
+	// leaf type first, such that every type comes before the types it extends.
+	ordered := append([]*tenancyv1alpha1.WorkspaceType{wts[len(wts)-1]}, wts[:len(wts)-1]...)
+
+	for _, wt := range ordered {
+		// entries of apiBindings come first, such that they take precedence over defaultAPIBindings.
+		for _, apiBinding := range wt.Spec.APIBindings {
+			binding := get(wt, apiBinding.APIExportReference)
+			if binding.overridden {
+				continue
+			}
+			binding.overridden = true
+			binding.name = apiBinding.Name
+			binding.acceptAllClaims = len(apiBinding.AcceptedPermissionClaims) == 0
+			binding.acceptedClaims = append(binding.acceptedClaims, apiBinding.AcceptedPermissionClaims...)
+		}
+		for _, exportRef := range wt.Spec.DefaultAPIBindings {
+			binding := get(wt, exportRef)
+			if binding.overridden {
+				continue
+			}
+			binding.overridden = true
+			binding.acceptAllClaims = true
+		}
+		for _, bundle := range wt.Spec.ClaimBundles {
+			binding := get(wt, bundle.APIExportReference)
+			binding.acceptedClaims = append(binding.acceptedClaims, bundle.AcceptedPermissionClaims...)
+		}
+	}
+
+	return bindings
+}
+
 // maxExportNamePrefixLength is the maximum allowed length for the export name portion of the generated API binding
 // name. Subtrace 1 for the dash ("-") that separates the export name prefix from the hash suffix, and 5 for the
 // hash length.
@@ -23,6 +23,11 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 )
 
 func TestGenerateAPIBindingName(t *testing.T) {
@@ -82,3 +87,64 @@ func TestGenerateAPIBindingNameWithMultipleSimilarLongNames(t *testing.T) {
 	require.Len(t, generated2, 253)
 	require.NotEqual(t, generated1, generated2, "expected different generated names")
 }
+
+func TestDefaultAPIBindingsFor(t *testing.T) {
+	t.Parallel()
+
+	configMaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	secrets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+	namedSecret := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, ResourceSelector: []apisv1alpha1.ResourceSelector{{Name: "creds"}}}
+
+	newType := func(cluster string, spec tenancyv1alpha1.WorkspaceTypeSpec) *tenancyv1alpha1.WorkspaceType {
+		return &tenancyv1alpha1.WorkspaceType{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{logicalcluster.AnnotationKey: cluster}},
+			Spec:       spec,
+		}
+	}
+	accepts := func(b *defaultAPIBinding, claim apisv1alpha1.PermissionClaim) bool {
+		_, accepted := b.accept(claim)
+		return accepted
+	}
+
+	// the leaf type comes last
+	bindings := defaultAPIBindingsFor([]*tenancyv1alpha1.WorkspaceType{
+		newType("root", tenancyv1alpha1.WorkspaceTypeSpec{
+			DefaultAPIBindings: []tenancyv1alpha1.APIExportReference{{Export: "overridden"}},
+			ClaimBundles: []tenancyv1alpha1.APIBindingClaimBundle{
+				{APIExportReference: tenancyv1alpha1.APIExportReference{Export: "mandated"}, AcceptedPermissionClaims: []apisv1alpha1.PermissionClaim{configMaps}},
+			},
+		}),
+		newType("root:org", tenancyv1alpha1.WorkspaceTypeSpec{
+			DefaultAPIBindings: []tenancyv1alpha1.APIExportReference{{Path: "root", Export: "legacy"}, {Path: "root", Export: "overridden"}},
+			APIBindings: []tenancyv1alpha1.WorkspaceTypeAPIBinding{
+				{APIExportReference: tenancyv1alpha1.APIExportReference{Path: "root", Export: "overridden"}, Name: "{export}-{workspace}", AcceptedPermissionClaims: []apisv1alpha1.PermissionClaim{namedSecret}},
+			},
+			ClaimBundles: []tenancyv1alpha1.APIBindingClaimBundle{
+				{APIExportReference: tenancyv1alpha1.APIExportReference{Export: "local"}, AcceptedPermissionClaims: []apisv1alpha1.PermissionClaim{configMaps}},
+				{APIExportReference: tenancyv1alpha1.APIExportReference{Path: "root", Export: "mandated"}, AcceptedPermissionClaims: []apisv1alpha1.PermissionClaim{namedSecret}},
+			},
+		}),
+	})
+
+	require.Len(t, bindings, 4)
+
+	require.Equal(t, tenancyv1alpha1.APIExportReference{Path: "root", Export: "overridden"}, bindings[0].exportRef)
+	require.False(t, accepts(bindings[0], configMaps), "the leaf type overrides the claims of the types it extends, and apiBindings take precedence over defaultAPIBindings")
+	require.Equal(t, "overridden-ws", bindings[0].apiBindingName("root:org:ws", "ws"))
+	claim, accepted := bindings[0].accept(secrets)
+	require.True(t, accepted, "claims can be narrowed down")
+	require.Equal(t, namedSecret, claim)
+
+	require.Equal(t, tenancyv1alpha1.APIExportReference{Path: "root:org", Export: "local"}, bindings[2].exportRef, "path defaults to the workspace of the type")
+	require.True(t, accepts(bindings[2], configMaps))
+	require.False(t, accepts(bindings[2], secrets))
+
+	require.Equal(t, tenancyv1alpha1.APIExportReference{Path: "root", Export: "mandated"}, bindings[3].exportRef)
+	require.True(t, accepts(bindings[3], configMaps), "claims of claim bundles are merged across types")
+	require.True(t, accepts(bindings[3], namedSecret))
+	claim, accepted = bindings[3].accept(secrets)
+	require.True(t, accepted, "claims with a wider scope than accepted are narrowed down")
+	require.Equal(t, namedSecret, claim)
+	_, accepted = bindings[3].accept(apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, ResourceSelector: []apisv1alpha1.ResourceSelector{{Name: "other"}}})
+	require.False(t, accepted, "claims for other selectors than accepted are not accepted")
+}
@@ -23,8 +23,10 @@ import (
 
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -36,6 +38,8 @@ import (
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 	tenancyv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/tenancy/v1alpha1"
@@ -60,7 +64,7 @@ func NewController(
 	globalWorkspaceTypeInformer tenancyv1alpha1informers.WorkspaceTypeClusterInformer,
 	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &Controller{
 		queue: queue,
@@ -85,6 +89,7 @@ func NewController(
 		logicalClusterLister:  logicalClusterInformer.Lister(),
 
 		commit: committer.NewCommitter[*tenancyv1alpha1.Workspace, tenancyv1alpha1client.WorkspaceInterface, *tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus](kcpClusterClient.TenancyV1alpha1().Workspaces()),
+		now:    time.Now,
 	}
 
 	indexers.AddIfNotPresentOrDie(workspaceInformer.Informer().GetIndexer(), cache.Indexers{
@@ -108,6 +113,22 @@ func NewController(
 		DeleteFunc: func(obj interface{}) { c.enqueueShard(obj) },
 	})
 
+	logicalClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, obj interface{}) {
+			oldLogicalCluster, ok := oldObj.(*corev1alpha1.LogicalCluster)
+			if !ok {
+				return
+			}
+			logicalCluster, ok := obj.(*corev1alpha1.LogicalCluster)
+			if !ok {
+				return
+			}
+			if oldLogicalCluster.Annotations[tenancyv1alpha1.PropagatedMetadataAnnotationKey] != logicalCluster.Annotations[tenancyv1alpha1.PropagatedMetadataAnnotationKey] {
+				c.enqueueChildren(logicalCluster)
+			}
+		},
+	})
+
 	return c, nil
 }
 
@@ -140,6 +161,7 @@ type Controller struct {
 
 	// commit creates a patch and submits it, if needed.
 	commit func(ctx context.Context, new, old *workspaceResource) error
+	now    func() time.Time
 }
 
 func (c *Controller) enqueue(obj interface{}) {
@@ -185,6 +207,26 @@ func (c *Controller) enqueueShard(obj interface{}) {
 	}
 }
 
+// enqueueChildren enqueues the workspaces in the given logical cluster, e.g. when the metadata
+// propagated to them has changed.
+func (c *Controller) enqueueChildren(logicalCluster *corev1alpha1.LogicalCluster) {
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+	workspaces, err := c.workspaceLister.Cluster(logicalcluster.From(logicalCluster)).List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, workspace := range workspaces {
+		key, err := kcpcache.MetaClusterNamespaceKeyFunc(workspace)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		logging.WithQueueKey(logger, key).V(2).Info("queueing Workspace because of propagated metadata change")
+		c.queue.Add(key)
+	}
+}
+
 func (c *Controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
 	defer c.queue.ShutDown()
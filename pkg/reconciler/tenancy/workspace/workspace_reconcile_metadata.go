@@ -35,7 +35,7 @@ func (r *metaDataReconciler) reconcile(ctx context.Context, workspace *tenancyv1
 
 	changed := false
 	expected := string(workspace.Status.Phase)
-	if !workspace.DeletionTimestamp.IsZero() {
+	if !workspace.DeletionTimestamp.IsZero() && workspace.Status.Phase != corev1alpha1.LogicalClusterPhaseTrashed {
 		expected = "Deleting"
 	}
 	if got := workspace.Labels[tenancyv1alpha1.WorkspacePhaseLabel]; got != expected {
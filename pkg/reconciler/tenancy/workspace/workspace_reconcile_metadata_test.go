@@ -85,6 +85,27 @@ func TestReconcileMetadata(t *testing.T) {
 			},
 			wantStatus: reconcileStatusStopAndRequeue,
 		},
+		{
+			name: "shows phase Trashed when soft deleted",
+			input: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{Time: date},
+					Labels: map[string]string{
+						"tenancy.kcp.io/phase": "Deleting",
+					},
+				},
+				Status: tenancyv1alpha1.WorkspaceStatus{
+					Phase: corev1alpha1.LogicalClusterPhaseTrashed,
+				},
+			},
+			expected: metav1.ObjectMeta{
+				DeletionTimestamp: &metav1.Time{Time: date},
+				Labels: map[string]string{
+					"tenancy.kcp.io/phase": "Trashed",
+				},
+			},
+			wantStatus: reconcileStatusStopAndRequeue,
+		},
 		{
 			name: "delete invalid owner annotation when ready",
 			input: &tenancyv1alpha1.Workspace{
@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/tenancy/initialization"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
@@ -35,6 +36,7 @@ type phaseReconciler struct {
 	getLogicalCluster func(ctx context.Context, cluster logicalcluster.Path) (*corev1alpha1.LogicalCluster, error)
 
 	requeueAfter func(workspace *tenancyv1alpha1.Workspace, after time.Duration)
+	now          func() time.Time
 }
 
 func (r *phaseReconciler) reconcile(ctx context.Context, workspace *tenancyv1alpha1.Workspace) (reconcileStatus, error) {
@@ -58,9 +60,10 @@ func (r *phaseReconciler) reconcile(ctx context.Context, workspace *tenancyv1alp
 		}
 
 		workspace.Status.Initializers = logicalCluster.Status.Initializers
+		workspace.Status.InitializationProgress = initialization.AggregateProgress(logicalCluster)
 
 		if initializers := workspace.Status.Initializers; len(initializers) > 0 {
-			after := time.Since(logicalCluster.CreationTimestamp.Time) / 5
+			after := r.now().Sub(logicalCluster.CreationTimestamp.Time) / 5
 			if max := time.Minute * 10; after > max {
 				after = max
 			}
@@ -72,6 +75,7 @@ func (r *phaseReconciler) reconcile(ctx context.Context, workspace *tenancyv1alp
 
 		logger.V(3).Info("LogicalCluster is ready")
 		workspace.Status.Phase = corev1alpha1.LogicalClusterPhaseReady
+		workspace.Status.InitializationProgress = nil
 		conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceInitialized)
 
 	case corev1alpha1.LogicalClusterPhaseReady:
@@ -88,7 +92,7 @@ func (r *phaseReconciler) reconcile(ctx context.Context, workspace *tenancyv1alp
 			}
 
 			if !conditions.IsTrue(workspace, tenancyv1alpha1.WorkspaceContentDeleted) {
-				after := time.Since(logicalCluster.CreationTimestamp.Time) / 5
+				after := r.now().Sub(logicalCluster.CreationTimestamp.Time) / 5
 				if max := time.Minute * 10; after > max {
 					after = max
 				}
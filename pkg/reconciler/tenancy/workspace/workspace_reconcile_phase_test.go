@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	reconcilertesting "github.com/kcp-dev/kcp/pkg/reconciler/testing"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+)
+
+func TestReconcilePhaseInitializers(t *testing.T) {
+	created := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakeClock(created)
+	queue := reconcilertesting.NewQueue(clock)
+
+	logicalCluster := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              corev1alpha1.LogicalClusterName,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1alpha1.LogicalClusterStatus{
+			Initializers: []corev1alpha1.LogicalClusterInitializer{"root:universal"},
+		},
+	}
+	workspace := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root"},
+		},
+		Spec: tenancyv1alpha1.WorkspaceSpec{Cluster: "abc", URL: "https://shard/clusters/abc"},
+		Status: tenancyv1alpha1.WorkspaceStatus{
+			Phase: corev1alpha1.LogicalClusterPhaseInitializing,
+		},
+	}
+	key := "root|test"
+
+	r := &phaseReconciler{
+		getLogicalCluster: func(ctx context.Context, cluster logicalcluster.Path) (*corev1alpha1.LogicalCluster, error) {
+			return logicalCluster, nil
+		},
+		requeueAfter: func(workspace *tenancyv1alpha1.Workspace, after time.Duration) {
+			queue.AddAfter(key, after)
+		},
+		now: clock.Now,
+	}
+
+	// the requeue delay grows with the age of the logical cluster, up to 10 minutes.
+	for _, tc := range []struct {
+		age       time.Duration
+		wantAfter time.Duration
+	}{
+		{age: 10 * time.Second, wantAfter: 2 * time.Second},
+		{age: 5 * time.Minute, wantAfter: time.Minute},
+		{age: 2 * time.Hour, wantAfter: 10 * time.Minute},
+	} {
+		clock.SetTime(created.Add(tc.age))
+		status, err := r.reconcile(context.Background(), workspace)
+		require.NoError(t, err)
+		require.Equal(t, reconcileStatusContinue, status)
+		require.Equal(t, corev1alpha1.LogicalClusterPhaseInitializing, workspace.Status.Phase)
+		require.False(t, conditions.IsTrue(workspace, tenancyv1alpha1.WorkspaceInitialized))
+
+		after, ok := queue.Waiting(key)
+		require.True(t, ok, "workspace should be requeued while initializers exist")
+		require.Equal(t, tc.wantAfter, after)
+
+		clock.Step(after)
+		require.Equal(t, 1, queue.Len())
+		item, _ := queue.Get()
+		queue.Done(item)
+	}
+
+	logicalCluster.Status.Initializers = nil
+	status, err := r.reconcile(context.Background(), workspace)
+	require.NoError(t, err)
+	require.Equal(t, reconcileStatusContinue, status)
+	require.Equal(t, corev1alpha1.LogicalClusterPhaseReady, workspace.Status.Phase)
+	require.True(t, conditions.IsTrue(workspace, tenancyv1alpha1.WorkspaceInitialized))
+	_, ok := queue.Waiting(key)
+	require.False(t, ok)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/admission/workspacetypeexists"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// metadataPropagationReconciler keeps the labels and annotations a workspace inherits from its parent
+// in sync, and passes on the metadata the workspace propagates to its own children by annotating its
+// LogicalCluster. The children are reconciled by the workspace controller of the shard of that
+// LogicalCluster, which that way propagates the metadata further down the tree.
+type metadataPropagationReconciler struct {
+	getLogicalCluster   func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	getWorkspaceType    func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error)
+	patchLogicalCluster func(ctx context.Context, cluster logicalcluster.Path, patch []byte) error
+}
+
+func (r *metadataPropagationReconciler) reconcile(ctx context.Context, workspace *tenancyv1alpha1.Workspace) (reconcileStatus, error) {
+	logger := klog.FromContext(ctx).WithValues("reconciler", "metadata-propagation")
+
+	if !workspace.DeletionTimestamp.IsZero() {
+		return reconcileStatusContinue, nil
+	}
+
+	parent, err := r.getLogicalCluster(logicalcluster.From(workspace))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcileStatusContinue, nil
+		}
+		return reconcileStatusContinue, err
+	}
+	inherited, err := workspacetypeexists.PropagatedMetadataFrom(parent.Annotations, tenancyv1alpha1.PropagatedMetadataAnnotationKey)
+	if err != nil {
+		logger.Error(err, "ignoring invalid propagated metadata of parent")
+		inherited = &workspacetypeexists.PropagatedMetadata{}
+	}
+	if changed, err := workspacetypeexists.ApplyInheritedMetadata(workspace, inherited); err != nil {
+		return reconcileStatusContinue, err
+	} else if changed {
+		// first update ObjectMeta before propagating further
+		return reconcileStatusStopAndRequeue, nil
+	}
+
+	switch workspace.Status.Phase {
+	case corev1alpha1.LogicalClusterPhaseInitializing, corev1alpha1.LogicalClusterPhaseReady:
+	default:
+		return reconcileStatusContinue, nil
+	}
+	if workspace.Spec.Cluster == "" {
+		return reconcileStatusContinue, nil
+	}
+
+	wt, err := r.getWorkspaceType(logicalcluster.NewPath(workspace.Spec.Type.Path), string(workspace.Spec.Type.Name))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconcileStatusContinue, err
+	} else if err != nil {
+		// the type is gone. Only pass on what was inherited.
+		wt = nil
+	}
+	value, err := workspacetypeexists.PropagatedMetadataFor(workspace, wt, inherited).Encode()
+	if err != nil {
+		return reconcileStatusContinue, err
+	}
+
+	if workspace.Annotations[tenancyv1alpha1.PropagatedMetadataAnnotationKey] == value {
+		return reconcileStatusContinue, nil
+	}
+
+	var annotationValue interface{}
+	if value != "" {
+		annotationValue = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: annotationValue,
+			},
+		},
+	})
+	if err != nil {
+		return reconcileStatusContinue, err
+	}
+	logger.V(3).Info("propagating metadata to children", "cluster", workspace.Spec.Cluster)
+	if err := r.patchLogicalCluster(ctx, logicalcluster.NewPath(workspace.Spec.Cluster), patch); err != nil {
+		return reconcileStatusContinue, fmt.Errorf("failed to propagate metadata to LogicalCluster %s: %w", workspace.Spec.Cluster, err)
+	}
+
+	if value == "" {
+		delete(workspace.Annotations, tenancyv1alpha1.PropagatedMetadataAnnotationKey)
+	} else {
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[tenancyv1alpha1.PropagatedMetadataAnnotationKey] = value
+	}
+
+	return reconcileStatusStopAndRequeue, nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func TestReconcileMetadataPropagation(t *testing.T) {
+	workspaceType := &tenancyv1alpha1.WorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: tenancyv1alpha1.WorkspaceTypeSpec{
+			PropagatedMetadata: &tenancyv1alpha1.WorkspaceMetadataPropagation{
+				Labels:      []string{"cost-center", "tenancy.kcp.io/phase"},
+				Annotations: []string{"example.com/owner", "kcp.io/cluster", "authorization.kcp.io/required-groups"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		parentAnnotations map[string]string
+		workspace         *tenancyv1alpha1.Workspace
+		wantLabels        map[string]string
+		wantAnnotations   map[string]string
+		wantPatch         string
+		wantStatus        reconcileStatus
+	}{
+		"inherits metadata of the parent": {
+			parentAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"},"annotations":{"example.com/owner":"alice"}}`,
+			},
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"cost-center": "5678", "a": "b"},
+				},
+			},
+			wantLabels: map[string]string{"cost-center": "1234", "a": "b"},
+			wantAnnotations: map[string]string{
+				"example.com/owner":                            "alice",
+				tenancyv1alpha1.InheritedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"},"annotations":{"example.com/owner":"alice"}}`,
+			},
+			wantStatus: reconcileStatusStopAndRequeue,
+		},
+		"removes metadata not inherited anymore": {
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"cost-center": "1234", "a": "b"},
+					Annotations: map[string]string{
+						tenancyv1alpha1.InheritedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"}}`,
+					},
+				},
+			},
+			wantLabels:      map[string]string{"a": "b"},
+			wantAnnotations: map[string]string{},
+			wantStatus:      reconcileStatusStopAndRequeue,
+		},
+		"propagates allowlisted metadata to the children": {
+			parentAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"region":"eu"}}`,
+			},
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"region": "eu", "cost-center": "1234", "a": "b"},
+					Annotations: map[string]string{
+						"example.com/owner":                            "alice",
+						"example.com/other":                            "foo",
+						tenancyv1alpha1.InheritedMetadataAnnotationKey: `{"labels":{"region":"eu"}}`,
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceSpec{
+					Cluster: "child",
+					Type:    tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+				},
+				Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseReady},
+			},
+			wantLabels: map[string]string{"region": "eu", "cost-center": "1234", "a": "b"},
+			wantAnnotations: map[string]string{
+				"example.com/owner":                             "alice",
+				"example.com/other":                             "foo",
+				tenancyv1alpha1.InheritedMetadataAnnotationKey:  `{"labels":{"region":"eu"}}`,
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"cost-center":"1234","region":"eu"},"annotations":{"example.com/owner":"alice"}}`,
+			},
+			wantPatch:  `{"metadata":{"annotations":{"tenancy.kcp.io/propagated-metadata":"{\"labels\":{\"cost-center\":\"1234\",\"region\":\"eu\"},\"annotations\":{\"example.com/owner\":\"alice\"}}"}}}`,
+			wantStatus: reconcileStatusStopAndRequeue,
+		},
+		"removes propagated metadata from the children": {
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"}}`,
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceSpec{
+					Cluster: "child",
+					Type:    tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+				},
+				Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseReady},
+			},
+			wantAnnotations: map[string]string{},
+			wantPatch:       `{"metadata":{"annotations":{"tenancy.kcp.io/propagated-metadata":null}}}`,
+			wantStatus:      reconcileStatusStopAndRequeue,
+		},
+		"does not inherit reserved metadata": {
+			parentAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"cost-center":"1234","tenancy.kcp.io/phase":"Ready"},"annotations":{"kcp.io/cluster":"parent"}}`,
+			},
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"tenancy.kcp.io/phase": "Initializing"},
+					Annotations: map[string]string{"kcp.io/cluster": "root"},
+				},
+			},
+			wantLabels: map[string]string{"cost-center": "1234", "tenancy.kcp.io/phase": "Initializing"},
+			wantAnnotations: map[string]string{
+				"kcp.io/cluster": "root",
+				tenancyv1alpha1.InheritedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"}}`,
+			},
+			wantStatus: reconcileStatusStopAndRequeue,
+		},
+		"does not propagate reserved metadata": {
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"cost-center": "1234", "tenancy.kcp.io/phase": "Ready"},
+					Annotations: map[string]string{
+						"kcp.io/cluster":                       "root",
+						"authorization.kcp.io/required-groups": "admins",
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceSpec{
+					Cluster: "child",
+					Type:    tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+				},
+				Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseReady},
+			},
+			wantLabels: map[string]string{"cost-center": "1234", "tenancy.kcp.io/phase": "Ready"},
+			wantAnnotations: map[string]string{
+				"kcp.io/cluster":                                "root",
+				"authorization.kcp.io/required-groups":          "admins",
+				tenancyv1alpha1.PropagatedMetadataAnnotationKey: `{"labels":{"cost-center":"1234"}}`,
+			},
+			wantPatch:  `{"metadata":{"annotations":{"tenancy.kcp.io/propagated-metadata":"{\"labels\":{\"cost-center\":\"1234\"}}"}}}`,
+			wantStatus: reconcileStatusStopAndRequeue,
+		},
+		"nothing to propagate while scheduling": {
+			workspace: &tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"cost-center": "1234"},
+				},
+				Spec: tenancyv1alpha1.WorkspaceSpec{
+					Type: tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "team"},
+				},
+				Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseScheduling},
+			},
+			wantLabels: map[string]string{"cost-center": "1234"},
+			wantStatus: reconcileStatusContinue,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPatch string
+			r := &metadataPropagationReconciler{
+				getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+					return &corev1alpha1.LogicalCluster{
+						ObjectMeta: metav1.ObjectMeta{Annotations: tc.parentAnnotations},
+					}, nil
+				},
+				getWorkspaceType: func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+					require.Equal(t, "root", path.String())
+					require.Equal(t, "team", name)
+					return workspaceType, nil
+				},
+				patchLogicalCluster: func(ctx context.Context, cluster logicalcluster.Path, patch []byte) error {
+					require.Equal(t, "child", cluster.String())
+					gotPatch = string(patch)
+					return nil
+				},
+			}
+
+			status, err := r.reconcile(context.Background(), tc.workspace)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, status)
+			require.Equal(t, tc.wantPatch, gotPatch)
+			require.Equal(t, tc.wantLabels, tc.workspace.Labels)
+			require.Equal(t, tc.wantAnnotations, tc.workspace.Annotations)
+		})
+	}
+}
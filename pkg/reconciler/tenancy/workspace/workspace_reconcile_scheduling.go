@@ -185,53 +185,64 @@ func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancy
 }
 
 func (r *schedulingReconciler) chooseShardAndMarkCondition(logger klog.Logger, workspace *tenancyv1alpha1.Workspace) (shard *corev1alpha1.Shard, reason string, err error) {
+	validShards, invalidShards, reason, err := schedulableShards(logger, r.listShards, workspace.Spec.Location)
+	if err != nil || reason != "" {
+		return nil, reason, err
+	}
+
+	if len(validShards) == 0 {
+		failures := make([]error, 0, len(invalidShards))
+		for name, x := range invalidShards {
+			failures = append(failures, fmt.Errorf("  %s: reason %q, message %q", name, x.reason, x.message))
+		}
+		logger.Error(utilerrors.NewAggregate(failures), "no valid shards found for workspace, skipping")
+		return nil, "No available shards to schedule the workspace", nil // retry is automatic when new shards show up
+	}
+	targetShard := validShards[rand.Intn(len(validShards))]
+	return targetShard, "", nil
+}
+
+type invalidShard struct {
+	reason, message string
+}
+
+// schedulableShards returns the shards a workspace with the given location can be scheduled to, and
+// the invalid shards matching the location. A non-empty reason is returned if the location is invalid.
+func schedulableShards(logger klog.Logger, listShards func(selector labels.Selector) ([]*corev1alpha1.Shard, error), location *tenancyv1alpha1.WorkspaceLocation) (valid []*corev1alpha1.Shard, invalid map[string]invalidShard, reason string, err error) {
 	selector := labels.Everything()
-	if workspace.Spec.Location != nil {
-		if workspace.Spec.Location.Selector != nil {
+	if location != nil {
+		if location.Selector != nil {
 			var err error
-			selector, err = metav1.LabelSelectorAsSelector(workspace.Spec.Location.Selector)
+			selector, err = metav1.LabelSelectorAsSelector(location.Selector)
 			if err != nil {
-				return nil, fmt.Sprintf("spec.location.selector is invalid: %v", err), nil // don't retry, cannot do anything useful
+				return nil, nil, fmt.Sprintf("spec.location.selector is invalid: %v", err), nil // don't retry, cannot do anything useful
 			}
 		}
 	}
 
-	shards, err := r.listShards(selector)
+	shards, err := listShards(selector)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, "", err
 	}
 
-	validShards := make([]*corev1alpha1.Shard, 0, len(shards))
-	invalidShards := map[string]struct {
-		reason, message string
-	}{}
+	valid = make([]*corev1alpha1.Shard, 0, len(shards))
+	invalid = map[string]invalidShard{}
 	for _, shard := range shards {
 		if _, ok := shard.Annotations[unschedulableAnnotationKey]; ok {
 			logger.V(4).Info("Skipping a shard because it is annotated as unschedulable", "shard", shard.Name, "annotation", unschedulableAnnotationKey)
 			continue
 		}
-		if valid, reason, message := isValidShard(shard); valid {
-			validShards = append(validShards, shard)
+		if ok, reason, message := isValidShard(shard); ok {
+			valid = append(valid, shard)
 		} else {
-			invalidShards[shard.Name] = struct {
-				reason, message string
-			}{
+			invalid[shard.Name] = invalidShard{
 				reason:  reason,
 				message: message,
 			}
 		}
 	}
 
-	if len(validShards) == 0 {
-		failures := make([]error, 0, len(invalidShards))
-		for name, x := range invalidShards {
-			failures = append(failures, fmt.Errorf("  %s: reason %q, message %q", name, x.reason, x.message))
-		}
-		logger.Error(utilerrors.NewAggregate(failures), "no valid shards found for workspace, skipping")
-		return nil, "No available shards to schedule the workspace", nil // retry is automatic when new shards show up
-	}
-	targetShard := validShards[rand.Intn(len(validShards))]
-	return targetShard, "", nil
+	return valid, invalid, "", nil
 }
 
 func (r *schedulingReconciler) createLogicalCluster(ctx context.Context, shard *corev1alpha1.Shard, cluster logicalcluster.Path, canonicalPath logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) error {
@@ -260,6 +271,12 @@ func (r *schedulingReconciler) createLogicalCluster(ctx context.Context, shard *
 	if groups, found := workspace.Annotations[authorization.RequiredGroupsAnnotationKey]; found {
 		logicalCluster.Annotations[authorization.RequiredGroupsAnnotationKey] = groups
 	}
+	if template, found := workspace.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]; found {
+		logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey] = template
+	}
+	if generation, found := workspace.Annotations[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey]; found {
+		logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey] = generation
+	}
 
 	// add initializers
 	var err error
@@ -311,7 +328,7 @@ func LogicalClustersInitializers(
 		if alias.Spec.Initializer {
 			initializers = append(initializers, initialization.InitializerForType(alias))
 		}
-		bindings = bindings || len(alias.Spec.DefaultAPIBindings) > 0
+		bindings = bindings || len(alias.Spec.DefaultAPIBindings) > 0 || len(alias.Spec.ClaimBundles) > 0
 	}
 	if bindings {
 		initializers = append(initializers, tenancyv1alpha1.WorkspaceAPIBindingsInitializer)
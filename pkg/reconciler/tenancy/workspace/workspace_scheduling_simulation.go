@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+const byShardHash = "byShardHash"
+
+const (
+	// MaxSimulatedWorkspaceBatches is the maximum number of batches of a scheduling simulation.
+	MaxSimulatedWorkspaceBatches = 100
+	// MaxSimulatedWorkspaces is the maximum number of workspaces of a batch of a scheduling simulation.
+	MaxSimulatedWorkspaces = 1000000
+)
+
+// SimulatedWorkspaces is a batch of hypothetical workspaces of the same type and location.
+type SimulatedWorkspaces struct {
+	// Type is the type of the workspaces.
+	Type tenancyv1alpha1.WorkspaceTypeReference `json:"type"`
+	// Count is the number of workspaces.
+	Count int `json:"count"`
+	// Location constrains the shards the workspaces can be scheduled to.
+	Location *tenancyv1alpha1.WorkspaceLocation `json:"location,omitempty"`
+}
+
+// SchedulingSimulationRequest is the input of a scheduling simulation.
+type SchedulingSimulationRequest struct {
+	Workspaces []SimulatedWorkspaces `json:"workspaces"`
+}
+
+// Validate checks that the request is within the limits of a simulation. The workspaces of a batch are
+// checked by the simulation, and reported as unschedulable if invalid.
+func (r *SchedulingSimulationRequest) Validate() error {
+	if len(r.Workspaces) > MaxSimulatedWorkspaceBatches {
+		return fmt.Errorf("at most %d batches of workspaces can be simulated, got %d", MaxSimulatedWorkspaceBatches, len(r.Workspaces))
+	}
+	return nil
+}
+
+// SimulatedWorkspacesResult is where a batch of hypothetical workspaces would be scheduled to.
+type SimulatedWorkspacesResult struct {
+	SimulatedWorkspaces `json:",inline"`
+
+	// Shards is the number of workspaces of the batch per shard.
+	Shards map[string]int `json:"shards,omitempty"`
+	// Unschedulable is the reason why the workspaces of the batch cannot be scheduled.
+	Unschedulable string `json:"unschedulable,omitempty"`
+}
+
+// SimulatedShardLoad is the number of workspaces on a shard before and after a scheduling simulation.
+type SimulatedShardLoad struct {
+	Name    string `json:"name"`
+	Current int    `json:"current"`
+	Added   int    `json:"added"`
+	Total   int    `json:"total"`
+}
+
+// SchedulingSimulationResult is the outcome of a scheduling simulation.
+type SchedulingSimulationResult struct {
+	Workspaces []SimulatedWorkspacesResult `json:"workspaces"`
+	Shards     []SimulatedShardLoad        `json:"shards"`
+}
+
+// SchedulingSimulator reports which shards new workspaces would be scheduled to, without creating them.
+// The load of a shard is the number of workspaces scheduled to it that are known to this shard.
+type SchedulingSimulator struct {
+	listShards       func(selector labels.Selector) ([]*corev1alpha1.Shard, error)
+	getWorkspaceType func(clusterName logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error)
+	countWorkspaces  func(shard *corev1alpha1.Shard) (int, error)
+}
+
+// NewSchedulingSimulator returns a SchedulingSimulator backed by the given informers.
+func NewSchedulingSimulator(
+	workspaceInformer tenancyv1alpha1informers.WorkspaceClusterInformer,
+	globalShardInformer corev1alpha1informers.ShardClusterInformer,
+	globalWorkspaceTypeInformer tenancyv1alpha1informers.WorkspaceTypeClusterInformer,
+) *SchedulingSimulator {
+	indexers.AddIfNotPresentOrDie(workspaceInformer.Informer().GetIndexer(), cache.Indexers{
+		byShardHash: indexByShardHash,
+	})
+	indexers.AddIfNotPresentOrDie(globalWorkspaceTypeInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+
+	workspaceIndexer := workspaceInformer.Informer().GetIndexer()
+	workspaceTypeIndexer := globalWorkspaceTypeInformer.Informer().GetIndexer()
+	shardLister := globalShardInformer.Lister()
+
+	return &SchedulingSimulator{
+		listShards: func(selector labels.Selector) ([]*corev1alpha1.Shard, error) {
+			return shardLister.List(selector)
+		},
+		getWorkspaceType: func(path logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+			return indexers.ByPathAndName[*tenancyv1alpha1.WorkspaceType](tenancyv1alpha1.Resource("workspacetypes"), workspaceTypeIndexer, path, name)
+		},
+		countWorkspaces: func(shard *corev1alpha1.Shard) (int, error) {
+			workspaces, err := workspaceIndexer.ByIndex(byShardHash, ByBase36Sha224NameValue(shard.Name))
+			return len(workspaces), err
+		},
+	}
+}
+
+// Simulate schedules the requested workspaces on paper. The scheduler picks a random valid shard for
+// every workspace, so the workspaces of a batch are distributed evenly over the valid shards, which is
+// the expected outcome.
+func (s *SchedulingSimulator) Simulate(logger klog.Logger, req *SchedulingSimulationRequest) (*SchedulingSimulationResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	allShards, err := s.listShards(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	loads := make(map[string]*SimulatedShardLoad, len(allShards))
+	for _, shard := range allShards {
+		current, err := s.countWorkspaces(shard)
+		if err != nil {
+			return nil, err
+		}
+		loads[shard.Name] = &SimulatedShardLoad{Name: shard.Name, Current: current}
+	}
+
+	result := &SchedulingSimulationResult{
+		Workspaces: make([]SimulatedWorkspacesResult, 0, len(req.Workspaces)),
+	}
+	for _, batch := range req.Workspaces {
+		batchResult := SimulatedWorkspacesResult{SimulatedWorkspaces: batch}
+		batchResult.Unschedulable, err = s.simulateBatch(logger, batch, loads, &batchResult)
+		if err != nil {
+			return nil, err
+		}
+		result.Workspaces = append(result.Workspaces, batchResult)
+	}
+
+	result.Shards = make([]SimulatedShardLoad, 0, len(loads))
+	for _, load := range loads {
+		load.Total = load.Current + load.Added
+		result.Shards = append(result.Shards, *load)
+	}
+	sort.Slice(result.Shards, func(i, j int) bool {
+		return result.Shards[i].Name < result.Shards[j].Name
+	})
+
+	return result, nil
+}
+
+func (s *SchedulingSimulator) simulateBatch(logger klog.Logger, batch SimulatedWorkspaces, loads map[string]*SimulatedShardLoad, result *SimulatedWorkspacesResult) (unschedulable string, err error) {
+	if batch.Count <= 0 {
+		return "count must be positive", nil
+	}
+	if batch.Count > MaxSimulatedWorkspaces {
+		return fmt.Sprintf("count must not exceed %d", MaxSimulatedWorkspaces), nil
+	}
+	if _, err := s.getWorkspaceType(logicalcluster.NewPath(batch.Type.Path), string(batch.Type.Name)); err != nil {
+		return fmt.Sprintf("workspace type %s:%s cannot be resolved: %v", batch.Type.Path, batch.Type.Name, err), nil
+	}
+
+	validShards, _, reason, err := schedulableShards(logger, s.listShards, batch.Location)
+	if err != nil || reason != "" {
+		return reason, err
+	}
+	if len(validShards) == 0 {
+		return "No available shards to schedule the workspace", nil
+	}
+	sort.Slice(validShards, func(i, j int) bool {
+		return validShards[i].Name < validShards[j].Name
+	})
+
+	// every shard gets the same share, and the first shards one more for the remainder.
+	result.Shards = make(map[string]int, len(validShards))
+	share, remainder := batch.Count/len(validShards), batch.Count%len(validShards)
+	for i, shard := range validShards {
+		added := share
+		if i < remainder {
+			added++
+		}
+		if added == 0 {
+			break
+		}
+		result.Shards[shard.Name] = added
+		if load, found := loads[shard.Name]; found {
+			load.Added += added
+		}
+	}
+
+	return "", nil
+}
+
+func indexByShardHash(obj interface{}) ([]string, error) {
+	workspace := obj.(*tenancyv1alpha1.Workspace)
+	if hash, found := workspace.Annotations[WorkspaceShardHashAnnotationKey]; found {
+		return []string{hash}, nil
+	}
+	return []string{}, nil
+}
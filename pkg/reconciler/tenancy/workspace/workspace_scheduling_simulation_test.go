@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func TestSchedulingSimulation(t *testing.T) {
+	east1, east2, west := shard("east-1"), shard("east-2"), shard("west")
+	east1.Labels["region"] = "east"
+	east2.Labels["region"] = "east"
+	west.Labels["region"] = "west"
+	cordoned := shard("cordoned")
+	cordoned.Annotations[unschedulableAnnotationKey] = "true"
+	shards := []*corev1alpha1.Shard{east1, east2, west, cordoned}
+
+	simulator := &SchedulingSimulator{
+		listShards: func(selector labels.Selector) ([]*corev1alpha1.Shard, error) {
+			var ret []*corev1alpha1.Shard
+			for _, shard := range shards {
+				if selector.Matches(labels.Set(shard.Labels)) {
+					ret = append(ret, shard)
+				}
+			}
+			return ret, nil
+		},
+		getWorkspaceType: func(clusterName logicalcluster.Path, name string) (*tenancyv1alpha1.WorkspaceType, error) {
+			if clusterName == logicalcluster.NewPath("root") && name == "universal" {
+				return workspaceType(name), nil
+			}
+			return nil, kerrors.NewNotFound(tenancyv1alpha1.Resource("workspacetypes"), name)
+		},
+		countWorkspaces: func(shard *corev1alpha1.Shard) (int, error) {
+			return map[string]int{"east-1": 10, "west": 3}[shard.Name], nil
+		},
+	}
+
+	universal := tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "universal"}
+	result, err := simulator.Simulate(klog.Background(), &SchedulingSimulationRequest{
+		Workspaces: []SimulatedWorkspaces{
+			{Type: universal, Count: 5, Location: &tenancyv1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "east"}}}},
+			{Type: universal, Count: 3},
+			{Type: tenancyv1alpha1.WorkspaceTypeReference{Path: "root", Name: "unknown"}, Count: 1},
+			{Type: universal, Count: 1, Location: &tenancyv1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "north"}}}},
+			{Type: universal, Count: MaxSimulatedWorkspaces + 1},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Workspaces, 5)
+	require.Equal(t, map[string]int{"east-1": 3, "east-2": 2}, result.Workspaces[0].Shards)
+	require.Equal(t, map[string]int{"east-1": 1, "east-2": 1, "west": 1}, result.Workspaces[1].Shards)
+	require.Contains(t, result.Workspaces[2].Unschedulable, "cannot be resolved")
+	require.Equal(t, "No available shards to schedule the workspace", result.Workspaces[3].Unschedulable)
+	require.Equal(t, "count must not exceed 1000000", result.Workspaces[4].Unschedulable)
+
+	require.Equal(t, []SimulatedShardLoad{
+		{Name: "cordoned"},
+		{Name: "east-1", Current: 10, Added: 4, Total: 14},
+		{Name: "east-2", Added: 3, Total: 3},
+		{Name: "west", Current: 3, Added: 1, Total: 4},
+	}, result.Shards)
+
+	result, err = simulator.Simulate(klog.Background(), &SchedulingSimulationRequest{
+		Workspaces: []SimulatedWorkspaces{{Type: universal, Count: MaxSimulatedWorkspaces}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"east-1": 333334, "east-2": 333333, "west": 333333}, result.Workspaces[0].Shards)
+
+	_, err = simulator.Simulate(klog.Background(), &SchedulingSimulationRequest{
+		Workspaces: make([]SimulatedWorkspaces, MaxSimulatedWorkspaceBatches+1),
+	})
+	require.EqualError(t, err, "at most 100 batches of workspaces can be simulated, got 101")
+}
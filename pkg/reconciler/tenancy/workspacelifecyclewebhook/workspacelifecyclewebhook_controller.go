@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelifecyclewebhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	tenancyv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/tenancy/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-workspacelifecyclewebhook"
+)
+
+// NewController returns a new controller which sends lifecycle events of Workspaces to the
+// WorkspaceLifecycleWebhooks of the workspace they live in.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	workspaceInformer tenancyv1alpha1informers.WorkspaceClusterInformer,
+	workspaceLifecycleWebhookInformer tenancyv1alpha1informers.WorkspaceLifecycleWebhookClusterInformer,
+) (*controller, error) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName),
+
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			return workspaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		listWorkspaces: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.Workspace, error) {
+			return workspaceInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+		listWorkspaceLifecycleWebhooks: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceLifecycleWebhook, error) {
+			return workspaceLifecycleWebhookInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+		deliver: newDeliverer().deliver,
+		now:     time.Now,
+		commit:  committer.NewCommitter[*tenancyv1alpha1.Workspace, tenancyv1alpha1client.WorkspaceInterface, *tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus](kcpClusterClient.TenancyV1alpha1().Workspaces()),
+	}
+
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueWorkspace(obj, logger)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueWorkspace(obj, logger)
+		},
+	})
+
+	workspaceLifecycleWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueWorkspaceLifecycleWebhook(obj, logger)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldWebhook, ok := oldObj.(*tenancyv1alpha1.WorkspaceLifecycleWebhook)
+			if !ok {
+				return
+			}
+			newWebhook, ok := newObj.(*tenancyv1alpha1.WorkspaceLifecycleWebhook)
+			if !ok {
+				return
+			}
+			// the subscribed events decide about the finalizer of the workspaces.
+			if !equality.Semantic.DeepEqual(oldWebhook.Spec.Events, newWebhook.Spec.Events) {
+				c.enqueueWorkspaceLifecycleWebhook(newObj, logger)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			c.enqueueWorkspaceLifecycleWebhook(obj, logger)
+		},
+	})
+
+	return c, nil
+}
+
+type workspaceResource = committer.Resource[*tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus]
+
+// controller delivers lifecycle events of Workspaces to WorkspaceLifecycleWebhooks. It is keyed
+// by Workspace.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getWorkspace                   func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error)
+	listWorkspaces                 func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.Workspace, error)
+	listWorkspaceLifecycleWebhooks func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceLifecycleWebhook, error)
+
+	// deliver sends the event to the webhook.
+	deliver func(ctx context.Context, webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook, ev *event) error
+
+	now func() time.Time
+
+	// commit creates a patch and submits it, if needed.
+	commit func(ctx context.Context, old, new *workspaceResource) error
+}
+
+func (c *controller) enqueueWorkspace(obj interface{}, logger logr.Logger) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(4).Info("queueing Workspace")
+	c.queue.Add(key)
+}
+
+// enqueueWorkspaceLifecycleWebhook enqueues the workspaces next to a new, changed or deleted webhook,
+// e.g. because they have been created before the webhook was seen by the informer, or their
+// finalizer is not needed anymore.
+func (c *controller) enqueueWorkspaceLifecycleWebhook(obj interface{}, logger logr.Logger) {
+	webhook, ok := obj.(*tenancyv1alpha1.WorkspaceLifecycleWebhook)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a WorkspaceLifecycleWebhook, but is %T", obj))
+		return
+	}
+
+	workspaces, err := c.listWorkspaces(logicalcluster.From(webhook))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger = logging.WithObject(logger, webhook)
+	for _, workspace := range workspaces {
+		c.enqueueWorkspace(workspace, logger.WithValues("reason", "WorkspaceLifecycleWebhook added"))
+	}
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%s: failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "unable to decode key")
+		return nil
+	}
+
+	workspace, err := c.getWorkspace(clusterName, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil // deleted in the meantime
+	}
+
+	old := workspace
+	workspace = workspace.DeepCopy()
+
+	logger = logging.WithObject(logger, workspace)
+	ctx = klog.NewContext(ctx, logger)
+
+	var errs []error
+	if err := c.reconcile(ctx, workspace); err != nil {
+		errs = append(errs, err)
+	}
+
+	// record the delivered events and failures, also when some deliveries failed. Metadata and
+	// status cannot be committed at once. The delivered events come first, the status follows
+	// when the update of the Workspace is observed.
+	oldResource := &workspaceResource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &workspaceResource{ObjectMeta: workspace.ObjectMeta, Spec: &workspace.Spec, Status: &workspace.Status}
+	if !equality.Semantic.DeepEqual(old.ObjectMeta, workspace.ObjectMeta) {
+		newResource.Status = &old.Status
+	}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelifecyclewebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/network"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+
+	// maxCachedTransports is the number of distinct CA bundles whose transports are kept.
+	maxCachedTransports = 100
+)
+
+var errNonPublicAddress = errors.New("webhook resolves to a non-public address")
+
+// cloudEvent is a CloudEvent v1.0 in structured content mode.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            *event    `json:"data"`
+}
+
+// body returns the request body and its content type for the format of the webhook.
+func body(webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook, ev *event) ([]byte, string, error) {
+	switch webhook.Spec.Format {
+	case tenancyv1alpha1.WorkspaceLifecycleWebhookFormatCloudEvents:
+		bs, err := json.Marshal(&cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              fmt.Sprintf("%s-%s", ev.Workspace.UID, strings.ToLower(string(ev.Type))),
+			Source:          fmt.Sprintf("/clusters/%s/apis/tenancy.kcp.io/v1alpha1/workspaces/%s", ev.Workspace.ParentCluster, ev.Workspace.Name),
+			Type:            "io.kcp.tenancy.workspace." + strings.ToLower(string(ev.Type)),
+			Time:            ev.Time.UTC(),
+			DataContentType: "application/json",
+			Data:            ev,
+		})
+		return bs, "application/cloudevents+json", err
+	case tenancyv1alpha1.WorkspaceLifecycleWebhookFormatJSON, "":
+		bs, err := json.Marshal(ev)
+		return bs, "application/json", err
+	default:
+		return nil, "", fmt.Errorf("unknown format %q", webhook.Spec.Format)
+	}
+}
+
+// deliverer posts events to webhooks. The webhooks are tenant-controlled, hence it only connects
+// to public addresses, does not follow redirects and does not report responses back.
+type deliverer struct {
+	// dialControl checks the address of every connection before it is established.
+	dialControl func(network, address string, c syscall.RawConn) error
+
+	// lock guards transports.
+	lock sync.Mutex
+	// transports caches one transport per CA bundle, keyed by its hash. Evicted transports close
+	// their idle connections.
+	transports *lru.Cache
+}
+
+func newDeliverer() *deliverer {
+	transports := lru.New(maxCachedTransports)
+	transports.OnEvicted = func(_ lru.Key, t interface{}) {
+		t.(*http.Transport).CloseIdleConnections()
+	}
+	return &deliverer{
+		dialControl: network.PublicAddressDialControl(nil),
+		transports:  transports,
+	}
+}
+
+// transportFor returns the transport trusting the given CA bundle, or the system roots if empty.
+func (d *deliverer) transportFor(caBundle []byte) (*http.Transport, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := fmt.Sprintf("%x", sha256.Sum256(caBundle))
+	if t, found := d.transports.Get(key); found {
+		return t.(*http.Transport), nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to parse caBundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: d.dialControl}
+	t := &http.Transport{
+		// no proxy: it would connect to the webhook on our behalf, unchecked.
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: deliveryTimeout,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConnsPerHost: 2,
+	}
+	d.transports.Add(key, t)
+	return t, nil
+}
+
+// deliver posts the event to the webhook. Every response other than 2xx is an error. Errors do not
+// carry details of the response or connection, as they are shown to the owner of the webhook.
+func (d *deliverer) deliver(ctx context.Context, webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook, ev *event) error {
+	logger := klog.FromContext(ctx)
+
+	bs, contentType, err := body(webhook, ev)
+	if err != nil {
+		return err
+	}
+
+	transport, err := d.transportFor(webhook.Spec.CABundle)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Spec.URL, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.V(4).Info("failed to reach webhook", "err", err.Error())
+		if errors.Is(err, network.ErrNonPublicAddress) {
+			return errNonPublicAddress
+		}
+		return errors.New("failed to reach the webhook")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelifecyclewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+)
+
+// terminatingDeliveryTimeout is how long after the deletion of a Workspace the delivery of its
+// Terminating events is retried before the finalizer is removed anyway.
+const terminatingDeliveryTimeout = 10 * time.Minute
+
+// event is the JSON object sent to webhooks, or the data of the CloudEvent.
+type event struct {
+	// type is the lifecycle transition.
+	Type tenancyv1alpha1.WorkspaceLifecycleEventType `json:"type"`
+	// time is when the transition happened, or was observed for Ready events.
+	Time metav1.Time `json:"time"`
+	// workspace is the Workspace that transitioned.
+	Workspace eventWorkspace `json:"workspace"`
+}
+
+type eventWorkspace struct {
+	Name string    `json:"name"`
+	UID  types.UID `json:"uid"`
+	// parentCluster is the logical cluster the Workspace object lives in.
+	ParentCluster string `json:"parentCluster"`
+	// cluster is the logical cluster of the workspace, once scheduled.
+	Cluster string                                 `json:"cluster,omitempty"`
+	Type    tenancyv1alpha1.WorkspaceTypeReference `json:"type"`
+	Labels  map[string]string                      `json:"labels,omitempty"`
+}
+
+func (c *controller) reconcile(ctx context.Context, workspace *tenancyv1alpha1.Workspace) error {
+	logger := klog.FromContext(ctx)
+
+	webhooks, err := c.listWorkspaceLifecycleWebhooks(logicalcluster.From(workspace))
+	if err != nil {
+		return err
+	}
+	_, found := workspace.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey]
+	finalizers := sets.NewString(workspace.Finalizers...)
+	if len(webhooks) == 0 && !found && !finalizers.Has(tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer) {
+		return nil
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name < webhooks[j].Name })
+
+	delivered := map[string][]tenancyv1alpha1.WorkspaceLifecycleEventType{}
+	if value := workspace.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), &delivered); err != nil {
+			// don't block on a broken annotation, but accept duplicate events.
+			logger.Error(err, "failed to decode delivered lifecycle events")
+			delivered = map[string][]tenancyv1alpha1.WorkspaceLifecycleEventType{}
+		}
+	}
+
+	var errs []error
+	var subscribedTerminating, pendingTerminating bool
+	newDelivered := map[string][]tenancyv1alpha1.WorkspaceLifecycleEventType{}
+	for _, webhook := range webhooks {
+		done := sets.NewString()
+		for _, t := range delivered[webhook.Name] {
+			done.Insert(string(t))
+		}
+		wanted := sets.NewString()
+		for _, t := range webhook.Spec.Events {
+			wanted.Insert(string(t))
+		}
+
+		for _, ev := range eventsOf(workspace) {
+			if done.Has(string(ev.Type)) || !wanted.Has(string(ev.Type)) || !after(ev, workspace, webhook) {
+				continue
+			}
+			logger.V(2).Info("delivering lifecycle event", "event", ev.Type, "workspaceLifecycleWebhook", webhook.Name)
+			if err := c.deliver(ctx, webhook, ev); err != nil {
+				// keep the order of events, retry later.
+				errs = append(errs, fmt.Errorf("failed to deliver %s event to WorkspaceLifecycleWebhook %s: %w", ev.Type, webhook.Name, err))
+				break
+			}
+			done.Insert(string(ev.Type))
+		}
+
+		if wanted.Has(string(tenancyv1alpha1.WorkspaceLifecycleEventTerminating)) {
+			subscribedTerminating = true
+			if workspace.DeletionTimestamp != nil && !workspace.DeletionTimestamp.Before(&webhook.CreationTimestamp) &&
+				!done.Has(string(tenancyv1alpha1.WorkspaceLifecycleEventTerminating)) {
+				pendingTerminating = true
+			}
+		}
+
+		for _, t := range done.List() {
+			newDelivered[webhook.Name] = append(newDelivered[webhook.Name], tenancyv1alpha1.WorkspaceLifecycleEventType(t))
+		}
+	}
+
+	// events of deleted webhooks are dropped.
+	if len(newDelivered) == 0 {
+		delete(workspace.Annotations, tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey)
+	} else {
+		bs, err := json.Marshal(newDelivered)
+		if err != nil {
+			return err
+		}
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey] = string(bs)
+	}
+
+	// hold deleted Workspaces until their Terminating events are delivered, but give up on webhooks
+	// failing for longer than terminatingDeliveryTimeout. Finalizers cannot be added after deletion.
+	switch {
+	case workspace.DeletionTimestamp == nil && subscribedTerminating:
+		if !finalizers.Has(tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer) {
+			workspace.Finalizers = append(workspace.Finalizers, tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer)
+		}
+	case pendingTerminating && c.now().Before(workspace.DeletionTimestamp.Add(terminatingDeliveryTimeout)):
+	case finalizers.Has(tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer):
+		if pendingTerminating {
+			logger.Info("giving up on delivering Terminating events", "timeout", terminatingDeliveryTimeout)
+		}
+		workspace.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer).List()
+	}
+
+	if len(errs) > 0 {
+		err := utilerrors.NewAggregate(errs)
+		conditions.MarkFalse(
+			workspace,
+			tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered,
+			tenancyv1alpha1.WorkspaceLifecycleWebhookDeliveryFailed,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"%v",
+			err,
+		)
+		return err
+	}
+	if len(webhooks) > 0 || conditions.Has(workspace, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered) {
+		conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered)
+	}
+
+	return nil
+}
+
+// eventsOf returns the lifecycle events the Workspace has gone through, in order.
+func eventsOf(workspace *tenancyv1alpha1.Workspace) []*event {
+	ws := eventWorkspace{
+		Name:          workspace.Name,
+		UID:           workspace.UID,
+		ParentCluster: logicalcluster.From(workspace).String(),
+		Cluster:       workspace.Spec.Cluster,
+		Type:          workspace.Spec.Type,
+		Labels:        workspace.Labels,
+	}
+
+	events := []*event{{Type: tenancyv1alpha1.WorkspaceLifecycleEventCreated, Time: workspace.CreationTimestamp, Workspace: ws}}
+	if workspace.Status.Phase == corev1alpha1.LogicalClusterPhaseReady {
+		events = append(events, &event{Type: tenancyv1alpha1.WorkspaceLifecycleEventReady, Time: metav1.Now(), Workspace: ws})
+	}
+	if workspace.DeletionTimestamp != nil {
+		events = append(events, &event{Type: tenancyv1alpha1.WorkspaceLifecycleEventTerminating, Time: *workspace.DeletionTimestamp, Workspace: ws})
+	}
+	return events
+}
+
+// after returns whether the event is sent to the webhook, i.e. whether the workspace has been created,
+// or for Terminating events deleted, after the webhook.
+func after(ev *event, workspace *tenancyv1alpha1.Workspace, webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook) bool {
+	since := workspace.CreationTimestamp
+	if ev.Type == tenancyv1alpha1.WorkspaceLifecycleEventTerminating {
+		since = ev.Time
+	}
+	return !since.Before(&webhook.CreationTimestamp)
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelifecyclewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	kcpfakeclient "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster/fake"
+	tenancyv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/tenancy/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	t0 := metav1.NewTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	t1 := metav1.NewTime(t0.Add(time.Hour))
+	t2 := metav1.NewTime(t0.Add(2 * time.Hour))
+	now := t2.Add(time.Minute)
+
+	webhook := func(name string, created metav1.Time, events ...tenancyv1alpha1.WorkspaceLifecycleEventType) *tenancyv1alpha1.WorkspaceLifecycleWebhook {
+		return &tenancyv1alpha1.WorkspaceLifecycleWebhook{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created},
+			Spec:       tenancyv1alpha1.WorkspaceLifecycleWebhookSpec{URL: "https://" + name, Events: events},
+		}
+	}
+	workspace := func(created metav1.Time, phase corev1alpha1.LogicalClusterPhaseType, deleted *metav1.Time, delivered string) *tenancyv1alpha1.Workspace {
+		ws := &tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "child",
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: "parent"},
+				CreationTimestamp: created,
+				DeletionTimestamp: deleted,
+			},
+			Status: tenancyv1alpha1.WorkspaceStatus{Phase: phase},
+		}
+		if delivered != "" {
+			ws.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey] = delivered
+		}
+		return ws
+	}
+	all := []tenancyv1alpha1.WorkspaceLifecycleEventType{tenancyv1alpha1.WorkspaceLifecycleEventCreated, tenancyv1alpha1.WorkspaceLifecycleEventReady, tenancyv1alpha1.WorkspaceLifecycleEventTerminating}
+
+	tests := map[string]struct {
+		workspace *tenancyv1alpha1.Workspace
+		webhooks  []*tenancyv1alpha1.WorkspaceLifecycleWebhook
+		failing   map[string]bool
+		finalizer bool
+
+		wantDelivered  []string
+		wantAnnotation string
+		wantCondition  bool
+		wantError      bool
+		wantFinalizer  bool
+	}{
+		"no webhooks": {
+			workspace: workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, ""),
+		},
+		"created": {
+			workspace:      workspace(t1, corev1alpha1.LogicalClusterPhaseScheduling, nil, ""),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, all...)},
+			wantDelivered:  []string{"a/Created"},
+			wantAnnotation: `{"a":["Created"]}`,
+			wantCondition:  true,
+			wantFinalizer:  true,
+		},
+		"ready, created already delivered": {
+			workspace:      workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, `{"a":["Created"]}`),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, all...)},
+			wantDelivered:  []string{"a/Ready"},
+			wantAnnotation: `{"a":["Created","Ready"]}`,
+			wantCondition:  true,
+			wantFinalizer:  true,
+		},
+		"only subscribed events": {
+			workspace:      workspace(t1, corev1alpha1.LogicalClusterPhaseReady, &t2, ""),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, tenancyv1alpha1.WorkspaceLifecycleEventTerminating), webhook("b", t0, tenancyv1alpha1.WorkspaceLifecycleEventReady)},
+			wantDelivered:  []string{"a/Terminating", "b/Ready"},
+			wantAnnotation: `{"a":["Terminating"],"b":["Ready"]}`,
+			wantCondition:  true,
+		},
+		"workspace created before webhook only gets terminating": {
+			workspace:      workspace(t0, corev1alpha1.LogicalClusterPhaseReady, &t2, ""),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t1, all...)},
+			wantDelivered:  []string{"a/Terminating"},
+			wantAnnotation: `{"a":["Terminating"]}`,
+			wantCondition:  true,
+		},
+		"failed delivery keeps order and is retried": {
+			workspace:      workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, ""),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, all...), webhook("b", t0, all...)},
+			failing:        map[string]bool{"a/Created": true},
+			wantDelivered:  []string{"b/Created", "b/Ready"},
+			wantAnnotation: `{"b":["Created","Ready"]}`,
+			wantError:      true,
+			wantFinalizer:  true,
+		},
+		"events of deleted webhooks are dropped": {
+			workspace:     workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, `{"gone":["Created","Ready"]}`),
+			wantCondition: false,
+		},
+		"no finalizer without terminating subscribers": {
+			workspace:      workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, `{"a":["Created"]}`),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, tenancyv1alpha1.WorkspaceLifecycleEventCreated)},
+			finalizer:      true,
+			wantAnnotation: `{"a":["Created"]}`,
+			wantCondition:  true,
+		},
+		"finalizer of deleted webhooks is removed": {
+			workspace: workspace(t1, corev1alpha1.LogicalClusterPhaseReady, nil, ""),
+			finalizer: true,
+		},
+		"finalizer is removed after terminating is delivered": {
+			workspace:      workspace(t0, corev1alpha1.LogicalClusterPhaseReady, &t2, ""),
+			webhooks:       []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t1, all...)},
+			finalizer:      true,
+			wantDelivered:  []string{"a/Terminating"},
+			wantAnnotation: `{"a":["Terminating"]}`,
+			wantCondition:  true,
+		},
+		"failed terminating delivery holds the finalizer": {
+			workspace:     workspace(t0, corev1alpha1.LogicalClusterPhaseReady, &t2, ""),
+			webhooks:      []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t1, all...)},
+			failing:       map[string]bool{"a/Terminating": true},
+			finalizer:     true,
+			wantError:     true,
+			wantFinalizer: true,
+		},
+		"failed terminating delivery releases the finalizer after the timeout": {
+			workspace: workspace(t0, corev1alpha1.LogicalClusterPhaseReady, &t1, ""),
+			webhooks:  []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t0, tenancyv1alpha1.WorkspaceLifecycleEventTerminating)},
+			failing:   map[string]bool{"a/Terminating": true},
+			finalizer: true,
+			wantError: true,
+		},
+		"finalizer is not added after deletion": {
+			workspace: workspace(t0, corev1alpha1.LogicalClusterPhaseReady, &t2, ""),
+			webhooks:  []*tenancyv1alpha1.WorkspaceLifecycleWebhook{webhook("a", t1, all...)},
+			failing:   map[string]bool{"a/Terminating": true},
+			wantError: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var delivered []string
+			c := &controller{
+				listWorkspaceLifecycleWebhooks: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceLifecycleWebhook, error) {
+					require.Equal(t, logicalcluster.Name("parent"), clusterName)
+					return tt.webhooks, nil
+				},
+				deliver: func(ctx context.Context, webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook, ev *event) error {
+					key := webhook.Name + "/" + string(ev.Type)
+					if tt.failing[key] {
+						return errors.New("connection refused")
+					}
+					delivered = append(delivered, key)
+					return nil
+				},
+				now: func() time.Time { return now },
+			}
+
+			ws := tt.workspace.DeepCopy()
+			if tt.finalizer {
+				ws.Finalizers = []string{"other", tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer}
+			}
+			err := c.reconcile(context.Background(), ws)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantDelivered, delivered)
+			require.Equal(t, tt.wantAnnotation, ws.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey])
+			if tt.wantError {
+				require.True(t, conditions.IsFalse(ws, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered))
+			} else {
+				require.Equal(t, tt.wantCondition, conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered))
+			}
+			require.Equal(t, tt.wantFinalizer, sets.NewString(ws.Finalizers...).Has(tenancyv1alpha1.WorkspaceLifecycleWebhookFinalizer))
+			if tt.finalizer {
+				require.Contains(t, ws.Finalizers, "other", "other finalizers must be kept")
+			}
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	t0 := metav1.NewTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	clusterName := logicalcluster.Name("parent")
+
+	client := kcpfakeclient.NewSimpleClientset(&tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "child",
+			Annotations:       map[string]string{logicalcluster.AnnotationKey: clusterName.String()},
+			CreationTimestamp: metav1.NewTime(t0.Add(time.Hour)),
+		},
+		Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseReady},
+	})
+	getWorkspace := func() *tenancyv1alpha1.Workspace {
+		ws, err := client.Cluster(clusterName.Path()).TenancyV1alpha1().Workspaces().Get(context.Background(), "child", metav1.GetOptions{})
+		require.NoError(t, err)
+		return ws
+	}
+
+	var delivered []string
+	c := &controller{
+		getWorkspace: func(_ logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			return client.Cluster(clusterName.Path()).TenancyV1alpha1().Workspaces().Get(context.Background(), name, metav1.GetOptions{})
+		},
+		listWorkspaceLifecycleWebhooks: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceLifecycleWebhook, error) {
+			return []*tenancyv1alpha1.WorkspaceLifecycleWebhook{{
+				ObjectMeta: metav1.ObjectMeta{Name: "a", CreationTimestamp: t0},
+				Spec:       tenancyv1alpha1.WorkspaceLifecycleWebhookSpec{URL: "https://a", Events: []tenancyv1alpha1.WorkspaceLifecycleEventType{tenancyv1alpha1.WorkspaceLifecycleEventCreated}},
+			}}, nil
+		},
+		deliver: func(ctx context.Context, webhook *tenancyv1alpha1.WorkspaceLifecycleWebhook, ev *event) error {
+			delivered = append(delivered, webhook.Name+"/"+string(ev.Type))
+			return nil
+		},
+		commit: committer.NewCommitter[*tenancyv1alpha1.Workspace, tenancyv1alpha1client.WorkspaceInterface, *tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus](client.TenancyV1alpha1().Workspaces()),
+	}
+	key := kcpcache.ToClusterAwareKey(clusterName.String(), "", "child")
+
+	// the first pass delivers and records the delivered events, but not the status.
+	require.NoError(t, c.process(context.Background(), key))
+	require.Equal(t, []string{"a/Created"}, delivered)
+	ws := getWorkspace()
+	require.Equal(t, `{"a":["Created"]}`, ws.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey])
+	require.False(t, conditions.Has(ws, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered))
+
+	// the second pass updates the status, without delivering again.
+	require.NoError(t, c.process(context.Background(), key))
+	require.Equal(t, []string{"a/Created"}, delivered)
+	ws = getWorkspace()
+	require.Equal(t, `{"a":["Created"]}`, ws.Annotations[tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey])
+	require.True(t, conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceLifecycleWebhooksDelivered))
+}
+
+func TestDeliver(t *testing.T) {
+	var gotContentType string
+	var gotBody map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+			return
+		}
+		gotContentType = r.Header.Get("Content-Type")
+		bs, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(bs, &gotBody))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("internal details"))
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	ev := &event{
+		Type:      tenancyv1alpha1.WorkspaceLifecycleEventCreated,
+		Time:      metav1.NewTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)),
+		Workspace: eventWorkspace{Name: "child", UID: "uid", ParentCluster: "parent"},
+	}
+	webhook := &tenancyv1alpha1.WorkspaceLifecycleWebhook{
+		Spec: tenancyv1alpha1.WorkspaceLifecycleWebhookSpec{URL: server.URL, CABundle: caBundle, Format: tenancyv1alpha1.WorkspaceLifecycleWebhookFormatJSON},
+	}
+
+	err := newDeliverer().deliver(context.Background(), webhook, ev)
+	require.ErrorIs(t, err, errNonPublicAddress, "loopback addresses must be denied")
+	require.Nil(t, gotBody)
+
+	// the test server listens on loopback.
+	d := newDeliverer()
+	d.dialControl = nil
+
+	require.NoError(t, d.deliver(context.Background(), webhook, ev))
+	require.Equal(t, "application/json", gotContentType)
+	require.Equal(t, "Created", gotBody["type"])
+
+	webhook.Spec.Format = tenancyv1alpha1.WorkspaceLifecycleWebhookFormatCloudEvents
+	require.NoError(t, d.deliver(context.Background(), webhook, ev))
+	require.Equal(t, "application/cloudevents+json", gotContentType)
+	require.Equal(t, "1.0", gotBody["specversion"])
+	require.Equal(t, "io.kcp.tenancy.workspace.created", gotBody["type"])
+	require.Equal(t, "/clusters/parent/apis/tenancy.kcp.io/v1alpha1/workspaces/child", gotBody["source"])
+	require.Equal(t, "Created", gotBody["data"].(map[string]interface{})["type"])
+	require.Equal(t, 1, d.transports.Len(), "transports must be reused")
+
+	status = http.StatusInternalServerError
+	require.EqualError(t, d.deliver(context.Background(), webhook, ev), "webhook responded with status code 500")
+
+	status = http.StatusOK
+	webhook.Spec.URL = server.URL + "/redirect"
+	require.EqualError(t, d.deliver(context.Background(), webhook, ev), "webhook responded with status code 307", "redirects must not be followed")
+
+	webhook.Spec.URL = server.URL
+	webhook.Spec.CABundle = nil
+	require.EqualError(t, d.deliver(context.Background(), webhook, ev), "failed to reach the webhook", "server certificate must not be trusted without caBundle")
+}
+
+func TestEvictedTransportsCloseIdleConnections(t *testing.T) {
+	closed := make(chan struct{})
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			close(closed)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	d := newDeliverer()
+	d.dialControl = nil
+	transport, err := d.transportFor(nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	require.NoError(t, resp.Body.Close())
+
+	d.transports.RemoveOldest()
+	select {
+	case <-closed:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("idle connection of the evicted transport was not closed")
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacerequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	tenancyv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/tenancy/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/tenancy/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-workspacerequest"
+)
+
+// NewController returns a new controller for WorkspaceRequests.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	workspaceRequestInformer tenancyinformers.WorkspaceRequestClusterInformer,
+	workspaceInformer tenancyinformers.WorkspaceClusterInformer,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
+
+	workspaceLister := workspaceInformer.Lister()
+	c := &controller{
+		queue:                  queue,
+		workspaceRequestLister: workspaceRequestInformer.Lister(),
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			return workspaceLister.Cluster(clusterName).Get(name)
+		},
+		createWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error) {
+			return kcpClusterClient.Cluster(clusterName.Path()).TenancyV1alpha1().Workspaces().Create(ctx, ws, metav1.CreateOptions{})
+		},
+		commit: committer.NewCommitter[*WorkspaceRequest, Patcher, *WorkspaceRequestSpec, *WorkspaceRequestStatus](kcpClusterClient.TenancyV1alpha1().WorkspaceRequests()),
+	}
+
+	workspaceRequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspaceRequest(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspaceRequest(obj) },
+	})
+
+	workspaceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			ws, ok := obj.(*tenancyv1alpha1.Workspace)
+			if !ok {
+				return false
+			}
+			_, found := ws.Annotations[tenancyv1alpha1.WorkspaceRequestAnnotationKey]
+			return found
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueWorkspace(obj) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspace(obj) },
+			DeleteFunc: func(obj interface{}) { c.enqueueWorkspace(obj) },
+		},
+	})
+
+	return c, nil
+}
+
+type WorkspaceRequest = tenancyv1alpha1.WorkspaceRequest
+type WorkspaceRequestSpec = tenancyv1alpha1.WorkspaceRequestSpec
+type WorkspaceRequestStatus = tenancyv1alpha1.WorkspaceRequestStatus
+type Patcher = tenancyv1alpha1client.WorkspaceRequestInterface
+type Resource = committer.Resource[*WorkspaceRequestSpec, *WorkspaceRequestStatus]
+type CommitFunc = func(context.Context, *Resource, *Resource) error
+
+// controller reconciles WorkspaceRequests. It creates the requested Workspace when a request is approved,
+// and reports the progress in the request status.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	workspaceRequestLister tenancyv1alpha1listers.WorkspaceRequestClusterLister
+	getWorkspace           func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error)
+	createWorkspace        func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error)
+	commit                 CommitFunc
+}
+
+// enqueueWorkspaceRequest enqueues a WorkspaceRequest.
+func (c *controller) enqueueWorkspaceRequest(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(2).Info("queueing WorkspaceRequest")
+	c.queue.Add(key)
+}
+
+// enqueueWorkspace enqueues the WorkspaceRequest a Workspace was created for.
+func (c *controller) enqueueWorkspace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ws, ok := obj.(*tenancyv1alpha1.Workspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected type %T", obj))
+		return
+	}
+
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(ws).String(), "", ws.Annotations[tenancyv1alpha1.WorkspaceRequestAnnotationKey])
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logging.WithObject(logger, ws).V(2).Info("queueing WorkspaceRequest because of Workspace")
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+	obj, err := c.workspaceRequestLister.Cluster(clusterName).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	reconcileErr := c.reconcile(ctx, obj)
+
+	// If the object being reconciled changed as a result, update it.
+	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		return err
+	}
+
+	return reconcileErr
+}
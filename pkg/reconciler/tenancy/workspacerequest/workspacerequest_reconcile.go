@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacerequest
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, req *tenancyv1alpha1.WorkspaceRequest) error {
+	switch req.Status.Decision {
+	case "":
+		req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhasePending
+		return nil
+	case tenancyv1alpha1.WorkspaceRequestRejected:
+		req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhaseRejected
+		return nil
+	}
+
+	// the workspace is created once. If it is deleted later, it is not recreated.
+	if req.Status.Phase == tenancyv1alpha1.WorkspaceRequestPhaseCompleted {
+		return nil
+	}
+	req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhaseApproved
+
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(req)
+	ws, err := c.getWorkspace(clusterName, req.Spec.WorkspaceName)
+	if errors.IsNotFound(err) {
+		logger.V(2).Info("creating Workspace for approved WorkspaceRequest", "workspace", req.Spec.WorkspaceName)
+		ws, err = c.createWorkspace(ctx, clusterName, workspaceFor(req))
+		if errors.IsAlreadyExists(err) {
+			// the informer has not seen it yet. We will be triggered again.
+			return nil
+		}
+	}
+	if errors.IsForbidden(err) || errors.IsInvalid(err) {
+		conditions.MarkFalse(req, tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, tenancyv1alpha1.WorkspaceRequestCreateFailedReason, conditionsv1alpha1.ConditionSeverityError, "Failed to create Workspace: %v", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if ws.Annotations[tenancyv1alpha1.WorkspaceRequestAnnotationKey] != req.Name {
+		conditions.MarkFalse(req, tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, tenancyv1alpha1.WorkspaceRequestWorkspaceExistsReason, conditionsv1alpha1.ConditionSeverityError, "Workspace %q already exists", ws.Name)
+		return nil
+	}
+
+	conditions.MarkTrue(req, tenancyv1alpha1.WorkspaceRequestWorkspaceCreated)
+	req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhaseCompleted
+	return nil
+}
+
+// workspaceFor returns the Workspace to create for the given approved WorkspaceRequest. The
+// requester becomes the owner of the workspace.
+func workspaceFor(req *tenancyv1alpha1.WorkspaceRequest) *tenancyv1alpha1.Workspace {
+	ws := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: req.Spec.WorkspaceName,
+			Annotations: map[string]string{
+				tenancyv1alpha1.WorkspaceRequestAnnotationKey: req.Name,
+			},
+		},
+	}
+	if owner, found := req.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey]; found {
+		ws.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey] = owner
+	}
+	if req.Spec.Type != nil {
+		ws.Spec.Type = *req.Spec.Type
+	}
+	return ws
+}
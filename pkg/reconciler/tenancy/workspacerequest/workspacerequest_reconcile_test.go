@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacerequest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+)
+
+const owner = `{"username":"requester"}`
+
+func newRequest(decision tenancyv1alpha1.WorkspaceRequestDecision) *tenancyv1alpha1.WorkspaceRequest {
+	return &tenancyv1alpha1.WorkspaceRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "request",
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:                            "root:org",
+				tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey: owner,
+			},
+		},
+		Spec: tenancyv1alpha1.WorkspaceRequestSpec{
+			WorkspaceName: "team",
+			Type:          &tenancyv1alpha1.WorkspaceTypeReference{Name: "universal", Path: "root"},
+		},
+		Status: tenancyv1alpha1.WorkspaceRequestStatus{
+			Decision: decision,
+		},
+	}
+}
+
+func newWorkspace(request string) *tenancyv1alpha1.Workspace {
+	ws := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}
+	if request != "" {
+		ws.Annotations[tenancyv1alpha1.WorkspaceRequestAnnotationKey] = request
+	}
+	return ws
+}
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		req       *tenancyv1alpha1.WorkspaceRequest
+		existing  *tenancyv1alpha1.Workspace
+		createErr error
+
+		wantCreated   bool
+		wantPhase     tenancyv1alpha1.WorkspaceRequestPhaseType
+		wantCondition *conditionsv1alpha1.Condition
+		wantErr       bool
+	}{
+		"pending": {
+			req:       newRequest(""),
+			wantPhase: tenancyv1alpha1.WorkspaceRequestPhasePending,
+		},
+		"rejected": {
+			req:       newRequest(tenancyv1alpha1.WorkspaceRequestRejected),
+			wantPhase: tenancyv1alpha1.WorkspaceRequestPhaseRejected,
+		},
+		"approved creates the workspace": {
+			req:           newRequest(tenancyv1alpha1.WorkspaceRequestApproved),
+			wantCreated:   true,
+			wantPhase:     tenancyv1alpha1.WorkspaceRequestPhaseCompleted,
+			wantCondition: &conditionsv1alpha1.Condition{Type: tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, Status: "True"},
+		},
+		"approved with workspace created before": {
+			req:           newRequest(tenancyv1alpha1.WorkspaceRequestApproved),
+			existing:      newWorkspace("request"),
+			wantPhase:     tenancyv1alpha1.WorkspaceRequestPhaseCompleted,
+			wantCondition: &conditionsv1alpha1.Condition{Type: tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, Status: "True"},
+		},
+		"approved with foreign workspace of the same name": {
+			req:           newRequest(tenancyv1alpha1.WorkspaceRequestApproved),
+			existing:      newWorkspace(""),
+			wantPhase:     tenancyv1alpha1.WorkspaceRequestPhaseApproved,
+			wantCondition: &conditionsv1alpha1.Condition{Type: tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, Status: "False", Reason: tenancyv1alpha1.WorkspaceRequestWorkspaceExistsReason},
+		},
+		"approved with workspace rejected by admission": {
+			req:           newRequest(tenancyv1alpha1.WorkspaceRequestApproved),
+			createErr:     errors.NewForbidden(tenancyv1alpha1.Resource("workspaces"), "team", fmt.Errorf("type not allowed")),
+			wantCreated:   true,
+			wantPhase:     tenancyv1alpha1.WorkspaceRequestPhaseApproved,
+			wantCondition: &conditionsv1alpha1.Condition{Type: tenancyv1alpha1.WorkspaceRequestWorkspaceCreated, Status: "False", Reason: tenancyv1alpha1.WorkspaceRequestCreateFailedReason},
+		},
+		"approved with transient create error": {
+			req:         newRequest(tenancyv1alpha1.WorkspaceRequestApproved),
+			createErr:   fmt.Errorf("connection refused"),
+			wantCreated: true,
+			wantPhase:   tenancyv1alpha1.WorkspaceRequestPhaseApproved,
+			wantErr:     true,
+		},
+		"completed with deleted workspace": {
+			req: func() *tenancyv1alpha1.WorkspaceRequest {
+				req := newRequest(tenancyv1alpha1.WorkspaceRequestApproved)
+				req.Status.Phase = tenancyv1alpha1.WorkspaceRequestPhaseCompleted
+				return req
+			}(),
+			wantPhase: tenancyv1alpha1.WorkspaceRequestPhaseCompleted,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created *tenancyv1alpha1.Workspace
+			c := &controller{
+				getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+					require.Equal(t, logicalcluster.Name("root:org"), clusterName)
+					require.Equal(t, "team", name)
+					if tt.existing == nil {
+						return nil, errors.NewNotFound(tenancyv1alpha1.Resource("workspaces"), name)
+					}
+					return tt.existing, nil
+				},
+				createWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error) {
+					require.Equal(t, logicalcluster.Name("root:org"), clusterName)
+					created = ws
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return ws, nil
+				},
+			}
+
+			err := c.reconcile(context.Background(), tt.req)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPhase, tt.req.Status.Phase)
+
+			if tt.wantCreated {
+				require.NotNil(t, created)
+				require.Equal(t, "team", created.Name)
+				require.Equal(t, owner, created.Annotations[tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey])
+				require.Equal(t, "request", created.Annotations[tenancyv1alpha1.WorkspaceRequestAnnotationKey])
+				require.Equal(t, tenancyv1alpha1.WorkspaceTypeReference{Name: "universal", Path: "root"}, created.Spec.Type)
+			} else {
+				require.Nil(t, created)
+			}
+
+			if tt.wantCondition == nil {
+				require.Nil(t, conditions.Get(tt.req, tenancyv1alpha1.WorkspaceRequestWorkspaceCreated))
+			} else {
+				got := conditions.Get(tt.req, tenancyv1alpha1.WorkspaceRequestWorkspaceCreated)
+				require.NotNil(t, got)
+				require.Equal(t, tt.wantCondition.Status, got.Status)
+				require.Equal(t, tt.wantCondition.Reason, got.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-workspacetemplate"
+
+	// byTemplate indexes LogicalClusters by the logical cluster of the workspace owning them and
+	// their WorkspaceTemplate, as <cluster>|<template>.
+	byTemplate = "workspacetemplate-byTemplate"
+)
+
+// NewController returns a new controller which creates the APIBindings, ClusterRoles and
+// ClusterRoleBindings of the WorkspaceTemplate of a workspace once the workspace is ready.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+	workspaceTemplateInformer, globalWorkspaceTemplateInformer tenancyv1alpha1informers.WorkspaceTemplateClusterInformer,
+	apiExportInformer, globalAPIExportInformer apisv1alpha1informers.APIExportClusterInformer,
+) (*controller, error) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName),
+
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		listLogicalClustersWithTemplate: func(clusterName logicalcluster.Name, template string) ([]*corev1alpha1.LogicalCluster, error) {
+			return indexers.ByIndex[*corev1alpha1.LogicalCluster](logicalClusterInformer.Informer().GetIndexer(), byTemplate, templateKey(clusterName.String(), template))
+		},
+		setTemplateApplied: func(ctx context.Context, clusterName logicalcluster.Path, template string) error {
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{tenancyv1alpha1.WorkspaceTemplateAppliedAnnotationKey: template},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			_, err = kcpClusterClient.Cluster(clusterName).CoreV1alpha1().LogicalClusters().Patch(ctx, corev1alpha1.LogicalClusterName, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		},
+
+		getWorkspaceTemplate: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error) {
+			template, err := workspaceTemplateInformer.Lister().Cluster(clusterName).Get(name)
+			if apierrors.IsNotFound(err) {
+				// the parent might live on another shard.
+				return globalWorkspaceTemplateInformer.Lister().Cluster(clusterName).Get(name)
+			}
+			return template, err
+		},
+		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return indexers.ByPathAndNameWithFallback[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), apiExportInformer.Informer().GetIndexer(), globalAPIExportInformer.Informer().GetIndexer(), path, name)
+		},
+
+		createAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
+			return err
+		},
+		createClusterRole: func(ctx context.Context, clusterName logicalcluster.Path, role *rbacv1.ClusterRole) error {
+			_, err := kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+			return err
+		},
+		createClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *rbacv1.ClusterRoleBinding) error {
+			_, err := kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+			return err
+		},
+	}
+
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+
+	indexers.AddIfNotPresentOrDie(logicalClusterInformer.Informer().GetIndexer(), cache.Indexers{
+		byTemplate: indexByTemplate,
+	})
+	indexers.AddIfNotPresentOrDie(apiExportInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+	indexers.AddIfNotPresentOrDie(globalAPIExportInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+
+	logicalClusterInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			logicalCluster, ok := obj.(*corev1alpha1.LogicalCluster)
+			return ok && pending(logicalCluster)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueLogicalCluster(obj, logger)
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				c.enqueueLogicalCluster(obj, logger)
+			},
+		},
+	})
+
+	for _, informer := range []tenancyv1alpha1informers.WorkspaceTemplateClusterInformer{workspaceTemplateInformer, globalWorkspaceTemplateInformer} {
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueWorkspaceTemplate(obj, logger)
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller stamps out the objects of WorkspaceTemplates in workspaces created from them. It is
+// keyed by LogicalCluster.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getLogicalCluster               func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	listLogicalClustersWithTemplate func(clusterName logicalcluster.Name, template string) ([]*corev1alpha1.LogicalCluster, error)
+	setTemplateApplied              func(ctx context.Context, clusterName logicalcluster.Path, template string) error
+
+	getWorkspaceTemplate func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error)
+	getAPIExport         func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+
+	createAPIBinding         func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error
+	createClusterRole        func(ctx context.Context, clusterName logicalcluster.Path, role *rbacv1.ClusterRole) error
+	createClusterRoleBinding func(ctx context.Context, clusterName logicalcluster.Path, binding *rbacv1.ClusterRoleBinding) error
+}
+
+// pending returns whether the WorkspaceTemplate of the LogicalCluster has not been applied yet.
+func pending(logicalCluster *corev1alpha1.LogicalCluster) bool {
+	template, found := logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]
+	return found && logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateAppliedAnnotationKey] != template
+}
+
+func templateKey(clusterName, template string) string {
+	return clusterName + "|" + template
+}
+
+func indexByTemplate(obj interface{}) ([]string, error) {
+	logicalCluster, ok := obj.(*corev1alpha1.LogicalCluster)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a LogicalCluster, but is %T", obj)
+	}
+	template, found := logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]
+	if !found || logicalCluster.Spec.Owner == nil || logicalCluster.Spec.Owner.Cluster == "" {
+		return []string{}, nil
+	}
+	return []string{templateKey(logicalCluster.Spec.Owner.Cluster, template)}, nil
+}
+
+func (c *controller) enqueueLogicalCluster(obj interface{}, logger logr.Logger) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(2).Info("queueing LogicalCluster")
+	c.queue.Add(key)
+}
+
+// enqueueWorkspaceTemplate enqueues the workspaces on this shard waiting for a WorkspaceTemplate,
+// e.g. because it has not been replicated yet when they became ready.
+func (c *controller) enqueueWorkspaceTemplate(obj interface{}, logger logr.Logger) {
+	template, ok := obj.(*tenancyv1alpha1.WorkspaceTemplate)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a WorkspaceTemplate, but is %T", obj))
+		return
+	}
+
+	logicalClusters, err := c.listLogicalClustersWithTemplate(logicalcluster.From(template), template.Name)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger = logging.WithObject(logger, template)
+	for _, logicalCluster := range logicalClusters {
+		if pending(logicalCluster) {
+			c.enqueueLogicalCluster(logicalCluster, logger.WithValues("reason", "WorkspaceTemplate added"))
+		}
+	}
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%s: failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "unable to decode key")
+		return nil
+	}
+
+	logicalCluster, err := c.getLogicalCluster(clusterName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil // deleted in the meantime
+	}
+
+	logger = logging.WithObject(logger, logicalCluster)
+	ctx = klog.NewContext(ctx, logger)
+
+	return c.reconcile(ctx, logicalCluster)
+}
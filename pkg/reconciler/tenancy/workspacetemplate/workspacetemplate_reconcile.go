@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/permissionclaim"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func (c *controller) reconcile(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	logger := klog.FromContext(ctx)
+
+	if !logicalCluster.DeletionTimestamp.IsZero() || !pending(logicalCluster) {
+		return nil
+	}
+	if logicalCluster.Status.Phase != corev1alpha1.LogicalClusterPhaseReady {
+		return nil // wait for initialization to finish, we are enqueued again on update.
+	}
+	owner := logicalCluster.Spec.Owner
+	if owner == nil || owner.Cluster == "" || owner.Resource != "workspaces" {
+		return nil // not a child workspace
+	}
+	clusterName := logicalcluster.From(logicalCluster)
+	parent := logicalcluster.Name(owner.Cluster)
+	name := logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateAnnotationKey]
+
+	template, err := c.getWorkspaceTemplate(parent, name)
+	if err != nil {
+		// retry, the template might not be replicated yet.
+		return fmt.Errorf("failed to get WorkspaceTemplate %s|%s: %w", parent, name, err)
+	}
+	if generation := strconv.FormatInt(template.Generation, 10); logicalCluster.Annotations[tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey] != generation {
+		// the creator of the workspace was authorized for another generation of the template.
+		logger.Info("not applying WorkspaceTemplate changed since the workspace was created", "workspaceTemplate", template.Name, "generation", generation)
+		return nil
+	}
+	labels := map[string]string{tenancyv1alpha1.WorkspaceTemplateLabelKey: template.Name}
+
+	var errs []error
+	for _, bundle := range template.Spec.APIBindings {
+		exportRef := apisv1alpha1.ExportBindingReference{Path: bundle.Path, Name: bundle.Export}
+		if exportRef.Path == "" {
+			exportRef.Path = parent.String()
+		}
+		apiExport, err := c.getAPIExport(logicalcluster.NewPath(exportRef.Path), exportRef.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get APIExport %s|%s: %w", exportRef.Path, exportRef.Name, err))
+			continue
+		}
+
+		apiBinding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   exportRef.Name,
+				Labels: labels,
+			},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.BindingReference{
+					Export: &exportRef,
+				},
+			},
+		}
+		for _, exportClaim := range apiExport.Spec.PermissionClaims {
+			claim, accepted := accept(bundle, exportClaim)
+			if !accepted {
+				// left to the workspace owner
+				continue
+			}
+			apiBinding.Spec.PermissionClaims = append(apiBinding.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+				PermissionClaim: claim,
+				State:           apisv1alpha1.ClaimAccepted,
+			})
+		}
+
+		logger.V(2).Info("creating APIBinding for WorkspaceTemplate", "apiBinding", apiBinding.Name)
+		if err := c.createAPIBinding(ctx, clusterName.Path(), apiBinding); err != nil && !apierrors.IsAlreadyExists(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, r := range template.Spec.ClusterRoles {
+		role := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: r.Name, Labels: labels},
+			Rules:      r.Rules,
+		}
+		logger.V(2).Info("creating ClusterRole for WorkspaceTemplate", "clusterRole", role.Name)
+		if err := c.createClusterRole(ctx, clusterName.Path(), role); err != nil && !apierrors.IsAlreadyExists(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, b := range template.Spec.ClusterRoleBindings {
+		binding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: b.Name, Labels: labels},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     b.ClusterRole,
+			},
+			Subjects: b.Subjects,
+		}
+		logger.V(2).Info("creating ClusterRoleBinding for WorkspaceTemplate", "clusterRoleBinding", binding.Name)
+		if err := c.createClusterRoleBinding(ctx, clusterName.Path(), binding); err != nil && !apierrors.IsAlreadyExists(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// objects are created only once, later changes to the template are not applied.
+	logger.V(2).Info("WorkspaceTemplate applied", "workspaceTemplate", template.Name)
+	return c.setTemplateApplied(ctx, clusterName.Path(), template.Name)
+}
+
+// accept returns the claim to accept for the given claim of the APIExport, and whether the bundle accepts it.
+func accept(bundle tenancyv1alpha1.APIBindingClaimBundle, claim apisv1alpha1.PermissionClaim) (apisv1alpha1.PermissionClaim, bool) {
+	for _, accepted := range bundle.AcceptedPermissionClaims {
+		if narrowed, ok := permissionclaim.Narrow(accepted, claim); ok {
+			return narrowed, true
+		}
+	}
+	return apisv1alpha1.PermissionClaim{}, false
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	claim := func(resource string) apisv1alpha1.PermissionClaim {
+		return apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: resource}, All: true}
+	}
+	child := func(phase corev1alpha1.LogicalClusterPhaseType, annotations ...string) *corev1alpha1.LogicalCluster {
+		lc := &corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: map[string]string{logicalcluster.AnnotationKey: "child"}},
+			Spec: corev1alpha1.LogicalClusterSpec{
+				Owner: &corev1alpha1.LogicalClusterOwner{APIVersion: "tenancy.kcp.io/v1alpha1", Resource: "workspaces", Name: "child", Cluster: "parent"},
+			},
+			Status: corev1alpha1.LogicalClusterStatus{Phase: phase},
+		}
+		for i := 0; i+1 < len(annotations); i += 2 {
+			lc.Annotations[annotations[i]] = annotations[i+1]
+		}
+		return lc
+	}
+	subject := rbacv1.Subject{Kind: "Group", APIGroup: rbacv1.GroupName, Name: "team-a"}
+	templates := map[string]*tenancyv1alpha1.WorkspaceTemplate{
+		"parent|team": {
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Generation: 2},
+			Spec: tenancyv1alpha1.WorkspaceTemplateSpec{
+				APIBindings: []tenancyv1alpha1.APIBindingClaimBundle{
+					{APIExportReference: tenancyv1alpha1.APIExportReference{Export: "widgets"}, AcceptedPermissionClaims: []apisv1alpha1.PermissionClaim{claim("configmaps")}},
+					{APIExportReference: tenancyv1alpha1.APIExportReference{Path: "root:org", Export: "gadgets"}},
+				},
+				ClusterRoles: []tenancyv1alpha1.WorkspaceTemplateClusterRole{
+					{Name: "widget-editor", Rules: []rbacv1.PolicyRule{{APIGroups: []string{"widgets.io"}, Resources: []string{"widgets"}, Verbs: []string{"*"}}}},
+				},
+				ClusterRoleBindings: []tenancyv1alpha1.WorkspaceTemplateClusterRoleBinding{
+					{Name: "team-a", ClusterRole: "widget-editor", Subjects: []rbacv1.Subject{subject}},
+				},
+			},
+		},
+		"parent|broken": {
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Generation: 2},
+			Spec: tenancyv1alpha1.WorkspaceTemplateSpec{
+				APIBindings: []tenancyv1alpha1.APIBindingClaimBundle{{APIExportReference: tenancyv1alpha1.APIExportReference{Export: "missing"}}},
+			},
+		},
+	}
+	exports := map[string]*apisv1alpha1.APIExport{
+		"parent|widgets": {
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+			Spec:       apisv1alpha1.APIExportSpec{PermissionClaims: []apisv1alpha1.PermissionClaim{claim("secrets"), claim("configmaps")}},
+		},
+		"root:org|gadgets": {ObjectMeta: metav1.ObjectMeta{Name: "gadgets"}},
+	}
+	labels := map[string]string{tenancyv1alpha1.WorkspaceTemplateLabelKey: "team"}
+
+	tests := map[string]struct {
+		logicalCluster *corev1alpha1.LogicalCluster
+		createErr      error
+
+		wantBindings            []*apisv1alpha1.APIBinding
+		wantClusterRoles        []*rbacv1.ClusterRole
+		wantClusterRoleBindings []*rbacv1.ClusterRoleBinding
+		wantApplied             string
+		wantError               bool
+	}{
+		"creates objects of the template when ready": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "2"),
+			wantBindings: []*apisv1alpha1.APIBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "widgets", Labels: labels},
+					Spec: apisv1alpha1.APIBindingSpec{
+						Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "parent", Name: "widgets"}},
+						PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+							{PermissionClaim: claim("configmaps"), State: apisv1alpha1.ClaimAccepted},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "gadgets", Labels: labels},
+					Spec: apisv1alpha1.APIBindingSpec{
+						Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "root:org", Name: "gadgets"}},
+					},
+				},
+			},
+			wantClusterRoles: []*rbacv1.ClusterRole{{
+				ObjectMeta: metav1.ObjectMeta{Name: "widget-editor", Labels: labels},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"widgets.io"}, Resources: []string{"widgets"}, Verbs: []string{"*"}}},
+			}},
+			wantClusterRoleBindings: []*rbacv1.ClusterRoleBinding{{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: labels},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "widget-editor"},
+				Subjects:   []rbacv1.Subject{subject},
+			}},
+			wantApplied: "team",
+		},
+		"not applied on missing APIExport": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "broken", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "2"),
+			wantError:      true,
+		},
+		"already exists is not an error": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "2"),
+			createErr:      apierrors.NewAlreadyExists(apisv1alpha1.Resource("apibindings"), "widgets"),
+			wantApplied:    "team",
+		},
+		"not applied when the template changed after the workspace was created": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "1"),
+		},
+		"waits for initialization": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseInitializing, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateGenerationAnnotationKey, "2"),
+		},
+		"already applied": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "team", tenancyv1alpha1.WorkspaceTemplateAppliedAnnotationKey, "team"),
+		},
+		"retries on missing template": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady, tenancyv1alpha1.WorkspaceTemplateAnnotationKey, "missing"),
+			wantError:      true,
+		},
+		"ignores workspaces without template": {
+			logicalCluster: child(corev1alpha1.LogicalClusterPhaseReady),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var bindings []*apisv1alpha1.APIBinding
+			var roles []*rbacv1.ClusterRole
+			var roleBindings []*rbacv1.ClusterRoleBinding
+			var applied string
+			c := &controller{
+				setTemplateApplied: func(ctx context.Context, clusterName logicalcluster.Path, template string) error {
+					require.Equal(t, "child", clusterName.String())
+					applied = template
+					return nil
+				},
+				getWorkspaceTemplate: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceTemplate, error) {
+					if template, found := templates[clusterName.String()+"|"+name]; found {
+						return template, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspacetemplates"), name)
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					if export, found := exports[path.String()+"|"+name]; found {
+						return export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				createAPIBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *apisv1alpha1.APIBinding) error {
+					require.Equal(t, "child", clusterName.String())
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					bindings = append(bindings, binding)
+					return nil
+				},
+				createClusterRole: func(ctx context.Context, clusterName logicalcluster.Path, role *rbacv1.ClusterRole) error {
+					require.Equal(t, "child", clusterName.String())
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					roles = append(roles, role)
+					return nil
+				},
+				createClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Path, binding *rbacv1.ClusterRoleBinding) error {
+					require.Equal(t, "child", clusterName.String())
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					roleBindings = append(roleBindings, binding)
+					return nil
+				},
+			}
+
+			err := c.reconcile(context.Background(), tt.logicalCluster)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantBindings, bindings)
+			require.Equal(t, tt.wantClusterRoles, roles)
+			require.Equal(t, tt.wantClusterRoleBindings, roleBindings)
+			require.Equal(t, tt.wantApplied, applied)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetrash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+	tenancyv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/tenancy/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+const (
+	ControllerName = "kcp-workspacetrash"
+
+	restoreBlockedReason = "RestoreBlocked"
+	restoreFailedReason  = "RestoreFailed"
+)
+
+// NewController returns a new controller which soft-deletes Workspaces: deleted ready Workspaces are
+// kept in the Trashed phase for the given retention, during which they can be restored, and are
+// garbage collected afterwards. With zero retention, soft deletion is disabled.
+func NewController(
+	retention time.Duration,
+	kcpClusterClient kcpclientset.ClusterInterface,
+	kcpExternalClient kcpclientset.ClusterInterface,
+	workspaceInformer tenancyv1alpha1informers.WorkspaceClusterInformer,
+	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+	recorder record.EventRecorder,
+) (*controller, error) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName),
+
+		retention: retention,
+		now:       time.Now,
+
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			return workspaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		createWorkspace: func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error) {
+			return kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().Workspaces().Create(ctx, workspace, metav1.CreateOptions{})
+		},
+		updateWorkspace: func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) error {
+			_, err := kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().Workspaces().Update(ctx, workspace, metav1.UpdateOptions{})
+			return err
+		},
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		patchLogicalClusterAnnotation: func(ctx context.Context, clusterName logicalcluster.Path, key string, value *string) error {
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]*string{key: value},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			_, err = kcpClusterClient.Cluster(clusterName).CoreV1alpha1().LogicalClusters().Patch(ctx, corev1alpha1.LogicalClusterName, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		},
+		updateLogicalClusterOwner: func(ctx context.Context, cluster logicalcluster.Path, owner *corev1alpha1.LogicalClusterOwner) error {
+			logicalCluster, err := kcpExternalClient.Cluster(cluster).CoreV1alpha1().LogicalClusters().Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			logicalCluster.Spec.Owner = owner
+			_, err = kcpExternalClient.Cluster(cluster).CoreV1alpha1().LogicalClusters().Update(ctx, logicalCluster, metav1.UpdateOptions{})
+			return err
+		},
+		deleteLogicalCluster: func(ctx context.Context, cluster logicalcluster.Path) error {
+			return kcpExternalClient.Cluster(cluster).CoreV1alpha1().LogicalClusters().Delete(ctx, corev1alpha1.LogicalClusterName, metav1.DeleteOptions{})
+		},
+		commit: committer.NewCommitter[*tenancyv1alpha1.Workspace, tenancyv1alpha1client.WorkspaceInterface, *tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus](kcpClusterClient.TenancyV1alpha1().Workspaces()),
+
+		recorder: recorder,
+	}
+
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueue(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueue(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueue(obj)
+		},
+	})
+
+	logicalClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueuePendingRestores(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueuePendingRestores(obj)
+		},
+	})
+
+	return c, nil
+}
+
+type workspaceResource = committer.Resource[*tenancyv1alpha1.WorkspaceSpec, *tenancyv1alpha1.WorkspaceStatus]
+
+// pendingRestore is a restore in progress, from before the Trashed Workspace is released until
+// the restored Workspace owns the logical cluster. It is recorded in the parent logical cluster,
+// as the Trashed Workspace is gone in between.
+type pendingRestore struct {
+	// OldUID is the UID of the Trashed Workspace.
+	OldUID types.UID `json:"oldUID"`
+	// Until is the end of the retention of the Trashed Workspace. If the restore is blocked until
+	// then, the logical cluster is deleted.
+	Until metav1.Time `json:"until"`
+	// Workspace is the Workspace to create.
+	Workspace *tenancyv1alpha1.Workspace `json:"workspace"`
+}
+
+// controller soft-deletes and restores Workspaces. It is keyed by Workspace.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	retention time.Duration
+	now       func() time.Time
+
+	getWorkspace                  func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error)
+	createWorkspace               func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error)
+	updateWorkspace               func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) error
+	getLogicalCluster             func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	patchLogicalClusterAnnotation func(ctx context.Context, clusterName logicalcluster.Path, key string, value *string) error
+	updateLogicalClusterOwner     func(ctx context.Context, cluster logicalcluster.Path, owner *corev1alpha1.LogicalClusterOwner) error
+	deleteLogicalCluster          func(ctx context.Context, cluster logicalcluster.Path) error
+
+	// commit creates a patch and submits it, if needed.
+	commit func(ctx context.Context, old, new *workspaceResource) error
+
+	recorder record.EventRecorder
+}
+
+func (c *controller) enqueue(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Workspace")
+	c.queue.Add(key)
+}
+
+// enqueuePendingRestores enqueues the Workspaces whose restores are recorded in the LogicalCluster,
+// e.g. to resume them after a restart.
+func (c *controller) enqueuePendingRestores(obj interface{}) {
+	logicalCluster, ok := obj.(*corev1alpha1.LogicalCluster)
+	if !ok {
+		return
+	}
+	for k := range logicalCluster.Annotations {
+		if !strings.HasPrefix(k, tenancyv1alpha1.WorkspaceRestoringAnnotationKeyPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(k, tenancyv1alpha1.WorkspaceRestoringAnnotationKeyPrefix)
+		key := kcpcache.ToClusterAwareKey(logicalcluster.From(logicalCluster).String(), "", name)
+
+		logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+		logger.V(4).Info("queueing Workspace with pending restore")
+		c.queue.Add(key)
+	}
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller", "retention", c.retention)
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.process(ctx, key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("%s: failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "unable to decode key")
+		return 0, nil
+	}
+
+	workspace, err := c.getWorkspace(clusterName, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if apierrors.IsNotFound(err) {
+		workspace = nil
+	}
+
+	pending, err := c.pendingRestore(ctx, clusterName, name)
+	if err != nil {
+		return 0, err
+	}
+	if pending != nil {
+		return c.completeRestore(ctx, clusterName, pending, workspace)
+	}
+	if workspace == nil {
+		return 0, nil // deleted in the meantime
+	}
+
+	logger = logging.WithObject(logger, workspace)
+	ctx = klog.NewContext(ctx, logger)
+
+	if c.restoreRequested(workspace) {
+		return c.restore(ctx, workspace)
+	}
+
+	old := workspace
+	workspace = workspace.DeepCopy()
+
+	requeueAfter := c.reconcile(ctx, workspace)
+
+	oldResource := &workspaceResource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &workspaceResource{ObjectMeta: workspace.ObjectMeta, Spec: &workspace.Spec, Status: &workspace.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		return 0, err
+	}
+
+	return requeueAfter, nil
+}
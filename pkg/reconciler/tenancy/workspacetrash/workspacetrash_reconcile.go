@@ -0,0 +1,306 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetrash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+// reconcile moves deleted Workspaces into and out of the Trashed phase. Metadata and status are
+// changed in separate steps, each change triggers the next reconciliation. It returns when the
+// Workspace must be reconciled again.
+func (c *controller) reconcile(ctx context.Context, workspace *tenancyv1alpha1.Workspace) time.Duration {
+	logger := klog.FromContext(ctx)
+	finalizers := sets.NewString(workspace.Finalizers...)
+
+	if workspace.DeletionTimestamp.IsZero() {
+		switch {
+		case c.retention > 0 && !finalizers.Has(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer):
+			workspace.Finalizers = append(workspace.Finalizers, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer)
+		case c.retention == 0 && finalizers.Has(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer):
+			workspace.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer).List()
+		}
+		return 0
+	}
+
+	if !finalizers.Has(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer) {
+		if workspace.Status.Phase == corev1alpha1.LogicalClusterPhaseTrashed {
+			// garbage collected, let the workspace controller delete the logical cluster.
+			workspace.Status.Phase = corev1alpha1.LogicalClusterPhaseReady
+		}
+		return 0
+	}
+
+	switch workspace.Status.Phase {
+	case corev1alpha1.LogicalClusterPhaseReady:
+		if c.retention == 0 {
+			workspace.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer).List()
+			return 0
+		}
+		until, found := trashedUntil(workspace)
+		if !found {
+			until = workspace.DeletionTimestamp.Add(c.retention)
+			if workspace.Annotations == nil {
+				workspace.Annotations = map[string]string{}
+			}
+			workspace.Annotations[tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey] = until.UTC().Format(time.RFC3339)
+			return 0
+		}
+		logger.Info("moving deleted Workspace to the trash", "until", until)
+		workspace.Status.Phase = corev1alpha1.LogicalClusterPhaseTrashed
+		return until.Sub(c.now())
+
+	case corev1alpha1.LogicalClusterPhaseTrashed:
+		until, found := trashedUntil(workspace)
+		if !found {
+			until = workspace.DeletionTimestamp.Add(c.retention)
+		}
+		if now := c.now(); now.Before(until) {
+			return until.Sub(now)
+		}
+		logger.Info("retention of trashed Workspace has passed, deleting it")
+		workspace.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer).List()
+		delete(workspace.Annotations, tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey)
+		delete(workspace.Annotations, tenancyv1alpha1.WorkspaceRestoreAnnotationKey)
+		return 0
+
+	default:
+		// nothing worth keeping before the workspace has become ready.
+		workspace.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer).List()
+		return 0
+	}
+}
+
+// trashedUntil returns the time until which the Workspace can be restored, if recorded.
+func trashedUntil(workspace *tenancyv1alpha1.Workspace) (time.Time, bool) {
+	value, found := workspace.Annotations[tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey]
+	if !found {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// restoreRequested returns whether the Workspace is Trashed and requested to be restored within
+// the retention period.
+func (c *controller) restoreRequested(workspace *tenancyv1alpha1.Workspace) bool {
+	if workspace.DeletionTimestamp.IsZero() || workspace.Status.Phase != corev1alpha1.LogicalClusterPhaseTrashed {
+		return false
+	}
+	if !sets.NewString(workspace.Finalizers...).Has(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer) {
+		return false
+	}
+	if _, found := workspace.Annotations[tenancyv1alpha1.WorkspaceRestoreAnnotationKey]; !found {
+		return false
+	}
+	until, found := trashedUntil(workspace)
+	if !found {
+		until = workspace.DeletionTimestamp.Add(c.retention)
+	}
+	return c.now().Before(until)
+}
+
+// restore records the restore of the Trashed Workspace in its parent logical cluster, then
+// releases it without deleting its logical cluster, and recreates it.
+func (c *controller) restore(ctx context.Context, workspace *tenancyv1alpha1.Workspace) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	until, found := trashedUntil(workspace)
+	if !found {
+		until = workspace.DeletionTimestamp.Add(c.retention)
+	}
+	pending := &pendingRestore{
+		OldUID:    workspace.UID,
+		Until:     metav1.NewTime(until),
+		Workspace: restoredWorkspace(workspace),
+	}
+
+	// once released, nothing but this record points to the logical cluster anymore. Hence it must
+	// be persisted first.
+	parent := logicalcluster.From(workspace)
+	if err := c.setPendingRestore(ctx, parent.Path(), workspace.Name, pending); err != nil {
+		return 0, err
+	}
+	logger.Info("restoring Workspace", "cluster", workspace.Spec.Cluster)
+
+	return c.completeRestore(ctx, parent, pending, workspace)
+}
+
+// completeRestore releases the Trashed Workspace, i.e. existing with the old UID, creates the
+// restored Workspace once the Trashed one is gone, and hands the logical cluster over to it.
+// Each step is triggered by the outcome of the previous one.
+func (c *controller) completeRestore(ctx context.Context, parent logicalcluster.Name, pending *pendingRestore, existing *tenancyv1alpha1.Workspace) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	var restored *tenancyv1alpha1.Workspace
+	switch {
+	case existing != nil && existing.UID == pending.OldUID:
+		// without both finalizers, the Workspace is deleted, but not its logical cluster.
+		finalizers := sets.NewString(existing.Finalizers...)
+		if !finalizers.HasAny(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer, corev1alpha1.LogicalClusterFinalizer) {
+			return 0, nil // waiting for it to be gone
+		}
+		released := existing.DeepCopy()
+		released.Finalizers = finalizers.Delete(tenancyv1alpha1.WorkspaceSoftDeletionFinalizer, corev1alpha1.LogicalClusterFinalizer).List()
+		return 0, c.updateWorkspace(ctx, parent.Path(), released)
+
+	case existing == nil:
+		ws := pending.Workspace.DeepCopy()
+		delete(ws.Annotations, logicalcluster.AnnotationKey)
+		created, err := c.createWorkspace(ctx, parent.Path(), ws)
+		if apierrors.IsAlreadyExists(err) {
+			// the Trashed Workspace is not gone yet, or our informer has not seen the restored one.
+			return 0, fmt.Errorf("waiting to create restored Workspace: %w", err)
+		} else if err != nil {
+			return 0, err
+		}
+		restored = created
+
+	case existing.Spec.Cluster == pending.Workspace.Spec.Cluster:
+		restored = existing
+
+	default:
+		return c.restoreBlocked(ctx, parent, pending, existing)
+	}
+
+	if err := c.updateLogicalClusterOwner(ctx, logicalcluster.NewPath(restored.Spec.Cluster), &corev1alpha1.LogicalClusterOwner{
+		APIVersion: tenancyv1alpha1.SchemeGroupVersion.String(),
+		Resource:   "workspaces",
+		Name:       restored.Name,
+		Cluster:    parent.String(),
+		UID:        restored.UID,
+	}); err != nil {
+		return 0, err
+	}
+	if err := c.setPendingRestore(ctx, parent.Path(), restored.Name, nil); err != nil {
+		return 0, err
+	}
+
+	logger.Info("restored Workspace", "cluster", restored.Spec.Cluster, "uid", restored.UID)
+	return 0, nil
+}
+
+// restoreBlocked handles another Workspace having taken the name of the Workspace to restore.
+// Until the retention of the Trashed Workspace has passed, the restore continues as soon as the
+// name is free again. Afterwards, the logical cluster is deleted as it would have been without
+// the restore.
+func (c *controller) restoreBlocked(ctx context.Context, parent logicalcluster.Name, pending *pendingRestore, existing *tenancyv1alpha1.Workspace) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	if now := c.now(); now.Before(pending.Until.Time) {
+		logger.Info("cannot restore Workspace, the name is taken", "cluster", pending.Workspace.Spec.Cluster, "until", pending.Until)
+		c.recorder.Eventf(existing, corev1.EventTypeWarning, restoreBlockedReason,
+			"Workspace %s cannot be restored into logical cluster %s while this Workspace has its name. Its logical cluster is deleted at %s.",
+			existing.Name, pending.Workspace.Spec.Cluster, pending.Until.UTC().Format(time.RFC3339))
+		return pending.Until.Sub(now), nil
+	}
+
+	logger.Info("retention of the Workspace to restore has passed, deleting its logical cluster", "cluster", pending.Workspace.Spec.Cluster)
+	if err := c.deleteLogicalCluster(ctx, logicalcluster.NewPath(pending.Workspace.Spec.Cluster)); err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	c.recorder.Eventf(existing, corev1.EventTypeWarning, restoreFailedReason,
+		"Workspace %s has not been restored because this Workspace has its name. Its logical cluster %s is deleted.",
+		existing.Name, pending.Workspace.Spec.Cluster)
+	return 0, c.setPendingRestore(ctx, parent.Path(), existing.Name, nil)
+}
+
+// pendingRestore returns the restore in progress of the named Workspace in the given logical
+// cluster, or nil.
+func (c *controller) pendingRestore(ctx context.Context, clusterName logicalcluster.Name, name string) (*pendingRestore, error) {
+	logicalCluster, err := c.getLogicalCluster(clusterName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	value, found := logicalCluster.Annotations[tenancyv1alpha1.WorkspaceRestoringAnnotationKeyPrefix+name]
+	if !found {
+		return nil, nil
+	}
+	var pending pendingRestore
+	if err := json.Unmarshal([]byte(value), &pending); err != nil || pending.Workspace == nil {
+		// nothing we can do about it, the logical cluster is left as is.
+		klog.FromContext(ctx).Error(err, "invalid pending Workspace restore", "cluster", clusterName, "name", name)
+		return nil, nil
+	}
+	return &pending, nil
+}
+
+// setPendingRestore records the restore in progress of the named Workspace in the given logical
+// cluster, or removes the record if pending is nil.
+func (c *controller) setPendingRestore(ctx context.Context, clusterName logicalcluster.Path, name string, pending *pendingRestore) error {
+	var value *string
+	if pending != nil {
+		bs, err := json.Marshal(pending)
+		if err != nil {
+			return err
+		}
+		value = pointer.String(string(bs))
+	}
+	return c.patchLogicalClusterAnnotation(ctx, clusterName, tenancyv1alpha1.WorkspaceRestoringAnnotationKeyPrefix+name, value)
+}
+
+// restoredWorkspace returns the Workspace replacing the given Trashed one, bound to the same logical cluster.
+func restoredWorkspace(workspace *tenancyv1alpha1.Workspace) *tenancyv1alpha1.Workspace {
+	restored := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workspace.Name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+			Finalizers: []string{
+				corev1alpha1.LogicalClusterFinalizer,
+				tenancyv1alpha1.WorkspaceSoftDeletionFinalizer,
+			},
+		},
+		Spec: *workspace.Spec.DeepCopy(),
+	}
+	for k, v := range workspace.Labels {
+		if k == tenancyv1alpha1.WorkspacePhaseLabel {
+			continue
+		}
+		restored.Labels[k] = v
+	}
+	for k, v := range workspace.Annotations {
+		switch k {
+		case tenancyv1alpha1.WorkspaceRestoreAnnotationKey,
+			tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey,
+			tenancyv1alpha1.WorkspaceLifecycleWebhookEventsAnnotationKey:
+			continue
+		}
+		restored.Annotations[k] = v
+	}
+	return restored
+}
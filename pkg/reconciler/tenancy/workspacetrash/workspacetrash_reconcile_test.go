@@ -0,0 +1,338 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetrash
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	t0 := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	deleted := &metav1.Time{Time: t0}
+	until := t0.Add(time.Hour).Format(time.RFC3339)
+
+	workspace := func(deletion *metav1.Time, phase corev1alpha1.LogicalClusterPhaseType, annotations map[string]string, finalizers ...string) *tenancyv1alpha1.Workspace {
+		return &tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "ws",
+				DeletionTimestamp: deletion,
+				Annotations:       annotations,
+				Finalizers:        finalizers,
+			},
+			Status: tenancyv1alpha1.WorkspaceStatus{Phase: phase},
+		}
+	}
+
+	tests := map[string]struct {
+		retention time.Duration
+		now       time.Time
+		workspace *tenancyv1alpha1.Workspace
+
+		want             *tenancyv1alpha1.Workspace
+		wantRequeueAfter time.Duration
+	}{
+		"adds finalizer": {
+			retention: time.Hour,
+			workspace: workspace(nil, corev1alpha1.LogicalClusterPhaseInitializing, nil, corev1alpha1.LogicalClusterFinalizer),
+			want:      workspace(nil, corev1alpha1.LogicalClusterPhaseInitializing, nil, corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+		},
+		"removes finalizer when disabled": {
+			workspace: workspace(nil, corev1alpha1.LogicalClusterPhaseReady, nil, corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:      workspace(nil, corev1alpha1.LogicalClusterPhaseReady, nil, corev1alpha1.LogicalClusterFinalizer),
+		},
+		"deleted ready workspace records retention": {
+			retention: time.Hour,
+			now:       t0,
+			workspace: workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, nil, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:      workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until}, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+		},
+		"deleted ready workspace is trashed": {
+			retention:        time.Hour,
+			now:              t0.Add(time.Minute),
+			workspace:        workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until}, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:             workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until}, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			wantRequeueAfter: 59 * time.Minute,
+		},
+		"deleted ready workspace is deleted when disabled": {
+			workspace: workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, nil, corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:      workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, nil, corev1alpha1.LogicalClusterFinalizer),
+		},
+		"deleted initializing workspace is not trashed": {
+			retention: time.Hour,
+			workspace: workspace(deleted, corev1alpha1.LogicalClusterPhaseInitializing, nil, corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:      workspace(deleted, corev1alpha1.LogicalClusterPhaseInitializing, nil, corev1alpha1.LogicalClusterFinalizer),
+		},
+		"trashed workspace within retention": {
+			now:              t0.Add(30 * time.Minute),
+			workspace:        workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until}, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:             workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until}, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			wantRequeueAfter: 30 * time.Minute,
+		},
+		"trashed workspace is garbage collected after retention": {
+			now:       t0.Add(time.Hour),
+			workspace: workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, map[string]string{tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey: until, "a": "b"}, corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer),
+			want:      workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, map[string]string{"a": "b"}, corev1alpha1.LogicalClusterFinalizer),
+		},
+		"garbage collected workspace is handed back to the workspace controller": {
+			now:       t0.Add(time.Hour),
+			workspace: workspace(deleted, corev1alpha1.LogicalClusterPhaseTrashed, nil, corev1alpha1.LogicalClusterFinalizer),
+			want:      workspace(deleted, corev1alpha1.LogicalClusterPhaseReady, nil, corev1alpha1.LogicalClusterFinalizer),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				retention: tt.retention,
+				now:       func() time.Time { return tt.now },
+			}
+			requeueAfter := c.reconcile(context.Background(), tt.workspace)
+			require.Equal(t, tt.want, tt.workspace)
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t0 := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	trashed := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ws",
+			UID:  "old",
+			Labels: map[string]string{
+				tenancyv1alpha1.WorkspacePhaseLabel: "Trashed",
+				"a":                                 "b",
+			},
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:                            "parent",
+				tenancyv1alpha1.WorkspaceTrashedUntilAnnotationKey:      t0.Add(time.Hour).Format(time.RFC3339),
+				tenancyv1alpha1.WorkspaceRestoreAnnotationKey:           "true",
+				tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey: `{"username":"user-1"}`,
+			},
+			DeletionTimestamp: &metav1.Time{Time: t0},
+			Finalizers:        []string{corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer},
+		},
+		Spec: tenancyv1alpha1.WorkspaceSpec{
+			Type:    tenancyv1alpha1.WorkspaceTypeReference{Name: "universal", Path: "root"},
+			Cluster: "child",
+			URL:     "https://shard/clusters/child",
+		},
+		Status: tenancyv1alpha1.WorkspaceStatus{Phase: corev1alpha1.LogicalClusterPhaseTrashed},
+	}
+
+	type fakes struct {
+		cached      *tenancyv1alpha1.Workspace
+		created     *tenancyv1alpha1.Workspace
+		released    *tenancyv1alpha1.Workspace
+		owner       *corev1alpha1.LogicalClusterOwner
+		deleted     string
+		annotations map[string]string
+		createErr   error
+	}
+	newController := func(f *fakes) (*controller, *record.FakeRecorder) {
+		if f.annotations == nil {
+			f.annotations = map[string]string{}
+		}
+		recorder := record.NewFakeRecorder(10)
+		return &controller{
+			now: func() time.Time { return t0.Add(time.Minute) },
+			getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+				if f.cached == nil {
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspaces"), name)
+				}
+				return f.cached, nil
+			},
+			createWorkspace: func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) (*tenancyv1alpha1.Workspace, error) {
+				require.Equal(t, "parent", clusterName.String())
+				if f.createErr != nil {
+					return nil, f.createErr
+				}
+				f.created = workspace.DeepCopy()
+				f.created.UID = "new"
+				return f.created, nil
+			},
+			updateWorkspace: func(ctx context.Context, clusterName logicalcluster.Path, workspace *tenancyv1alpha1.Workspace) error {
+				f.released = workspace
+				return nil
+			},
+			getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+				require.Equal(t, "parent", clusterName.String())
+				annotations := map[string]string{}
+				for k, v := range f.annotations {
+					annotations[k] = v
+				}
+				return &corev1alpha1.LogicalCluster{ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: annotations}}, nil
+			},
+			patchLogicalClusterAnnotation: func(ctx context.Context, clusterName logicalcluster.Path, key string, value *string) error {
+				require.Equal(t, "parent", clusterName.String())
+				if value == nil {
+					delete(f.annotations, key)
+				} else {
+					f.annotations[key] = *value
+				}
+				return nil
+			},
+			updateLogicalClusterOwner: func(ctx context.Context, cluster logicalcluster.Path, owner *corev1alpha1.LogicalClusterOwner) error {
+				require.Equal(t, "child", cluster.String())
+				f.owner = owner
+				return nil
+			},
+			deleteLogicalCluster: func(ctx context.Context, cluster logicalcluster.Path) error {
+				f.deleted = cluster.String()
+				return nil
+			},
+			recorder: recorder,
+		}, recorder
+	}
+	wantCreated := &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "ws",
+			UID:    "new",
+			Labels: map[string]string{"a": "b"},
+			Annotations: map[string]string{
+				tenancyv1alpha1.ExperimentalWorkspaceOwnerAnnotationKey: `{"username":"user-1"}`,
+			},
+			Finalizers: []string{corev1alpha1.LogicalClusterFinalizer, tenancyv1alpha1.WorkspaceSoftDeletionFinalizer},
+		},
+		Spec: trashed.Spec,
+	}
+	wantOwner := &corev1alpha1.LogicalClusterOwner{
+		APIVersion: "tenancy.kcp.io/v1alpha1",
+		Resource:   "workspaces",
+		Name:       "ws",
+		Cluster:    "parent",
+		UID:        "new",
+	}
+	pendingKey := tenancyv1alpha1.WorkspaceRestoringAnnotationKeyPrefix + "ws"
+	pending := func() map[string]string {
+		bs, err := json.Marshal(&pendingRestore{OldUID: "old", Until: metav1.NewTime(t0.Add(time.Hour)), Workspace: restoredWorkspace(trashed)})
+		require.NoError(t, err)
+		return map[string]string{pendingKey: string(bs)}
+	}
+
+	t.Run("records the restore before releasing the trashed workspace", func(t *testing.T) {
+		f := &fakes{cached: trashed}
+		c, _ := newController(f)
+
+		_, err := c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Empty(t, f.released.Finalizers)
+		require.Equal(t, pending(), f.annotations)
+		require.Nil(t, f.created, "the trashed workspace is not gone yet")
+
+		t.Log("A restarted controller completes the restore once the trashed workspace is gone")
+		f.cached = nil
+		c, _ = newController(f)
+		_, err = c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Equal(t, wantCreated, f.created)
+		require.Equal(t, wantOwner, f.owner)
+		require.Empty(t, f.annotations)
+	})
+
+	t.Run("waits for the released workspace to be gone", func(t *testing.T) {
+		released := trashed.DeepCopy()
+		released.Finalizers = nil
+		f := &fakes{cached: released, annotations: pending()}
+		c, _ := newController(f)
+
+		_, err := c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Nil(t, f.released)
+		require.Nil(t, f.created)
+		require.Equal(t, pending(), f.annotations)
+	})
+
+	t.Run("retries creation when the trashed workspace is not gone yet", func(t *testing.T) {
+		f := &fakes{annotations: pending(), createErr: apierrors.NewAlreadyExists(tenancyv1alpha1.Resource("workspaces"), "ws")}
+		c, _ := newController(f)
+
+		_, err := c.process(context.Background(), "parent|ws")
+		require.Error(t, err)
+		require.Nil(t, f.owner)
+		require.Equal(t, pending(), f.annotations)
+
+		f.createErr = nil
+		_, err = c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Equal(t, wantCreated, f.created)
+		require.Equal(t, wantOwner, f.owner)
+		require.Empty(t, f.annotations)
+	})
+
+	t.Run("hands over the logical cluster to an already created workspace", func(t *testing.T) {
+		f := &fakes{cached: wantCreated, annotations: pending()}
+		c, _ := newController(f)
+
+		_, err := c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Nil(t, f.created)
+		require.Equal(t, wantOwner, f.owner)
+		require.Empty(t, f.annotations)
+	})
+
+	t.Run("reports a taken name and deletes the logical cluster after retention", func(t *testing.T) {
+		other := &tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", UID: "other"},
+			Spec:       tenancyv1alpha1.WorkspaceSpec{Cluster: "other"},
+		}
+		f := &fakes{cached: other, annotations: pending()}
+		c, recorder := newController(f)
+
+		requeueAfter, err := c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Equal(t, 59*time.Minute, requeueAfter)
+		require.Contains(t, <-recorder.Events, restoreBlockedReason)
+		require.Nil(t, f.created)
+		require.Nil(t, f.owner)
+		require.Empty(t, f.deleted)
+		require.Equal(t, pending(), f.annotations)
+
+		c.now = func() time.Time { return t0.Add(2 * time.Hour) }
+		_, err = c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Contains(t, <-recorder.Events, restoreFailedReason)
+		require.Equal(t, "child", f.deleted)
+		require.Nil(t, f.owner)
+		require.Empty(t, f.annotations)
+	})
+
+	t.Run("ignores restore requests after retention", func(t *testing.T) {
+		f := &fakes{cached: trashed}
+		c, _ := newController(f)
+		c.now = func() time.Time { return t0.Add(2 * time.Hour) }
+		c.commit = func(ctx context.Context, old, new *workspaceResource) error { return nil }
+
+		_, err := c.process(context.Background(), "parent|ws")
+		require.NoError(t, err)
+		require.Nil(t, f.released)
+		require.Nil(t, f.created)
+		require.Empty(t, f.annotations)
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides helpers to unit test controllers without wall-clock sleeps.
+package testing
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+)
+
+// Queue is a deterministic workqueue.RateLimitingInterface for tests. Items added with a delay,
+// directly or through the rate limiter, become ready when the clock has been stepped past their
+// delay, without any background goroutine. The queue records what controllers add to it, so
+// tests can assert requeues and their backoff.
+//
+// Get blocks like the workqueue does. Tests should check Len before calling Get.
+type Queue struct {
+	queue       workqueue.Interface
+	clock       clock.PassiveClock
+	rateLimiter workqueue.RateLimiter
+
+	lock     sync.Mutex
+	waiting  map[interface{}]time.Time
+	added    []interface{}
+	requeues map[interface{}][]time.Duration
+}
+
+var _ workqueue.RateLimitingInterface = &Queue{}
+
+// NewQueue returns a queue using the given clock, e.g. a k8s.io/utils/clock/testing.FakeClock,
+// and the exponential per-item backoff of the default controller rate limiter.
+func NewQueue(clock clock.PassiveClock) *Queue {
+	return NewQueueWithRateLimiter(clock, workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second))
+}
+
+// NewQueueWithRateLimiter returns a queue using the given clock and rate limiter.
+func NewQueueWithRateLimiter(clock clock.PassiveClock, rateLimiter workqueue.RateLimiter) *Queue {
+	return &Queue{
+		queue:       workqueue.New(),
+		clock:       clock,
+		rateLimiter: rateLimiter,
+		waiting:     map[interface{}]time.Time{},
+		requeues:    map[interface{}][]time.Duration{},
+	}
+}
+
+// Add marks item as needing processing.
+func (q *Queue) Add(item interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.added = append(q.added, item)
+	q.queue.Add(item)
+}
+
+// Len promotes the items whose delay has passed and returns the number of ready items.
+func (q *Queue) Len() int {
+	q.promote()
+	return q.queue.Len()
+}
+
+// Get promotes the items whose delay has passed and blocks until an item is ready.
+func (q *Queue) Get() (interface{}, bool) {
+	q.promote()
+	return q.queue.Get()
+}
+
+// Done marks item as done processing.
+func (q *Queue) Done(item interface{}) {
+	q.queue.Done(item)
+}
+
+// ShutDown shuts down the queue.
+func (q *Queue) ShutDown() {
+	q.queue.ShutDown()
+}
+
+// ShutDownWithDrain shuts down the queue after all items have been processed.
+func (q *Queue) ShutDownWithDrain() {
+	q.queue.ShutDownWithDrain()
+}
+
+// ShuttingDown returns whether the queue is shutting down.
+func (q *Queue) ShuttingDown() bool {
+	return q.queue.ShuttingDown()
+}
+
+// AddAfter adds item when the clock has been stepped by duration. If item is waiting already,
+// the earlier time wins.
+func (q *Queue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	readyAt := q.clock.Now().Add(duration)
+	if existing, ok := q.waiting[item]; !ok || readyAt.Before(existing) {
+		q.waiting[item] = readyAt
+	}
+}
+
+// AddRateLimited adds item after the delay of the rate limiter and records the delay.
+func (q *Queue) AddRateLimited(item interface{}) {
+	delay := q.rateLimiter.When(item)
+
+	q.lock.Lock()
+	q.requeues[item] = append(q.requeues[item], delay)
+	q.lock.Unlock()
+
+	q.AddAfter(item, delay)
+}
+
+// Forget resets the backoff of item in the rate limiter.
+func (q *Queue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+// NumRequeues returns the number of rate limited requeues of item since it was forgotten.
+func (q *Queue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// Added returns the items that were added without a delay, in order.
+func (q *Queue) Added() []interface{} {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return append([]interface{}(nil), q.added...)
+}
+
+// Requeues returns the delays of all rate limited requeues of item, in order. Unlike
+// NumRequeues, the history is kept when item is forgotten.
+func (q *Queue) Requeues(item interface{}) []time.Duration {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return append([]time.Duration(nil), q.requeues[item]...)
+}
+
+// Waiting returns the remaining delay of item, and false if item is not waiting.
+func (q *Queue) Waiting(item interface{}) (time.Duration, bool) {
+	q.promote()
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	readyAt, ok := q.waiting[item]
+	if !ok {
+		return 0, false
+	}
+	return readyAt.Sub(q.clock.Now()), true
+}
+
+// promote adds the waiting items whose time has come, earliest first.
+func (q *Queue) promote() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.clock.Now()
+	var ready []interface{}
+	for item, readyAt := range q.waiting {
+		if !readyAt.After(now) {
+			ready = append(ready, item)
+		}
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return q.waiting[ready[i]].Before(q.waiting[ready[j]])
+	})
+	for _, item := range ready {
+		delete(q.waiting, item)
+		q.queue.Add(item)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestQueue(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC))
+	q := NewQueue(clock)
+
+	q.Add("a")
+	q.AddAfter("b", 2*time.Second)
+	q.AddAfter("c", time.Second)
+	q.AddAfter("b", 3*time.Second)
+	require.Equal(t, 1, q.Len())
+	require.Equal(t, []interface{}{"a"}, q.Added())
+
+	delay, ok := q.Waiting("b")
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, delay, "the earlier delay should win")
+
+	item, _ := q.Get()
+	require.Equal(t, "a", item)
+	q.Done(item)
+
+	clock.Step(2 * time.Second)
+	require.Equal(t, 2, q.Len())
+	item, _ = q.Get()
+	require.Equal(t, "c", item, "items should become ready in the order of their delays")
+	q.Done(item)
+	item, _ = q.Get()
+	require.Equal(t, "b", item)
+	q.Done(item)
+	_, ok = q.Waiting("b")
+	require.False(t, ok)
+
+	q.AddRateLimited("d")
+	q.AddRateLimited("d")
+	require.Equal(t, 2, q.NumRequeues("d"))
+	require.Equal(t, []time.Duration{5 * time.Millisecond, 10 * time.Millisecond}, q.Requeues("d"))
+	q.Forget("d")
+	require.Equal(t, 0, q.NumRequeues("d"))
+	require.Len(t, q.Requeues("d"), 2)
+}
@@ -34,6 +34,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 	workloadv1alpha1client "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/typed/workload/v1alpha1"
@@ -48,6 +49,7 @@ type Controller struct {
 	heartbeatThreshold time.Duration
 	commit             CommitFunc
 	getSyncTarget      func(clusterName logicalcluster.Name, name string) (*workloadv1alpha1.SyncTarget, error)
+	now                func() time.Time
 }
 
 func NewController(
@@ -55,12 +57,13 @@ func NewController(
 	syncTargetInformer workloadv1alpha1informers.SyncTargetClusterInformer,
 	heartbeatThreshold time.Duration,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &Controller{
 		queue:              queue,
 		kcpClusterClient:   kcpClusterClient,
 		heartbeatThreshold: heartbeatThreshold,
+		now:                time.Now,
 		commit:             committer.NewCommitter[*SyncTarget, Patcher, *SyncTargetSpec, *SyncTargetStatus](kcpClusterClient.WorkloadV1alpha1().SyncTargets()),
 		getSyncTarget: func(clusterName logicalcluster.Name, name string) (*workloadv1alpha1.SyncTarget, error) {
 			return syncTargetInformer.Cluster(clusterName).Lister().Get(name)
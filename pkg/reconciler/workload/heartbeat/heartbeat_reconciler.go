@@ -49,7 +49,7 @@ func (c *Controller) reconcile(ctx context.Context, key string, cluster *workloa
 			workloadv1alpha1.ErrorHeartbeatMissedReason,
 			conditionsv1alpha1.ConditionSeverityWarning,
 			"No heartbeat yet seen")
-	} else if time.Since(latestHeartbeat) > c.heartbeatThreshold {
+	} else if c.now().Sub(latestHeartbeat) > c.heartbeatThreshold {
 		logger.V(5).Info("marking HeartbeatHealthy false for SyncTarget due to a stale heartbeat")
 		conditions.MarkFalse(cluster,
 			workloadv1alpha1.HeartbeatHealthy,
@@ -61,7 +61,7 @@ func (c *Controller) reconcile(ctx context.Context, key string, cluster *workloa
 		conditions.MarkTrue(cluster, workloadv1alpha1.HeartbeatHealthy)
 
 		// Enqueue another check after which the heartbeat should have been updated again.
-		dur := time.Until(latestHeartbeat.Add(c.heartbeatThreshold))
+		dur := latestHeartbeat.Add(c.heartbeatThreshold).Sub(c.now())
 		c.queue.AddAfter(key, dur)
 	}
 
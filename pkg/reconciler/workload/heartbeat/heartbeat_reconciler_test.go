@@ -23,24 +23,15 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
 
+	reconcilertesting "github.com/kcp-dev/kcp/pkg/reconciler/testing"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 )
 
-type fakeDelayingQueue struct {
-	workqueue.RateLimitingInterface
-	duration time.Duration
-}
-
-var _ workqueue.DelayingInterface = (*fakeDelayingQueue)(nil)
-
-func (f *fakeDelayingQueue) AddAfter(obj interface{}, duration time.Duration) {
-	f.duration = duration
-}
-
 func TestReconcile(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
 	for _, tc := range []struct {
 		desc              string
 		lastHeartbeatTime time.Time
@@ -51,21 +42,21 @@ func TestReconcile(t *testing.T) {
 		wantReady: false,
 	}, {
 		desc:              "recent enough heartbeat",
-		lastHeartbeatTime: time.Now().Add(-10 * time.Second),
+		lastHeartbeatTime: now.Add(-10 * time.Second),
 		wantDur:           50 * time.Second,
 		wantReady:         true,
 	}, {
 		desc:              "not recent enough heartbeat",
-		lastHeartbeatTime: time.Now().Add(-90 * time.Second),
+		lastHeartbeatTime: now.Add(-90 * time.Second),
 		wantReady:         false,
 	}} {
 		t.Run(tc.desc, func(t *testing.T) {
-			queue := &fakeDelayingQueue{
-				RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "testing"),
-			}
+			clock := clocktesting.NewFakeClock(now)
+			queue := reconcilertesting.NewQueue(clock)
 			c := &Controller{
 				queue:              queue,
 				heartbeatThreshold: time.Minute,
+				now:                clock.Now,
 			}
 			ctx := context.Background()
 			heartbeat := metav1.NewTime(tc.lastHeartbeatTime)
@@ -82,10 +73,9 @@ func TestReconcile(t *testing.T) {
 				t.Fatalf("reconcile: %v", err)
 			}
 
-			// actual enqueued time must not be more than 30ms off from desired enqueue time.
-			delta := 30 * time.Millisecond
-			if tc.wantDur-delta > queue.duration {
-				t.Errorf("next enqueue time; got %s, want %s", queue.duration, tc.wantDur)
+			dur, _ := queue.Waiting("somekey")
+			if dur != tc.wantDur {
+				t.Errorf("next enqueue time; got %s, want %s", dur, tc.wantDur)
 			}
 			isReady := syncTarget.GetConditions()[0].Status == corev1.ConditionTrue
 			if isReady != tc.wantReady {
@@ -38,6 +38,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
@@ -57,6 +58,7 @@ import (
 const (
 	ControllerName         = "kcp-workload-placement"
 	bySelectedLocationPath = ControllerName + "-bySelectedLocationPath"
+	byScheduledSyncTarget  = ControllerName + "-byScheduledSyncTarget"
 )
 
 // NewController returns a new controller starting the process of selecting synctarget for a placement.
@@ -65,10 +67,11 @@ func NewController(
 	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
 	locationInformer, globalLocationInformer schedulinginformers.LocationClusterInformer,
 	syncTargetInformer, globalSyncTargetInformer workloadinformers.SyncTargetClusterInformer,
+	syncTargetPoolInformer, globalSyncTargetPoolInformer workloadinformers.SyncTargetPoolClusterInformer,
 	placementInformer schedulinginformers.PlacementClusterInformer,
 	apiBindingInformer apisinformers.APIBindingClusterInformer,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), ControllerName)
 
 	c := &controller{
 		queue: queue,
@@ -99,6 +102,14 @@ func NewController(
 			return targets, nil
 		},
 
+		listSyncTargetPools: func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error) {
+			pools, err := syncTargetPoolInformer.Lister().Cluster(clusterName).List(labels.Everything())
+			if err != nil || len(pools) == 0 {
+				return globalSyncTargetPoolInformer.Lister().Cluster(clusterName).List(labels.Everything())
+			}
+			return pools, nil
+		},
+
 		getLocation: func(path logicalcluster.Path, name string) (*schedulingv1alpha1.Location, error) {
 			return indexers.ByPathAndNameWithFallback[*schedulingv1alpha1.Location](schedulingv1alpha1.Resource("locations"), locationInformer.Informer().GetIndexer(), globalLocationInformer.Informer().GetIndexer(), path, name)
 		},
@@ -113,6 +124,7 @@ func NewController(
 
 	if err := placementInformer.Informer().AddIndexers(cache.Indexers{
 		bySelectedLocationPath: indexBySelectedLocationPath,
+		byScheduledSyncTarget:  indexByScheduledSyncTarget,
 	}); err != nil {
 		return nil, err
 	}
@@ -166,6 +178,20 @@ func NewController(
 		},
 	)
 
+	syncTargetPoolInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { c.enqueueSyncTargetPool(obj, logger) },
+			UpdateFunc: func(old, obj interface{}) {
+				oldPool := old.(*workloadv1alpha1.SyncTargetPool)
+				newPool := obj.(*workloadv1alpha1.SyncTargetPool)
+				if !reflect.DeepEqual(oldPool.Spec, newPool.Spec) || !reflect.DeepEqual(oldPool.Labels, newPool.Labels) {
+					c.enqueueSyncTargetPool(obj, logger)
+				}
+			},
+			DeleteFunc: func(obj interface{}) { c.enqueueSyncTargetPool(obj, logger) },
+		},
+	)
+
 	placementInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { c.enqueuePlacement(obj, logger) },
 		UpdateFunc: func(_, obj interface{}) { c.enqueuePlacement(obj, logger) },
@@ -204,7 +230,8 @@ type controller struct {
 
 	syncTargetLister workloadv1alpha1listers.SyncTargetClusterLister
 
-	listSyncTargets func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error)
+	listSyncTargets     func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error)
+	listSyncTargetPools func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error)
 
 	placementLister  schedulingv1alpha1listers.PlacementClusterLister
 	placementIndexer cache.Indexer
@@ -308,6 +335,31 @@ func (c *controller) enqueueSyncTarget(obj interface{}, logger logr.Logger) {
 	}
 }
 
+func (c *controller) enqueueSyncTargetPool(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	pool, ok := obj.(*workloadv1alpha1.SyncTargetPool)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type: %T", obj))
+		return
+	}
+
+	// Get all locations in the same cluster and enqueue locations.
+	locations, err := c.listLocations(logicalcluster.From(pool))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger = logger.WithValues(logging.FromPrefix("syncTargetPoolReason", pool)...)
+
+	for _, location := range locations {
+		c.enqueueLocation(location, logger)
+	}
+}
+
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
 )
 
 func indexBySelectedLocationPath(obj interface{}) ([]string, error) {
@@ -34,3 +35,17 @@ func indexBySelectedLocationPath(obj interface{}) ([]string, error) {
 
 	return []string{placement.Status.SelectedLocation.Path}, nil
 }
+
+func indexByScheduledSyncTarget(obj interface{}) ([]string, error) {
+	placement, ok := obj.(*schedulingv1alpha1.Placement)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a Placement, but is %T", obj)
+	}
+
+	syncTargetKey, found := placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]
+	if !found {
+		return []string{}, nil
+	}
+
+	return []string{syncTargetKey}, nil
+}
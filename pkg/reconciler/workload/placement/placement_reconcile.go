@@ -47,6 +47,8 @@ func (c *controller) reconcile(ctx context.Context, placement *schedulingv1alpha
 	reconcilers := []reconciler{
 		&placementSchedulingReconciler{
 			listSyncTargets:         c.listSyncTargets,
+			listSyncTargetPools:     c.listSyncTargetPools,
+			countPlacements:         c.countPlacements,
 			getLocation:             c.getLocation,
 			patchPlacement:          c.patchPlacement,
 			listWorkloadAPIBindings: c.listWorkloadAPIBindings,
@@ -78,6 +80,15 @@ func (c *controller) patchPlacement(ctx context.Context, clusterName logicalclus
 	return c.kcpClusterClient.Cluster(clusterName).SchedulingV1alpha1().Placements().Patch(ctx, name, pt, data, opts, subresources...)
 }
 
+// countPlacements returns the number of placements scheduled onto the SyncTarget with the given key.
+func (c *controller) countPlacements(syncTargetKey string) (int, error) {
+	placements, err := c.placementIndexer.ByIndex(byScheduledSyncTarget, syncTargetKey)
+	if err != nil {
+		return 0, err
+	}
+	return len(placements), nil
+}
+
 // listWorkloadAPIBindings list all compute apibindings.
 func (c *controller) listWorkloadAPIBindings(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
 	apiBindings, err := c.apiBindingLister.Cluster(clusterName).List(labels.Everything())
@@ -21,11 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -42,8 +45,13 @@ import (
 // placementSchedulingReconciler schedules placments according to the selected locations.
 // It considers only valid SyncTargets and updates the internal.workload.kcp.io/synctarget
 // annotation with the selected one on the placement object.
+//
+// Placements of a Location of SyncTargetPools are scheduled onto the member with the least
+// placements, such that the members of a pool are evenly loaded.
 type placementSchedulingReconciler struct {
 	listSyncTargets         func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error)
+	listSyncTargetPools     func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error)
+	countPlacements         func(syncTargetKey string) (int, error)
 	listWorkloadAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
 	getLocation             func(path logicalcluster.Path, name string) (*schedulingv1alpha1.Location, error)
 	patchPlacement          func(ctx context.Context, clusterName logicalcluster.Path, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*schedulingv1alpha1.Placement, error)
@@ -57,7 +65,7 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, placement
 	currentScheduled, foundScheduled := placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]
 
 	// 2. pick all valid synctargets in this placements
-	validSyncTargets, reason, message, err := r.getAllValidSyncTargetsForPlacement(ctx, placement)
+	validSyncTargets, pooled, reason, message, err := r.getAllValidSyncTargetsForPlacement(ctx, placement)
 	if err != nil {
 		return reconcileStatusStopAndRequeue, placement, err
 	}
@@ -85,19 +93,26 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, placement
 		}
 	}
 
-	// 3. randomly select one as the scheduled cluster
+	// 3. randomly select one as the scheduled cluster, or the least loaded member of a pool
 	// TODO(qiujian16): we currently schedule each in each location independently. It cannot guarantee 1 cluster is scheduled per location
 	// when the same synctargets are in multiple locations, we need to rethink whether we need a better algorithm or we need location
 	// to be exclusive.
 	scheduledSyncTarget := validSyncTargets[rand.Intn(len(validSyncTargets))]
+	if pooled {
+		if scheduledSyncTarget, err = r.leastLoadedSyncTarget(validSyncTargets); err != nil {
+			return reconcileStatusStopAndRequeue, placement, err
+		}
+	}
 	expectedAnnotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey] = workloadv1alpha1.ToSyncTargetKey(logicalcluster.From(scheduledSyncTarget), scheduledSyncTarget.Name)
 	updated, err := r.patchPlacementAnnotation(ctx, clusterName.Path(), placement, expectedAnnotations)
 	return reconcileStatusStopAndRequeue, updated, err
 }
 
-func (r *placementSchedulingReconciler) getAllValidSyncTargetsForPlacement(ctx context.Context, placement *schedulingv1alpha1.Placement) ([]*workloadv1alpha1.SyncTarget, string, string, error) {
+// getAllValidSyncTargetsForPlacement returns the SyncTargets the placement can be scheduled onto, and
+// whether they are members of the SyncTargetPools of the selected location.
+func (r *placementSchedulingReconciler) getAllValidSyncTargetsForPlacement(ctx context.Context, placement *schedulingv1alpha1.Placement) ([]*workloadv1alpha1.SyncTarget, bool, string, string, error) {
 	if placement.Status.Phase == schedulingv1alpha1.PlacementPending || placement.Status.SelectedLocation == nil {
-		return nil, schedulingv1alpha1.ScheduleLocationNotFound, "No selected location is scheduled", nil
+		return nil, false, schedulingv1alpha1.ScheduleLocationNotFound, "No selected location is scheduled", nil
 	}
 
 	locationWorkspace := logicalcluster.NewPath(placement.Status.SelectedLocation.Path)
@@ -106,36 +121,89 @@ func (r *placementSchedulingReconciler) getAllValidSyncTargetsForPlacement(ctx c
 		placement.Status.SelectedLocation.LocationName)
 	switch {
 	case errors.IsNotFound(err):
-		return nil, schedulingv1alpha1.ScheduleLocationNotFound, "Selected location is not found", nil
+		return nil, false, schedulingv1alpha1.ScheduleLocationNotFound, "Selected location is not found", nil
 	case err != nil:
-		return nil, "", "", err
+		return nil, false, "", "", err
 	}
+	pooled := locationreconciler.IsSyncTargetPoolLocation(location)
 
 	// find all synctargets in the location workspace
 	syncTargets, err := r.listSyncTargets(logicalcluster.From(location))
 	if err != nil {
-		return nil, "", "", err
+		return nil, pooled, "", "", err
 	}
 
-	// filter the SyncTargets by location
-	locationSyncTargets, err := locationreconciler.LocationSyncTargets(syncTargets, location)
-	if len(locationSyncTargets) == 0 || err != nil {
-		return nil, schedulingv1alpha1.ScheduleNoValidTargetReason, "No SyncTarget in the selected Location", err
+	// filter the SyncTargets by location, through the pools of the location if it selects pools
+	var locationSyncTargets []*workloadv1alpha1.SyncTarget
+	if pooled {
+		pools, err := r.listSyncTargetPools(logicalcluster.From(location))
+		if err != nil {
+			return nil, pooled, "", "", err
+		}
+		locationPools, err := locationreconciler.LocationSyncTargetPools(pools, location)
+		if err != nil {
+			return nil, pooled, "", "", err
+		}
+		locationSyncTargets, err = locationreconciler.PoolSyncTargets(syncTargets, locationPools...)
+		if len(locationSyncTargets) == 0 || err != nil {
+			return nil, pooled, schedulingv1alpha1.ScheduleNoValidTargetReason, "No SyncTarget in the SyncTargetPools of the selected Location", err
+		}
+	} else {
+		locationSyncTargets, err = locationreconciler.LocationSyncTargets(syncTargets, location)
+		if len(locationSyncTargets) == 0 || err != nil {
+			return nil, pooled, schedulingv1alpha1.ScheduleNoValidTargetReason, "No SyncTarget in the selected Location", err
+		}
 	}
 
 	// filter the SyncTargets by APIs
 	validSyncTargets, message, err := r.filterAPICompatible(ctx, placement, locationSyncTargets)
 	if len(validSyncTargets) == 0 || err != nil {
-		return nil, schedulingv1alpha1.ScheduleNoValidTargetReason, message, err
+		return nil, pooled, schedulingv1alpha1.ScheduleNoValidTargetReason, message, err
 	}
 
 	// filter the SyncTargets by status.
 	validSyncTargets = locationreconciler.FilterNonEvicting(locationreconciler.FilterReady(validSyncTargets))
 	if len(validSyncTargets) == 0 {
-		return validSyncTargets, schedulingv1alpha1.ScheduleNoValidTargetReason, "No SyncTarget is ready or non evicting", nil
+		return validSyncTargets, pooled, schedulingv1alpha1.ScheduleNoValidTargetReason, "No SyncTarget is ready or non evicting", nil
 	}
 
-	return validSyncTargets, "", "", nil
+	return validSyncTargets, pooled, "", "", nil
+}
+
+// leastLoadedSyncTarget returns the SyncTarget with the least placements scheduled onto it. Ties are
+// broken by the most allocatable CPU, and then by name.
+func (r *placementSchedulingReconciler) leastLoadedSyncTarget(syncTargets []*workloadv1alpha1.SyncTarget) (*workloadv1alpha1.SyncTarget, error) {
+	type candidate struct {
+		syncTarget *workloadv1alpha1.SyncTarget
+		placements int
+	}
+	candidates := make([]candidate, 0, len(syncTargets))
+	for _, syncTarget := range syncTargets {
+		placements, err := r.countPlacements(workloadv1alpha1.ToSyncTargetKey(logicalcluster.From(syncTarget), syncTarget.Name))
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate{syncTarget: syncTarget, placements: placements})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].placements != candidates[j].placements {
+			return candidates[i].placements < candidates[j].placements
+		}
+		cpuI, cpuJ := allocatableCPU(candidates[i].syncTarget), allocatableCPU(candidates[j].syncTarget)
+		if cmp := cpuI.Cmp(cpuJ); cmp != 0 {
+			return cmp > 0
+		}
+		return candidates[i].syncTarget.Name < candidates[j].syncTarget.Name
+	})
+	return candidates[0].syncTarget, nil
+}
+
+func allocatableCPU(syncTarget *workloadv1alpha1.SyncTarget) resource.Quantity {
+	if syncTarget.Status.Allocatable == nil {
+		return resource.Quantity{}
+	}
+	return (*syncTarget.Status.Allocatable)[corev1.ResourceCPU]
 }
 
 func (r *placementSchedulingReconciler) filterAPICompatible(ctx context.Context, placement *schedulingv1alpha1.Placement, syncTargets []*workloadv1alpha1.SyncTarget) ([]*workloadv1alpha1.SyncTarget, string, error) {
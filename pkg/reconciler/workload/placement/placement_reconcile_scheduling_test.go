@@ -27,6 +27,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -172,6 +173,111 @@ func TestSchedulingReconcile(t *testing.T) {
 	}
 }
 
+func TestSchedulingReconcileSyncTargetPool(t *testing.T) {
+	poolLocation := newLocation("test-location")
+	poolLocation.Spec.Resource.Resource = "synctargetpools"
+	pool := &workloadv1alpha1.SyncTargetPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: workloadv1alpha1.SyncTargetPoolSpec{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "true"}},
+		},
+	}
+	member := func(name string, ready bool, cpu string) *workloadv1alpha1.SyncTarget {
+		syncTarget := newSyncTarget(name, ready)
+		syncTarget.Labels = map[string]string{"pool": "true"}
+		if cpu != "" {
+			syncTarget.Status.Allocatable = &corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		}
+		return syncTarget
+	}
+
+	testCases := []struct {
+		name string
+
+		placement   *schedulingv1alpha1.Placement
+		syncTargets []*workloadv1alpha1.SyncTarget
+		placements  map[string]int
+
+		wantSyncTarget string
+	}{
+		{
+			name:           "least loaded member",
+			placement:      newPlacement("test", "test-location", ""),
+			syncTargets:    []*workloadv1alpha1.SyncTarget{member("c1", true, ""), member("c2", true, ""), member("c3", true, "")},
+			placements:     map[string]int{"c1": 2, "c2": 1, "c3": 3},
+			wantSyncTarget: "c2",
+		},
+		{
+			name:           "most allocatable cpu on equal load",
+			placement:      newPlacement("test", "test-location", ""),
+			syncTargets:    []*workloadv1alpha1.SyncTarget{member("c1", true, "2"), member("c2", true, "8"), member("c3", true, "4")},
+			wantSyncTarget: "c2",
+		},
+		{
+			name:           "name on equal load and cpu",
+			placement:      newPlacement("test", "test-location", ""),
+			syncTargets:    []*workloadv1alpha1.SyncTarget{member("c2", true, ""), member("c1", true, "")},
+			wantSyncTarget: "c1",
+		},
+		{
+			name:           "non-members and unready members are skipped",
+			placement:      newPlacement("test", "test-location", ""),
+			syncTargets:    []*workloadv1alpha1.SyncTarget{member("c1", false, ""), newSyncTarget("c2", true), member("c3", true, "")},
+			placements:     map[string]int{"c3": 5},
+			wantSyncTarget: "c3",
+		},
+		{
+			name:           "replaced when the member becomes unready",
+			placement:      newPlacement("test", "test-location", "c1"),
+			syncTargets:    []*workloadv1alpha1.SyncTarget{member("c1", false, ""), member("c2", true, "")},
+			placements:     map[string]int{"c1": 1},
+			wantSyncTarget: "c2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var patched *schedulingv1alpha1.Placement
+			reconciler := &placementSchedulingReconciler{
+				listSyncTargets: func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error) {
+					return testCase.syncTargets, nil
+				},
+				listSyncTargetPools: func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTargetPool, error) {
+					return []*workloadv1alpha1.SyncTargetPool{pool}, nil
+				},
+				countPlacements: func(syncTargetKey string) (int, error) {
+					for name, count := range testCase.placements {
+						if workloadv1alpha1.ToSyncTargetKey("", name) == syncTargetKey {
+							return count, nil
+						}
+					}
+					return 0, nil
+				},
+				getLocation: func(clusterName logicalcluster.Path, name string) (*schedulingv1alpha1.Location, error) {
+					return poolLocation, nil
+				},
+				patchPlacement: func(ctx context.Context, clusterName logicalcluster.Path, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*schedulingv1alpha1.Placement, error) {
+					placementData, err := json.Marshal(testCase.placement)
+					require.NoError(t, err)
+					updatedData, err := jsonpatch.MergePatch(placementData, data)
+					require.NoError(t, err)
+					patched = &schedulingv1alpha1.Placement{}
+					require.NoError(t, json.Unmarshal(updatedData, patched))
+					return patched, nil
+				},
+				listWorkloadAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return nil, nil
+				},
+			}
+
+			_, _, err := reconciler.reconcile(context.TODO(), testCase.placement)
+			require.NoError(t, err)
+			require.NotNil(t, patched)
+			require.Equal(t, workloadv1alpha1.ToSyncTargetKey("", testCase.wantSyncTarget), patched.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey])
+		})
+	}
+}
+
 func TestReconcileStatusConditions(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -48,6 +48,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/informer"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
@@ -68,8 +69,8 @@ func NewController(
 	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
 	placementInformer schedulingv1alpha1informers.PlacementClusterInformer,
 ) (*Controller, error) {
-	resourceQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-resource")
-	gvrQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-gvr")
+	resourceQueue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), "kcp-namespace-resource")
+	gvrQueue := workqueue.NewNamedRateLimitingQueue(ratelimiter.For(ControllerName), "kcp-namespace-gvr")
 
 	c := &Controller{
 		resourceQueue: resourceQueue,
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synctargetpool
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	locationreconciler "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/location"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+)
+
+// reconcile records the member SyncTargets of the pool and whether they accept placements.
+func (c *controller) reconcile(ctx context.Context, pool *workloadv1alpha1.SyncTargetPool) error {
+	syncTargets, err := c.listSyncTargets(logicalcluster.From(pool))
+	if err != nil {
+		return err
+	}
+	members, err := locationreconciler.PoolSyncTargets(syncTargets, pool)
+	if err != nil {
+		conditions.MarkFalse(pool, conditionsv1alpha1.ReadyCondition, workloadv1alpha1.InvalidSelectorReason, conditionsv1alpha1.ConditionSeverityError, "The selector is invalid: %v", err)
+		pool.Status.Members = nil
+		pool.Status.ReadyMembers = 0
+		return nil
+	}
+
+	ready := sets.NewString()
+	for _, syncTarget := range locationreconciler.FilterNonEvicting(locationreconciler.FilterReady(members)) {
+		ready.Insert(syncTarget.Name)
+	}
+
+	pool.Status.Members = make([]workloadv1alpha1.SyncTargetPoolMember, 0, len(members))
+	for _, syncTarget := range members {
+		pool.Status.Members = append(pool.Status.Members, workloadv1alpha1.SyncTargetPoolMember{
+			Name:  syncTarget.Name,
+			Ready: ready.Has(syncTarget.Name),
+		})
+	}
+	sort.Slice(pool.Status.Members, func(i, j int) bool {
+		return pool.Status.Members[i].Name < pool.Status.Members[j].Name
+	})
+	pool.Status.ReadyMembers = int32(ready.Len())
+
+	if ready.Len() == 0 {
+		conditions.MarkFalse(pool, conditionsv1alpha1.ReadyCondition, workloadv1alpha1.NoReadyMembersReason, conditionsv1alpha1.ConditionSeverityWarning, "No member of %d accepts placements", len(members))
+	} else {
+		conditions.MarkTrue(pool, conditionsv1alpha1.ReadyCondition)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synctargetpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+)
+
+func newSyncTarget(name string, ready bool, labels map[string]string) *workloadv1alpha1.SyncTarget {
+	syncTarget := &workloadv1alpha1.SyncTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+	if ready {
+		conditions.MarkTrue(syncTarget, conditionsv1alpha1.ReadyCondition)
+	}
+	return syncTarget
+}
+
+func TestReconcile(t *testing.T) {
+	evicting := newSyncTarget("c4", true, map[string]string{"pool": "a"})
+	evicting.Spec.EvictAfter = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+
+	tests := map[string]struct {
+		selector    metav1.LabelSelector
+		syncTargets []*workloadv1alpha1.SyncTarget
+
+		wantMembers      []workloadv1alpha1.SyncTargetPoolMember
+		wantReadyMembers int32
+		wantReady        corev1.ConditionStatus
+		wantReason       string
+	}{
+		"ready and unready members": {
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			syncTargets: []*workloadv1alpha1.SyncTarget{
+				newSyncTarget("c3", true, map[string]string{"pool": "a"}),
+				newSyncTarget("c1", false, map[string]string{"pool": "a"}),
+				newSyncTarget("c2", true, map[string]string{"pool": "b"}),
+				evicting,
+			},
+			wantMembers: []workloadv1alpha1.SyncTargetPoolMember{
+				{Name: "c1"},
+				{Name: "c3", Ready: true},
+				{Name: "c4"},
+			},
+			wantReadyMembers: 1,
+			wantReady:        corev1.ConditionTrue,
+		},
+		"no ready members": {
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			syncTargets: []*workloadv1alpha1.SyncTarget{
+				newSyncTarget("c1", false, map[string]string{"pool": "a"}),
+			},
+			wantMembers:      []workloadv1alpha1.SyncTargetPoolMember{{Name: "c1"}},
+			wantReadyMembers: 0,
+			wantReady:        corev1.ConditionFalse,
+			wantReason:       workloadv1alpha1.NoReadyMembersReason,
+		},
+		"invalid selector": {
+			selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "pool", Operator: "Bogus"}}},
+			syncTargets: []*workloadv1alpha1.SyncTarget{
+				newSyncTarget("c1", true, map[string]string{"pool": "a"}),
+			},
+			wantReady:  corev1.ConditionFalse,
+			wantReason: workloadv1alpha1.InvalidSelectorReason,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				listSyncTargets: func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.SyncTarget, error) {
+					return tc.syncTargets, nil
+				},
+			}
+			pool := &workloadv1alpha1.SyncTargetPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Spec:       workloadv1alpha1.SyncTargetPoolSpec{Selector: tc.selector},
+			}
+
+			require.NoError(t, c.reconcile(context.Background(), pool))
+			if tc.wantMembers == nil {
+				require.Empty(t, pool.Status.Members)
+			} else {
+				require.Equal(t, tc.wantMembers, pool.Status.Members)
+			}
+			require.Equal(t, tc.wantReadyMembers, pool.Status.ReadyMembers)
+			ready := conditions.Get(pool, conditionsv1alpha1.ReadyCondition)
+			require.NotNil(t, ready)
+			require.Equal(t, tc.wantReady, ready.Status)
+			require.Equal(t, tc.wantReason, ready.Reason)
+		})
+	}
+}
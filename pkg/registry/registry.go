@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry provides a registry of named factories, used as extension point
+// for downstream distributions of kcp.
+package registry
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry holds named factories of T. It is safe for concurrent use.
+type Registry[T any] struct {
+	lock      sync.RWMutex
+	factories map[string]func() T
+}
+
+// Register registers the factory under the given name. Registering a name again
+// replaces the previous factory, such that registering is idempotent.
+func (r *Registry[T]) Register(name string, factory func() T) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.factories == nil {
+		r.factories = map[string]func() T{}
+	}
+	r.factories[name] = factory
+}
+
+// Unregister removes the factory of the given name, if any.
+func (r *Registry[T]) Unregister(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.factories, name)
+}
+
+// New returns new instances of all registered factories by name.
+func (r *Registry[T]) New() map[string]T {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	instances := make(map[string]T, len(r.factories))
+	for name, factory := range r.factories {
+		instances[name] = factory()
+	}
+	return instances
+}
+
+// SortedNames returns the names of the given instances in a stable order.
+func SortedNames[T any](instances map[string]T) []string {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
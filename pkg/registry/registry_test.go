@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var r Registry[*int]
+	require.Empty(t, r.New())
+
+	r.Register("b", func() *int { return new(int) })
+	r.Register("a", func() *int { return new(int) })
+	r.Register("a", func() *int { i := 42; return &i })
+
+	instances := r.New()
+	require.Equal(t, []string{"a", "b"}, SortedNames(instances))
+	require.Equal(t, 42, *instances["a"], "registering again replaces the factory")
+	require.NotSame(t, instances["b"], r.New()["b"], "every call gets new instances")
+
+	r.Unregister("a")
+	require.Equal(t, []string{"b"}, SortedNames(r.New()))
+}
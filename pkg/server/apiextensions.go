@@ -39,6 +39,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identityrotation"
 	"github.com/kcp-dev/kcp/pkg/server/filters"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
@@ -121,7 +122,7 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 					Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.SystemBoundCRDsClusterName.String()},
 				},
 			})
-			crd, err := c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundResource.Schema.UID)
+			crd, err := c.getBoundCRD(boundResource.Schema.UID, boundResource.Schema.IdentityHash)
 			if err != nil {
 				logger.Error(err, "error getting bound CRD")
 				continue
@@ -131,6 +132,10 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 				continue
 			}
 
+			if boundResource.Alias != "" {
+				crd = decorateCRDWithAlias(crd, boundResource.Alias)
+			}
+
 			// system CRDs take priority over APIBindings from the local workspace.
 			if seen.Has(crdName(crd)) {
 				// Came from system
@@ -224,11 +229,10 @@ func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiex
 	}
 
 	partialMetadataRequest := filters.IsPartialMetadataRequest(ctx)
+	identity := IdentityFromContext(ctx)
 
 	if crd == nil {
 		// Not a system CRD, so check in priority order: identity, wildcard, "normal" single cluster
-
-		identity := IdentityFromContext(ctx)
 		if clusterName == "*" && identity != "" {
 			// Priority 2: APIBinding CRD
 			crd, err = c.getForIdentityWildcard(name, identity)
@@ -251,7 +255,10 @@ func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiex
 		crd = shallowCopyCRDAndDeepCopyAnnotations(crd)
 		addPartialMetadataCRDAnnotation(crd)
 
-		if clusterName == "*" {
+		// Wildcard partial metadata requests share one storage per group resource across all identities.
+		// Requests with an identity keep the storage of the bound CRD instead, which is rooted at the etcd
+		// prefix of the identity, such that objects of other APIExports are neither read nor decoded.
+		if clusterName == "*" && identity == "" {
 			crd.UID = types.UID(name + ".wildcard.partial-metadata")
 		}
 	}
@@ -297,6 +304,21 @@ func decorateCRDWithBinding(in *apiextensionsv1.CustomResourceDefinition, identi
 	return out
 }
 
+// decorateCRDWithAlias returns a copy of the bound CRD as discovered in the workspace of an APIBinding serving
+// it under an alias resource name. Only discovery sees the alias. Requests are served by the bound CRD itself,
+// such that objects are stored under the resource of the APIExport.
+func decorateCRDWithAlias(in *apiextensionsv1.CustomResourceDefinition, alias string) *apiextensionsv1.CustomResourceDefinition {
+	out := *in
+
+	for _, names := range []*apiextensionsv1.CustomResourceDefinitionNames{&out.Spec.Names, &out.Status.AcceptedNames} {
+		names.Plural = alias
+		names.Singular = ""
+		names.ShortNames = nil
+	}
+
+	return &out
+}
+
 // addPartialMetadataCRDAnnotation adds an annotation that marks this CRD as being
 // for a partial metadata request.
 func addPartialMetadataCRDAnnotation(crd *apiextensionsv1.CustomResourceDefinition) {
@@ -324,20 +346,23 @@ func (c *apiBindingAwareCRDLister) getForIdentityWildcard(name, identity string)
 	// sort of greatest-common-denominator for the CRD/schema?
 	apiBinding := apiBindings[0].(*apisv1alpha1.APIBinding)
 
-	var boundCRDName string
+	var schemaUID string
 
 	for _, r := range apiBinding.Status.BoundResources {
-		if r.Group == group && r.Resource == resource && r.Schema.IdentityHash == identity {
-			boundCRDName = r.Schema.UID
+		// the objects of the APIBinding might have been moved away from the requested identity while
+		// the identity of the APIExport is rotated. The bound CRD of the requested identity serves the
+		// objects of all other APIBindings still bound with it.
+		if r.Group == group && r.Resource == resource && (r.Schema.IdentityHash == identity || identityrotation.RetiredIdentity(apiBinding, r.Schema.IdentityHash) == identity) {
+			schemaUID = r.Schema.UID
 			break
 		}
 	}
 
-	if boundCRDName == "" {
+	if schemaUID == "" {
 		return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 	}
 
-	crd, err := c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundCRDName)
+	crd, err := c.getBoundCRD(schemaUID, identity)
 	if err != nil {
 		return nil, err
 	}
@@ -349,6 +374,14 @@ func (c *apiBindingAwareCRDLister) getForIdentityWildcard(name, identity string)
 	return crd, nil
 }
 
+// getBoundCRD returns the bound CRD of the APIResourceSchema with the given UID serving the objects of the given identity.
+func (c *apiBindingAwareCRDLister) getBoundCRD(schemaUID, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	_, crd, err := apibinding.GetBoundCRD(func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		return c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(name)
+	}, schemaUID, identity)
+	return crd, err
+}
+
 const annotationKeyPartialMetadata = "crd.kcp.io/partial-metadata"
 
 func (c *apiBindingAwareCRDLister) getForWildcardPartialMetadata(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
@@ -382,10 +415,19 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name, id
 		for _, boundResource := range apiBinding.Status.BoundResources {
 			// identity is empty string if the request is coming from a regular workspace client.
 			// It is set if the request is coming from the virtual apiexport apiserver client.
-			matchingIdentity := identity == "" || boundResource.Schema.IdentityHash == identity
+			// While the identity of the APIExport is rotated, it might still use the retired identity.
+			matchingIdentity := identity == "" || boundResource.Schema.IdentityHash == identity ||
+				identityrotation.RetiredIdentity(apiBinding, boundResource.Schema.IdentityHash) == identity
+
+			// Workspace clients see the resource under its alias, the virtual apiexport apiserver
+			// under the resource of the APIExport.
+			matchingResource := boundResource.ServedResource() == resource
+			if identity != "" {
+				matchingResource = boundResource.Resource == resource
+			}
 
-			if boundResource.Group == group && boundResource.Resource == resource && matchingIdentity {
-				crd, err = c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundResource.Schema.UID)
+			if boundResource.Group == group && matchingResource && matchingIdentity {
+				crd, err = c.getBoundCRD(boundResource.Schema.UID, boundResource.Schema.IdentityHash)
 				if err != nil && apierrors.IsNotFound(err) {
 					// If we got here, it means there is supposed to be a CRD coming from an APIBinding, but
 					// the CRD doesn't exist for some reason.
@@ -17,17 +17,30 @@ limitations under the License.
 package server
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/stretchr/testify/require"
 
 	apiextensionshelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/kcp-dev/kcp/pkg/admission/reservedcrdgroups"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/server/filters"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/apis/v1alpha1"
 )
 
 func TestSystemCRDsLogicalClusterName(t *testing.T) {
@@ -169,3 +182,130 @@ func TestShallowCopyAndMakePartialMetadataCRD(t *testing.T) {
 		t.Error("expected shallow copy to not modify original schema type")
 	}
 }
+
+func TestGetWildcardPartialMetadata(t *testing.T) {
+	bindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+		byIdentityGroupResource:   indexAPIBindingByIdentityGroupResource,
+	})
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+		byGroupResourceName:       indexCRDByGroupResourceName,
+	})
+	for _, identity := range []string{"identity-1", "identity-2"} {
+		require.NoError(t, bindingIndexer.Add(&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer-" + identity},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-" + identity, IdentityHash: identity}},
+				},
+			},
+		}))
+		require.NoError(t, crdIndexer.Add(&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "uid-" + identity,
+				UID:         types.UID("uid-" + identity),
+				Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.SystemBoundCRDsClusterName.String(), apisv1alpha1.AnnotationBoundCRDKey: ""},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+			},
+		}))
+	}
+	lister := &apiBindingAwareCRDClusterLister{
+		crdLister:         kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+		crdIndexer:        crdIndexer,
+		apiBindingLister:  apisv1alpha1listers.NewAPIBindingClusterLister(bindingIndexer),
+		apiBindingIndexer: bindingIndexer,
+	}
+
+	var ctx context.Context
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1")
+	filters.WithAcceptHeader(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) { ctx = req.Context() })).ServeHTTP(nil, req)
+
+	t.Log("Wildcard partial metadata requests with an identity use the storage of the bound CRD of that identity")
+	crd, err := lister.Cluster("*").Get(WithIdentity(ctx, "identity-2"), "widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid-identity-2"), crd.UID)
+	require.Equal(t, "identity-2", crd.Annotations[apisv1alpha1.AnnotationAPIIdentityKey])
+	require.Contains(t, crd.Annotations, annotationKeyPartialMetadata)
+
+	t.Log("Wildcard partial metadata requests without an identity use the storage across all identities")
+	crd, err = lister.Cluster("*").Get(ctx, "widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("widgets.example.io.wildcard.partial-metadata"), crd.UID)
+}
+
+func TestResourceOverrides(t *testing.T) {
+	bindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+	})
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc,
+	})
+	for _, identity := range []string{"identity-1", "identity-2"} {
+		boundResource := apisv1alpha1.BoundAPIResource{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-" + identity, IdentityHash: identity}}
+		if identity == "identity-2" {
+			boundResource.Alias = "other-widgets"
+		}
+		require.NoError(t, bindingIndexer.Add(&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets-" + identity,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer"},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{boundResource},
+			},
+		}))
+		require.NoError(t, crdIndexer.Add(&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "uid-" + identity,
+				UID:         types.UID("uid-" + identity),
+				Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.SystemBoundCRDsClusterName.String(), apisv1alpha1.AnnotationBoundCRDKey: ""},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget"},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				AcceptedNames: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget"},
+			},
+		}))
+	}
+	lister := &apiBindingAwareCRDClusterLister{
+		crdLister:        kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+		apiBindingLister: apisv1alpha1listers.NewAPIBindingClusterLister(bindingIndexer),
+	}
+
+	t.Log("Discovery serves the resource under its alias")
+	crds, err := lister.Cluster("consumer").List(context.Background(), labels.Everything())
+	require.NoError(t, err)
+	served := map[string]types.UID{}
+	for _, crd := range crds {
+		served[crd.Status.AcceptedNames.Plural] = crd.UID
+	}
+	require.Equal(t, map[string]types.UID{"widgets": "uid-identity-1", "other-widgets": "uid-identity-2"}, served)
+
+	t.Log("Requests for the alias are served by the bound CRD")
+	crd, err := lister.Cluster("consumer").Get(context.Background(), "other-widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid-identity-2"), crd.UID)
+	require.Equal(t, "widgets", crd.Status.AcceptedNames.Plural)
+	require.Equal(t, "identity-2", crd.Annotations[apisv1alpha1.AnnotationAPIIdentityKey])
+
+	crd, err = lister.Cluster("consumer").Get(context.Background(), "widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid-identity-1"), crd.UID)
+
+	t.Log("Requests with an identity use the resource of the APIExport")
+	crd, err = lister.Cluster("consumer").Get(WithIdentity(context.Background(), "identity-2"), "widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid-identity-2"), crd.UID)
+	_, err = lister.Cluster("consumer").Get(WithIdentity(context.Background(), "identity-2"), "other-widgets.example.io")
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+}
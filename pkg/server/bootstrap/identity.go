@@ -56,7 +56,8 @@ var (
 
 	// KcpRootGroupResourceExportNames lists the APIExports in the root workspace for standard kcp group resources.
 	KcpRootGroupResourceExportNames = map[schema.GroupResource]string{
-		{Group: "core.kcp.io", Resource: "shards"}: "shards.core.kcp.io",
+		{Group: "core.kcp.io", Resource: "shards"}:             "shards.core.kcp.io",
+		{Group: "core.kcp.io", Resource: "replicationconfigs"}: "shards.core.kcp.io",
 	}
 )
 
@@ -33,8 +33,10 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	apiextensionsconversion "k8s.io/apiextensions-apiserver/pkg/apiserver/conversion"
 	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
 	kcpapiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/endpoints/filters"
@@ -55,17 +57,23 @@ import (
 	kcpadmissioninitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/authorization"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
+	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
+	"github.com/kcp-dev/kcp/pkg/cache/offload"
 	"github.com/kcp-dev/kcp/pkg/conversion"
 	"github.com/kcp-dev/kcp/pkg/embeddedetcd"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
+	reconcilerworkspace "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspace"
 	"github.com/kcp-dev/kcp/pkg/server/bootstrap"
 	kcpfilters "github.com/kcp-dev/kcp/pkg/server/filters"
 	kcpserveroptions "github.com/kcp-dev/kcp/pkg/server/options"
 	"github.com/kcp-dev/kcp/pkg/server/options/batteries"
 	"github.com/kcp-dev/kcp/pkg/server/requestinfo"
+	"github.com/kcp-dev/kcp/pkg/tableconverter"
 	"github.com/kcp-dev/kcp/pkg/tunneler"
+	"github.com/kcp-dev/kcp/pkg/usage"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
@@ -95,6 +103,7 @@ type ExtraConfig struct {
 	// authentication
 	kcpAdminToken, shardAdminToken, userToken string
 	shardAdminTokenHash                       []byte
+	emergencyToken                            string
 
 	// clients
 	DynamicClusterClient                kcpdynamic.ClusterInterface
@@ -106,7 +115,15 @@ type ExtraConfig struct {
 	BootstrapDynamicClusterClient       kcpdynamic.ClusterInterface
 	BootstrapApiExtensionsClusterClient kcpapiextensionsclientset.ClusterInterface
 
+	// CacheDynamicClient talks to the cache server directly, without falling back to the shard.
 	CacheDynamicClient kcpdynamic.ClusterInterface
+	// CacheLocalFallback serves reads of the cache informers from the shard while the cache
+	// server is unreachable. It is nil if the fallback is disabled. The informers and clients
+	// of the replication never fall back.
+	CacheLocalFallback *cacheclient.LocalFallback
+	// CacheOffloader offloads the payload of big objects replicated to the cache server, and
+	// resolves it in the cache informers. It is nil if offloading is disabled.
+	CacheOffloader *offload.Offloader
 
 	LogicalClusterAdminConfig         *rest.Config // client config connecting directly to shards, skipping the front proxy
 	ExternalLogicalClusterAdminConfig *rest.Config // client config connecting to the front proxy
@@ -114,6 +131,9 @@ type ExtraConfig struct {
 	// misc
 	preHandlerChainMux   *handlerChainMuxes
 	quotaAdmissionStopCh chan struct{}
+	// usageRequestCounter counts the requests per workspace for the usage export. It is nil if
+	// the usage export is disabled.
+	usageRequestCounter *usage.RequestCounter
 
 	// URL getters depending on genericspiserver.ExternalAddress which is initialized on server run
 	ShardBaseURL             func() string
@@ -127,6 +147,11 @@ type ExtraConfig struct {
 	DiscoveringDynamicSharedInformerFactory *informer.DiscoveringDynamicSharedInformerFactory
 	CacheKcpSharedInformerFactory           kcpinformers.SharedInformerFactory
 	CacheKubeSharedInformerFactory          kcpkubernetesinformers.SharedInformerFactory
+	// ReplicationCacheKcpSharedInformerFactory and ReplicationCacheKubeSharedInformerFactory are the
+	// informers of the cache server used by the replication. They are the cache informers above,
+	// unless reads of these fall back to the shard.
+	ReplicationCacheKcpSharedInformerFactory  kcpinformers.SharedInformerFactory
+	ReplicationCacheKubeSharedInformerFactory kcpkubernetesinformers.SharedInformerFactory
 }
 
 type completedConfig struct {
@@ -181,6 +206,13 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 		go http.ListenAndServe(opts.Extra.ProfilerAddress, nil)
 	}
 
+	// the rate limits must be set before any controller is created.
+	rateLimits, err := opts.Controllers.RateLimitConfigs()
+	if err != nil {
+		return nil, err
+	}
+	ratelimiter.SetConfigs(rateLimits)
+
 	if opts.EmbeddedEtcd.Enabled {
 		var err error
 		c.EmbeddedEtcd, err = embeddedetcd.NewConfig(opts.EmbeddedEtcd, opts.GenericControlPlane.Etcd.EnableWatchCache)
@@ -189,38 +221,12 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 		}
 	}
 
-	var err error
 	var storageFactory *serverstorage.DefaultStorageFactory
 	c.GenericConfig, storageFactory, c.KubeSharedInformerFactory, c.KubeClusterClient, err = genericcontrolplane.BuildGenericConfig(opts.GenericControlPlane)
 	if err != nil {
 		return nil, err
 	}
 
-	cacheClientConfig, err := c.Options.Cache.Client.RestConfig(rest.CopyConfig(c.GenericConfig.LoopbackClientConfig))
-	if err != nil {
-		return nil, err
-	}
-	cacheKcpClusterClient, err := kcpclientset.NewForConfig(cacheClientConfig)
-	if err != nil {
-		return nil, err
-	}
-	cacheKubeClusterClient, err := kcpkubernetesclientset.NewForConfig(cacheClientConfig)
-	if err != nil {
-		return nil, err
-	}
-	c.CacheKcpSharedInformerFactory = kcpinformers.NewSharedInformerFactoryWithOptions(
-		cacheKcpClusterClient,
-		resyncPeriod,
-	)
-	c.CacheKubeSharedInformerFactory = kcpkubernetesinformers.NewSharedInformerFactoryWithOptions(
-		cacheKubeClusterClient,
-		resyncPeriod,
-	)
-	c.CacheDynamicClient, err = kcpdynamic.NewForConfig(cacheClientConfig)
-	if err != nil {
-		return nil, err
-	}
-
 	// Setup kcp * informers, but those will need the identities for the APIExports used to make the APIs available.
 	// The identities are not known before we can get them from the APIExports via the loopback client or from the root shard in case this is a non-root shard,
 	// hence we postpone this to getOrCreateKcpIdentities() in the kcp-start-informers post-start hook.
@@ -258,6 +264,84 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 		c.RootShardKcpClusterClient = c.KcpClusterClient
 	}
 
+	cacheClientConfig, err := c.Options.Cache.Client.RestConfig(rest.CopyConfig(c.GenericConfig.LoopbackClientConfig))
+	if err != nil {
+		return nil, err
+	}
+	// the replication compares the local objects with those of the cache server, and must not
+	// see the local objects instead.
+	replicationCacheClientConfig := rest.CopyConfig(cacheClientConfig)
+	if c.Options.Cache.Client.LocalFallback {
+		// reads fall back to the shard itself while the cache server is unreachable. The
+		// identity config is needed to serve wildcard requests of the kcp root APIs.
+		probeClient, err := kcpclientset.NewForConfig(rest.CopyConfig(cacheClientConfig))
+		if err != nil {
+			return nil, err
+		}
+		c.CacheLocalFallback, err = cacheclient.NewLocalFallback(c.IdentityConfig, func(ctx context.Context) error {
+			_, err := probeClient.ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{Limit: 1})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		cacheClientConfig = cacheclient.WithLocalFallbackRoundTripper(cacheClientConfig, c.CacheLocalFallback)
+	}
+	cacheKcpClusterClient, err := kcpclientset.NewForConfig(cacheClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	cacheKubeClusterClient, err := kcpkubernetesclientset.NewForConfig(cacheClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	c.CacheKcpSharedInformerFactory = kcpinformers.NewSharedInformerFactoryWithOptions(
+		cacheKcpClusterClient,
+		resyncPeriod,
+	)
+	c.CacheKubeSharedInformerFactory = kcpkubernetesinformers.NewSharedInformerFactoryWithOptions(
+		cacheKubeClusterClient,
+		resyncPeriod,
+	)
+	if err := installDiskBackedInformers(c, c.Options.InformerCache.DiskBackedResources, c.Options.InformerCache.Dir); err != nil {
+		return nil, err
+	}
+	c.ReplicationCacheKcpSharedInformerFactory = c.CacheKcpSharedInformerFactory
+	c.ReplicationCacheKubeSharedInformerFactory = c.CacheKubeSharedInformerFactory
+	if c.CacheLocalFallback != nil {
+		replicationCacheKcpClusterClient, err := kcpclientset.NewForConfig(replicationCacheClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		replicationCacheKubeClusterClient, err := kcpkubernetesclientset.NewForConfig(replicationCacheClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		c.ReplicationCacheKcpSharedInformerFactory = kcpinformers.NewSharedInformerFactoryWithOptions(
+			replicationCacheKcpClusterClient,
+			resyncPeriod,
+		)
+		c.ReplicationCacheKubeSharedInformerFactory = kcpkubernetesinformers.NewSharedInformerFactoryWithOptions(
+			replicationCacheKubeClusterClient,
+			resyncPeriod,
+		)
+	}
+	if c.CacheOffloader, err = c.Options.Cache.Client.Offloader(); err != nil {
+		return nil, err
+	}
+	if c.CacheOffloader != nil {
+		if err := c.CacheOffloader.TransformInformers(offload.KcpInformers(c.CacheKcpSharedInformerFactory)...); err != nil {
+			return nil, err
+		}
+		if err := c.CacheOffloader.TransformInformers(offload.KubeInformers(c.CacheKubeSharedInformerFactory)...); err != nil {
+			return nil, err
+		}
+	}
+	c.CacheDynamicClient, err = kcpdynamic.NewForConfig(replicationCacheClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	informerConfig := rest.CopyConfig(c.IdentityConfig)
 	informerConfig.UserAgent = "kcp-informers"
 	informerKcpClient, err := kcpclientset.NewForConfig(informerConfig)
@@ -313,6 +397,10 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.emergencyToken, err = opts.EmergencyAccess.ApplyTo(c.GenericConfig)
+	if err != nil {
+		return nil, err
+	}
 	if sets.NewString(opts.Extra.BatteriesIncluded...).Has(batteries.User) {
 		c.userToken = userToken
 	}
@@ -370,6 +458,11 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 	// Make sure to set our RequestInfoResolver that is capable of populating a RequestInfo even for /services/... URLs.
 	c.GenericConfig.RequestInfoResolver = requestinfo.NewKCPRequestInfoResolver()
 
+	discoveryChangesLongRunningRequestCheck := c.GenericConfig.LongRunningFunc
+	c.GenericConfig.LongRunningFunc = func(r *http.Request, requestInfo *request.RequestInfo) bool {
+		return (!requestInfo.IsResourceRequest && requestInfo.Path == DiscoveryChangesPath) || discoveryChangesLongRunningRequestCheck(r, requestInfo)
+	}
+
 	if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.SyncerTunnel) {
 		kubeBasicLongRunningRequestCheck := c.GenericConfig.LongRunningFunc
 		tunnelBasicLongRunningRequestCheck := genericfilters.BasicLongRunningRequestCheck(sets.NewString(""), sets.NewString("tunnel"))
@@ -382,9 +475,51 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 	// is called multiple times, but only one of the handler chain will actually be used. Hence, we wrap it
 	// to give handlers below one mux.Handle func to call.
 	c.preHandlerChainMux = &handlerChainMuxes{}
+	schedulingSimulator := reconcilerworkspace.NewSchedulingSimulator(
+		c.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(),
+		c.CacheKcpSharedInformerFactory.Core().V1alpha1().Shards(),
+		c.CacheKcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceTypes(),
+	)
+	indexers.AddIfNotPresentOrDie(c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().GetIndexer(), cache.Indexers{
+		indexers.APIBindingByServedResources: indexers.IndexAPIBindingByServedResources,
+	})
+	discoveryNotifier := NewDiscoveryNotifier(
+		c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+	)
+	featureInfo := &featureInfoProvider{
+		shardName:   opts.Extra.ShardName,
+		crdLister:   c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		shardLister: c.CacheKcpSharedInformerFactory.Core().V1alpha1().Shards().Lister(),
+	}
+	if opts.Virtual.Enabled {
+		// the virtual workspaces are only known after the virtual config is created below.
+		featureInfo.virtualWorkspaces = func() []string {
+			names := make([]string, 0, len(c.OptionalVirtual.Extra.VirtualWorkspaces))
+			for _, vw := range c.OptionalVirtual.Extra.VirtualWorkspaces {
+				names = append(names, vw.Name)
+			}
+			return names
+		}
+	}
+	if opts.Usage.ExportSinkURL != "" {
+		c.usageRequestCounter = usage.NewRequestCounter()
+	}
 	c.GenericConfig.BuildHandlerChainFunc = func(apiHandler http.Handler, genericConfig *genericapiserver.Config) (secure http.Handler) {
 		apiHandler = WithWildcardListWatchGuard(apiHandler)
+		if c.usageRequestCounter != nil {
+			apiHandler = c.usageRequestCounter.WithRequestCounting(apiHandler)
+		}
 		apiHandler = WithRequestIdentity(apiHandler)
+		apiHandler = WithDeprecatedBoundAPIMetrics(
+			apiHandler,
+			c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().GetIndexer(),
+			c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		)
+		apiHandler = WithSchedulingSimulation(apiHandler, schedulingSimulator)
+		apiHandler = WithDiscoveryChanges(apiHandler, discoveryNotifier)
+		apiHandler = WithDebugLogging(apiHandler)
+		apiHandler = WithFeatureInfo(apiHandler, featureInfo)
 		apiHandler = authorization.WithSubjectAccessReviewAuditAnnotations(apiHandler)
 		apiHandler = authorization.WithDeepSubjectAccessReview(apiHandler)
 
@@ -454,7 +589,7 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 
 		apiHandler = kcpfilters.WithAuditEventClusterAnnotation(apiHandler)
 		apiHandler = WithAuditAnnotation(apiHandler) // Must run before any audit annotation is made
-		apiHandler = WithLocalProxy(apiHandler, opts.Extra.ShardName, opts.Extra.ShardBaseURL, c.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(), c.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters())
+		apiHandler = WithLocalProxy(apiHandler, opts.Extra.ShardName, opts.Extra.ShardBaseURL, c.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(), c.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(), c.CacheKcpSharedInformerFactory.Core().V1alpha1().Shards())
 		apiHandler = WithInClusterServiceAccountRequestRewrite(apiHandler)
 		apiHandler = kcpfilters.WithAcceptHeader(apiHandler)
 		apiHandler = WithUserAgent(apiHandler)
@@ -516,8 +651,20 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 		return nil, fmt.Errorf("error configuring api extensions: %w", err)
 	}
 
+	// conversion webhooks of APIResourceSchemas are called by url, services are not supported. CRDs of
+	// tenants cannot use them. The webhooks are configured by API providers, hence only public addresses
+	// and the networks allowed by the administrator are connected to.
+	conversionWebhookNetworks, err := opts.Extra.ConversionWebhookAllowedNetworks()
+	if err != nil {
+		return nil, err
+	}
+	webhookConverterFactory, err := apiextensionsconversion.NewCRConverterFactory(nil, conversion.NewWebhookAuthResolverWrapper(conversionWebhookNetworks))
+	if err != nil {
+		return nil, fmt.Errorf("error configuring conversion webhooks: %w", err)
+	}
 	c.ApiExtensions.ExtraConfig.ConversionFactory = conversion.NewCRConverterFactory(
 		c.KcpSharedInformerFactory.Apis().V1alpha1().APIConversions(),
+		webhookConverterFactory,
 		opts.Extra.ConversionCELTransformationTimeout,
 	)
 	// make sure the informer gets started, otherwise conversions will not work!
@@ -541,7 +688,7 @@ func NewConfig(opts kcpserveroptions.CompletedOptions) (*Config, error) {
 	}
 	c.ApiExtensions.ExtraConfig.Client = c.ApiExtensionsClusterClient
 	c.ApiExtensions.ExtraConfig.Informers = c.ApiExtensionsSharedInformerFactory
-	c.ApiExtensions.ExtraConfig.TableConverterProvider = NewTableConverterProvider()
+	c.ApiExtensions.ExtraConfig.TableConverterProvider = tableconverter.NewProvider()
 
 	c.MiniAggregator = &aggregator.MiniAggregatorConfig{
 		GenericConfig: c.GenericConfig,
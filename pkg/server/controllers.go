@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	_ "net/http/pprof"
@@ -28,6 +29,8 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
 
 	corev1 "k8s.io/api/core/v1"
 	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
@@ -35,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	certutil "k8s.io/client-go/util/cert"
@@ -48,19 +52,26 @@ import (
 
 	configuniversal "github.com/kcp-dev/kcp/config/universal"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/cache/client/heartbeat"
+	"github.com/kcp-dev/kcp/pkg/events"
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
 	"github.com/kcp-dev/kcp/pkg/informer"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibindingdeletion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportbindings"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportendpointslice"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/crdcleanup"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/extraannotationsync"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identitycache"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identityrotation"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/namespacemarker"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/permissionclaimlabel"
 	apisreplicateclusterrole "github.com/kcp-dev/kcp/pkg/reconciler/apis/replicateclusterrole"
 	apisreplicateclusterrolebinding "github.com/kcp-dev/kcp/pkg/reconciler/apis/replicateclusterrolebinding"
 	apisreplicatelogicalcluster "github.com/kcp-dev/kcp/pkg/reconciler/apis/replicatelogicalcluster"
 	"github.com/kcp-dev/kcp/pkg/reconciler/cache/replication"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/cabundle"
 	logicalclusterctrl "github.com/kcp-dev/kcp/pkg/reconciler/core/logicalcluster"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion"
 	coresreplicateclusterrole "github.com/kcp-dev/kcp/pkg/reconciler/core/replicateclusterrole"
@@ -69,15 +80,21 @@ import (
 	"github.com/kcp-dev/kcp/pkg/reconciler/garbagecollector"
 	"github.com/kcp-dev/kcp/pkg/reconciler/kubequota"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/defaultapibinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/initialization"
 	tenancylogicalcluster "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/logicalcluster"
 	tenancyreplicateclusterrole "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/replicateclusterrole"
 	tenancyreplicateclusterrolebinding "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/replicateclusterrolebinding"
 	tenancyreplicatelogicalcluster "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/replicatelogicalcluster"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspace"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacelifecyclewebhook"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacerequest"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacetemplate"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacetrash"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacetype"
 	"github.com/kcp-dev/kcp/pkg/reconciler/topology/partitionset"
 	workloadsapiexport "github.com/kcp-dev/kcp/pkg/reconciler/workload/apiexport"
+	"github.com/kcp-dev/kcp/pkg/usage"
 	initializingworkspacesbuilder "github.com/kcp-dev/kcp/pkg/virtual/initializingworkspaces/builder"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
@@ -282,6 +299,85 @@ func (s *Server) installRootCAConfigMapController(ctx context.Context, config *r
 	})
 }
 
+func (s *Server) installCABundleConfigMapController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, cabundle.ControllerName)
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	caDataPath := s.Options.Controllers.SAController.RootCAFile
+	if caDataPath == "" {
+		caDataPath = s.Options.GenericControlPlane.SecureServing.SecureServingOptions.ServerCert.CertKey.CertFile
+	}
+	providers := make([]dynamiccertificates.CAContentProvider, 0, len(s.Options.Controllers.TrustedCABundleFiles)+1)
+	for _, file := range append([]string{caDataPath}, s.Options.Controllers.TrustedCABundleFiles...) {
+		provider, err := dynamiccertificates.NewDynamicCAContentFromFile("trusted-ca-bundle", file)
+		if err != nil {
+			return fmt.Errorf("error loading CA bundle file %s: %w", file, err)
+		}
+		providers = append(providers, provider)
+	}
+	caBundle := dynamiccertificates.NewUnionCAContentProvider(providers...)
+
+	c, err := cabundle.NewController(
+		kubeClusterClient,
+		s.KubeSharedInformerFactory.Core().V1().Namespaces(),
+		s.KubeSharedInformerFactory.Core().V1().ConfigMaps(),
+		caBundle,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(cabundle.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(cabundle.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		if runner, ok := caBundle.(dynamiccertificates.ControllerRunner); ok {
+			go runner.Run(goContext(hookContext), 1)
+		}
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installNamespaceMarkerController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, namespacemarker.ControllerName)
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := namespacemarker.NewController(
+		kubeClusterClient,
+		s.KubeSharedInformerFactory.Core().V1().Namespaces(),
+		s.KubeSharedInformerFactory.Core().V1().ConfigMaps(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(namespacemarker.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(namespacemarker.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
 func readCA(file string) ([]byte, error) {
 	rootCA, err := os.ReadFile(file)
 	if err != nil {
@@ -353,6 +449,7 @@ func (s *Server) installLogicalClusterDeletionController(ctx context.Context, co
 		metadataClusterClient,
 		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
 		discoverResourcesFn,
+		s.Options.Controllers.WorkspaceDeletionReportRetention,
 	)
 
 	return s.AddPostStartHook(postStartHookName(logicalclusterdeletion.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
@@ -542,6 +639,34 @@ func (s *Server) installLogicalCluster(ctx context.Context, config *rest.Config)
 	return nil
 }
 
+func (s *Server) installWorkspaceRequestController(ctx context.Context, config *rest.Config) error {
+	workspaceRequestConfig := rest.CopyConfig(config)
+	workspaceRequestConfig = rest.AddUserAgent(workspaceRequestConfig, workspacerequest.ControllerName)
+	kcpClusterClient, err := kcpclientset.NewForConfig(workspaceRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	workspaceRequestController, err := workspacerequest.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceRequests(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(workspacerequest.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacerequest.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+		go workspaceRequestController.Start(ctx, 2)
+		return nil
+	})
+}
+
 func (s *Server) installAPIBindingController(ctx context.Context, config *rest.Config, ddsif *informer.DiscoveringDynamicSharedInformerFactory) error {
 	// NOTE: keep `config` unaltered so there isn't cross-use between controllers installed here.
 	apiBindingConfig := rest.CopyConfig(config)
@@ -557,6 +682,11 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		return err
 	}
 
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(apiBindingConfig)
+	if err != nil {
+		return err
+	}
+
 	c, err := apibinding.NewController(
 		crdClusterClient,
 		kcpClusterClient,
@@ -568,6 +698,8 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
 		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIConversions(),
 		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		events.NewRecorder(ctx, kubeClusterClient, apibinding.ControllerName),
+		apibinding.SchemaRollout(s.Options.Controllers.APIBindingSchemaRollout),
 	)
 	if err != nil {
 		return err
@@ -804,6 +936,77 @@ func (s *Server) installCRDCleanupController(ctx context.Context, config *rest.C
 	})
 }
 
+func (s *Server) installIdentityRotationController(ctx context.Context, config *rest.Config) error {
+	// The objects are moved between identities in etcd directly, without decoding them. Values encrypted
+	// at rest are bound to their key, and cannot be moved like that.
+	if s.Options.GenericControlPlane.Etcd.EncryptionProviderConfigFilepath != "" {
+		klog.FromContext(ctx).Info("not starting the identity rotation controller because encryption at rest is configured", "controller", identityrotation.ControllerName)
+		return nil
+	}
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, identityrotation.ControllerName)
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	crdClusterClient, err := kcpapiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	storageConfig := s.Options.GenericControlPlane.Etcd.StorageConfig
+	var tlsConfig *tls.Config
+	if storageConfig.Transport.CertFile != "" || storageConfig.Transport.KeyFile != "" || storageConfig.Transport.TrustedCAFile != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      storageConfig.Transport.CertFile,
+			KeyFile:       storageConfig.Transport.KeyFile,
+			TrustedCAFile: storageConfig.Transport.TrustedCAFile,
+		}
+		if tlsConfig, err = tlsInfo.ClientConfig(); err != nil {
+			return err
+		}
+	}
+	etcdClient, err := clientv3.New(clientv3.Config{
+		Endpoints:   storageConfig.Transport.ServerList,
+		TLS:         tlsConfig,
+		DialTimeout: 20 * time.Second,
+		Context:     ctx,
+	})
+	if err != nil {
+		return err
+	}
+
+	c, err := identityrotation.NewController(
+		kcpClusterClient,
+		crdClusterClient,
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		identityrotation.NewEtcdStore(etcdClient),
+		storageConfig.Prefix,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(identityrotation.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(identityrotation.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go func() {
+			defer etcdClient.Close()
+			c.Start(goContext(hookContext), 2)
+		}()
+
+		return nil
+	})
+}
+
 func (s *Server) installAPIExportController(ctx context.Context, config *rest.Config) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, apiexport.ControllerName)
@@ -816,6 +1019,21 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		return err
 	}
 
+	var identityEscrow *identityescrow.Escrow
+	if s.Options.IdentityEscrow.StoreURL != "" {
+		store, err := identityescrow.NewStoreForURL(s.Options.IdentityEscrow.StoreURL)
+		if err != nil {
+			return err
+		}
+		encryptionKey, err := identityescrow.LoadEncryptionKey(s.Options.IdentityEscrow.EncryptionKeyFile)
+		if err != nil {
+			return err
+		}
+		if identityEscrow, err = identityescrow.New(store, encryptionKey); err != nil {
+			return err
+		}
+	}
+
 	c, err := apiexport.NewController(
 		kcpClusterClient,
 		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
@@ -823,6 +1041,8 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		kubeClusterClient,
 		s.KubeSharedInformerFactory.Core().V1().Namespaces(),
 		s.KubeSharedInformerFactory.Core().V1().Secrets(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		identityEscrow,
 	)
 	if err != nil {
 		return err
@@ -848,6 +1068,40 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 	})
 }
 
+func (s *Server) installAPIExportBindingsController(ctx context.Context, externalLogicalClusterAdminConfig *rest.Config) error {
+	// APIExports bound on this shard can live on any shard, hence the front-proxy is used.
+	config := rest.CopyConfig(externalLogicalClusterAdminConfig)
+	config = rest.AddUserAgent(config, apiexportbindings.ControllerName+"+"+s.Options.Extra.ShardName)
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := apiexportbindings.NewController(
+		s.Options.Extra.ShardName,
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(apiexportbindings.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(apiexportbindings.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
 func (s *Server) installApisReplicateClusterRoleControllers(ctx context.Context, config *rest.Config) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, apisreplicateclusterrole.ControllerName)
@@ -1066,6 +1320,156 @@ func (s *Server) installTenancyReplicateClusterRoleBindingControllers(ctx contex
 	})
 }
 
+func (s *Server) installDefaultAPIBindingController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, defaultapibinding.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := defaultapibinding.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().DefaultAPIBindings(),
+		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().DefaultAPIBindings(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(defaultapibinding.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(defaultapibinding.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceTemplateController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, workspacetemplate.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacetemplate.NewController(
+		kcpClusterClient,
+		kubeClusterClient,
+		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceTemplates(),
+		s.CacheKcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceTemplates(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(workspacetemplate.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacetemplate.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceLifecycleWebhookController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, workspacelifecyclewebhook.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacelifecyclewebhook.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceLifecycleWebhooks(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(workspacelifecyclewebhook.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacelifecyclewebhook.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceTrashController(ctx context.Context, config *rest.Config, externalLogicalClusterAdminConfig *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, workspacetrash.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	externalLogicalClusterAdminConfig = rest.CopyConfig(externalLogicalClusterAdminConfig)
+	externalLogicalClusterAdminConfig = rest.AddUserAgent(externalLogicalClusterAdminConfig, workspacetrash.ControllerName+"+"+s.Options.Extra.ShardName)
+	kcpExternalClient, err := kcpclientset.NewForConfig(externalLogicalClusterAdminConfig)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacetrash.NewController(
+		s.Options.Controllers.WorkspaceSoftDeletionRetention,
+		kcpClusterClient,
+		kcpExternalClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().Workspaces(),
+		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
+		events.NewRecorder(ctx, kubeClusterClient, workspacetrash.ControllerName),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(workspacetrash.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacetrash.ControllerName))
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
 func (s *Server) installAPIExportEndpointSliceController(ctx context.Context, config *rest.Config) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, apiexportendpointslice.ControllerName)
@@ -1241,8 +1645,24 @@ func (s *Server) installApiExportIdentityController(ctx context.Context, config
 }
 
 func (s *Server) installReplicationController(ctx context.Context, config *rest.Config) error {
+	cacheClientConfig, err := s.Options.Cache.Client.RestConfig(rest.CopyConfig(s.GenericConfig.LoopbackClientConfig))
+	if err != nil {
+		return err
+	}
+	stream, err := s.Options.Cache.Client.ReplicationStream(cacheClientConfig, s.Options.Extra.ShardName)
+	if err != nil {
+		return err
+	}
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, replication.ControllerName)
+	dynamicLocalClient, err := kcpdynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
 	// TODO(sttts): set user agent
-	controller, err := replication.NewController(s.Options.Extra.ShardName, s.CacheDynamicClient, s.KcpSharedInformerFactory, s.CacheKcpSharedInformerFactory, s.KubeSharedInformerFactory, s.CacheKubeSharedInformerFactory)
+	controller, err := replication.NewController(s.Options.Extra.ShardName, dynamicLocalClient, s.CacheDynamicClient, s.KcpSharedInformerFactory, s.ReplicationCacheKcpSharedInformerFactory, s.KubeSharedInformerFactory, s.ReplicationCacheKubeSharedInformerFactory, s.CacheOffloader, stream)
 	if err != nil {
 		return err
 	}
@@ -1254,6 +1674,12 @@ func (s *Server) installReplicationController(ctx context.Context, config *rest.
 		}
 
 		go controller.Start(goContext(hookContext), 2)
+
+		if interval := s.Options.Cache.Client.HeartbeatInterval; interval > 0 {
+			if err := heartbeat.Start(klog.NewContext(goContext(hookContext), logger), cacheClientConfig, s.Options.Extra.ShardName, interval, controller.ReplicationState); err != nil {
+				logger.Error(err, "failed to start heartbeats to the cache server")
+			}
+		}
 		return nil
 	})
 }
@@ -1303,6 +1729,31 @@ func (s *Server) installGarbageCollectorController(ctx context.Context, config *
 	})
 }
 
+func (s *Server) installUsageExporter(ctx context.Context) error {
+	if s.usageRequestCounter == nil {
+		return nil
+	}
+
+	sink, err := usage.NewSinkForURL(s.Options.Usage.ExportSinkURL)
+	if err != nil {
+		return err
+	}
+	objectCounter := usage.NewObjectCounter(s.DiscoveringDynamicSharedInformerFactory)
+	exporter := usage.NewExporter(s.Options.Extra.ShardName, s.Options.Usage.ExportInterval, sink, s.usageRequestCounter, objectCounter)
+
+	return s.AddPostStartHook("kcp-start-usage-exporter", func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", "kcp-start-usage-exporter")
+		if err := s.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go exporter.Start(goContext(hookContext))
+
+		return nil
+	})
+}
+
 func (s *Server) WaitForSync(stop <-chan struct{}) error {
 	// Wait for shared informer factories to by synced.
 	// factory. Otherwise, informer list calls may go into backoff (before the CRDs are ready) and
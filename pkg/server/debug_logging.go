@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+)
+
+// DebugLoggingPath is the non-resource path in the root workspace that changes the log
+// verbosity of the serving kcp process at runtime.
+const DebugLoggingPath = "/debug/logging"
+
+// DebugLoggingStatus is the response of GET requests to DebugLoggingPath.
+type DebugLoggingStatus struct {
+	// Verbosity is the global log verbosity.
+	Verbosity int `json:"verbosity"`
+	// Overrides are the targeted verbosity overrides, including expired ones with captured logs.
+	Overrides []logging.DebugOverride `json:"overrides,omitempty"`
+}
+
+// DebugLoggingVerbosity is the body of PUT requests to DebugLoggingPath, changing the global
+// log verbosity.
+type DebugLoggingVerbosity struct {
+	Verbosity int `json:"verbosity"`
+}
+
+// DebugLoggingOverride is the body of POST requests to DebugLoggingPath, adding a targeted
+// verbosity override.
+type DebugLoggingOverride struct {
+	logging.DebugOverride
+
+	// Duration is how long the override is active.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// WithDebugLogging serves DebugLoggingPath in the root workspace:
+//
+//   - GET returns the DebugLoggingStatus, or with the "capture" query parameter the log lines
+//     captured by the override with that ID as plain text.
+//   - PUT sets the global verbosity from a DebugLoggingVerbosity.
+//   - POST adds the targeted override in a DebugLoggingOverride and returns it with its ID.
+//   - DELETE removes the override with the ID in the "id" query parameter.
+//
+// Verbosity changes only affect the kcp process serving the request, i.e. requests should be
+// sent to the shard to debug directly. The handler has to run after authorization, i.e. the user
+// needs the verb on the non-resource URL in the root workspace.
+func WithDebugLogging(apiHandler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		info, ok := request.RequestInfoFrom(req.Context())
+		if cluster == nil || cluster.Name != core.RootCluster || !ok || info.IsResourceRequest || info.Path != DebugLoggingPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		logger := klog.FromContext(req.Context())
+		badRequest := func(format string, args ...interface{}) {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewBadRequest(fmt.Sprintf(format, args...)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+		}
+		notFound := func(id string) {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewNotFound(schema.GroupResource{Resource: "debugoverrides"}, id),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+		}
+		writeJSON := func(code int, obj interface{}) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			if err := json.NewEncoder(w).Encode(obj); err != nil {
+				logger.Error(err, "failed to write debug logging response")
+			}
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			if id := req.URL.Query().Get("capture"); id != "" {
+				lines, dropped, found := logging.CapturedLogs(id)
+				if !found {
+					notFound(id)
+					return
+				}
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				if dropped > 0 {
+					fmt.Fprintf(w, "# %d earlier lines dropped\n", dropped)
+				}
+				fmt.Fprint(w, strings.Join(lines, "\n"))
+				return
+			}
+			writeJSON(http.StatusOK, DebugLoggingStatus{
+				Verbosity: logging.Verbosity(),
+				Overrides: logging.DebugOverrides(),
+			})
+
+		case http.MethodPut:
+			var verbosity DebugLoggingVerbosity
+			if err := json.NewDecoder(req.Body).Decode(&verbosity); err != nil {
+				badRequest("invalid debug logging request: %v", err)
+				return
+			}
+			if err := logging.SetVerbosity(verbosity.Verbosity); err != nil {
+				badRequest("invalid debug logging request: %v", err)
+				return
+			}
+			logger.Info("changed log verbosity", "verbosity", verbosity.Verbosity)
+			writeJSON(http.StatusOK, DebugLoggingStatus{
+				Verbosity: logging.Verbosity(),
+				Overrides: logging.DebugOverrides(),
+			})
+
+		case http.MethodPost:
+			var override DebugLoggingOverride
+			if err := json.NewDecoder(req.Body).Decode(&override); err != nil {
+				badRequest("invalid debug logging request: %v", err)
+				return
+			}
+			added, err := logging.AddDebugOverride(override.DebugOverride, override.Duration.Duration)
+			if err != nil {
+				badRequest("invalid debug logging request: %v", err)
+				return
+			}
+			logger.Info("added log verbosity override", "id", added.ID, "component", added.Component, "cluster", added.Cluster, "verbosity", added.Verbosity, "expires", added.Expires)
+			writeJSON(http.StatusCreated, added)
+
+		case http.MethodDelete:
+			id := req.URL.Query().Get("id")
+			if id == "" {
+				badRequest("the id query parameter is required")
+				return
+			}
+			if !logging.RemoveDebugOverride(id) {
+				notFound(id)
+				return
+			}
+			logger.Info("removed log verbosity override", "id", id)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{}, info.Verb),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+		}
+	}
+}
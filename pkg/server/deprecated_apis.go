@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+var (
+	deprecatedBoundAPIRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "apibinding_deprecated_api_requests_total",
+			Help:           "Number of requests to deprecated versions of APIs bound through APIBindings, by APIExport.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"group", "version", "resource", "apiexport"},
+	)
+
+	registerDeprecatedBoundAPIMetrics sync.Once
+)
+
+// WithDeprecatedBoundAPIMetrics counts requests to API versions that the APIExport provider
+// marked as deprecated in the APIResourceSchema, per APIExport. The deprecation warnings
+// themselves are returned to clients by the apiextensions handler serving the bound CRD.
+//
+// apiBindingIndexer must have the indexers.APIBindingByServedResources index.
+func WithDeprecatedBoundAPIMetrics(
+	handler http.Handler,
+	apiBindingIndexer cache.Indexer,
+	crdLister kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister,
+) http.Handler {
+	registerDeprecatedBoundAPIMetrics.Do(func() {
+		legacyregistry.MustRegister(deprecatedBoundAPIRequests)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if export, info, deprecated := deprecatedBoundAPI(req, apiBindingIndexer, crdLister); deprecated {
+			deprecatedBoundAPIRequests.WithLabelValues(info.APIGroup, info.APIVersion, info.Resource, export).Inc()
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// deprecatedBoundAPI returns the APIExport of the API requested by req, if it is bound
+// through an APIBinding and the requested version is deprecated.
+func deprecatedBoundAPI(
+	req *http.Request,
+	apiBindingIndexer cache.Indexer,
+	crdLister kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister,
+) (string, *request.RequestInfo, bool) {
+	info, ok := request.RequestInfoFrom(req.Context())
+	if !ok || !info.IsResourceRequest || info.APIGroup == "" {
+		return "", nil, false
+	}
+	cluster := request.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Wildcard || cluster.Name.Empty() {
+		return "", nil, false
+	}
+
+	apiBindings, err := apiBindingIndexer.ByIndex(indexers.APIBindingByServedResources, indexers.APIBindingBoundResourceValue(cluster.Name, info.APIGroup, info.Resource))
+	if err != nil {
+		return "", nil, false
+	}
+	for _, obj := range apiBindings {
+		apiBinding := obj.(*apisv1alpha1.APIBinding)
+		for _, boundResource := range apiBinding.Status.BoundResources {
+			if boundResource.Group != info.APIGroup || boundResource.ServedResource() != info.Resource {
+				continue
+			}
+			crd, err := crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundResource.Schema.UID)
+			if err != nil {
+				return "", nil, false
+			}
+			for _, version := range crd.Spec.Versions {
+				if version.Name != info.APIVersion || !version.Deprecated {
+					continue
+				}
+				exportName := ""
+				if apiBinding.Spec.Reference.Export != nil {
+					exportName = apiBinding.Spec.Reference.Export.Name
+				}
+				return logicalcluster.NewPath(apiBinding.Status.APIExportClusterName).Join(exportName).String(), info, true
+			}
+			return "", nil, false
+		}
+	}
+	return "", nil, false
+}
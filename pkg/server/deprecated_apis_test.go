@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestDeprecatedBoundAPI(t *testing.T) {
+	bindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{indexers.APIBindingByServedResources: indexers.IndexAPIBindingByServedResources})
+	require.NoError(t, bindingIndexer.Add(&apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer"},
+		},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.BindingReference{Export: &apisv1alpha1.ExportBindingReference{Path: "root:provider", Name: "widgets"}},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			APIExportClusterName: "provider",
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-1"}},
+				{Group: "example.io", Resource: "gizmos", Alias: "gizmoaliases", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-1"}},
+			},
+		},
+	}))
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	require.NoError(t, crdIndexer.Add(&apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "uid-1",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.SystemBoundCRDsClusterName.String()},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Deprecated: true},
+				{Name: "v1"},
+			},
+		},
+	}))
+	crdLister := kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer)
+
+	tests := map[string]struct {
+		cluster        string
+		info           *request.RequestInfo
+		wantExport     string
+		wantDeprecated bool
+	}{
+		"deprecated version": {
+			cluster:        "consumer",
+			info:           &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.io", APIVersion: "v1alpha1", Resource: "widgets"},
+			wantExport:     "provider:widgets",
+			wantDeprecated: true,
+		},
+		"aliased resource": {
+			cluster:        "consumer",
+			info:           &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.io", APIVersion: "v1alpha1", Resource: "gizmoaliases"},
+			wantExport:     "provider:widgets",
+			wantDeprecated: true,
+		},
+		"current version": {
+			cluster: "consumer",
+			info:    &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.io", APIVersion: "v1", Resource: "widgets"},
+		},
+		"other resource": {
+			cluster: "consumer",
+			info:    &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.io", APIVersion: "v1alpha1", Resource: "gadgets"},
+		},
+		"other cluster": {
+			cluster: "other",
+			info:    &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.io", APIVersion: "v1alpha1", Resource: "widgets"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			ctx := request.WithCluster(req.Context(), request.Cluster{Name: logicalcluster.Name(tc.cluster)})
+			ctx = request.WithRequestInfo(ctx, tc.info)
+
+			export, _, deprecated := deprecatedBoundAPI(req.WithContext(ctx), bindingIndexer, crdLister)
+			require.Equal(t, tc.wantDeprecated, deprecated)
+			require.Equal(t, tc.wantExport, export)
+		})
+	}
+}
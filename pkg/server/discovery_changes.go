@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1informers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+)
+
+// DiscoveryChangesPath is the non-resource path in every workspace that streams notifications
+// about APIs being added to or removed from the workspace.
+const DiscoveryChangesPath = "/discoverychanges"
+
+const (
+	// DiscoveryChangedEventType is sent when the discovery information of the workspace changed.
+	DiscoveryChangedEventType = "DiscoveryChanged"
+	// DiscoveryHeartbeatEventType is sent periodically to keep idle connections open.
+	DiscoveryHeartbeatEventType = "Heartbeat"
+)
+
+// discoveryHeartbeatInterval is the interval of heartbeat events on idle streams.
+var discoveryHeartbeatInterval = 30 * time.Second
+
+// DiscoveryChangeEvent is a line in the stream served at DiscoveryChangesPath.
+type DiscoveryChangeEvent struct {
+	// Type is either DiscoveryChanged or Heartbeat.
+	Type string `json:"type"`
+	// Kind is the kind of the object that caused the change, i.e. APIBinding or CustomResourceDefinition.
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the object that caused the change.
+	Name string `json:"name,omitempty"`
+}
+
+// DiscoveryNotifier notifies subscribers when APIs are added to or removed from a workspace, i.e.
+// when APIBindings become bound, change their bound resources or are deleted, and when CRDs are
+// established or deleted.
+type DiscoveryNotifier struct {
+	lock        sync.Mutex
+	subscribers map[logicalcluster.Name]map[chan DiscoveryChangeEvent]struct{}
+}
+
+// NewDiscoveryNotifier returns a notifier watching the given informers.
+func NewDiscoveryNotifier(
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+) *DiscoveryNotifier {
+	n := &DiscoveryNotifier{
+		subscribers: map[logicalcluster.Name]map[chan DiscoveryChangeEvent]struct{}{},
+	}
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if binding, ok := obj.(*apisv1alpha1.APIBinding); ok && binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound {
+				n.notifyFor("APIBinding", binding)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBinding, ok := oldObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			newBinding, ok := newObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			if oldBinding.Status.Phase != newBinding.Status.Phase || !equality.Semantic.DeepEqual(oldBinding.Status.BoundResources, newBinding.Status.BoundResources) {
+				n.notifyFor("APIBinding", newBinding)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if binding, ok := obj.(*apisv1alpha1.APIBinding); ok {
+				n.notifyFor("APIBinding", binding)
+			}
+		},
+	})
+
+	crdInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition); ok && apihelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+				n.notifyFor("CustomResourceDefinition", crd)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCRD, ok := oldObj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+			newCRD, ok := newObj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+			if apihelpers.IsCRDConditionTrue(oldCRD, apiextensionsv1.Established) != apihelpers.IsCRDConditionTrue(newCRD, apiextensionsv1.Established) ||
+				!equality.Semantic.DeepEqual(oldCRD.Status.AcceptedNames, newCRD.Status.AcceptedNames) ||
+				!equality.Semantic.DeepEqual(servedVersions(oldCRD), servedVersions(newCRD)) {
+				n.notifyFor("CustomResourceDefinition", newCRD)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition); ok {
+				n.notifyFor("CustomResourceDefinition", crd)
+			}
+		},
+	})
+
+	return n
+}
+
+func servedVersions(crd *apiextensionsv1.CustomResourceDefinition) []string {
+	var versions []string
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			versions = append(versions, v.Name)
+		}
+	}
+	return versions
+}
+
+// Subscribe returns a channel receiving the discovery changes of the given logical cluster. Changes
+// are coalesced while the subscriber is busy, i.e. a subscriber only learns that discovery changed
+// since it last received from the channel. The returned function must be called to unsubscribe.
+func (n *DiscoveryNotifier) Subscribe(cluster logicalcluster.Name) (<-chan DiscoveryChangeEvent, func()) {
+	ch := make(chan DiscoveryChangeEvent, 1)
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.subscribers[cluster] == nil {
+		n.subscribers[cluster] = map[chan DiscoveryChangeEvent]struct{}{}
+	}
+	n.subscribers[cluster][ch] = struct{}{}
+
+	return ch, func() {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+		delete(n.subscribers[cluster], ch)
+		if len(n.subscribers[cluster]) == 0 {
+			delete(n.subscribers, cluster)
+		}
+	}
+}
+
+func (n *DiscoveryNotifier) notifyFor(kind string, obj metav1.Object) {
+	n.notify(logicalcluster.From(obj), DiscoveryChangeEvent{
+		Type: DiscoveryChangedEventType,
+		Kind: kind,
+		Name: obj.GetName(),
+	})
+}
+
+func (n *DiscoveryNotifier) notify(cluster logicalcluster.Name, event DiscoveryChangeEvent) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for ch := range n.subscribers[cluster] {
+		select {
+		case ch <- event:
+		default:
+			// a change is pending already
+		}
+	}
+}
+
+// WithDiscoveryChanges serves GET requests to DiscoveryChangesPath in any workspace. The response is a
+// stream of newline-delimited JSON DiscoveryChangeEvents, sent when APIs are added to or removed from
+// the workspace, so that clients can refresh their discovery information and RESTMappers right away.
+// Idle streams receive a heartbeat every 30 seconds.
+//
+// The handler has to run after authorization, i.e. the user needs the "get" verb on the non-resource
+// URL in the workspace. It is granted to all authenticated users by the bootstrap policy.
+func WithDiscoveryChanges(apiHandler http.Handler, notifier *DiscoveryNotifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		info, ok := request.RequestInfoFrom(req.Context())
+		if cluster == nil || cluster.Name.Empty() || cluster.Wildcard || !ok || info.IsResourceRequest || info.Path != DiscoveryChangesPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{}, info.Verb),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			responsewriters.InternalError(w, req, fmt.Errorf("unable to start discovery change stream: flushing not supported"))
+			return
+		}
+
+		events, unsubscribe := notifier.Subscribe(cluster.Name)
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		logger := klog.FromContext(req.Context())
+		encoder := json.NewEncoder(w)
+		heartbeat := time.NewTicker(discoveryHeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			var event DiscoveryChangeEvent
+			select {
+			case <-req.Context().Done():
+				return
+			case <-heartbeat.C:
+				event = DiscoveryChangeEvent{Type: DiscoveryHeartbeatEventType}
+			case event = <-events:
+			}
+			if err := encoder.Encode(event); err != nil {
+				logger.V(4).Info("failed to write discovery change event", "err", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
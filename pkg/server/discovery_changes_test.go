@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestDiscoveryNotifier(t *testing.T) {
+	n := &DiscoveryNotifier{subscribers: map[logicalcluster.Name]map[chan DiscoveryChangeEvent]struct{}{}}
+
+	consumer, unsubscribe := n.Subscribe("consumer")
+	other, unsubscribeOther := n.Subscribe("other")
+	defer unsubscribeOther()
+
+	n.notify("consumer", DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "APIBinding", Name: "a"})
+	n.notify("consumer", DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "APIBinding", Name: "b"})
+
+	require.Equal(t, DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "APIBinding", Name: "a"}, <-consumer, "changes should be coalesced")
+	require.Empty(t, consumer)
+	require.Empty(t, other, "other workspaces should not be notified")
+
+	unsubscribe()
+	n.notify("consumer", DiscoveryChangeEvent{Type: DiscoveryChangedEventType})
+	require.Empty(t, consumer)
+	require.NotContains(t, n.subscribers, logicalcluster.Name("consumer"))
+}
+
+func TestWithDiscoveryChanges(t *testing.T) {
+	n := &DiscoveryNotifier{subscribers: map[logicalcluster.Name]map[chan DiscoveryChangeEvent]struct{}{}}
+	handler := WithDiscoveryChanges(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), n)
+
+	withRequest := func(ctx context.Context, cluster logicalcluster.Name, method, path string) *http.Request {
+		ctx = request.WithCluster(ctx, request.Cluster{Name: cluster})
+		ctx = request.WithRequestInfo(ctx, &request.RequestInfo{Path: path, Verb: "get"})
+		return httptest.NewRequest(method, path, nil).WithContext(ctx)
+	}
+
+	t.Run("other paths are delegated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, withRequest(context.Background(), "consumer", http.MethodGet, "/api"))
+		require.Equal(t, http.StatusTeapot, w.Code)
+	})
+
+	t.Run("only GET is supported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, withRequest(context.Background(), "consumer", http.MethodPost, DiscoveryChangesPath))
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("changes are streamed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			handler.ServeHTTP(w, withRequest(req.Context(), "consumer", req.Method, req.URL.Path))
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+DiscoveryChangesPath, nil)
+		require.NoError(t, err)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// the handler subscribes before sending the headers
+		n.notify("other", DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "APIBinding", Name: "ignored"})
+		n.notify("consumer", DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "CustomResourceDefinition", Name: "widgets.example.io"})
+
+		lines := make(chan []byte)
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				lines <- scanner.Bytes()
+			}
+		}()
+
+		select {
+		case line := <-lines:
+			var event DiscoveryChangeEvent
+			require.NoError(t, json.Unmarshal(line, &event))
+			require.Equal(t, DiscoveryChangeEvent{Type: DiscoveryChangedEventType, Kind: "CustomResourceDefinition", Name: "widgets.example.io"}, event)
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("timed out waiting for the discovery change")
+		}
+	})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/version"
+	"k8s.io/klog/v2"
+
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+// FeatureInfoPath is the non-resource path in every workspace that describes the features of
+// the serving shard, for clients to adapt to them instead of probing.
+const FeatureInfoPath = "/featureinfo"
+
+// FeatureInfo is the response of GET requests to FeatureInfoPath.
+type FeatureInfo struct {
+	// Version is the version of the serving kcp process.
+	Version string `json:"version"`
+	// Shard is the name of the serving shard.
+	Shard string `json:"shard"`
+	// FeatureGates are the known feature gates and whether they are enabled.
+	FeatureGates map[string]bool `json:"featureGates"`
+	// APIGroupVersions are the group versions of the kcp APIs the shard serves, e.g. "apis.kcp.io/v1alpha1",
+	// i.e. the served versions of the system CRDs and of the CRDs bound through APIBindings of kcp groups.
+	// Whether they are available in a workspace depends on its APIBindings.
+	APIGroupVersions []string `json:"apiGroupVersions"`
+	// VirtualWorkspaces are the names of the virtual workspaces served by the shard itself. It is
+	// empty if the virtual workspaces are served by a separate process.
+	VirtualWorkspaces []string `json:"virtualWorkspaces,omitempty"`
+	// Topology describes the shards of the deployment.
+	Topology FeatureInfoTopology `json:"topology"`
+}
+
+// FeatureInfoTopology describes the shards of a deployment.
+type FeatureInfoTopology struct {
+	// Version changes whenever a shard is added or removed, or the URLs of a shard change.
+	Version string `json:"version"`
+	// Shards is the number of shards.
+	Shards int `json:"shards"`
+}
+
+// featureInfoProvider collects the FeatureInfo of a shard.
+type featureInfoProvider struct {
+	shardName string
+	crdLister kcpapiextensionsv1listers.CustomResourceDefinitionClusterLister
+	// virtualWorkspaces returns the names of the virtual workspaces served in-process.
+	virtualWorkspaces func() []string
+	shardLister       corev1alpha1listers.ShardClusterLister
+}
+
+func (p *featureInfoProvider) featureInfo() (*FeatureInfo, error) {
+	info := &FeatureInfo{
+		Version:      version.Get().GitVersion,
+		Shard:        p.shardName,
+		FeatureGates: map[string]bool{},
+	}
+	for _, name := range kcpfeatures.KnownFeatures() {
+		info.FeatureGates[name] = kcpfeatures.DefaultFeatureGate.Enabled(featuregate.Feature(name))
+	}
+	groupVersions := sets.NewString()
+	for _, clusterName := range []logicalcluster.Name{SystemCRDClusterName, apibinding.SystemBoundCRDsClusterName} {
+		crds, err := p.crdLister.Cluster(clusterName).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, crd := range crds {
+			if crd.Spec.Group != "kcp.io" && !strings.HasSuffix(crd.Spec.Group, ".kcp.io") {
+				continue
+			}
+			for _, version := range crd.Spec.Versions {
+				if version.Served {
+					groupVersions.Insert(schema.GroupVersion{Group: crd.Spec.Group, Version: version.Name}.String())
+				}
+			}
+		}
+	}
+	info.APIGroupVersions = groupVersions.List()
+	if p.virtualWorkspaces != nil {
+		info.VirtualWorkspaces = p.virtualWorkspaces()
+		sort.Strings(info.VirtualWorkspaces)
+	}
+
+	shards, err := p.shardLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Name < shards[j].Name })
+	h := sha256.New()
+	for _, shard := range shards {
+		for _, s := range []string{shard.Name, shard.Spec.BaseURL, shard.Spec.ExternalURL, shard.Spec.VirtualWorkspaceURL} {
+			h.Write([]byte(s))
+			h.Write([]byte{0})
+		}
+	}
+	info.Topology = FeatureInfoTopology{
+		Version: hex.EncodeToString(h.Sum(nil))[:16],
+		Shards:  len(shards),
+	}
+
+	return info, nil
+}
+
+// WithFeatureInfo serves FeatureInfoPath in every workspace. The information is the same in all
+// workspaces as it describes the serving shard.
+func WithFeatureInfo(apiHandler http.Handler, provider *featureInfoProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		info, ok := request.RequestInfoFrom(req.Context())
+		if cluster == nil || cluster.Name.Empty() || cluster.Wildcard || !ok || info.IsResourceRequest || info.Path != FeatureInfoPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{}, info.Verb),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		featureInfo, err := provider.featureInfo()
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(featureInfo); err != nil {
+			klog.FromContext(req.Context()).Error(err, "failed to write feature info response")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	corev1alpha1listers "github.com/kcp-dev/kcp/sdk/client/listers/core/v1alpha1"
+)
+
+func TestWithFeatureInfo(t *testing.T) {
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	addShard := func(name, baseURL string) {
+		require.NoError(t, indexer.Add(&corev1alpha1.Shard{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root"}},
+			Spec:       corev1alpha1.ShardSpec{BaseURL: baseURL},
+		}))
+	}
+	addShard("root", "https://root:6443")
+
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	for _, crd := range []struct {
+		cluster  logicalcluster.Name
+		name     string
+		group    string
+		versions []apiextensionsv1.CustomResourceDefinitionVersion
+	}{
+		{SystemCRDClusterName, "apibindings.apis.kcp.io", "apis.kcp.io", []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true}}},
+		{apibinding.SystemBoundCRDsClusterName, "uid-1", "tenancy.kcp.io", []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true}, {Name: "v1alpha0"}}},
+		{apibinding.SystemBoundCRDsClusterName, "uid-2", "example.io", []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}}},
+		{"consumer", "things.kcp.io", "kcp.io", []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}}},
+	} {
+		require.NoError(t, crdIndexer.Add(&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crd.name, Annotations: map[string]string{logicalcluster.AnnotationKey: crd.cluster.String()}},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: crd.group, Versions: crd.versions},
+		}))
+	}
+
+	provider := &featureInfoProvider{
+		shardName:         "root",
+		crdLister:         kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+		virtualWorkspaces: func() []string { return []string{"initializingworkspaces", "apiexport"} },
+		shardLister:       corev1alpha1listers.NewShardClusterLister(indexer),
+	}
+	handler := WithFeatureInfo(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), provider)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		ctx := request.WithCluster(context.Background(), request.Cluster{Name: "consumer"})
+		ctx = request.WithRequestInfo(ctx, &request.RequestInfo{Path: path, Verb: "get"})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil).WithContext(ctx))
+		return w
+	}
+	get := func() FeatureInfo {
+		w := serve(http.MethodGet, FeatureInfoPath)
+		require.Equal(t, http.StatusOK, w.Code)
+		var info FeatureInfo
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+		return info
+	}
+
+	require.Equal(t, http.StatusTeapot, serve(http.MethodGet, "/api").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, FeatureInfoPath).Code)
+
+	info := get()
+	require.Equal(t, "root", info.Shard)
+	require.Equal(t, []string{"apis.kcp.io/v1alpha1", "tenancy.kcp.io/v1alpha1"}, info.APIGroupVersions)
+	require.Equal(t, []string{"apiexport", "initializingworkspaces"}, info.VirtualWorkspaces)
+	require.Contains(t, info.FeatureGates, "KCPLocationAPI")
+	require.Equal(t, 1, info.Topology.Shards)
+	require.NotEmpty(t, info.Topology.Version)
+	require.Equal(t, info.Topology, get().Topology, "topology version should be stable")
+
+	addShard("beta", "https://beta:6443")
+	changed := get()
+	require.Equal(t, 2, changed.Topology.Shards)
+	require.NotEqual(t, info.Topology.Version, changed.Topology.Version)
+}
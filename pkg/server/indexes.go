@@ -21,6 +21,7 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identityrotation"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 )
 
@@ -52,6 +53,9 @@ func indexAPIBindingByIdentityGroupResource(obj interface{}) ([]string, error) {
 
 	for _, r := range apiBinding.Status.BoundResources {
 		ret = append(ret, identityGroupResourceKeyFunc(r.Schema.IdentityHash, r.Group, r.Resource))
+		if retired := identityrotation.RetiredIdentity(apiBinding, r.Schema.IdentityHash); retired != "" {
+			ret = append(ret, identityGroupResourceKeyFunc(retired, r.Group, r.Resource))
+		}
 	}
 
 	return ret, nil
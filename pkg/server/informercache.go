@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/informer/diskcache"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+// diskBackedInformers are the wildcard informers on the cache server that can keep their objects
+// on disk. Each registers its informer with the cache informer factories, before the informer is
+// requested by anybody else.
+var diskBackedInformers = map[schema.GroupResource]func(c *Config, store *diskcache.Cache){
+	apisv1alpha1.Resource("apiexports"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKcpSharedInformerFactory.InformerFor(&apisv1alpha1.APIExport{}, func(client kcpclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/apiexports.apis.kcp.io", diskcache.ListWatch(client.ApisV1alpha1().APIExports().List, client.ApisV1alpha1().APIExports().Watch), &apisv1alpha1.APIExport{}, resyncPeriod)
+		})
+	},
+	apisv1alpha1.Resource("apiresourceschemas"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKcpSharedInformerFactory.InformerFor(&apisv1alpha1.APIResourceSchema{}, func(client kcpclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/apiresourceschemas.apis.kcp.io", diskcache.ListWatch(client.ApisV1alpha1().APIResourceSchemas().List, client.ApisV1alpha1().APIResourceSchemas().Watch), &apisv1alpha1.APIResourceSchema{}, resyncPeriod)
+		})
+	},
+	apisv1alpha1.Resource("apiconversions"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKcpSharedInformerFactory.InformerFor(&apisv1alpha1.APIConversion{}, func(client kcpclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/apiconversions.apis.kcp.io", diskcache.ListWatch(client.ApisV1alpha1().APIConversions().List, client.ApisV1alpha1().APIConversions().Watch), &apisv1alpha1.APIConversion{}, resyncPeriod)
+		})
+	},
+	corev1alpha1.Resource("shards"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKcpSharedInformerFactory.InformerFor(&corev1alpha1.Shard{}, func(client kcpclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/shards.core.kcp.io", diskcache.ListWatch(client.CoreV1alpha1().Shards().List, client.CoreV1alpha1().Shards().Watch), &corev1alpha1.Shard{}, resyncPeriod)
+		})
+	},
+	tenancyv1alpha1.Resource("workspacetypes"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKcpSharedInformerFactory.InformerFor(&tenancyv1alpha1.WorkspaceType{}, func(client kcpclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/workspacetypes.tenancy.kcp.io", diskcache.ListWatch(client.TenancyV1alpha1().WorkspaceTypes().List, client.TenancyV1alpha1().WorkspaceTypes().Watch), &tenancyv1alpha1.WorkspaceType{}, resyncPeriod)
+		})
+	},
+	rbacv1.Resource("clusterroles"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKubeSharedInformerFactory.InformerFor(&rbacv1.ClusterRole{}, func(client kcpkubernetesclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/clusterroles.rbac.authorization.k8s.io", diskcache.ListWatch(client.RbacV1().ClusterRoles().List, client.RbacV1().ClusterRoles().Watch), &rbacv1.ClusterRole{}, resyncPeriod)
+		})
+	},
+	rbacv1.Resource("clusterrolebindings"): func(c *Config, store *diskcache.Cache) {
+		c.CacheKubeSharedInformerFactory.InformerFor(&rbacv1.ClusterRoleBinding{}, func(client kcpkubernetesclientset.ClusterInterface, resyncPeriod time.Duration) kcpcache.ScopeableSharedIndexInformer {
+			return store.NewInformer("cache/clusterrolebindings.rbac.authorization.k8s.io", diskcache.ListWatch(client.RbacV1().ClusterRoleBindings().List, client.RbacV1().ClusterRoleBindings().Watch), &rbacv1.ClusterRoleBinding{}, resyncPeriod)
+		})
+	},
+}
+
+// installDiskBackedInformers replaces the in-memory wildcard informers of the cache server for the
+// given resources by informers keeping their objects in an on-disk store in dir.
+func installDiskBackedInformers(c *Config, resources []string, dir string) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	installs := make([]func(c *Config, store *diskcache.Cache), 0, len(resources))
+	for _, resource := range resources {
+		install, found := diskBackedInformers[schema.ParseGroupResource(resource)]
+		if !found {
+			return fmt.Errorf("disk-backed informers are not supported for %q", resource)
+		}
+		installs = append(installs, install)
+	}
+
+	store, err := diskcache.Open(dir)
+	if err != nil {
+		return err
+	}
+	for _, install := range installs {
+		install(c, store)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -31,27 +32,69 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/index"
 	indexrewriters "github.com/kcp-dev/kcp/pkg/index/rewriters"
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	reconcilerworkspace "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspace"
 	"github.com/kcp-dev/kcp/pkg/server/filters"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 	corev1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/core/v1alpha1"
 	tenancyv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/client/routing"
 )
 
+const workspacesByLogicalCluster = "workspacesByLogicalCluster"
+
+func indexWorkspacesByLogicalCluster(obj interface{}) ([]string, error) {
+	ws, ok := obj.(*tenancyv1alpha1.Workspace)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a Workspace, but is %T", obj)
+	}
+	if ws.Spec.Cluster == "" {
+		return []string{}, nil
+	}
+	return []string{ws.Spec.Cluster}, nil
+}
+
 // WithLocalProxy returns a handler with a local-only mini-front-proxy. It is
 // able to translate logical clusters with the data on the local shard. This is
 // mainly interesting for standalone mode, without a real front-proxy in-front.
+//
+// Requests for logical clusters that are scheduled to another shard by their
+// Workspace on this shard, e.g. after they have been moved, are answered with
+// 421 Misdirected Request and the routing.ShardMovedHeader naming the other
+// shard, such that clients can re-resolve and retry. Unlike 429, client-go does
+// not retry 421 by itself, so unmodified clients fail fast.
 func WithLocalProxy(
 	handler http.Handler,
 	shardName, shardBaseURL string,
 	workspaceInformer tenancyv1alpha1informers.WorkspaceClusterInformer,
 	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+	shardInformer corev1alpha1informers.ShardClusterInformer,
 ) http.Handler {
 	indexState := index.New([]index.PathRewriter{
 		indexrewriters.UserRewriter,
 	})
 	indexState.UpsertShard(shardName, shardBaseURL)
 
+	indexers.AddIfNotPresentOrDie(workspaceInformer.Informer().GetIndexer(), cache.Indexers{
+		workspacesByLogicalCluster: indexWorkspacesByLogicalCluster,
+	})
+	moved := &movedClusters{
+		shardNameHash: reconcilerworkspace.ByBase36Sha224NameValue(shardName),
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			return workspaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		getWorkspacesByLogicalCluster: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.Workspace, error) {
+			return indexers.ByIndex[*tenancyv1alpha1.Workspace](workspaceInformer.Informer().GetIndexer(), workspacesByLogicalCluster, clusterName.String())
+		},
+		listShards: func() ([]*corev1alpha1.Shard, error) {
+			return shardInformer.Lister().List(labels.Everything())
+		},
+	}
+
 	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			ws := obj.(*tenancyv1alpha1.Workspace)
@@ -127,6 +170,11 @@ func WithLocalProxy(
 
 		// first pure names
 		if name, isName := path.Name(); isName {
+			if otherShard, found := moved.shardOfCluster(name); found {
+				logger.WithValues("cluster", name, "shard", otherShard).V(4).Info("cluster has moved to another shard")
+				shardMoved(w, otherShard)
+				return
+			}
 			cluster.Name = name
 			handler.ServeHTTP(w, req.WithContext(request.WithCluster(ctx, cluster)))
 			return
@@ -146,6 +194,17 @@ func WithLocalProxy(
 		clusterName, isName := path.Name()
 
 		if !isName && !foundInIndex {
+			// the parent might be here, with the workspace scheduled elsewhere.
+			if parent, workspaceName := path.Split(); !parent.Empty() {
+				if parentShard, parentCluster, found := indexState.Lookup(parent); found && parentShard == shardName {
+					if otherShard, found := moved.shardOfWorkspace(parentCluster, workspaceName); found {
+						logger.WithValues("cluster", path, "shard", otherShard).V(4).Info("cluster has moved to another shard")
+						shardMoved(w, otherShard)
+						return
+					}
+				}
+			}
+
 			// No rewrite, depend on the handler chain to do the right thing, like 403 or 404.
 			cluster.Name = logicalcluster.Name(path.String())
 			logger.WithValues("cluster", cluster.Name).Info("cluster not found")
@@ -167,3 +226,65 @@ func WithLocalProxy(
 		handler.ServeHTTP(w, req.WithContext(ctx))
 	})
 }
+
+// shardMoved tells the client to retry the request against the given shard.
+func shardMoved(w http.ResponseWriter, shard string) {
+	w.Header().Set(routing.ShardMovedHeader, shard)
+	http.Error(w, "Not found on this shard", http.StatusMisdirectedRequest)
+}
+
+// movedClusters finds the shards of logical clusters that are not on this shard,
+// but whose Workspace on this shard schedules them to another shard.
+type movedClusters struct {
+	shardNameHash string
+
+	getLogicalCluster             func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+	getWorkspace                  func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error)
+	getWorkspacesByLogicalCluster func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.Workspace, error)
+	listShards                    func() ([]*corev1alpha1.Shard, error)
+}
+
+// shardOfCluster returns the other shard of the given logical cluster, if it is not on this shard.
+func (m *movedClusters) shardOfCluster(clusterName logicalcluster.Name) (string, bool) {
+	if _, err := m.getLogicalCluster(clusterName); err == nil || !apierrors.IsNotFound(err) {
+		return "", false
+	}
+	workspaces, err := m.getWorkspacesByLogicalCluster(clusterName)
+	if err != nil {
+		return "", false
+	}
+	for _, ws := range workspaces {
+		if shard, found := m.scheduledShard(ws); found {
+			return shard, true
+		}
+	}
+	return "", false
+}
+
+// shardOfWorkspace returns the other shard of the logical cluster of the given workspace.
+func (m *movedClusters) shardOfWorkspace(parent logicalcluster.Name, name string) (string, bool) {
+	ws, err := m.getWorkspace(parent, name)
+	if err != nil {
+		return "", false
+	}
+	return m.scheduledShard(ws)
+}
+
+// scheduledShard returns the name of the shard the workspace is scheduled to, if it is
+// known and not this shard.
+func (m *movedClusters) scheduledShard(ws *tenancyv1alpha1.Workspace) (string, bool) {
+	hash, found := ws.Annotations[reconcilerworkspace.WorkspaceShardHashAnnotationKey]
+	if !found || hash == m.shardNameHash || ws.Spec.Cluster == "" {
+		return "", false
+	}
+	shards, err := m.listShards()
+	if err != nil {
+		return "", false
+	}
+	for _, shard := range shards {
+		if reconcilerworkspace.ByBase36Sha224NameValue(shard.Name) == hash {
+			return shard.Name, true
+		}
+	}
+	return "", false
+}
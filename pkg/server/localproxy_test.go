@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	reconcilerworkspace "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspace"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+)
+
+func TestMovedClusters(t *testing.T) {
+	workspace := func(name, cluster, shard string) *tenancyv1alpha1.Workspace {
+		ws := &tenancyv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+			Spec:       tenancyv1alpha1.WorkspaceSpec{Cluster: cluster},
+		}
+		if shard != "" {
+			ws.Annotations[reconcilerworkspace.WorkspaceShardHashAnnotationKey] = reconcilerworkspace.ByBase36Sha224NameValue(shard)
+		}
+		return ws
+	}
+	workspaces := map[string]*tenancyv1alpha1.Workspace{
+		"local":       workspace("local", "local-cluster", "alpha"),
+		"moved":       workspace("moved", "moved-cluster", "beta"),
+		"unknown":     workspace("unknown", "unknown-cluster", "gamma"),
+		"unscheduled": workspace("unscheduled", "", ""),
+	}
+	localClusters := map[logicalcluster.Name]bool{"local-cluster": true}
+
+	moved := &movedClusters{
+		shardNameHash: reconcilerworkspace.ByBase36Sha224NameValue("alpha"),
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			if localClusters[clusterName] {
+				return &corev1alpha1.LogicalCluster{}, nil
+			}
+			return nil, apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), corev1alpha1.LogicalClusterName)
+		},
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.Workspace, error) {
+			if ws, found := workspaces[name]; found && clusterName == "root" {
+				return ws, nil
+			}
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("workspaces"), name)
+		},
+		getWorkspacesByLogicalCluster: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.Workspace, error) {
+			var found []*tenancyv1alpha1.Workspace
+			for _, ws := range workspaces {
+				if ws.Spec.Cluster == clusterName.String() {
+					found = append(found, ws)
+				}
+			}
+			return found, nil
+		},
+		listShards: func() ([]*corev1alpha1.Shard, error) {
+			return []*corev1alpha1.Shard{
+				{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "beta"}},
+			}, nil
+		},
+	}
+
+	tests := map[string]struct {
+		parent    logicalcluster.Name
+		workspace string
+		cluster   logicalcluster.Name
+		wantShard string
+		wantFound bool
+	}{
+		"workspace on this shard":          {parent: "root", workspace: "local"},
+		"workspace moved to another shard": {parent: "root", workspace: "moved", wantShard: "beta", wantFound: true},
+		"workspace scheduled to unknown":   {parent: "root", workspace: "unknown"},
+		"workspace not scheduled":          {parent: "root", workspace: "unscheduled"},
+		"workspace not found":              {parent: "root", workspace: "missing"},
+		"cluster on this shard":            {cluster: "local-cluster"},
+		"cluster moved to another shard":   {cluster: "moved-cluster", wantShard: "beta", wantFound: true},
+		"cluster scheduled to unknown":     {cluster: "unknown-cluster"},
+		"cluster without workspace":        {cluster: "missing-cluster"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var shard string
+			var found bool
+			if tt.workspace != "" {
+				shard, found = moved.shardOfWorkspace(tt.parent, tt.workspace)
+			} else {
+				shard, found = moved.shardOfCluster(tt.cluster)
+			}
+			require.Equal(t, tt.wantFound, found)
+			require.Equal(t, tt.wantShard, shard)
+		})
+	}
+}
@@ -22,12 +22,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
 	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 	kcmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/ratelimiter"
 )
 
 type Controllers struct {
@@ -35,6 +39,26 @@ type Controllers struct {
 	IndividuallyEnabled []string
 
 	SAController kcmoptions.SAControllerOptions
+
+	// TrustedCABundleFiles are PEM encoded CA bundle files published in every namespace of every workspace,
+	// together with the CA of the kcp serving certificate.
+	TrustedCABundleFiles []string
+
+	// APIBindingSchemaRollout decides whether newer resource schemas of APIExports are rolled out to APIBindings
+	// automatically, or only when pinned.
+	APIBindingSchemaRollout string
+
+	// RateLimits are the workqueue rate limits of individual controllers as
+	// <controller>=<base-delay>:<max-delay>:<qps>[:<burst>], with "*" as controller for all others.
+	RateLimits []string
+
+	// WorkspaceSoftDeletionRetention is how long deleted Workspaces are kept in the Trashed phase, and can be
+	// restored, before they are deleted for real. Zero disables soft deletion.
+	WorkspaceSoftDeletionRetention time.Duration
+
+	// WorkspaceDeletionReportRetention is how long the reports of deleted Workspaces are retained as ConfigMaps
+	// in the parent workspace. Zero only emits them as events.
+	WorkspaceDeletionReportRetention time.Duration
 }
 
 var kcmDefaults *kcmoptions.KubeControllerManagerOptions
@@ -53,6 +77,8 @@ func NewControllers() *Controllers {
 		EnableAll: true,
 
 		SAController: *kcmDefaults.SAController,
+
+		APIBindingSchemaRollout: "Automatic",
 	}
 }
 
@@ -63,6 +89,40 @@ func (c *Controllers) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkHidden("unsupported-run-individual-controllers") //nolint:errcheck
 
 	c.SAController.AddFlags(fs)
+
+	fs.StringSliceVar(&c.TrustedCABundleFiles, "trusted-ca-bundle-files", c.TrustedCABundleFiles, "PEM encoded CA bundle files, e.g. of the front-proxy or organizational CAs, "+
+		"to publish together with the kcp serving CA as kcp-ca-bundle.crt ConfigMap in every namespace of every workspace. The files are reloaded when they change.")
+
+	fs.StringVar(&c.APIBindingSchemaRollout, "apibinding-schema-rollout", c.APIBindingSchemaRollout, "How newer resource schemas of APIExports are rolled out to bound resources of APIBindings. "+
+		"With Automatic, bound resources are served with the latest schemas unless pinned in the APIBinding. With Manual, they keep their bound schemas until a newer one is pinned. One of Automatic or Manual.")
+
+	fs.StringSliceVar(&c.RateLimits, "controller-rate-limits", c.RateLimits, "Workqueue rate limits of controllers as <controller>=<base-delay>:<max-delay>:<qps>[:<burst>], e.g. kcp-apibinding=10ms:5m:50:500. "+
+		"Failed keys are retried with exponential back-off from base to max delay, and all keys are limited by QPS and burst, which defaults to ten times the QPS. "+
+		"The controller * applies to all controllers without their own rate limit. Controllers not listed use 5ms:1000s:10:100.")
+	fs.DurationVar(&c.WorkspaceSoftDeletionRetention, "workspace-soft-deletion-retention", c.WorkspaceSoftDeletionRetention, "How long deleted ready Workspaces are kept in the Trashed phase "+
+		"before their logical clusters are deleted. During that time they can be restored with kubectl kcp workspace restore. Zero disables soft deletion.")
+	fs.DurationVar(&c.WorkspaceDeletionReportRetention, "workspace-deletion-report-retention", c.WorkspaceDeletionReportRetention, "How long the reports of deleted Workspaces "+
+		"are retained as deletion-report-<logical-cluster> ConfigMaps next to the Workspace in the parent workspace. Zero only emits them as events, subject to --event-ttl.")
+}
+
+// RateLimitConfigs returns the parsed RateLimits by controller name.
+func (c *Controllers) RateLimitConfigs() (map[string]ratelimiter.Config, error) {
+	configs := make(map[string]ratelimiter.Config, len(c.RateLimits))
+	for _, rl := range c.RateLimits {
+		name, limit, found := strings.Cut(rl, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid controller rate limit %q, expected <controller>=<base-delay>:<max-delay>:<qps>[:<burst>]", rl)
+		}
+		if _, dup := configs[name]; dup {
+			return nil, fmt.Errorf("duplicate controller rate limit for %q", name)
+		}
+		config, err := ratelimiter.Parse(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit of controller %q: %w", name, err)
+		}
+		configs[name] = config
+	}
+	return configs, nil
 }
 
 func (c *Controllers) Complete(rootDir string) error {
@@ -98,5 +158,27 @@ func (c *Controllers) Validate() []error {
 		errs = append(errs, saErrs...)
 	}
 
+	if c.APIBindingSchemaRollout != "Automatic" && c.APIBindingSchemaRollout != "Manual" {
+		errs = append(errs, fmt.Errorf("--apibinding-schema-rollout must be Automatic or Manual, got %q", c.APIBindingSchemaRollout))
+	}
+
+	if _, err := c.RateLimitConfigs(); err != nil {
+		errs = append(errs, fmt.Errorf("--controller-rate-limits: %w", err))
+	}
+
+	if c.WorkspaceSoftDeletionRetention < 0 {
+		errs = append(errs, fmt.Errorf("--workspace-soft-deletion-retention must not be negative, got %s", c.WorkspaceSoftDeletionRetention))
+	}
+
+	if c.WorkspaceDeletionReportRetention < 0 {
+		errs = append(errs, fmt.Errorf("--workspace-deletion-report-retention must not be negative, got %s", c.WorkspaceDeletionReportRetention))
+	}
+
+	for _, f := range c.TrustedCABundleFiles {
+		if _, err := os.Stat(f); err != nil {
+			errs = append(errs, fmt.Errorf("--trusted-ca-bundle-files: %w", err))
+		}
+	}
+
 	return errs
 }
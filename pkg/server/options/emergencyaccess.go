@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/group"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	authenticatorunion "k8s.io/apiserver/pkg/authentication/request/union"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/warning"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// An emergency admin being member of the privileged system group, like the shard admin,
+	// but only for a limited time after the break-glass credential has been used first.
+	emergencyAdminUserName = "system:kcp:emergency-admin"
+
+	// EmergencyAccessAuditAnnotationKey is the audit annotation added to every request
+	// authenticated with the break-glass credential.
+	EmergencyAccessAuditAnnotationKey = "authentication.kcp.io/emergency-access"
+	// EmergencyAccessExpiresAuditAnnotationKey is the audit annotation holding the time
+	// the break-glass access expires.
+	EmergencyAccessExpiresAuditAnnotationKey = "authentication.kcp.io/emergency-access-expires"
+)
+
+var (
+	emergencyAccessRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "kcp_emergency_access_requests_total",
+			Help:           "Number of requests authenticated with the break-glass emergency access credential, by result.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+	registerEmergencyAccessMetrics sync.Once
+)
+
+// EmergencyAccess configures a sealed break-glass credential for the shard. The credential is
+// generated once and written into a kubeconfig that is expected to be moved to a safe place by
+// the operator. When it is used for the first time, it grants shard admin access for a limited
+// duration. Afterwards it is rejected until a new credential is sealed by removing the store.
+type EmergencyAccess struct {
+	// KubeConfigPath is the path the sealed kubeconfig is written to when a new credential is
+	// generated. Empty disables emergency access.
+	KubeConfigPath string
+	// TokenStorePath is the path of the file holding the credential hash and its activation time.
+	TokenStorePath string
+	// Duration is how long the credential grants access after its first use.
+	Duration time.Duration
+}
+
+func NewEmergencyAccess(rootDir string) *EmergencyAccess {
+	return &EmergencyAccess{
+		TokenStorePath: filepath.Join(rootDir, ".emergency-token-store"),
+		Duration:       time.Hour,
+	}
+}
+
+func (s *EmergencyAccess) Validate() []error {
+	if s == nil || s.KubeConfigPath == "" {
+		return nil
+	}
+
+	errs := []error{}
+
+	if s.TokenStorePath == "" {
+		errs = append(errs, fmt.Errorf("--emergency-access-kubeconfig-path requires --emergency-access-token-store-path"))
+	}
+	if s.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("--emergency-access-duration must be positive"))
+	}
+
+	return errs
+}
+
+func (s *EmergencyAccess) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.KubeConfigPath, "emergency-access-kubeconfig-path", s.KubeConfigPath,
+		"Path to which a sealed break-glass kubeconfig is written when no emergency access credential exists yet. "+
+			"The credential grants shard admin access for --emergency-access-duration after its first use. "+
+			"Empty disables emergency access. If this is relative, it is relative to --root-directory.")
+	fs.StringVar(&s.TokenStorePath, "emergency-access-token-store-path", s.TokenStorePath,
+		"Path to which the emergency access token hash and its activation time are written. "+
+			"Remove it to seal a new credential. If this is relative, it is relative to --root-directory.")
+	fs.DurationVar(&s.Duration, "emergency-access-duration", s.Duration,
+		"Duration the emergency access credential grants shard admin access after its first use.")
+}
+
+// emergencyTokenStore is the persisted state of the break-glass credential.
+type emergencyTokenStore struct {
+	TokenHash string `json:"tokenHash"`
+	// ActivationTimestamp is the time of the first use of the credential.
+	ActivationTimestamp *time.Time `json:"activationTimestamp,omitempty"`
+}
+
+// ApplyTo adds the break-glass authenticator to the config. It returns a new token if none has been
+// sealed before, i.e. if the token store does not exist yet. Otherwise the returned token is empty.
+func (s *EmergencyAccess) ApplyTo(config *genericapiserver.Config) (emergencyToken string, err error) {
+	if s == nil || s.KubeConfigPath == "" {
+		return "", nil
+	}
+
+	store, err := readEmergencyTokenStore(s.TokenStorePath)
+	if os.IsNotExist(err) {
+		emergencyToken = uuid.New().String()
+		sum := sha256.Sum256([]byte(emergencyToken))
+		store = &emergencyTokenStore{TokenHash: hex.EncodeToString(sum[:])}
+		if err := writeEmergencyTokenStore(s.TokenStorePath, store); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	tokenHash, err := hex.DecodeString(store.TokenHash)
+	if err != nil {
+		return "", fmt.Errorf("invalid emergency access token store %q: %w", s.TokenStorePath, err)
+	}
+
+	registerEmergencyAccessMetrics.Do(func() {
+		legacyregistry.MustRegister(emergencyAccessRequests)
+	})
+
+	auth := &emergencyAuthenticator{
+		storePath:  s.TokenStorePath,
+		tokenHash:  tokenHash,
+		duration:   s.Duration,
+		activation: store.ActivationTimestamp,
+		now:        time.Now,
+	}
+	newAuthenticator := group.NewAuthenticatedGroupAdder(bearertoken.New(authenticator.WrapAudienceAgnosticToken(config.Authentication.APIAudiences, auth)))
+	config.Authentication.Authenticator = authenticatorunion.New(newAuthenticator, config.Authentication.Authenticator)
+
+	return emergencyToken, nil
+}
+
+// WriteKubeConfig writes the sealed kubeconfig if a new token has been generated by ApplyTo.
+func (s *EmergencyAccess) WriteKubeConfig(config genericapiserver.CompletedConfig, emergencyToken string) error {
+	if s == nil || s.KubeConfigPath == "" || emergencyToken == "" {
+		return nil
+	}
+
+	externalCACert, _ := config.SecureServing.Cert.CurrentCertKeyContent()
+	kubeConfig := clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			emergencyAdminUserName: {Token: emergencyToken},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"base": {
+				Server:                   fmt.Sprintf("https://%s", config.ExternalAddress),
+				CertificateAuthorityData: externalCACert,
+			},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"emergency": {Cluster: "base", AuthInfo: emergencyAdminUserName},
+		},
+		CurrentContext: "emergency",
+	}
+	if err := clientcmd.WriteToFile(kubeConfig, s.KubeConfigPath); err != nil {
+		return err
+	}
+
+	klog.Background().Info("sealed a new emergency access credential, move the kubeconfig to a safe place", "path", s.KubeConfigPath, "duration", s.Duration)
+	return nil
+}
+
+// emergencyAuthenticator authenticates the break-glass token. The first use activates the token
+// for the configured duration. Every use is logged, counted and annotated in the audit log.
+type emergencyAuthenticator struct {
+	storePath string
+	tokenHash []byte
+	duration  time.Duration
+	now       func() time.Time
+
+	lock       sync.Mutex
+	activation *time.Time
+}
+
+var _ authenticator.Token = &emergencyAuthenticator{}
+
+func (a *emergencyAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	sum := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(sum[:], a.tokenHash) != 1 {
+		return nil, false, nil
+	}
+
+	logger := klog.FromContext(ctx).WithValues("user", emergencyAdminUserName)
+	if info, ok := request.RequestInfoFrom(ctx); ok {
+		logger = logger.WithValues("verb", info.Verb, "path", info.Path)
+	}
+
+	expires, err := a.activate(logger)
+	if err != nil {
+		emergencyAccessRequests.WithLabelValues("error").Inc()
+		return nil, false, err
+	}
+	if !a.now().Before(expires) {
+		emergencyAccessRequests.WithLabelValues("expired").Inc()
+		logger.Error(nil, "BREAK-GLASS: rejected expired emergency access credential", "expired", expires)
+		return nil, false, errors.New("emergency access credential expired")
+	}
+
+	emergencyAccessRequests.WithLabelValues("granted").Inc()
+	logger.Info("BREAK-GLASS: request authenticated with emergency access credential", "expires", expires)
+	audit.AddAuditAnnotations(ctx,
+		EmergencyAccessAuditAnnotationKey, "true",
+		EmergencyAccessExpiresAuditAnnotationKey, expires.UTC().Format(time.RFC3339),
+	)
+	warning.AddWarning(ctx, "", fmt.Sprintf("emergency access credential used, access is audited and expires at %s", expires.UTC().Format(time.RFC3339)))
+
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   emergencyAdminUserName,
+			Groups: []string{user.SystemPrivilegedGroup},
+		},
+	}, true, nil
+}
+
+// activate persists the activation time on first use, and returns the expiry of the credential.
+func (a *emergencyAuthenticator) activate(logger klog.Logger) (time.Time, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.activation == nil {
+		now := a.now()
+		store := &emergencyTokenStore{TokenHash: hex.EncodeToString(a.tokenHash), ActivationTimestamp: &now}
+		if err := writeEmergencyTokenStore(a.storePath, store); err != nil {
+			// fail closed: an activation that is not persisted would restart the clock on restart.
+			return time.Time{}, fmt.Errorf("failed to activate emergency access credential: %w", err)
+		}
+		a.activation = &now
+		logger.Error(nil, "BREAK-GLASS: emergency access credential activated, granting shard admin access", "expires", now.Add(a.duration))
+	}
+
+	return a.activation.Add(a.duration), nil
+}
+
+func readEmergencyTokenStore(path string) (*emergencyTokenStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var store emergencyTokenStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("invalid emergency access token store %q: %w", path, err)
+	}
+	return &store, nil
+}
+
+func writeEmergencyTokenStore(path string, store *emergencyTokenStore) error {
+	data, err := json.Marshal(store)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+)
+
+func TestEmergencyAccess(t *testing.T) {
+	dir := t.TempDir()
+	o := NewEmergencyAccess(dir)
+	o.KubeConfigPath = filepath.Join(dir, "emergency.kubeconfig")
+
+	config := &genericapiserver.Config{}
+	config.Authentication.Authenticator = authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		return nil, false, nil
+	})
+	token, err := o.ApplyTo(config)
+	require.NoError(t, err)
+	require.NotEmpty(t, token, "expected a new sealed token")
+
+	again, err := o.ApplyTo(&genericapiserver.Config{Authentication: config.Authentication})
+	require.NoError(t, err)
+	require.Empty(t, again, "expected the sealed token to be reused")
+
+	store, err := readEmergencyTokenStore(o.TokenStorePath)
+	require.NoError(t, err)
+	require.Nil(t, store.ActivationTimestamp, "expected the token not to be activated before first use")
+
+	ctx := context.Background()
+	bearerRequest := func(token string) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/api", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	_, ok, _ := config.Authentication.Authenticator.AuthenticateRequest(bearerRequest("wrong"))
+	require.False(t, ok)
+
+	resp, ok, err := config.Authentication.Authenticator.AuthenticateRequest(bearerRequest(token))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, emergencyAdminUserName, resp.User.GetName())
+	require.Contains(t, resp.User.GetGroups(), user.SystemPrivilegedGroup)
+
+	store, err = readEmergencyTokenStore(o.TokenStorePath)
+	require.NoError(t, err)
+	require.NotNil(t, store.ActivationTimestamp, "expected the activation to be persisted")
+
+	// a restart keeps the activation time, and the token expires after the duration.
+	auth := &emergencyAuthenticator{
+		storePath:  o.TokenStorePath,
+		duration:   o.Duration,
+		activation: store.ActivationTimestamp,
+		now:        func() time.Time { return store.ActivationTimestamp.Add(o.Duration - time.Second) },
+	}
+	auth.tokenHash, err = hex.DecodeString(store.TokenHash)
+	require.NoError(t, err)
+
+	_, ok, err = auth.AuthenticateToken(ctx, token)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	auth.now = func() time.Time { return store.ActivationTimestamp.Add(o.Duration) }
+	_, ok, err = auth.AuthenticateToken(ctx, token)
+	require.Error(t, err)
+	require.False(t, ok)
+}
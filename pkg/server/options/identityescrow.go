@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kcp-dev/kcp/pkg/identityescrow"
+)
+
+// IdentityEscrow configures the escrow of APIExport identities to a store outside of kcp.
+type IdentityEscrow struct {
+	// StoreURL is the URL of the store the encrypted identities are kept in. Empty disables the escrow.
+	StoreURL string
+	// EncryptionKeyFile is the file with the base64 encoded AES-256 key the identities are encrypted with.
+	EncryptionKeyFile string
+}
+
+func NewIdentityEscrow() *IdentityEscrow {
+	return &IdentityEscrow{}
+}
+
+func (e *IdentityEscrow) Validate() []error {
+	if e == nil || (e.StoreURL == "" && e.EncryptionKeyFile == "") {
+		return nil
+	}
+
+	errs := []error{}
+
+	if e.StoreURL == "" {
+		errs = append(errs, fmt.Errorf("--identity-escrow-store-url is required if --identity-escrow-encryption-key-file is set"))
+	} else if _, err := identityescrow.NewStoreForURL(e.StoreURL); err != nil {
+		errs = append(errs, fmt.Errorf("--identity-escrow-store-url: %w", err))
+	}
+	if e.EncryptionKeyFile == "" {
+		errs = append(errs, fmt.Errorf("--identity-escrow-encryption-key-file is required if --identity-escrow-store-url is set"))
+	} else if _, err := identityescrow.LoadEncryptionKey(e.EncryptionKeyFile); err != nil {
+		errs = append(errs, fmt.Errorf("--identity-escrow-encryption-key-file: %w", err))
+	}
+
+	return errs
+}
+
+func (e *IdentityEscrow) AddFlags(fs *pflag.FlagSet) {
+	if e == nil {
+		return
+	}
+
+	fs.StringVar(&e.StoreURL, "identity-escrow-store-url", e.StoreURL,
+		"URL of the store encrypted copies of the APIExport identities of this shard are kept in, such that deleted "+
+			"identity secrets can be restored with \"kubectl kcp identity restore\". file:///path keeps them as files "+
+			"below the directory, http(s)://host/path writes them with PUT below the URL. Empty disables the identity escrow.")
+	fs.StringVar(&e.EncryptionKeyFile, "identity-escrow-encryption-key-file", e.EncryptionKeyFile,
+		"File with the base64 encoded 32 byte AES key the escrowed APIExport identities are encrypted with, "+
+			"e.g. created with \"head -c 32 /dev/urandom | base64\". Keep it separate from the store.")
+}
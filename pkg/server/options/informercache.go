@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+)
+
+// InformerCache configures wildcard informers keeping their objects on disk instead of in memory.
+type InformerCache struct {
+	// DiskBackedResources are the resources, as <resource>.<group>, whose wildcard informers on the
+	// cache server keep their objects on disk.
+	DiskBackedResources []string
+	// Dir is the directory the objects of disk-backed informers are kept in.
+	Dir string
+}
+
+func NewInformerCache(rootDir string) *InformerCache {
+	return &InformerCache{
+		Dir: filepath.Join(rootDir, "informer-cache"),
+	}
+}
+
+func (c *InformerCache) Validate() []error {
+	if c == nil || len(c.DiskBackedResources) == 0 {
+		return nil
+	}
+
+	errs := []error{}
+
+	if c.Dir == "" {
+		errs = append(errs, fmt.Errorf("--experimental-disk-backed-informers-dir is required if --experimental-disk-backed-informers is set"))
+	}
+
+	return errs
+}
+
+func (c *InformerCache) AddFlags(fs *pflag.FlagSet) {
+	if c == nil {
+		return
+	}
+
+	fs.StringSliceVar(&c.DiskBackedResources, "experimental-disk-backed-informers", c.DiskBackedResources,
+		"Experimental: resources, as <resource>.<group>, whose wildcard informers on replicated objects of the cache "+
+			"server keep the objects on disk, and only their keys and index values in memory. This lowers the memory "+
+			"footprint of shards with very many replicated objects, at the cost of latency of every read. Supported are "+
+			"apiexports.apis.kcp.io, apiresourceschemas.apis.kcp.io, apiconversions.apis.kcp.io, shards.core.kcp.io, "+
+			"workspacetypes.tenancy.kcp.io, clusterroles.rbac.authorization.k8s.io and clusterrolebindings.rbac.authorization.k8s.io.")
+	fs.StringVar(&c.Dir, "experimental-disk-backed-informers-dir", c.Dir,
+		"Directory the objects of disk-backed informers are kept in. Its content is discarded on start.")
+}
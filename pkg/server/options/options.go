@@ -43,9 +43,13 @@ type Options struct {
 	Controllers         Controllers
 	Authorization       Authorization
 	AdminAuthentication AdminAuthentication
+	EmergencyAccess     EmergencyAccess
 	Virtual             Virtual
 	HomeWorkspaces      HomeWorkspaces
 	Cache               Cache
+	Usage               Usage
+	IdentityEscrow      IdentityEscrow
+	InformerCache       InformerCache
 
 	Extra ExtraOptions
 }
@@ -66,6 +70,7 @@ type ExtraOptions struct {
 	LogicalClusterAdminKubeconfig         string
 	ExternalLogicalClusterAdminKubeconfig string
 	ConversionCELTransformationTimeout    time.Duration
+	ConversionWebhookAllowedCIDRs         []string
 	BatteriesIncluded                     []string
 }
 
@@ -75,9 +80,13 @@ type completedOptions struct {
 	Controllers         Controllers
 	Authorization       Authorization
 	AdminAuthentication AdminAuthentication
+	EmergencyAccess     EmergencyAccess
 	Virtual             Virtual
 	HomeWorkspaces      HomeWorkspaces
 	Cache               cacheCompleted
+	Usage               Usage
+	IdentityEscrow      IdentityEscrow
+	InformerCache       InformerCache
 
 	Extra ExtraOptions
 }
@@ -96,9 +105,13 @@ func NewOptions(rootDir string) *Options {
 		Controllers:         *NewControllers(),
 		Authorization:       *NewAuthorization(),
 		AdminAuthentication: *NewAdminAuthentication(rootDir),
+		EmergencyAccess:     *NewEmergencyAccess(rootDir),
 		Virtual:             *NewVirtual(),
 		HomeWorkspaces:      *NewHomeWorkspaces(),
 		Cache:               *NewCache(rootDir),
+		Usage:               *NewUsage(),
+		IdentityEscrow:      *NewIdentityEscrow(),
+		InformerCache:       *NewInformerCache(rootDir),
 
 		Extra: ExtraOptions{
 			ProfilerAddress:                    "",
@@ -151,9 +164,13 @@ func (o *Options) AddFlags(fss *cliflag.NamedFlagSets) {
 	o.Controllers.AddFlags(fss.FlagSet("KCP Controllers"))
 	o.Authorization.AddFlags(fss.FlagSet("KCP Authorization"))
 	o.AdminAuthentication.AddFlags(fss.FlagSet("KCP Authentication"))
+	o.EmergencyAccess.AddFlags(fss.FlagSet("KCP Authentication"))
 	o.Virtual.AddFlags(fss.FlagSet("KCP Virtual Workspaces"))
 	o.HomeWorkspaces.AddFlags(fss.FlagSet("KCP Home Workspaces"))
 	o.Cache.AddFlags(fss.FlagSet("KCP Cache Server"))
+	o.Usage.AddFlags(fss.FlagSet("KCP Usage Export"))
+	o.IdentityEscrow.AddFlags(fss.FlagSet("KCP Identity Escrow"))
+	o.InformerCache.AddFlags(fss.FlagSet("KCP Informer Cache"))
 
 	fs := fss.FlagSet("KCP")
 	fs.StringVar(&o.Extra.ProfilerAddress, "profiler-address", o.Extra.ProfilerAddress, "[Address]:port to bind the profiler to")
@@ -173,6 +190,8 @@ func (o *Options) AddFlags(fss *cliflag.NamedFlagSets) {
 	fs.MarkHidden("experimental-bind-free-port") //nolint:errcheck
 
 	fs.DurationVar(&o.Extra.ConversionCELTransformationTimeout, "conversion-cel-transformation-timeout", o.Extra.ConversionCELTransformationTimeout, "Maximum amount of time that CEL transformations may take per object conversion.")
+	fs.StringSliceVar(&o.Extra.ConversionWebhookAllowedCIDRs, "conversion-webhook-allowed-cidrs", o.Extra.ConversionWebhookAllowedCIDRs, "Networks, e.g. 10.96.0.0/12, "+
+		"that conversion webhooks of APIResourceSchemas may connect to in addition to public addresses. Loopback, private and link-local addresses are refused otherwise.")
 
 	fs.StringSliceVar(&o.Extra.BatteriesIncluded, "batteries-included", o.Extra.BatteriesIncluded, fmt.Sprintf(
 		`A list of batteries included (= default objects that might be unwanted in production, but are very helpful in trying out kcp or for development). These are the possible values: %s.
@@ -205,10 +224,18 @@ func (o *CompletedOptions) Validate() []error {
 	errs = append(errs, o.EmbeddedEtcd.Validate()...)
 	errs = append(errs, o.Authorization.Validate()...)
 	errs = append(errs, o.AdminAuthentication.Validate()...)
+	errs = append(errs, o.EmergencyAccess.Validate()...)
+	errs = append(errs, o.Usage.Validate()...)
+	errs = append(errs, o.IdentityEscrow.Validate()...)
+	errs = append(errs, o.InformerCache.Validate()...)
 	errs = append(errs, o.Virtual.Validate()...)
 	errs = append(errs, o.HomeWorkspaces.Validate()...)
 	errs = append(errs, o.Cache.Validate()...)
 
+	if _, err := o.Extra.ConversionWebhookAllowedNetworks(); err != nil {
+		errs = append(errs, err)
+	}
+
 	differential := false
 	for i, b := range o.Extra.BatteriesIncluded {
 		if strings.HasPrefix(b, "+") || strings.HasPrefix(b, "-") {
@@ -264,6 +291,18 @@ func (o *Options) Complete(rootDir string) (*CompletedOptions, error) {
 			return nil, err
 		}
 	}
+	if !filepath.IsAbs(o.EmergencyAccess.TokenStorePath) {
+		o.EmergencyAccess.TokenStorePath, err = filepath.Abs(o.EmergencyAccess.TokenStorePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(o.EmergencyAccess.KubeConfigPath) > 0 && !filepath.IsAbs(o.EmergencyAccess.KubeConfigPath) {
+		o.EmergencyAccess.KubeConfigPath, err = filepath.Abs(o.EmergencyAccess.KubeConfigPath)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if len(o.Extra.LogicalClusterAdminKubeconfig) > 0 && !filepath.IsAbs(o.Extra.LogicalClusterAdminKubeconfig) {
 		o.Extra.LogicalClusterAdminKubeconfig, err = filepath.Abs(o.Extra.LogicalClusterAdminKubeconfig)
 		if err != nil {
@@ -354,14 +393,31 @@ func (o *Options) Complete(rootDir string) (*CompletedOptions, error) {
 			Controllers:         o.Controllers,
 			Authorization:       o.Authorization,
 			AdminAuthentication: o.AdminAuthentication,
+			EmergencyAccess:     o.EmergencyAccess,
 			Virtual:             o.Virtual,
 			HomeWorkspaces:      o.HomeWorkspaces,
 			Cache:               cacheCompletedOptions,
+			Usage:               o.Usage,
+			IdentityEscrow:      o.IdentityEscrow,
+			InformerCache:       o.InformerCache,
 			Extra:               o.Extra,
 		},
 	}, nil
 }
 
+// ConversionWebhookAllowedNetworks returns the parsed --conversion-webhook-allowed-cidrs.
+func (o *ExtraOptions) ConversionWebhookAllowedNetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(o.ConversionWebhookAllowedCIDRs))
+	for _, cidr := range o.ConversionWebhookAllowedCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("--conversion-webhook-allowed-cidrs must be CIDRs, got %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
 func filter(name string, fs *pflag.FlagSet, allowed sets.String) *pflag.FlagSet {
 	filtered := pflag.NewFlagSet(name, pflag.ContinueOnError)
 	fs.VisitAll(func(f *pflag.Flag) {
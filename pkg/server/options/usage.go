@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kcp-dev/kcp/pkg/usage"
+)
+
+// Usage configures the export of per-workspace usage records, e.g. for billing and chargeback.
+type Usage struct {
+	// ExportSinkURL is the URL of the sink the usage records are exported to. Empty disables the export.
+	ExportSinkURL string
+	// ExportInterval is the period covered by every usage record.
+	ExportInterval time.Duration
+}
+
+func NewUsage() *Usage {
+	return &Usage{
+		ExportInterval: 5 * time.Minute,
+	}
+}
+
+func (u *Usage) Validate() []error {
+	if u == nil || u.ExportSinkURL == "" {
+		return nil
+	}
+
+	errs := []error{}
+
+	if _, err := usage.NewSinkForURL(u.ExportSinkURL); err != nil {
+		errs = append(errs, fmt.Errorf("--usage-export-sink-url: %w", err))
+	}
+	if u.ExportInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--usage-export-interval must be positive"))
+	}
+
+	return errs
+}
+
+func (u *Usage) AddFlags(fs *pflag.FlagSet) {
+	if u == nil {
+		return
+	}
+
+	fs.StringVar(&u.ExportSinkURL, "usage-export-sink-url", u.ExportSinkURL,
+		"URL of the sink per-workspace usage records (API requests, objects, storage bytes) of this shard are exported to. "+
+			"file:///path appends them as JSON lines, http(s)://host/path posts the records of every period as JSON array. "+
+			"Empty disables the usage export.")
+	fs.DurationVar(&u.ExportInterval, "usage-export-interval", u.ExportInterval,
+		"Period covered by every usage record, i.e. the interval usage records are exported at.")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	reconcilerworkspace "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspace"
+	"github.com/kcp-dev/kcp/sdk/apis/core"
+)
+
+// SchedulingSimulationPath is the non-resource path in the root workspace that simulates the
+// scheduling of workspaces.
+const SchedulingSimulationPath = "/workspacescheduling/simulation"
+
+// maxSchedulingSimulationRequestBytes is the maximum size of the body of a scheduling simulation request.
+const maxSchedulingSimulationRequestBytes = 1 << 20
+
+// WithSchedulingSimulation serves POST requests to SchedulingSimulationPath in the root workspace.
+// The request body is a reconcilerworkspace.SchedulingSimulationRequest, and the response reports the
+// shards the workspaces would be scheduled to and the resulting load of the shards. Nothing is created.
+//
+// The handler has to run after authorization, i.e. the user needs the "post" verb on the non-resource
+// URL in the root workspace.
+func WithSchedulingSimulation(apiHandler http.Handler, simulator *reconcilerworkspace.SchedulingSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		info, ok := request.RequestInfoFrom(req.Context())
+		if cluster == nil || cluster.Name != core.RootCluster || !ok || info.IsResourceRequest || info.Path != SchedulingSimulationPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodPost {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{}, info.Verb),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		var simulation reconcilerworkspace.SchedulingSimulationRequest
+		body := http.MaxBytesReader(w, req.Body, maxSchedulingSimulationRequestBytes)
+		if err := json.NewDecoder(body).Decode(&simulation); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				responsewriters.ErrorNegotiated(
+					apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("limit is %d bytes", maxBytesErr.Limit)),
+					errorCodecs, schema.GroupVersion{}, w, req,
+				)
+				return
+			}
+			responsewriters.ErrorNegotiated(
+				apierrors.NewBadRequest(fmt.Sprintf("invalid scheduling simulation request: %v", err)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+		if err := simulation.Validate(); err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewBadRequest(fmt.Sprintf("invalid scheduling simulation request: %v", err)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		result, err := simulator.Simulate(klog.FromContext(req.Context()), &simulation)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			klog.FromContext(req.Context()).Error(err, "failed to write scheduling simulation result")
+		}
+	}
+}
@@ -144,14 +144,20 @@ func (s *Server) Run(ctx context.Context) error {
 		logger := logger.WithValues("postStartHook", hookName)
 		ctx = klog.NewContext(ctx, logger)
 
+		if s.CacheLocalFallback != nil {
+			go s.CacheLocalFallback.Start(klog.NewContext(goContext(hookContext), logger), s.Options.Cache.Client.LocalFallbackProbeInterval)
+		}
+
 		logger.Info("starting kube informers")
 		s.KubeSharedInformerFactory.Start(hookContext.StopCh)
 		s.ApiExtensionsSharedInformerFactory.Start(hookContext.StopCh)
 		s.CacheKubeSharedInformerFactory.Start(hookContext.StopCh)
+		s.ReplicationCacheKubeSharedInformerFactory.Start(hookContext.StopCh)
 
 		s.KubeSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 		s.ApiExtensionsSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 		s.CacheKubeSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
+		s.ReplicationCacheKubeSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 
 		select {
 		case <-hookContext.StopCh:
@@ -257,9 +263,11 @@ func (s *Server) Run(ctx context.Context) error {
 
 		s.KcpSharedInformerFactory.Start(hookContext.StopCh)
 		s.CacheKcpSharedInformerFactory.Start(hookContext.StopCh)
+		s.ReplicationCacheKcpSharedInformerFactory.Start(hookContext.StopCh)
 
 		s.KcpSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 		s.CacheKcpSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
+		s.ReplicationCacheKcpSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 
 		// create or update shard
 		shard := &corev1alpha1.Shard{
@@ -365,12 +373,23 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if err := s.installCABundleConfigMapController(ctx, controllerConfig); err != nil {
+		return err
+	}
+
+	if err := s.installNamespaceMarkerController(ctx, controllerConfig); err != nil {
+		return err
+	}
+
 	if err := s.installApiExportIdentityController(ctx, controllerConfig); err != nil {
 		return err
 	}
 	if err := s.installReplicationController(ctx, controllerConfig); err != nil {
 		return err
 	}
+	if err := s.installUsageExporter(ctx); err != nil {
+		return err
+	}
 
 	enabled := sets.NewString(s.Options.Controllers.IndividuallyEnabled...)
 	if len(enabled) > 0 {
@@ -392,6 +411,12 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacerequest") {
+		if err := s.installWorkspaceRequestController(ctx, controllerConfig); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("apibinding") {
 		if err := s.installAPIBindingController(ctx, controllerConfig, s.DiscoveringDynamicSharedInformerFactory); err != nil {
 			return err
@@ -402,12 +427,18 @@ func (s *Server) Run(ctx context.Context) error {
 		if err := s.installExtraAnnotationSyncController(ctx, controllerConfig); err != nil {
 			return err
 		}
+		if err := s.installIdentityRotationController(ctx, controllerConfig); err != nil {
+			return err
+		}
 	}
 
 	if s.Options.Controllers.EnableAll || enabled.Has("apiexport") {
 		if err := s.installAPIExportController(ctx, controllerConfig); err != nil {
 			return err
 		}
+		if err := s.installAPIExportBindingsController(ctx, s.ExternalLogicalClusterAdminConfig); err != nil {
+			return err
+		}
 	}
 
 	if s.Options.Controllers.EnableAll || enabled.Has("apisreplicateclusterrole") {
@@ -469,6 +500,30 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("defaultapibinding") {
+		if err := s.installDefaultAPIBindingController(ctx, controllerConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacetemplate") {
+		if err := s.installWorkspaceTemplateController(ctx, controllerConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacelifecyclewebhook") {
+		if err := s.installWorkspaceLifecycleWebhookController(ctx, controllerConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacetrash") {
+		if err := s.installWorkspaceTrashController(ctx, controllerConfig, s.ExternalLogicalClusterAdminConfig); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("partition") {
 		if err := s.installPartitionSetController(ctx, controllerConfig); err != nil {
 			return err
@@ -496,6 +551,9 @@ func (s *Server) Run(ctx context.Context) error {
 	if err := s.Options.AdminAuthentication.WriteKubeConfig(s.GenericConfig, s.kcpAdminToken, s.shardAdminToken, s.userToken, s.shardAdminTokenHash); err != nil {
 		return err
 	}
+	if err := s.Options.EmergencyAccess.WriteKubeConfig(s.GenericConfig, s.emergencyToken); err != nil {
+		return err
+	}
 
 	return s.MiniAggregator.GenericAPIServer.PrepareRun().Run(ctx.Done())
 }
@@ -14,13 +14,17 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package server
+// Package tableconverter provides the table convertors of resources served from CRDs and
+// APIResourceSchemas, i.e. the columns of e.g. `kubectl get`.
+package tableconverter
 
 import (
 	"context"
 	"reflect"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apiserver"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresource/tableconvertor"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -43,7 +47,10 @@ type tableConverterProvider struct {
 
 var _ apiserver.TableConverterProvider = &tableConverterProvider{}
 
-func NewTableConverterProvider() *tableConverterProvider {
+var defaultProvider = NewProvider()
+
+// NewProvider returns a TableConverterProvider for the apiextensions apiserver.
+func NewProvider() apiserver.TableConverterProvider {
 	return &tableConverterProvider{
 		internalTableConverter: printerstorage.TableConvertor{
 			TableGenerator: printers.NewTableGenerator().With(printersinternal.AddHandlers),
@@ -100,3 +107,24 @@ func (t *tableConverterProvider) GetTableConverter(group, kind, listKind string)
 		return t.internalTableConverter.ConvertToTable(ctx, out, tableOptions)
 	})
 }
+
+// ForColumns returns the table convertor of a resource defined by a CRD or APIResourceSchema
+// with the given additional printer columns, like the apiextensions apiserver serves CRDs: built-in
+// Kubernetes resources are printed like the built-in types, other resources with the given columns,
+// or with an Age column if there are none.
+//
+// If the columns are invalid, an error is returned along with a table convertor printing the name only.
+func ForColumns(group, kind, listKind string, columns []apiextensionsv1.CustomResourceColumnDefinition) (rest.TableConvertor, error) {
+	if table := defaultProvider.GetTableConverter(group, kind, listKind); table != nil {
+		return table, nil
+	}
+	if len(columns) == 0 {
+		columns = []apiextensionsv1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", Description: ageDescription, JSONPath: ".metadata.creationTimestamp"},
+		}
+	}
+	return tableconvertor.New(columns)
+}
+
+// ageDescription is the description of the default Age column of the apiextensions apiserver.
+var ageDescription = metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tableconverter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestForColumns(t *testing.T) {
+	widget := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.io/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":              "foo",
+			"creationTimestamp": "2023-05-01T10:00:00Z",
+		},
+		"spec": map[string]interface{}{"color": "blue"},
+	}}
+
+	tests := map[string]struct {
+		columns     []apiextensionsv1.CustomResourceColumnDefinition
+		wantHeaders []string
+		wantCells   []interface{}
+		wantErr     bool
+	}{
+		"printer columns": {
+			columns: []apiextensionsv1.CustomResourceColumnDefinition{
+				{Name: "Color", Type: "string", JSONPath: ".spec.color"},
+			},
+			wantHeaders: []string{"Name", "Color"},
+			wantCells:   []interface{}{"foo", "blue"},
+		},
+		"no printer columns": {
+			wantHeaders: []string{"Name", "Age"},
+		},
+		"invalid printer columns": {
+			columns: []apiextensionsv1.CustomResourceColumnDefinition{
+				{Name: "Color", Type: "string", JSONPath: ".spec[color"},
+			},
+			wantHeaders: []string{"Name"},
+			wantCells:   []interface{}{"foo"},
+			wantErr:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			table, err := ForColumns("example.io", "Widget", "WidgetList", tt.columns)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			got, err := table.ConvertToTable(context.Background(), widget, nil)
+			require.NoError(t, err)
+			headers := make([]string, 0, len(got.ColumnDefinitions))
+			for _, c := range got.ColumnDefinitions {
+				headers = append(headers, c.Name)
+			}
+			require.Equal(t, tt.wantHeaders, headers)
+			require.Len(t, got.Rows, 1)
+			if tt.wantCells != nil {
+				require.Equal(t, tt.wantCells, got.Rows[0].Cells)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	kcpinformers "github.com/kcp-dev/client-go/informers"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// RequestCounter counts the API requests per logical cluster. Wildcard requests are not
+// attributed to any workspace and hence not counted.
+type RequestCounter struct {
+	lock   sync.Mutex
+	counts map[logicalcluster.Name]int64
+}
+
+var _ Source = &RequestCounter{}
+
+func NewRequestCounter() *RequestCounter {
+	return &RequestCounter{
+		counts: map[logicalcluster.Name]int64{},
+	}
+}
+
+// WithRequestCounting counts the requests passing through handler. The logical cluster of
+// the request must already be in the request context.
+func (c *RequestCounter) WithRequestCounting(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if cluster := request.ClusterFrom(req.Context()); cluster != nil && !cluster.Wildcard && !cluster.Name.Empty() {
+			c.lock.Lock()
+			c.counts[cluster.Name]++
+			c.lock.Unlock()
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Collect adds the requests counted since the last call.
+func (c *RequestCounter) Collect(_ context.Context, record func(cluster logicalcluster.Name) *Record) error {
+	c.lock.Lock()
+	counts := c.counts
+	c.counts = make(map[logicalcluster.Name]int64, len(counts))
+	c.lock.Unlock()
+
+	for cluster, n := range counts {
+		record(cluster).APIRequests += n
+	}
+	return nil
+}
+
+// InformerSource provides the informers of all resources of the shard, e.g. the discovering
+// dynamic shared informer factory.
+type InformerSource interface {
+	Informers() (informers map[schema.GroupVersionResource]kcpinformers.GenericClusterInformer, notSynced []schema.GroupVersionResource)
+}
+
+// ObjectCounter counts the objects and their approximate storage size per logical cluster
+// from the informers of all resources of the shard.
+type ObjectCounter struct {
+	informers InformerSource
+}
+
+var _ Source = &ObjectCounter{}
+
+func NewObjectCounter(informers InformerSource) *ObjectCounter {
+	return &ObjectCounter{informers: informers}
+}
+
+// Collect adds the objects currently in the synced informers.
+func (c *ObjectCounter) Collect(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error {
+	informers, _ := c.informers.Informers()
+	for gvr, informer := range informers {
+		resource := gvr.GroupResource().String()
+		for _, obj := range informer.Informer().GetIndexer().List() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			metaObj, err := meta.Accessor(obj)
+			if err != nil {
+				continue
+			}
+			r := record(logicalcluster.From(metaObj))
+			if r.Objects == nil {
+				r.Objects = map[string]int64{}
+			}
+			r.Objects[resource]++
+			if bs, err := json.Marshal(obj); err == nil {
+				r.StorageBytes += int64(len(bs))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage periodically exports per-workspace usage records of a shard, e.g. for
+// billing and chargeback, to a pluggable sink.
+package usage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// Record is the usage of a workspace on a shard during a period.
+type Record struct {
+	// Cluster is the logical cluster of the workspace.
+	Cluster logicalcluster.Name `json:"cluster"`
+	// Shard is the name of the shard reporting the usage.
+	Shard string `json:"shard"`
+	// Start and End delimit the period of the record.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// APIRequests is the number of API requests to the workspace during the period.
+	APIRequests int64 `json:"apiRequests"`
+	// Objects is the number of objects in the workspace at the end of the period,
+	// by group resource, e.g. "configmaps" or "widgets.example.io".
+	Objects map[string]int64 `json:"objects,omitempty"`
+	// StorageBytes approximates the storage used by the objects of the workspace at the
+	// end of the period by the size of their JSON encoding.
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+// Sink receives the usage records of every period. Implementations could e.g. write them
+// to a file, post them to a billing service or produce them to a message queue.
+type Sink interface {
+	// Export delivers the records of one period. It is never called concurrently.
+	Export(ctx context.Context, records []Record) error
+}
+
+// SinkFunc is a Sink implemented by a function.
+type SinkFunc func(ctx context.Context, records []Record) error
+
+func (f SinkFunc) Export(ctx context.Context, records []Record) error {
+	return f(ctx, records)
+}
+
+// Source contributes to the usage records of a period.
+type Source interface {
+	// Collect adds the usage of the period ending now. The record func returns the record
+	// of the given logical cluster, creating it if it does not exist yet.
+	Collect(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error
+}
+
+// Exporter collects the usage of all sources and exports it to the sink at every interval.
+type Exporter struct {
+	shard    string
+	interval time.Duration
+	sink     Sink
+	sources  []Source
+
+	clock clock.WithTicker
+}
+
+// NewExporter returns an exporter reporting the usage of the shard every interval.
+func NewExporter(shard string, interval time.Duration, sink Sink, sources ...Source) *Exporter {
+	return &Exporter{
+		shard:    shard,
+		interval: interval,
+		sink:     sink,
+		sources:  sources,
+		clock:    clock.RealClock{},
+	}
+}
+
+// Start exports usage records until ctx is done.
+func (e *Exporter) Start(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	logger := klog.FromContext(ctx).WithValues("component", "usage-exporter")
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting usage exporter", "interval", e.interval)
+	defer logger.Info("Shutting down usage exporter")
+
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	start := e.clock.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case end := <-ticker.C():
+			e.export(ctx, start, end)
+			start = end
+		}
+	}
+}
+
+// export collects the usage from all sources and passes the records to the sink. Failed
+// sources are skipped, and records not accepted by the sink are dropped.
+func (e *Exporter) export(ctx context.Context, start, end time.Time) {
+	logger := klog.FromContext(ctx)
+
+	records := map[logicalcluster.Name]*Record{}
+	record := func(cluster logicalcluster.Name) *Record {
+		r, ok := records[cluster]
+		if !ok {
+			r = &Record{Cluster: cluster, Shard: e.shard, Start: start, End: end}
+			records[cluster] = r
+		}
+		return r
+	}
+	for _, source := range e.sources {
+		if err := source.Collect(ctx, record); err != nil {
+			logger.Error(err, "failed to collect usage")
+		}
+	}
+
+	ret := make([]Record, 0, len(records))
+	for _, r := range records {
+		ret = append(ret, *r)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Cluster < ret[j].Cluster })
+
+	if err := e.sink.Export(ctx, ret); err != nil {
+		logger.Error(err, "failed to export usage records", "records", len(ret), "start", start, "end", end)
+		return
+	}
+	logger.V(4).Info("exported usage records", "records", len(ret))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+type sourceFunc func(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error
+
+func (f sourceFunc) Collect(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error {
+	return f(ctx, record)
+}
+
+func TestExporter(t *testing.T) {
+	counter := NewRequestCounter()
+	handler := counter.WithRequestCounting(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for _, cluster := range []*request.Cluster{
+		{Name: "root"},
+		{Name: "2k7nsx6bq9ez3h2y"},
+		{Name: "2k7nsx6bq9ez3h2y"},
+		{Wildcard: true},
+		nil,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		if cluster != nil {
+			req = req.WithContext(request.WithCluster(req.Context(), *cluster))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	objects := sourceFunc(func(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error {
+		r := record("2k7nsx6bq9ez3h2y")
+		r.Objects = map[string]int64{"configmaps": 3}
+		r.StorageBytes = 1024
+		record("idle").Objects = map[string]int64{"namespaces": 1}
+		return nil
+	})
+	failing := sourceFunc(func(ctx context.Context, record func(cluster logicalcluster.Name) *Record) error {
+		return errors.New("unavailable")
+	})
+
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+	clock := clocktesting.NewFakeClock(start)
+
+	exported := make(chan []Record, 1)
+	e := NewExporter("alpha", 5*time.Minute, SinkFunc(func(ctx context.Context, records []Record) error {
+		exported <- records
+		return nil
+	}), counter, failing, objects)
+	e.clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Start(ctx)
+
+	require.Eventually(t, clock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+	clock.Step(5 * time.Minute)
+
+	var records []Record
+	select {
+	case records = <-exported:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for usage records")
+	}
+	require.Equal(t, []Record{
+		{Cluster: "2k7nsx6bq9ez3h2y", Shard: "alpha", Start: start, End: end, APIRequests: 2, Objects: map[string]int64{"configmaps": 3}, StorageBytes: 1024},
+		{Cluster: "idle", Shard: "alpha", Start: start, End: end, Objects: map[string]int64{"namespaces": 1}},
+		{Cluster: "root", Shard: "alpha", Start: start, End: end, APIRequests: 1},
+	}, records)
+
+	// the request counts are reset for the next period.
+	next := map[logicalcluster.Name]*Record{}
+	require.NoError(t, counter.Collect(ctx, func(cluster logicalcluster.Name) *Record {
+		next[cluster] = &Record{}
+		return next[cluster]
+	}))
+	require.Empty(t, next)
+}
+
+func TestNewSinkForURL(t *testing.T) {
+	records := []Record{
+		{Cluster: "root", Shard: "alpha", APIRequests: 1},
+		{Cluster: "2k7nsx6bq9ez3h2y", Shard: "alpha", Objects: map[string]int64{"configmaps": 3}, StorageBytes: 1024},
+	}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "usage.jsonl")
+		sink, err := NewSinkForURL("file://" + path)
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Export(context.Background(), records))
+		require.NoError(t, sink.Export(context.Background(), records[:1]))
+
+		bs, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+		require.Len(t, lines, 3)
+		var got Record
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+		require.Equal(t, records[1].Cluster, got.Cluster)
+		require.Equal(t, records[1].Objects, got.Objects)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		var got []Record
+		status := http.StatusNoContent
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&got))
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		sink, err := NewSinkForURL(server.URL + "/usage")
+		require.NoError(t, err)
+		require.NoError(t, sink.Export(context.Background(), records))
+		require.Len(t, got, 2)
+		require.Equal(t, logicalcluster.Name("root"), got[0].Cluster)
+
+		status = http.StatusServiceUnavailable
+		require.Error(t, sink.Export(context.Background(), records))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewSinkForURL("kafka://broker:9092/usage")
+		require.Error(t, err)
+		_, err = NewSinkForURL("file://")
+		require.Error(t, err)
+	})
+}
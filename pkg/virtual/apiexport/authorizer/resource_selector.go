@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions/apis/v1alpha1"
+)
+
+// readOnlyVerbs are the verbs allowed on all of the objects of status-only claims.
+var readOnlyVerbs = sets.NewString("get", "list", "watch")
+
+type resourceSelectorAuthorizer struct {
+	getAPIExport  func(clusterName, apiExportName string) (*apisv1alpha1.APIExport, error)
+	getAPIBinding func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error)
+	delegate      authorizer.Authorizer
+}
+
+// NewResourceSelectorAuthorizer creates an authorizer that denies requests for claimed resources
+// whose namespace and name cannot be matched by any resource selector of the permission claim,
+// requests for subresources of claimed resources not listed by the permission claim, and changes
+// other than to the status of objects of status-only claims. For requests to a single logical
+// cluster, the claim is enforced as accepted by its APIBinding, and it is status-only if either the
+// APIExport claims it so or the APIBinding has accepted it so.
+// Labels are not known at authorization time; objects not matching the label selectors of a claim
+// are filtered by the storage of the virtual workspace. Objects of read-through claims can only be
+// read by name. Other requests are passed to the delegate.
+func NewResourceSelectorAuthorizer(
+	delegate authorizer.Authorizer,
+	apiExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	getAPIBinding func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error),
+) authorizer.Authorizer {
+	apiExportLister := apiExportInformer.Lister()
+
+	return &resourceSelectorAuthorizer{
+		getAPIExport: func(clusterName, apiExportName string) (*apisv1alpha1.APIExport, error) {
+			return apiExportLister.Cluster(logicalcluster.Name(clusterName)).Get(apiExportName)
+		},
+		getAPIBinding: getAPIBinding,
+		delegate:      delegate,
+	}
+}
+
+func (a *resourceSelectorAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	if !attr.IsResourceRequest() {
+		return a.delegate.Authorize(ctx, attr)
+	}
+
+	apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+	parts := strings.Split(string(apiDomainKey), "/")
+	if len(parts) < 2 {
+		return authorizer.DecisionNoOpinion, "", fmt.Errorf("invalid API domain key")
+	}
+
+	apiExport, err := a.getAPIExport(parts[0], parts[1])
+	if kerrors.IsNotFound(err) {
+		return authorizer.DecisionNoOpinion, "", fmt.Errorf("API export not found: %w", err)
+	}
+	if err != nil {
+		return authorizer.DecisionNoOpinion, "", err
+	}
+
+	claim, found := getClaim(apiExport, attr)
+	if found && claim.ReferencedBy != nil {
+		if attr.GetVerb() != "get" || attr.GetName() == "" || attr.GetSubresource() != "" {
+			return authorizer.DecisionDeny, fmt.Sprintf("%s are claimed read-through by API export: %q, workspace: %q, and can only be read by name",
+				attr.GetResource(), apiExport.Name, logicalcluster.From(apiExport)), nil
+		}
+		// whether the object is referenced is checked by the storage of the virtual workspace.
+		return a.delegate.Authorize(ctx, attr)
+	}
+	if found {
+		// the claim is enforced with the resource selectors and subresources the consumer has accepted,
+		// and status-only if either the APIExport or the APIBinding wants it so.
+		accepted, acceptedFound, err := a.acceptedClaim(ctx, apiExport, claim)
+		if err != nil {
+			return authorizer.DecisionNoOpinion, "", err
+		}
+		if acceptedFound {
+			accepted.StatusOnly = accepted.StatusOnly || claim.StatusOnly
+			claim = accepted
+		}
+	}
+	if found && !permissionclaims.MaySelect(claim, attr.GetNamespace(), attr.GetName()) {
+		return authorizer.DecisionDeny, fmt.Sprintf("%s not selected by the resource selectors of the permission claim of API export: %q, workspace: %q",
+			qualifiedName(attr), apiExport.Name, logicalcluster.From(apiExport)), nil
+	}
+	if found && !claimsSubresource(claim, attr.GetSubresource()) {
+		return authorizer.DecisionDeny, fmt.Sprintf("subresource %q not claimed by the permission claim of API export: %q, workspace: %q",
+			attr.GetSubresource(), apiExport.Name, logicalcluster.From(apiExport)), nil
+	}
+	if found && claim.StatusOnly && !readOnlyVerbs.Has(attr.GetVerb()) && attr.GetSubresource() != string(apisv1alpha1.PermissionClaimStatusSubresource) {
+		return authorizer.DecisionDeny, fmt.Sprintf("%s are claimed status-only by API export: %q, workspace: %q, and only their status can be changed",
+			attr.GetResource(), apiExport.Name, logicalcluster.From(apiExport)), nil
+	}
+
+	return a.delegate.Authorize(ctx, attr)
+}
+
+// acceptedClaim returns the claim the APIBinding of the logical cluster of the request has accepted
+// for the given claim of the APIExport. Nothing is found for wildcard requests.
+func (a *resourceSelectorAuthorizer) acceptedClaim(ctx context.Context, apiExport *apisv1alpha1.APIExport, claim apisv1alpha1.PermissionClaim) (apisv1alpha1.PermissionClaim, bool, error) {
+	cluster := genericapirequest.ClusterFrom(ctx)
+	if cluster == nil || cluster.Wildcard || cluster.Name.Empty() {
+		return apisv1alpha1.PermissionClaim{}, false, nil
+	}
+	apiBinding, err := a.getAPIBinding(cluster.Name, apiExport)
+	if kerrors.IsNotFound(err) {
+		return apisv1alpha1.PermissionClaim{}, false, nil
+	} else if err != nil {
+		return apisv1alpha1.PermissionClaim{}, false, err
+	}
+	accepted, found := permissionclaims.AcceptedClaim(apiBinding, claim)
+	return accepted, found, nil
+}
+
+// getClaim returns the permission claim of the APIExport for the resource of the request.
+func getClaim(apiExport *apisv1alpha1.APIExport, attr authorizer.Attributes) (apisv1alpha1.PermissionClaim, bool) {
+	for _, claim := range apiExport.Spec.PermissionClaims {
+		if claim.Resource == attr.GetResource() && claim.Group == attr.GetAPIGroup() {
+			return claim, true
+		}
+	}
+	return apisv1alpha1.PermissionClaim{}, false
+}
+
+// claimsSubresource returns whether the claim allows to access the given subresource. Claims
+// without subresources allow all of them.
+func claimsSubresource(claim apisv1alpha1.PermissionClaim, subresource string) bool {
+	if subresource == "" || len(claim.Subresources) == 0 {
+		return true
+	}
+	for _, s := range claim.Subresources {
+		if string(s) == subresource {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifiedName(attr authorizer.Attributes) string {
+	switch {
+	case attr.GetName() == "":
+		return fmt.Sprintf("namespace %q", attr.GetNamespace())
+	case attr.GetNamespace() == "":
+		return fmt.Sprintf("object %q", attr.GetName())
+	default:
+		return fmt.Sprintf("object %q", attr.GetNamespace()+"/"+attr.GetName())
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestResourceSelectorAuthorizer(t *testing.T) {
+	apiExport := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "export"},
+		Spec: apisv1alpha1.APIExportSpec{
+			PermissionClaims: []apisv1alpha1.PermissionClaim{
+				{
+					GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"},
+					ResourceSelector: []apisv1alpha1.ResourceSelector{
+						{Namespace: "default", Name: "setup"},
+						{Namespace: "team-*"},
+						{Namespace: "operator", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "my-operator"}}},
+					},
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"},
+					All:           true,
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Group: "apps", Resource: "deployments"},
+					All:           true,
+					Subresources:  []apisv1alpha1.PermissionClaimSubresource{apisv1alpha1.PermissionClaimScaleSubresource},
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Resource: "serviceaccounts"},
+					ReferencedBy: &apisv1alpha1.PermissionClaimReference{
+						GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+						NameField:     "spec.serviceAccountName",
+					},
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "gadgets"},
+					All:           true,
+					StatusOnly:    true,
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "things"},
+					All:           true,
+				},
+			},
+		},
+	}
+	// the consumer has accepted things as status-only, before the APIExport lifted it, and
+	// configmaps with fewer resource selectors than claimed now.
+	apiBinding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding", Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer"}},
+		Spec: apisv1alpha1.APIBindingSpec{
+			PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{
+					PermissionClaim: apisv1alpha1.PermissionClaim{
+						GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "things"},
+						All:           true,
+						StatusOnly:    true,
+					},
+					State: apisv1alpha1.ClaimAccepted,
+				},
+				{
+					PermissionClaim: apisv1alpha1.PermissionClaim{
+						GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+						ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default", Name: "setup"}},
+					},
+					State: apisv1alpha1.ClaimAccepted,
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		cluster logicalcluster.Name
+		attr    authorizer.AttributesRecord
+		want    authorizer.Decision
+	}{
+		"non-resource request": {
+			attr: authorizer.AttributesRecord{Path: "/api"},
+			want: authorizer.DecisionAllow,
+		},
+		"unclaimed resource": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, APIGroup: "example.io", Resource: "widgets", Name: "foo"},
+			want: authorizer.DecisionAllow,
+		},
+		"all claimed": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "secrets", Namespace: "kube-system", Name: "foo"},
+			want: authorizer.DecisionAllow,
+		},
+		"selected name": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "default", Name: "setup"},
+			want: authorizer.DecisionAllow,
+		},
+		"other name": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "default", Name: "other"},
+			want: authorizer.DecisionDeny,
+		},
+		"list in selected namespace": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "operator"},
+			want: authorizer.DecisionAllow,
+		},
+		"list in other namespace": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "kube-system"},
+			want: authorizer.DecisionDeny,
+		},
+		"list in namespace matching pattern": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "team-a"},
+			want: authorizer.DecisionAllow,
+		},
+		"subresource of claim without subresources": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "secrets", Namespace: "default", Name: "foo", Subresource: "status"},
+			want: authorizer.DecisionAllow,
+		},
+		"claimed subresource": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, APIGroup: "apps", Resource: "deployments", Namespace: "default", Name: "foo", Subresource: "scale"},
+			want: authorizer.DecisionAllow,
+		},
+		"unclaimed subresource": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, APIGroup: "apps", Resource: "deployments", Namespace: "default", Name: "foo", Subresource: "status"},
+			want: authorizer.DecisionDeny,
+		},
+		"main resource of claim with subresources": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, APIGroup: "apps", Resource: "deployments", Namespace: "default", Name: "foo"},
+			want: authorizer.DecisionAllow,
+		},
+		"list across namespaces": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps"},
+			want: authorizer.DecisionAllow,
+		},
+		"get of read-through claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "get", Resource: "serviceaccounts", Namespace: "default", Name: "foo"},
+			want: authorizer.DecisionAllow,
+		},
+		"list of read-through claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "list", Resource: "serviceaccounts", Namespace: "default"},
+			want: authorizer.DecisionDeny,
+		},
+		"update of read-through claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "update", Resource: "serviceaccounts", Namespace: "default", Name: "foo"},
+			want: authorizer.DecisionDeny,
+		},
+		"list of status-only claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "list", APIGroup: "example.io", Resource: "gadgets", Namespace: "default"},
+			want: authorizer.DecisionAllow,
+		},
+		"status update of status-only claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "update", APIGroup: "example.io", Resource: "gadgets", Namespace: "default", Name: "foo", Subresource: "status"},
+			want: authorizer.DecisionAllow,
+		},
+		"update of status-only claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "patch", APIGroup: "example.io", Resource: "gadgets", Namespace: "default", Name: "foo"},
+			want: authorizer.DecisionDeny,
+		},
+		"create of status-only claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "create", APIGroup: "example.io", Resource: "gadgets", Namespace: "default"},
+			want: authorizer.DecisionDeny,
+		},
+		"delete of status-only claim": {
+			attr: authorizer.AttributesRecord{ResourceRequest: true, Verb: "delete", APIGroup: "example.io", Resource: "gadgets", Namespace: "default", Name: "foo"},
+			want: authorizer.DecisionDeny,
+		},
+		"update of claim accepted as status-only": {
+			cluster: "consumer",
+			attr:    authorizer.AttributesRecord{ResourceRequest: true, Verb: "update", APIGroup: "example.io", Resource: "things", Namespace: "default", Name: "foo"},
+			want:    authorizer.DecisionDeny,
+		},
+		"status update of claim accepted as status-only": {
+			cluster: "consumer",
+			attr:    authorizer.AttributesRecord{ResourceRequest: true, Verb: "update", APIGroup: "example.io", Resource: "things", Namespace: "default", Name: "foo", Subresource: "status"},
+			want:    authorizer.DecisionAllow,
+		},
+		"selected name of accepted claim": {
+			cluster: "consumer",
+			attr:    authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "default", Name: "setup"},
+			want:    authorizer.DecisionAllow,
+		},
+		"list in namespace not selected by accepted claim": {
+			cluster: "consumer",
+			attr:    authorizer.AttributesRecord{ResourceRequest: true, Resource: "configmaps", Namespace: "team-a"},
+			want:    authorizer.DecisionDeny,
+		},
+		"update of claim accepted in another cluster": {
+			cluster: "other",
+			attr:    authorizer.AttributesRecord{ResourceRequest: true, Verb: "update", APIGroup: "example.io", Resource: "things", Namespace: "default", Name: "foo"},
+			want:    authorizer.DecisionAllow,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &resourceSelectorAuthorizer{
+				getAPIExport: func(clusterName, apiExportName string) (*apisv1alpha1.APIExport, error) {
+					return apiExport, nil
+				},
+				getAPIBinding: func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error) {
+					if clusterName != logicalcluster.From(apiBinding) {
+						return nil, kerrors.NewNotFound(apisv1alpha1.Resource("apibindings"), "")
+					}
+					return apiBinding, nil
+				},
+				delegate: authorizerfactory.NewAlwaysAllowAuthorizer(),
+			}
+			ctx := dynamiccontext.WithAPIDomainKey(context.Background(), "root:provider/export")
+			if tt.cluster != "" {
+				ctx = genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: tt.cluster})
+			}
+			tt.attr.User = &user.DefaultInfo{Name: "provider"}
+			got, _, err := a.Authorize(ctx, &tt.attr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
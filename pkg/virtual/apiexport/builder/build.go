@@ -20,15 +20,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/rest"
@@ -46,6 +51,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
 	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/handler"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
@@ -59,13 +65,28 @@ func BuildVirtualWorkspace(
 	cfg *rest.Config,
 	kubeClusterClient, deepSARClient kcpkubernetesclientset.ClusterInterface,
 	kcpClusterClient kcpclientset.ClusterInterface,
-	cachedKcpInformers kcpinformers.SharedInformerFactory,
+	wildcardKcpInformers, cachedKcpInformers kcpinformers.SharedInformerFactory,
+	listPageSize, maxListResponseBytes int64,
 ) ([]rootapiserver.NamedVirtualWorkspace, error) {
 	if !strings.HasSuffix(rootPathPrefix, "/") {
 		rootPathPrefix += "/"
 	}
 
 	readyCh := make(chan struct{})
+	claimUsage := newClaimUsageTracker(kcpClusterClient)
+	getAPIExport := func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return cachedKcpInformers.Apis().V1alpha1().APIExports().Lister().Cluster(clusterName).Get(name)
+	}
+	aliases := newConsumerAliasCache(getAPIExport)
+	// claims are enforced as accepted by the APIBindings of the consumers on this shard.
+	apiBindingLister := wildcardKcpInformers.Apis().V1alpha1().APIBindings().Lister()
+	getAPIBinding := func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error) {
+		apiBindings, err := apiBindingLister.Cluster(clusterName).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		return boundAPIBinding(apiBindings, apiExport, clusterName)
+	}
 
 	boundOrClaimedWorkspaceContent := &virtualdynamic.DynamicVirtualWorkspace{
 		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, ctx context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
@@ -105,13 +126,18 @@ func BuildVirtualWorkspace(
 					return dynamicClient, nil
 				}
 
+				extra := map[string][]string{
+					serviceaccount.ClusterNameKey: {cluster.Name.Path().String()},
+				}
+				if value, ok := providerQuotaLabelValue(ctx); ok {
+					extra[apisv1alpha1.ProviderQuotaUserExtraKey] = []string{value}
+				}
+
 				impersonationConfig := rest.CopyConfig(cfg)
 				impersonationConfig.Impersonate = rest.ImpersonationConfig{
 					UserName: "system:serviceaccount:default:rest",
 					Groups:   []string{bootstrap.SystemKcpAdminGroup},
-					Extra: map[string][]string{
-						serviceaccount.ClusterNameKey: {cluster.Name.Path().String()},
-					},
+					Extra:    extra,
 				}
 				impersonatedClient, err := kcpdynamic.NewForConfig(impersonationConfig)
 				if err != nil {
@@ -120,6 +146,16 @@ func BuildVirtualWorkspace(
 				return impersonatedClient, nil
 			}
 
+			readThrough := &readThroughClaims{
+				getAPIExport:  getAPIExport,
+				getAPIBinding: getAPIBinding,
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					return cachedKcpInformers.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
+				},
+				dynamicClusterClient: impersonatedDynamicClientGetter,
+				referencers:          newReferencers(dynamicClient),
+			}
+
 			apiReconciler, err := apireconciler.NewAPIReconciler(
 				kcpClusterClient,
 				cachedKcpInformers.Apis().V1alpha1().APIResourceSchemas(),
@@ -127,14 +163,24 @@ func BuildVirtualWorkspace(
 				func(apiResourceSchema *apisv1alpha1.APIResourceSchema, version string, identityHash string, optionalLabelRequirements labels.Requirements) (apidefinition.APIDefinition, error) {
 					ctx, cancelFn := context.WithCancel(context.Background())
 
-					var wrapper forwardingregistry.StorageWrapper
+					wrapper := forwardingregistry.StorageWrappers{
+						forwardingregistry.WithListPaging(listPageSize, maxListResponseBytes),
+						withProviderQuotaLabel(),
+						withConsumerAliases(aliases),
+						readThrough.referenceGuard(),
+					}
 					if len(optionalLabelRequirements) > 0 {
-						wrapper = forwardingregistry.WithLabelSelector(func(_ context.Context) labels.Requirements {
+						// only claimed resources are filtered by label
+						claimedResource := schema.GroupResource{Group: apiResourceSchema.Spec.Group, Resource: apiResourceSchema.Spec.Names.Plural}
+						wrapper = append(wrapper, forwardingregistry.WithLabelSelector(func(_ context.Context) labels.Requirements {
 							return optionalLabelRequirements
-						})
+						}), forwardingregistry.WithObjectFilter(claimedResourceSelectors(getAPIExport, getAPIBinding, claimedResource)),
+							// read-through claimed objects are not labelled, hence bypass the filters above
+							readThrough.wrapper(version, identityHash),
+							withClaimUsageTracking(claimUsage, identityHash))
 					}
 
-					storageBuilder := provideDelegatingRestStorage(ctx, impersonatedDynamicClientGetter, identityHash, wrapper)
+					storageBuilder := provideDelegatingRestStorage(ctx, impersonatedDynamicClientGetter, identityHash, &wrapper)
 					def, err := apiserver.CreateServingInfoFor(mainConfig, apiResourceSchema, version, storageBuilder)
 					if err != nil {
 						cancelFn()
@@ -169,6 +215,7 @@ func BuildVirtualWorkspace(
 				for name, informer := range map[string]cache.SharedIndexInformer{
 					"apiresourceschemas": cachedKcpInformers.Apis().V1alpha1().APIResourceSchemas().Informer(),
 					"apiexports":         cachedKcpInformers.Apis().V1alpha1().APIExports().Informer(),
+					"apibindings":        wildcardKcpInformers.Apis().V1alpha1().APIBindings().Informer(),
 				} {
 					if !cache.WaitForNamedCacheSync(name, hookContext.StopCh, informer.HasSynced) {
 						klog.Background().Error(nil, "informer not synced")
@@ -176,7 +223,9 @@ func BuildVirtualWorkspace(
 					}
 				}
 
+				readThrough.referencers.start(goContext(hookContext))
 				go apiReconciler.Start(goContext(hookContext))
+				go claimUsage.Start(goContext(hookContext))
 				return nil
 			}); err != nil {
 				return nil, err
@@ -184,11 +233,74 @@ func BuildVirtualWorkspace(
 
 			return apiReconciler, nil
 		},
-		Authorizer: newAuthorizer(kubeClusterClient, deepSARClient, cachedKcpInformers),
+		Authorizer: newAuthorizer(kubeClusterClient, deepSARClient, cachedKcpInformers, getAPIBinding),
+	}
+
+	consumerAliasesContent := &handler.VirtualWorkspace{
+		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, ctx context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			apiDomain, prefixToStrip, ok := digestConsumerAliasesUrl(urlPath, rootPathPrefix)
+			if !ok {
+				return false, "", ctx
+			}
+			return true, prefixToStrip, dynamiccontext.WithAPIDomainKey(ctx, apiDomain)
+		}),
+		ReadyChecker: framework.ReadyFunc(func() error {
+			select {
+			case <-readyCh:
+				return nil
+			default:
+				return errors.New("apiexport virtual workspace controllers are not started")
+			}
+		}),
+		HandlerFactory: func(_ genericapiserver.CompletedConfig) (http.Handler, error) {
+			return newConsumerAliasesHandler(
+				func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIExports().Get(ctx, name, metav1.GetOptions{})
+				},
+				func(ctx context.Context, clusterName logicalcluster.Name, export *apisv1alpha1.APIExport) error {
+					_, err := kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIExports().Update(ctx, export, metav1.UpdateOptions{})
+					return err
+				},
+			), nil
+		},
+		Authorizer: newConsumerAliasesAuthorizer(kubeClusterClient),
+	}
+
+	providerHealthContent := &handler.VirtualWorkspace{
+		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, ctx context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			apiDomain, prefixToStrip, ok := digestProviderHealthUrl(urlPath, rootPathPrefix)
+			if !ok {
+				return false, "", ctx
+			}
+			return true, prefixToStrip, dynamiccontext.WithAPIDomainKey(ctx, apiDomain)
+		}),
+		ReadyChecker: framework.ReadyFunc(func() error {
+			select {
+			case <-readyCh:
+				return nil
+			default:
+				return errors.New("apiexport virtual workspace controllers are not started")
+			}
+		}),
+		HandlerFactory: func(_ genericapiserver.CompletedConfig) (http.Handler, error) {
+			return newProviderHealthHandler(
+				func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIExports().Get(ctx, name, metav1.GetOptions{})
+				},
+				func(ctx context.Context, clusterName logicalcluster.Name, export *apisv1alpha1.APIExport) error {
+					_, err := kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIExports().UpdateStatus(ctx, export, metav1.UpdateOptions{})
+					return err
+				},
+				time.Now,
+			), nil
+		},
+		Authorizer: newProviderHealthAuthorizer(kubeClusterClient),
 	}
 
 	return []rootapiserver.NamedVirtualWorkspace{
 		{Name: VirtualWorkspaceName, VirtualWorkspace: boundOrClaimedWorkspaceContent},
+		{Name: VirtualWorkspaceName + "-" + consumerAliasesResource, VirtualWorkspace: consumerAliasesContent},
+		{Name: VirtualWorkspaceName + "-" + providerHealthResource, VirtualWorkspace: providerHealthContent},
 	}, nil
 }
 
@@ -257,16 +369,37 @@ func digestUrl(urlPath, rootPathPrefix string) (
 	return cluster, dynamiccontext.APIDomainKey(key), strings.TrimSuffix(urlPath, realPath), true
 }
 
-func newAuthorizer(kubeClusterClient, deepSARClient kcpkubernetesclientset.ClusterInterface, cachedKcpInformers kcpinformers.SharedInformerFactory) authorizer.Authorizer {
+func newAuthorizer(
+	kubeClusterClient, deepSARClient kcpkubernetesclientset.ClusterInterface,
+	cachedKcpInformers kcpinformers.SharedInformerFactory,
+	getAPIBinding func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error),
+) authorizer.Authorizer {
 	maximalPermissionAuth := virtualapiexportauth.NewMaximalPermissionAuthorizer(deepSARClient, cachedKcpInformers.Apis().V1alpha1().APIExports())
 	maximalPermissionAuth = authorization.NewDecorator("virtual.apiexport.maxpermissionpolicy.authorization.kcp.io", maximalPermissionAuth).AddAuditLogging().AddAnonymization().AddReasonAnnotation()
 
-	apiExportsContentAuth := virtualapiexportauth.NewAPIExportsContentAuthorizer(maximalPermissionAuth, kubeClusterClient)
+	resourceSelectorAuth := virtualapiexportauth.NewResourceSelectorAuthorizer(maximalPermissionAuth, cachedKcpInformers.Apis().V1alpha1().APIExports(), getAPIBinding)
+	resourceSelectorAuth = authorization.NewDecorator("virtual.apiexport.resourceselector.authorization.kcp.io", resourceSelectorAuth).AddAuditLogging().AddAnonymization().AddReasonAnnotation()
+
+	apiExportsContentAuth := virtualapiexportauth.NewAPIExportsContentAuthorizer(resourceSelectorAuth, kubeClusterClient)
 	apiExportsContentAuth = authorization.NewDecorator("virtual.apiexport.content.authorization.kcp.io", apiExportsContentAuth).AddAuditLogging().AddAnonymization()
 
 	return apiExportsContentAuth
 }
 
+func newConsumerAliasesAuthorizer(kubeClusterClient kcpkubernetesclientset.ClusterInterface) authorizer.Authorizer {
+	apiExportsContentAuth := virtualapiexportauth.NewAPIExportsContentAuthorizer(authorizerfactory.NewAlwaysAllowAuthorizer(), kubeClusterClient)
+	apiExportsContentAuth = authorization.NewDecorator("virtual.apiexport.consumeraliases.authorization.kcp.io", apiExportsContentAuth).AddAuditLogging().AddAnonymization()
+
+	return &consumerAliasesAuthorizer{delegate: apiExportsContentAuth}
+}
+
+func newProviderHealthAuthorizer(kubeClusterClient kcpkubernetesclientset.ClusterInterface) authorizer.Authorizer {
+	apiExportsContentAuth := virtualapiexportauth.NewAPIExportsContentAuthorizer(authorizerfactory.NewAlwaysAllowAuthorizer(), kubeClusterClient)
+	apiExportsContentAuth = authorization.NewDecorator("virtual.apiexport.providerhealth.authorization.kcp.io", apiExportsContentAuth).AddAuditLogging().AddAnonymization()
+
+	return &providerHealthAuthorizer{delegate: apiExportsContentAuth}
+}
+
 // apiDefinitionWithCancel calls the cancelFn on tear-down.
 type apiDefinitionWithCancel struct {
 	apidefinition.APIDefinition
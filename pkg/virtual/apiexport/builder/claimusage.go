@@ -0,0 +1,331 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	registry "github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+const (
+	// claimUsageSamplingInterval is the precision of the last used times of permission claims. Usage
+	// of a claim in a workspace is recorded at most once per interval.
+	claimUsageSamplingInterval = 10 * time.Minute
+
+	// claimUsageFlushInterval is how often recorded usage is written into the APIBinding status.
+	claimUsageFlushInterval = time.Minute
+)
+
+// claimUsageKey identifies a permission claim of an APIExport exercised in a consumer workspace.
+type claimUsageKey struct {
+	cluster       logicalcluster.Name
+	exportCluster logicalcluster.Name
+	exportName    string
+	group         string
+	resource      string
+	identityHash  string
+}
+
+// bindingKey identifies the APIBinding of an APIExport in a consumer workspace.
+type bindingKey struct {
+	cluster       logicalcluster.Name
+	exportCluster logicalcluster.Name
+	exportName    string
+}
+
+// claimUsageTracker records when permission claims are exercised through the virtual workspace,
+// and periodically writes the sampled last used times into the status of the APIBindings.
+type claimUsageTracker struct {
+	now func() time.Time
+
+	listAPIBindings        func(ctx context.Context, clusterName logicalcluster.Name) ([]apisv1alpha1.APIBinding, error)
+	updateAPIBindingStatus func(ctx context.Context, clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding) error
+
+	lock sync.Mutex
+	// pending holds the usage not written yet.
+	pending map[claimUsageKey]time.Time
+	// sampled holds the time usage was last recorded, to record at most once per sampling interval.
+	sampled map[claimUsageKey]time.Time
+}
+
+func newClaimUsageTracker(kcpClusterClient kcpclientset.ClusterInterface) *claimUsageTracker {
+	return &claimUsageTracker{
+		now: time.Now,
+		listAPIBindings: func(ctx context.Context, clusterName logicalcluster.Name) ([]apisv1alpha1.APIBinding, error) {
+			list, err := kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		},
+		updateAPIBindingStatus: func(ctx context.Context, clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding) error {
+			_, err := kcpClusterClient.Cluster(clusterName.Path()).ApisV1alpha1().APIBindings().UpdateStatus(ctx, apiBinding, metav1.UpdateOptions{})
+			return err
+		},
+		pending: map[claimUsageKey]time.Time{},
+		sampled: map[claimUsageKey]time.Time{},
+	}
+}
+
+// record records that the given claim was exercised now.
+func (t *claimUsageTracker) record(key claimUsageKey) {
+	now := t.now()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if last, found := t.sampled[key]; found && now.Sub(last) < claimUsageSamplingInterval {
+		return
+	}
+	t.sampled[key] = now
+	t.pending[key] = now
+}
+
+// Start writes the recorded usage periodically until the context is done.
+func (t *claimUsageTracker) Start(ctx context.Context) {
+	wait.UntilWithContext(ctx, t.flush, claimUsageFlushInterval)
+}
+
+func (t *claimUsageTracker) flush(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("component", "claim-usage-tracker")
+
+	t.lock.Lock()
+	pending := t.pending
+	t.pending = map[claimUsageKey]time.Time{}
+	now := t.now()
+	for key, last := range t.sampled {
+		if now.Sub(last) >= claimUsageSamplingInterval {
+			delete(t.sampled, key)
+		}
+	}
+	t.lock.Unlock()
+
+	byBinding := map[bindingKey]map[claimUsageKey]time.Time{}
+	for key, lastUsed := range pending {
+		bk := bindingKey{cluster: key.cluster, exportCluster: key.exportCluster, exportName: key.exportName}
+		if byBinding[bk] == nil {
+			byBinding[bk] = map[claimUsageKey]time.Time{}
+		}
+		byBinding[bk][key] = lastUsed
+	}
+
+	for bk, usage := range byBinding {
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			return t.updateBinding(ctx, bk, usage)
+		}); err != nil {
+			logger.Error(err, "failed to record permission claim usage", "cluster", bk.cluster, "exportCluster", bk.exportCluster, "exportName", bk.exportName)
+
+			// try again next time, unless newer usage has been recorded in the meantime
+			t.lock.Lock()
+			for key, lastUsed := range usage {
+				if _, found := t.pending[key]; !found {
+					t.pending[key] = lastUsed
+				}
+			}
+			t.lock.Unlock()
+		}
+	}
+}
+
+// updateBinding writes the usage of the claims into the status of the APIBinding of the APIExport.
+// Usage of claims that are not applied is dropped.
+func (t *claimUsageTracker) updateBinding(ctx context.Context, bk bindingKey, usage map[claimUsageKey]time.Time) error {
+	apiBindings, err := t.listAPIBindings(ctx, bk.cluster)
+	if err != nil {
+		return err
+	}
+
+	for i := range apiBindings {
+		apiBinding := &apiBindings[i]
+		if apiBinding.Spec.Reference.Export == nil || apiBinding.Spec.Reference.Export.Name != bk.exportName {
+			continue
+		}
+		if apiBinding.Status.APIExportClusterName != bk.exportCluster.String() {
+			continue
+		}
+
+		if !updateClaimsUsage(apiBinding, usage) {
+			return nil
+		}
+		return t.updateAPIBindingStatus(ctx, bk.cluster, apiBinding)
+	}
+
+	return nil
+}
+
+// updateClaimsUsage sets the last used times of the applied claims of the APIBinding to the given
+// usage, if it is more recent. It returns whether the APIBinding changed.
+func updateClaimsUsage(apiBinding *apisv1alpha1.APIBinding, usage map[claimUsageKey]time.Time) bool {
+	changed := false
+	for key, lastUsed := range usage {
+		applied := false
+		for _, claim := range apiBinding.Status.AppliedPermissionClaims {
+			if claim.Group == key.group && claim.Resource == key.resource && claim.IdentityHash == key.identityHash {
+				applied = true
+				break
+			}
+		}
+		if !applied {
+			continue
+		}
+
+		lastUsedTime := metav1.NewTime(lastUsed.Truncate(time.Second))
+		found := false
+		for i := range apiBinding.Status.PermissionClaimsUsage {
+			existing := &apiBinding.Status.PermissionClaimsUsage[i]
+			if existing.Group != key.group || existing.Resource != key.resource || existing.IdentityHash != key.identityHash {
+				continue
+			}
+			found = true
+			if existing.LastUsedTime.Before(&lastUsedTime) {
+				existing.LastUsedTime = lastUsedTime
+				changed = true
+			}
+			break
+		}
+		if !found {
+			apiBinding.Status.PermissionClaimsUsage = append(apiBinding.Status.PermissionClaimsUsage, apisv1alpha1.PermissionClaimUsage{
+				GroupResource: apisv1alpha1.GroupResource{Group: key.group, Resource: key.resource},
+				IdentityHash:  key.identityHash,
+				LastUsedTime:  lastUsedTime,
+			})
+			changed = true
+		}
+	}
+	return changed
+}
+
+// withClaimUsageTracking records the usage of a claimed resource for every request against it,
+// attributing wildcard requests to the workspaces of the returned objects.
+func withClaimUsageTracking(tracker *claimUsageTracker, identityHash string) registry.StorageWrapper {
+	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
+		recordFor := func(ctx context.Context, clusterName logicalcluster.Name) {
+			apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+			parts := strings.SplitN(string(apiDomainKey), "/", 2)
+			if len(parts) < 2 || clusterName.Empty() {
+				return
+			}
+			tracker.record(claimUsageKey{
+				cluster:       clusterName,
+				exportCluster: logicalcluster.Name(parts[0]),
+				exportName:    parts[1],
+				group:         resource.Group,
+				resource:      resource.Resource,
+				identityHash:  identityHash,
+			})
+		}
+		// record returns whether the request is against a single workspace, and records its usage.
+		record := func(ctx context.Context) bool {
+			cluster := genericapirequest.ClusterFrom(ctx)
+			if cluster == nil || cluster.Wildcard {
+				return false
+			}
+			recordFor(ctx, cluster.Name)
+			return true
+		}
+
+		delegateGetter := storage.GetterFunc
+		storage.GetterFunc = func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			record(ctx)
+			return delegateGetter.Get(ctx, name, options)
+		}
+
+		delegateCreater := storage.CreaterFunc
+		storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			record(ctx)
+			return delegateCreater.Create(ctx, obj, createValidation, options)
+		}
+
+		delegateUpdater := storage.UpdaterFunc
+		storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			record(ctx)
+			return delegateUpdater.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+		}
+
+		delegateGracefulDeleter := storage.GracefulDeleterFunc
+		storage.GracefulDeleterFunc = func(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+			record(ctx)
+			return delegateGracefulDeleter.Delete(ctx, name, deleteValidation, options)
+		}
+
+		delegateCollectionDeleter := storage.CollectionDeleterFunc
+		storage.CollectionDeleterFunc = func(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *metainternalversion.ListOptions) (runtime.Object, error) {
+			record(ctx)
+			return delegateCollectionDeleter.DeleteCollection(ctx, deleteValidation, options, listOptions)
+		}
+
+		delegateLister := storage.ListerFunc
+		storage.ListerFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+			if record(ctx) {
+				return delegateLister.List(ctx, options)
+			}
+			list, err := delegateLister.List(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+			seen := map[logicalcluster.Name]bool{}
+			_ = meta.EachListItem(list, func(obj runtime.Object) error {
+				if metaObj, ok := obj.(metav1.Object); ok {
+					if clusterName := logicalcluster.From(metaObj); !seen[clusterName] {
+						seen[clusterName] = true
+						recordFor(ctx, clusterName)
+					}
+				}
+				return nil
+			})
+			return list, nil
+		}
+
+		delegateWatcher := storage.WatcherFunc
+		storage.WatcherFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+			if record(ctx) {
+				return delegateWatcher.Watch(ctx, options)
+			}
+			w, err := delegateWatcher.Watch(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if metaObj, ok := event.Object.(metav1.Object); ok && event.Type != watch.Bookmark && event.Type != watch.Error {
+					recordFor(ctx, logicalcluster.From(metaObj))
+				}
+				return event, true
+			}), nil
+		}
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestClaimUsageTracker(t *testing.T) {
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+
+	apiBinding := apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cowboys"},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.BindingReference{
+				Export: &apisv1alpha1.ExportBindingReference{Path: "root:provider", Name: "cowboys"},
+			},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			APIExportClusterName: "provider",
+			AppliedPermissionClaims: []apisv1alpha1.PermissionClaim{
+				{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true},
+			},
+		},
+	}
+
+	var updated []apisv1alpha1.APIBinding
+	tracker := &claimUsageTracker{
+		now: func() time.Time { return now },
+		listAPIBindings: func(ctx context.Context, clusterName logicalcluster.Name) ([]apisv1alpha1.APIBinding, error) {
+			require.Equal(t, "consumer", clusterName.String())
+			binding := apiBinding.DeepCopy()
+			if len(updated) > 0 {
+				binding = updated[len(updated)-1].DeepCopy()
+			}
+			return []apisv1alpha1.APIBinding{*binding}, nil
+		},
+		updateAPIBindingStatus: func(ctx context.Context, clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding) error {
+			updated = append(updated, *apiBinding.DeepCopy())
+			return nil
+		},
+		pending: map[claimUsageKey]time.Time{},
+		sampled: map[claimUsageKey]time.Time{},
+	}
+
+	configMaps := claimUsageKey{cluster: "consumer", exportCluster: "provider", exportName: "cowboys", resource: "configmaps"}
+	secrets := claimUsageKey{cluster: "consumer", exportCluster: "provider", exportName: "cowboys", resource: "secrets"}
+
+	t.Log("Usage of an applied claim is written")
+	tracker.record(configMaps)
+	tracker.record(secrets)
+	tracker.flush(context.Background())
+	require.Len(t, updated, 1)
+	require.Equal(t, []apisv1alpha1.PermissionClaimUsage{
+		{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, LastUsedTime: metav1.NewTime(start)},
+	}, updated[0].Status.PermissionClaimsUsage)
+
+	t.Log("Usage within the sampling interval is not written")
+	now = start.Add(claimUsageSamplingInterval / 2)
+	tracker.record(configMaps)
+	tracker.flush(context.Background())
+	require.Len(t, updated, 1)
+
+	t.Log("Usage after the sampling interval is written")
+	now = start.Add(claimUsageSamplingInterval)
+	tracker.record(configMaps)
+	tracker.flush(context.Background())
+	require.Len(t, updated, 2)
+	require.Equal(t, metav1.NewTime(now), updated[1].Status.PermissionClaimsUsage[0].LastUsedTime)
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	registry "github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// consumerAliasesResource is the path segment of the endpoint managing the consumer aliases of an APIExport:
+//
+//	/services/apiexport/<apiexport-cluster>/<apiexport-name>/consumeraliases[/<consumer-cluster>]
+const consumerAliasesResource = "consumeraliases"
+
+// ConsumerAlias is an alias of a consumer logical cluster of an APIExport. It is the body of PUT requests
+// to the consumeraliases endpoint.
+type ConsumerAlias struct {
+	// Cluster is the logical cluster name of the consumer.
+	Cluster string `json:"cluster,omitempty"`
+	// Alias is a DNS label unique among the consumers of the APIExport.
+	Alias string `json:"alias"`
+}
+
+// ConsumerAliasList is the response of GET requests to the consumeraliases endpoint.
+type ConsumerAliasList struct {
+	Items []ConsumerAlias `json:"items"`
+}
+
+// consumerAliases returns the consumer aliases of the APIExport, by logical cluster name.
+func consumerAliases(export *apisv1alpha1.APIExport) (map[string]string, error) {
+	value, found := export.Annotations[apisv1alpha1.ConsumerAliasesAnnotationKey]
+	if !found {
+		return map[string]string{}, nil
+	}
+	aliases := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &aliases); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on APIExport %s|%s: %w", apisv1alpha1.ConsumerAliasesAnnotationKey, logicalcluster.From(export), export.Name, err)
+	}
+	return aliases, nil
+}
+
+func setConsumerAliases(export *apisv1alpha1.APIExport, aliases map[string]string) error {
+	if len(aliases) == 0 {
+		delete(export.Annotations, apisv1alpha1.ConsumerAliasesAnnotationKey)
+		return nil
+	}
+	bs, err := json.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	if export.Annotations == nil {
+		export.Annotations = map[string]string{}
+	}
+	export.Annotations[apisv1alpha1.ConsumerAliasesAnnotationKey] = string(bs)
+	return nil
+}
+
+// digestConsumerAliasesUrl accepts requests to the consumeraliases endpoint, and returns the API domain key
+// of the APIExport and the prefix to strip such that the path starts with /consumeraliases.
+func digestConsumerAliasesUrl(urlPath, rootPathPrefix string) (dynamiccontext.APIDomainKey, string, bool) {
+	if !strings.HasPrefix(urlPath, rootPathPrefix) {
+		return "", "", false
+	}
+
+	//  /services/apiexport/root:org:ws/<apiexport-name>/consumeraliases/<consumer-cluster>
+	//                     └────────────────────────┐
+	// Where the withoutRootPathPrefix starts here: ┘
+	parts := strings.SplitN(strings.TrimPrefix(urlPath, rootPathPrefix), "/", 4)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] != consumerAliasesResource {
+		return "", "", false
+	}
+	if len(parts) == 4 && (parts[3] == "" || strings.Contains(parts[3], "/")) {
+		return "", "", false
+	}
+
+	key := dynamiccontext.APIDomainKey(parts[0] + "/" + parts[1])
+	return key, rootPathPrefix + parts[0] + "/" + parts[1], true
+}
+
+// consumerAliasesAuthorizer authorizes requests to the consumeraliases endpoint with the verbs of the
+// apiexports/content subresource.
+type consumerAliasesAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+func (a *consumerAliasesAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	verb := attr.GetVerb()
+	switch verb {
+	case "get", "delete":
+	case "put":
+		verb = "update"
+	default:
+		return authorizer.DecisionDeny, fmt.Sprintf("verb %q is not supported for consumer aliases", verb), nil
+	}
+	return a.delegate.Authorize(ctx, authorizer.AttributesRecord{
+		User: attr.GetUser(),
+		Verb: verb,
+		Path: attr.GetPath(),
+	})
+}
+
+// newConsumerAliasesHandler serves the consumeraliases endpoint of APIExports:
+//
+//   - GET lists the aliases, or with a consumer cluster in the path returns its alias.
+//   - PUT sets the alias of the consumer cluster in the path from a ConsumerAlias.
+//   - DELETE removes the alias of the consumer cluster in the path.
+func newConsumerAliasesHandler(
+	getAPIExport func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error),
+	updateAPIExport func(ctx context.Context, clusterName logicalcluster.Name, export *apisv1alpha1.APIExport) error,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		parts := strings.SplitN(string(dynamiccontext.APIDomainKeyFrom(ctx)), "/", 2)
+		if len(parts) < 2 {
+			http.Error(w, "invalid API domain key", http.StatusInternalServerError)
+			return
+		}
+		exportCluster, exportName := logicalcluster.Name(parts[0]), parts[1]
+		consumer := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/"+consumerAliasesResource), "/")
+		if consumer != "" {
+			if !logicalcluster.Name(consumer).IsValid() {
+				http.Error(w, fmt.Sprintf("invalid logical cluster name %q", consumer), http.StatusBadRequest)
+				return
+			}
+		}
+
+		writeError := func(err error) {
+			if status, ok := err.(apierrors.APIStatus); ok {
+				http.Error(w, err.Error(), int(status.Status().Code))
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		writeJSON := func(obj interface{}) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(obj); err != nil {
+				klog.FromContext(ctx).Error(err, "failed to write consumer aliases response")
+			}
+		}
+		notFound := func() error {
+			return apierrors.NewNotFound(schema.GroupResource{Resource: consumerAliasesResource}, consumer)
+		}
+		// modify applies the given change to the aliases of the APIExport, retrying on conflicts.
+		modify := func(change func(aliases map[string]string) error) error {
+			return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				export, err := getAPIExport(ctx, exportCluster, exportName)
+				if err != nil {
+					return err
+				}
+				aliases, err := consumerAliases(export)
+				if err != nil {
+					return err
+				}
+				if err := change(aliases); err != nil {
+					return err
+				}
+				export = export.DeepCopy()
+				if err := setConsumerAliases(export, aliases); err != nil {
+					return err
+				}
+				return updateAPIExport(ctx, exportCluster, export)
+			})
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			export, err := getAPIExport(ctx, exportCluster, exportName)
+			if err != nil {
+				writeError(err)
+				return
+			}
+			aliases, err := consumerAliases(export)
+			if err != nil {
+				writeError(err)
+				return
+			}
+			if consumer != "" {
+				alias, found := aliases[consumer]
+				if !found {
+					writeError(notFound())
+					return
+				}
+				writeJSON(ConsumerAlias{Cluster: consumer, Alias: alias})
+				return
+			}
+			list := ConsumerAliasList{Items: []ConsumerAlias{}}
+			for cluster, alias := range aliases {
+				list.Items = append(list.Items, ConsumerAlias{Cluster: cluster, Alias: alias})
+			}
+			sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Cluster < list.Items[j].Cluster })
+			writeJSON(list)
+
+		case http.MethodPut:
+			if consumer == "" {
+				http.Error(w, "a consumer logical cluster is required", http.StatusBadRequest)
+				return
+			}
+			var body ConsumerAlias
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				http.Error(w, fmt.Sprintf("invalid consumer alias: %v", err), http.StatusBadRequest)
+				return
+			}
+			if body.Cluster != "" && body.Cluster != consumer {
+				http.Error(w, fmt.Sprintf("cluster %q does not match the path", body.Cluster), http.StatusBadRequest)
+				return
+			}
+			if errs := validation.IsDNS1123Label(body.Alias); len(errs) > 0 {
+				http.Error(w, fmt.Sprintf("invalid alias %q: %s", body.Alias, strings.Join(errs, ", ")), http.StatusBadRequest)
+				return
+			}
+			if err := modify(func(aliases map[string]string) error {
+				for cluster, alias := range aliases {
+					if alias == body.Alias && cluster != consumer {
+						// not a conflict error, which would be retried
+						return apierrors.NewAlreadyExists(schema.GroupResource{Resource: consumerAliasesResource}, body.Alias)
+					}
+				}
+				aliases[consumer] = body.Alias
+				return nil
+			}); err != nil {
+				writeError(err)
+				return
+			}
+			writeJSON(ConsumerAlias{Cluster: consumer, Alias: body.Alias})
+
+		case http.MethodDelete:
+			if consumer == "" {
+				http.Error(w, "a consumer logical cluster is required", http.StatusBadRequest)
+				return
+			}
+			if err := modify(func(aliases map[string]string) error {
+				if _, found := aliases[consumer]; !found {
+					return notFound()
+				}
+				delete(aliases, consumer)
+				return nil
+			}); err != nil {
+				writeError(err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, fmt.Sprintf("method %s is not supported", req.Method), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// consumerAliasCache caches the parsed consumer aliases per APIExport resource version.
+type consumerAliasCache struct {
+	getAPIExport func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+
+	lock    sync.Mutex
+	entries map[dynamiccontext.APIDomainKey]consumerAliasCacheEntry
+}
+
+func newConsumerAliasCache(getAPIExport func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)) *consumerAliasCache {
+	return &consumerAliasCache{
+		getAPIExport: getAPIExport,
+		entries:      map[dynamiccontext.APIDomainKey]consumerAliasCacheEntry{},
+	}
+}
+
+type consumerAliasCacheEntry struct {
+	resourceVersion string
+	aliases         map[string]string
+}
+
+// aliasesFor returns the consumer aliases of the APIExport of the API domain in the context.
+func (c *consumerAliasCache) aliasesFor(ctx context.Context) map[string]string {
+	key := dynamiccontext.APIDomainKeyFrom(ctx)
+	parts := strings.SplitN(string(key), "/", 2)
+	if len(parts) < 2 {
+		return nil
+	}
+	export, err := c.getAPIExport(logicalcluster.Name(parts[0]), parts[1])
+	if err != nil {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if entry, found := c.entries[key]; found && entry.resourceVersion == export.ResourceVersion {
+		return entry.aliases
+	}
+	aliases, err := consumerAliases(export)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "failed to parse consumer aliases")
+		return nil
+	}
+	c.entries[key] = consumerAliasCacheEntry{resourceVersion: export.ResourceVersion, aliases: aliases}
+	return aliases
+}
+
+// setConsumerAlias sets the consumer alias annotation of the object to the alias of its logical cluster,
+// or removes it if there is no alias.
+func setConsumerAlias(obj runtime.Object, aliases map[string]string) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	annotations := metaObj.GetAnnotations()
+	alias, found := aliases[logicalcluster.From(metaObj).String()]
+	if !found {
+		if _, exists := annotations[apisv1alpha1.ConsumerAliasAnnotationKey]; exists {
+			delete(annotations, apisv1alpha1.ConsumerAliasAnnotationKey)
+			metaObj.SetAnnotations(annotations)
+		}
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[apisv1alpha1.ConsumerAliasAnnotationKey] = alias
+	metaObj.SetAnnotations(annotations)
+}
+
+// withConsumerAliases adds the consumer alias annotation to the objects of wildcard lists and watches, and
+// removes it from written objects such that it is never persisted.
+func withConsumerAliases(aliases *consumerAliasCache) registry.StorageWrapper {
+	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
+		isWildcard := func(ctx context.Context) bool {
+			cluster := genericapirequest.ClusterFrom(ctx)
+			return cluster != nil && cluster.Wildcard
+		}
+
+		delegateCreater := storage.CreaterFunc
+		storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			setConsumerAlias(obj, nil)
+			return delegateCreater.Create(ctx, obj, createValidation, options)
+		}
+
+		delegateUpdater := storage.UpdaterFunc
+		storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			return delegateUpdater.Update(ctx, name, &consumerAliasStrippingObjectInfo{objInfo}, createValidation, updateValidation, forceAllowCreate, options)
+		}
+
+		delegateLister := storage.ListerFunc
+		storage.ListerFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+			list, err := delegateLister.List(ctx, options)
+			if err != nil || !isWildcard(ctx) {
+				return list, err
+			}
+			aliases := aliases.aliasesFor(ctx)
+			_ = meta.EachListItem(list, func(obj runtime.Object) error {
+				setConsumerAlias(obj, aliases)
+				return nil
+			})
+			return list, nil
+		}
+
+		delegateWatcher := storage.WatcherFunc
+		storage.WatcherFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+			w, err := delegateWatcher.Watch(ctx, options)
+			if err != nil || !isWildcard(ctx) {
+				return w, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if event.Type != watch.Bookmark && event.Type != watch.Error {
+					setConsumerAlias(event.Object, aliases.aliasesFor(ctx))
+				}
+				return event, true
+			}), nil
+		}
+	})
+}
+
+// consumerAliasStrippingObjectInfo removes the consumer alias annotation from the updated object.
+type consumerAliasStrippingObjectInfo struct {
+	rest.UpdatedObjectInfo
+}
+
+func (i *consumerAliasStrippingObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	obj, err := i.UpdatedObjectInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, err
+	}
+	setConsumerAlias(obj, nil)
+	return obj, nil
+}
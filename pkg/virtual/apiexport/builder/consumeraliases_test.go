@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestDigestConsumerAliasesUrl(t *testing.T) {
+	tests := map[string]struct {
+		urlPath  string
+		accepted bool
+		key      dynamiccontext.APIDomainKey
+		prefix   string
+	}{
+		"list":              {urlPath: "/services/apiexport/root:ws/export/consumeraliases", accepted: true, key: "root:ws/export", prefix: "/services/apiexport/root:ws/export"},
+		"consumer":          {urlPath: "/services/apiexport/root:ws/export/consumeraliases/abc", accepted: true, key: "root:ws/export", prefix: "/services/apiexport/root:ws/export"},
+		"content":           {urlPath: "/services/apiexport/root:ws/export/clusters/*/api/v1/configmaps"},
+		"nested":            {urlPath: "/services/apiexport/root:ws/export/consumeraliases/abc/def"},
+		"missing export":    {urlPath: "/services/apiexport/root:ws//consumeraliases"},
+		"other root prefix": {urlPath: "/services/other/root:ws/export/consumeraliases"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, prefix, accepted := digestConsumerAliasesUrl(tt.urlPath, "/services/apiexport/")
+			require.Equal(t, tt.accepted, accepted)
+			require.Equal(t, tt.key, key)
+			require.Equal(t, tt.prefix, prefix)
+		})
+	}
+}
+
+func TestConsumerAliasesHandler(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "export",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "provider"},
+		},
+	}
+	updates := 0
+	h := newConsumerAliasesHandler(
+		func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			require.Equal(t, logicalcluster.Name("provider"), clusterName)
+			require.Equal(t, "export", name)
+			return export, nil
+		},
+		func(ctx context.Context, clusterName logicalcluster.Name, updated *apisv1alpha1.APIExport) error {
+			updates++
+			export = updated
+			return nil
+		},
+	)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(dynamiccontext.WithAPIDomainKey(req.Context(), "provider/export"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Log("Assign aliases")
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/consumeraliases/abc", `{"alias":"acme-prod"}`).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/consumeraliases/def", `{"alias":"acme-dev"}`).Code)
+	require.Equal(t, `{"abc":"acme-prod","def":"acme-dev"}`, export.Annotations[apisv1alpha1.ConsumerAliasesAnnotationKey])
+
+	t.Log("Aliases are validated and unique")
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/consumeraliases/abc", `{"alias":"Not_A_Label"}`).Code)
+	require.Equal(t, http.StatusConflict, do(http.MethodPut, "/consumeraliases/xyz", `{"alias":"acme-prod"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/consumeraliases/abc", `{"cluster":"def","alias":"acme"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/consumeraliases", `{"alias":"acme"}`).Code)
+	require.Equal(t, 2, updates)
+
+	t.Log("Get and list aliases")
+	w := do(http.MethodGet, "/consumeraliases/abc", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"cluster":"abc","alias":"acme-prod"}`, w.Body.String())
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/consumeraliases/xyz", "").Code)
+	w = do(http.MethodGet, "/consumeraliases", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list ConsumerAliasList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, []ConsumerAlias{{Cluster: "abc", Alias: "acme-prod"}, {Cluster: "def", Alias: "acme-dev"}}, list.Items)
+
+	t.Log("Remove aliases")
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/consumeraliases/abc", "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/consumeraliases/abc", "").Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/consumeraliases/def", "").Code)
+	require.NotContains(t, export.Annotations, apisv1alpha1.ConsumerAliasesAnnotationKey)
+}
+
+func TestSetConsumerAlias(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: "abc"})
+
+	setConsumerAlias(obj, map[string]string{"abc": "acme-prod"})
+	require.Equal(t, "acme-prod", obj.GetAnnotations()[apisv1alpha1.ConsumerAliasAnnotationKey])
+
+	setConsumerAlias(obj, map[string]string{"def": "acme-dev"})
+	require.NotContains(t, obj.GetAnnotations(), apisv1alpha1.ConsumerAliasAnnotationKey, "aliases set by consumers must be removed")
+	require.Equal(t, "abc", obj.GetAnnotations()[logicalcluster.AnnotationKey])
+}
@@ -19,20 +19,25 @@ package builder
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
 	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/kube-openapi/pkg/validation/validate"
 
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
 	registry "github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
@@ -50,9 +55,132 @@ func provideAPIExportFilteredRestStorage(ctx context.Context, dynamicClusterClie
 	return registry.ProvideReadOnlyRestStorage(ctx, dynamicClusterClientFunc, registry.WithStaticLabelSelector(requirements), nil)
 }
 
+// providerQuotaLabelValue returns the value of the provider quota label for the APIExport
+// of the API domain in the context.
+func providerQuotaLabelValue(ctx context.Context) (string, bool) {
+	apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+	parts := strings.SplitN(string(apiDomainKey), "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return permissionclaims.ToAPIBindingExportLabelValue(logicalcluster.Name(parts[0]), parts[1]), true
+}
+
+// claimedResourceSelectors returns the filter of the objects of the claimed resource selected by
+// the permission claim as accepted by the APIBinding of the logical cluster of the objects, i.e.
+// with the resource selectors the consumer has agreed to. The APIExport and APIBinding are looked
+// up on every request, such that changed resource selectors apply immediately. Objects of logical
+// clusters that have not accepted the claim are not visible. Claims of all objects accepted for a
+// single logical cluster are not filtered.
+func claimedResourceSelectors(
+	getAPIExport func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error),
+	getAPIBinding func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error),
+	resource schema.GroupResource,
+) func(ctx context.Context) (registry.ObjectFilter, error) {
+	return func(ctx context.Context) (registry.ObjectFilter, error) {
+		apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+		parts := strings.SplitN(string(apiDomainKey), "/", 2)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API domain key %q", apiDomainKey)
+		}
+		apiExport, err := getAPIExport(logicalcluster.Name(parts[0]), parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		var claim *apisv1alpha1.PermissionClaim
+		for i := range apiExport.Spec.PermissionClaims {
+			if c := &apiExport.Spec.PermissionClaims[i]; c.Group == resource.Group && c.Resource == resource.Resource {
+				claim = c
+				break
+			}
+		}
+		if claim == nil {
+			return nil, nil
+		}
+
+		acceptedClaim := func(clusterName logicalcluster.Name) (*apisv1alpha1.PermissionClaim, error) {
+			apiBinding, err := getAPIBinding(clusterName, apiExport)
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			} else if err != nil {
+				return nil, err
+			}
+			if accepted, found := permissionclaims.AcceptedClaim(apiBinding, *claim); found {
+				return &accepted, nil
+			}
+			return nil, nil
+		}
+
+		if cluster := genericapirequest.ClusterFrom(ctx); cluster != nil && !cluster.Wildcard && !cluster.Name.Empty() {
+			accepted, err := acceptedClaim(cluster.Name)
+			if err != nil {
+				return nil, err
+			}
+			if accepted == nil {
+				return func(obj metav1.Object) (bool, error) {
+					return false, nil
+				}, nil
+			}
+			if accepted.All {
+				return nil, nil
+			}
+			return func(obj metav1.Object) (bool, error) {
+				return permissionclaims.Selects(*accepted, obj)
+			}, nil
+		}
+
+		return func(obj metav1.Object) (bool, error) {
+			accepted, err := acceptedClaim(logicalcluster.From(obj))
+			if err != nil || accepted == nil {
+				return false, err
+			}
+			return permissionclaims.Selects(*accepted, obj)
+		}, nil
+	}
+}
+
+// boundAPIBinding returns the APIBinding of the given logical cluster bound to the APIExport.
+func boundAPIBinding(apiBindings []*apisv1alpha1.APIBinding, apiExport *apisv1alpha1.APIExport, clusterName logicalcluster.Name) (*apisv1alpha1.APIBinding, error) {
+	for _, apiBinding := range apiBindings {
+		if apiBinding.Spec.Reference.Export == nil || apiBinding.Spec.Reference.Export.Name != apiExport.Name {
+			continue
+		}
+		if apiBinding.Status.APIExportClusterName != logicalcluster.From(apiExport).String() {
+			continue
+		}
+		return apiBinding, nil
+	}
+	return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apibindings"), fmt.Sprintf("%s|%s", clusterName, apiExport.Name))
+}
+
+// withProviderQuotaLabel labels objects created through the virtual workspace with the
+// provider quota label of the APIExport, such that they are charged against the quota
+// bucket of the provider in the consumer workspace.
+func withProviderQuotaLabel() registry.StorageWrapper {
+	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
+		delegateCreater := storage.CreaterFunc
+		storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			if value, ok := providerQuotaLabelValue(ctx); ok {
+				metaObj, ok := obj.(metav1.Object)
+				if !ok {
+					return nil, fmt.Errorf("expected a metav1.Object, got %T", obj)
+				}
+				objLabels := metaObj.GetLabels()
+				if objLabels == nil {
+					objLabels = map[string]string{}
+				}
+				objLabels[apisv1alpha1.ProviderQuotaLabelKey] = value
+				metaObj.SetLabels(objLabels)
+			}
+			return delegateCreater.Create(ctx, obj, createValidation, options)
+		}
+	})
+}
+
 // provideDelegatingRestStorage returns a forwarding storage build function, with an optional storage wrapper e.g. to add label based filtering.
 func provideDelegatingRestStorage(ctx context.Context, dynamicClusterClientFunc registry.DynamicClusterClientFunc, apiExportIdentityHash string, wrapper registry.StorageWrapper) apiserver.RestProviderFunc {
-	return func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, structuralSchema *structuralschema.Structural) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage) {
+	return func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, scaleSpec *apiextensions.CustomResourceSubresourceScale, structuralSchema *structuralschema.Structural) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage) {
 		statusSchemaValidate, statusEnabled := subresourcesSchemaValidator["status"]
 
 		var statusSpec *apiextensions.CustomResourceSubresourceStatus
@@ -60,9 +188,6 @@ func provideDelegatingRestStorage(ctx context.Context, dynamicClusterClientFunc
 			statusSpec = &apiextensions.CustomResourceSubresourceStatus{}
 		}
 
-		var scaleSpec *apiextensions.CustomResourceSubresourceScale
-		// TODO(sttts): implement scale subresource
-
 		strategy := customresource.NewStrategy(
 			typer,
 			namespaceScoped,
@@ -117,7 +242,30 @@ func provideDelegatingRestStorage(ctx context.Context, dynamicClusterClientFunc
 			}
 		}
 
-		// TODO(sttts): add scale subresource
+		if scaleSpec != nil {
+			scaleStorage := registry.NewScaleStorage(
+				resource,
+				apiExportIdentityHash,
+				namespaceScoped,
+				dynamicClusterClientFunc,
+				storage,
+				nil,
+			)
+			subresourceStorages["scale"] = &struct {
+				registry.FactoryFunc
+				registry.DestroyerFunc
+
+				registry.GetterFunc
+				registry.UpdaterFunc
+				// patch is implicit as we have get + update
+			}{
+				FactoryFunc:   scaleStorage.FactoryFunc,
+				DestroyerFunc: scaleStorage.DestroyerFunc,
+
+				GetterFunc:  scaleStorage.GetterFunc,
+				UpdaterFunc: scaleStorage.UpdaterFunc,
+			}
+		}
 
 		return &struct {
 			registry.FactoryFunc
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestClaimedResourceSelectors(t *testing.T) {
+	configmaps := apisv1alpha1.GroupResource{Resource: "configmaps"}
+	apiExport := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "export", Annotations: map[string]string{logicalcluster.AnnotationKey: "provider"}},
+		Spec: apisv1alpha1.APIExportSpec{
+			PermissionClaims: []apisv1alpha1.PermissionClaim{
+				{GroupResource: configmaps, ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default"}, {Namespace: "other"}}},
+			},
+		},
+	}
+	// the consumer has accepted the claim before the APIExport added the other namespace.
+	apiBindings := map[logicalcluster.Name]*apisv1alpha1.APIBinding{
+		"consumer": {
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Annotations: map[string]string{logicalcluster.AnnotationKey: "consumer"}},
+			Spec: apisv1alpha1.APIBindingSpec{
+				PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+					{
+						PermissionClaim: apisv1alpha1.PermissionClaim{GroupResource: configmaps, ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default"}}},
+						State:           apisv1alpha1.ClaimAccepted,
+					},
+				},
+			},
+		},
+		"rejecting": {
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Annotations: map[string]string{logicalcluster.AnnotationKey: "rejecting"}},
+			Spec: apisv1alpha1.APIBindingSpec{
+				PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+					{PermissionClaim: apiExport.Spec.PermissionClaims[0], State: apisv1alpha1.ClaimRejected},
+				},
+			},
+		},
+	}
+	filterFrom := claimedResourceSelectors(
+		func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExport, nil
+		},
+		func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error) {
+			if apiBinding, found := apiBindings[clusterName]; found {
+				return apiBinding, nil
+			}
+			return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apibindings"), "")
+		},
+		schema.GroupResource{Resource: "configmaps"},
+	)
+	object := func(cluster logicalcluster.Name, namespace string) metav1.Object {
+		return &metav1.ObjectMeta{Name: "cm", Namespace: namespace, Annotations: map[string]string{logicalcluster.AnnotationKey: cluster.String()}}
+	}
+
+	tests := map[string]struct {
+		cluster genericapirequest.Cluster
+		obj     metav1.Object
+		want    bool
+	}{
+		"accepted namespace":                  {cluster: genericapirequest.Cluster{Name: "consumer"}, obj: object("consumer", "default"), want: true},
+		"namespace claimed but not accepted":  {cluster: genericapirequest.Cluster{Name: "consumer"}, obj: object("consumer", "other")},
+		"rejected claim":                      {cluster: genericapirequest.Cluster{Name: "rejecting"}, obj: object("rejecting", "default")},
+		"no binding":                          {cluster: genericapirequest.Cluster{Name: "unbound"}, obj: object("unbound", "default")},
+		"wildcard, accepted namespace":        {cluster: genericapirequest.Cluster{Wildcard: true}, obj: object("consumer", "default"), want: true},
+		"wildcard, namespace not accepted":    {cluster: genericapirequest.Cluster{Wildcard: true}, obj: object("consumer", "other")},
+		"wildcard, cluster rejecting a claim": {cluster: genericapirequest.Cluster{Wildcard: true}, obj: object("rejecting", "default")},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := dynamiccontext.WithAPIDomainKey(context.Background(), "provider/export")
+			ctx = genericapirequest.WithCluster(ctx, tt.cluster)
+			filter, err := filterFrom(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, filter)
+			got, err := filter(tt.obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// providerHealthResource is the path segment of the endpoint the provider of an APIExport reports its health to:
+//
+//	/services/apiexport/<apiexport-cluster>/<apiexport-name>/providerhealth
+const providerHealthResource = "providerhealth"
+
+// defaultProviderLeaseDurationSeconds is the lease duration of heartbeats that do not specify one.
+const defaultProviderLeaseDurationSeconds = 60
+
+// ProviderHeartbeat is the body of PUT requests to the providerhealth endpoint.
+type ProviderHeartbeat struct {
+	// Healthy is whether the service backing the APIExport is healthy.
+	Healthy bool `json:"healthy"`
+	// Message optionally describes the health of the service.
+	Message string `json:"message,omitempty"`
+	// LeaseDurationSeconds is the duration after which the provider is considered unhealthy if it does
+	// not report again. It defaults to 60 seconds.
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+}
+
+// digestProviderHealthUrl accepts requests to the providerhealth endpoint, and returns the API domain key
+// of the APIExport and the prefix to strip such that the path is /providerhealth.
+func digestProviderHealthUrl(urlPath, rootPathPrefix string) (dynamiccontext.APIDomainKey, string, bool) {
+	if !strings.HasPrefix(urlPath, rootPathPrefix) {
+		return "", "", false
+	}
+
+	//  /services/apiexport/root:org:ws/<apiexport-name>/providerhealth
+	//                     └────────────────────────┐
+	// Where the withoutRootPathPrefix starts here: ┘
+	parts := strings.Split(strings.TrimPrefix(urlPath, rootPathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != providerHealthResource {
+		return "", "", false
+	}
+
+	key := dynamiccontext.APIDomainKey(parts[0] + "/" + parts[1])
+	return key, rootPathPrefix + parts[0] + "/" + parts[1], true
+}
+
+// providerHealthAuthorizer authorizes requests to the providerhealth endpoint with the verbs of the
+// apiexports/content subresource.
+type providerHealthAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+func (a *providerHealthAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	verb := attr.GetVerb()
+	switch verb {
+	case "get":
+	case "put":
+		verb = "update"
+	default:
+		return authorizer.DecisionDeny, fmt.Sprintf("verb %q is not supported for provider health", verb), nil
+	}
+	return a.delegate.Authorize(ctx, authorizer.AttributesRecord{
+		User: attr.GetUser(),
+		Verb: verb,
+		Path: attr.GetPath(),
+	})
+}
+
+// newProviderHealthHandler serves the providerhealth endpoint of APIExports:
+//
+//   - GET returns the last reported health of the provider.
+//   - PUT records a ProviderHeartbeat as the health of the provider in the APIExport status.
+func newProviderHealthHandler(
+	getAPIExport func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error),
+	updateAPIExportStatus func(ctx context.Context, clusterName logicalcluster.Name, export *apisv1alpha1.APIExport) error,
+	now func() time.Time,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		parts := strings.SplitN(string(dynamiccontext.APIDomainKeyFrom(ctx)), "/", 2)
+		if len(parts) < 2 {
+			http.Error(w, "invalid API domain key", http.StatusInternalServerError)
+			return
+		}
+		exportCluster, exportName := logicalcluster.Name(parts[0]), parts[1]
+
+		writeError := func(err error) {
+			if status, ok := err.(apierrors.APIStatus); ok {
+				http.Error(w, err.Error(), int(status.Status().Code))
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		writeJSON := func(obj interface{}) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(obj); err != nil {
+				klog.FromContext(ctx).Error(err, "failed to write provider health response")
+			}
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			export, err := getAPIExport(ctx, exportCluster, exportName)
+			if err != nil {
+				writeError(err)
+				return
+			}
+			if export.Status.ProviderHealth == nil {
+				writeError(apierrors.NewNotFound(schema.GroupResource{Resource: providerHealthResource}, exportName))
+				return
+			}
+			writeJSON(export.Status.ProviderHealth)
+
+		case http.MethodPut:
+			var heartbeat ProviderHeartbeat
+			if err := json.NewDecoder(req.Body).Decode(&heartbeat); err != nil {
+				http.Error(w, fmt.Sprintf("invalid provider heartbeat: %v", err), http.StatusBadRequest)
+				return
+			}
+			if heartbeat.LeaseDurationSeconds < 0 {
+				http.Error(w, "leaseDurationSeconds must not be negative", http.StatusBadRequest)
+				return
+			}
+			if heartbeat.LeaseDurationSeconds == 0 {
+				heartbeat.LeaseDurationSeconds = defaultProviderLeaseDurationSeconds
+			}
+
+			health := &apisv1alpha1.ProviderHealth{
+				Healthy:              heartbeat.Healthy,
+				Message:              heartbeat.Message,
+				LastHeartbeatTime:    metav1.NewTime(now().Truncate(time.Second)),
+				LeaseDurationSeconds: heartbeat.LeaseDurationSeconds,
+			}
+			if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				export, err := getAPIExport(ctx, exportCluster, exportName)
+				if err != nil {
+					return err
+				}
+				export = export.DeepCopy()
+				export.Status.ProviderHealth = health
+				return updateAPIExportStatus(ctx, exportCluster, export)
+			}); err != nil {
+				writeError(err)
+				return
+			}
+			writeJSON(health)
+
+		default:
+			http.Error(w, fmt.Sprintf("method %s is not supported", req.Method), http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestDigestProviderHealthUrl(t *testing.T) {
+	tests := map[string]struct {
+		urlPath  string
+		accepted bool
+		key      dynamiccontext.APIDomainKey
+		prefix   string
+	}{
+		"health":            {urlPath: "/services/apiexport/root:ws/export/providerhealth", accepted: true, key: "root:ws/export", prefix: "/services/apiexport/root:ws/export"},
+		"nested":            {urlPath: "/services/apiexport/root:ws/export/providerhealth/abc"},
+		"content":           {urlPath: "/services/apiexport/root:ws/export/clusters/*/api/v1/configmaps"},
+		"missing export":    {urlPath: "/services/apiexport/root:ws//providerhealth"},
+		"other root prefix": {urlPath: "/services/other/root:ws/export/providerhealth"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, prefix, accepted := digestProviderHealthUrl(tt.urlPath, "/services/apiexport/")
+			require.Equal(t, tt.accepted, accepted)
+			require.Equal(t, tt.key, key)
+			require.Equal(t, tt.prefix, prefix)
+		})
+	}
+}
+
+func TestProviderHealthHandler(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "export",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "provider"},
+		},
+	}
+	now := time.Date(2023, 5, 1, 12, 0, 0, 500, time.UTC)
+	h := newProviderHealthHandler(
+		func(ctx context.Context, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			require.Equal(t, logicalcluster.Name("provider"), clusterName)
+			require.Equal(t, "export", name)
+			return export, nil
+		},
+		func(ctx context.Context, clusterName logicalcluster.Name, updated *apisv1alpha1.APIExport) error {
+			export = updated
+			return nil
+		},
+		func() time.Time { return now },
+	)
+	do := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/providerhealth", strings.NewReader(body))
+		req = req.WithContext(dynamiccontext.WithAPIDomainKey(req.Context(), "provider/export"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Log("Nothing reported yet")
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "").Code)
+
+	t.Log("Report a heartbeat with the default lease")
+	require.Equal(t, http.StatusOK, do(http.MethodPut, `{"healthy":true}`).Code)
+	require.Equal(t, &apisv1alpha1.ProviderHealth{
+		Healthy:              true,
+		LastHeartbeatTime:    metav1.NewTime(now.Truncate(time.Second)),
+		LeaseDurationSeconds: defaultProviderLeaseDurationSeconds,
+	}, export.Status.ProviderHealth)
+
+	t.Log("Report an unhealthy provider")
+	now = now.Add(10 * time.Second)
+	require.Equal(t, http.StatusOK, do(http.MethodPut, `{"healthy":false,"message":"database down","leaseDurationSeconds":30}`).Code)
+	w := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"healthy":false,"message":"database down","lastHeartbeatTime":"2023-05-01T12:00:10Z","leaseDurationSeconds":30}`, w.Body.String())
+
+	t.Log("Invalid heartbeats are rejected")
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, `{"healthy":"yes"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, `{"healthy":true,"leaseDurationSeconds":-1}`).Code)
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodDelete, "").Code)
+	require.Equal(t, "database down", export.Status.ProviderHealth.Message)
+}
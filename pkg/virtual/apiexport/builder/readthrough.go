@@ -0,0 +1,376 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpdynamicinformer "github.com/kcp-dev/client-go/dynamic/dynamicinformer"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kaudit "k8s.io/apiserver/pkg/audit"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	registry "github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1/permissionclaims"
+)
+
+// readThroughReferenceAuditAnnotationKey is the audit annotation naming the object a read-through
+// claimed object was read for.
+const readThroughReferenceAuditAnnotationKey = "apiexport.virtual.kcp.io/read-through-reference"
+
+// readThroughClaims serves the objects of read-through permission claims, i.e. claims with
+// referencedBy. These objects are not labelled as claimed. They are fetched bypassing the
+// label selector of the claimed resource, and only if the APIBinding has accepted the claim,
+// and an object of the referencing resource in the same logical cluster and namespace holds
+// their name. The provider cannot set these names itself, see referenceGuard.
+type readThroughClaims struct {
+	getAPIExport         func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIBinding        func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	dynamicClusterClient registry.DynamicClusterClientFunc
+	referencers          *referencers
+}
+
+// wrapper returns the storage wrapper for the given version and identity of a claimed resource.
+// It must be applied last, such that it decorates the label filtered storage.
+func (c *readThroughClaims) wrapper(version, identityHash string) registry.StorageWrapper {
+	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
+		delegateGetter := storage.GetterFunc
+		storage.GetterFunc = func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			apiExport, claim, err := c.readThroughClaim(ctx, resource)
+			if err != nil {
+				return nil, err
+			}
+			if claim == nil {
+				return delegateGetter.Get(ctx, name, options)
+			}
+
+			gvr := resource.WithVersion(version)
+			if identityHash != "" {
+				gvr.Resource += ":" + identityHash
+			}
+			return c.get(ctx, apiExport, *claim, resource, gvr, name, options)
+		}
+	})
+}
+
+// referenceGuard returns the storage wrapper for the exported resources. It forbids the provider
+// to set or change the name fields of read-through claims referencing the resource, such that
+// only names written in the consumer workspace give access to the referenced objects. Clearing
+// a name is allowed. Names set before the claim was added to the APIExport are left as they
+// are, the consumer accepts them together with the claim.
+func (c *readThroughClaims) referenceGuard() registry.StorageWrapper {
+	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
+		delegateCreater := storage.CreaterFunc
+		storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			if err := c.checkReferences(ctx, resource, nil, obj); err != nil {
+				return nil, err
+			}
+			return delegateCreater.Create(ctx, obj, createValidation, options)
+		}
+
+		// patches and server-side apply requests are updates of the forwarding storage as well.
+		delegateUpdater := storage.UpdaterFunc
+		storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			guardedObjInfo := &referenceGuardedObjectInfo{
+				UpdatedObjectInfo: objInfo,
+				check: func(ctx context.Context, oldObj, newObj runtime.Object) error {
+					return c.checkReferences(ctx, resource, oldObj, newObj)
+				},
+			}
+			return delegateUpdater.Update(ctx, name, guardedObjInfo, createValidation, updateValidation, forceAllowCreate, options)
+		}
+	})
+}
+
+// checkReferences returns a Forbidden error if newObj names another object than oldObj in the
+// name field of a read-through claim of the APIExport of the request. oldObj is nil on create.
+func (c *readThroughClaims) checkReferences(ctx context.Context, resource schema.GroupResource, oldObj, newObj runtime.Object) error {
+	apiExport, err := c.apiExportFrom(ctx)
+	if err != nil {
+		return err
+	}
+	for _, claim := range apiExport.Spec.PermissionClaims {
+		reference := claim.ReferencedBy
+		if reference == nil || reference.Group != resource.Group || reference.Resource != resource.Resource {
+			continue
+		}
+		name := referencedName(newObj, reference.NameField)
+		if name == "" || name == referencedName(oldObj, reference.NameField) {
+			continue
+		}
+		var objName string
+		if metaObj, ok := newObj.(metav1.Object); ok {
+			objName = metaObj.GetName()
+		}
+		return apierrors.NewForbidden(resource, objName, fmt.Errorf("%s names %s read through by API export %s|%s and can only be set in the consumer workspace",
+			reference.NameField, schema.GroupResource{Group: claim.Group, Resource: claim.Resource}, logicalcluster.From(apiExport), apiExport.Name))
+	}
+	return nil
+}
+
+// referencedName returns the name held in the field at the dot-separated path of obj, or ""
+// if obj is nil or the field is not set.
+func referencedName(obj runtime.Object, nameField string) string {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u == nil {
+		return ""
+	}
+	value, _, _ := unstructured.NestedString(u.Object, strings.Split(nameField, ".")...)
+	return value
+}
+
+// referenceGuardedObjectInfo checks the updated object before it is forwarded.
+type referenceGuardedObjectInfo struct {
+	rest.UpdatedObjectInfo
+	check func(ctx context.Context, oldObj, newObj runtime.Object) error
+}
+
+func (i *referenceGuardedObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	newObj, err := i.UpdatedObjectInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.check(ctx, oldObj, newObj); err != nil {
+		return nil, err
+	}
+	return newObj, nil
+}
+
+// apiExportFrom returns the APIExport of the request.
+func (c *readThroughClaims) apiExportFrom(ctx context.Context) (*apisv1alpha1.APIExport, error) {
+	apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+	parts := strings.SplitN(string(apiDomainKey), "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid API domain key %q", apiDomainKey)
+	}
+	return c.getAPIExport(logicalcluster.Name(parts[0]), parts[1])
+}
+
+// readThroughClaim returns the APIExport of the request and its read-through claim of the given
+// resource, or nil if the resource is not claimed read-through.
+func (c *readThroughClaims) readThroughClaim(ctx context.Context, resource schema.GroupResource) (*apisv1alpha1.APIExport, *apisv1alpha1.PermissionClaim, error) {
+	apiExport, err := c.apiExportFrom(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range apiExport.Spec.PermissionClaims {
+		claim := &apiExport.Spec.PermissionClaims[i]
+		if claim.Group != resource.Group || claim.Resource != resource.Resource {
+			continue
+		}
+		if claim.ReferencedBy == nil {
+			return apiExport, nil, nil
+		}
+		return apiExport, claim, nil
+	}
+	return apiExport, nil, nil
+}
+
+func (c *readThroughClaims) get(ctx context.Context, apiExport *apisv1alpha1.APIExport, claim apisv1alpha1.PermissionClaim, resource schema.GroupResource, gvr schema.GroupVersionResource, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if cluster.Wildcard {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("%s are claimed read-through and can only be read in a single logical cluster", resource))
+	}
+	namespace, _ := genericapirequest.NamespaceFrom(ctx)
+
+	// the consumer must have accepted reading through the same references.
+	apiBinding, err := c.getAPIBinding(cluster.Name, apiExport)
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewNotFound(resource, name)
+	} else if err != nil {
+		return nil, err
+	}
+	accepted, found := permissionclaims.AcceptedClaim(apiBinding, claim)
+	if !found || accepted.ReferencedBy == nil || *accepted.ReferencedBy != *claim.ReferencedBy {
+		return nil, apierrors.NewNotFound(resource, name)
+	}
+	reference := accepted.ReferencedBy
+
+	referencingGR := schema.GroupResource{Group: reference.Group, Resource: reference.Resource}
+	referencingGVR, err := c.referencingResource(apiExport, referencingGR)
+	if err != nil {
+		return nil, err
+	}
+
+	// referencing objects of other namespaces, or cluster-scoped ones for namespaced objects, do not count.
+	referencer, found, err := c.referencers.find(ctx, referencingGVR, reference.NameField, cluster.Name, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, apierrors.NewNotFound(resource, name)
+	}
+	kaudit.AddAuditAnnotation(ctx, readThroughReferenceAuditAnnotationKey, fmt.Sprintf("%s %s", referencingGR, referencer))
+
+	dynamicClusterClient, err := c.dynamicClusterClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error generating dynamic client: %w", err)
+	}
+	client := dynamicClusterClient.Cluster(cluster.Name.Path())
+	var referencedClient dynamic.ResourceInterface = client.Resource(gvr)
+	if namespace != "" {
+		referencedClient = client.Resource(gvr).Namespace(namespace)
+	}
+	return referencedClient.Get(ctx, name, *options)
+}
+
+// referencingResource returns the resource of the APIExport referencing the objects of a
+// read-through claim, in its storage version and with the identity of the APIExport.
+func (c *readThroughClaims) referencingResource(apiExport *apisv1alpha1.APIExport, gr schema.GroupResource) (schema.GroupVersionResource, error) {
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		apiResourceSchema, err := c.getAPIResourceSchema(logicalcluster.From(apiExport), schemaName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		if apiResourceSchema.Spec.Group != gr.Group || apiResourceSchema.Spec.Names.Plural != gr.Resource {
+			continue
+		}
+
+		for _, version := range apiResourceSchema.Spec.Versions {
+			if !version.Storage {
+				continue
+			}
+			gvr := gr.WithVersion(version.Name)
+			gvr.Resource += ":" + apiExport.Status.IdentityHash
+			return gvr, nil
+		}
+	}
+	return schema.GroupVersionResource{}, apierrors.NewServiceUnavailable(fmt.Sprintf("resource %s referencing read-through claimed objects is not exported by API export %s|%s",
+		gr, logicalcluster.From(apiExport), apiExport.Name))
+}
+
+// referencers finds the objects of referencing resources holding a given name. It keeps one
+// informer across all logical clusters per referencing resource and name field, started on first
+// use, indexed by logical cluster, namespace and the name held.
+type referencers struct {
+	client kcpdynamic.ClusterInterface
+
+	lock      sync.Mutex
+	ctx       context.Context
+	informers map[referencersKey]cache.SharedIndexInformer
+}
+
+type referencersKey struct {
+	gvr       schema.GroupVersionResource
+	nameField string
+}
+
+const byReferencedName = "byReferencedName"
+
+func newReferencers(client kcpdynamic.ClusterInterface) *referencers {
+	return &referencers{
+		client:    client,
+		informers: map[referencersKey]cache.SharedIndexInformer{},
+	}
+}
+
+// start enables starting informers, which run until the context is done.
+func (r *referencers) start(ctx context.Context) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ctx = ctx
+}
+
+// find returns the namespace/name of an object of the referencing resource in the given logical
+// cluster and namespace holding the name in the field at the dot-separated path.
+func (r *referencers) find(ctx context.Context, gvr schema.GroupVersionResource, nameField string, clusterName logicalcluster.Name, namespace, name string) (string, bool, error) {
+	informer, err := r.informerFor(gvr, nameField)
+	if err != nil {
+		return "", false, err
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return "", false, apierrors.NewServiceUnavailable(fmt.Sprintf("objects of %s referencing read-through claimed objects are not synced", gvr.GroupResource()))
+	}
+
+	objs, err := informer.GetIndexer().ByIndex(byReferencedName, kcpcache.ToClusterAwareKey(clusterName.String(), namespace, name))
+	if err != nil {
+		return "", false, err
+	}
+	var referencers []string
+	for _, obj := range objs {
+		referencer, ok := obj.(metav1.Object)
+		if !ok {
+			continue
+		}
+		if ns := referencer.GetNamespace(); ns != "" {
+			referencers = append(referencers, ns+"/"+referencer.GetName())
+		} else {
+			referencers = append(referencers, referencer.GetName())
+		}
+	}
+	if len(referencers) == 0 {
+		return "", false, nil
+	}
+	sort.Strings(referencers)
+	return referencers[0], true, nil
+}
+
+func (r *referencers) informerFor(gvr schema.GroupVersionResource, nameField string) (cache.SharedIndexInformer, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.ctx == nil {
+		return nil, apierrors.NewServiceUnavailable("read-through claims are not served yet")
+	}
+	key := referencersKey{gvr: gvr, nameField: nameField}
+	if informer, found := r.informers[key]; found {
+		return informer, nil
+	}
+
+	fields := strings.Split(nameField, ".")
+	informer := kcpdynamicinformer.NewFilteredDynamicInformer(r.client, gvr, 0, cache.Indexers{
+		byReferencedName: func(obj interface{}) ([]string, error) {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return nil, fmt.Errorf("obj is supposed to be an Unstructured, but is %T", obj)
+			}
+			value, found, err := unstructured.NestedString(u.Object, fields...)
+			if err != nil || !found || value == "" {
+				return nil, nil //nolint:nilerr // objects without a name in the field reference nothing.
+			}
+			return []string{kcpcache.ToClusterAwareKey(logicalcluster.From(u).String(), u.GetNamespace(), value)}, nil
+		},
+	}, nil).Informer()
+	go informer.Run(r.ctx.Done())
+	r.informers[key] = informer
+	return informer, nil
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpfakedynamic "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/dynamic/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	registry "github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestReadThroughClaims(t *testing.T) {
+	widgetsGVR := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets:abc"}
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	apiExport := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "export",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "provider"},
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"v1.widgets.example.io"},
+			PermissionClaims: []apisv1alpha1.PermissionClaim{
+				{
+					GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"},
+					ReferencedBy: &apisv1alpha1.PermissionClaimReference{
+						GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+						NameField:     "spec.secretRef.name",
+					},
+				},
+				{
+					GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"},
+					All:           true,
+				},
+			},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "abc"},
+	}
+	widgets := &apisv1alpha1.APIResourceSchema{
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		widgetsGVR: "WidgetList",
+		secretsGVR: "SecretList",
+	})
+	tracker := fakeClient.Tracker().Cluster(logicalcluster.NewPath("consumer"))
+	for _, obj := range []*unstructured.Unstructured{
+		newUnstructured("example.io/v1", "Widget", "default", "widget", "spec", "secretRef", "name", "referenced"),
+		newUnstructured("example.io/v1", "Widget", "other", "widget", "spec", "secretRef", "name", "elsewhere"),
+	} {
+		require.NoError(t, tracker.Create(widgetsGVR, obj, obj.GetNamespace()))
+	}
+	for _, obj := range []*unstructured.Unstructured{
+		newUnstructured("v1", "Secret", "default", "referenced"),
+		newUnstructured("v1", "Secret", "default", "unreferenced"),
+		newUnstructured("v1", "Secret", "default", "elsewhere"),
+	} {
+		require.NoError(t, tracker.Create(secretsGVR, obj, obj.GetNamespace()))
+	}
+
+	apiBinding := &apisv1alpha1.APIBinding{
+		Spec: apisv1alpha1.APIBindingSpec{PermissionClaims: []apisv1alpha1.AcceptablePermissionClaim{
+			{PermissionClaim: apiExport.Spec.PermissionClaims[0], State: apisv1alpha1.ClaimAccepted},
+		}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	referencers := newReferencers(fakeClient)
+	referencers.start(ctx)
+
+	c := &readThroughClaims{
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExport, nil
+		},
+		getAPIBinding: func(clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) (*apisv1alpha1.APIBinding, error) {
+			require.Equal(t, logicalcluster.Name("consumer"), clusterName)
+			return apiBinding, nil
+		},
+		referencers: referencers,
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return widgets, nil
+		},
+		dynamicClusterClient: func(ctx context.Context) (kcpdynamic.ClusterInterface, error) {
+			return fakeClient, nil
+		},
+	}
+	delegated := false
+	storageFor := func(resource string) *registry.StoreFuncs {
+		storage := &registry.StoreFuncs{
+			GetterFunc: func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+				delegated = true
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+			},
+		}
+		c.wrapper("v1", "").Decorate(schema.GroupResource{Resource: resource}, storage)
+		return storage
+	}
+	ctxFor := func(cluster genericapirequest.Cluster) context.Context {
+		ctx := dynamiccontext.WithAPIDomainKey(ctx, "provider/export")
+		ctx = genericapirequest.WithCluster(ctx, cluster)
+		return genericapirequest.WithNamespace(ctx, "default")
+	}
+	ctx = ctxFor(genericapirequest.Cluster{Name: "consumer"})
+
+	t.Log("Referenced objects are read through")
+	obj, err := storageFor("secrets").Get(ctx, "referenced", &metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "referenced", obj.(*unstructured.Unstructured).GetName())
+	require.False(t, delegated)
+
+	t.Log("Unreferenced objects are not found")
+	_, err = storageFor("secrets").Get(ctx, "unreferenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+
+	t.Log("Objects referenced from another namespace are not found")
+	_, err = storageFor("secrets").Get(ctx, "elsewhere", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+
+	t.Log("Objects are not read through without an accepted claim")
+	apiBinding.Spec.PermissionClaims[0].State = apisv1alpha1.ClaimRejected
+	_, err = storageFor("secrets").Get(ctx, "referenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+	apiBinding.Spec.PermissionClaims[0].State = apisv1alpha1.ClaimAccepted
+
+	t.Log("Objects are not read through for other references than accepted")
+	apiBinding.Spec.PermissionClaims[0].ReferencedBy = &apisv1alpha1.PermissionClaimReference{
+		GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"},
+		NameField:     "spec.otherRef.name",
+	}
+	_, err = storageFor("secrets").Get(ctx, "referenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+	apiBinding.Spec.PermissionClaims[0].ReferencedBy = apiExport.Spec.PermissionClaims[0].ReferencedBy
+
+	t.Log("Wildcard requests are rejected")
+	_, err = storageFor("secrets").Get(ctxFor(genericapirequest.Cluster{Wildcard: true}), "referenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsBadRequest(err), "unexpected error: %v", err)
+
+	t.Log("Other claims are passed to the delegate")
+	_, err = storageFor("configmaps").Get(ctx, "foo", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+	require.True(t, delegated)
+
+	widgetsClient := fakeClient.Cluster(logicalcluster.NewPath("consumer")).Resource(widgetsGVR).Namespace("default")
+	widgetStorage := &registry.StoreFuncs{
+		CreaterFunc: func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			return widgetsClient.Create(ctx, obj.(*unstructured.Unstructured), *options)
+		},
+		UpdaterFunc: func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			oldObj, err := widgetsClient.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, false, err
+			}
+			obj, err := objInfo.UpdatedObject(ctx, oldObj)
+			if err != nil {
+				return nil, false, err
+			}
+			updated, err := widgetsClient.Update(ctx, obj.(*unstructured.Unstructured), *options)
+			return updated, false, err
+		},
+	}
+	c.referenceGuard().Decorate(schema.GroupResource{Group: "example.io", Resource: "widgets"}, widgetStorage)
+	updateWidget := func(name string, mutate func(obj *unstructured.Unstructured)) error {
+		_, _, err := widgetStorage.Update(ctx, name, rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+			obj := oldObj.(*unstructured.Unstructured).DeepCopy()
+			mutate(obj)
+			return obj, nil
+		}), nil, nil, false, &metav1.UpdateOptions{})
+		return err
+	}
+
+	t.Log("The provider cannot create referencing objects naming objects itself")
+	_, err = widgetStorage.Create(ctx, newUnstructured("example.io/v1", "Widget", "default", "provided", "spec", "secretRef", "name", "unreferenced"), nil, &metav1.CreateOptions{})
+	require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+	_, err = storageFor("secrets").Get(ctx, "unreferenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+
+	t.Log("The provider can create referencing objects without names")
+	_, err = widgetStorage.Create(ctx, newUnstructured("example.io/v1", "Widget", "default", "provided"), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Log("The provider cannot set names in referencing objects")
+	err = updateWidget("provided", func(obj *unstructured.Unstructured) {
+		require.NoError(t, unstructured.SetNestedField(obj.Object, "unreferenced", "spec", "secretRef", "name"))
+	})
+	require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+	_, err = storageFor("secrets").Get(ctx, "unreferenced", &metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+
+	t.Log("The provider cannot change names set by the consumer")
+	err = updateWidget("widget", func(obj *unstructured.Unstructured) {
+		require.NoError(t, unstructured.SetNestedField(obj.Object, "unreferenced", "spec", "secretRef", "name"))
+	})
+	require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+
+	t.Log("The provider can update referencing objects keeping the names set by the consumer")
+	err = updateWidget("widget", func(obj *unstructured.Unstructured) {
+		require.NoError(t, unstructured.SetNestedField(obj.Object, "bar", "spec", "foo"))
+	})
+	require.NoError(t, err)
+	_, err = storageFor("secrets").Get(ctx, "referenced", &metav1.GetOptions{})
+	require.NoError(t, err)
+
+	t.Log("The provider can clear names set by the consumer")
+	err = updateWidget("widget", func(obj *unstructured.Unstructured) {
+		unstructured.RemoveNestedField(obj.Object, "spec", "secretRef")
+	})
+	require.NoError(t, err)
+}
+
+func newUnstructured(apiVersion, kind, namespace, name string, fieldAndValue ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: "consumer"})
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if len(fieldAndValue) > 0 {
+		fields, value := fieldAndValue[:len(fieldAndValue)-1], fieldAndValue[len(fieldAndValue)-1]
+		if err := unstructured.SetNestedField(obj.Object, value, fields...); err != nil {
+			panic(err)
+		}
+	}
+	return obj
+}
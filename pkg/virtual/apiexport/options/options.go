@@ -17,7 +17,9 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"path"
+	"time"
 
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/spf13/pflag"
@@ -31,16 +33,44 @@ import (
 	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
 )
 
-type APIExport struct{}
+type APIExport struct {
+	// ListPageSize is the maximum number of objects of a list response paginated by the client,
+	// and the number of objects fetched per request from the shards when serving a list
+	// without limit. Zero disables paging.
+	ListPageSize int64
+	// MaxListResponseBytes is the maximum serialized size of the items of a list response
+	// without limit. Larger lists are rejected, asking the client to paginate. Zero means no limit,
+	// i.e. lists without limit are held in memory as a whole however large they are.
+	MaxListResponseBytes int64
+	// RequestTimeout is the deadline of non-long-running requests. Zero means the
+	// request timeout of the server applies.
+	RequestTimeout time.Duration
+	// AuditPolicyFile is the path to the audit policy of requests to the virtual workspace.
+	// Empty means the audit policy of the server applies.
+	AuditPolicyFile string
+}
 
 func New() *APIExport {
-	return &APIExport{}
+	return &APIExport{
+		ListPageSize:         500,
+		MaxListResponseBytes: 128 << 20,
+		RequestTimeout:       30 * time.Second,
+	}
 }
 
 func (o *APIExport) AddFlags(flags *pflag.FlagSet, prefix string) {
 	if o == nil {
 		return
 	}
+
+	flags.Int64Var(&o.ListPageSize, prefix+"apiexport-list-page-size", o.ListPageSize,
+		"The maximum number of objects of list responses of the APIExport virtual workspace paginated by the client, and the number of objects fetched per request from the shards when serving lists without limit. 0 disables paging.")
+	flags.Int64Var(&o.MaxListResponseBytes, prefix+"apiexport-max-list-response-bytes", o.MaxListResponseBytes,
+		"The maximum size in bytes of list responses without limit served by the APIExport virtual workspace. Larger lists are rejected, asking the client to paginate. 0 means no limit.")
+	flags.DurationVar(&o.RequestTimeout, prefix+"apiexport-request-timeout", o.RequestTimeout,
+		"The deadline of non-long-running requests to the APIExport virtual workspace. 0 means the request timeout of the server applies.")
+	flags.StringVar(&o.AuditPolicyFile, prefix+"apiexport-audit-policy-file", o.AuditPolicyFile,
+		"Path to the file that defines the audit policy of requests to the APIExport virtual workspace. Empty means the audit policy of the server applies. Only takes effect with an audit backend, e.g. --audit-log-path.")
 }
 
 func (o *APIExport) Validate(flagPrefix string) []error {
@@ -49,13 +79,23 @@ func (o *APIExport) Validate(flagPrefix string) []error {
 	}
 	errs := []error{}
 
+	if o.ListPageSize < 0 {
+		errs = append(errs, fmt.Errorf("--%sapiexport-list-page-size must be non-negative", flagPrefix))
+	}
+	if o.MaxListResponseBytes < 0 {
+		errs = append(errs, fmt.Errorf("--%sapiexport-max-list-response-bytes must be non-negative", flagPrefix))
+	}
+	if o.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("--%sapiexport-request-timeout must be non-negative", flagPrefix))
+	}
+
 	return errs
 }
 
 func (o *APIExport) NewVirtualWorkspaces(
 	rootPathPrefix string,
 	config *rest.Config,
-	cachedKcpInformers kcpinformers.SharedInformerFactory,
+	wildcardKcpInformers, cachedKcpInformers kcpinformers.SharedInformerFactory,
 ) (workspaces []rootapiserver.NamedVirtualWorkspace, err error) {
 	config = rest.AddUserAgent(rest.CopyConfig(config), "apiexport-virtual-workspace")
 	kcpClusterClient, err := kcpclientset.NewForConfig(config)
@@ -71,5 +111,13 @@ func (o *APIExport) NewVirtualWorkspaces(
 		return nil, err
 	}
 
-	return builder.BuildVirtualWorkspace(path.Join(rootPathPrefix, builder.VirtualWorkspaceName), config, kubeClusterClient, deepSARClient, kcpClusterClient, cachedKcpInformers)
+	workspaces, err = builder.BuildVirtualWorkspace(path.Join(rootPathPrefix, builder.VirtualWorkspaceName), config, kubeClusterClient, deepSARClient, kcpClusterClient, wildcardKcpInformers, cachedKcpInformers, o.ListPageSize, o.MaxListResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range workspaces {
+		workspaces[i].RequestTimeout = o.RequestTimeout
+		workspaces[i].AuditPolicyFile = o.AuditPolicyFile
+	}
+	return workspaces, nil
 }
@@ -36,3 +36,20 @@ func VirtualWorkspaceNameFrom(ctx context.Context) (string, bool) {
 	wcn, hasVirtualWorkspaceName := ctx.Value(virtualWorkspaceNameKey).(string)
 	return wcn, hasVirtualWorkspaceName
 }
+
+type virtualWorkspacePathPrefixKeyType string
+
+// virtualWorkspacePathPrefixKey is a context key that contains the URL path prefix
+// that has been stripped from a request before passing it to the virtual workspace.
+const virtualWorkspacePathPrefixKey virtualWorkspacePathPrefixKeyType = "VirtualWorkspacePathPrefix"
+
+// WithVirtualWorkspacePathPrefix adds the stripped URL path prefix to the context.
+func WithVirtualWorkspacePathPrefix(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, virtualWorkspacePathPrefixKey, prefix)
+}
+
+// VirtualWorkspacePathPrefixFrom retrieves the stripped URL path prefix from the context, if any.
+func VirtualWorkspacePathPrefixFrom(ctx context.Context) string {
+	prefix, _ := ctx.Value(virtualWorkspacePathPrefixKey).(string)
+	return prefix
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/client-go/scale/scheme/autoscalingv1"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+)
+
+// The aggregated discovery API of apidiscovery.k8s.io/v2beta1 serves all groups, versions and
+// resources of /api or /apis in one response, saving clients one request per group version.
+// The types are not part of the Kubernetes libraries kcp is built with, hence the wire format is
+// defined here, and only JSON is served.
+const (
+	aggregatedDiscoveryGroup   = "apidiscovery.k8s.io"
+	aggregatedDiscoveryVersion = "v2beta1"
+	aggregatedDiscoveryKind    = "APIGroupDiscoveryList"
+
+	aggregatedDiscoveryContentType = "application/json;g=" + aggregatedDiscoveryGroup + ";v=" + aggregatedDiscoveryVersion + ";as=" + aggregatedDiscoveryKind
+)
+
+type apiGroupDiscoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []apiGroupDiscovery `json:"items"`
+}
+
+type apiGroupDiscovery struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Versions are sorted by preference, the preferred version first.
+	Versions []apiVersionDiscovery `json:"versions,omitempty"`
+}
+
+type apiVersionDiscovery struct {
+	Version   string                 `json:"version"`
+	Resources []apiResourceDiscovery `json:"resources,omitempty"`
+	Freshness string                 `json:"freshness,omitempty"`
+}
+
+type apiResourceDiscovery struct {
+	Resource         string                    `json:"resource"`
+	ResponseKind     *metav1.GroupVersionKind  `json:"responseKind"`
+	Scope            string                    `json:"scope"`
+	SingularResource string                    `json:"singularResource"`
+	Verbs            []string                  `json:"verbs"`
+	ShortNames       []string                  `json:"shortNames,omitempty"`
+	Categories       []string                  `json:"categories,omitempty"`
+	Subresources     []apiSubresourceDiscovery `json:"subresources,omitempty"`
+}
+
+type apiSubresourceDiscovery struct {
+	Subresource  string                   `json:"subresource"`
+	ResponseKind *metav1.GroupVersionKind `json:"responseKind,omitempty"`
+	Verbs        []string                 `json:"verbs"`
+}
+
+// acceptsAggregatedDiscovery returns true if the Accept header of the request prefers the
+// aggregated discovery API over the legacy discovery types.
+func acceptsAggregatedDiscovery(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if params["as"] == "" {
+			// the legacy types are acceptable, and preferred over the following media types.
+			return false
+		}
+		if mediaType == "application/json" && params["as"] == aggregatedDiscoveryKind &&
+			(params["g"] == "" || params["g"] == aggregatedDiscoveryGroup) &&
+			(params["v"] == "" || params["v"] == aggregatedDiscoveryVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveAggregatedDiscovery serves the aggregated discovery document of the groups of the API
+// definition set accepted by includeGroup.
+func serveAggregatedDiscovery(w http.ResponseWriter, req *http.Request, apiSet apidefinition.APIDefinitionSet, includeGroup func(group string) bool) {
+	versions := map[string]map[string][]apiResourceDiscovery{}
+	for gvr, apiDef := range apiSet {
+		if !includeGroup(gvr.Group) {
+			continue
+		}
+		if versions[gvr.Group] == nil {
+			versions[gvr.Group] = map[string][]apiResourceDiscovery{}
+		}
+		versions[gvr.Group][gvr.Version] = append(versions[gvr.Group][gvr.Version], resourceDiscovery(gvr, apiDef))
+	}
+
+	list := &apiGroupDiscoveryList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: schema.GroupVersion{Group: aggregatedDiscoveryGroup, Version: aggregatedDiscoveryVersion}.String(),
+			Kind:       aggregatedDiscoveryKind,
+		},
+		Items: make([]apiGroupDiscovery, 0, len(versions)),
+	}
+	for group, resourcesByVersion := range versions {
+		g := apiGroupDiscovery{ObjectMeta: metav1.ObjectMeta{Name: group}}
+		for v, resources := range resourcesByVersion {
+			sort.Slice(resources, func(i, j int) bool {
+				return resources[i].Resource < resources[j].Resource
+			})
+			g.Versions = append(g.Versions, apiVersionDiscovery{Version: v, Resources: resources, Freshness: "Current"})
+		}
+		sort.Slice(g.Versions, func(i, j int) bool {
+			return version.CompareKubeAwareVersionStrings(g.Versions[i].Version, g.Versions[j].Version) > 0
+		})
+		list.Items = append(list.Items, g)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	bs, err := json.Marshal(list)
+	if err != nil {
+		responsewriters.InternalError(w, req, err)
+		return
+	}
+	w.Header().Set("Content-Type", aggregatedDiscoveryContentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs) //nolint:errcheck
+}
+
+// resourceDiscovery returns the aggregated discovery of a resource, including its subresources.
+func resourceDiscovery(gvr schema.GroupVersionResource, apiDef apidefinition.APIDefinition) apiResourceDiscovery {
+	apiResourceSchema := apiDef.GetAPIResourceSchema()
+	names := apiResourceSchema.Spec.Names
+
+	scope := "Cluster"
+	if apiResourceSchema.Spec.Scope == apiextensionsv1.NamespaceScoped {
+		scope = "Namespaced"
+	}
+	resource := apiResourceDiscovery{
+		Resource:         names.Plural,
+		ResponseKind:     &metav1.GroupVersionKind{Group: gvr.Group, Version: gvr.Version, Kind: names.Kind},
+		Scope:            scope,
+		SingularResource: names.Singular,
+		Verbs:            supportedVerbs(apiDef.GetStorage()),
+		ShortNames:       names.ShortNames,
+		Categories:       names.Categories,
+	}
+
+	for _, v := range apiResourceSchema.Spec.Versions {
+		if v.Name != gvr.Version {
+			continue
+		}
+		if v.Subresources.Status != nil {
+			resource.Subresources = append(resource.Subresources, apiSubresourceDiscovery{
+				Subresource:  "status",
+				ResponseKind: resource.ResponseKind,
+				Verbs:        supportedVerbs(apiDef.GetSubResourceStorage("status")),
+			})
+		}
+		if v.Subresources.Scale != nil && apiDef.GetSubResourceStorage("scale") != nil {
+			resource.Subresources = append(resource.Subresources, apiSubresourceDiscovery{
+				Subresource:  "scale",
+				ResponseKind: &metav1.GroupVersionKind{Group: autoscalingv1.SchemeGroupVersion.Group, Version: autoscalingv1.SchemeGroupVersion.Version, Kind: "Scale"},
+				Verbs:        supportedVerbs(apiDef.GetSubResourceStorage("scale")),
+			})
+		}
+	}
+	return resource
+}
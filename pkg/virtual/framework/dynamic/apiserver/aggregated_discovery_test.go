@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	dyncamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestAcceptsAggregatedDiscovery(t *testing.T) {
+	tests := map[string]bool{
+		"":                 false,
+		"application/json": false,
+		"application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList,application/json":                                                                             true,
+		"application/vnd.kubernetes.protobuf;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList,application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList": true,
+		"application/json;as=APIGroupDiscoveryList":                                             true,
+		"application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList,application/json": false,
+		"application/json, application/json;as=APIGroupDiscoveryList":                           false,
+		"application/json;as=Table":                                                             false,
+	}
+	for accept, want := range tests {
+		t.Run(accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/apis", nil)
+			req.Header.Set("Accept", accept)
+			require.Equal(t, want, acceptsAggregatedDiscovery(req))
+		})
+	}
+}
+
+func TestAggregatedDiscovery(t *testing.T) {
+	resourceSchema := func(group string, scope apiextensionsv1.ResourceScope, names apiextensionsv1.CustomResourceDefinitionNames, versions ...apisv1alpha1.APIResourceVersion) *apisv1alpha1.APIResourceSchema {
+		return &apisv1alpha1.APIResourceSchema{
+			Spec: apisv1alpha1.APIResourceSchemaSpec{Group: group, Scope: scope, Names: names, Versions: versions},
+		}
+	}
+	widgets := apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ShortNames: []string{"wd"}, Categories: []string{"all"}}
+	gadgets := apiextensionsv1.CustomResourceDefinitionNames{Plural: "gadgets", Singular: "gadget", Kind: "Gadget"}
+	configmaps := apiextensionsv1.CustomResourceDefinitionNames{Plural: "configmaps", Singular: "configmap", Kind: "ConfigMap"}
+	v1 := apisv1alpha1.APIResourceVersion{Name: "v1", Subresources: apiextensionsv1.CustomResourceSubresources{
+		Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+		Scale:  &apiextensionsv1.CustomResourceSubresourceScale{},
+	}}
+	v1beta1 := apisv1alpha1.APIResourceVersion{Name: "v1beta1"}
+	readOnly := &struct {
+		*base
+		*getter
+		*lister
+	}{}
+
+	apiSetRetriever := mockedAPISetRetriever{
+		schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}: &mockedAPIDefinition{
+			apiResourceSchema:  resourceSchema("example.io", apiextensionsv1.NamespaceScoped, widgets, v1beta1, v1),
+			store:              readOnly,
+			subresourcesStores: map[string]rest.Storage{"status": readOnly, "scale": readOnly},
+		},
+		schema.GroupVersionResource{Group: "example.io", Version: "v1beta1", Resource: "widgets"}: &mockedAPIDefinition{
+			apiResourceSchema: resourceSchema("example.io", apiextensionsv1.NamespaceScoped, widgets, v1beta1, v1),
+			store:             readOnly,
+		},
+		schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "gadgets"}: &mockedAPIDefinition{
+			apiResourceSchema: resourceSchema("example.io", apiextensionsv1.ClusterScoped, gadgets, apisv1alpha1.APIResourceVersion{Name: "v1"}),
+			store:             readOnly,
+		},
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}: &mockedAPIDefinition{
+			apiResourceSchema: resourceSchema("", apiextensionsv1.NamespaceScoped, configmaps, apisv1alpha1.APIResourceVersion{Name: "v1"}),
+			store:             readOnly,
+		},
+	}
+	delegate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "", http.StatusTeapot)
+	})
+	handler := &resourceHandler{
+		apiSetRetriever:         apiSetRetriever,
+		delegate:                delegate,
+		versionDiscoveryHandler: &versionDiscoveryHandler{apiSetRetriever: apiSetRetriever, delegate: delegate},
+		groupDiscoveryHandler:   &groupDiscoveryHandler{apiSetRetriever: apiSetRetriever, delegate: delegate},
+		rootDiscoveryHandler:    &rootDiscoveryHandler{apiSetRetriever: apiSetRetriever, delegate: delegate},
+	}
+
+	readOnlyVerbs := []string{"get", "list"}
+	widgetsDiscovery := func(version string) apiResourceDiscovery {
+		return apiResourceDiscovery{
+			Resource:         "widgets",
+			ResponseKind:     &metav1.GroupVersionKind{Group: "example.io", Version: version, Kind: "Widget"},
+			Scope:            "Namespaced",
+			SingularResource: "widget",
+			Verbs:            readOnlyVerbs,
+			ShortNames:       []string{"wd"},
+			Categories:       []string{"all"},
+		}
+	}
+	widgetsV1 := widgetsDiscovery("v1")
+	widgetsV1.Subresources = []apiSubresourceDiscovery{
+		{Subresource: "status", ResponseKind: widgetsV1.ResponseKind, Verbs: readOnlyVerbs},
+		{Subresource: "scale", ResponseKind: &metav1.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"}, Verbs: readOnlyVerbs},
+	}
+	typeMeta := metav1.TypeMeta{APIVersion: "apidiscovery.k8s.io/v2beta1", Kind: "APIGroupDiscoveryList"}
+
+	tests := map[string]struct {
+		path string
+		want apiGroupDiscoveryList
+	}{
+		"/apis": {
+			path: "/apis",
+			want: apiGroupDiscoveryList{TypeMeta: typeMeta, Items: []apiGroupDiscovery{{
+				ObjectMeta: metav1.ObjectMeta{Name: "example.io"},
+				Versions: []apiVersionDiscovery{
+					{Version: "v1", Freshness: "Current", Resources: []apiResourceDiscovery{
+						{
+							Resource:         "gadgets",
+							ResponseKind:     &metav1.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Gadget"},
+							Scope:            "Cluster",
+							SingularResource: "gadget",
+							Verbs:            readOnlyVerbs,
+						},
+						widgetsV1,
+					}},
+					{Version: "v1beta1", Freshness: "Current", Resources: []apiResourceDiscovery{widgetsDiscovery("v1beta1")}},
+				},
+			}}},
+		},
+		"/api": {
+			path: "/api",
+			want: apiGroupDiscoveryList{TypeMeta: typeMeta, Items: []apiGroupDiscovery{{
+				Versions: []apiVersionDiscovery{
+					{Version: "v1", Freshness: "Current", Resources: []apiResourceDiscovery{{
+						Resource:         "configmaps",
+						ResponseKind:     &metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+						Scope:            "Namespaced",
+						SingularResource: "configmap",
+						Verbs:            readOnlyVerbs,
+					}}},
+				},
+			}}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Accept", "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList,application/json")
+			req = req.WithContext(apirequest.WithRequestInfo(
+				dyncamiccontext.WithAPIDomainKey(req.Context(), "domain"),
+				&apirequest.RequestInfo{Path: tc.path},
+			))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, aggregatedDiscoveryContentType, recorder.Header().Get("Content-Type"))
+			var got apiGroupDiscoveryList
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			require.Empty(t, cmp.Diff(tc.want, got))
+		})
+	}
+}
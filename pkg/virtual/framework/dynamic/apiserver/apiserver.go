@@ -168,7 +168,8 @@ func (c completedConfig) New(virtualWorkspaceName string, delegationTarget gener
 	s.GenericAPIServer.Handler.GoRestfulContainer.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 		pathParts := splitPath(req.Request.URL.Path)
 		if len(pathParts) > 0 && pathParts[0] == "apis" ||
-			len(pathParts) > 1 && pathParts[0] == "api" {
+			len(pathParts) > 1 && pathParts[0] == "api" ||
+			len(pathParts) == 1 && pathParts[0] == "api" && acceptsAggregatedDiscovery(req.Request) {
 			crdHandler.ServeHTTP(resp.ResponseWriter, req.Request)
 		} else {
 			chain.ProcessFilter(req, resp)
@@ -182,9 +183,12 @@ func (c completedConfig) New(virtualWorkspaceName string, delegationTarget gener
 	s.GenericAPIServer.Handler.NonGoRestfulMux.Handle("/api/v1", crdHandler)
 	s.GenericAPIServer.Handler.NonGoRestfulMux.HandlePrefix("/api/v1/", crdHandler)
 
-	// TODO(david): plug OpenAPI if necessary. For now, according to the various virtual workspace use-cases,
-	// it doesn't seem necessary.
-	// Of course this requires using the --validate=false argument with some kubectl command like kubectl apply.
+	// serve the OpenAPI documents of the API domain of the request, for client-side validation,
+	// kubectl explain and generic clients.
+	openAPIHandler := newOpenAPIHandler(s.APISetRetriever, s.GenericAPIServer.StaticOpenAPISpec, delegateHandler)
+	s.GenericAPIServer.Handler.NonGoRestfulMux.Handle("/openapi/v2", openAPIHandler)
+	s.GenericAPIServer.Handler.NonGoRestfulMux.Handle("/openapi/v3", openAPIHandler)
+	s.GenericAPIServer.Handler.NonGoRestfulMux.HandlePrefix("/openapi/v3/", openAPIHandler)
 
 	return s, nil
 }
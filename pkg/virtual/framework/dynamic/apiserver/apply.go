@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// withApplyPatch passes the body of server-side apply requests to the storage through the request
+// context. The handler still applies the patch, e.g. for admission and to detect conflicts early,
+// but storages forwarding to another server can forward the apply patch itself, such that the
+// managed fields are tracked by that server, and not overwritten by an update of the applied object.
+func withApplyPatch(handler http.HandlerFunc, maxRequestBodyBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err != nil || mediaType != string(types.ApplyPatchType) {
+			handler(w, req)
+			return
+		}
+
+		// invalid options are rejected by the handler.
+		options := &metav1.PatchOptions{}
+		if err := metainternalversionscheme.ParameterCodec.DecodeParameters(req.URL.Query(), metav1.SchemeGroupVersion, options); err != nil {
+			handler(w, req)
+			return
+		}
+
+		var body io.Reader = req.Body
+		if maxRequestBodyBytes > 0 {
+			body = io.LimitReader(req.Body, maxRequestBodyBytes+1)
+		}
+		patch, err := io.ReadAll(body)
+		// the handler sees the complete body again, and rejects it if it is too large or broken.
+		req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(patch), req.Body))
+		if err != nil || maxRequestBodyBytes > 0 && int64(len(patch)) > maxRequestBodyBytes {
+			handler(w, req)
+			return
+		}
+
+		ctx := dynamiccontext.WithApplyPatch(req.Context(), dynamiccontext.ApplyPatch{
+			Patch: patch,
+			Force: options.Force != nil && *options.Force,
+		})
+		handler(w, req.WithContext(ctx))
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+func TestWithApplyPatch(t *testing.T) {
+	body := "apiVersion: v1\nkind: Widget\nmetadata:\n  name: a\n"
+
+	tests := map[string]struct {
+		contentType string
+		query       string
+		maxBytes    int64
+		wantPatch   *dynamiccontext.ApplyPatch
+	}{
+		"apply": {
+			contentType: "application/apply-patch+yaml",
+			query:       "?fieldManager=controller",
+			wantPatch:   &dynamiccontext.ApplyPatch{Patch: []byte(body)},
+		},
+		"force apply": {
+			contentType: "application/apply-patch+yaml; charset=utf-8",
+			query:       "?fieldManager=controller&force=true",
+			wantPatch:   &dynamiccontext.ApplyPatch{Patch: []byte(body), Force: true},
+		},
+		"merge patch": {
+			contentType: "application/merge-patch+json",
+		},
+		"too large": {
+			contentType: "application/apply-patch+yaml",
+			maxBytes:    10,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotBody string
+			var gotPatch *dynamiccontext.ApplyPatch
+			handler := withApplyPatch(func(w http.ResponseWriter, req *http.Request) {
+				if patch, ok := dynamiccontext.ApplyPatchFrom(req.Context()); ok {
+					gotPatch = &patch
+				}
+				bs, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				gotBody = string(bs)
+			}, tt.maxBytes)
+
+			req := httptest.NewRequest(http.MethodPatch, "/apis/example.io/v1/widgets/a"+tt.query, strings.NewReader(body))
+			req.Header.Set("Content-Type", tt.contentType)
+			handler(httptest.NewRecorder(), req)
+
+			require.Equal(t, body, gotBody, "the handler should see the complete body")
+			require.Equal(t, tt.wantPatch, gotPatch)
+		})
+	}
+}
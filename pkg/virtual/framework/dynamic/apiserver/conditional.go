@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// withConditionalGet serves GET requests with an ETag derived from the resourceVersion of the
+// returned object. If the If-None-Match header of the request matches it, the object is not
+// sent again, but 304 Not Modified is returned. This saves bandwidth for clients polling large
+// objects.
+func withConditionalGet(getter rest.Getter, newHandler func(rest.Getter) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		recorder := &resourceVersionRecorder{Getter: getter}
+		cw := &conditionalResponseWriter{
+			ResponseWriter: w,
+			ifNoneMatch:    req.Header.Get("If-None-Match"),
+			recorder:       recorder,
+		}
+		newHandler(recorder).ServeHTTP(responsewriter.WrapForHTTP1Or2(cw), req)
+	}
+}
+
+// resourceVersionRecorder records the resourceVersion of the object returned by the getter.
+type resourceVersionRecorder struct {
+	rest.Getter
+	resourceVersion string
+}
+
+func (r *resourceVersionRecorder) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := r.Getter.Get(ctx, name, options)
+	if err != nil {
+		return obj, err
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		r.resourceVersion = accessor.GetResourceVersion()
+	}
+	return obj, nil
+}
+
+// conditionalResponseWriter adds the ETag header to successful responses, and replaces them
+// with 304 Not Modified if the ETag matches the If-None-Match header of the request.
+type conditionalResponseWriter struct {
+	http.ResponseWriter
+	ifNoneMatch string
+	recorder    *resourceVersionRecorder
+
+	notModified bool
+}
+
+var _ responsewriter.UserProvidedDecorator = &conditionalResponseWriter{}
+
+func (w *conditionalResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *conditionalResponseWriter) WriteHeader(code int) {
+	if code == http.StatusOK && w.recorder.resourceVersion != "" {
+		etag := `W/"` + w.recorder.resourceVersion + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Add("Vary", "Accept")
+		if etagMatches(w.ifNoneMatch, etag) {
+			w.notModified = true
+			// a 304 response has no body.
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.Header().Del("Content-Encoding")
+			code = http.StatusNotModified
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *conditionalResponseWriter) Write(bs []byte) (int, error) {
+	if w.notModified {
+		return len(bs), nil
+	}
+	return w.ResponseWriter.Write(bs)
+}
+
+// etagMatches compares the etag with the values of an If-None-Match header, using the weak
+// comparison of RFC 9110, i.e. ignoring the W/ prefix.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+type fakeGetter struct {
+	base
+	obj *unstructured.Unstructured
+}
+
+func (g *fakeGetter) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	if g.obj == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "widgets"}, name)
+	}
+	return g.obj, nil
+}
+
+func TestWithConditionalGet(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetName("a")
+	obj.SetResourceVersion("42")
+
+	tests := map[string]struct {
+		obj         *unstructured.Unstructured
+		ifNoneMatch string
+		wantStatus  int
+		wantETag    string
+		wantBody    string
+	}{
+		"no If-None-Match":          {obj: obj, wantStatus: http.StatusOK, wantETag: `W/"42"`, wantBody: "object"},
+		"matching If-None-Match":    {obj: obj, ifNoneMatch: `W/"42"`, wantStatus: http.StatusNotModified, wantETag: `W/"42"`},
+		"matching strong ETag":      {obj: obj, ifNoneMatch: `"42"`, wantStatus: http.StatusNotModified, wantETag: `W/"42"`},
+		"matching one of many":      {obj: obj, ifNoneMatch: `W/"41", W/"42"`, wantStatus: http.StatusNotModified, wantETag: `W/"42"`},
+		"matching wildcard":         {obj: obj, ifNoneMatch: `*`, wantStatus: http.StatusNotModified, wantETag: `W/"42"`},
+		"outdated If-None-Match":    {obj: obj, ifNoneMatch: `W/"41"`, wantStatus: http.StatusOK, wantETag: `W/"42"`, wantBody: "object"},
+		"not found":                 {ifNoneMatch: `*`, wantStatus: http.StatusNotFound, wantBody: "not found"},
+		"not found, no conditional": {wantStatus: http.StatusNotFound, wantBody: "not found"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := withConditionalGet(&fakeGetter{obj: tt.obj}, func(getter rest.Getter) http.HandlerFunc {
+				return func(w http.ResponseWriter, req *http.Request) {
+					if _, err := getter.Get(req.Context(), "a", &metav1.GetOptions{}); err != nil {
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte("not found")) //nolint:errcheck
+						return
+					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("object")) //nolint:errcheck
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/apis/example.io/v1/widgets/a", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			require.Equal(t, tt.wantETag, rec.Header().Get("ETag"))
+			require.Equal(t, tt.wantBody, rec.Body.String())
+			if tt.wantStatus == http.StatusNotModified {
+				require.Empty(t, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apiserver/pkg/endpoints/handlers/negotiation"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/scale/scheme/autoscalingv1"
 	"k8s.io/kubernetes/pkg/genericcontrolplane/aggregator"
 
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
@@ -121,9 +122,17 @@ func (r *versionDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 					Verbs:      supportedVerbs(apiDef.GetSubResourceStorage("status")),
 				})
 			}
+			if v := apiResourceSchema.Spec.Versions[i]; v.Subresources.Scale != nil && apiDef.GetSubResourceStorage("scale") != nil {
+				apiResourcesForDiscovery = append(apiResourcesForDiscovery, metav1.APIResource{
+					Name:       apiResourceSchema.Spec.Names.Plural + "/scale",
+					Namespaced: apiResourceSchema.Spec.Scope == apiextensionsv1.NamespaceScoped,
+					Group:      autoscalingv1.SchemeGroupVersion.Group,
+					Version:    autoscalingv1.SchemeGroupVersion.Version,
+					Kind:       "Scale",
+					Verbs:      supportedVerbs(apiDef.GetSubResourceStorage("scale")),
+				})
+			}
 		}
-
-		// TODO(david): Add scale sub-resource ???
 	}
 
 	resourceListerFunc := discovery.APIResourceListerFunc(func() []metav1.APIResource {
@@ -201,6 +210,12 @@ func (r *groupDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Reque
 			w, req)
 		return
 	}
+	if requestedGroup == "" && acceptsAggregatedDiscovery(req) {
+		// /api is only routed here for aggregated discovery, hence it is served without API
+		// domain too, with an empty list.
+		serveAggregatedDiscovery(w, req, apiSet, func(group string) bool { return group == "" })
+		return
+	}
 	if !hasLocationKey {
 		r.delegate.ServeHTTP(w, req)
 		return
@@ -272,6 +287,11 @@ func (r *rootDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	if acceptsAggregatedDiscovery(req) {
+		serveAggregatedDiscovery(w, req, apiSet, func(group string) bool { return group != "" })
+		return
+	}
+
 	for gvr := range apiSet {
 		if gvr.Group == "" {
 			// Don't include CRDs in the core ("") group in /apis discovery. They
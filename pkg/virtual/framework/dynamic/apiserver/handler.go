@@ -211,6 +211,8 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	switch {
 	case subresource == "status" && subresources.Status != nil:
 		handlerFunc = r.serveStatus(w, req, requestInfo, apiDef, supportedTypes)
+	case subresource == "scale" && subresources.Scale != nil && apiDef.GetSubResourceStorage("scale") != nil:
+		handlerFunc = r.serveScale(w, req, requestInfo, apiDef, supportedTypes)
 	case len(subresource) == 0:
 		handlerFunc = r.serveResource(w, req, requestInfo, apiDef, supportedTypes)
 	default:
@@ -234,7 +236,9 @@ func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request
 	switch requestInfo.Verb {
 	case "get":
 		if storage, isAble := storage.(rest.Getter); isAble {
-			return handlers.GetResource(storage, requestScope)
+			return withConditionalGet(storage, func(getter rest.Getter) http.HandlerFunc {
+				return handlers.GetResource(getter, requestScope)
+			})
 		}
 	case "list":
 		if listerStorage, isAble := storage.(rest.Lister); isAble {
@@ -260,7 +264,7 @@ func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request
 		}
 	case "patch":
 		if storage, isAble := storage.(rest.Patcher); isAble {
-			return handlers.PatchResource(storage, requestScope, r.admission, supportedTypes)
+			return withApplyPatch(handlers.PatchResource(storage, requestScope, r.admission, supportedTypes), requestScope.MaxRequestBodyBytes)
 		}
 	case "delete":
 		if storage, isAble := storage.(rest.GracefulDeleter); isAble {
@@ -287,7 +291,9 @@ func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request,
 	switch requestInfo.Verb {
 	case "get":
 		if storage, isAble := storage.(rest.Getter); isAble {
-			return handlers.GetResource(storage, requestScope)
+			return withConditionalGet(storage, func(getter rest.Getter) http.HandlerFunc {
+				return handlers.GetResource(getter, requestScope)
+			})
 		}
 	case "update":
 		if storage, isAble := storage.(rest.Updater); isAble {
@@ -295,7 +301,34 @@ func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request,
 		}
 	case "patch":
 		if storage, isAble := storage.(rest.Patcher); isAble {
-			return handlers.PatchResource(storage, requestScope, r.admission, supportedTypes)
+			return withApplyPatch(handlers.PatchResource(storage, requestScope, r.admission, supportedTypes), requestScope.MaxRequestBodyBytes)
+		}
+	}
+	responsewriters.ErrorNegotiated(
+		apierrors.NewMethodNotSupported(schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}, requestInfo.Verb),
+		codecs, schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}, w, req,
+	)
+	return nil
+}
+
+func (r *resourceHandler) serveScale(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string) http.HandlerFunc {
+	requestScope := apiDef.GetSubResourceRequestScope("scale")
+	storage := apiDef.GetSubResourceStorage("scale")
+
+	switch requestInfo.Verb {
+	case "get":
+		if storage, isAble := storage.(rest.Getter); isAble {
+			return withConditionalGet(storage, func(getter rest.Getter) http.HandlerFunc {
+				return handlers.GetResource(getter, requestScope)
+			})
+		}
+	case "update":
+		if storage, isAble := storage.(rest.Updater); isAble {
+			return handlers.UpdateResource(storage, requestScope, r.admission)
+		}
+	case "patch":
+		if storage, isAble := storage.(rest.Patcher); isAble {
+			return withApplyPatch(handlers.PatchResource(storage, requestScope, r.admission, supportedTypes), requestScope.MaxRequestBodyBytes)
 		}
 	}
 	responsewriters.ErrorNegotiated(
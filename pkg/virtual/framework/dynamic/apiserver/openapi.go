@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/kube-openapi/pkg/handler"
+	"k8s.io/kube-openapi/pkg/handler3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dyncamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// defaultStaticOpenAPISpec is the basis of the OpenAPI v2 document of an API domain if
+// the generic apiserver has no static OpenAPI spec.
+var defaultStaticOpenAPISpec = &spec.Swagger{
+	SwaggerProps: spec.SwaggerProps{
+		Swagger: "2.0",
+		Info: &spec.Info{
+			InfoProps: spec.InfoProps{
+				Title:   "KCP Virtual Workspace",
+				Version: "unversioned",
+			},
+		},
+	},
+}
+
+// openAPIHandler serves the OpenAPI v2 and v3 documents of the APIs of the API domain
+// of a request. The documents are built on first access and rebuilt when the API
+// definition set of the API domain changes.
+type openAPIHandler struct {
+	apiSetRetriever   apidefinition.APIDefinitionSetGetter
+	staticOpenAPISpec *spec.Swagger
+	delegate          http.Handler
+
+	lock     sync.Mutex
+	services map[dyncamiccontext.APIDomainKey]*openAPIServices
+}
+
+// openAPIServices holds the OpenAPI services of one API domain.
+type openAPIServices struct {
+	// fingerprint identifies the API definition set the services have been built from.
+	fingerprint   string
+	v2            http.Handler
+	v3            *handler3.OpenAPIService
+	groupVersions map[string]bool
+}
+
+func newOpenAPIHandler(apiSetRetriever apidefinition.APIDefinitionSetGetter, staticOpenAPISpec *spec.Swagger, delegate http.Handler) *openAPIHandler {
+	if staticOpenAPISpec == nil {
+		staticOpenAPISpec = defaultStaticOpenAPISpec
+	}
+	return &openAPIHandler{
+		apiSetRetriever:   apiSetRetriever,
+		staticOpenAPISpec: staticOpenAPISpec,
+		delegate:          delegate,
+		services:          map[dyncamiccontext.APIDomainKey]*openAPIServices{},
+	}
+}
+
+func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	apiDomainKey := dyncamiccontext.APIDomainKeyFrom(ctx)
+
+	apiSet, hasLocationKey, err := h.apiSetRetriever.GetAPIDefinitionSet(ctx, apiDomainKey)
+	if err != nil {
+		responsewriters.ErrorNegotiated(
+			apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err)),
+			errorCodecs, schema.GroupVersion{},
+			w, req)
+		return
+	}
+	if !hasLocationKey {
+		h.lock.Lock()
+		delete(h.services, apiDomainKey)
+		h.lock.Unlock()
+
+		h.delegate.ServeHTTP(w, req)
+		return
+	}
+
+	services, err := h.servicesFor(apiDomainKey, apiSet)
+	if err != nil {
+		responsewriters.ErrorNegotiated(
+			apierrors.NewInternalError(fmt.Errorf("unable to build OpenAPI documents: %w", err)),
+			errorCodecs, schema.GroupVersion{},
+			w, req)
+		return
+	}
+
+	switch {
+	case req.URL.Path == "/openapi/v2":
+		services.v2.ServeHTTP(w, req)
+	case req.URL.Path == "/openapi/v3":
+		serveOpenAPIV3Discovery(services.v3, w, req)
+	case strings.HasPrefix(req.URL.Path, "/openapi/v3/"):
+		if !services.groupVersions[strings.TrimPrefix(req.URL.Path, "/openapi/v3/")] {
+			h.delegate.ServeHTTP(w, req)
+			return
+		}
+		services.v3.HandleGroupVersion(w, req)
+	default:
+		h.delegate.ServeHTTP(w, req)
+	}
+}
+
+// servicesFor returns the OpenAPI services for the given API definition set, reusing
+// the cached ones if the set has not changed.
+func (h *openAPIHandler) servicesFor(apiDomainKey dyncamiccontext.APIDomainKey, apiSet apidefinition.APIDefinitionSet) (*openAPIServices, error) {
+	fingerprint := fingerprintFor(apiSet)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if services, found := h.services[apiDomainKey]; found && services.fingerprint == fingerprint {
+		return services, nil
+	}
+
+	services, err := buildOpenAPIServices(h.staticOpenAPISpec, apiSet)
+	if err != nil {
+		return nil, err
+	}
+	services.fingerprint = fingerprint
+	h.services[apiDomainKey] = services
+
+	return services, nil
+}
+
+func buildOpenAPIServices(staticOpenAPISpec *spec.Swagger, apiSet apidefinition.APIDefinitionSet) (*openAPIServices, error) {
+	gvrs := make([]schema.GroupVersionResource, 0, len(apiSet))
+	for gvr := range apiSet {
+		gvrs = append(gvrs, gvr)
+	}
+	sort.Slice(gvrs, func(i, j int) bool {
+		return gvrs[i].String() < gvrs[j].String()
+	})
+
+	v2Specs := make([]*spec.Swagger, 0, len(gvrs))
+	v3Specs := map[string][]*spec3.OpenAPI{}
+	for _, gvr := range gvrs {
+		apiResourceSchema := apiSet[gvr].GetAPIResourceSchema()
+		apiResourceVersion, found := findAPIResourceVersion(apiResourceSchema, gvr.Version)
+		if !found {
+			continue
+		}
+
+		v2, err := buildOpenAPIV2(apiResourceSchema, apiResourceVersion, builder.Options{V2: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build OpenAPI v2 for %s: %w", gvr, err)
+		}
+		v2Specs = append(v2Specs, v2)
+
+		v3, err := buildOpenAPIV3(apiResourceSchema, apiResourceVersion, builder.Options{V2: false})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build OpenAPI v3 for %s: %w", gvr, err)
+		}
+		gv := "apis/" + gvr.Group + "/" + gvr.Version
+		if gvr.Group == "" {
+			gv = "api/" + gvr.Version
+		}
+		v3Specs[gv] = append(v3Specs[gv], v3)
+	}
+
+	mergedV2, err := builder.MergeSpecs(staticOpenAPISpec, v2Specs...)
+	if err != nil {
+		return nil, err
+	}
+	v2Service, err := handler.NewOpenAPIService(mergedV2)
+	if err != nil {
+		return nil, err
+	}
+	v2Mux := http.NewServeMux()
+	if err := v2Service.RegisterOpenAPIVersionedService("/openapi/v2", v2Mux); err != nil {
+		return nil, err
+	}
+
+	v3Service, err := handler3.NewOpenAPIService(nil)
+	if err != nil {
+		return nil, err
+	}
+	groupVersions := map[string]bool{}
+	for gv, specs := range v3Specs {
+		merged, err := builder.MergeSpecsV3(specs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge OpenAPI v3 for %s: %w", gv, err)
+		}
+		if err := v3Service.UpdateGroupVersion(gv, merged); err != nil {
+			return nil, err
+		}
+		groupVersions[gv] = true
+	}
+
+	return &openAPIServices{
+		v2:            v2Mux,
+		v3:            v3Service,
+		groupVersions: groupVersions,
+	}, nil
+}
+
+// fingerprintFor returns a string that changes whenever a schema of the API definition set changes.
+func fingerprintFor(apiSet apidefinition.APIDefinitionSet) string {
+	parts := make([]string, 0, len(apiSet))
+	for gvr, apiDef := range apiSet {
+		s := apiDef.GetAPIResourceSchema()
+		parts = append(parts, fmt.Sprintf("%s|%s|%s|%s|%s", gvr, logicalcluster.From(s), s.Name, s.UID, s.ResourceVersion))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// serveOpenAPIV3Discovery serves the OpenAPI v3 discovery document. The server relative URLs of
+// the group versions are prefixed with the virtual workspace URL path prefix of the request, so
+// that clients find the documents behind the prefix.
+func serveOpenAPIV3Discovery(service *handler3.OpenAPIService, w http.ResponseWriter, req *http.Request) {
+	prefix := strings.TrimSuffix(virtualcontext.VirtualWorkspacePathPrefixFrom(req.Context()), "/")
+	if prefix == "" {
+		service.HandleDiscovery(w, req)
+		return
+	}
+
+	rec := httptest.NewRecorder()
+	service.HandleDiscovery(rec, req)
+
+	var discovery handler3.OpenAPIV3Discovery
+	if err := json.Unmarshal(rec.Body.Bytes(), &discovery); err != nil {
+		responsewriters.ErrorNegotiated(
+			apierrors.NewInternalError(fmt.Errorf("unable to decode OpenAPI v3 discovery: %w", err)),
+			errorCodecs, schema.GroupVersion{},
+			w, req)
+		return
+	}
+	for gv, item := range discovery.Paths {
+		item.ServerRelativeURL = prefix + item.ServerRelativeURL
+		discovery.Paths[gv] = item
+	}
+
+	data, err := json.Marshal(&discovery)
+	if err != nil {
+		responsewriters.ErrorNegotiated(
+			apierrors.NewInternalError(fmt.Errorf("unable to encode OpenAPI v3 discovery: %w", err)),
+			errorCodecs, schema.GroupVersion{},
+			w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/handler3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	example := exampleAPIResourceSchema()
+	example.ResourceVersion = "1"
+	require.NoError(t, example.Spec.Versions[0].SetSchema(&apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type:       "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"num": {Type: "integer"}},
+			},
+		},
+	}))
+
+	apiSet := mockedAPISetRetriever{
+		schema.GroupVersionResource{Group: "stable.example.com", Version: "v1beta1", Resource: "examples"}: &mockedAPIDefinition{
+			apiResourceSchema: example,
+		},
+	}
+
+	delegate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "", http.StatusTeapot)
+	})
+	h := newOpenAPIHandler(apiSet, nil, delegate)
+
+	get := func(path, prefix string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "application/json")
+		if prefix != "" {
+			req = req.WithContext(virtualcontext.WithVirtualWorkspacePathPrefix(req.Context(), prefix))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("v2", func(t *testing.T) {
+		w := get("/openapi/v2", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var swagger spec.Swagger
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &swagger))
+		require.Contains(t, swagger.Paths.Paths, "/apis/stable.example.com/v1beta1/examples/{name}")
+		require.Contains(t, swagger.Definitions, "com.example.stable.v1beta1.Example")
+	})
+
+	t.Run("v3 discovery with path prefix", func(t *testing.T) {
+		w := get("/openapi/v3", "/services/apiexport/root/example")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var discovery handler3.OpenAPIV3Discovery
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discovery))
+		require.Len(t, discovery.Paths, 1)
+		gv, found := discovery.Paths["apis/stable.example.com/v1beta1"]
+		require.True(t, found)
+		require.True(t, strings.HasPrefix(gv.ServerRelativeURL, "/services/apiexport/root/example/openapi/v3/apis/stable.example.com/v1beta1?hash="), gv.ServerRelativeURL)
+	})
+
+	t.Run("v3 group version", func(t *testing.T) {
+		w := get("/openapi/v3/apis/stable.example.com/v1beta1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var openapi spec3.OpenAPI
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &openapi))
+		require.Contains(t, openapi.Paths.Paths, "/apis/stable.example.com/v1beta1/examples/{name}")
+	})
+
+	t.Run("unknown v3 group version is delegated", func(t *testing.T) {
+		w := get("/openapi/v3/apis/unknown.example.com/v1", "")
+		require.Equal(t, http.StatusTeapot, w.Code)
+	})
+
+	t.Run("documents are rebuilt on schema change", func(t *testing.T) {
+		before := h.services[""]
+		require.NotNil(t, before)
+
+		changed := example.DeepCopy()
+		changed.ResourceVersion = "2"
+		apiSet[schema.GroupVersionResource{Group: "stable.example.com", Version: "v1beta1", Resource: "examples"}] = &mockedAPIDefinition{apiResourceSchema: changed}
+
+		w := get("/openapi/v2", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotSame(t, before, h.services[""])
+	})
+}
@@ -29,13 +29,13 @@ import (
 	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
 	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
 	"k8s.io/apiextensions-apiserver/pkg/crdserverscheme"
-	"k8s.io/apiextensions-apiserver/pkg/registry/customresource/tableconvertor"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/endpoints/handlers"
 	"k8s.io/apiserver/pkg/endpoints/handlers/fieldmanager"
@@ -44,12 +44,16 @@ import (
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	utilopenapi "k8s.io/apiserver/pkg/util/openapi"
+	"k8s.io/client-go/scale"
+	"k8s.io/client-go/scale/scheme/autoscalingv1"
 	"k8s.io/klog/v2"
+	"k8s.io/kube-openapi/pkg/spec3"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
 	"k8s.io/kube-openapi/pkg/validation/validate"
 
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/tableconverter"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 )
@@ -57,7 +61,7 @@ import (
 var _ apidefinition.APIDefinition = (*servingInfo)(nil)
 
 // RestProviderFunc is the type of a function that builds REST storage implementations for the main resource and sub-resources, based on information passed by the resource handler about a given API.
-type RestProviderFunc func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, structuralSchema *structuralschema.Structural) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage)
+type RestProviderFunc func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, scaleSpec *apiextensionsinternal.CustomResourceSubresourceScale, structuralSchema *structuralschema.Structural) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage)
 
 // CreateServingInfoFor builds an APIDefinition for a apiResourceSchema.
 func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiResourceSchema *apisv1alpha1.APIResourceSchema, version string, restProvider RestProviderFunc) (apidefinition.APIDefinition, error) {
@@ -180,7 +184,16 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		subResourcesValidators["status"] = statusValidator
 	}
 
-	table, err := tableconvertor.New(apiResourceVersion.AdditionalPrinterColumns)
+	var scaleSpec *apiextensionsinternal.CustomResourceSubresourceScale
+	if scaleSubresource := apiResourceVersion.Subresources.Scale; scaleSubresource != nil {
+		equivalentResourceRegistry.RegisterKindFor(gvr, "scale", autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+		scaleSpec = &apiextensionsinternal.CustomResourceSubresourceScale{}
+		if err := apiextensionsv1.Convert_v1_CustomResourceSubresourceScale_To_apiextensions_CustomResourceSubresourceScale(scaleSubresource, scaleSpec, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	table, err := tableconverter.ForColumns(gvk.Group, gvk.Kind, listGVK.Kind, apiResourceVersion.AdditionalPrinterColumns)
 	if err != nil {
 		klog.Background().V(2).WithValues("cluster", logicalcluster.From(apiResourceSchema), "gvk", gvk, "err", err).Info("the CRD has an invalid printer specification, falling back to default printing")
 	}
@@ -194,6 +207,7 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		apiResourceSchema.Spec.Scope == apiextensionsv1.NamespaceScoped,
 		validator,
 		subResourcesValidators,
+		scaleSpec,
 		structuralSchema,
 	)
 
@@ -293,12 +307,42 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		}
 	}
 
+	var scaleScope handlers.RequestScope
+	scaleStorage, scaleEnabled := subresourceStorages["scale"]
+	if scaleEnabled {
+		// shallow copy
+		scaleScope = *requestScope
+		scaleConverter := scale.NewScaleConverter()
+		scaleScope.Subresource = "scale"
+		scaleScope.Serializer = serializer.NewCodecFactory(scaleConverter.Scheme())
+		scaleScope.Kind = autoscalingv1.SchemeGroupVersion.WithKind("Scale")
+		scaleScope.Namer = handlers.ContextBasedNaming{
+			Namer:         runtime.Namer(meta.NewAccessor()),
+			ClusterScoped: clusterScoped,
+		}
+		scaleScope.TableConvertor = rest.NewDefaultTableConvertor(gvr.GroupResource())
+
+		if kcpfeatures.DefaultFeatureGate.Enabled(features.ServerSideApply) {
+			scaleScope, err = apiextensionsapiserver.ScopeWithFieldManager(
+				typeConverter,
+				scaleScope,
+				nil,
+				"scale",
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	ret := &servingInfo{
 		apiResourceSchema:  apiResourceSchema,
 		storage:            storage,
 		statusStorage:      statusStorage,
+		scaleStorage:       scaleStorage,
 		requestScope:       requestScope,
 		statusRequestScope: &statusScope,
+		scaleRequestScope:  &scaleScope,
 		logicalClusterName: logicalcluster.From(apiResourceSchema),
 	}
 
@@ -312,9 +356,11 @@ type servingInfo struct {
 
 	storage       rest.Storage
 	statusStorage rest.Storage
+	scaleStorage  rest.Storage
 
 	requestScope       *handlers.RequestScope
 	statusRequestScope *handlers.RequestScope
+	scaleRequestScope  *handlers.RequestScope
 }
 
 // Implement APIDefinition interface
@@ -329,8 +375,11 @@ func (apiDef *servingInfo) GetStorage() rest.Storage {
 	return apiDef.storage
 }
 func (apiDef *servingInfo) GetSubResourceStorage(subresource string) rest.Storage {
-	if subresource == "status" {
+	switch subresource {
+	case "status":
 		return apiDef.statusStorage
+	case "scale":
+		return apiDef.scaleStorage
 	}
 	return nil
 }
@@ -338,8 +387,11 @@ func (apiDef *servingInfo) GetRequestScope() *handlers.RequestScope {
 	return apiDef.requestScope
 }
 func (apiDef *servingInfo) GetSubResourceRequestScope(subresource string) *handlers.RequestScope {
-	if subresource == "status" {
+	switch subresource {
+	case "status":
 		return apiDef.statusRequestScope
+	case "scale":
+		return apiDef.scaleRequestScope
 	}
 	return nil
 }
@@ -371,11 +423,29 @@ func (u nopConverter) ConvertFieldLabel(gvk schema.GroupVersionKind, label, valu
 
 // buildOpenAPIV2 builds OpenAPI v2 for the given apiResourceSpec.
 func buildOpenAPIV2(apiResourceSchema *apisv1alpha1.APIResourceSchema, apiResourceVersion *apisv1alpha1.APIResourceVersion, opts builder.Options) (*spec.Swagger, error) {
+	crd, err := crdFor(apiResourceSchema, apiResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return builder.BuildOpenAPIV2(crd, apiResourceVersion.Name, opts)
+}
+
+// buildOpenAPIV3 builds OpenAPI v3 for the given apiResourceSpec.
+func buildOpenAPIV3(apiResourceSchema *apisv1alpha1.APIResourceSchema, apiResourceVersion *apisv1alpha1.APIResourceVersion, opts builder.Options) (*spec3.OpenAPI, error) {
+	crd, err := crdFor(apiResourceSchema, apiResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return builder.BuildOpenAPIV3(crd, apiResourceVersion.Name, opts)
+}
+
+// crdFor returns a CRD with the given version of the apiResourceSpec, as input for the OpenAPI builder.
+func crdFor(apiResourceSchema *apisv1alpha1.APIResourceSchema, apiResourceVersion *apisv1alpha1.APIResourceVersion) (*apiextensionsv1.CustomResourceDefinition, error) {
 	openapiSchema, err := apiResourceVersion.GetSchema()
 	if err != nil {
 		return nil, err
 	}
-	crd := &apiextensionsv1.CustomResourceDefinition{
+	return &apiextensionsv1.CustomResourceDefinition{
 		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
 			Group: apiResourceSchema.Spec.Group,
 			Names: apiResourceSchema.Spec.Names,
@@ -390,8 +460,7 @@ func buildOpenAPIV2(apiResourceSchema *apisv1alpha1.APIResourceSchema, apiResour
 			},
 			Scope: apiResourceSchema.Spec.Scope,
 		},
-	}
-	return builder.BuildOpenAPIV2(crd, apiResourceVersion.Name, opts)
+	}, nil
 }
 
 func findAPIResourceVersion(schema *apisv1alpha1.APIResourceSchema, version string) (*apisv1alpha1.APIResourceVersion, bool) {
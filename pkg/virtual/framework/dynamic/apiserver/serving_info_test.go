@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+type resetFieldsStorage struct {
+	base
+}
+
+func (s *resetFieldsStorage) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return nil
+}
+
+func TestCreateServingInfoForDefaulting(t *testing.T) {
+	apiResourceSchema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "v1.widgets.example.io",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     "Widget",
+				ListKind: "WidgetList",
+			},
+			Scope: apiextensionsv1.ClusterScoped,
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: runtime.RawExtension{
+						Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object","default":{},"properties":{"replicas":{"type":"integer","default":1},"color":{"type":"string","default":"blue"}}}}}`),
+					},
+				},
+			},
+		},
+	}
+
+	restProvider := func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, scaleSpec *apiextensionsinternal.CustomResourceSubresourceScale, structuralSchema *structuralschema.Structural) (rest.Storage, map[string]rest.Storage) {
+		return &resetFieldsStorage{}, nil
+	}
+
+	config := genericapiserver.NewConfig(serializer.NewCodecFactory(runtime.NewScheme()))
+	config.ExternalAddress = "localhost:6443"
+	def, err := CreateServingInfoFor(config.Complete(nil), apiResourceSchema, "v1", restProvider)
+	require.NoError(t, err)
+	requestScope := def.GetRequestScope()
+
+	t.Log("Objects decoded from requests are defaulted")
+	info, ok := runtime.SerializerInfoForMediaType(requestScope.Serializer.SupportedMediaTypes(), runtime.ContentTypeJSON)
+	require.True(t, ok)
+	decoder := requestScope.Serializer.DecoderToVersion(info.Serializer, requestScope.HubGroupVersion)
+	obj, _, err := decoder.Decode([]byte(`{"apiVersion":"example.io/v1","kind":"Widget","metadata":{"name":"a"},"spec":{"replicas":3}}`), nil, &unstructured.Unstructured{})
+	require.NoError(t, err)
+	spec, _, err := unstructured.NestedMap(obj.(*unstructured.Unstructured).Object, "spec")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": int64(3), "color": "blue"}, spec)
+
+	t.Log("Objects built by the request handlers are defaulted")
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.io/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "b"},
+	}}
+	requestScope.Defaulter.Default(u)
+	spec, _, err = unstructured.NestedMap(u.Object, "spec")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": int64(1), "color": "blue"}, spec)
+}
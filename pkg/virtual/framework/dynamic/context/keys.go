@@ -43,3 +43,30 @@ func APIDomainKeyFrom(ctx context.Context) APIDomainKey {
 	adk, _ := ctx.Value(apiDomainKeyContextKey).(APIDomainKey)
 	return adk
 }
+
+// applyPatchContextKeyType is the type of the key for the request context value
+// that will carry the server-side apply patch.
+type applyPatchContextKeyType string
+
+// applyPatchContextKey is the key for the request context value
+// that will carry the server-side apply patch.
+const applyPatchContextKey applyPatchContextKeyType = "VirtualWorkspaceApplyPatch"
+
+// ApplyPatch is the server-side apply patch of a request, together with its force option.
+type ApplyPatch struct {
+	// Patch is the body of the apply request.
+	Patch []byte
+	// Force overrides conflicts with the fields of other managers.
+	Force bool
+}
+
+// WithApplyPatch adds a server-side apply patch to the context.
+func WithApplyPatch(ctx context.Context, patch ApplyPatch) context.Context {
+	return context.WithValue(ctx, applyPatchContextKey, patch)
+}
+
+// ApplyPatchFrom retrieves the server-side apply patch from the context, if any.
+func ApplyPatchFrom(ctx context.Context) (ApplyPatch, bool) {
+	patch, ok := ctx.Value(applyPatchContextKey).(ApplyPatch)
+	return patch, ok
+}
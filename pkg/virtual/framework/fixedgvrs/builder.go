@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedgvrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+type resource struct {
+	schema  *apisv1alpha1.APIResourceSchema
+	version string
+	storage StorageProvider
+}
+
+func (r resource) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.schema.Spec.Group, Version: r.version, Resource: r.schema.Spec.Names.Plural}
+}
+
+// Builder builds a virtual workspace serving a fixed set of resources in every API domain.
+type Builder struct {
+	pathResolver PathResolver
+	authorizer   authorizer.Authorizer
+	readyChecker framework.ReadyChecker
+	clusters     Clusters
+	resources    []resource
+	errs         []error
+}
+
+// NewBuilder returns a Builder for a virtual workspace serving the requests accepted by the
+// given PathResolver.
+func NewBuilder(pathResolver PathResolver) *Builder {
+	return &Builder{
+		pathResolver: pathResolver,
+		authorizer:   authorizerfactory.NewAlwaysDenyAuthorizer(),
+		readyChecker: framework.ReadyFunc(func() error { return nil }),
+	}
+}
+
+// WithAuthorizer sets the authorizer of all requests. Without, all requests are denied.
+func (b *Builder) WithAuthorizer(authz authorizer.Authorizer) *Builder {
+	b.authorizer = authz
+	return b
+}
+
+// WithReadyChecker sets the readiness check of the virtual workspace. Without, it is ready
+// immediately.
+func (b *Builder) WithReadyChecker(readyChecker framework.ReadyChecker) *Builder {
+	b.readyChecker = readyChecker
+	return b
+}
+
+// WithClusters restricts the logical clusters requests are accepted for. By default, requests for
+// single logical clusters and the wildcard are accepted.
+func (b *Builder) WithClusters(clusters Clusters) *Builder {
+	b.clusters = clusters
+	return b
+}
+
+// WithResource adds the given version of the resource described by the APIResourceSchema, served
+// from the REST storage of the StorageProvider.
+func (b *Builder) WithResource(schema *apisv1alpha1.APIResourceSchema, version string, storage StorageProvider) *Builder {
+	if schema == nil || storage == nil {
+		b.errs = append(b.errs, errors.New("resources need a schema and a storage provider"))
+		return b
+	}
+	r := resource{schema: schema, version: version, storage: storage}
+	for _, other := range b.resources {
+		if other.gvr() == r.gvr() {
+			b.errs = append(b.errs, fmt.Errorf("resource %s is added twice", r.gvr()))
+			return b
+		}
+	}
+	b.resources = append(b.resources, r)
+	return b
+}
+
+// Build returns the virtual workspace.
+func (b *Builder) Build() (*dynamic.DynamicVirtualWorkspace, error) {
+	errs := b.errs
+	if b.pathResolver == nil {
+		errs = append(errs, errors.New("a path resolver is required"))
+	}
+	if b.authorizer == nil {
+		errs = append(errs, errors.New("an authorizer is required"))
+	}
+	if len(b.resources) == 0 {
+		errs = append(errs, errors.New("at least one resource is required"))
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	pathResolver, clusters, resources := b.pathResolver, b.clusters, b.resources
+	return &dynamic.DynamicVirtualWorkspace{
+		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, ctx context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			cluster, apiDomain, prefixToStrip, ok := pathResolver.ResolvePath(urlPath)
+			if !ok || !clusters.accepts(cluster) {
+				return false, "", ctx
+			}
+
+			completedContext = genericapirequest.WithCluster(ctx, cluster)
+			completedContext = dynamiccontext.WithAPIDomainKey(completedContext, apiDomain)
+			return true, prefixToStrip, completedContext
+		}),
+		Authorizer:   b.authorizer,
+		ReadyChecker: b.readyChecker,
+		BootstrapAPISetManagement: func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
+			return &apiDefinitionSetGetter{config: mainConfig, resources: resources}, nil
+		},
+	}, nil
+}
+
+// apiDefinitionSetGetter creates the API definitions of the resources for every request.
+type apiDefinitionSetGetter struct {
+	config    genericapiserver.CompletedConfig
+	resources []resource
+}
+
+func (g *apiDefinitionSetGetter) GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apidefinition.APIDefinitionSet, bool, error) {
+	apis := make(apidefinition.APIDefinitionSet, len(g.resources))
+	tearDown := func() {
+		for _, def := range apis {
+			def.TearDown()
+		}
+	}
+	for _, r := range g.resources {
+		restProvider, err := r.storage.StorageFor(ctx, key)
+		if err != nil {
+			tearDown()
+			return nil, false, err
+		}
+		def, err := apiserver.CreateServingInfoFor(g.config, r.schema, r.version, restProvider)
+		if err != nil {
+			tearDown()
+			return nil, false, fmt.Errorf("failed to create serving info for %s: %w", r.gvr(), err)
+		}
+		apis[r.gvr()] = def
+	}
+	return apis, len(apis) > 0, nil
+}
+
+var _ apidefinition.APIDefinitionSetGetter = &apiDefinitionSetGetter{}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedgvrs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestPrefixPathResolver(t *testing.T) {
+	tests := map[string]struct {
+		segments int
+		urlPath  string
+
+		wantAccepted bool
+		wantCluster  genericapirequest.Cluster
+		wantDomain   dynamiccontext.APIDomainKey
+		wantPrefix   string
+	}{
+		"single segment": {
+			segments:     1,
+			urlPath:      "/services/test/domain/clusters/root/api/v1/configmaps",
+			wantAccepted: true,
+			wantCluster:  genericapirequest.Cluster{Name: "root"},
+			wantDomain:   "domain",
+			wantPrefix:   "/services/test/domain/clusters/root",
+		},
+		"two segments and wildcard": {
+			segments:     2,
+			urlPath:      "/services/test/root:org/widgets/clusters/*/apis/example.kcp.io/v1/widgets",
+			wantAccepted: true,
+			wantCluster:  genericapirequest.Cluster{Wildcard: true},
+			wantDomain:   "root:org/widgets",
+			wantPrefix:   "/services/test/root:org/widgets/clusters/*",
+		},
+		"cluster root": {
+			segments:     1,
+			urlPath:      "/services/test/domain/clusters/root",
+			wantAccepted: true,
+			wantCluster:  genericapirequest.Cluster{Name: "root"},
+			wantDomain:   "domain",
+			wantPrefix:   "/services/test/domain/clusters/root",
+		},
+		"other prefix": {
+			segments: 1,
+			urlPath:  "/services/other/domain/clusters/root/api/v1/configmaps",
+		},
+		"missing segment": {
+			segments: 2,
+			urlPath:  "/services/test/domain/clusters/root/api/v1/configmaps",
+		},
+		"empty segment": {
+			segments: 1,
+			urlPath:  "/services/test//clusters/root/api/v1/configmaps",
+		},
+		"no cluster": {
+			segments: 1,
+			urlPath:  "/services/test/domain/api/v1/configmaps",
+		},
+		"cluster path": {
+			segments: 1,
+			urlPath:  "/services/test/domain/clusters/root:org/api/v1/configmaps",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster, domain, prefix, accepted := NewPrefixPathResolver("/services/test", tt.segments).ResolvePath(tt.urlPath)
+			require.Equal(t, tt.wantAccepted, accepted)
+			require.Equal(t, tt.wantCluster, cluster)
+			require.Equal(t, tt.wantDomain, domain)
+			require.Equal(t, tt.wantPrefix, prefix)
+		})
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	schema := &apisv1alpha1.APIResourceSchema{
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.kcp.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+		},
+	}
+	storage := StorageProviderFunc(func(context.Context, dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error) {
+		return nil, nil
+	})
+	resolver := NewPrefixPathResolver("/services/test", 1)
+
+	t.Log("Invalid configurations are rejected")
+	_, err := NewBuilder(resolver).Build()
+	require.ErrorContains(t, err, "at least one resource is required")
+	_, err = NewBuilder(resolver).WithResource(schema, "v1", storage).WithResource(schema, "v1", storage).Build()
+	require.ErrorContains(t, err, "added twice")
+
+	t.Log("Requests are denied by default")
+	vw, err := NewBuilder(resolver).WithResource(schema, "v1", storage).Build()
+	require.NoError(t, err)
+	decision, _, err := vw.Authorize(context.Background(), authorizer.AttributesRecord{})
+	require.NoError(t, err)
+	require.NotEqual(t, authorizer.DecisionAllow, decision)
+	require.NoError(t, vw.IsReady())
+
+	t.Log("The cluster and the API domain are put into the context")
+	accepted, prefix, ctx := vw.ResolveRootPath("/services/test/domain/clusters/root/apis/example.kcp.io/v1/widgets", context.Background())
+	require.True(t, accepted)
+	require.Equal(t, "/services/test/domain/clusters/root", prefix)
+	require.Equal(t, dynamiccontext.APIDomainKey("domain"), dynamiccontext.APIDomainKeyFrom(ctx))
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	require.NoError(t, err)
+	require.Equal(t, logicalcluster.Name("root"), cluster.Name)
+
+	t.Log("Clusters are restricted")
+	vw, err = NewBuilder(resolver).WithResource(schema, "v1", storage).WithClusters(SingleClusters).Build()
+	require.NoError(t, err)
+	accepted, _, _ = vw.ResolveRootPath("/services/test/domain/clusters/*/apis/example.kcp.io/v1/widgets", context.Background())
+	require.False(t, accepted)
+	vw, err = NewBuilder(resolver).WithResource(schema, "v1", storage).WithClusters(WildcardCluster).Build()
+	require.NoError(t, err)
+	accepted, _, _ = vw.ResolveRootPath("/services/test/domain/clusters/root/apis/example.kcp.io/v1/widgets", context.Background())
+	require.False(t, accepted)
+	accepted, _, _ = vw.ResolveRootPath("/services/test/domain/clusters/*/apis/example.kcp.io/v1/widgets", context.Background())
+	require.True(t, accepted)
+}
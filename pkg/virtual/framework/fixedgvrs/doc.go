@@ -0,0 +1,28 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixedgvrs provides a builder for dynamic virtual workspaces serving a fixed set of
+// resources, described by APIResourceSchemas, in every API domain.
+//
+// The builder wires a PathResolver, an authorizer.Authorizer and a StorageProvider per resource
+// into a dynamic.DynamicVirtualWorkspace. It defaults to
+//
+// - deny all requests, unless an authorizer is given,
+//
+// - be ready immediately, unless a framework.ReadyChecker is given,
+//
+// - accept requests for single logical clusters and the wildcard.
+package fixedgvrs
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedgvrs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// PathResolver resolves the logical cluster and the API domain of a request from its URL path.
+type PathResolver interface {
+	// ResolvePath returns whether the request is accepted. If accepted, prefixToStrip is the prefix
+	// in front of the kube-like API surface, including `/clusters/<cluster>`.
+	ResolvePath(urlPath string) (cluster genericapirequest.Cluster, apiDomain dynamiccontext.APIDomainKey, prefixToStrip string, accepted bool)
+}
+
+// PathResolverFunc is a function implementing PathResolver.
+type PathResolverFunc func(urlPath string) (cluster genericapirequest.Cluster, apiDomain dynamiccontext.APIDomainKey, prefixToStrip string, accepted bool)
+
+func (f PathResolverFunc) ResolvePath(urlPath string) (cluster genericapirequest.Cluster, apiDomain dynamiccontext.APIDomainKey, prefixToStrip string, accepted bool) {
+	return f(urlPath)
+}
+
+var _ PathResolver = PathResolverFunc(nil)
+
+// StorageProvider provides the REST storage of a resource in an API domain.
+type StorageProvider interface {
+	StorageFor(ctx context.Context, apiDomain dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error)
+}
+
+// StorageProviderFunc is a function implementing StorageProvider.
+type StorageProviderFunc func(ctx context.Context, apiDomain dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error)
+
+func (f StorageProviderFunc) StorageFor(ctx context.Context, apiDomain dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error) {
+	return f(ctx, apiDomain)
+}
+
+var _ StorageProvider = StorageProviderFunc(nil)
+
+// Clusters restricts the logical clusters a virtual workspace serves requests for.
+type Clusters int
+
+const (
+	// AllClusters accepts requests for single logical clusters and the wildcard.
+	AllClusters Clusters = iota
+	// SingleClusters accepts requests for single logical clusters only.
+	SingleClusters
+	// WildcardCluster accepts requests for the wildcard only.
+	WildcardCluster
+)
+
+func (c Clusters) accepts(cluster genericapirequest.Cluster) bool {
+	switch c {
+	case SingleClusters:
+		return !cluster.Wildcard
+	case WildcardCluster:
+		return cluster.Wildcard
+	default:
+		return true
+	}
+}
+
+// NewPrefixPathResolver returns a PathResolver for URL paths of the shape
+//
+//	<rootPathPrefix>/<segment 1>/.../<segment n>/clusters/<cluster>/<kube-like API surface>
+//
+// where the n segments in front of the cluster, joined by slashes, are the API domain. The
+// segments must not be empty.
+func NewPrefixPathResolver(rootPathPrefix string, apiDomainSegments int) PathResolver {
+	if !strings.HasSuffix(rootPathPrefix, "/") {
+		rootPathPrefix += "/"
+	}
+
+	return PathResolverFunc(func(urlPath string) (genericapirequest.Cluster, dynamiccontext.APIDomainKey, string, bool) {
+		if !strings.HasPrefix(urlPath, rootPathPrefix) {
+			return genericapirequest.Cluster{}, "", "", false
+		}
+		parts := strings.SplitN(strings.TrimPrefix(urlPath, rootPathPrefix), "/", apiDomainSegments+1)
+		if len(parts) < apiDomainSegments+1 {
+			return genericapirequest.Cluster{}, "", "", false
+		}
+		for _, part := range parts[:apiDomainSegments] {
+			if part == "" {
+				return genericapirequest.Cluster{}, "", "", false
+			}
+		}
+		apiDomain := dynamiccontext.APIDomainKey(strings.Join(parts[:apiDomainSegments], "/"))
+
+		realPath := "/" + parts[apiDomainSegments]
+		if !strings.HasPrefix(realPath, "/clusters/") {
+			return genericapirequest.Cluster{}, "", "", false
+		}
+		parts = strings.SplitN(strings.TrimPrefix(realPath, "/clusters/"), "/", 2)
+		path := logicalcluster.NewPath(parts[0])
+		realPath = "/"
+		if len(parts) > 1 {
+			realPath += parts[1]
+		}
+
+		cluster := genericapirequest.Cluster{}
+		if path == logicalcluster.Wildcard {
+			cluster.Wildcard = true
+		} else {
+			var ok bool
+			cluster.Name, ok = path.Name()
+			if !ok {
+				return genericapirequest.Cluster{}, "", "", false
+			}
+		}
+
+		return cluster, apiDomain, strings.TrimSuffix(urlPath, realPath), true
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// metadataFieldsMatcher returns a function matching objects against the metadata.name and
+// metadata.namespace requirements of the field selector, or nil if there are none.
+//
+// Field selectors are passed to the delegate, but not every delegate evaluates them, e.g. for
+// wildcard requests across logical clusters. Evaluating the metadata fields, which every resource
+// supports, once more guarantees that lists and watches are restricted accordingly. Other fields
+// are only evaluated by the delegate.
+func metadataFieldsMatcher(selector fields.Selector) func(obj metav1.Object) bool {
+	if selector == nil || selector.Empty() {
+		return nil
+	}
+	var requirements fields.Requirements
+	for _, r := range selector.Requirements() {
+		if r.Field == "metadata.name" || r.Field == "metadata.namespace" {
+			requirements = append(requirements, r)
+		}
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	return func(obj metav1.Object) bool {
+		for _, r := range requirements {
+			value := obj.GetName()
+			if r.Field == "metadata.namespace" {
+				value = obj.GetNamespace()
+			}
+			switch r.Operator {
+			case selection.Equals, selection.DoubleEquals:
+				if value != r.Value {
+					return false
+				}
+			case selection.NotEquals:
+				if value == r.Value {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
+// filterList removes the items of the list not matching.
+func filterList(list *unstructured.UnstructuredList, matches func(obj metav1.Object) bool) {
+	items := list.Items[:0]
+	for _, item := range list.Items {
+		if matches(&item) {
+			items = append(items, item)
+		}
+	}
+	list.Items = items
+}
+
+// filterWatch drops the events of objects not matching. As the matched fields are immutable,
+// objects never start or stop matching during their lifetime.
+func filterWatch(w watch.Interface, matches func(obj metav1.Object) bool) watch.Interface {
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+			return event, true
+		}
+		metaObj, err := meta.Accessor(event.Object)
+		if err != nil {
+			return event, true
+		}
+		return event, matches(metaObj)
+	})
+}
@@ -19,6 +19,7 @@ package forwardingregistry
 import (
 	"context"
 
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
 	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
 	"k8s.io/apimachinery/pkg/api/validation/path"
@@ -134,6 +135,7 @@ func ProvideReadOnlyRestStorage(ctx context.Context, dynamicClusterClientFunc Dy
 		namespaceScoped bool,
 		schemaValidator *validate.SchemaValidator,
 		subresourcesSchemaValidator map[string]*validate.SchemaValidator,
+		_ *apiextensions.CustomResourceSubresourceScale,
 		structuralSchema *structuralschema.Structural,
 	) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage) {
 		statusSchemaValidate := subresourcesSchemaValidator["status"]
@@ -41,8 +41,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -50,6 +52,7 @@ import (
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/client-go/util/retry"
 
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
 )
 
@@ -58,6 +61,13 @@ var noxusGVR = schema.GroupVersionResource{Group: "mygroup.example.com", Resourc
 func newStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExportIdentityHash string, patchConflictRetryBackoff *wait.Backoff) (mainStorage, statusStorage rest.Storage) {
 	t.Helper()
 
+	return newWrappedStorage(t, clusterClient, apiExportIdentityHash, patchConflictRetryBackoff, forwardingregistry.StorageWrapperFunc(func(_ schema.GroupResource, store *forwardingregistry.StoreFuncs) {
+	}))
+}
+
+func newWrappedStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExportIdentityHash string, patchConflictRetryBackoff *wait.Backoff, wrapper forwardingregistry.StorageWrapper) (mainStorage, statusStorage rest.Storage) {
+	t.Helper()
+
 	gvr := noxusGVR
 	groupVersion := gvr.GroupVersion()
 
@@ -114,8 +124,7 @@ func newStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExpo
 		nil,
 		func(ctx context.Context) (kcpdynamic.ClusterInterface, error) { return clusterClient, nil },
 		patchConflictRetryBackoff,
-		forwardingregistry.StorageWrapperFunc(func(_ schema.GroupResource, store *forwardingregistry.StoreFuncs) {
-		}))
+		wrapper)
 }
 
 func createResource(namespace, name string) *unstructured.Unstructured {
@@ -181,6 +190,46 @@ func TestList(t *testing.T) {
 	require.Equal(t, "noxus", fakeClient.Actions()[0].GetResource().Resource)
 }
 
+func TestListWithFieldSelector(t *testing.T) {
+	resources := []runtime.Object{createResource("default", "foo"), createResource("default", "foo2"), createResource("other", "foo")}
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resources...)
+	storage, _ := newStorage(t, fakeClient, "", nil)
+	ctx := request.WithNamespace(context.Background(), "")
+	ctx = request.WithCluster(ctx, request.Cluster{Wildcard: true})
+
+	tests := map[string]struct {
+		selector  string
+		wantNames []string
+	}{
+		"no selector":           {wantNames: []string{"default/foo", "default/foo2", "other/foo"}},
+		"name":                  {selector: "metadata.name=foo", wantNames: []string{"default/foo", "other/foo"}},
+		"namespace":             {selector: "metadata.namespace==other", wantNames: []string{"other/foo"}},
+		"name and namespace":    {selector: "metadata.name=foo,metadata.namespace!=other", wantNames: []string{"default/foo"}},
+		"other fields":          {selector: "spec.string=other", wantNames: []string{"default/foo", "default/foo2", "other/foo"}},
+		"no match":              {selector: "metadata.name=bar"},
+		"other fields and name": {selector: "spec.string=other,metadata.name=foo2", wantNames: []string{"default/foo2"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient.ClearActions()
+			selector, err := fields.ParseSelector(tc.selector)
+			require.NoError(t, err)
+
+			lister := storage.(rest.Lister)
+			result, err := lister.List(ctx, &internalversion.ListOptions{FieldSelector: selector})
+			require.NoError(t, err)
+			var names []string
+			for _, item := range result.(*unstructured.UnstructuredList).Items {
+				names = append(names, item.GetNamespace()+"/"+item.GetName())
+			}
+			require.ElementsMatch(t, tc.wantNames, names)
+
+			require.Len(t, fakeClient.Actions(), 1)
+			require.Equal(t, selector.String(), fakeClient.Actions()[0].(kcptesting.ListAction).GetListRestrictions().Fields.String(), "the field selector should be passed to the delegate")
+		})
+	}
+}
+
 func TestWildcardListWithAPIExportIdentity(t *testing.T) {
 	resources := []runtime.Object{createResource("default", "foo"), createResource("default", "foo2")}
 	noxusGVRWithHash := noxusGVR.GroupVersion().WithResource("noxus:" + "apiExportIdentityHash")
@@ -277,6 +326,39 @@ func TestWatch(t *testing.T) {
 	require.Equal(t, "noxus", fakeClient.Actions()[0].GetResource().Resource)
 }
 
+func TestWatchWithFieldSelector(t *testing.T) {
+	resources := []runtime.Object{createResource("default", "foo"), createResource("default", "foo2")}
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeWatcher := watch.NewFake()
+	defer fakeWatcher.Stop()
+	fakeClient.PrependWatchReactor("noxus", kcptesting.DefaultWatchReactor(fakeWatcher, nil))
+	storage, _ := newStorage(t, fakeClient, "", nil)
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+
+	watchedError := &metav1.Status{
+		Status:  "Failure",
+		Message: "message",
+	}
+
+	checkWatchEvents(t,
+		func() {
+			fakeWatcher.Add(resources[0])
+			fakeWatcher.Add(resources[1])
+			fakeWatcher.Modify(resources[1])
+			fakeWatcher.Delete(resources[0])
+			fakeWatcher.Error(watchedError)
+		},
+		func() (watch.Interface, error) {
+			watcher := storage.(rest.Watcher)
+			return watcher.Watch(ctx, &internalversion.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", "foo")})
+		}, []watch.Event{
+			{Type: watch.Added, Object: resources[0]},
+			{Type: watch.Deleted, Object: resources[0]},
+			{Type: watch.Error, Object: watchedError},
+		})
+}
+
 func TestWildcardWatchWithPIExportIdentity(t *testing.T) {
 	resources := []runtime.Object{createResource("default", "foo"), createResource("default", "foo2")}
 	noxusGVRWithHash := noxusGVR.GroupVersion().WithResource("noxus:apiExportIdentityHash")
@@ -534,3 +616,146 @@ func TestPatch(t *testing.T) {
 	}
 	require.Equalf(t, backoff.Steps, updates, "Should have tried calling client.Update %d times to overcome resourceVersion conflicts, before finally returning a Conflict error.", backoff.Steps)
 }
+
+func TestApply(t *testing.T) {
+	resource := createResource("default", "foo")
+	resource.SetResourceVersion("100")
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resource)
+	var patchAction kcptesting.PatchAction
+	fakeClient.PrependReactor("patch", "noxus", func(action kcptesting.Action) (handled bool, ret runtime.Object, err error) {
+		patchAction = action.(kcptesting.PatchAction)
+		applied := resource.DeepCopy()
+		applied.SetResourceVersion("101")
+		return true, applied, nil
+	})
+
+	storage, _ := newStorage(t, fakeClient, "", nil)
+	patch := []byte("apiVersion: mygroup.example.com/v1beta1\nkind: Noxu\nmetadata:\n  name: foo\nspec:\n  replicas: 3\n")
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{Verb: "patch"})
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+	ctx = dynamiccontext.WithApplyPatch(ctx, dynamiccontext.ApplyPatch{Patch: patch, Force: true})
+
+	applied := false
+	applier := func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		applied = true
+		obj := oldObj.DeepCopyObject().(*unstructured.Unstructured)
+		require.NoError(t, unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas"))
+		return obj, nil
+	}
+
+	updater := storage.(rest.Updater)
+	result, _, err := updater.Update(ctx, resource.GetName(), rest.DefaultUpdatedObjectInfo(nil, applier), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{FieldManager: "controller"})
+	require.NoError(t, err)
+	require.True(t, applied, "the patch should still be applied locally, e.g. to detect conflicts")
+	require.Equal(t, "101", result.(*unstructured.Unstructured).GetResourceVersion())
+
+	require.NotNil(t, patchAction, "the apply patch should be forwarded to the delegate")
+	require.Equal(t, types.ApplyPatchType, patchAction.GetPatchType())
+	require.JSONEq(t, `{"apiVersion":"mygroup.example.com/v1beta1","kind":"Noxu","metadata":{"name":"foo","resourceVersion":"100"},"spec":{"replicas":3}}`, string(patchAction.GetPatch()),
+		"only the applied fields should be forwarded, with the resourceVersion they were computed against")
+	for _, action := range fakeClient.Actions() {
+		require.NotEqual(t, "update", action.GetVerb(), "the applied object should not be sent as an update")
+	}
+}
+
+func TestApplyRetriesOnConflict(t *testing.T) {
+	resource := createResource("default", "foo")
+	resource.SetResourceVersion("100")
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resource)
+	fakeClient.PrependReactor("get", "noxus", func(action kcptesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, resource.DeepCopy(), nil
+	})
+	var patches []string
+	fakeClient.PrependReactor("patch", "noxus", func(action kcptesting.Action) (handled bool, ret runtime.Object, err error) {
+		patches = append(patches, string(action.(kcptesting.PatchAction).GetPatch()))
+		if len(patches) == 1 {
+			// the object changed after the local apply.
+			resource.SetResourceVersion("101")
+			return true, nil, errors.NewConflict(schema.GroupResource{Group: "mygroup.example.com", Resource: "noxus"}, "foo", fmt.Errorf("the object has been modified"))
+		}
+		return true, resource.DeepCopy(), nil
+	})
+
+	storage, _ := newStorage(t, fakeClient, "", nil)
+	patch := []byte("apiVersion: mygroup.example.com/v1beta1\nkind: Noxu\nmetadata:\n  name: foo\nspec:\n  replicas: 3\n")
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{Verb: "patch"})
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+	ctx = dynamiccontext.WithApplyPatch(ctx, dynamiccontext.ApplyPatch{Patch: patch})
+
+	applier := func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		obj := oldObj.DeepCopyObject().(*unstructured.Unstructured)
+		require.NoError(t, unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas"))
+		return obj, nil
+	}
+
+	updater := storage.(rest.Updater)
+	_, _, err := updater.Update(ctx, resource.GetName(), rest.DefaultUpdatedObjectInfo(nil, applier), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{FieldManager: "controller"})
+	require.NoError(t, err)
+	require.Len(t, patches, 2, "the apply should be retried against the changed object")
+	require.Contains(t, patches[0], `"resourceVersion":"100"`)
+	require.Contains(t, patches[1], `"resourceVersion":"101"`)
+}
+
+// mutatingObjectInfo sets a label on the updated object and removes an annotation, like the storage
+// wrappers of virtual workspaces do.
+type mutatingObjectInfo struct {
+	rest.UpdatedObjectInfo
+}
+
+func (i *mutatingObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	obj, err := i.UpdatedObjectInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, err
+	}
+	u := obj.(*unstructured.Unstructured)
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels["mutated"] = "true"
+	u.SetLabels(labels)
+	annotations := u.GetAnnotations()
+	delete(annotations, "stripped")
+	u.SetAnnotations(annotations)
+	return u, nil
+}
+
+func TestApplyWithMutatingWrapper(t *testing.T) {
+	resource := createResource("default", "foo")
+	resource.SetResourceVersion("100")
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resource)
+	var patchAction kcptesting.PatchAction
+	fakeClient.PrependReactor("patch", "noxus", func(action kcptesting.Action) (handled bool, ret runtime.Object, err error) {
+		patchAction = action.(kcptesting.PatchAction)
+		return true, resource.DeepCopy(), nil
+	})
+
+	storage, _ := newWrappedStorage(t, fakeClient, "", nil, forwardingregistry.StorageWrapperFunc(func(_ schema.GroupResource, store *forwardingregistry.StoreFuncs) {
+		delegateUpdater := store.UpdaterFunc
+		store.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			return delegateUpdater.Update(ctx, name, &mutatingObjectInfo{objInfo}, createValidation, updateValidation, forceAllowCreate, options)
+		}
+	}))
+	patch := []byte("apiVersion: mygroup.example.com/v1beta1\nkind: Noxu\nmetadata:\n  name: foo\n  annotations:\n    stripped: \"true\"\nspec:\n  replicas: 3\n")
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{Verb: "patch"})
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+	ctx = dynamiccontext.WithApplyPatch(ctx, dynamiccontext.ApplyPatch{Patch: patch})
+
+	applier := func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		obj := oldObj.DeepCopyObject().(*unstructured.Unstructured)
+		obj.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: "test", "stripped": "true"})
+		require.NoError(t, unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas"))
+		return obj, nil
+	}
+
+	updater := storage.(rest.Updater)
+	_, _, err := updater.Update(ctx, resource.GetName(), rest.DefaultUpdatedObjectInfo(nil, applier), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{FieldManager: "controller"})
+	require.NoError(t, err)
+
+	require.NotNil(t, patchAction, "the apply patch should be forwarded to the delegate")
+	require.JSONEq(t, `{"apiVersion":"mygroup.example.com/v1beta1","kind":"Noxu","metadata":{"name":"foo","resourceVersion":"100","annotations":{},"labels":{"mutated":"true"}},"spec":{"replicas":3}}`, string(patchAction.GetPatch()),
+		"the mutations of the wrapper should be forwarded with the applied fields")
+}
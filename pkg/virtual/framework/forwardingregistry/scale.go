@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// NewScaleStorage returns a REST storage for the scale subresource that forwards calls to the
+// scale subresource of the delegate. Objects are first read through the given storage of the main
+// resource, such that its wrappers, e.g. object filters, also apply to the scale subresource.
+func NewScaleStorage(
+	resource schema.GroupVersionResource,
+	apiExportIdentityHash string,
+	namespaceScoped bool,
+	dynamicClusterClientFunc DynamicClusterClientFunc,
+	mainStorage *StoreFuncs,
+	patchConflictRetryBackoff *wait.Backoff,
+) *StoreFuncs {
+	if patchConflictRetryBackoff == nil {
+		patchConflictRetryBackoff = &retry.DefaultRetry
+	}
+
+	client := clientGetter(dynamicClusterClientFunc, namespaceScoped, resource, apiExportIdentityHash)
+	s := &StoreFuncs{}
+	s.FactoryFunc = func() runtime.Object {
+		return &autoscalingv1.Scale{}
+	}
+	s.DestroyerFunc = func() {}
+	s.GetterFunc = func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+		if _, err := mainStorage.Get(ctx, name, &metav1.GetOptions{}); err != nil {
+			return nil, err
+		}
+
+		delegate, err := client(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, err := delegate.Get(ctx, name, *options, "scale")
+		if err != nil {
+			return nil, err
+		}
+		return toScale(obj)
+	}
+	s.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, _ rest.ValidateObjectFunc, _ rest.ValidateObjectUpdateFunc, _ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+		doUpdate := func() (*autoscalingv1.Scale, error) {
+			// subresources never allow create on update, hence no forceAllowCreate handling.
+			oldObj, err := s.Get(ctx, name, &metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			obj, err := objInfo.UpdatedObject(ctx, oldObj)
+			if err != nil {
+				return nil, err
+			}
+
+			if applyPatch, ok := dynamiccontext.ApplyPatchFrom(ctx); ok {
+				patch, err := forwardedApplyPatch(applyPatch.Patch, oldObj, obj)
+				if err != nil {
+					return nil, err
+				}
+				delegate, err := client(ctx)
+				if err != nil {
+					return nil, err
+				}
+				result, err := delegate.Patch(ctx, name, types.ApplyPatchType, patch, updateToPatchOptions(options, applyPatch.Force), "scale")
+				if err != nil {
+					return nil, err
+				}
+				return toScale(result)
+			}
+
+			scale, ok := obj.(*autoscalingv1.Scale)
+			if !ok {
+				return nil, fmt.Errorf("not a Scale: %T", obj)
+			}
+
+			raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(scale)
+			if err != nil {
+				return nil, err
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			unstructuredObj.SetGroupVersionKind(autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+
+			delegate, err := client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result, err := delegate.Update(ctx, unstructuredObj, *options, "scale")
+			if err != nil {
+				return nil, err
+			}
+			return toScale(result)
+		}
+
+		if requestInfo, ok := genericapirequest.RequestInfoFrom(ctx); ok && requestInfo.Verb == "patch" {
+			var result *autoscalingv1.Scale
+			err := retry.RetryOnConflict(*patchConflictRetryBackoff, func() error {
+				var err error
+				result, err = doUpdate()
+				return err
+			})
+			return result, false, err
+		}
+
+		result, err := doUpdate()
+		return result, false, err
+	}
+	return s
+}
+
+// toScale converts the unstructured scale subresource returned by the delegate.
+func toScale(obj *unstructured.Unstructured) (*autoscalingv1.Scale, error) {
+	scale := &autoscalingv1.Scale{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
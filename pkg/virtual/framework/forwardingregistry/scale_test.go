@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry_test
+
+import (
+	"context"
+	"testing"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/stretchr/testify/require"
+
+	kcpfakedynamic "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/dynamic/fake"
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+)
+
+func scaleResource(replicas int64, resourceVersion string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "autoscaling/v1",
+			"kind":       "Scale",
+			"metadata": map[string]interface{}{
+				"namespace":       "default",
+				"name":            "foo",
+				"resourceVersion": resourceVersion,
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+			},
+			"status": map[string]interface{}{
+				"replicas": replicas,
+			},
+		},
+	}
+}
+
+func newScaleStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, visible func(name string) bool) rest.Storage {
+	t.Helper()
+
+	mainStorage := &forwardingregistry.StoreFuncs{
+		GetterFunc: func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			if !visible(name) {
+				return nil, errors.NewNotFound(noxusGVR.GroupResource(), name)
+			}
+			return createResource("default", name), nil
+		},
+	}
+
+	return forwardingregistry.NewScaleStorage(
+		noxusGVR,
+		"",
+		true,
+		func(ctx context.Context) (kcpdynamic.ClusterInterface, error) { return clusterClient, nil },
+		mainStorage,
+		nil,
+	)
+}
+
+func TestScaleGet(t *testing.T) {
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeClient.PrependReactor("get", "noxus", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		require.Equal(t, "scale", action.GetSubresource())
+		return true, scaleResource(7, "100"), nil
+	})
+	storage := newScaleStorage(t, fakeClient, func(name string) bool { return name == "foo" })
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+
+	getter := storage.(rest.Getter)
+	result, err := getter.Get(ctx, "foo", &metav1.GetOptions{})
+	require.NoError(t, err)
+	require.IsType(t, &autoscalingv1.Scale{}, result)
+	scale := result.(*autoscalingv1.Scale)
+	require.Equal(t, int32(7), scale.Spec.Replicas)
+	require.Equal(t, "100", scale.ResourceVersion)
+
+	_, err = getter.Get(ctx, "hidden", &metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err), "expected the scale of objects hidden by the main storage not to be found, got %v", err)
+	for _, action := range fakeClient.Actions() {
+		require.NotEqual(t, "hidden", action.(kcptesting.GetAction).GetName(), "the delegate should not be called for hidden objects")
+	}
+}
+
+func TestScaleUpdate(t *testing.T) {
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeClient.PrependReactor("get", "noxus", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, scaleResource(7, "100"), nil
+	})
+	var updated *unstructured.Unstructured
+	fakeClient.PrependReactor("update", "noxus", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		require.Equal(t, "scale", action.GetSubresource())
+		updated = action.(kcptesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+		result := updated.DeepCopy()
+		result.SetResourceVersion("101")
+		return true, result, nil
+	})
+	storage := newScaleStorage(t, fakeClient, func(name string) bool { return true })
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: "test"})
+
+	scaleUp := func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		scale := oldObj.DeepCopyObject().(*autoscalingv1.Scale)
+		scale.Spec.Replicas++
+		return scale, nil
+	}
+
+	updater := storage.(rest.Updater)
+	result, created, err := updater.Update(ctx, "foo", rest.DefaultUpdatedObjectInfo(nil, scaleUp), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.False(t, created)
+
+	require.NotNil(t, updated)
+	require.Equal(t, "autoscaling/v1", updated.GetAPIVersion())
+	require.Equal(t, "Scale", updated.GetKind())
+	replicas, _, err := unstructured.NestedInt64(updated.Object, "spec", "replicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(8), replicas)
+
+	scale := result.(*autoscalingv1.Scale)
+	require.Equal(t, int32(8), scale.Spec.Replicas)
+	require.Equal(t, "101", scale.ResourceVersion)
+}
@@ -18,25 +18,31 @@ package forwardingregistry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/yaml"
 
 	dynamicextension "github.com/kcp-dev/kcp/pkg/virtual/framework/client/dynamic"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
 )
 
 // StoreFuncs holds proto-functions that can be mutated by successive actors to wrap behavior.
@@ -172,7 +178,14 @@ func DefaultDynamicDelegatedStoreFuncs(
 			return nil, err
 		}
 
-		return delegate.List(ctx, v1ListOptions)
+		list, err := delegate.List(ctx, v1ListOptions)
+		if err != nil {
+			return nil, err
+		}
+		if matches := metadataFieldsMatcher(options.FieldSelector); matches != nil {
+			filterList(list, matches)
+		}
+		return list, nil
 	}
 	s.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, _ rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
 		delegate, err := client(ctx)
@@ -212,6 +225,17 @@ func DefaultDynamicDelegatedStoreFuncs(
 				return nil, fmt.Errorf("not an Unstructured: %T", obj)
 			}
 
+			if applyPatch, ok := dynamiccontext.ApplyPatchFrom(ctx); ok {
+				// Forward server-side apply requests as such, for the delegate to track the managed
+				// fields. Updating the applied object would attribute all applied fields to an
+				// update operation of the field manager.
+				patch, err := forwardedApplyPatch(applyPatch.Patch, oldObj, unstructuredObj)
+				if err != nil {
+					return nil, err
+				}
+				return delegate.Patch(ctx, name, types.ApplyPatchType, patch, updateToPatchOptions(options, applyPatch.Force), subResources...)
+			}
+
 			if oldObj == nil {
 				// The object does not currently exist.
 				// We switch to calling a create operation on the forwarding registry.
@@ -255,7 +279,14 @@ func DefaultDynamicDelegatedStoreFuncs(
 			}
 		}()
 
-		return delegate.Watch(watchCtx, v1ListOptions)
+		w, err := delegate.Watch(watchCtx, v1ListOptions)
+		if err != nil {
+			return nil, err
+		}
+		if matches := metadataFieldsMatcher(options.FieldSelector); matches != nil {
+			return filterWatch(w, matches), nil
+		}
+		return w, nil
 	}
 	s.TableConvertorFunc = tableConvertor.ConvertToTable
 	s.CategoriesProviderFunc = func() []string {
@@ -346,6 +377,91 @@ func updateToCreateOptions(uo *metav1.UpdateOptions) metav1.CreateOptions {
 	return co
 }
 
+// updateToPatchOptions creates a PatchOptions with the same field values as the provided UpdateOptions,
+// and the given force option of a server-side apply request.
+func updateToPatchOptions(uo *metav1.UpdateOptions, force bool) metav1.PatchOptions {
+	po := metav1.PatchOptions{
+		DryRun:          uo.DryRun,
+		Force:           &force,
+		FieldManager:    uo.FieldManager,
+		FieldValidation: uo.FieldValidation,
+	}
+	po.TypeMeta.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("PatchOptions"))
+	return po
+}
+
+// forwardedApplyPatch returns the server-side apply patch to forward to the delegate. obj is the result of
+// applying patch to oldObj, after admission and the storage wrappers, which may have mutated it. The
+// returned patch holds the fields of patch and the fields that differ between oldObj and obj, with their
+// values in obj. Hence, mutations are applied too, fields removed from obj are dropped from the patch, and
+// the field manager does not claim fields of other managers it has not applied. Lists of patch are
+// forwarded unchanged, i.e. mutations of list items in patch are lost.
+//
+// As the patch is computed against oldObj, it carries the resourceVersion of oldObj as precondition.
+// If the object changed in the meantime, the delegate rejects it with a conflict, and the request is
+// retried against the new object.
+func forwardedApplyPatch(patch []byte, oldObj, obj runtime.Object) ([]byte, error) {
+	patchFields := map[string]interface{}{}
+	if err := yaml.Unmarshal(patch, &patchFields); err != nil {
+		return nil, apiErrorBadRequest(fmt.Errorf("invalid apply patch: %w", err))
+	}
+	objFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	oldFields := map[string]interface{}{}
+	if oldObj != nil {
+		if oldFields, err = runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := appliedFields(objFields, patchFields, oldFields)
+	metadata, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		fields["metadata"] = metadata
+	}
+	// the managed fields of obj are computed by the local apply.
+	delete(metadata, "managedFields")
+	if oldObj != nil {
+		old, err := meta.Accessor(oldObj)
+		if err != nil {
+			return nil, err
+		}
+		metadata["resourceVersion"] = old.GetResourceVersion()
+	}
+	return json.Marshal(fields)
+}
+
+// appliedFields returns the fields of obj that are in patch or differ from old.
+func appliedFields(obj, patch, old map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range obj {
+		patchValue, inPatch := patch[key]
+		oldValue, inOld := old[key]
+		switch value := value.(type) {
+		case map[string]interface{}:
+			patchMap, _ := patchValue.(map[string]interface{})
+			oldMap, _ := oldValue.(map[string]interface{})
+			if nested := appliedFields(value, patchMap, oldMap); len(nested) > 0 || inPatch {
+				fields[key] = nested
+			}
+		case []interface{}:
+			if inPatch {
+				fields[key] = patchValue
+			} else if !inOld || !reflect.DeepEqual(value, oldValue) {
+				fields[key] = value
+			}
+		default:
+			if inPatch || value != nil && (!inOld || !reflect.DeepEqual(value, oldValue)) {
+				fields[key] = value
+			}
+		}
+	}
+	return fields
+}
+
 // apiErrorBadRequest returns a apierrors.StatusError with a BadRequest reason.
 func apiErrorBadRequest(err error) *apierrors.StatusError {
 	return &apierrors.StatusError{ErrStatus: metav1.Status{
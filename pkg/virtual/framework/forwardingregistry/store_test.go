@@ -67,3 +67,29 @@ func TestUpdateToCreateOptions(t *testing.T) {
 	}
 	require.Equalf(t, expectedCreateOptions, co, "CreateOptions should have the same fields as the UpdateOptions")
 }
+
+func TestUpdateToPatchOptions(t *testing.T) {
+	uo := &metav1.UpdateOptions{
+		DryRun: []string{
+			"All",
+		},
+		FieldManager:    "manager",
+		FieldValidation: "Strict",
+	}
+	po := updateToPatchOptions(uo, true)
+
+	force := true
+	expectedPatchOptions := metav1.PatchOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PatchOptions",
+			APIVersion: "meta.k8s.io/v1",
+		},
+		DryRun: []string{
+			"All",
+		},
+		Force:           &force,
+		FieldManager:    "manager",
+		FieldValidation: "Strict",
+	}
+	require.Equalf(t, expectedPatchOptions, po, "PatchOptions should have the same fields as the UpdateOptions")
+}
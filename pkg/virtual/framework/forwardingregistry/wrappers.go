@@ -18,15 +18,26 @@ package forwardingregistry
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
 )
 
 func WithStaticLabelSelector(labelSelector labels.Requirements) StorageWrapper {
@@ -74,5 +85,696 @@ func WithLabelSelector(labelSelectorFrom func(ctx context.Context) labels.Requir
 			options.LabelSelector = selector.Add(labelSelectorFrom(ctx)...)
 			return delegateWatcher.Watch(ctx, options)
 		}
+
+		// DeleteCollection deletes the subset of the requested objects matching the label selector,
+		// and reports the number of skipped objects as a warning.
+		delegateCollectionDeleter := storage.CollectionDeleterFunc
+		if delegateCollectionDeleter == nil {
+			return
+		}
+		storage.CollectionDeleterFunc = func(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+			requirements := labelSelectorFrom(ctx)
+			permitted := labels.Everything().Add(requirements...)
+
+			skipped := 0
+			if delegateLister != nil {
+				if _, err := eachListPage(ctx, delegateLister, listOptions, func(items []runtime.Object) error {
+					for _, item := range items {
+						metaObj, err := meta.Accessor(item)
+						if err != nil {
+							return err
+						}
+						if !permitted.Matches(labels.Set(metaObj.GetLabels())) {
+							skipped++
+						}
+					}
+					return nil
+				}); err != nil {
+					return nil, err
+				}
+			}
+
+			restricted := listOptions.DeepCopy()
+			selector := restricted.LabelSelector
+			if selector == nil {
+				selector = labels.Everything()
+			}
+			restricted.LabelSelector = selector.Add(requirements...)
+			result, err := delegateCollectionDeleter.DeleteCollection(ctx, deleteValidation, options, restricted)
+			if err != nil {
+				return nil, err
+			}
+
+			if skipped > 0 {
+				warning.AddWarning(ctx, "", skippedWarning(resource, skipped))
+			}
+			return result, nil
+		}
 	})
 }
+
+// strippedObject returns a copy of obj with only the type and the metadata identifying it: name,
+// namespace, uid, resourceVersion and logical cluster. It stands for an object that has stopped
+// passing a filter, such that its new content is not shown.
+func strippedObject(obj runtime.Object) (runtime.Object, error) {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	stripped := &unstructured.Unstructured{}
+	stripped.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	stripped.SetNamespace(metaObj.GetNamespace())
+	stripped.SetName(metaObj.GetName())
+	stripped.SetUID(metaObj.GetUID())
+	stripped.SetResourceVersion(metaObj.GetResourceVersion())
+	if cluster, found := metaObj.GetAnnotations()[logicalcluster.AnnotationKey]; found {
+		stripped.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: cluster})
+	}
+	return stripped, nil
+}
+
+// ObjectFilter returns whether an object is visible through the storage.
+type ObjectFilter func(obj metav1.Object) (bool, error)
+
+// WithObjectFilter restricts the storage to the objects passing the filter returned by filterFrom
+// for a request. A nil filter does not restrict the storage.
+//
+// Other than label selectors, filters cannot be passed to the delegate. Hence, lists and watches
+// are filtered after the fact, objects no longer passing the filter are reported as deleted to
+// watchers, stripped down to their identifying metadata, and objects are deleted one by one by DeleteCollection.
+func WithObjectFilter(filterFrom func(ctx context.Context) (ObjectFilter, error)) StorageWrapper {
+	return StorageWrapperFunc(func(resource schema.GroupResource, storage *StoreFuncs) {
+		passes := func(filter ObjectFilter, obj runtime.Object) (bool, error) {
+			metaObj, err := meta.Accessor(obj)
+			if err != nil {
+				return false, err
+			}
+			return filter(metaObj)
+		}
+
+		delegateGetter := storage.GetterFunc
+		storage.GetterFunc = func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			obj, err := delegateGetter.Get(ctx, name, options)
+			if err != nil || filter == nil {
+				return obj, err
+			}
+			if ok, err := passes(filter, obj); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, errors.NewNotFound(resource, name)
+			}
+			return obj, nil
+		}
+
+		delegateLister := storage.ListerFunc
+		storage.ListerFunc = func(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			list, err := delegateLister.List(ctx, options)
+			if err != nil || filter == nil {
+				return list, err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+			filtered := make([]runtime.Object, 0, len(items))
+			for _, item := range items {
+				if ok, err := passes(filter, item); err != nil {
+					return nil, err
+				} else if ok {
+					filtered = append(filtered, item)
+				}
+			}
+			if len(filtered) == len(items) {
+				return list, nil
+			}
+			if err := meta.SetList(list, filtered); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+
+		delegateWatcher := storage.WatcherFunc
+		storage.WatcherFunc = func(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			w, err := delegateWatcher.Watch(ctx, options)
+			if err != nil || filter == nil {
+				return w, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+					return event, true
+				}
+				if ok, err := passes(filter, event.Object); err == nil && ok {
+					return event, true
+				}
+				// The object might have passed the filter before it was modified.
+				// Let the watcher forget about it, without showing the new content.
+				if event.Type == watch.Modified {
+					stripped, err := strippedObject(event.Object)
+					if err != nil {
+						return watch.Event{Type: watch.Error, Object: &errors.NewInternalError(err).ErrStatus}, true
+					}
+					return watch.Event{Type: watch.Deleted, Object: stripped}, true
+				}
+				return event, false
+			}), nil
+		}
+
+		delegateCreater := storage.CreaterFunc
+		storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if filter != nil {
+				if err := checkCreated(ctx, resource, filter, obj); err != nil {
+					return nil, err
+				}
+			}
+			return delegateCreater.Create(ctx, obj, createValidation, options)
+		}
+
+		delegateUpdater := storage.UpdaterFunc
+		storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, false, err
+			}
+			if filter != nil {
+				objInfo = &filteredUpdatedObjectInfo{UpdatedObjectInfo: objInfo, resource: resource, name: name, filter: filter}
+			}
+			return delegateUpdater.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+		}
+
+		// Deletion requires the object to pass the filter. The UID precondition makes sure that
+		// the checked object is deleted, and not a replacement created in the meantime.
+		wrappedGetter := storage.GetterFunc
+		delegateGracefulDeleter := storage.GracefulDeleterFunc
+		storage.GracefulDeleterFunc = func(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, false, err
+			}
+			if filter != nil {
+				obj, err := wrappedGetter.Get(ctx, name, &metav1.GetOptions{})
+				if err != nil {
+					return nil, false, err
+				}
+				options = withUIDPrecondition(options, obj)
+			}
+			return delegateGracefulDeleter.Delete(ctx, name, deleteValidation, options)
+		}
+
+		delegateCollectionDeleter := storage.CollectionDeleterFunc
+		if delegateCollectionDeleter == nil || delegateLister == nil || delegateGracefulDeleter == nil {
+			return
+		}
+		storage.CollectionDeleterFunc = func(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+			filter, err := filterFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if filter == nil {
+				return delegateCollectionDeleter.DeleteCollection(ctx, deleteValidation, options, listOptions)
+			}
+
+			skipped := 0
+			var deleted []runtime.Object
+			list, err := eachListPage(ctx, delegateLister, listOptions, func(items []runtime.Object) error {
+				for _, item := range items {
+					metaObj, err := meta.Accessor(item)
+					if err != nil {
+						return err
+					}
+					if ok, err := filter(metaObj); err != nil {
+						return err
+					} else if !ok {
+						skipped++
+						continue
+					}
+					obj, _, err := delegateGracefulDeleter.Delete(ctx, metaObj.GetName(), deleteValidation, withUIDPrecondition(options, item))
+					if errors.IsNotFound(err) || errors.IsConflict(err) {
+						// deleted or replaced in the meantime.
+						continue
+					} else if err != nil {
+						return err
+					}
+					deleted = append(deleted, obj)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := meta.SetList(list, deleted); err != nil {
+				return nil, err
+			}
+			if listMeta, err := meta.ListAccessor(list); err == nil {
+				listMeta.SetContinue("")
+			}
+
+			if skipped > 0 {
+				warning.AddWarning(ctx, "", skippedWarning(resource, skipped))
+			}
+			return list, nil
+		}
+	})
+}
+
+// checkCreated returns a Forbidden error if the object to be created does not pass the filter.
+func checkCreated(ctx context.Context, resource schema.GroupResource, filter ObjectFilter, obj runtime.Object) error {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if metaObj.GetNamespace() == "" {
+		// the namespace of the request is only set on the object by the delegate.
+		if namespace, ok := genericapirequest.NamespaceFrom(ctx); ok && namespace != "" {
+			metaObj = &metav1.ObjectMeta{Namespace: namespace, Name: metaObj.GetName(), Labels: metaObj.GetLabels()}
+		}
+	}
+	if ok, err := filter(metaObj); err != nil {
+		return err
+	} else if !ok {
+		return errors.NewForbidden(resource, metaObj.GetName(), fmt.Errorf("the object is not covered by the permission claims"))
+	}
+	return nil
+}
+
+// filteredUpdatedObjectInfo hides existing objects not passing the filter, and forbids
+// updates of objects such that they do not pass the filter anymore.
+type filteredUpdatedObjectInfo struct {
+	rest.UpdatedObjectInfo
+	resource schema.GroupResource
+	name     string
+	filter   ObjectFilter
+}
+
+func (i *filteredUpdatedObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	if oldObj != nil {
+		metaObj, err := meta.Accessor(oldObj)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := i.filter(metaObj); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, errors.NewNotFound(i.resource, i.name)
+		}
+	}
+	obj, err := i.UpdatedObjectInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCreated(ctx, i.resource, i.filter, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func withUIDPrecondition(options *metav1.DeleteOptions, obj runtime.Object) *metav1.DeleteOptions {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil || metaObj.GetUID() == "" {
+		return options
+	}
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	} else {
+		options = options.DeepCopy()
+	}
+	if options.Preconditions == nil {
+		options.Preconditions = &metav1.Preconditions{}
+	}
+	if options.Preconditions.UID == nil {
+		uid := metaObj.GetUID()
+		options.Preconditions.UID = &uid
+	}
+	return options
+}
+
+// deleteCollectionPageSize is the page size of the lists of DeleteCollection.
+const deleteCollectionPageSize = 500
+
+// eachListPage lists the objects selected by options from the delegate in pages of
+// deleteCollectionPageSize objects, and calls fn with the objects of each page. It returns the
+// last page.
+func eachListPage(ctx context.Context, lister ListerFunc, options *internalversion.ListOptions, fn func(items []runtime.Object) error) (runtime.Object, error) {
+	pageOptions := options.DeepCopy()
+	if pageOptions == nil {
+		pageOptions = &internalversion.ListOptions{}
+	}
+	pageOptions.Limit = deleteCollectionPageSize
+	pageOptions.Continue = ""
+	for {
+		page, err := lister.List(ctx, pageOptions)
+		if err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(page)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(items); err != nil {
+			return nil, err
+		}
+		listMeta, err := meta.ListAccessor(page)
+		if err != nil {
+			return nil, err
+		}
+		if listMeta.GetContinue() == "" {
+			return page, nil
+		}
+		pageOptions.Continue = listMeta.GetContinue()
+		// continued pages are served at the resource version of the first one.
+		pageOptions.ResourceVersion = ""
+		pageOptions.ResourceVersionMatch = ""
+	}
+}
+
+// skippedWarning reports the number of objects skipped by DeleteCollection. The objects are not
+// named, as the caller is not supposed to see them.
+func skippedWarning(resource schema.GroupResource, skipped int) string {
+	return fmt.Sprintf("skipped %d %s not covered by the permission claims", skipped, resource)
+}
+
+func qualifiedName(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// WithListPaging bounds the memory of list requests. Lists the client paginates with limit
+// are served page by page: limit and continue are passed to the delegate, a limit larger than
+// maxPageSize is capped, and the response is continued with the continue token of the delegate.
+// Lists without limit are served as a whole, as the client expects, but fetched from the
+// delegate in pages of maxPageSize objects, such that the raw response of the delegate never
+// holds more than a page. Zero maxPageSize passes the client's limit through unchanged.
+//
+// If maxResponseBytes is positive, lists without limit whose items exceed that size in
+// JSON are rejected, asking the client to paginate with limit and continue. The size is
+// checked item by item while the pages are accumulated, such that a list is rejected before
+// it is held as a whole.
+//
+// The resourceVersion is passed through unchanged. Hence, with resourceVersion "0" the
+// delegate may serve the list from its watch cache and ignore the limit, like kube-apiserver.
+//
+// Cross-cluster lists with a limit are served with continue tokens of the wrapper, see
+// crossClusterContinueToken.
+//
+// WithListPaging should be the first wrapper applied, such that other wrappers see the
+// list options only once.
+func WithListPaging(maxPageSize, maxResponseBytes int64) StorageWrapper {
+	return StorageWrapperFunc(func(resource schema.GroupResource, storage *StoreFuncs) {
+		delegateLister := storage.ListerFunc
+		storage.ListerFunc = func(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+			if options.Limit <= 0 {
+				return listAllPages(ctx, resource, delegateLister, options, maxPageSize, maxResponseBytes)
+			}
+
+			pageOptions := options
+			if maxPageSize > 0 && options.Limit > maxPageSize {
+				pageOptions = options.DeepCopy()
+				pageOptions.Limit = maxPageSize
+			}
+			if cluster := genericapirequest.ClusterFrom(ctx); cluster != nil && cluster.Wildcard {
+				return listCrossClusterPage(ctx, resource, delegateLister, pageOptions)
+			}
+			return delegateLister.List(ctx, pageOptions)
+		}
+	})
+}
+
+// listAllPages serves a list without limit, fetching it from the delegate in pages of
+// pageSize objects and rejecting it as soon as its items exceed maxResponseBytes.
+func listAllPages(ctx context.Context, resource schema.GroupResource, delegateLister ListerFunc, options *internalversion.ListOptions, pageSize, maxResponseBytes int64) (runtime.Object, error) {
+	pageOptions := options
+	if pageSize > 0 {
+		pageOptions = options.DeepCopy()
+		pageOptions.Limit = pageSize
+	}
+
+	var result *unstructured.UnstructuredList
+	var size int64
+	for {
+		obj, err := delegateLister.List(ctx, pageOptions)
+		if err != nil {
+			return nil, err
+		}
+		page, ok := obj.(*unstructured.UnstructuredList)
+		if !ok {
+			return nil, fmt.Errorf("expected an UnstructuredList, got %T", obj)
+		}
+
+		// check the size item by item, such that the list stops growing as soon as it is too large.
+		if maxResponseBytes > 0 {
+			for i := range page.Items {
+				if size += jsonSize(page.Items[i].Object) + 1; size > maxResponseBytes {
+					return nil, newListTooLargeError(resource, maxResponseBytes)
+				}
+			}
+		}
+
+		if result == nil {
+			result = page
+		} else {
+			result.Items = append(result.Items, page.Items...)
+		}
+
+		if pageSize <= 0 || page.GetContinue() == "" {
+			break
+		}
+
+		// continuations are served from the snapshot of the first page.
+		if pageOptions == options {
+			pageOptions = options.DeepCopy()
+		}
+		pageOptions.Continue = page.GetContinue()
+		pageOptions.ResourceVersion = ""
+		pageOptions.ResourceVersionMatch = ""
+	}
+
+	result.SetContinue("")
+	result.SetRemainingItemCount(nil)
+	return result, nil
+}
+
+// jsonSize returns the size of the JSON encoding of the given unstructured value without
+// encoding it. It matches encoding/json up to the formatting of some floats.
+func jsonSize(v interface{}) int64 {
+	switch v := v.(type) {
+	case nil:
+		return int64(len("null"))
+	case bool:
+		if v {
+			return int64(len("true"))
+		}
+		return int64(len("false"))
+	case string:
+		return jsonStringSize(v)
+	case int64:
+		var buf [20]byte
+		return int64(len(strconv.AppendInt(buf[:0], v, 10)))
+	case float64:
+		var buf [32]byte
+		return int64(len(strconv.AppendFloat(buf[:0], v, 'g', -1, 64)))
+	case map[string]interface{}:
+		size := int64(2) // {}
+		for key, value := range v {
+			// "key":value,
+			size += jsonStringSize(key) + 1 + jsonSize(value) + 1
+		}
+		if len(v) > 0 {
+			size-- // no comma after the last field
+		}
+		return size
+	case []interface{}:
+		size := int64(2) // []
+		for _, value := range v {
+			size += jsonSize(value) + 1
+		}
+		if len(v) > 0 {
+			size--
+		}
+		return size
+	default:
+		// other types do not show up in objects decoded from JSON.
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return 0
+		}
+		return int64(len(bs))
+	}
+}
+
+// jsonStringSize returns the size of the given string in JSON, including quotes and the
+// escapes encoding/json applies.
+func jsonStringSize(s string) int64 {
+	size := int64(len(s)) + 2
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\' || c == '\n' || c == '\r' || c == '\t':
+			size++ // \x
+		case c < 0x20 || c == '<' || c == '>' || c == '&':
+			size += 5 // \u00xx
+		case c == 0xe2 && i+2 < len(s) && s[i+1] == 0x80 && (s[i+2] == 0xa8 || s[i+2] == 0xa9):
+			size += 3 // \u2028 and \u2029 replace three bytes by six
+		}
+	}
+	return size
+}
+
+func newListTooLargeError(resource schema.GroupResource, maxResponseBytes int64) error {
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusRequestEntityTooLarge,
+		Reason:  metav1.StatusReasonRequestEntityTooLarge,
+		Message: fmt.Sprintf("list of %s exceeds the maximum response size of %d bytes, use limit and continue to paginate", resource, maxResponseBytes),
+		Details: &metav1.StatusDetails{
+			Group: resource.Group,
+			Kind:  resource.Resource,
+		},
+	}}
+}
+
+// crossClusterContinueToken is the continue token of cross-cluster lists paginated by the
+// client. Objects of cross-cluster lists are ordered by logical cluster, and within a cluster
+// by namespace and name. The token records this progress, i.e. the last returned object, and
+// the list resumes right after it. Hence, pages have exactly the requested size, independent
+// of the page boundaries of the delegate, and a page never repeats or skips objects of the
+// snapshot, even if a cluster spans several pages.
+type crossClusterContinueToken struct {
+	// ResourceVersion is the resource version of the snapshot all pages are served from.
+	ResourceVersion string `json:"rv"`
+	// Continue is the continue token of the delegate page holding the object after the
+	// last returned one. It is empty for the first page of the delegate.
+	Continue string `json:"continue,omitempty"`
+	// Cluster is the logical cluster of the last returned object.
+	Cluster string `json:"cluster"`
+	// Name is the namespace/name of the last returned object, or the name if cluster-scoped.
+	Name string `json:"name"`
+}
+
+func encodeCrossClusterContinueToken(token *crossClusterContinueToken) (string, error) {
+	bs, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+
+func decodeCrossClusterContinueToken(value string) (*crossClusterContinueToken, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	token := &crossClusterContinueToken{}
+	if err := json.Unmarshal(bs, token); err != nil {
+		return nil, err
+	}
+	if token.ResourceVersion == "" || token.Cluster == "" || token.Name == "" {
+		return nil, fmt.Errorf("incomplete token")
+	}
+	return token, nil
+}
+
+// crossClusterKey is the storage order of objects of cross-cluster lists.
+func crossClusterKey(cluster string, obj metav1.Object) string {
+	return cluster + "/" + qualifiedName(obj)
+}
+
+// listCrossClusterPage serves a page of at most options.Limit objects of a cross-cluster list,
+// fetching pages of the same size from the delegate.
+func listCrossClusterPage(ctx context.Context, resource schema.GroupResource, delegateLister ListerFunc, options *internalversion.ListOptions) (runtime.Object, error) {
+	pageOptions := options.DeepCopy()
+
+	var after string
+	var token *crossClusterContinueToken
+	if options.Continue != "" {
+		var err error
+		if token, err = decodeCrossClusterContinueToken(options.Continue); err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid continue token of a cross-cluster list of %s: %v", resource, err))
+		}
+		after = token.Cluster + "/" + token.Name
+		pageOptions.Continue = token.Continue
+		pageOptions.ResourceVersion = ""
+		pageOptions.ResourceVersionMatch = ""
+		if token.Continue == "" {
+			// the delegate list starts over, from the snapshot of the first page.
+			pageOptions.ResourceVersion = token.ResourceVersion
+			pageOptions.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+		}
+	}
+
+	var result *unstructured.UnstructuredList
+	for {
+		obj, err := delegateLister.List(ctx, pageOptions)
+		if err != nil {
+			return nil, err
+		}
+		page, ok := obj.(*unstructured.UnstructuredList)
+		if !ok {
+			return nil, fmt.Errorf("expected an UnstructuredList, got %T", obj)
+		}
+		if result == nil {
+			result = &unstructured.UnstructuredList{Object: page.Object}
+			if token != nil {
+				result.SetResourceVersion(token.ResourceVersion)
+			}
+		}
+
+		for i := range page.Items {
+			item := &page.Items[i]
+			cluster := logicalcluster.From(item).String()
+			if after != "" && crossClusterKey(cluster, item) <= after {
+				continue
+			}
+			result.Items = append(result.Items, *item)
+
+			if int64(len(result.Items)) < options.Limit {
+				continue
+			}
+			next := &crossClusterContinueToken{
+				ResourceVersion: result.GetResourceVersion(),
+				Continue:        pageOptions.Continue,
+				Cluster:         cluster,
+				Name:            qualifiedName(item),
+			}
+			if i == len(page.Items)-1 {
+				if page.GetContinue() == "" {
+					// nothing left.
+					result.SetContinue("")
+					return result, nil
+				}
+				next.Continue = page.GetContinue()
+			}
+			value, err := encodeCrossClusterContinueToken(next)
+			if err != nil {
+				return nil, err
+			}
+			result.SetContinue(value)
+			result.SetRemainingItemCount(nil)
+			return result, nil
+		}
+
+		if page.GetContinue() == "" {
+			result.SetContinue("")
+			result.SetRemainingItemCount(nil)
+			return result, nil
+		}
+		pageOptions.Continue = page.GetContinue()
+		pageOptions.ResourceVersion = ""
+		pageOptions.ResourceVersionMatch = ""
+	}
+}
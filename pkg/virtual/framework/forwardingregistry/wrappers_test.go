@@ -0,0 +1,526 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
+)
+
+// pagingLister serves numItems objects, honouring limit and continue like a storage would.
+type pagingLister struct {
+	numItems int
+	requests []*internalversion.ListOptions
+}
+
+func (l *pagingLister) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	l.requests = append(l.requests, options.DeepCopy())
+	return l.page(options, func(i int) unstructured.Unstructured {
+		item := unstructured.Unstructured{}
+		item.SetName(fmt.Sprintf("item-%d", i))
+		return item
+	})
+}
+
+func (l *pagingLister) page(options *internalversion.ListOptions, item func(i int) unstructured.Unstructured) (runtime.Object, error) {
+	start := 0
+	if options.Continue != "" {
+		var err error
+		if start, err = strconv.Atoi(options.Continue); err != nil {
+			return nil, err
+		}
+	}
+	end := l.numItems
+	if options.Limit > 0 && start+int(options.Limit) < end {
+		end = start + int(options.Limit)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetResourceVersion("42")
+	for i := start; i < end; i++ {
+		list.Items = append(list.Items, item(i))
+	}
+	if end < l.numItems {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	return list, nil
+}
+
+func TestWithListPaging(t *testing.T) {
+	resource := schema.GroupResource{Group: "example.io", Resource: "things"}
+
+	tests := map[string]struct {
+		maxPageSize, maxResponseBytes int64
+		numItems                      int
+		options                       *internalversion.ListOptions
+		wantLimit                     int64
+		wantItems                     int
+		wantRequests                  int
+		wantContinue                  string
+		wantTooLarge                  bool
+	}{
+		"unlimited list is fetched in pages": {
+			maxPageSize:  2,
+			numItems:     5,
+			options:      &internalversion.ListOptions{},
+			wantLimit:    2,
+			wantItems:    5,
+			wantRequests: 3,
+		},
+		"larger client limit is capped": {
+			maxPageSize:  2,
+			numItems:     5,
+			options:      &internalversion.ListOptions{Limit: 3},
+			wantLimit:    2,
+			wantItems:    2,
+			wantRequests: 1,
+			wantContinue: "2",
+		},
+		"client limit and continue are passed through": {
+			maxPageSize:  3,
+			numItems:     5,
+			options:      &internalversion.ListOptions{Limit: 2, Continue: "2"},
+			wantLimit:    2,
+			wantItems:    2,
+			wantRequests: 1,
+			wantContinue: "4",
+		},
+		"last page": {
+			maxPageSize:  2,
+			numItems:     5,
+			options:      &internalversion.ListOptions{Limit: 2, Continue: "4"},
+			wantLimit:    2,
+			wantItems:    1,
+			wantRequests: 1,
+		},
+		"paging disabled": {
+			numItems:         5,
+			maxResponseBytes: 1 << 20,
+			options:          &internalversion.ListOptions{},
+			wantItems:        5,
+			wantRequests:     1,
+		},
+		"too large": {
+			maxPageSize:      2,
+			maxResponseBytes: 100,
+			numItems:         5,
+			options:          &internalversion.ListOptions{},
+			wantLimit:        2,
+			wantTooLarge:     true,
+		},
+		"too large without paging": {
+			maxResponseBytes: 100,
+			numItems:         5,
+			options:          &internalversion.ListOptions{},
+			wantTooLarge:     true,
+		},
+		"too large is not checked for lists paginated by the client": {
+			maxPageSize:      2,
+			maxResponseBytes: 100,
+			numItems:         5,
+			options:          &internalversion.ListOptions{Limit: 2},
+			wantLimit:        2,
+			wantItems:        2,
+			wantRequests:     1,
+			wantContinue:     "2",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lister := &pagingLister{numItems: tc.numItems}
+			storage := &StoreFuncs{ListerFunc: lister.List}
+			WithListPaging(tc.maxPageSize, tc.maxResponseBytes).Decorate(resource, storage)
+
+			obj, err := storage.List(context.Background(), tc.options)
+			if tc.wantTooLarge {
+				require.True(t, errors.IsRequestEntityTooLargeError(err), "expected 413, got %v", err)
+				require.Less(t, len(lister.requests), 3, "a too large list must be rejected before it is fetched as a whole")
+				return
+			}
+			require.NoError(t, err)
+
+			list := obj.(*unstructured.UnstructuredList)
+			require.Len(t, list.Items, tc.wantItems)
+			require.Equal(t, tc.wantContinue, list.GetContinue())
+			require.Equal(t, "42", list.GetResourceVersion())
+			require.Len(t, lister.requests, tc.wantRequests)
+			require.Equal(t, tc.wantLimit, lister.requests[0].Limit)
+			require.Equal(t, tc.options.Continue, lister.requests[0].Continue)
+		})
+	}
+
+	t.Run("resourceVersion 0 is passed through", func(t *testing.T) {
+		lister := &pagingLister{numItems: 3}
+		storage := &StoreFuncs{ListerFunc: lister.List}
+		WithListPaging(2, 0).Decorate(resource, storage)
+
+		_, err := storage.List(context.Background(), &internalversion.ListOptions{ResourceVersion: "0"})
+		require.NoError(t, err)
+		require.Len(t, lister.requests, 2)
+		require.Equal(t, "0", lister.requests[0].ResourceVersion)
+		require.Equal(t, "", lister.requests[1].ResourceVersion, "continuations must not set a resourceVersion")
+	})
+}
+
+func TestJSONSize(t *testing.T) {
+	objects := []map[string]interface{}{
+		{},
+		{"metadata": map[string]interface{}{"name": "item-0"}},
+		{
+			"apiVersion": "example.io/v1",
+			"kind":       "Thing",
+			"metadata": map[string]interface{}{
+				"name":        "a",
+				"labels":      map[string]interface{}{"app": "<x>&\"y\""},
+				"annotations": map[string]interface{}{"note": "line\nbreak\ttab\\ \x01 \u2028 äöü"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(-42),
+				"ratio":    float64(0.5),
+				"enabled":  true,
+				"disabled": false,
+				"nothing":  nil,
+				"list":     []interface{}{},
+				"items":    []interface{}{int64(1), "two", map[string]interface{}{"three": float64(3)}},
+			},
+		},
+	}
+	for _, obj := range objects {
+		bs, err := json.Marshal(obj)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(bs)), jsonSize(obj), "size of %s", bs)
+	}
+}
+
+// crossClusterLister serves objects of several logical clusters in storage order, honouring
+// limit and continue like a storage would.
+type crossClusterLister struct {
+	items    []unstructured.Unstructured
+	requests []*internalversion.ListOptions
+}
+
+func newCrossClusterLister(keys ...string) *crossClusterLister {
+	sort.Strings(keys)
+	l := &crossClusterLister{}
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 2)
+		item := unstructured.Unstructured{}
+		item.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: parts[0]})
+		item.SetName(parts[1])
+		l.items = append(l.items, item)
+	}
+	return l
+}
+
+func (l *crossClusterLister) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	l.requests = append(l.requests, options.DeepCopy())
+	return (&pagingLister{numItems: len(l.items)}).page(options, func(i int) unstructured.Unstructured { return l.items[i] })
+}
+
+func TestWithListPagingCrossCluster(t *testing.T) {
+	resource := schema.GroupResource{Group: "example.io", Resource: "things"}
+	ctx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Wildcard: true})
+
+	for _, limit := range []int64{1, 2, 3} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			lister := newCrossClusterLister("root:a/x", "root:a/y", "root:a/z", "root:a-b/x", "root:b/x", "root:b/y", "root:c/x")
+			storage := &StoreFuncs{ListerFunc: lister.List}
+			WithListPaging(3, 0).Decorate(resource, storage)
+
+			var got []string
+			options := &internalversion.ListOptions{Limit: limit}
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, len(lister.items), "too many pages")
+				obj, err := storage.List(ctx, options)
+				require.NoError(t, err)
+				list := obj.(*unstructured.UnstructuredList)
+				require.Equal(t, "42", list.GetResourceVersion())
+				if list.GetContinue() != "" {
+					require.Len(t, list.Items, int(limit), "pages but the last must be full")
+				}
+				for i := range list.Items {
+					got = append(got, logicalcluster.From(&list.Items[i]).String()+"/"+list.Items[i].GetName())
+				}
+				if list.GetContinue() == "" {
+					break
+				}
+				options = &internalversion.ListOptions{Limit: limit, Continue: list.GetContinue()}
+			}
+			want := []string{}
+			for i := range lister.items {
+				want = append(want, logicalcluster.From(&lister.items[i]).String()+"/"+lister.items[i].GetName())
+			}
+			require.Equal(t, want, got, "every object must be listed exactly once, in storage order")
+
+			for _, r := range lister.requests {
+				require.Equal(t, limit, r.Limit, "the delegate must be paged with the client limit")
+				if r.Continue == "" && r.ResourceVersion != "" {
+					require.Equal(t, "42", r.ResourceVersion, "restarting the delegate must use the snapshot of the first page")
+					require.Equal(t, metav1.ResourceVersionMatchExact, r.ResourceVersionMatch)
+				}
+			}
+		})
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		lister := newCrossClusterLister("root:a/x", "root:a/y", "root:b/x")
+		storage := &StoreFuncs{ListerFunc: lister.List}
+		WithListPaging(2, 0).Decorate(resource, storage)
+
+		obj, err := storage.List(ctx, &internalversion.ListOptions{})
+		require.NoError(t, err)
+		list := obj.(*unstructured.UnstructuredList)
+		require.Len(t, list.Items, 3)
+		require.Empty(t, list.GetContinue())
+		require.Len(t, lister.requests, 2, "unlimited lists must be fetched in pages")
+	})
+
+	storage := &StoreFuncs{ListerFunc: newCrossClusterLister("root:a/x").List}
+	WithListPaging(2, 0).Decorate(resource, storage)
+	_, err := storage.List(ctx, &internalversion.ListOptions{Limit: 3, Continue: "3"})
+	require.True(t, errors.IsBadRequest(err), "expected a bad request error for a foreign continue token, got %v", err)
+}
+
+type warningRecorder []string
+
+func (r *warningRecorder) AddWarning(_, text string) {
+	*r = append(*r, text)
+}
+
+func TestWithLabelSelectorDeleteCollection(t *testing.T) {
+	resource := schema.GroupResource{Group: "example.io", Resource: "things"}
+	claimed := labels.Set{"claimed": "true"}
+	requirements, _ := labels.SelectorFromSet(claimed).Requirements()
+
+	item := func(name string, l labels.Set) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetLabels(l)
+		return obj
+	}
+	var deleteOptions *internalversion.ListOptions
+	storage := &StoreFuncs{
+		ListerFunc: func(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+				item("permitted", claimed),
+				item("unlabeled", nil),
+				item("other", labels.Set{"claimed": "false"}),
+			}}, nil
+		},
+		CollectionDeleterFunc: func(ctx context.Context, _ rest.ValidateObjectFunc, _ *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+			deleteOptions = listOptions
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{item("permitted", claimed)}}, nil
+		},
+	}
+	WithStaticLabelSelector(requirements).Decorate(resource, storage)
+
+	var warnings warningRecorder
+	ctx := warning.WithWarningRecorder(context.Background(), &warnings)
+	userSelector := labels.SelectorFromSet(labels.Set{"app": "foo"})
+	obj, err := storage.DeleteCollection(ctx, nil, &metav1.DeleteOptions{}, &internalversion.ListOptions{LabelSelector: userSelector})
+	require.NoError(t, err)
+	require.Len(t, obj.(*unstructured.UnstructuredList).Items, 1)
+
+	require.Equal(t, "app=foo,claimed=true", deleteOptions.LabelSelector.String(), "only permitted objects must be deleted")
+	require.Equal(t, []string{"skipped 2 things.example.io not covered by the permission claims"}, []string(warnings))
+
+	warnings = nil
+	lister := &pagingLister{numItems: deleteCollectionPageSize + 1}
+	storage.ListerFunc = lister.List
+	storage.CollectionDeleterFunc = func(ctx context.Context, _ rest.ValidateObjectFunc, _ *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+		return &unstructured.UnstructuredList{}, nil
+	}
+	WithStaticLabelSelector(requirements).Decorate(resource, storage)
+	_, err = storage.DeleteCollection(ctx, nil, &metav1.DeleteOptions{}, &internalversion.ListOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{fmt.Sprintf("skipped %d things.example.io not covered by the permission claims", deleteCollectionPageSize+1)}, []string(warnings))
+	require.Len(t, lister.requests, 2, "the objects must be listed page by page")
+	for _, r := range lister.requests {
+		require.Equal(t, int64(deleteCollectionPageSize), r.Limit)
+	}
+
+	warnings = nil
+	storage.ListerFunc = nil
+	storage.CollectionDeleterFunc = func(ctx context.Context, _ rest.ValidateObjectFunc, _ *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+		return &unstructured.UnstructuredList{}, nil
+	}
+	WithStaticLabelSelector(requirements).Decorate(resource, storage)
+	_, err = storage.DeleteCollection(ctx, nil, &metav1.DeleteOptions{}, &internalversion.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, warnings, "nothing is reported without a lister")
+}
+
+func TestWithObjectFilter(t *testing.T) {
+	resource := schema.GroupResource{Group: "example.io", Resource: "things"}
+	item := func(name string, l labels.Set) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetUID(types.UID(name))
+		obj.SetLabels(l)
+		return obj
+	}
+	objects := map[string]*unstructured.Unstructured{
+		"selected":   item("selected", labels.Set{"managed": "true"}),
+		"unselected": item("unselected", labels.Set{"managed": "false"}),
+	}
+
+	var deleted []string
+	var deletedUIDs []types.UID
+	fake := watch.NewFake()
+	storage := &StoreFuncs{
+		WatcherFunc: func(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+			return fake, nil
+		},
+		GetterFunc: func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			obj, found := objects[name]
+			if !found {
+				return nil, errors.NewNotFound(resource, name)
+			}
+			return obj.DeepCopy(), nil
+		},
+		ListerFunc: func(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*objects["selected"], *objects["unselected"]}}, nil
+		},
+		CreaterFunc: func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+			return obj, nil
+		},
+		UpdaterFunc: func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			obj, err := objInfo.UpdatedObject(ctx, objects[name].DeepCopy())
+			return obj, false, err
+		},
+		GracefulDeleterFunc: func(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+			deleted = append(deleted, name)
+			deletedUIDs = append(deletedUIDs, *options.Preconditions.UID)
+			return objects[name], true, nil
+		},
+		CollectionDeleterFunc: func(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+			return nil, fmt.Errorf("must not be called")
+		},
+	}
+	WithObjectFilter(func(ctx context.Context) (ObjectFilter, error) {
+		return func(obj metav1.Object) (bool, error) {
+			return obj.GetLabels()["managed"] == "true", nil
+		}, nil
+	}).Decorate(resource, storage)
+
+	var warnings warningRecorder
+	ctx := warning.WithWarningRecorder(context.Background(), &warnings)
+	ctx = genericapirequest.WithNamespace(ctx, "default")
+
+	t.Run("get", func(t *testing.T) {
+		_, err := storage.Get(ctx, "selected", &metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = storage.Get(ctx, "unselected", &metav1.GetOptions{})
+		require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list, err := storage.List(ctx, &internalversion.ListOptions{})
+		require.NoError(t, err)
+		items := list.(*unstructured.UnstructuredList).Items
+		require.Len(t, items, 1)
+		require.Equal(t, "selected", items[0].GetName())
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		w, err := storage.Watch(ctx, &internalversion.ListOptions{})
+		require.NoError(t, err)
+		defer w.Stop()
+
+		go func() {
+			fake.Add(objects["unselected"])
+			fake.Add(objects["selected"])
+			fake.Modify(objects["unselected"])
+			fake.Delete(objects["unselected"])
+			fake.Delete(objects["selected"])
+		}()
+		var got []watch.EventType
+		for i := 0; i < 3; i++ {
+			event := <-w.ResultChan()
+			got = append(got, event.Type)
+			if i == 1 {
+				stripped := event.Object.(*unstructured.Unstructured)
+				require.Equal(t, "unselected", stripped.GetName())
+				require.Empty(t, stripped.GetLabels(), "the content of an object no longer passing the filter must not be shown")
+			}
+		}
+		require.Equal(t, []watch.EventType{watch.Added, watch.Deleted, watch.Deleted}, got)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		obj := item("new", labels.Set{"managed": "true"})
+		obj.SetNamespace("")
+		_, err := storage.Create(ctx, obj, nil, &metav1.CreateOptions{})
+		require.NoError(t, err)
+		_, err = storage.Create(ctx, item("new", nil), nil, &metav1.CreateOptions{})
+		require.True(t, errors.IsForbidden(err), "expected Forbidden, got %v", err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		unlabel := rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+			obj := oldObj.(*unstructured.Unstructured).DeepCopy()
+			obj.SetLabels(nil)
+			return obj, nil
+		})
+		_, _, err := storage.Update(ctx, "unselected", rest.DefaultUpdatedObjectInfo(objects["unselected"]), nil, nil, false, &metav1.UpdateOptions{})
+		require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+		_, _, err = storage.Update(ctx, "selected", unlabel, nil, nil, false, &metav1.UpdateOptions{})
+		require.True(t, errors.IsForbidden(err), "expected Forbidden, got %v", err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted, deletedUIDs = nil, nil
+		_, _, err := storage.Delete(ctx, "unselected", nil, &metav1.DeleteOptions{})
+		require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+		_, _, err = storage.Delete(ctx, "selected", nil, &metav1.DeleteOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"selected"}, deleted)
+		require.Equal(t, []types.UID{"selected"}, deletedUIDs)
+	})
+
+	t.Run("delete collection", func(t *testing.T) {
+		deleted, warnings = nil, nil
+		list, err := storage.DeleteCollection(ctx, nil, &metav1.DeleteOptions{}, &internalversion.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, list.(*unstructured.UnstructuredList).Items, 1)
+		require.Equal(t, []string{"selected"}, deleted)
+		require.Equal(t, []string{"skipped 1 things.example.io not covered by the permission claims"}, []string(warnings))
+	})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+)
+
+// auditPolicies evaluates the audit policy of the virtual workspace of a request, and the audit
+// policy of the server for virtual workspaces without their own policy.
+//
+// The audit policy is evaluated deep in the handler chain, with the authorizer attributes of the
+// request only. The virtual workspace of the request is passed along with the authenticated user,
+// see withVirtualWorkspaceUser.
+type auditPolicies struct {
+	server            audit.PolicyRuleEvaluator
+	virtualWorkspaces map[string]audit.PolicyRuleEvaluator
+}
+
+// newAuditPolicies loads the audit policies of the virtual workspaces. Every policy file is loaded
+// once, also if shared by several virtual workspaces. It returns nil if no virtual workspace has an
+// audit policy of its own.
+func newAuditPolicies(server audit.PolicyRuleEvaluator, virtualWorkspaces []NamedVirtualWorkspace) (*auditPolicies, error) {
+	policies := &auditPolicies{server: server, virtualWorkspaces: map[string]audit.PolicyRuleEvaluator{}}
+	loaded := map[string]audit.PolicyRuleEvaluator{}
+	for _, vw := range virtualWorkspaces {
+		if vw.AuditPolicyFile == "" {
+			continue
+		}
+		evaluator, found := loaded[vw.AuditPolicyFile]
+		if !found {
+			p, err := policy.LoadPolicyFromFile(vw.AuditPolicyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the audit policy of the %s virtual workspace: %w", vw.Name, err)
+			}
+			evaluator = policy.NewPolicyRuleEvaluator(p)
+			loaded[vw.AuditPolicyFile] = evaluator
+		}
+		policies.virtualWorkspaces[vw.Name] = evaluator
+	}
+	if len(policies.virtualWorkspaces) == 0 {
+		return nil, nil
+	}
+	return policies, nil
+}
+
+func (p *auditPolicies) EvaluatePolicyRule(attrs authorizer.Attributes) audit.RequestAuditConfigWithLevel {
+	if u, ok := attrs.GetUser().(*virtualWorkspaceUser); ok {
+		if evaluator, found := p.virtualWorkspaces[u.virtualWorkspace]; found {
+			return evaluator.EvaluatePolicyRule(attrs)
+		}
+	}
+	if p.server == nil {
+		return audit.RequestAuditConfigWithLevel{Level: auditinternal.LevelNone}
+	}
+	return p.server.EvaluatePolicyRule(attrs)
+}
+
+// virtualWorkspaceUser is an authenticated user of a request to a virtual workspace.
+type virtualWorkspaceUser struct {
+	user.Info
+	virtualWorkspace string
+}
+
+// withVirtualWorkspaceUser records the virtual workspace of a request with the authenticated user,
+// for auditPolicies to evaluate the audit policy of the virtual workspace.
+func withVirtualWorkspaceUser(delegate authenticator.Request) authenticator.Request {
+	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		resp, ok, err := delegate.AuthenticateRequest(req)
+		if !ok || err != nil {
+			return resp, ok, err
+		}
+		name, found := virtualcontext.VirtualWorkspaceNameFrom(req.Context())
+		if !found {
+			return resp, ok, err
+		}
+		return &authenticator.Response{
+			Audiences: resp.Audiences,
+			User:      &virtualWorkspaceUser{Info: resp.User, virtualWorkspace: name},
+		}, true, nil
+	})
+}
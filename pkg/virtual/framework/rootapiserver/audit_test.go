@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+)
+
+func writePolicy(t *testing.T, level string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit-policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: "+level+"\n"), 0600))
+	return path
+}
+
+func TestAuditPolicies(t *testing.T) {
+	requestResponse := writePolicy(t, "RequestResponse")
+
+	policies, err := newAuditPolicies(nil, []NamedVirtualWorkspace{{Name: "apiexport"}})
+	require.NoError(t, err)
+	require.Nil(t, policies, "no routing without policies of virtual workspaces")
+
+	_, err = newAuditPolicies(nil, []NamedVirtualWorkspace{{Name: "apiexport", AuditPolicyFile: filepath.Join(t.TempDir(), "missing.yaml")}})
+	require.Error(t, err)
+
+	policies, err = newAuditPolicies(nil, []NamedVirtualWorkspace{
+		{Name: "apiexport", AuditPolicyFile: requestResponse},
+		{Name: "initializingworkspaces", AuditPolicyFile: requestResponse},
+		{Name: "other"},
+	})
+	require.NoError(t, err)
+	require.Same(t, policies.virtualWorkspaces["apiexport"], policies.virtualWorkspaces["initializingworkspaces"], "a shared policy file must be loaded once")
+
+	attrs := func(vw string) authorizer.Attributes {
+		var u user.Info = &user.DefaultInfo{Name: "alice"}
+		if vw != "" {
+			u = &virtualWorkspaceUser{Info: u, virtualWorkspace: vw}
+		}
+		return authorizer.AttributesRecord{User: u, Verb: "get", ResourceRequest: true, Resource: "widgets"}
+	}
+	require.Equal(t, auditinternal.LevelRequestResponse, policies.EvaluatePolicyRule(attrs("apiexport")).Level)
+	require.Equal(t, auditinternal.LevelNone, policies.EvaluatePolicyRule(attrs("other")).Level, "the policy of the server applies")
+	require.Equal(t, auditinternal.LevelNone, policies.EvaluatePolicyRule(attrs("")).Level, "the policy of the server applies")
+}
+
+func TestWithVirtualWorkspaceUser(t *testing.T) {
+	authn := withVirtualWorkspaceUser(authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "alice"}}, true, nil
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+	require.NoError(t, err)
+	resp, ok, err := authn.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.IsType(t, &user.DefaultInfo{}, resp.User, "requests outside of virtual workspaces are not tagged")
+
+	req = req.WithContext(virtualcontext.WithVirtualWorkspaceName(context.Background(), "apiexport"))
+	resp, ok, err = authn.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "alice", resp.User.GetName())
+	require.Equal(t, "apiexport", resp.User.(*virtualWorkspaceUser).virtualWorkspace)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package rootapiserver
 
 import (
+	"time"
+
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/rest"
 
@@ -26,6 +28,13 @@ import (
 type NamedVirtualWorkspace struct {
 	Name string
 	framework.VirtualWorkspace
+
+	// RequestTimeout is the deadline of non-long-running requests to the virtual workspace.
+	// Zero means the request timeout of the server applies.
+	RequestTimeout time.Duration
+	// AuditPolicyFile is the path to the audit policy of requests to the virtual workspace.
+	// Empty means the audit policy of the server applies.
+	AuditPolicyFile string
 }
 
 type Config struct {
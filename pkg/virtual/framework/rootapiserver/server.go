@@ -63,7 +63,12 @@ func NewServer(c CompletedConfig, delegationTarget genericapiserver.DelegationTa
 		}
 	}
 
-	c.Generic.BuildHandlerChainFunc = getRootHandlerChain(c, delegateAPIServer)
+	auditPolicies, err := newAuditPolicies(c.Generic.AuditPolicyRuleEvaluator, c.Extra.VirtualWorkspaces)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Generic.BuildHandlerChainFunc = getRootHandlerChain(c, delegateAPIServer, auditPolicies)
 	c.Generic.ReadyzChecks = append(c.Generic.ReadyzChecks, asHealthChecks(c.Extra.VirtualWorkspaces)...)
 
 	genericServer, err := c.Generic.New("virtual-workspaces-root-apiserver", delegateAPIServer)
@@ -78,19 +83,26 @@ func NewServer(c CompletedConfig, delegationTarget genericapiserver.DelegationTa
 	return s, nil
 }
 
-func getRootHandlerChain(c CompletedConfig, delegateAPIServer genericapiserver.DelegationTarget) func(http.Handler, *genericapiserver.Config) http.Handler {
+func getRootHandlerChain(c CompletedConfig, delegateAPIServer genericapiserver.DelegationTarget, auditPolicies *auditPolicies) func(http.Handler, *genericapiserver.Config) http.Handler {
 	return func(apiHandler http.Handler, genericConfig *genericapiserver.Config) http.Handler {
-		delegateAfterDefaultHandlerChain := genericapiserver.DefaultBuildHandlerChain(
-			http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-				if _, virtualWorkspaceNameExists := virtualcontext.VirtualWorkspaceNameFrom(req.Context()); virtualWorkspaceNameExists {
-					delegatedHandler := delegateAPIServer.UnprotectedHandler()
-					if delegatedHandler != nil {
-						delegatedHandler.ServeHTTP(w, req)
-					}
-					return
+		deadlines := newRequestDeadlines(c.Generic.RequestTimeout, c.Extra.VirtualWorkspaces, c.Generic.RequestInfoResolver, c.Generic.LongRunningFunc)
+		delegate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if _, virtualWorkspaceNameExists := virtualcontext.VirtualWorkspaceNameFrom(req.Context()); virtualWorkspaceNameExists {
+				delegatedHandler := delegateAPIServer.UnprotectedHandler()
+				if delegatedHandler != nil {
+					delegatedHandler.ServeHTTP(w, req)
 				}
-				apiHandler.ServeHTTP(w, req)
-			}), c.Generic.Config)
+				return
+			}
+			apiHandler.ServeHTTP(w, req)
+		})
+		chainConfig := *c.Generic.Config
+		chainConfig.RequestTimeout = deadlines.maxTimeout()
+		if auditPolicies != nil && chainConfig.Authentication.Authenticator != nil {
+			chainConfig.AuditPolicyRuleEvaluator = auditPolicies
+			chainConfig.Authentication.Authenticator = withVirtualWorkspaceUser(chainConfig.Authentication.Authenticator)
+		}
+		delegateAfterDefaultHandlerChain := genericapiserver.DefaultBuildHandlerChain(delegate, &chainConfig)
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			requestContext := req.Context()
 			// detect old kubectl plugins and inject warning headers
@@ -100,6 +112,7 @@ func getRootHandlerChain(c CompletedConfig, delegateAPIServer genericapiserver.D
 					fmt.Sprintf("You are using an old kubectl-kcp plugin. Please update to a version matching the kcp server version %q.", componentbaseversion.Get().GitVersion))
 			}
 
+			var vwName string
 			for _, vw := range c.Extra.VirtualWorkspaces {
 				if accepted, prefixToStrip, completedContext := vw.ResolveRootPath(req.URL.Path, requestContext); accepted {
 					req.URL.Path = strings.TrimPrefix(req.URL.Path, prefixToStrip)
@@ -112,11 +125,13 @@ func getRootHandlerChain(c CompletedConfig, delegateAPIServer genericapiserver.D
 						return
 					}
 					req.URL = newURL
+					completedContext = virtualcontext.WithVirtualWorkspacePathPrefix(completedContext, prefixToStrip)
 					req = req.WithContext(virtualcontext.WithVirtualWorkspaceName(completedContext, vw.Name))
+					vwName = vw.Name
 					break
 				}
 			}
-			delegateAfterDefaultHandlerChain.ServeHTTP(w, req)
+			deadlines.serveHTTP(delegateAfterDefaultHandlerChain, vwName, w, req)
 		})
 	}
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	requestDuration = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Name: "virtual_workspace_request_duration_seconds",
+			Help: "Latency distribution in seconds of non-long-running requests per virtual workspace.",
+			Buckets: []float64{0.05, 0.1, 0.2, 0.4, 0.6, 0.8, 1.0, 1.25, 1.5, 2, 3,
+				4, 5, 6, 8, 10, 15, 20, 30, 45, 60, 120, 300},
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"virtual_workspace"},
+	)
+	requestTimeouts = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "virtual_workspace_request_timeouts_total",
+			Help:           "Number of non-long-running requests per virtual workspace that exceeded their deadline.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"virtual_workspace"},
+	)
+)
+
+var registerMetrics sync.Once
+
+func init() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(requestDuration, requestTimeouts)
+	})
+}
+
+// requestDeadlines enforces the request timeouts of the virtual workspaces. The default handler chain
+// only knows one timeout for all requests. It is configured with the maximum of all timeouts, and the
+// shorter deadline of the virtual workspace is set on the request context before, which the
+// timeout filter of the chain honors.
+type requestDeadlines struct {
+	defaultTimeout time.Duration
+	timeouts       map[string]time.Duration
+
+	requestInfoResolver request.RequestInfoResolver
+	longRunning         request.LongRunningRequestCheck
+}
+
+func newRequestDeadlines(defaultTimeout time.Duration, virtualWorkspaces []NamedVirtualWorkspace, requestInfoResolver request.RequestInfoResolver, longRunning request.LongRunningRequestCheck) *requestDeadlines {
+	d := &requestDeadlines{
+		defaultTimeout:      defaultTimeout,
+		timeouts:            make(map[string]time.Duration, len(virtualWorkspaces)),
+		requestInfoResolver: requestInfoResolver,
+		longRunning:         longRunning,
+	}
+	for _, vw := range virtualWorkspaces {
+		if vw.RequestTimeout > 0 {
+			d.timeouts[vw.Name] = vw.RequestTimeout
+		}
+	}
+	return d
+}
+
+// maxTimeout is the timeout the default handler chain must be configured with.
+func (d *requestDeadlines) maxTimeout() time.Duration {
+	max := d.defaultTimeout
+	for _, timeout := range d.timeouts {
+		if timeout > max {
+			max = timeout
+		}
+	}
+	return max
+}
+
+// serveHTTP serves the request to the given virtual workspace, or to the root if the name is empty, with
+// the deadline of the virtual workspace.
+func (d *requestDeadlines) serveHTTP(handler http.Handler, vwName string, w http.ResponseWriter, req *http.Request) {
+	timeout, found := d.timeouts[vwName]
+	if !found {
+		timeout = d.defaultTimeout
+	}
+	if timeout <= 0 || d.isLongRunning(req) {
+		handler.ServeHTTP(w, req)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+
+	if vwName == "" {
+		return
+	}
+	requestDuration.WithLabelValues(vwName).Observe(time.Since(start).Seconds())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		requestTimeouts.WithLabelValues(vwName).Inc()
+	}
+}
+
+func (d *requestDeadlines) isLongRunning(req *http.Request) bool {
+	if d.longRunning == nil || d.requestInfoResolver == nil {
+		return false
+	}
+	info, err := d.requestInfoResolver.NewRequestInfo(req)
+	if err != nil {
+		// the handler chain will fail the request
+		return false
+	}
+	return d.longRunning(req, info)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestRequestDeadlines(t *testing.T) {
+	deadlines := newRequestDeadlines(time.Minute, []NamedVirtualWorkspace{
+		{Name: "short", RequestTimeout: 10 * time.Second},
+		{Name: "long", RequestTimeout: 5 * time.Minute},
+		{Name: "default"},
+	}, &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}, func(r *http.Request, requestInfo *request.RequestInfo) bool {
+		return requestInfo.Verb == "watch"
+	})
+
+	require.Equal(t, 5*time.Minute, deadlines.maxTimeout())
+
+	tests := map[string]struct {
+		vwName       string
+		url          string
+		wantDeadline time.Duration
+	}{
+		"shorter timeout":         {vwName: "short", url: "/api/v1/configmaps", wantDeadline: 10 * time.Second},
+		"longer timeout":          {vwName: "long", url: "/api/v1/configmaps", wantDeadline: 5 * time.Minute},
+		"default timeout":         {vwName: "default", url: "/api/v1/configmaps", wantDeadline: time.Minute},
+		"root":                    {url: "/healthz", wantDeadline: time.Minute},
+		"watch has no deadline":   {vwName: "short", url: "/api/v1/configmaps?watch=true"},
+		"numeric watch parameter": {vwName: "long", url: "/api/v1/configmaps?watch=1"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				deadline, hasDeadline = req.Context().Deadline()
+			})
+
+			start := time.Now()
+			deadlines.serveHTTP(handler, tc.vwName, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if tc.wantDeadline == 0 {
+				require.False(t, hasDeadline, "unexpected deadline")
+				return
+			}
+			require.True(t, hasDeadline, "expected deadline")
+			require.WithinDuration(t, start.Add(tc.wantDeadline), deadline, time.Second)
+		})
+	}
+}
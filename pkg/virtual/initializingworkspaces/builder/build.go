@@ -28,11 +28,9 @@ import (
 
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
-	"github.com/kcp-dev/logicalcluster/v3"
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	genericapiserver "k8s.io/apiserver/pkg/server"
@@ -46,10 +44,9 @@ import (
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
 	"github.com/kcp-dev/kcp/pkg/server/requestinfo"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
-	virtualworkspacesdynamic "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic"
-	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
 	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/fixedgvrs"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/handler"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 	"github.com/kcp-dev/kcp/pkg/virtual/initializingworkspaces"
@@ -67,10 +64,6 @@ func BuildVirtualWorkspace(
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	wildcardKcpInformers kcpinformers.SharedInformerFactory,
 ) ([]rootapiserver.NamedVirtualWorkspace, error) {
-	if !strings.HasSuffix(rootPathPrefix, "/") {
-		rootPathPrefix += "/"
-	}
-
 	logicalClusterResource := apisv1alpha1.APIResourceSchema{}
 	if err := rootphase0.Unmarshal("apiresourceschema-logicalclusters.core.kcp.io.yaml", &logicalClusterResource); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal logicalclusters resource: %w", err)
@@ -87,82 +80,45 @@ func BuildVirtualWorkspace(
 		v.Schema.Raw = bs // wipe schemas. We don't want validation here.
 	}
 
+	pathResolver := fixedgvrs.NewPrefixPathResolver(rootPathPrefix, 1)
 	cachingAuthorizer := delegated.NewCachingAuthorizer(kubeClusterClient, authorizerWithCache, delegated.CachingOptions{})
 
 	wildcardLogicalClustersName := initializingworkspaces.VirtualWorkspaceName + "-wildcard-logicalclusters"
-	wildcardLogicalClusters := &virtualworkspacesdynamic.DynamicVirtualWorkspace{
-		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
-			cluster, apiDomain, prefixToStrip, ok := digestUrl(urlPath, rootPathPrefix)
-			if !ok {
-				return false, "", requestContext
-			}
-
-			if !cluster.Wildcard {
-				// this virtual workspace requires that a wildcard be provided
-				return false, "", requestContext
-			}
-
-			completedContext = genericapirequest.WithCluster(requestContext, cluster)
-			completedContext = dynamiccontext.WithAPIDomainKey(completedContext, apiDomain)
-			return true, prefixToStrip, completedContext
-		}),
-		Authorizer: cachingAuthorizer,
-		ReadyChecker: framework.ReadyFunc(func() error {
-			return nil
-		}),
-		BootstrapAPISetManagement: func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
-			return &singleResourceAPIDefinitionSetProvider{
-				config:               mainConfig,
-				dynamicClusterClient: dynamicClusterClient,
-				exposeSubresources:   false,
-				resource:             &logicalClusterResource,
-				storageProvider:      filteredLogicalClusterReadOnlyRestStorage,
-			}, nil
-		},
+	wildcardLogicalClusters, err := fixedgvrs.NewBuilder(pathResolver).
+		WithClusters(fixedgvrs.WildcardCluster).
+		WithAuthorizer(cachingAuthorizer).
+		WithResource(&logicalClusterResource, corev1alpha1.SchemeGroupVersion.Version, fixedgvrs.StorageProviderFunc(func(ctx context.Context, apiDomain dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error) {
+			return filteredLogicalClusterReadOnlyRestStorage(ctx, dynamicClusterClient, corev1alpha1.LogicalClusterInitializer(apiDomain))
+		})).
+		Build()
+	if err != nil {
+		return nil, err
 	}
 
 	LogicalClustersName := initializingworkspaces.VirtualWorkspaceName + "-logicalclusters"
-	logicalClusters := &virtualworkspacesdynamic.DynamicVirtualWorkspace{
-		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, ctx context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
-			cluster, apiDomain, prefixToStrip, ok := digestUrl(urlPath, rootPathPrefix)
-			if !ok {
-				return false, "", ctx
-			}
-
-			if cluster.Wildcard {
-				// this virtual workspace requires that a specific cluster be provided
-				return false, "", ctx
-			}
-
-			// this delegating server only works for logicalclusters.core.kcp.io
-			if resourceURL := strings.TrimPrefix(urlPath, prefixToStrip); !isLogicalClusterRequest(resourceURL) {
-				return false, "", ctx
-			}
-
-			completedContext = genericapirequest.WithCluster(ctx, cluster)
-			completedContext = dynamiccontext.WithAPIDomainKey(completedContext, apiDomain)
-			return true, prefixToStrip, completedContext
-		}),
-		Authorizer: cachingAuthorizer,
-		ReadyChecker: framework.ReadyFunc(func() error {
-			return nil
-		}),
-		BootstrapAPISetManagement: func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
-			return &singleResourceAPIDefinitionSetProvider{
-				config:               mainConfig,
-				dynamicClusterClient: dynamicClusterClient,
-				exposeSubresources:   true,
-				resource:             &logicalClusterResource,
-				storageProvider:      delegatingLogicalClusterReadOnlyRestStorage,
-			}, nil
-		},
+	logicalClusters, err := fixedgvrs.NewBuilder(fixedgvrs.PathResolverFunc(func(urlPath string) (genericapirequest.Cluster, dynamiccontext.APIDomainKey, string, bool) {
+		cluster, apiDomain, prefixToStrip, ok := pathResolver.ResolvePath(urlPath)
+		// this delegating server only works for logicalclusters.core.kcp.io
+		if !ok || !isLogicalClusterRequest(strings.TrimPrefix(urlPath, prefixToStrip)) {
+			return genericapirequest.Cluster{}, "", "", false
+		}
+		return cluster, apiDomain, prefixToStrip, true
+	})).
+		WithClusters(fixedgvrs.SingleClusters).
+		WithAuthorizer(cachingAuthorizer).
+		WithResource(&logicalClusterResource, corev1alpha1.SchemeGroupVersion.Version, fixedgvrs.StorageProviderFunc(func(ctx context.Context, apiDomain dynamiccontext.APIDomainKey) (apiserver.RestProviderFunc, error) {
+			return delegatingLogicalClusterReadOnlyRestStorage(ctx, dynamicClusterClient, corev1alpha1.LogicalClusterInitializer(apiDomain))
+		})).
+		Build()
+	if err != nil {
+		return nil, err
 	}
 
 	workspaceContentReadyCh := make(chan struct{})
 	workspaceContentName := initializingworkspaces.VirtualWorkspaceName + "-workspace-content"
 	workspaceContent := &handler.VirtualWorkspace{
 		RootPathResolver: framework.RootPathResolverFunc(func(urlPath string, context context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
-			cluster, apiDomain, prefixToStrip, ok := digestUrl(urlPath, rootPathPrefix)
+			cluster, apiDomain, prefixToStrip, ok := pathResolver.ResolvePath(urlPath)
 			if !ok {
 				return false, "", context
 			}
@@ -305,106 +261,12 @@ func isLogicalClusterRequest(path string) bool {
 	return info.IsResourceRequest && info.APIGroup == corev1alpha1.SchemeGroupVersion.Group && info.Resource == "logicalclusters"
 }
 
-func digestUrl(urlPath, rootPathPrefix string) (
-	cluster genericapirequest.Cluster,
-	key dynamiccontext.APIDomainKey,
-	logicalPath string,
-	accepted bool,
-) {
-	if !strings.HasPrefix(urlPath, rootPathPrefix) {
-		return genericapirequest.Cluster{}, dynamiccontext.APIDomainKey(""), "", false
-	}
-	withoutRootPathPrefix := strings.TrimPrefix(urlPath, rootPathPrefix)
-
-	// Incoming requests to this virtual workspace will look like:
-	//  /services/initializingworkspaces/<initializer>/clusters/<something>/apis/workload.kcp.io/v1alpha1/synctargets
-	//                                  └───────────┐
-	// Where the withoutRootPathPrefix starts here: ┘
-	parts := strings.SplitN(withoutRootPathPrefix, "/", 2)
-	if len(parts) < 2 {
-		return genericapirequest.Cluster{}, dynamiccontext.APIDomainKey(""), "", false
-	}
-
-	initializerName := parts[0]
-	if initializerName == "" {
-		return genericapirequest.Cluster{}, dynamiccontext.APIDomainKey(""), "", false
-	}
-
-	realPath := "/" + parts[1]
-
-	//  /services/initializingworkspaces/<initializer>/clusters/<something>/apis/workload.kcp.io/v1alpha1/synctargets
-	//                  ┌─────────────────────────────┘
-	// We are now here: ┘
-	// Now, we parse out the logical cluster.
-	if !strings.HasPrefix(realPath, "/clusters/") {
-		return genericapirequest.Cluster{}, dynamiccontext.APIDomainKey(""), "", false // don't accept
-	}
-
-	withoutClustersPrefix := strings.TrimPrefix(realPath, "/clusters/")
-	parts = strings.SplitN(withoutClustersPrefix, "/", 2)
-	path := logicalcluster.NewPath(parts[0])
-	realPath = "/"
-	if len(parts) > 1 {
-		realPath += parts[1]
-	}
-
-	cluster = genericapirequest.Cluster{}
-	if path == logicalcluster.Wildcard {
-		cluster.Wildcard = true
-	} else {
-		var ok bool
-		cluster.Name, ok = path.Name()
-		if !ok {
-			return genericapirequest.Cluster{}, "", "", false
-		}
-	}
-
-	return cluster, dynamiccontext.APIDomainKey(initializerName), strings.TrimSuffix(urlPath, realPath), true
-}
-
 // URLFor returns the absolute path for the specified initializer.
 func URLFor(initializerName corev1alpha1.LogicalClusterInitializer) string {
 	// TODO(ncdc): make /services hard-coded everywhere instead of configurable.
 	return path.Join("/services", initializingworkspaces.VirtualWorkspaceName, string(initializerName))
 }
 
-type singleResourceAPIDefinitionSetProvider struct {
-	config               genericapiserver.CompletedConfig
-	dynamicClusterClient kcpdynamic.ClusterInterface
-	resource             *apisv1alpha1.APIResourceSchema
-	exposeSubresources   bool
-	storageProvider      func(ctx context.Context, clusterClient kcpdynamic.ClusterInterface, initializer corev1alpha1.LogicalClusterInitializer) (apiserver.RestProviderFunc, error)
-}
-
-func (a *singleResourceAPIDefinitionSetProvider) GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apis apidefinition.APIDefinitionSet, apisExist bool, err error) {
-	restProvider, err := a.storageProvider(ctx, a.dynamicClusterClient, corev1alpha1.LogicalClusterInitializer(key))
-	if err != nil {
-		return nil, false, err
-	}
-
-	apiDefinition, err := apiserver.CreateServingInfoFor(
-		a.config,
-		a.resource,
-		corev1alpha1.SchemeGroupVersion.Version,
-		restProvider,
-	)
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to create serving info: %w", err)
-	}
-
-	apis = apidefinition.APIDefinitionSet{
-		schema.GroupVersionResource{
-			Group:    corev1alpha1.SchemeGroupVersion.Group,
-			Version:  corev1alpha1.SchemeGroupVersion.Version,
-			Resource: "logicalclusters",
-		}: apiDefinition,
-	}
-
-	return apis, len(apis) > 0, nil
-}
-
-var _ apidefinition.APIDefinitionSetGetter = &singleResourceAPIDefinitionSetProvider{}
-
 func authorizerWithCache(ctx context.Context, cache delegated.Cache, attr authorizer.Attributes) (authorizer.Decision, string, error) {
 	clusterName, name, err := initialization.TypeFrom(corev1alpha1.LogicalClusterInitializer(dynamiccontext.APIDomainKeyFrom(ctx)))
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
 	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/validation/path"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -97,6 +98,7 @@ func delegatingLogicalClusterReadOnlyRestStorage(
 		namespaceScoped bool,
 		schemaValidator *validate.SchemaValidator,
 		subresourcesSchemaValidator map[string]*validate.SchemaValidator,
+		_ *apiextensions.CustomResourceSubresourceScale,
 		structuralSchema *structuralschema.Structural,
 	) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage) {
 		statusSchemaValidate, statusEnabled := subresourcesSchemaValidator["status"]
@@ -191,7 +193,7 @@ func delegatingLogicalClusterReadOnlyRestStorage(
 }
 
 // withUpdateValidation adds further validation to ensure that a user of this virtual workspace can only
-// remove their own initializer from the list.
+// remove their own initializer from the list, and only report progress for their own initializer.
 func withUpdateValidation(initializer corev1alpha1.LogicalClusterInitializer) registry.StorageWrapper {
 	return registry.StorageWrapperFunc(func(resource schema.GroupResource, storage *registry.StoreFuncs) {
 		delegateUpdater := storage.UpdaterFunc
@@ -216,17 +218,66 @@ func withUpdateValidation(initializer corev1alpha1.LogicalClusterInitializer) re
 						fmt.Sprintf("only removing the %q initializer is supported", initializer),
 					)},
 				)
-				if len(previous)-len(current) != 1 {
-					return invalidUpdateErr
-				}
-				for _, item := range current {
-					if item == string(initializer) {
+				// updates that leave the initializers untouched can only report progress
+				if !equality.Semantic.DeepEqual(previous, current) {
+					if len(previous)-len(current) != 1 {
 						return invalidUpdateErr
 					}
+					for _, item := range current {
+						if item == string(initializer) {
+							return invalidUpdateErr
+						}
+					}
+				}
+
+				previousProgress, err := foreignInitializerProgress(old.(*unstructured.Unstructured), initializer)
+				if err != nil {
+					return errors.NewInternalError(fmt.Errorf("error accessing initializer progress from old object: %w", err))
 				}
+				currentProgress, err := foreignInitializerProgress(obj.(*unstructured.Unstructured), initializer)
+				if err != nil {
+					logger.Error(err, "error accessing initializer progress from new object")
+					return errors.NewInternalError(fmt.Errorf("error accessing initializer progress from new object: %w", err))
+				}
+				if !equality.Semantic.DeepEqual(previousProgress, currentProgress) {
+					return errors.NewInvalid(
+						tenancyv1alpha1.Kind("Workspace"),
+						name,
+						field.ErrorList{field.Forbidden(
+							field.NewPath("status", "initializerProgress"),
+							fmt.Sprintf("only the progress of the %q initializer can be changed", initializer),
+						)},
+					)
+				}
+
 				return updateValidation(ctx, obj, old)
 			})
 			return delegateUpdater.Update(ctx, name, objInfo, createValidation, validation, forceAllowCreate, options)
 		}
 	})
 }
+
+// foreignInitializerProgress returns the progress entries of all initializers other than the given one,
+// keyed by initializer.
+func foreignInitializerProgress(obj *unstructured.Unstructured, initializer corev1alpha1.LogicalClusterInitializer) (map[string]interface{}, error) {
+	entries, _, err := unstructured.NestedSlice(obj.UnstructuredContent(), "status", "initializerProgress")
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]interface{}, len(entries))
+	for i, entry := range entries {
+		progress, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T of initializerProgress[%d]", entry, i)
+		}
+		name, _, err := unstructured.NestedString(progress, "initializer")
+		if err != nil {
+			return nil, err
+		}
+		if name == string(initializer) {
+			continue
+		}
+		ret[name] = progress
+	}
+	return ret, nil
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"path"
+	"time"
 
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
@@ -31,16 +33,31 @@ import (
 	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
 )
 
-type InitializingWorkspaces struct{}
+type InitializingWorkspaces struct {
+	// RequestTimeout is the deadline of non-long-running requests. Zero means the
+	// request timeout of the server applies.
+	RequestTimeout time.Duration
+	// AuditPolicyFile is the path to the audit policy of requests to the virtual workspace.
+	// Empty means the audit policy of the server applies.
+	AuditPolicyFile string
+}
 
 func New() *InitializingWorkspaces {
-	return &InitializingWorkspaces{}
+	return &InitializingWorkspaces{
+		// initializers tend to do bulk operations in the initializing workspaces.
+		RequestTimeout: 3 * time.Minute,
+	}
 }
 
 func (o *InitializingWorkspaces) AddFlags(flags *pflag.FlagSet, prefix string) {
 	if o == nil {
 		return
 	}
+
+	flags.DurationVar(&o.RequestTimeout, prefix+"initializingworkspaces-request-timeout", o.RequestTimeout,
+		"The deadline of non-long-running requests to the initializingworkspaces virtual workspace. 0 means the request timeout of the server applies.")
+	flags.StringVar(&o.AuditPolicyFile, prefix+"initializingworkspaces-audit-policy-file", o.AuditPolicyFile,
+		"Path to the file that defines the audit policy of requests to the initializingworkspaces virtual workspace. Empty means the audit policy of the server applies. Only takes effect with an audit backend, e.g. --audit-log-path.")
 }
 
 func (o *InitializingWorkspaces) Validate(flagPrefix string) []error {
@@ -49,6 +66,10 @@ func (o *InitializingWorkspaces) Validate(flagPrefix string) []error {
 	}
 	errs := []error{}
 
+	if o.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("--%sinitializingworkspaces-request-timeout must be non-negative", flagPrefix))
+	}
+
 	return errs
 }
 
@@ -67,5 +88,13 @@ func (o *InitializingWorkspaces) NewVirtualWorkspaces(
 		return nil, err
 	}
 
-	return builder.BuildVirtualWorkspace(config, path.Join(rootPathPrefix, initializingworkspaces.VirtualWorkspaceName), dynamicClusterClient, kubeClusterClient, wildcardKcpInformers)
+	workspaces, err = builder.BuildVirtualWorkspace(config, path.Join(rootPathPrefix, initializingworkspaces.VirtualWorkspaceName), dynamicClusterClient, kubeClusterClient, wildcardKcpInformers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range workspaces {
+		workspaces[i].RequestTimeout = o.RequestTimeout
+		workspaces[i].AuditPolicyFile = o.AuditPolicyFile
+	}
+	return workspaces, nil
 }
@@ -35,12 +35,16 @@ const virtualWorkspacesFlagPrefix = "virtual-workspaces-"
 type Options struct {
 	APIExport              *apiexportoptions.APIExport
 	InitializingWorkspaces *initializingworkspacesoptions.InitializingWorkspaces
+
+	// Providers holds the additional virtual workspace providers registered via RegisterProvider, by name.
+	Providers map[string]Provider
 }
 
 func NewOptions() *Options {
 	return &Options{
 		APIExport:              apiexportoptions.New(),
 		InitializingWorkspaces: initializingworkspacesoptions.New(),
+		Providers:              newRegisteredProviders(),
 	}
 }
 
@@ -49,12 +53,19 @@ func (o *Options) Validate() []error {
 
 	errs = append(errs, o.APIExport.Validate(virtualWorkspacesFlagPrefix)...)
 	errs = append(errs, o.InitializingWorkspaces.Validate(virtualWorkspacesFlagPrefix)...)
+	for _, name := range sortedProviderNames(o.Providers) {
+		errs = append(errs, o.Providers[name].Validate(virtualWorkspacesFlagPrefix)...)
+	}
 
 	return errs
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	o.APIExport.AddFlags(fs, virtualWorkspacesFlagPrefix)
 	o.InitializingWorkspaces.AddFlags(fs, virtualWorkspacesFlagPrefix)
+	for _, name := range sortedProviderNames(o.Providers) {
+		o.Providers[name].AddFlags(fs, virtualWorkspacesFlagPrefix)
+	}
 }
 
 func (o *Options) NewVirtualWorkspaces(
@@ -63,7 +74,7 @@ func (o *Options) NewVirtualWorkspaces(
 	wildcardKubeInformers kcpkubernetesinformers.SharedInformerFactory,
 	wildcardKcpInformers, cachedKcpInformers kcpinformers.SharedInformerFactory,
 ) ([]rootapiserver.NamedVirtualWorkspace, error) {
-	apiexports, err := o.APIExport.NewVirtualWorkspaces(rootPathPrefix, config, cachedKcpInformers)
+	apiexports, err := o.APIExport.NewVirtualWorkspaces(rootPathPrefix, config, wildcardKcpInformers, cachedKcpInformers)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +84,21 @@ func (o *Options) NewVirtualWorkspaces(
 		return nil, err
 	}
 
-	all, err := Merge(apiexports, initializingworkspaces)
+	sets := [][]rootapiserver.NamedVirtualWorkspace{apiexports, initializingworkspaces}
+	informers := Informers{
+		WildcardKubeInformers: wildcardKubeInformers,
+		WildcardKcpInformers:  wildcardKcpInformers,
+		CachedKcpInformers:    cachedKcpInformers,
+	}
+	for _, name := range sortedProviderNames(o.Providers) {
+		vws, err := o.Providers[name].NewVirtualWorkspaces(rootPathPrefix, config, informers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create virtual workspaces of provider %q: %w", name, err)
+		}
+		sets = append(sets, vws)
+	}
+
+	all, err := Merge(sets...)
 	if err != nil {
 		return nil, err
 	}
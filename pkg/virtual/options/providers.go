@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	"github.com/spf13/pflag"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/registry"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
+	kcpinformers "github.com/kcp-dev/kcp/sdk/client/informers/externalversions"
+)
+
+// Informers are the shared informer factories available to virtual workspace providers.
+type Informers struct {
+	WildcardKubeInformers kcpkubernetesinformers.SharedInformerFactory
+	WildcardKcpInformers  kcpinformers.SharedInformerFactory
+	CachedKcpInformers    kcpinformers.SharedInformerFactory
+}
+
+// Provider provides additional virtual workspaces, e.g. of a downstream distribution of kcp.
+// It is wired into the kcp server and the standalone virtual workspaces server like the
+// built-in virtual workspaces.
+type Provider interface {
+	// AddFlags adds the flags of the provider. All flags must start with the given prefix.
+	AddFlags(fs *pflag.FlagSet, prefix string)
+	// Validate validates the flags of the provider.
+	Validate(flagPrefix string) []error
+	// NewVirtualWorkspaces returns the virtual workspaces of the provider. Their names must
+	// be unique among all virtual workspaces.
+	NewVirtualWorkspaces(rootPathPrefix string, config *rest.Config, informers Informers) ([]rootapiserver.NamedVirtualWorkspace, error)
+}
+
+// ProviderFactory creates a Provider.
+type ProviderFactory func() Provider
+
+var providerFactories registry.Registry[Provider]
+
+// RegisterProvider registers an additional virtual workspace provider under the given name.
+// Every Options created afterwards gets its own instance. Registering the same name again
+// replaces the previous factory.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerFactories.Register(name, factory)
+}
+
+// UnregisterProvider removes the virtual workspace provider of the given name.
+func UnregisterProvider(name string) {
+	providerFactories.Unregister(name)
+}
+
+// newRegisteredProviders returns new instances of all registered providers.
+func newRegisteredProviders() map[string]Provider {
+	return providerFactories.New()
+}
+
+// sortedProviderNames returns the names of the providers in a stable order.
+func sortedProviderNames(providers map[string]Provider) []string {
+	return registry.SortedNames(providers)
+}
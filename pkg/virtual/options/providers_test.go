@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
+)
+
+type testProvider struct {
+	name    string
+	enabled bool
+}
+
+func (p *testProvider) AddFlags(fs *pflag.FlagSet, prefix string) {
+	fs.BoolVar(&p.enabled, prefix+p.name+"-enabled", p.enabled, "Enable the test virtual workspace.")
+}
+
+func (p *testProvider) Validate(flagPrefix string) []error {
+	if !p.enabled {
+		return []error{errors.New("--" + flagPrefix + p.name + "-enabled must be set")}
+	}
+	return nil
+}
+
+func (p *testProvider) NewVirtualWorkspaces(rootPathPrefix string, config *rest.Config, informers Informers) ([]rootapiserver.NamedVirtualWorkspace, error) {
+	return []rootapiserver.NamedVirtualWorkspace{{Name: p.name}}, nil
+}
+
+func TestMerge(t *testing.T) {
+	_, err := Merge([]rootapiserver.NamedVirtualWorkspace{{Name: "a"}}, []rootapiserver.NamedVirtualWorkspace{{Name: "b"}, {Name: "a"}})
+	require.EqualError(t, err, `duplicate virtual workspace "a"`)
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider("test", func() Provider { return &testProvider{name: "other"} })
+	RegisterProvider("test", func() Provider { return &testProvider{name: "test"} })
+	t.Cleanup(func() { UnregisterProvider("test") })
+
+	o := NewOptions()
+	require.Contains(t, o.Providers, "test")
+	require.NotSame(t, o.Providers["test"], NewOptions().Providers["test"], "every Options must get its own provider instance")
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.AddFlags(fs)
+	require.Len(t, o.Validate(), 1)
+	require.NoError(t, fs.Parse([]string{"--virtual-workspaces-test-enabled"}))
+	require.Empty(t, o.Validate())
+}
@@ -77,5 +77,27 @@ func CRDToAPIResourceSchema(crd *apiextensionsv1.CustomResourceDefinition, prefi
 		apiResourceSchema.Spec.Versions = append(apiResourceSchema.Spec.Versions, apiResourceVersion)
 	}
 
+	// Only webhook conversions are carried over. The "None" strategy is the default of CRDs, and
+	// would otherwise take precedence over an APIConversion of the APIResourceSchema.
+	if conversion := crd.Spec.Conversion; conversion != nil && conversion.Strategy == apiextensionsv1.WebhookConverter {
+		if conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+			return nil, field.Required(field.NewPath("spec", "conversion", "webhook", "clientConfig"), "required for the Webhook conversion strategy")
+		}
+		clientConfig := conversion.Webhook.ClientConfig
+		if clientConfig.URL == nil {
+			return nil, field.Invalid(field.NewPath("spec", "conversion", "webhook", "clientConfig", "service"), clientConfig.Service, "services are not supported, a url is required")
+		}
+		apiResourceSchema.Spec.Conversion = &CustomResourceConversion{
+			Strategy: WebhookConverter,
+			Webhook: &WebhookConversion{
+				ClientConfig: WebhookClientConfig{
+					URL:      *clientConfig.URL,
+					CABundle: clientConfig.CABundle,
+				},
+				ConversionReviewVersions: conversion.Webhook.ConversionReviewVersions,
+			},
+		}
+	}
+
 	return apiResourceSchema, nil
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCRDToAPIResourceSchemaConversion(t *testing.T) {
+	url := "https://example.com/convert"
+	newCRD := func(conversion *apiextensionsv1.CustomResourceConversion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+				Scope: apiextensionsv1.ClusterScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true, Storage: true},
+				},
+				Conversion: conversion,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conversion *apiextensionsv1.CustomResourceConversion
+		want       *CustomResourceConversion
+		wantErr    string
+	}{
+		{name: "no conversion"},
+		{name: "none is not carried over", conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}},
+		{
+			name: "webhook",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: &url, CABundle: []byte("ca")},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			want: &CustomResourceConversion{
+				Strategy: WebhookConverter,
+				Webhook: &WebhookConversion{
+					ClientConfig:             WebhookClientConfig{URL: url, CABundle: []byte("ca")},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+		},
+		{
+			name: "webhook service",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{Service: &apiextensionsv1.ServiceReference{Namespace: "default", Name: "converter"}},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			wantErr: "services are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := CRDToAPIResourceSchema(newCRD(tt.conversion), "today")
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, schema.Spec.Conversion)
+		})
+	}
+}
+
+func TestCRDToAPIResourceSchemaValidationRules(t *testing.T) {
+	rules := apiextensionsv1.ValidationRules{{Rule: "self.replicas <= 10", Message: "too many replicas"}}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: apiextensionsv1.ClusterScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"spec": {
+							Type:         "object",
+							Properties:   map[string]apiextensionsv1.JSONSchemaProps{"replicas": {Type: "integer"}},
+							XValidations: rules,
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	schema, err := CRDToAPIResourceSchema(crd, "today")
+	require.NoError(t, err)
+	got, err := schema.Spec.Versions[0].GetSchema()
+	require.NoError(t, err)
+	require.Equal(t, rules, got.Properties["spec"].XValidations)
+}
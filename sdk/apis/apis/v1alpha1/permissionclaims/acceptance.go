@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// PolicyAccepts returns whether any rule of the acceptance policy matches the permission claim.
+func PolicyAccepts(policy *apisv1alpha1.PermissionClaimAcceptancePolicy, claim apisv1alpha1.PermissionClaim) bool {
+	if policy == nil {
+		return false
+	}
+	for _, rule := range policy.Rules {
+		if RuleMatches(rule, claim) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleMatches returns whether all fields of the acceptance rule that are set match the permission claim.
+func RuleMatches(rule apisv1alpha1.PermissionClaimAcceptanceRule, claim apisv1alpha1.PermissionClaim) bool {
+	if rule.Group != "*" && rule.Group != claim.Group {
+		return false
+	}
+	if rule.Resource != "*" && rule.Resource != claim.Resource {
+		return false
+	}
+	if rule.IdentityHash != "" && rule.IdentityHash != claim.IdentityHash {
+		return false
+	}
+	if claim.All {
+		return rule.All && len(rule.Namespaces) == 0
+	}
+	if len(rule.Namespaces) == 0 {
+		return true
+	}
+	if len(claim.ResourceSelector) == 0 {
+		return false
+	}
+	for _, selector := range claim.ResourceSelector {
+		if !namespaceAllowed(rule.Namespaces, selector.Namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// namespaceAllowed returns whether every namespace the selector namespace pattern can match is
+// matched by one of the allowed patterns. An empty selector namespace matches all namespaces.
+func namespaceAllowed(allowed []string, pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for _, a := range allowed {
+		// a "*" in the selector pattern can only be matched by a "*" in the allowed pattern,
+		// which then matches everything the selector "*" expands to.
+		if NamespaceMatches(a, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestPolicyAccepts(t *testing.T) {
+	selected := func(namespaces ...string) apisv1alpha1.PermissionClaim {
+		claim := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}}
+		for _, ns := range namespaces {
+			claim.ResourceSelector = append(claim.ResourceSelector, apisv1alpha1.ResourceSelector{Namespace: ns})
+		}
+		return claim
+	}
+	all := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	widgets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, IdentityHash: "abc", All: true}
+
+	tests := map[string]struct {
+		rules []apisv1alpha1.PermissionClaimAcceptanceRule
+		claim apisv1alpha1.PermissionClaim
+		want  bool
+	}{
+		"no rules": {
+			claim: selected("default"),
+		},
+		"resource": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps"}},
+			claim: selected("default"),
+			want:  true,
+		},
+		"other resource": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "secrets"}},
+			claim: selected("default"),
+		},
+		"core group does not match other groups": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "*", All: true}},
+			claim: widgets,
+		},
+		"any group and resource": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Group: "*", Resource: "*", All: true}},
+			claim: widgets,
+			want:  true,
+		},
+		"identity": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Group: "example.io", Resource: "widgets", IdentityHash: "abc", All: true}},
+			claim: widgets,
+			want:  true,
+		},
+		"other identity": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Group: "example.io", Resource: "widgets", IdentityHash: "def", All: true}},
+			claim: widgets,
+		},
+		"all objects not allowed": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps"}},
+			claim: all,
+		},
+		"all objects allowed": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", All: true}},
+			claim: all,
+			want:  true,
+		},
+		"all objects with namespaces": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", All: true, Namespaces: []string{"*"}}},
+			claim: all,
+		},
+		"namespaces": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", Namespaces: []string{"default", "team-*"}}},
+			claim: selected("default", "team-a", "team-b-*"),
+			want:  true,
+		},
+		"some namespace not allowed": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", Namespaces: []string{"default", "team-*"}}},
+			claim: selected("default", "kube-system"),
+		},
+		"namespace pattern wider than allowed": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", Namespaces: []string{"team-a-*"}}},
+			claim: selected("team-*"),
+		},
+		"selector without namespace": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "configmaps", Namespaces: []string{"default"}}},
+			claim: selected(""),
+		},
+		"any rule": {
+			rules: []apisv1alpha1.PermissionClaimAcceptanceRule{{Resource: "secrets"}, {Resource: "configmaps", All: true}},
+			claim: all,
+			want:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var policy *apisv1alpha1.PermissionClaimAcceptancePolicy
+			if tt.rules != nil {
+				policy = &apisv1alpha1.PermissionClaimAcceptancePolicy{Rules: tt.rules}
+			}
+			require.Equal(t, tt.want, PolicyAccepts(policy, tt.claim))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// AcceptedClaim returns the claim the APIBinding has accepted for the given claim of its APIExport,
+// i.e. the accepted claim of the same group, resource and identity. It can differ from the claim of
+// the APIExport, e.g. because the APIExport has changed it after it was accepted.
+func AcceptedClaim(apiBinding *apisv1alpha1.APIBinding, claim apisv1alpha1.PermissionClaim) (apisv1alpha1.PermissionClaim, bool) {
+	for _, acceptable := range apiBinding.Spec.PermissionClaims {
+		if acceptable.State == apisv1alpha1.ClaimAccepted && acceptable.PermissionClaim.Equal(claim) {
+			return acceptable.PermissionClaim, true
+		}
+	}
+	return apisv1alpha1.PermissionClaim{}, false
+}
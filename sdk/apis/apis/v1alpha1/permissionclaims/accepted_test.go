@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestAcceptedClaim(t *testing.T) {
+	exported := apisv1alpha1.PermissionClaim{
+		GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "*"}},
+	}
+	accepted := apisv1alpha1.PermissionClaim{
+		GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "team-a"}},
+	}
+	secrets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+
+	tests := map[string]struct {
+		claims    []apisv1alpha1.AcceptablePermissionClaim
+		want      apisv1alpha1.PermissionClaim
+		wantFound bool
+	}{
+		"not decided": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted}},
+		},
+		"rejected": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: exported, State: apisv1alpha1.ClaimRejected}},
+		},
+		"accepted as exported": {
+			claims:    []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: exported, State: apisv1alpha1.ClaimAccepted}},
+			want:      exported,
+			wantFound: true,
+		},
+		"accepted differently": {
+			claims:    []apisv1alpha1.AcceptablePermissionClaim{{PermissionClaim: secrets, State: apisv1alpha1.ClaimAccepted}, {PermissionClaim: accepted, State: apisv1alpha1.ClaimAccepted}},
+			want:      accepted,
+			wantFound: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			binding := &apisv1alpha1.APIBinding{Spec: apisv1alpha1.APIBindingSpec{PermissionClaims: tc.claims}}
+			got, found := AcceptedClaim(binding, exported)
+			require.Equal(t, tc.wantFound, found)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -29,6 +29,8 @@ import (
 // ToLabelKeyAndValue creates a safe key and value for labeling a resource to grant access
 // based on the permissionClaim.
 func ToLabelKeyAndValue(exportClusterName logicalcluster.Name, exportName string, permissionClaim apisv1alpha1.PermissionClaim) (string, string, error) {
+	// subresources do not change which objects are claimed.
+	permissionClaim.Subresources = nil
 	bytes, err := json.Marshal(permissionClaim)
 	if err != nil {
 		return "", "", err
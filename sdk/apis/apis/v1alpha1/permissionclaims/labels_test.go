@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestToLabelKeyAndValueIgnoresSubresources(t *testing.T) {
+	claim := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	withSubresources := claim
+	withSubresources.Subresources = []apisv1alpha1.PermissionClaimSubresource{apisv1alpha1.PermissionClaimStatusSubresource}
+
+	key, value, err := ToLabelKeyAndValue(logicalcluster.Name("root:provider"), "example", claim)
+	require.NoError(t, err)
+	subresourcesKey, subresourcesValue, err := ToLabelKeyAndValue(logicalcluster.Name("root:provider"), "example", withSubresources)
+	require.NoError(t, err)
+	require.Equal(t, key, subresourcesKey)
+	require.Equal(t, value, subresourcesValue)
+
+	require.Len(t, withSubresources.Subresources, 1, "the claim of the caller must not be mutated")
+}
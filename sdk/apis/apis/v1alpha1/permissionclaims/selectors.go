@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+// Selects returns whether the permission claim covers the given object, i.e. whether the claim
+// claims all objects or one of its resource selectors matches the object.
+func Selects(claim apisv1alpha1.PermissionClaim, obj metav1.Object) (bool, error) {
+	if claim.All {
+		return true, nil
+	}
+	for _, selector := range claim.ResourceSelector {
+		if ok, err := SelectorMatches(selector, obj); err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SelectorMatches returns whether all fields of the resource selector that are set match the object.
+func SelectorMatches(selector apisv1alpha1.ResourceSelector, obj metav1.Object) (bool, error) {
+	if selector.Namespace != "" && !NamespaceMatches(selector.Namespace, obj.GetNamespace()) {
+		return false, nil
+	}
+	if selector.Name != "" && selector.Name != obj.GetName() {
+		return false, nil
+	}
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return true, nil
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+	if err != nil {
+		return false, err
+	}
+	return labelSelector.Matches(labels.Set(obj.GetLabels())), nil
+}
+
+// MaySelect returns whether the permission claim can cover objects with the given namespace and
+// name, without looking at their labels. An empty namespace or name stands for any, e.g. for
+// requests spanning multiple objects.
+func MaySelect(claim apisv1alpha1.PermissionClaim, namespace, name string) bool {
+	if claim.All {
+		return true
+	}
+	for _, selector := range claim.ResourceSelector {
+		if SelectorMayMatch(selector, namespace, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectorMayMatch returns whether the name and namespace of the resource selector match the given
+// namespace and name. An empty namespace or name stands for any.
+func SelectorMayMatch(selector apisv1alpha1.ResourceSelector, namespace, name string) bool {
+	if selector.Namespace != "" && namespace != "" && !NamespaceMatches(selector.Namespace, namespace) {
+		return false
+	}
+	if selector.Name != "" && name != "" && selector.Name != name {
+		return false
+	}
+	return true
+}
+
+// NamespaceMatches returns whether the namespace matches the namespace pattern of a resource
+// selector. A "*" in the pattern matches any sequence of characters, including the empty one.
+func NamespaceMatches(pattern, namespace string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == namespace
+	}
+
+	// the first part is a prefix, the last a suffix, and the others must appear in order in between.
+	prefix, suffix := parts[0], parts[len(parts)-1]
+	if len(namespace) < len(prefix)+len(suffix) || !strings.HasPrefix(namespace, prefix) || !strings.HasSuffix(namespace, suffix) {
+		return false
+	}
+	rest := namespace[len(prefix) : len(namespace)-len(suffix)]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return true
+}
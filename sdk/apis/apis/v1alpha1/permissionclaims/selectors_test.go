@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaims
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
+func TestSelects(t *testing.T) {
+	managed := metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "my-operator"}}
+	obj := &metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "setup",
+		Labels:    map[string]string{"app.kubernetes.io/managed-by": "my-operator"},
+	}
+
+	tests := map[string]struct {
+		selectors []apisv1alpha1.ResourceSelector
+		all       bool
+		want      bool
+		wantErr   bool
+	}{
+		"all": {
+			all:  true,
+			want: true,
+		},
+		"no selectors": {},
+		"name": {
+			selectors: []apisv1alpha1.ResourceSelector{{Name: "setup"}},
+			want:      true,
+		},
+		"other name": {
+			selectors: []apisv1alpha1.ResourceSelector{{Name: "other"}},
+		},
+		"namespace and labels": {
+			selectors: []apisv1alpha1.ResourceSelector{{Namespace: "default", LabelSelector: managed}},
+			want:      true,
+		},
+		"namespace pattern and labels": {
+			selectors: []apisv1alpha1.ResourceSelector{{Namespace: "def*", LabelSelector: managed}},
+			want:      true,
+		},
+		"labels in other namespace": {
+			selectors: []apisv1alpha1.ResourceSelector{{Namespace: "kube-system", LabelSelector: managed}},
+		},
+		"other labels": {
+			selectors: []apisv1alpha1.ResourceSelector{{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "helm"}}}},
+		},
+		"expressions": {
+			selectors: []apisv1alpha1.ResourceSelector{{LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app.kubernetes.io/managed-by", Operator: metav1.LabelSelectorOpExists},
+			}}}},
+			want: true,
+		},
+		"any selector": {
+			selectors: []apisv1alpha1.ResourceSelector{{Name: "other"}, {LabelSelector: managed}},
+			want:      true,
+		},
+		"invalid expression": {
+			selectors: []apisv1alpha1.ResourceSelector{{LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app.kubernetes.io/managed-by", Operator: "Like"},
+			}}}},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			claim := apisv1alpha1.PermissionClaim{
+				GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+				All:              tt.all,
+				ResourceSelector: tt.selectors,
+			}
+			got, err := Selects(claim, obj)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMaySelect(t *testing.T) {
+	claim := apisv1alpha1.PermissionClaim{
+		GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"},
+		ResourceSelector: []apisv1alpha1.ResourceSelector{
+			{Namespace: "default", Name: "setup"},
+			{Namespace: "operator"},
+			{Namespace: "team-a-*", Name: "config"},
+		},
+	}
+
+	require.True(t, MaySelect(claim, "default", "setup"))
+	require.True(t, MaySelect(claim, "default", ""), "a list in the namespace can contain the named object")
+	require.True(t, MaySelect(claim, "", ""), "a list across namespaces can contain selected objects")
+	require.True(t, MaySelect(claim, "operator", "anything"))
+	require.False(t, MaySelect(claim, "default", "other"))
+	require.False(t, MaySelect(claim, "kube-system", ""))
+	require.True(t, MaySelect(claim, "team-a-dev", "config"))
+	require.True(t, MaySelect(claim, "team-a-dev", ""))
+	require.False(t, MaySelect(claim, "team-a-dev", "other"))
+	require.False(t, MaySelect(claim, "team-b-dev", ""))
+}
+
+func TestNamespaceMatches(t *testing.T) {
+	tests := []struct {
+		pattern, namespace string
+		want               bool
+	}{
+		{"default", "default", true},
+		{"default", "default2", false},
+		{"*", "default", true},
+		{"team-a-*", "team-a-dev", true},
+		{"team-a-*", "team-a-", true},
+		{"team-a-*", "team-b-dev", false},
+		{"*-staging", "team-a-staging", true},
+		{"*-staging", "team-a-prod", false},
+		{"team-*-staging", "team-a-staging", true},
+		{"team-*-staging", "team-staging", false},
+		{"team-*-*-prod", "team-a-eu-prod", true},
+		{"team-*-*-prod", "team-a-prod", false},
+		{"a*a", "a", false},
+		{"a*a", "aa", true},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, NamespaceMatches(tt.pattern, tt.namespace), "pattern %q, namespace %q", tt.pattern, tt.namespace)
+	}
+}
@@ -26,6 +26,11 @@ const (
 	// InternalAPIBindingExportLabelKey is the label key on an APIBinding with the
 	// base62(sha224(<clusterName>:<exportName>)) as value to filter bindings by export.
 	InternalAPIBindingExportLabelKey = "internal.apis.kcp.io/export"
+
+	// InternalAPIBindingIdentityRotationAnnotationKey is the annotation key on an APIBinding recording the
+	// progress of moving its bound objects to the next identity of a rotated APIExport identity, as JSON
+	// object with the "from" and "to" identity hashes and etcd revisions. It is managed by kcp.
+	InternalAPIBindingIdentityRotationAnnotationKey = "internal.apis.kcp.io/identity-rotation"
 )
 
 // APIBinding enables a set of resources and their behaviour through an external
@@ -80,6 +85,97 @@ type APIBindingSpec struct {
 	//
 	// +optional
 	PermissionClaims []AcceptablePermissionClaim `json:"permissionClaims,omitempty"`
+
+	// acceptancePolicy accepts permission claims of the APIExport automatically, initially and
+	// whenever the API service provider adds claims to the APIExport. Accepted claims are added
+	// to permissionClaims. Claims that are already accepted or rejected in permissionClaims are
+	// left untouched, i.e. a claim matching the policy can still be rejected explicitly.
+	// Changing or removing the policy does not revoke claims it accepted before.
+	//
+	// +optional
+	AcceptancePolicy *PermissionClaimAcceptancePolicy `json:"acceptancePolicy,omitempty"`
+
+	// resourceOverrides serves resources of the APIExport under another resource name in this
+	// workspace. This allows to bind APIExports exporting the same group and resource side by side.
+	// Objects keep their API version and kind, i.e. clients resolving resources by kind cannot
+	// tell the resources apart.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	ResourceOverrides []ResourceOverride `json:"resourceOverrides,omitempty"`
+
+	// pinnedResourceSchemas pins resources of the APIExport to the given APIResourceSchemas, by
+	// name in the workspace of the APIExport. A pinned resource keeps being served with its pinned
+	// schema when the APIExport moves on to a newer schema of the resource. Removing the pin rolls
+	// out the latest schema of the APIExport. Only the latest schemas of the APIExport and the
+	// schemas the resources are bound with can be pinned. Other pins, and pins of resources the
+	// APIExport does not export, are ignored. The SchemaDrift condition reports the resources not
+	// served with the latest schema.
+	//
+	// +optional
+	// +listType=set
+	PinnedResourceSchemas []string `json:"pinnedResourceSchemas,omitempty"`
+}
+
+// ResourceOverride serves a resource of an APIExport under another resource name.
+type ResourceOverride struct {
+	// group and resource select the resource of the APIExport.
+	GroupResource `json:",inline"`
+
+	// alias is the resource name the resource is served as in the workspace, in the same group.
+	// Singular and short names of the resource are not served.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	Alias string `json:"alias"`
+}
+
+// PermissionClaimAcceptancePolicy selects permission claims that are accepted automatically.
+type PermissionClaimAcceptancePolicy struct {
+	// rules select the permission claims to accept. A claim is accepted if any rule matches.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Rules []PermissionClaimAcceptanceRule `json:"rules"`
+}
+
+// PermissionClaimAcceptanceRule matches permission claims. All fields that are set must match.
+type PermissionClaimAcceptanceRule struct {
+	// group is the API group of the claimed resource, the empty string for the core group,
+	// or "*" for all groups.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the claimed resource, or "*" for all resources.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// identityHash restricts the rule to claims of resources with this identity.
+	//
+	// +optional
+	IdentityHash string `json:"identityHash,omitempty"`
+
+	// all allows to accept claims of all objects of the resource. By default, only claims
+	// restricted by resource selectors are accepted.
+	//
+	// +optional
+	All bool `json:"all,omitempty"`
+
+	// namespaces restricts the rule to claims whose resource selectors all select objects in
+	// one of these namespaces. Entries may contain "*" wildcards matching any sequence of
+	// characters. Claims of all objects never match a rule with namespaces.
+	//
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 // AcceptablePermissionClaim is a PermissionClaim that records if the user accepts or rejects it.
@@ -92,6 +188,15 @@ type AcceptablePermissionClaim struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Enum=Accepted;Rejected
 	State AcceptablePermissionClaimState `json:"state"`
+
+	// reason is a CamelCase reason for rejecting the claim, e.g. TooBroad or NotNeeded. It is
+	// counted in the permission claim summary of the APIExport status if the provider publishes
+	// the APIBindings of the APIExport. It can only be set for rejected claims.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[A-Z][A-Za-z0-9]*$`
+	Reason string `json:"reason,omitempty"`
 }
 
 type AcceptablePermissionClaimState string
@@ -177,6 +282,30 @@ type APIBindingStatus struct {
 	// the binding to grant.
 	// +optional
 	ExportPermissionClaims []PermissionClaim `json:"exportPermissionClaims,omitempty"`
+
+	// permissionClaimsUsage records when the API service provider last exercised the applied
+	// permission claims through the APIExport virtual workspace. Usage is sampled, i.e. the
+	// recorded time can lag behind the actual last use by the sampling interval. Claims that
+	// have never been used since they were applied are not listed.
+	//
+	// +optional
+	PermissionClaimsUsage []PermissionClaimUsage `json:"permissionClaimsUsage,omitempty"`
+}
+
+// PermissionClaimUsage records when a permission claim was last used.
+type PermissionClaimUsage struct {
+	GroupResource `json:",inline"`
+
+	// identityHash is the identity hash of the claimed resource. It is empty for core types.
+	//
+	// +optional
+	IdentityHash string `json:"identityHash,omitempty"`
+
+	// lastUsedTime is the time the API service provider last accessed the claimed resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	LastUsedTime metav1.Time `json:"lastUsedTime"`
 }
 
 // These are valid conditions of APIBinding.
@@ -225,6 +354,48 @@ const (
 	// PermissionClaimsApplied is a condition for APIBinding that indicates that all the accepted permission claims
 	// have been applied.
 	PermissionClaimsApplied conditionsv1alpha1.ConditionType = "PermissionClaimsApplied"
+
+	// PermissionClaimsAutoAccepted is a condition for APIBinding that lists the permission claims of the APIExport
+	// accepted by the acceptance policy. It is only set if the APIBinding has an acceptance policy.
+	PermissionClaimsAutoAccepted conditionsv1alpha1.ConditionType = "PermissionClaimsAutoAccepted"
+	// NoPermissionClaimsMatchedReason is a reason for the PermissionClaimsAutoAccepted condition that no permission
+	// claim of the APIExport matches the acceptance policy.
+	NoPermissionClaimsMatchedReason = "NoPermissionClaimsMatched"
+
+	// ProviderHealthy is a condition for APIBinding that reflects the health reported by the provider of the
+	// APIExport. It is only set if the provider reports its health.
+	ProviderHealthy conditionsv1alpha1.ConditionType = "ProviderHealthy"
+
+	// ProviderUnhealthyReason is a reason for the ProviderHealthy condition that the provider reported itself unhealthy.
+	ProviderUnhealthyReason = "ProviderUnhealthy"
+	// ProviderHeartbeatExpiredReason is a reason for the ProviderHealthy condition that the provider did not report its
+	// health within the lease duration of its last heartbeat.
+	ProviderHeartbeatExpiredReason = "ProviderHeartbeatExpired"
+
+	// ExportChangesApplied is a condition for APIBinding that reflects whether changes of the resource schemas and
+	// permission claims of the APIExport have been rolled out. It is only set if the APIExport has maintenance windows.
+	ExportChangesApplied conditionsv1alpha1.ConditionType = "ExportChangesApplied"
+
+	// OutsideMaintenanceWindowReason is a reason for the ExportChangesApplied condition that changes of the APIExport
+	// are pending until its next maintenance window.
+	OutsideMaintenanceWindowReason = "OutsideMaintenanceWindow"
+
+	// SchemaDrift is a condition for APIBinding that is true if bound resources are served with other schemas than
+	// the latest resource schemas of the APIExport, because they are pinned or the schema rollout is manual.
+	SchemaDrift conditionsv1alpha1.ConditionType = "SchemaDrift"
+
+	// PinnedResourceSchemasReason is a reason for the SchemaDrift condition that bound resources are pinned to
+	// resource schemas other than the latest.
+	PinnedResourceSchemasReason = "PinnedResourceSchemas"
+	// ManualSchemaRolloutReason is a reason for the SchemaDrift condition that newer resource schemas of bound
+	// resources are not rolled out automatically. They are rolled out by pinning them.
+	ManualSchemaRolloutReason = "ManualSchemaRollout"
+	// NoSchemaDriftReason is a reason for the SchemaDrift condition that all bound resources are served with the
+	// latest resource schemas of the APIExport.
+	NoSchemaDriftReason = "NoSchemaDrift"
+	// PinnedResourceSchemaNotFoundReason is a reason for the BindingUpToDate condition that a pinned resource schema
+	// does not exist.
+	PinnedResourceSchemaNotFoundReason = "PinnedResourceSchemaNotFound"
 )
 
 // These are annotations for bound CRDs
@@ -241,6 +412,10 @@ const (
 	// for the request. This data is synthetic; it is not stored in etcd and instead is only applied when retrieving
 	// CRs for the CRD.
 	AnnotationAPIIdentityKey = "apis.kcp.io/identity"
+	// AnnotationBoundCRDIdentityKey is the annotation key for a bound CRD indicating the identity hash of the objects
+	// it serves. Unlike AnnotationAPIIdentityKey it is stored. When the identity of an APIExport is rotated, a second
+	// bound CRD is created for the same APIResourceSchema, serving the objects stored under the new identity.
+	AnnotationBoundCRDIdentityKey = "apis.kcp.io/bound-crd-identity"
 )
 
 // BoundAPIResource describes a bound GroupVersionResource through an APIResourceSchema of an APIExport..
@@ -256,6 +431,12 @@ type BoundAPIResource struct {
 	// +required
 	Resource string `json:"resource"`
 
+	// alias is the resource name the bound API is served as in the workspace if it is
+	// overridden in spec.resourceOverrides.
+	//
+	// +optional
+	Alias string `json:"alias,omitempty"`
+
 	// Schema references the APIResourceSchema that is bound to this API.
 	//
 	// +required
@@ -274,6 +455,14 @@ type BoundAPIResource struct {
 	StorageVersions []string `json:"storageVersions,omitempty"`
 }
 
+// ServedResource returns the resource name the bound API is served as in the workspace.
+func (r BoundAPIResource) ServedResource() string {
+	if r.Alias != "" {
+		return r.Alias
+	}
+	return r.Resource
+}
+
 // BoundAPIResourceSchema is a reference to an APIResourceSchema.
 type BoundAPIResourceSchema struct {
 	// name is the bound APIResourceSchema name.
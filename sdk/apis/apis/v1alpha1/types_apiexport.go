@@ -32,6 +32,20 @@ const (
 	IdentityVerificationFailedReason = "IdentityVerificationFailed"
 	IdentityGenerationFailedReason   = "IdentityGenerationFailed"
 
+	// APIExportIdentityEscrowed is set if the identity escrow is enabled on the shard, and signals
+	// whether an encrypted copy of the identity has been stored in the escrow.
+	APIExportIdentityEscrowed conditionsv1alpha1.ConditionType = "IdentityEscrowed"
+
+	IdentityEscrowFailedReason = "IdentityEscrowFailed"
+
+	// APIExportIdentityRotated is set while and after the identity of the APIExport is rotated to
+	// spec.identity.nextSecretRef. It is false while the objects of APIBindings are migrated to the
+	// storage of the next identity, and true when the rotation is completed.
+	APIExportIdentityRotated conditionsv1alpha1.ConditionType = "IdentityRotated"
+
+	IdentityRotationInProgressReason = "IdentityRotationInProgress"
+	IdentityRotationFailedReason     = "IdentityRotationFailed"
+
 	APIExportVirtualWorkspaceURLsReady conditionsv1alpha1.ConditionType = "VirtualWorkspaceURLsReady"
 
 	ErrorGeneratingURLsReason = "ErrorGeneratingURLs"
@@ -119,8 +133,9 @@ type APIExportSpec struct {
 	// The identity is a secret of the API provider. The APIBindings referencing this APIExport
 	// will store a derived, non-sensitive value of this identity.
 	//
-	// The identity of an APIExport cannot be changed. A derived, non-sensitive value of
-	// the identity key is stored in the APIExport status and this value is immutable.
+	// The identity of an APIExport cannot be changed, but it can be rotated through
+	// nextSecretRef. A derived, non-sensitive value of the identity key is stored in the
+	// APIExport status and this value is immutable outside of a rotation.
 	//
 	// The identity is defaulted. A secret with the name of the APIExport is automatically
 	// created.
@@ -164,8 +179,70 @@ type APIExportSpec struct {
 	// +listMapKey=group
 	// +listMapKey=resource
 	PermissionClaims []PermissionClaim `json:"permissionClaims,omitempty"`
+
+	// maintenanceWindows restricts when changes of latestResourceSchemas and permissionClaims
+	// are rolled out to APIBindings that are already bound. Outside of the windows, the changes
+	// are pending, which is reported by the ExportChangesApplied condition of the APIBindings.
+	// New APIBindings are bound to the current schemas and claims immediately.
+	//
+	// If unset, changes are rolled out immediately.
+	//
+	// +optional
+	// +listType=atomic
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// bindingVisibility controls what is published about the APIBindings bound to this APIExport
+	// in status.bindings. The workspace paths of the consumers are only published with Workspaces.
+	//
+	// If unset, nothing is published.
+	//
+	// +optional
+	BindingVisibility APIExportBindingVisibility `json:"bindingVisibility,omitempty"`
+}
+
+// APIExportBindingVisibility controls what is published about the APIBindings of an APIExport.
+//
+// +kubebuilder:validation:Enum=None;Count;Workspaces
+type APIExportBindingVisibility string
+
+const (
+	// APIExportBindingVisibilityNone publishes nothing about the APIBindings.
+	APIExportBindingVisibilityNone APIExportBindingVisibility = "None"
+	// APIExportBindingVisibilityCount publishes the number of APIBindings.
+	APIExportBindingVisibilityCount APIExportBindingVisibility = "Count"
+	// APIExportBindingVisibilityWorkspaces publishes the number of APIBindings and the paths of
+	// the workspaces they are in.
+	APIExportBindingVisibilityWorkspaces APIExportBindingVisibility = "Workspaces"
+)
+
+// MaintenanceWindow is a recurring window of time, in UTC.
+type MaintenanceWindow struct {
+	// days are the days of the week the window starts on. If unset, the window starts
+	// every day.
+	//
+	// +optional
+	// +listType=set
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+
+	// start is the time of day the window starts at, in the format HH:MM, in UTC.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// duration is the length of the window, e.g. 2h. It must be positive and at most a week.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
 }
 
+// MaintenanceWindowDay is a day of the week.
+//
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWindowDay string
+
 // Identity defines the identity of an APIExport, i.e. determines the etcd prefix
 // data of this APIExport are stored under.
 type Identity struct {
@@ -173,6 +250,17 @@ type Identity struct {
 	//
 	// +optional
 	SecretRef *corev1.SecretReference `json:"secretRef,omitempty"`
+
+	// nextSecretRef is a reference to a secret that contains the identity the APIExport is
+	// rotated to, e.g. because the current identity was compromised.
+	//
+	// While set, both identities are accepted, and the objects of the APIBindings are migrated to
+	// the storage of the next identity. When all are migrated, secretRef is replaced by
+	// nextSecretRef, and nextSecretRef is cleared. The progress is reported by the IdentityRotated
+	// condition.
+	//
+	// +optional
+	NextSecretRef *corev1.SecretReference `json:"nextSecretRef,omitempty"`
 }
 
 // MaximalPermissionPolicy is a wrapper type around the multiple options that would be allowed.
@@ -193,14 +281,47 @@ const (
 	APIExportPermissionClaimLabelPrefix = "claimed.internal.apis.kcp.io/"
 )
 
+const (
+	// ProviderQuotaLabelKey is the label key on objects created by a service provider through
+	// the APIExport virtual workspace in a consumer workspace. The value is the hash of the APIExport
+	// as computed by permissionclaims.ToAPIBindingExportLabelValue.
+	//
+	// A ResourceQuota in the consumer workspace with this label is a quota bucket of the provider:
+	// objects carrying the same label value are charged against it instead of against the quotas
+	// of the consumer.
+	ProviderQuotaLabelKey = "quota.apis.kcp.io/apiexport"
+
+	// ProviderQuotaUserExtraKey is the user info extra key set by the APIExport virtual workspace on
+	// the requests it forwards to consumer workspaces. The value is the same as the value of the
+	// ProviderQuotaLabelKey label. Only requests with this extra may set the label.
+	ProviderQuotaUserExtraKey = "quota.apis.kcp.io/apiexport"
+
+	// ConsumerAliasesAnnotationKey is the annotation key on an APIExport holding the aliases the
+	// service provider assigned to consumer logical clusters, as JSON map from logical cluster name
+	// to alias. It is managed through the consumeraliases endpoint of the APIExport virtual workspace.
+	ConsumerAliasesAnnotationKey = "apis.kcp.io/consumer-aliases"
+
+	// ConsumerAliasAnnotationKey is the annotation key the APIExport virtual workspace sets on objects
+	// in wildcard lists and watches to the alias of their logical cluster. It is not persisted.
+	ConsumerAliasAnnotationKey = "apis.kcp.io/consumer-alias"
+
+	// NamespaceMarkerConfigMapName is the name of the ConfigMap kcp creates in every namespace of a
+	// consumer workspace selected by an accepted permission claim on configmaps with a resource
+	// selector of this name. Service providers watch it through the APIExport virtual workspace to
+	// learn about new namespaces, e.g. to provision default objects in them.
+	NamespaceMarkerConfigMapName = "kcp-namespace-marker"
+)
+
 // PermissionClaim identifies an object by GR and identity hash.
 // Its purpose is to determine the added permissions that a service provider may
 // request and that a consumer may accept and allow the service provider access to.
 //
-// +kubebuilder:validation:XValidation:rule="(has(self.all) && self.all) != (has(self.resourceSelector) && size(self.resourceSelector) > 0)",message="either \"all\" or \"resourceSelector\" must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.referencedBy) || (has(self.all) && self.all) != (has(self.resourceSelector) && size(self.resourceSelector) > 0)",message="either \"all\" or \"resourceSelector\" must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.referencedBy) || (!(has(self.all) && self.all) && !(has(self.resourceSelector) && size(self.resourceSelector) > 0))",message="\"referencedBy\" is mutually exclusive with \"all\" and \"resourceSelector\""
+// +kubebuilder:validation:XValidation:rule="!(has(self.statusOnly) && self.statusOnly) || !has(self.referencedBy)",message="\"statusOnly\" is mutually exclusive with \"referencedBy\""
 // +kubebuilder:validation:XValidation:rule="!has(self.group) || self.group != \"core.kcp.io\" || self.resource != \"logicalclusters\" || (has(self.identityHash) && self.identityHash != \"\")",message="logicalclusters cannot be claimed"
 type PermissionClaim struct {
-	GroupResource `json:","`
+	GroupResource `json:",inline"`
 
 	// all claims all resources for the given group/resource.
 	// This is mutually exclusive with resourceSelector.
@@ -218,9 +339,68 @@ type PermissionClaim struct {
 	// Note that one must look this up for a particular KCP instance.
 	// +optional
 	IdentityHash string `json:"identityHash,omitempty"`
+
+	// subresources restricts the subresources of the claimed objects that can be accessed
+	// through the APIExport virtual workspace. If unset, all subresources served for the
+	// claimed resource can be accessed.
+	//
+	// +optional
+	// +listType=set
+	Subresources []PermissionClaimSubresource `json:"subresources,omitempty"`
+
+	// statusOnly restricts changes of the claimed objects through the APIExport virtual workspace
+	// to their status subresource. The claimed objects can be read, but not created, updated or
+	// deleted, e.g. for objects whose spec is owned by the consumer and whose status is owned by
+	// the service provider. This is mutually exclusive with referencedBy.
+	//
+	// +optional
+	StatusOnly bool `json:"statusOnly,omitempty"`
+
+	// referencedBy makes this a read-through claim. Instead of objects selected by all or
+	// resourceSelector, the provider can only get the objects referenced by a field of objects
+	// of a resource exported by the same APIExport, e.g. the Secret named in the spec of an
+	// exported object. Every read is checked against the referencing objects and audited.
+	// Referenced objects cannot be listed, watched or changed through the virtual workspace.
+	// This is mutually exclusive with all and resourceSelector.
+	//
+	// +optional
+	ReferencedBy *PermissionClaimReference `json:"referencedBy,omitempty"`
 }
 
-// +kubebuilder:validation:XValidation:rule="has(self.__namespace__) || has(self.name)",message="at least one field must be set"
+// PermissionClaimReference identifies the field of exported objects holding the names of the
+// objects of a read-through claim.
+type PermissionClaimReference struct {
+	// group and resource identify the referencing resource. It must be exported by the same
+	// APIExport.
+	GroupResource `json:",inline"`
+
+	// nameField is the path of the string field of the referencing objects holding the name
+	// of the referenced object, as dot-separated field names, e.g. "spec.secretRef.name".
+	// Referenced objects of a namespaced resource must be in the namespace of the referencing
+	// object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`
+	NameField string `json:"nameField"`
+}
+
+// PermissionClaimSubresource is a subresource of claimed objects.
+//
+// +kubebuilder:validation:Enum=status;scale
+type PermissionClaimSubresource string
+
+const (
+	// PermissionClaimStatusSubresource is the status subresource.
+	PermissionClaimStatusSubresource PermissionClaimSubresource = "status"
+	// PermissionClaimScaleSubresource is the scale subresource.
+	PermissionClaimScaleSubresource PermissionClaimSubresource = "scale"
+)
+
+// ResourceSelector selects objects of a claimed group/resource. All fields that are set must
+// match for an object to be selected.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.__namespace__) || has(self.name) || (has(self.matchLabels) && size(self.matchLabels) > 0) || (has(self.matchExpressions) && size(self.matchExpressions) > 0)",message="at least one field must be set"
 type ResourceSelector struct {
 	// name of an object within a claimed group/resource.
 	// It matches the metadata.name field of the underlying object.
@@ -233,12 +413,20 @@ type ResourceSelector struct {
 	Name string `json:"name,omitempty"`
 
 	// namespace containing the named object. Matches metadata.namespace field.
-	// If "name" is unset, all objects from the namespace are being claimed.
+	// It may contain "*" wildcards matching any sequence of characters, e.g. "team-a-*"
+	// for all namespaces with that prefix.
+	// If "name" is unset, all objects from the matching namespaces are being claimed.
 	//
 	// +optional
 	// +kubebuilder:validation:MinLength=1
 	Namespace string `json:"namespace,omitempty"`
 
+	// LabelSelector selects objects by their labels, e.g. all objects labeled
+	// app.kubernetes.io/managed-by=my-operator.
+	//
+	// +optional
+	metav1.LabelSelector `json:",inline"`
+
 	//
 	// WARNING: If adding new fields, add them to the XValidation check!
 	//
@@ -267,6 +455,7 @@ type GroupResource struct {
 	// For core groups this is the empty string '""'.
 	//
 	// +kubebuilder:validation:Pattern=`^(|[a-z0-9]([-a-z0-9]*[a-z0-9](\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*)?)$`
+	// +default=""
 	// +optional
 	Group string `json:"group,omitempty"`
 
@@ -282,11 +471,17 @@ type GroupResource struct {
 // APIExportStatus defines the observed state of APIExport.
 type APIExportStatus struct {
 	// identityHash is the hash of the API identity key of this APIExport. This value
-	// is immutable as soon as it is set.
+	// is immutable as soon as it is set, except when the identity is rotated.
 	//
 	// +optional
 	IdentityHash string `json:"identityHash,omitempty"`
 
+	// nextIdentityHash is the hash of the identity referenced by spec.identity.nextSecretRef
+	// while the identity is rotated. It replaces identityHash when the rotation is completed.
+	//
+	// +optional
+	NextIdentityHash string `json:"nextIdentityHash,omitempty"`
+
 	// conditions is a list of conditions that apply to the APIExport.
 	//
 	// +optional
@@ -298,6 +493,126 @@ type APIExportStatus struct {
 	//
 	// +optional
 	VirtualWorkspaces []VirtualWorkspace `json:"virtualWorkspaces,omitempty"`
+
+	// providerHealth is the health last reported by the provider through the health endpoint
+	// of the APIExport virtual workspace. It is propagated to the ProviderHealthy condition of
+	// the APIBindings of this APIExport.
+	//
+	// +optional
+	ProviderHealth *ProviderHealth `json:"providerHealth,omitempty"`
+
+	// bindings summarizes the APIBindings bound to this APIExport, per shard they are on, as
+	// configured by spec.bindingVisibility.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=shard
+	Bindings []APIExportBindings `json:"bindings,omitempty"`
+}
+
+// APIExportBindings summarizes the APIBindings of an APIExport on one shard.
+type APIExportBindings struct {
+	// shard is the name of the shard the APIBindings are on.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Shard string `json:"shard"`
+
+	// count is the number of APIBindings on the shard bound to this APIExport.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Count int32 `json:"count"`
+
+	// workspaces are the paths of the workspaces of the APIBindings on the shard, sorted, if
+	// spec.bindingVisibility is Workspaces. At most 100 workspaces are listed.
+	//
+	// +optional
+	// +listType=atomic
+	Workspaces []string `json:"workspaces,omitempty"`
+
+	// permissionClaims summarizes how the APIBindings on the shard decided about the permission
+	// claims of the APIExport, sorted by group, resource and identity hash.
+	//
+	// +optional
+	// +listType=atomic
+	PermissionClaims []PermissionClaimSummary `json:"permissionClaims,omitempty"`
+}
+
+// PermissionClaimSummary counts the APIBindings accepting and rejecting a permission claim.
+// APIBindings that neither accept nor reject the claim, e.g. because they have not decided yet
+// or removed their acceptance, are counted by neither.
+type PermissionClaimSummary struct {
+	GroupResource `json:",inline"`
+
+	// identityHash is the identity hash of the claimed resource. It is empty for core types.
+	//
+	// +optional
+	IdentityHash string `json:"identityHash,omitempty"`
+
+	// accepted is the number of APIBindings accepting the claim.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Accepted int32 `json:"accepted"`
+
+	// rejected is the number of APIBindings rejecting the claim.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Rejected int32 `json:"rejected"`
+
+	// rejectionReasons counts the reasons of the rejecting APIBindings, sorted by reason.
+	// Rejections without a reason are counted as Unspecified.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=reason
+	RejectionReasons []PermissionClaimRejectionReason `json:"rejectionReasons,omitempty"`
+}
+
+// PermissionClaimRejectionReason counts the APIBindings rejecting a claim for a reason.
+type PermissionClaimRejectionReason struct {
+	// reason is the reason given by the APIBindings.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Reason string `json:"reason"`
+
+	// count is the number of APIBindings rejecting the claim for the reason.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Count int32 `json:"count"`
+}
+
+// ProviderHealth is a heartbeat of the provider of an APIExport.
+type ProviderHealth struct {
+	// healthy is whether the provider considers the service backing the APIExport healthy.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Healthy bool `json:"healthy"`
+
+	// message is a human readable description of the health of the provider.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// lastHeartbeatTime is the time the provider last reported its health.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
+
+	// leaseDurationSeconds is the duration after the last heartbeat after which the provider
+	// is considered unhealthy if it does not report again.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds"`
 }
 
 type VirtualWorkspace struct {
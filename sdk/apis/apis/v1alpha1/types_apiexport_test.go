@@ -147,6 +147,34 @@ func TestAPIExportPermissionClaimCELValidation(t *testing.T) {
 				"all":          true,
 			},
 		},
+		{
+			name: "referencedBy is set",
+			current: map[string]interface{}{
+				"referencedBy": map[string]interface{}{"group": "example.kcp.io", "resource": "widgets", "nameField": "spec.secretRef.name"},
+			},
+		},
+		{
+			name: "referencedBy and all are set",
+			current: map[string]interface{}{
+				"all":          true,
+				"referencedBy": map[string]interface{}{"group": "example.kcp.io", "resource": "widgets", "nameField": "spec.secretRef.name"},
+			},
+			wantErrs: []string{
+				"openAPIV3Schema.properties.spec.properties.permissionClaims.items: Invalid value: \"object\": \"referencedBy\" is mutually exclusive with \"all\" and \"resourceSelector\"",
+			},
+		},
+		{
+			name: "referencedBy and resourceSelector are set",
+			current: map[string]interface{}{
+				"resourceSelector": []interface{}{
+					map[string]interface{}{"namespace": "foo"},
+				},
+				"referencedBy": map[string]interface{}{"group": "example.kcp.io", "resource": "widgets", "nameField": "spec.secretRef.name"},
+			},
+			wantErrs: []string{
+				"openAPIV3Schema.properties.spec.properties.permissionClaims.items: Invalid value: \"object\": \"referencedBy\" is mutually exclusive with \"all\" and \"resourceSelector\"",
+			},
+		},
 	}
 
 	validators := apitest.FieldValidatorsFromFile(t, "../../../../config/crds/apis.kcp.io_apiexports.yaml")
@@ -212,6 +240,30 @@ func TestResourceSelectorCELValidation(t *testing.T) {
 				"namespace": "bar",
 			},
 		},
+		{
+			name: "matchLabels is set",
+			current: map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app.kubernetes.io/managed-by": "my-operator"},
+			},
+		},
+		{
+			name: "matchExpressions is set",
+			current: map[string]interface{}{
+				"matchExpressions": []interface{}{
+					map[string]interface{}{"key": "app.kubernetes.io/managed-by", "operator": "Exists"},
+				},
+			},
+		},
+		{
+			name: "empty label selector",
+			current: map[string]interface{}{
+				"matchLabels":      map[string]interface{}{},
+				"matchExpressions": []interface{}{},
+			},
+			wantErrs: []string{
+				"openAPIV3Schema.properties.spec.properties.permissionClaims.items.properties.resourceSelector.items: Invalid value: \"object\": at least one field must be set",
+			},
+		},
 	}
 
 	validators := apitest.FieldValidatorsFromFile(t, "../../../../config/crds/apis.kcp.io_apiexports.yaml")
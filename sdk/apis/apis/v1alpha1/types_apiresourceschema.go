@@ -75,6 +75,86 @@ type APIResourceSchemaSpec struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MinItems=1
 	Versions []APIResourceVersion `json:"versions"`
+
+	// conversion defines how custom resources are converted between the versions. If it is not
+	// set and there are multiple versions, the versions are converted with the APIConversion of
+	// the same name as the APIResourceSchema.
+	//
+	// +optional
+	Conversion *CustomResourceConversion `json:"conversion,omitempty"`
+}
+
+// ConversionStrategyType describes different conversion types.
+type ConversionStrategyType string
+
+const (
+	// NoneConverter is a converter that only sets apiversion of the CR and leave everything else unchanged.
+	NoneConverter ConversionStrategyType = "None"
+	// WebhookConverter is a converter that calls to an external webhook to convert the CR.
+	WebhookConverter ConversionStrategyType = "Webhook"
+)
+
+// CustomResourceConversion describes how to convert different versions of a CR.
+type CustomResourceConversion struct {
+	// strategy specifies how custom resources are converted between versions. Allowed values are:
+	// - `"None"`: The converter only change the apiVersion and would not touch any other field in the custom resource.
+	// - `"Webhook"`: API Server will call to an external webhook to do the conversion. Additional information
+	//   is needed for this option. This requires spec.conversion.webhook to be set.
+	//
+	// +required
+	// +kubebuilder:validation:Enum=None;Webhook
+	Strategy ConversionStrategyType `json:"strategy"`
+
+	// webhook describes how to call the conversion webhook. Required when `strategy` is set to `"Webhook"`.
+	//
+	// +optional
+	Webhook *WebhookConversion `json:"webhook,omitempty"`
+}
+
+// WebhookConversion describes how to call a conversion webhook.
+type WebhookConversion struct {
+	// clientConfig is the instructions for how to call the webhook if strategy is `Webhook`.
+	//
+	// +required
+	ClientConfig WebhookClientConfig `json:"clientConfig"`
+
+	// conversionReviewVersions is an ordered list of preferred `ConversionReview`
+	// versions the Webhook expects. The API server will use the first version in
+	// the list which it supports. If none of the versions specified in this list
+	// are supported by API server, conversion will fail for the custom resource.
+	// If a persisted Webhook configuration specifies allowed versions and does not
+	// include any versions known to the API Server, calls to the webhook will fail.
+	//
+	// +required
+	// +listType=atomic
+	// +kubebuilder:validation:MinItems=1
+	ConversionReviewVersions []string `json:"conversionReviewVersions"`
+}
+
+// WebhookClientConfig contains the information to make a TLS connection with the webhook.
+type WebhookClientConfig struct {
+	// url gives the location of the webhook, in standard URL form
+	// (`scheme://host:port/path`).
+	//
+	// The scheme must be "https"; the URL must begin with "https://".
+	//
+	// A path is optional, and if present may be any string permissible in
+	// a URL. You may use the path to pass an arbitrary string to the
+	// webhook, for example, a cluster identifier.
+	//
+	// Attempting to use a user or basic auth e.g. "user:password@" is not
+	// allowed. Fragments ("#...") and query parameters ("?...") are not
+	// allowed, either.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// caBundle is a PEM encoded CA bundle which will be used to validate the webhook's server certificate.
+	// If unspecified, system trust roots on the apiserver are used.
+	//
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
 }
 
 // APIResourceVersion describes one API version of a resource.
@@ -169,6 +249,13 @@ const (
 	// version that would otherwise be lost during round-tripping to a different API version. An example key and value
 	// might look like this: preserve.conversion.apis.kcp.io/v2: {"spec.someNewField": "someValue"}.
 	VersionPreservationAnnotationKeyPrefix = "preserve.conversion.apis.kcp.io/"
+
+	// CrossWorkspaceReferencesAnnotationKey is the annotation key on an APIResourceSchema to opt into the validation
+	// of references to objects in other workspaces on admission. The value is a JSON list of references, each with the
+	// group and resource of the referenced objects and the dot separated paths of the fields holding the logical
+	// cluster path and the name, e.g.
+	// [{"group":"apis.kcp.io","resource":"apiexports","pathField":"spec.export.path","nameField":"spec.export.name"}].
+	CrossWorkspaceReferencesAnnotationKey = "apis.kcp.io/cross-workspace-references"
 )
 
 // +crd
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultAPIBindingLabelKey is set on the APIBindings created in child workspaces for a
+// DefaultAPIBinding of the parent workspace, holding the name of the DefaultAPIBinding.
+const DefaultAPIBindingLabelKey = "apis.kcp.io/default-apibinding"
+
+// DefaultAPIBindingAcceptedClaimsAnnotationKey is set on the APIBindings created for a
+// DefaultAPIBinding, holding the JSON list of the permission claims accepted for the
+// DefaultAPIBinding, as opposed to those accepted by the workspace owner.
+const DefaultAPIBindingAcceptedClaimsAnnotationKey = "apis.kcp.io/default-apibinding-accepted-claims"
+
+// DefaultAPIBinding declares an APIBinding to create in every child workspace of the workspace it
+// lives in, regardless of the type of the child workspace.
+//
+// The APIBinding in a child workspace has the name of the DefaultAPIBinding, and carries the
+// DefaultAPIBindingLabelKey label. It is deleted when the DefaultAPIBinding is deleted or references
+// another APIExport, unless the label has been removed. Changes to the accepted permission claims
+// are applied to existing APIBindings, leaving claims accepted by the workspace owner alone. The
+// creation and changes of the export of a DefaultAPIBinding require the bind permission on the
+// APIExport. APIBindings with the same name not created
+// for the DefaultAPIBinding are left alone.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Export",type="string",JSONPath=".spec.export.name"
+// +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".spec.export.path"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DefaultAPIBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	Spec DefaultAPIBindingSpec `json:"spec"`
+}
+
+// DefaultAPIBindingSpec records the APIExport to bind in child workspaces.
+type DefaultAPIBindingSpec struct {
+	// export is a reference to the APIExport to bind. If the path is unset, the logical
+	// cluster of the DefaultAPIBinding is used.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Export ExportBindingReference `json:"export"`
+
+	// acceptedPermissionClaims are the permission claims of the APIExport that are accepted
+	// in the child workspaces. A claim is only accepted if it equals a claim of the APIExport,
+	// including its scope. Other claims are left to the owners of the child workspaces.
+	//
+	// +optional
+	AcceptedPermissionClaims []PermissionClaim `json:"acceptedPermissionClaims,omitempty"`
+}
+
+// DefaultAPIBindingList is a list of DefaultAPIBinding resources.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DefaultAPIBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DefaultAPIBinding `json:"items"`
+}
@@ -59,13 +59,16 @@ const (
 
 // LogicalClusterPhaseType is the type of the current phase of the logical cluster.
 //
-// +kubebuilder:validation:Enum=Scheduling;Initializing;Ready
+// +kubebuilder:validation:Enum=Scheduling;Initializing;Ready;Trashed
 type LogicalClusterPhaseType string
 
 const (
 	LogicalClusterPhaseScheduling   LogicalClusterPhaseType = "Scheduling"
 	LogicalClusterPhaseInitializing LogicalClusterPhaseType = "Initializing"
 	LogicalClusterPhaseReady        LogicalClusterPhaseType = "Ready"
+	// LogicalClusterPhaseTrashed is only used by Workspaces that have been deleted while soft deletion
+	// is enabled. Their logical cluster is kept until the retention period has passed.
+	LogicalClusterPhaseTrashed LogicalClusterPhaseType = "Trashed"
 )
 
 // LogicalClusterInitializer is a unique string corresponding to a logical cluster
@@ -166,6 +169,42 @@ type LogicalClusterStatus struct {
 	//
 	// +optional
 	Initializers []LogicalClusterInitializer `json:"initializers,omitempty"`
+
+	// initializerProgress is the progress reported by the initializers of the logical
+	// cluster. Every initializer may only write its own entry, through the
+	// initializingworkspaces virtual workspace.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=initializer
+	InitializerProgress []InitializerProgress `json:"initializerProgress,omitempty"`
+}
+
+// InitializerProgress is the progress of an initializer of a logical cluster.
+type InitializerProgress struct {
+	// initializer is the initializer reporting the progress.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Initializer LogicalClusterInitializer `json:"initializer"`
+
+	// percent is the estimated completion of the initializer, from 0 to 100.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent,omitempty"`
+
+	// message is a human readable description of the current step of the initializer.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// lastTransitionTime is the time the percent or the message changed last.
+	//
+	// +optional
+	LastTransitionTime v1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 func (in *LogicalCluster) SetConditions(c conditionsv1alpha1.Conditions) {
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReplicationConfig opts additional resources into the replication of the shards to the cache server,
+// on top of the resources that are always replicated.
+//
+// Only ReplicationConfigs in the root workspace are honored. They are replicated to the cache server
+// themselves, such that all shards observe them.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ReplicationConfig struct {
+	v1.TypeMeta `json:",inline"`
+	// +optional
+	v1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec ReplicationConfigSpec `json:"spec,omitempty"`
+}
+
+// ReplicationConfigSpec holds the desired state of the ReplicationConfig.
+type ReplicationConfigSpec struct {
+	// resources are replicated to the cache server by all shards.
+	//
+	// The resources must be served by the shards and by the cache server. For resources that
+	// are always replicated, only the conflictPolicy is honored.
+	//
+	// +optional
+	Resources []ReplicatedResource `json:"resources,omitempty"`
+}
+
+// ReplicatedResource is a resource replicated to the cache server.
+type ReplicatedResource struct {
+	// group is the API group of the resource. It is empty for the core group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// version is the API version of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// resource is the lower-case plural name of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// labelSelector restricts the replicated objects to those matching it. All objects of the
+	// resource are replicated if it is not set. If multiple ReplicationConfigs list the same
+	// resource, objects matching any of their selectors are replicated.
+	//
+	// +optional
+	LabelSelector *v1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// conflictPolicy decides how replicated objects are reconciled whose copy in the cache
+	// server has been modified other than by the replication. LocalWins overwrites the cached
+	// copy, CacheWins updates the local object to match the cached copy, and Fail modifies
+	// neither and only records the conflict. Defaults to LocalWins. If multiple
+	// ReplicationConfigs list the same resource, Fail takes precedence over CacheWins, and
+	// CacheWins over LocalWins.
+	//
+	// +optional
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// endpoints are the names of the cache servers of other regions the objects are made
+	// available to, in addition to the cache server of the shard. They are the peer names of
+	// the federation of the cache servers, and the cache servers must federate with the cache
+	// server of the shard and be configured with their own name. Empty means that the objects
+	// are only replicated to the cache server of the shard.
+	//
+	// +optional
+	// +listType=set
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// ConflictPolicy decides how conflicting modifications of a replicated object are resolved.
+//
+// +kubebuilder:validation:Enum=LocalWins;CacheWins;Fail
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyLocalWins overwrites the cached copy with the local object.
+	ConflictPolicyLocalWins ConflictPolicy = "LocalWins"
+	// ConflictPolicyCacheWins updates the local object to match the cached copy.
+	ConflictPolicyCacheWins ConflictPolicy = "CacheWins"
+	// ConflictPolicyFail modifies neither the local object nor the cached copy, and only
+	// records the conflict.
+	ConflictPolicyFail ConflictPolicy = "Fail"
+)
+
+// ReplicationConfigList is a list of ReplicationConfig resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ReplicationConfigList struct {
+	v1.TypeMeta `json:",inline"`
+	v1.ListMeta `json:"metadata"`
+
+	Items []ReplicationConfig `json:"items"`
+}
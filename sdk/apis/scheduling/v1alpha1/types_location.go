@@ -109,7 +109,7 @@ type GroupVersionResource struct {
 	// resource is the name of the resource.
 	// +kubebuilder:validation:Pattern=`^[a-z][-a-z0-9]*[a-z0-9]$`
 	// +kubebuilder:validation:MinLength:1
-	// +kubebuilder:validation:Enum="synctargets"
+	// +kubebuilder:validation:Enum="synctargets";"synctargetpools"
 	// +required
 	// +kubebuilder:validation:Required
 	Resource string `json:"resource"`
@@ -92,3 +92,39 @@ func InitializerToLabel(initializer corev1alpha1.LogicalClusterInitializer) (str
 	labelKeyHashLength := validation.LabelValueMaxLength - len(tenancyv1alpha1.WorkspaceInitializerLabelPrefix)
 	return tenancyv1alpha1.WorkspaceInitializerLabelPrefix + hash[0:labelKeyHashLength], hash
 }
+
+// AggregateProgress computes the initialization progress of a workspace from the progress reported by the
+// initializers of its logical cluster. Initializers that are not pending anymore count as complete, pending
+// initializers without a progress entry as not started. Nil is returned if there are no initializers.
+func AggregateProgress(logicalCluster *corev1alpha1.LogicalCluster) *tenancyv1alpha1.WorkspaceInitializationProgress {
+	all := logicalCluster.Spec.Initializers
+	if len(all) == 0 {
+		return nil
+	}
+
+	progress := make(map[corev1alpha1.LogicalClusterInitializer]corev1alpha1.InitializerProgress, len(logicalCluster.Status.InitializerProgress))
+	for _, p := range logicalCluster.Status.InitializerProgress {
+		progress[p.Initializer] = p
+	}
+
+	ret := &tenancyv1alpha1.WorkspaceInitializationProgress{}
+	var sum int32
+	for _, initializer := range all {
+		if !InitializerPresent(initializer, logicalCluster.Status.Initializers) {
+			sum += 100
+			continue
+		}
+		p, found := progress[initializer]
+		if !found {
+			continue
+		}
+		sum += p.Percent
+		if ret.LastTransitionTime.Before(&p.LastTransitionTime) {
+			ret.LastTransitionTime = p.LastTransitionTime
+			ret.Message = p.Message
+		}
+	}
+	ret.Percent = sum / int32(len(all))
+
+	return ret
+}
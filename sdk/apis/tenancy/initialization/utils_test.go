@@ -19,10 +19,15 @@ package initialization
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 )
 
 func TestInitializerToLabel(t *testing.T) {
@@ -44,3 +49,49 @@ func TestInitializerToLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestAggregateProgress(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+
+	tests := map[string]struct {
+		spec     []corev1alpha1.LogicalClusterInitializer
+		pending  []corev1alpha1.LogicalClusterInitializer
+		progress []corev1alpha1.InitializerProgress
+		want     *tenancyv1alpha1.WorkspaceInitializationProgress
+	}{
+		"no initializers": {},
+		"nothing reported": {
+			spec:    []corev1alpha1.LogicalClusterInitializer{"root:a", "root:b"},
+			pending: []corev1alpha1.LogicalClusterInitializer{"root:a", "root:b"},
+			want:    &tenancyv1alpha1.WorkspaceInitializationProgress{},
+		},
+		"finished initializers count as complete": {
+			spec:    []corev1alpha1.LogicalClusterInitializer{"root:a", "root:b"},
+			pending: []corev1alpha1.LogicalClusterInitializer{"root:b"},
+			want:    &tenancyv1alpha1.WorkspaceInitializationProgress{Percent: 50},
+		},
+		"latest message wins": {
+			spec:    []corev1alpha1.LogicalClusterInitializer{"root:a", "root:b", "root:c"},
+			pending: []corev1alpha1.LogicalClusterInitializer{"root:b", "root:c"},
+			progress: []corev1alpha1.InitializerProgress{
+				{Initializer: "root:a", Percent: 90, Message: "ignored", LastTransitionTime: later},
+				{Initializer: "root:b", Percent: 50, Message: "copying", LastTransitionTime: earlier},
+				{Initializer: "root:c", Percent: 20, Message: "binding", LastTransitionTime: later},
+			},
+			want: &tenancyv1alpha1.WorkspaceInitializationProgress{Percent: 56, Message: "binding", LastTransitionTime: later},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			logicalCluster := &corev1alpha1.LogicalCluster{
+				Spec: corev1alpha1.LogicalClusterSpec{Initializers: tt.spec},
+				Status: corev1alpha1.LogicalClusterStatus{
+					Initializers:        tt.pending,
+					InitializerProgress: tt.progress,
+				},
+			}
+			require.Equal(t, tt.want, AggregateProgress(logicalCluster))
+		})
+	}
+}
@@ -47,6 +47,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Workspace{},
 		&WorkspaceList{},
+		&WorkspaceLifecycleWebhook{},
+		&WorkspaceRequest{},
+		&WorkspaceLifecycleWebhookList{},
+		&WorkspaceRequestList{},
+		&WorkspaceTemplate{},
+		&WorkspaceTemplateList{},
 		&WorkspaceType{},
 		&WorkspaceTypeList{},
 	)
@@ -95,6 +95,38 @@ const (
 // the type of the workspace on the corresponding LogicalCluster object. Its format is "root:ws:name".
 const LogicalClusterTypeAnnotationKey = "internal.tenancy.kcp.io/type"
 
+const (
+	// PropagatedMetadataAnnotationKey is the annotation key on LogicalClusters, and on the Workspaces
+	// they belong to, holding the labels and annotations that are propagated to child workspaces.
+	// The value is a JSON object with "labels" and "annotations" maps.
+	PropagatedMetadataAnnotationKey = "tenancy.kcp.io/propagated-metadata"
+
+	// InheritedMetadataAnnotationKey is the annotation key on Workspaces holding the labels and
+	// annotations propagated from the parent workspace, in the same format as
+	// PropagatedMetadataAnnotationKey.
+	InheritedMetadataAnnotationKey = "internal.tenancy.kcp.io/inherited-metadata"
+)
+
+const (
+	// WorkspaceSoftDeletionFinalizer is the finalizer on Workspaces holding them in the Trashed phase
+	// after deletion while soft deletion is enabled, until the retention period has passed or the
+	// Workspace is restored.
+	WorkspaceSoftDeletionFinalizer = "tenancy.kcp.io/soft-deletion"
+
+	// WorkspaceTrashedUntilAnnotationKey is the annotation key on Trashed Workspaces holding the RFC3339
+	// time until which they can be restored.
+	WorkspaceTrashedUntilAnnotationKey = "tenancy.kcp.io/trashed-until"
+
+	// WorkspaceRestoreAnnotationKey is the annotation key on Trashed Workspaces requesting to restore them.
+	// The Workspace is then recreated with the same name, bound to the same logical cluster.
+	WorkspaceRestoreAnnotationKey = "tenancy.kcp.io/restore"
+
+	// WorkspaceRestoringAnnotationKeyPrefix is the prefix of the annotation keys on LogicalClusters
+	// recording the restores in progress of their Trashed Workspaces, followed by the Workspace name.
+	// The value holds the Workspace to recreate, until it owns the logical cluster of the Trashed one.
+	WorkspaceRestoringAnnotationKeyPrefix = "restoring.internal.tenancy.kcp.io/"
+)
+
 // Workspace defines a generic Kubernetes-cluster-like endpoint, with standard Kubernetes
 // discovery APIs, OpenAPI and resource API endpoints.
 //
@@ -112,7 +144,7 @@ const LogicalClusterTypeAnnotationKey = "internal.tenancy.kcp.io/type"
 // +kubebuilder:resource:scope=Cluster,categories=kcp,shortName=ws
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type.name`,description="Type of the workspace"
 // +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.metadata.labels['region']`,description="The region this workspace is in"
-// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.metadata.labels['tenancy\.kcp\.io/phase']`,description="The current phase (e.g. Scheduling, Initializing, Ready, Deleting)"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.metadata.labels['tenancy\.kcp\.io/phase']`,description="The current phase (e.g. Scheduling, Initializing, Ready, Trashed, Deleting)"
 // +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.URL`,description="URL to access the workspace"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Workspace struct {
@@ -181,7 +213,7 @@ type WorkspaceLocation struct {
 
 // WorkspaceStatus communicates the observed state of the Workspace.
 type WorkspaceStatus struct {
-	// Phase of the workspace (Scheduling, Initializing, Ready).
+	// Phase of the workspace (Scheduling, Initializing, Ready, Trashed).
 	//
 	// +kubebuilder:default=Scheduling
 	Phase corev1alpha1.LogicalClusterPhaseType `json:"phase,omitempty"`
@@ -195,6 +227,32 @@ type WorkspaceStatus struct {
 	//
 	// +optional
 	Initializers []corev1alpha1.LogicalClusterInitializer `json:"initializers,omitempty"`
+
+	// initializationProgress aggregates the progress reported by the initializers of the
+	// workspace while it is initializing.
+	//
+	// +optional
+	InitializationProgress *WorkspaceInitializationProgress `json:"initializationProgress,omitempty"`
+}
+
+// WorkspaceInitializationProgress is the aggregated progress of the initializers of a workspace.
+type WorkspaceInitializationProgress struct {
+	// percent is the estimated completion of the initialization, from 0 to 100. Initializers
+	// that already finished count as complete, and those not reporting progress as not started.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// message is the most recent message reported by one of the remaining initializers.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// lastTransitionTime is the time the most recent progress was reported.
+	//
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 func (in *Workspace) SetConditions(c conditionsv1alpha1.Conditions) {
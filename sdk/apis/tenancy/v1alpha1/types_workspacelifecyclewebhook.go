@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+const (
+	// WorkspaceLifecycleWebhookEventsAnnotationKey is the annotation key on Workspaces holding the
+	// lifecycle events delivered to each WorkspaceLifecycleWebhook, as JSON map from webhook name
+	// to the list of delivered events.
+	WorkspaceLifecycleWebhookEventsAnnotationKey = "internal.tenancy.kcp.io/lifecycle-webhook-events"
+
+	// WorkspaceLifecycleWebhookFinalizer is the finalizer on Workspaces while a WorkspaceLifecycleWebhook
+	// of the parent workspace subscribes to Terminating events. After deletion, it is removed once
+	// the Terminating events have been delivered, or their delivery has failed for some minutes.
+	WorkspaceLifecycleWebhookFinalizer = "tenancy.kcp.io/lifecycle-webhooks"
+
+	// WorkspaceLifecycleWebhooksDelivered represents the status of the delivery of lifecycle events
+	// of a Workspace to the WorkspaceLifecycleWebhooks of its parent workspace.
+	WorkspaceLifecycleWebhooksDelivered conditionsv1alpha1.ConditionType = "LifecycleWebhooksDelivered"
+
+	// WorkspaceLifecycleWebhookDeliveryFailed is a reason for the LifecycleWebhooksDelivered condition
+	// of a Workspace when an event could not be delivered. It is retried with backoff.
+	WorkspaceLifecycleWebhookDeliveryFailed = "DeliveryFailed"
+)
+
+// WorkspaceLifecycleEventType is a lifecycle transition of a Workspace.
+//
+// +kubebuilder:validation:Enum=Created;Ready;Terminating
+type WorkspaceLifecycleEventType string
+
+const (
+	// WorkspaceLifecycleEventCreated is sent when a Workspace has been created.
+	WorkspaceLifecycleEventCreated WorkspaceLifecycleEventType = "Created"
+	// WorkspaceLifecycleEventReady is sent when a Workspace has become ready for the first time.
+	WorkspaceLifecycleEventReady WorkspaceLifecycleEventType = "Ready"
+	// WorkspaceLifecycleEventTerminating is sent when the deletion of a Workspace has started.
+	WorkspaceLifecycleEventTerminating WorkspaceLifecycleEventType = "Terminating"
+)
+
+// WorkspaceLifecycleWebhookFormat is the format of the requests sent to a WorkspaceLifecycleWebhook.
+//
+// +kubebuilder:validation:Enum=JSON;CloudEvents
+type WorkspaceLifecycleWebhookFormat string
+
+const (
+	// WorkspaceLifecycleWebhookFormatJSON sends a JSON object describing the event.
+	WorkspaceLifecycleWebhookFormatJSON WorkspaceLifecycleWebhookFormat = "JSON"
+	// WorkspaceLifecycleWebhookFormatCloudEvents sends a CloudEvent in structured content mode,
+	// with the JSON object describing the event as data.
+	WorkspaceLifecycleWebhookFormatCloudEvents WorkspaceLifecycleWebhookFormat = "CloudEvents"
+)
+
+// WorkspaceLifecycleWebhook registers an HTTPS endpoint that is called on lifecycle transitions of
+// the Workspaces in the workspace it lives in, e.g. to register them for billing or to set up
+// external DNS.
+//
+// Every event is sent once per webhook, in the order Created, Ready, Terminating. Created and Ready
+// events are sent for Workspaces created after the webhook, Terminating events for Workspaces deleted
+// after the webhook has been created. Failed requests are retried with backoff, and recorded in the
+// LifecycleWebhooksDelivered condition of the Workspace. The deletion of a Workspace waits for its
+// Terminating events, but at most some minutes when the webhook keeps failing.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,description="URL of the webhook"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceLifecycleWebhook struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	Spec WorkspaceLifecycleWebhookSpec `json:"spec"`
+}
+
+// WorkspaceLifecycleWebhookSpec describes the endpoint and the events of a WorkspaceLifecycleWebhook.
+type WorkspaceLifecycleWebhookSpec struct {
+	// url is the HTTPS URL the events are posted to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
+
+	// caBundle is a PEM encoded CA bundle used to verify the serving certificate of the webhook.
+	// If unset, the system trust roots are used.
+	//
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// events are the lifecycle transitions that are sent.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Events []WorkspaceLifecycleEventType `json:"events"`
+
+	// format is the format of the request body, either "JSON" for a JSON object describing
+	// the event, or "CloudEvents" for a CloudEvent in structured content mode.
+	//
+	// +optional
+	// +kubebuilder:default=JSON
+	Format WorkspaceLifecycleWebhookFormat `json:"format,omitempty"`
+}
+
+// WorkspaceLifecycleWebhookList is a list of WorkspaceLifecycleWebhook resources.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceLifecycleWebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceLifecycleWebhook `json:"items"`
+}
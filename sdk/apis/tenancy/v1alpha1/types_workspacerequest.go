@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// WorkspaceRequestAnnotationKey is the annotation key on Workspaces created for an approved
+// WorkspaceRequest, holding the name of the request.
+const WorkspaceRequestAnnotationKey = "tenancy.kcp.io/workspace-request"
+
+// WorkspaceRequest asks for a child workspace of the workspace it is created in. It allows users
+// without the permission to create workspaces to request one, to be approved or rejected by users
+// with the permission to update workspacerequests/status. An approved request is turned into a
+// Workspace owned by the requester.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Workspace",type=string,JSONPath=`.spec.workspaceName`,description="Name of the requested workspace"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type.name`,description="Type of the requested workspace"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The current phase (e.g. Pending, Rejected, Approved, Completed)"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WorkspaceRequestSpec `json:"spec"`
+
+	// +optional
+	Status WorkspaceRequestStatus `json:"status,omitempty"`
+}
+
+// WorkspaceRequestSpec holds the requested workspace. It is immutable.
+//
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type WorkspaceRequestSpec struct {
+	// workspaceName is the name of the requested workspace. The workspace is created as child
+	// of the workspace the request lives in.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$"
+	WorkspaceName string `json:"workspaceName"`
+
+	// type is the type of the requested workspace. If no type is provided, the default type
+	// for the workspace in which the request lives is used.
+	//
+	// +optional
+	Type *WorkspaceTypeReference `json:"type,omitempty"`
+
+	// justification explains to the approvers why the workspace is needed.
+	//
+	// +optional
+	Justification string `json:"justification,omitempty"`
+}
+
+// WorkspaceRequestDecision is the decision of an approver on a WorkspaceRequest.
+//
+// +kubebuilder:validation:Enum=Approved;Rejected
+type WorkspaceRequestDecision string
+
+const (
+	// WorkspaceRequestApproved means that the workspace is to be created.
+	WorkspaceRequestApproved WorkspaceRequestDecision = "Approved"
+	// WorkspaceRequestRejected means that the workspace is not created.
+	WorkspaceRequestRejected WorkspaceRequestDecision = "Rejected"
+)
+
+// WorkspaceRequestPhaseType is the phase of a WorkspaceRequest.
+//
+// +kubebuilder:validation:Enum=Pending;Rejected;Approved;Completed
+type WorkspaceRequestPhaseType string
+
+const (
+	// WorkspaceRequestPhasePending means that no decision has been made yet.
+	WorkspaceRequestPhasePending WorkspaceRequestPhaseType = "Pending"
+	// WorkspaceRequestPhaseRejected means that the request was rejected.
+	WorkspaceRequestPhaseRejected WorkspaceRequestPhaseType = "Rejected"
+	// WorkspaceRequestPhaseApproved means that the request was approved, but the workspace
+	// has not been created yet.
+	WorkspaceRequestPhaseApproved WorkspaceRequestPhaseType = "Approved"
+	// WorkspaceRequestPhaseCompleted means that the workspace has been created.
+	WorkspaceRequestPhaseCompleted WorkspaceRequestPhaseType = "Completed"
+)
+
+// These are valid conditions of WorkspaceRequest.
+const (
+	// WorkspaceRequestWorkspaceCreated represents the status of creating the workspace of an
+	// approved request.
+	WorkspaceRequestWorkspaceCreated conditionsv1alpha1.ConditionType = "WorkspaceCreated"
+	// WorkspaceRequestWorkspaceExistsReason reason in WorkspaceCreated condition means that a
+	// workspace with the requested name exists that was not created for the request.
+	WorkspaceRequestWorkspaceExistsReason = "WorkspaceExists"
+	// WorkspaceRequestCreateFailedReason reason in WorkspaceCreated condition means that the
+	// workspace could not be created, e.g. because it was rejected by admission.
+	WorkspaceRequestCreateFailedReason = "CreateFailed"
+)
+
+// WorkspaceRequestStatus communicates the decision on a WorkspaceRequest and its observed state.
+type WorkspaceRequestStatus struct {
+	// decision is set by an approver to approve or reject the request. It can only be set once,
+	// and not by the requester.
+	//
+	// +optional
+	Decision WorkspaceRequestDecision `json:"decision,omitempty"`
+
+	// message is an optional explanation of the decision by the approver.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// decidedBy is the name of the user that made the decision.
+	//
+	// Set by the system.
+	//
+	// +optional
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// decisionTime is the time the decision was made.
+	//
+	// Set by the system.
+	//
+	// +optional
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+
+	// phase of the request (Pending, Rejected, Approved, Completed).
+	//
+	// +optional
+	Phase WorkspaceRequestPhaseType `json:"phase,omitempty"`
+
+	// Current processing state of the WorkspaceRequest.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+func (in *WorkspaceRequest) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *WorkspaceRequest) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+// WorkspaceRequestList is a list of WorkspaceRequests
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceRequest `json:"items"`
+}
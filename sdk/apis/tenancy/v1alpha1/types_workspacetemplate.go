@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WorkspaceTemplateAnnotationKey is the annotation key on Workspaces created from a
+	// WorkspaceTemplate, holding the name of the template in the parent workspace. It is copied
+	// to the LogicalCluster of the workspace.
+	WorkspaceTemplateAnnotationKey = "tenancy.kcp.io/template"
+
+	// WorkspaceTemplateAppliedAnnotationKey is the annotation key on LogicalClusters holding the
+	// name of the WorkspaceTemplate whose APIBindings and RBAC have been created in the workspace.
+	WorkspaceTemplateAppliedAnnotationKey = "internal.tenancy.kcp.io/template-applied"
+
+	// WorkspaceTemplateGenerationAnnotationKey is the annotation key on Workspaces created from a
+	// WorkspaceTemplate, holding the generation of the template the creating user was authorized
+	// for. It is copied to the LogicalCluster of the workspace, and the template is only applied
+	// if it has not changed since.
+	WorkspaceTemplateGenerationAnnotationKey = "internal.tenancy.kcp.io/template-generation"
+
+	// WorkspaceTemplateLabelKey is set on the objects created in a workspace for a WorkspaceTemplate,
+	// holding the name of the template.
+	WorkspaceTemplateLabelKey = "tenancy.kcp.io/template"
+)
+
+// WorkspaceTemplate describes workspaces to create repeatedly with the same type, metadata,
+// APIBindings and RBAC. It is used for Workspaces in the workspace it lives in, created with the
+// WorkspaceTemplateAnnotationKey annotation, e.g. by "kubectl kcp workspace create --template".
+//
+// The type, labels and annotations are set on the Workspace by the client creating it. The
+// APIBindings, ClusterRoles and ClusterRoleBindings are created in the workspace once it is ready.
+// They are created once, i.e. later changes to the template do not affect existing workspaces, and
+// the objects can be changed or deleted by the owners of the workspace.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type.name`,description="Type of the workspaces"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	Spec WorkspaceTemplateSpec `json:"spec"`
+}
+
+// WorkspaceTemplateSpec describes the workspaces created from a template.
+type WorkspaceTemplateSpec struct {
+	// type is the type of the workspaces. If no type is provided, the default type for the
+	// workspace in which the template lives is used.
+	//
+	// +optional
+	Type *WorkspaceTypeReference `json:"type,omitempty"`
+
+	// labels are set on the workspaces.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// annotations are set on the workspaces.
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// apiBindings are APIs to bind in the workspaces, together with permission claims of their
+	// APIExports that are accepted on behalf of the workspace owner.
+	//
+	// +optional
+	APIBindings []APIBindingClaimBundle `json:"apiBindings,omitempty"`
+
+	// clusterRoles are created in the workspaces.
+	//
+	// +optional
+	ClusterRoles []WorkspaceTemplateClusterRole `json:"clusterRoles,omitempty"`
+
+	// clusterRoleBindings are created in the workspaces.
+	//
+	// +optional
+	ClusterRoleBindings []WorkspaceTemplateClusterRoleBinding `json:"clusterRoleBindings,omitempty"`
+}
+
+// WorkspaceTemplateClusterRole is a ClusterRole to create in the workspaces of a template.
+type WorkspaceTemplateClusterRole struct {
+	// name is the name of the ClusterRole.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// rules are the policy rules of the ClusterRole.
+	//
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// WorkspaceTemplateClusterRoleBinding is a ClusterRoleBinding to create in the workspaces of a template.
+type WorkspaceTemplateClusterRoleBinding struct {
+	// name is the name of the ClusterRoleBinding.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// clusterRole is the name of the bound ClusterRole in the workspace, e.g. one of
+	// clusterRoles or a built-in one like "admin".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClusterRole string `json:"clusterRole"`
+
+	// subjects are the users, groups and service accounts the ClusterRole is bound to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Subjects []rbacv1.Subject `json:"subjects"`
+}
+
+// WorkspaceTemplateList is a list of WorkspaceTemplate resources.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceTemplate `json:"items"`
+}
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
 )
@@ -116,6 +117,119 @@ type WorkspaceTypeSpec struct {
 	//
 	// +optional
 	DefaultAPIBindings []APIExportReference `json:"defaultAPIBindings,omitempty"`
+
+	// apiBindings are APIs to bind during initialization of workspaces created from this type,
+	// with the name of the APIBinding and the permission claims to accept. An APIExport listed in
+	// defaultAPIBindings or apiBindings of a type overrides the entries for the same APIExport of
+	// the types it extends. If an APIExport is listed in both of a type, the entry in apiBindings
+	// is used.
+	//
+	// +optional
+	APIBindings []WorkspaceTypeAPIBinding `json:"apiBindings,omitempty"`
+
+	// claimBundles are APIs to bind during initialization of workspaces created from this type,
+	// together with permission claims of their APIExports that are accepted on behalf of the
+	// workspace owner. Workspace owners do not have to accept these claims manually.
+	//
+	// +optional
+	ClaimBundles []APIBindingClaimBundle `json:"claimBundles,omitempty"`
+
+	// propagatedMetadata selects labels and annotations of workspaces of this type that
+	// are propagated to all descendant workspaces, on creation and whenever they change.
+	// Propagated values cannot be overridden in descendant workspaces.
+	//
+	// +optional
+	PropagatedMetadata *WorkspaceMetadataPropagation `json:"propagatedMetadata,omitempty"`
+
+	// apiRestrictions restricts the APIs that can be used in workspaces of this type. Objects
+	// of restricted resources cannot be created or updated, and APIExports exporting restricted
+	// resources cannot be bound. Resources of the core.kcp.io, tenancy.kcp.io and apis.kcp.io
+	// groups are never restricted. Extending another WorkspaceType does not inherit its
+	// apiRestrictions.
+	//
+	// +optional
+	APIRestrictions *APIRestrictions `json:"apiRestrictions,omitempty"`
+
+	// podSecurity enforces a Pod Security Standards level on pods and pod templates of bound
+	// compute APIs, e.g. deployments, in workspaces of this type, before they are synced to
+	// physical clusters. Extending another WorkspaceType does not inherit its podSecurity.
+	//
+	// +optional
+	PodSecurity *WorkspacePodSecurity `json:"podSecurity,omitempty"`
+}
+
+// APIRestrictions restricts the API group resources usable in a workspace.
+type APIRestrictions struct {
+	// allowed are the only group resources that can be used, if set.
+	//
+	// +optional
+	Allowed []GroupResourcePattern `json:"allowed,omitempty"`
+
+	// denied are group resources that cannot be used, even if allowed.
+	//
+	// +optional
+	Denied []GroupResourcePattern `json:"denied,omitempty"`
+}
+
+// PodSecurityLevel is a Pod Security Standards level.
+//
+// +kubebuilder:validation:Enum=privileged;baseline;restricted
+type PodSecurityLevel string
+
+const (
+	// PodSecurityLevelPrivileged is unrestricted.
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	// PodSecurityLevelBaseline prevents known privilege escalations.
+	PodSecurityLevelBaseline PodSecurityLevel = "baseline"
+	// PodSecurityLevelRestricted follows pod hardening best practices.
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+// WorkspacePodSecurity is the Pod Security Standards level enforced in a workspace, like the
+// pod-security.kubernetes.io/enforce labels of namespaces.
+type WorkspacePodSecurity struct {
+	// enforce is the level pods and pod templates must satisfy. Objects violating it are rejected.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Enforce PodSecurityLevel `json:"enforce"`
+
+	// enforceVersion is the version of the level, "latest" or a Kubernetes minor version like "v1.25".
+	//
+	// +optional
+	// +kubebuilder:default=latest
+	// +kubebuilder:validation:Pattern=`^(latest|v1\.[0-9]+)$`
+	EnforceVersion string `json:"enforceVersion,omitempty"`
+}
+
+// GroupResourcePattern matches API group resources.
+type GroupResourcePattern struct {
+	// group is the API group, the empty string for the core group, or "*" for all groups.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the resource, or "*" for all resources of the group.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+}
+
+// WorkspaceMetadataPropagation selects labels and annotations by key.
+type WorkspaceMetadataPropagation struct {
+	// labels are the keys of the propagated labels.
+	//
+	// +optional
+	// +listType=set
+	Labels []string `json:"labels,omitempty"`
+
+	// annotations are the keys of the propagated annotations.
+	//
+	// +optional
+	// +listType=set
+	Annotations []string `json:"annotations,omitempty"`
 }
 
 // APIExportReference provides the fields necessary to resolve an APIExport.
@@ -136,6 +250,42 @@ type APIExportReference struct {
 	Export string `json:"export"`
 }
 
+// WorkspaceTypeAPIBinding references an APIExport to bind during initialization of a workspace.
+type WorkspaceTypeAPIBinding struct {
+	APIExportReference `json:",inline"`
+
+	// name is the name of the APIBinding. The placeholders "{export}" and "{workspace}" are
+	// replaced with the name of the APIExport and the name of the initialized workspace. If
+	// unset, the name is generated from the name of the APIExport and a hash.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9{]([-a-z0-9.{}]*[a-z0-9}])?$`
+	Name string `json:"name,omitempty"`
+
+	// acceptedPermissionClaims are the permission claims of the APIExport that are accepted.
+	// If unset, all claims of the APIExport are accepted. Otherwise, a claim is accepted if
+	// it equals a claim of the APIExport, or narrows it down to a subset of its resource
+	// selectors. Other claims of the APIExport are left to the workspace owner.
+	//
+	// +optional
+	AcceptedPermissionClaims []apisv1alpha1.PermissionClaim `json:"acceptedPermissionClaims,omitempty"`
+}
+
+// APIBindingClaimBundle references an APIExport to bind, and the permission claims of the
+// APIExport that are accepted when binding it.
+type APIBindingClaimBundle struct {
+	APIExportReference `json:",inline"`
+
+	// acceptedPermissionClaims are the permission claims of the APIExport that are accepted.
+	// A claim is accepted if it equals a claim of the APIExport, or narrows it down to a
+	// subset of its resource selectors. Other claims of the APIExport are neither accepted nor rejected, and left to the
+	// workspace owner.
+	//
+	// +optional
+	AcceptedPermissionClaims []apisv1alpha1.PermissionClaim `json:"acceptedPermissionClaims,omitempty"`
+}
+
 // WorkspaceTypeSelector describes a set of types.
 type WorkspaceTypeSelector struct {
 	// none means that no type matches.
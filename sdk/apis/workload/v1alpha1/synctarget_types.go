@@ -111,7 +111,7 @@ type SyncTargetStatus struct {
 }
 
 type ResourceToSync struct {
-	apisv1alpha1.GroupResource `json:","`
+	apisv1alpha1.GroupResource `json:",inline"`
 
 	// versions are the resource versions the syncer can choose to sync depending on
 	// availability on the downstream cluster. Conversion to the storage version, if necessary,
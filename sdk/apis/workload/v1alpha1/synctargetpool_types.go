@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/apis/third_party/conditions/util/conditions"
+)
+
+// SyncTargetPool groups interchangeable SyncTargets of its workspace into one elastic target.
+//
+// Placements target pools through Locations of the synctargetpools resource. The scheduler
+// places a placement onto the ready member with the least placements, and moves it to another
+// member when the member becomes unready or starts evicting.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Ready Members",type="integer",JSONPath=`.status.readyMembers`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type SyncTargetPool struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	// +optional
+	Spec SyncTargetPoolSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state.
+	// +optional
+	Status SyncTargetPoolStatus `json:"status,omitempty"`
+}
+
+var _ conditions.Getter = &SyncTargetPool{}
+var _ conditions.Setter = &SyncTargetPool{}
+
+// SyncTargetPoolSpec holds the desired state of the SyncTargetPool.
+type SyncTargetPoolSpec struct {
+	// selector selects the member SyncTargets in the workspace of the pool by their labels.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// SyncTargetPoolStatus communicates the observed state of the SyncTargetPool.
+type SyncTargetPoolStatus struct {
+	// members are the SyncTargets selected by the pool, sorted by name.
+	//
+	// +optional
+	Members []SyncTargetPoolMember `json:"members,omitempty"`
+
+	// readyMembers is the number of members accepting placements.
+	//
+	// +optional
+	ReadyMembers int32 `json:"readyMembers,omitempty"`
+
+	// Current processing state of the SyncTargetPool.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// SyncTargetPoolMember is a SyncTarget selected by a pool.
+type SyncTargetPoolMember struct {
+	// name is the name of the SyncTarget.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// ready is true if the SyncTarget accepts placements, i.e. it is ready, schedulable
+	// and not evicting.
+	//
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+}
+
+// Conditions and ConditionReasons for the SyncTargetPool object.
+const (
+	// NoReadyMembersReason indicates that no member of a pool accepts placements.
+	NoReadyMembersReason = "NoReadyMembers"
+	// InvalidSelectorReason indicates that the selector of a pool cannot be parsed.
+	InvalidSelectorReason = "InvalidSelector"
+)
+
+func (in *SyncTargetPool) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+func (in *SyncTargetPool) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+// SyncTargetPoolList is a list of SyncTargetPool resources.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SyncTargetPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SyncTargetPool `json:"items"`
+}
@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apiresource/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func APIResourceImport(name string) *APIResourceImportApplyConfiguration {
 	return b
 }
 
+// ExtractAPIResourceImport extracts the applied configuration owned by fieldManager from
+// apiResourceImport. If no managedFields are found in apiResourceImport for fieldManager, a
+// APIResourceImportApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// apiResourceImport must be a unmodified APIResourceImport API object that was retrieved from the Kubernetes API.
+// ExtractAPIResourceImport provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractAPIResourceImport(apiResourceImport *apiresourcev1alpha1.APIResourceImport, fieldManager string) (*APIResourceImportApplyConfiguration, error) {
+	return extractAPIResourceImport(apiResourceImport, fieldManager, "")
+}
+
+// ExtractAPIResourceImportStatus is the same as ExtractAPIResourceImport except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractAPIResourceImportStatus(apiResourceImport *apiresourcev1alpha1.APIResourceImport, fieldManager string) (*APIResourceImportApplyConfiguration, error) {
+	return extractAPIResourceImport(apiResourceImport, fieldManager, "status")
+}
+
+func extractAPIResourceImport(apiResourceImport *apiresourcev1alpha1.APIResourceImport, fieldManager string, subresource string) (*APIResourceImportApplyConfiguration, error) {
+	b := &APIResourceImportApplyConfiguration{}
+	err := managedfields.ExtractInto(apiResourceImport, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImport"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(apiResourceImport.Name)
+
+	b.WithKind("APIResourceImport")
+	b.WithAPIVersion("apiresource.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apiresource/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func NegotiatedAPIResource(name string) *NegotiatedAPIResourceApplyConfiguration
 	return b
 }
 
+// ExtractNegotiatedAPIResource extracts the applied configuration owned by fieldManager from
+// negotiatedAPIResource. If no managedFields are found in negotiatedAPIResource for fieldManager, a
+// NegotiatedAPIResourceApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// negotiatedAPIResource must be a unmodified NegotiatedAPIResource API object that was retrieved from the Kubernetes API.
+// ExtractNegotiatedAPIResource provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractNegotiatedAPIResource(negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResource, fieldManager string) (*NegotiatedAPIResourceApplyConfiguration, error) {
+	return extractNegotiatedAPIResource(negotiatedAPIResource, fieldManager, "")
+}
+
+// ExtractNegotiatedAPIResourceStatus is the same as ExtractNegotiatedAPIResource except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractNegotiatedAPIResourceStatus(negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResource, fieldManager string) (*NegotiatedAPIResourceApplyConfiguration, error) {
+	return extractNegotiatedAPIResource(negotiatedAPIResource, fieldManager, "status")
+}
+
+func extractNegotiatedAPIResource(negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResource, fieldManager string, subresource string) (*NegotiatedAPIResourceApplyConfiguration, error) {
+	b := &NegotiatedAPIResourceApplyConfiguration{}
+	err := managedfields.ExtractInto(negotiatedAPIResource, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResource"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(negotiatedAPIResource.Name)
+
+	b.WithKind("NegotiatedAPIResource")
+	b.WithAPIVersion("apiresource.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
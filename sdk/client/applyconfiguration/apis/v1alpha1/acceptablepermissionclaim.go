@@ -27,6 +27,7 @@ import (
 type AcceptablePermissionClaimApplyConfiguration struct {
 	PermissionClaimApplyConfiguration `json:",inline"`
 	State                             *apisv1alpha1.AcceptablePermissionClaimState `json:"state,omitempty"`
+	Reason                            *string                                      `json:"reason,omitempty"`
 }
 
 // AcceptablePermissionClaimApplyConfiguration constructs an declarative configuration of the AcceptablePermissionClaim type for use with
@@ -39,7 +40,6 @@ func AcceptablePermissionClaim() *AcceptablePermissionClaimApplyConfiguration {
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Group field is set to the value of the last call.
 func (b *AcceptablePermissionClaimApplyConfiguration) WithGroup(value string) *AcceptablePermissionClaimApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Group = &value
 	return b
 }
@@ -48,17 +48,10 @@ func (b *AcceptablePermissionClaimApplyConfiguration) WithGroup(value string) *A
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Resource field is set to the value of the last call.
 func (b *AcceptablePermissionClaimApplyConfiguration) WithResource(value string) *AcceptablePermissionClaimApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Resource = &value
 	return b
 }
 
-func (b *AcceptablePermissionClaimApplyConfiguration) ensureGroupResourceApplyConfigurationExists() {
-	if b.GroupResourceApplyConfiguration == nil {
-		b.GroupResourceApplyConfiguration = &GroupResourceApplyConfiguration{}
-	}
-}
-
 // WithAll sets the All field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the All field is set to the value of the last call.
@@ -88,6 +81,24 @@ func (b *AcceptablePermissionClaimApplyConfiguration) WithIdentityHash(value str
 	return b
 }
 
+// WithSubresources adds the given value to the Subresources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Subresources field.
+func (b *AcceptablePermissionClaimApplyConfiguration) WithSubresources(values ...apisv1alpha1.PermissionClaimSubresource) *AcceptablePermissionClaimApplyConfiguration {
+	for i := range values {
+		b.Subresources = append(b.Subresources, values[i])
+	}
+	return b
+}
+
+// WithReferencedBy sets the ReferencedBy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReferencedBy field is set to the value of the last call.
+func (b *AcceptablePermissionClaimApplyConfiguration) WithReferencedBy(value *PermissionClaimReferenceApplyConfiguration) *AcceptablePermissionClaimApplyConfiguration {
+	b.ReferencedBy = value
+	return b
+}
+
 // WithState sets the State field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the State field is set to the value of the last call.
@@ -95,3 +106,11 @@ func (b *AcceptablePermissionClaimApplyConfiguration) WithState(value apisv1alph
 	b.State = &value
 	return b
 }
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *AcceptablePermissionClaimApplyConfiguration) WithReason(value string) *AcceptablePermissionClaimApplyConfiguration {
+	b.Reason = &value
+	return b
+}
@@ -21,8 +21,11 @@ package v1alpha1
 // APIBindingSpecApplyConfiguration represents an declarative configuration of the APIBindingSpec type for use
 // with apply.
 type APIBindingSpecApplyConfiguration struct {
-	Reference        *BindingReferenceApplyConfiguration           `json:"reference,omitempty"`
-	PermissionClaims []AcceptablePermissionClaimApplyConfiguration `json:"permissionClaims,omitempty"`
+	Reference             *BindingReferenceApplyConfiguration                `json:"reference,omitempty"`
+	PermissionClaims      []AcceptablePermissionClaimApplyConfiguration      `json:"permissionClaims,omitempty"`
+	AcceptancePolicy      *PermissionClaimAcceptancePolicyApplyConfiguration `json:"acceptancePolicy,omitempty"`
+	ResourceOverrides     []ResourceOverrideApplyConfiguration               `json:"resourceOverrides,omitempty"`
+	PinnedResourceSchemas []string                                           `json:"pinnedResourceSchemas,omitempty"`
 }
 
 // APIBindingSpecApplyConfiguration constructs an declarative configuration of the APIBindingSpec type for use with
@@ -51,3 +54,34 @@ func (b *APIBindingSpecApplyConfiguration) WithPermissionClaims(values ...*Accep
 	}
 	return b
 }
+
+// WithAcceptancePolicy sets the AcceptancePolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AcceptancePolicy field is set to the value of the last call.
+func (b *APIBindingSpecApplyConfiguration) WithAcceptancePolicy(value *PermissionClaimAcceptancePolicyApplyConfiguration) *APIBindingSpecApplyConfiguration {
+	b.AcceptancePolicy = value
+	return b
+}
+
+// WithResourceOverrides adds the given value to the ResourceOverrides field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ResourceOverrides field.
+func (b *APIBindingSpecApplyConfiguration) WithResourceOverrides(values ...*ResourceOverrideApplyConfiguration) *APIBindingSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResourceOverrides")
+		}
+		b.ResourceOverrides = append(b.ResourceOverrides, *values[i])
+	}
+	return b
+}
+
+// WithPinnedResourceSchemas adds the given value to the PinnedResourceSchemas field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PinnedResourceSchemas field.
+func (b *APIBindingSpecApplyConfiguration) WithPinnedResourceSchemas(values ...string) *APIBindingSpecApplyConfiguration {
+	for i := range values {
+		b.PinnedResourceSchemas = append(b.PinnedResourceSchemas, values[i])
+	}
+	return b
+}
@@ -26,12 +26,13 @@ import (
 // APIBindingStatusApplyConfiguration represents an declarative configuration of the APIBindingStatus type for use
 // with apply.
 type APIBindingStatusApplyConfiguration struct {
-	APIExportClusterName    *string                              `json:"apiExportClusterName,omitempty"`
-	BoundResources          []BoundAPIResourceApplyConfiguration `json:"boundResources,omitempty"`
-	Phase                   *apisv1alpha1.APIBindingPhaseType    `json:"phase,omitempty"`
-	Conditions              *conditionsv1alpha1.Conditions       `json:"conditions,omitempty"`
-	AppliedPermissionClaims []PermissionClaimApplyConfiguration  `json:"appliedPermissionClaims,omitempty"`
-	ExportPermissionClaims  []PermissionClaimApplyConfiguration  `json:"exportPermissionClaims,omitempty"`
+	APIExportClusterName    *string                                  `json:"apiExportClusterName,omitempty"`
+	BoundResources          []BoundAPIResourceApplyConfiguration     `json:"boundResources,omitempty"`
+	Phase                   *apisv1alpha1.APIBindingPhaseType        `json:"phase,omitempty"`
+	Conditions              *conditionsv1alpha1.Conditions           `json:"conditions,omitempty"`
+	AppliedPermissionClaims []PermissionClaimApplyConfiguration      `json:"appliedPermissionClaims,omitempty"`
+	ExportPermissionClaims  []PermissionClaimApplyConfiguration      `json:"exportPermissionClaims,omitempty"`
+	PermissionClaimsUsage   []PermissionClaimUsageApplyConfiguration `json:"permissionClaimsUsage,omitempty"`
 }
 
 // APIBindingStatusApplyConfiguration constructs an declarative configuration of the APIBindingStatus type for use with
@@ -102,3 +103,16 @@ func (b *APIBindingStatusApplyConfiguration) WithExportPermissionClaims(values .
 	}
 	return b
 }
+
+// WithPermissionClaimsUsage adds the given value to the PermissionClaimsUsage field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PermissionClaimsUsage field.
+func (b *APIBindingStatusApplyConfiguration) WithPermissionClaimsUsage(values ...*PermissionClaimUsageApplyConfiguration) *APIBindingStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithPermissionClaimsUsage")
+		}
+		b.PermissionClaimsUsage = append(b.PermissionClaimsUsage, *values[i])
+	}
+	return b
+}
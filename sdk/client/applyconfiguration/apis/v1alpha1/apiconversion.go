@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -43,6 +46,41 @@ func APIConversion(name string) *APIConversionApplyConfiguration {
 	return b
 }
 
+// ExtractAPIConversion extracts the applied configuration owned by fieldManager from
+// apiConversion. If no managedFields are found in apiConversion for fieldManager, a
+// APIConversionApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// apiConversion must be a unmodified APIConversion API object that was retrieved from the Kubernetes API.
+// ExtractAPIConversion provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractAPIConversion(apiConversion *apisv1alpha1.APIConversion, fieldManager string) (*APIConversionApplyConfiguration, error) {
+	return extractAPIConversion(apiConversion, fieldManager, "")
+}
+
+// ExtractAPIConversionStatus is the same as ExtractAPIConversion except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractAPIConversionStatus(apiConversion *apisv1alpha1.APIConversion, fieldManager string) (*APIConversionApplyConfiguration, error) {
+	return extractAPIConversion(apiConversion, fieldManager, "status")
+}
+
+func extractAPIConversion(apiConversion *apisv1alpha1.APIConversion, fieldManager string, subresource string) (*APIConversionApplyConfiguration, error) {
+	b := &APIConversionApplyConfiguration{}
+	err := managedfields.ExtractInto(apiConversion, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversion"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(apiConversion.Name)
+
+	b.WithKind("APIConversion")
+	b.WithAPIVersion("apis.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func APIExport(name string) *APIExportApplyConfiguration {
 	return b
 }
 
+// ExtractAPIExport extracts the applied configuration owned by fieldManager from
+// apiExport. If no managedFields are found in apiExport for fieldManager, a
+// APIExportApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// apiExport must be a unmodified APIExport API object that was retrieved from the Kubernetes API.
+// ExtractAPIExport provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractAPIExport(apiExport *apisv1alpha1.APIExport, fieldManager string) (*APIExportApplyConfiguration, error) {
+	return extractAPIExport(apiExport, fieldManager, "")
+}
+
+// ExtractAPIExportStatus is the same as ExtractAPIExport except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractAPIExportStatus(apiExport *apisv1alpha1.APIExport, fieldManager string) (*APIExportApplyConfiguration, error) {
+	return extractAPIExport(apiExport, fieldManager, "status")
+}
+
+func extractAPIExport(apiExport *apisv1alpha1.APIExport, fieldManager string, subresource string) (*APIExportApplyConfiguration, error) {
+	b := &APIExportApplyConfiguration{}
+	err := managedfields.ExtractInto(apiExport, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExport"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(apiExport.Name)
+
+	b.WithKind("APIExport")
+	b.WithAPIVersion("apis.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
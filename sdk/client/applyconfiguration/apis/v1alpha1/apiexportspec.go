@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	v1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
 // APIExportSpecApplyConfiguration represents an declarative configuration of the APIExportSpec type for use
 // with apply.
 type APIExportSpecApplyConfiguration struct {
@@ -25,6 +29,8 @@ type APIExportSpecApplyConfiguration struct {
 	Identity                *IdentityApplyConfiguration                `json:"identity,omitempty"`
 	MaximalPermissionPolicy *MaximalPermissionPolicyApplyConfiguration `json:"maximalPermissionPolicy,omitempty"`
 	PermissionClaims        []PermissionClaimApplyConfiguration        `json:"permissionClaims,omitempty"`
+	MaintenanceWindows      []MaintenanceWindowApplyConfiguration      `json:"maintenanceWindows,omitempty"`
+	BindingVisibility       *v1alpha1.APIExportBindingVisibility       `json:"bindingVisibility,omitempty"`
 }
 
 // APIExportSpecApplyConfiguration constructs an declarative configuration of the APIExportSpec type for use with
@@ -71,3 +77,24 @@ func (b *APIExportSpecApplyConfiguration) WithPermissionClaims(values ...*Permis
 	}
 	return b
 }
+
+// WithMaintenanceWindows adds the given value to the MaintenanceWindows field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the MaintenanceWindows field.
+func (b *APIExportSpecApplyConfiguration) WithMaintenanceWindows(values ...*MaintenanceWindowApplyConfiguration) *APIExportSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithMaintenanceWindows")
+		}
+		b.MaintenanceWindows = append(b.MaintenanceWindows, *values[i])
+	}
+	return b
+}
+
+// WithBindingVisibility sets the BindingVisibility field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BindingVisibility field is set to the value of the last call.
+func (b *APIExportSpecApplyConfiguration) WithBindingVisibility(value v1alpha1.APIExportBindingVisibility) *APIExportSpecApplyConfiguration {
+	b.BindingVisibility = &value
+	return b
+}
@@ -25,9 +25,12 @@ import (
 // APIExportStatusApplyConfiguration represents an declarative configuration of the APIExportStatus type for use
 // with apply.
 type APIExportStatusApplyConfiguration struct {
-	IdentityHash      *string                              `json:"identityHash,omitempty"`
-	Conditions        *v1alpha1.Conditions                 `json:"conditions,omitempty"`
-	VirtualWorkspaces []VirtualWorkspaceApplyConfiguration `json:"virtualWorkspaces,omitempty"`
+	IdentityHash      *string                               `json:"identityHash,omitempty"`
+	NextIdentityHash  *string                               `json:"nextIdentityHash,omitempty"`
+	Conditions        *v1alpha1.Conditions                  `json:"conditions,omitempty"`
+	VirtualWorkspaces []VirtualWorkspaceApplyConfiguration  `json:"virtualWorkspaces,omitempty"`
+	ProviderHealth    *ProviderHealthApplyConfiguration     `json:"providerHealth,omitempty"`
+	Bindings          []APIExportBindingsApplyConfiguration `json:"bindings,omitempty"`
 }
 
 // APIExportStatusApplyConfiguration constructs an declarative configuration of the APIExportStatus type for use with
@@ -44,6 +47,14 @@ func (b *APIExportStatusApplyConfiguration) WithIdentityHash(value string) *APIE
 	return b
 }
 
+// WithNextIdentityHash sets the NextIdentityHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextIdentityHash field is set to the value of the last call.
+func (b *APIExportStatusApplyConfiguration) WithNextIdentityHash(value string) *APIExportStatusApplyConfiguration {
+	b.NextIdentityHash = &value
+	return b
+}
+
 // WithConditions sets the Conditions field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Conditions field is set to the value of the last call.
@@ -64,3 +75,24 @@ func (b *APIExportStatusApplyConfiguration) WithVirtualWorkspaces(values ...*Vir
 	}
 	return b
 }
+
+// WithProviderHealth sets the ProviderHealth field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProviderHealth field is set to the value of the last call.
+func (b *APIExportStatusApplyConfiguration) WithProviderHealth(value *ProviderHealthApplyConfiguration) *APIExportStatusApplyConfiguration {
+	b.ProviderHealth = value
+	return b
+}
+
+// WithBindings adds the given value to the Bindings field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Bindings field.
+func (b *APIExportStatusApplyConfiguration) WithBindings(values ...*APIExportBindingsApplyConfiguration) *APIExportStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithBindings")
+		}
+		b.Bindings = append(b.Bindings, *values[i])
+	}
+	return b
+}
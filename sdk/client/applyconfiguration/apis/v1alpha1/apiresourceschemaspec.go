@@ -27,10 +27,11 @@ import (
 // APIResourceSchemaSpecApplyConfiguration represents an declarative configuration of the APIResourceSchemaSpec type for use
 // with apply.
 type APIResourceSchemaSpecApplyConfiguration struct {
-	Group    *string                                             `json:"group,omitempty"`
-	Names    *v1.CustomResourceDefinitionNamesApplyConfiguration `json:"names,omitempty"`
-	Scope    *apiextensionsv1.ResourceScope                      `json:"scope,omitempty"`
-	Versions []APIResourceVersionApplyConfiguration              `json:"versions,omitempty"`
+	Group      *string                                             `json:"group,omitempty"`
+	Names      *v1.CustomResourceDefinitionNamesApplyConfiguration `json:"names,omitempty"`
+	Scope      *apiextensionsv1.ResourceScope                      `json:"scope,omitempty"`
+	Versions   []APIResourceVersionApplyConfiguration              `json:"versions,omitempty"`
+	Conversion *CustomResourceConversionApplyConfiguration         `json:"conversion,omitempty"`
 }
 
 // APIResourceSchemaSpecApplyConfiguration constructs an declarative configuration of the APIResourceSchemaSpec type for use with
@@ -75,3 +76,11 @@ func (b *APIResourceSchemaSpecApplyConfiguration) WithVersions(values ...*APIRes
 	}
 	return b
 }
+
+// WithConversion sets the Conversion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Conversion field is set to the value of the last call.
+func (b *APIResourceSchemaSpecApplyConfiguration) WithConversion(value *CustomResourceConversionApplyConfiguration) *APIResourceSchemaSpecApplyConfiguration {
+	b.Conversion = value
+	return b
+}
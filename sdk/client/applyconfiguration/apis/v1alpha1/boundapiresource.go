@@ -23,6 +23,7 @@ package v1alpha1
 type BoundAPIResourceApplyConfiguration struct {
 	Group           *string                                   `json:"group,omitempty"`
 	Resource        *string                                   `json:"resource,omitempty"`
+	Alias           *string                                   `json:"alias,omitempty"`
 	Schema          *BoundAPIResourceSchemaApplyConfiguration `json:"schema,omitempty"`
 	StorageVersions []string                                  `json:"storageVersions,omitempty"`
 }
@@ -49,6 +50,14 @@ func (b *BoundAPIResourceApplyConfiguration) WithResource(value string) *BoundAP
 	return b
 }
 
+// WithAlias sets the Alias field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Alias field is set to the value of the last call.
+func (b *BoundAPIResourceApplyConfiguration) WithAlias(value string) *BoundAPIResourceApplyConfiguration {
+	b.Alias = &value
+	return b
+}
+
 // WithSchema sets the Schema field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Schema field is set to the value of the last call.
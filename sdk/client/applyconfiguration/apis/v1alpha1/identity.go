@@ -25,7 +25,8 @@ import (
 // IdentityApplyConfiguration represents an declarative configuration of the Identity type for use
 // with apply.
 type IdentityApplyConfiguration struct {
-	SecretRef *v1.SecretReference `json:"secretRef,omitempty"`
+	SecretRef     *v1.SecretReference `json:"secretRef,omitempty"`
+	NextSecretRef *v1.SecretReference `json:"nextSecretRef,omitempty"`
 }
 
 // IdentityApplyConfiguration constructs an declarative configuration of the Identity type for use with
@@ -41,3 +42,11 @@ func (b *IdentityApplyConfiguration) WithSecretRef(value v1.SecretReference) *Id
 	b.SecretRef = &value
 	return b
 }
+
+// WithNextSecretRef sets the NextSecretRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextSecretRef field is set to the value of the last call.
+func (b *IdentityApplyConfiguration) WithNextSecretRef(value v1.SecretReference) *IdentityApplyConfiguration {
+	b.NextSecretRef = &value
+	return b
+}
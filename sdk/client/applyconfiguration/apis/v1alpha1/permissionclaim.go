@@ -18,13 +18,20 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+)
+
 // PermissionClaimApplyConfiguration represents an declarative configuration of the PermissionClaim type for use
 // with apply.
 type PermissionClaimApplyConfiguration struct {
-	*GroupResourceApplyConfiguration `json:"GroupResource,omitempty"`
-	All                              *bool                                `json:"all,omitempty"`
-	ResourceSelector                 []ResourceSelectorApplyConfiguration `json:"resourceSelector,omitempty"`
-	IdentityHash                     *string                              `json:"identityHash,omitempty"`
+	GroupResourceApplyConfiguration `json:",inline"`
+	All                             *bool                                       `json:"all,omitempty"`
+	ResourceSelector                []ResourceSelectorApplyConfiguration        `json:"resourceSelector,omitempty"`
+	IdentityHash                    *string                                     `json:"identityHash,omitempty"`
+	Subresources                    []apisv1alpha1.PermissionClaimSubresource   `json:"subresources,omitempty"`
+	StatusOnly                      *bool                                       `json:"statusOnly,omitempty"`
+	ReferencedBy                    *PermissionClaimReferenceApplyConfiguration `json:"referencedBy,omitempty"`
 }
 
 // PermissionClaimApplyConfiguration constructs an declarative configuration of the PermissionClaim type for use with
@@ -37,7 +44,6 @@ func PermissionClaim() *PermissionClaimApplyConfiguration {
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Group field is set to the value of the last call.
 func (b *PermissionClaimApplyConfiguration) WithGroup(value string) *PermissionClaimApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Group = &value
 	return b
 }
@@ -46,17 +52,10 @@ func (b *PermissionClaimApplyConfiguration) WithGroup(value string) *PermissionC
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Resource field is set to the value of the last call.
 func (b *PermissionClaimApplyConfiguration) WithResource(value string) *PermissionClaimApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Resource = &value
 	return b
 }
 
-func (b *PermissionClaimApplyConfiguration) ensureGroupResourceApplyConfigurationExists() {
-	if b.GroupResourceApplyConfiguration == nil {
-		b.GroupResourceApplyConfiguration = &GroupResourceApplyConfiguration{}
-	}
-}
-
 // WithAll sets the All field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the All field is set to the value of the last call.
@@ -85,3 +84,29 @@ func (b *PermissionClaimApplyConfiguration) WithIdentityHash(value string) *Perm
 	b.IdentityHash = &value
 	return b
 }
+
+// WithSubresources adds the given value to the Subresources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Subresources field.
+func (b *PermissionClaimApplyConfiguration) WithSubresources(values ...apisv1alpha1.PermissionClaimSubresource) *PermissionClaimApplyConfiguration {
+	for i := range values {
+		b.Subresources = append(b.Subresources, values[i])
+	}
+	return b
+}
+
+// WithStatusOnly sets the StatusOnly field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StatusOnly field is set to the value of the last call.
+func (b *PermissionClaimApplyConfiguration) WithStatusOnly(value bool) *PermissionClaimApplyConfiguration {
+	b.StatusOnly = &value
+	return b
+}
+
+// WithReferencedBy sets the ReferencedBy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReferencedBy field is set to the value of the last call.
+func (b *PermissionClaimApplyConfiguration) WithReferencedBy(value *PermissionClaimReferenceApplyConfiguration) *PermissionClaimApplyConfiguration {
+	b.ReferencedBy = value
+	return b
+}
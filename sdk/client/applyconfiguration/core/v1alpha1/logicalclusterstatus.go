@@ -26,10 +26,11 @@ import (
 // LogicalClusterStatusApplyConfiguration represents an declarative configuration of the LogicalClusterStatus type for use
 // with apply.
 type LogicalClusterStatusApplyConfiguration struct {
-	URL          *string                              `json:"URL,omitempty"`
-	Phase        *v1alpha1.LogicalClusterPhaseType    `json:"phase,omitempty"`
-	Conditions   *conditionsv1alpha1.Conditions       `json:"conditions,omitempty"`
-	Initializers []v1alpha1.LogicalClusterInitializer `json:"initializers,omitempty"`
+	URL                 *string                                 `json:"URL,omitempty"`
+	Phase               *v1alpha1.LogicalClusterPhaseType       `json:"phase,omitempty"`
+	Conditions          *conditionsv1alpha1.Conditions          `json:"conditions,omitempty"`
+	Initializers        []v1alpha1.LogicalClusterInitializer    `json:"initializers,omitempty"`
+	InitializerProgress []InitializerProgressApplyConfiguration `json:"initializerProgress,omitempty"`
 }
 
 // LogicalClusterStatusApplyConfiguration constructs an declarative configuration of the LogicalClusterStatus type for use with
@@ -71,3 +72,16 @@ func (b *LogicalClusterStatusApplyConfiguration) WithInitializers(values ...v1al
 	}
 	return b
 }
+
+// WithInitializerProgress adds the given value to the InitializerProgress field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the InitializerProgress field.
+func (b *LogicalClusterStatusApplyConfiguration) WithInitializerProgress(values ...*InitializerProgressApplyConfiguration) *LogicalClusterStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithInitializerProgress")
+		}
+		b.InitializerProgress = append(b.InitializerProgress, *values[i])
+	}
+	return b
+}
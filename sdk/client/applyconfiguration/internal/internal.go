@@ -39,6 +39,2653 @@ func Parser() *typed.Parser {
 var parserOnce sync.Once
 var parser *typed.Parser
 var schemaYAML = typed.YAMLObject(`types:
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImport
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportCondition
+  map:
+    fields:
+    - name: lastTransitionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: message
+      type:
+        scalar: string
+    - name: reason
+      type:
+        scalar: string
+    - name: status
+      type:
+        scalar: string
+      default: ""
+    - name: type
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImport
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportSpec
+  map:
+    fields:
+    - name: categories
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: columnDefinitions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.ColumnDefinition
+          elementRelationship: associative
+          keys:
+          - name
+    - name: groupVersion
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.GroupVersion
+      default: {}
+    - name: kind
+      type:
+        scalar: string
+      default: ""
+    - name: listKind
+      type:
+        scalar: string
+    - name: location
+      type:
+        scalar: string
+      default: ""
+    - name: openAPIV3Schema
+      type:
+        namedType: __untyped_atomic_
+      default: {}
+    - name: plural
+      type:
+        scalar: string
+      default: ""
+    - name: schemaUpdateStrategy
+      type:
+        scalar: string
+    - name: scope
+      type:
+        scalar: string
+      default: ""
+    - name: shortNames
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: singular
+      type:
+        scalar: string
+    - name: subResources
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.SubResource
+          elementRelationship: associative
+          keys:
+          - name
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.APIResourceImportCondition
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.ColumnDefinition
+  map:
+    fields:
+    - name: description
+      type:
+        scalar: string
+      default: ""
+    - name: format
+      type:
+        scalar: string
+      default: ""
+    - name: jsonPath
+      type:
+        scalar: string
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: priority
+      type:
+        scalar: numeric
+      default: 0
+    - name: type
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.GroupVersion
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+    - name: version
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResource
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceCondition
+  map:
+    fields:
+    - name: lastTransitionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: message
+      type:
+        scalar: string
+    - name: reason
+      type:
+        scalar: string
+    - name: status
+      type:
+        scalar: string
+      default: ""
+    - name: type
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResource
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceSpec
+  map:
+    fields:
+    - name: categories
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: columnDefinitions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.ColumnDefinition
+          elementRelationship: associative
+          keys:
+          - name
+    - name: groupVersion
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.GroupVersion
+      default: {}
+    - name: kind
+      type:
+        scalar: string
+      default: ""
+    - name: listKind
+      type:
+        scalar: string
+    - name: openAPIV3Schema
+      type:
+        namedType: __untyped_atomic_
+      default: {}
+    - name: plural
+      type:
+        scalar: string
+      default: ""
+    - name: publish
+      type:
+        scalar: boolean
+    - name: scope
+      type:
+        scalar: string
+      default: ""
+    - name: shortNames
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: singular
+      type:
+        scalar: string
+    - name: subResources
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.SubResource
+          elementRelationship: associative
+          keys:
+          - name
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.NegotiatedAPIResourceCondition
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apiresource.v1alpha1.SubResource
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBinding
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBindingSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBindingStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBindingList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBinding
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBindingSpec
+  map:
+    fields:
+    - name: acceptancePolicy
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimAcceptancePolicy
+    - name: permissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.AcceptablePermissionClaim
+          elementRelationship: atomic
+    - name: pinnedResourceSchemas
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: reference
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BindingReference
+      default: {}
+    - name: resourceOverrides
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ResourceOverride
+          elementRelationship: associative
+          keys:
+          - group
+          - resource
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIBindingStatus
+  map:
+    fields:
+    - name: apiExportClusterName
+      type:
+        scalar: string
+    - name: appliedPermissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: atomic
+    - name: boundResources
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BoundAPIResource
+          elementRelationship: associative
+          keys:
+          - group
+          - resource
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: exportPermissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: atomic
+    - name: permissionClaimsUsage
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimUsage
+          elementRelationship: atomic
+    - name: phase
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversion
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversionSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversionList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversion
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversionRule
+  map:
+    fields:
+    - name: destination
+      type:
+        scalar: string
+      default: ""
+    - name: field
+      type:
+        scalar: string
+      default: ""
+    - name: transformation
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversionSpec
+  map:
+    fields:
+    - name: conversions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIVersionConversion
+          elementRelationship: associative
+          keys:
+          - from
+          - to
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExport
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportBindings
+  map:
+    fields:
+    - name: count
+      type:
+        scalar: numeric
+      default: 0
+    - name: permissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimSummary
+          elementRelationship: atomic
+    - name: shard
+      type:
+        scalar: string
+      default: ""
+    - name: workspaces
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpoint
+  map:
+    fields:
+    - name: url
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSlice
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSliceSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSliceStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSliceList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSlice
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSliceSpec
+  map:
+    fields:
+    - name: export
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ExportBindingReference
+      default: {}
+    - name: partition
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpointSliceStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: endpoints
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportEndpoint
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExport
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportSpec
+  map:
+    fields:
+    - name: bindingVisibility
+      type:
+        scalar: string
+    - name: identity
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.Identity
+    - name: latestResourceSchemas
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: maintenanceWindows
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.MaintenanceWindow
+          elementRelationship: atomic
+    - name: maximalPermissionPolicy
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.MaximalPermissionPolicy
+    - name: permissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: associative
+          keys:
+          - group
+          - resource
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportStatus
+  map:
+    fields:
+    - name: bindings
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIExportBindings
+          elementRelationship: associative
+          keys:
+          - shard
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: identityHash
+      type:
+        scalar: string
+    - name: nextIdentityHash
+      type:
+        scalar: string
+    - name: virtualWorkspaces
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.VirtualWorkspace
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceSchema
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceSchemaSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceSchemaList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceSchema
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceSchemaSpec
+  map:
+    fields:
+    - name: conversion
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.CustomResourceConversion
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: names
+      type:
+        namedType: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceDefinitionNames
+      default: {}
+    - name: scope
+      type:
+        scalar: string
+      default: ""
+    - name: versions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceVersion
+          elementRelationship: associative
+          keys:
+          - name
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIResourceVersion
+  map:
+    fields:
+    - name: additionalPrinterColumns
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceColumnDefinition
+          elementRelationship: associative
+          keys:
+          - name
+    - name: deprecated
+      type:
+        scalar: boolean
+    - name: deprecationWarning
+      type:
+        scalar: string
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: schema
+      type:
+        namedType: __untyped_atomic_
+      default: {}
+    - name: served
+      type:
+        scalar: boolean
+      default: false
+    - name: storage
+      type:
+        scalar: boolean
+      default: false
+    - name: subresources
+      type:
+        namedType: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresources
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIVersionConversion
+  map:
+    fields:
+    - name: from
+      type:
+        scalar: string
+      default: ""
+    - name: preserve
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: rules
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.APIConversionRule
+          elementRelationship: associative
+          keys:
+          - destination
+    - name: to
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.AcceptablePermissionClaim
+  map:
+    fields:
+    - name: all
+      type:
+        scalar: boolean
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+    - name: reason
+      type:
+        scalar: string
+    - name: referencedBy
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimReference
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: resourceSelector
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ResourceSelector
+          elementRelationship: atomic
+    - name: subresources
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: state
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BindingReference
+  map:
+    fields:
+    - name: export
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ExportBindingReference
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BoundAPIResource
+  map:
+    fields:
+    - name: alias
+      type:
+        scalar: string
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: schema
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BoundAPIResourceSchema
+      default: {}
+    - name: storageVersions
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.BoundAPIResourceSchema
+  map:
+    fields:
+    - name: UID
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.CustomResourceConversion
+  map:
+    fields:
+    - name: strategy
+      type:
+        scalar: string
+      default: ""
+    - name: webhook
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.WebhookConversion
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.DefaultAPIBinding
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.DefaultAPIBindingSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.DefaultAPIBindingSpec
+  map:
+    fields:
+    - name: acceptedPermissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: atomic
+    - name: export
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ExportBindingReference
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ExportBindingReference
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: path
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.Identity
+  map:
+    fields:
+    - name: nextSecretRef
+      type:
+        namedType: io.k8s.api.core.v1.SecretReference
+    - name: secretRef
+      type:
+        namedType: io.k8s.api.core.v1.SecretReference
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.LocalAPIExportPolicy
+  map:
+    elementType:
+      scalar: untyped
+      list:
+        elementType:
+          namedType: __untyped_atomic_
+        elementRelationship: atomic
+      map:
+        elementType:
+          namedType: __untyped_deduced_
+        elementRelationship: separable
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.MaintenanceWindow
+  map:
+    fields:
+    - name: days
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: duration
+      type:
+        scalar: string
+    - name: start
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.MaximalPermissionPolicy
+  map:
+    fields:
+    - name: local
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.LocalAPIExportPolicy
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+  map:
+    fields:
+    - name: all
+      type:
+        scalar: boolean
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+    - name: referencedBy
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimReference
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: resourceSelector
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ResourceSelector
+          elementRelationship: atomic
+    - name: statusOnly
+      type:
+        scalar: boolean
+    - name: subresources
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimAcceptancePolicy
+  map:
+    fields:
+    - name: rules
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimAcceptanceRule
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimAcceptanceRule
+  map:
+    fields:
+    - name: all
+      type:
+        scalar: boolean
+    - name: group
+      type:
+        scalar: string
+    - name: identityHash
+      type:
+        scalar: string
+    - name: namespaces
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimReference
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: nameField
+      type:
+        scalar: string
+      default: ""
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimRejectionReason
+  map:
+    fields:
+    - name: count
+      type:
+        scalar: numeric
+      default: 0
+    - name: reason
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimSummary
+  map:
+    fields:
+    - name: accepted
+      type:
+        scalar: numeric
+      default: 0
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+    - name: rejected
+      type:
+        scalar: numeric
+      default: 0
+    - name: rejectionReasons
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimRejectionReason
+          elementRelationship: associative
+          keys:
+          - reason
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaimUsage
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+    - name: lastUsedTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ResourceOverride
+  map:
+    fields:
+    - name: alias
+      type:
+        scalar: string
+      default: ""
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.ResourceSelector
+  map:
+    fields:
+    - name: matchExpressions
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelectorRequirement
+          elementRelationship: atomic
+    - name: matchLabels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: name
+      type:
+        scalar: string
+    - name: namespace
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.VirtualWorkspace
+  map:
+    fields:
+    - name: url
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.WebhookClientConfig
+  map:
+    fields:
+    - name: caBundle
+      type:
+        scalar: string
+    - name: url
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.WebhookConversion
+  map:
+    fields:
+    - name: clientConfig
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.WebhookClientConfig
+      default: {}
+    - name: conversionReviewVersions
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.InitializerProgress
+  map:
+    fields:
+    - name: initializer
+      type:
+        scalar: string
+      default: ""
+    - name: lastTransitionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: message
+      type:
+        scalar: string
+      default: ""
+    - name: percent
+      type:
+        scalar: numeric
+      default: 0
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalCluster
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalCluster
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterOwner
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+      default: ""
+    - name: cluster
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: namespace
+      type:
+        scalar: string
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: uid
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterSpec
+  map:
+    fields:
+    - name: directlyDeletable
+      type:
+        scalar: boolean
+    - name: initializers
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: owner
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterOwner
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.LogicalClusterStatus
+  map:
+    fields:
+    - name: URL
+      type:
+        scalar: string
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: initializerProgress
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.InitializerProgress
+          elementRelationship: associative
+          keys:
+          - initializer
+    - name: initializers
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: phase
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.Shard
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.ShardSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.ShardStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.ShardList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.Shard
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.ShardSpec
+  map:
+    fields:
+    - name: baseURL
+      type:
+        scalar: string
+      default: ""
+    - name: externalURL
+      type:
+        scalar: string
+    - name: virtualWorkspaceURL
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.core.v1alpha1.ShardStatus
+  map:
+    fields:
+    - name: capacity
+      type:
+        map:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.api.resource.Quantity
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.AvailableSelectorLabel
+  map:
+    fields:
+    - name: description
+      type:
+        scalar: string
+    - name: key
+      type:
+        scalar: string
+      default: ""
+    - name: values
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.GroupVersionResource
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: version
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.Location
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.Location
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationReference
+  map:
+    fields:
+    - name: locationName
+      type:
+        scalar: string
+      default: ""
+    - name: path
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationSpec
+  map:
+    fields:
+    - name: availableSelectorLabels
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.AvailableSelectorLabel
+          elementRelationship: associative
+          keys:
+          - key
+    - name: description
+      type:
+        scalar: string
+    - name: instanceSelector
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+    - name: resource
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.GroupVersionResource
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationStatus
+  map:
+    fields:
+    - name: availableInstances
+      type:
+        scalar: numeric
+    - name: instances
+      type:
+        scalar: numeric
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.Placement
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.PlacementSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.PlacementStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.PlacementList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.Placement
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.PlacementSpec
+  map:
+    fields:
+    - name: locationResource
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.GroupVersionResource
+      default: {}
+    - name: locationSelectors
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+          elementRelationship: atomic
+    - name: locationWorkspace
+      type:
+        scalar: string
+    - name: namespaceSelector
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+- name: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.PlacementStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: phase
+      type:
+        scalar: string
+    - name: selectedLocation
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.LocationReference
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIBindingClaimBundle
+  map:
+    fields:
+    - name: acceptedPermissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: atomic
+    - name: export
+      type:
+        scalar: string
+      default: ""
+    - name: path
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIExportReference
+  map:
+    fields:
+    - name: export
+      type:
+        scalar: string
+      default: ""
+    - name: path
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIRestrictions
+  map:
+    fields:
+    - name: allowed
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.GroupResourcePattern
+          elementRelationship: atomic
+    - name: denied
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.GroupResourcePattern
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.GroupResourcePattern
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.VirtualWorkspace
+  map:
+    fields:
+    - name: url
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.Workspace
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceInitializationProgress
+  map:
+    fields:
+    - name: lastTransitionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: message
+      type:
+        scalar: string
+      default: ""
+    - name: percent
+      type:
+        scalar: numeric
+      default: 0
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceLifecycleWebhook
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceLifecycleWebhookSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceLifecycleWebhookSpec
+  map:
+    fields:
+    - name: caBundle
+      type:
+        scalar: string
+    - name: events
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: format
+      type:
+        scalar: string
+    - name: url
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.Workspace
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceLocation
+  map:
+    fields:
+    - name: selector
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceMetadataPropagation
+  map:
+    fields:
+    - name: annotations
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: labels
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspacePodSecurity
+  map:
+    fields:
+    - name: enforce
+      type:
+        scalar: string
+      default: ""
+    - name: enforceVersion
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequest
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequestSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequestStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequestList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequest
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequestSpec
+  map:
+    fields:
+    - name: justification
+      type:
+        scalar: string
+    - name: type
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+    - name: workspaceName
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceRequestStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: decidedBy
+      type:
+        scalar: string
+    - name: decision
+      type:
+        scalar: string
+    - name: decisionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+    - name: message
+      type:
+        scalar: string
+    - name: phase
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceSpec
+  map:
+    fields:
+    - name: URL
+      type:
+        scalar: string
+    - name: cluster
+      type:
+        scalar: string
+    - name: location
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceLocation
+    - name: type
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: initializationProgress
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceInitializationProgress
+    - name: initializers
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: phase
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplate
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateClusterRole
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: rules
+      type:
+        list:
+          elementType:
+            namedType: __untyped_atomic_
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateClusterRoleBinding
+  map:
+    fields:
+    - name: clusterRole
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: subjects
+      type:
+        list:
+          elementType:
+            namedType: __untyped_atomic_
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateSpec
+  map:
+    fields:
+    - name: annotations
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: apiBindings
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIBindingClaimBundle
+          elementRelationship: atomic
+    - name: clusterRoleBindings
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateClusterRoleBinding
+          elementRelationship: atomic
+    - name: clusterRoles
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTemplateClusterRole
+          elementRelationship: atomic
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: type
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceType
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeAPIBinding
+  map:
+    fields:
+    - name: acceptedPermissionClaims
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.apis.v1alpha1.PermissionClaim
+          elementRelationship: atomic
+    - name: export
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+    - name: path
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeExtension
+  map:
+    fields:
+    - name: with
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceType
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: path
+      type:
+        scalar: string
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeSelector
+  map:
+    fields:
+    - name: none
+      type:
+        scalar: boolean
+    - name: types
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeSpec
+  map:
+    fields:
+    - name: additionalWorkspaceLabels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: apiBindings
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeAPIBinding
+          elementRelationship: atomic
+    - name: apiRestrictions
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIRestrictions
+    - name: claimBundles
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIBindingClaimBundle
+          elementRelationship: atomic
+    - name: defaultAPIBindings
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIExportReference
+          elementRelationship: atomic
+    - name: defaultChildWorkspaceType
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeReference
+    - name: extend
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeExtension
+      default: {}
+    - name: initializer
+      type:
+        scalar: boolean
+    - name: limitAllowedChildren
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeSelector
+    - name: limitAllowedParents
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeSelector
+    - name: podSecurity
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspacePodSecurity
+    - name: propagatedMetadata
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceMetadataPropagation
+- name: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceTypeStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: virtualWorkspaces
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.VirtualWorkspace
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+  map:
+    fields:
+    - name: lastTransitionTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: message
+      type:
+        scalar: string
+    - name: reason
+      type:
+        scalar: string
+    - name: severity
+      type:
+        scalar: string
+    - name: status
+      type:
+        scalar: string
+      default: ""
+    - name: type
+      type:
+        scalar: string
+      default: ""
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.Partition
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSpec
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.Partition
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSet
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSetSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSetStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSetList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSet
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSetSpec
+  map:
+    fields:
+    - name: dimensions
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: shardSelector
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSetStatus
+  map:
+    fields:
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: count
+      type:
+        scalar: numeric
+- name: com.github.kcp-dev.kcp.sdk.apis.topology.v1alpha1.PartitionSpec
+  map:
+    fields:
+    - name: selector
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.ResourceToSync
+  map:
+    fields:
+    - name: group
+      type:
+        scalar: string
+      default: ""
+    - name: identityHash
+      type:
+        scalar: string
+      default: ""
+    - name: resource
+      type:
+        scalar: string
+      default: ""
+    - name: state
+      type:
+        scalar: string
+    - name: versions
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTarget
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+      default: {}
+    - name: spec
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTargetSpec
+      default: {}
+    - name: status
+      type:
+        namedType: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTargetStatus
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTargetList
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: items
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTarget
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+      default: {}
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTargetSpec
+  map:
+    fields:
+    - name: cells
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: evictAfter
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+    - name: supportedAPIExports
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.APIExportReference
+          elementRelationship: atomic
+    - name: unschedulable
+      type:
+        scalar: boolean
+      default: false
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTargetStatus
+  map:
+    fields:
+    - name: allocatable
+      type:
+        map:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.api.resource.Quantity
+    - name: capacity
+      type:
+        map:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.api.resource.Quantity
+    - name: conditions
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.third_party.conditions.apis.conditions.v1alpha1.Condition
+          elementRelationship: atomic
+    - name: lastSyncerHeartbeatTime
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+    - name: syncedResources
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.ResourceToSync
+          elementRelationship: atomic
+    - name: virtualWorkspaces
+      type:
+        list:
+          elementType:
+            namedType: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.VirtualWorkspace
+          elementRelationship: atomic
+- name: com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.VirtualWorkspace
+  map:
+    fields:
+    - name: syncerURL
+      type:
+        scalar: string
+      default: ""
+    - name: upsyncerURL
+      type:
+        scalar: string
+      default: ""
+- name: io.k8s.api.core.v1.SecretReference
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: namespace
+      type:
+        scalar: string
+    elementRelationship: atomic
+- name: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceColumnDefinition
+  map:
+    fields:
+    - name: description
+      type:
+        scalar: string
+    - name: format
+      type:
+        scalar: string
+    - name: jsonPath
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: priority
+      type:
+        scalar: numeric
+    - name: type
+      type:
+        scalar: string
+      default: ""
+- name: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceDefinitionNames
+  map:
+    fields:
+    - name: categories
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: kind
+      type:
+        scalar: string
+      default: ""
+    - name: listKind
+      type:
+        scalar: string
+    - name: plural
+      type:
+        scalar: string
+      default: ""
+    - name: shortNames
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: singular
+      type:
+        scalar: string
+- name: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresourceScale
+  map:
+    fields:
+    - name: labelSelectorPath
+      type:
+        scalar: string
+    - name: specReplicasPath
+      type:
+        scalar: string
+      default: ""
+    - name: statusReplicasPath
+      type:
+        scalar: string
+      default: ""
+- name: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresourceStatus
+  map:
+    elementType:
+      scalar: untyped
+      list:
+        elementType:
+          namedType: __untyped_atomic_
+        elementRelationship: atomic
+      map:
+        elementType:
+          namedType: __untyped_deduced_
+        elementRelationship: separable
+- name: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresources
+  map:
+    fields:
+    - name: scale
+      type:
+        namedType: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresourceScale
+    - name: status
+      type:
+        namedType: io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresourceStatus
+- name: io.k8s.apimachinery.pkg.api.resource.Quantity
+  scalar: untyped
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.FieldsV1
+  map:
+    elementType:
+      scalar: untyped
+      list:
+        elementType:
+          namedType: __untyped_atomic_
+        elementRelationship: atomic
+      map:
+        elementType:
+          namedType: __untyped_deduced_
+        elementRelationship: separable
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector
+  map:
+    fields:
+    - name: matchExpressions
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelectorRequirement
+          elementRelationship: atomic
+    - name: matchLabels
+      type:
+        map:
+          elementType:
+            scalar: string
+    elementRelationship: atomic
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelectorRequirement
+  map:
+    fields:
+    - name: key
+      type:
+        scalar: string
+      default: ""
+    - name: operator
+      type:
+        scalar: string
+      default: ""
+    - name: values
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta
+  map:
+    fields:
+    - name: continue
+      type:
+        scalar: string
+    - name: remainingItemCount
+      type:
+        scalar: numeric
+    - name: resourceVersion
+      type:
+        scalar: string
+    - name: selfLink
+      type:
+        scalar: string
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.ManagedFieldsEntry
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: fieldsType
+      type:
+        scalar: string
+    - name: fieldsV1
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.FieldsV1
+    - name: manager
+      type:
+        scalar: string
+    - name: operation
+      type:
+        scalar: string
+    - name: subresource
+      type:
+        scalar: string
+    - name: time
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+  map:
+    fields:
+    - name: annotations
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: clusterName
+      type:
+        scalar: string
+    - name: creationTimestamp
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+      default: {}
+    - name: deletionGracePeriodSeconds
+      type:
+        scalar: numeric
+    - name: deletionTimestamp
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+    - name: finalizers
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: generateName
+      type:
+        scalar: string
+    - name: generation
+      type:
+        scalar: numeric
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: managedFields
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ManagedFieldsEntry
+          elementRelationship: atomic
+    - name: name
+      type:
+        scalar: string
+    - name: namespace
+      type:
+        scalar: string
+    - name: ownerReferences
+      type:
+        list:
+          elementType:
+            namedType: io.k8s.apimachinery.pkg.apis.meta.v1.OwnerReference
+          elementRelationship: associative
+          keys:
+          - uid
+    - name: resourceVersion
+      type:
+        scalar: string
+    - name: selfLink
+      type:
+        scalar: string
+    - name: uid
+      type:
+        scalar: string
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.OwnerReference
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+      default: ""
+    - name: blockOwnerDeletion
+      type:
+        scalar: boolean
+    - name: controller
+      type:
+        scalar: boolean
+    - name: kind
+      type:
+        scalar: string
+      default: ""
+    - name: name
+      type:
+        scalar: string
+      default: ""
+    - name: uid
+      type:
+        scalar: string
+      default: ""
+    elementRelationship: atomic
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.Time
+  scalar: untyped
+- name: io.k8s.apimachinery.pkg.runtime.RawExtension
+  map:
+    elementType:
+      scalar: untyped
+      list:
+        elementType:
+          namedType: __untyped_atomic_
+        elementRelationship: atomic
+      map:
+        elementType:
+          namedType: __untyped_deduced_
+        elementRelationship: separable
 - name: __untyped_atomic_
   scalar: untyped
   list:
@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/scheduling/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func Placement(name string) *PlacementApplyConfiguration {
 	return b
 }
 
+// ExtractPlacement extracts the applied configuration owned by fieldManager from
+// placement. If no managedFields are found in placement for fieldManager, a
+// PlacementApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// placement must be a unmodified Placement API object that was retrieved from the Kubernetes API.
+// ExtractPlacement provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractPlacement(placement *schedulingv1alpha1.Placement, fieldManager string) (*PlacementApplyConfiguration, error) {
+	return extractPlacement(placement, fieldManager, "")
+}
+
+// ExtractPlacementStatus is the same as ExtractPlacement except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractPlacementStatus(placement *schedulingv1alpha1.Placement, fieldManager string) (*PlacementApplyConfiguration, error) {
+	return extractPlacement(placement, fieldManager, "status")
+}
+
+func extractPlacement(placement *schedulingv1alpha1.Placement, fieldManager string, subresource string) (*PlacementApplyConfiguration, error) {
+	b := &PlacementApplyConfiguration{}
+	err := managedfields.ExtractInto(placement, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.scheduling.v1alpha1.Placement"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(placement.Name)
+
+	b.WithKind("Placement")
+	b.WithAPIVersion("scheduling.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
@@ -26,9 +26,10 @@ import (
 // WorkspaceStatusApplyConfiguration represents an declarative configuration of the WorkspaceStatus type for use
 // with apply.
 type WorkspaceStatusApplyConfiguration struct {
-	Phase        *v1alpha1.LogicalClusterPhaseType    `json:"phase,omitempty"`
-	Conditions   *conditionsv1alpha1.Conditions       `json:"conditions,omitempty"`
-	Initializers []v1alpha1.LogicalClusterInitializer `json:"initializers,omitempty"`
+	Phase                  *v1alpha1.LogicalClusterPhaseType                  `json:"phase,omitempty"`
+	Conditions             *conditionsv1alpha1.Conditions                     `json:"conditions,omitempty"`
+	Initializers           []v1alpha1.LogicalClusterInitializer               `json:"initializers,omitempty"`
+	InitializationProgress *WorkspaceInitializationProgressApplyConfiguration `json:"initializationProgress,omitempty"`
 }
 
 // WorkspaceStatusApplyConfiguration constructs an declarative configuration of the WorkspaceStatus type for use with
@@ -62,3 +63,11 @@ func (b *WorkspaceStatusApplyConfiguration) WithInitializers(values ...v1alpha1.
 	}
 	return b
 }
+
+// WithInitializationProgress sets the InitializationProgress field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the InitializationProgress field is set to the value of the last call.
+func (b *WorkspaceStatusApplyConfiguration) WithInitializationProgress(value *WorkspaceInitializationProgressApplyConfiguration) *WorkspaceStatusApplyConfiguration {
+	b.InitializationProgress = value
+	return b
+}
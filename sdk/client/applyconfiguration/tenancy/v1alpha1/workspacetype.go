@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func WorkspaceType(name string) *WorkspaceTypeApplyConfiguration {
 	return b
 }
 
+// ExtractWorkspaceType extracts the applied configuration owned by fieldManager from
+// workspaceType. If no managedFields are found in workspaceType for fieldManager, a
+// WorkspaceTypeApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// workspaceType must be a unmodified WorkspaceType API object that was retrieved from the Kubernetes API.
+// ExtractWorkspaceType provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractWorkspaceType(workspaceType *tenancyv1alpha1.WorkspaceType, fieldManager string) (*WorkspaceTypeApplyConfiguration, error) {
+	return extractWorkspaceType(workspaceType, fieldManager, "")
+}
+
+// ExtractWorkspaceTypeStatus is the same as ExtractWorkspaceType except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractWorkspaceTypeStatus(workspaceType *tenancyv1alpha1.WorkspaceType, fieldManager string) (*WorkspaceTypeApplyConfiguration, error) {
+	return extractWorkspaceType(workspaceType, fieldManager, "status")
+}
+
+func extractWorkspaceType(workspaceType *tenancyv1alpha1.WorkspaceType, fieldManager string, subresource string) (*WorkspaceTypeApplyConfiguration, error) {
+	b := &WorkspaceTypeApplyConfiguration{}
+	err := managedfields.ExtractInto(workspaceType, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.tenancy.v1alpha1.WorkspaceType"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(workspaceType.Name)
+
+	b.WithKind("WorkspaceType")
+	b.WithAPIVersion("tenancy.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
@@ -21,13 +21,18 @@ package v1alpha1
 // WorkspaceTypeSpecApplyConfiguration represents an declarative configuration of the WorkspaceTypeSpec type for use
 // with apply.
 type WorkspaceTypeSpecApplyConfiguration struct {
-	Initializer               *bool                                     `json:"initializer,omitempty"`
-	Extend                    *WorkspaceTypeExtensionApplyConfiguration `json:"extend,omitempty"`
-	AdditionalWorkspaceLabels map[string]string                         `json:"additionalWorkspaceLabels,omitempty"`
-	DefaultChildWorkspaceType *WorkspaceTypeReferenceApplyConfiguration `json:"defaultChildWorkspaceType,omitempty"`
-	LimitAllowedChildren      *WorkspaceTypeSelectorApplyConfiguration  `json:"limitAllowedChildren,omitempty"`
-	LimitAllowedParents       *WorkspaceTypeSelectorApplyConfiguration  `json:"limitAllowedParents,omitempty"`
-	DefaultAPIBindings        []APIExportReferenceApplyConfiguration    `json:"defaultAPIBindings,omitempty"`
+	Initializer               *bool                                           `json:"initializer,omitempty"`
+	Extend                    *WorkspaceTypeExtensionApplyConfiguration       `json:"extend,omitempty"`
+	AdditionalWorkspaceLabels map[string]string                               `json:"additionalWorkspaceLabels,omitempty"`
+	DefaultChildWorkspaceType *WorkspaceTypeReferenceApplyConfiguration       `json:"defaultChildWorkspaceType,omitempty"`
+	LimitAllowedChildren      *WorkspaceTypeSelectorApplyConfiguration        `json:"limitAllowedChildren,omitempty"`
+	LimitAllowedParents       *WorkspaceTypeSelectorApplyConfiguration        `json:"limitAllowedParents,omitempty"`
+	DefaultAPIBindings        []APIExportReferenceApplyConfiguration          `json:"defaultAPIBindings,omitempty"`
+	APIBindings               []WorkspaceTypeAPIBindingApplyConfiguration     `json:"apiBindings,omitempty"`
+	ClaimBundles              []APIBindingClaimBundleApplyConfiguration       `json:"claimBundles,omitempty"`
+	PropagatedMetadata        *WorkspaceMetadataPropagationApplyConfiguration `json:"propagatedMetadata,omitempty"`
+	APIRestrictions           *APIRestrictionsApplyConfiguration              `json:"apiRestrictions,omitempty"`
+	PodSecurity               *WorkspacePodSecurityApplyConfiguration         `json:"podSecurity,omitempty"`
 }
 
 // WorkspaceTypeSpecApplyConfiguration constructs an declarative configuration of the WorkspaceTypeSpec type for use with
@@ -102,3 +107,53 @@ func (b *WorkspaceTypeSpecApplyConfiguration) WithDefaultAPIBindings(values ...*
 	}
 	return b
 }
+
+// WithAPIBindings adds the given value to the APIBindings field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the APIBindings field.
+func (b *WorkspaceTypeSpecApplyConfiguration) WithAPIBindings(values ...*WorkspaceTypeAPIBindingApplyConfiguration) *WorkspaceTypeSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAPIBindings")
+		}
+		b.APIBindings = append(b.APIBindings, *values[i])
+	}
+	return b
+}
+
+// WithClaimBundles adds the given value to the ClaimBundles field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ClaimBundles field.
+func (b *WorkspaceTypeSpecApplyConfiguration) WithClaimBundles(values ...*APIBindingClaimBundleApplyConfiguration) *WorkspaceTypeSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithClaimBundles")
+		}
+		b.ClaimBundles = append(b.ClaimBundles, *values[i])
+	}
+	return b
+}
+
+// WithPropagatedMetadata sets the PropagatedMetadata field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PropagatedMetadata field is set to the value of the last call.
+func (b *WorkspaceTypeSpecApplyConfiguration) WithPropagatedMetadata(value *WorkspaceMetadataPropagationApplyConfiguration) *WorkspaceTypeSpecApplyConfiguration {
+	b.PropagatedMetadata = value
+	return b
+}
+
+// WithAPIRestrictions sets the APIRestrictions field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the APIRestrictions field is set to the value of the last call.
+func (b *WorkspaceTypeSpecApplyConfiguration) WithAPIRestrictions(value *APIRestrictionsApplyConfiguration) *WorkspaceTypeSpecApplyConfiguration {
+	b.APIRestrictions = value
+	return b
+}
+
+// WithPodSecurity sets the PodSecurity field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodSecurity field is set to the value of the last call.
+func (b *WorkspaceTypeSpecApplyConfiguration) WithPodSecurity(value *WorkspacePodSecurityApplyConfiguration) *WorkspaceTypeSpecApplyConfiguration {
+	b.PodSecurity = value
+	return b
+}
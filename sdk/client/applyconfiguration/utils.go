@@ -126,6 +126,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationapisv1alpha1.APIConversionSpecApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("APIExport"):
 		return &applyconfigurationapisv1alpha1.APIExportApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("APIExportBindings"):
+		return &applyconfigurationapisv1alpha1.APIExportBindingsApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("APIExportEndpoint"):
 		return &applyconfigurationapisv1alpha1.APIExportEndpointApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("APIExportEndpointSlice"):
@@ -152,26 +154,56 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationapisv1alpha1.BoundAPIResourceApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("BoundAPIResourceSchema"):
 		return &applyconfigurationapisv1alpha1.BoundAPIResourceSchemaApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("CustomResourceConversion"):
+		return &applyconfigurationapisv1alpha1.CustomResourceConversionApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("DefaultAPIBinding"):
+		return &applyconfigurationapisv1alpha1.DefaultAPIBindingApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("DefaultAPIBindingSpec"):
+		return &applyconfigurationapisv1alpha1.DefaultAPIBindingSpecApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("ExportBindingReference"):
 		return &applyconfigurationapisv1alpha1.ExportBindingReferenceApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("GroupResource"):
 		return &applyconfigurationapisv1alpha1.GroupResourceApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("Identity"):
 		return &applyconfigurationapisv1alpha1.IdentityApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("MaintenanceWindow"):
+		return &applyconfigurationapisv1alpha1.MaintenanceWindowApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("MaximalPermissionPolicy"):
 		return &applyconfigurationapisv1alpha1.MaximalPermissionPolicyApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaim"):
 		return &applyconfigurationapisv1alpha1.PermissionClaimApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimAcceptancePolicy"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimAcceptancePolicyApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimAcceptanceRule"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimAcceptanceRuleApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimReference"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimReferenceApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimRejectionReason"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimRejectionReasonApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimSummary"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimSummaryApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("PermissionClaimUsage"):
+		return &applyconfigurationapisv1alpha1.PermissionClaimUsageApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("ProviderHealth"):
+		return &applyconfigurationapisv1alpha1.ProviderHealthApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("ResourceOverride"):
+		return &applyconfigurationapisv1alpha1.ResourceOverrideApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("ResourceSelector"):
 		return &applyconfigurationapisv1alpha1.ResourceSelectorApplyConfiguration{}
 	case apisv1alpha1.SchemeGroupVersion.WithKind("VirtualWorkspace"):
 		return &applyconfigurationapisv1alpha1.VirtualWorkspaceApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("WebhookClientConfig"):
+		return &applyconfigurationapisv1alpha1.WebhookClientConfigApplyConfiguration{}
+	case apisv1alpha1.SchemeGroupVersion.WithKind("WebhookConversion"):
+		return &applyconfigurationapisv1alpha1.WebhookConversionApplyConfiguration{}
 
 		// Group=conditions, Version=v1alpha1
 	case conditionsv1alpha1.SchemeGroupVersion.WithKind("Condition"):
 		return &applyconfigurationconditionsv1alpha1.ConditionApplyConfiguration{}
 
 		// Group=core.kcp.io, Version=v1alpha1
+	case corev1alpha1.SchemeGroupVersion.WithKind("InitializerProgress"):
+		return &applyconfigurationcorev1alpha1.InitializerProgressApplyConfiguration{}
 	case corev1alpha1.SchemeGroupVersion.WithKind("LogicalCluster"):
 		return &applyconfigurationcorev1alpha1.LogicalClusterApplyConfiguration{}
 	case corev1alpha1.SchemeGroupVersion.WithKind("LogicalClusterOwner"):
@@ -180,6 +212,12 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationcorev1alpha1.LogicalClusterSpecApplyConfiguration{}
 	case corev1alpha1.SchemeGroupVersion.WithKind("LogicalClusterStatus"):
 		return &applyconfigurationcorev1alpha1.LogicalClusterStatusApplyConfiguration{}
+	case corev1alpha1.SchemeGroupVersion.WithKind("ReplicatedResource"):
+		return &applyconfigurationcorev1alpha1.ReplicatedResourceApplyConfiguration{}
+	case corev1alpha1.SchemeGroupVersion.WithKind("ReplicationConfig"):
+		return &applyconfigurationcorev1alpha1.ReplicationConfigApplyConfiguration{}
+	case corev1alpha1.SchemeGroupVersion.WithKind("ReplicationConfigSpec"):
+		return &applyconfigurationcorev1alpha1.ReplicationConfigSpecApplyConfiguration{}
 	case corev1alpha1.SchemeGroupVersion.WithKind("Shard"):
 		return &applyconfigurationcorev1alpha1.ShardApplyConfiguration{}
 	case corev1alpha1.SchemeGroupVersion.WithKind("ShardSpec"):
@@ -224,20 +262,52 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationschedulingv1alpha1.PlacementStatusApplyConfiguration{}
 
 		// Group=tenancy.kcp.io, Version=v1alpha1
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("APIBindingClaimBundle"):
+		return &applyconfigurationtenancyv1alpha1.APIBindingClaimBundleApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("APIExportReference"):
 		return &applyconfigurationtenancyv1alpha1.APIExportReferenceApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("APIRestrictions"):
+		return &applyconfigurationtenancyv1alpha1.APIRestrictionsApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("GroupResourcePattern"):
+		return &applyconfigurationtenancyv1alpha1.GroupResourcePatternApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("VirtualWorkspace"):
 		return &applyconfigurationtenancyv1alpha1.VirtualWorkspaceApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("Workspace"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceInitializationProgress"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceInitializationProgressApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceLifecycleWebhook"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceLifecycleWebhookApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceLifecycleWebhookSpec"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceLifecycleWebhookSpecApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceLocation"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceLocationApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceMetadataPropagation"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceMetadataPropagationApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspacePodSecurity"):
+		return &applyconfigurationtenancyv1alpha1.WorkspacePodSecurityApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceRequest"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceRequestApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceRequestSpec"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceRequestSpecApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceRequestStatus"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceRequestStatusApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceSpec"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceSpecApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceStatus"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceStatusApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTemplate"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceTemplateApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTemplateClusterRole"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceTemplateClusterRoleApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTemplateClusterRoleBinding"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceTemplateClusterRoleBindingApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTemplateSpec"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceTemplateSpecApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceType"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceTypeApplyConfiguration{}
+	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTypeAPIBinding"):
+		return &applyconfigurationtenancyv1alpha1.WorkspaceTypeAPIBindingApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTypeExtension"):
 		return &applyconfigurationtenancyv1alpha1.WorkspaceTypeExtensionApplyConfiguration{}
 	case tenancyv1alpha1.SchemeGroupVersion.WithKind("WorkspaceTypeReference"):
@@ -266,6 +336,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationworkloadv1alpha1.ResourceToSyncApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTarget"):
 		return &applyconfigurationworkloadv1alpha1.SyncTargetApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetPool"):
+		return &applyconfigurationworkloadv1alpha1.SyncTargetPoolApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetPoolMember"):
+		return &applyconfigurationworkloadv1alpha1.SyncTargetPoolMemberApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetPoolSpec"):
+		return &applyconfigurationworkloadv1alpha1.SyncTargetPoolSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetPoolStatus"):
+		return &applyconfigurationworkloadv1alpha1.SyncTargetPoolStatusApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetSpec"):
 		return &applyconfigurationworkloadv1alpha1.SyncTargetSpecApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTargetStatus"):
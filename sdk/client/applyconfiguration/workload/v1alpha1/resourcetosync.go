@@ -26,10 +26,10 @@ import (
 // ResourceToSyncApplyConfiguration represents an declarative configuration of the ResourceToSync type for use
 // with apply.
 type ResourceToSyncApplyConfiguration struct {
-	*v1alpha1.GroupResourceApplyConfiguration `json:"GroupResource,omitempty"`
-	Versions                                  []string                                  `json:"versions,omitempty"`
-	IdentityHash                              *string                                   `json:"identityHash,omitempty"`
-	State                                     *workloadv1alpha1.ResourceCompatibleState `json:"state,omitempty"`
+	v1alpha1.GroupResourceApplyConfiguration `json:",inline"`
+	Versions                                 []string                                  `json:"versions,omitempty"`
+	IdentityHash                             *string                                   `json:"identityHash,omitempty"`
+	State                                    *workloadv1alpha1.ResourceCompatibleState `json:"state,omitempty"`
 }
 
 // ResourceToSyncApplyConfiguration constructs an declarative configuration of the ResourceToSync type for use with
@@ -42,7 +42,6 @@ func ResourceToSync() *ResourceToSyncApplyConfiguration {
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Group field is set to the value of the last call.
 func (b *ResourceToSyncApplyConfiguration) WithGroup(value string) *ResourceToSyncApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Group = &value
 	return b
 }
@@ -51,17 +50,10 @@ func (b *ResourceToSyncApplyConfiguration) WithGroup(value string) *ResourceToSy
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Resource field is set to the value of the last call.
 func (b *ResourceToSyncApplyConfiguration) WithResource(value string) *ResourceToSyncApplyConfiguration {
-	b.ensureGroupResourceApplyConfigurationExists()
 	b.Resource = &value
 	return b
 }
 
-func (b *ResourceToSyncApplyConfiguration) ensureGroupResourceApplyConfigurationExists() {
-	if b.GroupResourceApplyConfiguration == nil {
-		b.GroupResourceApplyConfiguration = &v1alpha1.GroupResourceApplyConfiguration{}
-	}
-}
-
 // WithVersions adds the given value to the Versions field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Versions field.
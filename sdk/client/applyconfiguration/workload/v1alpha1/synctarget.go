@@ -21,7 +21,10 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+	internal "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/internal"
 	v1 "github.com/kcp-dev/kcp/sdk/client/applyconfiguration/meta/v1"
 )
 
@@ -44,6 +47,41 @@ func SyncTarget(name string) *SyncTargetApplyConfiguration {
 	return b
 }
 
+// ExtractSyncTarget extracts the applied configuration owned by fieldManager from
+// syncTarget. If no managedFields are found in syncTarget for fieldManager, a
+// SyncTargetApplyConfiguration is returned with only the Name, Namespace (if applicable),
+// APIVersion and Kind populated. It is possible that no managed fields were found for because other
+// field managers have taken ownership of all the fields previously owned by fieldManager, or because
+// the fieldManager never owned fields any fields.
+// syncTarget must be a unmodified SyncTarget API object that was retrieved from the Kubernetes API.
+// ExtractSyncTarget provides a way to perform a extract/modify-in-place/apply workflow.
+// Note that an extracted apply configuration will contain fewer fields than what the fieldManager previously
+// applied if another fieldManager has updated or force applied any of the previously applied fields.
+// Experimental!
+func ExtractSyncTarget(syncTarget *workloadv1alpha1.SyncTarget, fieldManager string) (*SyncTargetApplyConfiguration, error) {
+	return extractSyncTarget(syncTarget, fieldManager, "")
+}
+
+// ExtractSyncTargetStatus is the same as ExtractSyncTarget except
+// that it extracts the status subresource applied configuration.
+// Experimental!
+func ExtractSyncTargetStatus(syncTarget *workloadv1alpha1.SyncTarget, fieldManager string) (*SyncTargetApplyConfiguration, error) {
+	return extractSyncTarget(syncTarget, fieldManager, "status")
+}
+
+func extractSyncTarget(syncTarget *workloadv1alpha1.SyncTarget, fieldManager string, subresource string) (*SyncTargetApplyConfiguration, error) {
+	b := &SyncTargetApplyConfiguration{}
+	err := managedfields.ExtractInto(syncTarget, internal.Parser().Type("com.github.kcp-dev.kcp.sdk.apis.workload.v1alpha1.SyncTarget"), fieldManager, b, subresource)
+	if err != nil {
+		return nil, err
+	}
+	b.WithName(syncTarget.Name)
+
+	b.WithKind("SyncTarget")
+	b.WithAPIVersion("workload.kcp.io/v1alpha1")
+	return b, nil
+}
+
 // WithKind sets the Kind field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Kind field is set to the value of the last call.
@@ -39,6 +39,7 @@ type ApisV1alpha1ClusterInterface interface {
 	APIExportEndpointSlicesClusterGetter
 	APIResourceSchemasClusterGetter
 	APIConversionsClusterGetter
+	DefaultAPIBindingsClusterGetter
 }
 
 type ApisV1alpha1ClusterScoper interface {
@@ -76,6 +77,10 @@ func (c *ApisV1alpha1ClusterClient) APIConversions() APIConversionClusterInterfa
 	return &aPIConversionsClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *ApisV1alpha1ClusterClient) DefaultAPIBindings() DefaultAPIBindingClusterInterface {
+	return &defaultAPIBindingsClusterInterface{clientCache: c.clientCache}
+}
+
 // NewForConfig creates a new ApisV1alpha1ClusterClient for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
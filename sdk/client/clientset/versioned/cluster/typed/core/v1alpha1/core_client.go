@@ -35,6 +35,7 @@ import (
 type CoreV1alpha1ClusterInterface interface {
 	CoreV1alpha1ClusterScoper
 	LogicalClustersClusterGetter
+	ReplicationConfigsClusterGetter
 	ShardsClusterGetter
 }
 
@@ -57,6 +58,10 @@ func (c *CoreV1alpha1ClusterClient) LogicalClusters() LogicalClusterClusterInter
 	return &logicalClustersClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *CoreV1alpha1ClusterClient) ReplicationConfigs() ReplicationConfigClusterInterface {
+	return &replicationConfigsClusterInterface{clientCache: c.clientCache}
+}
+
 func (c *CoreV1alpha1ClusterClient) Shards() ShardClusterInterface {
 	return &shardsClusterInterface{clientCache: c.clientCache}
 }
@@ -48,6 +48,10 @@ func (c *CoreV1alpha1ClusterClient) LogicalClusters() kcpcorev1alpha1.LogicalClu
 	return &logicalClustersClusterClient{Fake: c.Fake}
 }
 
+func (c *CoreV1alpha1ClusterClient) ReplicationConfigs() kcpcorev1alpha1.ReplicationConfigClusterInterface {
+	return &replicationConfigsClusterClient{Fake: c.Fake}
+}
+
 func (c *CoreV1alpha1ClusterClient) Shards() kcpcorev1alpha1.ShardClusterInterface {
 	return &shardsClusterClient{Fake: c.Fake}
 }
@@ -68,6 +72,10 @@ func (c *CoreV1alpha1Client) LogicalClusters() corev1alpha1.LogicalClusterInterf
 	return &logicalClustersClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
 }
 
+func (c *CoreV1alpha1Client) ReplicationConfigs() corev1alpha1.ReplicationConfigInterface {
+	return &replicationConfigsClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
+}
+
 func (c *CoreV1alpha1Client) Shards() corev1alpha1.ShardInterface {
 	return &shardsClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
 }
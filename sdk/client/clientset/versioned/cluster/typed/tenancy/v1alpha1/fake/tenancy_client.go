@@ -48,6 +48,18 @@ func (c *TenancyV1alpha1ClusterClient) Workspaces() kcptenancyv1alpha1.Workspace
 	return &workspacesClusterClient{Fake: c.Fake}
 }
 
+func (c *TenancyV1alpha1ClusterClient) WorkspaceLifecycleWebhooks() kcptenancyv1alpha1.WorkspaceLifecycleWebhookClusterInterface {
+	return &workspaceLifecycleWebhooksClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceRequests() kcptenancyv1alpha1.WorkspaceRequestClusterInterface {
+	return &workspaceRequestsClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceTemplates() kcptenancyv1alpha1.WorkspaceTemplateClusterInterface {
+	return &workspaceTemplatesClusterClient{Fake: c.Fake}
+}
+
 func (c *TenancyV1alpha1ClusterClient) WorkspaceTypes() kcptenancyv1alpha1.WorkspaceTypeClusterInterface {
 	return &workspaceTypesClusterClient{Fake: c.Fake}
 }
@@ -68,6 +80,18 @@ func (c *TenancyV1alpha1Client) Workspaces() tenancyv1alpha1.WorkspaceInterface
 	return &workspacesClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
 }
 
+func (c *TenancyV1alpha1Client) WorkspaceLifecycleWebhooks() tenancyv1alpha1.WorkspaceLifecycleWebhookInterface {
+	return &workspaceLifecycleWebhooksClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceRequests() tenancyv1alpha1.WorkspaceRequestInterface {
+	return &workspaceRequestsClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceTemplates() tenancyv1alpha1.WorkspaceTemplateInterface {
+	return &workspaceTemplatesClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
+}
+
 func (c *TenancyV1alpha1Client) WorkspaceTypes() tenancyv1alpha1.WorkspaceTypeInterface {
 	return &workspaceTypesClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
 }
@@ -35,6 +35,9 @@ import (
 type TenancyV1alpha1ClusterInterface interface {
 	TenancyV1alpha1ClusterScoper
 	WorkspacesClusterGetter
+	WorkspaceLifecycleWebhooksClusterGetter
+	WorkspaceRequestsClusterGetter
+	WorkspaceTemplatesClusterGetter
 	WorkspaceTypesClusterGetter
 }
 
@@ -57,6 +60,18 @@ func (c *TenancyV1alpha1ClusterClient) Workspaces() WorkspaceClusterInterface {
 	return &workspacesClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *TenancyV1alpha1ClusterClient) WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookClusterInterface {
+	return &workspaceLifecycleWebhooksClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceRequests() WorkspaceRequestClusterInterface {
+	return &workspaceRequestsClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceTemplates() WorkspaceTemplateClusterInterface {
+	return &workspaceTemplatesClusterInterface{clientCache: c.clientCache}
+}
+
 func (c *TenancyV1alpha1ClusterClient) WorkspaceTypes() WorkspaceTypeClusterInterface {
 	return &workspaceTypesClusterInterface{clientCache: c.clientCache}
 }
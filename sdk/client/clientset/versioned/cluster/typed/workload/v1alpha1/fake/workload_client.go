@@ -48,6 +48,10 @@ func (c *WorkloadV1alpha1ClusterClient) SyncTargets() kcpworkloadv1alpha1.SyncTa
 	return &syncTargetsClusterClient{Fake: c.Fake}
 }
 
+func (c *WorkloadV1alpha1ClusterClient) SyncTargetPools() kcpworkloadv1alpha1.SyncTargetPoolClusterInterface {
+	return &syncTargetPoolsClusterClient{Fake: c.Fake}
+}
+
 var _ workloadv1alpha1.WorkloadV1alpha1Interface = (*WorkloadV1alpha1Client)(nil)
 
 type WorkloadV1alpha1Client struct {
@@ -63,3 +67,7 @@ func (c *WorkloadV1alpha1Client) RESTClient() rest.Interface {
 func (c *WorkloadV1alpha1Client) SyncTargets() workloadv1alpha1.SyncTargetInterface {
 	return &syncTargetsClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
 }
+
+func (c *WorkloadV1alpha1Client) SyncTargetPools() workloadv1alpha1.SyncTargetPoolInterface {
+	return &syncTargetPoolsClient{Fake: c.Fake, ClusterPath: c.ClusterPath}
+}
@@ -35,6 +35,7 @@ import (
 type WorkloadV1alpha1ClusterInterface interface {
 	WorkloadV1alpha1ClusterScoper
 	SyncTargetsClusterGetter
+	SyncTargetPoolsClusterGetter
 }
 
 type WorkloadV1alpha1ClusterScoper interface {
@@ -56,6 +57,10 @@ func (c *WorkloadV1alpha1ClusterClient) SyncTargets() SyncTargetClusterInterface
 	return &syncTargetsClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *WorkloadV1alpha1ClusterClient) SyncTargetPools() SyncTargetPoolClusterInterface {
+	return &syncTargetPoolsClusterInterface{clientCache: c.clientCache}
+}
+
 // NewForConfig creates a new WorkloadV1alpha1ClusterClient for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
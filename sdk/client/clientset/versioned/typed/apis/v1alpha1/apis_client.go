@@ -31,6 +31,7 @@ type ApisV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	APIBindingsGetter
 	APIConversionsGetter
+	DefaultAPIBindingsGetter
 	APIExportsGetter
 	APIExportEndpointSlicesGetter
 	APIResourceSchemasGetter
@@ -49,6 +50,10 @@ func (c *ApisV1alpha1Client) APIConversions() APIConversionInterface {
 	return newAPIConversions(c)
 }
 
+func (c *ApisV1alpha1Client) DefaultAPIBindings() DefaultAPIBindingInterface {
+	return newDefaultAPIBindings(c)
+}
+
 func (c *ApisV1alpha1Client) APIExports() APIExportInterface {
 	return newAPIExports(c)
 }
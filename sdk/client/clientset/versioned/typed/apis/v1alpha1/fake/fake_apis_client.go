@@ -37,6 +37,10 @@ func (c *FakeApisV1alpha1) APIConversions() v1alpha1.APIConversionInterface {
 	return &FakeAPIConversions{c}
 }
 
+func (c *FakeApisV1alpha1) DefaultAPIBindings() v1alpha1.DefaultAPIBindingInterface {
+	return &FakeDefaultAPIBindings{c}
+}
+
 func (c *FakeApisV1alpha1) APIExports() v1alpha1.APIExportInterface {
 	return &FakeAPIExports{c}
 }
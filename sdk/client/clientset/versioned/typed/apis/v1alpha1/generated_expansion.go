@@ -21,6 +21,7 @@ package v1alpha1
 type APIBindingExpansion interface{}
 
 type APIConversionExpansion interface{}
+type DefaultAPIBindingExpansion interface{}
 
 type APIExportExpansion interface{}
 
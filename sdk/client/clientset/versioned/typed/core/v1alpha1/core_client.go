@@ -30,6 +30,7 @@ import (
 type CoreV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	LogicalClustersGetter
+	ReplicationConfigsGetter
 	ShardsGetter
 }
 
@@ -42,6 +43,10 @@ func (c *CoreV1alpha1Client) LogicalClusters() LogicalClusterInterface {
 	return newLogicalClusters(c)
 }
 
+func (c *CoreV1alpha1Client) ReplicationConfigs() ReplicationConfigInterface {
+	return newReplicationConfigs(c)
+}
+
 func (c *CoreV1alpha1Client) Shards() ShardInterface {
 	return newShards(c)
 }
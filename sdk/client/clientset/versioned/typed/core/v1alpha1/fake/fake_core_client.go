@@ -33,6 +33,10 @@ func (c *FakeCoreV1alpha1) LogicalClusters() v1alpha1.LogicalClusterInterface {
 	return &FakeLogicalClusters{c}
 }
 
+func (c *FakeCoreV1alpha1) ReplicationConfigs() v1alpha1.ReplicationConfigInterface {
+	return &FakeReplicationConfigs{c}
+}
+
 func (c *FakeCoreV1alpha1) Shards() v1alpha1.ShardInterface {
 	return &FakeShards{c}
 }
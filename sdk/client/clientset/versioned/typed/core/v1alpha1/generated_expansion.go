@@ -20,4 +20,6 @@ package v1alpha1
 
 type LogicalClusterExpansion interface{}
 
+type ReplicationConfigExpansion interface{}
+
 type ShardExpansion interface{}
@@ -33,6 +33,18 @@ func (c *FakeTenancyV1alpha1) Workspaces() v1alpha1.WorkspaceInterface {
 	return &FakeWorkspaces{c}
 }
 
+func (c *FakeTenancyV1alpha1) WorkspaceLifecycleWebhooks() v1alpha1.WorkspaceLifecycleWebhookInterface {
+	return &FakeWorkspaceLifecycleWebhooks{c}
+}
+
+func (c *FakeTenancyV1alpha1) WorkspaceRequests() v1alpha1.WorkspaceRequestInterface {
+	return &FakeWorkspaceRequests{c}
+}
+
+func (c *FakeTenancyV1alpha1) WorkspaceTemplates() v1alpha1.WorkspaceTemplateInterface {
+	return &FakeWorkspaceTemplates{c}
+}
+
 func (c *FakeTenancyV1alpha1) WorkspaceTypes() v1alpha1.WorkspaceTypeInterface {
 	return &FakeWorkspaceTypes{c}
 }
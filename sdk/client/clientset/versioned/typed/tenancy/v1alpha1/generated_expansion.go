@@ -20,4 +20,10 @@ package v1alpha1
 
 type WorkspaceExpansion interface{}
 
+type WorkspaceLifecycleWebhookExpansion interface{}
+
+type WorkspaceRequestExpansion interface{}
+
+type WorkspaceTemplateExpansion interface{}
+
 type WorkspaceTypeExpansion interface{}
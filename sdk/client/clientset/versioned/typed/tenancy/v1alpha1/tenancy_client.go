@@ -30,6 +30,9 @@ import (
 type TenancyV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	WorkspacesGetter
+	WorkspaceLifecycleWebhooksGetter
+	WorkspaceRequestsGetter
+	WorkspaceTemplatesGetter
 	WorkspaceTypesGetter
 }
 
@@ -42,6 +45,18 @@ func (c *TenancyV1alpha1Client) Workspaces() WorkspaceInterface {
 	return newWorkspaces(c)
 }
 
+func (c *TenancyV1alpha1Client) WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookInterface {
+	return newWorkspaceLifecycleWebhooks(c)
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceRequests() WorkspaceRequestInterface {
+	return newWorkspaceRequests(c)
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceTemplates() WorkspaceTemplateInterface {
+	return newWorkspaceTemplates(c)
+}
+
 func (c *TenancyV1alpha1Client) WorkspaceTypes() WorkspaceTypeInterface {
 	return newWorkspaceTypes(c)
 }
@@ -30,6 +30,7 @@ import (
 type WorkloadV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	SyncTargetsGetter
+	SyncTargetPoolsGetter
 }
 
 // WorkloadV1alpha1Client is used to interact with features provided by the workload.kcp.io group.
@@ -41,6 +42,10 @@ func (c *WorkloadV1alpha1Client) SyncTargets() SyncTargetInterface {
 	return newSyncTargets(c)
 }
 
+func (c *WorkloadV1alpha1Client) SyncTargetPools() SyncTargetPoolInterface {
+	return newSyncTargetPools(c)
+}
+
 // NewForConfig creates a new WorkloadV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// continuePrefix distinguishes continue tokens of aggregated lists from those of a single shard, and
+// from consistency tokens.
+const continuePrefix = "kcp-continue.v1."
+
+// continueToken is the continue token of a paginated aggregated list. Shards are listed one after
+// another in the order of their names.
+type continueToken struct {
+	// ResourceVersions are the resource versions of the shards listed so far.
+	ResourceVersions Token `json:"rv"`
+	// Shard is the shard to continue with.
+	Shard string `json:"shard"`
+	// Continue is the continue token of Shard. Empty if Shard has not been listed yet.
+	Continue string `json:"continue,omitempty"`
+}
+
+// ListFunc lists the objects of a single shard.
+type ListFunc func(ctx context.Context, shard string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+
+// List lists the objects of all given shards and returns them as one list, whose resource version is
+// the Token of the resource versions of all shards.
+//
+// The resource version in opts can be a Token, in which case every shard is listed at its resource
+// version in the token, and shards missing in the token at the most recent resource version.
+// The resource versions "" and "0" apply to all shards. Other resource versions are only comparable
+// within a single shard and are rejected if there is more than one shard.
+//
+// With opts.Limit, the shards are listed one after another and the list is continued on the
+// shard where the previous page ended. Every shard is listed consistently at the resource version
+// of its first page.
+func List(ctx context.Context, shards []string, opts metav1.ListOptions, list ListFunc) (*unstructured.UnstructuredList, error) {
+	shards = append([]string(nil), shards...)
+	sort.Strings(shards)
+
+	rvs := Token{}
+	start, shardContinue := 0, ""
+	switch {
+	case opts.Continue != "":
+		ct, err := parseContinue(opts.Continue)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		start = sort.SearchStrings(shards, ct.Shard)
+		if start == len(shards) || shards[start] != ct.Shard {
+			return nil, apierrors.NewResourceExpired(fmt.Sprintf("shard %q of the continue token does not exist anymore", ct.Shard))
+		}
+		rvs, shardContinue = ct.ResourceVersions, ct.Continue
+		if rvs == nil {
+			rvs = Token{}
+		}
+	case IsToken(opts.ResourceVersion):
+		t, err := ParseToken(opts.ResourceVersion)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		rvs = t
+	case opts.ResourceVersion == "", opts.ResourceVersion == "0", len(shards) <= 1:
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("resource version %q is not a consistency token, but the list spans %d shards", opts.ResourceVersion, len(shards)))
+	}
+
+	ret := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	remaining := opts.Limit
+	for i := start; i < len(shards); i++ {
+		shard := shards[i]
+
+		shardOpts := opts
+		shardOpts.Continue = ""
+		switch {
+		case i == start && shardContinue != "":
+			// the resource version is encoded in the continue token of the shard
+			shardOpts.Continue = shardContinue
+			shardOpts.ResourceVersion, shardOpts.ResourceVersionMatch = "", ""
+		case rvs[shard] != "":
+			shardOpts.ResourceVersion = rvs[shard]
+		case IsToken(opts.ResourceVersion) || opts.Continue != "":
+			shardOpts.ResourceVersion, shardOpts.ResourceVersionMatch = "", ""
+		}
+		if opts.Limit > 0 {
+			shardOpts.Limit = remaining
+		}
+
+		shardList, err := list(ctx, shard, shardOpts)
+		if err != nil {
+			return nil, err
+		}
+		if ret.GetAPIVersion() == "" {
+			ret.SetAPIVersion(shardList.GetAPIVersion())
+			ret.SetKind(shardList.GetKind())
+		}
+		ret.Items = append(ret.Items, shardList.Items...)
+		rvs[shard] = shardList.GetResourceVersion()
+
+		if opts.Limit <= 0 {
+			continue
+		}
+		remaining -= int64(len(shardList.Items))
+		if c := shardList.GetContinue(); c != "" {
+			ret.SetContinue(continueToken{ResourceVersions: rvs, Shard: shard, Continue: c}.String())
+			break
+		}
+		if remaining <= 0 && i+1 < len(shards) {
+			ret.SetContinue(continueToken{ResourceVersions: rvs, Shard: shards[i+1]}.String())
+			break
+		}
+	}
+	ret.SetResourceVersion(rvs.String())
+
+	return ret, nil
+}
+
+func (c continueToken) String() string {
+	return continuePrefix + encode(c)
+}
+
+func parseContinue(s string) (*continueToken, error) {
+	if !strings.HasPrefix(s, continuePrefix) {
+		return nil, fmt.Errorf("%q is not a continue token of an aggregated list", s)
+	}
+	var ct continueToken
+	if err := decode(strings.TrimPrefix(s, continuePrefix), &ct); err != nil {
+		return nil, fmt.Errorf("invalid continue token %q: %w", s, err)
+	}
+	return &ct, nil
+}
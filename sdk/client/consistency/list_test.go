@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// fakeShards serves lists of ConfigMaps with names <shard>-<i>, paginated with the index as continue token.
+type fakeShards struct {
+	objects map[string]int
+	rvs     map[string]string
+	// requests records the list options per shard.
+	requests map[string][]metav1.ListOptions
+}
+
+func (f *fakeShards) list(_ context.Context, shard string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	f.requests[shard] = append(f.requests[shard], opts)
+
+	start := 0
+	if opts.Continue != "" {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	end := f.objects[shard]
+	if opts.Limit > 0 && int64(end-start) > opts.Limit {
+		end = start + int(opts.Limit)
+	}
+
+	l := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	l.SetAPIVersion("v1")
+	l.SetKind("ConfigMapList")
+	l.SetResourceVersion(f.rvs[shard])
+	for i := start; i < end; i++ {
+		obj := unstructured.Unstructured{}
+		obj.SetName(shard + "-" + strconv.Itoa(i))
+		l.Items = append(l.Items, obj)
+	}
+	if end < f.objects[shard] {
+		l.SetContinue(strconv.Itoa(end))
+	}
+	return l, nil
+}
+
+func names(l *unstructured.UnstructuredList) []string {
+	var ret []string
+	for _, obj := range l.Items {
+		ret = append(ret, obj.GetName())
+	}
+	return ret
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	newShards := func() *fakeShards {
+		return &fakeShards{
+			objects:  map[string]int{"amber": 3, "sapphire": 2},
+			rvs:      map[string]string{"amber": "100", "sapphire": "7"},
+			requests: map[string][]metav1.ListOptions{},
+		}
+	}
+	shards := []string{"sapphire", "amber"}
+
+	t.Run("all shards are aggregated with a token of their resource versions", func(t *testing.T) {
+		f := newShards()
+		l, err := List(ctx, shards, metav1.ListOptions{}, f.list)
+		require.NoError(t, err)
+		require.Equal(t, []string{"amber-0", "amber-1", "amber-2", "sapphire-0", "sapphire-1"}, names(l))
+		require.Equal(t, "ConfigMapList", l.GetKind())
+		require.Equal(t, Token{"amber": "100", "sapphire": "7"}.String(), l.GetResourceVersion())
+		require.Empty(t, l.GetContinue())
+	})
+
+	t.Run("a token resumes every shard at its resource version", func(t *testing.T) {
+		f := newShards()
+		_, err := List(ctx, shards, metav1.ListOptions{ResourceVersion: Token{"amber": "90"}.String(), ResourceVersionMatch: metav1.ResourceVersionMatchExact}, f.list)
+		require.NoError(t, err)
+		require.Equal(t, "90", f.requests["amber"][0].ResourceVersion)
+		require.Equal(t, metav1.ResourceVersionMatchExact, f.requests["amber"][0].ResourceVersionMatch)
+		require.Empty(t, f.requests["sapphire"][0].ResourceVersion, "shards missing in the token are listed at the most recent resource version")
+		require.Empty(t, f.requests["sapphire"][0].ResourceVersionMatch)
+	})
+
+	t.Run("plain resource versions are rejected for multiple shards", func(t *testing.T) {
+		f := newShards()
+		_, err := List(ctx, shards, metav1.ListOptions{ResourceVersion: "100"}, f.list)
+		require.True(t, apierrors.IsBadRequest(err))
+
+		_, err = List(ctx, []string{"amber"}, metav1.ListOptions{ResourceVersion: "100"}, f.list)
+		require.NoError(t, err)
+	})
+
+	t.Run("pages continue across shards", func(t *testing.T) {
+		f := newShards()
+		var got []string
+		opts := metav1.ListOptions{Limit: 2}
+		pages := 0
+		for {
+			l, err := List(ctx, shards, opts, f.list)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(l.Items), 2)
+			got = append(got, names(l)...)
+			pages++
+			if l.GetContinue() == "" {
+				require.Equal(t, Token{"amber": "100", "sapphire": "7"}.String(), l.GetResourceVersion())
+				break
+			}
+			require.False(t, IsToken(l.GetContinue()), "continue tokens are not consistency tokens")
+			opts.Continue = l.GetContinue()
+		}
+		require.Equal(t, 3, pages)
+		require.Equal(t, []string{"amber-0", "amber-1", "amber-2", "sapphire-0", "sapphire-1"}, got)
+	})
+
+	t.Run("continue on a removed shard expires", func(t *testing.T) {
+		f := newShards()
+		_, err := List(ctx, []string{"amber"}, metav1.ListOptions{Continue: continueToken{Shard: "sapphire"}.String()}, f.list)
+		require.True(t, apierrors.IsResourceExpired(err))
+	})
+
+	t.Run("a token is not a continue token", func(t *testing.T) {
+		f := newShards()
+		_, err := List(ctx, shards, metav1.ListOptions{Continue: Token{"amber": "100"}.String()}, f.list)
+		require.True(t, apierrors.IsBadRequest(err))
+	})
+}
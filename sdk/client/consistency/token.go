@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consistency implements consistency tokens for reads spanning multiple shards.
+//
+// Resource versions are only comparable within one shard. A Token composes the resource versions
+// observed on every shard of an aggregated read into one opaque string, which is returned as the
+// resource version of the aggregated list, or of the bookmarks of the aggregated watch, and can be
+// passed back to List or Watch to resume from the same point on every shard.
+package consistency
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenPrefix distinguishes tokens from plain resource versions of a single shard.
+const tokenPrefix = "kcp-rv.v1."
+
+// Token maps shard names to the resource version observed on that shard.
+type Token map[string]string
+
+// IsToken returns true if the given resource version is a Token, and not the resource version of
+// a single shard.
+func IsToken(resourceVersion string) bool {
+	return strings.HasPrefix(resourceVersion, tokenPrefix)
+}
+
+// ParseToken decodes a Token from its String representation.
+func ParseToken(s string) (Token, error) {
+	if !IsToken(s) {
+		return nil, fmt.Errorf("%q is not a consistency token", s)
+	}
+	t := Token{}
+	if err := decode(strings.TrimPrefix(s, tokenPrefix), &t); err != nil {
+		return nil, fmt.Errorf("invalid consistency token %q: %w", s, err)
+	}
+	return t, nil
+}
+
+// String encodes the token. The encoding is stable, i.e. equal tokens have equal encodings.
+func (t Token) String() string {
+	if t == nil {
+		t = Token{}
+	}
+	return tokenPrefix + encode(t)
+}
+
+// Merge returns a new token with the resource versions of both tokens. For shards in both tokens,
+// the later resource version wins. Resource versions that are not numbers, and hence not
+// comparable, are taken from other.
+func (t Token) Merge(other Token) Token {
+	ret := make(Token, len(t)+len(other))
+	for shard, rv := range t {
+		ret[shard] = rv
+	}
+	for shard, rv := range other {
+		if existing, ok := ret[shard]; ok && !later(rv, existing) {
+			continue
+		}
+		ret[shard] = rv
+	}
+	return ret
+}
+
+// later returns true if a is later than b, or if they are not comparable.
+func later(a, b string) bool {
+	x, errA := strconv.ParseUint(a, 10, 64)
+	y, errB := strconv.ParseUint(b, 10, 64)
+	if errA != nil || errB != nil {
+		return true
+	}
+	return x > y
+}
+
+// encode marshals v to JSON and encodes it URL-safe. encoding/json sorts map keys, making the
+// encoding stable.
+func encode(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		// cannot happen for the types of this package
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+func decode(s string, v interface{}) error {
+	bs, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	token := Token{"amber": "42", "sapphire": "7"}
+	s := token.String()
+	require.True(t, IsToken(s))
+	require.False(t, IsToken("42"))
+	require.Equal(t, s, Token{"sapphire": "7", "amber": "42"}.String(), "encoding must be stable")
+
+	parsed, err := ParseToken(s)
+	require.NoError(t, err)
+	require.Equal(t, token, parsed)
+
+	_, err = ParseToken("42")
+	require.Error(t, err)
+	_, err = ParseToken(tokenPrefix + "!!!")
+	require.Error(t, err)
+}
+
+func TestTokenMerge(t *testing.T) {
+	tests := map[string]struct {
+		a, b Token
+		want Token
+	}{
+		"disjoint": {
+			a:    Token{"amber": "1"},
+			b:    Token{"sapphire": "2"},
+			want: Token{"amber": "1", "sapphire": "2"},
+		},
+		"later wins": {
+			a:    Token{"amber": "10", "sapphire": "2"},
+			b:    Token{"amber": "9", "sapphire": "3"},
+			want: Token{"amber": "10", "sapphire": "3"},
+		},
+		"not comparable": {
+			a:    Token{"amber": "10"},
+			b:    Token{"amber": "x"},
+			want: Token{"amber": "x"},
+		},
+		"nil": {
+			b:    Token{"amber": "1"},
+			want: Token{"amber": "1"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.a.Merge(tt.b))
+		})
+	}
+}
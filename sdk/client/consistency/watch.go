@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// WatchFunc watches the objects of a single shard.
+type WatchFunc func(ctx context.Context, shard string, opts metav1.ListOptions) (watch.Interface, error)
+
+// Watch watches the objects of all given shards and merges their events into one watch.
+//
+// The resource version in opts can be a Token, e.g. the resource version of a list returned by
+// List, in which case every shard is watched from its resource version in the token, and shards
+// missing in the token from the most recent resource version. The resource versions "" and "0"
+// apply to all shards. Other resource versions are only comparable within a single shard and are
+// rejected if there is more than one shard.
+//
+// Events keep the resource versions of their shard. With opts.AllowWatchBookmarks, every event is
+// followed by a bookmark whose resource version is the Token of the resource versions of all shards
+// seen so far, i.e. a client, like a reflector, resumes from the last bookmark by passing its
+// resource version to List or Watch. Bookmarks of the shards are replaced by such bookmarks.
+//
+// The watch ends when the watch of any shard ends.
+func Watch(ctx context.Context, shards []string, opts metav1.ListOptions, watchFn WatchFunc) (watch.Interface, error) {
+	rvs := Token{}
+	switch {
+	case IsToken(opts.ResourceVersion):
+		t, err := ParseToken(opts.ResourceVersion)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		rvs = t
+	case opts.ResourceVersion == "", opts.ResourceVersion == "0", len(shards) <= 1:
+		for _, shard := range shards {
+			rvs[shard] = opts.ResourceVersion
+		}
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("resource version %q is not a consistency token, but the watch spans %d shards", opts.ResourceVersion, len(shards)))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &aggregatedWatch{
+		cancel:    cancel,
+		result:    make(chan watch.Event),
+		stopped:   make(chan struct{}),
+		bookmarks: opts.AllowWatchBookmarks,
+		rvs:       rvs,
+	}
+
+	watchers := make([]watch.Interface, 0, len(shards))
+	for _, shard := range shards {
+		shardOpts := opts
+		shardOpts.ResourceVersion = rvs[shard]
+		shardWatch, err := watchFn(ctx, shard, shardOpts)
+		if err != nil {
+			cancel()
+			for _, sw := range watchers {
+				sw.Stop()
+			}
+			return nil, err
+		}
+		watchers = append(watchers, shardWatch)
+	}
+
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(shard string, shardWatch watch.Interface) {
+			defer wg.Done()
+			defer w.Stop()
+			defer shardWatch.Stop()
+			w.forward(shard, shardWatch)
+		}(shard, watchers[i])
+	}
+	go func() {
+		wg.Wait()
+		close(w.result)
+	}()
+
+	return w, nil
+}
+
+// aggregatedWatch merges the watches of multiple shards.
+type aggregatedWatch struct {
+	cancel    context.CancelFunc
+	result    chan watch.Event
+	stopOnce  sync.Once
+	stopped   chan struct{}
+	bookmarks bool
+
+	// lock serializes the events of all shards, such that every bookmark includes the resource
+	// versions of all events sent before it.
+	lock sync.Mutex
+	rvs  Token
+}
+
+var _ watch.Interface = &aggregatedWatch{}
+
+func (w *aggregatedWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *aggregatedWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+		w.cancel()
+	})
+}
+
+// forward sends the events of the given shard until its watch ends or the aggregated watch is stopped.
+func (w *aggregatedWatch) forward(shard string, shardWatch watch.Interface) {
+	for {
+		select {
+		case <-w.stopped:
+			return
+		case evt, ok := <-shardWatch.ResultChan():
+			if !ok {
+				return
+			}
+			if !w.send(shard, evt) {
+				return
+			}
+		}
+	}
+}
+
+// send sends the given event of the given shard, followed by a bookmark if requested. It returns
+// false if the aggregated watch was stopped.
+func (w *aggregatedWatch) send(shard string, evt watch.Event) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if evt.Type == watch.Error {
+		return w.sendEvent(evt)
+	}
+
+	obj, err := meta.Accessor(evt.Object)
+	if err != nil {
+		return w.sendEvent(watch.Event{Type: watch.Error, Object: &apierrors.NewInternalError(fmt.Errorf("unexpected object of shard %q: %w", shard, err)).ErrStatus})
+	}
+	w.rvs = w.rvs.Merge(Token{shard: obj.GetResourceVersion()})
+
+	if evt.Type != watch.Bookmark && !w.sendEvent(evt) {
+		return false
+	}
+	if !w.bookmarks {
+		return true
+	}
+	bookmark := &unstructured.Unstructured{}
+	bookmark.SetGroupVersionKind(evt.Object.GetObjectKind().GroupVersionKind())
+	bookmark.SetResourceVersion(w.rvs.String())
+	return w.sendEvent(watch.Event{Type: watch.Bookmark, Object: bookmark})
+}
+
+func (w *aggregatedWatch) sendEvent(evt watch.Event) bool {
+	select {
+	case <-w.stopped:
+		return false
+	case w.result <- evt:
+		return true
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// fakeWatches serves a fake watch per shard, and records the watch options per shard.
+type fakeWatches struct {
+	lock     sync.Mutex
+	watches  map[string]*watch.FakeWatcher
+	requests map[string][]metav1.ListOptions
+}
+
+func newFakeWatches() *fakeWatches {
+	return &fakeWatches{watches: map[string]*watch.FakeWatcher{}, requests: map[string][]metav1.ListOptions{}}
+}
+
+func (f *fakeWatches) watch(_ context.Context, shard string, opts metav1.ListOptions) (watch.Interface, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests[shard] = append(f.requests[shard], opts)
+	f.watches[shard] = watch.NewFake()
+	return f.watches[shard], nil
+}
+
+func configMap(name, rv string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	obj.SetResourceVersion(rv)
+	return obj
+}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	shards := []string{"amber", "sapphire"}
+
+	t.Run("a listed token round-trips through the watch", func(t *testing.T) {
+		l, err := List(ctx, shards, metav1.ListOptions{}, (&fakeShards{
+			objects:  map[string]int{"amber": 1, "sapphire": 1},
+			rvs:      map[string]string{"amber": "100", "sapphire": "7"},
+			requests: map[string][]metav1.ListOptions{},
+		}).list)
+		require.NoError(t, err)
+
+		f := newFakeWatches()
+		w, err := Watch(ctx, shards, metav1.ListOptions{ResourceVersion: l.GetResourceVersion(), AllowWatchBookmarks: true}, f.watch)
+		require.NoError(t, err)
+		defer w.Stop()
+		require.Equal(t, "100", f.requests["amber"][0].ResourceVersion)
+		require.Equal(t, "7", f.requests["sapphire"][0].ResourceVersion)
+
+		t.Log("Events keep the resource version of their shard, and are followed by a bookmark with the token")
+		go f.watches["sapphire"].Modify(configMap("sapphire-0", "8"))
+		evt := <-w.ResultChan()
+		require.Equal(t, watch.Modified, evt.Type)
+		require.Equal(t, "8", evt.Object.(*unstructured.Unstructured).GetResourceVersion())
+		evt = <-w.ResultChan()
+		require.Equal(t, watch.Bookmark, evt.Type)
+		require.Equal(t, "ConfigMap", evt.Object.GetObjectKind().GroupVersionKind().Kind)
+		require.Equal(t, Token{"amber": "100", "sapphire": "8"}.String(), evt.Object.(*unstructured.Unstructured).GetResourceVersion())
+
+		t.Log("Bookmarks of the shards are replaced by bookmarks with the token")
+		go f.watches["amber"].Action(watch.Bookmark, configMap("", "105"))
+		evt = <-w.ResultChan()
+		require.Equal(t, watch.Bookmark, evt.Type)
+		token := evt.Object.(*unstructured.Unstructured).GetResourceVersion()
+		require.Equal(t, Token{"amber": "105", "sapphire": "8"}.String(), token)
+
+		t.Log("The token of the last bookmark resumes every shard")
+		resumed := newFakeWatches()
+		w2, err := Watch(ctx, shards, metav1.ListOptions{ResourceVersion: token}, resumed.watch)
+		require.NoError(t, err)
+		defer w2.Stop()
+		require.Equal(t, "105", resumed.requests["amber"][0].ResourceVersion)
+		require.Equal(t, "8", resumed.requests["sapphire"][0].ResourceVersion)
+
+		relisted := &fakeShards{objects: map[string]int{}, rvs: map[string]string{}, requests: map[string][]metav1.ListOptions{}}
+		_, err = List(ctx, shards, metav1.ListOptions{ResourceVersion: token}, relisted.list)
+		require.NoError(t, err)
+		require.Equal(t, "105", relisted.requests["amber"][0].ResourceVersion)
+		require.Equal(t, "8", relisted.requests["sapphire"][0].ResourceVersion)
+	})
+
+	t.Run("no bookmarks unless requested", func(t *testing.T) {
+		f := newFakeWatches()
+		w, err := Watch(ctx, shards, metav1.ListOptions{}, f.watch)
+		require.NoError(t, err)
+		defer w.Stop()
+
+		go func() {
+			f.watches["amber"].Add(configMap("amber-0", "101"))
+			f.watches["amber"].Delete(configMap("amber-0", "102"))
+		}()
+		require.Equal(t, watch.Added, (<-w.ResultChan()).Type)
+		require.Equal(t, watch.Deleted, (<-w.ResultChan()).Type)
+	})
+
+	t.Run("plain resource versions are rejected for multiple shards", func(t *testing.T) {
+		_, err := Watch(ctx, shards, metav1.ListOptions{ResourceVersion: "100"}, newFakeWatches().watch)
+		require.True(t, apierrors.IsBadRequest(err))
+
+		w, err := Watch(ctx, []string{"amber"}, metav1.ListOptions{ResourceVersion: "100"}, newFakeWatches().watch)
+		require.NoError(t, err)
+		w.Stop()
+	})
+
+	t.Run("the watch ends with the watch of any shard", func(t *testing.T) {
+		f := newFakeWatches()
+		w, err := Watch(ctx, shards, metav1.ListOptions{}, f.watch)
+		require.NoError(t, err)
+
+		f.watches["sapphire"].Stop()
+		for range w.ResultChan() {
+		}
+		require.True(t, f.watches["amber"].IsStopped())
+	})
+
+	t.Run("stopping ends the watches of all shards", func(t *testing.T) {
+		f := newFakeWatches()
+		w, err := Watch(ctx, shards, metav1.ListOptions{}, f.watch)
+		require.NoError(t, err)
+
+		w.Stop()
+		for range w.ResultChan() {
+		}
+		require.True(t, f.watches["amber"].IsStopped())
+		require.True(t, f.watches["sapphire"].IsStopped())
+	})
+}
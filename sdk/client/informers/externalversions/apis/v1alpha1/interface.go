@@ -36,6 +36,8 @@ type ClusterInterface interface {
 	APIResourceSchemas() APIResourceSchemaClusterInformer
 	// APIConversions returns a APIConversionClusterInformer
 	APIConversions() APIConversionClusterInformer
+	// DefaultAPIBindings returns a DefaultAPIBindingClusterInformer
+	DefaultAPIBindings() DefaultAPIBindingClusterInformer
 }
 
 type version struct {
@@ -73,6 +75,11 @@ func (v *version) APIConversions() APIConversionClusterInformer {
 	return &aPIConversionClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// DefaultAPIBindings returns a DefaultAPIBindingClusterInformer
+func (v *version) DefaultAPIBindings() DefaultAPIBindingClusterInformer {
+	return &defaultAPIBindingClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 type Interface interface {
 	// APIBindings returns a APIBindingInformer
 	APIBindings() APIBindingInformer
@@ -84,6 +91,8 @@ type Interface interface {
 	APIResourceSchemas() APIResourceSchemaInformer
 	// APIConversions returns a APIConversionInformer
 	APIConversions() APIConversionInformer
+	// DefaultAPIBindings returns a DefaultAPIBindingInformer
+	DefaultAPIBindings() DefaultAPIBindingInformer
 }
 
 type scopedVersion struct {
@@ -121,3 +130,8 @@ func (v *scopedVersion) APIResourceSchemas() APIResourceSchemaInformer {
 func (v *scopedVersion) APIConversions() APIConversionInformer {
 	return &aPIConversionScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// DefaultAPIBindings returns a DefaultAPIBindingInformer
+func (v *scopedVersion) DefaultAPIBindings() DefaultAPIBindingInformer {
+	return &defaultAPIBindingScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
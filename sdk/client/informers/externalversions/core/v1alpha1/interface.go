@@ -28,6 +28,8 @@ import (
 type ClusterInterface interface {
 	// LogicalClusters returns a LogicalClusterClusterInformer
 	LogicalClusters() LogicalClusterClusterInformer
+	// ReplicationConfigs returns a ReplicationConfigClusterInformer
+	ReplicationConfigs() ReplicationConfigClusterInformer
 	// Shards returns a ShardClusterInformer
 	Shards() ShardClusterInformer
 }
@@ -47,6 +49,11 @@ func (v *version) LogicalClusters() LogicalClusterClusterInformer {
 	return &logicalClusterClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ReplicationConfigs returns a ReplicationConfigClusterInformer
+func (v *version) ReplicationConfigs() ReplicationConfigClusterInformer {
+	return &replicationConfigClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Shards returns a ShardClusterInformer
 func (v *version) Shards() ShardClusterInformer {
 	return &shardClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -55,6 +62,8 @@ func (v *version) Shards() ShardClusterInformer {
 type Interface interface {
 	// LogicalClusters returns a LogicalClusterInformer
 	LogicalClusters() LogicalClusterInformer
+	// ReplicationConfigs returns a ReplicationConfigInformer
+	ReplicationConfigs() ReplicationConfigInformer
 	// Shards returns a ShardInformer
 	Shards() ShardInformer
 }
@@ -75,6 +84,11 @@ func (v *scopedVersion) LogicalClusters() LogicalClusterInformer {
 	return &logicalClusterScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ReplicationConfigs returns a ReplicationConfigInformer
+func (v *scopedVersion) ReplicationConfigs() ReplicationConfigInformer {
+	return &replicationConfigScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Shards returns a ShardInformer
 func (v *scopedVersion) Shards() ShardInformer {
 	return &shardScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -108,9 +108,13 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().APIResourceSchemas().Informer()}, nil
 	case apisv1alpha1.SchemeGroupVersion.WithResource("apiconversions"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().APIConversions().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("defaultapibindings"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().DefaultAPIBindings().Informer()}, nil
 	// Group=core.kcp.io, Version=V1alpha1
 	case corev1alpha1.SchemeGroupVersion.WithResource("logicalclusters"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().LogicalClusters().Informer()}, nil
+	case corev1alpha1.SchemeGroupVersion.WithResource("replicationconfigs"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().ReplicationConfigs().Informer()}, nil
 	case corev1alpha1.SchemeGroupVersion.WithResource("shards"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().Shards().Informer()}, nil
 	// Group=scheduling.kcp.io, Version=V1alpha1
@@ -121,6 +125,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=tenancy.kcp.io, Version=V1alpha1
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspaces"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().Workspaces().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacelifecyclewebhooks"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceLifecycleWebhooks().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacerequests"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceRequests().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetemplates"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceTemplates().Informer()}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetypes"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceTypes().Informer()}, nil
 	// Group=topology.kcp.io, Version=V1alpha1
@@ -131,6 +141,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=workload.kcp.io, Version=V1alpha1
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("synctargets"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().SyncTargets().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("synctargetpools"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().SyncTargetPools().Informer()}, nil
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -163,10 +175,16 @@ func (f *sharedScopedInformerFactory) ForResource(resource schema.GroupVersionRe
 	case apisv1alpha1.SchemeGroupVersion.WithResource("apiconversions"):
 		informer := f.Apis().V1alpha1().APIConversions().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("defaultapibindings"):
+		informer := f.Apis().V1alpha1().DefaultAPIBindings().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	// Group=core.kcp.io, Version=V1alpha1
 	case corev1alpha1.SchemeGroupVersion.WithResource("logicalclusters"):
 		informer := f.Core().V1alpha1().LogicalClusters().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case corev1alpha1.SchemeGroupVersion.WithResource("replicationconfigs"):
+		informer := f.Core().V1alpha1().ReplicationConfigs().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	case corev1alpha1.SchemeGroupVersion.WithResource("shards"):
 		informer := f.Core().V1alpha1().Shards().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
@@ -181,6 +199,15 @@ func (f *sharedScopedInformerFactory) ForResource(resource schema.GroupVersionRe
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspaces"):
 		informer := f.Tenancy().V1alpha1().Workspaces().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacelifecyclewebhooks"):
+		informer := f.Tenancy().V1alpha1().WorkspaceLifecycleWebhooks().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacerequests"):
+		informer := f.Tenancy().V1alpha1().WorkspaceRequests().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetemplates"):
+		informer := f.Tenancy().V1alpha1().WorkspaceTemplates().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacetypes"):
 		informer := f.Tenancy().V1alpha1().WorkspaceTypes().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
@@ -195,6 +222,9 @@ func (f *sharedScopedInformerFactory) ForResource(resource schema.GroupVersionRe
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("synctargets"):
 		informer := f.Workload().V1alpha1().SyncTargets().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("synctargetpools"):
+		informer := f.Workload().V1alpha1().SyncTargetPools().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
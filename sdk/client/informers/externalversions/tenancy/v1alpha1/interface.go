@@ -28,6 +28,12 @@ import (
 type ClusterInterface interface {
 	// Workspaces returns a WorkspaceClusterInformer
 	Workspaces() WorkspaceClusterInformer
+	// WorkspaceLifecycleWebhooks returns a WorkspaceLifecycleWebhookClusterInformer
+	WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookClusterInformer
+	// WorkspaceRequests returns a WorkspaceRequestClusterInformer
+	WorkspaceRequests() WorkspaceRequestClusterInformer
+	// WorkspaceTemplates returns a WorkspaceTemplateClusterInformer
+	WorkspaceTemplates() WorkspaceTemplateClusterInformer
 	// WorkspaceTypes returns a WorkspaceTypeClusterInformer
 	WorkspaceTypes() WorkspaceTypeClusterInformer
 }
@@ -47,6 +53,21 @@ func (v *version) Workspaces() WorkspaceClusterInformer {
 	return &workspaceClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// WorkspaceLifecycleWebhooks returns a WorkspaceLifecycleWebhookClusterInformer
+func (v *version) WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookClusterInformer {
+	return &workspaceLifecycleWebhookClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceRequests returns a WorkspaceRequestClusterInformer
+func (v *version) WorkspaceRequests() WorkspaceRequestClusterInformer {
+	return &workspaceRequestClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceTemplates returns a WorkspaceTemplateClusterInformer
+func (v *version) WorkspaceTemplates() WorkspaceTemplateClusterInformer {
+	return &workspaceTemplateClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // WorkspaceTypes returns a WorkspaceTypeClusterInformer
 func (v *version) WorkspaceTypes() WorkspaceTypeClusterInformer {
 	return &workspaceTypeClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -55,6 +76,12 @@ func (v *version) WorkspaceTypes() WorkspaceTypeClusterInformer {
 type Interface interface {
 	// Workspaces returns a WorkspaceInformer
 	Workspaces() WorkspaceInformer
+	// WorkspaceLifecycleWebhooks returns a WorkspaceLifecycleWebhookInformer
+	WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookInformer
+	// WorkspaceRequests returns a WorkspaceRequestInformer
+	WorkspaceRequests() WorkspaceRequestInformer
+	// WorkspaceTemplates returns a WorkspaceTemplateInformer
+	WorkspaceTemplates() WorkspaceTemplateInformer
 	// WorkspaceTypes returns a WorkspaceTypeInformer
 	WorkspaceTypes() WorkspaceTypeInformer
 }
@@ -75,6 +102,21 @@ func (v *scopedVersion) Workspaces() WorkspaceInformer {
 	return &workspaceScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// WorkspaceLifecycleWebhooks returns a WorkspaceLifecycleWebhookInformer
+func (v *scopedVersion) WorkspaceLifecycleWebhooks() WorkspaceLifecycleWebhookInformer {
+	return &workspaceLifecycleWebhookScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceRequests returns a WorkspaceRequestInformer
+func (v *scopedVersion) WorkspaceRequests() WorkspaceRequestInformer {
+	return &workspaceRequestScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceTemplates returns a WorkspaceTemplateInformer
+func (v *scopedVersion) WorkspaceTemplates() WorkspaceTemplateInformer {
+	return &workspaceTemplateScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // WorkspaceTypes returns a WorkspaceTypeInformer
 func (v *scopedVersion) WorkspaceTypes() WorkspaceTypeInformer {
 	return &workspaceTypeScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -28,6 +28,8 @@ import (
 type ClusterInterface interface {
 	// SyncTargets returns a SyncTargetClusterInformer
 	SyncTargets() SyncTargetClusterInformer
+	// SyncTargetPools returns a SyncTargetPoolClusterInformer
+	SyncTargetPools() SyncTargetPoolClusterInformer
 }
 
 type version struct {
@@ -45,9 +47,16 @@ func (v *version) SyncTargets() SyncTargetClusterInformer {
 	return &syncTargetClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// SyncTargetPools returns a SyncTargetPoolClusterInformer
+func (v *version) SyncTargetPools() SyncTargetPoolClusterInformer {
+	return &syncTargetPoolClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 type Interface interface {
 	// SyncTargets returns a SyncTargetInformer
 	SyncTargets() SyncTargetInformer
+	// SyncTargetPools returns a SyncTargetPoolInformer
+	SyncTargetPools() SyncTargetPoolInformer
 }
 
 type scopedVersion struct {
@@ -65,3 +74,8 @@ func NewScoped(f internalinterfaces.SharedScopedInformerFactory, namespace strin
 func (v *scopedVersion) SyncTargets() SyncTargetInformer {
 	return &syncTargetScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// SyncTargetPools returns a SyncTargetPoolInformer
+func (v *scopedVersion) SyncTargetPools() SyncTargetPoolInformer {
+	return &syncTargetPoolScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
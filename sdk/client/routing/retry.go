@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routing retries requests that reached a shard not hosting the requested logical cluster.
+//
+// A shard answers requests for logical clusters that live on another shard, e.g. while a workspace
+// moves between shards and the routing of the front-proxy is not updated yet, with a 421 Misdirected
+// Request response carrying the ShardMovedHeader. Unlike 429 Too Many Requests, client-go does not
+// retry these responses by itself. NewRetryingRoundTripper retries them, optionally after refreshing
+// the routing, so that workspace moves are invisible to clients.
+package routing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// ShardMovedHeader is set by shards on responses to requests for logical clusters they do not host.
+// Its value is the name of the shard hosting the logical cluster according to the responding shard.
+const ShardMovedHeader = "X-Kcp-Shard-Moved"
+
+const (
+	// maxRetries is the number of times a request is retried after a shard-moved response.
+	maxRetries = 5
+	// retryInterval is the wait before retrying a request after a shard-moved response.
+	retryInterval = time.Second
+)
+
+// RefreshFunc refreshes the routing of a request after it reached a shard not hosting the
+// requested logical cluster. It returns the request to retry, e.g. with the URL of the given shard
+// now hosting the logical cluster. Clients talking to the front-proxy do not have to refresh
+// anything, as the front-proxy routes the retried request.
+type RefreshFunc func(req *http.Request, shard string) (*http.Request, error)
+
+// NewShardURLRefresh returns a RefreshFunc for clients talking to shards directly. It sends
+// retried requests to the base URL of the shard now hosting the logical cluster, as returned by
+// shardURL, e.g. from the spec.baseURL of the Shard objects. Requests are retried unchanged
+// if the shard is not known.
+func NewShardURLRefresh(shardURL func(shard string) (baseURL string, found bool)) RefreshFunc {
+	return func(req *http.Request, shard string) (*http.Request, error) {
+		baseURL, found := shardURL(shard)
+		if shard == "" || !found {
+			return req, nil
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL %q of shard %q: %w", baseURL, shard, err)
+		}
+		req.URL.Scheme = u.Scheme
+		req.URL.Host = u.Host
+		req.Host = ""
+		return req, nil
+	}
+}
+
+// IsShardMoved returns true if the response was sent by a shard not hosting the requested logical
+// cluster, and the request should be retried. The returned shard is the one hosting the logical
+// cluster according to the responding shard.
+func IsShardMoved(resp *http.Response) (shard string, moved bool) {
+	if resp == nil || resp.StatusCode != http.StatusMisdirectedRequest {
+		return "", false
+	}
+	if _, moved = resp.Header[http.CanonicalHeaderKey(ShardMovedHeader)]; !moved {
+		return "", false
+	}
+	return resp.Header.Get(ShardMovedHeader), true
+}
+
+// NewRetryingRoundTripper returns a round tripper retrying requests that reached a shard not
+// hosting the requested logical cluster, waiting a second before each retry. The given refresh
+// function, if not nil, is called before every retry.
+//
+// Requests with a body are only retried if the body can be recreated, i.e. if GetBody is set. If
+// all retries fail, the last shard-moved response is returned.
+func NewRetryingRoundTripper(delegate http.RoundTripper, refresh RefreshFunc) http.RoundTripper {
+	return &retryingRoundTripper{delegate: delegate, refresh: refresh, sleep: sleep}
+}
+
+// WithRetryOnShardMoved returns a copy of the given config whose requests are retried after
+// reaching a shard not hosting the requested logical cluster, see NewRetryingRoundTripper.
+// Cluster-aware clients created from the config, e.g. by kcpdynamic.NewForConfig, pick it up.
+func WithRetryOnShardMoved(cfg *rest.Config, refresh RefreshFunc) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return NewRetryingRoundTripper(rt, refresh)
+	})
+	return cfg
+}
+
+type retryingRoundTripper struct {
+	delegate http.RoundTripper
+	refresh  RefreshFunc
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+var _ http.RoundTripper = &retryingRoundTripper{}
+
+func (rt *retryingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retries := 0; ; retries++ {
+		resp, err := rt.delegate.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		shard, moved := IsShardMoved(resp)
+		if !moved || retries == maxRetries || req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+
+		// drain the body, for the connection to be reused.
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()
+
+		if err := rt.sleep(req.Context(), retryInterval); err != nil {
+			return nil, err
+		}
+
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("failed to recreate the body of the request to retry it: %w", err)
+			}
+		}
+		if rt.refresh != nil {
+			if next, err = rt.refresh(next, shard); err != nil {
+				return nil, fmt.Errorf("failed to refresh the routing to shard %q: %w", shard, err)
+			}
+		}
+		req = next
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
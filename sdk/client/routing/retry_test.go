@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func response(status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader("body"))}
+}
+
+func shardMoved(shard string) *http.Response {
+	return response(http.StatusMisdirectedRequest, http.Header{ShardMovedHeader: []string{shard}})
+}
+
+func TestRetryingRoundTripper(t *testing.T) {
+	tests := map[string]struct {
+		responses   []*http.Response
+		body        bool
+		noGetBody   bool
+		refresh     bool
+		wantStatus  int
+		wantCalls   int
+		wantSleeps  []time.Duration
+		wantBodies  []string
+		wantTargets []string
+	}{
+		"success": {
+			responses:   []*http.Response{response(http.StatusOK, nil)},
+			wantStatus:  http.StatusOK,
+			wantCalls:   1,
+			wantTargets: []string{"front-proxy"},
+		},
+		"rate limited, not moved": {
+			responses:   []*http.Response{response(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})},
+			wantStatus:  http.StatusTooManyRequests,
+			wantCalls:   1,
+			wantTargets: []string{"front-proxy"},
+		},
+		"misdirected, not moved": {
+			responses:   []*http.Response{response(http.StatusMisdirectedRequest, nil)},
+			wantStatus:  http.StatusMisdirectedRequest,
+			wantCalls:   1,
+			wantTargets: []string{"front-proxy"},
+		},
+		"moved, then found": {
+			responses:   []*http.Response{shardMoved("beta"), response(http.StatusOK, nil)},
+			wantStatus:  http.StatusOK,
+			wantCalls:   2,
+			wantSleeps:  []time.Duration{time.Second},
+			wantTargets: []string{"front-proxy", "front-proxy"},
+		},
+		"moved with body": {
+			responses:   []*http.Response{shardMoved("beta"), response(http.StatusCreated, nil)},
+			body:        true,
+			wantStatus:  http.StatusCreated,
+			wantCalls:   2,
+			wantSleeps:  []time.Duration{time.Second},
+			wantBodies:  []string{"payload", "payload"},
+			wantTargets: []string{"front-proxy", "front-proxy"},
+		},
+		"moved with body that cannot be recreated": {
+			responses:   []*http.Response{shardMoved("beta")},
+			body:        true,
+			noGetBody:   true,
+			wantStatus:  http.StatusMisdirectedRequest,
+			wantCalls:   1,
+			wantBodies:  []string{"payload"},
+			wantTargets: []string{"front-proxy"},
+		},
+		"moved, refreshing the routing": {
+			responses:   []*http.Response{shardMoved("beta"), response(http.StatusOK, nil)},
+			refresh:     true,
+			wantStatus:  http.StatusOK,
+			wantCalls:   2,
+			wantSleeps:  []time.Duration{time.Second},
+			wantTargets: []string{"front-proxy", "beta"},
+		},
+		"moved too often": {
+			responses: []*http.Response{
+				shardMoved("beta"), shardMoved("beta"), shardMoved("beta"),
+				shardMoved("beta"), shardMoved("beta"), shardMoved("beta"),
+			},
+			wantStatus: http.StatusMisdirectedRequest,
+			wantCalls:  maxRetries + 1,
+			wantSleeps: []time.Duration{time.Second, time.Second, time.Second, time.Second, time.Second},
+			wantTargets: []string{
+				"front-proxy", "front-proxy", "front-proxy", "front-proxy", "front-proxy", "front-proxy",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			var bodies, targets []string
+			delegate := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				resp := tt.responses[calls]
+				calls++
+				targets = append(targets, req.URL.Host)
+				if req.Body != nil {
+					bs, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+					bodies = append(bodies, string(bs))
+				}
+				return resp, nil
+			})
+
+			var refresh RefreshFunc
+			if tt.refresh {
+				refresh = func(req *http.Request, shard string) (*http.Request, error) {
+					req.URL.Host = shard
+					return req, nil
+				}
+			}
+			var sleeps []time.Duration
+			rt := &retryingRoundTripper{delegate: delegate, refresh: refresh, sleep: func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}}
+
+			var body io.Reader
+			if tt.body {
+				body = bytes.NewReader([]byte("payload"))
+			}
+			req, err := http.NewRequest(http.MethodPost, "https://front-proxy/clusters/root:org/api/v1/configmaps", body)
+			require.NoError(t, err)
+			if tt.noGetBody {
+				req.GetBody = nil
+			}
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, tt.wantSleeps, sleeps)
+			require.Equal(t, tt.wantBodies, bodies)
+			require.Equal(t, tt.wantTargets, targets)
+		})
+	}
+}
+
+func TestRetryingRoundTripperCancelled(t *testing.T) {
+	rt := NewRetryingRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return shardMoved("beta"), nil
+	}), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://front-proxy/clusters/root:org/api/v1/configmaps", nil)
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(req) //nolint:bodyclose
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestShardURLRefresh(t *testing.T) {
+	refresh := NewShardURLRefresh(func(shard string) (string, bool) {
+		if shard == "beta" {
+			return "https://beta.example.com:6443", true
+		}
+		return "", false
+	})
+
+	t.Log("A known shard gets the retried request")
+	req, err := http.NewRequest(http.MethodGet, "https://alpha.example.com:6443/clusters/root:org/api/v1/configmaps", nil)
+	require.NoError(t, err)
+	req, err = refresh(req, "beta")
+	require.NoError(t, err)
+	require.Equal(t, "https://beta.example.com:6443/clusters/root:org/api/v1/configmaps", req.URL.String())
+
+	t.Log("An unknown shard leaves the request unchanged")
+	req, err = http.NewRequest(http.MethodGet, "https://alpha.example.com:6443/clusters/root:org/api/v1/configmaps", nil)
+	require.NoError(t, err)
+	req, err = refresh(req, "gamma")
+	require.NoError(t, err)
+	require.Equal(t, "https://alpha.example.com:6443/clusters/root:org/api/v1/configmaps", req.URL.String())
+}
+
+func TestClientGoOnShardMoved(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(ShardMovedHeader, "beta")
+		http.Error(w, "Not found on this shard", http.StatusMisdirectedRequest)
+	}))
+	defer server.Close()
+
+	get := func(cfg *rest.Config) error {
+		client, err := kubernetes.NewForConfig(cfg)
+		require.NoError(t, err)
+		_, err = client.CoreV1().ConfigMaps("default").Get(context.Background(), "config", metav1.GetOptions{})
+		return err
+	}
+
+	t.Log("Plain client-go gives up after the first shard-moved response")
+	err := get(&rest.Config{Host: server.URL + "/clusters/root:org"})
+	require.Error(t, err)
+	var status apierrors.APIStatus
+	require.ErrorAs(t, err, &status)
+	require.Equal(t, int32(http.StatusMisdirectedRequest), status.Status().Code)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	t.Log("The retrying round tripper retries inside a single client-go attempt")
+	atomic.StoreInt32(&calls, 0)
+	cfg := &rest.Config{Host: server.URL + "/clusters/root:org"}
+	cfg.Wrap(func(delegate http.RoundTripper) http.RoundTripper {
+		return &retryingRoundTripper{delegate: delegate, sleep: func(context.Context, time.Duration) error { return nil }}
+	})
+	require.Error(t, get(cfg))
+	require.Equal(t, int32(maxRetries+1), atomic.LoadInt32(&calls))
+}
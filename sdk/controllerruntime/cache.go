@@ -0,0 +1,411 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const defaultResync = 10 * time.Hour
+
+// NewClusterAwareCache returns a controller-runtime cache for manager.Options.NewCache
+// that lists and watches the objects of all logical clusters behind the wildcard
+// endpoint of the given config, see NewWildcardConfig. Its informers are created by
+// NewSharedIndexInformer, i.e. they key objects by logical cluster.
+//
+// Get reads the object of the logical cluster found in the context, see WithCluster,
+// and fails without one. List returns the objects of the logical cluster found in the
+// context, or of all logical clusters if there is none. Field selectors are supported
+// for fields indexed by IndexField, with an exact match, like in controller-runtime.
+//
+// Of the options, Scheme, Mapper, Resync and Namespace are supported.
+func NewClusterAwareCache(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+	if opts.Scheme == nil {
+		opts.Scheme = scheme.Scheme
+	}
+	if opts.Mapper == nil {
+		var err error
+		if opts.Mapper, err = NewClusterAwareMapperProvider(config); err != nil {
+			return nil, fmt.Errorf("could not create RESTMapper from config: %w", err)
+		}
+	}
+	resync := defaultResync
+	if opts.Resync != nil {
+		resync = *opts.Resync
+	}
+
+	config = NewWildcardConfig(config)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterAwareCache{
+		config:        config,
+		dynamicClient: dynamicClient,
+		scheme:        opts.Scheme,
+		codecs:        serializer.NewCodecFactory(opts.Scheme),
+		paramCodec:    runtime.NewParameterCodec(opts.Scheme),
+		mapper:        opts.Mapper,
+		resync:        resync,
+		namespace:     opts.Namespace,
+		informers:     map[informerKey]*clusterAwareInformer{},
+	}, nil
+}
+
+type informerKey struct {
+	gvk          schema.GroupVersionKind
+	unstructured bool
+}
+
+type clusterAwareInformer struct {
+	toolscache.SharedIndexInformer
+	gvk   schema.GroupVersionKind
+	scope apimeta.RESTScopeName
+}
+
+type clusterAwareCache struct {
+	config        *rest.Config
+	dynamicClient dynamic.Interface
+	scheme        *runtime.Scheme
+	codecs        serializer.CodecFactory
+	paramCodec    runtime.ParameterCodec
+	mapper        apimeta.RESTMapper
+	resync        time.Duration
+	namespace     string
+
+	lock      sync.Mutex
+	informers map[informerKey]*clusterAwareInformer
+	// ctx is the context of Start, nil before. Informers created later are started with it.
+	ctx context.Context
+}
+
+var _ cache.Cache = &clusterAwareCache{}
+
+func (c *clusterAwareCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	clusterName, found := ClusterFromContext(ctx)
+	if !found {
+		return fmt.Errorf("cannot get %s without a logical cluster in the context", key)
+	}
+	informer, err := c.informerFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err := c.waitForSync(ctx, informer); err != nil {
+		return err
+	}
+	if informer.scope == apimeta.RESTScopeNameRoot {
+		key.Namespace = ""
+	}
+
+	item, exists, err := informer.GetIndexer().GetByKey(kcpcache.ToClusterAwareKey(clusterName.String(), key.Namespace, key.Name))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{Group: informer.gvk.Group, Resource: informer.gvk.Kind}, key.Name)
+	}
+	return copyInto(item.(runtime.Object), obj, informer.gvk)
+}
+
+func (c *clusterAwareCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	_, isUnstructured := list.(*unstructured.UnstructuredList)
+	informer, err := c.informerForKind(ctx, gvk, isUnstructured)
+	if err != nil {
+		return err
+	}
+	if err := c.waitForSync(ctx, informer); err != nil {
+		return err
+	}
+
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	clusterName, hasCluster := ClusterFromContext(ctx)
+
+	var items []interface{}
+	switch {
+	case listOpts.FieldSelector != nil:
+		field, value, exact := requiresExactMatch(listOpts.FieldSelector)
+		if !exact {
+			return fmt.Errorf("non-exact field matches are not supported by the cache")
+		}
+		items, err = informer.GetIndexer().ByIndex(fieldIndexName(field), value)
+	case hasCluster && listOpts.Namespace != "":
+		items, err = informer.GetIndexer().ByIndex(kcpcache.ClusterAndNamespaceIndexName, kcpcache.ClusterAndNamespaceIndexKey(clusterName, listOpts.Namespace))
+	case hasCluster:
+		items, err = informer.GetIndexer().ByIndex(kcpcache.ClusterIndexName, kcpcache.ClusterIndexKey(clusterName))
+	default:
+		items = informer.GetIndexer().List()
+	}
+	if err != nil {
+		return err
+	}
+
+	objs := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		if listOpts.Limit > 0 && int64(len(objs)) >= listOpts.Limit {
+			break
+		}
+		obj := item.(runtime.Object)
+		accessor, err := apimeta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if hasCluster && logicalcluster.From(accessor) != clusterName {
+			continue
+		}
+		if listOpts.Namespace != "" && accessor.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(accessor.GetLabels())) {
+			continue
+		}
+		obj = obj.DeepCopyObject()
+		obj.GetObjectKind().SetGroupVersionKind(informer.gvk)
+		objs = append(objs, obj)
+	}
+	return apimeta.SetList(list, objs)
+}
+
+func (c *clusterAwareCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	return c.informerFor(ctx, obj)
+}
+
+func (c *clusterAwareCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	return c.informerForKind(ctx, gvk, !c.scheme.Recognizes(gvk))
+}
+
+func (c *clusterAwareCache) Start(ctx context.Context) error {
+	c.lock.Lock()
+	if c.ctx != nil {
+		c.lock.Unlock()
+		return fmt.Errorf("cache is already started")
+	}
+	c.ctx = ctx
+	for _, informer := range c.informers {
+		go informer.Run(ctx.Done())
+	}
+	c.lock.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (c *clusterAwareCache) WaitForCacheSync(ctx context.Context) bool {
+	c.lock.Lock()
+	synced := make([]toolscache.InformerSynced, 0, len(c.informers))
+	for _, informer := range c.informers {
+		synced = append(synced, informer.HasSynced)
+	}
+	c.lock.Unlock()
+	return toolscache.WaitForCacheSync(ctx.Done(), synced...)
+}
+
+// waitForSync waits for the given informer to sync, which is only needed for informers
+// created by the read.
+func (c *clusterAwareCache) waitForSync(ctx context.Context, informer *clusterAwareInformer) error {
+	c.lock.Lock()
+	started := c.ctx != nil
+	c.lock.Unlock()
+	if !started {
+		return &cache.ErrCacheNotStarted{}
+	}
+	if !toolscache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed waiting for the %s informer to sync: %w", informer.gvk, ctx.Err())
+	}
+	return nil
+}
+
+// IndexField adds an index over the given field. Objects of all logical clusters are
+// found by the index, List narrows them down to the logical cluster of the context.
+func (c *clusterAwareCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	informer, err := c.informerFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return informer.AddIndexers(toolscache.Indexers{
+		fieldIndexName(field): func(item interface{}) ([]string, error) {
+			obj, ok := item.(client.Object)
+			if !ok {
+				return nil, fmt.Errorf("object %T is not a client.Object", item)
+			}
+			return extractValue(obj), nil
+		},
+	})
+}
+
+func (c *clusterAwareCache) informerFor(ctx context.Context, obj client.Object) (*clusterAwareInformer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	_, isUnstructured := obj.(*unstructured.Unstructured)
+	return c.informerForKind(ctx, gvk, isUnstructured)
+}
+
+func (c *clusterAwareCache) informerForKind(ctx context.Context, gvk schema.GroupVersionKind, isUnstructured bool) (*clusterAwareInformer, error) {
+	key := informerKey{gvk: gvk, unstructured: isUnstructured}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if informer, found := c.informers[key]; found {
+		return informer, nil
+	}
+
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	var lw *toolscache.ListWatch
+	var exampleObject runtime.Object
+	if isUnstructured {
+		lw, exampleObject = c.unstructuredListWatch(gvk, mapping)
+	} else if lw, exampleObject, err = c.structuredListWatch(gvk, mapping); err != nil {
+		return nil, err
+	}
+
+	informer := &clusterAwareInformer{
+		SharedIndexInformer: NewSharedIndexInformer(lw, exampleObject, c.resync, toolscache.Indexers{}),
+		gvk:                 gvk,
+		scope:               mapping.Scope.Name(),
+	}
+	c.informers[key] = informer
+	if c.ctx != nil {
+		go informer.Run(c.ctx.Done())
+	}
+	return informer, nil
+}
+
+// structuredListWatch lists and watches typed objects, like controller-runtime does.
+func (c *clusterAwareCache) structuredListWatch(gvk schema.GroupVersionKind, mapping *apimeta.RESTMapping) (*toolscache.ListWatch, runtime.Object, error) {
+	restClient, err := apiutil.RESTClientForGVK(gvk, false, c.config, c.codecs)
+	if err != nil {
+		return nil, nil, err
+	}
+	listObj, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, nil, err
+	}
+	exampleObject, err := c.scheme.New(gvk)
+	if err != nil {
+		return nil, nil, err
+	}
+	namespaced := mapping.Scope.Name() != apimeta.RESTScopeNameRoot
+
+	return &toolscache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			res := listObj.DeepCopyObject()
+			err := restClient.Get().
+				NamespaceIfScoped(c.namespace, namespaced).
+				Resource(mapping.Resource.Resource).
+				VersionedParams(&opts, c.paramCodec).
+				Do(context.TODO()).
+				Into(res)
+			return res, err
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.Watch = true
+			return restClient.Get().
+				NamespaceIfScoped(c.namespace, namespaced).
+				Resource(mapping.Resource.Resource).
+				VersionedParams(&opts, c.paramCodec).
+				Watch(context.TODO())
+		},
+	}, exampleObject, nil
+}
+
+// unstructuredListWatch lists and watches unstructured objects through the dynamic client.
+func (c *clusterAwareCache) unstructuredListWatch(gvk schema.GroupVersionKind, mapping *apimeta.RESTMapping) (*toolscache.ListWatch, runtime.Object) {
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(mapping.Resource)
+	if c.namespace != "" && mapping.Scope.Name() != apimeta.RESTScopeNameRoot {
+		resource = c.dynamicClient.Resource(mapping.Resource).Namespace(c.namespace)
+	}
+	exampleObject := &unstructured.Unstructured{}
+	exampleObject.SetGroupVersionKind(gvk)
+
+	return &toolscache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return resource.List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.Watch = true
+			return resource.Watch(context.TODO(), opts)
+		},
+	}, exampleObject
+}
+
+// copyInto copies the cached object into out, which must be of the same type.
+func copyInto(cached runtime.Object, out client.Object, gvk schema.GroupVersionKind) error {
+	cached = cached.DeepCopyObject()
+	outVal := reflect.ValueOf(out)
+	objVal := reflect.ValueOf(cached)
+	if !objVal.Type().AssignableTo(outVal.Type()) {
+		return fmt.Errorf("cache had type %s, but %s was asked for", objVal.Type(), outVal.Type())
+	}
+	reflect.Indirect(outVal).Set(reflect.Indirect(objVal))
+	out.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// fieldIndexName returns the name of the index over the given field, as used by controller-runtime.
+func fieldIndexName(field string) string {
+	return "field:" + field
+}
+
+// requiresExactMatch checks if the given field selector is of the form `k=v` or `k==v`.
+func requiresExactMatch(sel fields.Selector) (field, val string, required bool) {
+	reqs := sel.Requirements()
+	if len(reqs) != 1 {
+		return "", "", false
+	}
+	req := reqs[0]
+	if req.Operator != selection.Equals && req.Operator != selection.DoubleEquals {
+		return "", "", false
+	}
+	return req.Field, req.Value, true
+}
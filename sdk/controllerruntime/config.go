@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"net/http"
+	"strings"
+
+	kcpclient "github.com/kcp-dev/apimachinery/v2/pkg/client"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/sdk/client/routing"
+)
+
+// NewClusterAwareConfig returns a copy of the given config whose requests are routed
+// to the logical cluster found in the request context, see WithCluster. Requests
+// without a logical cluster in the context are sent to the configured host unchanged.
+//
+// Requests reaching a shard that does not host the logical cluster anymore, e.g.
+// while its workspace moves to another shard, are retried, see routing.NewRetryingRoundTripper.
+func NewClusterAwareConfig(cfg *rest.Config) *rest.Config {
+	return kcpclient.SetMultiClusterRoundTripper(routing.WithRetryOnShardMoved(cfg, nil))
+}
+
+// NewClusterAwareHTTPClient returns an HTTP client for the given config that routes
+// requests to the logical cluster found in the request context.
+func NewClusterAwareHTTPClient(cfg *rest.Config) (*http.Client, error) {
+	return rest.HTTPClientFor(NewClusterAwareConfig(cfg))
+}
+
+// NewWildcardConfig returns a copy of the given config pointing to the wildcard
+// endpoint /clusters/* below the configured host. This is the config informers
+// use to list and watch objects across all logical clusters, e.g. of an APIExport
+// virtual workspace.
+//
+// A host that already points to a logical cluster is returned unchanged.
+func NewWildcardConfig(cfg *rest.Config) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	if strings.Contains(cfg.Host, "/clusters/") {
+		return cfg
+	}
+	cfg.Host = strings.TrimSuffix(cfg.Host, "/") + logicalcluster.Wildcard.RequestPath()
+	return cfg
+}
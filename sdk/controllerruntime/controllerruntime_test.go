@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClusterAwareConfig(t *testing.T) {
+	tests := map[string]struct {
+		host    string
+		cluster logicalcluster.Name
+		path    string
+		want    string
+	}{
+		"no cluster in context": {
+			host: "https://kcp.example.com",
+			path: "/api/v1/configmaps",
+			want: "/api/v1/configmaps",
+		},
+		"cluster in context": {
+			host:    "https://kcp.example.com",
+			cluster: "abc",
+			path:    "/apis/apis.kcp.io/v1alpha1/apibindings",
+			want:    "/clusters/abc/apis/apis.kcp.io/v1alpha1/apibindings",
+		},
+		"APIExport virtual workspace": {
+			host:    "https://kcp.example.com/services/apiexport/root:org/my-export",
+			cluster: "abc",
+			path:    "/services/apiexport/root:org/my-export/api/v1/namespaces/default/configmaps",
+			want:    "/services/apiexport/root:org/my-export/clusters/abc/api/v1/namespaces/default/configmaps",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			cfg := &rest.Config{
+				Host: tt.host,
+				WrapTransport: func(http.RoundTripper) http.RoundTripper {
+					return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						got = req.URL.Path
+						return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+					})
+				},
+			}
+			client, err := NewClusterAwareHTTPClient(cfg)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if !tt.cluster.Empty() {
+				ctx = WithCluster(ctx, tt.cluster)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://kcp.example.com"+tt.path, nil)
+			require.NoError(t, err)
+			_, err = client.Do(req) //nolint:bodyclose
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewWildcardConfig(t *testing.T) {
+	require.Equal(t, "https://kcp.example.com/clusters/*", NewWildcardConfig(&rest.Config{Host: "https://kcp.example.com/"}).Host)
+	require.Equal(t, "https://kcp.example.com/services/apiexport/root/export/clusters/*", NewWildcardConfig(&rest.Config{Host: "https://kcp.example.com/services/apiexport/root/export"}).Host)
+	require.Equal(t, "https://kcp.example.com/clusters/root", NewWildcardConfig(&rest.Config{Host: "https://kcp.example.com/clusters/root"}).Host)
+}
+
+func TestRequest(t *testing.T) {
+	obj := &metav1.ObjectMeta{
+		Name:        "foo",
+		Namespace:   "bar",
+		Annotations: map[string]string{logicalcluster.AnnotationKey: "abc"},
+	}
+	req := RequestForObject(obj)
+	require.Equal(t, "abc|bar/foo", req.String())
+
+	parsed, err := RequestFromKey(req.String())
+	require.NoError(t, err)
+	require.Equal(t, req, parsed)
+
+	_, err = RequestFromKey("bar/foo")
+	require.Error(t, err)
+
+	cluster, ok := ClusterFromContext(req.IntoContext(context.Background()))
+	require.True(t, ok)
+	require.Equal(t, logicalcluster.Name("abc"), cluster)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllerruntime contains the kcp specific building blocks needed to run
+// controller-runtime based operators against kcp.
+//
+// controller-runtime's Manager, Cache and Client are not aware of logical clusters.
+// This package provides cluster-aware replacements to be plugged into manager.Options
+// so that a single manager can serve all logical clusters behind a wildcard endpoint,
+// e.g. an APIExport virtual workspace URL:
+//
+//   - NewClusterAwareCache for manager.Options.NewCache lists and watches the wildcard
+//     endpoint, see NewWildcardConfig, with informers keyed by logical cluster, see
+//     NewSharedIndexInformer. It reads the logical cluster stored in the context.
+//   - NewClusterAwareClient for manager.Options.NewClient reads from that cache and
+//     sends writes to the logical cluster stored in the context, see NewClusterAwareConfig.
+//   - NewClusterAwareMapperProvider for manager.Options.MapperProvider discovers the
+//     APIs at the wildcard endpoint.
+//   - NewClusterAwareManager is manager.New with these options defaulted.
+//   - EnqueueRequestForObject and NewReconciler carry the logical cluster of an object
+//     through the work queue into a Reconciler, as a Request, and a context scoped to it.
+//
+// The package is built against controller-runtime v0.12.
+package controllerruntime
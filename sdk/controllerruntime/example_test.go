@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime_test
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kcp-dev/kcp/sdk/controllerruntime"
+)
+
+// This example reconciles the config maps of all logical clusters bound to an APIExport.
+func ExampleNewClusterAwareManager() {
+	cfg := &rest.Config{Host: "https://kcp.example.com/services/apiexport/root:org/my-export"}
+
+	mgr, err := controllerruntime.NewClusterAwareManager(cfg, manager.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := controller.New("configmaps", mgr, controller.Options{
+		Reconciler: controllerruntime.NewReconciler(controllerruntime.ReconcilerFunc(func(ctx context.Context, req controllerruntime.Request) (reconcile.Result, error) {
+			// ctx is scoped to req.ClusterName: the client reads and writes objects of that logical cluster.
+			var cm corev1.ConfigMap
+			if err := mgr.GetClient().Get(ctx, req.NamespacedName, &cm); err != nil {
+				return reconcile.Result{}, err
+			}
+			fmt.Printf("reconciling %s in %s\n", req.NamespacedName, req.ClusterName)
+			return reconcile.Result{}, nil
+		})),
+	})
+	if err != nil {
+		panic(err)
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &controllerruntime.EnqueueRequestForObject{}); err != nil {
+		panic(err)
+	}
+
+	if err := mgr.Start(context.Background()); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpinformers "github.com/kcp-dev/apimachinery/v2/third_party/informers"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewSharedIndexInformer returns a shared index informer for a wildcard list watcher
+// that keys objects by logical cluster, namespace and name, and maintains the
+// kcpcache.ClusterIndexName and kcpcache.ClusterAndNamespaceIndexName indexes in
+// addition to the given ones. Its signature matches controller-runtime's
+// cache.Options.NewInformerFunc.
+func NewSharedIndexInformer(lw cache.ListerWatcher, exampleObject runtime.Object, defaultEventHandlerResyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return kcpinformers.NewSharedIndexInformer(lw, exampleObject, defaultEventHandlerResyncPeriod, WithClusterIndexers(indexers))
+}
+
+// WithClusterIndexers returns the given indexers extended by the logical cluster
+// indexes. The given map is not modified.
+func WithClusterIndexers(indexers cache.Indexers) cache.Indexers {
+	ret := cache.Indexers{
+		kcpcache.ClusterIndexName:             kcpcache.ClusterIndexFunc,
+		kcpcache.ClusterAndNamespaceIndexName: kcpcache.ClusterAndNamespaceIndexFunc,
+	}
+	for name, fn := range indexers {
+		ret[name] = fn
+	}
+	return ret
+}
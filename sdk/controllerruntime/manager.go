@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	_ cache.NewCacheFunc    = NewClusterAwareCache
+	_ cluster.NewClientFunc = NewClusterAwareClient
+)
+
+// NewClusterAwareManager returns a controller-runtime manager serving all logical
+// clusters behind the given config, e.g. an APIExport virtual workspace URL. It is
+// manager.New with the NewCache, NewClient and MapperProvider options defaulted to
+// NewClusterAwareCache, NewClusterAwareClient and NewClusterAwareMapperProvider.
+//
+// Leader election needs a config for a concrete logical cluster, see
+// manager.Options.LeaderElectionConfig, as the given config does not name one.
+func NewClusterAwareManager(config *rest.Config, opts manager.Options) (manager.Manager, error) {
+	if opts.NewCache == nil {
+		opts.NewCache = NewClusterAwareCache
+	}
+	if opts.NewClient == nil {
+		opts.NewClient = NewClusterAwareClient
+	}
+	if opts.MapperProvider == nil {
+		opts.MapperProvider = NewClusterAwareMapperProvider
+	}
+	return manager.New(config, opts)
+}
+
+// NewClusterAwareClient returns a controller-runtime client for manager.Options.NewClient.
+// It reads from the given cache and sends writes, and reads of uncached objects, to the
+// logical cluster found in the request context, see WithCluster.
+func NewClusterAwareClient(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+	return cluster.DefaultNewClient(cache, NewClusterAwareConfig(config), options, uncachedObjects...)
+}
+
+// NewClusterAwareMapperProvider returns a REST mapper for manager.Options.MapperProvider.
+// It discovers the APIs served at the wildcard endpoint of the given config, see
+// NewWildcardConfig, lazily when a kind is mapped the first time.
+func NewClusterAwareMapperProvider(config *rest.Config) (meta.RESTMapper, error) {
+	return apiutil.NewDynamicRESTMapper(NewWildcardConfig(config), apiutil.WithLazyDiscovery)
+}
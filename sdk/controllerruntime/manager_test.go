@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// fakeKcp serves the config maps of two logical clusters at the wildcard endpoint,
+// and records the paths of created config maps.
+type fakeKcp struct {
+	lock    sync.Mutex
+	created []string
+	done    chan struct{}
+}
+
+func configMap(cluster logicalcluster.Name, name, owner string) corev1.ConfigMap {
+	return corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster.String()},
+			Labels:      map[string]string{"cluster": cluster.String()},
+		},
+		Data: map[string]string{"owner": owner},
+	}
+}
+
+func (f *fakeKcp) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/clusters/*/api/v1/configmaps" && req.URL.Query().Get("watch") == "true":
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-req.Context().Done():
+		case <-f.done:
+		}
+	case req.Method == http.MethodGet && req.URL.Path == "/clusters/*/api/v1/configmaps":
+		json.NewEncoder(w).Encode(&corev1.ConfigMapList{ //nolint:errcheck
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"},
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+			Items: []corev1.ConfigMap{
+				configMap("a", "config", "alice"),
+				configMap("b", "config", "bob"),
+				configMap("b", "other", "alice"),
+			},
+		})
+	case req.Method == http.MethodPost:
+		f.lock.Lock()
+		f.created = append(f.created, req.URL.Path)
+		f.lock.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		created := configMap("a", "new", "carol")
+		json.NewEncoder(w).Encode(&created) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClusterAwareManager(t *testing.T) {
+	fake := &fakeKcp{done: make(chan struct{})}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer close(fake.done)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	mgr, err := NewClusterAwareManager(&rest.Config{Host: server.URL}, manager.Options{
+		Scheme:             scheme.Scheme,
+		MetricsBindAddress: "0",
+		MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+			return mapper, nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, mgr.GetFieldIndexer().IndexField(ctx, &corev1.ConfigMap{}, "data.owner", func(obj client.Object) []string {
+		return []string{obj.(*corev1.ConfigMap).Data["owner"]}
+	}))
+
+	var lock sync.Mutex
+	reconciled := map[Request]string{}
+	c, err := controller.New("configmaps", mgr, controller.Options{
+		Reconciler: NewReconciler(ReconcilerFunc(func(ctx context.Context, req Request) (reconcile.Result, error) {
+			var cm corev1.ConfigMap
+			if err := mgr.GetClient().Get(ctx, req.NamespacedName, &cm); err != nil {
+				return reconcile.Result{}, err
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			reconciled[req] = cm.Data["owner"]
+			return reconcile.Result{}, nil
+		})),
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &EnqueueRequestForObject{}))
+
+	go mgr.Start(ctx) //nolint:errcheck
+	require.True(t, mgr.GetCache().WaitForCacheSync(ctx))
+
+	t.Log("The reconciler gets the requests of all logical clusters, and reads their objects")
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(reconciled) == 3
+	}, wait.ForeverTestTimeout, 100*time.Millisecond)
+	require.Equal(t, map[Request]string{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "config"}, ClusterName: "a"}: "alice",
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "config"}, ClusterName: "b"}: "bob",
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}, ClusterName: "b"}:  "alice",
+	}, reconciled)
+
+	cl := mgr.GetClient()
+	key := types.NamespacedName{Namespace: "default", Name: "config"}
+
+	t.Log("Get reads the object of the logical cluster in the context")
+	var cm corev1.ConfigMap
+	require.NoError(t, cl.Get(WithCluster(ctx, "b"), key, &cm))
+	require.Equal(t, "bob", cm.Data["owner"])
+	require.True(t, apierrors.IsNotFound(cl.Get(WithCluster(ctx, "a"), types.NamespacedName{Namespace: "default", Name: "other"}, &cm)))
+	require.Error(t, cl.Get(ctx, key, &cm), "a logical cluster is required")
+
+	t.Log("List reads the objects of the logical cluster in the context, or of all")
+	var list corev1.ConfigMapList
+	require.NoError(t, cl.List(WithCluster(ctx, "b"), &list, client.InNamespace("default")))
+	require.Len(t, list.Items, 2)
+	require.NoError(t, cl.List(ctx, &list))
+	require.Len(t, list.Items, 3)
+	require.NoError(t, cl.List(ctx, &list, client.MatchingLabels{"cluster": "a"}))
+	require.Len(t, list.Items, 1)
+
+	t.Log("Field indexes are scoped to the logical cluster in the context")
+	require.NoError(t, cl.List(WithCluster(ctx, "b"), &list, client.MatchingFields{"data.owner": "alice"}))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "other", list.Items[0].Name)
+	require.NoError(t, cl.List(ctx, &list, client.MatchingFields{"data.owner": "alice"}))
+	require.Len(t, list.Items, 2)
+
+	t.Log("Unstructured objects are cached too")
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	require.NoError(t, mgr.GetCache().Get(WithCluster(ctx, "a"), key, u))
+	require.Equal(t, "a", logicalcluster.From(u).String())
+
+	t.Log("Writes are sent to the logical cluster in the context")
+	created := configMap("", "new", "carol")
+	created.Annotations = nil
+	require.NoError(t, cl.Create(WithCluster(ctx, "a"), &created))
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+	require.Equal(t, []string{"/clusters/a/api/v1/namespaces/default/configmaps"}, fake.created)
+}
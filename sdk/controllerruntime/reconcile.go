@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler reconciles objects of logical clusters. It is the cluster-aware
+// counterpart of controller-runtime's reconcile.Reconciler.
+type Reconciler interface {
+	// Reconcile is called with a context scoped to the logical cluster of the request,
+	// i.e. clients of NewClusterAwareClient read and write objects of that cluster.
+	Reconcile(ctx context.Context, req Request) (reconcile.Result, error)
+}
+
+// ReconcilerFunc is a function implementing Reconciler.
+type ReconcilerFunc func(ctx context.Context, req Request) (reconcile.Result, error)
+
+// Reconcile calls the function.
+func (f ReconcilerFunc) Reconcile(ctx context.Context, req Request) (reconcile.Result, error) {
+	return f(ctx, req)
+}
+
+// NewReconciler adapts the given Reconciler to controller-runtime's reconcile.Reconciler,
+// for the requests enqueued by EnqueueRequestForObject. controller-runtime's requests do
+// not carry a logical cluster, hence it is encoded into their name, see Request.String.
+// Requests without a logical cluster are ignored.
+func NewReconciler(r Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		request, err := RequestFromKey(req.Name)
+		if err != nil {
+			// not enqueued by EnqueueRequestForObject, retrying does not help.
+			return reconcile.Result{}, nil
+		}
+		return r.Reconcile(request.IntoContext(ctx), request)
+	})
+}
+
+// EnqueueRequestForObject enqueues the Request of the object of an event, encoded for
+// NewReconciler. It replaces controller-runtime's handler.EnqueueRequestForObject, which
+// drops the logical cluster of the object.
+type EnqueueRequestForObject struct{}
+
+var _ handler.EventHandler = &EnqueueRequestForObject{}
+
+// Create implements EventHandler.
+func (e *EnqueueRequestForObject) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	enqueue(evt.Object, q)
+}
+
+// Update implements EventHandler.
+func (e *EnqueueRequestForObject) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if evt.ObjectNew != nil {
+		enqueue(evt.ObjectNew, q)
+		return
+	}
+	enqueue(evt.ObjectOld, q)
+}
+
+// Delete implements EventHandler.
+func (e *EnqueueRequestForObject) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	enqueue(evt.Object, q)
+}
+
+// Generic implements EventHandler.
+func (e *EnqueueRequestForObject) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	enqueue(evt.Object, q)
+}
+
+func enqueue(obj client.Object, q workqueue.RateLimitingInterface) {
+	if obj == nil {
+		return
+	}
+	q.Add(ToReconcileRequest(RequestForObject(obj)))
+}
+
+// ToReconcileRequest encodes the given Request into a controller-runtime request, for
+// NewReconciler to decode it.
+func ToReconcileRequest(r Request) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: r.String()}}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Request is a reconcile request for an object in a logical cluster. It is the
+// cluster-aware counterpart of controller-runtime's reconcile.Request.
+type Request struct {
+	types.NamespacedName
+
+	// ClusterName is the logical cluster the object lives in.
+	ClusterName logicalcluster.Name
+}
+
+// String returns the cluster-aware key of the request, as produced by
+// kcpcache.MetaClusterNamespaceKeyFunc.
+func (r Request) String() string {
+	return kcpcache.ToClusterAwareKey(r.ClusterName.String(), r.Namespace, r.Name)
+}
+
+// RequestFromKey parses a cluster-aware key as produced by the informers of
+// NewSharedIndexInformer into a Request.
+func RequestFromKey(key string) (Request, error) {
+	clusterName, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		return Request{}, err
+	}
+	if clusterName.Empty() {
+		return Request{}, fmt.Errorf("key %q does not contain a logical cluster", key)
+	}
+	return Request{
+		NamespacedName: types.NamespacedName{Namespace: namespace, Name: name},
+		ClusterName:    clusterName,
+	}, nil
+}
+
+// RequestForObject returns the Request for the given object.
+func RequestForObject(obj metav1.Object) Request {
+	return Request{
+		NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		ClusterName:    logicalcluster.From(obj),
+	}
+}
+
+// WithCluster returns a context which makes clients created from NewClusterAwareConfig
+// or NewClusterAwareHTTPClient send their requests to the given logical cluster.
+func WithCluster(ctx context.Context, clusterName logicalcluster.Name) context.Context {
+	return logicalcluster.WithCluster(ctx, clusterName)
+}
+
+// ClusterFromContext returns the logical cluster stored in the context by WithCluster.
+func ClusterFromContext(ctx context.Context) (logicalcluster.Name, bool) {
+	return logicalcluster.ClusterFromContext(ctx)
+}
+
+// IntoContext returns a context scoped to the logical cluster of the request. This is
+// the context a reconciler should pass to its client calls.
+func (r Request) IntoContext(ctx context.Context) context.Context {
+	return WithCluster(ctx, r.ClusterName)
+}
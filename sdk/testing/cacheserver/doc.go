@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cacheserver provides an in-memory fake of kcp's cache server for unit and
+// integration tests of controllers replicating objects into the cache server or
+// resolving objects from it, without booting the cache-server binary.
+//
+// The fake serves the REST surface of the cache server, with or without the
+// /services/cache prefix:
+//
+//	/services/cache/shards/{shard}/clusters/{cluster}/api/{version}/...
+//	/services/cache/shards/{shard}/clusters/{cluster}/apis/{group}/{version}/...
+//
+// Objects are created, read, updated (including the status subresource), deleted, listed
+// and watched. Lists and watches accept the "*" wildcard for the shard and the cluster,
+// and the label and field selectors on metadata.name and metadata.namespace. Like the
+// cache server, the fake does not know the schema of the objects, i.e. it stores them
+// unvalidated. Patches and the replication stream are not served.
+//
+// The config of the fake can be used with the round trippers of the cache client, or
+// with a host that includes the shard:
+//
+//	server := cacheserver.New(t)
+//	config := server.Config()
+//	config.Host += "/services/cache/shards/amber"
+//	client, err := kcpclientset.NewForConfig(config)
+//	exports, err := client.ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{})
+//
+// This package only depends on client-go and the kcp SDK.
+package cacheserver
@@ -0,0 +1,656 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacheserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	kcpscheme "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/scheme"
+)
+
+const (
+	// shardAnnotationKey is the annotation holding the shard an object has been
+	// replicated from.
+	shardAnnotationKey = "kcp.io/shard"
+
+	servicePrefix = "/services/cache"
+	wildcard      = "*"
+)
+
+// Server is an in-memory fake of the cache server, serving HTTP on a local port.
+type Server struct {
+	server   *httptest.Server
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	lock            sync.Mutex
+	resourceVersion int64
+	kinds           map[schema.GroupResource]string
+	objects         map[objectKey]*unstructured.Unstructured
+	events          []event
+	// changed is closed and replaced whenever an event is recorded.
+	changed chan struct{}
+}
+
+type objectKey struct {
+	resource  schema.GroupResource
+	shard     string
+	cluster   string
+	namespace string
+	name      string
+}
+
+type event struct {
+	key             objectKey
+	eventType       watch.EventType
+	object          *unstructured.Unstructured
+	resourceVersion int64
+}
+
+// New starts a fake cache server that is closed when the test finishes. The kinds of the
+// kcp and Kubernetes APIs are known upfront, the kinds of other APIs are learnt from the
+// objects created.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		stopCh:  make(chan struct{}),
+		kinds:   map[schema.GroupResource]string{},
+		objects: map[objectKey]*unstructured.Unstructured{},
+		changed: make(chan struct{}),
+	}
+	for _, scheme := range []*runtime.Scheme{clientgoscheme.Scheme, kcpscheme.Scheme} {
+		for gvk := range scheme.AllKnownTypes() {
+			if strings.HasSuffix(gvk.Kind, "List") {
+				continue
+			}
+			plural, _ := meta.UnsafeGuessKindToResource(gvk)
+			s.kinds[plural.GroupResource()] = gvk.Kind
+		}
+	}
+	s.server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// URL returns the base URL of the server, without the /services/cache prefix.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Config returns a config for the server. Like the configs of the cache client options,
+// the host of the config includes neither the /services/cache prefix nor a shard.
+func (s *Server) Config() *rest.Config {
+	return &rest.Config{
+		Host:    s.server.URL,
+		QPS:     -1,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Close terminates the open watches and shuts the server down.
+func (s *Server) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		s.server.Close()
+	})
+}
+
+// Add stores the object as if it had been created on the given shard, in the logical
+// cluster of its kcp.io/cluster annotation. It returns the stored object.
+func (s *Server) Add(shard string, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	info := &requestInfo{
+		shard:     shard,
+		cluster:   logicalcluster.From(obj).String(),
+		resource:  gvr,
+		namespace: obj.GetNamespace(),
+	}
+	created, err := s.create(info, obj.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get returns the object stored for the given shard and logical cluster.
+func (s *Server) Get(shard string, cluster logicalcluster.Name, gr schema.GroupResource, namespace, name string) (*unstructured.Unstructured, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	obj, found := s.objects[objectKey{resource: gr, shard: shard, cluster: cluster.String(), namespace: namespace, name: name}]
+	if !found {
+		return nil, false
+	}
+	return obj.DeepCopy(), true
+}
+
+// List returns the objects of the resource stored for all shards and logical clusters,
+// sorted by shard, logical cluster, namespace and name.
+func (s *Server) List(gr schema.GroupResource) []*unstructured.Unstructured {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.listLocked(&requestInfo{shard: wildcard, cluster: wildcard, resource: gr.WithVersion("")}, labels.Everything(), fields.Everything())
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/livez", "/readyz", "/healthz":
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+		return
+	}
+
+	info, err := parseRequestInfo(req.URL.Path)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodGet && info.name == "" && isWatch(req):
+		s.serveWatch(w, req, info)
+	case req.Method == http.MethodGet && info.name == "":
+		s.serveList(w, req, info)
+	case req.Method == http.MethodGet:
+		s.serveGet(w, info)
+	case req.Method == http.MethodPost && info.name == "":
+		s.serveCreate(w, req, info)
+	case req.Method == http.MethodPut && info.name != "":
+		s.serveUpdate(w, req, info)
+	case req.Method == http.MethodDelete && info.name != "":
+		s.serveDelete(w, info)
+	default:
+		writeError(w, apierrors.NewMethodNotSupported(info.resource.GroupResource(), req.Method))
+	}
+}
+
+func (s *Server) serveGet(w http.ResponseWriter, info *requestInfo) {
+	if info.shard == wildcard || info.cluster == wildcard {
+		writeError(w, apierrors.NewBadRequest("a shard and a logical cluster are required to get an object"))
+		return
+	}
+	if info.subresource != "" && info.subresource != "status" {
+		writeError(w, apierrors.NewNotFound(info.resource.GroupResource(), info.name))
+		return
+	}
+
+	s.lock.Lock()
+	obj, found := s.objects[info.key(info.name)]
+	s.lock.Unlock()
+	if !found {
+		writeError(w, apierrors.NewNotFound(info.resource.GroupResource(), info.name))
+		return
+	}
+	writeObject(w, http.StatusOK, obj)
+}
+
+func (s *Server) serveList(w http.ResponseWriter, req *http.Request, info *requestInfo) {
+	labelSelector, fieldSelector, err := selectorsFrom(req)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	s.lock.Lock()
+	items := s.listLocked(info, labelSelector, fieldSelector)
+	resourceVersion := s.resourceVersion
+	kind := s.kinds[info.resource.GroupResource()]
+	s.lock.Unlock()
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetAPIVersion(info.resource.GroupVersion().String())
+	list.SetKind(kind + "List")
+	list.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+	for _, item := range items {
+		list.Items = append(list.Items, *item)
+	}
+	writeObject(w, http.StatusOK, list)
+}
+
+func (s *Server) serveWatch(w http.ResponseWriter, req *http.Request, info *requestInfo) {
+	labelSelector, fieldSelector, err := selectorsFrom(req)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	var since int64
+	var initial []*unstructured.Unstructured
+	if rv := req.URL.Query().Get("resourceVersion"); rv != "" && rv != "0" {
+		since, err = strconv.ParseInt(rv, 10, 64)
+		if err != nil {
+			writeError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid resourceVersion %q", rv)))
+			return
+		}
+	} else {
+		s.lock.Lock()
+		initial = s.listLocked(info, labelSelector, fieldSelector)
+		since = s.resourceVersion
+		s.lock.Unlock()
+	}
+	var timeout <-chan time.Time
+	if seconds := req.URL.Query().Get("timeoutSeconds"); seconds != "" {
+		if n, err := strconv.Atoi(seconds); err == nil && n > 0 {
+			timeout = time.After(time.Duration(n) * time.Second)
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, apierrors.NewInternalError(fmt.Errorf("streaming is not supported")))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	send := func(eventType watch.EventType, obj *unstructured.Unstructured) bool {
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			return false
+		}
+		if err := encoder.Encode(&metav1.WatchEvent{Type: string(eventType), Object: runtime.RawExtension{Raw: raw}}); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	for _, obj := range initial {
+		if !send(watch.Added, obj) {
+			return
+		}
+	}
+
+	for {
+		s.lock.Lock()
+		var pending []event
+		for _, e := range s.events {
+			if e.resourceVersion > since && info.matches(e.key) && matchesSelectors(e.object, labelSelector, fieldSelector) {
+				pending = append(pending, e)
+			}
+		}
+		since = s.resourceVersion
+		changed := s.changed
+		s.lock.Unlock()
+
+		for _, e := range pending {
+			if !send(e.eventType, e.object) {
+				return
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			return
+		case <-req.Context().Done():
+			return
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *Server) serveCreate(w http.ResponseWriter, req *http.Request, info *requestInfo) {
+	obj, err := decodeObject(req)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	created, err := s.create(info, obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeObject(w, http.StatusCreated, created)
+}
+
+func (s *Server) create(info *requestInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if info.shard == "" || info.shard == wildcard || info.cluster == "" || info.cluster == wildcard {
+		return nil, apierrors.NewBadRequest("a shard and a logical cluster are required to create an object")
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(info.namespace)
+	} else if obj.GetNamespace() != info.namespace {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("the namespace of the object %q does not match the namespace of the request %q", obj.GetNamespace(), info.namespace))
+	}
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		obj.SetName(obj.GetGenerateName() + utilrand.String(5))
+	}
+	if obj.GetName() == "" {
+		return nil, apierrors.NewBadRequest("name or generateName is required")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := info.key(obj.GetName())
+	if _, found := s.objects[key]; found {
+		return nil, apierrors.NewAlreadyExists(info.resource.GroupResource(), obj.GetName())
+	}
+
+	if obj.GetKind() == "" {
+		obj.SetAPIVersion(info.resource.GroupVersion().String())
+		obj.SetKind(s.kinds[info.resource.GroupResource()])
+	} else if _, found := s.kinds[info.resource.GroupResource()]; !found {
+		s.kinds[info.resource.GroupResource()] = obj.GetKind()
+	}
+	obj.SetUID(types.UID(uuid.NewUUID()))
+	obj.SetCreationTimestamp(metav1.Now().Rfc3339Copy())
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[logicalcluster.AnnotationKey] = info.cluster
+	if _, found := annotations[shardAnnotationKey]; !found {
+		annotations[shardAnnotationKey] = info.shard
+	}
+	obj.SetAnnotations(annotations)
+
+	s.recordLocked(key, watch.Added, obj)
+	return obj.DeepCopy(), nil
+}
+
+func (s *Server) serveUpdate(w http.ResponseWriter, req *http.Request, info *requestInfo) {
+	if info.shard == wildcard || info.cluster == wildcard {
+		writeError(w, apierrors.NewBadRequest("a shard and a logical cluster are required to update an object"))
+		return
+	}
+	if info.subresource != "" && info.subresource != "status" {
+		writeError(w, apierrors.NewNotFound(info.resource.GroupResource(), info.name))
+		return
+	}
+	obj, err := decodeObject(req)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	if obj.GetName() != info.name {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf("the name of the object %q does not match the name of the request %q", obj.GetName(), info.name)))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := info.key(info.name)
+	existing, found := s.objects[key]
+	if !found {
+		writeError(w, apierrors.NewNotFound(info.resource.GroupResource(), info.name))
+		return
+	}
+	if rv := obj.GetResourceVersion(); rv != "" && rv != existing.GetResourceVersion() {
+		writeError(w, apierrors.NewConflict(info.resource.GroupResource(), info.name, fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again")))
+		return
+	}
+
+	updated := obj
+	if info.subresource == "status" {
+		updated = existing.DeepCopy()
+		if status, found := obj.Object["status"]; found {
+			updated.Object["status"] = status
+		} else {
+			delete(updated.Object, "status")
+		}
+	} else {
+		updated.SetNamespace(existing.GetNamespace())
+		updated.SetUID(existing.GetUID())
+		updated.SetCreationTimestamp(existing.GetCreationTimestamp())
+		if updated.GetKind() == "" {
+			updated.SetAPIVersion(existing.GetAPIVersion())
+			updated.SetKind(existing.GetKind())
+		}
+		annotations := updated.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[logicalcluster.AnnotationKey] = info.cluster
+		if _, found := annotations[shardAnnotationKey]; !found {
+			annotations[shardAnnotationKey] = info.shard
+		}
+		updated.SetAnnotations(annotations)
+	}
+
+	s.recordLocked(key, watch.Modified, updated)
+	writeObject(w, http.StatusOK, updated)
+}
+
+func (s *Server) serveDelete(w http.ResponseWriter, info *requestInfo) {
+	if info.shard == wildcard || info.cluster == wildcard {
+		writeError(w, apierrors.NewBadRequest("a shard and a logical cluster are required to delete an object"))
+		return
+	}
+
+	s.lock.Lock()
+	key := info.key(info.name)
+	existing, found := s.objects[key]
+	if found {
+		s.recordLocked(key, watch.Deleted, existing.DeepCopy())
+	}
+	s.lock.Unlock()
+	if !found {
+		writeError(w, apierrors.NewNotFound(info.resource.GroupResource(), info.name))
+		return
+	}
+
+	writeObject(w, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Details: &metav1.StatusDetails{
+			Name:  info.name,
+			Group: info.resource.Group,
+			Kind:  info.resource.Resource,
+			UID:   existing.GetUID(),
+		},
+	})
+}
+
+// recordLocked bumps the resource version, stores the object under the key, or removes it
+// for deletions, and notifies the watches.
+func (s *Server) recordLocked(key objectKey, eventType watch.EventType, obj *unstructured.Unstructured) {
+	s.resourceVersion++
+	obj.SetResourceVersion(strconv.FormatInt(s.resourceVersion, 10))
+	if eventType == watch.Deleted {
+		delete(s.objects, key)
+	} else {
+		s.objects[key] = obj.DeepCopy()
+	}
+	s.events = append(s.events, event{key: key, eventType: eventType, object: obj.DeepCopy(), resourceVersion: s.resourceVersion})
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) listLocked(info *requestInfo, labelSelector labels.Selector, fieldSelector fields.Selector) []*unstructured.Unstructured {
+	var keys []objectKey
+	for key, obj := range s.objects {
+		if info.matches(key) && matchesSelectors(obj, labelSelector, fieldSelector) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.shard != b.shard {
+			return a.shard < b.shard
+		}
+		if a.cluster != b.cluster {
+			return a.cluster < b.cluster
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+
+	items := make([]*unstructured.Unstructured, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, s.objects[key].DeepCopy())
+	}
+	return items
+}
+
+// requestInfo is the scope of a request, parsed from its path.
+type requestInfo struct {
+	shard       string
+	cluster     string
+	resource    schema.GroupVersionResource
+	namespace   string
+	name        string
+	subresource string
+}
+
+// parseRequestInfo parses paths of the form
+// [/services/cache]/shards/{shard}/clusters/{cluster}/{api/{version}|apis/{group}/{version}}/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]].
+func parseRequestInfo(path string) (*requestInfo, error) {
+	path = strings.TrimPrefix(path, servicePrefix)
+	if !strings.HasPrefix(path, "/shards/") {
+		return nil, fmt.Errorf("a shard name is required")
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/shards/"), "/"), "/")
+	if segments[0] == "" {
+		return nil, fmt.Errorf("a shard name is required")
+	}
+	info := &requestInfo{shard: segments[0]}
+	segments = segments[1:]
+
+	if len(segments) < 2 || segments[0] != "clusters" || segments[1] == "" {
+		return nil, fmt.Errorf("a logical cluster is required in path %s", path)
+	}
+	info.cluster = segments[1]
+	segments = segments[2:]
+
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		info.resource.Version = segments[1]
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		info.resource.Group = segments[1]
+		info.resource.Version = segments[2]
+		segments = segments[3:]
+	default:
+		return nil, fmt.Errorf("unable to parse API group and version from path %s", path)
+	}
+
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		info.namespace = segments[1]
+		segments = segments[2:]
+	}
+	if len(segments) == 0 || len(segments) > 3 {
+		return nil, fmt.Errorf("unable to parse resource from path %s", path)
+	}
+	info.resource.Resource = segments[0]
+	if len(segments) > 1 {
+		info.name = segments[1]
+	}
+	if len(segments) > 2 {
+		info.subresource = segments[2]
+	}
+	return info, nil
+}
+
+func (i *requestInfo) key(name string) objectKey {
+	return objectKey{
+		resource:  i.resource.GroupResource(),
+		shard:     i.shard,
+		cluster:   i.cluster,
+		namespace: i.namespace,
+		name:      name,
+	}
+}
+
+// matches returns whether the key is in the scope of the request, taking wildcards into account.
+func (i *requestInfo) matches(key objectKey) bool {
+	return key.resource == i.resource.GroupResource() &&
+		(i.shard == wildcard || i.shard == key.shard) &&
+		(i.cluster == wildcard || i.cluster == key.cluster) &&
+		(i.namespace == "" || i.namespace == key.namespace)
+}
+
+func isWatch(req *http.Request) bool {
+	w := req.URL.Query().Get("watch")
+	return w == "true" || w == "1"
+}
+
+func selectorsFrom(req *http.Request) (labels.Selector, fields.Selector, error) {
+	labelSelector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(req.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return labelSelector, fieldSelector, nil
+}
+
+func matchesSelectors(obj *unstructured.Unstructured, labelSelector labels.Selector, fieldSelector fields.Selector) bool {
+	return labelSelector.Matches(labels.Set(obj.GetLabels())) &&
+		fieldSelector.Matches(fields.Set{"metadata.name": obj.GetName(), "metadata.namespace": obj.GetNamespace()})
+}
+
+func decodeObject(req *http.Request) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := json.NewDecoder(req.Body).Decode(&obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %w", err)
+	}
+	return obj, nil
+}
+
+func writeObject(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := apierrors.NewInternalError(err).Status()
+	if statusErr, ok := err.(apierrors.APIStatus); ok {
+		status = statusErr.Status()
+	}
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	writeObject(w, int(status.Code), &status)
+}
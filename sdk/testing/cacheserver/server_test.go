@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacheserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
+)
+
+const wait = 30 * time.Second
+
+var apiExportsGVR = apisv1alpha1.SchemeGroupVersion.WithResource("apiexports")
+
+func newAPIExport(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apisv1alpha1.SchemeGroupVersion.String(),
+		"kind":       "APIExport",
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+}
+
+func dynamicClient(t *testing.T, s *Server, shard, cluster string) dynamic.Interface {
+	t.Helper()
+
+	config := s.Config()
+	config.Host += "/services/cache/shards/" + shard + "/clusters/" + cluster
+	client, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+	return client
+}
+
+func TestCRUD(t *testing.T) {
+	s := New(t)
+	ctx := context.Background()
+	client := dynamicClient(t, s, "amber", "root:org").Resource(apiExportsGVR)
+
+	created, err := client.Create(ctx, newAPIExport("export"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "root:org", created.GetAnnotations()[logicalcluster.AnnotationKey])
+	require.Equal(t, "amber", created.GetAnnotations()[shardAnnotationKey])
+	require.NotEmpty(t, created.GetUID())
+	require.NotEmpty(t, created.GetResourceVersion())
+
+	_, err = client.Create(ctx, newAPIExport("export"), metav1.CreateOptions{})
+	require.True(t, apierrors.IsAlreadyExists(err), "unexpected error: %v", err)
+
+	got, err := client.Get(ctx, "export", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created, got)
+
+	got.SetLabels(map[string]string{"updated": "true"})
+	updated, err := client.Update(ctx, got, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", updated.GetLabels()["updated"])
+	require.Equal(t, created.GetUID(), updated.GetUID())
+
+	_, err = client.Update(ctx, got, metav1.UpdateOptions{})
+	require.True(t, apierrors.IsConflict(err), "unexpected error: %v", err)
+
+	withStatus := updated.DeepCopy()
+	withStatus.SetLabels(nil)
+	require.NoError(t, unstructured.SetNestedField(withStatus.Object, "ready", "status", "phase"))
+	statusUpdated, err := client.UpdateStatus(ctx, withStatus, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", statusUpdated.GetLabels()["updated"], "status updates must not change anything but the status")
+	phase, _, _ := unstructured.NestedString(statusUpdated.Object, "status", "phase")
+	require.Equal(t, "ready", phase)
+
+	_, found := s.Get("amber", "root:org", apiExportsGVR.GroupResource(), "", "export")
+	require.True(t, found)
+
+	require.NoError(t, client.Delete(ctx, "export", metav1.DeleteOptions{}))
+	_, err = client.Get(ctx, "export", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+	err = client.Delete(ctx, "export", metav1.DeleteOptions{})
+	require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+}
+
+func TestWildcardList(t *testing.T) {
+	s := New(t)
+	ctx := context.Background()
+
+	for _, scope := range []struct{ shard, cluster string }{{"amber", "root:a"}, {"amber", "root:b"}, {"sapphire", "root:a"}} {
+		_, err := dynamicClient(t, s, scope.shard, scope.cluster).Resource(apiExportsGVR).Create(ctx, newAPIExport("export"), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		shard, cluster string
+		want           int
+	}{
+		{"amber", "root:a", 1},
+		{"amber", "*", 2},
+		{"*", "root:a", 2},
+		{"*", "*", 3},
+		{"emerald", "*", 0},
+	}
+	for _, tt := range tests {
+		list, err := dynamicClient(t, s, tt.shard, tt.cluster).Resource(apiExportsGVR).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, list.Items, tt.want, "shard %s, cluster %s", tt.shard, tt.cluster)
+	}
+
+	list, err := dynamicClient(t, s, "*", "*").Resource(apiExportsGVR).List(ctx, metav1.ListOptions{FieldSelector: "metadata.name=other"})
+	require.NoError(t, err)
+	require.Empty(t, list.Items)
+
+	require.Len(t, s.List(apiExportsGVR.GroupResource()), 3)
+}
+
+func TestTypedClient(t *testing.T) {
+	s := New(t)
+	ctx := context.Background()
+
+	_, err := s.Add("amber", apiExportsGVR, &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "export",
+			"annotations": map[string]interface{}{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}})
+	require.NoError(t, err)
+
+	config := s.Config()
+	config.Host += "/services/cache/shards/amber"
+	client, err := kcpclientset.NewForConfig(config)
+	require.NoError(t, err)
+
+	exports, err := client.ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, exports.Items, 1)
+	require.Equal(t, "export", exports.Items[0].Name)
+
+	export, err := client.Cluster(logicalcluster.NewPath("root:org")).ApisV1alpha1().APIExports().Get(ctx, "export", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "export", export.Name)
+
+	stored, found := s.Get("amber", "root:org", apiExportsGVR.GroupResource(), "", "export")
+	require.True(t, found)
+	require.Equal(t, "APIExport", stored.GetKind(), "kinds of kcp APIs are known upfront")
+
+	bindings, err := client.ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, bindings.Items)
+}
+
+func TestWatch(t *testing.T) {
+	s := New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+
+	_, err := dynamicClient(t, s, "amber", "root:a").Resource(apiExportsGVR).Create(ctx, newAPIExport("existing"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	w, err := dynamicClient(t, s, "*", "*").Resource(apiExportsGVR).Watch(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	client := dynamicClient(t, s, "sapphire", "root:b").Resource(apiExportsGVR)
+	created, err := client.Create(ctx, newAPIExport("export"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, client.Delete(ctx, "export", metav1.DeleteOptions{}))
+
+	for _, want := range []struct {
+		eventType watch.EventType
+		name      string
+	}{{watch.Added, "existing"}, {watch.Added, "export"}, {watch.Deleted, "export"}} {
+		select {
+		case e := <-w.ResultChan():
+			require.Equal(t, want.eventType, e.Type)
+			require.Equal(t, want.name, e.Object.(*unstructured.Unstructured).GetName())
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s event of %s", want.eventType, want.name)
+		}
+	}
+
+	// resuming from a resource version replays the events after it.
+	resumed, err := dynamicClient(t, s, "sapphire", "*").Resource(apiExportsGVR).Watch(ctx, metav1.ListOptions{ResourceVersion: created.GetResourceVersion()})
+	require.NoError(t, err)
+	defer resumed.Stop()
+	select {
+	case e := <-resumed.ResultChan():
+		require.Equal(t, watch.Deleted, e.Type)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the replayed event")
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	s := New(t)
+
+	for _, path := range []string{"/livez", "/readyz", "/healthz"} {
+		resp, err := http.Get(s.URL() + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	for _, path := range []string{
+		"/services/cache/clusters/root/apis/apis.kcp.io/v1alpha1/apiexports",
+		"/services/cache/shards/amber/apis/apis.kcp.io/v1alpha1/apiexports",
+		"/services/cache/shards/*/clusters/*/apis/apis.kcp.io/v1alpha1/apiexports/export",
+	} {
+		resp, err := http.Get(s.URL() + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+	}
+
+	_, err := dynamicClient(t, s, "*", "root").Resource(schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apiexports"}).Create(context.Background(), newAPIExport("export"), metav1.CreateOptions{})
+	require.True(t, apierrors.IsBadRequest(err), "unexpected error: %v", err)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exportcompat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// updatedLabelKey is the label set on the sample objects to exercise updates.
+const updatedLabelKey = "exportcompat.kcp.io/updated"
+
+// resourceFor returns the resource of obj, and the CRD defining it.
+func (h *harness) resourceFor(obj *unstructured.Unstructured) (schema.GroupVersionResource, *apiextensionsv1.CustomResourceDefinition, error) {
+	gvk := obj.GroupVersionKind()
+	crd, found := h.crds[gvk.GroupKind()]
+	if !found {
+		return schema.GroupVersionResource{}, nil, fmt.Errorf("no CRD for %s", gvk.GroupKind())
+	}
+	return gvk.GroupVersion().WithResource(crd.Spec.Names.Plural), crd, nil
+}
+
+// resourceClient returns the client for obj's resource in the given workspace, scoped
+// to obj's namespace if the resource is namespaced.
+func (h *harness) resourceClient(t *testing.T, path logicalcluster.Path, obj *unstructured.Unstructured) dynamic.ResourceInterface {
+	t.Helper()
+
+	gvr, crd, err := h.resourceFor(obj)
+	require.NoError(t, err)
+	return scopedClient(h.dynamicClientFor(t, path).Resource(gvr), crd, obj)
+}
+
+func scopedClient(client dynamic.NamespaceableResourceInterface, crd *apiextensionsv1.CustomResourceDefinition, obj *unstructured.Unstructured) dynamic.ResourceInterface {
+	if crd.Spec.Scope == apiextensionsv1.ClusterScoped {
+		return client
+	}
+	return client.Namespace(obj.GetNamespace())
+}
+
+// sampleObject returns a copy of obj ready to be created.
+func (h *harness) sampleObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	if _, crd, err := h.resourceFor(obj); err == nil && crd.Spec.Scope == apiextensionsv1.NamespaceScoped && obj.GetNamespace() == "" {
+		obj.SetNamespace(metav1.NamespaceDefault)
+	}
+	return obj
+}
+
+func (h *harness) checkCRUD(ctx context.Context, t *testing.T) {
+	for _, sample := range h.provider.Objects {
+		obj := h.sampleObject(sample)
+		client := h.resourceClient(t, h.consumerPath, obj)
+		desc := fmt.Sprintf("%s %s", obj.GroupVersionKind().Kind, objectName(obj))
+
+		created, err := client.Create(ctx, obj, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create %s", desc)
+
+		got, err := client.Get(ctx, created.GetName(), metav1.GetOptions{})
+		require.NoError(t, err, "failed to get %s", desc)
+		require.Equal(t, created.GetUID(), got.GetUID(), "got a different %s than created", desc)
+
+		labels := got.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[updatedLabelKey] = "true"
+		got.SetLabels(labels)
+		updated, err := client.Update(ctx, got, metav1.UpdateOptions{})
+		require.NoError(t, err, "failed to update %s", desc)
+		require.Equal(t, "true", updated.GetLabels()[updatedLabelKey], "update of %s was not persisted", desc)
+
+		list, err := client.List(ctx, metav1.ListOptions{LabelSelector: updatedLabelKey + "=true"})
+		require.NoError(t, err, "failed to list %s", obj.GroupVersionKind().Kind)
+		found := false
+		for _, item := range list.Items {
+			found = found || item.GetUID() == created.GetUID()
+		}
+		require.True(t, found, "%s not found in list", desc)
+
+		err = client.Delete(ctx, created.GetName(), metav1.DeleteOptions{})
+		require.NoError(t, err, "failed to delete %s", desc)
+	}
+}
+
+func (h *harness) checkPermissionClaims(ctx context.Context, t *testing.T) {
+	if len(h.export.Spec.PermissionClaims) == 0 {
+		t.Skip("APIExport has no permission claims")
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(h.configFor(h.consumerPath))
+	require.NoError(t, err, "failed to create discovery client for %s", h.consumerPath)
+	resourceLists, err := discoveryClient.ServerPreferredResources()
+	require.NoError(t, err, "failed to discover resources in %s", h.consumerPath)
+
+	for _, vw := range h.export.Status.VirtualWorkspaces {
+		cfg := h.configFor(h.consumerCluster.Path())
+		cfg.Host = vw.URL + h.consumerCluster.Path().RequestPath()
+		client, err := dynamic.NewForConfig(cfg)
+		require.NoError(t, err, "failed to create client for virtual workspace %s", vw.URL)
+
+		for _, claim := range h.export.Spec.PermissionClaims {
+			gvr, found := preferredResource(resourceLists, claim.Group, claim.Resource)
+			require.True(t, found, "claimed resource %s is not served in %s", claim.GroupResource, h.consumerPath)
+
+			_, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+			require.NoError(t, err, "failed to list claimed resource %s through virtual workspace %s", gvr, vw.URL)
+		}
+	}
+}
+
+// preferredResource returns the preferred version of the given resource.
+func preferredResource(resourceLists []*metav1.APIResourceList, group, resource string) (schema.GroupVersionResource, bool) {
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || gv.Group != group {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if r.Name == resource {
+				return gv.WithResource(resource), true
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+func (h *harness) checkValidationParity(ctx context.Context, t *testing.T) {
+	check := func(sample *unstructured.Unstructured, valid bool) {
+		obj := h.sampleObject(sample)
+		desc := fmt.Sprintf("%s %s", obj.GroupVersionKind().Kind, objectName(obj))
+
+		_, referenceErr := h.resourceClient(t, h.referencePath, obj).Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		_, exportErr := h.resourceClient(t, h.consumerPath, obj).Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+
+		switch {
+		case valid && referenceErr != nil:
+			t.Errorf("sample %s is rejected by the CRD: %v", desc, referenceErr)
+		case !valid && referenceErr == nil:
+			t.Errorf("invalid sample %s is accepted by the CRD", desc)
+		}
+		switch {
+		case referenceErr == nil && exportErr != nil:
+			t.Errorf("%s is accepted by the CRD, but rejected by the APIExport: %v", desc, exportErr)
+		case referenceErr != nil && exportErr == nil:
+			t.Errorf("%s is rejected by the CRD, but accepted by the APIExport: %v", desc, referenceErr)
+		}
+	}
+
+	for _, obj := range h.provider.Objects {
+		check(obj, true)
+	}
+	for _, obj := range h.provider.InvalidObjects {
+		check(obj, false)
+	}
+}
+
+func (h *harness) checkConversionParity(ctx context.Context, t *testing.T) {
+	for _, sample := range h.provider.Objects {
+		obj := h.sampleObject(sample)
+		desc := fmt.Sprintf("%s %s", obj.GroupVersionKind().Kind, objectName(obj))
+		_, crd, err := h.resourceFor(obj)
+		require.NoError(t, err)
+
+		referenceClient := h.resourceClient(t, h.referencePath, obj)
+		exportClient := h.resourceClient(t, h.consumerPath, obj)
+
+		referenceObj, err := referenceClient.Create(ctx, obj, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create %s from the CRD", desc)
+		exportObj, err := exportClient.Create(ctx, obj, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create %s from the APIExport", desc)
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+			gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version.Name, Resource: crd.Spec.Names.Plural}
+
+			reference, err := scopedClient(h.dynamicClientFor(t, h.referencePath).Resource(gvr), crd, obj).Get(ctx, referenceObj.GetName(), metav1.GetOptions{})
+			require.NoError(t, err, "failed to get %s as %s from the CRD", desc, gvr.Version)
+			exported, err := scopedClient(h.dynamicClientFor(t, h.consumerPath).Resource(gvr), crd, obj).Get(ctx, exportObj.GetName(), metav1.GetOptions{})
+			require.NoError(t, err, "failed to get %s as %s from the APIExport", desc, gvr.Version)
+
+			if diff := cmp.Diff(withoutMetadata(reference), withoutMetadata(exported)); diff != "" {
+				t.Errorf("%s as %s differs between CRD and APIExport (-CRD +APIExport):\n%s", desc, gvr.Version, diff)
+			}
+		}
+
+		require.NoError(t, referenceClient.Delete(ctx, referenceObj.GetName(), metav1.DeleteOptions{}), "failed to delete %s from the CRD", desc)
+		require.NoError(t, exportClient.Delete(ctx, exportObj.GetName(), metav1.DeleteOptions{}), "failed to delete %s from the APIExport", desc)
+	}
+}
+
+// withoutMetadata returns the content of obj without the metadata, which naturally
+// differs between workspaces.
+func withoutMetadata(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().UnstructuredContent()
+	delete(content, "metadata")
+	return content
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName() + "*"
+	}
+	if obj.GetNamespace() == "" {
+		return name
+	}
+	return obj.GetNamespace() + "/" + name
+}
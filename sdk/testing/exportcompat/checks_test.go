@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exportcompat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPreferredResource(t *testing.T) {
+	resourceLists := []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps"}, {Name: "secrets"}}},
+		{GroupVersion: "wildwest.dev/v1beta1", APIResources: []metav1.APIResource{{Name: "cowboys"}}},
+	}
+
+	gvr, found := preferredResource(resourceLists, "", "secrets")
+	require.True(t, found)
+	require.Equal(t, schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, gvr)
+
+	gvr, found = preferredResource(resourceLists, "wildwest.dev", "cowboys")
+	require.True(t, found)
+	require.Equal(t, schema.GroupVersionResource{Group: "wildwest.dev", Version: "v1beta1", Resource: "cowboys"}, gvr)
+
+	_, found = preferredResource(resourceLists, "wildwest.dev", "sheriffs")
+	require.False(t, found)
+}
+
+func TestSampleObject(t *testing.T) {
+	h := &harness{crds: map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition{
+		{Group: "wildwest.dev", Kind: "Cowboy"}: {Spec: apiextensionsv1.CustomResourceDefinitionSpec{Scope: apiextensionsv1.NamespaceScoped}},
+		{Group: "wildwest.dev", Kind: "Town"}:   {Spec: apiextensionsv1.CustomResourceDefinitionSpec{Scope: apiextensionsv1.ClusterScoped}},
+	}}
+
+	cowboy := &unstructured.Unstructured{}
+	cowboy.SetAPIVersion("wildwest.dev/v1alpha1")
+	cowboy.SetKind("Cowboy")
+	cowboy.SetName("luckyluke")
+	cowboy.SetResourceVersion("42")
+	sample := h.sampleObject(cowboy)
+	require.Equal(t, "default", sample.GetNamespace())
+	require.Empty(t, sample.GetResourceVersion())
+	require.Equal(t, "42", cowboy.GetResourceVersion(), "input must not be mutated")
+	require.Equal(t, "default/luckyluke", objectName(sample))
+
+	town := &unstructured.Unstructured{}
+	town.SetAPIVersion("wildwest.dev/v1alpha1")
+	town.SetKind("Town")
+	town.SetGenerateName("daisy-")
+	sample = h.sampleObject(town)
+	require.Empty(t, sample.GetNamespace())
+	require.Equal(t, "daisy-*", objectName(sample))
+}
+
+func TestWithoutMetadata(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wildwest.dev/v1alpha1",
+		"kind":       "Cowboy",
+		"metadata":   map[string]interface{}{"name": "luckyluke"},
+		"spec":       map[string]interface{}{"intent": "should catch joe"},
+	}}
+
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "wildwest.dev/v1alpha1",
+		"kind":       "Cowboy",
+		"spec":       map[string]interface{}{"intent": "should catch joe"},
+	}, withoutMetadata(obj))
+	require.Contains(t, obj.Object, "metadata", "input must not be mutated")
+}
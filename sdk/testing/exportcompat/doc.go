@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exportcompat is a test harness for API providers moving their
+// CustomResourceDefinitions to an APIExport. Providers run it in their CI to verify
+// that the exported APIs behave like the original CRDs:
+//
+//   - the APIResourceSchemas and the APIExport are applied in a provider workspace,
+//   - a consumer workspace binds to the APIExport, accepting all permission claims,
+//   - the sample objects are created, read, updated, listed and deleted in the consumer
+//     workspace,
+//   - every accepted permission claim is exercised through the APIExport virtual workspace,
+//   - the original CRDs are applied in a reference workspace, and validation and
+//     conversion of the sample objects are compared between both workspaces.
+//
+// By default, the harness runs against a kcp server started in the test process by
+// kcp's e2e framework (github.com/kcp-dev/kcp/test/e2e/framework):
+//
+//	framework.RunExportCompat(t, exportcompat.Provider{
+//		CRDs:           []*apiextensionsv1.CustomResourceDefinition{widgetsCRD},
+//		Objects:        []*unstructured.Unstructured{validWidget},
+//		InvalidObjects: []*unstructured.Unstructured{widgetWithoutSpec},
+//	})
+//
+// Run takes any other kcp server implementing Server, e.g. a shared server of a CI
+// environment, and the workspace to create the test workspaces in:
+//
+//	exportcompat.Run(t, server, logicalcluster.NewPath("root:ci"), provider)
+//
+// This package only depends on client-go and the kcp SDK so that it does not pull the
+// kcp server into the dependencies of a provider's module unless it runs kcp in-process.
+package exportcompat
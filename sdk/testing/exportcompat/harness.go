@@ -0,0 +1,325 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exportcompat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned"
+)
+
+const (
+	// defaultExportName is the name of the APIExport created if Provider.Export is not set.
+	defaultExportName = "exportcompat"
+	// schemaPrefix is the prefix of the APIResourceSchemas derived from Provider.CRDs.
+	schemaPrefix = "exportcompat"
+
+	pollInterval = 100 * time.Millisecond
+)
+
+// Server is a running kcp server. The RunningServer of kcp's e2e framework implements it, and
+// framework.RunExportCompat runs the harness against one started in the test process.
+type Server interface {
+	// BaseConfig returns a config with admin access to the server, without a logical
+	// cluster in its host.
+	BaseConfig(t *testing.T) *rest.Config
+}
+
+// Provider describes the APIs under test.
+type Provider struct {
+	// CRDs are the original CustomResourceDefinitions of the provider. They are the
+	// reference the exported APIs are compared against.
+	CRDs []*apiextensionsv1.CustomResourceDefinition
+
+	// Schemas are the APIResourceSchemas of the APIExport. If empty, they are derived
+	// from CRDs.
+	Schemas []*apisv1alpha1.APIResourceSchema
+
+	// Export is the APIExport under test. If nil, an APIExport exporting all Schemas
+	// is created. If its latestResourceSchemas are empty, they are set to Schemas.
+	// All of its permission claims are accepted by the consumer.
+	Export *apisv1alpha1.APIExport
+
+	// Objects are valid sample objects of the exported APIs. They are exercised with
+	// CRUD operations, and they are expected to be accepted and converted identically
+	// by the exported APIs and the CRDs. Namespaced objects without namespace are
+	// created in the default namespace.
+	Objects []*unstructured.Unstructured
+
+	// InvalidObjects are sample objects expected to be rejected by both the exported
+	// APIs and the CRDs.
+	InvalidObjects []*unstructured.Unstructured
+}
+
+// Run sets up provider, consumer and reference workspaces below parent and runs the
+// compatibility checks for the given provider as subtests of t.
+func Run(t *testing.T, server Server, parent logicalcluster.Path, provider Provider) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	h := &harness{
+		config:   server.BaseConfig(t),
+		provider: provider,
+		crds:     map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition{},
+	}
+	for _, crd := range provider.CRDs {
+		h.crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+	}
+
+	providerWorkspace := h.createWorkspace(ctx, t, parent, "exportcompat-provider-")
+	consumerWorkspace := h.createWorkspace(ctx, t, parent, "exportcompat-consumer-")
+	referenceWorkspace := h.createWorkspace(ctx, t, parent, "exportcompat-reference-")
+	h.providerPath = parent.Join(providerWorkspace.Name)
+	h.consumerPath = parent.Join(consumerWorkspace.Name)
+	h.consumerCluster = logicalcluster.Name(consumerWorkspace.Spec.Cluster)
+	h.referencePath = parent.Join(referenceWorkspace.Name)
+
+	h.applyExport(ctx, t)
+	h.bind(ctx, t)
+	h.applyCRDs(ctx, t)
+
+	t.Run("crud", func(t *testing.T) { h.checkCRUD(ctx, t) })
+	t.Run("permission claims", func(t *testing.T) { h.checkPermissionClaims(ctx, t) })
+	t.Run("validation parity", func(t *testing.T) { h.checkValidationParity(ctx, t) })
+	t.Run("conversion parity", func(t *testing.T) { h.checkConversionParity(ctx, t) })
+}
+
+type harness struct {
+	config   *rest.Config
+	provider Provider
+	crds     map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition
+
+	providerPath    logicalcluster.Path
+	consumerPath    logicalcluster.Path
+	consumerCluster logicalcluster.Name
+	referencePath   logicalcluster.Path
+
+	// export is the APIExport as created in the provider workspace.
+	export *apisv1alpha1.APIExport
+}
+
+// configFor returns a copy of the harness config pointing to the given logical cluster.
+func (h *harness) configFor(path logicalcluster.Path) *rest.Config {
+	cfg := rest.CopyConfig(h.config)
+	cfg.Host = strings.TrimSuffix(cfg.Host, "/") + path.RequestPath()
+	return cfg
+}
+
+func (h *harness) kcpClientFor(t *testing.T, path logicalcluster.Path) kcpclientset.Interface {
+	t.Helper()
+	client, err := kcpclientset.NewForConfig(h.configFor(path))
+	require.NoError(t, err, "failed to create kcp client for %s", path)
+	return client
+}
+
+func (h *harness) dynamicClientFor(t *testing.T, path logicalcluster.Path) dynamic.Interface {
+	t.Helper()
+	client, err := dynamic.NewForConfig(h.configFor(path))
+	require.NoError(t, err, "failed to create dynamic client for %s", path)
+	return client
+}
+
+func (h *harness) createWorkspace(ctx context.Context, t *testing.T, parent logicalcluster.Path, generateName string) *tenancyv1alpha1.Workspace {
+	t.Helper()
+
+	client := h.kcpClientFor(t, parent)
+	ws, err := client.TenancyV1alpha1().Workspaces().Create(ctx, &tenancyv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: generateName},
+		Spec: tenancyv1alpha1.WorkspaceSpec{
+			Type: tenancyv1alpha1.WorkspaceTypeReference{
+				Name: "universal",
+				Path: "root",
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create workspace under %s", parent)
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+		defer cancel()
+		if err := client.TenancyV1alpha1().Workspaces().Delete(ctx, ws.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			t.Logf("failed to delete workspace %s: %v", parent.Join(ws.Name), err)
+		}
+	})
+
+	eventually(t, func() (bool, string) {
+		ws, err = client.TenancyV1alpha1().Workspaces().Get(ctx, ws.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		return ws.Status.Phase == corev1alpha1.LogicalClusterPhaseReady, fmt.Sprintf("phase is %q", ws.Status.Phase)
+	}, "workspace %s did not become ready", parent.Join(ws.Name))
+
+	t.Logf("Created workspace %s", parent.Join(ws.Name))
+	return ws
+}
+
+func (h *harness) applyExport(ctx context.Context, t *testing.T) {
+	t.Helper()
+
+	schemas := h.provider.Schemas
+	if len(schemas) == 0 {
+		for _, crd := range h.provider.CRDs {
+			s, err := apisv1alpha1.CRDToAPIResourceSchema(crd, schemaPrefix)
+			require.NoError(t, err, "failed to convert CRD %s to an APIResourceSchema", crd.Name)
+			schemas = append(schemas, s)
+		}
+	}
+	require.NotEmpty(t, schemas, "the provider has neither APIResourceSchemas nor CRDs")
+
+	client := h.kcpClientFor(t, h.providerPath)
+	for _, s := range schemas {
+		s = s.DeepCopy()
+		s.ResourceVersion = ""
+		_, err := client.ApisV1alpha1().APIResourceSchemas().Create(ctx, s, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create APIResourceSchema %s", s.Name)
+	}
+
+	export := &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: defaultExportName}}
+	if h.provider.Export != nil {
+		export = h.provider.Export.DeepCopy()
+		export.ResourceVersion = ""
+	}
+	if len(export.Spec.LatestResourceSchemas) == 0 {
+		for _, s := range schemas {
+			export.Spec.LatestResourceSchemas = append(export.Spec.LatestResourceSchemas, s.Name)
+		}
+	}
+	_, err := client.ApisV1alpha1().APIExports().Create(ctx, export, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create APIExport %s", export.Name)
+
+	eventually(t, func() (bool, string) {
+		h.export, err = client.ApisV1alpha1().APIExports().Get(ctx, export.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		return h.export.Status.IdentityHash != "" && len(h.export.Status.VirtualWorkspaces) > 0, "identity hash or virtual workspace URLs not set"
+	}, "APIExport %s did not become ready", h.providerPath.Join(export.Name))
+}
+
+func (h *harness) bind(ctx context.Context, t *testing.T) {
+	t.Helper()
+
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: h.export.Name},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.BindingReference{
+				Export: &apisv1alpha1.ExportBindingReference{
+					Path: h.providerPath.String(),
+					Name: h.export.Name,
+				},
+			},
+		},
+	}
+	for _, claim := range h.export.Spec.PermissionClaims {
+		binding.Spec.PermissionClaims = append(binding.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+			PermissionClaim: claim,
+			State:           apisv1alpha1.ClaimAccepted,
+		})
+	}
+
+	client := h.kcpClientFor(t, h.consumerPath)
+	_, err := client.ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create APIBinding %s", binding.Name)
+
+	eventually(t, func() (bool, string) {
+		binding, err = client.ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound, fmt.Sprintf("phase is %q", binding.Status.Phase)
+	}, "APIBinding %s did not become bound", h.consumerPath.Join(binding.Name))
+
+	h.waitForResources(ctx, t, h.consumerPath)
+}
+
+func (h *harness) applyCRDs(ctx context.Context, t *testing.T) {
+	t.Helper()
+
+	cfg := h.configFor(h.referencePath)
+	client, err := apiextensionsclientset.NewForConfig(cfg)
+	require.NoError(t, err, "failed to create apiextensions client for %s", h.referencePath)
+
+	for _, crd := range h.provider.CRDs {
+		crd = crd.DeepCopy()
+		crd.ResourceVersion = ""
+		_, err := client.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create CRD %s", crd.Name)
+	}
+
+	h.waitForResources(ctx, t, h.referencePath)
+}
+
+// waitForResources waits until all served versions of the provider's resources can be
+// listed in the given workspace.
+func (h *harness) waitForResources(ctx context.Context, t *testing.T, path logicalcluster.Path) {
+	t.Helper()
+
+	client := h.dynamicClientFor(t, path)
+	for _, crd := range h.provider.CRDs {
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+			gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version.Name, Resource: crd.Spec.Names.Plural}
+			eventually(t, func() (bool, string) {
+				if _, err := client.Resource(gvr).List(ctx, metav1.ListOptions{}); err != nil {
+					return false, err.Error()
+				}
+				return true, ""
+			}, "%s is not served in %s", gvr, path)
+		}
+	}
+}
+
+// eventually polls condition until it returns true, failing the test with the last
+// returned reason after wait.ForeverTestTimeout.
+func eventually(t *testing.T, condition func() (bool, string), msgAndArgs ...interface{}) {
+	t.Helper()
+
+	var reason string
+	err := wait.PollImmediate(pollInterval, wait.ForeverTestTimeout, func() (bool, error) {
+		var done bool
+		done, reason = condition()
+		return done, nil
+	})
+	if err != nil {
+		require.Fail(t, fmt.Sprintf("condition not met: %s", reason), msgAndArgs...)
+	}
+}
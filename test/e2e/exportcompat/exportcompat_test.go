@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exportcompat
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/sdk/testing/exportcompat"
+	"github.com/kcp-dev/kcp/test/e2e/fixtures/wildwest"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+)
+
+func TestExportCompatibility(t *testing.T) {
+	t.Parallel()
+	framework.Suite(t, "control-plane")
+
+	server := framework.SharedKcpServer(t)
+	orgPath, _ := framework.NewOrganizationFixture(t, server)
+
+	exportcompat.Run(t, server, orgPath, wildwestProvider(t))
+}
+
+func TestExportCompatibilityInProcess(t *testing.T) {
+	t.Parallel()
+	framework.Suite(t, "control-plane")
+
+	framework.RunExportCompat(t, wildwestProvider(t))
+}
+
+// wildwestProvider exports the cowboys of the wildwest fixture, claiming configmaps.
+func wildwestProvider(t *testing.T) exportcompat.Provider {
+	t.Helper()
+
+	cowboy := func(name string, intent interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "wildwest.dev/v1alpha1",
+			"kind":       "Cowboy",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       map[string]interface{}{"intent": intent},
+		}}
+	}
+
+	return exportcompat.Provider{
+		CRDs: []*apiextensionsv1.CustomResourceDefinition{
+			wildwest.CRD(t, metav1.GroupResource{Group: "wildwest.dev", Resource: "cowboys"}),
+		},
+		Export: &apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "wildwest.dev"},
+			Spec: apisv1alpha1.APIExportSpec{
+				PermissionClaims: []apisv1alpha1.PermissionClaim{
+					{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true},
+				},
+			},
+		},
+		Objects: []*unstructured.Unstructured{
+			cowboy("luckyluke", "should catch joe"),
+		},
+		InvalidObjects: []*unstructured.Unstructured{
+			cowboy("averell", 42),
+		},
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/stretchr/testify/require"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	kcpapiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned/typed/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -54,3 +55,12 @@ func FakePClusterCreate(t *testing.T, client apiextensionsv1client.CustomResourc
 	err := configcrds.CreateFromFS(ctx, client, rawCustomResourceDefinitions, grs...)
 	require.NoError(t, err)
 }
+
+// CRD returns the CustomResourceDefinition of the given wildwest resource.
+func CRD(t *testing.T, gr metav1.GroupResource) *apiextensionsv1.CustomResourceDefinition {
+	t.Helper()
+
+	crd, err := configcrds.CRD(rawCustomResourceDefinitions, gr)
+	require.NoError(t, err)
+	return crd
+}
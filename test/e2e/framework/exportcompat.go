@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/kcp/sdk/testing/exportcompat"
+)
+
+var _ exportcompat.Server = RunningServer(nil)
+
+// RunExportCompat runs the export compatibility checks of the given provider against a
+// private kcp server started in the test process, below a new organization workspace.
+// Use exportcompat.Run directly to run the checks against another server.
+func RunExportCompat(t *testing.T, provider exportcompat.Provider) {
+	t.Helper()
+
+	server := PrivateKcpServer(t, WithRunInProcess())
+	orgPath, _ := NewOrganizationFixture(t, server)
+	exportcompat.Run(t, server, orgPath, provider)
+}
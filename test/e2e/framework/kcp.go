@@ -447,6 +447,14 @@ func WithCustomArguments(args ...string) KcpConfigOption {
 	}
 }
 
+// WithRunInProcess runs the kcp server in the test process instead of a child process.
+func WithRunInProcess() KcpConfigOption {
+	return func(cfg *kcpConfig) *kcpConfig {
+		cfg.RunInProcess = true
+		return cfg
+	}
+}
+
 // kcpConfig qualify a kcp server to start
 //
 // Deprecated for use outside this package. Prefer PrivateKcpServer().
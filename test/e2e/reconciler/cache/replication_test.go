@@ -44,6 +44,7 @@ import (
 
 	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
 	"github.com/kcp-dev/kcp/pkg/cache/client/shard"
+	"github.com/kcp-dev/kcp/pkg/reconciler/cache/replication"
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/sdk/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
@@ -673,7 +674,7 @@ func (b *replicateResourceScenario) verifyResourceReplicationHelper(ctx context.
 			}
 			return false, err.Error()
 		}
-		t.Logf("Compare if both the original and replicated resources (%s %s/%s) are the same except %s annotation and ResourceVersion", b.gvr, cluster, b.resourceName, genericapirequest.AnnotationKey)
+		t.Logf("Compare if both the original and replicated resources (%s %s/%s) are the same except the replication annotations and ResourceVersion", b.gvr, cluster, b.resourceName)
 		cachedResourceMeta, err := meta.Accessor(cachedResource)
 		if err != nil {
 			return false, err.Error()
@@ -693,6 +694,8 @@ func (b *replicateResourceScenario) verifyResourceReplicationHelper(ctx context.
 		}
 
 		unstructured.RemoveNestedField(cachedResource.Object, "metadata", "annotations", genericapirequest.AnnotationKey)
+		unstructured.RemoveNestedField(cachedResource.Object, "metadata", "annotations", replication.SourceResourceVersionAnnotationKey)
+		unstructured.RemoveNestedField(cachedResource.Object, "metadata", "annotations", replication.ContentHashAnnotationKey)
 		if cachedStatus, ok := cachedResource.Object["status"]; ok && cachedStatus == nil || (cachedStatus != nil && len(cachedStatus.(map[string]interface{})) == 0) {
 			// TODO: worth investigating:
 			// for some reason cached resources have an empty status set whereas the original resources don't
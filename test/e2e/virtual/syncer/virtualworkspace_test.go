@@ -633,6 +633,123 @@ func TestSyncerVirtualWorkspace(t *testing.T) {
 				require.Empty(t, cmp.Diff(expectedModifiedKcpCowboy.Spec, modifiedkcpCowboy.Spec))
 			},
 		},
+		{
+			name: "only resources in namespaces placed on the SyncTarget are visible through syncer virtual workspace",
+			work: func(t *testing.T, testCaseWorkspace logicalcluster.Path) {
+				t.Helper()
+
+				ctx, cancelFunc := context.WithCancel(context.Background())
+				t.Cleanup(cancelFunc)
+
+				wildwestLocationPath, wildwestLocationWorkspace := framework.NewWorkspaceFixture(t, server, testCaseWorkspace, framework.WithName("wildwest-locations"), framework.TODO_WithoutMultiShardSupport())
+				wildwestLocationClusterName := logicalcluster.Name(wildwestLocationWorkspace.Spec.Cluster)
+				logWithTimestampf(t, "Deploying syncer into workspace %s", wildwestLocationPath)
+
+				wildwestSyncer := framework.NewSyncerFixture(t, server, wildwestLocationPath,
+					framework.WithExtraResources("cowboys.wildwest.dev"),
+					// empty APIExports so we do not add global kubernetes APIExport.
+					framework.WithAPIExports(""),
+					framework.WithSyncTargetName("wildwest"),
+					framework.WithSyncedUserWorkspaces(wildwestLocationWorkspace),
+					framework.WithDownstreamPreparation(func(config *rest.Config, isFakePCluster bool) {
+						// Always install the crd regardless of whether the target is
+						// logical or not since cowboys is not a native type.
+						sinkCrdClient, err := apiextensionsclientset.NewForConfig(config)
+						require.NoError(t, err)
+						logWithTimestampf(t, "Installing test CRDs into sink cluster...")
+						fixturewildwest.FakePClusterCreate(t, sinkCrdClient.ApiextensionsV1().CustomResourceDefinitions(), metav1.GroupResource{Group: wildwest.GroupName, Resource: "cowboys"})
+					}),
+				).CreateSyncTargetAndApplyToDownstream(t).StartAPIImporter(t).StartHeartBeat(t)
+
+				logWithTimestampf(t, "Bind wildwest location workspace to itself, placing only namespaces labeled placed=true")
+				framework.NewBindCompute(t, wildwestLocationPath, server,
+					framework.WithAPIExportsWorkloadBindOption(wildwestLocationPath.Join(workloadv1alpha1.ImportedAPISExportName).String()),
+					framework.WithNSSelectorWorkloadBindOption(metav1.LabelSelector{MatchLabels: map[string]string{"placed": "true"}}),
+				).Bind(t)
+
+				wildwestClusterClient, err := wildwestclientset.NewForConfig(server.BaseConfig(t))
+				require.NoError(t, err)
+
+				syncTargetKey := wildwestSyncer.ToSyncTargetKey()
+
+				logWithTimestampf(t, "Create a placed and an unplaced namespace")
+				_, err = kubeClusterClient.Cluster(wildwestLocationPath).CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: "placed", Labels: map[string]string{"placed": "true"}},
+				}, metav1.CreateOptions{})
+				require.NoError(t, err)
+				_, err = kubeClusterClient.Cluster(wildwestLocationPath).CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: "unplaced"},
+				}, metav1.CreateOptions{})
+				require.NoError(t, err)
+
+				logWithTimestampf(t, "Wait for the placed namespace to be scheduled onto the SyncTarget")
+				framework.Eventually(t, func() (bool, string) {
+					ns, err := kubeClusterClient.Cluster(wildwestLocationPath).CoreV1().Namespaces().Get(ctx, "placed", metav1.GetOptions{})
+					require.NoError(t, err)
+					return ns.Labels[workloadv1alpha1.ClusterResourceStateLabelPrefix+syncTargetKey] == string(workloadv1alpha1.ResourceStateSync), fmt.Sprintf("%v", ns.Labels)
+				}, wait.ForeverTestTimeout, time.Millisecond*100)
+
+				logWithTimestampf(t, "Wait for being able to list cowboys in the consumer workspace via direct access")
+				framework.Eventually(t, func() (bool, string) {
+					_, err := wildwestClusterClient.Cluster(wildwestLocationPath).WildwestV1alpha1().Cowboys("").List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return false, err.Error()
+					}
+					return true, ""
+				}, wait.ForeverTestTimeout, time.Millisecond*100)
+
+				logWithTimestampf(t, "Create cowboys luckyluke in the placed namespace and joe in the unplaced namespace")
+				for namespace, name := range map[string]string{"placed": "luckyluke", "unplaced": "joe"} {
+					_, err = wildwestClusterClient.Cluster(wildwestLocationPath).WildwestV1alpha1().Cowboys(namespace).Create(ctx, &wildwestv1alpha1.Cowboy{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: name,
+						},
+					}, metav1.CreateOptions{})
+					require.NoError(t, err)
+				}
+
+				wildwestVWConfig := rest.CopyConfig(server.BaseConfig(t))
+				framework.Eventually(t, func() (found bool, _ string) {
+					var err error
+					wildwestVWConfig.Host, found, err = framework.VirtualWorkspaceURL(ctx, kcpClusterClient, wildwestLocationWorkspace, wildwestSyncer.GetSyncerVirtualWorkspaceURLs())
+					require.NoError(t, err)
+					return found, "Syncer virtual workspace URL not found"
+				}, wait.ForeverTestTimeout, time.Millisecond*100, "Syncer virtual workspace URL not found")
+				vwClusterClient, err := wildwestclientset.NewForConfig(wildwestVWConfig)
+				require.NoError(t, err)
+
+				logWithTimestampf(t, "Wait for luckyluke to show up via virtual workspace wildcard request")
+				framework.Eventually(t, func() (bool, string) {
+					cowboys, err := vwClusterClient.WildwestV1alpha1().Cowboys().List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return false, err.Error()
+					}
+					var names []string
+					for _, cowboy := range cowboys.Items {
+						names = append(names, cowboy.Namespace+"/"+cowboy.Name)
+					}
+					require.NotContains(t, names, "unplaced/joe")
+					return len(names) == 1 && names[0] == "placed/luckyluke", fmt.Sprintf("cowboys: %v", names)
+				}, wait.ForeverTestTimeout, time.Millisecond*100)
+
+				logWithTimestampf(t, "Verify joe is not found via virtual workspace request")
+				_, err = vwClusterClient.Cluster(wildwestLocationClusterName.Path()).WildwestV1alpha1().Cowboys("unplaced").Get(ctx, "joe", metav1.GetOptions{})
+				require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+
+				logWithTimestampf(t, "Place the namespace of joe on the SyncTarget")
+				_, err = kubeClusterClient.Cluster(wildwestLocationPath).CoreV1().Namespaces().Patch(ctx, "unplaced", types.MergePatchType, []byte(`{"metadata":{"labels":{"placed":"true"}}}`), metav1.PatchOptions{})
+				require.NoError(t, err)
+
+				logWithTimestampf(t, "Wait for joe to show up via virtual workspace request")
+				framework.Eventually(t, func() (bool, string) {
+					_, err := vwClusterClient.Cluster(wildwestLocationClusterName.Path()).WildwestV1alpha1().Cowboys("unplaced").Get(ctx, "joe", metav1.GetOptions{})
+					if err != nil {
+						return false, err.Error()
+					}
+					return true, ""
+				}, wait.ForeverTestTimeout, time.Millisecond*100)
+			},
+		},
 		{
 			name: "access kcp resources through syncer virtual workspace, from a other workspace to the wildwest resources through an APIBinding",
 			work: func(t *testing.T, testCaseWorkspace logicalcluster.Path) {
@@ -29,6 +29,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/events"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
 	schedulinglocationstatus "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/location"
 	schedulingplacement "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/placement"
@@ -43,6 +44,7 @@ import (
 	workloadresource "github.com/kcp-dev/kcp/pkg/reconciler/workload/resource"
 	synctargetcontroller "github.com/kcp-dev/kcp/pkg/reconciler/workload/synctarget"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/synctargetexports"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/synctargetpool"
 	kcpclientset "github.com/kcp-dev/kcp/sdk/client/clientset/versioned/cluster"
 )
 
@@ -163,6 +165,7 @@ func (s *Server) installSchedulingLocationStatusController(ctx context.Context,
 		kcpClusterClient,
 		s.Core.KcpSharedInformerFactory.Scheduling().V1alpha1().Locations(),
 		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargets(),
+		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargetPools(),
 	)
 	if err != nil {
 		return err
@@ -230,6 +233,8 @@ func (s *Server) installWorkloadPlacementScheduler(ctx context.Context, config *
 		s.Core.CacheKcpSharedInformerFactory.Scheduling().V1alpha1().Locations(),
 		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargets(),
 		s.Core.CacheKcpSharedInformerFactory.Workload().V1alpha1().SyncTargets(),
+		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargetPools(),
+		s.Core.CacheKcpSharedInformerFactory.Workload().V1alpha1().SyncTargetPools(),
 		s.Core.KcpSharedInformerFactory.Scheduling().V1alpha1().Placements(),
 		s.Core.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
 	)
@@ -258,11 +263,17 @@ func (s *Server) installSchedulingPlacementController(ctx context.Context, confi
 		return err
 	}
 
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
 	c, err := schedulingplacement.NewController(
 		kcpClusterClient,
 		s.Core.KubeSharedInformerFactory.Core().V1().Namespaces(),
 		s.Core.KcpSharedInformerFactory.Scheduling().V1alpha1().Locations(),
 		s.Core.KcpSharedInformerFactory.Scheduling().V1alpha1().Placements(),
+		events.NewRecorder(ctx, kubeClusterClient, schedulingplacement.ControllerName),
 	)
 	if err != nil {
 		return err
@@ -407,6 +418,33 @@ func (s *Server) installSyncTargetController(ctx context.Context, config *rest.C
 	})
 }
 
+func (s *Server) installSyncTargetPoolController(ctx context.Context, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, synctargetpool.ControllerName)
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c := synctargetpool.NewController(
+		kcpClusterClient,
+		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargetPools(),
+		s.Core.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargets(),
+	)
+
+	return s.Core.AddPostStartHook(postStartHookName(synctargetpool.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(synctargetpool.ControllerName))
+		if err := s.Core.WaitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
 func (s *Server) installWorkloadReplicateClusterRoleControllers(ctx context.Context, config *rest.Config) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, workloadreplicateclusterrole.ControllerName)
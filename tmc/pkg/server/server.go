@@ -130,6 +130,9 @@ func (s *Server) Run(ctx context.Context) error {
 			if err := s.installWorkloadPlacementScheduler(ctx, controllerConfig); err != nil {
 				return err
 			}
+			if err := s.installSyncTargetPoolController(ctx, controllerConfig); err != nil {
+				return err
+			}
 			if err := s.installSchedulingLocationStatusController(ctx, controllerConfig); err != nil {
 				return err
 			}
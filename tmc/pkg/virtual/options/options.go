@@ -17,6 +17,7 @@ limitations under the License.
 package options
 
 import (
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
 	"github.com/spf13/pflag"
 
 	"k8s.io/client-go/rest"
@@ -53,9 +54,10 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 func (o *Options) NewVirtualWorkspaces(
 	config *rest.Config,
 	rootPathPrefix string,
+	wildcardKubeInformers kcpkubernetesinformers.SharedInformerFactory,
 	cachedKcpInformers kcpinformers.SharedInformerFactory,
 ) ([]rootapiserver.NamedVirtualWorkspace, error) {
-	syncer, err := o.Syncer.NewVirtualWorkspaces(rootPathPrefix, config, cachedKcpInformers)
+	syncer, err := o.Syncer.NewVirtualWorkspaces(rootPathPrefix, config, wildcardKubeInformers, cachedKcpInformers)
 	if err != nil {
 		return nil, err
 	}
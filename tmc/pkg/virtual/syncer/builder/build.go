@@ -20,8 +20,10 @@ import (
 	"strings"
 
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 
@@ -48,6 +50,7 @@ func BuildVirtualWorkspace(
 	rootPathPrefix string,
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	dynamicClusterClient kcpdynamic.ClusterInterface,
+	wildcardKubeInformers kcpkubernetesinformers.SharedInformerFactory,
 	cachedKCPInformers kcpinformers.SharedInformerFactory,
 ) []rootapiserver.NamedVirtualWorkspace {
 	if !strings.HasSuffix(rootPathPrefix, "/") {
@@ -68,6 +71,10 @@ func BuildVirtualWorkspace(
 		},
 	)
 
+	// The syncer must only see the content of namespaces placed on its SyncTarget, even
+	// if the resource state label on the objects has not caught up with placement yet.
+	placedNamespacesOnly := withPlacedNamespacesOnly(wildcardKubeInformers.Core().V1().Namespaces().Lister())
+
 	provider := templateProvider{
 		kubeClusterClient:    kubeClusterClient,
 		dynamicClusterClient: dynamicClusterClient,
@@ -94,7 +101,12 @@ func BuildVirtualWorkspace(
 					TransformationProvider:   &transformations.SpecDiffTransformation{},
 					SummarizingRulesProvider: &transformations.DefaultSummarizingRules{},
 				},
-				storageWrapperBuilder: forwardingregistry.WithStaticLabelSelector,
+				storageWrapperBuilder: func(labelSelector labels.Requirements) forwardingregistry.StorageWrapper {
+					return &forwardingregistry.StorageWrappers{
+						forwardingregistry.WithStaticLabelSelector(labelSelector),
+						placedNamespacesOnly,
+					}
+				},
 			}).buildVirtualWorkspace(),
 		},
 		{
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+
+	corev1listers "github.com/kcp-dev/client-go/listers/core/v1"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+	syncercontext "github.com/kcp-dev/kcp/tmc/pkg/virtual/syncer/context"
+)
+
+// withPlacedNamespacesOnly returns a StorageWrapper that hides namespaced objects whose
+// namespace is not placed on the SyncTarget of the request. The resource state label on
+// the objects themselves is only updated asynchronously by the resource controller and
+// can hence be stale, while the namespace label is the source of truth for placement.
+// Cluster-scoped objects are not affected.
+func withPlacedNamespacesOnly(namespaceLister corev1listers.NamespaceClusterLister) forwardingregistry.StorageWrapper {
+	return forwardingregistry.StorageWrapperFunc(func(resource schema.GroupResource, storage *forwardingregistry.StoreFuncs) {
+		placed := func(ctx context.Context, clusterName logicalcluster.Name, namespace string) (bool, error) {
+			if namespace == "" {
+				return true, nil
+			}
+			syncTargetKey, err := syncercontext.SyncTargetKeyFrom(ctx)
+			if err != nil {
+				return false, err
+			}
+			ns, err := namespaceLister.Cluster(clusterName).Get(namespace)
+			if errors.IsNotFound(err) {
+				return false, nil
+			} else if err != nil {
+				return false, err
+			}
+			return ns.Labels[workloadv1alpha1.ClusterResourceStateLabelPrefix+syncTargetKey] == string(workloadv1alpha1.ResourceStateSync), nil
+		}
+		objectPlaced := func(ctx context.Context, obj runtime.Object) (bool, error) {
+			metaObj, err := meta.Accessor(obj)
+			if err != nil {
+				return false, err
+			}
+			return placed(ctx, logicalcluster.From(metaObj), metaObj.GetNamespace())
+		}
+
+		delegateGetter := storage.GetterFunc
+		storage.GetterFunc = func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			obj, err := delegateGetter.Get(ctx, name, options)
+			if err != nil {
+				return obj, err
+			}
+			if ok, err := objectPlaced(ctx, obj); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, errors.NewNotFound(resource, name)
+			}
+			return obj, nil
+		}
+
+		delegateLister := storage.ListerFunc
+		storage.ListerFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+			list, err := delegateLister.List(ctx, options)
+			if err != nil {
+				return list, err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+			filtered := make([]runtime.Object, 0, len(items))
+			for _, item := range items {
+				if ok, err := objectPlaced(ctx, item); err != nil {
+					return nil, err
+				} else if ok {
+					filtered = append(filtered, item)
+				}
+			}
+			if len(filtered) == len(items) {
+				return list, nil
+			}
+			if err := meta.SetList(list, filtered); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+
+		delegateUpdater := storage.UpdaterFunc
+		storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+			if cluster := genericapirequest.ClusterFrom(ctx); cluster != nil && !cluster.Wildcard {
+				namespace, _ := genericapirequest.NamespaceFrom(ctx)
+				if ok, err := placed(ctx, cluster.Name, namespace); err != nil {
+					return nil, false, err
+				} else if !ok {
+					return nil, false, errors.NewNotFound(resource, name)
+				}
+			}
+			return delegateUpdater.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+		}
+
+		delegateWatcher := storage.WatcherFunc
+		storage.WatcherFunc = func(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+			w, err := delegateWatcher.Watch(ctx, options)
+			if err != nil {
+				return w, err
+			}
+
+			// A watch from a resource version follows a list, whose objects the watcher knows
+			// if they were placed at that time. Otherwise, the watcher starts from scratch.
+			assumePlaced := options.ResourceVersion != "" && options.ResourceVersion != "0"
+			// placedObjects records whether the objects of the watch were placed at their last
+			// event, unless it is the default of unknown objects.
+			placedObjects := map[string]bool{}
+
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+					return event, true
+				}
+				metaObj, err := meta.Accessor(event.Object)
+				if err != nil {
+					return event, true
+				}
+				key := logicalcluster.From(metaObj).String() + "|" + metaObj.GetNamespace() + "/" + metaObj.GetName()
+				wasPlaced, known := placedObjects[key]
+				if !known {
+					wasPlaced = assumePlaced && event.Type != watch.Added
+				}
+
+				if event.Type == watch.Deleted {
+					delete(placedObjects, key)
+					return event, wasPlaced
+				}
+
+				isPlaced, err := objectPlaced(ctx, event.Object)
+				if err != nil {
+					isPlaced = false
+				}
+				if isPlaced || assumePlaced {
+					placedObjects[key] = isPlaced
+				} else {
+					delete(placedObjects, key)
+				}
+				switch {
+				case isPlaced && !wasPlaced:
+					return watch.Event{Type: watch.Added, Object: event.Object}, true
+				case isPlaced:
+					return event, true
+				case wasPlaced:
+					// The namespace has been removed from the SyncTarget. Let the watcher
+					// forget about the object.
+					return watch.Event{Type: watch.Deleted, Object: event.Object}, true
+				default:
+					return event, false
+				}
+			}), nil
+		}
+	})
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	corev1listers "github.com/kcp-dev/client-go/listers/core/v1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/workload/v1alpha1"
+	syncercontext "github.com/kcp-dev/kcp/tmc/pkg/virtual/syncer/context"
+)
+
+func newObject(cluster, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: cluster})
+	return obj
+}
+
+func TestWithPlacedNamespacesOnly(t *testing.T) {
+	syncTargetKey := workloadv1alpha1.ToSyncTargetKey("root:org", "cluster-1")
+	resource := schema.GroupResource{Resource: "configmaps"}
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	for _, ns := range []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "placed",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "ws"},
+			Labels:      map[string]string{workloadv1alpha1.ClusterResourceStateLabelPrefix + syncTargetKey: string(workloadv1alpha1.ResourceStateSync)},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "other",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "ws"},
+			Labels:      map[string]string{workloadv1alpha1.ClusterResourceStateLabelPrefix + "other": string(workloadv1alpha1.ResourceStateSync)},
+		}},
+	} {
+		require.NoError(t, indexer.Add(ns))
+	}
+
+	objects := []*unstructured.Unstructured{
+		newObject("ws", "placed", "a"),
+		newObject("ws", "other", "b"),
+		newObject("ws", "missing", "c"),
+		newObject("ws", "", "d"),
+	}
+	var watcher *watch.FakeWatcher
+
+	storage := &forwardingregistry.StoreFuncs{
+		GetterFunc: func(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+			for _, obj := range objects {
+				if obj.GetName() == name {
+					return obj, nil
+				}
+			}
+			return nil, errors.NewNotFound(resource, name)
+		},
+		ListerFunc: func(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+			list := &unstructured.UnstructuredList{}
+			for _, obj := range objects {
+				list.Items = append(list.Items, *obj)
+			}
+			return list, nil
+		},
+		WatcherFunc: func(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+			watcher = watch.NewFake()
+			return watcher, nil
+		},
+	}
+	withPlacedNamespacesOnly(corev1listers.NewNamespaceClusterLister(indexer)).Decorate(resource, storage)
+
+	ctx := syncercontext.WithSyncTargetKey(context.Background(), syncTargetKey)
+
+	t.Run("get", func(t *testing.T) {
+		_, err := storage.Get(ctx, "a", &metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = storage.Get(ctx, "b", &metav1.GetOptions{})
+		require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+		_, err = storage.Get(ctx, "c", &metav1.GetOptions{})
+		require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+		_, err = storage.Get(ctx, "d", &metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list, err := storage.List(ctx, &metainternalversion.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, item := range list.(*unstructured.UnstructuredList).Items {
+			names = append(names, item.GetName())
+		}
+		require.Equal(t, []string{"a", "d"}, names)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		w, err := storage.Watch(ctx, &metainternalversion.ListOptions{})
+		require.NoError(t, err)
+		defer w.Stop()
+
+		// events are sent one by one, such that an event passing the filter tells that all
+		// events before have been processed, and the namespace can be changed.
+		expect := func(eventType watch.EventType, name string) {
+			t.Helper()
+			event := <-w.ResultChan()
+			require.Equal(t, eventType, event.Type)
+			require.Equal(t, name, event.Object.(*unstructured.Unstructured).GetName())
+		}
+		setPlaced := func(placed bool) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "toggled",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "ws"},
+			}}
+			if placed {
+				ns.Labels = map[string]string{workloadv1alpha1.ClusterResourceStateLabelPrefix + syncTargetKey: string(workloadv1alpha1.ResourceStateSync)}
+			}
+			require.NoError(t, indexer.Update(ns))
+		}
+		setPlaced(true)
+
+		watcher.Add(newObject("ws", "other", "b"))
+		watcher.Add(newObject("ws", "toggled", "e"))
+		expect(watch.Added, "e")
+		watcher.Modify(newObject("ws", "other", "b"))
+		watcher.Delete(newObject("ws", "other", "b"))
+		watcher.Modify(newObject("ws", "toggled", "e"))
+		expect(watch.Modified, "e")
+
+		setPlaced(false)
+		watcher.Modify(newObject("ws", "toggled", "e"))
+		expect(watch.Deleted, "e")
+		watcher.Modify(newObject("ws", "toggled", "e"))
+		watcher.Add(newObject("ws", "placed", "a"))
+		expect(watch.Added, "a")
+
+		setPlaced(true)
+		watcher.Modify(newObject("ws", "toggled", "e"))
+		expect(watch.Added, "e")
+		watcher.Delete(newObject("ws", "toggled", "e"))
+		expect(watch.Deleted, "e")
+	})
+
+	t.Run("watch from resource version", func(t *testing.T) {
+		w, err := storage.Watch(ctx, &metainternalversion.ListOptions{ResourceVersion: "42"})
+		require.NoError(t, err)
+		defer w.Stop()
+
+		// b might have been placed when listed, and is forgotten once.
+		watcher.Modify(newObject("ws", "other", "b"))
+		event := <-w.ResultChan()
+		require.Equal(t, watch.Deleted, event.Type)
+		require.Equal(t, "b", event.Object.(*unstructured.Unstructured).GetName())
+
+		watcher.Modify(newObject("ws", "other", "b"))
+		watcher.Delete(newObject("ws", "other", "b"))
+		watcher.Modify(newObject("ws", "placed", "a"))
+		event = <-w.ResultChan()
+		require.Equal(t, watch.Modified, event.Type)
+		require.Equal(t, "a", event.Object.(*unstructured.Unstructured).GetName())
+	})
+}